@@ -48,10 +48,32 @@ func (src *Cluster) ConvertTo(dstRaw conversion.Hub) error {
 	if restored.Spec.Topology != nil {
 		dst.Spec.Topology = restored.Spec.Topology
 	}
+	dst.Spec.ControlPlaneProvidesInfrastructure = restored.Spec.ControlPlaneProvidesInfrastructure
+	dst.Spec.MaintenanceWindows = restored.Spec.MaintenanceWindows
+	dst.Spec.Metadata = restored.Spec.Metadata
+	dst.Status.ReadyWorkers = restored.Status.ReadyWorkers
+	dst.Status.UpToDateWorkers = restored.Status.UpToDateWorkers
+	dst.Status.UnavailableControlPlane = restored.Status.UnavailableControlPlane
+	dst.Status.RemediatingMachines = restored.Status.RemediatingMachines
+	restoreFailureDomains(restored.Status.FailureDomains, dst.Status.FailureDomains)
 
 	return nil
 }
 
+// restoreFailureDomains restores the failure domain fields added with v1beta1.
+func restoreFailureDomains(restored, dst clusterv1.FailureDomains) {
+	for id, fd := range dst {
+		restoredFD, ok := restored[id]
+		if !ok {
+			continue
+		}
+		fd.Capacity = restoredFD.Capacity
+		fd.Labels = restoredFD.Labels
+		fd.Taints = restoredFD.Taints
+		dst[id] = fd
+	}
+}
+
 func (dst *Cluster) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*clusterv1.Cluster)
 
@@ -99,8 +121,14 @@ func (src *Machine) ConvertTo(dstRaw conversion.Hub) error {
 
 	dst.Spec.NodeDeletionTimeout = restored.Spec.NodeDeletionTimeout
 	dst.Spec.NodeVolumeDetachTimeout = restored.Spec.NodeVolumeDetachTimeout
+	dst.Spec.NodeStartupTimeout = restored.Spec.NodeStartupTimeout
+	dst.Spec.NodeReRegistrationPolicy = restored.Spec.NodeReRegistrationPolicy
+	dst.Spec.NodeDrainEvictionTimeout = restored.Spec.NodeDrainEvictionTimeout
 	dst.Status.NodeInfo = restored.Status.NodeInfo
+	dst.Status.Capacity = restored.Status.Capacity
+	dst.Status.Allocatable = restored.Status.Allocatable
 	dst.Status.CertificatesExpiryDate = restored.Status.CertificatesExpiryDate
+	dst.Status.NodeDrainStartTime = restored.Status.NodeDrainStartTime
 	return nil
 }
 
@@ -144,6 +172,11 @@ func (src *MachineSet) ConvertTo(dstRaw conversion.Hub) error {
 	}
 	dst.Spec.Template.Spec.NodeDeletionTimeout = restored.Spec.Template.Spec.NodeDeletionTimeout
 	dst.Spec.Template.Spec.NodeVolumeDetachTimeout = restored.Spec.Template.Spec.NodeVolumeDetachTimeout
+	dst.Spec.Template.Spec.NodeStartupTimeout = restored.Spec.Template.Spec.NodeStartupTimeout
+	dst.Spec.Template.Spec.NodeReRegistrationPolicy = restored.Spec.Template.Spec.NodeReRegistrationPolicy
+	dst.Spec.Template.Spec.NodeDrainEvictionTimeout = restored.Spec.Template.Spec.NodeDrainEvictionTimeout
+	dst.Spec.FailureDomainSpreadPolicy = restored.Spec.FailureDomainSpreadPolicy
+	dst.Spec.MachineNamingStrategy = restored.Spec.MachineNamingStrategy
 	dst.Status.Conditions = restored.Status.Conditions
 	return nil
 }
@@ -199,6 +232,19 @@ func (src *MachineDeployment) ConvertTo(dstRaw conversion.Hub) error {
 
 	dst.Spec.Template.Spec.NodeDeletionTimeout = restored.Spec.Template.Spec.NodeDeletionTimeout
 	dst.Spec.Template.Spec.NodeVolumeDetachTimeout = restored.Spec.Template.Spec.NodeVolumeDetachTimeout
+	dst.Spec.Template.Spec.NodeStartupTimeout = restored.Spec.Template.Spec.NodeStartupTimeout
+	dst.Spec.Template.Spec.NodeReRegistrationPolicy = restored.Spec.Template.Spec.NodeReRegistrationPolicy
+	dst.Spec.Template.Spec.NodeDrainEvictionTimeout = restored.Spec.Template.Spec.NodeDrainEvictionTimeout
+	if restored.Spec.Strategy != nil && restored.Spec.Strategy.Canary != nil {
+		if dst.Spec.Strategy == nil {
+			dst.Spec.Strategy = &clusterv1.MachineDeploymentStrategy{}
+		}
+		dst.Spec.Strategy.Canary = restored.Spec.Strategy.Canary
+	}
+
+	dst.Spec.FailureDomainSpreadPolicy = restored.Spec.FailureDomainSpreadPolicy
+	dst.Spec.MachineSetNamingStrategy = restored.Spec.MachineSetNamingStrategy
+	dst.Spec.MachineNamingStrategy = restored.Spec.MachineNamingStrategy
 	dst.Spec.RolloutAfter = restored.Spec.RolloutAfter
 	dst.Status.Conditions = restored.Status.Conditions
 	return nil
@@ -283,11 +329,21 @@ func Convert_v1beta1_MachineSetStatus_To_v1alpha3_MachineSetStatus(in *clusterv1
 	return autoConvert_v1beta1_MachineSetStatus_To_v1alpha3_MachineSetStatus(in, out, nil)
 }
 
+func Convert_v1beta1_FailureDomainSpec_To_v1alpha3_FailureDomainSpec(in *clusterv1.FailureDomainSpec, out *FailureDomainSpec, s apiconversion.Scope) error {
+	// spec.{capacity,labels,taints} have been added with v1beta1.
+	return autoConvert_v1beta1_FailureDomainSpec_To_v1alpha3_FailureDomainSpec(in, out, s)
+}
+
 func Convert_v1beta1_ClusterSpec_To_v1alpha3_ClusterSpec(in *clusterv1.ClusterSpec, out *ClusterSpec, s apiconversion.Scope) error {
-	// NOTE: custom conversion func is required because spec.Topology does not exist in v1alpha3
+	// NOTE: custom conversion func is required because spec.Topology, spec.ControlPlaneProvidesInfrastructure and spec.MaintenanceWindows do not exist in v1alpha3
 	return autoConvert_v1beta1_ClusterSpec_To_v1alpha3_ClusterSpec(in, out, s)
 }
 
+func Convert_v1beta1_ClusterStatus_To_v1alpha3_ClusterStatus(in *clusterv1.ClusterStatus, out *ClusterStatus, s apiconversion.Scope) error {
+	// status.readyWorkers, status.upToDateWorkers, status.unavailableControlPlane and status.remediatingMachines have been added with v1beta1.
+	return autoConvert_v1beta1_ClusterStatus_To_v1alpha3_ClusterStatus(in, out, s)
+}
+
 func Convert_v1alpha3_Bootstrap_To_v1beta1_Bootstrap(in *Bootstrap, out *clusterv1.Bootstrap, s apiconversion.Scope) error {
 	return autoConvert_v1alpha3_Bootstrap_To_v1beta1_Bootstrap(in, out, s)
 }
@@ -321,6 +377,15 @@ func Convert_v1beta1_MachineDeploymentSpec_To_v1alpha3_MachineDeploymentSpec(in
 	return autoConvert_v1beta1_MachineDeploymentSpec_To_v1alpha3_MachineDeploymentSpec(in, out, s)
 }
 
+func Convert_v1beta1_MachineDeploymentStrategy_To_v1alpha3_MachineDeploymentStrategy(in *clusterv1.MachineDeploymentStrategy, out *MachineDeploymentStrategy, s apiconversion.Scope) error {
+	// spec.strategy.canary has been added with v1beta1.
+	return autoConvert_v1beta1_MachineDeploymentStrategy_To_v1alpha3_MachineDeploymentStrategy(in, out, s)
+}
+
+func Convert_v1beta1_MachineSetSpec_To_v1alpha3_MachineSetSpec(in *clusterv1.MachineSetSpec, out *MachineSetSpec, s apiconversion.Scope) error {
+	return autoConvert_v1beta1_MachineSetSpec_To_v1alpha3_MachineSetSpec(in, out, s)
+}
+
 func Convert_v1beta1_MachineDeploymentStatus_To_v1alpha3_MachineDeploymentStatus(in *clusterv1.MachineDeploymentStatus, out *MachineDeploymentStatus, s apiconversion.Scope) error {
 	// Status.Conditions was introduced in v1alpha4, thus requiring a custom conversion function; the values is going to be preserved in an annotation thus allowing roundtrip without loosing informations
 	return autoConvert_v1beta1_MachineDeploymentStatus_To_v1alpha3_MachineDeploymentStatus(in, out, s)
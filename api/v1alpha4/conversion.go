@@ -77,10 +77,32 @@ func (src *Cluster) ConvertTo(dstRaw conversion.Hub) error {
 			dst.Spec.Topology.Workers.MachinePools = restored.Spec.Topology.Workers.MachinePools
 		}
 	}
+	dst.Spec.ControlPlaneProvidesInfrastructure = restored.Spec.ControlPlaneProvidesInfrastructure
+	dst.Spec.MaintenanceWindows = restored.Spec.MaintenanceWindows
+	dst.Spec.Metadata = restored.Spec.Metadata
+	dst.Status.ReadyWorkers = restored.Status.ReadyWorkers
+	dst.Status.UpToDateWorkers = restored.Status.UpToDateWorkers
+	dst.Status.UnavailableControlPlane = restored.Status.UnavailableControlPlane
+	dst.Status.RemediatingMachines = restored.Status.RemediatingMachines
+	restoreFailureDomains(restored.Status.FailureDomains, dst.Status.FailureDomains)
 
 	return nil
 }
 
+// restoreFailureDomains restores the failure domain fields added with v1beta1.
+func restoreFailureDomains(restored, dst clusterv1.FailureDomains) {
+	for id, fd := range dst {
+		restoredFD, ok := restored[id]
+		if !ok {
+			continue
+		}
+		fd.Capacity = restoredFD.Capacity
+		fd.Labels = restoredFD.Labels
+		fd.Taints = restoredFD.Taints
+		dst[id] = fd
+	}
+}
+
 func (dst *Cluster) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*clusterv1.Cluster)
 
@@ -127,7 +149,10 @@ func (src *ClusterClass) ConvertTo(dstRaw conversion.Hub) error {
 	dst.Spec.ControlPlane.NodeDrainTimeout = restored.Spec.ControlPlane.NodeDrainTimeout
 	dst.Spec.ControlPlane.NodeVolumeDetachTimeout = restored.Spec.ControlPlane.NodeVolumeDetachTimeout
 	dst.Spec.ControlPlane.NodeDeletionTimeout = restored.Spec.ControlPlane.NodeDeletionTimeout
+	dst.Spec.ControlPlane.RolloutPolicy = restored.Spec.ControlPlane.RolloutPolicy
+	dst.Spec.ControlPlane.NamingStrategy = restored.Spec.ControlPlane.NamingStrategy
 	dst.Spec.Workers.MachinePools = restored.Spec.Workers.MachinePools
+	dst.Spec.Addons = restored.Spec.Addons
 
 	for i := range restored.Spec.Workers.MachineDeployments {
 		dst.Spec.Workers.MachineDeployments[i].MachineHealthCheck = restored.Spec.Workers.MachineDeployments[i].MachineHealthCheck
@@ -137,6 +162,12 @@ func (src *ClusterClass) ConvertTo(dstRaw conversion.Hub) error {
 		dst.Spec.Workers.MachineDeployments[i].NodeDeletionTimeout = restored.Spec.Workers.MachineDeployments[i].NodeDeletionTimeout
 		dst.Spec.Workers.MachineDeployments[i].MinReadySeconds = restored.Spec.Workers.MachineDeployments[i].MinReadySeconds
 		dst.Spec.Workers.MachineDeployments[i].Strategy = restored.Spec.Workers.MachineDeployments[i].Strategy
+		dst.Spec.Workers.MachineDeployments[i].IPAddressClaims = restored.Spec.Workers.MachineDeployments[i].IPAddressClaims
+		dst.Spec.Workers.MachineDeployments[i].NamingStrategy = restored.Spec.Workers.MachineDeployments[i].NamingStrategy
+		dst.Spec.Workers.MachineDeployments[i].MachineSetNamingStrategy = restored.Spec.Workers.MachineDeployments[i].MachineSetNamingStrategy
+		dst.Spec.Workers.MachineDeployments[i].MachineNamingStrategy = restored.Spec.Workers.MachineDeployments[i].MachineNamingStrategy
+		dst.Spec.Workers.MachineDeployments[i].Autoscaling = restored.Spec.Workers.MachineDeployments[i].Autoscaling
+		dst.Spec.Workers.MachineDeployments[i].Variables = restored.Spec.Workers.MachineDeployments[i].Variables
 	}
 
 	dst.Status = restored.Status
@@ -186,7 +217,13 @@ func (src *Machine) ConvertTo(dstRaw conversion.Hub) error {
 
 	dst.Spec.NodeDeletionTimeout = restored.Spec.NodeDeletionTimeout
 	dst.Status.CertificatesExpiryDate = restored.Status.CertificatesExpiryDate
+	dst.Status.NodeDrainStartTime = restored.Status.NodeDrainStartTime
+	dst.Status.Capacity = restored.Status.Capacity
+	dst.Status.Allocatable = restored.Status.Allocatable
 	dst.Spec.NodeVolumeDetachTimeout = restored.Spec.NodeVolumeDetachTimeout
+	dst.Spec.NodeStartupTimeout = restored.Spec.NodeStartupTimeout
+	dst.Spec.NodeReRegistrationPolicy = restored.Spec.NodeReRegistrationPolicy
+	dst.Spec.NodeDrainEvictionTimeout = restored.Spec.NodeDrainEvictionTimeout
 	return nil
 }
 
@@ -232,6 +269,11 @@ func (src *MachineSet) ConvertTo(dstRaw conversion.Hub) error {
 
 	dst.Spec.Template.Spec.NodeDeletionTimeout = restored.Spec.Template.Spec.NodeDeletionTimeout
 	dst.Spec.Template.Spec.NodeVolumeDetachTimeout = restored.Spec.Template.Spec.NodeVolumeDetachTimeout
+	dst.Spec.Template.Spec.NodeStartupTimeout = restored.Spec.Template.Spec.NodeStartupTimeout
+	dst.Spec.Template.Spec.NodeReRegistrationPolicy = restored.Spec.Template.Spec.NodeReRegistrationPolicy
+	dst.Spec.Template.Spec.NodeDrainEvictionTimeout = restored.Spec.Template.Spec.NodeDrainEvictionTimeout
+	dst.Spec.FailureDomainSpreadPolicy = restored.Spec.FailureDomainSpreadPolicy
+	dst.Spec.MachineNamingStrategy = restored.Spec.MachineNamingStrategy
 	return nil
 }
 
@@ -273,6 +315,18 @@ func (src *MachineDeployment) ConvertTo(dstRaw conversion.Hub) error {
 
 	dst.Spec.Template.Spec.NodeDeletionTimeout = restored.Spec.Template.Spec.NodeDeletionTimeout
 	dst.Spec.Template.Spec.NodeVolumeDetachTimeout = restored.Spec.Template.Spec.NodeVolumeDetachTimeout
+	dst.Spec.Template.Spec.NodeStartupTimeout = restored.Spec.Template.Spec.NodeStartupTimeout
+	dst.Spec.Template.Spec.NodeReRegistrationPolicy = restored.Spec.Template.Spec.NodeReRegistrationPolicy
+	dst.Spec.Template.Spec.NodeDrainEvictionTimeout = restored.Spec.Template.Spec.NodeDrainEvictionTimeout
+	if restored.Spec.Strategy != nil && restored.Spec.Strategy.Canary != nil {
+		if dst.Spec.Strategy == nil {
+			dst.Spec.Strategy = &clusterv1.MachineDeploymentStrategy{}
+		}
+		dst.Spec.Strategy.Canary = restored.Spec.Strategy.Canary
+	}
+	dst.Spec.FailureDomainSpreadPolicy = restored.Spec.FailureDomainSpreadPolicy
+	dst.Spec.MachineSetNamingStrategy = restored.Spec.MachineSetNamingStrategy
+	dst.Spec.MachineNamingStrategy = restored.Spec.MachineNamingStrategy
 	dst.Spec.RolloutAfter = restored.Spec.RolloutAfter
 	return nil
 }
@@ -329,6 +383,21 @@ func Convert_v1alpha4_MachineStatus_To_v1beta1_MachineStatus(in *MachineStatus,
 	return autoConvert_v1alpha4_MachineStatus_To_v1beta1_MachineStatus(in, out, s)
 }
 
+func Convert_v1beta1_ClusterSpec_To_v1alpha4_ClusterSpec(in *clusterv1.ClusterSpec, out *ClusterSpec, s apiconversion.Scope) error {
+	// spec.controlPlaneProvidesInfrastructure and spec.maintenanceWindows have been added with v1beta1.
+	return autoConvert_v1beta1_ClusterSpec_To_v1alpha4_ClusterSpec(in, out, s)
+}
+
+func Convert_v1beta1_ClusterStatus_To_v1alpha4_ClusterStatus(in *clusterv1.ClusterStatus, out *ClusterStatus, s apiconversion.Scope) error {
+	// status.readyWorkers, status.upToDateWorkers, status.unavailableControlPlane and status.remediatingMachines have been added with v1beta1.
+	return autoConvert_v1beta1_ClusterStatus_To_v1alpha4_ClusterStatus(in, out, s)
+}
+
+func Convert_v1beta1_FailureDomainSpec_To_v1alpha4_FailureDomainSpec(in *clusterv1.FailureDomainSpec, out *FailureDomainSpec, s apiconversion.Scope) error {
+	// spec.{capacity,labels,taints} have been added with v1beta1.
+	return autoConvert_v1beta1_FailureDomainSpec_To_v1alpha4_FailureDomainSpec(in, out, s)
+}
+
 func Convert_v1beta1_ClusterClassSpec_To_v1alpha4_ClusterClassSpec(in *clusterv1.ClusterClassSpec, out *ClusterClassSpec, s apiconversion.Scope) error {
 	// spec.{variables,patches} has been added with v1beta1.
 	return autoConvert_v1beta1_ClusterClassSpec_To_v1alpha4_ClusterClassSpec(in, out, s)
@@ -343,6 +412,15 @@ func Convert_v1beta1_MachineDeploymentSpec_To_v1alpha4_MachineDeploymentSpec(in
 	return autoConvert_v1beta1_MachineDeploymentSpec_To_v1alpha4_MachineDeploymentSpec(in, out, s)
 }
 
+func Convert_v1beta1_MachineDeploymentStrategy_To_v1alpha4_MachineDeploymentStrategy(in *clusterv1.MachineDeploymentStrategy, out *MachineDeploymentStrategy, s apiconversion.Scope) error {
+	// spec.strategy.canary has been added with v1beta1.
+	return autoConvert_v1beta1_MachineDeploymentStrategy_To_v1alpha4_MachineDeploymentStrategy(in, out, s)
+}
+
+func Convert_v1beta1_MachineSetSpec_To_v1alpha4_MachineSetSpec(in *clusterv1.MachineSetSpec, out *MachineSetSpec, s apiconversion.Scope) error {
+	return autoConvert_v1beta1_MachineSetSpec_To_v1alpha4_MachineSetSpec(in, out, s)
+}
+
 func Convert_v1beta1_Topology_To_v1alpha4_Topology(in *clusterv1.Topology, out *Topology, s apiconversion.Scope) error {
 	// spec.topology.variables has been added with v1beta1.
 	return autoConvert_v1beta1_Topology_To_v1alpha4_Topology(in, out, s)
@@ -65,6 +65,32 @@ type ClusterSpec struct {
 	// +optional
 	InfrastructureRef *corev1.ObjectReference `json:"infrastructureRef,omitempty"`
 
+	// ControlPlaneProvidesInfrastructure indicates that the infrastructure of the Cluster is provided by the
+	// control plane referenced by ControlPlaneRef, e.g. for hosted control planes, and that no
+	// InfrastructureRef is required.
+	// When true, InfrastructureRef must not be set, the Cluster infrastructure is considered ready, and
+	// the ControlPlaneEndpoint and the failure domains are read from the control plane object.
+	// +optional
+	ControlPlaneProvidesInfrastructure bool `json:"controlPlaneProvidesInfrastructure,omitempty"`
+
+	// MaintenanceWindows define when disruptive operations are allowed for the Cluster, i.e. rollouts of the
+	// KubeadmControlPlane and of MachineDeployments, and remediation of unhealthy Machines by MachineHealthChecks.
+	// Outside of the maintenance windows those operations are deferred until the next window opens, unless the
+	// Cluster has the cluster.x-k8s.io/ignore-maintenance-windows annotation; rollouts already in progress are completed.
+	// If not set, disruptive operations are always allowed.
+	// +optional
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+
+	// Metadata defines labels and annotations that are propagated to all the descendants of the Cluster, i.e.
+	// the infrastructure cluster, the control plane, MachineDeployments, MachineSets, MachinePools, Machines,
+	// the corresponding infrastructure and bootstrap objects and the Nodes of the workload cluster.
+	// Labels and annotations defined here have the lowest precedence, i.e. the ones defined in the Cluster topology,
+	// in the ClusterClass and in the templates of MachineSets, MachinePools and control planes take precedence over them.
+	// Keys in the cluster.x-k8s.io domain and its subdomains, e.g. topology.cluster.x-k8s.io, are reserved for
+	// Cluster API and are not allowed.
+	// +optional
+	Metadata *ObjectMeta `json:"metadata,omitempty"`
+
 	// This encapsulates the topology for the cluster.
 	// NOTE: It is required to enable the ClusterTopology
 	// feature gate flag to activate managed topologies support;
@@ -73,6 +99,18 @@ type ClusterSpec struct {
 	Topology *Topology `json:"topology,omitempty"`
 }
 
+// MaintenanceWindow defines a recurring time window during which disruptive operations are allowed.
+type MaintenanceWindow struct {
+	// Schedule defines when the maintenance window opens, as a cron expression with five fields
+	// (minute, hour, day of month, month, day of week) evaluated in UTC, e.g. "0 22 * * 1-5" for
+	// 10 PM UTC on weekdays. Fields support numbers, '*', ranges (1-5), lists (1,3,5) and steps (*/15).
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+
+	// Duration is how long the maintenance window stays open, e.g. "2h".
+	Duration metav1.Duration `json:"duration"`
+}
+
 // Topology encapsulates the information of the managed resources.
 type Topology struct {
 	// The name of the ClusterClass object to create the topology.
@@ -324,7 +362,30 @@ type ClusterVariable struct {
 	// hard-coded schema for apiextensionsv1.JSON which cannot be produced by another type via controller-tools,
 	// i.e. it is not possible to have no type field.
 	// Ref: https://github.com/kubernetes-sigs/controller-tools/blob/d0e03a142d0ecdd5491593e941ee1d6b5d91dba6/pkg/crd/known_types.go#L106-L111
-	Value apiextensionsv1.JSON `json:"value"`
+	// +optional
+	Value apiextensionsv1.JSON `json:"value,omitempty"`
+
+	// ValueFrom is a source for the value of the variable. It can be used to share values, e.g. proxy settings
+	// or registry credentials, across many Clusters instead of inlining them into every Cluster.
+	// The value is read by the topology controller each time the Cluster is reconciled; it is not copied into the Cluster.
+	// Cannot be used if value is set.
+	// +optional
+	ValueFrom *ClusterVariableValueSource `json:"valueFrom,omitempty"`
+}
+
+// ClusterVariableValueSource defines a source for the value of a ClusterVariable.
+// Exactly one of its fields must be set.
+// The content of the selected key is used as a string if the schema of the variable is of type string;
+// otherwise it is parsed as JSON, e.g. `true` or `{"url": "http://proxy:3128"}`, and if it is not valid JSON
+// it is used as a string.
+type ClusterVariableValueSource struct {
+	// ConfigMapKeyRef selects a key of a ConfigMap in the namespace of the Cluster.
+	// +optional
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+
+	// SecretKeyRef selects a key of a Secret in the namespace of the Cluster.
+	// +optional
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
 }
 
 // MachineDeploymentVariables can be used to provide variables for a specific MachineDeployment.
@@ -416,6 +477,28 @@ type ClusterStatus struct {
 	// +optional
 	ControlPlaneReady bool `json:"controlPlaneReady"`
 
+	// ReadyWorkers is the number of worker Machines of the Cluster, i.e. the Machines which are not part of
+	// the control plane, which are ready: not being deleted, with a Node, and with the Ready and NodeHealthy
+	// conditions true.
+	// +optional
+	ReadyWorkers int32 `json:"readyWorkers,omitempty"`
+
+	// UpToDateWorkers is the number of worker Machines of the Cluster belonging to a MachineSet which are
+	// up-to-date with the spec of the MachineDeployment they belong to. Machines of MachineSets not belonging
+	// to a MachineDeployment are always considered up-to-date, while MachinePool Machines and Machines not
+	// belonging to a MachineSet are never counted.
+	// +optional
+	UpToDateWorkers int32 `json:"upToDateWorkers,omitempty"`
+
+	// UnavailableControlPlane is the number of control plane Machines of the Cluster which are not ready.
+	// +optional
+	UnavailableControlPlane int32 `json:"unavailableControlPlane,omitempty"`
+
+	// RemediatingMachines is the number of Machines of the Cluster which failed a MachineHealthCheck and are
+	// waiting to be remediated by their owner, i.e. with the OwnerRemediated condition false.
+	// +optional
+	RemediatingMachines int32 `json:"remediatingMachines,omitempty"`
+
 	// Conditions defines current service state of the cluster.
 	// +optional
 	Conditions Conditions `json:"conditions,omitempty"`
@@ -636,4 +719,30 @@ type FailureDomainSpec struct {
 	// Attributes is a free form map of attributes an infrastructure provider might use or require.
 	// +optional
 	Attributes map[string]string `json:"attributes,omitempty"`
+
+	// Capacity is a hint about the relative capacity of this failure domain; Machines are spread across
+	// failure domains proportionally to their capacity, e.g. a failure domain with capacity 2 gets twice
+	// the Machines of a failure domain with capacity 1, and no new Machines are placed in failure domains
+	// with capacity 0. If not set, the capacity is 1.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Capacity *int32 `json:"capacity,omitempty"`
+
+	// Labels are labels describing this failure domain, e.g. zone labels, that are applied to the Nodes
+	// of the Machines placed in this failure domain.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Taints are default taints that are added to the Nodes of the Machines placed in this failure domain
+	// when the Nodes are reconciled for the first time; they can be removed from the Nodes afterwards.
+	// +optional
+	Taints []corev1.Taint `json:"taints,omitempty"`
+}
+
+// GetCapacity returns the capacity of the failure domain, defaulting to 1 if not set.
+func (in FailureDomainSpec) GetCapacity() int32 {
+	if in.Capacity == nil {
+		return 1
+	}
+	return *in.Capacity
 }
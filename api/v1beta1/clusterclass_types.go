@@ -74,6 +74,53 @@ type ClusterClassSpec struct {
 	// Note: Patches will be applied in the order of the array.
 	// +optional
 	Patches []ClusterClassPatch `json:"patches,omitempty"`
+
+	// Addons defines the add-ons installed in the Clusters using this ClusterClass, e.g. the CNI.
+	// For each add-on enabled in a Cluster, the topology controller creates a ClusterResourceSet
+	// selecting only that Cluster.
+	// NOTE: This field requires the ClusterResourceSet feature gate to be enabled.
+	// +optional
+	Addons []ClusterClassAddon `json:"addons,omitempty"`
+}
+
+// ClusterClassAddon defines an add-on installed in the Clusters using a ClusterClass.
+type ClusterClassAddon struct {
+	// Name of the add-on; it must be unique within the ClusterClass.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// EnabledIf is a Go template to be used to calculate if an add-on should be installed in a Cluster.
+	// The same variables available in patches can be used in the template.
+	// The add-on is installed if the rendered template value is equal to "true".
+	// If EnabledIf is not set, the add-on is installed in all the Clusters using the ClusterClass.
+	// +optional
+	EnabledIf *string `json:"enabledIf,omitempty"`
+
+	// ClusterResourceSet defines the ClusterResourceSet created for each Cluster the add-on is installed in.
+	ClusterResourceSet ClusterClassAddonClusterResourceSet `json:"clusterResourceSet"`
+}
+
+// ClusterClassAddonClusterResourceSet defines the ClusterResourceSet created for an add-on.
+type ClusterClassAddonClusterResourceSet struct {
+	// Resources is a list of Secrets/ConfigMaps in the namespace of the Cluster, where each contains
+	// one or more resources to be applied to the Cluster.
+	Resources []ClusterClassAddonResource `json:"resources"`
+
+	// Strategy is the strategy to be used during applying resources. Defaults to ApplyOnce.
+	// +kubebuilder:validation:Enum=ApplyOnce;Reconcile
+	// +optional
+	Strategy string `json:"strategy,omitempty"`
+}
+
+// ClusterClassAddonResource specifies a resource of an add-on.
+type ClusterClassAddonResource struct {
+	// Name of the resource in the namespace of the Cluster.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Kind of the resource. Supported kinds are: Secrets and ConfigMaps.
+	// +kubebuilder:validation:Enum=Secret;ConfigMap
+	Kind string `json:"kind"`
 }
 
 // ControlPlaneClass defines the class for the control plane.
@@ -125,6 +172,59 @@ type ControlPlaneClass struct {
 	// NOTE: This value can be overridden while defining a Cluster.Topology.
 	// +optional
 	NodeDeletionTimeout *metav1.Duration `json:"nodeDeletionTimeout,omitempty"`
+
+	// RolloutPolicy defines how changes to the control plane templates of this ClusterClass, i.e. the
+	// ControlPlaneTemplate and the control plane InfrastructureMachineTemplate, are rolled out to the
+	// control planes of the Clusters using this ClusterClass.
+	// If not set, the control planes of all the Clusters are rolled out at the same time.
+	// +optional
+	RolloutPolicy *ControlPlaneRolloutPolicy `json:"rolloutPolicy,omitempty"`
+
+	// NamingStrategy allows changing the naming pattern used when creating the control plane provider object
+	// and the control plane InfrastructureMachineTemplates.
+	// +optional
+	NamingStrategy *ControlPlaneClassNamingStrategy `json:"namingStrategy,omitempty"`
+}
+
+// ControlPlaneClassNamingStrategy defines the naming strategy for control plane objects.
+type ControlPlaneClassNamingStrategy struct {
+	// Template defines the template to use for generating the name of the ControlPlane object
+	// and of its InfrastructureMachineTemplates.
+	// If not defined, it will fallback to `{{ .cluster.name }}-{{ .random }}` for the ControlPlane object
+	// and to `{{ .cluster.name }}-control-plane-{{ .random }}` for the InfrastructureMachineTemplates.
+	// If the templated string exceeds 63 characters, it will be trimmed to 58 characters and will
+	// get concatenated with a random suffix of length 5.
+	// The templating mechanism provides the following arguments:
+	// * `.cluster.name`: The name of the cluster object.
+	// * `.random`: A random alphanumeric string, without vowels, of length 5.
+	// The template must contain `{{ .random }}`.
+	// +optional
+	Template *string `json:"template,omitempty"`
+}
+
+// ControlPlaneRolloutPolicy defines how changes to the control plane templates of a ClusterClass are rolled out
+// across the Clusters using the ClusterClass.
+type ControlPlaneRolloutPolicy struct {
+	// MaxConcurrentClusters is the maximum number of Clusters using this ClusterClass rolling out their
+	// control plane at the same time; the rollout of the control planes of the other Clusters is held
+	// until the ongoing rollouts are completed.
+	// Defaults to 1.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxConcurrentClusters *int32 `json:"maxConcurrentClusters,omitempty"`
+
+	// SoakTime is the minimum amount of time to wait after a Cluster using this ClusterClass completed the
+	// rollout of its control plane before starting the rollout of the control plane of another Cluster.
+	// Defaults to 0, meaning that rollouts start as soon as allowed by MaxConcurrentClusters.
+	// +optional
+	SoakTime *metav1.Duration `json:"soakTime,omitempty"`
+
+	// Timeout is the maximum amount of time a Cluster using this ClusterClass is expected to take to roll out
+	// its control plane; Clusters rolling out their control plane for longer, e.g. because the rollout is stuck
+	// or failed, no longer hold the rollout of the control plane of other Clusters.
+	// Defaults to 1h.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
 }
 
 // WorkersClass is a collection of deployment classes.
@@ -193,6 +293,91 @@ type MachineDeploymentClass struct {
 	// new ones.
 	// NOTE: This value can be overridden while defining a Cluster.Topology using this MachineDeploymentClass.
 	Strategy *MachineDeploymentStrategy `json:"strategy,omitempty"`
+
+	// IPAddressClaims defines the IPAddressClaims that should be created for every Machine
+	// of a MachineDeployment using this MachineDeploymentClass.
+	// +optional
+	IPAddressClaims []IPAddressClaimTemplate `json:"ipAddressClaims,omitempty"`
+
+	// NamingStrategy allows changing the naming pattern used when creating the MachineDeployment
+	// and its bootstrap and infrastructure templates.
+	// +optional
+	NamingStrategy *MachineDeploymentClassNamingStrategy `json:"namingStrategy,omitempty"`
+
+	// MachineSetNamingStrategy allows changing the naming pattern used when creating the MachineSets
+	// of a MachineDeployment using this MachineDeploymentClass.
+	// +optional
+	MachineSetNamingStrategy *MachineSetNamingStrategy `json:"machineSetNamingStrategy,omitempty"`
+
+	// MachineNamingStrategy allows changing the naming pattern used when creating the Machines, and
+	// their bootstrap configs and InfrastructureMachines, of a MachineDeployment using this MachineDeploymentClass.
+	// +optional
+	MachineNamingStrategy *MachineNamingStrategy `json:"machineNamingStrategy,omitempty"`
+
+	// Autoscaling configures how the capacity of the Machines of a MachineDeployment using this MachineDeploymentClass
+	// is exposed to the cluster-autoscaler, so it can scale the MachineDeployment from zero.
+	// +optional
+	Autoscaling *MachineDeploymentClassAutoscaling `json:"autoscaling,omitempty"`
+
+	// Variables defines the variables which are only available to MachineDeployments using this
+	// MachineDeploymentClass. This allows a MachineDeploymentClass to expose settings that are specific to
+	// the bootstrap or infrastructure provider it is using, e.g. when MachineDeploymentClasses of the same
+	// ClusterClass are using different bootstrap providers.
+	// Values for these variables can be set via Cluster.spec.topology.workers.machineDeployments[].variables.overrides.
+	// NOTE: Names must not conflict with the names of the variables defined in ClusterClass.spec.variables.
+	// +optional
+	Variables []ClusterClassVariable `json:"variables,omitempty"`
+}
+
+// MachineDeploymentClassAutoscaling defines how the capacity of the Machines of a MachineDeployment
+// is exposed to the cluster-autoscaler.
+//
+// The capacity annotations (capacity.cluster-autoscaler.kubernetes.io/*) of the InfrastructureMachineTemplate
+// of the MachineDeploymentClass are always propagated to the MachineDeployment, unless the same annotations are
+// set in the metadata of the MachineDeploymentClass or of the MachineDeployment topology.
+type MachineDeploymentClassAutoscaling struct {
+	// CapacityVariable is the name of a variable defining the capacity of the Machines of a MachineDeployment.
+	// The value of the variable, that can be overridden for each MachineDeployment topology, must be an object
+	// with the optional cpu, memory, ephemeralDisk, gpuType, gpuCount and maxPods properties; each property
+	// is set as the corresponding capacity annotation on the MachineDeployment, overriding the annotations
+	// from any other source.
+	// +optional
+	CapacityVariable *string `json:"capacityVariable,omitempty"`
+}
+
+// MachineDeploymentClassNamingStrategy defines the naming strategy for MachineDeployment objects.
+type MachineDeploymentClassNamingStrategy struct {
+	// Template defines the template to use for generating the name of the MachineDeployment object
+	// and of its bootstrap and infrastructure templates.
+	// If not defined, it will fallback to `{{ .cluster.name }}-{{ .machineDeployment.topologyName }}-{{ .random }}`
+	// for the MachineDeployment object, and to `{{ .cluster.name }}-{{ .machineDeployment.topologyName }}-bootstrap-{{ .random }}`
+	// and `{{ .cluster.name }}-{{ .machineDeployment.topologyName }}-infra-{{ .random }}` for the templates.
+	// If the templated string exceeds 63 characters, it will be trimmed to 58 characters and will
+	// get concatenated with a random suffix of length 5.
+	// The templating mechanism provides the following arguments:
+	// * `.cluster.name`: The name of the cluster object.
+	// * `.random`: A random alphanumeric string, without vowels, of length 5.
+	// * `.machineDeployment.topologyName`: The name of the MachineDeployment topology (Cluster.spec.topology.workers.machineDeployments[].name).
+	// The template must contain `{{ .random }}`.
+	// +optional
+	Template *string `json:"template,omitempty"`
+}
+
+// IPAddressClaimTemplate defines an IPAddressClaim which is created for every Machine of a MachineDeployment
+// and how the allocated IP address is injected into the InfrastructureMachine of the Machine.
+type IPAddressClaimTemplate struct {
+	// Name of the IPAddressClaimTemplate. It must be unique within the MachineDeploymentClass and is used
+	// as a suffix of the IPAddressClaim name and to expose the allocated IP address via
+	// the builtin.machine.ipAddresses.<name> variable.
+	Name string `json:"name"`
+
+	// PoolRef is a reference to the pool from which an IP address should be allocated.
+	PoolRef corev1.TypedLocalObjectReference `json:"poolRef"`
+
+	// JSONPatches defines the patches which are applied to the InfrastructureMachine of a Machine
+	// once an IP address has been allocated for it.
+	// Only the builtin.machine variable is available when computing the patches.
+	JSONPatches []JSONPatch `json:"jsonPatches"`
 }
 
 // MachineDeploymentClassTemplate defines how a MachineDeployment generated from a MachineDeploymentClass
@@ -17,6 +17,8 @@ limitations under the License.
 package v1beta1
 
 import (
+	"strings"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -33,6 +35,10 @@ const (
 	// to track the name of the MachineDeployment topology it represents.
 	ClusterTopologyMachineDeploymentNameLabel = "topology.cluster.x-k8s.io/deployment-name"
 
+	// ClusterTopologyAddonNameLabel is the label set on the generated ClusterResourceSet objects
+	// to track the name of the ClusterClass add-on they represent.
+	ClusterTopologyAddonNameLabel = "topology.cluster.x-k8s.io/addon-name"
+
 	// ClusterTopologyHoldUpgradeSequenceAnnotation can be used to hold the entire MachineDeployment upgrade sequence.
 	// If the annotation is set on a MachineDeployment topology in Cluster.spec.topology.workers, the Kubernetes upgrade
 	// for this MachineDeployment topology and all subsequent ones is deferred.
@@ -57,6 +63,31 @@ const (
 	// a classy Cluster to define the maximum concurrency while upgrading MachineDeployments.
 	ClusterTopologyUpgradeConcurrencyAnnotation = "topology.cluster.x-k8s.io/upgrade-concurrency"
 
+	// ClusterTopologyVariableValueSourcesHashAnnotation is the annotation set by the topology controller on a classy
+	// Cluster with variables using valueFrom; it contains a hash of the values read from the ConfigMaps and Secrets,
+	// so that changes to the values, and the rollouts they trigger, can be tracked on the Cluster.
+	ClusterTopologyVariableValueSourcesHashAnnotation = "topology.cluster.x-k8s.io/variable-value-sources-hash"
+
+	// ClusterTopologyControlPlaneRevisionAnnotation is the annotation set by the topology controller on the Cluster
+	// object of a classy Cluster to track the revision of the control plane templates of the ClusterClass the control
+	// plane has been rolled out to.
+	ClusterTopologyControlPlaneRevisionAnnotation = "topology.cluster.x-k8s.io/control-plane-revision"
+
+	// ClusterTopologyControlPlaneRolloutInProgressAnnotation is the annotation set by the topology controller on the
+	// Cluster object of a classy Cluster while the control plane is rolling out to a new revision of the control plane
+	// templates of the ClusterClass. The value is the generation of the control plane being rolled out.
+	ClusterTopologyControlPlaneRolloutInProgressAnnotation = "topology.cluster.x-k8s.io/control-plane-rollout-in-progress"
+
+	// ClusterTopologyControlPlaneRolloutCompletedAnnotation is the annotation set by the topology controller on the
+	// Cluster object of a classy Cluster when the control plane completed a rollout to a new revision of the control
+	// plane templates of the ClusterClass. The value is the completion time in RFC3339 format.
+	ClusterTopologyControlPlaneRolloutCompletedAnnotation = "topology.cluster.x-k8s.io/control-plane-rollout-completed"
+
+	// ClusterTopologyControlPlaneRolloutStartedAnnotation is the annotation set by the topology controller on the
+	// Cluster object of a classy Cluster while the control plane is rolling out to a new revision of the control plane
+	// templates of the ClusterClass. The value is the start time in RFC3339 format.
+	ClusterTopologyControlPlaneRolloutStartedAnnotation = "topology.cluster.x-k8s.io/control-plane-rollout-started"
+
 	// ClusterTopologyMachinePoolNameLabel is the label set on the generated  MachinePool objects
 	// to track the name of the MachinePool topology it represents.
 	ClusterTopologyMachinePoolNameLabel = "topology.cluster.x-k8s.io/pool-name"
@@ -65,6 +96,27 @@ const (
 	// update that disallows a pre-existing Cluster to be populated with Topology information and Class.
 	ClusterTopologyUnsafeUpdateClassNameAnnotation = "unsafe.topology.cluster.x-k8s.io/disable-update-class-name-check"
 
+	// ClusterTopologyAdoptAnnotation can be set on an existing Cluster without a managed topology, together with
+	// Cluster.spec.topology, to attach it to a ClusterClass. The topology controller adopts the existing
+	// InfrastructureCluster, ControlPlane and MachineDeployments, instead of creating new ones, and then removes
+	// the annotation.
+	// NOTE: MachineDeployments are adopted by the MachineDeployment topology with the same name.
+	ClusterTopologyAdoptAnnotation = "topology.cluster.x-k8s.io/adopt"
+
+	// ClusterClassReplicationLabel can be set to "true" on a Namespace to opt-in to the replication of the
+	// ClusterClasses and the referenced templates defined in the ClusterClass replication source namespace.
+	// NOTE: It is required to enable the ClusterClassReplication feature gate flag to use replication.
+	ClusterClassReplicationLabel = "clusterclass.cluster.x-k8s.io/replicate"
+
+	// ClusterClassReplicatedFromLabel is the label set on ClusterClasses and templates created by the ClusterClass
+	// replication controller; the value is the namespace of the source object.
+	ClusterClassReplicatedFromLabel = "clusterclass.cluster.x-k8s.io/replicated-from"
+
+	// ClusterClassReplicatedGenerationAnnotation is the annotation set on templates created by the ClusterClass
+	// replication controller; the value is the generation of the source template, and it is used to replace the
+	// replica when the spec of the source template changes.
+	ClusterClassReplicatedGenerationAnnotation = "clusterclass.cluster.x-k8s.io/replicated-generation"
+
 	// ProviderNameLabel is the label set on components in the provider manifest.
 	// This label allows to easily identify all the components belonging to a provider; the clusterctl
 	// tool uses this label for implementing provider's lifecycle operations.
@@ -85,6 +137,10 @@ const (
 	// LabelsFromMachineAnnotation is the annotation set on nodes to track the labels originated from machines.
 	LabelsFromMachineAnnotation = "cluster.x-k8s.io/labels-from-machine"
 
+	// CordonedByMachineAnnotation is the annotation set on nodes cordoned because of the cordon-node annotation
+	// on their machine, so they are uncordoned only if they were cordoned by Cluster API.
+	CordonedByMachineAnnotation = "cluster.x-k8s.io/cordoned-by-machine"
+
 	// OwnerNameAnnotation is the annotation set on nodes identifying the owner name.
 	OwnerNameAnnotation = "cluster.x-k8s.io/owner-name"
 
@@ -95,6 +151,21 @@ const (
 	// on the reconciled object.
 	PausedAnnotation = "cluster.x-k8s.io/paused"
 
+	// PausedByClusterAnnotation is set, together with the paused annotation, by the Cluster controller on the
+	// infrastructure, control plane and bootstrap objects of a paused Cluster; it allows the Cluster controller to
+	// remove the paused annotation once the Cluster is unpaused, preserving the paused annotations set by users.
+	// It is also set on the Cluster itself once its pause state has been propagated.
+	PausedByClusterAnnotation = "cluster.x-k8s.io/paused-by-cluster"
+
+	// IgnoreMaintenanceWindowsAnnotation can be applied to Clusters to allow disruptive operations, e.g. rollouts and
+	// remediation, outside of the maintenance windows defined in the Cluster spec.
+	IgnoreMaintenanceWindowsAnnotation = "cluster.x-k8s.io/ignore-maintenance-windows"
+
+	// ReconcilePriorityAnnotation is an annotation that can be applied to Clusters to set the priority
+	// of the reconciliation of the Cluster and of its objects; supported values are high, normal and low.
+	// Clusters without the annotation have normal priority.
+	ReconcilePriorityAnnotation = "cluster.x-k8s.io/reconcile-priority"
+
 	// DisableMachineCreateAnnotation is an annotation that can be used to signal a MachineSet to stop creating new machines.
 	// It is utilized in the OnDelete MachineDeploymentStrategy to allow the MachineDeployment controller to scale down
 	// older MachineSets when Machines are deleted and add the new replicas to the latest MachineSet.
@@ -133,6 +204,11 @@ const (
 	// the MachineSet.
 	MachineSetSkipPreflightChecksAnnotation = "machineset.cluster.x-k8s.io/skip-preflight-checks"
 
+	// MachineSetAdoptAnnotation is the annotation used to allow a MachineSet to adopt an orphaned Machine
+	// when the MachineSet controller requires explicit adoptions; the value must be the name of the MachineSet.
+	// Example: "machineset.cluster.x-k8s.io/adopt": "md-1-abcde".
+	MachineSetAdoptAnnotation = "machineset.cluster.x-k8s.io/adopt"
+
 	// ClusterSecretType defines the type of secret created by core components.
 	// Note: This is used by core CAPI, CAPBK, and KCP to determine whether a secret is created by the controllers
 	// themselves or supplied by the user (e.g. bring your own certificates).
@@ -149,6 +225,11 @@ const (
 	// External infrastructure providers should ensure that the annotation, once set, cannot be removed.
 	ManagedByAnnotation = "cluster.x-k8s.io/managed-by"
 
+	// OperationIDAnnotation is an annotation set by clusterctl on the objects touched by an operation, e.g. init, move,
+	// upgrade or rollout. Its value is the ID of the clusterctl invocation, and it is added to the logs of the controllers
+	// reconciling the object, so the controller activity can be correlated with the clusterctl operation that triggered it.
+	OperationIDAnnotation = "cluster.x-k8s.io/operation-id"
+
 	// TopologyDryRunAnnotation is an annotation that gets set on objects by the topology controller
 	// only during a server side dry run apply operation. It is used for validating
 	// update webhooks for objects which get updated by template rotation (e.g. InfrastructureMachineTemplate).
@@ -164,6 +245,13 @@ const (
 	// This annotation can be used to inform MachinePool status during in-progress scaling scenarios.
 	ReplicasManagedByAnnotation = "cluster.x-k8s.io/replicas-managed-by"
 
+	// MachinePoolAdoptExistingMachinesAnnotation is an annotation that can be set on a MachinePool to make the MachinePool
+	// controller adopt the pre-existing provider instances reported by the InfraMachinePool, matching them by provider ID.
+	// The practical effect of this is that InfraMachines and stand-alone Machines with a provider ID in the
+	// InfraMachinePool's spec.providerIDList are adopted by the MachinePool, and Machines are created for them if missing.
+	// This annotation can be used to migrate existing node groups to MachinePools.
+	MachinePoolAdoptExistingMachinesAnnotation = "cluster.x-k8s.io/adopt-existing-machines"
+
 	// AutoscalerMinSizeAnnotation defines the minimum node group size.
 	// The annotation is used by autoscaler.
 	// The annotation is copied from kubernetes/autoscaler.
@@ -184,14 +272,45 @@ const (
 	// Note: It can be used by setting as top level annotation on MachineDeployment and MachineSets.
 	AutoscalerMaxSizeAnnotation = "cluster.x-k8s.io/cluster-api-autoscaler-node-group-max-size"
 
+	// AutoscalerCapacityAnnotationPrefix is the prefix of the annotations used by the autoscaler to know the capacity
+	// of the Machines of a MachineDeployment or MachineSet when scaling it from zero.
+	// Ref: https://github.com/kubernetes/autoscaler/blob/master/cluster-autoscaler/cloudprovider/clusterapi/README.md#scale-from-zero-support
+	AutoscalerCapacityAnnotationPrefix = "capacity.cluster-autoscaler.kubernetes.io/"
+
+	// AutoscalerCapacityCPUAnnotation defines the number of CPUs of the Machines, e.g. "2".
+	AutoscalerCapacityCPUAnnotation = AutoscalerCapacityAnnotationPrefix + "cpu"
+
+	// AutoscalerCapacityMemoryAnnotation defines the memory of the Machines, e.g. "8G".
+	AutoscalerCapacityMemoryAnnotation = AutoscalerCapacityAnnotationPrefix + "memory"
+
+	// AutoscalerCapacityEphemeralDiskAnnotation defines the ephemeral disk of the Machines, e.g. "100Gi".
+	AutoscalerCapacityEphemeralDiskAnnotation = AutoscalerCapacityAnnotationPrefix + "ephemeral-disk"
+
+	// AutoscalerCapacityGPUTypeAnnotation defines the type of the GPUs of the Machines, e.g. "nvidia.com/gpu".
+	AutoscalerCapacityGPUTypeAnnotation = AutoscalerCapacityAnnotationPrefix + "gpu-type"
+
+	// AutoscalerCapacityGPUCountAnnotation defines the number of GPUs of the Machines, e.g. "1".
+	AutoscalerCapacityGPUCountAnnotation = AutoscalerCapacityAnnotationPrefix + "gpu-count"
+
+	// AutoscalerCapacityMaxPodsAnnotation defines the maximum number of Pods on the Machines, e.g. "110".
+	AutoscalerCapacityMaxPodsAnnotation = AutoscalerCapacityAnnotationPrefix + "maxPods"
+
 	// VariableDefinitionFromInline indicates a patch or variable was defined in the `.spec` of a ClusterClass
 	// rather than from an external patch extension.
 	VariableDefinitionFromInline = "inline"
 )
 
 // MachineSetPreflightCheck defines a valid MachineSet preflight check.
+// Besides the preflight checks defined below, providers can implement additional preflight checks
+// using Runtime Extensions for the MachineSetPreflightCheck hook; the names of provider-defined preflight checks
+// must be prefixed by a domain owned by the provider, e.g. "infrastructure.example.com/CapacityAvailable".
 type MachineSetPreflightCheck string
 
+// IsProviderDefined returns true if the preflight check is implemented by a provider.
+func (c MachineSetPreflightCheck) IsProviderDefined() bool {
+	return strings.Contains(string(c), "/")
+}
+
 const (
 	// MachineSetPreflightCheckAll can be used to represent all the MachineSet preflight checks.
 	MachineSetPreflightCheckAll MachineSetPreflightCheck = "All"
@@ -265,6 +384,18 @@ type MachineAddress struct {
 // MachineAddresses is a slice of MachineAddress items to be used by infrastructure providers.
 type MachineAddresses []MachineAddress
 
+// SummaryRow is a key/value pair describing an infrastructure object, e.g. the ID of a VPC or
+// the type of an instance. Infrastructure providers can optionally surface a list of rows in
+// `status.summary` of InfrastructureCluster and InfrastructureMachine objects, so this
+// provider-specific information is shown by standard tooling like `clusterctl describe cluster`.
+type SummaryRow struct {
+	// Key is the name of the row, e.g. "VPC ID".
+	Key string `json:"key"`
+
+	// Value is the value of the row.
+	Value string `json:"value"`
+}
+
 // ObjectMeta is metadata that all persisted resources must have, which includes all objects
 // users must create. This is a copy of customizable fields from metav1.ObjectMeta.
 //
@@ -38,6 +38,10 @@ const (
 
 	// IncorrectExternalRefReason (Severity=Error) documents a CAPI object with an incorrect external object reference.
 	IncorrectExternalRefReason = "IncorrectExternalRef"
+
+	// WaitingForMaintenanceWindowReason (Severity=Info) documents a disruptive operation, e.g. a rollout or remediation,
+	// deferred until the next maintenance window of the Cluster opens.
+	WaitingForMaintenanceWindowReason = "WaitingForMaintenanceWindow"
 )
 
 const (
@@ -118,6 +122,13 @@ const (
 	// NOTE: This reason is used only as a fallback when the bootstrap object is not reporting its own ready condition.
 	WaitingForDataSecretFallbackReason = "WaitingForDataSecret"
 
+	// BootstrapExecSucceededCondition reports the progress and the result of the execution of the bootstrap data
+	// on the node, e.g. a readiness check or kubeadm failing, before the node joins the cluster.
+	// This condition is mirrored from the condition with the same type in the infrastructure ref object, if any;
+	// infrastructure providers can set it by observing the node, e.g. the bootstrap success sentinel file
+	// or the serial console output of the machine.
+	BootstrapExecSucceededCondition ConditionType = "BootstrapExecSucceeded"
+
 	// DrainingSucceededCondition provide evidence of the status of the node drain operation which happens during the machine
 	// deletion process.
 	DrainingSucceededCondition ConditionType = "DrainingSucceeded"
@@ -128,6 +139,10 @@ const (
 	// DrainingFailedReason (Severity=Warning) documents a machine node drain operation failed.
 	DrainingFailedReason = "DrainingFailed"
 
+	// DrainingSkippedReason (Severity=Warning) documents a machine node drain and wait for volume detach being skipped
+	// because the workload cluster has been unreachable for too long.
+	DrainingSkippedReason = "DrainingSkipped"
+
 	// PreDrainDeleteHookSucceededCondition reports a machine waiting for a PreDrainDeleteHook before being delete.
 	PreDrainDeleteHookSucceededCondition ConditionType = "PreDrainDeleteHookSucceeded"
 
@@ -142,6 +157,14 @@ const (
 
 	// WaitingForVolumeDetachReason (Severity=Info) provide evidence that a machine node waiting for volumes to be attached.
 	WaitingForVolumeDetachReason = "WaitingForVolumeDetach"
+
+	// WorkloadClusterReachableCondition reports whether the control plane of the workload cluster could be reached
+	// while deleting a machine. It is only set when skipping node drain and volume detach for unreachable clusters is enabled.
+	WorkloadClusterReachableCondition ConditionType = "WorkloadClusterReachable"
+
+	// WorkloadClusterUnreachableReason (Severity=Warning) documents a machine being deleted while the control plane of
+	// the workload cluster cannot be reached.
+	WorkloadClusterUnreachableReason = "WorkloadClusterUnreachable"
 )
 
 const (
@@ -232,6 +255,28 @@ const (
 	WaitingForAvailableMachinesReason = "WaitingForAvailableMachines"
 )
 
+// Conditions and condition Reasons for rollouts of MachineDeployments and control planes.
+
+const (
+	// RolloutInProgressCondition is True while a rollout is in progress, that is, while there are replicas that are not
+	// up-to-date with the desired spec; the message reports the progress of the rollout.
+	// NOTE: This condition is not included in the Ready summary of the objects.
+	RolloutInProgressCondition ConditionType = "RolloutInProgress"
+
+	// RollingOutReason documents a rollout in progress.
+	RollingOutReason = "RollingOut"
+
+	// RolloutCompletedReason (Severity=Info) documents that all the replicas are up-to-date with the desired spec.
+	RolloutCompletedReason = "RolloutCompleted"
+
+	// CanaryRollingBackReason documents a MachineDeployment scaling down canary Machines that have been rolled back.
+	CanaryRollingBackReason = "CanaryRollingBack"
+
+	// CanaryRolledBackReason (Severity=Warning) documents that the canary Machines of a MachineDeployment have been
+	// rolled back and the MachineDeployment is kept on the old MachineSets until the machine template changes again.
+	CanaryRolledBackReason = "CanaryRolledBack"
+)
+
 // Conditions and condition Reasons for  MachineSets.
 
 const (
@@ -286,6 +331,11 @@ const (
 	// not yet completed because Control Plane is not yet updated to match the desired topology spec.
 	TopologyReconciledControlPlaneUpgradePendingReason = "ControlPlaneUpgradePending"
 
+	// TopologyReconciledControlPlaneRolloutPendingReason (Severity=Info) documents reconciliation of a Cluster topology
+	// not yet completed because the rollout of the Control Plane to new control plane templates of the ClusterClass
+	// is held by the rollout policy of the ClusterClass.
+	TopologyReconciledControlPlaneRolloutPendingReason = "ControlPlaneRolloutPending"
+
 	// TopologyReconciledMachineDeploymentsCreatePendingReason (Severity=Info) documents reconciliation of a Cluster topology
 	// not yet completed because at least one of the MachineDeployments is yet to be created.
 	// This generally happens because new MachineDeployment creations are held off while the ControlPlane is not stable.
@@ -307,6 +357,11 @@ const (
 	// yet completed because the ClusterClass has not reconciled yet. If this condition persists there may be an issue
 	// with the ClusterClass surfaced in the ClusterClass status or controller logs.
 	TopologyReconciledClusterClassNotReconciledReason = "ClusterClassNotReconciled"
+
+	// TopologyReconciledAdoptionPendingReason (Severity=Info) documents reconciliation of a Cluster topology not
+	// yet completed because the existing objects of a Cluster attached to a ClusterClass using the
+	// topology.cluster.x-k8s.io/adopt annotation are still being adopted.
+	TopologyReconciledAdoptionPendingReason = "AdoptionPending"
 )
 
 // Conditions and condition reasons for ClusterClass.
@@ -33,6 +33,11 @@ const (
 	// ExcludeNodeDrainingAnnotation annotation explicitly skips node draining if set.
 	ExcludeNodeDrainingAnnotation = "machine.cluster.x-k8s.io/exclude-node-draining"
 
+	// CordonNodeAnnotation annotation, if set on a Machine, cordons the Node of the Machine, i.e. marks it unschedulable;
+	// the Node is cordoned again if it gets uncordoned while the annotation is set. When the annotation is removed,
+	// the Node is uncordoned if it was cordoned because of the annotation.
+	CordonNodeAnnotation = "machine.cluster.x-k8s.io/cordon-node"
+
 	// ExcludeWaitForNodeVolumeDetachAnnotation annotation explicitly skips the waiting for node volume detaching if set.
 	ExcludeWaitForNodeVolumeDetachAnnotation = "machine.cluster.x-k8s.io/exclude-wait-for-node-volume-detach"
 
@@ -122,6 +127,15 @@ type MachineSpec struct {
 	// +optional
 	NodeDrainTimeout *metav1.Duration `json:"nodeDrainTimeout,omitempty"`
 
+	// NodeDrainEvictionTimeout is the total amount of time that the controller will spend on evicting Pods from
+	// a node using the Eviction API, which honors PodDisruptionBudgets. When exceeded, the Pods which are still
+	// to be evicted are deleted instead, without honoring PodDisruptionBudgets.
+	// The default value is 0, meaning that Pods are always evicted using the Eviction API.
+	// NOTE: NodeDrainEvictionTimeout should be shorter than NodeDrainTimeout, if set, because draining is skipped
+	// entirely once NodeDrainTimeout is exceeded.
+	// +optional
+	NodeDrainEvictionTimeout *metav1.Duration `json:"nodeDrainEvictionTimeout,omitempty"`
+
 	// NodeVolumeDetachTimeout is the total amount of time that the controller will spend on waiting for all volumes
 	// to be detached. The default value is 0, meaning that the volumes can be detached without any time limitations.
 	// +optional
@@ -132,8 +146,37 @@ type MachineSpec struct {
 	// Defaults to 10 seconds.
 	// +optional
 	NodeDeletionTimeout *metav1.Duration `json:"nodeDeletionTimeout,omitempty"`
+
+	// NodeStartupTimeout is the total amount of time that the controller will wait for the Machine
+	// to get a Node reference after the Machine has been created. When exceeded, the NodeHealthy condition
+	// is set to false and, if the Machine is owned by a MachineSet, the Machine is remediated by the MachineSet.
+	// The default value is 0, meaning that the controller will wait for the Node without any time limitations.
+	// NOTE: NodeStartupTimeout works independently of MachineHealthChecks.
+	// +optional
+	NodeStartupTimeout *metav1.Duration `json:"nodeStartupTimeout,omitempty"`
+
+	// NodeReRegistrationPolicy defines how the controller handles a Node that re-registered with the ProviderID
+	// of the Machine, but with a different UID than the Node the Machine is linked to, e.g. after the host
+	// has been reprovisioned at the OS level.
+	// When not set, the Node is accepted and the UID of the Node the Machine is linked to is updated.
+	// +kubebuilder:validation:Enum=NodeNotFound;Relink
+	// +optional
+	NodeReRegistrationPolicy NodeReRegistrationPolicy `json:"nodeReRegistrationPolicy,omitempty"`
 }
 
+// NodeReRegistrationPolicy defines how the controller handles a Node that re-registered with the ProviderID of a Machine.
+type NodeReRegistrationPolicy string
+
+const (
+	// NodeNotFoundNodeReRegistrationPolicy reports the Node the Machine is linked to as not found when a Node
+	// with the same ProviderID but a different UID is registered.
+	NodeNotFoundNodeReRegistrationPolicy NodeReRegistrationPolicy = "NodeNotFound"
+
+	// RelinkNodeReRegistrationPolicy links the Machine to the Node that re-registered with the same ProviderID
+	// but a different UID.
+	RelinkNodeReRegistrationPolicy NodeReRegistrationPolicy = "Relink"
+)
+
 // ANCHOR_END: MachineSpec
 
 // ANCHOR: MachineStatus
@@ -149,6 +192,17 @@ type MachineStatus struct {
 	// +optional
 	NodeInfo *corev1.NodeSystemInfo `json:"nodeInfo,omitempty"`
 
+	// Capacity represents the total resources of the Machine, e.g. CPU, memory, GPUs or other devices.
+	// This field is copied from the Node once it exists; before that, it is copied from the optional
+	// status.capacity field of the infrastructure provider reference, if any.
+	// +optional
+	Capacity corev1.ResourceList `json:"capacity,omitempty"`
+
+	// Allocatable represents the resources of the Machine that are available for scheduling.
+	// This field is copied from the Node.
+	// +optional
+	Allocatable corev1.ResourceList `json:"allocatable,omitempty"`
+
 	// LastUpdated identifies when the phase of the Machine last transitioned.
 	// +optional
 	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
@@ -206,6 +260,11 @@ type MachineStatus struct {
 	// +optional
 	CertificatesExpiryDate *metav1.Time `json:"certificatesExpiryDate,omitempty"`
 
+	// NodeDrainStartTime is the time when the drain of the node of the Machine started.
+	// It is used to compute the node drain timeouts and it is not changed when the drain is retried.
+	// +optional
+	NodeDrainStartTime *metav1.Time `json:"nodeDrainStartTime,omitempty"`
+
 	// BootstrapReady is the state of the bootstrap provider.
 	// +optional
 	BootstrapReady bool `json:"bootstrapReady"`
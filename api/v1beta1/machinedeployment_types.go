@@ -38,6 +38,11 @@ const (
 	// OnDeleteMachineDeploymentStrategyType replaces old MachineSets when the deletion of the associated machines are completed.
 	OnDeleteMachineDeploymentStrategyType MachineDeploymentStrategyType = "OnDelete"
 
+	// CanaryMachineDeploymentStrategyType brings up a limited number of canary Machines from the new MachineSet
+	// while keeping the old MachineSets at full size; once the canary Machines are available and the AfterCanaryReady
+	// Runtime Hook (if any) approves them, the old MachineSet is replaced by the new one using rolling update.
+	CanaryMachineDeploymentStrategyType MachineDeploymentStrategyType = "Canary"
+
 	// RevisionAnnotation is the revision annotation of a machine deployment's machine sets which records its rollout sequence.
 	RevisionAnnotation = "machinedeployment.clusters.x-k8s.io/revision"
 
@@ -54,6 +59,18 @@ const (
 	// proportions in case the deployment has surge replicas.
 	MaxReplicasAnnotation = "machinedeployment.clusters.x-k8s.io/max-replicas"
 
+	// CanaryAnnotation records the outcome of the canary phase of a machine deployment using the Canary
+	// strategy on the new machine set; valid values are "Approved" and "RolledBack".
+	CanaryAnnotation = "machinedeployment.clusters.x-k8s.io/canary"
+
+	// CanaryApproved is the value of the CanaryAnnotation when the canary Machines have been approved
+	// and the rollout proceeds using rolling update.
+	CanaryApproved = "Approved"
+
+	// CanaryRolledBack is the value of the CanaryAnnotation when the canary Machines have been rejected
+	// and the new machine set has been scaled down to zero.
+	CanaryRolledBack = "RolledBack"
+
 	// MachineDeploymentUniqueLabel is used to uniquely identify the Machines of a MachineSet.
 	// The MachineDeployment controller will set this label on a MachineSet when it is created.
 	// The label is also applied to the Machines of the MachineSet and used in the MachineSet selector.
@@ -126,6 +143,22 @@ type MachineDeploymentSpec struct {
 	// +optional
 	MinReadySeconds *int32 `json:"minReadySeconds,omitempty"`
 
+	// FailureDomainSpreadPolicy defines how Machines are spread across the failure domains of the Cluster.
+	// The policy is propagated in-place to the MachineSets and applies to the Machines of each MachineSet.
+	// If not set, Machines are created in the failure domain defined in the Machine template.
+	// +optional
+	FailureDomainSpreadPolicy *FailureDomainSpreadPolicy `json:"failureDomainSpreadPolicy,omitempty"`
+
+	// MachineSetNamingStrategy allows changing the naming pattern used when creating the MachineSets
+	// of this MachineDeployment.
+	// +optional
+	MachineSetNamingStrategy *MachineSetNamingStrategy `json:"machineSetNamingStrategy,omitempty"`
+
+	// MachineNamingStrategy allows changing the naming pattern used when creating the Machines of this
+	// MachineDeployment. The strategy is propagated in-place to the MachineSets.
+	// +optional
+	MachineNamingStrategy *MachineNamingStrategy `json:"machineNamingStrategy,omitempty"`
+
 	// The number of old MachineSets to retain to allow rollback.
 	// This is a pointer to distinguish between explicit zero and not specified.
 	// Defaults to 1.
@@ -154,18 +187,39 @@ type MachineDeploymentSpec struct {
 type MachineDeploymentStrategy struct {
 	// Type of deployment.
 	// Default is RollingUpdate.
-	// +kubebuilder:validation:Enum=RollingUpdate;OnDelete
+	// +kubebuilder:validation:Enum=RollingUpdate;OnDelete;Canary
 	// +optional
 	Type MachineDeploymentStrategyType `json:"type,omitempty"`
 
 	// Rolling update config params. Present only if
-	// MachineDeploymentStrategyType = RollingUpdate.
+	// MachineDeploymentStrategyType = RollingUpdate or Canary; with the Canary strategy
+	// these params are used once the canary Machines have been approved.
 	// +optional
 	RollingUpdate *MachineRollingUpdateDeployment `json:"rollingUpdate,omitempty"`
+
+	// Canary config params. Present only if
+	// MachineDeploymentStrategyType = Canary.
+	// +optional
+	Canary *MachineCanaryDeployment `json:"canary,omitempty"`
 }
 
 // ANCHOR_END: MachineDeploymentStrategy
 
+// ANCHOR: MachineCanaryDeployment
+
+// MachineCanaryDeployment is used to control the desired behavior of the canary phase of a rollout.
+type MachineCanaryDeployment struct {
+	// Replicas is the number of canary Machines created from the new machine template
+	// while the old MachineSets are kept at full size.
+	// The number is capped to the desired number of machines of the MachineDeployment.
+	// Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+}
+
+// ANCHOR_END: MachineCanaryDeployment
+
 // ANCHOR: MachineRollingUpdateDeployment
 
 // MachineRollingUpdateDeployment is used to control the desired behavior of rolling update.
@@ -211,6 +265,21 @@ type MachineRollingUpdateDeployment struct {
 
 // ANCHOR_END: MachineRollingUpdateDeployment
 
+// MachineSetNamingStrategy defines the naming strategy for the MachineSets of a MachineDeployment.
+type MachineSetNamingStrategy struct {
+	// Template defines the template to use for generating the names of the MachineSets.
+	// If not defined, it will fallback to `{{ .machineDeployment.name }}-{{ .random }}`.
+	// If the templated string exceeds 63 characters, it will be trimmed and will get concatenated
+	// with the random suffix.
+	// The templating mechanism provides the following arguments:
+	// * `.cluster.name`: The name of the cluster object.
+	// * `.machineDeployment.name`: The name of the MachineDeployment object.
+	// * `.random`: A random alphanumeric string, without vowels, uniquely identifying the MachineSet.
+	// The template must contain `{{ .random }}`.
+	// +optional
+	Template *string `json:"template,omitempty"`
+}
+
 // ANCHOR: MachineDeploymentStatus
 
 // MachineDeploymentStatus defines the observed state of MachineDeployment.
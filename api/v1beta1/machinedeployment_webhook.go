@@ -130,11 +130,23 @@ func (webhook *machineDeploymentDefaulter) Default(ctx context.Context, obj runt
 		m.Spec.Template.Labels = make(map[string]string)
 	}
 
-	// Default RollingUpdate strategy only if strategy type is RollingUpdate.
-	if m.Spec.Strategy.Type == RollingUpdateMachineDeploymentStrategyType {
+	// Default RollingUpdate strategy only if strategy type is RollingUpdate or Canary.
+	if m.Spec.Strategy.Type == RollingUpdateMachineDeploymentStrategyType || m.Spec.Strategy.Type == CanaryMachineDeploymentStrategyType {
 		if m.Spec.Strategy.RollingUpdate == nil {
 			m.Spec.Strategy.RollingUpdate = &MachineRollingUpdateDeployment{}
 		}
+		// Machines named with ordinals are replaced by deleting them first, given that the replacement Machines
+		// reuse their names.
+		if m.Spec.MachineNamingStrategy != nil && m.Spec.MachineNamingStrategy.Type == OrdinalMachineNamingStrategyType {
+			if m.Spec.Strategy.RollingUpdate.MaxSurge == nil {
+				ios0 := intstr.FromInt(0)
+				m.Spec.Strategy.RollingUpdate.MaxSurge = &ios0
+			}
+			if m.Spec.Strategy.RollingUpdate.MaxUnavailable == nil {
+				ios1 := intstr.FromInt(1)
+				m.Spec.Strategy.RollingUpdate.MaxUnavailable = &ios1
+			}
+		}
 		if m.Spec.Strategy.RollingUpdate.MaxSurge == nil {
 			ios1 := intstr.FromInt(1)
 			m.Spec.Strategy.RollingUpdate.MaxSurge = &ios1
@@ -145,6 +157,16 @@ func (webhook *machineDeploymentDefaulter) Default(ctx context.Context, obj runt
 		}
 	}
 
+	// Default Canary strategy only if strategy type is Canary.
+	if m.Spec.Strategy.Type == CanaryMachineDeploymentStrategyType {
+		if m.Spec.Strategy.Canary == nil {
+			m.Spec.Strategy.Canary = &MachineCanaryDeployment{}
+		}
+		if m.Spec.Strategy.Canary.Replicas == nil {
+			m.Spec.Strategy.Canary.Replicas = pointer.Int32(1)
+		}
+	}
+
 	// If no selector has been provided, add label and selector for the
 	// MachineDeployment's name as a default way of providing uniqueness.
 	if len(m.Spec.Selector.MatchLabels) == 0 && len(m.Spec.Selector.MatchExpressions) == 0 {
@@ -260,12 +282,64 @@ func (m *MachineDeployment) validate(old *MachineDeployment) error {
 		}
 	}
 
+	if m.Spec.Strategy != nil && m.Spec.Strategy.Canary != nil && m.Spec.Strategy.Type != CanaryMachineDeploymentStrategyType {
+		allErrs = append(
+			allErrs,
+			field.Forbidden(
+				specPath.Child("strategy", "canary"),
+				fmt.Sprintf("can only be set when strategy type is %s", CanaryMachineDeploymentStrategyType),
+			),
+		)
+	}
+
 	if m.Spec.Template.Spec.Version != nil {
 		if !version.KubeSemver.MatchString(*m.Spec.Template.Spec.Version) {
 			allErrs = append(allErrs, field.Invalid(specPath.Child("template", "spec", "version"), *m.Spec.Template.Spec.Version, "must be a valid semantic version"))
 		}
 	}
 
+	if m.Spec.FailureDomainSpreadPolicy != nil && m.Spec.Template.Spec.FailureDomain != nil {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("template", "spec", "failureDomain"), "cannot be set when spec.failureDomainSpreadPolicy is set"))
+	}
+
+	if m.Spec.MachineSetNamingStrategy != nil && m.Spec.MachineSetNamingStrategy.Template != nil {
+		allErrs = append(allErrs, validateNamingStrategyTemplate(*m.Spec.MachineSetNamingStrategy.Template, map[string]interface{}{
+			"cluster":           map[string]interface{}{"name": "cluster"},
+			"machineDeployment": map[string]interface{}{"name": "machinedeployment"},
+		}, specPath.Child("machineSetNamingStrategy", "template"))...)
+	}
+	allErrs = append(allErrs, validateMachineNamingStrategy(m.Spec.MachineNamingStrategy, specPath.Child("machineNamingStrategy"))...)
+
+	// Machines named with ordinals can only be replaced after the Machines with the same ordinal are deleted,
+	// so rollouts must not create Machines in excess of replicas.
+	if m.Spec.MachineNamingStrategy != nil && m.Spec.MachineNamingStrategy.Type == OrdinalMachineNamingStrategyType && m.Spec.Strategy != nil {
+		switch m.Spec.Strategy.Type {
+		case OnDeleteMachineDeploymentStrategyType:
+			// Machines are replaced only after being deleted.
+		case RollingUpdateMachineDeploymentStrategyType:
+			if m.Spec.Strategy.RollingUpdate != nil && m.Spec.Strategy.RollingUpdate.MaxSurge != nil {
+				maxSurge, err := intstr.GetScaledValueFromIntOrPercent(m.Spec.Strategy.RollingUpdate.MaxSurge, int(pointer.Int32Deref(m.Spec.Replicas, 1)), true)
+				if err == nil && maxSurge > 0 {
+					allErrs = append(
+						allErrs,
+						field.Forbidden(
+							specPath.Child("strategy", "rollingUpdate", "maxSurge"),
+							fmt.Sprintf("must be 0 when spec.machineNamingStrategy.type is %s", OrdinalMachineNamingStrategyType),
+						),
+					)
+				}
+			}
+		default:
+			allErrs = append(
+				allErrs,
+				field.Forbidden(
+					specPath.Child("strategy", "type"),
+					fmt.Sprintf("must be %s or %s when spec.machineNamingStrategy.type is %s", RollingUpdateMachineDeploymentStrategyType, OnDeleteMachineDeploymentStrategyType, OrdinalMachineNamingStrategyType),
+				),
+			)
+		}
+	}
+
 	if len(allErrs) == 0 {
 		return nil
 	}
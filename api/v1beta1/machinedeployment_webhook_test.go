@@ -78,6 +78,142 @@ func TestMachineDeploymentDefault(t *testing.T) {
 	g.Expect(*md.Spec.Template.Spec.Version).To(Equal("v1.19.10"))
 }
 
+func TestMachineDeploymentDefaultCanaryStrategy(t *testing.T) {
+	g := NewWithT(t)
+	md := &MachineDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-md",
+		},
+		Spec: MachineDeploymentSpec{
+			ClusterName: "test-cluster",
+			Strategy: &MachineDeploymentStrategy{
+				Type: CanaryMachineDeploymentStrategyType,
+			},
+		},
+	}
+
+	scheme, err := SchemeBuilder.Build()
+	g.Expect(err).ToNot(HaveOccurred())
+	defaulter := MachineDeploymentDefaulter(scheme)
+
+	ctx := admission.NewContextWithRequest(context.Background(), admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: admissionv1.Create,
+		},
+	})
+	g.Expect(defaulter.Default(ctx, md)).To(Succeed())
+
+	g.Expect(md.Spec.Strategy.RollingUpdate).ToNot(BeNil())
+	g.Expect(md.Spec.Strategy.RollingUpdate.MaxSurge.IntValue()).To(Equal(1))
+	g.Expect(md.Spec.Strategy.RollingUpdate.MaxUnavailable.IntValue()).To(Equal(0))
+	g.Expect(md.Spec.Strategy.Canary).ToNot(BeNil())
+	g.Expect(md.Spec.Strategy.Canary.Replicas).To(Equal(pointer.Int32(1)))
+}
+
+func TestMachineDeploymentDefaultOrdinalMachineNamingStrategy(t *testing.T) {
+	g := NewWithT(t)
+	md := &MachineDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-md",
+		},
+		Spec: MachineDeploymentSpec{
+			ClusterName: "test-cluster",
+			MachineNamingStrategy: &MachineNamingStrategy{
+				Type: OrdinalMachineNamingStrategyType,
+			},
+		},
+	}
+
+	scheme, err := SchemeBuilder.Build()
+	g.Expect(err).ToNot(HaveOccurred())
+	defaulter := MachineDeploymentDefaulter(scheme)
+
+	ctx := admission.NewContextWithRequest(context.Background(), admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: admissionv1.Create,
+		},
+	})
+	g.Expect(defaulter.Default(ctx, md)).To(Succeed())
+
+	g.Expect(md.Spec.Strategy.Type).To(Equal(RollingUpdateMachineDeploymentStrategyType))
+	g.Expect(md.Spec.Strategy.RollingUpdate.MaxSurge.IntValue()).To(Equal(0))
+	g.Expect(md.Spec.Strategy.RollingUpdate.MaxUnavailable.IntValue()).To(Equal(1))
+
+	_, err = md.ValidateCreate()
+	g.Expect(err).ToNot(HaveOccurred())
+}
+
+func TestMachineDeploymentOrdinalMachineNamingStrategyValidation(t *testing.T) {
+	maxSurgeZero := intstr.FromInt(0)
+	maxSurgeZeroPercentage := intstr.FromString("0%")
+	maxSurgeOne := intstr.FromInt(1)
+	maxUnavailableOne := intstr.FromInt(1)
+
+	tests := []struct {
+		name      string
+		strategy  MachineDeploymentStrategy
+		expectErr bool
+	}{
+		{
+			name: "pass with RollingUpdate strategy and maxSurge 0",
+			strategy: MachineDeploymentStrategy{
+				Type:          RollingUpdateMachineDeploymentStrategyType,
+				RollingUpdate: &MachineRollingUpdateDeployment{MaxSurge: &maxSurgeZero, MaxUnavailable: &maxUnavailableOne},
+			},
+			expectErr: false,
+		},
+		{
+			name: "pass with RollingUpdate strategy and maxSurge 0%",
+			strategy: MachineDeploymentStrategy{
+				Type:          RollingUpdateMachineDeploymentStrategyType,
+				RollingUpdate: &MachineRollingUpdateDeployment{MaxSurge: &maxSurgeZeroPercentage, MaxUnavailable: &maxUnavailableOne},
+			},
+			expectErr: false,
+		},
+		{
+			name:      "pass with OnDelete strategy",
+			strategy:  MachineDeploymentStrategy{Type: OnDeleteMachineDeploymentStrategyType},
+			expectErr: false,
+		},
+		{
+			name: "should return error with RollingUpdate strategy and maxSurge greater than 0",
+			strategy: MachineDeploymentStrategy{
+				Type:          RollingUpdateMachineDeploymentStrategyType,
+				RollingUpdate: &MachineRollingUpdateDeployment{MaxSurge: &maxSurgeOne, MaxUnavailable: &maxUnavailableOne},
+			},
+			expectErr: true,
+		},
+		{
+			name: "should return error with Canary strategy",
+			strategy: MachineDeploymentStrategy{
+				Type:          CanaryMachineDeploymentStrategyType,
+				RollingUpdate: &MachineRollingUpdateDeployment{MaxSurge: &maxSurgeZero, MaxUnavailable: &maxUnavailableOne},
+				Canary:        &MachineCanaryDeployment{Replicas: pointer.Int32(1)},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			md := &MachineDeployment{
+				Spec: MachineDeploymentSpec{
+					Replicas:              pointer.Int32(3),
+					Strategy:              &tt.strategy,
+					MachineNamingStrategy: &MachineNamingStrategy{Type: OrdinalMachineNamingStrategyType},
+				},
+			}
+			_, err := md.ValidateCreate()
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+		})
+	}
+}
+
 func TestCalculateMachineDeploymentReplicas(t *testing.T) {
 	tests := []struct {
 		name             string
@@ -371,6 +507,38 @@ func TestMachineDeploymentValidation(t *testing.T) {
 			},
 			expectErr: false,
 		},
+		{
+			name:      "should not return error for canary with Canary strategy",
+			selectors: map[string]string{"foo": "bar"},
+			labels:    map[string]string{"foo": "bar"},
+			strategy: MachineDeploymentStrategy{
+				Type: CanaryMachineDeploymentStrategyType,
+				RollingUpdate: &MachineRollingUpdateDeployment{
+					MaxUnavailable: &goodMaxUnavailableInt,
+					MaxSurge:       &goodMaxSurgeInt,
+				},
+				Canary: &MachineCanaryDeployment{
+					Replicas: pointer.Int32(1),
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name:      "should return error for canary with RollingUpdate strategy",
+			selectors: map[string]string{"foo": "bar"},
+			labels:    map[string]string{"foo": "bar"},
+			strategy: MachineDeploymentStrategy{
+				Type: RollingUpdateMachineDeploymentStrategyType,
+				RollingUpdate: &MachineRollingUpdateDeployment{
+					MaxUnavailable: &goodMaxUnavailableInt,
+					MaxSurge:       &goodMaxSurgeInt,
+				},
+				Canary: &MachineCanaryDeployment{
+					Replicas: pointer.Int32(1),
+				},
+			},
+			expectErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,116 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MachineDrainRuleDrainBehavior defines how Pods matching a MachineDrainRule are handled when draining a Node.
+type MachineDrainRuleDrainBehavior string
+
+const (
+	// MachineDrainRuleDrainBehaviorDrain means that the Pods are evicted in the order defined by the
+	// MachineDrainRule, together with the Pods not selected by any MachineDrainRule, which have order 0.
+	MachineDrainRuleDrainBehaviorDrain MachineDrainRuleDrainBehavior = "Drain"
+
+	// MachineDrainRuleDrainBehaviorSkip means that the Pods are not evicted; the drain completes
+	// without waiting for them.
+	MachineDrainRuleDrainBehaviorSkip MachineDrainRuleDrainBehavior = "Skip"
+
+	// MachineDrainRuleDrainBehaviorDrainLast means that the Pods are evicted only after all the other
+	// Pods have been evicted and all the Pods with the WaitCompleted behavior have completed.
+	MachineDrainRuleDrainBehaviorDrainLast MachineDrainRuleDrainBehavior = "DrainLast"
+
+	// MachineDrainRuleDrainBehaviorWaitCompleted means that the Pods are not evicted; the drain waits
+	// for them to complete, i.e. to reach the Succeeded or Failed phase.
+	MachineDrainRuleDrainBehaviorWaitCompleted MachineDrainRuleDrainBehavior = "WaitCompleted"
+)
+
+// MachineDrainRuleSpec defines the spec of a MachineDrainRule.
+type MachineDrainRuleSpec struct {
+	// Behavior defines how the selected Pods are handled when draining Nodes,
+	// one of Drain, Skip, DrainLast or WaitCompleted.
+	// If a Pod is selected by more than one MachineDrainRule, Skip takes precedence over
+	// WaitCompleted, which takes precedence over DrainLast, which takes precedence over Drain.
+	// The Drain behavior requires the MachineDrainRule feature gate to be enabled.
+	// +kubebuilder:validation:Enum=Drain;Skip;DrainLast;WaitCompleted
+	Behavior MachineDrainRuleDrainBehavior `json:"behavior"`
+
+	// Order defines the order in which the selected Pods are evicted when the behavior is Drain.
+	// Pods are evicted in ascending order, waiting for all the Pods with a lower order to be gone;
+	// Pods not selected by any MachineDrainRule have order 0, so Pods with a negative order are evicted
+	// before them and Pods with a positive order after them. All the Pods with the Drain behavior are evicted
+	// before waiting for the Pods with the WaitCompleted behavior.
+	// If a Pod is selected by more than one MachineDrainRule with the Drain behavior, the highest order is used.
+	// Can be set only if the behavior is Drain. Defaults to 0.
+	// Requires the MachineDrainRule feature gate to be enabled.
+	// +optional
+	Order *int32 `json:"order,omitempty"`
+
+	// ClusterSelector selects the Clusters, in the same namespace of the MachineDrainRule, the rule applies to;
+	// the rule is applied when draining the Nodes of all the Machines of the selected Clusters.
+	// If not set, the rule applies to all the Clusters in the namespace.
+	// +optional
+	ClusterSelector *metav1.LabelSelector `json:"clusterSelector,omitempty"`
+
+	// Pods selects the Pods in the workload cluster the rule applies to.
+	// A Pod is selected if it matches any of the entries.
+	// +kubebuilder:validation:MinItems=1
+	Pods []MachineDrainRulePodSelector `json:"pods"`
+}
+
+// MachineDrainRulePodSelector selects Pods in a workload cluster.
+type MachineDrainRulePodSelector struct {
+	// Selector is a label selector for the Pods.
+	// If not set, all the Pods in the namespaces selected by NamespaceSelector are selected.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// NamespaceSelector is a label selector for the namespaces of the Pods.
+	// If not set, Pods in all the namespaces are selected.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=machinedrainrules,shortName=mdr,scope=Namespaced,categories=cluster-api
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Behavior",type="string",JSONPath=".spec.behavior",description="Drain behavior of the selected Pods"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of MachineDrainRule"
+
+// MachineDrainRule is the Schema for the machinedrainrules API.
+type MachineDrainRule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the drain behavior for the selected Pods.
+	Spec MachineDrainRuleSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// MachineDrainRuleList contains a list of MachineDrainRule.
+type MachineDrainRuleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MachineDrainRule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MachineDrainRule{}, &MachineDrainRuleList{})
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"sigs.k8s.io/cluster-api/feature"
+)
+
+func (m *MachineDrainRule) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(m).
+		Complete()
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-cluster-x-k8s-io-v1beta1-machinedrainrule,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=cluster.x-k8s.io,resources=machinedrainrules,versions=v1beta1,name=validation.machinedrainrule.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
+
+var _ webhook.Validator = &MachineDrainRule{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
+func (m *MachineDrainRule) ValidateCreate() (admission.Warnings, error) {
+	return nil, m.validate()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
+func (m *MachineDrainRule) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	if _, ok := old.(*MachineDrainRule); !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a MachineDrainRule but got a %T", old))
+	}
+	return nil, m.validate()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
+func (m *MachineDrainRule) ValidateDelete() (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (m *MachineDrainRule) validate() error {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	// NOTE: The Drain behavior and the order are behind the MachineDrainRule feature gate flag; the web hook
+	// must prevent using them in case the feature flag is disabled.
+	if !feature.Gates.Enabled(feature.MachineDrainRule) {
+		if m.Spec.Behavior == MachineDrainRuleDrainBehaviorDrain {
+			allErrs = append(
+				allErrs,
+				field.Forbidden(specPath.Child("behavior"), fmt.Sprintf("can be set to %s only if the MachineDrainRule feature flag is enabled", MachineDrainRuleDrainBehaviorDrain)),
+			)
+		}
+		if m.Spec.Order != nil {
+			allErrs = append(
+				allErrs,
+				field.Forbidden(specPath.Child("order"), "can be set only if the MachineDrainRule feature flag is enabled"),
+			)
+		}
+	}
+
+	if m.Spec.Order != nil && m.Spec.Behavior != MachineDrainRuleDrainBehaviorDrain {
+		allErrs = append(
+			allErrs,
+			field.Forbidden(specPath.Child("order"), fmt.Sprintf("can be set only if behavior is %s", MachineDrainRuleDrainBehaviorDrain)),
+		)
+	}
+
+	if m.Spec.ClusterSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(m.Spec.ClusterSelector); err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("clusterSelector"), m.Spec.ClusterSelector, err.Error()))
+		}
+	}
+
+	for i, podSelector := range m.Spec.Pods {
+		if podSelector.Selector != nil {
+			if _, err := metav1.LabelSelectorAsSelector(podSelector.Selector); err != nil {
+				allErrs = append(allErrs, field.Invalid(specPath.Child("pods").Index(i).Child("selector"), podSelector.Selector, err.Error()))
+			}
+		}
+		if podSelector.NamespaceSelector != nil {
+			if _, err := metav1.LabelSelectorAsSelector(podSelector.NamespaceSelector); err != nil {
+				allErrs = append(allErrs, field.Invalid(specPath.Child("pods").Index(i).Child("namespaceSelector"), podSelector.NamespaceSelector, err.Error()))
+			}
+		}
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind("MachineDrainRule").GroupKind(), m.Name, allErrs)
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilfeature "k8s.io/component-base/featuregate/testing"
+	"k8s.io/utils/pointer"
+
+	"sigs.k8s.io/cluster-api/feature"
+)
+
+func TestMachineDrainRuleValidation(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.MachineDrainRule, true)()
+
+	tests := []struct {
+		name      string
+		spec      MachineDrainRuleSpec
+		expectErr bool
+	}{
+		{
+			name: "should succeed with the Drain behavior and an order",
+			spec: MachineDrainRuleSpec{
+				Behavior: MachineDrainRuleDrainBehaviorDrain,
+				Order:    pointer.Int32(-1),
+				Pods:     []MachineDrainRulePodSelector{{}},
+			},
+			expectErr: false,
+		},
+		{
+			name: "should fail with an order and a behavior other than Drain",
+			spec: MachineDrainRuleSpec{
+				Behavior: MachineDrainRuleDrainBehaviorSkip,
+				Order:    pointer.Int32(1),
+				Pods:     []MachineDrainRulePodSelector{{}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "should fail with an invalid cluster selector",
+			spec: MachineDrainRuleSpec{
+				Behavior:        MachineDrainRuleDrainBehaviorSkip,
+				ClusterSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"-invalid": "value"}},
+				Pods:            []MachineDrainRulePodSelector{{}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "should fail with an invalid Pod selector",
+			spec: MachineDrainRuleSpec{
+				Behavior: MachineDrainRuleDrainBehaviorWaitCompleted,
+				Pods: []MachineDrainRulePodSelector{{
+					NamespaceSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "team", Operator: "Unknown"}}},
+				}},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			m := &MachineDrainRule{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+				Spec:       tt.spec,
+			}
+
+			_, createErr := m.ValidateCreate()
+			_, updateErr := m.ValidateUpdate(m.DeepCopy())
+			if tt.expectErr {
+				g.Expect(createErr).To(HaveOccurred())
+				g.Expect(updateErr).To(HaveOccurred())
+			} else {
+				g.Expect(createErr).ToNot(HaveOccurred())
+				g.Expect(updateErr).ToNot(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestMachineDrainRuleValidationFeatureGateDisabled(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.MachineDrainRule, false)()
+
+	tests := []struct {
+		name      string
+		spec      MachineDrainRuleSpec
+		expectErr bool
+	}{
+		{
+			name: "should succeed with the Skip behavior",
+			spec: MachineDrainRuleSpec{
+				Behavior: MachineDrainRuleDrainBehaviorSkip,
+				Pods:     []MachineDrainRulePodSelector{{}},
+			},
+			expectErr: false,
+		},
+		{
+			name: "should fail with the Drain behavior",
+			spec: MachineDrainRuleSpec{
+				Behavior: MachineDrainRuleDrainBehaviorDrain,
+				Pods:     []MachineDrainRulePodSelector{{}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "should fail with an order",
+			spec: MachineDrainRuleSpec{
+				Behavior: MachineDrainRuleDrainBehaviorSkip,
+				Order:    pointer.Int32(1),
+				Pods:     []MachineDrainRulePodSelector{{}},
+			},
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			m := &MachineDrainRule{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+				Spec:       tt.spec,
+			}
+			_, err := m.ValidateCreate()
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+			_, err = m.ValidateUpdate(m.DeepCopy())
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+		})
+	}
+}
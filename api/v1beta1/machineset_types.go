@@ -57,6 +57,15 @@ type MachineSetSpec struct {
 	// +optional
 	DeletePolicy string `json:"deletePolicy,omitempty"`
 
+	// FailureDomainSpreadPolicy defines how Machines are spread across the failure domains of the Cluster.
+	// If not set, Machines are created in the failure domain defined in the Machine template.
+	// +optional
+	FailureDomainSpreadPolicy *FailureDomainSpreadPolicy `json:"failureDomainSpreadPolicy,omitempty"`
+
+	// MachineNamingStrategy allows changing the naming pattern used when creating the Machines of this MachineSet.
+	// +optional
+	MachineNamingStrategy *MachineNamingStrategy `json:"machineNamingStrategy,omitempty"`
+
 	// Selector is a label query over machines that should match the replica count.
 	// Label keys and values that must match in order to be controlled by this MachineSet.
 	// It must match the machine template's labels.
@@ -116,6 +125,79 @@ const (
 	OldestMachineSetDeletePolicy MachineSetDeletePolicy = "Oldest"
 )
 
+// FailureDomainSpreadPolicyType defines how Machines are spread across failure domains.
+type FailureDomainSpreadPolicyType string
+
+const (
+	// SpreadFailureDomainSpreadPolicyType creates new Machines in the failure domain with the fewest Machines;
+	// existing Machines are never moved to a different failure domain.
+	SpreadFailureDomainSpreadPolicyType FailureDomainSpreadPolicyType = "Spread"
+
+	// RebalanceFailureDomainSpreadPolicyType creates new Machines in the failure domain with the fewest Machines,
+	// like SpreadFailureDomainSpreadPolicyType, and additionally replaces Machines in the failure domains with the most Machines
+	// whenever Machines are not evenly spread, e.g. after a failure domain that was unavailable comes back.
+	RebalanceFailureDomainSpreadPolicyType FailureDomainSpreadPolicyType = "Rebalance"
+)
+
+// FailureDomainSpreadPolicy defines how Machines are spread across failure domains.
+type FailureDomainSpreadPolicy struct {
+	// Type of failure domain spread policy.
+	// Valid values are "Spread" and "Rebalance".
+	// +kubebuilder:validation:Enum=Spread;Rebalance
+	Type FailureDomainSpreadPolicyType `json:"type"`
+
+	// MaxUnavailable is the maximum number of Machines that can be unavailable while Machines are replaced
+	// to rebalance failure domains; Machines unavailable for other reasons count against this number too.
+	// Only used with the Rebalance type. Defaults to 1.
+	// NOTE: KubeadmControlPlane always replaces one Machine at a time by creating the new Machine first,
+	// so this field must not be set for control plane Machines.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxUnavailable *int32 `json:"maxUnavailable,omitempty"`
+}
+
+// MachineNamingStrategyType defines the type of naming strategy for the Machines of a MachineSet.
+type MachineNamingStrategyType string
+
+const (
+	// RandomMachineNamingStrategyType names Machines with a random suffix, optionally using a template.
+	RandomMachineNamingStrategyType MachineNamingStrategyType = "Random"
+
+	// OrdinalMachineNamingStrategyType names Machines with a stable ordinal, like the Pods of a StatefulSet:
+	// the Machines of a MachineDeployment are named `<machineDeployment name>-<ordinal>`, and a Machine replacing
+	// a deleted Machine reuses its ordinal, and thus its name and the name of its InfrastructureMachine.
+	OrdinalMachineNamingStrategyType MachineNamingStrategyType = "Ordinal"
+)
+
+// MachineNamingStrategy defines the naming strategy for the Machines of a MachineSet.
+type MachineNamingStrategy struct {
+	// Type is the type of naming strategy, either Random or Ordinal. Defaults to Random.
+	// With Ordinal, the Machines of a MachineDeployment are named `<machineDeployment name>-<ordinal>`, or
+	// `<machineSet name>-<ordinal>` for MachineSets not owned by a MachineDeployment, where ordinals go from 0
+	// to the number of replicas minus one; the bootstrap configs and the InfrastructureMachines of the Machines
+	// get the same name as the Machines. A new Machine always gets the lowest ordinal not in use, so a Machine
+	// replacing a deleted Machine is created only after the deleted Machine is gone, and reuses its name.
+	// Ordinal cannot be used together with Template, and requires MachineDeployments to use the OnDelete
+	// strategy or the RollingUpdate strategy with maxSurge set to 0.
+	// +optional
+	// +kubebuilder:validation:Enum=Random;Ordinal
+	Type MachineNamingStrategyType `json:"type,omitempty"`
+
+	// Template defines the template to use for generating the names of the Machines.
+	// The bootstrap configs and the InfrastructureMachines of the Machines get the same name as the Machines.
+	// If not defined, Machines are named `{{ .machineSet.name }}-{{ .random }}`, while bootstrap configs
+	// and InfrastructureMachines are named after the templates they are created from.
+	// If the templated string exceeds 63 characters, it will be trimmed to 58 characters and will
+	// get concatenated with a random suffix of length 5.
+	// The templating mechanism provides the following arguments:
+	// * `.cluster.name`: The name of the cluster object.
+	// * `.machineSet.name`: The name of the MachineSet object.
+	// * `.random`: A random alphanumeric string, without vowels, of length 5.
+	// The template must contain `{{ .random }}`.
+	// +optional
+	Template *string `json:"template,omitempty"`
+}
+
 // ANCHOR: MachineSetStatus
 
 // MachineSetStatus defines the observed state of MachineSet.
@@ -17,14 +17,17 @@ limitations under the License.
 package v1beta1
 
 import (
+	"bytes"
 	"fmt"
 	"strings"
+	"text/template"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -152,6 +155,18 @@ func (m *MachineSet) validate(old *MachineSet) error {
 		}
 	}
 
+	if m.Spec.FailureDomainSpreadPolicy != nil && m.Spec.Template.Spec.FailureDomain != nil {
+		allErrs = append(
+			allErrs,
+			field.Forbidden(
+				specPath.Child("template", "spec", "failureDomain"),
+				"cannot be set when spec.failureDomainSpreadPolicy is set",
+			),
+		)
+	}
+
+	allErrs = append(allErrs, validateMachineNamingStrategy(m.Spec.MachineNamingStrategy, specPath.Child("machineNamingStrategy"))...)
+
 	if len(allErrs) == 0 {
 		return nil
 	}
@@ -159,6 +174,45 @@ func (m *MachineSet) validate(old *MachineSet) error {
 	return apierrors.NewInvalid(GroupVersion.WithKind("MachineSet").GroupKind(), m.Name, allErrs)
 }
 
+// validateMachineNamingStrategy validates the template of a MachineNamingStrategy.
+func validateMachineNamingStrategy(strategy *MachineNamingStrategy, fldPath *field.Path) field.ErrorList {
+	if strategy == nil || strategy.Template == nil {
+		return nil
+	}
+	if strategy.Type == OrdinalMachineNamingStrategyType {
+		return field.ErrorList{field.Forbidden(fldPath.Child("template"), fmt.Sprintf("cannot be set when type is %s", OrdinalMachineNamingStrategyType))}
+	}
+	return validateNamingStrategyTemplate(*strategy.Template, map[string]interface{}{
+		"cluster":    map[string]interface{}{"name": "cluster"},
+		"machineSet": map[string]interface{}{"name": "machineset"},
+	}, fldPath.Child("template"))
+}
+
+// validateNamingStrategyTemplate validates that a naming strategy template contains {{ .random }},
+// so the generated names are unique, and that it generates a valid name with the given arguments.
+func validateNamingStrategyTemplate(tpl string, data map[string]interface{}, fldPath *field.Path) field.ErrorList {
+	if !strings.Contains(tpl, "{{ .random }}") {
+		return field.ErrorList{field.Invalid(fldPath, tpl, "invalid template, {{ .random }} is missing")}
+	}
+
+	t, err := template.New("name generator").Option("missingkey=error").Parse(tpl)
+	if err != nil {
+		return field.ErrorList{field.Invalid(fldPath, tpl, fmt.Sprintf("invalid template: %v", err))}
+	}
+	args := map[string]interface{}{"random": "abcde"}
+	for k, v := range data {
+		args[k] = v
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, args); err != nil {
+		return field.ErrorList{field.Invalid(fldPath, tpl, fmt.Sprintf("invalid template: %v", err))}
+	}
+	if errs := validation.IsDNS1123Subdomain(buf.String()); len(errs) > 0 {
+		return field.ErrorList{field.Invalid(fldPath, tpl, fmt.Sprintf("invalid template, generated names would not be valid Kubernetes object names: %s", strings.Join(errs, ", ")))}
+	}
+	return nil
+}
+
 func validateSkippedMachineSetPreflightChecks(o client.Object) *field.Error {
 	if o == nil {
 		return nil
@@ -179,7 +233,8 @@ func validateSkippedMachineSetPreflightChecks(o client.Object) *field.Error {
 	invalid := []MachineSetPreflightCheck{}
 	for i := range skippedList {
 		skipped := MachineSetPreflightCheck(strings.TrimSpace(skippedList[i]))
-		if !supported.Has(skipped) {
+		// Provider-defined preflight checks are validated by the Runtime Extensions implementing them.
+		if !supported.Has(skipped) && !skipped.IsProviderDefined() {
 			invalid = append(invalid, skipped)
 		}
 	}
@@ -187,7 +242,7 @@ func validateSkippedMachineSetPreflightChecks(o client.Object) *field.Error {
 		return field.Invalid(
 			field.NewPath("metadata", "annotations", MachineSetSkipPreflightChecksAnnotation),
 			invalid,
-			fmt.Sprintf("skipped preflight check(s) must be among: %v", sets.List(supported)),
+			fmt.Sprintf("skipped preflight check(s) must be among: %v or be provider-defined preflight checks prefixed by the provider domain", sets.List(supported)),
 		)
 	}
 	return nil
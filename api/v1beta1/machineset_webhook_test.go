@@ -238,6 +238,120 @@ func TestMachineSetVersionValidation(t *testing.T) {
 	}
 }
 
+func TestMachineSetFailureDomainSpreadPolicyValidation(t *testing.T) {
+	tests := []struct {
+		name                      string
+		failureDomainSpreadPolicy *FailureDomainSpreadPolicy
+		failureDomain             *string
+		expectErr                 bool
+	}{
+		{
+			name:          "should succeed when only the failure domain is set",
+			failureDomain: pointer.String("fd1"),
+			expectErr:     false,
+		},
+		{
+			name:                      "should succeed when only the failure domain spread policy is set",
+			failureDomainSpreadPolicy: &FailureDomainSpreadPolicy{Type: RebalanceFailureDomainSpreadPolicyType},
+			expectErr:                 false,
+		},
+		{
+			name:                      "should return error when both the failure domain and the failure domain spread policy are set",
+			failureDomainSpreadPolicy: &FailureDomainSpreadPolicy{Type: SpreadFailureDomainSpreadPolicyType},
+			failureDomain:             pointer.String("fd1"),
+			expectErr:                 true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ms := &MachineSet{
+				Spec: MachineSetSpec{
+					FailureDomainSpreadPolicy: tt.failureDomainSpreadPolicy,
+					Template: MachineTemplateSpec{
+						Spec: MachineSpec{
+							FailureDomain: tt.failureDomain,
+						},
+					},
+				},
+			}
+
+			warnings, err := ms.ValidateCreate()
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+			g.Expect(warnings).To(BeEmpty())
+		})
+	}
+}
+
+func TestMachineSetMachineNamingStrategyValidation(t *testing.T) {
+	tests := []struct {
+		name         string
+		strategyType MachineNamingStrategyType
+		template     *string
+		expectErr    bool
+	}{
+		{
+			name:      "should succeed when the naming strategy is not set",
+			expectErr: false,
+		},
+		{
+			name:      "should succeed with a valid template",
+			template:  pointer.String("{{ .cluster.name }}-{{ .machineSet.name }}-{{ .random }}"),
+			expectErr: false,
+		},
+		{
+			name:      "should return error when the template does not contain {{ .random }}",
+			template:  pointer.String("{{ .machineSet.name }}"),
+			expectErr: true,
+		},
+		{
+			name:      "should return error when the template uses an undefined argument",
+			template:  pointer.String("{{ .machineDeployment.name }}-{{ .random }}"),
+			expectErr: true,
+		},
+		{
+			name:      "should return error when the template generates invalid names",
+			template:  pointer.String("{{ .machineSet.name }}_{{ .random }}"),
+			expectErr: true,
+		},
+		{
+			name:         "should succeed with the Ordinal type",
+			strategyType: OrdinalMachineNamingStrategyType,
+			expectErr:    false,
+		},
+		{
+			name:         "should return error when the template is set with the Ordinal type",
+			strategyType: OrdinalMachineNamingStrategyType,
+			template:     pointer.String("{{ .machineSet.name }}-{{ .random }}"),
+			expectErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ms := &MachineSet{}
+			if tt.template != nil || tt.strategyType != "" {
+				ms.Spec.MachineNamingStrategy = &MachineNamingStrategy{Type: tt.strategyType, Template: tt.template}
+			}
+
+			_, err := ms.ValidateCreate()
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+		})
+	}
+}
+
 func TestValidateSkippedMachineSetPreflightChecks(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -282,6 +396,17 @@ func TestValidateSkippedMachineSetPreflightChecks(t *testing.T) {
 			},
 			expectErr: false,
 		},
+		{
+			name: "should pass if provider-defined preflight checks are skipped",
+			ms: &MachineSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						MachineSetSkipPreflightChecksAnnotation: string(MachineSetPreflightCheckKubeadmVersionSkew) + ",infrastructure.example.com/CapacityAvailable",
+					},
+				},
+			},
+			expectErr: false,
+		},
 		{
 			name: "should fail if invalid preflight checks are skipped",
 			ms: &MachineSet{
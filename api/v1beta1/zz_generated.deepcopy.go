@@ -124,6 +124,62 @@ func (in *ClusterClass) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterClassAddon) DeepCopyInto(out *ClusterClassAddon) {
+	*out = *in
+	if in.EnabledIf != nil {
+		in, out := &in.EnabledIf, &out.EnabledIf
+		*out = new(string)
+		**out = **in
+	}
+	in.ClusterResourceSet.DeepCopyInto(&out.ClusterResourceSet)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterClassAddon.
+func (in *ClusterClassAddon) DeepCopy() *ClusterClassAddon {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterClassAddon)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterClassAddonClusterResourceSet) DeepCopyInto(out *ClusterClassAddonClusterResourceSet) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]ClusterClassAddonResource, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterClassAddonClusterResourceSet.
+func (in *ClusterClassAddonClusterResourceSet) DeepCopy() *ClusterClassAddonClusterResourceSet {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterClassAddonClusterResourceSet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterClassAddonResource) DeepCopyInto(out *ClusterClassAddonResource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterClassAddonResource.
+func (in *ClusterClassAddonResource) DeepCopy() *ClusterClassAddonResource {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterClassAddonResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterClassList) DeepCopyInto(out *ClusterClassList) {
 	*out = *in
@@ -208,6 +264,13 @@ func (in *ClusterClassSpec) DeepCopyInto(out *ClusterClassSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Addons != nil {
+		in, out := &in.Addons, &out.Addons
+		*out = make([]ClusterClassAddon, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterClassSpec.
@@ -384,6 +447,16 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(v1.ObjectReference)
 		**out = **in
 	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = new(ObjectMeta)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Topology != nil {
 		in, out := &in.Topology, &out.Topology
 		*out = new(Topology)
@@ -444,6 +517,11 @@ func (in *ClusterStatus) DeepCopy() *ClusterStatus {
 func (in *ClusterVariable) DeepCopyInto(out *ClusterVariable) {
 	*out = *in
 	in.Value.DeepCopyInto(&out.Value)
+	if in.ValueFrom != nil {
+		in, out := &in.ValueFrom, &out.ValueFrom
+		*out = new(ClusterVariableValueSource)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterVariable.
@@ -456,6 +534,31 @@ func (in *ClusterVariable) DeepCopy() *ClusterVariable {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterVariableValueSource) DeepCopyInto(out *ClusterVariableValueSource) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(v1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterVariableValueSource.
+func (in *ClusterVariableValueSource) DeepCopy() *ClusterVariableValueSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterVariableValueSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Condition) DeepCopyInto(out *Condition) {
 	*out = *in
@@ -523,6 +626,16 @@ func (in *ControlPlaneClass) DeepCopyInto(out *ControlPlaneClass) {
 		*out = new(metav1.Duration)
 		**out = **in
 	}
+	if in.RolloutPolicy != nil {
+		in, out := &in.RolloutPolicy, &out.RolloutPolicy
+		*out = new(ControlPlaneRolloutPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NamingStrategy != nil {
+		in, out := &in.NamingStrategy, &out.NamingStrategy
+		*out = new(ControlPlaneClassNamingStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlaneClass.
@@ -535,6 +648,56 @@ func (in *ControlPlaneClass) DeepCopy() *ControlPlaneClass {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControlPlaneClassNamingStrategy) DeepCopyInto(out *ControlPlaneClassNamingStrategy) {
+	*out = *in
+	if in.Template != nil {
+		in, out := &in.Template, &out.Template
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlaneClassNamingStrategy.
+func (in *ControlPlaneClassNamingStrategy) DeepCopy() *ControlPlaneClassNamingStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(ControlPlaneClassNamingStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControlPlaneRolloutPolicy) DeepCopyInto(out *ControlPlaneRolloutPolicy) {
+	*out = *in
+	if in.MaxConcurrentClusters != nil {
+		in, out := &in.MaxConcurrentClusters, &out.MaxConcurrentClusters
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SoakTime != nil {
+		in, out := &in.SoakTime, &out.SoakTime
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlaneRolloutPolicy.
+func (in *ControlPlaneRolloutPolicy) DeepCopy() *ControlPlaneRolloutPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ControlPlaneRolloutPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ControlPlaneTopology) DeepCopyInto(out *ControlPlaneTopology) {
 	*out = *in
@@ -623,6 +786,25 @@ func (in *FailureDomainSpec) DeepCopyInto(out *FailureDomainSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Taints != nil {
+		in, out := &in.Taints, &out.Taints
+		*out = make([]v1.Taint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailureDomainSpec.
@@ -635,6 +817,26 @@ func (in *FailureDomainSpec) DeepCopy() *FailureDomainSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailureDomainSpreadPolicy) DeepCopyInto(out *FailureDomainSpreadPolicy) {
+	*out = *in
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailureDomainSpreadPolicy.
+func (in *FailureDomainSpreadPolicy) DeepCopy() *FailureDomainSpreadPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(FailureDomainSpreadPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in FailureDomains) DeepCopyInto(out *FailureDomains) {
 	{
@@ -656,6 +858,29 @@ func (in FailureDomains) DeepCopy() FailureDomains {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IPAddressClaimTemplate) DeepCopyInto(out *IPAddressClaimTemplate) {
+	*out = *in
+	in.PoolRef.DeepCopyInto(&out.PoolRef)
+	if in.JSONPatches != nil {
+		in, out := &in.JSONPatches, &out.JSONPatches
+		*out = make([]JSONPatch, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPAddressClaimTemplate.
+func (in *IPAddressClaimTemplate) DeepCopy() *IPAddressClaimTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(IPAddressClaimTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *JSONPatch) DeepCopyInto(out *JSONPatch) {
 	*out = *in
@@ -871,6 +1096,26 @@ func (in MachineAddresses) DeepCopy() MachineAddresses {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineCanaryDeployment) DeepCopyInto(out *MachineCanaryDeployment) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineCanaryDeployment.
+func (in *MachineCanaryDeployment) DeepCopy() *MachineCanaryDeployment {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineCanaryDeployment)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MachineDeployment) DeepCopyInto(out *MachineDeployment) {
 	*out = *in
@@ -937,6 +1182,40 @@ func (in *MachineDeploymentClass) DeepCopyInto(out *MachineDeploymentClass) {
 		*out = new(MachineDeploymentStrategy)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.IPAddressClaims != nil {
+		in, out := &in.IPAddressClaims, &out.IPAddressClaims
+		*out = make([]IPAddressClaimTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NamingStrategy != nil {
+		in, out := &in.NamingStrategy, &out.NamingStrategy
+		*out = new(MachineDeploymentClassNamingStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MachineSetNamingStrategy != nil {
+		in, out := &in.MachineSetNamingStrategy, &out.MachineSetNamingStrategy
+		*out = new(MachineSetNamingStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MachineNamingStrategy != nil {
+		in, out := &in.MachineNamingStrategy, &out.MachineNamingStrategy
+		*out = new(MachineNamingStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(MachineDeploymentClassAutoscaling)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Variables != nil {
+		in, out := &in.Variables, &out.Variables
+		*out = make([]ClusterClassVariable, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineDeploymentClass.
@@ -949,6 +1228,46 @@ func (in *MachineDeploymentClass) DeepCopy() *MachineDeploymentClass {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineDeploymentClassAutoscaling) DeepCopyInto(out *MachineDeploymentClassAutoscaling) {
+	*out = *in
+	if in.CapacityVariable != nil {
+		in, out := &in.CapacityVariable, &out.CapacityVariable
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineDeploymentClassAutoscaling.
+func (in *MachineDeploymentClassAutoscaling) DeepCopy() *MachineDeploymentClassAutoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineDeploymentClassAutoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineDeploymentClassNamingStrategy) DeepCopyInto(out *MachineDeploymentClassNamingStrategy) {
+	*out = *in
+	if in.Template != nil {
+		in, out := &in.Template, &out.Template
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineDeploymentClassNamingStrategy.
+func (in *MachineDeploymentClassNamingStrategy) DeepCopy() *MachineDeploymentClassNamingStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineDeploymentClassNamingStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MachineDeploymentClassTemplate) DeepCopyInto(out *MachineDeploymentClassTemplate) {
 	*out = *in
@@ -1023,6 +1342,21 @@ func (in *MachineDeploymentSpec) DeepCopyInto(out *MachineDeploymentSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.FailureDomainSpreadPolicy != nil {
+		in, out := &in.FailureDomainSpreadPolicy, &out.FailureDomainSpreadPolicy
+		*out = new(FailureDomainSpreadPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MachineSetNamingStrategy != nil {
+		in, out := &in.MachineSetNamingStrategy, &out.MachineSetNamingStrategy
+		*out = new(MachineSetNamingStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MachineNamingStrategy != nil {
+		in, out := &in.MachineNamingStrategy, &out.MachineNamingStrategy
+		*out = new(MachineNamingStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.RevisionHistoryLimit != nil {
 		in, out := &in.RevisionHistoryLimit, &out.RevisionHistoryLimit
 		*out = new(int32)
@@ -1075,6 +1409,11 @@ func (in *MachineDeploymentStrategy) DeepCopyInto(out *MachineDeploymentStrategy
 		*out = new(MachineRollingUpdateDeployment)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(MachineCanaryDeployment)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineDeploymentStrategy.
@@ -1170,6 +1509,121 @@ func (in *MachineDeploymentVariables) DeepCopy() *MachineDeploymentVariables {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineDrainRule) DeepCopyInto(out *MachineDrainRule) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineDrainRule.
+func (in *MachineDrainRule) DeepCopy() *MachineDrainRule {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineDrainRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MachineDrainRule) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineDrainRuleList) DeepCopyInto(out *MachineDrainRuleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MachineDrainRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineDrainRuleList.
+func (in *MachineDrainRuleList) DeepCopy() *MachineDrainRuleList {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineDrainRuleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MachineDrainRuleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineDrainRulePodSelector) DeepCopyInto(out *MachineDrainRulePodSelector) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineDrainRulePodSelector.
+func (in *MachineDrainRulePodSelector) DeepCopy() *MachineDrainRulePodSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineDrainRulePodSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineDrainRuleSpec) DeepCopyInto(out *MachineDrainRuleSpec) {
+	*out = *in
+	if in.Order != nil {
+		in, out := &in.Order, &out.Order
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ClusterSelector != nil {
+		in, out := &in.ClusterSelector, &out.ClusterSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Pods != nil {
+		in, out := &in.Pods, &out.Pods
+		*out = make([]MachineDrainRulePodSelector, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineDrainRuleSpec.
+func (in *MachineDrainRuleSpec) DeepCopy() *MachineDrainRuleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineDrainRuleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MachineHealthCheck) DeepCopyInto(out *MachineHealthCheck) {
 	*out = *in
@@ -1390,6 +1844,26 @@ func (in *MachineList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineNamingStrategy) DeepCopyInto(out *MachineNamingStrategy) {
+	*out = *in
+	if in.Template != nil {
+		in, out := &in.Template, &out.Template
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineNamingStrategy.
+func (in *MachineNamingStrategy) DeepCopy() *MachineNamingStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineNamingStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MachinePoolClass) DeepCopyInto(out *MachinePoolClass) {
 	*out = *in
@@ -1611,6 +2085,26 @@ func (in *MachineSetList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineSetNamingStrategy) DeepCopyInto(out *MachineSetNamingStrategy) {
+	*out = *in
+	if in.Template != nil {
+		in, out := &in.Template, &out.Template
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineSetNamingStrategy.
+func (in *MachineSetNamingStrategy) DeepCopy() *MachineSetNamingStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineSetNamingStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MachineSetSpec) DeepCopyInto(out *MachineSetSpec) {
 	*out = *in
@@ -1619,6 +2113,16 @@ func (in *MachineSetSpec) DeepCopyInto(out *MachineSetSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.FailureDomainSpreadPolicy != nil {
+		in, out := &in.FailureDomainSpreadPolicy, &out.FailureDomainSpreadPolicy
+		*out = new(FailureDomainSpreadPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MachineNamingStrategy != nil {
+		in, out := &in.MachineNamingStrategy, &out.MachineNamingStrategy
+		*out = new(MachineNamingStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Selector.DeepCopyInto(&out.Selector)
 	in.Template.DeepCopyInto(&out.Template)
 }
@@ -1690,6 +2194,11 @@ func (in *MachineSpec) DeepCopyInto(out *MachineSpec) {
 		*out = new(metav1.Duration)
 		**out = **in
 	}
+	if in.NodeDrainEvictionTimeout != nil {
+		in, out := &in.NodeDrainEvictionTimeout, &out.NodeDrainEvictionTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 	if in.NodeVolumeDetachTimeout != nil {
 		in, out := &in.NodeVolumeDetachTimeout, &out.NodeVolumeDetachTimeout
 		*out = new(metav1.Duration)
@@ -1700,6 +2209,11 @@ func (in *MachineSpec) DeepCopyInto(out *MachineSpec) {
 		*out = new(metav1.Duration)
 		**out = **in
 	}
+	if in.NodeStartupTimeout != nil {
+		in, out := &in.NodeStartupTimeout, &out.NodeStartupTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineSpec.
@@ -1725,6 +2239,20 @@ func (in *MachineStatus) DeepCopyInto(out *MachineStatus) {
 		*out = new(v1.NodeSystemInfo)
 		**out = **in
 	}
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Allocatable != nil {
+		in, out := &in.Allocatable, &out.Allocatable
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
 	if in.LastUpdated != nil {
 		in, out := &in.LastUpdated, &out.LastUpdated
 		*out = (*in).DeepCopy()
@@ -1748,6 +2276,10 @@ func (in *MachineStatus) DeepCopyInto(out *MachineStatus) {
 		in, out := &in.CertificatesExpiryDate, &out.CertificatesExpiryDate
 		*out = (*in).DeepCopy()
 	}
+	if in.NodeDrainStartTime != nil {
+		in, out := &in.NodeDrainStartTime, &out.NodeDrainStartTime
+		*out = (*in).DeepCopy()
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make(Conditions, len(*in))
@@ -1784,6 +2316,22 @@ func (in *MachineTemplateSpec) DeepCopy() *MachineTemplateSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetworkRanges) DeepCopyInto(out *NetworkRanges) {
 	*out = *in
@@ -1937,6 +2485,21 @@ func (in *PatchSelectorMatchMachinePoolClass) DeepCopy() *PatchSelectorMatchMach
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SummaryRow) DeepCopyInto(out *SummaryRow) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SummaryRow.
+func (in *SummaryRow) DeepCopy() *SummaryRow {
+	if in == nil {
+		return nil
+	}
+	out := new(SummaryRow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Topology) DeepCopyInto(out *Topology) {
 	*out = *in
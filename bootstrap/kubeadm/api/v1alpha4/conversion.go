@@ -49,6 +49,8 @@ func (src *KubeadmConfig) ConvertTo(dstRaw conversion.Hub) error {
 	}
 
 	dst.Spec.Ignition = restored.Spec.Ignition
+	dst.Spec.ImageOverrides = restored.Spec.ImageOverrides
+	dst.Spec.ReadinessChecks = restored.Spec.ReadinessChecks
 	if restored.Spec.InitConfiguration != nil {
 		if dst.Spec.InitConfiguration == nil {
 			dst.Spec.InitConfiguration = &bootstrapv1.InitConfiguration{}
@@ -130,6 +132,8 @@ func (src *KubeadmConfigTemplate) ConvertTo(dstRaw conversion.Hub) error {
 	dst.Spec.Template.ObjectMeta = restored.Spec.Template.ObjectMeta
 
 	dst.Spec.Template.Spec.Ignition = restored.Spec.Template.Spec.Ignition
+	dst.Spec.Template.Spec.ImageOverrides = restored.Spec.Template.Spec.ImageOverrides
+	dst.Spec.Template.Spec.ReadinessChecks = restored.Spec.Template.Spec.ReadinessChecks
 	if restored.Spec.Template.Spec.InitConfiguration != nil {
 		if dst.Spec.Template.Spec.InitConfiguration == nil {
 			dst.Spec.Template.Spec.InitConfiguration = &bootstrapv1.InitConfiguration{}
@@ -186,7 +190,7 @@ func (dst *KubeadmConfigTemplateList) ConvertFrom(srcRaw conversion.Hub) error {
 
 // Convert_v1beta1_KubeadmConfigSpec_To_v1alpha4_KubeadmConfigSpec is an autogenerated conversion function.
 func Convert_v1beta1_KubeadmConfigSpec_To_v1alpha4_KubeadmConfigSpec(in *bootstrapv1.KubeadmConfigSpec, out *KubeadmConfigSpec, s apiconversion.Scope) error {
-	// KubeadmConfigSpec.Ignition does not exist in kubeadm v1alpha4 API.
+	// KubeadmConfigSpec.Ignition, KubeadmConfigSpec.ImageOverrides and KubeadmConfigSpec.ReadinessChecks do not exist in kubeadm v1alpha4 API.
 	return autoConvert_v1beta1_KubeadmConfigSpec_To_v1alpha4_KubeadmConfigSpec(in, out, s)
 }
 
@@ -201,12 +205,12 @@ func Convert_v1beta1_JoinConfiguration_To_v1alpha4_JoinConfiguration(in *bootstr
 }
 
 func Convert_v1beta1_File_To_v1alpha4_File(in *bootstrapv1.File, out *File, s apiconversion.Scope) error {
-	// File.Append does not exist in kubeadm v1alpha4 API.
+	// File.Append, File.Compress and File.Defer do not exist in kubeadm v1alpha4 API.
 	return autoConvert_v1beta1_File_To_v1alpha4_File(in, out, s)
 }
 
 func Convert_v1beta1_User_To_v1alpha4_User(in *bootstrapv1.User, out *User, s apiconversion.Scope) error {
-	// User.PasswdFrom does not exist in kubeadm v1alpha4 API.
+	// User.PasswdFrom and User.SSHAuthorizedKeysFrom do not exist in kubeadm v1alpha4 API.
 	return autoConvert_v1beta1_User_To_v1alpha4_User(in, out, s)
 }
 
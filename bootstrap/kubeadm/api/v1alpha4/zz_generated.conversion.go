@@ -790,6 +790,8 @@ func autoConvert_v1beta1_File_To_v1alpha4_File(in *v1beta1.File, out *File, s co
 	out.Permissions = in.Permissions
 	out.Encoding = Encoding(in.Encoding)
 	// WARNING: in.Append requires manual conversion: does not exist in peer-type
+	// WARNING: in.Compress requires manual conversion: does not exist in peer-type
+	// WARNING: in.Defer requires manual conversion: does not exist in peer-type
 	out.Content = in.Content
 	out.ContentFrom = (*FileSource)(unsafe.Pointer(in.ContentFrom))
 	return nil
@@ -1188,6 +1190,8 @@ func autoConvert_v1beta1_KubeadmConfigSpec_To_v1alpha4_KubeadmConfigSpec(in *v1b
 	out.Verbosity = (*int32)(unsafe.Pointer(in.Verbosity))
 	out.UseExperimentalRetryJoin = in.UseExperimentalRetryJoin
 	// WARNING: in.Ignition requires manual conversion: does not exist in peer-type
+	// WARNING: in.ImageOverrides requires manual conversion: does not exist in peer-type
+	// WARNING: in.ReadinessChecks requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -1536,5 +1540,6 @@ func autoConvert_v1beta1_User_To_v1alpha4_User(in *v1beta1.User, out *User, s co
 	out.LockPassword = (*bool)(unsafe.Pointer(in.LockPassword))
 	out.Sudo = (*string)(unsafe.Pointer(in.Sudo))
 	out.SSHAuthorizedKeys = *(*[]string)(unsafe.Pointer(&in.SSHAuthorizedKeys))
+	// WARNING: in.SSHAuthorizedKeysFrom requires manual conversion: does not exist in peer-type
 	return nil
 }
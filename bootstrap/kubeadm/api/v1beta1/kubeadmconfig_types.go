@@ -105,6 +105,95 @@ type KubeadmConfigSpec struct {
 	// Ignition contains Ignition specific configuration.
 	// +optional
 	Ignition *IgnitionSpec `json:"ignition,omitempty"`
+
+	// ImageOverrides allows to pull the images of single components from a different registry,
+	// e.g. a mirror in air-gapped environments, without patching the ClusterConfiguration.
+	// +optional
+	ImageOverrides *ImageOverrides `json:"imageOverrides,omitempty"`
+
+	// ReadinessChecks specifies checks to run on the node after PreKubeadmCommands and before kubeadm init or join,
+	// e.g. to wait for storage or network dependencies of the node to be ready.
+	// The checks run in order; if a check does not succeed within its timeout, kubeadm is not run and
+	// the bootstrap success sentinel file is not written.
+	// NOTE: ReadinessChecks are not supported with the Ignition format.
+	// +optional
+	ReadinessChecks []ReadinessCheck `json:"readinessChecks,omitempty"`
+}
+
+// ReadinessCheck defines a check to run on the node before kubeadm init or join.
+// Exactly one of Command or HTTPGet must be set.
+type ReadinessCheck struct {
+	// Name of the check, used to report failures.
+	Name string `json:"name"`
+
+	// Command is a shell command which must exit with status 0 for the check to succeed.
+	// +optional
+	Command string `json:"command,omitempty"`
+
+	// HTTPGet defines an HTTP endpoint which must return a 2xx status code for the check to succeed.
+	// +optional
+	HTTPGet *HTTPGetReadinessCheck `json:"httpGet,omitempty"`
+
+	// TimeoutSeconds is the time to wait for the check to succeed. Defaults to 300 seconds.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+
+	// PeriodSeconds is the time to wait between attempts of the check. Defaults to 5 seconds.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PeriodSeconds *int32 `json:"periodSeconds,omitempty"`
+}
+
+// HTTPGetReadinessCheck defines an HTTP endpoint to be checked on the node.
+type HTTPGetReadinessCheck struct {
+	// URL of the endpoint, using the http or https scheme.
+	URL string `json:"url"`
+
+	// InsecureSkipTLSVerify disables the verification of the certificate of https endpoints.
+	// +optional
+	InsecureSkipTLSVerify bool `json:"insecureSkipTLSVerify,omitempty"`
+}
+
+// ImageOverrides defines per component overrides for the images used by kubeadm.
+// Overrides only apply to components for which an image repository is not already set in the ClusterConfiguration.
+type ImageOverrides struct {
+	// EtcdImageRepository sets the container registry to pull the local etcd image from.
+	// It is ignored when using an external etcd.
+	// +optional
+	EtcdImageRepository string `json:"etcdImageRepository,omitempty"`
+
+	// CoreDNSImageRepository sets the container registry to pull the CoreDNS image from.
+	// +optional
+	CoreDNSImageRepository string `json:"coreDNSImageRepository,omitempty"`
+}
+
+// ClusterConfigurationWithImageOverrides returns a copy of the ClusterConfiguration with the image repositories
+// defined in ImageOverrides set for the components that do not already define one.
+func (c *KubeadmConfigSpec) ClusterConfigurationWithImageOverrides() *ClusterConfiguration {
+	if c.ImageOverrides == nil {
+		return c.ClusterConfiguration.DeepCopy()
+	}
+
+	clusterConfiguration := &ClusterConfiguration{}
+	if c.ClusterConfiguration != nil {
+		clusterConfiguration = c.ClusterConfiguration.DeepCopy()
+	}
+
+	if repository := c.ImageOverrides.EtcdImageRepository; repository != "" && clusterConfiguration.Etcd.External == nil {
+		if clusterConfiguration.Etcd.Local == nil {
+			clusterConfiguration.Etcd.Local = &LocalEtcd{}
+		}
+		if clusterConfiguration.Etcd.Local.ImageRepository == "" {
+			clusterConfiguration.Etcd.Local.ImageRepository = repository
+		}
+	}
+
+	if repository := c.ImageOverrides.CoreDNSImageRepository; repository != "" && clusterConfiguration.DNS.ImageRepository == "" {
+		clusterConfiguration.DNS.ImageRepository = repository
+	}
+
+	return clusterConfiguration
 }
 
 // IgnitionSpec contains Ignition specific configuration.
@@ -230,6 +319,19 @@ type File struct {
 	// +optional
 	Append bool `json:"append,omitempty"`
 
+	// Compress specifies whether to compress the content of the file with gzip, and to encode it with base64,
+	// when generating the bootstrap data, so large files, e.g. binaries or certificate bundles, don't exceed
+	// the size limits of the bootstrap data; the file is written decompressed on disk.
+	// Compress can't be used together with Encoding, and it is not supported with the Ignition format.
+	// +optional
+	Compress bool `json:"compress,omitempty"`
+
+	// Defer specifies whether to defer writing the file until the final stage of the bootstrap process,
+	// after users are created and packages are installed, e.g. to set the owner to a user defined in Users.
+	// Defer is not supported with the Ignition format.
+	// +optional
+	Defer bool `json:"defer,omitempty"`
+
 	// Content is the actual content of the file.
 	// +optional
 	Content string `json:"content,omitempty"`
@@ -279,6 +381,26 @@ type SecretPasswdSource struct {
 	Key string `json:"key"`
 }
 
+// SSHAuthorizedKeysSource is a union of all possible external source types for ssh authorized keys.
+// Only one field may be populated in any given instance. Developers adding new
+// sources of data for target systems should add them here.
+type SSHAuthorizedKeysSource struct {
+	// Secret represents a secret that should populate the ssh authorized keys.
+	Secret SecretSSHAuthorizedKeysSource `json:"secret"`
+}
+
+// SecretSSHAuthorizedKeysSource adapts a Secret into a SSHAuthorizedKeysSource.
+//
+// The value of the key in the target Secret's Data field is read as a list of ssh authorized keys,
+// one per line, like in an authorized_keys file; empty lines and lines starting with # are ignored.
+type SecretSSHAuthorizedKeysSource struct {
+	// Name of the secret in the KubeadmBootstrapConfig's namespace to use.
+	Name string `json:"name"`
+
+	// Key is the key in the secret's data map for this value.
+	Key string `json:"key"`
+}
+
 // User defines the input for a generated user in cloud-init.
 type User struct {
 	// Name specifies the user name
@@ -327,6 +449,11 @@ type User struct {
 	// SSHAuthorizedKeys specifies a list of ssh authorized keys for the user
 	// +optional
 	SSHAuthorizedKeys []string `json:"sshAuthorizedKeys,omitempty"`
+
+	// SSHAuthorizedKeysFrom is a referenced source of ssh authorized keys for the user, which are added to
+	// the SSHAuthorizedKeys when generating the bootstrap data.
+	// +optional
+	SSHAuthorizedKeysFrom *SSHAuthorizedKeysSource `json:"sshAuthorizedKeysFrom,omitempty"`
 }
 
 // NTP defines input for generated ntp in cloud-init.
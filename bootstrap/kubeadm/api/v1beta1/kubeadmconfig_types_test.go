@@ -0,0 +1,98 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestClusterConfigurationWithImageOverrides(t *testing.T) {
+	tests := []struct {
+		name string
+		spec KubeadmConfigSpec
+		want *ClusterConfiguration
+	}{
+		{
+			name: "no overrides and no ClusterConfiguration",
+			spec: KubeadmConfigSpec{},
+			want: nil,
+		},
+		{
+			name: "no overrides",
+			spec: KubeadmConfigSpec{
+				ClusterConfiguration: &ClusterConfiguration{ImageRepository: "example.com"},
+			},
+			want: &ClusterConfiguration{ImageRepository: "example.com"},
+		},
+		{
+			name: "overrides without ClusterConfiguration",
+			spec: KubeadmConfigSpec{
+				ImageOverrides: &ImageOverrides{
+					EtcdImageRepository:    "etcd.example.com",
+					CoreDNSImageRepository: "coredns.example.com",
+				},
+			},
+			want: &ClusterConfiguration{
+				Etcd: Etcd{Local: &LocalEtcd{ImageMeta: ImageMeta{ImageRepository: "etcd.example.com"}}},
+				DNS:  DNS{ImageMeta: ImageMeta{ImageRepository: "coredns.example.com"}},
+			},
+		},
+		{
+			name: "overrides do not replace image repositories set in ClusterConfiguration",
+			spec: KubeadmConfigSpec{
+				ClusterConfiguration: &ClusterConfiguration{
+					Etcd: Etcd{Local: &LocalEtcd{ImageMeta: ImageMeta{ImageRepository: "etcd.local"}}},
+					DNS:  DNS{ImageMeta: ImageMeta{ImageRepository: "coredns.local"}},
+				},
+				ImageOverrides: &ImageOverrides{
+					EtcdImageRepository:    "etcd.example.com",
+					CoreDNSImageRepository: "coredns.example.com",
+				},
+			},
+			want: &ClusterConfiguration{
+				Etcd: Etcd{Local: &LocalEtcd{ImageMeta: ImageMeta{ImageRepository: "etcd.local"}}},
+				DNS:  DNS{ImageMeta: ImageMeta{ImageRepository: "coredns.local"}},
+			},
+		},
+		{
+			name: "etcd override is ignored with external etcd",
+			spec: KubeadmConfigSpec{
+				ClusterConfiguration: &ClusterConfiguration{
+					Etcd: Etcd{External: &ExternalEtcd{Endpoints: []string{"https://etcd:2379"}}},
+				},
+				ImageOverrides: &ImageOverrides{
+					EtcdImageRepository: "etcd.example.com",
+				},
+			},
+			want: &ClusterConfiguration{
+				Etcd: Etcd{External: &ExternalEtcd{Endpoints: []string{"https://etcd:2379"}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			original := tt.spec.DeepCopy()
+			g.Expect(tt.spec.ClusterConfigurationWithImageOverrides()).To(Equal(tt.want))
+			// The KubeadmConfigSpec must not be modified.
+			g.Expect(&tt.spec).To(Equal(original))
+		})
+	}
+}
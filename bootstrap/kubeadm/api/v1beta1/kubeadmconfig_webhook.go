@@ -18,6 +18,7 @@ package v1beta1
 
 import (
 	"fmt"
+	"net/url"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -32,11 +33,15 @@ import (
 var (
 	cannotUseWithIgnition                            = fmt.Sprintf("not supported when spec.format is set to %q", Ignition)
 	conflictingFileSourceMsg                         = "only one of content or contentFrom may be specified for a single file"
+	conflictingFileEncodingMsg                       = "encoding can't be specified for a compressed file"
 	conflictingUserSourceMsg                         = "only one of passwd or passwdFrom may be specified for a single user"
+	conflictingReadinessCheckMsg                     = "exactly one of command or httpGet must be specified for a single readiness check"
+	invalidReadinessCheckURLMsg                      = "must be a valid http or https URL"
 	kubeadmBootstrapFormatIgnitionFeatureDisabledMsg = "can be set only if the KubeadmBootstrapFormatIgnition feature gate is enabled"
 	missingSecretNameMsg                             = "secret file source must specify non-empty secret name"
 	missingSecretKeyMsg                              = "secret file source must specify non-empty secret key"
 	pathConflictMsg                                  = "path property must be unique among all files"
+	readinessCheckNameConflictMsg                    = "name must be unique among all readiness checks"
 )
 
 func (c *KubeadmConfig) SetupWebhookWithManager(mgr ctrl.Manager) error {
@@ -102,6 +107,7 @@ func (c *KubeadmConfigSpec) Validate(pathPrefix *field.Path) field.ErrorList {
 
 	allErrs = append(allErrs, c.validateFiles(pathPrefix)...)
 	allErrs = append(allErrs, c.validateUsers(pathPrefix)...)
+	allErrs = append(allErrs, c.validateReadinessChecks(pathPrefix)...)
 	allErrs = append(allErrs, c.validateIgnition(pathPrefix)...)
 
 	return allErrs
@@ -147,6 +153,16 @@ func (c *KubeadmConfigSpec) validateFiles(pathPrefix *field.Path) field.ErrorLis
 				)
 			}
 		}
+		if file.Compress && file.Encoding != "" {
+			allErrs = append(
+				allErrs,
+				field.Invalid(
+					pathPrefix.Child("files").Index(i).Child("encoding"),
+					file.Encoding,
+					conflictingFileEncodingMsg,
+				),
+			)
+		}
 		_, conflict := knownPaths[file.Path]
 		if conflict {
 			allErrs = append(
@@ -202,6 +218,85 @@ func (c *KubeadmConfigSpec) validateUsers(pathPrefix *field.Path) field.ErrorLis
 				)
 			}
 		}
+		// n.b.: if we ever add types besides Secret as a SSHAuthorizedKeysFrom
+		// Source, we must add webhook validation here for one of the
+		// sources being non-nil.
+		if user.SSHAuthorizedKeysFrom != nil {
+			if user.SSHAuthorizedKeysFrom.Secret.Name == "" {
+				allErrs = append(
+					allErrs,
+					field.Required(
+						pathPrefix.Child("users").Index(i).Child("sshAuthorizedKeysFrom", "secret", "name"),
+						missingSecretNameMsg,
+					),
+				)
+			}
+			if user.SSHAuthorizedKeysFrom.Secret.Key == "" {
+				allErrs = append(
+					allErrs,
+					field.Required(
+						pathPrefix.Child("users").Index(i).Child("sshAuthorizedKeysFrom", "secret", "key"),
+						missingSecretKeyMsg,
+					),
+				)
+			}
+		}
+	}
+
+	return allErrs
+}
+
+func (c *KubeadmConfigSpec) validateReadinessChecks(pathPrefix *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	knownNames := map[string]struct{}{}
+
+	for i := range c.ReadinessChecks {
+		check := c.ReadinessChecks[i]
+		if check.Name == "" {
+			allErrs = append(
+				allErrs,
+				field.Required(
+					pathPrefix.Child("readinessChecks").Index(i).Child("name"),
+					"must be set",
+				),
+			)
+		}
+		if _, conflict := knownNames[check.Name]; check.Name != "" && conflict {
+			allErrs = append(
+				allErrs,
+				field.Invalid(
+					pathPrefix.Child("readinessChecks").Index(i).Child("name"),
+					check.Name,
+					readinessCheckNameConflictMsg,
+				),
+			)
+		}
+		knownNames[check.Name] = struct{}{}
+
+		if (check.Command == "") == (check.HTTPGet == nil) {
+			allErrs = append(
+				allErrs,
+				field.Invalid(
+					pathPrefix.Child("readinessChecks").Index(i),
+					check,
+					conflictingReadinessCheckMsg,
+				),
+			)
+		}
+		if check.HTTPGet != nil {
+			u, err := url.Parse(check.HTTPGet.URL)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				allErrs = append(
+					allErrs,
+					field.Invalid(
+						pathPrefix.Child("readinessChecks").Index(i).Child("httpGet", "url"),
+						check.HTTPGet.URL,
+						invalidReadinessCheckURLMsg,
+					),
+				)
+			}
+		}
 	}
 
 	return allErrs
@@ -261,6 +356,16 @@ func (c *KubeadmConfigSpec) validateIgnition(pathPrefix *field.Path) field.Error
 		)
 	}
 
+	if len(c.ReadinessChecks) > 0 {
+		allErrs = append(
+			allErrs,
+			field.Forbidden(
+				pathPrefix.Child("readinessChecks"),
+				cannotUseWithIgnition,
+			),
+		)
+	}
+
 	for i, file := range c.Files {
 		if file.Encoding == Gzip || file.Encoding == GzipBase64 {
 			allErrs = append(
@@ -271,6 +376,24 @@ func (c *KubeadmConfigSpec) validateIgnition(pathPrefix *field.Path) field.Error
 				),
 			)
 		}
+		if file.Compress {
+			allErrs = append(
+				allErrs,
+				field.Forbidden(
+					pathPrefix.Child("files").Index(i).Child("compress"),
+					cannotUseWithIgnition,
+				),
+			)
+		}
+		if file.Defer {
+			allErrs = append(
+				allErrs,
+				field.Forbidden(
+					pathPrefix.Child("files").Index(i).Child("defer"),
+					cannotUseWithIgnition,
+				),
+			)
+		}
 	}
 
 	if c.DiskSetup == nil {
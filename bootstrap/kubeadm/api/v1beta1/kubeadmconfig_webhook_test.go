@@ -272,6 +272,66 @@ func TestKubeadmConfigValidate(t *testing.T) {
 			},
 			expectErr: true,
 		},
+		"valid sshAuthorizedKeysFrom": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: KubeadmConfigSpec{
+					Users: []User{
+						{
+							SSHAuthorizedKeysFrom: &SSHAuthorizedKeysSource{
+								Secret: SecretSSHAuthorizedKeysSource{
+									Name: "foo",
+									Key:  "bar",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"invalid sshAuthorizedKeysFrom without name": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: KubeadmConfigSpec{
+					Users: []User{
+						{
+							SSHAuthorizedKeysFrom: &SSHAuthorizedKeysSource{
+								Secret: SecretSSHAuthorizedKeysSource{
+									Key: "bar",
+								},
+							},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"invalid sshAuthorizedKeysFrom without key": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: KubeadmConfigSpec{
+					Users: []User{
+						{
+							SSHAuthorizedKeysFrom: &SSHAuthorizedKeysSource{
+								Secret: SecretSSHAuthorizedKeysSource{
+									Name: "foo",
+								},
+							},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
 		"Ignition field is set, format is not Ignition": {
 			enableIgnitionFeature: true,
 			in: &KubeadmConfig{
@@ -452,6 +512,219 @@ func TestKubeadmConfigValidate(t *testing.T) {
 			},
 			expectErr: true,
 		},
+		"compressed file": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					Files: []File{
+						{
+							Compress: true,
+						},
+					},
+				},
+			},
+			expectErr: false,
+		},
+		"compressed file with encoding": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					Files: []File{
+						{
+							Compress: true,
+							Encoding: Base64,
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"deferred file": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					Files: []File{
+						{
+							Defer: true,
+						},
+					},
+				},
+			},
+			expectErr: false,
+		},
+		"compressed file specified with Ignition": {
+			enableIgnitionFeature: true,
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					Format: Ignition,
+					Files: []File{
+						{
+							Compress: true,
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"deferred file specified with Ignition": {
+			enableIgnitionFeature: true,
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					Format: Ignition,
+					Files: []File{
+						{
+							Defer: true,
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"readiness checks": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					ReadinessChecks: []ReadinessCheck{
+						{
+							Name:    "csi",
+							Command: "test -S /run/csi/csi.sock",
+						},
+						{
+							Name:    "network",
+							HTTPGet: &HTTPGetReadinessCheck{URL: "https://127.0.0.1:9099/readiness"},
+						},
+					},
+				},
+			},
+			expectErr: false,
+		},
+		"readiness check without name": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					ReadinessChecks: []ReadinessCheck{
+						{
+							Command: "true",
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"readiness checks with duplicate names": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					ReadinessChecks: []ReadinessCheck{
+						{
+							Name:    "foo",
+							Command: "true",
+						},
+						{
+							Name:    "foo",
+							Command: "true",
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"readiness check with both command and httpGet": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					ReadinessChecks: []ReadinessCheck{
+						{
+							Name:    "foo",
+							Command: "true",
+							HTTPGet: &HTTPGetReadinessCheck{URL: "http://127.0.0.1:8080"},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"readiness check without command and httpGet": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					ReadinessChecks: []ReadinessCheck{
+						{
+							Name: "foo",
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"readiness check with invalid url": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					ReadinessChecks: []ReadinessCheck{
+						{
+							Name:    "foo",
+							HTTPGet: &HTTPGetReadinessCheck{URL: "ftp://127.0.0.1"},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"readiness checks specified with Ignition": {
+			enableIgnitionFeature: true,
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					Format: Ignition,
+					ReadinessChecks: []ReadinessCheck{
+						{
+							Name:    "foo",
+							Command: "true",
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
 	}
 
 	for name, tt := range cases {
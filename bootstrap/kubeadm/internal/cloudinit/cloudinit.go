@@ -45,6 +45,7 @@ type BaseUserData struct {
 	Header               string
 	PreKubeadmCommands   []string
 	PostKubeadmCommands  []string
+	ReadinessChecks      []bootstrapv1.ReadinessCheck
 	AdditionalFiles      []bootstrapv1.File
 	WriteFiles           []bootstrapv1.File
 	Users                []bootstrapv1.User
@@ -56,6 +57,8 @@ type BaseUserData struct {
 	KubeadmCommand       string
 	KubeadmVerbosity     string
 	SentinelFileCommand  string
+	// ReadinessChecksCommand runs the readiness checks before kubeadm, if any.
+	ReadinessChecksCommand string
 }
 
 func (input *BaseUserData) prepare() error {
@@ -71,7 +74,7 @@ func (input *BaseUserData) prepare() error {
 		input.WriteFiles = append(input.WriteFiles, *joinScriptFile)
 	}
 	input.SentinelFileCommand = sentinelFileCommand
-	return nil
+	return input.prepareReadinessChecks()
 }
 
 func generate(kind string, tpl string, data interface{}) ([]byte, error) {
@@ -50,6 +50,12 @@ func TestNewInitControlPlaneAdditionalFileEncodings(t *testing.T) {
 					Append:  true,
 					Content: "hi",
 				},
+				{
+					Path:    "/tmp/deferred-path",
+					Owner:   "capi:capi",
+					Defer:   true,
+					Content: "hi",
+				},
 			},
 			WriteFiles: nil,
 			Users:      nil,
@@ -80,6 +86,11 @@ func TestNewInitControlPlaneAdditionalFileEncodings(t *testing.T) {
       hi`,
 		`-   path: /tmp/existing-path
     append: true
+    content: |
+      hi`,
+		`-   path: /tmp/deferred-path
+    owner: capi:capi
+    defer: true
     content: |
       hi`,
 	}
@@ -125,6 +136,59 @@ func TestNewInitControlPlaneCommands(t *testing.T) {
 	}
 }
 
+func TestNewInitControlPlaneReadinessChecks(t *testing.T) {
+	g := NewWithT(t)
+
+	cpinput := &ControlPlaneInput{
+		BaseUserData: BaseUserData{
+			Header:             "test",
+			PreKubeadmCommands: []string{"echo pre"},
+			ReadinessChecks: []bootstrapv1.ReadinessCheck{
+				{
+					Name:    "csi",
+					Command: "test -S '/run/csi/csi.sock'",
+				},
+				{
+					Name: "network",
+					HTTPGet: &bootstrapv1.HTTPGetReadinessCheck{
+						URL:                   "https://127.0.0.1:9099/readiness",
+						InsecureSkipTLSVerify: true,
+					},
+					TimeoutSeconds: pointer.Int32(600),
+					PeriodSeconds:  pointer.Int32(10),
+				},
+			},
+		},
+		Certificates:         secret.Certificates{},
+		ClusterConfiguration: "my-cluster-config",
+		InitConfiguration:    "my-init-config",
+	}
+
+	for _, certificate := range cpinput.Certificates {
+		certificate.KeyPair = &certs.KeyPair{
+			Cert: []byte("some certificate"),
+			Key:  []byte("some key"),
+		}
+	}
+
+	out, err := NewInitControlPlane(cpinput)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	expected := []string{
+		`-   path: ` + readinessChecksScriptName + `
+    owner: ` + readinessChecksScriptOwner + `
+    permissions: '` + readinessChecksScriptPermissions + `'
+    `,
+		`readiness-check 'csi' 300 5 'test -S '"'"'/run/csi/csi.sock'"'"''`,
+		`readiness-check 'network' 600 10 'curl --fail --silent --show-error --output /dev/null --max-time 10 --insecure '"'"'https://127.0.0.1:9099/readiness'"'"''`,
+		`  - "echo pre"
+  - '` + readinessChecksScriptName + ` && kubeadm init --config /run/kubeadm/kubeadm.yaml  && ` + sentinelFileCommand + `'`,
+	}
+	for _, f := range expected {
+		g.Expect(out).To(ContainSubstring(f))
+	}
+}
+
 func TestNewInitControlPlaneDiskMounts(t *testing.T) {
 	g := NewWithT(t)
 
@@ -278,3 +342,36 @@ func TestNewJoinControlPlaneExperimentalRetry(t *testing.T) {
 		g.Expect(out).To(ContainSubstring(f))
 	}
 }
+
+func TestNewJoinControlPlaneReadinessChecks(t *testing.T) {
+	g := NewWithT(t)
+
+	cpinput := &ControlPlaneJoinInput{
+		BaseUserData: BaseUserData{
+			Header:               "test",
+			UseExperimentalRetry: true,
+			ReadinessChecks: []bootstrapv1.ReadinessCheck{
+				{
+					Name:    "csi",
+					Command: "true",
+				},
+			},
+		},
+		Certificates:      secret.Certificates{},
+		BootstrapToken:    "my-bootstrap-token",
+		JoinConfiguration: "my-join-config",
+	}
+
+	for _, certificate := range cpinput.Certificates {
+		certificate.KeyPair = &certs.KeyPair{
+			Cert: []byte("some certificate"),
+			Key:  []byte("some key"),
+		}
+	}
+
+	out, err := NewJoinControlPlane(cpinput)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(out).To(ContainSubstring(`-   path: ` + readinessChecksScriptName))
+	g.Expect(out).To(ContainSubstring(`  - ` + readinessChecksScriptName + ` && ` + retriableJoinScriptName + ` && ` + sentinelFileCommand))
+}
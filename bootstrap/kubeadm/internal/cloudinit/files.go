@@ -32,6 +32,9 @@ write_files:{{ range . }}
     {{ if .Append -}}
     append: true
     {{ end -}}
+    {{ if .Defer -}}
+    defer: true
+    {{ end -}}
     content: |
 {{.Content | Indent 6}}
 {{- end -}}
@@ -0,0 +1,61 @@
+#!/bin/bash
+# Copyright 2023 The Kubernetes Authors.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+# Runs the readiness checks of the node before kubeadm init or join.
+# If a check does not succeed within its timeout, the reason is written to the
+# readiness checks failure file and the script exits with a non-zero status.
+
+readiness_checks_failed_file="{{ .FailureFile }}"
+rm -f "${readiness_checks_failed_file}"
+
+# Log an error.
+log::error() {
+  timestamp=$(date --iso-8601=seconds)
+  echo "!!! [${timestamp}] ${1}" >&2
+}
+
+# Print a status line.  Formatted to show up in a stream of output.
+log::info() {
+  timestamp=$(date --iso-8601=seconds)
+  echo "+++ [${timestamp}] ${1}"
+}
+
+# Run a readiness check until it succeeds or its timeout expires.
+# Args:
+#   $1 Name of the check
+#   $2 Timeout in seconds
+#   $3 Period between attempts in seconds
+#   $4 Command of the check
+readiness-check() {
+  local name="${1}"
+  local timeout="${2}"
+  local period="${3}"
+  local command="${4}"
+  local deadline=$(($(date +%s) + timeout))
+
+  log::info "running readiness check ${name}"
+  until bash -c "${command}"; do
+    if [ "$(date +%s)" -ge "${deadline}" ]; then
+      log::error "readiness check ${name} did not succeed within ${timeout}s"
+      echo "readiness check ${name} did not succeed within ${timeout}s" > "${readiness_checks_failed_file}"
+      exit 1
+    fi
+    sleep "${period}"
+  done
+  log::info "readiness check ${name} succeeded"
+}
+{{ range .ReadinessChecks }}
+readiness-check {{ .Name | ShellQuote }} {{ .TimeoutSeconds }} {{ .PeriodSeconds }} {{ .Command | ShellQuote }}
+{{- end }}
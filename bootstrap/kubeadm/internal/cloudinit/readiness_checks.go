@@ -0,0 +1,120 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudinit
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+)
+
+const (
+	readinessChecksScriptName        = "/run/cluster-api/readiness-checks.sh"
+	readinessChecksScriptOwner       = "root:root"
+	readinessChecksScriptPermissions = "0700"
+	// readinessChecksFailedFile is written by the readiness checks script with the reason of the failure
+	// when a readiness check does not succeed within its timeout.
+	readinessChecksFailedFile = "/run/cluster-api/readiness-checks.failed"
+
+	defaultReadinessCheckTimeoutSeconds = 300
+	defaultReadinessCheckPeriodSeconds  = 5
+	readinessCheckHTTPGetTimeoutSeconds = 10
+)
+
+var (
+	//go:embed readiness-checks.sh
+	readinessChecksScript string
+)
+
+// readinessChecksScriptInput defines the context to generate the readiness checks script.
+type readinessChecksScriptInput struct {
+	FailureFile     string
+	ReadinessChecks []readinessCheck
+}
+
+// readinessCheck is a readiness check with its command and defaults resolved.
+type readinessCheck struct {
+	Name           string
+	Command        string
+	TimeoutSeconds int32
+	PeriodSeconds  int32
+}
+
+// prepareReadinessChecks adds the readiness checks script to the files to be written on the node,
+// and sets the command running it before kubeadm, if any readiness check is defined.
+func (input *BaseUserData) prepareReadinessChecks() error {
+	if len(input.ReadinessChecks) == 0 {
+		return nil
+	}
+
+	scriptFile, err := generateReadinessChecksScript(input.ReadinessChecks)
+	if err != nil {
+		return err
+	}
+	input.WriteFiles = append(input.WriteFiles, *scriptFile)
+	input.ReadinessChecksCommand = readinessChecksScriptName
+	return nil
+}
+
+func generateReadinessChecksScript(checks []bootstrapv1.ReadinessCheck) (*bootstrapv1.File, error) {
+	scriptInput := readinessChecksScriptInput{
+		FailureFile: readinessChecksFailedFile,
+	}
+	for _, check := range checks {
+		c := readinessCheck{
+			Name:           check.Name,
+			Command:        check.Command,
+			TimeoutSeconds: defaultReadinessCheckTimeoutSeconds,
+			PeriodSeconds:  defaultReadinessCheckPeriodSeconds,
+		}
+		if check.HTTPGet != nil {
+			c.Command = httpGetReadinessCheckCommand(check.HTTPGet)
+		}
+		if check.TimeoutSeconds != nil {
+			c.TimeoutSeconds = *check.TimeoutSeconds
+		}
+		if check.PeriodSeconds != nil {
+			c.PeriodSeconds = *check.PeriodSeconds
+		}
+		scriptInput.ReadinessChecks = append(scriptInput.ReadinessChecks, c)
+	}
+
+	script, err := generate("ReadinessChecksScript", readinessChecksScript, scriptInput)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate readiness checks script")
+	}
+	return &bootstrapv1.File{
+		Path:        readinessChecksScriptName,
+		Owner:       readinessChecksScriptOwner,
+		Permissions: readinessChecksScriptPermissions,
+		Content:     string(script),
+	}, nil
+}
+
+// httpGetReadinessCheckCommand returns the command checking an HTTP endpoint returns a 2xx status code.
+func httpGetReadinessCheckCommand(httpGet *bootstrapv1.HTTPGetReadinessCheck) string {
+	args := []string{"curl", "--fail", "--silent", "--show-error", "--output", "/dev/null", "--max-time", fmt.Sprint(readinessCheckHTTPGetTimeoutSeconds)}
+	if httpGet.InsecureSkipTLSVerify {
+		args = append(args, "--insecure")
+	}
+	args = append(args, templateShellQuote(httpGet.URL))
+	return strings.Join(args, " ")
+}
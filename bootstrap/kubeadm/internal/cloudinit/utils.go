@@ -23,7 +23,8 @@ import (
 
 var (
 	defaultTemplateFuncMap = template.FuncMap{
-		"Indent": templateYAMLIndent,
+		"Indent":     templateYAMLIndent,
+		"ShellQuote": templateShellQuote,
 	}
 )
 
@@ -32,3 +33,8 @@ func templateYAMLIndent(i int, input string) string {
 	ident := "\n" + strings.Repeat(" ", i)
 	return strings.Repeat(" ", i) + strings.Join(split, ident)
 }
+
+// templateShellQuote quotes the input as a single argument for a POSIX shell.
+func templateShellQuote(input string) string {
+	return "'" + strings.ReplaceAll(input, "'", `'"'"'`) + "'"
+}
@@ -17,9 +17,13 @@ limitations under the License.
 package controllers
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/base64"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/blang/semver"
@@ -442,7 +446,8 @@ func (r *KubeadmConfigReconciler) handleClusterNotInitialized(ctx context.Contex
 	// injects into config.ClusterConfiguration values from top level object
 	r.reconcileTopLevelObjectSettings(ctx, scope.Cluster, machine, scope.Config)
 
-	clusterdata, err := kubeadmtypes.MarshalClusterConfigurationForVersion(scope.Config.Spec.ClusterConfiguration, parsedVersion)
+	// Apply the image overrides to a copy of the ClusterConfiguration, so they are not persisted in the actual KubeadmConfig.
+	clusterdata, err := kubeadmtypes.MarshalClusterConfigurationForVersion(scope.Config.Spec.ClusterConfigurationWithImageOverrides(), parsedVersion)
 	if err != nil {
 		scope.Error(err, "Failed to marshal cluster configuration")
 		return ctrl.Result{}, err
@@ -496,6 +501,7 @@ func (r *KubeadmConfigReconciler) handleClusterNotInitialized(ctx context.Contex
 			NTP:                 scope.Config.Spec.NTP,
 			PreKubeadmCommands:  scope.Config.Spec.PreKubeadmCommands,
 			PostKubeadmCommands: scope.Config.Spec.PostKubeadmCommands,
+			ReadinessChecks:     scope.Config.Spec.ReadinessChecks,
 			Users:               users,
 			Mounts:              scope.Config.Spec.Mounts,
 			DiskSetup:           scope.Config.Spec.DiskSetup,
@@ -605,6 +611,7 @@ func (r *KubeadmConfigReconciler) joinWorker(ctx context.Context, scope *Scope)
 			NTP:                  scope.Config.Spec.NTP,
 			PreKubeadmCommands:   scope.Config.Spec.PreKubeadmCommands,
 			PostKubeadmCommands:  scope.Config.Spec.PostKubeadmCommands,
+			ReadinessChecks:      scope.Config.Spec.ReadinessChecks,
 			Users:                users,
 			Mounts:               scope.Config.Spec.Mounts,
 			DiskSetup:            scope.Config.Spec.DiskSetup,
@@ -710,6 +717,7 @@ func (r *KubeadmConfigReconciler) joinControlplane(ctx context.Context, scope *S
 			NTP:                  scope.Config.Spec.NTP,
 			PreKubeadmCommands:   scope.Config.Spec.PreKubeadmCommands,
 			PostKubeadmCommands:  scope.Config.Spec.PostKubeadmCommands,
+			ReadinessChecks:      scope.Config.Spec.ReadinessChecks,
 			Users:                users,
 			Mounts:               scope.Config.Spec.Mounts,
 			DiskSetup:            scope.Config.Spec.DiskSetup,
@@ -757,12 +765,34 @@ func (r *KubeadmConfigReconciler) resolveFiles(ctx context.Context, cfg *bootstr
 			in.ContentFrom = nil
 			in.Content = string(data)
 		}
+		if in.Compress {
+			content, err := compressFileContent([]byte(in.Content))
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to compress file %s", in.Path)
+			}
+			in.Compress = false
+			in.Encoding = bootstrapv1.GzipBase64
+			in.Content = content
+		}
 		collected = append(collected, in)
 	}
 
 	return collected, nil
 }
 
+// compressFileContent compresses file content with gzip and encodes it with base64.
+func compressFileContent(content []byte) (string, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(content); err != nil {
+		return "", err
+	}
+	if err := gz.Close(); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
 // resolveSecretFileContent returns file content fetched from a referenced secret object.
 func (r *KubeadmConfigReconciler) resolveSecretFileContent(ctx context.Context, ns string, source bootstrapv1.File) ([]byte, error) {
 	secret := &corev1.Secret{}
@@ -796,6 +826,14 @@ func (r *KubeadmConfigReconciler) resolveUsers(ctx context.Context, cfg *bootstr
 			passwdContent := string(data)
 			in.Passwd = &passwdContent
 		}
+		if in.SSHAuthorizedKeysFrom != nil {
+			data, err := r.resolveSecretSSHAuthorizedKeysContent(ctx, cfg.Namespace, in)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to resolve sshAuthorizedKeys source")
+			}
+			in.SSHAuthorizedKeysFrom = nil
+			in.SSHAuthorizedKeys = append(append([]string{}, in.SSHAuthorizedKeys...), parseSSHAuthorizedKeys(data)...)
+		}
 		collected = append(collected, in)
 	}
 
@@ -819,6 +857,37 @@ func (r *KubeadmConfigReconciler) resolveSecretPasswordContent(ctx context.Conte
 	return data, nil
 }
 
+// resolveSecretSSHAuthorizedKeysContent returns ssh authorized keys fetched from a referenced secret object.
+func (r *KubeadmConfigReconciler) resolveSecretSSHAuthorizedKeysContent(ctx context.Context, ns string, source bootstrapv1.User) ([]byte, error) {
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: ns, Name: source.SSHAuthorizedKeysFrom.Secret.Name}
+	if err := r.Client.Get(ctx, key, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, errors.Wrapf(err, "secret not found: %s", key)
+		}
+		return nil, errors.Wrapf(err, "failed to retrieve Secret %q", key)
+	}
+	data, ok := secret.Data[source.SSHAuthorizedKeysFrom.Secret.Key]
+	if !ok {
+		return nil, errors.Errorf("secret references non-existent secret key: %q", source.SSHAuthorizedKeysFrom.Secret.Key)
+	}
+	return data, nil
+}
+
+// parseSSHAuthorizedKeys parses ssh authorized keys in the authorized_keys file format,
+// ignoring empty lines and comments.
+func parseSSHAuthorizedKeys(data []byte) []string {
+	keys := []string{}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		keys = append(keys, line)
+	}
+	return keys
+}
+
 // ClusterToKubeadmConfigs is a handler.ToRequestsFunc to be used to enqueue
 // requests for reconciliation of KubeadmConfigs.
 func (r *KubeadmConfigReconciler) ClusterToKubeadmConfigs(ctx context.Context, o client.Object) []ctrl.Request {
@@ -542,6 +542,61 @@ func TestKubeadmConfigReconciler_Reconcile_GenerateCloudConfigData(t *testing.T)
 	g.Expect(err).ToNot(HaveOccurred())
 }
 
+func TestKubeadmConfigReconciler_Reconcile_GenerateCloudConfigDataWithImageOverrides(t *testing.T) {
+	g := NewWithT(t)
+
+	configName := "control-plane-init-cfg"
+	cluster := builder.Cluster(metav1.NamespaceDefault, "cluster").Build()
+	cluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{Host: "validhost", Port: 6443}
+	cluster.Status.InfrastructureReady = true
+
+	controlPlaneInitMachine := newControlPlaneMachine(cluster, "control-plane-init-machine")
+	controlPlaneInitConfig := newControlPlaneInitKubeadmConfig(controlPlaneInitMachine.Namespace, configName)
+	controlPlaneInitConfig.Spec.ImageOverrides = &bootstrapv1.ImageOverrides{
+		EtcdImageRepository:    "etcd.example.com",
+		CoreDNSImageRepository: "coredns.example.com",
+	}
+
+	addKubeadmConfigToMachine(controlPlaneInitConfig, controlPlaneInitMachine)
+
+	objects := []client.Object{
+		cluster,
+		controlPlaneInitMachine,
+		controlPlaneInitConfig,
+	}
+	objects = append(objects, createSecrets(t, cluster, controlPlaneInitConfig)...)
+
+	myclient := fake.NewClientBuilder().WithObjects(objects...).WithStatusSubresource(&bootstrapv1.KubeadmConfig{}).Build()
+
+	k := &KubeadmConfigReconciler{
+		Client:              myclient,
+		SecretCachingClient: myclient,
+		Tracker:             remote.NewTestClusterCacheTracker(logr.New(log.NullLogSink{}), myclient, myclient.Scheme(), client.ObjectKey{Name: cluster.Name, Namespace: cluster.Namespace}),
+		KubeadmInitLock:     &myInitLocker{},
+	}
+
+	request := ctrl.Request{
+		NamespacedName: client.ObjectKey{
+			Namespace: metav1.NamespaceDefault,
+			Name:      configName,
+		},
+	}
+	_, err := k.Reconcile(ctx, request)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Expect the bootstrap data to contain the image overrides.
+	s := &corev1.Secret{}
+	g.Expect(myclient.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: configName}, s)).To(Succeed())
+	g.Expect(string(s.Data["value"])).To(ContainSubstring("imageRepository: etcd.example.com"))
+	g.Expect(string(s.Data["value"])).To(ContainSubstring("imageRepository: coredns.example.com"))
+
+	// Expect the image overrides not to be persisted in the KubeadmConfig.
+	cfg, err := getKubeadmConfig(myclient, configName, metav1.NamespaceDefault)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(cfg.Spec.ClusterConfiguration.Etcd.Local).To(BeNil())
+	g.Expect(cfg.Spec.ClusterConfiguration.DNS.ImageRepository).To(BeEmpty())
+}
+
 // If a control plane has no JoinConfiguration, then we will create a default and no error will occur.
 func TestKubeadmConfigReconciler_Reconcile_ErrorIfJoiningControlPlaneHasInvalidConfiguration(t *testing.T) {
 	g := NewWithT(t)
@@ -2018,6 +2073,38 @@ func TestKubeadmConfigReconciler_ResolveFiles(t *testing.T) {
 			},
 			objects: []client.Object{testSecret},
 		},
+		"compressed contentFrom should be compressed and encoded": {
+			cfg: &bootstrapv1.KubeadmConfig{
+				Spec: bootstrapv1.KubeadmConfigSpec{
+					Files: []bootstrapv1.File{
+						{
+							ContentFrom: &bootstrapv1.FileSource{
+								Secret: bootstrapv1.SecretFileSource{
+									Name: "source",
+									Key:  "key",
+								},
+							},
+							Path:        "/path",
+							Owner:       "root:root",
+							Permissions: "0600",
+							Compress:    true,
+							Defer:       true,
+						},
+					},
+				},
+			},
+			expect: []bootstrapv1.File{
+				{
+					Content:     "H4sIAAAAAAAA/wADAPz/Zm9vAwAhZXOMAwAAAA==",
+					Encoding:    bootstrapv1.GzipBase64,
+					Path:        "/path",
+					Owner:       "root:root",
+					Permissions: "0600",
+					Defer:       true,
+				},
+			},
+			objects: []client.Object{testSecret},
+		},
 		"multiple files should work correctly": {
 			cfg: &bootstrapv1.KubeadmConfig{
 				Spec: bootstrapv1.KubeadmConfigSpec{
@@ -2102,7 +2189,8 @@ func TestKubeadmConfigReconciler_ResolveUsers(t *testing.T) {
 			Name: "source",
 		},
 		Data: map[string][]byte{
-			"key": []byte(fakePasswd),
+			"key":             []byte(fakePasswd),
+			"authorized_keys": []byte("# comment\nssh-rsa AAAA foo@example.com\n\nssh-ed25519 BBBB bar@example.com\n"),
 		},
 	}
 
@@ -2153,6 +2241,31 @@ func TestKubeadmConfigReconciler_ResolveUsers(t *testing.T) {
 			},
 			objects: []client.Object{testSecret},
 		},
+		"sshAuthorizedKeysFrom should convert correctly": {
+			cfg: &bootstrapv1.KubeadmConfig{
+				Spec: bootstrapv1.KubeadmConfigSpec{
+					Users: []bootstrapv1.User{
+						{
+							Name:              "foo",
+							SSHAuthorizedKeys: []string{"ssh-rsa CCCC baz@example.com"},
+							SSHAuthorizedKeysFrom: &bootstrapv1.SSHAuthorizedKeysSource{
+								Secret: bootstrapv1.SecretSSHAuthorizedKeysSource{
+									Name: "source",
+									Key:  "authorized_keys",
+								},
+							},
+						},
+					},
+				},
+			},
+			expect: []bootstrapv1.User{
+				{
+					Name:              "foo",
+					SSHAuthorizedKeys: []string{"ssh-rsa CCCC baz@example.com", "ssh-rsa AAAA foo@example.com", "ssh-ed25519 BBBB bar@example.com"},
+				},
+			},
+			objects: []client.Object{testSecret},
+		},
 		"multiple users should work correctly": {
 			cfg: &bootstrapv1.KubeadmConfig{
 				Spec: bootstrapv1.KubeadmConfigSpec{
@@ -2203,10 +2316,14 @@ func TestKubeadmConfigReconciler_ResolveUsers(t *testing.T) {
 			// not been mutated and all password we expected to be sourced
 			// from secret still are.
 			passwdFrom := map[string]bool{}
+			sshAuthorizedKeysFrom := map[string]int{}
 			for _, user := range tc.cfg.Spec.Users {
 				if user.PasswdFrom != nil {
 					passwdFrom[user.Name] = true
 				}
+				if user.SSHAuthorizedKeysFrom != nil {
+					sshAuthorizedKeysFrom[user.Name] = len(user.SSHAuthorizedKeys)
+				}
 			}
 
 			users, err := k.resolveUsers(ctx, tc.cfg)
@@ -2217,6 +2334,10 @@ func TestKubeadmConfigReconciler_ResolveUsers(t *testing.T) {
 					g.Expect(user.PasswdFrom).NotTo(BeNil())
 					g.Expect(user.Passwd).To(BeNil())
 				}
+				if keys, ok := sshAuthorizedKeysFrom[user.Name]; ok {
+					g.Expect(user.SSHAuthorizedKeysFrom).NotTo(BeNil())
+					g.Expect(user.SSHAuthorizedKeys).To(HaveLen(keys))
+				}
 			}
 		})
 	}
@@ -31,4 +31,10 @@ const (
 	//
 	// It will help any validation webhook to take decision based on it.
 	DeleteForMoveAnnotation = "clusterctl.cluster.x-k8s.io/delete-for-move"
+
+	// MoveParentsAnnotation can be set on objects that should be moved together with one or more parent objects,
+	// but that do not have an OwnerReference to them, e.g. IP pools or credentials used by a Cluster.
+	// The value is a comma separated list of parents in the <Kind>[.<group>]/<name> format, e.g. Cluster.cluster.x-k8s.io/my-cluster;
+	// parents must be in the same namespace of the object or global objects.
+	MoveParentsAnnotation = "clusterctl.cluster.x-k8s.io/move-parents"
 )
@@ -0,0 +1,297 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alpha
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+)
+
+const (
+	// bulkStateConfigMapPrefix is the prefix of the name of the ConfigMaps recording the state of bulk operations.
+	bulkStateConfigMapPrefix = "clusterctl-bulk-"
+
+	// bulkPatchHashAnnotation records the hash of the patch applied by a bulk operation, so a bulk operation
+	// can only be resumed with the same patch.
+	bulkPatchHashAnnotation = "clusterctl.cluster.x-k8s.io/bulk-patch-hash"
+
+	// BulkStatePatched is the state recorded for Clusters which have been patched successfully.
+	BulkStatePatched = "Patched"
+
+	// BulkStateFailed is the state recorded for Clusters which failed to be patched.
+	BulkStateFailed = "Failed"
+)
+
+// Bulk defines the behavior of a bulk operations implementation.
+type Bulk interface {
+	// Patch applies a patch to all the Clusters matching a label selector.
+	Patch(cluster.Proxy, BulkPatchInput) (*BulkPatchResult, error)
+}
+
+// BulkPatchInput defines the input of a bulk patch operation.
+type BulkPatchInput struct {
+	// Name identifies the bulk operation. The state of the operation is recorded in a ConfigMap
+	// derived from this name, and running the operation again with the same name resumes it.
+	// If empty, the name is derived from the namespace, selector and patch.
+	Name string
+
+	// Namespace where the Clusters live. If empty, Clusters in all namespaces are patched.
+	Namespace string
+
+	// StateNamespace is the namespace of the ConfigMap recording the state of the operation.
+	StateNamespace string
+
+	// Selector is the label selector for the Clusters to patch.
+	Selector string
+
+	// Patch is the patch to apply to each Cluster, in JSON format.
+	Patch []byte
+
+	// PatchType is the type of the patch; only merge and JSON patches are supported.
+	PatchType types.PatchType
+
+	// Concurrency is the maximum number of Clusters patched in parallel.
+	Concurrency int
+
+	// QPS is the maximum number of Clusters patched per second; if zero, patches are not rate limited.
+	QPS float64
+}
+
+// BulkPatchResult defines the result of a bulk patch operation.
+type BulkPatchResult struct {
+	// Patched is the list of Clusters patched by this execution of the operation.
+	Patched []types.NamespacedName
+
+	// Skipped is the list of Clusters skipped because they have been patched by a previous execution of the operation.
+	Skipped []types.NamespacedName
+
+	// Failed is the list of Clusters which failed to be patched, with the corresponding error.
+	Failed map[types.NamespacedName]error
+}
+
+var _ Bulk = &bulk{}
+
+type bulk struct{}
+
+func newBulkClient() Bulk {
+	return &bulk{}
+}
+
+// Patch applies a patch to all the Clusters matching a label selector, with limited concurrency and rate.
+// Progress is recorded in a ConfigMap after each Cluster, so an interrupted operation can be resumed
+// by running it again with the same name and patch; Clusters already patched are skipped.
+func (b *bulk) Patch(proxy cluster.Proxy, input BulkPatchInput) (*BulkPatchResult, error) {
+	log := logf.Log
+
+	if input.PatchType != types.MergePatchType && input.PatchType != types.JSONPatchType {
+		return nil, errors.Errorf("invalid patch type %q, only %q and %q are supported", input.PatchType, types.MergePatchType, types.JSONPatchType)
+	}
+	if !json.Valid(input.Patch) {
+		return nil, errors.New("the patch must be valid JSON")
+	}
+	selector, err := labels.Parse(input.Selector)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid selector %q", input.Selector)
+	}
+	if selector.Empty() {
+		return nil, errors.New("a non-empty selector is required")
+	}
+	if input.Name == "" {
+		// Derive the name from the operation, so running the same operation again resumes it.
+		input.Name = fmt.Sprintf("%x", sha256.Sum256([]byte(input.Namespace+"/"+input.Selector+"/"+string(input.PatchType)+"/"+string(input.Patch))))[:10]
+	}
+	concurrency := input.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	c, err := proxy.NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	clusterList := &clusterv1.ClusterList{}
+	listOpts := []client.ListOption{client.MatchingLabelsSelector{Selector: selector}}
+	if input.Namespace != "" {
+		listOpts = append(listOpts, client.InNamespace(input.Namespace))
+	}
+	if err := c.List(ctx, clusterList, listOpts...); err != nil {
+		return nil, errors.Wrap(err, "failed to list Clusters")
+	}
+	sort.Slice(clusterList.Items, func(i, j int) bool {
+		return bulkStateKey(&clusterList.Items[i]) < bulkStateKey(&clusterList.Items[j])
+	})
+
+	state, err := getOrCreateBulkState(c, input)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BulkPatchResult{Failed: map[types.NamespacedName]error{}}
+	var pending []*clusterv1.Cluster
+	for i := range clusterList.Items {
+		cl := &clusterList.Items[i]
+		if state.Data[bulkStateKey(cl)] == BulkStatePatched {
+			result.Skipped = append(result.Skipped, client.ObjectKeyFromObject(cl))
+			continue
+		}
+		pending = append(pending, cl)
+	}
+	log.Info("Starting bulk patch", "name", input.Name, "clusters", len(clusterList.Items), "skipped", len(result.Skipped))
+
+	limiter := rate.NewLimiter(rate.Inf, 0)
+	if input.QPS > 0 {
+		limiter = rate.NewLimiter(rate.Limit(input.QPS), 1)
+	}
+
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		processed int
+		stateErr  error
+		waitErr   error
+	)
+	work := make(chan *clusterv1.Cluster)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// NOTE: Workers keep draining work after an error, so the producer never blocks.
+			for cl := range work {
+				if err := limiter.Wait(ctx); err != nil {
+					mu.Lock()
+					if waitErr == nil {
+						waitErr = errors.Wrap(err, "failed to wait for the rate limiter")
+					}
+					mu.Unlock()
+					continue
+				}
+				patchErr := c.Patch(ctx, cl, client.RawPatch(input.PatchType, input.Patch))
+
+				mu.Lock()
+				processed++
+				key := client.ObjectKeyFromObject(cl)
+				status := BulkStatePatched
+				if patchErr != nil {
+					status = BulkStateFailed
+					result.Failed[key] = patchErr
+					log.Info("Failed to patch Cluster", "Cluster", klog.KObj(cl), "progress", fmt.Sprintf("%d/%d", processed, len(pending)), "error", patchErr.Error())
+				} else {
+					result.Patched = append(result.Patched, key)
+					log.Info("Patched Cluster", "Cluster", klog.KObj(cl), "progress", fmt.Sprintf("%d/%d", processed, len(pending)))
+				}
+				if err := recordBulkState(c, state, bulkStateKey(cl), status); err != nil && stateErr == nil {
+					stateErr = err
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, cl := range pending {
+		mu.Lock()
+		stop := stateErr != nil || waitErr != nil
+		mu.Unlock()
+		if stop {
+			break
+		}
+		work <- cl
+	}
+	close(work)
+	wg.Wait()
+
+	if stateErr != nil {
+		return result, stateErr
+	}
+	if waitErr != nil {
+		return result, waitErr
+	}
+	if len(result.Failed) > 0 {
+		return result, errors.Errorf("failed to patch %d Clusters; run the bulk operation %q again to retry them", len(result.Failed), input.Name)
+	}
+	return result, nil
+}
+
+// bulkStateKey returns the key used to record the state of a Cluster in the state ConfigMap.
+// Namespaces cannot contain dots, so the key is unambiguous.
+func bulkStateKey(cl *clusterv1.Cluster) string {
+	return fmt.Sprintf("%s.%s", cl.Namespace, cl.Name)
+}
+
+// getOrCreateBulkState returns the ConfigMap recording the state of a bulk operation, creating it if it does not exist.
+func getOrCreateBulkState(c client.Client, input BulkPatchInput) (*corev1.ConfigMap, error) {
+	patchHash := fmt.Sprintf("%x", sha256.Sum256(append([]byte(input.PatchType+":"), input.Patch...)))
+
+	state := &corev1.ConfigMap{}
+	key := client.ObjectKey{Namespace: input.StateNamespace, Name: bulkStateConfigMapPrefix + input.Name}
+	if err := c.Get(ctx, key, state); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, errors.Wrapf(err, "failed to get state of bulk operation %q", input.Name)
+		}
+		state = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: key.Namespace,
+				Name:      key.Name,
+				Annotations: map[string]string{
+					bulkPatchHashAnnotation: patchHash,
+				},
+			},
+			Data: map[string]string{},
+		}
+		if err := c.Create(ctx, state); err != nil {
+			return nil, errors.Wrapf(err, "failed to create ConfigMap %s to record the state of bulk operation %q", klog.KObj(state), input.Name)
+		}
+		return state, nil
+	}
+
+	if state.Annotations[bulkPatchHashAnnotation] != patchHash {
+		return nil, errors.Errorf("bulk operation %q has been started with a different patch; use a different name or delete ConfigMap %s", input.Name, klog.KObj(state))
+	}
+	if state.Data == nil {
+		state.Data = map[string]string{}
+	}
+	return state, nil
+}
+
+// recordBulkState records the state of a Cluster in the state ConfigMap.
+func recordBulkState(c client.Client, state *corev1.ConfigMap, key, status string) error {
+	patch, err := json.Marshal(map[string]interface{}{"data": map[string]string{key: status}})
+	if err != nil {
+		return err
+	}
+	if err := c.Patch(ctx, state, client.RawPatch(types.MergePatchType, patch)); err != nil {
+		return errors.Wrapf(err, "failed to record state of Cluster %s in ConfigMap %s", strings.Replace(key, ".", "/", 1), klog.KObj(state))
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alpha
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+func Test_BulkPatch(t *testing.T) {
+	newCluster := func(namespace, name string, labels map[string]string) *clusterv1.Cluster {
+		return &clusterv1.Cluster{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Cluster",
+				APIVersion: clusterv1.GroupVersion.String(),
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      name,
+				Labels:    labels,
+			},
+		}
+	}
+	dev := map[string]string{"env": "dev"}
+	input := BulkPatchInput{
+		Name:           "test",
+		StateNamespace: "default",
+		Selector:       "env=dev",
+		Patch:          []byte(`{"metadata":{"annotations":{"patched":"true"}}}`),
+		PatchType:      types.MergePatchType,
+		Concurrency:    2,
+	}
+
+	g := NewWithT(t)
+	proxy := test.NewFakeProxy().WithObjs(
+		newCluster("ns1", "cluster1", dev),
+		newCluster("ns2", "cluster2", dev),
+		newCluster("ns1", "cluster3", map[string]string{"env": "prod"}),
+	)
+	b := newBulkClient()
+
+	// Patch the Clusters matching the selector and record the state.
+	result, err := b.Patch(proxy, input)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result.Patched).To(ConsistOf(
+		types.NamespacedName{Namespace: "ns1", Name: "cluster1"},
+		types.NamespacedName{Namespace: "ns2", Name: "cluster2"},
+	))
+	g.Expect(result.Skipped).To(BeEmpty())
+	g.Expect(result.Failed).To(BeEmpty())
+
+	c, err := proxy.NewClient()
+	g.Expect(err).ToNot(HaveOccurred())
+	for _, key := range []client.ObjectKey{{Namespace: "ns1", Name: "cluster1"}, {Namespace: "ns2", Name: "cluster2"}, {Namespace: "ns1", Name: "cluster3"}} {
+		cl := &clusterv1.Cluster{}
+		g.Expect(c.Get(context.TODO(), key, cl)).To(Succeed())
+		if key.Name == "cluster3" {
+			g.Expect(cl.Annotations).ToNot(HaveKey("patched"))
+			continue
+		}
+		g.Expect(cl.Annotations).To(HaveKeyWithValue("patched", "true"))
+	}
+
+	state := &corev1.ConfigMap{}
+	g.Expect(c.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: "clusterctl-bulk-test"}, state)).To(Succeed())
+	g.Expect(state.Data).To(Equal(map[string]string{
+		"ns1.cluster1": BulkStatePatched,
+		"ns2.cluster2": BulkStatePatched,
+	}))
+
+	// Running the operation again skips the Clusters already patched.
+	g.Expect(c.Create(context.TODO(), newCluster("ns1", "cluster4", dev))).To(Succeed())
+	result, err = b.Patch(proxy, input)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result.Patched).To(ConsistOf(types.NamespacedName{Namespace: "ns1", Name: "cluster4"}))
+	g.Expect(result.Skipped).To(HaveLen(2))
+
+	// Resuming the operation with a different patch fails.
+	input.Patch = []byte(`{"metadata":{"annotations":{"patched":"false"}}}`)
+	_, err = b.Patch(proxy, input)
+	g.Expect(err).To(HaveOccurred())
+}
+
+func Test_BulkPatchRateLimiterError(t *testing.T) {
+	g := NewWithT(t)
+
+	// Use a cancelled context, so waiting for the rate limiter fails for every Cluster.
+	cancelledCtx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	ctx = cancelledCtx
+	defer func() { ctx = context.TODO() }()
+
+	proxy := test.NewFakeProxy()
+	for _, name := range []string{"cluster1", "cluster2", "cluster3", "cluster4"} {
+		proxy.WithObjs(&clusterv1.Cluster{
+			TypeMeta:   metav1.TypeMeta{Kind: "Cluster", APIVersion: clusterv1.GroupVersion.String()},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: name, Labels: map[string]string{"env": "dev"}},
+		})
+	}
+	input := BulkPatchInput{
+		Name:           "test",
+		StateNamespace: "default",
+		Selector:       "env=dev",
+		Patch:          []byte(`{"metadata":{"annotations":{"patched":"true"}}}`),
+		PatchType:      types.MergePatchType,
+		Concurrency:    1,
+		QPS:            1,
+	}
+
+	result, err := newBulkClient().Patch(proxy, input)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(result.Patched).To(BeEmpty())
+}
+
+func Test_BulkPatchInvalidInput(t *testing.T) {
+	tests := []struct {
+		name  string
+		input BulkPatchInput
+	}{
+		{
+			name:  "empty selector",
+			input: BulkPatchInput{Name: "test", Patch: []byte(`{}`), PatchType: types.MergePatchType},
+		},
+		{
+			name:  "invalid selector",
+			input: BulkPatchInput{Name: "test", Selector: "env in (", Patch: []byte(`{}`), PatchType: types.MergePatchType},
+		},
+		{
+			name:  "unsupported patch type",
+			input: BulkPatchInput{Name: "test", Selector: "env=dev", Patch: []byte(`{}`), PatchType: types.StrategicMergePatchType},
+		},
+		{
+			name:  "invalid patch",
+			input: BulkPatchInput{Name: "test", Selector: "env=dev", Patch: []byte(`{`), PatchType: types.MergePatchType},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			_, err := newBulkClient().Patch(test.NewFakeProxy(), tt.input)
+			g.Expect(err).To(HaveOccurred())
+		})
+	}
+}
@@ -25,11 +25,17 @@ var (
 // Client is the alpha client.
 type Client interface {
 	Rollout() Rollout
+	Bulk() Bulk
+	ClusterUpgrade() ClusterUpgrade
+	TopologyAdopt() TopologyAdopt
 }
 
 // alphaClient implements Client.
 type alphaClient struct {
-	rollout Rollout
+	rollout        Rollout
+	bulk           Bulk
+	clusterUpgrade ClusterUpgrade
+	topologyAdopt  TopologyAdopt
 }
 
 // ensure alphaClient implements Client.
@@ -45,6 +51,27 @@ func InjectRollout(rollout Rollout) Option {
 	}
 }
 
+// InjectBulk allows to override the bulk implementation to use.
+func InjectBulk(bulk Bulk) Option {
+	return func(c *alphaClient) {
+		c.bulk = bulk
+	}
+}
+
+// InjectClusterUpgrade allows to override the cluster upgrade implementation to use.
+func InjectClusterUpgrade(clusterUpgrade ClusterUpgrade) Option {
+	return func(c *alphaClient) {
+		c.clusterUpgrade = clusterUpgrade
+	}
+}
+
+// InjectTopologyAdopt allows to override the topology adopt implementation to use.
+func InjectTopologyAdopt(topologyAdopt TopologyAdopt) Option {
+	return func(c *alphaClient) {
+		c.topologyAdopt = topologyAdopt
+	}
+}
+
 // New returns a Client.
 func New(options ...Option) Client {
 	return newAlphaClient(options...)
@@ -61,9 +88,36 @@ func newAlphaClient(options ...Option) *alphaClient {
 		client.rollout = newRolloutClient()
 	}
 
+	// if there is an injected bulk, use it, otherwise use a default one
+	if client.bulk == nil {
+		client.bulk = newBulkClient()
+	}
+
+	// if there is an injected cluster upgrade, use it, otherwise use a default one
+	if client.clusterUpgrade == nil {
+		client.clusterUpgrade = newClusterUpgradeClient()
+	}
+
+	// if there is an injected topology adopt, use it, otherwise use a default one
+	if client.topologyAdopt == nil {
+		client.topologyAdopt = newTopologyAdoptClient()
+	}
+
 	return client
 }
 
 func (c *alphaClient) Rollout() Rollout {
 	return c.rollout
 }
+
+func (c *alphaClient) Bulk() Bulk {
+	return c.bulk
+}
+
+func (c *alphaClient) ClusterUpgrade() ClusterUpgrade {
+	return c.clusterUpgrade
+}
+
+func (c *alphaClient) TopologyAdopt() TopologyAdopt {
+	return c.topologyAdopt
+}
@@ -0,0 +1,395 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alpha
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/blang/semver"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+	"sigs.k8s.io/cluster-api/controllers/external"
+	"sigs.k8s.io/cluster-api/internal/contract"
+	"sigs.k8s.io/cluster-api/internal/controllers/machinedeployment/mdutil"
+)
+
+const (
+	// defaultClusterUpgradeTimeout is the default maximum time to wait for each step of a cluster upgrade.
+	defaultClusterUpgradeTimeout = 30 * time.Minute
+
+	// defaultClusterUpgradePollInterval is the default interval between checks of the progress of a cluster upgrade step.
+	defaultClusterUpgradePollInterval = 10 * time.Second
+)
+
+// ClusterUpgrade defines the behavior of a workload cluster Kubernetes version upgrade implementation.
+type ClusterUpgrade interface {
+	// Plan returns the steps required to upgrade the Kubernetes version of a Cluster, without changing it.
+	Plan(cluster.Proxy, ClusterUpgradeInput) (*ClusterUpgradePlan, error)
+
+	// Apply upgrades the Kubernetes version of a Cluster, executing the steps of the plan in order and
+	// waiting for each step to complete before starting the next one.
+	Apply(cluster.Proxy, ClusterUpgradeInput) (*ClusterUpgradePlan, error)
+}
+
+// ClusterUpgradeInput defines the input of a cluster upgrade operation.
+type ClusterUpgradeInput struct {
+	// Name of the Cluster to upgrade.
+	Name string
+
+	// Namespace of the Cluster to upgrade.
+	Namespace string
+
+	// KubernetesVersion is the target Kubernetes version.
+	KubernetesVersion string
+
+	// Timeout is the maximum time to wait for each step to complete. If zero, 30 minutes are used.
+	Timeout time.Duration
+
+	// PollInterval is the interval between checks of the progress of each step. If zero, 10 seconds are used.
+	PollInterval time.Duration
+}
+
+// ClusterUpgradePlan defines the steps required to upgrade the Kubernetes version of a Cluster.
+type ClusterUpgradePlan struct {
+	// Cluster is the Cluster to upgrade.
+	Cluster types.NamespacedName
+
+	// CurrentVersion is the current Kubernetes version of the control plane of the Cluster.
+	CurrentVersion string
+
+	// TargetVersion is the target Kubernetes version.
+	TargetVersion string
+
+	// Steps are the steps of the upgrade, in the order they are executed.
+	// If empty, the Cluster is already at the target version.
+	Steps []ClusterUpgradeStep
+}
+
+// ClusterUpgradeStep defines a step of a cluster upgrade, i.e. the change of the Kubernetes version of an object.
+type ClusterUpgradeStep struct {
+	// Kind of the object changed by the step: Cluster for Clusters with a managed topology, the kind of
+	// the control plane or MachineDeployment otherwise.
+	Kind string
+
+	// Name of the object changed by the step.
+	Name string
+
+	// FromVersion is the current Kubernetes version of the object.
+	FromVersion string
+
+	// ToVersion is the target Kubernetes version of the object.
+	ToVersion string
+}
+
+var _ ClusterUpgrade = &clusterUpgrade{}
+
+type clusterUpgrade struct{}
+
+func newClusterUpgradeClient() ClusterUpgrade {
+	return &clusterUpgrade{}
+}
+
+// Plan returns the steps required to upgrade the Kubernetes version of a Cluster: for Clusters with a managed
+// topology the only step is changing the topology version, given that the topology controller takes care of
+// upgrading the control plane and then the MachineDeployments; for other Clusters the control plane is
+// upgraded first, and then each MachineDeployment.
+func (u *clusterUpgrade) Plan(proxy cluster.Proxy, input ClusterUpgradeInput) (*ClusterUpgradePlan, error) {
+	c, err := proxy.NewClient()
+	if err != nil {
+		return nil, err
+	}
+	return planClusterUpgrade(c, input)
+}
+
+// Apply upgrades the Kubernetes version of a Cluster, executing the steps of the plan in order and waiting
+// for each step to complete before starting the next one.
+func (u *clusterUpgrade) Apply(proxy cluster.Proxy, input ClusterUpgradeInput) (*ClusterUpgradePlan, error) {
+	log := logf.Log
+
+	c, err := proxy.NewClient()
+	if err != nil {
+		return nil, err
+	}
+	plan, err := planClusterUpgrade(c, input)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := input.Timeout
+	if timeout == 0 {
+		timeout = defaultClusterUpgradeTimeout
+	}
+	pollInterval := input.PollInterval
+	if pollInterval == 0 {
+		pollInterval = defaultClusterUpgradePollInterval
+	}
+
+	for i, step := range plan.Steps {
+		log.Info(fmt.Sprintf("Upgrading %s %s from %s to %s", step.Kind, step.Name, step.FromVersion, step.ToVersion), "step", fmt.Sprintf("%d/%d", i+1, len(plan.Steps)))
+
+		var upgraded func(context.Context) (bool, error)
+		switch step.Kind {
+		case "Cluster":
+			upgraded, err = upgradeTopologyVersion(c, plan)
+		case "MachineDeployment":
+			upgraded, err = upgradeMachineDeployment(c, plan, step)
+		default:
+			upgraded, err = upgradeControlPlane(c, plan)
+		}
+		if err != nil {
+			return plan, err
+		}
+
+		log.Info(fmt.Sprintf("Waiting for %s %s to be upgraded", step.Kind, step.Name))
+		if err := wait.PollUntilContextTimeout(ctx, pollInterval, timeout, true, upgraded); err != nil {
+			return plan, errors.Wrapf(err, "failed waiting for %s %s to be upgraded to %s", step.Kind, step.Name, step.ToVersion)
+		}
+	}
+	return plan, nil
+}
+
+// planClusterUpgrade computes the steps required to upgrade the Kubernetes version of a Cluster.
+func planClusterUpgrade(c client.Client, input ClusterUpgradeInput) (*ClusterUpgradePlan, error) {
+	targetVersion, err := semver.ParseTolerant(input.KubernetesVersion)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid Kubernetes version %q", input.KubernetesVersion)
+	}
+
+	cl := &clusterv1.Cluster{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: input.Namespace, Name: input.Name}, cl); err != nil {
+		return nil, errors.Wrapf(err, "failed to get Cluster %s/%s", input.Namespace, input.Name)
+	}
+
+	plan := &ClusterUpgradePlan{
+		Cluster:       client.ObjectKeyFromObject(cl),
+		TargetVersion: input.KubernetesVersion,
+	}
+
+	// For Clusters with a managed topology, the topology controller upgrades the control plane and the
+	// MachineDeployments in the correct order.
+	if cl.Spec.Topology != nil {
+		plan.CurrentVersion = cl.Spec.Topology.Version
+		if err := validateUpgradePath(plan.CurrentVersion, targetVersion); err != nil {
+			return nil, err
+		}
+		if plan.CurrentVersion != plan.TargetVersion {
+			plan.Steps = append(plan.Steps, ClusterUpgradeStep{Kind: "Cluster", Name: cl.Name, FromVersion: plan.CurrentVersion, ToVersion: plan.TargetVersion})
+		}
+		return plan, nil
+	}
+
+	if cl.Spec.ControlPlaneRef == nil {
+		return nil, errors.Errorf("Cluster %s has no control plane; only Clusters with a control plane can be upgraded", klog.KObj(cl))
+	}
+	controlPlane, err := external.Get(ctx, c, cl.Spec.ControlPlaneRef, cl.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	controlPlaneVersion, err := contract.ControlPlane().Version().Get(controlPlane)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the version of %s %s", controlPlane.GetKind(), klog.KObj(controlPlane))
+	}
+	plan.CurrentVersion = *controlPlaneVersion
+	if err := validateUpgradePath(plan.CurrentVersion, targetVersion); err != nil {
+		return nil, err
+	}
+	if plan.CurrentVersion != plan.TargetVersion {
+		plan.Steps = append(plan.Steps, ClusterUpgradeStep{Kind: controlPlane.GetKind(), Name: controlPlane.GetName(), FromVersion: plan.CurrentVersion, ToVersion: plan.TargetVersion})
+	}
+
+	// The MachineDeployments are upgraded after the control plane, given that the version of the kubelet
+	// must not be newer than the version of the API server.
+	machineDeployments := &clusterv1.MachineDeploymentList{}
+	if err := c.List(ctx, machineDeployments, client.InNamespace(cl.Namespace), client.MatchingLabels{clusterv1.ClusterNameLabel: cl.Name}); err != nil {
+		return nil, errors.Wrapf(err, "failed to list MachineDeployments of Cluster %s", klog.KObj(cl))
+	}
+	sort.Slice(machineDeployments.Items, func(i, j int) bool {
+		return machineDeployments.Items[i].Name < machineDeployments.Items[j].Name
+	})
+	for _, md := range machineDeployments.Items {
+		var mdVersion string
+		if md.Spec.Template.Spec.Version != nil {
+			mdVersion = *md.Spec.Template.Spec.Version
+		}
+		if mdVersion == plan.TargetVersion {
+			continue
+		}
+		plan.Steps = append(plan.Steps, ClusterUpgradeStep{Kind: "MachineDeployment", Name: md.Name, FromVersion: mdVersion, ToVersion: plan.TargetVersion})
+	}
+	return plan, nil
+}
+
+// validateUpgradePath returns an error if the Kubernetes version cannot be changed from current to target,
+// i.e. in case of downgrades or of upgrades skipping a minor version.
+func validateUpgradePath(current string, target semver.Version) error {
+	currentVersion, err := semver.ParseTolerant(current)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse the current Kubernetes version %q", current)
+	}
+	if target.LT(currentVersion) {
+		return errors.Errorf("downgrading the Kubernetes version from %s to v%s is not supported", current, target)
+	}
+	if target.Major != currentVersion.Major || target.Minor > currentVersion.Minor+1 {
+		return errors.Errorf("upgrading the Kubernetes version from %s to v%s is not supported: minor versions cannot be skipped, upgrade to v%d.%d first", current, target, currentVersion.Major, currentVersion.Minor+1)
+	}
+	return nil
+}
+
+// upgradeTopologyVersion changes the version of the managed topology of a Cluster, and returns a func checking
+// if the control plane and the MachineDeployments of the Cluster have been upgraded.
+func upgradeTopologyVersion(c client.Client, plan *ClusterUpgradePlan) (func(context.Context) (bool, error), error) {
+	cl := &clusterv1.Cluster{}
+	if err := c.Get(ctx, plan.Cluster, cl); err != nil {
+		return nil, errors.Wrapf(err, "failed to get Cluster %s", plan.Cluster)
+	}
+	if err := c.Patch(ctx, cl, operationIDPatch()); err != nil {
+		return nil, errors.Wrapf(err, "failed to set operation ID on Cluster %s", plan.Cluster)
+	}
+	patch := client.RawPatch(types.MergePatchType, []byte(fmt.Sprintf(`{"spec":{"topology":{"version":%q}}}`, plan.TargetVersion)))
+	if err := c.Patch(ctx, cl, patch); err != nil {
+		return nil, errors.Wrapf(err, "failed to patch the topology version of Cluster %s", plan.Cluster)
+	}
+
+	return func(ctx context.Context) (bool, error) {
+		cl := &clusterv1.Cluster{}
+		if err := c.Get(ctx, plan.Cluster, cl); err != nil {
+			return false, errors.Wrapf(err, "failed to get Cluster %s", plan.Cluster)
+		}
+		if cl.Spec.ControlPlaneRef == nil {
+			return false, nil
+		}
+		controlPlane, err := external.Get(ctx, c, cl.Spec.ControlPlaneRef, cl.Namespace)
+		if err != nil {
+			return false, err
+		}
+		if upgraded, err := isControlPlaneUpgraded(controlPlane, plan.TargetVersion); err != nil || !upgraded {
+			return false, err
+		}
+
+		machineDeployments := &clusterv1.MachineDeploymentList{}
+		if err := c.List(ctx, machineDeployments, client.InNamespace(cl.Namespace), client.MatchingLabels{clusterv1.ClusterNameLabel: cl.Name}); err != nil {
+			return false, errors.Wrapf(err, "failed to list MachineDeployments of Cluster %s", plan.Cluster)
+		}
+		for i := range machineDeployments.Items {
+			if !isMachineDeploymentUpgraded(&machineDeployments.Items[i], plan.TargetVersion) {
+				return false, nil
+			}
+		}
+		return true, nil
+	}, nil
+}
+
+// upgradeControlPlane changes the version of the control plane of a Cluster, and returns a func checking
+// if the control plane has been upgraded.
+func upgradeControlPlane(c client.Client, plan *ClusterUpgradePlan) (func(context.Context) (bool, error), error) {
+	cl := &clusterv1.Cluster{}
+	if err := c.Get(ctx, plan.Cluster, cl); err != nil {
+		return nil, errors.Wrapf(err, "failed to get Cluster %s", plan.Cluster)
+	}
+	controlPlane, err := external.Get(ctx, c, cl.Spec.ControlPlaneRef, cl.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.Patch(ctx, controlPlane, operationIDPatch()); err != nil {
+		return nil, errors.Wrapf(err, "failed to set operation ID on %s %s", controlPlane.GetKind(), klog.KObj(controlPlane))
+	}
+	patch := client.RawPatch(types.MergePatchType, []byte(fmt.Sprintf(`{"spec":{"version":%q}}`, plan.TargetVersion)))
+	if err := c.Patch(ctx, controlPlane, patch); err != nil {
+		return nil, errors.Wrapf(err, "failed to patch the version of %s %s", controlPlane.GetKind(), klog.KObj(controlPlane))
+	}
+
+	return func(ctx context.Context) (bool, error) {
+		controlPlane, err := external.Get(ctx, c, cl.Spec.ControlPlaneRef, cl.Namespace)
+		if err != nil {
+			return false, err
+		}
+		return isControlPlaneUpgraded(controlPlane, plan.TargetVersion)
+	}, nil
+}
+
+// upgradeMachineDeployment changes the version of a MachineDeployment, and returns a func checking
+// if the MachineDeployment has been upgraded.
+func upgradeMachineDeployment(c client.Client, plan *ClusterUpgradePlan, step ClusterUpgradeStep) (func(context.Context) (bool, error), error) {
+	key := client.ObjectKey{Namespace: plan.Cluster.Namespace, Name: step.Name}
+	md := &clusterv1.MachineDeployment{}
+	if err := c.Get(ctx, key, md); err != nil {
+		return nil, errors.Wrapf(err, "failed to get MachineDeployment %s", key)
+	}
+	if err := c.Patch(ctx, md, operationIDPatch()); err != nil {
+		return nil, errors.Wrapf(err, "failed to set operation ID on MachineDeployment %s", key)
+	}
+	patch := client.RawPatch(types.MergePatchType, []byte(fmt.Sprintf(`{"spec":{"template":{"spec":{"version":%q}}}}`, plan.TargetVersion)))
+	if err := c.Patch(ctx, md, patch); err != nil {
+		return nil, errors.Wrapf(err, "failed to patch the version of MachineDeployment %s", key)
+	}
+
+	return func(ctx context.Context) (bool, error) {
+		md := &clusterv1.MachineDeployment{}
+		if err := c.Get(ctx, key, md); err != nil {
+			if apierrors.IsNotFound(err) {
+				return false, errors.Errorf("MachineDeployment %s has been deleted", key)
+			}
+			return false, errors.Wrapf(err, "failed to get MachineDeployment %s", key)
+		}
+		return isMachineDeploymentUpgraded(md, plan.TargetVersion), nil
+	}, nil
+}
+
+// isControlPlaneUpgraded returns true if all the machines of the control plane run the target version and
+// the control plane is neither upgrading nor scaling.
+func isControlPlaneUpgraded(controlPlane *unstructured.Unstructured, targetVersion string) (bool, error) {
+	statusVersion, err := contract.ControlPlane().StatusVersion().Get(controlPlane)
+	if err != nil {
+		if errors.Is(err, contract.ErrFieldNotFound) {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "failed to get the status version of %s %s", controlPlane.GetKind(), klog.KObj(controlPlane))
+	}
+	if *statusVersion != targetVersion {
+		return false, nil
+	}
+	if upgrading, err := contract.ControlPlane().IsUpgrading(controlPlane); err != nil || upgrading {
+		return false, err
+	}
+	if scaling, err := contract.ControlPlane().IsScaling(controlPlane); err != nil || scaling {
+		return false, err
+	}
+	return true, nil
+}
+
+// isMachineDeploymentUpgraded returns true if the MachineDeployment has the target version and its rollout is complete.
+func isMachineDeploymentUpgraded(md *clusterv1.MachineDeployment, targetVersion string) bool {
+	if md.Spec.Template.Spec.Version == nil || *md.Spec.Template.Spec.Version != targetVersion {
+		return false
+	}
+	if md.Spec.Replicas == nil {
+		return false
+	}
+	return mdutil.DeploymentComplete(md, &md.Status)
+}
@@ -0,0 +1,335 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alpha
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+)
+
+func Test_ClusterUpgrade_Plan(t *testing.T) {
+	newCluster := func(topologyVersion string) *clusterv1.Cluster {
+		cluster := &clusterv1.Cluster{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Cluster",
+				APIVersion: clusterv1.GroupVersion.String(),
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "cluster1",
+			},
+			Spec: clusterv1.ClusterSpec{
+				ControlPlaneRef: &corev1.ObjectReference{
+					Kind:       "KubeadmControlPlane",
+					APIVersion: controlplanev1.GroupVersion.String(),
+					Namespace:  "default",
+					Name:       "cp1",
+				},
+			},
+		}
+		if topologyVersion != "" {
+			cluster.Spec.Topology = &clusterv1.Topology{Class: "class1", Version: topologyVersion}
+		}
+		return cluster
+	}
+
+	tests := []struct {
+		name              string
+		objs              []client.Object
+		kubernetesVersion string
+		wantErr           bool
+		wantPlan          *ClusterUpgradePlan
+	}{
+		{
+			name: "cluster with a managed topology is upgraded by changing the topology version",
+			objs: []client.Object{
+				newCluster("v1.28.3"),
+				newKubeadmControlPlane("cp1", "v1.28.3", "v1.28.3"),
+				newMachineDeployment("md1", "v1.28.3"),
+			},
+			kubernetesVersion: "v1.29.0",
+			wantPlan: &ClusterUpgradePlan{
+				Cluster:        types.NamespacedName{Namespace: "default", Name: "cluster1"},
+				CurrentVersion: "v1.28.3",
+				TargetVersion:  "v1.29.0",
+				Steps: []ClusterUpgradeStep{
+					{Kind: "Cluster", Name: "cluster1", FromVersion: "v1.28.3", ToVersion: "v1.29.0"},
+				},
+			},
+		},
+		{
+			name: "cluster without a managed topology is upgraded control plane first, then MachineDeployments",
+			objs: []client.Object{
+				newCluster(""),
+				newKubeadmControlPlane("cp1", "v1.28.3", "v1.28.3"),
+				newMachineDeployment("md2", "v1.28.3"),
+				newMachineDeployment("md1", "v1.28.3"),
+			},
+			kubernetesVersion: "v1.29.0",
+			wantPlan: &ClusterUpgradePlan{
+				Cluster:        types.NamespacedName{Namespace: "default", Name: "cluster1"},
+				CurrentVersion: "v1.28.3",
+				TargetVersion:  "v1.29.0",
+				Steps: []ClusterUpgradeStep{
+					{Kind: "KubeadmControlPlane", Name: "cp1", FromVersion: "v1.28.3", ToVersion: "v1.29.0"},
+					{Kind: "MachineDeployment", Name: "md1", FromVersion: "v1.28.3", ToVersion: "v1.29.0"},
+					{Kind: "MachineDeployment", Name: "md2", FromVersion: "v1.28.3", ToVersion: "v1.29.0"},
+				},
+			},
+		},
+		{
+			name: "objects already at the target version are skipped",
+			objs: []client.Object{
+				newCluster(""),
+				newKubeadmControlPlane("cp1", "v1.29.0", "v1.29.0"),
+				newMachineDeployment("md1", "v1.29.0"),
+				newMachineDeployment("md2", "v1.28.3"),
+			},
+			kubernetesVersion: "v1.29.0",
+			wantPlan: &ClusterUpgradePlan{
+				Cluster:        types.NamespacedName{Namespace: "default", Name: "cluster1"},
+				CurrentVersion: "v1.29.0",
+				TargetVersion:  "v1.29.0",
+				Steps: []ClusterUpgradeStep{
+					{Kind: "MachineDeployment", Name: "md2", FromVersion: "v1.28.3", ToVersion: "v1.29.0"},
+				},
+			},
+		},
+		{
+			name: "downgrades are not supported",
+			objs: []client.Object{
+				newCluster("v1.28.3"),
+			},
+			kubernetesVersion: "v1.28.1",
+			wantErr:           true,
+		},
+		{
+			name: "upgrades skipping a minor version are not supported",
+			objs: []client.Object{
+				newCluster(""),
+				newKubeadmControlPlane("cp1", "v1.27.3", "v1.27.3"),
+			},
+			kubernetesVersion: "v1.29.0",
+			wantErr:           true,
+		},
+		{
+			name: "invalid target version",
+			objs: []client.Object{
+				newCluster("v1.28.3"),
+			},
+			kubernetesVersion: "latest",
+			wantErr:           true,
+		},
+		{
+			name:              "cluster does not exist",
+			kubernetesVersion: "v1.29.0",
+			wantErr:           true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			proxy := test.NewFakeProxy().WithObjs(tt.objs...)
+			u := newClusterUpgradeClient()
+
+			plan, err := u.Plan(proxy, ClusterUpgradeInput{
+				Name:              "cluster1",
+				Namespace:         "default",
+				KubernetesVersion: tt.kubernetesVersion,
+			})
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(plan).To(Equal(tt.wantPlan))
+		})
+	}
+}
+
+func Test_ClusterUpgrade_Apply(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := &clusterv1.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Cluster",
+			APIVersion: clusterv1.GroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "cluster1",
+		},
+		Spec: clusterv1.ClusterSpec{
+			ControlPlaneRef: &corev1.ObjectReference{
+				Kind:       "KubeadmControlPlane",
+				APIVersion: controlplanev1.GroupVersion.String(),
+				Namespace:  "default",
+				Name:       "cp1",
+			},
+		},
+	}
+	// NOTE: status versions are already set to the target version, given that there are no controllers
+	// upgrading the machines in this test.
+	proxy := test.NewFakeProxy().WithObjs(
+		cluster,
+		newKubeadmControlPlane("cp1", "v1.28.3", "v1.29.0"),
+		newMachineDeployment("md1", "v1.28.3"),
+	)
+	u := newClusterUpgradeClient()
+
+	plan, err := u.Apply(proxy, ClusterUpgradeInput{
+		Name:              "cluster1",
+		Namespace:         "default",
+		KubernetesVersion: "v1.29.0",
+		Timeout:           time.Second,
+		PollInterval:      10 * time.Millisecond,
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(plan.Steps).To(HaveLen(2))
+
+	c, err := proxy.NewClient()
+	g.Expect(err).ToNot(HaveOccurred())
+
+	kcp := &controlplanev1.KubeadmControlPlane{}
+	g.Expect(c.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: "cp1"}, kcp)).To(Succeed())
+	g.Expect(kcp.Spec.Version).To(Equal("v1.29.0"))
+	g.Expect(kcp.Annotations).To(HaveKey(clusterv1.OperationIDAnnotation))
+
+	md := &clusterv1.MachineDeployment{}
+	g.Expect(c.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: "md1"}, md)).To(Succeed())
+	g.Expect(md.Spec.Template.Spec.Version).To(HaveValue(Equal("v1.29.0")))
+
+	// Upgrading again is a no-op.
+	plan, err = u.Apply(proxy, ClusterUpgradeInput{
+		Name:              "cluster1",
+		Namespace:         "default",
+		KubernetesVersion: "v1.29.0",
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(plan.Steps).To(BeEmpty())
+}
+
+func Test_ClusterUpgrade_ApplyTimeout(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := &clusterv1.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Cluster",
+			APIVersion: clusterv1.GroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "cluster1",
+		},
+		Spec: clusterv1.ClusterSpec{
+			ControlPlaneRef: &corev1.ObjectReference{
+				Kind:       "KubeadmControlPlane",
+				APIVersion: controlplanev1.GroupVersion.String(),
+				Namespace:  "default",
+				Name:       "cp1",
+			},
+		},
+	}
+	proxy := test.NewFakeProxy().WithObjs(
+		cluster,
+		newKubeadmControlPlane("cp1", "v1.28.3", "v1.28.3"),
+		newMachineDeployment("md1", "v1.28.3"),
+	)
+	u := newClusterUpgradeClient()
+
+	// The control plane is never upgraded, so the MachineDeployments must not be changed.
+	_, err := u.Apply(proxy, ClusterUpgradeInput{
+		Name:              "cluster1",
+		Namespace:         "default",
+		KubernetesVersion: "v1.29.0",
+		Timeout:           100 * time.Millisecond,
+		PollInterval:      10 * time.Millisecond,
+	})
+	g.Expect(err).To(HaveOccurred())
+
+	c, err := proxy.NewClient()
+	g.Expect(err).ToNot(HaveOccurred())
+	md := &clusterv1.MachineDeployment{}
+	g.Expect(c.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: "md1"}, md)).To(Succeed())
+	g.Expect(md.Spec.Template.Spec.Version).To(HaveValue(Equal("v1.28.3")))
+}
+
+func newKubeadmControlPlane(name, version, statusVersion string) *controlplanev1.KubeadmControlPlane {
+	return &controlplanev1.KubeadmControlPlane{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "KubeadmControlPlane",
+			APIVersion: controlplanev1.GroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name,
+		},
+		Spec: controlplanev1.KubeadmControlPlaneSpec{
+			Replicas: pointer.Int32(3),
+			Version:  version,
+		},
+		Status: controlplanev1.KubeadmControlPlaneStatus{
+			Version:         pointer.String(statusVersion),
+			Replicas:        3,
+			UpdatedReplicas: 3,
+			ReadyReplicas:   3,
+		},
+	}
+}
+
+func newMachineDeployment(name, version string) *clusterv1.MachineDeployment {
+	return &clusterv1.MachineDeployment{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "MachineDeployment",
+			APIVersion: clusterv1.GroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name,
+			Labels:    map[string]string{clusterv1.ClusterNameLabel: "cluster1"},
+		},
+		Spec: clusterv1.MachineDeploymentSpec{
+			ClusterName: "cluster1",
+			Replicas:    pointer.Int32(2),
+			Template: clusterv1.MachineTemplateSpec{
+				Spec: clusterv1.MachineSpec{
+					ClusterName: "cluster1",
+					Version:     pointer.String(version),
+				},
+			},
+		},
+		Status: clusterv1.MachineDeploymentStatus{
+			ObservedGeneration: 10,
+			Replicas:           2,
+			UpdatedReplicas:    2,
+			AvailableReplicas:  2,
+		},
+	}
+}
@@ -67,6 +67,11 @@ func patchKubeadmControlPlane(proxy cluster.Proxy, name, namespace string, patch
 		return errors.Wrapf(err, "failed to get KubeadmControlPlane %s/%s", kcpObj.GetNamespace(), kcpObj.GetName())
 	}
 
+	// Record the ID of the operation before changing the KubeadmControlPlane, so the activity of the controllers can be correlated with it.
+	if err := cFrom.Patch(ctx, kcpObj, operationIDPatch()); err != nil {
+		return errors.Wrapf(err, "failed to set operation ID on KubeadmControlPlane %s/%s", kcpObj.GetNamespace(), kcpObj.GetName())
+	}
+
 	if err := cFrom.Patch(ctx, kcpObj, patch); err != nil {
 		return errors.Wrapf(err, "failed while patching KubeadmControlPlane %s/%s", kcpObj.GetNamespace(), kcpObj.GetName())
 	}
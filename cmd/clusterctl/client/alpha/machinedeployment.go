@@ -73,6 +73,11 @@ func patchMachineDeployment(proxy cluster.Proxy, name, namespace string, patch c
 		return errors.Wrapf(err, "failed to get MachineDeployment %s/%s", mdObj.GetNamespace(), mdObj.GetName())
 	}
 
+	// Record the ID of the operation before changing the MachineDeployment, so the activity of the controllers can be correlated with it.
+	if err := cFrom.Patch(ctx, mdObj, operationIDPatch()); err != nil {
+		return errors.Wrapf(err, "failed to set operation ID on MachineDeployment %s/%s", mdObj.GetNamespace(), mdObj.GetName())
+	}
+
 	if err := cFrom.Patch(ctx, mdObj, patch); err != nil {
 		return errors.Wrapf(err, "failed while patching MachineDeployment %s/%s", mdObj.GetNamespace(), mdObj.GetName())
 	}
@@ -17,8 +17,13 @@ limitations under the License.
 package alpha
 
 import (
+	"fmt"
+
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
 )
 
@@ -53,3 +58,8 @@ type rollout struct{}
 func newRolloutClient() Rollout {
 	return &rollout{}
 }
+
+// operationIDPatch returns a patch recording the ID of the current clusterctl invocation on the object being rolled out.
+func operationIDPatch() client.Patch {
+	return client.RawPatch(types.MergePatchType, []byte(fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`, clusterv1.OperationIDAnnotation, cluster.OperationID())))
+}
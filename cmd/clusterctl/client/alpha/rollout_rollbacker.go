@@ -76,5 +76,6 @@ func rollbackMachineDeployment(proxy cluster.Proxy, md *clusterv1.MachineDeploym
 	delete(revMSTemplate.Labels, clusterv1.MachineDeploymentUniqueLabel)
 
 	md.Spec.Template = revMSTemplate
+	cluster.SetOperationIDAnnotation(md)
 	return patchHelper.Patch(ctx, md)
 }
@@ -0,0 +1,495 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alpha
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+	"sigs.k8s.io/cluster-api/controllers/external"
+	"sigs.k8s.io/cluster-api/internal/contract"
+	"sigs.k8s.io/cluster-api/internal/topology/adoption"
+)
+
+// TopologyAdopt defines the behavior of the adoption of a Cluster without a managed topology into a ClusterClass.
+type TopologyAdopt interface {
+	// Adopt attaches an existing Cluster without a managed topology to a ClusterClass, computing the topology
+	// from the current state of its control plane and MachineDeployments, and returns the adopted Cluster.
+	Adopt(cluster.Proxy, TopologyAdoptInput) (*clusterv1.Cluster, error)
+}
+
+// TopologyAdoptInput defines the input of a topology adopt operation.
+type TopologyAdoptInput struct {
+	// Name of the Cluster to adopt.
+	Name string
+
+	// Namespace of the Cluster to adopt.
+	Namespace string
+
+	// ClusterClass is the name of the ClusterClass to attach the Cluster to; it must exist in the namespace of the Cluster.
+	ClusterClass string
+
+	// MachineDeploymentClasses maps the names of the MachineDeployments of the Cluster to the MachineDeployment
+	// classes of the ClusterClass. It can be omitted for MachineDeployments when the ClusterClass has only one
+	// MachineDeployment class.
+	MachineDeploymentClasses map[string]string
+
+	// DryRun, if true, only computes the adopted Cluster without changing it.
+	DryRun bool
+}
+
+var _ TopologyAdopt = &topologyAdopt{}
+
+type topologyAdopt struct{}
+
+func newTopologyAdoptClient() TopologyAdopt {
+	return &topologyAdopt{}
+}
+
+// Adopt attaches an existing Cluster without a managed topology to a ClusterClass.
+// The Cluster topology is computed from the current state of the Cluster: the version and the replicas of the
+// control plane, the replicas of the MachineDeployments, and the values of the variables of the ClusterClass
+// that are set by its inline patches in the existing objects. The Cluster is then annotated so that the
+// topology controller adopts the existing objects instead of creating new ones.
+// NOTE: The topology controller rolls out the existing objects if they differ from the ones generated by the ClusterClass.
+func (a *topologyAdopt) Adopt(proxy cluster.Proxy, input TopologyAdoptInput) (*clusterv1.Cluster, error) {
+	c, err := proxy.NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	cl, err := computeAdoptedCluster(c, input)
+	if err != nil {
+		return nil, err
+	}
+	if input.DryRun {
+		return cl, nil
+	}
+
+	if err := c.Update(ctx, cl); err != nil {
+		return nil, errors.Wrapf(err, "failed to attach Cluster %s to ClusterClass %s", klog.KObj(cl), input.ClusterClass)
+	}
+	return cl, nil
+}
+
+// adoptedObjects are the existing objects of a Cluster the values of the variables are computed from.
+type adoptedObjects struct {
+	infrastructureCluster *unstructured.Unstructured
+	controlPlane          *unstructured.Unstructured
+	machineDeployments    []adoptedMachineDeployment
+}
+
+// adoptedMachineDeployment is an existing MachineDeployment of a Cluster with its bootstrap and infrastructure templates.
+type adoptedMachineDeployment struct {
+	name      string
+	class     string
+	templates []*unstructured.Unstructured
+}
+
+// computeAdoptedCluster returns a copy of a Cluster without a managed topology, with the topology computed from
+// its current state and the annotation required for the topology controller to adopt its existing objects.
+func computeAdoptedCluster(c client.Client, input TopologyAdoptInput) (*clusterv1.Cluster, error) {
+	cl := &clusterv1.Cluster{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: input.Namespace, Name: input.Name}, cl); err != nil {
+		return nil, errors.Wrapf(err, "failed to get Cluster %s/%s", input.Namespace, input.Name)
+	}
+	if cl.Spec.Topology != nil {
+		return nil, errors.Errorf("Cluster %s already has a managed topology", klog.KObj(cl))
+	}
+	if cl.Spec.ControlPlaneRef == nil {
+		return nil, errors.Errorf("Cluster %s has no control plane; only Clusters with a control plane can be adopted", klog.KObj(cl))
+	}
+
+	clusterClass := &clusterv1.ClusterClass{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: cl.Namespace, Name: input.ClusterClass}, clusterClass); err != nil {
+		return nil, errors.Wrapf(err, "failed to get ClusterClass %s/%s", cl.Namespace, input.ClusterClass)
+	}
+
+	objs := adoptedObjects{}
+	if cl.Spec.InfrastructureRef != nil {
+		infrastructureCluster, err := external.Get(ctx, c, cl.Spec.InfrastructureRef, cl.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		objs.infrastructureCluster = infrastructureCluster
+	}
+	controlPlane, err := external.Get(ctx, c, cl.Spec.ControlPlaneRef, cl.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	objs.controlPlane = controlPlane
+
+	controlPlaneVersion, err := contract.ControlPlane().Version().Get(controlPlane)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the version of %s %s", controlPlane.GetKind(), klog.KObj(controlPlane))
+	}
+	topology := &clusterv1.Topology{
+		Class:   clusterClass.Name,
+		Version: *controlPlaneVersion,
+	}
+	if replicas, err := contract.ControlPlane().Replicas().Get(controlPlane); err == nil {
+		topology.ControlPlane.Replicas = pointer.Int32(int32(*replicas))
+	}
+
+	machineDeployments := &clusterv1.MachineDeploymentList{}
+	if err := c.List(ctx, machineDeployments, client.InNamespace(cl.Namespace), client.MatchingLabels{clusterv1.ClusterNameLabel: cl.Name}); err != nil {
+		return nil, errors.Wrapf(err, "failed to list MachineDeployments of Cluster %s", klog.KObj(cl))
+	}
+	sort.Slice(machineDeployments.Items, func(i, j int) bool {
+		return machineDeployments.Items[i].Name < machineDeployments.Items[j].Name
+	})
+	mdNames := map[string]bool{}
+	for _, md := range machineDeployments.Items {
+		mdNames[md.Name] = true
+
+		mdClass, err := machineDeploymentClassFor(md.Name, input.MachineDeploymentClasses, clusterClass)
+		if err != nil {
+			return nil, err
+		}
+
+		adoptedMD := adoptedMachineDeployment{name: md.Name, class: mdClass}
+		for _, ref := range []*corev1.ObjectReference{md.Spec.Template.Spec.Bootstrap.ConfigRef, &md.Spec.Template.Spec.InfrastructureRef} {
+			if ref == nil || ref.Name == "" {
+				continue
+			}
+			template, err := external.Get(ctx, c, ref, md.Namespace)
+			if err != nil {
+				return nil, err
+			}
+			adoptedMD.templates = append(adoptedMD.templates, template)
+		}
+		objs.machineDeployments = append(objs.machineDeployments, adoptedMD)
+
+		if topology.Workers == nil {
+			topology.Workers = &clusterv1.WorkersTopology{}
+		}
+		topology.Workers.MachineDeployments = append(topology.Workers.MachineDeployments, clusterv1.MachineDeploymentTopology{
+			Name:     md.Name,
+			Class:    mdClass,
+			Replicas: md.Spec.Replicas,
+		})
+	}
+	for name := range input.MachineDeploymentClasses {
+		if !mdNames[name] {
+			return nil, errors.Errorf("MachineDeployment %s does not exist in Cluster %s", name, klog.KObj(cl))
+		}
+	}
+
+	variables, overrides, err := computeAdoptedVariables(clusterClass, objs)
+	if err != nil {
+		return nil, err
+	}
+	topology.Variables = variables
+	if topology.Workers != nil {
+		for i := range topology.Workers.MachineDeployments {
+			mdTopology := &topology.Workers.MachineDeployments[i]
+			if mdOverrides := overrides[mdTopology.Name]; len(mdOverrides) > 0 {
+				mdTopology.Variables = &clusterv1.MachineDeploymentVariables{Overrides: mdOverrides}
+			}
+		}
+	}
+
+	cl.Spec.Topology = topology
+
+	// Templates shared with other objects can't be adopted, because the topology controller deletes
+	// the templates it owns when rotating them.
+	sharedTemplates, err := adoption.GetSharedTemplates(ctx, c, cl)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to check the templates of Cluster %s", klog.KObj(cl))
+	}
+	if len(sharedTemplates) > 0 {
+		messages := []string{}
+		for _, sharedTemplate := range sharedTemplates {
+			messages = append(messages, sharedTemplate.String())
+		}
+		return nil, errors.Errorf("Cluster %s cannot be adopted, because some of its templates are shared with other objects; "+
+			"use dedicated templates instead: %s", klog.KObj(cl), strings.Join(messages, ", "))
+	}
+
+	if cl.Annotations == nil {
+		cl.Annotations = map[string]string{}
+	}
+	cl.Annotations[clusterv1.ClusterTopologyAdoptAnnotation] = ""
+	return cl, nil
+}
+
+// machineDeploymentClassFor returns the MachineDeployment class of the ClusterClass a MachineDeployment is adopted as.
+func machineDeploymentClassFor(mdName string, mdClasses map[string]string, clusterClass *clusterv1.ClusterClass) (string, error) {
+	mdClass, ok := mdClasses[mdName]
+	if !ok {
+		if len(clusterClass.Spec.Workers.MachineDeployments) != 1 {
+			return "", errors.Errorf("the MachineDeployment class for MachineDeployment %s must be specified: ClusterClass %s has %d MachineDeployment classes",
+				mdName, clusterClass.Name, len(clusterClass.Spec.Workers.MachineDeployments))
+		}
+		return clusterClass.Spec.Workers.MachineDeployments[0].Class, nil
+	}
+
+	for _, mdc := range clusterClass.Spec.Workers.MachineDeployments {
+		if mdc.Class == mdClass {
+			return mdClass, nil
+		}
+	}
+	return "", errors.Errorf("MachineDeployment class %s of MachineDeployment %s does not exist in ClusterClass %s", mdClass, mdName, clusterClass.Name)
+}
+
+// computeAdoptedVariables computes the values of the variables of a ClusterClass from the existing objects of a Cluster,
+// by reading the values set by the inline patches of the ClusterClass which use a variable as a value.
+// It returns the Cluster variables, and the MachineDeployment variable overrides by MachineDeployment name, for
+// the variables defined by a MachineDeployment class or with different values in different MachineDeployments.
+// NOTE: Patches using templates, external patches and variables without a value in the existing objects are ignored;
+// the variables which are not computed will get their default value, if any.
+func computeAdoptedVariables(clusterClass *clusterv1.ClusterClass, objs adoptedObjects) ([]clusterv1.ClusterVariable, map[string][]clusterv1.ClusterVariable, error) {
+	clusterVariableNames := map[string]bool{}
+	for _, v := range clusterClass.Spec.Variables {
+		clusterVariableNames[v.Name] = true
+	}
+	mdClassVariableNames := map[string]map[string]bool{}
+	for _, mdc := range clusterClass.Spec.Workers.MachineDeployments {
+		mdClassVariableNames[mdc.Class] = map[string]bool{}
+		for _, v := range mdc.Variables {
+			mdClassVariableNames[mdc.Class][v.Name] = true
+		}
+	}
+
+	clusterValues := map[string]interface{}{}
+	mdValues := map[string]map[string]interface{}{}
+
+	// Compute the Cluster variables from the InfrastructureCluster and the ControlPlane first, so the values in
+	// the MachineDeployments which are different are set as overrides.
+	for _, def := range adoptablePatchDefinitions(clusterClass) {
+		var obj *unstructured.Unstructured
+		switch {
+		case def.Selector.MatchResources.InfrastructureCluster && objs.infrastructureCluster != nil && selectorMatches(def.Selector, objs.infrastructureCluster, true):
+			obj = objs.infrastructureCluster
+		case def.Selector.MatchResources.ControlPlane && objs.controlPlane != nil && selectorMatches(def.Selector, objs.controlPlane, true):
+			obj = objs.controlPlane
+		default:
+			continue
+		}
+		for _, p := range def.JSONPatches {
+			name := adoptableVariable(p)
+			if name == "" || !clusterVariableNames[name] {
+				continue
+			}
+			if _, ok := clusterValues[name]; ok {
+				continue
+			}
+			path, ok := objectPathFromTemplatePath(p.Path)
+			if !ok {
+				continue
+			}
+			if value, ok := valueAtPath(obj.Object, path); ok {
+				clusterValues[name] = value
+			}
+		}
+	}
+
+	for _, def := range adoptablePatchDefinitions(clusterClass) {
+		if def.Selector.MatchResources.MachineDeploymentClass == nil {
+			continue
+		}
+		for _, md := range objs.machineDeployments {
+			if !matchesMachineDeploymentClass(def.Selector.MatchResources.MachineDeploymentClass.Names, md.class) {
+				continue
+			}
+			for _, template := range md.templates {
+				if !selectorMatches(def.Selector, template, false) {
+					continue
+				}
+				for _, p := range def.JSONPatches {
+					name := adoptableVariable(p)
+					if name == "" {
+						continue
+					}
+					value, ok := valueAtPath(template.Object, p.Path)
+					if !ok {
+						continue
+					}
+
+					switch {
+					case mdClassVariableNames[md.class][name]:
+						// Variables defined by the MachineDeployment class can only be set as overrides.
+					case clusterVariableNames[name]:
+						clusterValue, ok := clusterValues[name]
+						if !ok {
+							clusterValues[name] = value
+							continue
+						}
+						if reflect.DeepEqual(clusterValue, value) {
+							continue
+						}
+					default:
+						continue
+					}
+					if mdValues[md.name] == nil {
+						mdValues[md.name] = map[string]interface{}{}
+					}
+					if _, ok := mdValues[md.name][name]; !ok {
+						mdValues[md.name][name] = value
+					}
+				}
+			}
+		}
+	}
+
+	variables, err := toClusterVariables(clusterValues)
+	if err != nil {
+		return nil, nil, err
+	}
+	overrides := map[string][]clusterv1.ClusterVariable{}
+	for mdName, values := range mdValues {
+		mdVariables, err := toClusterVariables(values)
+		if err != nil {
+			return nil, nil, err
+		}
+		overrides[mdName] = mdVariables
+	}
+	return variables, overrides, nil
+}
+
+// adoptablePatchDefinitions returns the definitions of the inline patches of a ClusterClass.
+func adoptablePatchDefinitions(clusterClass *clusterv1.ClusterClass) []clusterv1.PatchDefinition {
+	defs := []clusterv1.PatchDefinition{}
+	for _, patch := range clusterClass.Spec.Patches {
+		defs = append(defs, patch.Definitions...)
+	}
+	return defs
+}
+
+// adoptableVariable returns the name of the variable used as a value by a JSON patch, if its value can be read
+// from the patched object, or an empty string otherwise.
+// NOTE: Builtin variables and fields of object variables are not adopted.
+func adoptableVariable(p clusterv1.JSONPatch) string {
+	if p.ValueFrom == nil || p.ValueFrom.Variable == nil {
+		return ""
+	}
+	if p.Op != "add" && p.Op != "replace" {
+		return ""
+	}
+	name := *p.ValueFrom.Variable
+	if strings.HasPrefix(name, "builtin.") || strings.Contains(name, ".") {
+		return ""
+	}
+	return name
+}
+
+// selectorMatches returns true if a patch selector matches an object; if fromTemplate is true the object is
+// generated from the template selected by the patch, otherwise the object is the template itself.
+func selectorMatches(selector clusterv1.PatchSelector, obj *unstructured.Unstructured, fromTemplate bool) bool {
+	selectorGV, err := schema.ParseGroupVersion(selector.APIVersion)
+	if err != nil {
+		return false
+	}
+	kind := selector.Kind
+	if fromTemplate {
+		kind = strings.TrimSuffix(kind, clusterv1.TemplateSuffix)
+	}
+	return obj.GroupVersionKind().Group == selectorGV.Group && obj.GetKind() == kind
+}
+
+// matchesMachineDeploymentClass returns true if a MachineDeployment class matches one of the names of a patch
+// selector, supporting the same wildcards of the patches.
+func matchesMachineDeploymentClass(names []string, mdClass string) bool {
+	for _, name := range names {
+		switch {
+		case name == "*" || name == mdClass:
+			return true
+		case strings.HasPrefix(name, "*") && strings.HasSuffix(mdClass, strings.TrimPrefix(name, "*")):
+			return true
+		case strings.HasSuffix(name, "*") && strings.HasPrefix(mdClass, strings.TrimSuffix(name, "*")):
+			return true
+		}
+	}
+	return false
+}
+
+// objectPathFromTemplatePath returns the path in an object of a path in the template the object is generated from,
+// e.g. /spec/template/spec/foo is /spec/foo.
+func objectPathFromTemplatePath(path string) (string, bool) {
+	if !strings.HasPrefix(path, "/spec/template/") {
+		return "", false
+	}
+	return strings.TrimPrefix(path, "/spec/template"), true
+}
+
+// valueAtPath returns the value at a JSON pointer path in an object, if it exists.
+func valueAtPath(obj map[string]interface{}, path string) (interface{}, bool) {
+	if !strings.HasPrefix(path, "/") {
+		return nil, false
+	}
+
+	var current interface{} = obj
+	for _, token := range strings.Split(strings.TrimPrefix(path, "/"), "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch c := current.(type) {
+		case map[string]interface{}:
+			value, ok := c[token]
+			if !ok {
+				return nil, false
+			}
+			current = value
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(c) {
+				return nil, false
+			}
+			current = c[i]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// toClusterVariables converts variable values to Cluster variables, sorted by name.
+func toClusterVariables(values map[string]interface{}) ([]clusterv1.ClusterVariable, error) {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	variables := make([]clusterv1.ClusterVariable, 0, len(names))
+	for _, name := range names {
+		raw, err := json.Marshal(values[name])
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to marshal the value of variable %s", name)
+		}
+		variables = append(variables, clusterv1.ClusterVariable{
+			Name:  name,
+			Value: apiextensionsv1.JSON{Raw: raw},
+		})
+	}
+	if len(variables) == 0 {
+		return nil, nil
+	}
+	return variables, nil
+}
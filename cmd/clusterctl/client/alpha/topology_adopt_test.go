@@ -0,0 +1,417 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alpha
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+	fakebootstrap "sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test/providers/bootstrap"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+)
+
+func Test_TopologyAdopt_Adopt(t *testing.T) {
+	newCluster := func(topology *clusterv1.Topology) *clusterv1.Cluster {
+		return &clusterv1.Cluster{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Cluster",
+				APIVersion: clusterv1.GroupVersion.String(),
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "cluster1",
+			},
+			Spec: clusterv1.ClusterSpec{
+				ControlPlaneRef: &corev1.ObjectReference{
+					Kind:       "KubeadmControlPlane",
+					APIVersion: controlplanev1.GroupVersion.String(),
+					Namespace:  "default",
+					Name:       "cp1",
+				},
+				Topology: topology,
+			},
+		}
+	}
+	newControlPlane := func() *controlplanev1.KubeadmControlPlane {
+		cp := newKubeadmControlPlane("cp1", "v1.28.3", "v1.28.3")
+		cp.Spec.KubeadmConfigSpec.ClusterConfiguration = &bootstrapv1.ClusterConfiguration{
+			ImageRepository: "registry.example.com",
+		}
+		return cp
+	}
+	newClusterClass := func(mdClasses ...string) *clusterv1.ClusterClass {
+		cc := &clusterv1.ClusterClass{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "ClusterClass",
+				APIVersion: clusterv1.GroupVersion.String(),
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "class1",
+			},
+			Spec: clusterv1.ClusterClassSpec{
+				Variables: []clusterv1.ClusterClassVariable{
+					{Name: "imageRepository", Schema: clusterv1.VariableSchema{OpenAPIV3Schema: clusterv1.JSONSchemaProps{Type: "string"}}},
+				},
+				Patches: []clusterv1.ClusterClassPatch{
+					{
+						Name: "imageRepository",
+						Definitions: []clusterv1.PatchDefinition{
+							{
+								Selector: clusterv1.PatchSelector{
+									APIVersion:     controlplanev1.GroupVersion.String(),
+									Kind:           "KubeadmControlPlaneTemplate",
+									MatchResources: clusterv1.PatchSelectorMatch{ControlPlane: true},
+								},
+								JSONPatches: []clusterv1.JSONPatch{
+									{
+										Op:        "add",
+										Path:      "/spec/template/spec/kubeadmConfigSpec/clusterConfiguration/imageRepository",
+										ValueFrom: &clusterv1.JSONPatchValue{Variable: pointer.String("imageRepository")},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		for _, mdClass := range mdClasses {
+			cc.Spec.Workers.MachineDeployments = append(cc.Spec.Workers.MachineDeployments, clusterv1.MachineDeploymentClass{Class: mdClass})
+		}
+		return cc
+	}
+
+	sharedBootstrapTemplate := &fakebootstrap.GenericBootstrapConfigTemplate{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "GenericBootstrapConfigTemplate",
+			APIVersion: fakebootstrap.GroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "shared",
+		},
+	}
+	withBootstrapTemplate := func(md *clusterv1.MachineDeployment, template *fakebootstrap.GenericBootstrapConfigTemplate) *clusterv1.MachineDeployment {
+		md.Spec.Template.Spec.Bootstrap.ConfigRef = &corev1.ObjectReference{
+			Kind:       template.Kind,
+			APIVersion: template.APIVersion,
+			Namespace:  template.Namespace,
+			Name:       template.Name,
+		}
+		return md
+	}
+	otherClusterMachineDeployment := func(name string) *clusterv1.MachineDeployment {
+		md := newMachineDeployment(name, "v1.28.3")
+		md.Labels[clusterv1.ClusterNameLabel] = "cluster2"
+		md.Spec.ClusterName = "cluster2"
+		return md
+	}
+
+	tests := []struct {
+		name                     string
+		objs                     []client.Object
+		machineDeploymentClasses map[string]string
+		wantErr                  bool
+		wantTopology             *clusterv1.Topology
+	}{
+		{
+			name: "cluster is adopted computing the topology from the existing objects",
+			objs: []client.Object{
+				newCluster(nil),
+				newClusterClass("default-worker"),
+				newControlPlane(),
+				newMachineDeployment("md1", "v1.28.3"),
+				newMachineDeployment("md2", "v1.28.3"),
+			},
+			wantTopology: &clusterv1.Topology{
+				Class:   "class1",
+				Version: "v1.28.3",
+				ControlPlane: clusterv1.ControlPlaneTopology{
+					Replicas: pointer.Int32(3),
+				},
+				Workers: &clusterv1.WorkersTopology{
+					MachineDeployments: []clusterv1.MachineDeploymentTopology{
+						{Name: "md1", Class: "default-worker", Replicas: pointer.Int32(2)},
+						{Name: "md2", Class: "default-worker", Replicas: pointer.Int32(2)},
+					},
+				},
+				Variables: []clusterv1.ClusterVariable{
+					{Name: "imageRepository", Value: apiextensionsv1.JSON{Raw: []byte(`"registry.example.com"`)}},
+				},
+			},
+		},
+		{
+			name: "MachineDeployments are adopted using the given MachineDeployment classes",
+			objs: []client.Object{
+				newCluster(nil),
+				newClusterClass("default-worker", "large-worker"),
+				newControlPlane(),
+				newMachineDeployment("md1", "v1.28.3"),
+				newMachineDeployment("md2", "v1.28.3"),
+			},
+			machineDeploymentClasses: map[string]string{"md1": "default-worker", "md2": "large-worker"},
+			wantTopology: &clusterv1.Topology{
+				Class:   "class1",
+				Version: "v1.28.3",
+				ControlPlane: clusterv1.ControlPlaneTopology{
+					Replicas: pointer.Int32(3),
+				},
+				Workers: &clusterv1.WorkersTopology{
+					MachineDeployments: []clusterv1.MachineDeploymentTopology{
+						{Name: "md1", Class: "default-worker", Replicas: pointer.Int32(2)},
+						{Name: "md2", Class: "large-worker", Replicas: pointer.Int32(2)},
+					},
+				},
+				Variables: []clusterv1.ClusterVariable{
+					{Name: "imageRepository", Value: apiextensionsv1.JSON{Raw: []byte(`"registry.example.com"`)}},
+				},
+			},
+		},
+		{
+			name: "fails if the MachineDeployment class is not given and the ClusterClass has more than one",
+			objs: []client.Object{
+				newCluster(nil),
+				newClusterClass("default-worker", "large-worker"),
+				newControlPlane(),
+				newMachineDeployment("md1", "v1.28.3"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "fails if the MachineDeployment class does not exist in the ClusterClass",
+			objs: []client.Object{
+				newCluster(nil),
+				newClusterClass("default-worker"),
+				newControlPlane(),
+				newMachineDeployment("md1", "v1.28.3"),
+			},
+			machineDeploymentClasses: map[string]string{"md1": "does-not-exist"},
+			wantErr:                  true,
+		},
+		{
+			name: "fails if a MachineDeployment with a class does not exist",
+			objs: []client.Object{
+				newCluster(nil),
+				newClusterClass("default-worker"),
+				newControlPlane(),
+				newMachineDeployment("md1", "v1.28.3"),
+			},
+			machineDeploymentClasses: map[string]string{"md1": "default-worker", "does-not-exist": "default-worker"},
+			wantErr:                  true,
+		},
+		{
+			name: "fails if the templates of a MachineDeployment are shared with other objects",
+			objs: []client.Object{
+				newCluster(nil),
+				newClusterClass("default-worker"),
+				newControlPlane(),
+				sharedBootstrapTemplate,
+				withBootstrapTemplate(newMachineDeployment("md1", "v1.28.3"), sharedBootstrapTemplate),
+				withBootstrapTemplate(otherClusterMachineDeployment("md2"), sharedBootstrapTemplate),
+			},
+			wantErr: true,
+		},
+		{
+			name: "fails if the cluster already has a managed topology",
+			objs: []client.Object{
+				newCluster(&clusterv1.Topology{Class: "class1", Version: "v1.28.3"}),
+				newClusterClass("default-worker"),
+				newControlPlane(),
+			},
+			wantErr: true,
+		},
+		{
+			name: "fails if the ClusterClass does not exist",
+			objs: []client.Object{
+				newCluster(nil),
+				newControlPlane(),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			proxy := test.NewFakeProxy().WithObjs(tt.objs...)
+			a := newTopologyAdoptClient()
+			input := TopologyAdoptInput{
+				Name:                     "cluster1",
+				Namespace:                "default",
+				ClusterClass:             "class1",
+				MachineDeploymentClasses: tt.machineDeploymentClasses,
+			}
+
+			// A dry run computes the adopted Cluster without changing it.
+			got, err := a.Adopt(proxy, TopologyAdoptInput{
+				Name:                     input.Name,
+				Namespace:                input.Namespace,
+				ClusterClass:             input.ClusterClass,
+				MachineDeploymentClasses: input.MachineDeploymentClasses,
+				DryRun:                   true,
+			})
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got.Spec.Topology).To(BeComparableTo(tt.wantTopology))
+			g.Expect(got.Annotations).To(HaveKey(clusterv1.ClusterTopologyAdoptAnnotation))
+
+			c, err := proxy.NewClient()
+			g.Expect(err).ToNot(HaveOccurred())
+			cluster := &clusterv1.Cluster{}
+			g.Expect(c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "cluster1"}, cluster)).To(Succeed())
+			g.Expect(cluster.Spec.Topology).To(BeNil())
+
+			// Adopt attaches the Cluster to the ClusterClass.
+			_, err = a.Adopt(proxy, input)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "cluster1"}, cluster)).To(Succeed())
+			g.Expect(cluster.Spec.Topology).To(BeComparableTo(tt.wantTopology))
+			g.Expect(cluster.Annotations).To(HaveKey(clusterv1.ClusterTopologyAdoptAnnotation))
+		})
+	}
+}
+
+func Test_computeAdoptedVariables(t *testing.T) {
+	g := NewWithT(t)
+
+	newTemplate := func(name, instanceType string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"instanceType": instanceType,
+					},
+				},
+			},
+		}}
+		u.SetAPIVersion("infrastructure.cluster.x-k8s.io/v1beta1")
+		u.SetKind("GenericInfrastructureMachineTemplate")
+		u.SetName(name)
+		return u
+	}
+	instanceTypePatch := func(variable string, mdClasses ...string) clusterv1.ClusterClassPatch {
+		return clusterv1.ClusterClassPatch{
+			Name: variable,
+			Definitions: []clusterv1.PatchDefinition{
+				{
+					Selector: clusterv1.PatchSelector{
+						APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+						Kind:       "GenericInfrastructureMachineTemplate",
+						MatchResources: clusterv1.PatchSelectorMatch{
+							MachineDeploymentClass: &clusterv1.PatchSelectorMatchMachineDeploymentClass{Names: mdClasses},
+						},
+					},
+					JSONPatches: []clusterv1.JSONPatch{
+						{
+							Op:        "replace",
+							Path:      "/spec/template/spec/instanceType",
+							ValueFrom: &clusterv1.JSONPatchValue{Variable: pointer.String(variable)},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	clusterClass := &clusterv1.ClusterClass{
+		Spec: clusterv1.ClusterClassSpec{
+			Variables: []clusterv1.ClusterClassVariable{
+				{Name: "instanceType"},
+			},
+			Workers: clusterv1.WorkersClass{
+				MachineDeployments: []clusterv1.MachineDeploymentClass{
+					{Class: "default-worker"},
+					{Class: "gpu-worker", Variables: []clusterv1.ClusterClassVariable{{Name: "gpuInstanceType"}}},
+				},
+			},
+			Patches: []clusterv1.ClusterClassPatch{
+				instanceTypePatch("instanceType", "default-*"),
+				instanceTypePatch("gpuInstanceType", "gpu-worker"),
+			},
+		},
+	}
+
+	variables, overrides, err := computeAdoptedVariables(clusterClass, adoptedObjects{
+		machineDeployments: []adoptedMachineDeployment{
+			{name: "md1", class: "default-worker", templates: []*unstructured.Unstructured{newTemplate("md1", "small")}},
+			{name: "md2", class: "default-worker", templates: []*unstructured.Unstructured{newTemplate("md2", "large")}},
+			{name: "md3", class: "gpu-worker", templates: []*unstructured.Unstructured{newTemplate("md3", "gpu")}},
+		},
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// The value of the first MachineDeployment is used for the Cluster variable, different values are set as overrides.
+	g.Expect(variables).To(BeComparableTo([]clusterv1.ClusterVariable{
+		{Name: "instanceType", Value: apiextensionsv1.JSON{Raw: []byte(`"small"`)}},
+	}))
+	g.Expect(overrides).To(BeComparableTo(map[string][]clusterv1.ClusterVariable{
+		"md2": {{Name: "instanceType", Value: apiextensionsv1.JSON{Raw: []byte(`"large"`)}}},
+		"md3": {{Name: "gpuInstanceType", Value: apiextensionsv1.JSON{Raw: []byte(`"gpu"`)}}},
+	}))
+}
+
+func Test_valueAtPath(t *testing.T) {
+	obj := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"files": []interface{}{
+				map[string]interface{}{"path": "/etc/foo"},
+			},
+			"a/b": "escaped",
+		},
+	}
+
+	tests := []struct {
+		name      string
+		path      string
+		wantValue interface{}
+		wantOK    bool
+	}{
+		{name: "map field", path: "/spec/files/0/path", wantValue: "/etc/foo", wantOK: true},
+		{name: "escaped field", path: "/spec/a~1b", wantValue: "escaped", wantOK: true},
+		{name: "missing field", path: "/spec/missing", wantOK: false},
+		{name: "index out of range", path: "/spec/files/1", wantOK: false},
+		{name: "append to array", path: "/spec/files/-", wantOK: false},
+		{name: "invalid path", path: "spec", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			value, ok := valueAtPath(obj, tt.path)
+			g.Expect(ok).To(Equal(tt.wantOK))
+			if !tt.wantOK {
+				g.Expect(value).To(BeNil())
+				return
+			}
+			g.Expect(value).To(Equal(tt.wantValue))
+		})
+	}
+}
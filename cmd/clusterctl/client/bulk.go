@@ -0,0 +1,89 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/alpha"
+)
+
+// BulkPatchOptions carries the options supported by BulkPatch.
+type BulkPatchOptions struct {
+	// Kubeconfig defines the kubeconfig to use for accessing the management cluster. If empty,
+	// default rules for kubeconfig discovery will be used.
+	Kubeconfig Kubeconfig
+
+	// Name identifies the bulk operation; running an interrupted operation again with the same name resumes it.
+	Name string
+
+	// Namespace where the Clusters live. If empty, Clusters in all namespaces are patched.
+	Namespace string
+
+	// StateNamespace is the namespace of the ConfigMap recording the state of the operation. If unspecified,
+	// Namespace is used, or the namespace will be inferred from the current configuration.
+	StateNamespace string
+
+	// Selector is the label selector for the Clusters to patch.
+	Selector string
+
+	// Patch is the patch to apply to each Cluster, in JSON format.
+	Patch []byte
+
+	// PatchType is the type of the patch, either merge or JSON patch.
+	PatchType types.PatchType
+
+	// Concurrency is the maximum number of Clusters patched in parallel.
+	Concurrency int
+
+	// QPS is the maximum number of Clusters patched per second; if zero, patches are not rate limited.
+	QPS float64
+}
+
+// BulkPatchResult defines the result of the bulk patch operation.
+type BulkPatchResult = alpha.BulkPatchResult
+
+// BulkPatch applies a patch to all the Clusters matching a label selector.
+func (c *clusterctlClient) BulkPatch(options BulkPatchOptions) (*BulkPatchResult, error) {
+	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
+	if err != nil {
+		return nil, err
+	}
+
+	stateNamespace := options.StateNamespace
+	if stateNamespace == "" {
+		stateNamespace = options.Namespace
+	}
+	if stateNamespace == "" {
+		currentNamespace, err := clusterClient.Proxy().CurrentNamespace()
+		if err != nil {
+			return nil, err
+		}
+		stateNamespace = currentNamespace
+	}
+
+	return c.alphaClient.Bulk().Patch(clusterClient.Proxy(), alpha.BulkPatchInput{
+		Name:           options.Name,
+		Namespace:      options.Namespace,
+		StateNamespace: stateNamespace,
+		Selector:       options.Selector,
+		Patch:          options.Patch,
+		PatchType:      options.PatchType,
+		Concurrency:    options.Concurrency,
+		QPS:            options.QPS,
+	})
+}
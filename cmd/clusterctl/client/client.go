@@ -17,6 +17,7 @@ limitations under the License.
 package client
 
 import (
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/alpha"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
@@ -51,6 +52,20 @@ type Client interface {
 	// Delete deletes providers from a management cluster.
 	Delete(options DeleteOptions) error
 
+	// DeleteSelfManaged decommissions a self-hosted management cluster by moving all the Cluster API objects
+	// to a temporary management cluster and then deleting the self-hosted cluster from there.
+	DeleteSelfManaged(options DeleteSelfManagedOptions) error
+
+	// PlanDeleteCluster returns the plan to delete a workload Cluster, i.e. the objects deleted in each phase.
+	PlanDeleteCluster(options DeleteClusterOptions) (DeleteClusterPlan, error)
+
+	// DeleteCluster deletes a workload Cluster in order: first the workers, then the control plane,
+	// the infrastructure cluster and finally the Cluster itself.
+	DeleteCluster(options DeleteClusterOptions) error
+
+	// Wait waits for a condition of Cluster API resources, including provider resources, or for their deletion.
+	Wait(options WaitOptions) error
+
 	// Move moves all the Cluster API objects existing in a namespace (or from all the namespaces if empty) to a target management cluster.
 	Move(options MoveOptions) error
 
@@ -86,6 +101,12 @@ type AlphaClient interface {
 	RolloutUndo(options RolloutUndoOptions) error
 	// TopologyPlan dry runs the topology reconciler
 	TopologyPlan(options TopologyPlanOptions) (*TopologyPlanOutput, error)
+	// BulkPatch applies a patch to many Clusters selected by labels
+	BulkPatch(options BulkPatchOptions) (*BulkPatchResult, error)
+	// UpgradeCluster upgrades the Kubernetes version of a workload cluster, or plans the upgrade
+	UpgradeCluster(options UpgradeClusterOptions) (*ClusterUpgradePlan, error)
+	// TopologyAdopt attaches a Cluster without a managed topology to a ClusterClass
+	TopologyAdopt(options TopologyAdoptOptions) (*clusterv1.Cluster, error)
 }
 
 // YamlPrinter exposes methods that prints the processed template and
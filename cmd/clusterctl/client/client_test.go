@@ -28,6 +28,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
@@ -105,6 +106,22 @@ func (f fakeClient) Delete(options DeleteOptions) error {
 	return f.internalClient.Delete(options)
 }
 
+func (f fakeClient) DeleteSelfManaged(options DeleteSelfManagedOptions) error {
+	return f.internalClient.DeleteSelfManaged(options)
+}
+
+func (f fakeClient) PlanDeleteCluster(options DeleteClusterOptions) (DeleteClusterPlan, error) {
+	return f.internalClient.PlanDeleteCluster(options)
+}
+
+func (f fakeClient) DeleteCluster(options DeleteClusterOptions) error {
+	return f.internalClient.DeleteCluster(options)
+}
+
+func (f fakeClient) Wait(options WaitOptions) error {
+	return f.internalClient.Wait(options)
+}
+
 func (f fakeClient) Move(options MoveOptions) error {
 	return f.internalClient.Move(options)
 }
@@ -149,6 +166,18 @@ func (f fakeClient) TopologyPlan(options TopologyPlanOptions) (*cluster.Topology
 	return f.internalClient.TopologyPlan(options)
 }
 
+func (f fakeClient) BulkPatch(options BulkPatchOptions) (*BulkPatchResult, error) {
+	return f.internalClient.BulkPatch(options)
+}
+
+func (f fakeClient) UpgradeCluster(options UpgradeClusterOptions) (*ClusterUpgradePlan, error) {
+	return f.internalClient.UpgradeCluster(options)
+}
+
+func (f fakeClient) TopologyAdopt(options TopologyAdoptOptions) (*clusterv1.Cluster, error) {
+	return f.internalClient.TopologyAdopt(options)
+}
+
 // newFakeClient returns a clusterctl client that allows to execute tests on a set of fake config, fake repositories and fake clusters.
 // you can use WithCluster and WithRepository to prepare for the test case.
 func newFakeClient(configClient config.Client) *fakeClient {
@@ -96,6 +96,9 @@ func (p *providerComponents) createObj(obj unstructured.Unstructured) error {
 		return err
 	}
 
+	// record the ID of the clusterctl operation installing or upgrading the component
+	SetOperationIDAnnotation(&obj)
+
 	// check if the component already exists, and eventually update it
 	currentR := &unstructured.Unstructured{}
 	currentR.SetGroupVersionKind(obj.GroupVersionKind())
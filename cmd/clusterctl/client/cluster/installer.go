@@ -64,12 +64,20 @@ type ProviderInstaller interface {
 
 	// Images returns the list of images required for installing the providers ready in the install queue.
 	Images() []string
+
+	// HealthReport returns the report of the checks verifying the health of the providers installed by
+	// the last Install with VerifyProviders set.
+	HealthReport() []ProviderHealth
 }
 
 // InstallOptions defines the options used to configure installation.
 type InstallOptions struct {
 	WaitProviders       bool
 	WaitProviderTimeout time.Duration
+
+	// VerifyProviders instructs the installer to wait till the providers are healthy, i.e. CRDs are established,
+	// certificates are issued, managers are available and webhooks can be called. It implies WaitProviders.
+	VerifyProviders bool
 }
 
 // providerInstaller implements ProviderInstaller.
@@ -80,6 +88,7 @@ type providerInstaller struct {
 	providerComponents      ComponentsClient
 	providerInventory       InventoryClient
 	installQueue            []repository.Components
+	healthReport            []ProviderHealth
 }
 
 var _ ProviderInstaller = &providerInstaller{}
@@ -103,9 +112,18 @@ func (i *providerInstaller) Install(opts InstallOptions) ([]repository.Component
 		ret = append(ret, components)
 	}
 
+	if opts.VerifyProviders {
+		report, err := verifyProvidersHealth(opts, i.installQueue, i.proxy)
+		i.healthReport = report
+		return ret, err
+	}
 	return ret, waitForProvidersReady(opts, i.installQueue, i.proxy)
 }
 
+func (i *providerInstaller) HealthReport() []ProviderHealth {
+	return i.healthReport
+}
+
 func installComponentsAndUpdateInventory(components repository.Components, providerComponents ComponentsClient, providerInventory InventoryClient) error {
 	log := logf.Log
 	log.Info("Installing", "Provider", components.ManifestLabel(), "Version", components.Version(), "TargetNamespace", components.TargetNamespace())
@@ -0,0 +1,543 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/repository"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/util"
+	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+)
+
+const (
+	// CRDsEstablishedHealthCheck verifies all the CRDs of a provider are established.
+	CRDsEstablishedHealthCheck = "CRDsEstablished"
+
+	// CertificatesReadyHealthCheck verifies all the cert-manager Certificates of a provider are issued.
+	CertificatesReadyHealthCheck = "CertificatesReady"
+
+	// ManagerRBACHealthCheck verifies the service account of the provider manager can list and watch the CRDs of the provider.
+	ManagerRBACHealthCheck = "ManagerRBAC"
+
+	// ManagerAvailableHealthCheck verifies the provider manager Deployment is available.
+	ManagerAvailableHealthCheck = "ManagerAvailable"
+
+	// WebhooksAvailableHealthCheck verifies the CA bundle is injected in all the webhooks of a provider and
+	// that the webhook services have ready endpoints.
+	WebhooksAvailableHealthCheck = "WebhooksAvailable"
+
+	// WebhooksDryRunHealthCheck verifies the provider webhooks are called when creating an object of the provider in dry-run.
+	WebhooksDryRunHealthCheck = "WebhooksDryRun"
+)
+
+const (
+	certificateKind            = "Certificate"
+	certManagerGroup           = "cert-manager.io"
+	healthCheckPollInterval    = 2 * time.Second
+	healthCheckObjectPrefix    = "clusterctl-health-check-"
+	rbacAggregationGracePeriod = 30 * time.Second
+)
+
+// ProviderHealthCheck is the result of a check verifying an installed provider is working.
+type ProviderHealthCheck struct {
+	// Name of the check, e.g. CRDsEstablished.
+	Name string
+
+	// Healthy is true if the check succeeded.
+	Healthy bool
+
+	// Message describes the result of the check.
+	Message string
+}
+
+// ProviderHealth reports the result of the checks verifying an installed provider is working.
+type ProviderHealth struct {
+	// Provider is the manifest label of the provider, e.g. cluster-api.
+	Provider string
+
+	// Version of the provider.
+	Version string
+
+	// TargetNamespace where the provider is installed.
+	TargetNamespace string
+
+	// Checks are the checks run for the provider, in order; checks are not run after the first failing one.
+	Checks []ProviderHealthCheck
+}
+
+// Healthy returns true if all the checks of the provider succeeded.
+func (h ProviderHealth) Healthy() bool {
+	for _, c := range h.Checks {
+		if !c.Healthy {
+			return false
+		}
+	}
+	return true
+}
+
+// providerHealthCheckFunc checks the health of a provider; it returns false if the provider is not yet healthy,
+// and an error if the provider cannot become healthy without an intervention, e.g. fixing cert-manager or RBAC.
+type providerHealthCheckFunc func(ctx context.Context, c client.Client, components repository.Components) (bool, string, error)
+
+// verifyProvidersHealth waits till the installed providers are healthy and returns a report of the checks performed.
+// The checks of each provider, which are run in the same order the providers are installed, are interrupted
+// after WaitProviderTimeout, or as soon as a check fails in a way that requires an intervention.
+func verifyProvidersHealth(opts InstallOptions, installQueue []repository.Components, proxy Proxy) ([]ProviderHealth, error) {
+	log := logf.Log
+	log.Info("Verifying providers health...")
+
+	c, err := proxy.NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	checks := []struct {
+		name  string
+		check providerHealthCheckFunc
+	}{
+		{name: CRDsEstablishedHealthCheck, check: checkCRDsEstablished},
+		{name: CertificatesReadyHealthCheck, check: checkCertificatesReady},
+		{name: ManagerRBACHealthCheck, check: newManagerRBACCheck()},
+		{name: ManagerAvailableHealthCheck, check: checkManagerAvailable},
+		{name: WebhooksAvailableHealthCheck, check: checkWebhooksAvailable},
+		{name: WebhooksDryRunHealthCheck, check: checkWebhooksDryRun},
+	}
+
+	report := make([]ProviderHealth, 0, len(installQueue))
+	unhealthy := []string{}
+	for _, components := range installQueue {
+		health := ProviderHealth{
+			Provider:        components.ManifestLabel(),
+			Version:         components.Version(),
+			TargetNamespace: components.TargetNamespace(),
+		}
+
+		ctx, cancel := context.WithTimeout(context.TODO(), opts.WaitProviderTimeout)
+		for _, check := range checks {
+			result := runProviderHealthCheck(ctx, check.name, check.check, c, components)
+			health.Checks = append(health.Checks, result)
+			log.V(1).Info("Provider health check", "Provider", health.Provider, "Check", result.Name, "Healthy", result.Healthy, "Message", result.Message)
+			if !result.Healthy {
+				break
+			}
+		}
+		cancel()
+
+		if !health.Healthy() {
+			unhealthy = append(unhealthy, health.Provider)
+		}
+		report = append(report, health)
+	}
+
+	if len(unhealthy) > 0 {
+		return report, errors.Errorf("providers %s are not healthy", strings.Join(unhealthy, ", "))
+	}
+	return report, nil
+}
+
+// runProviderHealthCheck runs a check till it succeeds, it fails with an error or the context is done.
+func runProviderHealthCheck(ctx context.Context, name string, check providerHealthCheckFunc, c client.Client, components repository.Components) ProviderHealthCheck {
+	var message string
+	err := wait.PollUntilContextCancel(ctx, healthCheckPollInterval, true, func(ctx context.Context) (bool, error) {
+		healthy, msg, err := check(ctx, c, components)
+		message = msg
+		return healthy, err
+	})
+	if err != nil {
+		if wait.Interrupted(err) {
+			return ProviderHealthCheck{Name: name, Healthy: false, Message: fmt.Sprintf("timed out: %s", message)}
+		}
+		return ProviderHealthCheck{Name: name, Healthy: false, Message: err.Error()}
+	}
+	return ProviderHealthCheck{Name: name, Healthy: true, Message: message}
+}
+
+// checkCRDsEstablished checks all the CRDs of the provider are established.
+func checkCRDsEstablished(ctx context.Context, c client.Client, components repository.Components) (bool, string, error) {
+	crds, err := providerCRDs(components)
+	if err != nil {
+		return false, "", err
+	}
+
+	notEstablished := []string{}
+	for _, crd := range crds {
+		current := &apiextensionsv1.CustomResourceDefinition{}
+		if err := c.Get(ctx, client.ObjectKey{Name: crd.Name}, current); err != nil {
+			return false, fmt.Sprintf("failed to get CRD %s: %v", crd.Name, err), nil
+		}
+		if !isCRDEstablished(current) {
+			notEstablished = append(notEstablished, crd.Name)
+		}
+	}
+	if len(notEstablished) > 0 {
+		return false, fmt.Sprintf("CRDs %s are not established", strings.Join(notEstablished, ", ")), nil
+	}
+	return true, fmt.Sprintf("%d CRDs established", len(crds)), nil
+}
+
+func isCRDEstablished(crd *apiextensionsv1.CustomResourceDefinition) bool {
+	for _, condition := range crd.Status.Conditions {
+		if condition.Type == apiextensionsv1.Established {
+			return condition.Status == apiextensionsv1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// checkCertificatesReady checks all the cert-manager Certificates of the provider are issued; it fails fast
+// if cert-manager is not installed or if it failed issuing a certificate.
+func checkCertificatesReady(ctx context.Context, c client.Client, components repository.Components) (bool, string, error) {
+	certificates := 0
+	notReady := []string{}
+	for _, obj := range components.Objs() {
+		if obj.GroupVersionKind().Group != certManagerGroup || obj.GetKind() != certificateKind {
+			continue
+		}
+		certificates++
+
+		key := client.ObjectKeyFromObject(&obj)
+		certificate := &unstructured.Unstructured{}
+		certificate.SetGroupVersionKind(obj.GroupVersionKind())
+		if err := c.Get(ctx, key, certificate); err != nil {
+			if meta.IsNoMatchError(err) {
+				return false, "", errors.Wrap(err, "cert-manager is not installed")
+			}
+			return false, fmt.Sprintf("failed to get Certificate %s: %v", key, err), nil
+		}
+
+		ready, _, _ := certificateCondition(certificate, "Ready")
+		if ready == string(corev1.ConditionTrue) {
+			continue
+		}
+		if issuing, reason, message := certificateCondition(certificate, "Issuing"); issuing == string(corev1.ConditionFalse) && reason == "Failed" {
+			return false, "", errors.Errorf("cert-manager failed to issue Certificate %s: %s", key, message)
+		}
+		notReady = append(notReady, key.String())
+	}
+	if len(notReady) > 0 {
+		return false, fmt.Sprintf("Certificates %s are not ready", strings.Join(notReady, ", ")), nil
+	}
+	return true, fmt.Sprintf("%d Certificates ready", certificates), nil
+}
+
+// certificateCondition returns the status, reason and message of a condition of a cert-manager Certificate.
+func certificateCondition(certificate *unstructured.Unstructured, conditionType string) (string, string, string) {
+	conditions, _, _ := unstructured.NestedSlice(certificate.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || condition["type"] != conditionType {
+			continue
+		}
+		status, _ := condition["status"].(string)
+		reason, _ := condition["reason"].(string)
+		message, _ := condition["message"].(string)
+		return status, reason, message
+	}
+	return "", "", ""
+}
+
+// newManagerRBACCheck returns a check verifying the service account of the provider manager can list and watch
+// the CRDs of the provider; it fails fast if this is still not allowed after the time required to aggregate ClusterRoles.
+func newManagerRBACCheck() providerHealthCheckFunc {
+	var firstDenied time.Time
+	return func(ctx context.Context, c client.Client, components repository.Components) (bool, string, error) {
+		crds, err := providerCRDs(components)
+		if err != nil {
+			return false, "", err
+		}
+
+		denied := []string{}
+		for _, dep := range providerManagers(components) {
+			serviceAccount := dep.Spec.Template.Spec.ServiceAccountName
+			if serviceAccount == "" {
+				serviceAccount = "default"
+			}
+			user := fmt.Sprintf("system:serviceaccount:%s:%s", dep.Namespace, serviceAccount)
+
+			for _, crd := range crds {
+				for _, verb := range []string{"list", "watch"} {
+					review := &authorizationv1.SubjectAccessReview{
+						Spec: authorizationv1.SubjectAccessReviewSpec{
+							User:   user,
+							Groups: []string{"system:serviceaccounts", "system:serviceaccounts:" + dep.Namespace},
+							ResourceAttributes: &authorizationv1.ResourceAttributes{
+								Verb:     verb,
+								Group:    crd.Spec.Group,
+								Resource: crd.Spec.Names.Plural,
+							},
+						},
+					}
+					if err := c.Create(ctx, review); err != nil {
+						return false, fmt.Sprintf("failed to review the permissions of %s: %v", user, err), nil
+					}
+					if !review.Status.Allowed {
+						denied = append(denied, fmt.Sprintf("%s cannot %s %s.%s", user, verb, crd.Spec.Names.Plural, crd.Spec.Group))
+					}
+				}
+			}
+		}
+		if len(denied) > 0 {
+			message := strings.Join(denied, ", ")
+			if firstDenied.IsZero() {
+				firstDenied = time.Now()
+			}
+			if time.Since(firstDenied) > rbacAggregationGracePeriod {
+				return false, "", errors.Errorf("manager RBAC is broken: %s", message)
+			}
+			return false, message, nil
+		}
+		return true, "manager can list and watch the provider CRDs", nil
+	}
+}
+
+// checkManagerAvailable checks the provider manager Deployments are available.
+func checkManagerAvailable(ctx context.Context, c client.Client, components repository.Components) (bool, string, error) {
+	managers := providerManagers(components)
+	notAvailable := []string{}
+	for _, dep := range managers {
+		key := client.ObjectKeyFromObject(dep)
+		current := &appsv1.Deployment{}
+		if err := c.Get(ctx, key, current); err != nil {
+			return false, fmt.Sprintf("failed to get Deployment %s: %v", key, err), nil
+		}
+		available := false
+		for _, condition := range current.Status.Conditions {
+			if condition.Type == appsv1.DeploymentAvailable && condition.Status == corev1.ConditionTrue {
+				available = true
+			}
+		}
+		if !available {
+			notAvailable = append(notAvailable, key.String())
+		}
+	}
+	if len(notAvailable) > 0 {
+		return false, fmt.Sprintf("Deployments %s are not available", strings.Join(notAvailable, ", ")), nil
+	}
+	return true, fmt.Sprintf("%d Deployments available", len(managers)), nil
+}
+
+// checkWebhooksAvailable checks the CA bundle is injected in all the webhooks of the provider, including
+// conversion webhooks, and that the services of the webhooks have ready endpoints.
+func checkWebhooksAvailable(ctx context.Context, c client.Client, components repository.Components) (bool, string, error) {
+	clientConfigs := map[string]admissionregistrationv1.WebhookClientConfig{}
+	for _, obj := range components.Objs() {
+		switch obj.GetKind() {
+		case validatingWebhookConfigurationKind:
+			current := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+			if err := c.Get(ctx, client.ObjectKey{Name: obj.GetName()}, current); err != nil {
+				return false, fmt.Sprintf("failed to get ValidatingWebhookConfiguration %s: %v", obj.GetName(), err), nil
+			}
+			for _, webhook := range current.Webhooks {
+				clientConfigs[fmt.Sprintf("%s/%s", current.Name, webhook.Name)] = webhook.ClientConfig
+			}
+		case mutatingWebhookConfigurationKind:
+			current := &admissionregistrationv1.MutatingWebhookConfiguration{}
+			if err := c.Get(ctx, client.ObjectKey{Name: obj.GetName()}, current); err != nil {
+				return false, fmt.Sprintf("failed to get MutatingWebhookConfiguration %s: %v", obj.GetName(), err), nil
+			}
+			for _, webhook := range current.Webhooks {
+				clientConfigs[fmt.Sprintf("%s/%s", current.Name, webhook.Name)] = webhook.ClientConfig
+			}
+		case customResourceDefinitionKind:
+			current := &apiextensionsv1.CustomResourceDefinition{}
+			if err := c.Get(ctx, client.ObjectKey{Name: obj.GetName()}, current); err != nil {
+				return false, fmt.Sprintf("failed to get CRD %s: %v", obj.GetName(), err), nil
+			}
+			conversion := current.Spec.Conversion
+			if conversion == nil || conversion.Strategy != apiextensionsv1.WebhookConverter || conversion.Webhook == nil || conversion.Webhook.ClientConfig == nil {
+				continue
+			}
+			clientConfig := admissionregistrationv1.WebhookClientConfig{
+				URL:      conversion.Webhook.ClientConfig.URL,
+				CABundle: conversion.Webhook.ClientConfig.CABundle,
+			}
+			if service := conversion.Webhook.ClientConfig.Service; service != nil {
+				clientConfig.Service = &admissionregistrationv1.ServiceReference{Namespace: service.Namespace, Name: service.Name}
+			}
+			clientConfigs[fmt.Sprintf("%s/conversion", current.Name)] = clientConfig
+		}
+	}
+
+	for name, clientConfig := range clientConfigs {
+		if len(clientConfig.CABundle) == 0 {
+			return false, fmt.Sprintf("CA bundle is not injected in webhook %s", name), nil
+		}
+		if clientConfig.Service == nil {
+			continue
+		}
+		endpoints := &corev1.Endpoints{}
+		key := client.ObjectKey{Namespace: clientConfig.Service.Namespace, Name: clientConfig.Service.Name}
+		if err := c.Get(ctx, key, endpoints); err != nil {
+			return false, fmt.Sprintf("failed to get Endpoints %s of webhook %s: %v", key, name, err), nil
+		}
+		ready := false
+		for _, subset := range endpoints.Subsets {
+			if len(subset.Addresses) > 0 {
+				ready = true
+			}
+		}
+		if !ready {
+			return false, fmt.Sprintf("Service %s of webhook %s has no ready endpoints", key, name), nil
+		}
+	}
+	return true, fmt.Sprintf("%d webhooks available", len(clientConfigs)), nil
+}
+
+// checkWebhooksDryRun creates in dry-run an empty object of the first kind of the provider handled by a webhook
+// without side effects, and checks the webhook is called; errors returned by the webhook, e.g. because
+// the object is not valid, are expected.
+func checkWebhooksDryRun(ctx context.Context, c client.Client, components repository.Components) (bool, string, error) {
+	crds, err := providerCRDs(components)
+	if err != nil {
+		return false, "", err
+	}
+
+	for _, obj := range components.Objs() {
+		var webhookName string
+		var rules []admissionregistrationv1.RuleWithOperations
+		switch obj.GetKind() {
+		case validatingWebhookConfigurationKind:
+			webhooks := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, webhooks); err != nil {
+				return false, "", errors.Wrapf(err, "failed to convert ValidatingWebhookConfiguration %s", obj.GetName())
+			}
+			for _, webhook := range webhooks.Webhooks {
+				if hasNoSideEffects(webhook.SideEffects) {
+					webhookName, rules = webhook.Name, webhook.Rules
+					break
+				}
+			}
+		case mutatingWebhookConfigurationKind:
+			webhooks := &admissionregistrationv1.MutatingWebhookConfiguration{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, webhooks); err != nil {
+				return false, "", errors.Wrapf(err, "failed to convert MutatingWebhookConfiguration %s", obj.GetName())
+			}
+			for _, webhook := range webhooks.Webhooks {
+				if hasNoSideEffects(webhook.SideEffects) {
+					webhookName, rules = webhook.Name, webhook.Rules
+					break
+				}
+			}
+		default:
+			continue
+		}
+
+		gvk, namespaced, ok := dryRunTarget(rules, crds)
+		if !ok {
+			continue
+		}
+
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(gvk)
+		u.SetGenerateName(healthCheckObjectPrefix)
+		if namespaced {
+			u.SetNamespace(components.TargetNamespace())
+		}
+		if err := c.Create(ctx, u, client.DryRunAll); err != nil && isWebhookCallError(err) {
+			return false, fmt.Sprintf("failed to call webhook %s creating %s in dry-run: %v", webhookName, gvk.Kind, err), nil
+		}
+		return true, fmt.Sprintf("webhook %s called creating %s in dry-run", webhookName, gvk.Kind), nil
+	}
+	return true, "no webhook to call", nil
+}
+
+func hasNoSideEffects(sideEffects *admissionregistrationv1.SideEffectClass) bool {
+	return sideEffects != nil && (*sideEffects == admissionregistrationv1.SideEffectClassNone || *sideEffects == admissionregistrationv1.SideEffectClassNoneOnDryRun)
+}
+
+// dryRunTarget returns the kind of a CRD of the provider which is created in dry-run to call a webhook with the given rules.
+func dryRunTarget(rules []admissionregistrationv1.RuleWithOperations, crds []*apiextensionsv1.CustomResourceDefinition) (schema.GroupVersionKind, bool, bool) {
+	for _, rule := range rules {
+		create := false
+		for _, operation := range rule.Operations {
+			if operation == admissionregistrationv1.Create || operation == admissionregistrationv1.OperationAll {
+				create = true
+			}
+		}
+		if !create || len(rule.APIGroups) == 0 || len(rule.APIVersions) == 0 {
+			continue
+		}
+		for _, crd := range crds {
+			if crd.Spec.Group != rule.APIGroups[0] {
+				continue
+			}
+			for _, resource := range rule.Resources {
+				if resource != crd.Spec.Names.Plural {
+					continue
+				}
+				gvk := schema.GroupVersionKind{Group: crd.Spec.Group, Version: rule.APIVersions[0], Kind: crd.Spec.Names.Kind}
+				return gvk, crd.Spec.Scope == apiextensionsv1.NamespaceScoped, true
+			}
+		}
+	}
+	return schema.GroupVersionKind{}, false, false
+}
+
+// isWebhookCallError returns true if the API server failed calling a webhook, e.g. because the webhook
+// server is not reachable or its certificate is not valid.
+func isWebhookCallError(err error) bool {
+	return strings.Contains(err.Error(), "failed calling webhook")
+}
+
+// providerCRDs returns the CRDs of a provider.
+func providerCRDs(components repository.Components) ([]*apiextensionsv1.CustomResourceDefinition, error) {
+	crds := []*apiextensionsv1.CustomResourceDefinition{}
+	for _, obj := range components.Objs() {
+		if obj.GetKind() != customResourceDefinitionKind {
+			continue
+		}
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, crd); err != nil {
+			return nil, errors.Wrapf(err, "failed to convert CRD %s", obj.GetName())
+		}
+		crds = append(crds, crd)
+	}
+	return crds, nil
+}
+
+// providerManagers returns the manager Deployments of a provider.
+func providerManagers(components repository.Components) []*appsv1.Deployment {
+	deployments := []*appsv1.Deployment{}
+	for _, obj := range components.Objs() {
+		if !util.IsDeploymentWithManager(obj) {
+			continue
+		}
+		dep := &appsv1.Deployment{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, dep); err != nil {
+			continue
+		}
+		deployments = append(deployments, dep)
+	}
+	return deployments
+}
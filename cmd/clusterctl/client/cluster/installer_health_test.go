@@ -0,0 +1,301 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+func Test_checkCRDsEstablished(t *testing.T) {
+	crd := fakeHealthCheckCRD()
+	establishedCRD := crd.DeepCopy()
+	establishedCRD.Status.Conditions = []apiextensionsv1.CustomResourceDefinitionCondition{
+		{Type: apiextensionsv1.Established, Status: apiextensionsv1.ConditionTrue},
+	}
+
+	tests := []struct {
+		name        string
+		objs        []client.Object
+		wantHealthy bool
+	}{
+		{
+			name:        "Not healthy if the CRD does not exist",
+			wantHealthy: false,
+		},
+		{
+			name:        "Not healthy if the CRD is not established",
+			objs:        []client.Object{crd},
+			wantHealthy: false,
+		},
+		{
+			name:        "Healthy if the CRD is established",
+			objs:        []client.Object{establishedCRD},
+			wantHealthy: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			c, err := test.NewFakeProxy().WithObjs(tt.objs...).NewClient()
+			g.Expect(err).ToNot(HaveOccurred())
+
+			healthy, _, err := checkCRDsEstablished(context.Background(), c, newFakeComponentsWithObjs(t, crd))
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(healthy).To(Equal(tt.wantHealthy))
+		})
+	}
+}
+
+func Test_checkCertificatesReady(t *testing.T) {
+	certificateWithCondition := func(conditionType, status, reason string) *unstructured.Unstructured {
+		certificate := &unstructured.Unstructured{}
+		certificate.SetGroupVersionKind(schema.GroupVersionKind{Group: certManagerGroup, Version: "v1", Kind: certificateKind})
+		certificate.SetNamespace("infra1-system")
+		certificate.SetName("serving-cert")
+		if conditionType != "" {
+			_ = unstructured.SetNestedSlice(certificate.Object, []interface{}{
+				map[string]interface{}{"type": conditionType, "status": status, "reason": reason},
+			}, "status", "conditions")
+		}
+		return certificate
+	}
+
+	tests := []struct {
+		name        string
+		objs        []client.Object
+		wantHealthy bool
+		wantErr     bool
+	}{
+		{
+			name:        "Not healthy if the Certificate does not exist",
+			wantHealthy: false,
+		},
+		{
+			name:        "Not healthy if the Certificate is being issued",
+			objs:        []client.Object{certificateWithCondition("Issuing", "True", "")},
+			wantHealthy: false,
+		},
+		{
+			name:        "Fails if cert-manager failed to issue the Certificate",
+			objs:        []client.Object{certificateWithCondition("Issuing", "False", "Failed")},
+			wantHealthy: false,
+			wantErr:     true,
+		},
+		{
+			name:        "Healthy if the Certificate is ready",
+			objs:        []client.Object{certificateWithCondition("Ready", "True", "Ready")},
+			wantHealthy: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			c, err := test.NewFakeProxy().WithObjs(tt.objs...).NewClient()
+			g.Expect(err).ToNot(HaveOccurred())
+
+			healthy, _, err := checkCertificatesReady(context.Background(), c, newFakeComponentsWithObjs(t, certificateWithCondition("", "", "")))
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+			g.Expect(healthy).To(Equal(tt.wantHealthy))
+		})
+	}
+}
+
+func Test_checkWebhooksAvailable(t *testing.T) {
+	sideEffects := admissionregistrationv1.SideEffectClassNone
+	webhookConfiguration := &admissionregistrationv1.ValidatingWebhookConfiguration{
+		TypeMeta:   metav1.TypeMeta{APIVersion: admissionregistrationv1.SchemeGroupVersion.String(), Kind: validatingWebhookConfigurationKind},
+		ObjectMeta: metav1.ObjectMeta{Name: "validating-webhook-configuration"},
+		Webhooks: []admissionregistrationv1.ValidatingWebhook{
+			{
+				Name: "validation.foos.test.cluster.x-k8s.io",
+				ClientConfig: admissionregistrationv1.WebhookClientConfig{
+					Service: &admissionregistrationv1.ServiceReference{Namespace: "ns1", Name: "webhook-service"},
+				},
+				SideEffects: &sideEffects,
+			},
+		},
+	}
+	injectedWebhookConfiguration := webhookConfiguration.DeepCopy()
+	injectedWebhookConfiguration.Webhooks[0].ClientConfig.CABundle = []byte("ca")
+
+	endpoints := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "webhook-service"},
+	}
+	readyEndpoints := endpoints.DeepCopy()
+	readyEndpoints.Subsets = []corev1.EndpointSubset{{Addresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}}}}
+
+	tests := []struct {
+		name        string
+		objs        []client.Object
+		wantHealthy bool
+	}{
+		{
+			name:        "Not healthy if the CA bundle is not injected",
+			objs:        []client.Object{webhookConfiguration, readyEndpoints},
+			wantHealthy: false,
+		},
+		{
+			name:        "Not healthy if the webhook service has no ready endpoints",
+			objs:        []client.Object{injectedWebhookConfiguration, endpoints},
+			wantHealthy: false,
+		},
+		{
+			name:        "Healthy if the CA bundle is injected and the webhook service has ready endpoints",
+			objs:        []client.Object{injectedWebhookConfiguration, readyEndpoints},
+			wantHealthy: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			c, err := test.NewFakeProxy().WithObjs(tt.objs...).NewClient()
+			g.Expect(err).ToNot(HaveOccurred())
+
+			healthy, _, err := checkWebhooksAvailable(context.Background(), c, newFakeComponentsWithObjs(t, webhookConfiguration))
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(healthy).To(Equal(tt.wantHealthy))
+		})
+	}
+}
+
+func Test_dryRunTarget(t *testing.T) {
+	crd := fakeHealthCheckCRD()
+
+	tests := []struct {
+		name           string
+		rules          []admissionregistrationv1.RuleWithOperations
+		wantGVK        schema.GroupVersionKind
+		wantNamespaced bool
+		wantOK         bool
+	}{
+		{
+			name: "Returns the kind of a CRD of the provider handled on create",
+			rules: []admissionregistrationv1.RuleWithOperations{
+				{
+					Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create, admissionregistrationv1.Update},
+					Rule: admissionregistrationv1.Rule{
+						APIGroups:   []string{"test.cluster.x-k8s.io"},
+						APIVersions: []string{"v1beta1"},
+						Resources:   []string{"foos"},
+					},
+				},
+			},
+			wantGVK:        schema.GroupVersionKind{Group: "test.cluster.x-k8s.io", Version: "v1beta1", Kind: "Foo"},
+			wantNamespaced: true,
+			wantOK:         true,
+		},
+		{
+			name: "Ignores rules not handling create",
+			rules: []admissionregistrationv1.RuleWithOperations{
+				{
+					Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Delete},
+					Rule: admissionregistrationv1.Rule{
+						APIGroups:   []string{"test.cluster.x-k8s.io"},
+						APIVersions: []string{"v1beta1"},
+						Resources:   []string{"foos"},
+					},
+				},
+			},
+			wantOK: false,
+		},
+		{
+			name: "Ignores rules for resources not of the provider",
+			rules: []admissionregistrationv1.RuleWithOperations{
+				{
+					Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.OperationAll},
+					Rule: admissionregistrationv1.Rule{
+						APIGroups:   []string{""},
+						APIVersions: []string{"v1"},
+						Resources:   []string{"pods"},
+					},
+				},
+			},
+			wantOK: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			gvk, namespaced, ok := dryRunTarget(tt.rules, []*apiextensionsv1.CustomResourceDefinition{crd})
+			g.Expect(ok).To(Equal(tt.wantOK))
+			g.Expect(gvk).To(Equal(tt.wantGVK))
+			g.Expect(namespaced).To(Equal(tt.wantNamespaced))
+		})
+	}
+}
+
+func TestProviderHealth_Healthy(t *testing.T) {
+	g := NewWithT(t)
+
+	health := ProviderHealth{
+		Checks: []ProviderHealthCheck{
+			{Name: CRDsEstablishedHealthCheck, Healthy: true},
+		},
+	}
+	g.Expect(health.Healthy()).To(BeTrue())
+
+	health.Checks = append(health.Checks, ProviderHealthCheck{Name: CertificatesReadyHealthCheck, Healthy: false})
+	g.Expect(health.Healthy()).To(BeFalse())
+}
+
+func fakeHealthCheckCRD() *apiextensionsv1.CustomResourceDefinition {
+	return &apiextensionsv1.CustomResourceDefinition{
+		TypeMeta:   metav1.TypeMeta{APIVersion: apiextensionsv1.SchemeGroupVersion.String(), Kind: customResourceDefinitionKind},
+		ObjectMeta: metav1.ObjectMeta{Name: "foos.test.cluster.x-k8s.io"},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: "test.cluster.x-k8s.io",
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Kind: "Foo", Plural: "foos"},
+			Scope: apiextensionsv1.NamespaceScoped,
+		},
+	}
+}
+
+func newFakeComponentsWithObjs(t *testing.T, objs ...client.Object) *fakeComponents {
+	t.Helper()
+
+	components := newFakeComponents("infra1", clusterctlv1.InfrastructureProviderType, "v1.0.0", "infra1-system").(*fakeComponents)
+	for _, obj := range objs {
+		u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			t.Fatal(err)
+		}
+		components.objs = append(components.objs, unstructured.Unstructured{Object: u})
+	}
+	return components
+}
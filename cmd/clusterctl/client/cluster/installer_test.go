@@ -304,6 +304,7 @@ func Test_providerInstaller_ValidateCRDName(t *testing.T) {
 type fakeComponents struct {
 	config.Provider
 	inventoryObject clusterctlv1.Provider
+	objs            []unstructured.Unstructured
 }
 
 func (c *fakeComponents) Version() string {
@@ -319,7 +320,7 @@ func (c *fakeComponents) Images() []string {
 }
 
 func (c *fakeComponents) TargetNamespace() string {
-	panic("not implemented")
+	return c.inventoryObject.Namespace
 }
 
 func (c *fakeComponents) InventoryObject() clusterctlv1.Provider {
@@ -327,7 +328,10 @@ func (c *fakeComponents) InventoryObject() clusterctlv1.Provider {
 }
 
 func (c *fakeComponents) Objs() []unstructured.Unstructured {
-	return []unstructured.Unstructured{}
+	if c.objs == nil {
+		return []unstructured.Unstructured{}
+	}
+	return c.objs
 }
 
 func (c *fakeComponents) Yaml() ([]byte, error) {
@@ -49,7 +49,9 @@ type ResourceMutatorFunc func(u *unstructured.Unstructured) error
 // ObjectMover defines methods for moving Cluster API objects to another management cluster.
 type ObjectMover interface {
 	// Move moves all the Cluster API objects existing in a namespace (or from all the namespaces if empty) to a target management cluster.
-	Move(namespace string, toCluster Client, dryRun bool, mutators ...ResourceMutatorFunc) error
+	// Before moving, pre-flight checks are run on the target management cluster; errors of the pre-flight checks
+	// listed in ignorePreflightErrors are reported as warnings.
+	Move(namespace string, toCluster Client, dryRun bool, ignorePreflightErrors []string, mutators ...ResourceMutatorFunc) error
 
 	// ToDirectory writes all the Cluster API objects existing in a namespace (or from all the namespaces if empty) to a target directory.
 	ToDirectory(namespace string, directory string) error
@@ -68,7 +70,7 @@ type objectMover struct {
 // ensure objectMover implements the ObjectMover interface.
 var _ ObjectMover = &objectMover{}
 
-func (o *objectMover) Move(namespace string, toCluster Client, dryRun bool, mutators ...ResourceMutatorFunc) error {
+func (o *objectMover) Move(namespace string, toCluster Client, dryRun bool, ignorePreflightErrors []string, mutators ...ResourceMutatorFunc) error {
 	log := logf.Log
 	log.Info("Performing move...")
 	o.dryRun = dryRun
@@ -78,18 +80,19 @@ func (o *objectMover) Move(namespace string, toCluster Client, dryRun bool, muta
 		log.Info("********************************************************")
 	}
 
-	// checks that all the required providers in place in the target cluster.
-	if !o.dryRun {
-		if err := o.checkTargetProviders(toCluster.ProviderInventory()); err != nil {
-			return errors.Wrap(err, "failed to check providers in target cluster")
-		}
-	}
-
 	objectGraph, err := o.getObjectGraph(namespace)
 	if err != nil {
 		return errors.Wrap(err, "failed to get object graph")
 	}
 
+	// Checks that the target cluster is ready to receive the objects, e.g. that all the required providers and CRDs are in place,
+	// so the move does not stop half way.
+	if !o.dryRun {
+		if err := o.runPreflightChecks(objectGraph, toCluster, ignorePreflightErrors); err != nil {
+			return err
+		}
+	}
+
 	// Move the objects to the target cluster.
 	var proxy Proxy
 	if !o.dryRun {
@@ -893,6 +896,9 @@ func (o *objectMover) createTargetObject(nodeToCreate *node, toProxy Proxy, muta
 	// Rebuild the owner reference chain
 	o.buildOwnerChain(obj, nodeToCreate)
 
+	// Record the ID of the move operation, so it can be correlated with the activity of the controllers in the target cluster.
+	SetOperationIDAnnotation(obj)
+
 	// FIXME Workaround for https://github.com/kubernetes/kubernetes/issues/32220. Remove when the issue is fixed.
 	// If the resource already exists, the API server ordinarily returns an AlreadyExists error. Due to the above issue, if the resource has a non-empty metadata.generateName field, the API server returns a ServerTimeoutError. To ensure that the API server returns an AlreadyExists error, we set the metadata.generateName field to an empty string.
 	if len(obj.GetName()) > 0 && len(obj.GetGenerateName()) > 0 {
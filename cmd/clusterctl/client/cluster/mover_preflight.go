@@ -0,0 +1,373 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+)
+
+// MovePreflightCheck is the name of a pre-flight check executed on the target cluster before moving objects.
+type MovePreflightCheck string
+
+const (
+	// ProviderVersionsPreflightCheck checks that all the providers installed in the source cluster are installed
+	// in the target cluster with the same or a newer version.
+	ProviderVersionsPreflightCheck MovePreflightCheck = "ProviderVersions"
+
+	// CRDVersionsPreflightCheck checks that the target cluster has the CRDs for all the objects to be moved, and
+	// that the CRDs serve the API version of the objects in the source cluster.
+	CRDVersionsPreflightCheck MovePreflightCheck = "CRDVersions"
+
+	// WebhooksPreflightCheck checks that the services of the webhooks installed by clusterctl in the target cluster,
+	// including conversion webhooks, have ready endpoints.
+	WebhooksPreflightCheck MovePreflightCheck = "Webhooks"
+
+	// NamespacesPreflightCheck checks that the namespaces of the objects to be moved exist in the target cluster or can be
+	// created, i.e. they are not being deleted.
+	NamespacesPreflightCheck MovePreflightCheck = "Namespaces"
+
+	// CertManagerPreflightCheck checks that cert-manager, if installed by clusterctl in the target cluster, is available.
+	CertManagerPreflightCheck MovePreflightCheck = "CertManager"
+
+	// AllPreflightChecks can be used to ignore errors of all the pre-flight checks.
+	AllPreflightChecks MovePreflightCheck = "all"
+)
+
+// movePreflightChecks is the ordered list of pre-flight checks executed on the target cluster.
+var movePreflightChecks = []MovePreflightCheck{
+	ProviderVersionsPreflightCheck,
+	CRDVersionsPreflightCheck,
+	WebhooksPreflightCheck,
+	NamespacesPreflightCheck,
+	CertManagerPreflightCheck,
+}
+
+// movePreflightResult is the result of a pre-flight check.
+type movePreflightResult struct {
+	check   MovePreflightCheck
+	err     error
+	ignored bool
+}
+
+// movePreflightReport is the report of the pre-flight checks executed on the target cluster.
+type movePreflightReport struct {
+	results []movePreflightResult
+}
+
+// log logs the result of each pre-flight check.
+func (r *movePreflightReport) log() {
+	log := logf.Log
+	for _, result := range r.results {
+		switch {
+		case result.err == nil:
+			log.Info("Pre-flight check passed", "check", result.check)
+		case result.ignored:
+			log.Info(fmt.Sprintf("Warning: ignoring failed pre-flight check: %s", result.err.Error()), "check", result.check)
+		default:
+			log.Info(fmt.Sprintf("Pre-flight check failed: %s", result.err.Error()), "check", result.check)
+		}
+	}
+}
+
+// aggregate returns the errors of the failed pre-flight checks which are not ignored.
+func (r *movePreflightReport) aggregate() error {
+	errList := []error{}
+	for _, result := range r.results {
+		if result.err != nil && !result.ignored {
+			errList = append(errList, errors.Wrapf(result.err, "pre-flight check %s failed", result.check))
+		}
+	}
+	if len(errList) == 0 {
+		return nil
+	}
+	return errors.Wrapf(kerrors.NewAggregate(errList), "target cluster pre-flight checks failed (errors can be ignored using --ignore-preflight-errors)")
+}
+
+// ignoredPreflightChecks returns the set of pre-flight checks whose errors should be ignored.
+func ignoredPreflightChecks(ignorePreflightErrors []string) (sets.Set[MovePreflightCheck], error) {
+	ignored := sets.Set[MovePreflightCheck]{}
+	for _, name := range ignorePreflightErrors {
+		if strings.EqualFold(name, string(AllPreflightChecks)) {
+			return sets.New[MovePreflightCheck](movePreflightChecks...), nil
+		}
+
+		found := false
+		for _, check := range movePreflightChecks {
+			if strings.EqualFold(name, string(check)) {
+				ignored.Insert(check)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, errors.Errorf("invalid pre-flight check %q, valid values are %s and %s", name, movePreflightChecks, AllPreflightChecks)
+		}
+	}
+	return ignored, nil
+}
+
+// runPreflightChecks runs the pre-flight checks on the target cluster, so the move operation does not start if it
+// can't be completed, e.g. because of missing CRDs in the target cluster.
+func (o *objectMover) runPreflightChecks(graph *objectGraph, toCluster Client, ignorePreflightErrors []string) error {
+	log := logf.Log
+	log.Info("Running pre-flight checks on the target cluster")
+
+	ignored, err := ignoredPreflightChecks(ignorePreflightErrors)
+	if err != nil {
+		return err
+	}
+
+	checkFuncs := map[MovePreflightCheck]func() error{
+		ProviderVersionsPreflightCheck: func() error { return o.checkTargetProviders(toCluster.ProviderInventory()) },
+		CRDVersionsPreflightCheck:      func() error { return checkTargetCRDVersions(graph, toCluster.Proxy()) },
+		WebhooksPreflightCheck:         func() error { return checkTargetWebhooks(toCluster.Proxy()) },
+		NamespacesPreflightCheck:       func() error { return checkTargetNamespaces(graph, toCluster.Proxy()) },
+		CertManagerPreflightCheck:      func() error { return checkTargetCertManager(toCluster.Proxy()) },
+	}
+
+	report := &movePreflightReport{}
+	for _, check := range movePreflightChecks {
+		report.results = append(report.results, movePreflightResult{
+			check:   check,
+			err:     checkFuncs[check](),
+			ignored: ignored.Has(check),
+		})
+	}
+	report.log()
+
+	return report.aggregate()
+}
+
+// checkTargetCRDVersions checks that the target cluster has the CRDs for all the objects to be moved, and that
+// the CRDs serve the API version of the objects in the source cluster.
+func checkTargetCRDVersions(graph *objectGraph, toProxy Proxy) error {
+	c, err := toProxy.NewClient()
+	if err != nil {
+		return err
+	}
+
+	crdList := &apiextensionsv1.CustomResourceDefinitionList{}
+	if err := retryWithExponentialBackoff(newReadBackoff(), func() error {
+		return c.List(ctx, crdList)
+	}); err != nil {
+		return errors.Wrap(err, "failed to list CRDs in the target cluster")
+	}
+
+	crds := map[schema.GroupKind]apiextensionsv1.CustomResourceDefinition{}
+	for _, crd := range crdList.Items {
+		crds[schema.GroupKind{Group: crd.Spec.Group, Kind: crd.Spec.Names.Kind}] = crd
+	}
+
+	gvks := sets.Set[schema.GroupVersionKind]{}
+	for _, n := range graph.getMoveNodes() {
+		gvk := n.identity.GroupVersionKind()
+		// Core types, e.g. Secrets and ConfigMaps, are always available.
+		if gvk.Group == "" {
+			continue
+		}
+		gvks.Insert(gvk)
+	}
+
+	errList := []error{}
+	for _, gvk := range sortedGroupVersionKinds(gvks) {
+		crd, ok := crds[gvk.GroupKind()]
+		if !ok {
+			errList = append(errList, errors.Errorf("CRD for %s not found in the target cluster", gvk.GroupKind()))
+			continue
+		}
+
+		served := false
+		for _, version := range crd.Spec.Versions {
+			if version.Name == gvk.Version && version.Served {
+				served = true
+				break
+			}
+		}
+		if !served {
+			errList = append(errList, errors.Errorf("CRD %s in the target cluster does not serve version %s", crd.Name, gvk.Version))
+		}
+	}
+	return kerrors.NewAggregate(errList)
+}
+
+// checkTargetWebhooks checks that the services of the webhooks installed by clusterctl in the target cluster,
+// including conversion webhooks, have ready endpoints.
+func checkTargetWebhooks(toProxy Proxy) error {
+	c, err := toProxy.NewClient()
+	if err != nil {
+		return err
+	}
+
+	services := sets.Set[client.ObjectKey]{}
+
+	validatingWebhooks := &admissionregistrationv1.ValidatingWebhookConfigurationList{}
+	if err := c.List(ctx, validatingWebhooks, client.HasLabels{clusterctlv1.ClusterctlLabel}); err != nil {
+		return errors.Wrap(err, "failed to list ValidatingWebhookConfigurations in the target cluster")
+	}
+	for _, config := range validatingWebhooks.Items {
+		for _, webhook := range config.Webhooks {
+			if webhook.ClientConfig.Service != nil {
+				services.Insert(client.ObjectKey{Namespace: webhook.ClientConfig.Service.Namespace, Name: webhook.ClientConfig.Service.Name})
+			}
+		}
+	}
+
+	mutatingWebhooks := &admissionregistrationv1.MutatingWebhookConfigurationList{}
+	if err := c.List(ctx, mutatingWebhooks, client.HasLabels{clusterctlv1.ClusterctlLabel}); err != nil {
+		return errors.Wrap(err, "failed to list MutatingWebhookConfigurations in the target cluster")
+	}
+	for _, config := range mutatingWebhooks.Items {
+		for _, webhook := range config.Webhooks {
+			if webhook.ClientConfig.Service != nil {
+				services.Insert(client.ObjectKey{Namespace: webhook.ClientConfig.Service.Namespace, Name: webhook.ClientConfig.Service.Name})
+			}
+		}
+	}
+
+	crdList := &apiextensionsv1.CustomResourceDefinitionList{}
+	if err := c.List(ctx, crdList, client.HasLabels{clusterctlv1.ClusterctlLabel}); err != nil {
+		return errors.Wrap(err, "failed to list CRDs in the target cluster")
+	}
+	for _, crd := range crdList.Items {
+		conversion := crd.Spec.Conversion
+		if conversion != nil && conversion.Strategy == apiextensionsv1.WebhookConverter && conversion.Webhook != nil &&
+			conversion.Webhook.ClientConfig != nil && conversion.Webhook.ClientConfig.Service != nil {
+			services.Insert(client.ObjectKey{Namespace: conversion.Webhook.ClientConfig.Service.Namespace, Name: conversion.Webhook.ClientConfig.Service.Name})
+		}
+	}
+
+	errList := []error{}
+	for _, key := range sortedObjectKeys(services) {
+		endpoints := &corev1.Endpoints{}
+		if err := c.Get(ctx, key, endpoints); err != nil {
+			if apierrors.IsNotFound(err) {
+				errList = append(errList, errors.Errorf("webhook service %s has no endpoints in the target cluster", key))
+				continue
+			}
+			return errors.Wrapf(err, "failed to get endpoints of webhook service %s in the target cluster", key)
+		}
+
+		ready := false
+		for _, subset := range endpoints.Subsets {
+			if len(subset.Addresses) > 0 {
+				ready = true
+				break
+			}
+		}
+		if !ready {
+			errList = append(errList, errors.Errorf("webhook service %s has no ready endpoints in the target cluster", key))
+		}
+	}
+	return kerrors.NewAggregate(errList)
+}
+
+// checkTargetNamespaces checks that the namespaces of the objects to be moved exist in the target cluster or can be
+// created, i.e. they are not being deleted.
+func checkTargetNamespaces(graph *objectGraph, toProxy Proxy) error {
+	c, err := toProxy.NewClient()
+	if err != nil {
+		return err
+	}
+
+	namespaces := sets.Set[string]{}
+	for _, n := range graph.getMoveNodes() {
+		if n.identity.Namespace != "" {
+			namespaces.Insert(n.identity.Namespace)
+		}
+	}
+
+	errList := []error{}
+	for _, namespace := range sets.List(namespaces) {
+		ns := &corev1.Namespace{}
+		if err := c.Get(ctx, client.ObjectKey{Name: namespace}, ns); err != nil {
+			// Namespaces not existing in the target cluster are created during move.
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return errors.Wrapf(err, "failed to get namespace %s in the target cluster", namespace)
+		}
+		if !ns.DeletionTimestamp.IsZero() {
+			errList = append(errList, errors.Errorf("namespace %s is being deleted in the target cluster", namespace))
+		}
+	}
+	return kerrors.NewAggregate(errList)
+}
+
+// checkTargetCertManager checks that cert-manager, if installed by clusterctl in the target cluster, is available.
+func checkTargetCertManager(toProxy Proxy) error {
+	log := logf.Log
+
+	c, err := toProxy.NewClient()
+	if err != nil {
+		return err
+	}
+
+	deployments := &appsv1.DeploymentList{}
+	if err := c.List(ctx, deployments, client.MatchingLabels{clusterctlv1.ClusterctlCoreLabel: clusterctlv1.ClusterctlCoreLabelCertManagerValue}); err != nil {
+		return errors.Wrap(err, "failed to list cert-manager Deployments in the target cluster")
+	}
+	if len(deployments.Items) == 0 {
+		log.V(1).Info("cert-manager has not been installed by clusterctl in the target cluster, skipping the cert-manager availability check")
+		return nil
+	}
+
+	errList := []error{}
+	for _, deployment := range deployments.Items {
+		replicas := int32(1)
+		if deployment.Spec.Replicas != nil {
+			replicas = *deployment.Spec.Replicas
+		}
+		if deployment.Status.AvailableReplicas < replicas {
+			errList = append(errList, errors.Errorf("cert-manager Deployment %s has %d available replicas, %d expected", client.ObjectKeyFromObject(&deployment), deployment.Status.AvailableReplicas, replicas))
+		}
+	}
+	return kerrors.NewAggregate(errList)
+}
+
+// sortedGroupVersionKinds returns the GroupVersionKinds in a deterministic order.
+func sortedGroupVersionKinds(gvks sets.Set[schema.GroupVersionKind]) []schema.GroupVersionKind {
+	list := gvks.UnsortedList()
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].String() < list[j].String()
+	})
+	return list
+}
+
+// sortedObjectKeys returns the ObjectKeys in a deterministic order.
+func sortedObjectKeys(keys sets.Set[client.ObjectKey]) []client.ObjectKey {
+	list := keys.UnsortedList()
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].String() < list[j].String()
+	})
+	return list
+}
@@ -0,0 +1,320 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+func Test_ignoredPreflightChecks(t *testing.T) {
+	tests := []struct {
+		name                  string
+		ignorePreflightErrors []string
+		want                  sets.Set[MovePreflightCheck]
+		wantErr               bool
+	}{
+		{
+			name: "No ignored checks",
+			want: sets.Set[MovePreflightCheck]{},
+		},
+		{
+			name:                  "Ignored checks are matched case insensitive",
+			ignorePreflightErrors: []string{"certmanager", "Webhooks"},
+			want:                  sets.New[MovePreflightCheck](CertManagerPreflightCheck, WebhooksPreflightCheck),
+		},
+		{
+			name:                  "all ignores all the checks",
+			ignorePreflightErrors: []string{"all"},
+			want:                  sets.New[MovePreflightCheck](movePreflightChecks...),
+		},
+		{
+			name:                  "Fails for unknown checks",
+			ignorePreflightErrors: []string{"CertManager", "DoesNotExist"},
+			wantErr:               true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := ignoredPreflightChecks(tt.ignorePreflightErrors)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_movePreflightReport_aggregate(t *testing.T) {
+	g := NewWithT(t)
+
+	report := &movePreflightReport{
+		results: []movePreflightResult{
+			{check: ProviderVersionsPreflightCheck},
+			{check: WebhooksPreflightCheck, err: errors.New("webhook service not ready"), ignored: true},
+		},
+	}
+	g.Expect(report.aggregate()).To(Succeed())
+
+	report.results = append(report.results, movePreflightResult{check: CertManagerPreflightCheck, err: errors.New("cert-manager not available")})
+	err := report.aggregate()
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("pre-flight check CertManager failed"))
+	g.Expect(err.Error()).ToNot(ContainSubstring("webhook service not ready"))
+}
+
+func Test_checkTargetCRDVersions(t *testing.T) {
+	tests := []struct {
+		name    string
+		toProxy Proxy
+		wantErr bool
+	}{
+		{
+			name: "All the CRDs in place",
+			toProxy: test.NewFakeProxy().WithObjs(
+				test.FakeNamespacedCustomResourceDefinition("cluster.x-k8s.io", "Cluster", "v1beta1"),
+				test.FakeNamespacedCustomResourceDefinition("infrastructure.cluster.x-k8s.io", "GenericInfrastructureCluster", "v1beta1"),
+			),
+		},
+		{
+			name: "Fails if a CRD is missing",
+			toProxy: test.NewFakeProxy().WithObjs(
+				test.FakeNamespacedCustomResourceDefinition("cluster.x-k8s.io", "Cluster", "v1beta1"),
+			),
+			wantErr: true,
+		},
+		{
+			name: "Fails if a CRD does not serve the version of the objects",
+			toProxy: test.NewFakeProxy().WithObjs(
+				test.FakeNamespacedCustomResourceDefinition("cluster.x-k8s.io", "Cluster", "v1beta1"),
+				test.FakeNamespacedCustomResourceDefinition("infrastructure.cluster.x-k8s.io", "GenericInfrastructureCluster", "v1beta2"),
+			),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			graph, err := getDetachedObjectGraphWihObjs(test.NewFakeCluster("ns1", "cluster1").Objs())
+			g.Expect(err).ToNot(HaveOccurred())
+			graph.setSoftOwnership()
+			graph.setTenants()
+
+			err = checkTargetCRDVersions(graph, tt.toProxy)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+		})
+	}
+}
+
+func Test_checkTargetWebhooks(t *testing.T) {
+	webhookConfiguration := &admissionregistrationv1.ValidatingWebhookConfiguration{
+		TypeMeta: metav1.TypeMeta{Kind: "ValidatingWebhookConfiguration", APIVersion: admissionregistrationv1.SchemeGroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "capi-validating-webhook-configuration",
+			Labels: map[string]string{clusterctlv1.ClusterctlLabel: ""},
+		},
+		Webhooks: []admissionregistrationv1.ValidatingWebhook{
+			{
+				Name: "validation.cluster.cluster.x-k8s.io",
+				ClientConfig: admissionregistrationv1.WebhookClientConfig{
+					Service: &admissionregistrationv1.ServiceReference{Namespace: "capi-system", Name: "capi-webhook-service"},
+				},
+			},
+		},
+	}
+	crd := test.FakeNamespacedCustomResourceDefinition("cluster.x-k8s.io", "Cluster", "v1beta1")
+	crd.Spec.Conversion = &apiextensionsv1.CustomResourceConversion{
+		Strategy: apiextensionsv1.WebhookConverter,
+		Webhook: &apiextensionsv1.WebhookConversion{
+			ClientConfig: &apiextensionsv1.WebhookClientConfig{
+				Service: &apiextensionsv1.ServiceReference{Namespace: "capi-system", Name: "capi-conversion-service"},
+			},
+		},
+	}
+	endpoints := func(name string, ready bool) *corev1.Endpoints {
+		e := &corev1.Endpoints{
+			TypeMeta:   metav1.TypeMeta{Kind: "Endpoints", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "capi-system", Name: name},
+		}
+		if ready {
+			e.Subsets = []corev1.EndpointSubset{{Addresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}}}}
+		} else {
+			e.Subsets = []corev1.EndpointSubset{{NotReadyAddresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}}}}
+		}
+		return e
+	}
+
+	tests := []struct {
+		name    string
+		toProxy Proxy
+		wantErr bool
+	}{
+		{
+			name:    "No webhooks",
+			toProxy: test.NewFakeProxy(),
+		},
+		{
+			name:    "All the webhook services have ready endpoints",
+			toProxy: test.NewFakeProxy().WithObjs(webhookConfiguration, crd, endpoints("capi-webhook-service", true), endpoints("capi-conversion-service", true)),
+		},
+		{
+			name:    "Fails if a webhook service has no endpoints",
+			toProxy: test.NewFakeProxy().WithObjs(webhookConfiguration, crd, endpoints("capi-webhook-service", true)),
+			wantErr: true,
+		},
+		{
+			name:    "Fails if a webhook service has no ready endpoints",
+			toProxy: test.NewFakeProxy().WithObjs(webhookConfiguration, crd, endpoints("capi-webhook-service", false), endpoints("capi-conversion-service", true)),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := checkTargetWebhooks(tt.toProxy)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+		})
+	}
+}
+
+func Test_checkTargetNamespaces(t *testing.T) {
+	tests := []struct {
+		name    string
+		toProxy Proxy
+		wantErr bool
+	}{
+		{
+			name:    "Namespace does not exist",
+			toProxy: test.NewFakeProxy(),
+		},
+		{
+			name: "Namespace exists",
+			toProxy: test.NewFakeProxy().WithObjs(&corev1.Namespace{
+				TypeMeta:   metav1.TypeMeta{Kind: "Namespace", APIVersion: "v1"},
+				ObjectMeta: metav1.ObjectMeta{Name: "ns1"},
+			}),
+		},
+		{
+			name: "Fails if the namespace is being deleted",
+			toProxy: test.NewFakeProxy().WithObjs(&corev1.Namespace{
+				TypeMeta: metav1.TypeMeta{Kind: "Namespace", APIVersion: "v1"},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "ns1",
+					DeletionTimestamp: &metav1.Time{Time: time.Now()},
+					Finalizers:        []string{"kubernetes"},
+				},
+			}),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			graph, err := getDetachedObjectGraphWihObjs(test.NewFakeCluster("ns1", "cluster1").Objs())
+			g.Expect(err).ToNot(HaveOccurred())
+			graph.setSoftOwnership()
+			graph.setTenants()
+
+			err = checkTargetNamespaces(graph, tt.toProxy)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+		})
+	}
+}
+
+func Test_checkTargetCertManager(t *testing.T) {
+	deployment := func(availableReplicas int32) client.Object {
+		return &appsv1.Deployment{
+			TypeMeta: metav1.TypeMeta{Kind: "Deployment", APIVersion: appsv1.SchemeGroupVersion.String()},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "cert-manager",
+				Name:      "cert-manager-webhook",
+				Labels:    map[string]string{clusterctlv1.ClusterctlCoreLabel: clusterctlv1.ClusterctlCoreLabelCertManagerValue},
+			},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: pointer.Int32(1),
+			},
+			Status: appsv1.DeploymentStatus{
+				AvailableReplicas: availableReplicas,
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		toProxy Proxy
+		wantErr bool
+	}{
+		{
+			name:    "cert-manager not installed by clusterctl",
+			toProxy: test.NewFakeProxy(),
+		},
+		{
+			name:    "cert-manager available",
+			toProxy: test.NewFakeProxy().WithObjs(deployment(1)),
+		},
+		{
+			name:    "Fails if cert-manager is not available",
+			toProxy: test.NewFakeProxy().WithObjs(deployment(0)),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := checkTargetCertManager(tt.toProxy)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+		})
+	}
+}
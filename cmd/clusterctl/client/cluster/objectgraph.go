@@ -26,6 +26,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -38,6 +39,7 @@ import (
 
 const clusterTopologyNameKey = "cluster.spec.topology.class"
 const clusterResourceSetBindingClusterNameKey = "clusterresourcesetbinding.spec.clustername"
+const moveParentsKey = "metadata.annotations.move-parents"
 
 type empty struct{}
 
@@ -93,6 +95,39 @@ type node struct {
 	additionalInfo map[string]interface{}
 }
 
+// moveParentRef is a reference to a parent object defined with the move-parents annotation.
+type moveParentRef struct {
+	// groupKind of the parent; an empty group matches parents in any group.
+	groupKind schema.GroupKind
+	name      string
+}
+
+// matches returns true if the node is the parent object the reference points to.
+func (r moveParentRef) matches(n *node) bool {
+	gk := n.identity.GroupVersionKind().GroupKind()
+	if gk.Kind != r.groupKind.Kind || (r.groupKind.Group != "" && gk.Group != r.groupKind.Group) {
+		return false
+	}
+	return n.identity.Name == r.name
+}
+
+// parseMoveParents parses the value of the move-parents annotation.
+func parseMoveParents(value string) ([]moveParentRef, error) {
+	refs := []moveParentRef{}
+	for _, parent := range strings.Split(value, ",") {
+		parent = strings.TrimSpace(parent)
+		if parent == "" {
+			continue
+		}
+		groupKind, name, ok := strings.Cut(parent, "/")
+		if !ok || groupKind == "" || name == "" {
+			return nil, errors.Errorf("invalid parent %q, expected <Kind>[.<group>]/<name>", parent)
+		}
+		refs = append(refs, moveParentRef{groupKind: schema.ParseGroupKind(groupKind), name: name})
+	}
+	return refs, nil
+}
+
 type discoveryTypeInfo struct {
 	typeMeta           metav1.TypeMeta
 	forceMove          bool
@@ -158,6 +193,18 @@ func (n *node) captureAdditionalInformation(obj *unstructured.Unstructured) erro
 		}
 		n.additionalInfo[clusterResourceSetBindingClusterNameKey] = binding.Spec.ClusterName
 	}
+
+	// If the object has the move-parents annotation capture the parents it is referencing to.
+	if value, ok := obj.GetAnnotations()[clusterctlv1.MoveParentsAnnotation]; ok {
+		parents, err := parseMoveParents(value)
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse the %s annotation of object %s", clusterctlv1.MoveParentsAnnotation, n.identityStr())
+		}
+		if n.additionalInfo == nil {
+			n.additionalInfo = map[string]interface{}{}
+		}
+		n.additionalInfo[moveParentsKey] = parents
+	}
 	return nil
 }
 
@@ -322,7 +369,7 @@ func (o *objectGraph) objMetaToNode(obj *unstructured.Unstructured, n *node) {
 }
 
 // getDiscoveryTypes returns the list of TypeMeta to be considered for the move discovery phase.
-// This list includes all the types defines by the CRDs installed by clusterctl and the ConfigMap/Secret core types.
+// This list includes all the types defines by the CRDs installed by clusterctl or labeled for move, and the ConfigMap/Secret core types.
 func (o *objectGraph) getDiscoveryTypes() error {
 	crdList := &apiextensionsv1.CustomResourceDefinitionList{}
 	getDiscoveryTypesBackoff := newReadBackoff()
@@ -403,9 +450,21 @@ func getCRDList(proxy Proxy, crdList *apiextensionsv1.CustomResourceDefinitionLi
 		return err
 	}
 
-	if err := c.List(ctx, crdList, client.HasLabels{clusterctlv1.ClusterctlLabel}); err != nil {
+	allCRDs := &apiextensionsv1.CustomResourceDefinitionList{}
+	if err := c.List(ctx, allCRDs); err != nil {
 		return errors.Wrap(err, "failed to get the list of CRDs required for the move discovery phase")
 	}
+
+	// Consider the CRDs installed by clusterctl and the CRDs explicitly labeled for move, e.g. CRDs of
+	// external resources like IP pools or credentials not installed by clusterctl.
+	for _, crd := range allCRDs.Items {
+		for _, label := range []string{clusterctlv1.ClusterctlLabel, clusterctlv1.ClusterctlMoveLabel, clusterctlv1.ClusterctlMoveHierarchyLabel} {
+			if _, ok := crd.Labels[label]; ok {
+				crdList.Items = append(crdList.Items, crd)
+				break
+			}
+		}
+	}
 	return nil
 }
 
@@ -635,6 +694,32 @@ func (o *objectGraph) setSoftOwnership() {
 			}
 		}
 	}
+
+	// Objects with the move-parents annotation are soft owned by the parents defined in the annotation;
+	// parents must be in the same namespace of the object or global objects.
+	nodes := o.getNodes()
+	for _, n := range nodes {
+		parents, ok := n.additionalInfo[moveParentsKey].([]moveParentRef)
+		if !ok {
+			continue
+		}
+
+		for _, parent := range parents {
+			found := false
+			for _, other := range nodes {
+				if other == n || other.virtual || !parent.matches(other) {
+					continue
+				}
+				if other.identity.Namespace == n.identity.Namespace || other.isGlobal {
+					n.addSoftOwner(other)
+					found = true
+				}
+			}
+			if !found {
+				log.V(5).Info("Parent defined in the move-parents annotation not found", "kind", n.identity.Kind, "name", n.identity.Name, "namespace", n.identity.Namespace, "parent", fmt.Sprintf("%s/%s", parent.groupKind, parent.name))
+			}
+		}
+	}
 }
 
 // setTenants identifies all the nodes linked to a parent with forceMoveHierarchy = true (e.g. Clusters or ClusterResourceSet)
@@ -23,8 +23,10 @@ import (
 
 	. "github.com/onsi/gomega"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -182,6 +184,45 @@ func TestObjectGraph_getDiscoveryTypeMetaList(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Include CRDs not installed by clusterctl only if labeled for move",
+			fields: fields{
+				proxy: test.NewFakeProxy().
+					WithObjs(
+						func() client.Object {
+							crd := test.FakeNamespacedCustomResourceDefinition("foo", "Bar", "v1")
+							crd.Labels = map[string]string{clusterctlv1.ClusterctlMoveLabel: ""}
+							return crd
+						}(),
+						func() client.Object {
+							crd := test.FakeNamespacedCustomResourceDefinition("foo", "Baz", "v1")
+							crd.Labels = nil
+							return crd
+						}(),
+					),
+			},
+			want: map[string]*discoveryTypeInfo{
+				"bars.foo": {
+					typeMeta:           metav1.TypeMeta{Kind: "Bar", APIVersion: "foo/v1"},
+					forceMove:          true,
+					forceMoveHierarchy: false,
+					scope:              "Namespaced",
+				},
+				"secrets.v1": {
+					typeMeta:           metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+					forceMove:          false,
+					forceMoveHierarchy: false,
+					scope:              "",
+				},
+				"configmaps.v1": {
+					typeMeta:           metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+					forceMove:          false,
+					forceMoveHierarchy: false,
+					scope:              "",
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "Identified force move hierarchy label",
 			fields: fields{
@@ -2114,6 +2155,90 @@ func Test_objectGraph_setSoftOwnership(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Objects with the move-parents annotation are soft owned by their parents",
+			fields: fields{
+				objs: func() []client.Object {
+					objs := test.NewFakeCluster("ns1", "cluster1").Objs()
+					objs = append(objs, test.NewFakeCluster("ns2", "cluster1").Objs()...)
+					objs = append(objs,
+						&corev1.Secret{
+							TypeMeta: metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+							ObjectMeta: metav1.ObjectMeta{
+								Name:        "credentials",
+								Namespace:   "ns1",
+								UID:         "/v1, Kind=Secret, ns1/credentials",
+								Annotations: map[string]string{clusterctlv1.MoveParentsAnnotation: "Cluster.cluster.x-k8s.io/cluster1"},
+							},
+						},
+						&corev1.ConfigMap{
+							TypeMeta: metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+							ObjectMeta: metav1.ObjectMeta{
+								Name:        "ip-pool",
+								Namespace:   "ns1",
+								UID:         "/v1, Kind=ConfigMap, ns1/ip-pool",
+								Annotations: map[string]string{clusterctlv1.MoveParentsAnnotation: "Cluster/cluster1, Secret/credentials, Cluster/does-not-exist"},
+							},
+						},
+					)
+
+					return objs
+				}(),
+			},
+			want: wantGraph{
+				nodes: map[string]wantGraphItem{
+					"cluster.x-k8s.io/v1beta1, Kind=Cluster, ns1/cluster1": {
+						forceMove:          true,
+						forceMoveHierarchy: true,
+					},
+					"infrastructure.cluster.x-k8s.io/v1beta1, Kind=GenericInfrastructureCluster, ns1/cluster1": {
+						owners: []string{
+							"cluster.x-k8s.io/v1beta1, Kind=Cluster, ns1/cluster1",
+						},
+					},
+					"/v1, Kind=Secret, ns1/cluster1-ca": {
+						softOwners: []string{
+							"cluster.x-k8s.io/v1beta1, Kind=Cluster, ns1/cluster1",
+						},
+					},
+					"/v1, Kind=Secret, ns1/cluster1-kubeconfig": {
+						owners: []string{
+							"cluster.x-k8s.io/v1beta1, Kind=Cluster, ns1/cluster1",
+						},
+					},
+					"cluster.x-k8s.io/v1beta1, Kind=Cluster, ns2/cluster1": {
+						forceMove:          true,
+						forceMoveHierarchy: true,
+					},
+					"infrastructure.cluster.x-k8s.io/v1beta1, Kind=GenericInfrastructureCluster, ns2/cluster1": {
+						owners: []string{
+							"cluster.x-k8s.io/v1beta1, Kind=Cluster, ns2/cluster1",
+						},
+					},
+					"/v1, Kind=Secret, ns2/cluster1-ca": {
+						softOwners: []string{
+							"cluster.x-k8s.io/v1beta1, Kind=Cluster, ns2/cluster1",
+						},
+					},
+					"/v1, Kind=Secret, ns2/cluster1-kubeconfig": {
+						owners: []string{
+							"cluster.x-k8s.io/v1beta1, Kind=Cluster, ns2/cluster1",
+						},
+					},
+					"/v1, Kind=Secret, ns1/credentials": {
+						softOwners: []string{
+							"cluster.x-k8s.io/v1beta1, Kind=Cluster, ns1/cluster1", // NB. the cluster in ns2 is not a parent, because it is in another namespace
+						},
+					},
+					"/v1, Kind=ConfigMap, ns1/ip-pool": {
+						softOwners: []string{
+							"cluster.x-k8s.io/v1beta1, Kind=Cluster, ns1/cluster1",
+							"/v1, Kind=Secret, ns1/credentials", // NB. parents not existing are ignored
+						},
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -2129,6 +2254,54 @@ func Test_objectGraph_setSoftOwnership(t *testing.T) {
 	}
 }
 
+func Test_parseMoveParents(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    []moveParentRef
+		wantErr bool
+	}{
+		{
+			name:  "Parent with group",
+			value: "Cluster.cluster.x-k8s.io/cluster1",
+			want: []moveParentRef{
+				{groupKind: schema.GroupKind{Group: "cluster.x-k8s.io", Kind: "Cluster"}, name: "cluster1"},
+			},
+		},
+		{
+			name:  "Multiple parents with and without group",
+			value: "Cluster/cluster1, GenericIPPool.ipam.cluster.x-k8s.io/pool1,",
+			want: []moveParentRef{
+				{groupKind: schema.GroupKind{Kind: "Cluster"}, name: "cluster1"},
+				{groupKind: schema.GroupKind{Group: "ipam.cluster.x-k8s.io", Kind: "GenericIPPool"}, name: "pool1"},
+			},
+		},
+		{
+			name:    "Fails for parents without name",
+			value:   "Cluster",
+			wantErr: true,
+		},
+		{
+			name:    "Fails for parents without kind",
+			value:   "/cluster1",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := parseMoveParents(tt.value)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
 func Test_objectGraph_setClusterTenants(t *testing.T) {
 	type fields struct {
 		objs []client.Object
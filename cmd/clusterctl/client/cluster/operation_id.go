@@ -0,0 +1,58 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"os"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+	"sigs.k8s.io/cluster-api/util/annotations"
+)
+
+// OperationIDEnvVar is the environment variable that can be used to set the ID of a clusterctl operation,
+// e.g. to correlate clusterctl invocations with the CI job running them.
+const OperationIDEnvVar = "CLUSTERCTL_OPERATION_ID"
+
+var (
+	operationID     string
+	operationIDOnce sync.Once
+)
+
+// OperationID returns the ID of the current clusterctl invocation.
+// The ID is generated the first time it is required, unless it is set using the CLUSTERCTL_OPERATION_ID
+// environment variable, and it is then shared by all the actions performed by this invocation.
+func OperationID() string {
+	operationIDOnce.Do(func() {
+		operationID = os.Getenv(OperationIDEnvVar)
+		if operationID == "" {
+			operationID = string(uuid.NewUUID())
+		}
+		logf.Log.Info("Using operation ID", "ID", operationID)
+	})
+	return operationID
+}
+
+// SetOperationIDAnnotation records the ID of the current clusterctl invocation on the object, so the controllers
+// reconciling the object can add it to their logs.
+func SetOperationIDAnnotation(obj metav1.Object) {
+	annotations.AddAnnotations(obj, map[string]string{clusterv1.OperationIDAnnotation: OperationID()})
+}
@@ -80,6 +80,10 @@ type TopologyPlanInput struct {
 	Objs              []*unstructured.Unstructured
 	TargetClusterName string
 	TargetNamespace   string
+
+	// AllClusters runs the topology reconciler on all the affected Clusters, and reports the Machine
+	// rollouts each of them would go through in TopologyPlanOutput.Rollouts.
+	AllClusters bool
 }
 
 // PatchSummary defined the patch observed on an object.
@@ -102,6 +106,9 @@ type TopologyPlanOutput struct {
 	// ChangeSummary is the full list of changes (objects created, modified and deleted) observed
 	// on the ReconciledCluster. ChangeSummary is empty if ReconciledCluster is empty.
 	*ChangeSummary
+	// Rollouts is the list of Machine rollouts each of the affected Clusters would go through.
+	// Rollouts is only computed if TopologyPlanInput.AllClusters is set.
+	Rollouts []ClusterRollout
 }
 
 // Plan performs a dry run execution of the topology reconciler using the given inputs.
@@ -193,6 +200,24 @@ func (t *topologyClient) Plan(in *TopologyPlanInput) (*TopologyPlanOutput, error
 		targetCluster = &affectedClusters[0]
 	}
 
+	if in.AllClusters {
+		// Run the topology reconciler on each of the affected clusters, each time with a new dry run client,
+		// so the changes observed on a cluster do not leak into the ones observed on the other clusters.
+		res.Rollouts = []ClusterRollout{}
+		for _, cluster := range affectedClusters {
+			changes, rollout, err := t.dryRunReconcile(ctx, dryrun.NewClient(c, objs), cluster)
+			if err != nil {
+				return nil, err
+			}
+			res.Rollouts = append(res.Rollouts, *rollout)
+			if targetCluster != nil && *targetCluster == cluster {
+				res.ReconciledCluster = targetCluster
+				res.ChangeSummary = changes
+			}
+		}
+		return res, nil
+	}
+
 	if targetCluster == nil {
 		// There is no target cluster, return here. We will
 		// not generate a full change summary.
@@ -200,25 +225,45 @@ func (t *topologyClient) Plan(in *TopologyPlanInput) (*TopologyPlanOutput, error
 	}
 
 	res.ReconciledCluster = targetCluster
+	changes, _, err := t.dryRunReconcile(ctx, dryRunClient, *targetCluster)
+	if err != nil {
+		return nil, err
+	}
+	res.ChangeSummary = changes
+
+	return res, nil
+}
+
+// dryRunReconcile runs the topology reconciler on the cluster using the given dry run client.
+// It returns the changes observed during the execution and the Machine rollouts they would trigger.
+func (t *topologyClient) dryRunReconcile(ctx context.Context, dryRunClient *dryrun.Client, cluster client.ObjectKey) (*ChangeSummary, *ClusterRollout, error) {
 	reconciler := &clustertopologycontroller.Reconciler{
 		Client:                    dryRunClient,
 		APIReader:                 dryRunClient,
 		UnstructuredCachingClient: dryRunClient,
 	}
 	reconciler.SetupForDryRun(&noOpRecorder{})
-	request := reconcile.Request{NamespacedName: *targetCluster}
+	request := reconcile.Request{NamespacedName: cluster}
 	// Run the topology reconciler.
 	if _, err := reconciler.Reconcile(ctx, request); err != nil {
-		return nil, errors.Wrap(err, "failed to dry run the topology controller")
+		return nil, nil, errors.Wrap(err, "failed to dry run the topology controller")
 	}
 	// Calculate changes observed by dry run client.
 	changes, err := dryRunClient.Changes(ctx)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get changes made by the topology controller")
+		return nil, nil, errors.Wrap(err, "failed to get changes made by the topology controller")
 	}
-	res.ChangeSummary = changes
 
-	return res, nil
+	// Calculate the rollouts triggered by the changes.
+	c := &clusterv1.Cluster{}
+	if err := dryRunClient.Get(ctx, cluster, c); err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to get Cluster %s", cluster)
+	}
+	rollout, err := computeClusterRollout(c, changes)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to compute rollouts of Cluster %s", cluster)
+	}
+	return changes, rollout, nil
 }
 
 // validateInput checks that the topology plan input does not violate any of the below expectations:
@@ -0,0 +1,147 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/controllers/machinedeployment/mdutil"
+)
+
+// controlPlaneInPlaceFields are the fields of the control plane spec that are propagated in-place to
+// the control plane Machines, or that do not change the Machines at all, and thus do not trigger a rollout.
+var controlPlaneInPlaceFields = [][]string{
+	{"spec", "replicas"},
+	{"spec", "machineTemplate", "metadata"},
+	{"spec", "machineTemplate", "nodeDrainTimeout"},
+	{"spec", "machineTemplate", "nodeVolumeDetachTimeout"},
+	{"spec", "machineTemplate", "nodeDeletionTimeout"},
+}
+
+// ClusterRollout defines the Machine rollouts that would be triggered on a Cluster by the input of the plan operation.
+type ClusterRollout struct {
+	// Cluster is the Cluster which would go through the rollouts.
+	Cluster client.ObjectKey
+
+	// ControlPlane is the reference to the control plane of the Cluster if the control plane Machines would be rolled out.
+	ControlPlane *corev1.ObjectReference
+
+	// MachineDeployments is the list of MachineDeployments of the Cluster whose Machines would be rolled out.
+	MachineDeployments []client.ObjectKey
+}
+
+// HasRollouts returns true if the control plane or any of the MachineDeployments of the Cluster would be rolled out.
+func (r *ClusterRollout) HasRollouts() bool {
+	return r.ControlPlane != nil || len(r.MachineDeployments) > 0
+}
+
+// computeClusterRollout computes the rollouts a Cluster would go through given the changes observed
+// when dry running the topology reconciler on it.
+func computeClusterRollout(cluster *clusterv1.Cluster, changes *ChangeSummary) (*ClusterRollout, error) {
+	rollout := &ClusterRollout{
+		Cluster: client.ObjectKeyFromObject(cluster),
+	}
+
+	for _, m := range changes.Modified {
+		switch {
+		case m.After.GroupVersionKind().GroupKind() == clusterv1.GroupVersion.WithKind("MachineDeployment").GroupKind():
+			rolling, err := machineDeploymentRollsOut(m)
+			if err != nil {
+				return nil, err
+			}
+			if rolling {
+				rollout.MachineDeployments = append(rollout.MachineDeployments, client.ObjectKeyFromObject(m.After))
+			}
+		case isControlPlane(cluster, m.After):
+			rolling, err := controlPlaneRollsOut(m)
+			if err != nil {
+				return nil, err
+			}
+			if rolling {
+				rollout.ControlPlane = objToRef(m.After)
+			}
+		}
+	}
+
+	sort.Slice(rollout.MachineDeployments, func(i, j int) bool {
+		return rollout.MachineDeployments[i].Name < rollout.MachineDeployments[j].Name
+	})
+	return rollout, nil
+}
+
+// isControlPlane returns true if the object is the control plane of the Cluster.
+func isControlPlane(cluster *clusterv1.Cluster, obj *unstructured.Unstructured) bool {
+	ref := cluster.Spec.ControlPlaneRef
+	if ref == nil {
+		return false
+	}
+	return ref.GroupVersionKind().GroupKind() == obj.GroupVersionKind().GroupKind() &&
+		ref.Namespace == obj.GetNamespace() &&
+		ref.Name == obj.GetName()
+}
+
+// machineDeploymentRollsOut returns true if the changes to the MachineDeployment would trigger a rollout
+// of its Machines, i.e. if the Machine template changed in fields which are not propagated in-place.
+func machineDeploymentRollsOut(patch *PatchSummary) (bool, error) {
+	before := &clusterv1.MachineDeployment{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(patch.Before.Object, before); err != nil {
+		return false, errors.Wrapf(err, "failed to convert MachineDeployment %s", client.ObjectKeyFromObject(patch.Before))
+	}
+	after := &clusterv1.MachineDeployment{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(patch.After.Object, after); err != nil {
+		return false, errors.Wrapf(err, "failed to convert MachineDeployment %s", client.ObjectKeyFromObject(patch.After))
+	}
+	return !mdutil.EqualMachineTemplate(&before.Spec.Template, &after.Spec.Template), nil
+}
+
+// controlPlaneRollsOut returns true if the changes to the control plane would trigger a rollout of its
+// Machines, i.e. if the spec of the control plane changed in fields which are not propagated in-place.
+//
+// NOTE: As the plan operation can't know the rollout logic of each control plane provider, this func
+// assumes that any change to the spec, except the ones to the well known in-place fields, triggers a rollout.
+func controlPlaneRollsOut(patch *PatchSummary) (bool, error) {
+	before, err := controlPlaneRolloutFields(patch.Before)
+	if err != nil {
+		return false, err
+	}
+	after, err := controlPlaneRolloutFields(patch.After)
+	if err != nil {
+		return false, err
+	}
+	return !equality.Semantic.DeepEqual(before, after), nil
+}
+
+// controlPlaneRolloutFields returns the spec of the control plane without the in-place fields.
+func controlPlaneRolloutFields(obj *unstructured.Unstructured) (map[string]interface{}, error) {
+	objCopy := obj.DeepCopy()
+	for _, path := range controlPlaneInPlaceFields {
+		unstructured.RemoveNestedField(objCopy.Object, path...)
+	}
+	spec, _, err := unstructured.NestedMap(objCopy.Object, "spec")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get spec of %s %s", obj.GetKind(), client.ObjectKeyFromObject(obj))
+	}
+	return spec, nil
+}
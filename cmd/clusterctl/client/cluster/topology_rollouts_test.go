@@ -0,0 +1,149 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func Test_computeClusterRollout(t *testing.T) {
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "my-cluster"},
+		Spec: clusterv1.ClusterSpec{
+			ControlPlaneRef: &corev1.ObjectReference{
+				APIVersion: "controlplane.cluster.x-k8s.io/v1beta1",
+				Kind:       "KubeadmControlPlane",
+				Namespace:  "default",
+				Name:       "my-cluster-cp",
+			},
+		},
+	}
+
+	controlPlane := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "controlplane.cluster.x-k8s.io/v1beta1",
+		"kind":       "KubeadmControlPlane",
+		"metadata": map[string]interface{}{
+			"namespace": "default",
+			"name":      "my-cluster-cp",
+		},
+		"spec": map[string]interface{}{
+			"replicas": int64(1),
+			"version":  "v1.27.0",
+			"machineTemplate": map[string]interface{}{
+				"metadata": map[string]interface{}{},
+			},
+		},
+	}}
+	controlPlaneScaled := controlPlane.DeepCopy()
+	g := NewWithT(t)
+	g.Expect(unstructured.SetNestedField(controlPlaneScaled.Object, int64(3), "spec", "replicas")).To(Succeed())
+	g.Expect(unstructured.SetNestedStringMap(controlPlaneScaled.Object, map[string]string{"foo": "bar"}, "spec", "machineTemplate", "metadata", "labels")).To(Succeed())
+	controlPlaneUpgraded := controlPlane.DeepCopy()
+	g.Expect(unstructured.SetNestedField(controlPlaneUpgraded.Object, "v1.28.0", "spec", "version")).To(Succeed())
+
+	md := &clusterv1.MachineDeployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: clusterv1.GroupVersion.String(), Kind: "MachineDeployment"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "my-cluster-md"},
+		Spec: clusterv1.MachineDeploymentSpec{
+			ClusterName: "my-cluster",
+			Replicas:    pointer.Int32(1),
+			Template: clusterv1.MachineTemplateSpec{
+				Spec: clusterv1.MachineSpec{
+					ClusterName: "my-cluster",
+					Version:     pointer.String("v1.27.0"),
+					InfrastructureRef: corev1.ObjectReference{
+						APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+						Kind:       "DockerMachineTemplate",
+						Name:       "my-cluster-md-infra",
+					},
+				},
+			},
+		},
+	}
+	mdScaled := md.DeepCopy()
+	mdScaled.Spec.Replicas = pointer.Int32(3)
+	mdScaled.Spec.Template.Labels = map[string]string{"foo": "bar"}
+	mdRotated := md.DeepCopy()
+	mdRotated.Spec.Template.Spec.InfrastructureRef.Name = "my-cluster-md-infra-rotated"
+
+	tests := []struct {
+		name     string
+		modified []*PatchSummary
+		want     *ClusterRollout
+	}{
+		{
+			name: "No rollouts if nothing changed",
+			want: &ClusterRollout{Cluster: client.ObjectKeyFromObject(cluster)},
+		},
+		{
+			name: "No rollouts if only in-place fields changed",
+			modified: []*PatchSummary{
+				{Before: controlPlane, After: controlPlaneScaled},
+				{Before: mustToUnstructuredObject(md), After: mustToUnstructuredObject(mdScaled)},
+			},
+			want: &ClusterRollout{Cluster: client.ObjectKeyFromObject(cluster)},
+		},
+		{
+			name: "Control plane rollout if the version changed",
+			modified: []*PatchSummary{
+				{Before: controlPlane, After: controlPlaneUpgraded},
+			},
+			want: &ClusterRollout{
+				Cluster:      client.ObjectKeyFromObject(cluster),
+				ControlPlane: cluster.Spec.ControlPlaneRef,
+			},
+		},
+		{
+			name: "MachineDeployment rollout if the template is rotated",
+			modified: []*PatchSummary{
+				{Before: mustToUnstructuredObject(md), After: mustToUnstructuredObject(mdRotated)},
+			},
+			want: &ClusterRollout{
+				Cluster:            client.ObjectKeyFromObject(cluster),
+				MachineDeployments: []client.ObjectKey{client.ObjectKeyFromObject(md)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := computeClusterRollout(cluster, &ChangeSummary{Modified: tt.modified})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+			g.Expect(got.HasRollouts()).To(Equal(tt.want.ControlPlane != nil || len(tt.want.MachineDeployments) > 0))
+		})
+	}
+}
+
+func mustToUnstructuredObject(obj client.Object) *unstructured.Unstructured {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		panic(err)
+	}
+	return &unstructured.Unstructured{Object: u}
+}
@@ -24,6 +24,7 @@ import (
 
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/types"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -75,6 +76,7 @@ func Test_topologyClient_Plan(t *testing.T) {
 		created                []item
 		modified               []item
 		deleted                []item
+		rollouts               []ClusterRollout
 	}
 	tests := []struct {
 		name            string
@@ -240,6 +242,58 @@ func Test_topologyClient_Plan(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Modifying an existing DockerMachineTemplate. Affects multiple clusters. All Clusters reconciled.",
+			existingObjects: mustToUnstructured(
+				mockCRDsYAML,
+				existingMyClusterClassYAML,
+				existingMyClusterYAML,
+				existingMySecondClusterYAML,
+			),
+			args: args{
+				in: &TopologyPlanInput{
+					Objs:        mustToUnstructured(modifiedDockerMachineTemplateYAML),
+					AllClusters: true,
+				},
+			},
+			want: out{
+				affectedClusters: func() []client.ObjectKey {
+					cluster := client.ObjectKey{Namespace: "default", Name: "my-cluster"}
+					cluster2 := client.ObjectKey{Namespace: "default", Name: "my-second-cluster"}
+					return []client.ObjectKey{cluster, cluster2}
+				}(),
+				affectedClusterClasses: func() []client.ObjectKey {
+					cc := client.ObjectKey{Namespace: "default", Name: "my-cluster-class"}
+					return []client.ObjectKey{cc}
+				}(),
+				modified:          []item{},
+				created:           []item{},
+				reconciledCluster: nil,
+				// Modifying the DockerMachineTemplate will result in template rotation, which rolls out
+				// the control plane of both the Clusters.
+				rollouts: []ClusterRollout{
+					{
+						Cluster: client.ObjectKey{Namespace: "default", Name: "my-cluster"},
+						ControlPlane: &corev1.ObjectReference{
+							APIVersion: "controlplane.cluster.x-k8s.io/v1beta1",
+							Kind:       "KubeadmControlPlane",
+							Namespace:  "default",
+							Name:       "my-cluster-fwbpf",
+						},
+					},
+					{
+						Cluster: client.ObjectKey{Namespace: "default", Name: "my-second-cluster"},
+						ControlPlane: &corev1.ObjectReference{
+							APIVersion: "controlplane.cluster.x-k8s.io/v1beta1",
+							Kind:       "KubeadmControlPlane",
+							Namespace:  "default",
+							Name:       "my-second-cluster-fwbpf",
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "Input with objects in different namespaces should return error",
 			args: args{
@@ -303,6 +357,9 @@ func Test_topologyClient_Plan(t *testing.T) {
 				g.Expect(*res.ReconciledCluster).To(Equal(*tt.want.reconciledCluster))
 			}
 
+			// Check the rollouts.
+			g.Expect(res.Rollouts).To(ConsistOf(tt.want.rollouts))
+
 			// Check the created objects.
 			for _, created := range tt.want.created {
 				g.Expect(res.Created).To(ContainElement(MatchTopologyPlanOutputItem(created.kind, created.namespace, created.namePrefix)))
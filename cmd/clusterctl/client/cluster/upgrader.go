@@ -449,7 +449,7 @@ func (u *providerUpgrader) doUpgrade(upgradePlan *UpgradePlan, opts UpgradeOptio
 		}
 	}
 
-	return waitForProvidersReady(InstallOptions(opts), installQueue, u.proxy)
+	return waitForProvidersReady(InstallOptions{WaitProviders: opts.WaitProviders, WaitProviderTimeout: opts.WaitProviderTimeout}, installQueue, u.proxy)
 }
 
 func (u *providerUpgrader) scaleDownProvider(provider clusterctlv1.Provider) error {
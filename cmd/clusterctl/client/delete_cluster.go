@@ -0,0 +1,453 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+)
+
+const defaultDeleteClusterTimeout = 30 * time.Minute
+
+// deleteClusterPollInterval is the interval used when waiting for the objects of a phase to be deleted.
+// NOTE: this is a variable so it can be changed in tests.
+var deleteClusterPollInterval = 10 * time.Second
+
+const (
+	// DeleteClusterWorkersPhase deletes the MachineDeployments, MachineSets, MachinePools and worker Machines of a Cluster.
+	DeleteClusterWorkersPhase = "Workers"
+
+	// DeleteClusterControlPlanePhase deletes the control plane of a Cluster, or its control plane Machines if
+	// the Cluster does not have a control plane object.
+	DeleteClusterControlPlanePhase = "ControlPlane"
+
+	// DeleteClusterInfrastructurePhase deletes the infrastructure cluster of a Cluster.
+	DeleteClusterInfrastructurePhase = "InfrastructureCluster"
+
+	// DeleteClusterClusterPhase deletes the Cluster.
+	DeleteClusterClusterPhase = "Cluster"
+)
+
+// DeleteClusterOptions carries the options supported by PlanDeleteCluster and DeleteCluster.
+type DeleteClusterOptions struct {
+	// Kubeconfig defines the kubeconfig to use for accessing the management cluster. If empty,
+	// default rules for kubeconfig discovery will be used.
+	Kubeconfig Kubeconfig
+
+	// Namespace where the Cluster exists. If unspecified, the current namespace will be used.
+	Namespace string
+
+	// ClusterName is the name of the Cluster to delete.
+	ClusterName string
+
+	// Timeout defines how long to wait for the objects of each phase to be deleted; if not set, it defaults to 30 minutes.
+	Timeout time.Duration
+
+	// Force removes the finalizers of the objects of a phase which are still not deleted after Timeout, and
+	// then waits again for them to be deleted.
+	// NOTE: removing finalizers can leave behind the infrastructure of the deleted objects, e.g. cloud resources.
+	Force bool
+}
+
+// DeleteClusterPlan is the ordered list of phases executed to delete a Cluster.
+type DeleteClusterPlan struct {
+	// Cluster to delete.
+	Cluster client.ObjectKey
+
+	// Phases executed in order; each phase waits for its objects to be deleted before the next one starts.
+	Phases []DeleteClusterPhase
+}
+
+// DeleteClusterPhase is a step of the deletion of a Cluster.
+type DeleteClusterPhase struct {
+	// Name of the phase, e.g. Workers.
+	Name string
+
+	// Objects deleted in this phase.
+	Objects []corev1.ObjectReference
+
+	// Machines is the number of Machines deleted in this phase, either directly or by deleting their owners.
+	Machines int
+}
+
+// PlanDeleteCluster returns the plan to delete a Cluster, i.e. the objects deleted in each phase.
+func (c *clusterctlClient) PlanDeleteCluster(options DeleteClusterOptions) (DeleteClusterPlan, error) {
+	ctx := context.TODO()
+
+	proxyClient, cluster, err := c.getClusterToDelete(ctx, &options)
+	if err != nil {
+		return DeleteClusterPlan{}, err
+	}
+	return planDeleteCluster(ctx, proxyClient, cluster)
+}
+
+// DeleteCluster deletes a Cluster in order: first the workers, then the control plane, the infrastructure
+// cluster and finally the Cluster itself, waiting for the objects of each phase to be deleted before moving
+// to the next one.
+func (c *clusterctlClient) DeleteCluster(options DeleteClusterOptions) error {
+	log := logf.Log
+	ctx := context.TODO()
+
+	if options.Timeout == 0 {
+		options.Timeout = defaultDeleteClusterTimeout
+	}
+
+	proxyClient, cluster, err := c.getClusterToDelete(ctx, &options)
+	if err != nil {
+		return err
+	}
+
+	// The topology controller recreates the objects of a Cluster with a managed topology if they are deleted
+	// while the Cluster is not being deleted; the Cluster controller deletes them in the same order instead.
+	if cluster.Spec.Topology != nil {
+		log.Info("Deleting Cluster with a managed topology, the Cluster controller deletes its objects", "Cluster", cluster.Name, "Namespace", cluster.Namespace)
+		if err := proxyClient.Delete(ctx, cluster); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete Cluster %s", client.ObjectKeyFromObject(cluster))
+		}
+	}
+
+	for _, phaseName := range []string{DeleteClusterWorkersPhase, DeleteClusterControlPlanePhase, DeleteClusterInfrastructurePhase, DeleteClusterClusterPhase} {
+		if err := deleteClusterPhase(ctx, proxyClient, cluster, phaseName, options); err != nil {
+			return err
+		}
+	}
+
+	log.Info("Cluster deleted", "Cluster", cluster.Name, "Namespace", cluster.Namespace)
+	return nil
+}
+
+func (c *clusterctlClient) getClusterToDelete(ctx context.Context, options *DeleteClusterOptions) (client.Client, *clusterv1.Cluster, error) {
+	if options.ClusterName == "" {
+		return nil, nil, errors.New("the name of the Cluster to delete must be set")
+	}
+
+	clusterClient, err := c.getClusterClient(options.Kubeconfig)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if options.Namespace == "" {
+		currentNamespace, err := clusterClient.Proxy().CurrentNamespace()
+		if err != nil {
+			return nil, nil, err
+		}
+		options.Namespace = currentNamespace
+	}
+
+	proxyClient, err := clusterClient.Proxy().NewClient()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cluster := &clusterv1.Cluster{}
+	key := client.ObjectKey{Namespace: options.Namespace, Name: options.ClusterName}
+	if err := proxyClient.Get(ctx, key, cluster); err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to get Cluster %s", key)
+	}
+	return proxyClient, cluster, nil
+}
+
+func planDeleteCluster(ctx context.Context, c client.Client, cluster *clusterv1.Cluster) (DeleteClusterPlan, error) {
+	plan := DeleteClusterPlan{Cluster: client.ObjectKeyFromObject(cluster)}
+	for _, phaseName := range []string{DeleteClusterWorkersPhase, DeleteClusterControlPlanePhase, DeleteClusterInfrastructurePhase, DeleteClusterClusterPhase} {
+		phase, err := getDeleteClusterPhase(ctx, c, cluster, phaseName)
+		if err != nil {
+			return DeleteClusterPlan{}, err
+		}
+		plan.Phases = append(plan.Phases, phase)
+	}
+	return plan, nil
+}
+
+// getDeleteClusterPhase returns the objects deleted in a phase and the number of Machines deleted with them.
+func getDeleteClusterPhase(ctx context.Context, c client.Client, cluster *clusterv1.Cluster, phaseName string) (DeleteClusterPhase, error) {
+	phase := DeleteClusterPhase{Name: phaseName}
+	inCluster := []client.ListOption{client.InNamespace(cluster.Namespace), client.MatchingLabels{clusterv1.ClusterNameLabel: cluster.Name}}
+
+	switch phaseName {
+	case DeleteClusterWorkersPhase:
+		machineDeployments := &clusterv1.MachineDeploymentList{}
+		if err := c.List(ctx, machineDeployments, inCluster...); err != nil {
+			return phase, errors.Wrapf(err, "failed to list MachineDeployments of Cluster %s", client.ObjectKeyFromObject(cluster))
+		}
+		for i := range machineDeployments.Items {
+			phase.Objects = append(phase.Objects, objectReference(&machineDeployments.Items[i], clusterv1.GroupVersion.WithKind("MachineDeployment")))
+		}
+
+		// MachineSets controlled by a MachineDeployment are deleted with it.
+		machineSets := &clusterv1.MachineSetList{}
+		if err := c.List(ctx, machineSets, inCluster...); err != nil {
+			return phase, errors.Wrapf(err, "failed to list MachineSets of Cluster %s", client.ObjectKeyFromObject(cluster))
+		}
+		for i := range machineSets.Items {
+			if metav1.GetControllerOf(&machineSets.Items[i]) == nil {
+				phase.Objects = append(phase.Objects, objectReference(&machineSets.Items[i], clusterv1.GroupVersion.WithKind("MachineSet")))
+			}
+		}
+
+		// MachinePools are an experimental feature, ignore them if their CRD is not installed.
+		machinePools := &expv1.MachinePoolList{}
+		if err := c.List(ctx, machinePools, inCluster...); err != nil && !meta.IsNoMatchError(err) {
+			return phase, errors.Wrapf(err, "failed to list MachinePools of Cluster %s", client.ObjectKeyFromObject(cluster))
+		}
+		for i := range machinePools.Items {
+			phase.Objects = append(phase.Objects, objectReference(&machinePools.Items[i], expv1.GroupVersion.WithKind("MachinePool")))
+		}
+
+		machines, err := listMachines(ctx, c, cluster, false)
+		if err != nil {
+			return phase, err
+		}
+		for i := range machines {
+			if metav1.GetControllerOf(&machines[i]) == nil {
+				phase.Objects = append(phase.Objects, objectReference(&machines[i], clusterv1.GroupVersion.WithKind("Machine")))
+			}
+		}
+		phase.Machines = len(machines)
+	case DeleteClusterControlPlanePhase:
+		machines, err := listMachines(ctx, c, cluster, true)
+		if err != nil {
+			return phase, err
+		}
+		if cluster.Spec.ControlPlaneRef != nil {
+			exists, err := objectExists(ctx, c, *cluster.Spec.ControlPlaneRef)
+			if err != nil {
+				return phase, err
+			}
+			if exists {
+				phase.Objects = append(phase.Objects, *cluster.Spec.ControlPlaneRef)
+			}
+		} else {
+			for i := range machines {
+				phase.Objects = append(phase.Objects, objectReference(&machines[i], clusterv1.GroupVersion.WithKind("Machine")))
+			}
+		}
+		phase.Machines = len(machines)
+	case DeleteClusterInfrastructurePhase:
+		if cluster.Spec.InfrastructureRef != nil {
+			exists, err := objectExists(ctx, c, *cluster.Spec.InfrastructureRef)
+			if err != nil {
+				return phase, err
+			}
+			if exists {
+				phase.Objects = append(phase.Objects, *cluster.Spec.InfrastructureRef)
+			}
+		}
+	case DeleteClusterClusterPhase:
+		ref := objectReference(cluster, clusterv1.GroupVersion.WithKind("Cluster"))
+		exists, err := objectExists(ctx, c, ref)
+		if err != nil {
+			return phase, err
+		}
+		if exists {
+			phase.Objects = append(phase.Objects, ref)
+		}
+	}
+	return phase, nil
+}
+
+// deleteClusterPhase deletes the objects of a phase and waits for them, and for the Machines of the phase, to be deleted.
+func deleteClusterPhase(ctx context.Context, c client.Client, cluster *clusterv1.Cluster, phaseName string, options DeleteClusterOptions) error {
+	log := logf.Log
+
+	phase, err := getDeleteClusterPhase(ctx, c, cluster, phaseName)
+	if err != nil {
+		return err
+	}
+	if len(phase.Objects) == 0 && phase.Machines == 0 {
+		log.V(1).Info("Nothing to delete", "Phase", phaseName)
+		return nil
+	}
+
+	log.Info("Deleting", "Phase", phaseName, "Objects", len(phase.Objects), "Machines", phase.Machines)
+	for _, ref := range phase.Objects {
+		if err := deleteObject(ctx, c, ref); err != nil {
+			return err
+		}
+	}
+
+	err = waitDeleteClusterPhase(ctx, c, cluster, phaseName, options.Timeout)
+	if err == nil || !options.Force {
+		return err
+	}
+
+	log.Info("Removing finalizers of the objects still to be deleted, their infrastructure could be left behind", "Phase", phaseName)
+	if err := removeDeleteClusterPhaseFinalizers(ctx, c, cluster, phaseName); err != nil {
+		return err
+	}
+	return waitDeleteClusterPhase(ctx, c, cluster, phaseName, options.Timeout)
+}
+
+// waitDeleteClusterPhase waits for the objects and the Machines of a phase to be deleted, logging progress.
+func waitDeleteClusterPhase(ctx context.Context, c client.Client, cluster *clusterv1.Cluster, phaseName string, timeout time.Duration) error {
+	log := logf.Log
+
+	lastRemaining := ""
+	err := wait.PollUntilContextTimeout(ctx, deleteClusterPollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		phase, err := getDeleteClusterPhase(ctx, c, cluster, phaseName)
+		if err != nil {
+			log.V(5).Info("Failed to get the objects to be deleted, retrying", "Phase", phaseName, "Cause", err.Error())
+			return false, nil
+		}
+		if len(phase.Objects) == 0 && phase.Machines == 0 {
+			log.Info("Deleted", "Phase", phaseName)
+			return true, nil
+		}
+		if remaining := fmt.Sprintf("%d/%d", len(phase.Objects), phase.Machines); remaining != lastRemaining {
+			log.Info("Waiting for deletion", "Phase", phaseName, "Objects", len(phase.Objects), "Machines", phase.Machines)
+			lastRemaining = remaining
+		}
+		return false, nil
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed waiting for phase %s of the deletion of Cluster %s", phaseName, client.ObjectKeyFromObject(cluster))
+	}
+	return nil
+}
+
+// removeDeleteClusterPhaseFinalizers removes the finalizers of the objects of a phase, of its Machines and of the
+// infrastructure and bootstrap objects of the Machines.
+func removeDeleteClusterPhaseFinalizers(ctx context.Context, c client.Client, cluster *clusterv1.Cluster, phaseName string) error {
+	phase, err := getDeleteClusterPhase(ctx, c, cluster, phaseName)
+	if err != nil {
+		return err
+	}
+	refs := phase.Objects
+
+	switch phaseName {
+	case DeleteClusterWorkersPhase, DeleteClusterControlPlanePhase:
+		machines, err := listMachines(ctx, c, cluster, phaseName == DeleteClusterControlPlanePhase)
+		if err != nil {
+			return err
+		}
+		for i := range machines {
+			m := &machines[i]
+			refs = append(refs, objectReference(m, clusterv1.GroupVersion.WithKind("Machine")), m.Spec.InfrastructureRef)
+			if m.Spec.Bootstrap.ConfigRef != nil {
+				refs = append(refs, *m.Spec.Bootstrap.ConfigRef)
+			}
+		}
+		if phaseName == DeleteClusterWorkersPhase {
+			machineSets := &clusterv1.MachineSetList{}
+			if err := c.List(ctx, machineSets, client.InNamespace(cluster.Namespace), client.MatchingLabels{clusterv1.ClusterNameLabel: cluster.Name}); err != nil {
+				return errors.Wrapf(err, "failed to list MachineSets of Cluster %s", client.ObjectKeyFromObject(cluster))
+			}
+			for i := range machineSets.Items {
+				refs = append(refs, objectReference(&machineSets.Items[i], clusterv1.GroupVersion.WithKind("MachineSet")))
+			}
+		}
+	}
+
+	for _, ref := range refs {
+		if ref.Name == "" {
+			continue
+		}
+		if ref.Namespace == "" {
+			ref.Namespace = cluster.Namespace
+		}
+		if err := removeFinalizers(ctx, c, ref); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listMachines lists the control plane or the worker Machines of a Cluster.
+func listMachines(ctx context.Context, c client.Client, cluster *clusterv1.Cluster, controlPlane bool) ([]clusterv1.Machine, error) {
+	machines := &clusterv1.MachineList{}
+	if err := c.List(ctx, machines, client.InNamespace(cluster.Namespace), client.MatchingLabels{clusterv1.ClusterNameLabel: cluster.Name}); err != nil {
+		return nil, errors.Wrapf(err, "failed to list Machines of Cluster %s", client.ObjectKeyFromObject(cluster))
+	}
+	ret := []clusterv1.Machine{}
+	for _, m := range machines.Items {
+		if _, isControlPlane := m.Labels[clusterv1.MachineControlPlaneLabel]; isControlPlane == controlPlane {
+			ret = append(ret, m)
+		}
+	}
+	return ret, nil
+}
+
+func objectExists(ctx context.Context, c client.Client, ref corev1.ObjectReference) (bool, error) {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion(ref.APIVersion)
+	u.SetKind(ref.Kind)
+	if err := c.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, u); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "failed to get %s %s/%s", ref.Kind, ref.Namespace, ref.Name)
+	}
+	return true, nil
+}
+
+func deleteObject(ctx context.Context, c client.Client, ref corev1.ObjectReference) error {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion(ref.APIVersion)
+	u.SetKind(ref.Kind)
+	u.SetNamespace(ref.Namespace)
+	u.SetName(ref.Name)
+	if err := c.Delete(ctx, u); err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete %s %s/%s", ref.Kind, ref.Namespace, ref.Name)
+	}
+	return nil
+}
+
+func removeFinalizers(ctx context.Context, c client.Client, ref corev1.ObjectReference) error {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion(ref.APIVersion)
+	u.SetKind(ref.Kind)
+	if err := c.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, u); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get %s %s/%s", ref.Kind, ref.Namespace, ref.Name)
+	}
+	if len(u.GetFinalizers()) == 0 {
+		return nil
+	}
+
+	patch := client.MergeFrom(u.DeepCopy())
+	u.SetFinalizers(nil)
+	if err := c.Patch(ctx, u, patch); err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to remove finalizers from %s %s/%s", ref.Kind, ref.Namespace, ref.Name)
+	}
+	return nil
+}
+
+func objectReference(obj client.Object, gvk schema.GroupVersionKind) corev1.ObjectReference {
+	return corev1.ObjectReference{
+		APIVersion: gvk.GroupVersion().String(),
+		Kind:       gvk.Kind,
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+	}
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+	fakecontrolplane "sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test/providers/controlplane"
+	fakeinfrastructure "sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test/providers/infrastructure"
+)
+
+func Test_clusterctlClient_PlanDeleteCluster(t *testing.T) {
+	g := NewWithT(t)
+
+	md := &clusterv1.MachineDeployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo-md", Namespace: "default", Labels: map[string]string{clusterv1.ClusterNameLabel: "foo"}},
+	}
+	ms := &clusterv1.MachineSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "foo-md-xyz",
+			Namespace:       "default",
+			Labels:          map[string]string{clusterv1.ClusterNameLabel: "foo"},
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: clusterv1.GroupVersion.String(), Kind: "MachineDeployment", Name: md.Name, Controller: pointer.Bool(true)}},
+		},
+	}
+	objs := append(deleteClusterObjs(),
+		md,
+		ms,
+		&clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "foo-md-xyz-abc",
+				Namespace:       "default",
+				Labels:          map[string]string{clusterv1.ClusterNameLabel: "foo"},
+				OwnerReferences: []metav1.OwnerReference{{APIVersion: clusterv1.GroupVersion.String(), Kind: "MachineSet", Name: ms.Name, Controller: pointer.Bool(true)}},
+			},
+		},
+		&clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "foo-cp-abc",
+				Namespace:       "default",
+				Labels:          map[string]string{clusterv1.ClusterNameLabel: "foo", clusterv1.MachineControlPlaneLabel: ""},
+				OwnerReferences: []metav1.OwnerReference{{APIVersion: fakecontrolplane.GroupVersion.String(), Kind: "GenericControlPlane", Name: "foo-cp", Controller: pointer.Bool(true)}},
+			},
+		},
+		// Machine of another Cluster.
+		&clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{Name: "bar-machine", Namespace: "default", Labels: map[string]string{clusterv1.ClusterNameLabel: "bar"}},
+		},
+	)
+
+	c, _ := fakeClientForDeleteCluster(objs...)
+	plan, err := c.PlanDeleteCluster(DeleteClusterOptions{
+		Kubeconfig:  Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+		Namespace:   "default",
+		ClusterName: "foo",
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(plan.Cluster).To(Equal(client.ObjectKey{Namespace: "default", Name: "foo"}))
+	g.Expect(plan.Phases).To(Equal([]DeleteClusterPhase{
+		{
+			Name: DeleteClusterWorkersPhase,
+			Objects: []corev1.ObjectReference{
+				{APIVersion: clusterv1.GroupVersion.String(), Kind: "MachineDeployment", Namespace: "default", Name: "foo-md"},
+				{APIVersion: clusterv1.GroupVersion.String(), Kind: "Machine", Namespace: "default", Name: "foo-worker"},
+			},
+			Machines: 2,
+		},
+		{
+			Name:     DeleteClusterControlPlanePhase,
+			Objects:  []corev1.ObjectReference{{APIVersion: fakecontrolplane.GroupVersion.String(), Kind: "GenericControlPlane", Namespace: "default", Name: "foo-cp"}},
+			Machines: 1,
+		},
+		{
+			Name:    DeleteClusterInfrastructurePhase,
+			Objects: []corev1.ObjectReference{{APIVersion: fakeinfrastructure.GroupVersion.String(), Kind: "GenericInfrastructureCluster", Namespace: "default", Name: "foo"}},
+		},
+		{
+			Name:    DeleteClusterClusterPhase,
+			Objects: []corev1.ObjectReference{{APIVersion: clusterv1.GroupVersion.String(), Kind: "Cluster", Namespace: "default", Name: "foo"}},
+		},
+	}))
+}
+
+func Test_clusterctlClient_DeleteCluster(t *testing.T) {
+	deleteClusterPollInterval = 10 * time.Millisecond
+
+	stuckMachine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "foo-stuck",
+			Namespace:  "default",
+			Labels:     map[string]string{clusterv1.ClusterNameLabel: "foo"},
+			Finalizers: []string{clusterv1.MachineFinalizer},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		objs    []client.Object
+		force   bool
+		wantErr bool
+	}{
+		{
+			name: "deletes the Cluster and its objects",
+			objs: deleteClusterObjs(),
+		},
+		{
+			name:    "fails if the objects of a phase are not deleted",
+			objs:    append(deleteClusterObjs(), stuckMachine.DeepCopy()),
+			wantErr: true,
+		},
+		{
+			name:  "removes the finalizers of the objects of a phase which are not deleted with force",
+			objs:  append(deleteClusterObjs(), stuckMachine.DeepCopy()),
+			force: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			c, mgmt := fakeClientForDeleteCluster(tt.objs...)
+			err := c.DeleteCluster(DeleteClusterOptions{
+				Kubeconfig:  Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+				Namespace:   "default",
+				ClusterName: "foo",
+				Timeout:     100 * time.Millisecond,
+				Force:       tt.force,
+			})
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+
+			proxyClient, err := mgmt.Proxy().NewClient()
+			g.Expect(err).ToNot(HaveOccurred())
+
+			clusters := &clusterv1.ClusterList{}
+			g.Expect(proxyClient.List(context.Background(), clusters)).To(Succeed())
+			g.Expect(clusters.Items).To(BeEmpty())
+			machines := &clusterv1.MachineList{}
+			g.Expect(proxyClient.List(context.Background(), machines)).To(Succeed())
+			g.Expect(machines.Items).To(BeEmpty())
+			infraClusters := &fakeinfrastructure.GenericInfrastructureClusterList{}
+			g.Expect(proxyClient.List(context.Background(), infraClusters)).To(Succeed())
+			g.Expect(infraClusters.Items).To(BeEmpty())
+		})
+	}
+}
+
+// deleteClusterObjs returns a Cluster with its control plane, infrastructure cluster, a MachineDeployment
+// and a worker Machine not owned by a MachineDeployment.
+func deleteClusterObjs() []client.Object {
+	return []client.Object{
+		&clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+			Spec: clusterv1.ClusterSpec{
+				ControlPlaneRef:   &corev1.ObjectReference{APIVersion: fakecontrolplane.GroupVersion.String(), Kind: "GenericControlPlane", Namespace: "default", Name: "foo-cp"},
+				InfrastructureRef: &corev1.ObjectReference{APIVersion: fakeinfrastructure.GroupVersion.String(), Kind: "GenericInfrastructureCluster", Namespace: "default", Name: "foo"},
+			},
+		},
+		&fakecontrolplane.GenericControlPlane{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-cp", Namespace: "default"},
+		},
+		&fakeinfrastructure.GenericInfrastructureCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+		},
+		&clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-worker", Namespace: "default", Labels: map[string]string{clusterv1.ClusterNameLabel: "foo"}},
+		},
+	}
+}
+
+func fakeClientForDeleteCluster(objs ...client.Object) (*fakeClient, *fakeClusterClient) {
+	core := config.NewProvider("cluster-api", "https://somewhere.com", clusterctlv1.CoreProviderType)
+	config1 := newFakeConfig().WithProvider(core)
+
+	mgmt := newFakeCluster(cluster.Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"}, config1).
+		WithProviderInventory(core.Name(), core.Type(), "v1.0.0", "cluster-api-system").
+		WithObjs(test.FakeCAPISetupObjects()...).
+		WithObjs(objs...)
+
+	return newFakeClient(config1).WithCluster(mgmt), mgmt
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+)
+
+const defaultDeleteSelfManagedTimeout = 30 * time.Minute
+
+// deleteSelfManagedPollInterval is the interval used when waiting for Clusters to be deleted.
+// NOTE: this is a variable so it can be changed in tests.
+var deleteSelfManagedPollInterval = 10 * time.Second
+
+// DeleteSelfManagedOptions carries the options supported by DeleteSelfManaged.
+type DeleteSelfManagedOptions struct {
+	// Kubeconfig defines the kubeconfig to use for accessing the self-hosted management cluster. If empty,
+	// default rules for kubeconfig discovery will be used.
+	Kubeconfig Kubeconfig
+
+	// ToKubeconfig defines the kubeconfig to use for accessing the temporary management cluster, e.g. a kind
+	// bootstrap cluster, that takes over the Cluster API objects before the self-hosted cluster is deleted.
+	// The temporary management cluster must be initialized with the same providers of the self-hosted one.
+	ToKubeconfig Kubeconfig
+
+	// Namespace where the self-hosted Cluster exists. If unspecified, the current namespace will be used.
+	Namespace string
+
+	// ClusterName is the name of the Cluster object describing the self-hosted management cluster.
+	ClusterName string
+
+	// DeleteWorkloadClusters deletes all the other Clusters after they have been moved to the temporary
+	// management cluster. If false, the other Clusters are handed off to the temporary management cluster.
+	DeleteWorkloadClusters bool
+
+	// Timeout defines how long to wait for each Cluster deletion; if not set, it defaults to 30 minutes.
+	Timeout time.Duration
+}
+
+// DeleteSelfManaged decommissions a self-hosted management cluster by moving all the Cluster API objects
+// to a temporary management cluster, optionally deleting all the workload clusters, and then deleting the
+// self-hosted cluster from the temporary management cluster.
+func (c *clusterctlClient) DeleteSelfManaged(options DeleteSelfManagedOptions) error {
+	log := logf.Log
+	ctx := context.TODO()
+
+	if options.ClusterName == "" {
+		return errors.New("the name of the self-hosted Cluster must be set")
+	}
+	if options.ToKubeconfig == (Kubeconfig{}) {
+		return errors.New("the kubeconfig for the temporary management cluster must be set")
+	}
+	if options.Timeout == 0 {
+		options.Timeout = defaultDeleteSelfManagedTimeout
+	}
+
+	fromCluster, err := c.getClusterClient(options.Kubeconfig)
+	if err != nil {
+		return err
+	}
+	toCluster, err := c.getClusterClient(options.ToKubeconfig)
+	if err != nil {
+		return err
+	}
+
+	if options.Namespace == "" {
+		currentNamespace, err := fromCluster.Proxy().CurrentNamespace()
+		if err != nil {
+			return err
+		}
+		options.Namespace = currentNamespace
+	}
+
+	fromClient, err := fromCluster.Proxy().NewClient()
+	if err != nil {
+		return err
+	}
+	toClient, err := toCluster.Proxy().NewClient()
+	if err != nil {
+		return err
+	}
+
+	selfHostedKey := client.ObjectKey{Namespace: options.Namespace, Name: options.ClusterName}
+	log.Info("Checking the self-hosted management cluster", "Cluster", selfHostedKey.Name, "Namespace", selfHostedKey.Namespace)
+	if err := checkSelfHosted(ctx, fromClient, selfHostedKey); err != nil {
+		return err
+	}
+
+	// Move all the Cluster API objects, including the self-hosted Cluster, to the temporary management cluster;
+	// from now on all the Clusters are managed from there.
+	log.Info("Moving all the Cluster API objects to the temporary management cluster")
+	if err := fromCluster.ObjectMover().Move("", toCluster, false, nil); err != nil {
+		return errors.Wrap(err, "failed to move Cluster API objects to the temporary management cluster")
+	}
+
+	clusters := &clusterv1.ClusterList{}
+	if err := toClient.List(ctx, clusters); err != nil {
+		return errors.Wrap(err, "failed to list Clusters in the temporary management cluster")
+	}
+	for i := range clusters.Items {
+		workloadCluster := &clusters.Items[i]
+		if client.ObjectKeyFromObject(workloadCluster) == selfHostedKey {
+			continue
+		}
+		if !options.DeleteWorkloadClusters {
+			log.Info("Handing off Cluster to the temporary management cluster", "Cluster", workloadCluster.Name, "Namespace", workloadCluster.Namespace)
+			continue
+		}
+		if err := deleteClusterAndWait(ctx, toClient, workloadCluster, options.Timeout); err != nil {
+			return err
+		}
+	}
+
+	selfHosted := &clusterv1.Cluster{}
+	if err := toClient.Get(ctx, selfHostedKey, selfHosted); err != nil {
+		return errors.Wrapf(err, "failed to get Cluster %s from the temporary management cluster", selfHostedKey)
+	}
+	return deleteClusterAndWait(ctx, toClient, selfHosted, options.Timeout)
+}
+
+// checkSelfHosted checks that the Cluster exists and that at least one of its Machines is a Node of the
+// cluster it is read from, which means the Cluster is the self-hosted management cluster itself.
+func checkSelfHosted(ctx context.Context, c client.Client, key client.ObjectKey) error {
+	cluster := &clusterv1.Cluster{}
+	if err := c.Get(ctx, key, cluster); err != nil {
+		return errors.Wrapf(err, "failed to get Cluster %s", key)
+	}
+
+	machines := &clusterv1.MachineList{}
+	if err := c.List(ctx, machines, client.InNamespace(key.Namespace), client.MatchingLabels{clusterv1.ClusterNameLabel: key.Name}); err != nil {
+		return errors.Wrapf(err, "failed to list Machines for Cluster %s", key)
+	}
+	nodeNames := sets.Set[string]{}
+	for _, m := range machines.Items {
+		if m.Status.NodeRef != nil {
+			nodeNames.Insert(m.Status.NodeRef.Name)
+		}
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := c.List(ctx, nodes); err != nil {
+		return errors.Wrap(err, "failed to list Nodes")
+	}
+	for _, n := range nodes.Items {
+		if nodeNames.Has(n.Name) {
+			return nil
+		}
+	}
+	return errors.Errorf("Cluster %s is not a self-hosted management cluster: none of its Machines is a Node of the management cluster", key)
+}
+
+// deleteClusterAndWait deletes a Cluster and waits for the deletion to complete.
+func deleteClusterAndWait(ctx context.Context, c client.Client, cluster *clusterv1.Cluster, timeout time.Duration) error {
+	log := logf.Log
+
+	key := client.ObjectKeyFromObject(cluster)
+	log.Info("Deleting Cluster", "Cluster", cluster.Name, "Namespace", cluster.Namespace)
+	if err := c.Delete(ctx, cluster); err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete Cluster %s", key)
+	}
+
+	err := wait.PollUntilContextTimeout(ctx, deleteSelfManagedPollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		if err := c.Get(ctx, key, &clusterv1.Cluster{}); err != nil {
+			if apierrors.IsNotFound(err) {
+				return true, nil
+			}
+			log.V(5).Info("Failed to get Cluster, retrying", "Cluster", cluster.Name, "Namespace", cluster.Namespace, "Cause", err.Error())
+			return false, nil
+		}
+		log.V(1).Info("Waiting for Cluster to be deleted", "Cluster", cluster.Name, "Namespace", cluster.Namespace)
+		return false, nil
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed waiting for Cluster %s to be deleted", key)
+	}
+	log.Info("Cluster deleted", "Cluster", cluster.Name, "Namespace", cluster.Namespace)
+	return nil
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+func Test_clusterctlClient_DeleteSelfManaged(t *testing.T) {
+	deleteSelfManagedPollInterval = 10 * time.Millisecond
+
+	tests := []struct {
+		name               string
+		options            DeleteSelfManagedOptions
+		sourceObjs         []client.Object
+		wantTargetClusters []string
+		wantErr            bool
+	}{
+		{
+			name: "hands off workload clusters and deletes the self-hosted cluster",
+			options: DeleteSelfManagedOptions{
+				Kubeconfig:   Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+				ToKubeconfig: Kubeconfig{Path: "kubeconfig", Context: "bootstrap-context"},
+				Namespace:    "default",
+				ClusterName:  "mgmt",
+			},
+			sourceObjs:         selfHostedObjs("mgmt", "mgmt-node", "mgmt-node"),
+			wantTargetClusters: []string{"workload"},
+		},
+		{
+			name: "deletes workload clusters and the self-hosted cluster",
+			options: DeleteSelfManagedOptions{
+				Kubeconfig:             Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+				ToKubeconfig:           Kubeconfig{Path: "kubeconfig", Context: "bootstrap-context"},
+				Namespace:              "default",
+				ClusterName:            "mgmt",
+				DeleteWorkloadClusters: true,
+			},
+			sourceObjs:         selfHostedObjs("mgmt", "mgmt-node", "mgmt-node"),
+			wantTargetClusters: []string{},
+		},
+		{
+			name: "fails if the Cluster is not the self-hosted management cluster",
+			options: DeleteSelfManagedOptions{
+				Kubeconfig:   Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+				ToKubeconfig: Kubeconfig{Path: "kubeconfig", Context: "bootstrap-context"},
+				Namespace:    "default",
+				ClusterName:  "mgmt",
+			},
+			sourceObjs:         selfHostedObjs("mgmt", "mgmt-node", "another-node"),
+			wantTargetClusters: []string{"mgmt", "workload"},
+			wantErr:            true,
+		},
+		{
+			name: "fails if the Cluster does not exist",
+			options: DeleteSelfManagedOptions{
+				Kubeconfig:   Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+				ToKubeconfig: Kubeconfig{Path: "kubeconfig", Context: "bootstrap-context"},
+				Namespace:    "default",
+				ClusterName:  "does-not-exist",
+			},
+			sourceObjs:         selfHostedObjs("mgmt", "mgmt-node", "mgmt-node"),
+			wantTargetClusters: []string{"mgmt", "workload"},
+			wantErr:            true,
+		},
+		{
+			name: "fails if the temporary management cluster is not set",
+			options: DeleteSelfManagedOptions{
+				Kubeconfig:  Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+				Namespace:   "default",
+				ClusterName: "mgmt",
+			},
+			sourceObjs:         selfHostedObjs("mgmt", "mgmt-node", "mgmt-node"),
+			wantTargetClusters: []string{"mgmt", "workload"},
+			wantErr:            true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			c, target := fakeClientForDeleteSelfManaged(tt.sourceObjs)
+
+			err := c.DeleteSelfManaged(tt.options)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+
+			// NOTE: the fake object mover does not move objects, so the target cluster is initialized with
+			// the Clusters existing after the move.
+			targetClient, err := target.Proxy().NewClient()
+			g.Expect(err).ToNot(HaveOccurred())
+			clusters := &clusterv1.ClusterList{}
+			g.Expect(targetClient.List(context.Background(), clusters)).To(Succeed())
+			names := []string{}
+			for _, c := range clusters.Items {
+				names = append(names, c.Name)
+			}
+			g.Expect(names).To(ConsistOf(tt.wantTargetClusters))
+		})
+	}
+}
+
+// selfHostedObjs returns a Cluster with a Machine hosted on machineNode, and a Node named node.
+func selfHostedObjs(clusterName, machineNode, node string) []client.Object {
+	return []client.Object{
+		&clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: clusterName, Namespace: "default"},
+		},
+		&clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      clusterName + "-machine",
+				Namespace: "default",
+				Labels:    map[string]string{clusterv1.ClusterNameLabel: clusterName},
+			},
+			Spec: clusterv1.MachineSpec{ClusterName: clusterName},
+			Status: clusterv1.MachineStatus{
+				NodeRef: &corev1.ObjectReference{Kind: "Node", Name: machineNode},
+			},
+		},
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: node},
+		},
+	}
+}
+
+func fakeClientForDeleteSelfManaged(sourceObjs []client.Object) (*fakeClient, *fakeClusterClient) {
+	core := config.NewProvider("cluster-api", "https://somewhere.com", clusterctlv1.CoreProviderType)
+	infra := config.NewProvider("infra", "https://somewhere.com", clusterctlv1.InfrastructureProviderType)
+
+	config1 := newFakeConfig().
+		WithProvider(core).
+		WithProvider(infra)
+
+	source := newFakeCluster(cluster.Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"}, config1).
+		WithProviderInventory(core.Name(), core.Type(), "v1.0.0", "cluster-api-system").
+		WithProviderInventory(infra.Name(), infra.Type(), "v2.0.0", "infra-system").
+		WithObjectMover(&fakeObjectMover{}).
+		WithObjs(test.FakeCAPISetupObjects()...).
+		WithObjs(sourceObjs...)
+
+	target := newFakeCluster(cluster.Kubeconfig{Path: "kubeconfig", Context: "bootstrap-context"}, config1).
+		WithProviderInventory(core.Name(), core.Type(), "v1.0.0", "cluster-api-system").
+		WithProviderInventory(infra.Name(), infra.Type(), "v2.0.0", "infra-system").
+		WithObjs(test.FakeCAPISetupObjects()...).
+		WithObjs(
+			&clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "mgmt", Namespace: "default"}},
+			&clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "workload", Namespace: "default"}},
+		)
+
+	client := newFakeClient(config1).
+		WithCluster(source).
+		WithCluster(target)
+
+	return client, target
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package client
 
 import (
+	"fmt"
 	"sort"
 	"time"
 
@@ -75,6 +76,11 @@ type InitOptions struct {
 	// WaitProviderTimeout sets the timeout per provider wait installation
 	WaitProviderTimeout time.Duration
 
+	// VerifyProviders instructs the init command to wait till the providers are healthy, i.e. CRDs are established,
+	// certificates are issued, managers are available and webhooks can be called, and to log a health report.
+	// It implies WaitProviders.
+	VerifyProviders bool
+
 	// SkipTemplateProcess allows for skipping the call to the template processor, including also variable replacement in the component YAML.
 	// NOTE this works only if the rawYaml is a valid yaml by itself, like e.g when using envsubst/the simple processor.
 	skipTemplateProcess bool
@@ -148,8 +154,12 @@ func (c *clusterctlClient) Init(options InitOptions) ([]Components, error) {
 	installOpts := cluster.InstallOptions{
 		WaitProviders:       options.WaitProviders,
 		WaitProviderTimeout: options.WaitProviderTimeout,
+		VerifyProviders:     options.VerifyProviders,
 	}
 	components, err := installer.Install(installOpts)
+	if options.VerifyProviders {
+		logProvidersHealthReport(installer.HealthReport())
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -173,6 +183,32 @@ func (c *clusterctlClient) Init(options InitOptions) ([]Components, error) {
 	return aliasComponents, nil
 }
 
+// logProvidersHealthReport logs the result of the checks verifying the health of the installed providers.
+func logProvidersHealthReport(report []cluster.ProviderHealth) {
+	log := logf.Log
+	if len(report) == 0 {
+		return
+	}
+
+	log.Info("")
+	log.Info("Providers health report:")
+	for _, health := range report {
+		status := "Healthy"
+		if !health.Healthy() {
+			status = "Unhealthy"
+		}
+		log.Info(fmt.Sprintf("  %s %s (%s): %s", health.Provider, health.Version, health.TargetNamespace, status))
+		for _, check := range health.Checks {
+			result := "OK"
+			if !check.Healthy {
+				result = "FAILED"
+			}
+			log.Info(fmt.Sprintf("    [%s] %s: %s", result, check.Name, check.Message))
+		}
+	}
+	log.Info("")
+}
+
 // InitImages returns the list of images required for init.
 func (c *clusterctlClient) InitImages(options InitOptions) ([]string, error) {
 	// gets access to the management cluster
@@ -50,6 +50,10 @@ type MoveOptions struct {
 
 	// DryRun means the move action is a dry run, no real action will be performed.
 	DryRun bool
+
+	// IgnorePreflightErrors is the list of pre-flight checks run on the target cluster whose errors are reported
+	// as warnings instead of failing the move, e.g. CertManager; "all" ignores errors of all the pre-flight checks.
+	IgnorePreflightErrors []string
 }
 
 func (c *clusterctlClient) Move(options MoveOptions) error {
@@ -98,7 +102,7 @@ func (c *clusterctlClient) move(options MoveOptions) error {
 		}
 	}
 
-	return fromCluster.ObjectMover().Move(options.Namespace, toCluster, options.DryRun, options.ExperimentalResourceMutators...)
+	return fromCluster.ObjectMover().Move(options.Namespace, toCluster, options.DryRun, options.IgnorePreflightErrors, options.ExperimentalResourceMutators...)
 }
 
 func (c *clusterctlClient) fromDirectory(options MoveOptions) error {
@@ -298,7 +298,7 @@ type fakeObjectMover struct {
 	fromDirectoryErr error
 }
 
-func (f *fakeObjectMover) Move(_ string, _ cluster.Client, _ bool, _ ...cluster.ResourceMutatorFunc) error {
+func (f *fakeObjectMover) Move(_ string, _ cluster.Client, _ bool, _ []string, _ ...cluster.ResourceMutatorFunc) error {
 	return f.moveErr
 }
 
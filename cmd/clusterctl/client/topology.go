@@ -19,6 +19,8 @@ package client
 import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/alpha"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
 )
 
@@ -39,6 +41,10 @@ type TopologyPlanOptions struct {
 	// This namespace is used as default for objects with missing namespaces.
 	// If the namespace of any of the input objects conflicts with Namespace an error is returned.
 	Namespace string
+
+	// AllClusters dryrun reconciles all the clusters affected by the input, and reports the Machine rollouts
+	// each of them would go through, e.g. to check which clusters would roll out when a ClusterClass is changed.
+	AllClusters bool
 }
 
 // TopologyPlanOutput defines the output of the topology plan operation.
@@ -56,7 +62,59 @@ func (c *clusterctlClient) TopologyPlan(options TopologyPlanOptions) (*TopologyP
 		Objs:              options.Objs,
 		TargetClusterName: options.Cluster,
 		TargetNamespace:   options.Namespace,
+		AllClusters:       options.AllClusters,
 	})
 
 	return out, err
 }
+
+// TopologyAdoptOptions define options for TopologyAdopt.
+type TopologyAdoptOptions struct {
+	// Kubeconfig defines the kubeconfig to use for accessing the management cluster. If empty,
+	// default rules for kubeconfig discovery will be used.
+	Kubeconfig Kubeconfig
+
+	// ClusterName is the name of the workload cluster to attach to the ClusterClass.
+	ClusterName string
+
+	// Namespace where the workload cluster is located. If unspecified, the current namespace will be used.
+	Namespace string
+
+	// ClusterClass is the name of the ClusterClass to attach the workload cluster to.
+	ClusterClass string
+
+	// MachineDeploymentClasses maps the names of the MachineDeployments of the workload cluster to the
+	// MachineDeployment classes of the ClusterClass. It can be omitted when the ClusterClass has only one
+	// MachineDeployment class.
+	MachineDeploymentClasses map[string]string
+
+	// DryRun, if true, only computes the adopted Cluster without changing the workload cluster.
+	DryRun bool
+}
+
+// TopologyAdopt attaches a Cluster without a managed topology to a ClusterClass; the topology of the Cluster is
+// computed from its current state, and the topology controller adopts the existing objects of the Cluster.
+// It returns the adopted Cluster.
+func (c *clusterctlClient) TopologyAdopt(options TopologyAdoptOptions) (*clusterv1.Cluster, error) {
+	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
+	if err != nil {
+		return nil, err
+	}
+
+	// If the option specifying the Namespace is empty, try to detect it.
+	if options.Namespace == "" {
+		currentNamespace, err := clusterClient.Proxy().CurrentNamespace()
+		if err != nil {
+			return nil, err
+		}
+		options.Namespace = currentNamespace
+	}
+
+	return c.alphaClient.TopologyAdopt().Adopt(clusterClient.Proxy(), alpha.TopologyAdoptInput{
+		Name:                     options.ClusterName,
+		Namespace:                options.Namespace,
+		ClusterClass:             options.ClusterClass,
+		MachineDeploymentClasses: options.MachineDeploymentClasses,
+		DryRun:                   options.DryRun,
+	})
+}
@@ -25,6 +25,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/contract"
 	"sigs.k8s.io/cluster-api/util/conditions"
 )
 
@@ -56,6 +57,20 @@ func GetOtherConditions(obj client.Object) []*clusterv1.Condition {
 	return conditions
 }
 
+// GetSummary returns the summary rows an infrastructure object surfaces in status.summary, if defined.
+func GetSummary(obj client.Object) []clusterv1.SummaryRow {
+	objUnstructured, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil
+	}
+	// NOTE: InfrastructureCluster and InfrastructureMachine objects surface the summary in the same field.
+	summary, err := contract.InfrastructureMachine().Summary().Get(objUnstructured)
+	if err != nil {
+		return nil
+	}
+	return summary
+}
+
 func setReadyCondition(obj client.Object, ready *clusterv1.Condition) {
 	setter := objToSetter(obj)
 	if setter == nil {
@@ -0,0 +1,77 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"time"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/alpha"
+)
+
+// UpgradeClusterOptions carries the options supported by UpgradeCluster.
+type UpgradeClusterOptions struct {
+	// Kubeconfig defines the kubeconfig to use for accessing the management cluster. If empty,
+	// default rules for kubeconfig discovery will be used.
+	Kubeconfig Kubeconfig
+
+	// ClusterName is the name of the workload cluster to upgrade.
+	ClusterName string
+
+	// Namespace where the workload cluster is located. If unspecified, the current namespace will be used.
+	Namespace string
+
+	// KubernetesVersion is the target Kubernetes version of the workload cluster.
+	KubernetesVersion string
+
+	// PlanOnly, if true, only computes the steps of the upgrade without changing the workload cluster.
+	PlanOnly bool
+
+	// Timeout is the maximum time to wait for each step of the upgrade to complete.
+	Timeout time.Duration
+}
+
+// ClusterUpgradePlan defines the steps required to upgrade the Kubernetes version of a workload cluster.
+type ClusterUpgradePlan = alpha.ClusterUpgradePlan
+
+// UpgradeCluster upgrades the Kubernetes version of a workload cluster, first the control plane and then
+// the MachineDeployments, waiting for each step to complete; if PlanOnly is set, it only returns the plan.
+func (c *clusterctlClient) UpgradeCluster(options UpgradeClusterOptions) (*ClusterUpgradePlan, error) {
+	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
+	if err != nil {
+		return nil, err
+	}
+
+	// If the option specifying the Namespace is empty, try to detect it.
+	if options.Namespace == "" {
+		currentNamespace, err := clusterClient.Proxy().CurrentNamespace()
+		if err != nil {
+			return nil, err
+		}
+		options.Namespace = currentNamespace
+	}
+
+	input := alpha.ClusterUpgradeInput{
+		Name:              options.ClusterName,
+		Namespace:         options.Namespace,
+		KubernetesVersion: options.KubernetesVersion,
+		Timeout:           options.Timeout,
+	}
+	if options.PlanOnly {
+		return c.alphaClient.ClusterUpgrade().Plan(clusterClient.Proxy(), input)
+	}
+	return c.alphaClient.ClusterUpgrade().Apply(clusterClient.Proxy(), input)
+}
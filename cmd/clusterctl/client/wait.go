@@ -0,0 +1,261 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+	"sigs.k8s.io/cluster-api/util"
+)
+
+const defaultWaitTimeout = 10 * time.Minute
+
+// waitPollInterval is the interval used when waiting for a condition.
+// NOTE: this is a variable so it can be changed in tests.
+var waitPollInterval = 5 * time.Second
+
+// WaitForDelete is the value of WaitOptions.For to wait for resources to be deleted.
+const WaitForDelete = "delete"
+
+// WaitOptions carries the options supported by Wait.
+type WaitOptions struct {
+	// Kubeconfig defines the kubeconfig to use for accessing the management cluster. If empty,
+	// default rules for kubeconfig discovery will be used.
+	Kubeconfig Kubeconfig
+
+	// Resources to wait for, in the resource/name form, e.g. cluster/foo or kubeadmcontrolplane/foo-cp.
+	// The resource can be the kind, the singular, the plural or a short name of a Cluster API or of a provider
+	// CRD, optionally followed by the group, e.g. machines.cluster.x-k8s.io.
+	Resources []string
+
+	// Namespace where the resources live. If unspecified, the namespace name will be inferred
+	// from the current configuration.
+	Namespace string
+
+	// For is what to wait for: condition=Type to wait for a condition to be True, condition=Type=Status
+	// to wait for a condition to have the given status, or delete to wait for the resources to be deleted.
+	For string
+
+	// Timeout defines how long to wait for all the resources; if not set, it defaults to 10 minutes.
+	Timeout time.Duration
+}
+
+// waitFor is what to wait for, parsed from WaitOptions.For.
+type waitFor struct {
+	delete          bool
+	conditionType   string
+	conditionStatus corev1.ConditionStatus
+}
+
+// Wait waits for a condition of Cluster API resources, including provider resources, or for their deletion.
+// A condition is considered met only if the status of the resource is up to date, i.e. its observedGeneration,
+// if any, matches its generation.
+func (c *clusterctlClient) Wait(options WaitOptions) error {
+	log := logf.Log
+	ctx := context.TODO()
+
+	if options.Timeout == 0 {
+		options.Timeout = defaultWaitTimeout
+	}
+
+	forCondition, err := parseWaitFor(options.For)
+	if err != nil {
+		return err
+	}
+
+	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
+	if err != nil {
+		return err
+	}
+	objRefs, err := getObjectRefs(clusterClient, options.Namespace, options.Resources)
+	if err != nil {
+		return err
+	}
+	proxyClient, err := clusterClient.Proxy().NewClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, options.Timeout)
+	defer cancel()
+
+	for _, ref := range objRefs {
+		gvk, err := resolveContractResource(ctx, proxyClient, ref.Kind)
+		if err != nil {
+			return err
+		}
+		ref.SetGroupVersionKind(gvk)
+
+		if err := waitForObject(ctx, proxyClient, ref, forCondition); err != nil {
+			return err
+		}
+		log.Info("Condition met", "Kind", ref.Kind, "Name", ref.Name, "Namespace", ref.Namespace, "For", options.For)
+	}
+	return nil
+}
+
+func parseWaitFor(s string) (waitFor, error) {
+	if strings.ToLower(s) == WaitForDelete {
+		return waitFor{delete: true}, nil
+	}
+
+	condition, ok := strings.CutPrefix(s, "condition=")
+	if !ok || condition == "" {
+		return waitFor{}, errors.Errorf("invalid wait condition %q: must be condition=Type, condition=Type=Status or delete", s)
+	}
+	conditionType, status, hasStatus := strings.Cut(condition, "=")
+	ret := waitFor{conditionType: conditionType, conditionStatus: corev1.ConditionTrue}
+	if hasStatus {
+		switch {
+		case strings.EqualFold(status, string(corev1.ConditionTrue)):
+			ret.conditionStatus = corev1.ConditionTrue
+		case strings.EqualFold(status, string(corev1.ConditionFalse)):
+			ret.conditionStatus = corev1.ConditionFalse
+		case strings.EqualFold(status, string(corev1.ConditionUnknown)):
+			ret.conditionStatus = corev1.ConditionUnknown
+		default:
+			return waitFor{}, errors.Errorf("invalid wait condition %q: the status must be True, False or Unknown", s)
+		}
+	}
+	return ret, nil
+}
+
+// resolveContractResource returns the GroupVersionKind of a resource by looking at the CRDs implementing
+// the Cluster API contract, i.e. with the contract label; the version is the latest one supporting the contract.
+func resolveContractResource(ctx context.Context, c client.Client, resource string) (schema.GroupVersionKind, error) {
+	crds := &apiextensionsv1.CustomResourceDefinitionList{}
+	if err := c.List(ctx, crds, client.HasLabels{clusterv1.GroupVersion.String()}); err != nil {
+		return schema.GroupVersionKind{}, errors.Wrap(err, "failed to list the CRDs implementing the Cluster API contract")
+	}
+
+	resource = strings.ToLower(resource)
+	matches := []apiextensionsv1.CustomResourceDefinition{}
+	for _, crd := range crds.Items {
+		names := []string{crd.Spec.Names.Plural, crd.Spec.Names.Singular, strings.ToLower(crd.Spec.Names.Kind)}
+		names = append(names, crd.Spec.Names.ShortNames...)
+		for _, name := range names {
+			if name == "" {
+				continue
+			}
+			if resource == name || resource == name+"."+crd.Spec.Group {
+				matches = append(matches, crd)
+				break
+			}
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return schema.GroupVersionKind{}, errors.Errorf("resource %q is not a Cluster API or a provider resource", resource)
+	case 1:
+	default:
+		candidates := []string{}
+		for _, crd := range matches {
+			candidates = append(candidates, crd.Name)
+		}
+		sort.Strings(candidates)
+		return schema.GroupVersionKind{}, errors.Errorf("resource %q is ambiguous, use one of %s", resource, strings.Join(candidates, ", "))
+	}
+
+	crd := matches[0]
+	versions := util.KubeAwareAPIVersions(strings.Split(crd.Labels[clusterv1.GroupVersion.String()], "_"))
+	sort.Sort(versions)
+	return schema.GroupVersionKind{Group: crd.Spec.Group, Version: versions[len(versions)-1], Kind: crd.Spec.Names.Kind}, nil
+}
+
+// waitForObject waits for a condition of an object, or for its deletion, logging the current status when it changes.
+func waitForObject(ctx context.Context, c client.Client, ref corev1.ObjectReference, forCondition waitFor) error {
+	log := logf.Log
+
+	lastStatus := ""
+	err := wait.PollUntilContextCancel(ctx, waitPollInterval, true, func(ctx context.Context) (bool, error) {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(ref.GroupVersionKind())
+		if err := c.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, obj); err != nil {
+			if apierrors.IsNotFound(err) {
+				if forCondition.delete {
+					return true, nil
+				}
+				return false, errors.Errorf("%s %s/%s not found", ref.Kind, ref.Namespace, ref.Name)
+			}
+			log.V(5).Info("Failed to get object, retrying", "Kind", ref.Kind, "Name", ref.Name, "Namespace", ref.Namespace, "Cause", err.Error())
+			return false, nil
+		}
+		if forCondition.delete {
+			if lastStatus == "" {
+				log.Info("Waiting for deletion", "Kind", ref.Kind, "Name", ref.Name, "Namespace", ref.Namespace)
+				lastStatus = "waiting"
+			}
+			return false, nil
+		}
+
+		met, status := isConditionMet(obj, forCondition)
+		if status != lastStatus {
+			log.Info("Waiting for condition", "Kind", ref.Kind, "Name", ref.Name, "Namespace", ref.Namespace, "Condition", forCondition.conditionType, "Status", status)
+			lastStatus = status
+		}
+		return met, nil
+	})
+	if err != nil {
+		if wait.Interrupted(err) {
+			return errors.Errorf("timed out waiting for %s %s/%s, last status: %s", ref.Kind, ref.Namespace, ref.Name, lastStatus)
+		}
+		return err
+	}
+	return nil
+}
+
+// isConditionMet returns true if the condition of the object has the expected status and the status of
+// the object is up to date; it also returns a description of the current status of the condition.
+func isConditionMet(obj *unstructured.Unstructured, forCondition waitFor) (bool, string) {
+	if observedGeneration, found, _ := unstructured.NestedInt64(obj.Object, "status", "observedGeneration"); found && observedGeneration < obj.GetGeneration() {
+		return false, fmt.Sprintf("status not yet observed for generation %d", obj.GetGeneration())
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || !strings.EqualFold(fmt.Sprint(condition["type"]), forCondition.conditionType) {
+			continue
+		}
+		status := fmt.Sprint(condition["status"])
+		description := status
+		if reason, ok := condition["reason"].(string); ok && reason != "" {
+			description += ", " + reason
+		}
+		if message, ok := condition["message"].(string); ok && message != "" {
+			description += ": " + message
+		}
+		return status == string(forCondition.conditionStatus), description
+	}
+	return false, "condition not found"
+}
@@ -0,0 +1,289 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+func Test_parseWaitFor(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    waitFor
+		wantErr bool
+	}{
+		{
+			name: "delete",
+			s:    "delete",
+			want: waitFor{delete: true},
+		},
+		{
+			name: "condition defaults to True",
+			s:    "condition=Ready",
+			want: waitFor{conditionType: "Ready", conditionStatus: corev1.ConditionTrue},
+		},
+		{
+			name: "condition with status",
+			s:    "condition=Paused=false",
+			want: waitFor{conditionType: "Paused", conditionStatus: corev1.ConditionFalse},
+		},
+		{
+			name:    "invalid status",
+			s:       "condition=Ready=maybe",
+			wantErr: true,
+		},
+		{
+			name:    "missing condition",
+			s:       "condition=",
+			wantErr: true,
+		},
+		{
+			name:    "unknown",
+			s:       "jsonpath={.status.phase}",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := parseWaitFor(tt.s)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_resolveContractResource(t *testing.T) {
+	c := fake.NewClientBuilder().WithScheme(test.FakeScheme).WithObjects(
+		waitTestCRD("cluster.x-k8s.io", "Cluster", "clusters", "v1beta1", "cl"),
+		waitTestCRD("cluster.x-k8s.io", "MachineDeployment", "machinedeployments", "v1alpha4_v1beta1", "md"),
+		waitTestCRD("infrastructure.cluster.x-k8s.io", "DockerCluster", "dockerclusters", "v1beta1"),
+		waitTestCRD("other.cluster.x-k8s.io", "DockerCluster", "dockerclusters", "v1beta1"),
+	).Build()
+
+	tests := []struct {
+		name     string
+		resource string
+		want     schema.GroupVersionKind
+		wantErr  bool
+	}{
+		{
+			name:     "kind",
+			resource: "Cluster",
+			want:     clusterv1.GroupVersion.WithKind("Cluster"),
+		},
+		{
+			name:     "short name, latest version of the contract",
+			resource: "md",
+			want:     clusterv1.GroupVersion.WithKind("MachineDeployment"),
+		},
+		{
+			name:     "plural with group",
+			resource: "dockerclusters.infrastructure.cluster.x-k8s.io",
+			want:     schema.GroupVersionKind{Group: "infrastructure.cluster.x-k8s.io", Version: "v1beta1", Kind: "DockerCluster"},
+		},
+		{
+			name:     "ambiguous",
+			resource: "dockercluster",
+			wantErr:  true,
+		},
+		{
+			name:     "not a Cluster API resource",
+			resource: "pods",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := resolveContractResource(context.Background(), c, tt.resource)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_isConditionMet(t *testing.T) {
+	objWithCondition := func(generation, observedGeneration int64, status string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"status": map[string]interface{}{
+				"observedGeneration": observedGeneration,
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Ready", "status": status, "reason": "WaitingForNodes"},
+				},
+			},
+		}}
+		obj.SetGeneration(generation)
+		return obj
+	}
+	ready := waitFor{conditionType: "Ready", conditionStatus: corev1.ConditionTrue}
+
+	tests := []struct {
+		name       string
+		obj        *unstructured.Unstructured
+		forCond    waitFor
+		wantMet    bool
+		wantStatus string
+	}{
+		{
+			name:       "condition with the expected status",
+			obj:        objWithCondition(2, 2, "True"),
+			forCond:    ready,
+			wantMet:    true,
+			wantStatus: "True, WaitingForNodes",
+		},
+		{
+			name:       "condition with another status",
+			obj:        objWithCondition(2, 2, "False"),
+			forCond:    ready,
+			wantMet:    false,
+			wantStatus: "False, WaitingForNodes",
+		},
+		{
+			name:       "status not yet observed",
+			obj:        objWithCondition(3, 2, "True"),
+			forCond:    ready,
+			wantMet:    false,
+			wantStatus: "status not yet observed for generation 3",
+		},
+		{
+			name:       "condition not found",
+			obj:        objWithCondition(2, 2, "True"),
+			forCond:    waitFor{conditionType: "ControlPlaneReady", conditionStatus: corev1.ConditionTrue},
+			wantMet:    false,
+			wantStatus: "condition not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			met, status := isConditionMet(tt.obj, tt.forCond)
+			g.Expect(met).To(Equal(tt.wantMet))
+			g.Expect(status).To(Equal(tt.wantStatus))
+		})
+	}
+}
+
+func Test_clusterctlClient_Wait(t *testing.T) {
+	waitPollInterval = 10 * time.Millisecond
+
+	readyCluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+		Status: clusterv1.ClusterStatus{
+			Conditions: clusterv1.Conditions{
+				{Type: clusterv1.ControlPlaneReadyCondition, Status: corev1.ConditionTrue},
+			},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		resources []string
+		forCond   string
+		wantErr   bool
+	}{
+		{
+			name:      "condition met",
+			resources: []string{"cluster/foo"},
+			forCond:   "condition=ControlPlaneReady",
+		},
+		{
+			name:      "times out if the condition is not met",
+			resources: []string{"cluster/foo"},
+			forCond:   "condition=InfrastructureReady",
+			wantErr:   true,
+		},
+		{
+			name:      "fails if the resource does not exist",
+			resources: []string{"cluster/bar"},
+			forCond:   "condition=ControlPlaneReady",
+			wantErr:   true,
+		},
+		{
+			name:      "deleted",
+			resources: []string{"cluster/bar"},
+			forCond:   "delete",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			config1 := newFakeConfig()
+			mgmt := newFakeCluster(cluster.Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"}, config1).
+				WithObjs(waitTestCRD("cluster.x-k8s.io", "Cluster", "clusters", "v1beta1"), readyCluster)
+			c := newFakeClient(config1).WithCluster(mgmt)
+
+			err := c.Wait(WaitOptions{
+				Kubeconfig: Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+				Resources:  tt.resources,
+				Namespace:  "default",
+				For:        tt.forCond,
+				Timeout:    100 * time.Millisecond,
+			})
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+		})
+	}
+}
+
+func waitTestCRD(group, kind, plural, contractVersions string, shortNames ...string) client.Object {
+	return &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   plural + "." + group,
+			Labels: map[string]string{clusterv1.GroupVersion.String(): contractVersions},
+		},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: group,
+			Names: apiextensionsv1.CustomResourceDefinitionNames{
+				Kind:       kind,
+				Plural:     plural,
+				ShortNames: shortNames,
+			},
+		},
+	}
+}
@@ -31,6 +31,9 @@ func init() {
 	// Alpha commands should be added here.
 	alphaCmd.AddCommand(rolloutCmd)
 	alphaCmd.AddCommand(topologyCmd)
+	alphaCmd.AddCommand(bulkCmd)
+	alphaCmd.AddCommand(alphaUpgradeCmd)
+	alphaCmd.AddCommand(waitCmd)
 
 	RootCmd.AddCommand(alphaCmd)
 }
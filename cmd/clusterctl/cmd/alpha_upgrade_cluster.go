@@ -0,0 +1,128 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+)
+
+type alphaUpgradeClusterOptions struct {
+	kubeconfig        string
+	kubeconfigContext string
+	clusterName       string
+	namespace         string
+	kubernetesVersion string
+	plan              bool
+	timeout           time.Duration
+}
+
+var auc = &alphaUpgradeClusterOptions{}
+
+var alphaUpgradeClusterCmd = &cobra.Command{
+	Use:   "cluster",
+	Short: "Upgrade the Kubernetes version of a workload cluster",
+	Long: LongDesc(`
+		Upgrade the Kubernetes version of a workload cluster.
+
+		For clusters with a managed topology the topology version is changed, and the topology controller
+		upgrades the control plane and then the MachineDeployments. For other clusters the version of the
+		control plane is changed first and then the version of each MachineDeployment, one at a time.
+		Each step waits for the previous one to be completed.
+
+		Downgrades and upgrades skipping a Kubernetes minor version are not supported.`),
+	Example: Examples(`
+		# Upgrade the workload cluster foo to Kubernetes v1.29.2.
+		clusterctl alpha upgrade cluster --cluster foo --kubernetes-version v1.29.2
+
+		# Print the steps required to upgrade the workload cluster foo in namespace bar, without upgrading it.
+		clusterctl alpha upgrade cluster --cluster foo -n bar --kubernetes-version v1.29.2 --plan`),
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runAlphaUpgradeCluster()
+	},
+}
+
+func init() {
+	alphaUpgradeClusterCmd.Flags().StringVar(&auc.kubeconfig, "kubeconfig", "",
+		"Path to the kubeconfig for the management cluster. If unspecified, default discovery rules apply.")
+	alphaUpgradeClusterCmd.Flags().StringVar(&auc.kubeconfigContext, "kubeconfig-context", "",
+		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+
+	alphaUpgradeClusterCmd.Flags().StringVar(&auc.clusterName, "cluster", "", "name of the workload cluster to upgrade")
+	alphaUpgradeClusterCmd.Flags().StringVarP(&auc.namespace, "namespace", "n", "", "namespace of the workload cluster. If unspecified, the current namespace will be used")
+	alphaUpgradeClusterCmd.Flags().StringVar(&auc.kubernetesVersion, "kubernetes-version", "", "target Kubernetes version of the workload cluster")
+	alphaUpgradeClusterCmd.Flags().BoolVar(&auc.plan, "plan", false, "print the steps of the upgrade without upgrading the workload cluster")
+	alphaUpgradeClusterCmd.Flags().DurationVar(&auc.timeout, "timeout", 30*time.Minute, "maximum time to wait for each step of the upgrade to complete")
+
+	if err := alphaUpgradeClusterCmd.MarkFlagRequired("cluster"); err != nil {
+		panic(err)
+	}
+	if err := alphaUpgradeClusterCmd.MarkFlagRequired("kubernetes-version"); err != nil {
+		panic(err)
+	}
+
+	alphaUpgradeCmd.AddCommand(alphaUpgradeClusterCmd)
+}
+
+func runAlphaUpgradeCluster() error {
+	c, err := client.New(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	plan, err := c.UpgradeCluster(client.UpgradeClusterOptions{
+		Kubeconfig:        client.Kubeconfig{Path: auc.kubeconfig, Context: auc.kubeconfigContext},
+		ClusterName:       auc.clusterName,
+		Namespace:         auc.namespace,
+		KubernetesVersion: auc.kubernetesVersion,
+		PlanOnly:          auc.plan,
+		Timeout:           auc.timeout,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(plan.Steps) == 0 {
+		fmt.Printf("Cluster %s is already at Kubernetes version %s.\n", plan.Cluster, plan.TargetVersion)
+		return nil
+	}
+
+	if !auc.plan {
+		fmt.Printf("Cluster %s upgraded from Kubernetes version %s to %s.\n", plan.Cluster, plan.CurrentVersion, plan.TargetVersion)
+		return nil
+	}
+
+	fmt.Printf("Upgrade plan for Cluster %s from Kubernetes version %s to %s:\n\n", plan.Cluster, plan.CurrentVersion, plan.TargetVersion)
+	w := tabwriter.NewWriter(os.Stdout, 10, 4, 3, ' ', 0)
+	fmt.Fprintln(w, "STEP\tKIND\tNAME\tCURRENT VERSION\tTARGET VERSION")
+	for i, step := range plan.Steps {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", i+1, step.Kind, step.Name, step.FromVersion, step.ToVersion)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Println("")
+	fmt.Println("You can now apply the upgrade by executing the same command without the --plan flag.")
+	return nil
+}
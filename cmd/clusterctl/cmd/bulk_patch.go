@@ -0,0 +1,141 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+)
+
+type bulkPatchOptions struct {
+	kubeconfig        string
+	kubeconfigContext string
+	name              string
+	namespace         string
+	stateNamespace    string
+	selector          string
+	patchFile         string
+	patchType         string
+	concurrency       int
+	qps               float64
+}
+
+var bp = &bulkPatchOptions{}
+
+var bulkPatchCmd = &cobra.Command{
+	Use:   "patch",
+	Short: "Apply a patch to all the clusters matching a label selector",
+	Long: LongDesc(`
+		Apply a merge or JSON patch to all the Clusters matching a label selector.
+
+		Clusters are patched in parallel up to the given concurrency, and at the given maximum rate.
+		The progress of the operation is recorded in a ConfigMap named clusterctl-bulk-<name>; if the
+		operation is interrupted or some Clusters fail to be patched, run the same command again to resume it.
+		Clusters which have already been patched are skipped.
+	`),
+	Example: Examples(`
+		# Apply the patch in patch.yaml to all the Clusters with label env=dev in all namespaces.
+		clusterctl alpha bulk patch --selector env=dev --patch-file patch.yaml
+
+		# Apply a JSON patch to the Clusters with label env=dev in namespace foo, at most 10 at a time.
+		clusterctl alpha bulk patch -n foo --selector env=dev --patch-file patch.json --type json --concurrency 10
+
+		# Name the operation, so it can be resumed with the same name.
+		clusterctl alpha bulk patch --name bump-k8s --selector env=dev --patch-file patch.yaml
+	`),
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runBulkPatch()
+	},
+}
+
+func init() {
+	bulkPatchCmd.Flags().StringVar(&bp.kubeconfig, "kubeconfig", "",
+		"Path to the kubeconfig for the management cluster. If unspecified, default discovery rules apply.")
+	bulkPatchCmd.Flags().StringVar(&bp.kubeconfigContext, "kubeconfig-context", "",
+		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+
+	bulkPatchCmd.Flags().StringVar(&bp.name, "name", "", "name of the bulk operation, used to resume it; if unspecified, it is derived from the namespace, selector and patch")
+	bulkPatchCmd.Flags().StringVarP(&bp.namespace, "namespace", "n", "", "namespace of the clusters to patch. If unspecified, clusters in all namespaces are patched")
+	bulkPatchCmd.Flags().StringVar(&bp.stateNamespace, "state-namespace", "", "namespace of the ConfigMap recording the state of the operation. If unspecified, the namespace of the clusters or the current namespace is used")
+	bulkPatchCmd.Flags().StringVarP(&bp.selector, "selector", "l", "", "label selector for the clusters to patch")
+	bulkPatchCmd.Flags().StringVar(&bp.patchFile, "patch-file", "", "path to a YAML or JSON file with the patch to apply")
+	bulkPatchCmd.Flags().StringVar(&bp.patchType, "type", "merge", "type of the patch, one of merge or json")
+	bulkPatchCmd.Flags().IntVar(&bp.concurrency, "concurrency", 5, "maximum number of clusters patched in parallel")
+	bulkPatchCmd.Flags().Float64Var(&bp.qps, "qps", 2, "maximum number of clusters patched per second; 0 disables rate limiting")
+
+	if err := bulkPatchCmd.MarkFlagRequired("selector"); err != nil {
+		panic(err)
+	}
+	if err := bulkPatchCmd.MarkFlagRequired("patch-file"); err != nil {
+		panic(err)
+	}
+
+	bulkCmd.AddCommand(bulkPatchCmd)
+}
+
+func runBulkPatch() error {
+	var patchType types.PatchType
+	switch bp.patchType {
+	case "merge":
+		patchType = types.MergePatchType
+	case "json":
+		patchType = types.JSONPatchType
+	default:
+		return errors.Errorf("invalid patch type %q, valid values are merge and json", bp.patchType)
+	}
+
+	raw, err := os.ReadFile(bp.patchFile)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read patch file %q", bp.patchFile)
+	}
+	patch, err := yaml.YAMLToJSON(raw)
+	if err != nil {
+		return errors.Wrapf(err, "failed to convert patch file %q to JSON", bp.patchFile)
+	}
+
+	c, err := client.New(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	result, err := c.BulkPatch(client.BulkPatchOptions{
+		Kubeconfig:     client.Kubeconfig{Path: bp.kubeconfig, Context: bp.kubeconfigContext},
+		Name:           bp.name,
+		Namespace:      bp.namespace,
+		StateNamespace: bp.stateNamespace,
+		Selector:       bp.selector,
+		Patch:          patch,
+		PatchType:      patchType,
+		Concurrency:    bp.concurrency,
+		QPS:            bp.qps,
+	})
+	if result != nil {
+		fmt.Printf("Patched %d Clusters, skipped %d Clusters already patched, %d Clusters failed.\n", len(result.Patched), len(result.Skipped), len(result.Failed))
+		for key, patchErr := range result.Failed {
+			fmt.Printf("  %s: %v\n", key, patchErr)
+		}
+	}
+	return err
+}
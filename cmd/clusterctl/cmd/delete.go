@@ -17,6 +17,8 @@ limitations under the License.
 package cmd
 
 import (
+	"time"
+
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
@@ -36,6 +38,14 @@ type deleteOptions struct {
 	includeNamespace          bool
 	includeCRDs               bool
 	deleteAll                 bool
+
+	selfManaged               bool
+	toKubeconfig              string
+	toKubeconfigContext       string
+	namespace                 string
+	clusterName               string
+	deleteWorkloadClusters    bool
+	selfManagedTimeoutSeconds int
 }
 
 var dd = &deleteOptions{}
@@ -45,7 +55,12 @@ var deleteCmd = &cobra.Command{
 	GroupID: groupManagement,
 	Short:   "Delete one or more providers from the management cluster",
 	Long: LongDesc(`
-		Delete one or more providers from the management cluster.`),
+		Delete one or more providers from the management cluster.
+
+		When the --self-managed flag is set, decommission a self-hosted management cluster instead:
+		all the Cluster API objects are moved to a temporary management cluster, e.g. a kind cluster
+		initialized with the same providers, workload clusters are either deleted or handed off to the
+		temporary management cluster, and finally the self-hosted cluster is deleted.`),
 
 	Example: Examples(`
 		# Deletes the AWS provider
@@ -80,7 +95,14 @@ var deleteCmd = &cobra.Command{
 		# Reset the management cluster to its original state
 		# Important! As a consequence of this operation all the corresponding resources on target clouds
 		# are "orphaned" and thus there may be ongoing costs incurred as a result of this.
-		clusterctl delete --all --include-crd  --include-namespace`),
+		clusterctl delete --all --include-crd  --include-namespace
+
+		# Decommission the self-hosted management cluster described by the Cluster mgmt in namespace default,
+		# handing off all the workload clusters to the management cluster in bootstrap.kubeconfig.
+		clusterctl delete --self-managed --cluster-name mgmt --namespace default --to-kubeconfig bootstrap.kubeconfig
+
+		# Decommission the self-hosted management cluster and delete all the workload clusters.
+		clusterctl delete --self-managed --cluster-name mgmt --to-kubeconfig bootstrap.kubeconfig --delete-workload-clusters`),
 	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runDelete()
@@ -116,6 +138,21 @@ func init() {
 	deleteCmd.Flags().BoolVar(&dd.deleteAll, "all", false,
 		"Force deletion of all the providers")
 
+	deleteCmd.Flags().BoolVar(&dd.selfManaged, "self-managed", false,
+		"Decommission the self-hosted management cluster by moving all the Cluster API objects to a temporary management cluster before deleting it")
+	deleteCmd.Flags().StringVar(&dd.toKubeconfig, "to-kubeconfig", "",
+		"Path to the kubeconfig file to use for the temporary management cluster. Used only with --self-managed.")
+	deleteCmd.Flags().StringVar(&dd.toKubeconfigContext, "to-kubeconfig-context", "",
+		"Context to be used within the kubeconfig file for the temporary management cluster. If empty, current context will be used. Used only with --self-managed.")
+	deleteCmd.Flags().StringVarP(&dd.namespace, "namespace", "n", "",
+		"The namespace where the self-hosted Cluster exists. If unspecified, the current namespace will be used. Used only with --self-managed.")
+	deleteCmd.Flags().StringVar(&dd.clusterName, "cluster-name", "",
+		"The name of the Cluster describing the self-hosted management cluster. Used only with --self-managed.")
+	deleteCmd.Flags().BoolVar(&dd.deleteWorkloadClusters, "delete-workload-clusters", false,
+		"Delete all the workload clusters instead of handing them off to the temporary management cluster. Used only with --self-managed.")
+	deleteCmd.Flags().IntVar(&dd.selfManagedTimeoutSeconds, "timeout", 30*60,
+		"Time in seconds to wait for the deletion of each Cluster. Used only with --self-managed.")
+
 	RootCmd.AddCommand(deleteCmd)
 }
 
@@ -133,6 +170,27 @@ func runDelete() error {
 		(len(dd.runtimeExtensionProviders) > 0) ||
 		(len(dd.addonProviders) > 0)
 
+	if dd.selfManaged {
+		if dd.deleteAll || hasProviderNames || dd.includeCRDs || dd.includeNamespace {
+			return errors.New("The --self-managed flag can't be used in combination with --all, --core, --bootstrap, --control-plane, --infrastructure, --ipam, --extension, --addon, --include-crd, --include-namespace")
+		}
+		if dd.toKubeconfig == "" {
+			return errors.New("The --to-kubeconfig flag must be set when using --self-managed")
+		}
+		if dd.clusterName == "" {
+			return errors.New("The --cluster-name flag must be set when using --self-managed")
+		}
+
+		return c.DeleteSelfManaged(client.DeleteSelfManagedOptions{
+			Kubeconfig:             client.Kubeconfig{Path: dd.kubeconfig, Context: dd.kubeconfigContext},
+			ToKubeconfig:           client.Kubeconfig{Path: dd.toKubeconfig, Context: dd.toKubeconfigContext},
+			Namespace:              dd.namespace,
+			ClusterName:            dd.clusterName,
+			DeleteWorkloadClusters: dd.deleteWorkloadClusters,
+			Timeout:                time.Duration(dd.selfManagedTimeoutSeconds) * time.Second,
+		})
+	}
+
 	if dd.deleteAll && hasProviderNames {
 		return errors.New("The --all flag can't be used in combination with --core, --bootstrap, --control-plane, --infrastructure, --ipam, --extension, --addon")
 	}
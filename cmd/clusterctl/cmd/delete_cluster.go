@@ -0,0 +1,164 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+)
+
+type deleteClusterOptions struct {
+	kubeconfig        string
+	kubeconfigContext string
+	namespace         string
+	timeoutSeconds    int
+	force             bool
+	dryRun            bool
+	yes               bool
+}
+
+var ddc = &deleteClusterOptions{}
+
+var deleteClusterCmd = &cobra.Command{
+	Use:   "cluster NAME",
+	Short: "Delete a workload cluster",
+	Long: LongDesc(`
+		Delete a workload cluster.
+
+		The Cluster is deleted in order: first the MachineDeployments, MachineSets, MachinePools and worker Machines,
+		then the control plane, the infrastructure cluster and finally the Cluster itself, waiting for the objects
+		of each phase to be deleted before moving to the next one.
+
+		Before deleting, the plan with the objects deleted in each phase is printed and a confirmation is requested.`),
+
+	Example: Examples(`
+		# Print the plan to delete the Cluster foo in the current namespace, without deleting it.
+		clusterctl delete cluster foo --dry-run
+
+		# Delete the Cluster foo in the namespace bar without asking for confirmation.
+		clusterctl delete cluster foo --namespace bar --yes
+
+		# Delete the Cluster foo, removing the finalizers of the objects which are not deleted after 10 minutes.
+		# Important! As a consequence of this operation, the infrastructure of those objects could be left behind
+		# and there might be ongoing costs incurred as a result of this.
+		clusterctl delete cluster foo --timeout 600 --force`),
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runDeleteCluster(args[0], os.Stdin, os.Stdout)
+	},
+}
+
+func init() {
+	deleteClusterCmd.Flags().StringVar(&ddc.kubeconfig, "kubeconfig", "",
+		"Path to the kubeconfig file to use for accessing the management cluster. If unspecified, default discovery rules apply.")
+	deleteClusterCmd.Flags().StringVar(&ddc.kubeconfigContext, "kubeconfig-context", "",
+		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+	deleteClusterCmd.Flags().StringVarP(&ddc.namespace, "namespace", "n", "",
+		"The namespace where the Cluster exists. If unspecified, the current namespace will be used.")
+	deleteClusterCmd.Flags().IntVar(&ddc.timeoutSeconds, "timeout", 30*60,
+		"Time in seconds to wait for the objects of each phase to be deleted.")
+	deleteClusterCmd.Flags().BoolVar(&ddc.force, "force", false,
+		"Remove the finalizers of the objects of a phase which are not deleted after the timeout. The infrastructure of those objects could be left behind.")
+	deleteClusterCmd.Flags().BoolVar(&ddc.dryRun, "dry-run", false,
+		"Print the plan to delete the Cluster without deleting it.")
+	deleteClusterCmd.Flags().BoolVarP(&ddc.yes, "yes", "y", false,
+		"Delete the Cluster without asking for confirmation.")
+
+	deleteCmd.AddCommand(deleteClusterCmd)
+}
+
+func runDeleteCluster(name string, in io.Reader, out io.Writer) error {
+	c, err := client.New(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	options := client.DeleteClusterOptions{
+		Kubeconfig:  client.Kubeconfig{Path: ddc.kubeconfig, Context: ddc.kubeconfigContext},
+		Namespace:   ddc.namespace,
+		ClusterName: name,
+		Timeout:     time.Duration(ddc.timeoutSeconds) * time.Second,
+		Force:       ddc.force,
+	}
+
+	plan, err := c.PlanDeleteCluster(options)
+	if err != nil {
+		return err
+	}
+	printDeleteClusterPlan(out, plan)
+
+	if ddc.dryRun {
+		return nil
+	}
+	if !ddc.yes {
+		fmt.Fprintf(out, "\nDo you want to delete Cluster %s? [y/N]: ", plan.Cluster)
+		answer, err := bufio.NewReader(in).ReadString('\n')
+		if err != nil && err != io.EOF {
+			return errors.Wrap(err, "failed to read the confirmation")
+		}
+		if answer = strings.ToLower(strings.TrimSpace(answer)); answer != "y" && answer != "yes" {
+			return errors.New("deletion aborted")
+		}
+	}
+
+	return c.DeleteCluster(options)
+}
+
+func printDeleteClusterPlan(out io.Writer, plan client.DeleteClusterPlan) {
+	fmt.Fprintf(out, "Plan to delete Cluster %s:\n\n", plan.Cluster)
+
+	w := tabwriter.NewWriter(out, 10, 4, 3, ' ', 0)
+	fmt.Fprintln(w, "PHASE\tOBJECTS\tMACHINES")
+	for _, phase := range plan.Phases {
+		fmt.Fprintf(w, "%s\t%s\t%d\n", phase.Name, countObjectsByKind(phase), phase.Machines)
+	}
+	w.Flush()
+}
+
+// countObjectsByKind returns the number of objects of each kind deleted in a phase, e.g. MachineDeployment=2, Machine=1.
+func countObjectsByKind(phase client.DeleteClusterPhase) string {
+	if len(phase.Objects) == 0 {
+		return "-"
+	}
+
+	counts := map[string]int{}
+	kinds := []string{}
+	for _, ref := range phase.Objects {
+		if counts[ref.Kind] == 0 {
+			kinds = append(kinds, ref.Kind)
+		}
+		counts[ref.Kind]++
+	}
+	sort.Strings(kinds)
+
+	ret := make([]string, 0, len(kinds))
+	for _, kind := range kinds {
+		ret = append(ret, fmt.Sprintf("%s=%d", kind, counts[kind]))
+	}
+	return strings.Join(ret, ", ")
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterctlclient "sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+)
+
+func Test_printDeleteClusterPlan(t *testing.T) {
+	g := NewWithT(t)
+
+	plan := clusterctlclient.DeleteClusterPlan{
+		Cluster: client.ObjectKey{Namespace: "default", Name: "foo"},
+		Phases: []clusterctlclient.DeleteClusterPhase{
+			{
+				Name: clusterctlclient.DeleteClusterWorkersPhase,
+				Objects: []corev1.ObjectReference{
+					{Kind: "MachineDeployment", Name: "foo-md-0"},
+					{Kind: "Machine", Name: "foo-worker"},
+					{Kind: "MachineDeployment", Name: "foo-md-1"},
+				},
+				Machines: 4,
+			},
+			{
+				Name: clusterctlclient.DeleteClusterControlPlanePhase,
+			},
+		},
+	}
+
+	out := &bytes.Buffer{}
+	printDeleteClusterPlan(out, plan)
+	g.Expect(out.String()).To(Equal(`Plan to delete Cluster default/foo:
+
+PHASE          OBJECTS                          MACHINES
+Workers        Machine=1, MachineDeployment=2   4
+ControlPlane   -                                0
+`))
+}
@@ -239,10 +239,14 @@ func addObjectRow(prefix string, tbl *tablewriter.Table, objectTree *tree.Object
 		readyDescriptor.age,
 		readyDescriptor.message})
 
-	// If it is required to show all the conditions for the object, add a row for each object's conditions.
+	// Add a row for each summary row surfaced by the object and, if it is required to show all the conditions
+	// for the object, a row for each object's conditions.
+	summary := tree.GetSummary(obj)
+	var otherConditions []*clusterv1.Condition
 	if tree.IsShowConditionsObject(obj) {
-		addOtherConditions(prefix, tbl, objectTree, obj)
+		otherConditions = tree.GetOtherConditions(obj)
 	}
+	addObjectDetails(prefix, tbl, objectTree, obj, summary, otherConditions)
 
 	// Add a row for each object's children, taking care of updating the tree view prefix.
 	childrenObj := objectTree.GetObjectsByParent(obj.GetUID())
@@ -264,23 +268,34 @@ func addObjectRow(prefix string, tbl *tablewriter.Table, objectTree *tree.Object
 	}
 }
 
-// addOtherConditions adds a row for each object condition except the ready condition,
-// which is already represented on the object's main row.
-func addOtherConditions(prefix string, tbl *tablewriter.Table, objectTree *tree.ObjectTree, obj ctrlclient.Object) {
-	// Add a row for each other condition, taking care of updating the tree view prefix.
-	// In this case the tree prefix get a filler, to indent conditions from objects, and eventually a
-	// and additional pipe if the object has children that should be presented after the conditions.
+// addObjectDetails adds a row for each summary row surfaced by the object, followed by a row for each of the given
+// object conditions; the ready condition is excluded, because it is already represented on the object's main row.
+func addObjectDetails(prefix string, tbl *tablewriter.Table, objectTree *tree.ObjectTree, obj ctrlclient.Object, summary []clusterv1.SummaryRow, otherConditions []*clusterv1.Condition) {
+	// Add a row for each summary row and other condition, taking care of updating the tree view prefix.
+	// In this case the tree prefix get a filler, to indent details from objects, and eventually a
+	// and additional pipe if the object has children that should be presented after the details.
 	filler := strings.Repeat(" ", 10)
 	childrenPipe := indent
 	if objectTree.IsObjectWithChild(obj.GetUID()) {
 		childrenPipe = pipe
 	}
 
-	otherConditions := tree.GetOtherConditions(obj)
+	detailsCount := len(summary) + len(otherConditions)
+	for i, row := range summary {
+		summaryPrefix := getChildPrefix(prefix+childrenPipe+filler, i, detailsCount)
+		tbl.Append([]string{
+			fmt.Sprintf("%s%s", gray.Sprint(summaryPrefix), cyan.Sprint(row.Key)),
+			"",
+			"",
+			"",
+			"",
+			row.Value})
+	}
+
 	for i := range otherConditions {
 		otherCondition := otherConditions[i]
 		otherDescriptor := newConditionDescriptor(otherCondition)
-		otherConditionPrefix := getChildPrefix(prefix+childrenPipe+filler, i, len(otherConditions))
+		otherConditionPrefix := getChildPrefix(prefix+childrenPipe+filler, len(summary)+i, detailsCount)
 		tbl.Append([]string{
 			fmt.Sprintf("%s%s", gray.Sprint(otherConditionPrefix), cyan.Sprint(otherCondition.Type)),
 			otherDescriptor.readyColor.Sprint(otherDescriptor.status),
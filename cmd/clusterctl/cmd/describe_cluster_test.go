@@ -27,6 +27,7 @@ import (
 	. "github.com/onsi/gomega"
 	gtype "github.com/onsi/gomega/types"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -274,6 +275,35 @@ func Test_TreePrefix(t *testing.T) {
 				"  └─Object/child2.1",
 			},
 		},
+		{
+			name: "Summary rows should get the right prefix",
+			objectTree: func() *tree.ObjectTree {
+				root := fakeObject("root")
+				obectjTree := tree.NewObjectTree(root, tree.ObjectTreeOptions{})
+
+				o1 := fakeInfrastructureObject("child1", []interface{}{
+					map[string]interface{}{"key": "S1.1", "value": "v1"},
+					map[string]interface{}{"key": "S1.2", "value": "v2"},
+				})
+				o1_1 := fakeObject("child1.1")
+				o2 := fakeInfrastructureObject("child2", []interface{}{
+					map[string]interface{}{"key": "S2.1", "value": "v1"},
+				})
+				obectjTree.Add(root, o1)
+				obectjTree.Add(o1, o1_1)
+				obectjTree.Add(root, o2)
+				return obectjTree
+			}(),
+			expectPrefix: []string{
+				"Object/root",
+				"├─InfrastructureObject/child1",
+				"│ │           ├─S1.1", // first summary row gets pipes, children pipe and ├─
+				"│ │           └─S1.2", // last summary row gets pipes, children pipe and └─
+				"│ └─Object/child1.1",
+				"└─InfrastructureObject/child2",
+				"              └─S2.1", // last summary row gets spaces and └─
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -313,6 +343,23 @@ func fakeObject(name string, options ...objectOption) ctrlclient.Object {
 	return c
 }
 
+func fakeInfrastructureObject(name string, summary []interface{}) ctrlclient.Object {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+			"kind":       "InfrastructureObject",
+			"metadata": map[string]interface{}{
+				"namespace": "ns",
+				"name":      name,
+				"uid":       name,
+			},
+			"status": map[string]interface{}{
+				"summary": summary,
+			},
+		},
+	}
+}
+
 func withAnnotation(name, value string) func(ctrlclient.Object) {
 	return func(c ctrlclient.Object) {
 		if c.GetAnnotations() == nil {
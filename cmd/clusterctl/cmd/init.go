@@ -38,6 +38,7 @@ type initOptions struct {
 	validate                  bool
 	waitProviders             bool
 	waitProviderTimeout       int
+	verifyProviders           bool
 }
 
 var initOpts = &initOptions{}
@@ -80,7 +81,10 @@ var initCmd = &cobra.Command{
 		clusterctl init --infrastructure=aws,vsphere
 
 		# Initialize a management cluster with a custom target namespace for the provider resources.
-		clusterctl init --infrastructure aws --target-namespace foo`),
+		clusterctl init --infrastructure aws --target-namespace foo
+
+		# Initialize a management cluster and wait for the providers to be healthy, printing a health report.
+		clusterctl init --infrastructure aws --verify-providers`),
 	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runInit()
@@ -111,7 +115,9 @@ func init() {
 	initCmd.Flags().BoolVar(&initOpts.waitProviders, "wait-providers", false,
 		"Wait for providers to be installed.")
 	initCmd.Flags().IntVar(&initOpts.waitProviderTimeout, "wait-provider-timeout", 5*60,
-		"Wait timeout per provider installation in seconds. This value is ignored if --wait-providers is false")
+		"Wait timeout per provider installation in seconds. This value is ignored if --wait-providers and --verify-providers are false")
+	initCmd.Flags().BoolVar(&initOpts.verifyProviders, "verify-providers", false,
+		"Wait for providers to be healthy, i.e. CRDs are established, certificates are issued, managers are available and webhooks can be called, then print a health report. Implies --wait-providers.")
 	initCmd.Flags().BoolVar(&initOpts.validate, "validate", true,
 		"If true, clusterctl will validate that the deployments will succeed on the management cluster.")
 
@@ -138,6 +144,7 @@ func runInit() error {
 		LogUsageInstructions:      true,
 		WaitProviders:             initOpts.waitProviders,
 		WaitProviderTimeout:       time.Duration(initOpts.waitProviderTimeout) * time.Second,
+		VerifyProviders:           initOpts.verifyProviders,
 		IgnoreValidationErrors:    !initOpts.validate,
 	}
 
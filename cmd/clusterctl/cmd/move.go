@@ -32,6 +32,7 @@ type moveOptions struct {
 	fromDirectory         string
 	toDirectory           string
 	dryRun                bool
+	ignorePreflightErrors []string
 }
 
 var mo = &moveOptions{}
@@ -79,6 +80,10 @@ func init() {
 	moveCmd.Flags().StringVar(&mo.fromDirectory, "from-directory", "",
 		"Read Cluster API objects and all dependencies from a directory into a management cluster.")
 
+	moveCmd.Flags().StringSliceVar(&mo.ignorePreflightErrors, "ignore-preflight-errors", nil,
+		"A list of pre-flight checks run on the destination management cluster whose errors will be shown as warnings. "+
+			"Valid values are ProviderVersions, CRDVersions, Webhooks, Namespaces, CertManager and all.")
+
 	moveCmd.MarkFlagsMutuallyExclusive("to-directory", "to-kubeconfig")
 	moveCmd.MarkFlagsMutuallyExclusive("from-directory", "to-directory")
 	moveCmd.MarkFlagsMutuallyExclusive("from-directory", "kubeconfig")
@@ -100,11 +105,12 @@ func runMove() error {
 	}
 
 	return c.Move(client.MoveOptions{
-		FromKubeconfig: client.Kubeconfig{Path: mo.fromKubeconfig, Context: mo.fromKubeconfigContext},
-		ToKubeconfig:   client.Kubeconfig{Path: mo.toKubeconfig, Context: mo.toKubeconfigContext},
-		FromDirectory:  mo.fromDirectory,
-		ToDirectory:    mo.toDirectory,
-		Namespace:      mo.namespace,
-		DryRun:         mo.dryRun,
+		FromKubeconfig:        client.Kubeconfig{Path: mo.fromKubeconfig, Context: mo.fromKubeconfigContext},
+		ToKubeconfig:          client.Kubeconfig{Path: mo.toKubeconfig, Context: mo.toKubeconfigContext},
+		FromDirectory:         mo.fromDirectory,
+		ToDirectory:           mo.toDirectory,
+		Namespace:             mo.namespace,
+		DryRun:                mo.dryRun,
+		IgnorePreflightErrors: mo.ignorePreflightErrors,
 	})
 }
@@ -0,0 +1,123 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+)
+
+type topologyAdoptOptions struct {
+	kubeconfig               string
+	kubeconfigContext        string
+	clusterName              string
+	namespace                string
+	clusterClass             string
+	machineDeploymentClasses map[string]string
+	dryRun                   bool
+}
+
+var ta = &topologyAdoptOptions{}
+
+var topologyAdoptCmd = &cobra.Command{
+	Use:   "adopt",
+	Short: "Attach an existing workload cluster without a managed topology to a ClusterClass",
+	Long: LongDesc(`
+		Attach an existing workload cluster without a managed topology to a ClusterClass.
+
+		The topology of the cluster is computed from its current state: the Kubernetes version and the replicas
+		of the control plane, the MachineDeployments with their replicas, and the values of the variables of the
+		ClusterClass which are set by its inline patches in the existing objects.
+		The topology controller then adopts the existing InfrastructureCluster, control plane and MachineDeployments
+		instead of creating new ones.
+
+		Note: The existing objects are rolled out if they differ from the ones generated from the ClusterClass;
+		use --dry-run and check the result with "clusterctl alpha topology plan" before adopting a cluster.`),
+	Example: Examples(`
+		# Attach the workload cluster foo to the ClusterClass quick-start.
+		clusterctl alpha topology adopt --cluster foo --class quick-start
+
+		# Attach the workload cluster foo in namespace bar to the ClusterClass quick-start, adopting the
+		# MachineDeployments md-0 and md-1 as the MachineDeployment classes default-worker and large-worker.
+		clusterctl alpha topology adopt --cluster foo -n bar --class quick-start \
+			--machine-deployment-class md-0=default-worker,md-1=large-worker
+
+		# Print the adopted workload cluster foo, without changing it.
+		clusterctl alpha topology adopt --cluster foo --class quick-start --dry-run`),
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTopologyAdopt()
+	},
+}
+
+func init() {
+	topologyAdoptCmd.Flags().StringVar(&ta.kubeconfig, "kubeconfig", "",
+		"Path to the kubeconfig for the management cluster. If unspecified, default discovery rules apply.")
+	topologyAdoptCmd.Flags().StringVar(&ta.kubeconfigContext, "kubeconfig-context", "",
+		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+
+	topologyAdoptCmd.Flags().StringVar(&ta.clusterName, "cluster", "", "name of the workload cluster to adopt")
+	topologyAdoptCmd.Flags().StringVarP(&ta.namespace, "namespace", "n", "", "namespace of the workload cluster. If unspecified, the current namespace will be used")
+	topologyAdoptCmd.Flags().StringVar(&ta.clusterClass, "class", "", "name of the ClusterClass to attach the workload cluster to")
+	topologyAdoptCmd.Flags().StringToStringVar(&ta.machineDeploymentClasses, "machine-deployment-class", nil,
+		"MachineDeployment class of each MachineDeployment of the workload cluster, as <machine-deployment>=<class>. Can be omitted if the ClusterClass has only one MachineDeployment class")
+	topologyAdoptCmd.Flags().BoolVar(&ta.dryRun, "dry-run", false, "print the adopted workload cluster without changing it")
+
+	if err := topologyAdoptCmd.MarkFlagRequired("cluster"); err != nil {
+		panic(err)
+	}
+	if err := topologyAdoptCmd.MarkFlagRequired("class"); err != nil {
+		panic(err)
+	}
+
+	topologyCmd.AddCommand(topologyAdoptCmd)
+}
+
+func runTopologyAdopt() error {
+	c, err := client.New(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	cluster, err := c.TopologyAdopt(client.TopologyAdoptOptions{
+		Kubeconfig:               client.Kubeconfig{Path: ta.kubeconfig, Context: ta.kubeconfigContext},
+		ClusterName:              ta.clusterName,
+		Namespace:                ta.namespace,
+		ClusterClass:             ta.clusterClass,
+		MachineDeploymentClasses: ta.machineDeploymentClasses,
+		DryRun:                   ta.dryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	if ta.dryRun {
+		out, err := yaml.Marshal(cluster)
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(out))
+		return nil
+	}
+
+	fmt.Printf("Cluster %s attached to ClusterClass %s; its existing objects are now adopted by the topology controller.\n", cluster.Name, ta.clusterClass)
+	return nil
+}
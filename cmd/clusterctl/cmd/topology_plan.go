@@ -32,6 +32,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/utils/exec"
 	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
 
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
@@ -45,6 +46,7 @@ type topologyPlanOptions struct {
 	cluster           string
 	namespace         string
 	outDir            string
+	allClusters       bool
 }
 
 var tp = &topologyPlanOptions{}
@@ -80,6 +82,9 @@ var topologyPlanCmd = &cobra.Command{
 
 		# List the clusters and ClusterClasses impacted by a template change.
 		clusterctl alpha topology plan -f modified-template.yaml -o output/
+
+		# List the control planes and MachineDeployments of all the clusters that would be rolled out when a ClusterClass is changed.
+		clusterctl alpha topology plan -f modified-cluster-class.yaml --all-clusters -o output/
 	`),
 	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -97,6 +102,7 @@ func init() {
 	topologyPlanCmd.Flags().StringVarP(&tp.cluster, "cluster", "c", "", "name of the target cluster; this parameter is required when more than one cluster is affected")
 	topologyPlanCmd.Flags().StringVarP(&tp.namespace, "namespace", "n", "", "target namespace for the operation. If specified, it is used as default namespace for objects with missing namespace")
 	topologyPlanCmd.Flags().StringVarP(&tp.outDir, "output-directory", "o", "", "output directory to write details about created/modified objects")
+	topologyPlanCmd.Flags().BoolVar(&tp.allClusters, "all-clusters", false, "dry run all the affected clusters and report the control planes and MachineDeployments that would be rolled out")
 
 	if err := topologyPlanCmd.MarkFlagRequired("file"); err != nil {
 		panic(err)
@@ -128,10 +134,11 @@ func runTopologyPlan() error {
 	}
 
 	out, err := c.TopologyPlan(client.TopologyPlanOptions{
-		Kubeconfig: client.Kubeconfig{Path: tp.kubeconfig, Context: tp.kubeconfigContext},
-		Objs:       convertToPtrSlice(objs),
-		Cluster:    tp.cluster,
-		Namespace:  tp.namespace,
+		Kubeconfig:  client.Kubeconfig{Path: tp.kubeconfig, Context: tp.kubeconfigContext},
+		Objs:        convertToPtrSlice(objs),
+		Cluster:     tp.cluster,
+		Namespace:   tp.namespace,
+		AllClusters: tp.allClusters,
 	})
 	if err != nil {
 		return err
@@ -146,6 +153,12 @@ func printTopologyPlanOutput(out *cluster.TopologyPlanOutput, outdir string) err
 		// No affected clusters. Return early.
 		return nil
 	}
+	if out.Rollouts != nil {
+		printRollouts(out)
+		if err := writeRolloutsFile(out, outdir); err != nil {
+			return errors.Wrap(err, "failed to write rollouts file")
+		}
+	}
 	if out.ReconciledCluster == nil {
 		fmt.Printf("No target cluster identified. Use --cluster to specify a target cluster to get detailed changes.")
 	} else {
@@ -184,6 +197,80 @@ func printAffectedClusters(out *cluster.TopologyPlanOutput) {
 	fmt.Printf("\n")
 }
 
+func printRollouts(out *cluster.TopologyPlanOutput) {
+	rollouts := []cluster.ClusterRollout{}
+	for _, r := range out.Rollouts {
+		if r.HasRollouts() {
+			rollouts = append(rollouts, r)
+		}
+	}
+	if len(rollouts) == 0 {
+		fmt.Printf("No Machines will be rolled out by the changes.\n\n")
+		return
+	}
+
+	fmt.Printf("The following Machines will be rolled out by the changes:\n")
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Cluster", "Kind", "Name"})
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetCenterSeparator("")
+	table.SetColumnSeparator("")
+	table.SetRowSeparator("")
+	table.SetHeaderLine(false)
+	table.SetBorder(false)
+	for _, r := range rollouts {
+		if r.ControlPlane != nil {
+			table.Append([]string{r.Cluster.String(), r.ControlPlane.Kind, r.ControlPlane.Name})
+		}
+		for _, md := range r.MachineDeployments {
+			table.Append([]string{r.Cluster.String(), "MachineDeployment", md.Name})
+		}
+	}
+	fmt.Printf("\n")
+	table.Render()
+	fmt.Printf("\n")
+}
+
+// topologyPlanRollout is the representation of a cluster.ClusterRollout written to the rollouts file.
+type topologyPlanRollout struct {
+	Cluster            string   `json:"cluster"`
+	ControlPlane       string   `json:"controlPlane,omitempty"`
+	MachineDeployments []string `json:"machineDeployments,omitempty"`
+}
+
+// writeRolloutsFile writes the rollouts of all the affected clusters to a file, so they
+// can be consumed by other tools, e.g. to gate ClusterClass changes in CI.
+func writeRolloutsFile(out *cluster.TopologyPlanOutput, outDir string) error {
+	if _, err := os.Stat(outDir); os.IsNotExist(err) {
+		return fmt.Errorf("output directory %q does not exist", outDir)
+	}
+
+	rollouts := []topologyPlanRollout{}
+	for _, r := range out.Rollouts {
+		rollout := topologyPlanRollout{
+			Cluster: r.Cluster.String(),
+		}
+		if r.ControlPlane != nil {
+			rollout.ControlPlane = fmt.Sprintf("%s/%s", r.ControlPlane.Kind, r.ControlPlane.Name)
+		}
+		for _, md := range r.MachineDeployments {
+			rollout.MachineDeployments = append(rollout.MachineDeployments, md.Name)
+		}
+		rollouts = append(rollouts, rollout)
+	}
+
+	data, err := yaml.Marshal(rollouts)
+	if err != nil {
+		return errors.Wrap(err, "failed to convert rollouts to yaml")
+	}
+	filePath := path.Join(outDir, "rollouts.yaml")
+	if err := os.WriteFile(filePath, data, 0600); err != nil {
+		return errors.Wrapf(err, "failed to write yaml to file %q", filePath)
+	}
+	fmt.Printf("Rollouts are written to file %q\n\n", filePath)
+	return nil
+}
+
 func printChangeSummary(out *cluster.TopologyPlanOutput) {
 	if len(out.Created) == 0 && len(out.Modified) == 0 && len(out.Deleted) == 0 {
 		fmt.Printf("No changes detected for Cluster %q.\n", fmt.Sprintf("%s/%s", out.ReconciledCluster.Namespace, out.ReconciledCluster.Name))
@@ -0,0 +1,97 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+)
+
+type waitOptions struct {
+	kubeconfig        string
+	kubeconfigContext string
+	namespace         string
+	forCondition      string
+	timeout           time.Duration
+}
+
+var waitOpts = &waitOptions{}
+
+var waitCmd = &cobra.Command{
+	Use:   "wait RESOURCE/NAME...",
+	Short: "Wait for a condition of Cluster API resources",
+	Long: LongDesc(`
+		Wait for a condition of Cluster API resources, including provider resources, or for their deletion.
+
+		Resources are identified by their kind, singular, plural or short name, optionally followed by the group,
+		e.g. cluster/foo or machinedeployments.cluster.x-k8s.io/foo-md-0; the kinds are discovered from the CRDs
+		implementing the Cluster API contract, so the resources of all the installed providers are supported.
+
+		A condition is considered met only when the status of the resource is up to date, i.e. its
+		observedGeneration, if any, matches its generation.`),
+
+	Example: Examples(`
+		# Wait for the control plane of the Cluster foo to be ready.
+		clusterctl alpha wait --for=condition=ControlPlaneReady cluster/foo --timeout=30m
+
+		# Wait for the MachineDeployment foo-md-0 and the KubeadmControlPlane foo-cp to be ready.
+		clusterctl alpha wait --for=condition=Ready machinedeployment/foo-md-0 kubeadmcontrolplane/foo-cp
+
+		# Wait for a condition to be False.
+		clusterctl alpha wait --for=condition=Paused=False cluster/foo
+
+		# Wait for the Cluster foo to be deleted.
+		clusterctl alpha wait --for=delete cluster/foo`),
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runWait(args)
+	},
+}
+
+func init() {
+	waitCmd.Flags().StringVar(&waitOpts.kubeconfig, "kubeconfig", "",
+		"Path to the kubeconfig file to use for accessing the management cluster. If unspecified, default discovery rules apply.")
+	waitCmd.Flags().StringVar(&waitOpts.kubeconfigContext, "kubeconfig-context", "",
+		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+	waitCmd.Flags().StringVarP(&waitOpts.namespace, "namespace", "n", "",
+		"Namespace where the resources reside. If unspecified, the current namespace will be used.")
+	waitCmd.Flags().StringVar(&waitOpts.forCondition, "for", "",
+		"The condition to wait for: condition=Type, condition=Type=Status or delete.")
+	waitCmd.Flags().DurationVar(&waitOpts.timeout, "timeout", 10*time.Minute,
+		"The time to wait for all the resources, e.g. 30m.")
+	if err := waitCmd.MarkFlagRequired("for"); err != nil {
+		panic(err)
+	}
+}
+
+func runWait(args []string) error {
+	c, err := client.New(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	return c.Wait(client.WaitOptions{
+		Kubeconfig: client.Kubeconfig{Path: waitOpts.kubeconfig, Context: waitOpts.kubeconfigContext},
+		Resources:  args,
+		Namespace:  waitOpts.namespace,
+		For:        waitOpts.forCondition,
+		Timeout:    waitOpts.timeout,
+	})
+}
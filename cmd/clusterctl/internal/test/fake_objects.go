@@ -1421,7 +1421,7 @@ func fakeCRD(group string, kind string, versions []string) *apiextensionsv1.Cust
 
 	for i, version := range versions {
 		// set the first version as a storage version
-		versionObj := apiextensionsv1.CustomResourceDefinitionVersion{Name: version}
+		versionObj := apiextensionsv1.CustomResourceDefinitionVersion{Name: version, Served: true}
 		if i == 0 {
 			versionObj.Storage = true
 		}
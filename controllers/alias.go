@@ -20,6 +20,7 @@ import (
 	"context"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -27,6 +28,7 @@ import (
 	"sigs.k8s.io/cluster-api/controllers/remote"
 	clustercontroller "sigs.k8s.io/cluster-api/internal/controllers/cluster"
 	clusterclasscontroller "sigs.k8s.io/cluster-api/internal/controllers/clusterclass"
+	clusterclassreplicationcontroller "sigs.k8s.io/cluster-api/internal/controllers/clusterclassreplication"
 	machinecontroller "sigs.k8s.io/cluster-api/internal/controllers/machine"
 	machinedeploymentcontroller "sigs.k8s.io/cluster-api/internal/controllers/machinedeployment"
 	machinehealthcheckcontroller "sigs.k8s.io/cluster-api/internal/controllers/machinehealthcheck"
@@ -35,6 +37,7 @@ import (
 	machinedeploymenttopologycontroller "sigs.k8s.io/cluster-api/internal/controllers/topology/machinedeployment"
 	machinesettopologycontroller "sigs.k8s.io/cluster-api/internal/controllers/topology/machineset"
 	runtimeclient "sigs.k8s.io/cluster-api/internal/runtime/client"
+	"sigs.k8s.io/cluster-api/util/priority"
 )
 
 // Following types provides access to reconcilers implemented in internal/controllers, thus
@@ -48,14 +51,28 @@ type ClusterReconciler struct {
 
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
+
+	// WorkerMachineDeletionBatchSize is the maximum number of worker Machines being deleted at the same time
+	// when a Cluster is deleted.
+	WorkerMachineDeletionBatchSize int
+
+	// ReconcilePriorityWeights are the weights of the priority queue serving the requests for the objects of
+	// Clusters by their reconcile priority.
+	ReconcilePriorityWeights priority.Weights
+
+	// NamespaceConcurrency is the maximum number of objects of the same namespace reconciled at the same time.
+	NamespaceConcurrency int
 }
 
 func (r *ClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
 	return (&clustercontroller.Reconciler{
-		Client:                    r.Client,
-		UnstructuredCachingClient: r.UnstructuredCachingClient,
-		APIReader:                 r.APIReader,
-		WatchFilterValue:          r.WatchFilterValue,
+		Client:                         r.Client,
+		UnstructuredCachingClient:      r.UnstructuredCachingClient,
+		APIReader:                      r.APIReader,
+		WatchFilterValue:               r.WatchFilterValue,
+		WorkerMachineDeletionBatchSize: r.WorkerMachineDeletionBatchSize,
+		ReconcilePriorityWeights:       r.ReconcilePriorityWeights,
+		NamespaceConcurrency:           r.NamespaceConcurrency,
 	}).SetupWithManager(ctx, mgr, options)
 }
 
@@ -71,16 +88,46 @@ type MachineReconciler struct {
 
 	// NodeDrainClientTimeout timeout of the client used for draining nodes.
 	NodeDrainClientTimeout time.Duration
+
+	// UnreachableClusterTimeout is the duration after which node drain and wait for volume detach are skipped
+	// when deleting a Machine whose workload cluster control plane cannot be reached.
+	UnreachableClusterTimeout time.Duration
+
+	// PreDrainTaint is the taint added to the node of a Machine being deleted before evicting its Pods.
+	PreDrainTaint *corev1.Taint
+
+	// PreDrainTaintObservationPeriod is how long to wait after adding the PreDrainTaint before evicting Pods.
+	PreDrainTaintObservationPeriod time.Duration
+
+	// MachineToNodeLabelDomains are the label domains of the Machine labels propagated to the Node.
+	MachineToNodeLabelDomains []string
+
+	// NodeToMachineLabels are the keys of the Node labels reflected on the Machine.
+	NodeToMachineLabels []string
+
+	// ReconcilePriorityWeights are the weights of the priority queue serving the requests for the objects of
+	// Clusters by their reconcile priority.
+	ReconcilePriorityWeights priority.Weights
+
+	// NamespaceConcurrency is the maximum number of objects of the same namespace reconciled at the same time.
+	NamespaceConcurrency int
 }
 
 func (r *MachineReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
 	return (&machinecontroller.Reconciler{
-		Client:                    r.Client,
-		UnstructuredCachingClient: r.UnstructuredCachingClient,
-		APIReader:                 r.APIReader,
-		Tracker:                   r.Tracker,
-		WatchFilterValue:          r.WatchFilterValue,
-		NodeDrainClientTimeout:    r.NodeDrainClientTimeout,
+		Client:                         r.Client,
+		UnstructuredCachingClient:      r.UnstructuredCachingClient,
+		APIReader:                      r.APIReader,
+		Tracker:                        r.Tracker,
+		WatchFilterValue:               r.WatchFilterValue,
+		NodeDrainClientTimeout:         r.NodeDrainClientTimeout,
+		UnreachableClusterTimeout:      r.UnreachableClusterTimeout,
+		PreDrainTaint:                  r.PreDrainTaint,
+		PreDrainTaintObservationPeriod: r.PreDrainTaintObservationPeriod,
+		MachineToNodeLabelDomains:      r.MachineToNodeLabelDomains,
+		NodeToMachineLabels:            r.NodeToMachineLabels,
+		ReconcilePriorityWeights:       r.ReconcilePriorityWeights,
+		NamespaceConcurrency:           r.NamespaceConcurrency,
 	}).SetupWithManager(ctx, mgr, options)
 }
 
@@ -90,9 +137,21 @@ type MachineSetReconciler struct {
 	UnstructuredCachingClient client.Client
 	APIReader                 client.Reader
 	Tracker                   *remote.ClusterCacheTracker
+	RuntimeClient             runtimeclient.Client
 
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
+
+	// ReconcilePriorityWeights are the weights of the priority queue serving the requests for the objects of
+	// Clusters by their reconcile priority.
+	ReconcilePriorityWeights priority.Weights
+
+	// NamespaceConcurrency is the maximum number of objects of the same namespace reconciled at the same time.
+	NamespaceConcurrency int
+
+	// AdoptionPolicy defines how MachineSets adopt orphaned Machines matching their selector,
+	// one of Always, Annotated or DryRun. If empty, Always is used.
+	AdoptionPolicy string
 }
 
 func (r *MachineSetReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
@@ -101,7 +160,11 @@ func (r *MachineSetReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Ma
 		UnstructuredCachingClient: r.UnstructuredCachingClient,
 		APIReader:                 r.APIReader,
 		Tracker:                   r.Tracker,
+		RuntimeClient:             r.RuntimeClient,
 		WatchFilterValue:          r.WatchFilterValue,
+		ReconcilePriorityWeights:  r.ReconcilePriorityWeights,
+		NamespaceConcurrency:      r.NamespaceConcurrency,
+		AdoptionPolicy:            machinesetcontroller.AdoptionPolicy(r.AdoptionPolicy),
 	}).SetupWithManager(ctx, mgr, options)
 }
 
@@ -110,9 +173,17 @@ type MachineDeploymentReconciler struct {
 	Client                    client.Client
 	UnstructuredCachingClient client.Client
 	APIReader                 client.Reader
+	RuntimeClient             runtimeclient.Client
 
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
+
+	// ReconcilePriorityWeights are the weights of the priority queue serving the requests for the objects of
+	// Clusters by their reconcile priority.
+	ReconcilePriorityWeights priority.Weights
+
+	// NamespaceConcurrency is the maximum number of objects of the same namespace reconciled at the same time.
+	NamespaceConcurrency int
 }
 
 func (r *MachineDeploymentReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
@@ -120,7 +191,10 @@ func (r *MachineDeploymentReconciler) SetupWithManager(ctx context.Context, mgr
 		Client:                    r.Client,
 		UnstructuredCachingClient: r.UnstructuredCachingClient,
 		APIReader:                 r.APIReader,
+		RuntimeClient:             r.RuntimeClient,
 		WatchFilterValue:          r.WatchFilterValue,
+		ReconcilePriorityWeights:  r.ReconcilePriorityWeights,
+		NamespaceConcurrency:      r.NamespaceConcurrency,
 	}).SetupWithManager(ctx, mgr, options)
 }
 
@@ -233,3 +307,28 @@ func (r *ClusterClassReconciler) SetupWithManager(ctx context.Context, mgr ctrl.
 		WatchFilterValue:          r.WatchFilterValue,
 	}).SetupWithManager(ctx, mgr, options)
 }
+
+// ClusterClassReplicationReconciler replicates ClusterClasses and the referenced templates from a source namespace
+// to all the namespaces opting-in.
+type ClusterClassReplicationReconciler struct {
+	Client client.Client
+
+	// UnstructuredCachingClient provides a client that forces caching of unstructured objects,
+	// thus allowing to optimize reads for templates or provider specific objects.
+	UnstructuredCachingClient client.Client
+
+	// SourceNamespace is the namespace of the ClusterClasses and templates to be replicated.
+	SourceNamespace string
+
+	// WatchFilterValue is the label value used to filter events prior to reconciliation.
+	WatchFilterValue string
+}
+
+func (r *ClusterClassReplicationReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	return (&clusterclassreplicationcontroller.Reconciler{
+		Client:                    r.Client,
+		UnstructuredCachingClient: r.UnstructuredCachingClient,
+		SourceNamespace:           r.SourceNamespace,
+		WatchFilterValue:          r.WatchFilterValue,
+	}).SetupWithManager(ctx, mgr, options)
+}
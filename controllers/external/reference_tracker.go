@@ -0,0 +1,212 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package external
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"sigs.k8s.io/cluster-api/util/predicates"
+)
+
+// referenceKey identifies an object referenced by an owner.
+type referenceKey struct {
+	groupKind schema.GroupKind
+	types.NamespacedName
+}
+
+// ReferenceTracker tracks the external objects referenced by owner objects, e.g. the bootstrap and
+// infrastructure objects referenced by Machines, and watches each referenced kind.
+//
+// Events for a referenced object are mapped to the owners referencing it, so owners are reconciled as soon as
+// the object is created or changes, even before the owner is set in the object's owner references; the number
+// of owners referencing each kind is tracked, so events for kinds no longer referenced are dropped early.
+//
+// NOTE: controller-runtime does not support removing watches, so a kind is watched until the controller stops
+// even if it is no longer referenced.
+type ReferenceTracker struct {
+	Controller controller.Controller
+	Cache      cache.Cache
+
+	watchLock sync.Mutex
+	// watched are the kinds with a watch.
+	watched map[schema.GroupKind]struct{}
+
+	lock sync.RWMutex
+	// kindRefs is the number of owners referencing each kind.
+	kindRefs map[schema.GroupKind]int
+	// objectRefs are the owners referencing each object.
+	objectRefs map[referenceKey]map[types.NamespacedName]struct{}
+	// ownerRefs are the objects referenced by each owner.
+	ownerRefs map[types.NamespacedName][]referenceKey
+}
+
+// Track records the objects referenced by an owner, replacing the ones previously recorded, and ensures
+// a watch exists for each referenced kind; nil references are ignored.
+// NOTE: References without a namespace are assumed to be in the namespace of the owner.
+func (t *ReferenceTracker) Track(log logr.Logger, owner types.NamespacedName, refs []*corev1.ObjectReference, p ...predicate.Predicate) error {
+	keys := []referenceKey{}
+	gvks := map[schema.GroupKind]schema.GroupVersionKind{}
+	for _, ref := range refs {
+		if ref == nil {
+			continue
+		}
+		gvk := ref.GroupVersionKind()
+		namespace := ref.Namespace
+		if namespace == "" {
+			namespace = owner.Namespace
+		}
+		keys = append(keys, referenceKey{
+			groupKind:      gvk.GroupKind(),
+			NamespacedName: types.NamespacedName{Namespace: namespace, Name: ref.Name},
+		})
+		gvks[gvk.GroupKind()] = gvk
+	}
+
+	t.lock.Lock()
+	t.untrack(owner)
+	for _, key := range keys {
+		t.kindRefs[key.groupKind]++
+		if t.objectRefs[key] == nil {
+			t.objectRefs[key] = map[types.NamespacedName]struct{}{}
+		}
+		t.objectRefs[key][owner] = struct{}{}
+	}
+	if len(keys) > 0 {
+		t.ownerRefs[owner] = keys
+	}
+	t.lock.Unlock()
+
+	// Watch the referenced kinds in a predictable order.
+	groupKinds := make([]schema.GroupKind, 0, len(gvks))
+	for gk := range gvks {
+		groupKinds = append(groupKinds, gk)
+	}
+	sort.Slice(groupKinds, func(i, j int) bool { return groupKinds[i].String() < groupKinds[j].String() })
+	for _, gk := range groupKinds {
+		if err := t.watch(log, gvks[gk], p...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Untrack removes the objects referenced by an owner, e.g. when the owner is deleted.
+func (t *ReferenceTracker) Untrack(owner types.NamespacedName) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.untrack(owner)
+}
+
+// ReferenceCount returns the number of owners referencing a kind.
+func (t *ReferenceTracker) ReferenceCount(gk schema.GroupKind) int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return t.kindRefs[gk]
+}
+
+// OwnersForObject maps an object to reconcile requests for the owners referencing it.
+func (t *ReferenceTracker) OwnersForObject(_ context.Context, o client.Object) []reconcile.Request {
+	gk := o.GetObjectKind().GroupVersionKind().GroupKind()
+
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.kindRefs[gk] == 0 {
+		return nil
+	}
+
+	key := referenceKey{groupKind: gk, NamespacedName: types.NamespacedName{Namespace: o.GetNamespace(), Name: o.GetName()}}
+	requests := make([]reconcile.Request, 0, len(t.objectRefs[key]))
+	for owner := range t.objectRefs[key] {
+		requests = append(requests, reconcile.Request{NamespacedName: owner})
+	}
+	sort.Slice(requests, func(i, j int) bool { return requests[i].String() < requests[j].String() })
+	return requests
+}
+
+// untrack removes the objects referenced by an owner; it must be called with the lock held.
+func (t *ReferenceTracker) untrack(owner types.NamespacedName) {
+	if t.kindRefs == nil {
+		t.kindRefs = map[schema.GroupKind]int{}
+		t.objectRefs = map[referenceKey]map[types.NamespacedName]struct{}{}
+		t.ownerRefs = map[types.NamespacedName][]referenceKey{}
+	}
+
+	for _, key := range t.ownerRefs[owner] {
+		t.kindRefs[key.groupKind]--
+		if t.kindRefs[key.groupKind] <= 0 {
+			delete(t.kindRefs, key.groupKind)
+		}
+		delete(t.objectRefs[key], owner)
+		if len(t.objectRefs[key]) == 0 {
+			delete(t.objectRefs, key)
+		}
+	}
+	delete(t.ownerRefs, owner)
+}
+
+// watch uses the controller to issue a Watch only if the kind isn't watched yet.
+func (t *ReferenceTracker) watch(log logr.Logger, gvk schema.GroupVersionKind, p ...predicate.Predicate) error {
+	// Consider this a no-op if the controller isn't present.
+	if t.Controller == nil {
+		return nil
+	}
+
+	t.watchLock.Lock()
+	defer t.watchLock.Unlock()
+
+	if t.watched == nil {
+		t.watched = map[schema.GroupKind]struct{}{}
+	}
+	if _, ok := t.watched[gvk.GroupKind()]; ok {
+		return nil
+	}
+
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(gvk)
+
+	log.Info(fmt.Sprintf("Adding watch on referenced external object %q", gvk.String()))
+	err := t.Controller.Watch(
+		source.Kind(t.Cache, u),
+		handler.EnqueueRequestsFromMapFunc(t.OwnersForObject),
+		append(p, predicates.ResourceNotPaused(log))...,
+	)
+	if err != nil {
+		return errors.Wrapf(err, "failed to add watch on referenced external object %q", gvk.String())
+	}
+	t.watched[gvk.GroupKind()] = struct{}{}
+	return nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package external
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestReferenceTrackerWatchesEachKindOnce(t *testing.T) {
+	g := NewWithT(t)
+	ctrl := newWatchCountController(false)
+	tracker := &ReferenceTracker{Controller: ctrl}
+
+	bootstrapRef := &corev1.ObjectReference{APIVersion: "bootstrap.cluster.x-k8s.io/v1beta1", Kind: "GenericBootstrapConfig", Name: "bootstrap"}
+	infraRef := &corev1.ObjectReference{APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1", Kind: "GenericInfrastructureMachine", Name: "infra"}
+
+	g.Expect(tracker.Track(logger, types.NamespacedName{Namespace: "default", Name: "machine1"}, []*corev1.ObjectReference{bootstrapRef, infraRef})).To(Succeed())
+	g.Expect(ctrl.count).To(Equal(2))
+
+	g.Expect(tracker.Track(logger, types.NamespacedName{Namespace: "default", Name: "machine2"}, []*corev1.ObjectReference{nil, infraRef})).To(Succeed())
+	g.Expect(ctrl.count).To(Equal(2))
+}
+
+func TestReferenceTrackerRetryWatch(t *testing.T) {
+	g := NewWithT(t)
+	ctrl := newWatchCountController(true)
+	tracker := &ReferenceTracker{Controller: ctrl}
+
+	ref := &corev1.ObjectReference{APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1", Kind: "GenericInfrastructureMachine", Name: "infra"}
+	owner := types.NamespacedName{Namespace: "default", Name: "machine1"}
+
+	g.Expect(tracker.Track(logger, owner, []*corev1.ObjectReference{ref})).ToNot(Succeed())
+	g.Expect(ctrl.count).To(Equal(1))
+	// Tracking the same kind again after a failure should retry the watch.
+	g.Expect(tracker.Track(logger, owner, []*corev1.ObjectReference{ref})).ToNot(Succeed())
+	g.Expect(ctrl.count).To(Equal(2))
+}
+
+func TestReferenceTrackerOwnersForObject(t *testing.T) {
+	g := NewWithT(t)
+	tracker := &ReferenceTracker{}
+
+	gk := schema.GroupKind{Group: "infrastructure.cluster.x-k8s.io", Kind: "GenericInfrastructureMachine"}
+	machine1 := types.NamespacedName{Namespace: "default", Name: "machine1"}
+	machine2 := types.NamespacedName{Namespace: "default", Name: "machine2"}
+	infraRef := func(name string) *corev1.ObjectReference {
+		return &corev1.ObjectReference{APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1", Kind: "GenericInfrastructureMachine", Name: name}
+	}
+	infraObj := func(namespace, name string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion("infrastructure.cluster.x-k8s.io/v1beta1")
+		u.SetKind("GenericInfrastructureMachine")
+		u.SetNamespace(namespace)
+		u.SetName(name)
+		return u
+	}
+
+	g.Expect(tracker.Track(logger, machine1, []*corev1.ObjectReference{infraRef("infra1")})).To(Succeed())
+	g.Expect(tracker.Track(logger, machine2, []*corev1.ObjectReference{infraRef("infra2")})).To(Succeed())
+	g.Expect(tracker.ReferenceCount(gk)).To(Equal(2))
+
+	// Objects are mapped to the owners referencing them.
+	g.Expect(tracker.OwnersForObject(context.Background(), infraObj("default", "infra1"))).To(Equal([]reconcile.Request{{NamespacedName: machine1}}))
+	g.Expect(tracker.OwnersForObject(context.Background(), infraObj("other", "infra1"))).To(BeEmpty())
+
+	// Tracking an owner again replaces the objects it references.
+	g.Expect(tracker.Track(logger, machine2, []*corev1.ObjectReference{infraRef("infra1")})).To(Succeed())
+	g.Expect(tracker.ReferenceCount(gk)).To(Equal(2))
+	g.Expect(tracker.OwnersForObject(context.Background(), infraObj("default", "infra1"))).To(Equal([]reconcile.Request{{NamespacedName: machine1}, {NamespacedName: machine2}}))
+	g.Expect(tracker.OwnersForObject(context.Background(), infraObj("default", "infra2"))).To(BeEmpty())
+
+	// Untracking the owners drops the references.
+	tracker.Untrack(machine1)
+	tracker.Untrack(machine2)
+	g.Expect(tracker.ReferenceCount(gk)).To(Equal(0))
+	g.Expect(tracker.OwnersForObject(context.Background(), infraObj("default", "infra1"))).To(BeEmpty())
+}
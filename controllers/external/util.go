@@ -73,6 +73,10 @@ type CreateFromTemplateInput struct {
 	// ClusterName is the cluster this object is linked to.
 	ClusterName string
 
+	// Name is an optional name of the cloned object; if not set, a name is generated from the name of the template.
+	// +optional
+	Name string
+
 	// OwnerRef is an optional OwnerReference to attach to the cloned object.
 	// +optional
 	OwnerRef *metav1.OwnerReference
@@ -97,6 +101,7 @@ func CreateFromTemplate(ctx context.Context, in *CreateFromTemplateInput) (*core
 		TemplateRef: in.TemplateRef,
 		Namespace:   in.Namespace,
 		ClusterName: in.ClusterName,
+		Name:        in.Name,
 		OwnerRef:    in.OwnerRef,
 		Labels:      in.Labels,
 		Annotations: in.Annotations,
@@ -128,6 +133,10 @@ type GenerateTemplateInput struct {
 	// ClusterName is the cluster this object is linked to.
 	ClusterName string
 
+	// Name is an optional name of the cloned object; if not set, a name is generated from the name of the template.
+	// +optional
+	Name string
+
 	// OwnerRef is an optional OwnerReference to attach to the cloned object.
 	// +optional
 	OwnerRef *metav1.OwnerReference
@@ -156,7 +165,10 @@ func GenerateTemplate(in *GenerateTemplateInput) (*unstructured.Unstructured, er
 	to.SetFinalizers(nil)
 	to.SetUID("")
 	to.SetSelfLink("")
-	to.SetName(names.SimpleNameGenerator.GenerateName(in.Template.GetName() + "-"))
+	to.SetName(in.Name)
+	if in.Name == "" {
+		to.SetName(names.SimpleNameGenerator.GenerateName(in.Template.GetName() + "-"))
+	}
 	to.SetNamespace(in.Namespace)
 
 	// Set annotations.
@@ -26,6 +26,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	kcfg "sigs.k8s.io/cluster-api/util/kubeconfig"
+	"sigs.k8s.io/cluster-api/util/tracing"
 )
 
 const (
@@ -62,6 +63,7 @@ func RESTConfig(ctx context.Context, sourceName string, c client.Reader, cluster
 
 	restConfig.UserAgent = DefaultClusterAPIUserAgent(sourceName)
 	restConfig.Timeout = defaultClientTimeout
+	restConfig.Wrap(tracing.WrapTransport)
 
 	return restConfig, nil
 }
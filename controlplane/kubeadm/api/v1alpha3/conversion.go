@@ -68,6 +68,8 @@ func (src *KubeadmControlPlane) ConvertTo(dstRaw conversion.Hub) error {
 	}
 
 	dst.Spec.KubeadmConfigSpec.Ignition = restored.Spec.KubeadmConfigSpec.Ignition
+	dst.Spec.KubeadmConfigSpec.ImageOverrides = restored.Spec.KubeadmConfigSpec.ImageOverrides
+	dst.Spec.KubeadmConfigSpec.ReadinessChecks = restored.Spec.KubeadmConfigSpec.ReadinessChecks
 	if restored.Spec.KubeadmConfigSpec.InitConfiguration != nil {
 		if dst.Spec.KubeadmConfigSpec.InitConfiguration == nil {
 			dst.Spec.KubeadmConfigSpec.InitConfiguration = &bootstrapv1.InitConfiguration{}
@@ -102,6 +104,14 @@ func (src *KubeadmControlPlane) ConvertTo(dstRaw conversion.Hub) error {
 	if restored.Spec.RemediationStrategy != nil {
 		dst.Spec.RemediationStrategy = restored.Spec.RemediationStrategy
 	}
+	if restored.Spec.FailureDomainSpreadPolicy != nil {
+		dst.Spec.FailureDomainSpreadPolicy = restored.Spec.FailureDomainSpreadPolicy
+	}
+	if restored.Spec.ScaleUpStrategy != nil {
+		dst.Spec.ScaleUpStrategy = restored.Spec.ScaleUpStrategy
+	}
+	dst.Spec.EtcdLearnerMode = restored.Spec.EtcdLearnerMode
+	dst.Spec.UpgradePolicy = restored.Spec.UpgradePolicy
 	if restored.Status.LastRemediation != nil {
 		dst.Status.LastRemediation = restored.Status.LastRemediation
 	}
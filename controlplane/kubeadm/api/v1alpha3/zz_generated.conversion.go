@@ -202,6 +202,10 @@ func autoConvert_v1beta1_KubeadmControlPlaneSpec_To_v1alpha3_KubeadmControlPlane
 	// WARNING: in.RolloutAfter requires manual conversion: does not exist in peer-type
 	out.RolloutStrategy = (*RolloutStrategy)(unsafe.Pointer(in.RolloutStrategy))
 	// WARNING: in.RemediationStrategy requires manual conversion: does not exist in peer-type
+	// WARNING: in.FailureDomainSpreadPolicy requires manual conversion: does not exist in peer-type
+	// WARNING: in.ScaleUpStrategy requires manual conversion: does not exist in peer-type
+	// WARNING: in.EtcdLearnerMode requires manual conversion: does not exist in peer-type
+	// WARNING: in.UpgradePolicy requires manual conversion: does not exist in peer-type
 	return nil
 }
 
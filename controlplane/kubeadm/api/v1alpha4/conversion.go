@@ -51,6 +51,8 @@ func (src *KubeadmControlPlane) ConvertTo(dstRaw conversion.Hub) error {
 	}
 
 	dst.Spec.KubeadmConfigSpec.Ignition = restored.Spec.KubeadmConfigSpec.Ignition
+	dst.Spec.KubeadmConfigSpec.ImageOverrides = restored.Spec.KubeadmConfigSpec.ImageOverrides
+	dst.Spec.KubeadmConfigSpec.ReadinessChecks = restored.Spec.KubeadmConfigSpec.ReadinessChecks
 	if restored.Spec.KubeadmConfigSpec.InitConfiguration != nil {
 		if dst.Spec.KubeadmConfigSpec.InitConfiguration == nil {
 			dst.Spec.KubeadmConfigSpec.InitConfiguration = &bootstrapv1.InitConfiguration{}
@@ -87,6 +89,14 @@ func (src *KubeadmControlPlane) ConvertTo(dstRaw conversion.Hub) error {
 	if restored.Spec.RemediationStrategy != nil {
 		dst.Spec.RemediationStrategy = restored.Spec.RemediationStrategy
 	}
+	if restored.Spec.FailureDomainSpreadPolicy != nil {
+		dst.Spec.FailureDomainSpreadPolicy = restored.Spec.FailureDomainSpreadPolicy
+	}
+	if restored.Spec.ScaleUpStrategy != nil {
+		dst.Spec.ScaleUpStrategy = restored.Spec.ScaleUpStrategy
+	}
+	dst.Spec.EtcdLearnerMode = restored.Spec.EtcdLearnerMode
+	dst.Spec.UpgradePolicy = restored.Spec.UpgradePolicy
 	if restored.Status.LastRemediation != nil {
 		dst.Status.LastRemediation = restored.Status.LastRemediation
 	}
@@ -133,6 +143,8 @@ func (src *KubeadmControlPlaneTemplate) ConvertTo(dstRaw conversion.Hub) error {
 	dst.Spec.Template.Spec.KubeadmConfigSpec.Files = restored.Spec.Template.Spec.KubeadmConfigSpec.Files
 	dst.Spec.Template.Spec.KubeadmConfigSpec.Users = restored.Spec.Template.Spec.KubeadmConfigSpec.Users
 	dst.Spec.Template.Spec.KubeadmConfigSpec.Ignition = restored.Spec.Template.Spec.KubeadmConfigSpec.Ignition
+	dst.Spec.Template.Spec.KubeadmConfigSpec.ImageOverrides = restored.Spec.Template.Spec.KubeadmConfigSpec.ImageOverrides
+	dst.Spec.Template.Spec.KubeadmConfigSpec.ReadinessChecks = restored.Spec.Template.Spec.KubeadmConfigSpec.ReadinessChecks
 	dst.Spec.Template.Spec.MachineTemplate = restored.Spec.Template.Spec.MachineTemplate
 
 	if restored.Spec.Template.Spec.KubeadmConfigSpec.Users != nil {
@@ -188,6 +200,14 @@ func (src *KubeadmControlPlaneTemplate) ConvertTo(dstRaw conversion.Hub) error {
 	if restored.Spec.Template.Spec.RemediationStrategy != nil {
 		dst.Spec.Template.Spec.RemediationStrategy = restored.Spec.Template.Spec.RemediationStrategy
 	}
+	if restored.Spec.Template.Spec.FailureDomainSpreadPolicy != nil {
+		dst.Spec.Template.Spec.FailureDomainSpreadPolicy = restored.Spec.Template.Spec.FailureDomainSpreadPolicy
+	}
+	if restored.Spec.Template.Spec.ScaleUpStrategy != nil {
+		dst.Spec.Template.Spec.ScaleUpStrategy = restored.Spec.Template.Spec.ScaleUpStrategy
+	}
+	dst.Spec.Template.Spec.EtcdLearnerMode = restored.Spec.Template.Spec.EtcdLearnerMode
+	dst.Spec.Template.Spec.UpgradePolicy = restored.Spec.Template.Spec.UpgradePolicy
 
 	return nil
 }
@@ -279,6 +299,10 @@ func Convert_v1beta1_KubeadmControlPlaneMachineTemplate_To_v1alpha4_KubeadmContr
 func Convert_v1beta1_KubeadmControlPlaneSpec_To_v1alpha4_KubeadmControlPlaneSpec(in *controlplanev1.KubeadmControlPlaneSpec, out *KubeadmControlPlaneSpec, scope apiconversion.Scope) error {
 	// .RolloutBefore was added in v1beta1.
 	// .RemediationStrategy was added in v1beta1.
+	// .FailureDomainSpreadPolicy was added in v1beta1.
+	// .ScaleUpStrategy was added in v1beta1.
+	// .EtcdLearnerMode was added in v1beta1.
+	// .UpgradePolicy was added in v1beta1.
 	return autoConvert_v1beta1_KubeadmControlPlaneSpec_To_v1alpha4_KubeadmControlPlaneSpec(in, out, scope)
 }
 
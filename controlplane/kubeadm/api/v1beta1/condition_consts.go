@@ -54,6 +54,11 @@ const (
 	// RollingUpdateInProgressReason (Severity=Warning) documents a KubeadmControlPlane object executing a
 	// rolling upgrade for aligning the machines spec to the desired state.
 	RollingUpdateInProgressReason = "RollingUpdateInProgress"
+
+	// UnsupportedVersionSkewReason (Severity=Error) documents a KubeadmControlPlane object not executing a
+	// rolling upgrade because the new version skips a minor version of the control plane running in the workload
+	// cluster, or it violates the version skew policy for the kubelets of the MachineDeployments of the Cluster.
+	UnsupportedVersionSkewReason = "UnsupportedVersionSkew"
 )
 
 const (
@@ -132,6 +137,10 @@ const (
 	// EtcdMemberUnhealthyReason (Severity=Error) documents a Machine's etcd member is unhealthy.
 	EtcdMemberUnhealthyReason = "EtcdMemberUnhealthy"
 
+	// EtcdMemberLearnerReason (Severity=Info) documents a Machine's etcd member is a learner waiting to be promoted
+	// to a voting member.
+	EtcdMemberLearnerReason = "EtcdMemberLearner"
+
 	// MachinesCreatedCondition documents that the machines controlled by the KubeadmControlPlane are created.
 	// When this condition is false, it indicates that there was an error when cloning the infrastructure/bootstrap template or
 	// when generating the machine object.
@@ -149,3 +158,26 @@ const (
 	// generate a machine object.
 	MachineGenerationFailedReason = "MachineGenerationFailed"
 )
+
+// Conditions and condition Reasons for the EtcdSnapshot and EtcdRestore objects.
+
+const (
+	// EtcdSnapshotCompletedCondition documents that the snapshot has been taken and uploaded to the backend.
+	EtcdSnapshotCompletedCondition clusterv1.ConditionType = "SnapshotCompleted"
+
+	// EtcdSnapshotFailedReason (Severity=Error) documents an EtcdSnapshot failing to take or to upload the snapshot.
+	EtcdSnapshotFailedReason = "SnapshotFailed"
+
+	// EtcdRestoreCompletedCondition documents that etcd has been restored from the snapshot.
+	EtcdRestoreCompletedCondition clusterv1.ConditionType = "RestoreCompleted"
+
+	// EtcdRestoreValidationFailedReason (Severity=Error) documents an EtcdRestore rejected by the KubeadmControlPlane
+	// validation, e.g. because the snapshot is not completed or the control plane has more than one replica.
+	EtcdRestoreValidationFailedReason = "ValidationFailed"
+
+	// EtcdRestoreInProgressReason (Severity=Info) documents an EtcdRestore running on the control plane node.
+	EtcdRestoreInProgressReason = "RestoreInProgress"
+
+	// EtcdRestoreFailedReason (Severity=Error) documents an EtcdRestore failing on the control plane node.
+	EtcdRestoreFailedReason = "RestoreFailed"
+)
@@ -0,0 +1,142 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const (
+	// EtcdRestoreAnnotation is set on a KubeadmControlPlane paused by an EtcdRestore, with the name of the
+	// EtcdRestore as value. It is removed together with the paused annotation when the restore completes or fails.
+	EtcdRestoreAnnotation = "controlplane.cluster.x-k8s.io/etcd-restore"
+
+	// DefaultEtcdRestoreHelperImage is the default image used to download snapshots and to swap
+	// the etcd data directory on the control plane node.
+	DefaultEtcdRestoreHelperImage = "curlimages/curl:8.4.0"
+)
+
+// EtcdRestorePhase is a string representation of an EtcdRestore phase.
+type EtcdRestorePhase string
+
+const (
+	// EtcdRestorePhasePending is the EtcdRestore state when the restore has not been validated yet.
+	EtcdRestorePhasePending = EtcdRestorePhase("Pending")
+
+	// EtcdRestorePhaseRestoring is the EtcdRestore state when the KubeadmControlPlane has been paused and
+	// the snapshot is being restored on the control plane node.
+	EtcdRestorePhaseRestoring = EtcdRestorePhase("Restoring")
+
+	// EtcdRestorePhaseCompleted is the EtcdRestore state when etcd has been restored from the snapshot.
+	EtcdRestorePhaseCompleted = EtcdRestorePhase("Completed")
+
+	// EtcdRestorePhaseFailed is the EtcdRestore state when the restore has been rejected or it failed.
+	EtcdRestorePhaseFailed = EtcdRestorePhase("Failed")
+)
+
+// EtcdRestoreSpec defines the desired state of EtcdRestore.
+type EtcdRestoreSpec struct {
+	// ControlPlaneName is the name of the KubeadmControlPlane, in the same namespace, managing
+	// the etcd cluster to restore.
+	// +kubebuilder:validation:MinLength=1
+	ControlPlaneName string `json:"controlPlaneName"`
+
+	// SnapshotName is the name of a completed EtcdSnapshot, in the same namespace, taken from the etcd cluster
+	// of the same KubeadmControlPlane.
+	// +kubebuilder:validation:MinLength=1
+	SnapshotName string `json:"snapshotName"`
+
+	// HelperImage is the image used to download the snapshot and to swap the etcd data directory
+	// on the control plane node; it must provide sh and curl.
+	// Defaults to curlimages/curl:8.4.0.
+	// +optional
+	HelperImage string `json:"helperImage,omitempty"`
+}
+
+// EtcdRestoreStatus defines the observed state of EtcdRestore.
+type EtcdRestoreStatus struct {
+	// Phase represents the current phase of the restore.
+	// E.g. Pending, Restoring, Completed, Failed.
+	// +optional
+	Phase EtcdRestorePhase `json:"phase,omitempty"`
+
+	// NodeName is the name of the control plane node the snapshot is restored on.
+	// +optional
+	NodeName string `json:"nodeName,omitempty"`
+
+	// StartTime is the time the restore started on the control plane node.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is the time the restore completed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// FailureMessage contains an error message explaining why the restore was rejected or failed.
+	// +optional
+	FailureMessage *string `json:"failureMessage,omitempty"`
+
+	// Conditions defines current service state of the EtcdRestore.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=etcdrestores,scope=Namespaced,categories=cluster-api
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Control Plane",type="string",JSONPath=".spec.controlPlaneName",description="KubeadmControlPlane managing the etcd cluster"
+// +kubebuilder:printcolumn:name="Snapshot",type="string",JSONPath=".spec.snapshotName",description="EtcdSnapshot to restore"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="EtcdRestore status such as Pending/Restoring/Completed/Failed"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of EtcdRestore"
+// +k8s:conversion-gen=false
+
+// EtcdRestore is the Schema for the etcdrestores API.
+// An EtcdRestore restores the etcd cluster of a KubeadmControlPlane from an EtcdSnapshot; the KubeadmControlPlane
+// is paused while the restore is in progress.
+type EtcdRestore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   EtcdRestoreSpec   `json:"spec,omitempty"`
+	Status EtcdRestoreStatus `json:"status,omitempty"`
+}
+
+// GetConditions returns the set of conditions for this object.
+func (in *EtcdRestore) GetConditions() clusterv1.Conditions {
+	return in.Status.Conditions
+}
+
+// SetConditions sets the conditions on this object.
+func (in *EtcdRestore) SetConditions(conditions clusterv1.Conditions) {
+	in.Status.Conditions = conditions
+}
+
+// +kubebuilder:object:root=true
+
+// EtcdRestoreList contains a list of EtcdRestore.
+type EtcdRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []EtcdRestore `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&EtcdRestore{}, &EtcdRestoreList{})
+}
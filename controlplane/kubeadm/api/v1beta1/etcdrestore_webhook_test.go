@@ -0,0 +1,133 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilfeature "k8s.io/component-base/featuregate/testing"
+
+	"sigs.k8s.io/cluster-api/feature"
+	utildefaulting "sigs.k8s.io/cluster-api/util/defaulting"
+)
+
+func TestEtcdRestoreDefault(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.EtcdSnapshotRestore, true)()
+
+	g := NewWithT(t)
+
+	restore := &EtcdRestore{
+		ObjectMeta: metav1.ObjectMeta{Name: "restore", Namespace: metav1.NamespaceDefault},
+		Spec: EtcdRestoreSpec{
+			ControlPlaneName: "kcp",
+			SnapshotName:     "snapshot",
+		},
+	}
+	t.Run("for EtcdRestore", utildefaulting.DefaultValidateTest(restore.DeepCopy()))
+	restore.Default()
+
+	g.Expect(restore.Spec.HelperImage).To(Equal(DefaultEtcdRestoreHelperImage))
+}
+
+func TestEtcdRestoreValidateCreate(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.EtcdSnapshotRestore, true)()
+
+	valid := &EtcdRestore{
+		ObjectMeta: metav1.ObjectMeta{Name: "restore", Namespace: metav1.NamespaceDefault},
+		Spec: EtcdRestoreSpec{
+			ControlPlaneName: "kcp",
+			SnapshotName:     "snapshot",
+		},
+	}
+	withoutControlPlaneName := valid.DeepCopy()
+	withoutControlPlaneName.Spec.ControlPlaneName = ""
+	withoutSnapshotName := valid.DeepCopy()
+	withoutSnapshotName.Spec.SnapshotName = ""
+
+	tests := []struct {
+		name      string
+		restore   *EtcdRestore
+		expectErr bool
+	}{
+		{
+			name:      "should pass with a valid restore",
+			restore:   valid,
+			expectErr: false,
+		},
+		{
+			name:      "should fail without control plane name",
+			restore:   withoutControlPlaneName,
+			expectErr: true,
+		},
+		{
+			name:      "should fail without snapshot name",
+			restore:   withoutSnapshotName,
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			warnings, err := tt.restore.ValidateCreate()
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+			g.Expect(warnings).To(BeEmpty())
+		})
+	}
+}
+
+func TestEtcdRestoreValidateCreateFeatureGateDisabled(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.EtcdSnapshotRestore, false)()
+
+	g := NewWithT(t)
+
+	restore := &EtcdRestore{
+		Spec: EtcdRestoreSpec{
+			ControlPlaneName: "kcp",
+			SnapshotName:     "snapshot",
+		},
+	}
+	_, err := restore.ValidateCreate()
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestEtcdRestoreValidateUpdate(t *testing.T) {
+	g := NewWithT(t)
+
+	old := &EtcdRestore{
+		Spec: EtcdRestoreSpec{
+			ControlPlaneName: "kcp",
+			SnapshotName:     "snapshot",
+		},
+	}
+
+	withStatus := old.DeepCopy()
+	withStatus.Status.Phase = EtcdRestorePhaseRestoring
+	_, err := withStatus.ValidateUpdate(old)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	withOtherSnapshot := old.DeepCopy()
+	withOtherSnapshot.Spec.SnapshotName = "other-snapshot"
+	_, err = withOtherSnapshot.ValidateUpdate(old)
+	g.Expect(err).To(HaveOccurred())
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const (
+	// EtcdSnapshotUploaderURLKey is the key in the uploader Secret containing the base URL of the
+	// backend snapshots are uploaded to.
+	EtcdSnapshotUploaderURLKey = "url"
+
+	// EtcdSnapshotUploaderAuthorizationKey is the key in the uploader Secret containing the optional value
+	// of the Authorization header to be used when uploading and downloading snapshots.
+	EtcdSnapshotUploaderAuthorizationKey = "authorization"
+)
+
+// EtcdSnapshotPhase is a string representation of an EtcdSnapshot phase.
+type EtcdSnapshotPhase string
+
+const (
+	// EtcdSnapshotPhasePending is the EtcdSnapshot state when the snapshot has not been taken yet.
+	EtcdSnapshotPhasePending = EtcdSnapshotPhase("Pending")
+
+	// EtcdSnapshotPhaseCompleted is the EtcdSnapshot state when the snapshot has been uploaded to the backend.
+	EtcdSnapshotPhaseCompleted = EtcdSnapshotPhase("Completed")
+
+	// EtcdSnapshotPhaseFailed is the EtcdSnapshot state when taking or uploading the snapshot failed.
+	// Failed snapshots are not retried; a new EtcdSnapshot must be created instead.
+	EtcdSnapshotPhaseFailed = EtcdSnapshotPhase("Failed")
+)
+
+// EtcdSnapshotSpec defines the desired state of EtcdSnapshot.
+type EtcdSnapshotSpec struct {
+	// ControlPlaneName is the name of the KubeadmControlPlane, in the same namespace, managing
+	// the etcd cluster to take a snapshot of.
+	// +kubebuilder:validation:MinLength=1
+	ControlPlaneName string `json:"controlPlaneName"`
+
+	// Uploader defines where the snapshot is uploaded to.
+	Uploader EtcdSnapshotUploader `json:"uploader"`
+}
+
+// EtcdSnapshotUploader defines the backend etcd snapshots are uploaded to.
+// Snapshots are uploaded with an HTTP PUT request to <url>/<namespace>/<control plane name>/<snapshot name>.db,
+// so any object-store-like backend accepting PUT requests can be used.
+type EtcdSnapshotUploader struct {
+	// SecretName is the name of a Secret, in the same namespace, containing the configuration of the backend.
+	// The Secret must contain the base URL of the backend under the "url" key, and it can contain the value
+	// of the Authorization header to be used for requests under the "authorization" key.
+	// +kubebuilder:validation:MinLength=1
+	SecretName string `json:"secretName"`
+}
+
+// EtcdSnapshotStatus defines the observed state of EtcdSnapshot.
+type EtcdSnapshotStatus struct {
+	// Phase represents the current phase of the snapshot.
+	// E.g. Pending, Completed, Failed.
+	// +optional
+	Phase EtcdSnapshotPhase `json:"phase,omitempty"`
+
+	// Location is the URL the snapshot has been uploaded to.
+	// +optional
+	Location string `json:"location,omitempty"`
+
+	// Size is the size of the snapshot in bytes.
+	// +optional
+	Size *int64 `json:"size,omitempty"`
+
+	// Member is the name of the etcd member the snapshot has been taken from.
+	// +optional
+	Member string `json:"member,omitempty"`
+
+	// CompletionTime is the time the snapshot has been uploaded to the backend.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// FailureMessage contains an error message explaining why the snapshot failed.
+	// +optional
+	FailureMessage *string `json:"failureMessage,omitempty"`
+
+	// Conditions defines current service state of the EtcdSnapshot.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=etcdsnapshots,scope=Namespaced,categories=cluster-api
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Control Plane",type="string",JSONPath=".spec.controlPlaneName",description="KubeadmControlPlane managing the etcd cluster"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="EtcdSnapshot status such as Pending/Completed/Failed"
+// +kubebuilder:printcolumn:name="Size",type="integer",JSONPath=".status.size",description="Size of the snapshot in bytes"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of EtcdSnapshot"
+// +k8s:conversion-gen=false
+
+// EtcdSnapshot is the Schema for the etcdsnapshots API.
+// An EtcdSnapshot takes a snapshot of the etcd cluster of a KubeadmControlPlane and uploads it to a backend.
+type EtcdSnapshot struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   EtcdSnapshotSpec   `json:"spec,omitempty"`
+	Status EtcdSnapshotStatus `json:"status,omitempty"`
+}
+
+// GetConditions returns the set of conditions for this object.
+func (in *EtcdSnapshot) GetConditions() clusterv1.Conditions {
+	return in.Status.Conditions
+}
+
+// SetConditions sets the conditions on this object.
+func (in *EtcdSnapshot) SetConditions(conditions clusterv1.Conditions) {
+	in.Status.Conditions = conditions
+}
+
+// +kubebuilder:object:root=true
+
+// EtcdSnapshotList contains a list of EtcdSnapshot.
+type EtcdSnapshotList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []EtcdSnapshot `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&EtcdSnapshot{}, &EtcdSnapshotList{})
+}
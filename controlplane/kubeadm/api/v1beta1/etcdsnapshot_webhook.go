@@ -0,0 +1,85 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+	"reflect"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"sigs.k8s.io/cluster-api/feature"
+)
+
+func (in *EtcdSnapshot) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(in).
+		Complete()
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-controlplane-cluster-x-k8s-io-v1beta1-etcdsnapshot,mutating=false,failurePolicy=fail,groups=controlplane.cluster.x-k8s.io,resources=etcdsnapshots,versions=v1beta1,name=validation.etcdsnapshot.controlplane.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
+
+var _ webhook.Validator = &EtcdSnapshot{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
+func (in *EtcdSnapshot) ValidateCreate() (admission.Warnings, error) {
+	// NOTE: EtcdSnapshot is behind the EtcdSnapshotRestore feature gate flag; the web hook
+	// must prevent creating new objects in case the feature flag is disabled.
+	if !feature.Gates.Enabled(feature.EtcdSnapshotRestore) {
+		return nil, field.Forbidden(
+			field.NewPath("spec"),
+			"can be set only if the EtcdSnapshotRestore feature flag is enabled",
+		)
+	}
+
+	var allErrs field.ErrorList
+	if in.Spec.ControlPlaneName == "" {
+		allErrs = append(allErrs, field.Required(field.NewPath("spec", "controlPlaneName"), "must be set"))
+	}
+	if in.Spec.Uploader.SecretName == "" {
+		allErrs = append(allErrs, field.Required(field.NewPath("spec", "uploader", "secretName"), "must be set"))
+	}
+	if len(allErrs) > 0 {
+		return nil, apierrors.NewInvalid(GroupVersion.WithKind("EtcdSnapshot").GroupKind(), in.Name, allErrs)
+	}
+	return nil, nil
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
+func (in *EtcdSnapshot) ValidateUpdate(oldRaw runtime.Object) (admission.Warnings, error) {
+	old, ok := oldRaw.(*EtcdSnapshot)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected an EtcdSnapshot but got a %T", oldRaw))
+	}
+
+	if !reflect.DeepEqual(in.Spec, old.Spec) {
+		return nil, apierrors.NewInvalid(GroupVersion.WithKind("EtcdSnapshot").GroupKind(), in.Name, field.ErrorList{
+			field.Forbidden(field.NewPath("spec"), "EtcdSnapshot spec field is immutable. Please create a new resource instead."),
+		})
+	}
+	return nil, nil
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
+func (in *EtcdSnapshot) ValidateDelete() (admission.Warnings, error) {
+	return nil, nil
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilfeature "k8s.io/component-base/featuregate/testing"
+
+	"sigs.k8s.io/cluster-api/feature"
+)
+
+func TestEtcdSnapshotValidateCreate(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.EtcdSnapshotRestore, true)()
+
+	valid := &EtcdSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Name: "snapshot", Namespace: metav1.NamespaceDefault},
+		Spec: EtcdSnapshotSpec{
+			ControlPlaneName: "kcp",
+			Uploader:         EtcdSnapshotUploader{SecretName: "uploader"},
+		},
+	}
+	withoutControlPlaneName := valid.DeepCopy()
+	withoutControlPlaneName.Spec.ControlPlaneName = ""
+	withoutUploaderSecret := valid.DeepCopy()
+	withoutUploaderSecret.Spec.Uploader.SecretName = ""
+
+	tests := []struct {
+		name      string
+		snapshot  *EtcdSnapshot
+		expectErr bool
+	}{
+		{
+			name:      "should pass with a valid snapshot",
+			snapshot:  valid,
+			expectErr: false,
+		},
+		{
+			name:      "should fail without control plane name",
+			snapshot:  withoutControlPlaneName,
+			expectErr: true,
+		},
+		{
+			name:      "should fail without uploader secret name",
+			snapshot:  withoutUploaderSecret,
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			warnings, err := tt.snapshot.ValidateCreate()
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+			g.Expect(warnings).To(BeEmpty())
+		})
+	}
+}
+
+func TestEtcdSnapshotValidateCreateFeatureGateDisabled(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.EtcdSnapshotRestore, false)()
+
+	g := NewWithT(t)
+
+	snapshot := &EtcdSnapshot{
+		Spec: EtcdSnapshotSpec{
+			ControlPlaneName: "kcp",
+			Uploader:         EtcdSnapshotUploader{SecretName: "uploader"},
+		},
+	}
+	_, err := snapshot.ValidateCreate()
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestEtcdSnapshotValidateUpdate(t *testing.T) {
+	g := NewWithT(t)
+
+	old := &EtcdSnapshot{
+		Spec: EtcdSnapshotSpec{
+			ControlPlaneName: "kcp",
+			Uploader:         EtcdSnapshotUploader{SecretName: "uploader"},
+		},
+	}
+
+	withStatus := old.DeepCopy()
+	withStatus.Status.Phase = EtcdSnapshotPhaseCompleted
+	_, err := withStatus.ValidateUpdate(old)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	withOtherSecret := old.DeepCopy()
+	withOtherSecret.Spec.Uploader.SecretName = "other-uploader"
+	_, err = withOtherSecret.ValidateUpdate(old)
+	g.Expect(err).To(HaveOccurred())
+}
@@ -37,6 +37,47 @@ const (
 	RollingUpdateStrategyType RolloutStrategyType = "RollingUpdate"
 )
 
+// RemediationOrderType defines the order in which unhealthy machines of a KubeadmControlPlane are remediated.
+type RemediationOrderType string
+
+const (
+	// OldestRemediationOrderType remediates the oldest unhealthy machine first.
+	OldestRemediationOrderType RemediationOrderType = "Oldest"
+
+	// EtcdUnhealthyFirstRemediationOrderType remediates unhealthy machines whose etcd member is already unhealthy first,
+	// and then the oldest unhealthy machine; remediating a machine with an unhealthy etcd member does not reduce
+	// the number of healthy etcd members.
+	EtcdUnhealthyFirstRemediationOrderType RemediationOrderType = "EtcdUnhealthyFirst"
+)
+
+// EtcdQuorumPolicyType defines how etcd quorum is preserved when remediating machines of a KubeadmControlPlane.
+type EtcdQuorumPolicyType string
+
+const (
+	// PreserveQuorumEtcdQuorumPolicyType remediates a machine only if etcd keeps quorum after remediation.
+	PreserveQuorumEtcdQuorumPolicyType EtcdQuorumPolicyType = "PreserveQuorum"
+
+	// PreserveQuorumMarginEtcdQuorumPolicyType remediates a machine only if etcd keeps quorum after remediation
+	// and it can still tolerate the failure of an additional member, i.e. the quorum margin is greater than 0,
+	// unless the machine has the RemediationForceAnnotation.
+	PreserveQuorumMarginEtcdQuorumPolicyType EtcdQuorumPolicyType = "PreserveQuorumMargin"
+)
+
+// AddonUpgradePolicyType defines how KCP upgrades an add-on deployed by kubeadm, i.e. CoreDNS or kube-proxy.
+type AddonUpgradePolicyType string
+
+const (
+	// UpgradeAddonUpgradePolicyType upgrades the add-on once all the control plane machines are up-to-date.
+	UpgradeAddonUpgradePolicyType AddonUpgradePolicyType = "Upgrade"
+
+	// DeferAddonUpgradePolicyType upgrades the add-on once all the Machines of the Cluster, including the worker
+	// Machines and the MachinePools, are running the Kubernetes version of the KubeadmControlPlane.
+	DeferAddonUpgradePolicyType AddonUpgradePolicyType = "Defer"
+
+	// SkipAddonUpgradePolicyType never upgrades the add-on, e.g. because it is managed externally.
+	SkipAddonUpgradePolicyType AddonUpgradePolicyType = "Skip"
+)
+
 const (
 	// KubeadmControlPlaneFinalizer is the finalizer applied to KubeadmControlPlane resources
 	// by its managing controller.
@@ -65,6 +106,11 @@ const (
 	// failures in updating remediation retry (the counter restarts from zero).
 	RemediationForAnnotation = "controlplane.cluster.x-k8s.io/remediation-for"
 
+	// RemediationForceAnnotation can be set on an unhealthy machine to force its remediation when the
+	// PreserveQuorumMargin etcd quorum policy would block it because etcd can't tolerate any additional failure
+	// after remediation. Remediations which could result in etcd losing quorum are never forced.
+	RemediationForceAnnotation = "controlplane.cluster.x-k8s.io/remediation-force"
+
 	// DefaultMinHealthyPeriod defines the default minimum period before we consider a remediation on a
 	// machine unrelated from the previous remediation.
 	DefaultMinHealthyPeriod = 1 * time.Hour
@@ -117,6 +163,32 @@ type KubeadmControlPlaneSpec struct {
 	// The RemediationStrategy that controls how control plane machine remediation happens.
 	// +optional
 	RemediationStrategy *RemediationStrategy `json:"remediationStrategy,omitempty"`
+
+	// FailureDomainSpreadPolicy defines how control plane machines are spread across failure domains.
+	// Control plane machines are always created in the failure domain with the fewest machines; when using
+	// the Rebalance type, control plane machines are also rolled out to restore the balance across failure domains,
+	// e.g. after a failure domain that was unavailable comes back. MaxUnavailable is not supported, because
+	// control plane machines are always rebalanced one at a time.
+	// +optional
+	FailureDomainSpreadPolicy *clusterv1.FailureDomainSpreadPolicy `json:"failureDomainSpreadPolicy,omitempty"`
+
+	// ScaleUpStrategy controls how control plane machines are created when scaling up.
+	// +optional
+	ScaleUpStrategy *ScaleUpStrategy `json:"scaleUpStrategy,omitempty"`
+
+	// EtcdLearnerMode defines if new control plane machines join the etcd cluster as learners, i.e. as non-voting
+	// members that are promoted to voting members only once in sync with the leader, which reduces the risk of losing
+	// the etcd quorum during scale up and rollouts. While a member is a learner, KCP waits for its promotion before
+	// proceeding with other scale up or scale down operations.
+	// It is used only for Kubernetes versions >= v1.27.0 when etcd is managed by KCP, by setting the EtcdLearnerMode
+	// kubeadm feature gate; if not set, the feature gate is left as defined in the kubeadm ClusterConfiguration.
+	// +optional
+	EtcdLearnerMode *bool `json:"etcdLearnerMode,omitempty"`
+
+	// UpgradePolicy defines how the CoreDNS and kube-proxy add-ons deployed by kubeadm are upgraded
+	// during control plane upgrades.
+	// +optional
+	UpgradePolicy *UpgradePolicy `json:"upgradePolicy,omitempty"`
 }
 
 // KubeadmControlPlaneMachineTemplate defines the template for Machines
@@ -226,6 +298,68 @@ type RemediationStrategy struct {
 	// If not set, this value is defaulted to 1h.
 	// +optional
 	MinHealthyPeriod *metav1.Duration `json:"minHealthyPeriod,omitempty"`
+
+	// Order defines the order in which unhealthy machines are remediated, one at a time.
+	// Supported values are "Oldest", which remediates the oldest unhealthy machine first, and "EtcdUnhealthyFirst",
+	// which remediates unhealthy machines whose etcd member is already unhealthy first.
+	//
+	// If not set, this value is defaulted to Oldest.
+	// +kubebuilder:validation:Enum=Oldest;EtcdUnhealthyFirst
+	// +optional
+	Order RemediationOrderType `json:"order,omitempty"`
+
+	// EtcdQuorumPolicy defines how KCP preserves etcd quorum when remediating machines, if etcd is managed by KCP.
+	// Supported values are "PreserveQuorum", which remediates a machine only if etcd keeps quorum after remediation,
+	// and "PreserveQuorumMargin", which additionally requires etcd to tolerate the failure of another member after
+	// remediation, unless the machine to be remediated has the controlplane.cluster.x-k8s.io/remediation-force
+	// annotation.
+	//
+	// If not set, this value is defaulted to PreserveQuorum.
+	// +kubebuilder:validation:Enum=PreserveQuorum;PreserveQuorumMargin
+	// +optional
+	EtcdQuorumPolicy EtcdQuorumPolicyType `json:"etcdQuorumPolicy,omitempty"`
+}
+
+// ScaleUpStrategy describes how control plane machines are created when scaling up.
+type ScaleUpStrategy struct {
+	// MaxConcurrent is the maximum number of control plane machines created at the same time when scaling up,
+	// e.g. from 1 to 3 or from 3 to 5 replicas, when etcd is external. The machines are created once all the existing
+	// machines are healthy and their infrastructure is provisioned concurrently.
+	// When etcd is managed by KCP, machines are always created one at a time, waiting for the etcd member of each
+	// machine to be healthy before creating the next one, because adding etcd members concurrently can cause the
+	// loss of quorum. Machines created while rolling out are always created one at a time.
+	// Defaults to 1.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxConcurrent *int32 `json:"maxConcurrent,omitempty"`
+}
+
+// UpgradePolicy defines how the CoreDNS and kube-proxy add-ons deployed by kubeadm are upgraded.
+type UpgradePolicy struct {
+	// CoreDNS defines how CoreDNS is upgraded.
+	// +optional
+	CoreDNS *AddonUpgradePolicy `json:"coreDNS,omitempty"`
+
+	// KubeProxy defines how kube-proxy is upgraded.
+	// +optional
+	KubeProxy *AddonUpgradePolicy `json:"kubeProxy,omitempty"`
+}
+
+// AddonUpgradePolicy defines how an add-on deployed by kubeadm is upgraded.
+type AddonUpgradePolicy struct {
+	// Type of the upgrade policy, one of Upgrade, Defer or Skip.
+	// If not set, this value is defaulted to Upgrade.
+	// +kubebuilder:validation:Enum=Upgrade;Defer;Skip
+	// +optional
+	Type AddonUpgradePolicyType `json:"type,omitempty"`
+
+	// Version pins the version, i.e. the image tag, of the add-on.
+	// For CoreDNS it takes precedence over spec.kubeadmConfigSpec.clusterConfiguration.dns.imageTag and the
+	// upgrade must be supported by the CoreDNS Corefile migration; for kube-proxy it replaces the Kubernetes
+	// version of the KubeadmControlPlane and it must be within the supported version skew.
+	// It cannot be set if type is Skip.
+	// +optional
+	Version string `json:"version,omitempty"`
 }
 
 // KubeadmControlPlaneStatus defines the observed state of KubeadmControlPlane.
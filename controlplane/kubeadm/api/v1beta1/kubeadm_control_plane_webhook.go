@@ -33,6 +33,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
 	"sigs.k8s.io/cluster-api/internal/util/kubeadm"
 	"sigs.k8s.io/cluster-api/util/container"
@@ -174,6 +175,9 @@ func (in *KubeadmControlPlane) ValidateUpdate(old runtime.Object) (admission.War
 		{spec, kubeadmConfigSpec, diskSetup, "*"},
 		{spec, kubeadmConfigSpec, "format"},
 		{spec, kubeadmConfigSpec, "mounts"},
+		{spec, kubeadmConfigSpec, "imageOverrides"},
+		{spec, kubeadmConfigSpec, "imageOverrides", "*"},
+		{spec, kubeadmConfigSpec, "readinessChecks"},
 		{spec, "machineTemplate", "metadata"},
 		{spec, "machineTemplate", "metadata", "*"},
 		{spec, "machineTemplate", "infrastructureRef", "apiVersion"},
@@ -191,6 +195,13 @@ func (in *KubeadmControlPlane) ValidateUpdate(old runtime.Object) (admission.War
 		{spec, "rolloutBefore", "*"},
 		{spec, "rolloutStrategy"},
 		{spec, "rolloutStrategy", "*"},
+		{spec, "failureDomainSpreadPolicy"},
+		{spec, "failureDomainSpreadPolicy", "*"},
+		{spec, "scaleUpStrategy"},
+		{spec, "scaleUpStrategy", "*"},
+		{spec, "etcdLearnerMode"},
+		{spec, "upgradePolicy"},
+		{spec, "upgradePolicy", "*"},
 	}
 
 	allErrs := validateKubeadmControlPlaneSpec(in.Spec, in.Namespace, field.NewPath("spec"))
@@ -235,6 +246,7 @@ func (in *KubeadmControlPlane) ValidateUpdate(old runtime.Object) (admission.War
 	}
 
 	allErrs = append(allErrs, in.validateVersion(prev.Spec.Version)...)
+	allErrs = append(allErrs, in.validateRunningVersion(prev)...)
 	allErrs = append(allErrs, validateClusterConfiguration(in.Spec.KubeadmConfigSpec.ClusterConfiguration, prev.Spec.KubeadmConfigSpec.ClusterConfiguration, field.NewPath("spec", "kubeadmConfigSpec", "clusterConfiguration"))...)
 	allErrs = append(allErrs, in.validateCoreDNSVersion(prev)...)
 	allErrs = append(allErrs, in.Spec.KubeadmConfigSpec.Validate(field.NewPath("spec", "kubeadmConfigSpec"))...)
@@ -336,6 +348,8 @@ func validateKubeadmControlPlaneSpec(s KubeadmControlPlaneSpec, namespace string
 
 	allErrs = append(allErrs, validateRolloutBefore(s.RolloutBefore, pathPrefix.Child("rolloutBefore"))...)
 	allErrs = append(allErrs, validateRolloutStrategy(s.RolloutStrategy, s.Replicas, pathPrefix.Child("rolloutStrategy"))...)
+	allErrs = append(allErrs, validateFailureDomainSpreadPolicy(s.FailureDomainSpreadPolicy, pathPrefix.Child("failureDomainSpreadPolicy"))...)
+	allErrs = append(allErrs, validateUpgradePolicy(s.UpgradePolicy, s.Version, pathPrefix.Child("upgradePolicy"))...)
 
 	return allErrs
 }
@@ -356,6 +370,98 @@ func validateRolloutBefore(rolloutBefore *RolloutBefore, pathPrefix *field.Path)
 	return allErrs
 }
 
+// maxKubeProxyMinorVersionSkew is the maximum number of minor versions kube-proxy can be older than kube-apiserver.
+const maxKubeProxyMinorVersionSkew = 3
+
+// validateUpgradePolicy validates the upgrade policy for the CoreDNS and kube-proxy add-ons.
+// If kubernetesVersion is empty the version skew of kube-proxy is not validated.
+func validateUpgradePolicy(upgradePolicy *UpgradePolicy, kubernetesVersion string, pathPrefix *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if upgradePolicy == nil {
+		return allErrs
+	}
+
+	if upgradePolicy.CoreDNS != nil {
+		allErrs = append(allErrs, validateAddonUpgradePolicy(upgradePolicy.CoreDNS, pathPrefix.Child("coreDNS"))...)
+		if upgradePolicy.CoreDNS.Version != "" {
+			if _, err := version.ParseMajorMinorPatchTolerant(upgradePolicy.CoreDNS.Version); err != nil {
+				allErrs = append(allErrs, field.Invalid(pathPrefix.Child("coreDNS", "version"), upgradePolicy.CoreDNS.Version,
+					fmt.Sprintf("failed to parse CoreDNS version: %v", err)))
+			}
+		}
+	}
+
+	if upgradePolicy.KubeProxy != nil {
+		allErrs = append(allErrs, validateAddonUpgradePolicy(upgradePolicy.KubeProxy, pathPrefix.Child("kubeProxy"))...)
+		if upgradePolicy.KubeProxy.Version != "" {
+			allErrs = append(allErrs, validateKubeProxyVersion(upgradePolicy.KubeProxy.Version, kubernetesVersion, pathPrefix.Child("kubeProxy", "version"))...)
+		}
+	}
+
+	return allErrs
+}
+
+func validateAddonUpgradePolicy(addonUpgradePolicy *AddonUpgradePolicy, pathPrefix *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if addonUpgradePolicy.Type == SkipAddonUpgradePolicyType && addonUpgradePolicy.Version != "" {
+		allErrs = append(allErrs, field.Forbidden(pathPrefix.Child("version"), fmt.Sprintf("cannot be set if type is %s", SkipAddonUpgradePolicyType)))
+	}
+
+	return allErrs
+}
+
+// validateKubeProxyVersion validates that the pinned kube-proxy version is not newer than the Kubernetes version
+// of the control plane and within the supported version skew.
+func validateKubeProxyVersion(kubeProxyVersion, kubernetesVersion string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if !version.KubeSemver.MatchString(kubeProxyVersion) {
+		return append(allErrs, field.Invalid(fldPath, kubeProxyVersion, "must be a valid semantic version"))
+	}
+	if kubernetesVersion == "" {
+		return allErrs
+	}
+
+	pinnedVersion, err := version.ParseMajorMinorPatchTolerant(kubeProxyVersion)
+	if err != nil {
+		return append(allErrs, field.Invalid(fldPath, kubeProxyVersion, fmt.Sprintf("failed to parse kube-proxy version: %v", err)))
+	}
+	controlPlaneVersion, err := version.ParseMajorMinorPatchTolerant(kubernetesVersion)
+	if err != nil {
+		// The Kubernetes version is validated separately.
+		return allErrs
+	}
+
+	if pinnedVersion.GT(controlPlaneVersion) {
+		allErrs = append(allErrs, field.Forbidden(fldPath,
+			fmt.Sprintf("kube-proxy version %s cannot be newer than the Kubernetes version %s", kubeProxyVersion, kubernetesVersion)))
+	} else if pinnedVersion.Major != controlPlaneVersion.Major || pinnedVersion.Minor+maxKubeProxyMinorVersionSkew < controlPlaneVersion.Minor {
+		allErrs = append(allErrs, field.Forbidden(fldPath,
+			fmt.Sprintf("kube-proxy version %s cannot be more than %d minor versions older than the Kubernetes version %s", kubeProxyVersion, maxKubeProxyMinorVersionSkew, kubernetesVersion)))
+	}
+
+	return allErrs
+}
+
+func validateFailureDomainSpreadPolicy(policy *clusterv1.FailureDomainSpreadPolicy, pathPrefix *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	// KCP always replaces one Machine at a time, creating the new Machine first.
+	if policy != nil && policy.MaxUnavailable != nil {
+		allErrs = append(
+			allErrs,
+			field.Forbidden(
+				pathPrefix.Child("maxUnavailable"),
+				"is not supported by KubeadmControlPlane, which always rebalances one Machine at a time",
+			),
+		)
+	}
+
+	return allErrs
+}
+
 func validateRolloutStrategy(rolloutStrategy *RolloutStrategy, replicas *int32, pathPrefix *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -527,33 +633,32 @@ func paths(path []string, diff map[string]interface{}) [][]string {
 }
 
 func (in *KubeadmControlPlane) validateCoreDNSVersion(prev *KubeadmControlPlane) (allErrs field.ErrorList) {
-	if in.Spec.KubeadmConfigSpec.ClusterConfiguration == nil || prev.Spec.KubeadmConfigSpec.ClusterConfiguration == nil {
-		return allErrs
-	}
+	fromImageTag, _ := prev.coreDNSImageTag()
+	toImageTag, toPath := in.coreDNSImageTag()
+
 	// return if either current or target versions is empty
-	if prev.Spec.KubeadmConfigSpec.ClusterConfiguration.DNS.ImageTag == "" || in.Spec.KubeadmConfigSpec.ClusterConfiguration.DNS.ImageTag == "" {
+	if fromImageTag == "" || toImageTag == "" {
 		return allErrs
 	}
-	targetDNS := &in.Spec.KubeadmConfigSpec.ClusterConfiguration.DNS
 
-	fromVersion, err := version.ParseMajorMinorPatchTolerant(prev.Spec.KubeadmConfigSpec.ClusterConfiguration.DNS.ImageTag)
+	fromVersion, err := version.ParseMajorMinorPatchTolerant(fromImageTag)
 	if err != nil {
 		allErrs = append(allErrs,
 			field.Invalid(
-				field.NewPath("spec", "kubeadmConfigSpec", "clusterConfiguration", "dns", "imageTag"),
-				prev.Spec.KubeadmConfigSpec.ClusterConfiguration.DNS.ImageTag,
+				toPath,
+				fromImageTag,
 				fmt.Sprintf("failed to parse current CoreDNS version: %v", err),
 			),
 		)
 		return allErrs
 	}
 
-	toVersion, err := version.ParseMajorMinorPatchTolerant(targetDNS.ImageTag)
+	toVersion, err := version.ParseMajorMinorPatchTolerant(toImageTag)
 	if err != nil {
 		allErrs = append(allErrs,
 			field.Invalid(
-				field.NewPath("spec", "kubeadmConfigSpec", "clusterConfiguration", "dns", "imageTag"),
-				targetDNS.ImageTag,
+				toPath,
+				toImageTag,
 				fmt.Sprintf("failed to parse target CoreDNS version: %v", err),
 			),
 		)
@@ -568,7 +673,7 @@ func (in *KubeadmControlPlane) validateCoreDNSVersion(prev *KubeadmControlPlane)
 		allErrs = append(
 			allErrs,
 			field.Forbidden(
-				field.NewPath("spec", "kubeadmConfigSpec", "clusterConfiguration", "dns", "imageTag"),
+				toPath,
 				fmt.Sprintf("cannot migrate CoreDNS up to '%v' from '%v': %v", toVersion, fromVersion, err),
 			),
 		)
@@ -577,6 +682,19 @@ func (in *KubeadmControlPlane) validateCoreDNSVersion(prev *KubeadmControlPlane)
 	return allErrs
 }
 
+// coreDNSImageTag returns the CoreDNS image tag set in the KubeadmControlPlane, i.e. the version pinned in the
+// upgrade policy or the image tag of the ClusterConfiguration, together with the path of the corresponding field.
+func (in *KubeadmControlPlane) coreDNSImageTag() (string, *field.Path) {
+	if in.Spec.UpgradePolicy != nil && in.Spec.UpgradePolicy.CoreDNS != nil && in.Spec.UpgradePolicy.CoreDNS.Version != "" {
+		return in.Spec.UpgradePolicy.CoreDNS.Version, field.NewPath("spec", "upgradePolicy", "coreDNS", "version")
+	}
+	imageTagPath := field.NewPath("spec", "kubeadmConfigSpec", "clusterConfiguration", "dns", "imageTag")
+	if in.Spec.KubeadmConfigSpec.ClusterConfiguration == nil {
+		return "", imageTagPath
+	}
+	return in.Spec.KubeadmConfigSpec.ClusterConfiguration.DNS.ImageTag, imageTagPath
+}
+
 func (in *KubeadmControlPlane) validateVersion(previousVersion string) (allErrs field.ErrorList) {
 	fromVersion, err := version.ParseMajorMinorPatch(previousVersion)
 	if err != nil {
@@ -659,6 +777,43 @@ func (in *KubeadmControlPlane) validateVersion(previousVersion string) (allErrs
 	return allErrs
 }
 
+// validateRunningVersion validates that an update of the version is upgrading at most one minor version
+// from the oldest version running in the control plane, as reported in status.version; this prevents
+// skipping a minor version by changing the version again while a previous upgrade is still rolling out.
+func (in *KubeadmControlPlane) validateRunningVersion(prev *KubeadmControlPlane) (allErrs field.ErrorList) {
+	if prev.Status.Version == nil || in.Spec.Version == prev.Spec.Version {
+		return nil
+	}
+
+	runningVersion, err := version.ParseMajorMinorPatch(*prev.Status.Version)
+	if err != nil {
+		// Ignore invalid status versions; the status is not set by users.
+		return nil //nolint:nilerr
+	}
+	toVersion, err := version.ParseMajorMinorPatch(in.Spec.Version)
+	if err != nil {
+		// Invalid versions are reported by validateVersion.
+		return nil //nolint:nilerr
+	}
+
+	// Note: Checking against this ceilVersion allows upgrading to the next minor
+	// version irrespective of the patch version.
+	ceilVersion := semver.Version{
+		Major: runningVersion.Major,
+		Minor: runningVersion.Minor + 2,
+		Patch: 0,
+	}
+	if toVersion.GTE(ceilVersion) {
+		allErrs = append(allErrs,
+			field.Forbidden(
+				field.NewPath("spec", "version"),
+				fmt.Sprintf("cannot update Kubernetes version to %s while control plane machines are running %s; wait for the current upgrade to complete", in.Spec.Version, *prev.Status.Version),
+			),
+		)
+	}
+	return allErrs
+}
+
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
 func (in *KubeadmControlPlane) ValidateDelete() (admission.Warnings, error) {
 	return nil, nil
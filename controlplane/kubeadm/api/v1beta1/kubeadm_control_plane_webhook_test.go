@@ -24,9 +24,11 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	utilfeature "k8s.io/component-base/featuregate/testing"
 	"k8s.io/utils/pointer"
 
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
 	"sigs.k8s.io/cluster-api/feature"
 	utildefaulting "sigs.k8s.io/cluster-api/util/defaulting"
@@ -145,6 +147,12 @@ func TestKubeadmControlPlaneValidateCreate(t *testing.T) {
 		CertificatesExpiryDays: pointer.Int32(5), // less than minimum
 	}
 
+	invalidFailureDomainSpreadPolicy := valid.DeepCopy()
+	invalidFailureDomainSpreadPolicy.Spec.FailureDomainSpreadPolicy = &clusterv1.FailureDomainSpreadPolicy{
+		Type:           clusterv1.RebalanceFailureDomainSpreadPolicyType,
+		MaxUnavailable: pointer.Int32(2),
+	}
+
 	invalidIgnitionConfiguration := valid.DeepCopy()
 	invalidIgnitionConfiguration.Spec.KubeadmConfigSpec.Ignition = &bootstrapv1.IgnitionSpec{}
 
@@ -223,6 +231,11 @@ func TestKubeadmControlPlaneValidateCreate(t *testing.T) {
 			expectErr: true,
 			kcp:       invalidRolloutBeforeCertificateExpiryDays,
 		},
+		{
+			name:      "should return error when failureDomainSpreadPolicy.maxUnavailable is set",
+			expectErr: true,
+			kcp:       invalidFailureDomainSpreadPolicy,
+		},
 
 		{
 			name:                  "should return error when Ignition configuration is invalid",
@@ -417,6 +430,9 @@ func TestKubeadmControlPlaneValidateUpdate(t *testing.T) {
 		MinHealthyPeriod: &metav1.Duration{Duration: 10 * time.Hour},
 		RetryPeriod:      metav1.Duration{Duration: 10 * time.Minute},
 	}
+	validUpdate.Spec.FailureDomainSpreadPolicy = &clusterv1.FailureDomainSpreadPolicy{
+		Type: clusterv1.RebalanceFailureDomainSpreadPolicyType,
+	}
 	validUpdate.Spec.KubeadmConfigSpec.Format = bootstrapv1.CloudConfig
 
 	scaleToZero := before.DeepCopy()
@@ -541,6 +557,21 @@ func TestKubeadmControlPlaneValidateUpdate(t *testing.T) {
 		},
 	}
 
+	validPinnedCoreDNSToVersion := dns.DeepCopy()
+	validPinnedCoreDNSToVersion.Spec.UpgradePolicy = &UpgradePolicy{
+		CoreDNS: &AddonUpgradePolicy{Version: "1.7.0"},
+	}
+
+	invalidPinnedCoreDNSToVersion := dns.DeepCopy()
+	invalidPinnedCoreDNSToVersion.Spec.UpgradePolicy = &UpgradePolicy{
+		CoreDNS: &AddonUpgradePolicy{Version: "1.6.5"},
+	}
+
+	kubeProxyUpgradePolicy := before.DeepCopy()
+	kubeProxyUpgradePolicy.Spec.UpgradePolicy = &UpgradePolicy{
+		KubeProxy: &AddonUpgradePolicy{Type: DeferAddonUpgradePolicyType},
+	}
+
 	certificatesDir := before.DeepCopy()
 	certificatesDir.Spec.KubeadmConfigSpec.ClusterConfiguration.CertificatesDir = "a new certificates directory"
 
@@ -851,6 +882,24 @@ func TestKubeadmControlPlaneValidateUpdate(t *testing.T) {
 			kcp:       dnsInvalidCoreDNSToVersion,
 		},
 
+		{
+			name:      "should succeed when pinning a CoreDNS version which can be migrated to",
+			expectErr: false,
+			before:    dns,
+			kcp:       validPinnedCoreDNSToVersion,
+		},
+		{
+			name:      "should fail when pinning a CoreDNS version which cannot be migrated to",
+			expectErr: true,
+			before:    dns,
+			kcp:       invalidPinnedCoreDNSToVersion,
+		},
+		{
+			name:      "should succeed when changing the kube-proxy upgrade policy",
+			expectErr: false,
+			before:    before,
+			kcp:       kubeProxyUpgradePolicy,
+		},
 		{
 			name:      "should fail when making a change to the cluster config's certificatesDir",
 			expectErr: true,
@@ -1039,6 +1088,95 @@ func TestKubeadmControlPlaneValidateUpdate(t *testing.T) {
 	}
 }
 
+func TestValidateUpgradePolicy(t *testing.T) {
+	tests := []struct {
+		name              string
+		upgradePolicy     *UpgradePolicy
+		kubernetesVersion string
+		expectErr         bool
+	}{
+		{
+			name:              "should succeed when the upgrade policy is not set",
+			kubernetesVersion: "v1.28.0",
+		},
+		{
+			name: "should succeed when the add-ons are skipped or deferred",
+			upgradePolicy: &UpgradePolicy{
+				CoreDNS:   &AddonUpgradePolicy{Type: SkipAddonUpgradePolicyType},
+				KubeProxy: &AddonUpgradePolicy{Type: DeferAddonUpgradePolicyType},
+			},
+			kubernetesVersion: "v1.28.0",
+		},
+		{
+			name: "should succeed when valid versions are pinned",
+			upgradePolicy: &UpgradePolicy{
+				CoreDNS:   &AddonUpgradePolicy{Version: "v1.10.1"},
+				KubeProxy: &AddonUpgradePolicy{Version: "v1.27.3"},
+			},
+			kubernetesVersion: "v1.28.0",
+		},
+		{
+			name: "should fail when a version is pinned and the add-on is skipped",
+			upgradePolicy: &UpgradePolicy{
+				CoreDNS: &AddonUpgradePolicy{Type: SkipAddonUpgradePolicyType, Version: "v1.10.1"},
+			},
+			kubernetesVersion: "v1.28.0",
+			expectErr:         true,
+		},
+		{
+			name: "should fail when the pinned CoreDNS version is invalid",
+			upgradePolicy: &UpgradePolicy{
+				CoreDNS: &AddonUpgradePolicy{Version: "v1.10"},
+			},
+			kubernetesVersion: "v1.28.0",
+			expectErr:         true,
+		},
+		{
+			name: "should fail when the pinned kube-proxy version is invalid",
+			upgradePolicy: &UpgradePolicy{
+				KubeProxy: &AddonUpgradePolicy{Version: "1.27.3"},
+			},
+			kubernetesVersion: "v1.28.0",
+			expectErr:         true,
+		},
+		{
+			name: "should fail when the pinned kube-proxy version is newer than the Kubernetes version",
+			upgradePolicy: &UpgradePolicy{
+				KubeProxy: &AddonUpgradePolicy{Version: "v1.28.1"},
+			},
+			kubernetesVersion: "v1.28.0",
+			expectErr:         true,
+		},
+		{
+			name: "should fail when the pinned kube-proxy version is too old",
+			upgradePolicy: &UpgradePolicy{
+				KubeProxy: &AddonUpgradePolicy{Version: "v1.24.0"},
+			},
+			kubernetesVersion: "v1.28.0",
+			expectErr:         true,
+		},
+		{
+			name: "should succeed when the Kubernetes version is not known",
+			upgradePolicy: &UpgradePolicy{
+				KubeProxy: &AddonUpgradePolicy{Version: "v1.24.0"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			allErrs := validateUpgradePolicy(tt.upgradePolicy, tt.kubernetesVersion, field.NewPath("spec", "upgradePolicy"))
+			if tt.expectErr {
+				g.Expect(allErrs).ToNot(BeEmpty())
+			} else {
+				g.Expect(allErrs).To(BeEmpty())
+			}
+		})
+	}
+}
+
 func TestValidateVersion(t *testing.T) {
 	tests := []struct {
 		name                 string
@@ -1195,6 +1333,68 @@ func TestValidateVersion(t *testing.T) {
 		})
 	}
 }
+func TestKubeadmControlPlaneValidateRunningVersion(t *testing.T) {
+	tests := []struct {
+		name          string
+		oldVersion    string
+		statusVersion *string
+		newVersion    string
+		expectErr     bool
+	}{
+		{
+			name:       "pass without status version",
+			oldVersion: "v1.27.3",
+			newVersion: "v1.28.0",
+		},
+		{
+			name:          "pass when the version is not changed",
+			oldVersion:    "v1.28.0",
+			statusVersion: pointer.String("v1.26.3"),
+			newVersion:    "v1.28.0",
+		},
+		{
+			name:          "pass when upgrading to the next minor version of the running control plane",
+			oldVersion:    "v1.27.3",
+			statusVersion: pointer.String("v1.27.3"),
+			newVersion:    "v1.28.0",
+		},
+		{
+			name:          "pass when changing the version of a rollout in progress to the next minor version",
+			oldVersion:    "v1.28.0",
+			statusVersion: pointer.String("v1.27.3"),
+			newVersion:    "v1.28.1",
+		},
+		{
+			name:          "error when upgrading while the running control plane is older than the previous version",
+			oldVersion:    "v1.28.0",
+			statusVersion: pointer.String("v1.27.3"),
+			newVersion:    "v1.29.0",
+			expectErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			prev := &KubeadmControlPlane{
+				Spec:   KubeadmControlPlaneSpec{Version: tt.oldVersion},
+				Status: KubeadmControlPlaneStatus{Version: tt.statusVersion},
+			}
+			kcp := &KubeadmControlPlane{
+				Spec: KubeadmControlPlaneSpec{Version: tt.newVersion},
+			}
+
+			allErrs := kcp.validateRunningVersion(prev)
+			if tt.expectErr {
+				g.Expect(allErrs).ToNot(BeEmpty())
+			} else {
+				g.Expect(allErrs).To(BeEmpty())
+			}
+		})
+	}
+}
+
 func TestKubeadmControlPlaneValidateUpdateAfterDefaulting(t *testing.T) {
 	before := &KubeadmControlPlane{
 		ObjectMeta: metav1.ObjectMeta{
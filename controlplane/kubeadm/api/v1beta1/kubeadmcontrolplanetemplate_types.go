@@ -101,6 +101,32 @@ type KubeadmControlPlaneTemplateResourceSpec struct {
 	// The RemediationStrategy that controls how control plane machine remediation happens.
 	// +optional
 	RemediationStrategy *RemediationStrategy `json:"remediationStrategy,omitempty"`
+
+	// FailureDomainSpreadPolicy defines how control plane machines are spread across failure domains.
+	// Control plane machines are always created in the failure domain with the fewest machines; when using
+	// the Rebalance type, control plane machines are also rolled out to restore the balance across failure domains,
+	// e.g. after a failure domain that was unavailable comes back. MaxUnavailable is not supported, because
+	// control plane machines are always rebalanced one at a time.
+	// +optional
+	FailureDomainSpreadPolicy *clusterv1.FailureDomainSpreadPolicy `json:"failureDomainSpreadPolicy,omitempty"`
+
+	// ScaleUpStrategy controls how control plane machines are created when scaling up.
+	// +optional
+	ScaleUpStrategy *ScaleUpStrategy `json:"scaleUpStrategy,omitempty"`
+
+	// EtcdLearnerMode defines if new control plane machines join the etcd cluster as learners, i.e. as non-voting
+	// members that are promoted to voting members only once in sync with the leader, which reduces the risk of losing
+	// the etcd quorum during scale up and rollouts. While a member is a learner, KCP waits for its promotion before
+	// proceeding with other scale up or scale down operations.
+	// It is used only for Kubernetes versions >= v1.27.0 when etcd is managed by KCP, by setting the EtcdLearnerMode
+	// kubeadm feature gate; if not set, the feature gate is left as defined in the kubeadm ClusterConfiguration.
+	// +optional
+	EtcdLearnerMode *bool `json:"etcdLearnerMode,omitempty"`
+
+	// UpgradePolicy defines how the CoreDNS and kube-proxy add-ons deployed by kubeadm are upgraded
+	// during control plane upgrades.
+	// +optional
+	UpgradePolicy *UpgradePolicy `json:"upgradePolicy,omitempty"`
 }
 
 // KubeadmControlPlaneTemplateMachineTemplate defines the template for Machines
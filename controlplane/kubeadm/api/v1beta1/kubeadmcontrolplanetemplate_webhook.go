@@ -107,6 +107,8 @@ func validateKubeadmControlPlaneTemplateResourceSpec(s KubeadmControlPlaneTempla
 
 	allErrs = append(allErrs, validateRolloutBefore(s.RolloutBefore, pathPrefix.Child("rolloutBefore"))...)
 	allErrs = append(allErrs, validateRolloutStrategy(s.RolloutStrategy, nil, pathPrefix.Child("rolloutStrategy"))...)
+	allErrs = append(allErrs, validateFailureDomainSpreadPolicy(s.FailureDomainSpreadPolicy, pathPrefix.Child("failureDomainSpreadPolicy"))...)
+	allErrs = append(allErrs, validateUpgradePolicy(s.UpgradePolicy, "", pathPrefix.Child("upgradePolicy"))...)
 
 	return allErrs
 }
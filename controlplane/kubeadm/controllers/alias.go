@@ -37,6 +37,14 @@ type KubeadmControlPlaneReconciler struct {
 	EtcdDialTimeout time.Duration
 	EtcdCallTimeout time.Duration
 
+	// EtcdClientIdleTimeout is the duration after which unused connections with etcd members, which are otherwise
+	// reused across reconciles, are closed. If zero, connections are established at every reconcile.
+	EtcdClientIdleTimeout time.Duration
+
+	// ApproveKubeletServingCertificates enables the approval of the kubelet serving certificate signing requests
+	// of the nodes of the Machines of the cluster.
+	ApproveKubeletServingCertificates bool
+
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
 }
@@ -50,5 +58,54 @@ func (r *KubeadmControlPlaneReconciler) SetupWithManager(ctx context.Context, mg
 		EtcdDialTimeout:     r.EtcdDialTimeout,
 		EtcdCallTimeout:     r.EtcdCallTimeout,
 		WatchFilterValue:    r.WatchFilterValue,
+
+		EtcdClientIdleTimeout:             r.EtcdClientIdleTimeout,
+		ApproveKubeletServingCertificates: r.ApproveKubeletServingCertificates,
+	}).SetupWithManager(ctx, mgr, options)
+}
+
+// EtcdSnapshotReconciler reconciles an EtcdSnapshot object.
+type EtcdSnapshotReconciler struct {
+	Client  client.Client
+	Tracker *remote.ClusterCacheTracker
+
+	EtcdDialTimeout time.Duration
+	EtcdCallTimeout time.Duration
+
+	// WatchFilterValue is the label value used to filter events prior to reconciliation.
+	WatchFilterValue string
+}
+
+// SetupWithManager sets up the reconciler with the Manager.
+func (r *EtcdSnapshotReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	return (&kubeadmcontrolplanecontrollers.EtcdSnapshotReconciler{
+		Client:           r.Client,
+		Tracker:          r.Tracker,
+		EtcdDialTimeout:  r.EtcdDialTimeout,
+		EtcdCallTimeout:  r.EtcdCallTimeout,
+		WatchFilterValue: r.WatchFilterValue,
+	}).SetupWithManager(ctx, mgr, options)
+}
+
+// EtcdRestoreReconciler reconciles an EtcdRestore object.
+type EtcdRestoreReconciler struct {
+	Client  client.Client
+	Tracker *remote.ClusterCacheTracker
+
+	EtcdDialTimeout time.Duration
+	EtcdCallTimeout time.Duration
+
+	// WatchFilterValue is the label value used to filter events prior to reconciliation.
+	WatchFilterValue string
+}
+
+// SetupWithManager sets up the reconciler with the Manager.
+func (r *EtcdRestoreReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	return (&kubeadmcontrolplanecontrollers.EtcdRestoreReconciler{
+		Client:           r.Client,
+		Tracker:          r.Tracker,
+		EtcdDialTimeout:  r.EtcdDialTimeout,
+		EtcdCallTimeout:  r.EtcdCallTimeout,
+		WatchFilterValue: r.WatchFilterValue,
 	}).SetupWithManager(ctx, mgr, options)
 }
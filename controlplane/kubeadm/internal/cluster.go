@@ -31,6 +31,7 @@ import (
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controllers/remote"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal/etcd"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util/collections"
 	"sigs.k8s.io/cluster-api/util/secret"
@@ -57,6 +58,9 @@ type Management struct {
 	Tracker             *remote.ClusterCacheTracker
 	EtcdDialTimeout     time.Duration
 	EtcdCallTimeout     time.Duration
+	// EtcdClientCache caches connections with etcd members across reconciles; if nil, connections
+	// are established at every reconcile.
+	EtcdClientCache *etcd.ClientCache
 }
 
 // RemoteClusterConnectionError represents a failure to connect to a remote cluster.
@@ -173,7 +177,7 @@ func (m *Management) GetWorkloadCluster(ctx context.Context, clusterKey client.O
 		restConfig:          restConfig,
 		Client:              c,
 		CoreDNSMigrator:     &CoreDNSMigrator{},
-		etcdClientGenerator: NewEtcdClientGenerator(restConfig, tlsConfig, m.EtcdDialTimeout, m.EtcdCallTimeout),
+		etcdClientGenerator: NewEtcdClientGenerator(restConfig, tlsConfig, m.EtcdDialTimeout, m.EtcdCallTimeout, m.EtcdClientCache, clusterKey),
 	}, nil
 }
 
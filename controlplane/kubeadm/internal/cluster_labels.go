@@ -23,9 +23,17 @@ import (
 )
 
 // ControlPlaneMachineLabelsForCluster returns a set of labels to add to a control plane machine for this specific cluster.
-func ControlPlaneMachineLabelsForCluster(kcp *controlplanev1.KubeadmControlPlane, clusterName string) map[string]string {
+// Labels from the MachineTemplate take precedence over the ones from the Cluster metadata.
+func ControlPlaneMachineLabelsForCluster(kcp *controlplanev1.KubeadmControlPlane, cluster *clusterv1.Cluster) map[string]string {
 	labels := map[string]string{}
 
+	// Add the labels from the Cluster metadata.
+	if cluster.Spec.Metadata != nil {
+		for k, v := range cluster.Spec.Metadata.Labels {
+			labels[k] = v
+		}
+	}
+
 	// Add the labels from the MachineTemplate.
 	// Note: we intentionally don't use the map directly to ensure we don't modify the map in KCP.
 	for k, v := range kcp.Spec.MachineTemplate.ObjectMeta.Labels {
@@ -33,9 +41,29 @@ func ControlPlaneMachineLabelsForCluster(kcp *controlplanev1.KubeadmControlPlane
 	}
 
 	// Always force these labels over the ones coming from the spec.
-	labels[clusterv1.ClusterNameLabel] = clusterName
+	labels[clusterv1.ClusterNameLabel] = cluster.Name
 	labels[clusterv1.MachineControlPlaneLabel] = ""
 	// Note: MustFormatValue is used here as the label value can be a hash if the control plane name is longer than 63 characters.
 	labels[clusterv1.MachineControlPlaneNameLabel] = format.MustFormatValue(kcp.Name)
 	return labels
 }
+
+// ControlPlaneMachineAnnotationsForCluster returns a set of annotations to add to a control plane machine for this specific cluster.
+// Annotations from the MachineTemplate take precedence over the ones from the Cluster metadata.
+func ControlPlaneMachineAnnotationsForCluster(kcp *controlplanev1.KubeadmControlPlane, cluster *clusterv1.Cluster) map[string]string {
+	annotations := map[string]string{}
+
+	// Add the annotations from the Cluster metadata.
+	if cluster.Spec.Metadata != nil {
+		for k, v := range cluster.Spec.Metadata.Annotations {
+			annotations[k] = v
+		}
+	}
+
+	// Add the annotations from the MachineTemplate.
+	// Note: we intentionally don't use the map directly to ensure we don't modify the map in KCP.
+	for k, v := range kcp.Spec.MachineTemplate.ObjectMeta.Annotations {
+		annotations[k] = v
+	}
+	return annotations
+}
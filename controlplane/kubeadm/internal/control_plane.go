@@ -18,7 +18,9 @@ package internal
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/blang/semver"
 	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -31,6 +33,7 @@ import (
 	"sigs.k8s.io/cluster-api/controllers/external"
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util/collections"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/failuredomains"
 	"sigs.k8s.io/cluster-api/util/patch"
 )
@@ -138,10 +141,64 @@ func (c *ControlPlane) NextFailureDomainForScaleUp() *string {
 	return failuredomains.PickFewest(c.FailureDomains().FilterControlPlane(), c.UpToDateMachines())
 }
 
+// NextFailureDomainsForScaleUp returns the failure domains for the given number of machines created at the same time
+// during a scale up; the failure domains are picked as if the machines were created one at a time, so they are spread
+// across the failure domains with the fewest number of up-to-date machines.
+func (c *ControlPlane) NextFailureDomainsForScaleUp(count int) []*string {
+	failureDomains := make([]*string, 0, count)
+	// NOTE: UpToDateMachines returns a new collection, so inserting machines doesn't change the control plane machines.
+	machines := c.UpToDateMachines()
+	for i := 0; i < count; i++ {
+		var fd *string
+		if len(c.Cluster.Status.FailureDomains.FilterControlPlane()) > 0 {
+			fd = failuredomains.PickFewest(c.FailureDomains().FilterControlPlane(), machines)
+		}
+		failureDomains = append(failureDomains, fd)
+
+		// Account for the machine to be created in the failure domain when picking the next ones.
+		machines.Insert(&clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("scale-up-%d", i)},
+			Spec:       clusterv1.MachineSpec{FailureDomain: fd},
+		})
+	}
+	return failureDomains
+}
+
+// MachinesToCreateForScaleUp returns the number of machines to be created at the same time during a scale up, i.e. the
+// number of missing replicas up to spec.scaleUpStrategy.maxConcurrent; it is always one when surging during rollouts.
+// NOTE: When etcd is managed by KCP, machines are always created one at a time, so the next machine is created only
+// after the etcd member of the previous one is healthy, as checked by the preflight checks; adding etcd members
+// concurrently can cause the loss of quorum.
+func (c *ControlPlane) MachinesToCreateForScaleUp() int {
+	if c.IsEtcdManaged() {
+		return 1
+	}
+
+	maxConcurrent := 1
+	if c.KCP.Spec.ScaleUpStrategy != nil && c.KCP.Spec.ScaleUpStrategy.MaxConcurrent != nil {
+		maxConcurrent = int(*c.KCP.Spec.ScaleUpStrategy.MaxConcurrent)
+	}
+
+	missingReplicas := 0
+	if c.KCP.Spec.Replicas != nil {
+		missingReplicas = int(*c.KCP.Spec.Replicas) - c.Machines.Len()
+	}
+
+	switch {
+	case missingReplicas < 1:
+		return 1
+	case missingReplicas < maxConcurrent:
+		return missingReplicas
+	default:
+		return maxConcurrent
+	}
+}
+
 // InitialControlPlaneConfig returns a new KubeadmConfigSpec that is to be used for an initializing control plane.
 func (c *ControlPlane) InitialControlPlaneConfig() *bootstrapv1.KubeadmConfigSpec {
 	bootstrapSpec := c.KCP.Spec.KubeadmConfigSpec.DeepCopy()
 	bootstrapSpec.JoinConfiguration = nil
+	c.setEtcdLearnerModeFeatureGate(bootstrapSpec)
 	return bootstrapSpec
 }
 
@@ -152,14 +209,56 @@ func (c *ControlPlane) JoinControlPlaneConfig() *bootstrapv1.KubeadmConfigSpec {
 	// NOTE: For the joining we are preserving the ClusterConfiguration in order to determine if the
 	// cluster is using an external etcd in the kubeadm bootstrap provider (even if this is not required by kubeadm Join).
 	// TODO: Determine if this copy of cluster configuration can be used for rollouts (thus allowing to remove the annotation at machine level)
+	c.setEtcdLearnerModeFeatureGate(bootstrapSpec)
 	return bootstrapSpec
 }
 
+// EtcdLearnerMode returns if new control plane machines should join etcd as learners, or nil if
+// the EtcdLearnerMode kubeadm feature gate should be left as defined in the kubeadm ClusterConfiguration.
+func (c *ControlPlane) EtcdLearnerMode() *bool {
+	if c.KCP.Spec.EtcdLearnerMode == nil || !c.IsEtcdManaged() {
+		return nil
+	}
+	parsedVersion, err := semver.ParseTolerant(c.KCP.Spec.Version)
+	if err != nil || parsedVersion.LT(minVerEtcdLearnerMode) {
+		return nil
+	}
+	return c.KCP.Spec.EtcdLearnerMode
+}
+
+// setEtcdLearnerModeFeatureGate sets the EtcdLearnerMode kubeadm feature gate in the bootstrap spec,
+// if the control plane defines it.
+// NOTE: The ClusterConfiguration of the bootstrap spec is not considered when detecting machines needing rollout,
+// so setting the feature gate does not trigger rollouts.
+func (c *ControlPlane) setEtcdLearnerModeFeatureGate(bootstrapSpec *bootstrapv1.KubeadmConfigSpec) {
+	learnerMode := c.EtcdLearnerMode()
+	if learnerMode == nil {
+		return
+	}
+	if bootstrapSpec.ClusterConfiguration == nil {
+		bootstrapSpec.ClusterConfiguration = &bootstrapv1.ClusterConfiguration{}
+	}
+	if bootstrapSpec.ClusterConfiguration.FeatureGates == nil {
+		bootstrapSpec.ClusterConfiguration.FeatureGates = map[string]bool{}
+	}
+	bootstrapSpec.ClusterConfiguration.FeatureGates[EtcdLearnerModeFeatureGate] = *learnerMode
+}
+
 // HasDeletingMachine returns true if any machine in the control plane is in the process of being deleted.
 func (c *ControlPlane) HasDeletingMachine() bool {
 	return len(c.Machines.Filter(collections.HasDeletionTimestamp)) > 0
 }
 
+// IsRollingOut returns true if a rollout of the control plane machines is already in progress, i.e. if the
+// MachinesSpecUpToDate condition reports a rolling update or if there are more machines than the desired replicas,
+// because a machine was created to replace an outdated one which is not deleted yet.
+func (c *ControlPlane) IsRollingOut() bool {
+	if conditions.GetReason(c.KCP, controlplanev1.MachinesSpecUpToDateCondition) == controlplanev1.RollingUpdateInProgressReason {
+		return true
+	}
+	return c.KCP.Spec.Replicas != nil && c.Machines.Len() > int(*c.KCP.Spec.Replicas)
+}
+
 // GetKubeadmConfig returns the KubeadmConfig of a given machine.
 func (c *ControlPlane) GetKubeadmConfig(machineName string) (*bootstrapv1.KubeadmConfig, bool) {
 	kubeadmConfig, ok := c.KubeadmConfigs[machineName]
@@ -181,9 +280,42 @@ func (c *ControlPlane) MachinesNeedingRollout() (collections.Machines, map[strin
 			rolloutReasons[m.Name] = reason
 		}
 	}
+
+	// If no machine needs to be rolled out for other reasons, roll out a machine to rebalance failure domains, if required.
+	if len(machinesNeedingRollout) == 0 {
+		if m := c.machineNeedingRebalance(machines); m != nil {
+			machinesNeedingRollout.Insert(m)
+			rolloutReasons[m.Name] = fmt.Sprintf("Machine %s is in failure domain %s, which has more machines than other failure domains", m.Name, *m.Spec.FailureDomain)
+		}
+	}
 	return machinesNeedingRollout, rolloutReasons
 }
 
+// machineNeedingRebalance returns the oldest machine in the failure domain with the most machines if machines are not
+// evenly spread across failure domains and the KCP uses the Rebalance FailureDomainSpreadPolicy; otherwise it returns nil.
+func (c *ControlPlane) machineNeedingRebalance(machines collections.Machines) *clusterv1.Machine {
+	policy := c.KCP.Spec.FailureDomainSpreadPolicy
+	if policy == nil || policy.Type != clusterv1.RebalanceFailureDomainSpreadPolicyType {
+		return nil
+	}
+
+	// Rebalance only when the control plane is not scaling; this also prevents rebalancing again
+	// while the replacement machine has been created but the old one is not yet deleted.
+	if c.KCP.Spec.Replicas == nil || len(machines) != int(*c.KCP.Spec.Replicas) {
+		return nil
+	}
+
+	failureDomains := c.FailureDomains().FilterControlPlane()
+	if failuredomains.IsBalanced(failureDomains, machines) {
+		return nil
+	}
+	failureDomain := failuredomains.PickMost(failureDomains, machines, machines)
+	if failureDomain == nil {
+		return nil
+	}
+	return machines.Filter(collections.InFailureDomains(failureDomain)).Oldest()
+}
+
 // UpToDateMachines returns the machines that are up to date with the control
 // plane's configuration and therefore do not require rollout.
 func (c *ControlPlane) UpToDateMachines() collections.Machines {
@@ -18,11 +18,14 @@ package internal
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util/collections"
 	"sigs.k8s.io/cluster-api/util/conditions"
@@ -62,6 +65,127 @@ func TestControlPlane(t *testing.T) {
 	})
 }
 
+func TestMachineNeedingRebalance(t *testing.T) {
+	failureDomains := clusterv1.FailureDomains{
+		"one":   failureDomain(true),
+		"two":   failureDomain(true),
+		"three": failureDomain(true),
+		"four":  failureDomain(false),
+	}
+	rebalance := &clusterv1.FailureDomainSpreadPolicy{Type: clusterv1.RebalanceFailureDomainSpreadPolicyType}
+	now := time.Now()
+	unbalancedMachines := collections.FromMachines(
+		machine("machine-1", withFailureDomain("one"), withCreationTimestamp(now.Add(-2*time.Hour))),
+		machine("machine-2", withFailureDomain("one"), withCreationTimestamp(now.Add(-1*time.Hour))),
+		machine("machine-3", withFailureDomain("two"), withCreationTimestamp(now.Add(-3*time.Hour))),
+	)
+
+	tests := []struct {
+		name                      string
+		failureDomainSpreadPolicy *clusterv1.FailureDomainSpreadPolicy
+		replicas                  int32
+		machines                  collections.Machines
+		expectedMachine           string
+	}{
+		{
+			name:            "no failure domain spread policy",
+			replicas:        3,
+			machines:        unbalancedMachines,
+			expectedMachine: "",
+		},
+		{
+			name:                      "spread failure domain spread policy",
+			failureDomainSpreadPolicy: &clusterv1.FailureDomainSpreadPolicy{Type: clusterv1.SpreadFailureDomainSpreadPolicyType},
+			replicas:                  3,
+			machines:                  unbalancedMachines,
+			expectedMachine:           "",
+		},
+		{
+			name:                      "control plane is scaling",
+			failureDomainSpreadPolicy: rebalance,
+			replicas:                  4,
+			machines:                  unbalancedMachines,
+			expectedMachine:           "",
+		},
+		{
+			name:                      "machines are balanced",
+			failureDomainSpreadPolicy: rebalance,
+			replicas:                  3,
+			machines: collections.FromMachines(
+				machine("machine-1", withFailureDomain("one")),
+				machine("machine-2", withFailureDomain("two")),
+				machine("machine-3", withFailureDomain("three")),
+			),
+			expectedMachine: "",
+		},
+		{
+			name:                      "machines are not balanced, should return the oldest machine in the failure domain with most machines",
+			failureDomainSpreadPolicy: rebalance,
+			replicas:                  3,
+			machines:                  unbalancedMachines,
+			expectedMachine:           "machine-1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			controlPlane := &ControlPlane{
+				KCP: &controlplanev1.KubeadmControlPlane{
+					Spec: controlplanev1.KubeadmControlPlaneSpec{
+						Replicas:                  &tt.replicas,
+						FailureDomainSpreadPolicy: tt.failureDomainSpreadPolicy,
+					},
+				},
+				Cluster: &clusterv1.Cluster{
+					Status: clusterv1.ClusterStatus{
+						FailureDomains: failureDomains,
+					},
+				},
+				Machines: tt.machines,
+			}
+
+			m := controlPlane.machineNeedingRebalance(tt.machines)
+			if tt.expectedMachine == "" {
+				g.Expect(m).To(BeNil())
+				return
+			}
+			g.Expect(m).ToNot(BeNil())
+			g.Expect(m.Name).To(Equal(tt.expectedMachine))
+		})
+	}
+}
+
+func TestMachinesNeedingRollout_DeleteMachineAnnotation(t *testing.T) {
+	g := NewWithT(t)
+
+	controlPlane := &ControlPlane{
+		KCP: &controlplanev1.KubeadmControlPlane{
+			Spec: controlplanev1.KubeadmControlPlaneSpec{
+				Version: "v1.28.0",
+			},
+		},
+		Cluster: &clusterv1.Cluster{},
+		Machines: collections.FromMachines(
+			machine("machine-1", withVersion("v1.28.0")),
+			machine("machine-2", withVersion("v1.28.0"), withAnnotation(clusterv1.DeleteMachineAnnotation, "")),
+			machine("machine-3", withVersion("v1.28.0")),
+		),
+	}
+
+	// The annotation alone does not trigger a rollout.
+	machines, _ := controlPlane.MachinesNeedingRollout()
+	g.Expect(machines).To(BeEmpty())
+	g.Expect(controlPlane.UpToDateMachines().Names()).To(ConsistOf("machine-1", "machine-2", "machine-3"))
+
+	// The annotated Machine is the one selected for deletion at the next scale down or rollout step.
+	annotated := controlPlane.MachineWithDeleteAnnotation(controlPlane.Machines)
+	g.Expect(annotated.Names()).To(ConsistOf("machine-2"))
+	selected, err := controlPlane.MachineInFailureDomainWithMostMachines(annotated)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(selected.Name).To(Equal("machine-2"))
+}
+
 func TestHasUnhealthyMachine(t *testing.T) {
 	// healthy machine (without MachineHealthCheckSucceded condition)
 	healthyMachine1 := &clusterv1.Machine{}
@@ -89,6 +213,236 @@ func TestHasUnhealthyMachine(t *testing.T) {
 	g.Expect(c.HasUnhealthyMachine()).To(BeTrue())
 }
 
+func TestIsRollingOut(t *testing.T) {
+	rollingUpdateKCP := &controlplanev1.KubeadmControlPlane{Spec: controlplanev1.KubeadmControlPlaneSpec{Replicas: pointer.Int32(3)}}
+	conditions.MarkFalse(rollingUpdateKCP, controlplanev1.MachinesSpecUpToDateCondition, controlplanev1.RollingUpdateInProgressReason, clusterv1.ConditionSeverityWarning, "")
+	waitingKCP := &controlplanev1.KubeadmControlPlane{Spec: controlplanev1.KubeadmControlPlaneSpec{Replicas: pointer.Int32(3)}}
+	conditions.MarkFalse(waitingKCP, controlplanev1.MachinesSpecUpToDateCondition, clusterv1.WaitingForMaintenanceWindowReason, clusterv1.ConditionSeverityInfo, "")
+
+	tests := []struct {
+		name     string
+		kcp      *controlplanev1.KubeadmControlPlane
+		machines collections.Machines
+		expected bool
+	}{
+		{
+			name:     "should return false if no rollout was started",
+			kcp:      waitingKCP,
+			machines: collections.FromMachines(machine("machine-1"), machine("machine-2"), machine("machine-3")),
+			expected: false,
+		},
+		{
+			name:     "should return true if a rolling update is in progress",
+			kcp:      rollingUpdateKCP,
+			machines: collections.FromMachines(machine("machine-1"), machine("machine-2"), machine("machine-3")),
+			expected: true,
+		},
+		{
+			name:     "should return true if there are more machines than replicas",
+			kcp:      waitingKCP,
+			machines: collections.FromMachines(machine("machine-1"), machine("machine-2"), machine("machine-3"), machine("machine-4")),
+			expected: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			controlPlane := &ControlPlane{
+				KCP:      tt.kcp,
+				Machines: tt.machines,
+			}
+			g.Expect(controlPlane.IsRollingOut()).To(Equal(tt.expected))
+		})
+	}
+}
+
+func TestNextFailureDomainsForScaleUp(t *testing.T) {
+	g := NewWithT(t)
+
+	controlPlane := &ControlPlane{
+		KCP: &controlplanev1.KubeadmControlPlane{},
+		Cluster: &clusterv1.Cluster{
+			Status: clusterv1.ClusterStatus{
+				FailureDomains: clusterv1.FailureDomains{
+					"one":   failureDomain(true),
+					"two":   failureDomain(true),
+					"three": failureDomain(true),
+					"four":  failureDomain(false),
+				},
+			},
+		},
+		Machines: collections.New(),
+	}
+
+	// Machines created at the same time are spread across the control plane failure domains.
+	failureDomains := controlPlane.NextFailureDomainsForScaleUp(3)
+	g.Expect(failureDomains).To(HaveLen(3))
+	g.Expect([]string{*failureDomains[0], *failureDomains[1], *failureDomains[2]}).To(ConsistOf("one", "two", "three"))
+	g.Expect(controlPlane.Machines).To(BeEmpty())
+
+	// Without failure domains, no failure domain is picked.
+	controlPlane.Cluster.Status.FailureDomains = nil
+	g.Expect(controlPlane.NextFailureDomainsForScaleUp(2)).To(Equal([]*string{nil, nil}))
+}
+
+func TestMachinesToCreateForScaleUp(t *testing.T) {
+	machines := collections.FromMachines(machine("machine-1"))
+	externalEtcd := &bootstrapv1.ClusterConfiguration{
+		Etcd: bootstrapv1.Etcd{External: &bootstrapv1.ExternalEtcd{}},
+	}
+
+	tests := []struct {
+		name                 string
+		replicas             int32
+		scaleUpStrategy      *controlplanev1.ScaleUpStrategy
+		clusterConfiguration *bootstrapv1.ClusterConfiguration
+		machines             collections.Machines
+		expected             int
+	}{
+		{
+			name:     "without scale up strategy, should create one machine",
+			replicas: 3,
+			machines: machines,
+			expected: 1,
+		},
+		{
+			name:            "without max concurrent, should create one machine",
+			replicas:        3,
+			scaleUpStrategy: &controlplanev1.ScaleUpStrategy{},
+			machines:        machines,
+			expected:        1,
+		},
+		{
+			name:                 "should create up to max concurrent machines",
+			replicas:             5,
+			scaleUpStrategy:      &controlplanev1.ScaleUpStrategy{MaxConcurrent: pointer.Int32(2)},
+			clusterConfiguration: externalEtcd,
+			machines:             machines,
+			expected:             2,
+		},
+		{
+			name:                 "should create only the missing machines",
+			replicas:             3,
+			scaleUpStrategy:      &controlplanev1.ScaleUpStrategy{MaxConcurrent: pointer.Int32(5)},
+			clusterConfiguration: externalEtcd,
+			machines:             machines,
+			expected:             2,
+		},
+		{
+			name:            "should create one machine when etcd is managed",
+			replicas:        5,
+			scaleUpStrategy: &controlplanev1.ScaleUpStrategy{MaxConcurrent: pointer.Int32(2)},
+			machines:        machines,
+			expected:        1,
+		},
+		{
+			name:                 "should create one machine when surging during rollouts",
+			replicas:             1,
+			scaleUpStrategy:      &controlplanev1.ScaleUpStrategy{MaxConcurrent: pointer.Int32(5)},
+			clusterConfiguration: externalEtcd,
+			machines:             machines,
+			expected:             1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			controlPlane := &ControlPlane{
+				KCP: &controlplanev1.KubeadmControlPlane{
+					Spec: controlplanev1.KubeadmControlPlaneSpec{
+						Replicas:        pointer.Int32(tt.replicas),
+						ScaleUpStrategy: tt.scaleUpStrategy,
+						KubeadmConfigSpec: bootstrapv1.KubeadmConfigSpec{
+							ClusterConfiguration: tt.clusterConfiguration,
+						},
+					},
+				},
+				Machines: tt.machines,
+			}
+			g.Expect(controlPlane.MachinesToCreateForScaleUp()).To(Equal(tt.expected))
+		})
+	}
+}
+
+func TestEtcdLearnerMode(t *testing.T) {
+	tests := []struct {
+		name                  string
+		version               string
+		etcdLearnerMode       *bool
+		clusterConfiguration  *bootstrapv1.ClusterConfiguration
+		expected              *bool
+		expectedFeatureGates  map[string]bool
+		expectedNoClusterConf bool
+	}{
+		{
+			name:                  "should not set the feature gate if etcd learner mode is not set",
+			version:               "v1.27.1",
+			expectedNoClusterConf: true,
+		},
+		{
+			name:                  "should not set the feature gate if the version does not support etcd learner mode",
+			version:               "v1.26.3",
+			etcdLearnerMode:       pointer.Bool(true),
+			expectedNoClusterConf: true,
+		},
+		{
+			name:            "should not set the feature gate if etcd is external",
+			version:         "v1.27.1",
+			etcdLearnerMode: pointer.Bool(true),
+			clusterConfiguration: &bootstrapv1.ClusterConfiguration{
+				Etcd: bootstrapv1.Etcd{External: &bootstrapv1.ExternalEtcd{}},
+			},
+		},
+		{
+			name:                 "should enable the feature gate",
+			version:              "v1.27.1",
+			etcdLearnerMode:      pointer.Bool(true),
+			expected:             pointer.Bool(true),
+			expectedFeatureGates: map[string]bool{EtcdLearnerModeFeatureGate: true},
+		},
+		{
+			name:            "should disable the feature gate preserving other feature gates",
+			version:         "v1.28.0",
+			etcdLearnerMode: pointer.Bool(false),
+			clusterConfiguration: &bootstrapv1.ClusterConfiguration{
+				FeatureGates: map[string]bool{"PublicKeysECDSA": true, EtcdLearnerModeFeatureGate: true},
+			},
+			expected:             pointer.Bool(false),
+			expectedFeatureGates: map[string]bool{"PublicKeysECDSA": true, EtcdLearnerModeFeatureGate: false},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			controlPlane := &ControlPlane{
+				KCP: &controlplanev1.KubeadmControlPlane{
+					Spec: controlplanev1.KubeadmControlPlaneSpec{
+						Version:         tt.version,
+						EtcdLearnerMode: tt.etcdLearnerMode,
+						KubeadmConfigSpec: bootstrapv1.KubeadmConfigSpec{
+							ClusterConfiguration: tt.clusterConfiguration,
+						},
+					},
+				},
+			}
+			g.Expect(controlPlane.EtcdLearnerMode()).To(Equal(tt.expected))
+
+			for _, bootstrapSpec := range []*bootstrapv1.KubeadmConfigSpec{controlPlane.InitialControlPlaneConfig(), controlPlane.JoinControlPlaneConfig()} {
+				if tt.expectedNoClusterConf {
+					g.Expect(bootstrapSpec.ClusterConfiguration).To(BeNil())
+					continue
+				}
+				g.Expect(bootstrapSpec.ClusterConfiguration.FeatureGates).To(Equal(tt.expectedFeatureGates))
+			}
+			// The KCP spec must not be changed.
+			g.Expect(controlPlane.KCP.Spec.KubeadmConfigSpec.ClusterConfiguration).To(Equal(tt.clusterConfiguration))
+		})
+	}
+}
+
 type machineOpt func(*clusterv1.Machine)
 
 func failureDomain(controlPlane bool) clusterv1.FailureDomainSpec {
@@ -103,6 +457,27 @@ func withFailureDomain(fd string) machineOpt {
 	}
 }
 
+func withCreationTimestamp(t time.Time) machineOpt {
+	return func(m *clusterv1.Machine) {
+		m.CreationTimestamp = metav1.NewTime(t)
+	}
+}
+
+func withVersion(version string) machineOpt {
+	return func(m *clusterv1.Machine) {
+		m.Spec.Version = &version
+	}
+}
+
+func withAnnotation(key, value string) machineOpt {
+	return func(m *clusterv1.Machine) {
+		if m.Annotations == nil {
+			m.Annotations = map[string]string{}
+		}
+		m.Annotations[key] = value
+	}
+}
+
 func machine(name string, opts ...machineOpt) *clusterv1.Machine {
 	m := &clusterv1.Machine{
 		ObjectMeta: metav1.ObjectMeta{
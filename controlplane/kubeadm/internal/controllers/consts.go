@@ -30,4 +30,12 @@ const (
 	// dependentCertRequeueAfter is how long to wait before checking again to see if
 	// dependent certificates have been created.
 	dependentCertRequeueAfter = 30 * time.Second
+
+	// unsupportedVersionSkewRequeueAfter is how long to wait before checking again if
+	// an upgrade blocked by an unsupported version skew can proceed.
+	unsupportedVersionSkewRequeueAfter = 1 * time.Minute
+
+	// deferredAddonUpgradeRequeueAfter is how long to wait before checking again if
+	// a deferred upgrade of CoreDNS or kube-proxy can proceed.
+	deferredAddonUpgradeRequeueAfter = 1 * time.Minute
 )
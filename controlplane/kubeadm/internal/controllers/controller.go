@@ -24,6 +24,7 @@ import (
 
 	"github.com/blang/semver"
 	"github.com/pkg/errors"
+	certificatesv1 "k8s.io/api/certificates/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -37,20 +38,25 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controllers/remote"
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal/etcd"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api/feature"
 	"sigs.k8s.io/cluster-api/internal/contract"
+	"sigs.k8s.io/cluster-api/internal/util/maintenance"
+	"sigs.k8s.io/cluster-api/internal/util/rollout"
 	"sigs.k8s.io/cluster-api/internal/util/ssa"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/collections"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	clog "sigs.k8s.io/cluster-api/util/log"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	"sigs.k8s.io/cluster-api/util/secret"
@@ -67,6 +73,8 @@ const (
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io;bootstrap.cluster.x-k8s.io;controlplane.cluster.x-k8s.io,resources=*,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;clusters/status,verbs=get;list;watch
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines;machines/status,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinedeployments,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinepools,verbs=get;list;watch
 // +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch
 
 // KubeadmControlPlaneReconciler reconciles a KubeadmControlPlane object.
@@ -80,6 +88,15 @@ type KubeadmControlPlaneReconciler struct {
 	EtcdDialTimeout time.Duration
 	EtcdCallTimeout time.Duration
 
+	// EtcdClientIdleTimeout is the duration after which unused connections with etcd members, which are otherwise
+	// reused across reconciles, are closed. If zero, connections are established at every reconcile.
+	EtcdClientIdleTimeout time.Duration
+
+	// ApproveKubeletServingCertificates enables the approval of the kubelet serving certificate signing requests
+	// of the nodes of the Machines of the cluster, e.g. for clusters where kubelets are configured
+	// with rotate-server-certificates and no other approver is deployed.
+	ApproveKubeletServingCertificates bool
+
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
 
@@ -95,7 +112,7 @@ type KubeadmControlPlaneReconciler struct {
 }
 
 func (r *KubeadmControlPlaneReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
-	c, err := ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&controlplanev1.KubeadmControlPlane{}).
 		Owns(&clusterv1.Machine{}).
 		WithOptions(options).
@@ -109,7 +126,17 @@ func (r *KubeadmControlPlaneReconciler) SetupWithManager(ctx context.Context, mg
 					predicates.ClusterUnpausedAndInfrastructureReady(ctrl.LoggerFrom(ctx)),
 				),
 			),
-		).Build(r)
+		)
+	if r.ApproveKubeletServingCertificates {
+		// Watch all the Machines of the cluster, so the kubelet serving certificate signing requests of their nodes
+		// are approved as soon as the nodes are linked to the Machines.
+		b = b.Watches(
+			&clusterv1.Machine{},
+			handler.EnqueueRequestsFromMapFunc(r.machineToKubeadmControlPlane),
+			builder.WithPredicates(predicates.ResourceFieldsChanged(ctrl.LoggerFrom(ctx), "status.nodeRef", "status.addresses")),
+		)
+	}
+	c, err := b.Build(r)
 	if err != nil {
 		return errors.Wrap(err, "failed setting up with a controller manager")
 	}
@@ -122,12 +149,20 @@ func (r *KubeadmControlPlaneReconciler) SetupWithManager(ctx context.Context, mg
 		if r.Tracker == nil {
 			return errors.New("cluster cache tracker is nil, cannot create the internal management cluster resource")
 		}
+		var etcdClientCache *etcd.ClientCache
+		if r.EtcdClientIdleTimeout > 0 {
+			etcdClientCache = etcd.NewClientCache(r.EtcdClientIdleTimeout)
+			if err := mgr.Add(etcdClientCache); err != nil {
+				return errors.Wrap(err, "failed to add the etcd client cache to the controller manager")
+			}
+		}
 		r.managementCluster = &internal.Management{
 			Client:              r.Client,
 			SecretCachingClient: r.SecretCachingClient,
 			Tracker:             r.Tracker,
 			EtcdDialTimeout:     r.EtcdDialTimeout,
 			EtcdCallTimeout:     r.EtcdCallTimeout,
+			EtcdClientCache:     etcdClientCache,
 		}
 	}
 
@@ -145,6 +180,7 @@ func (r *KubeadmControlPlaneReconciler) Reconcile(ctx context.Context, req ctrl.
 	kcp := &controlplanev1.KubeadmControlPlane{}
 	if err := r.Client.Get(ctx, req.NamespacedName, kcp); err != nil {
 		if apierrors.IsNotFound(err) {
+			rollout.DeleteMetrics("KubeadmControlPlane", req.NamespacedName)
 			return ctrl.Result{}, nil
 		}
 		return ctrl.Result{Requeue: true}, nil
@@ -162,6 +198,7 @@ func (r *KubeadmControlPlaneReconciler) Reconcile(ctx context.Context, req ctrl.
 	}
 	log = log.WithValues("Cluster", klog.KObj(cluster))
 	ctx = ctrl.LoggerInto(ctx, log)
+	ctx, log = clog.AddOperationID(ctx, kcp)
 
 	if annotations.IsPaused(cluster, kcp) {
 		log.Info("Reconciliation is paused for this object")
@@ -314,6 +351,8 @@ func patchKubeadmControlPlane(ctx context.Context, patchHelper *patch.Helper, kc
 	)
 
 	// Patch the object, ignoring conflicts on the conditions owned by this controller.
+	// NOTE: Conditions are applied with server-side apply, so conflicts with other controllers setting conditions
+	// on the KubeadmControlPlane are resolved by the API server without retrying the reconcile.
 	return patchHelper.Patch(
 		ctx,
 		kcp,
@@ -327,6 +366,7 @@ func patchKubeadmControlPlane(ctx context.Context, patchHelper *patch.Helper, kc
 			controlplanev1.CertificatesAvailableCondition,
 		}},
 		patch.WithStatusObservedGeneration{},
+		patch.WithServerSideApply{FieldManager: kcpManagerName},
 	)
 }
 
@@ -387,6 +427,13 @@ func (r *KubeadmControlPlaneReconciler) reconcile(ctx context.Context, controlPl
 		return ctrl.Result{}, err
 	}
 
+	// Approves the kubelet serving certificate signing requests of the nodes of the Machines of the cluster, if enabled.
+	// NOTE: Failures are logged without blocking the other KCP operations, given that the control plane doesn't depend
+	// on the kubelet serving certificates.
+	if err := r.reconcileKubeletServingCertificates(ctx, controlPlane); err != nil {
+		log.Error(err, "Failed to approve kubelet serving certificate signing requests")
+	}
+
 	// Reconcile unhealthy machines by triggering deletion and requeue if it is considered safe to remediate,
 	// otherwise continue with the other KCP operations.
 	if result, err := r.reconcileUnhealthyMachines(ctx, controlPlane); err != nil || !result.IsZero() {
@@ -401,6 +448,32 @@ func (r *KubeadmControlPlaneReconciler) reconcile(ctx context.Context, controlPl
 		for _, rolloutReason := range rolloutReasons {
 			reasons = append(reasons, rolloutReason)
 		}
+		// Defer new rollouts until the next maintenance window of the Cluster opens, if any.
+		// NOTE: Rollouts already in progress are completed, so the control plane is not left with more machines than
+		// the desired replicas or with a mix of old and new machines until the next maintenance window.
+		if !controlPlane.IsRollingOut() {
+			allowed, requeueAfter, err := maintenance.IsAllowed(controlPlane.Cluster, time.Now())
+			if err != nil {
+				return ctrl.Result{}, errors.Wrap(err, "failed to check the maintenance windows of the Cluster")
+			}
+			if !allowed {
+				log.Info(fmt.Sprintf("Waiting for the next maintenance window of the Cluster to roll out Control Plane machines: %s", strings.Join(reasons, ",")), "machinesNeedingRollout", machinesNeedingRollout.Names(), "requeueAfter", requeueAfter)
+				conditions.MarkFalse(controlPlane.KCP, controlplanev1.MachinesSpecUpToDateCondition, clusterv1.WaitingForMaintenanceWindowReason, clusterv1.ConditionSeverityInfo, "Waiting for the next maintenance window to roll %d replicas with outdated spec (%d replicas up to date)", len(machinesNeedingRollout), len(controlPlane.Machines)-len(machinesNeedingRollout))
+				return ctrl.Result{RequeueAfter: requeueAfter}, nil
+			}
+		}
+
+		// Block upgrades which are not supported given the versions actually running in the workload cluster.
+		message, err := r.validateVersionSkew(ctx, controlPlane)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if message != "" {
+			log.Info(fmt.Sprintf("Not rolling out Control Plane machines: %s", message), "machinesNeedingRollout", machinesNeedingRollout.Names())
+			conditions.MarkFalse(controlPlane.KCP, controlplanev1.MachinesSpecUpToDateCondition, controlplanev1.UnsupportedVersionSkewReason, clusterv1.ConditionSeverityError, message)
+			return ctrl.Result{RequeueAfter: unsupportedVersionSkewRequeueAfter}, nil
+		}
+
 		log.Info(fmt.Sprintf("Rolling out Control Plane machines: %s", strings.Join(reasons, ",")), "machinesNeedingRollout", machinesNeedingRollout.Names())
 		conditions.MarkFalse(controlPlane.KCP, controlplanev1.MachinesSpecUpToDateCondition, controlplanev1.RollingUpdateInProgressReason, clusterv1.ConditionSeverityWarning, "Rolling %d replicas with outdated spec (%d replicas up to date)", len(machinesNeedingRollout), len(controlPlane.Machines)-len(machinesNeedingRollout))
 		return r.upgradeControlPlane(ctx, controlPlane, machinesNeedingRollout)
@@ -455,14 +528,33 @@ func (r *KubeadmControlPlaneReconciler) reconcile(ctx context.Context, controlPl
 		return ctrl.Result{}, errors.Wrapf(err, "failed to parse kubernetes version %q", controlPlane.KCP.Spec.Version)
 	}
 
+	// Add-ons with the Defer upgrade policy are upgraded only once all the Machines of the Cluster are upgraded.
+	var result ctrl.Result
+	deferKubeProxyUpgrade := internal.KubeProxyUpgradePolicy(controlPlane.KCP).Type == controlplanev1.DeferAddonUpgradePolicyType
+	deferCoreDNSUpgrade := internal.CoreDNSUpgradePolicy(controlPlane.KCP).Type == controlplanev1.DeferAddonUpgradePolicyType
+	if deferKubeProxyUpgrade || deferCoreDNSUpgrade {
+		machinesUpgraded, err := r.allMachinesUpgraded(ctx, controlPlane, parsedVersion)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		deferKubeProxyUpgrade = deferKubeProxyUpgrade && !machinesUpgraded
+		deferCoreDNSUpgrade = deferCoreDNSUpgrade && !machinesUpgraded
+	}
+
 	// Update kube-proxy daemonset.
-	if err := workloadCluster.UpdateKubeProxyImageInfo(ctx, controlPlane.KCP, parsedVersion); err != nil {
+	if deferKubeProxyUpgrade {
+		log.V(4).Info("Deferring kube-proxy upgrade until all the Machines of the Cluster are upgraded")
+		result = ctrl.Result{RequeueAfter: deferredAddonUpgradeRequeueAfter}
+	} else if err := workloadCluster.UpdateKubeProxyImageInfo(ctx, controlPlane.KCP, parsedVersion); err != nil {
 		log.Error(err, "failed to update kube-proxy daemonset")
 		return ctrl.Result{}, err
 	}
 
 	// Update CoreDNS deployment.
-	if err := workloadCluster.UpdateCoreDNS(ctx, controlPlane.KCP, parsedVersion); err != nil {
+	if deferCoreDNSUpgrade {
+		log.V(4).Info("Deferring CoreDNS upgrade until all the Machines of the Cluster are upgraded")
+		result = ctrl.Result{RequeueAfter: deferredAddonUpgradeRequeueAfter}
+	} else if err := workloadCluster.UpdateCoreDNS(ctx, controlPlane.KCP, parsedVersion); err != nil {
 		return ctrl.Result{}, errors.Wrap(err, "failed to update CoreDNS deployment")
 	}
 
@@ -473,7 +565,50 @@ func (r *KubeadmControlPlaneReconciler) reconcile(ctx context.Context, controlPl
 	if err := r.reconcileCertificateExpiries(ctx, controlPlane); err != nil {
 		return ctrl.Result{}, err
 	}
-	return ctrl.Result{}, nil
+	return result, nil
+}
+
+// allMachinesUpgraded returns true if all the Machines of the Cluster, including the worker Machines and the
+// MachinePools, are running at least the given Kubernetes version.
+func (r *KubeadmControlPlaneReconciler) allMachinesUpgraded(ctx context.Context, controlPlane *internal.ControlPlane, kubernetesVersion semver.Version) (bool, error) {
+	machines, err := r.managementCluster.GetMachinesForCluster(ctx, controlPlane.Cluster)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get the Machines of the Cluster")
+	}
+	for _, machine := range machines {
+		if machine.Spec.Version == nil {
+			continue
+		}
+		machineVersion, err := version.ParseMajorMinorPatchTolerant(*machine.Spec.Version)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to parse the Kubernetes version of Machine %s", klog.KObj(machine))
+		}
+		if machineVersion.LT(kubernetesVersion) {
+			return false, nil
+		}
+	}
+
+	if !feature.Gates.Enabled(feature.MachinePool) {
+		return true, nil
+	}
+	machinePools, err := r.managementCluster.GetMachinePoolsForCluster(ctx, controlPlane.Cluster)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get the MachinePools of the Cluster")
+	}
+	for i := range machinePools.Items {
+		machinePool := &machinePools.Items[i]
+		if machinePool.Spec.Template.Spec.Version == nil {
+			continue
+		}
+		machinePoolVersion, err := version.ParseMajorMinorPatchTolerant(*machinePool.Spec.Template.Spec.Version)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to parse the Kubernetes version of MachinePool %s", klog.KObj(machinePool))
+		}
+		if machinePoolVersion.LT(kubernetesVersion) {
+			return false, nil
+		}
+	}
+	return true, nil
 }
 
 // reconcileClusterCertificates ensures that all the cluster certificates exists and
@@ -510,8 +645,9 @@ func (r *KubeadmControlPlaneReconciler) reconcileDelete(ctx context.Context, con
 	log := ctrl.LoggerFrom(ctx)
 	log.Info("Reconcile KubeadmControlPlane deletion")
 
-	// If no control plane machines remain, remove the finalizer
+	// If no control plane machines remain, remove the finalizer and the metrics reporting the control plane health.
 	if len(controlPlane.Machines) == 0 {
+		internal.DeleteHealthMetrics(controlPlane.Cluster)
 		controllerutil.RemoveFinalizer(controlPlane.KCP, controlplanev1.KubeadmControlPlaneFinalizer)
 		return ctrl.Result{}, nil
 	}
@@ -586,6 +722,21 @@ func (r *KubeadmControlPlaneReconciler) ClusterToKubeadmControlPlane(_ context.C
 	return nil
 }
 
+// machineToKubeadmControlPlane is a handler.ToRequestsFunc to be used to enqueue requests for reconciliation
+// for KubeadmControlPlane based on updates to any Machine of its Cluster.
+func (r *KubeadmControlPlaneReconciler) machineToKubeadmControlPlane(ctx context.Context, o client.Object) []ctrl.Request {
+	m, ok := o.(*clusterv1.Machine)
+	if !ok {
+		panic(fmt.Sprintf("Expected a Machine but got a %T", o))
+	}
+
+	cluster := &clusterv1.Cluster{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: m.Namespace, Name: m.Spec.ClusterName}, cluster); err != nil {
+		return nil
+	}
+	return r.ClusterToKubeadmControlPlane(ctx, cluster)
+}
+
 // syncMachines updates Machines, InfrastructureMachines and KubeadmConfigs to propagate in-place mutable fields from KCP.
 // Note: It also cleans up managed fields of all Machines so that Machines that were
 // created/patched before (< v1.4.0) the controller adopted Server-Side-Apply (SSA) can also work with SSA.
@@ -827,6 +978,60 @@ func (r *KubeadmControlPlaneReconciler) reconcileCertificateExpiries(ctx context
 	return nil
 }
 
+func (r *KubeadmControlPlaneReconciler) reconcileKubeletServingCertificates(ctx context.Context, controlPlane *internal.ControlPlane) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	if !r.ApproveKubeletServingCertificates {
+		return nil
+	}
+
+	// Return if KCP is not yet initialized (no API server to contact for approving certificate signing requests).
+	if !controlPlane.KCP.Status.Initialized {
+		return nil
+	}
+
+	if err := r.watchKubeletServingCertificateSigningRequests(ctx, controlPlane); err != nil {
+		return err
+	}
+
+	machines, err := r.managementCluster.GetMachinesForCluster(ctx, controlPlane.Cluster)
+	if err != nil {
+		return errors.Wrap(err, "failed to list Machines")
+	}
+
+	workloadCluster, err := controlPlane.GetWorkloadCluster(ctx)
+	if err != nil {
+		return errors.Wrap(err, "cannot get remote client to workload cluster")
+	}
+
+	approved, err := workloadCluster.ApproveKubeletServingCertificateSigningRequests(ctx, machines)
+	if len(approved) > 0 {
+		log.Info("Approved kubelet serving certificate signing requests", "CertificateSigningRequests", strings.Join(approved, ", "))
+	}
+	return err
+}
+
+// watchKubeletServingCertificateSigningRequests watches the kubelet serving certificate signing requests in the
+// workload cluster, so they are approved as soon as they are created.
+func (r *KubeadmControlPlaneReconciler) watchKubeletServingCertificateSigningRequests(ctx context.Context, controlPlane *internal.ControlPlane) error {
+	kcpKey := client.ObjectKeyFromObject(controlPlane.KCP)
+	return r.Tracker.Watch(ctx, remote.WatchInput{
+		Name:    "kubeadmcontrolplane-watchCertificateSigningRequests",
+		Cluster: util.ObjectKey(controlPlane.Cluster),
+		Watcher: r.controller,
+		Kind:    &certificatesv1.CertificateSigningRequest{},
+		EventHandler: handler.EnqueueRequestsFromMapFunc(func(context.Context, client.Object) []ctrl.Request {
+			return []ctrl.Request{{NamespacedName: kcpKey}}
+		}),
+		Predicates: []predicate.Predicate{
+			predicate.NewPredicateFuncs(func(o client.Object) bool {
+				csr, ok := o.(*certificatesv1.CertificateSigningRequest)
+				return ok && csr.Spec.SignerName == certificatesv1.KubeletServingSignerName
+			}),
+		},
+	})
+}
+
 func (r *KubeadmControlPlaneReconciler) adoptMachines(ctx context.Context, kcp *controlplanev1.KubeadmControlPlane, machines collections.Machines, cluster *clusterv1.Cluster) error {
 	// We do an uncached full quorum read against the KCP to avoid re-adopting Machines the garbage collector just intentionally orphaned
 	// See https://github.com/kubernetes/kubernetes/issues/42639
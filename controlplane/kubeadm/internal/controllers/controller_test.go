@@ -37,6 +37,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/tools/record"
+	utilfeature "k8s.io/component-base/featuregate/testing"
 	"k8s.io/klog/v2/klogr"
 	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -498,7 +499,7 @@ func TestKubeadmControlPlaneReconciler_adoption(t *testing.T) {
 				ObjectMeta: metav1.ObjectMeta{
 					Namespace: cluster.Namespace,
 					Name:      name,
-					Labels:    internal.ControlPlaneMachineLabelsForCluster(kcp, cluster.Name),
+					Labels:    internal.ControlPlaneMachineLabelsForCluster(kcp, cluster),
 				},
 				Spec: clusterv1.MachineSpec{
 					Bootstrap: clusterv1.Bootstrap{
@@ -566,7 +567,7 @@ func TestKubeadmControlPlaneReconciler_adoption(t *testing.T) {
 				ObjectMeta: metav1.ObjectMeta{
 					Namespace: cluster.Namespace,
 					Name:      name,
-					Labels:    internal.ControlPlaneMachineLabelsForCluster(kcp, cluster.Name),
+					Labels:    internal.ControlPlaneMachineLabelsForCluster(kcp, cluster),
 				},
 				Spec: clusterv1.MachineSpec{
 					Bootstrap: clusterv1.Bootstrap{
@@ -681,7 +682,7 @@ func TestKubeadmControlPlaneReconciler_adoption(t *testing.T) {
 				ObjectMeta: metav1.ObjectMeta{
 					Namespace: cluster.Namespace,
 					Name:      name,
-					Labels:    internal.ControlPlaneMachineLabelsForCluster(kcp, cluster.Name),
+					Labels:    internal.ControlPlaneMachineLabelsForCluster(kcp, cluster),
 				},
 				Spec: clusterv1.MachineSpec{
 					Bootstrap: clusterv1.Bootstrap{
@@ -739,7 +740,7 @@ func TestKubeadmControlPlaneReconciler_adoption(t *testing.T) {
 					ObjectMeta: metav1.ObjectMeta{
 						Namespace: cluster.Namespace,
 						Name:      "test0",
-						Labels:    internal.ControlPlaneMachineLabelsForCluster(kcp, cluster.Name),
+						Labels:    internal.ControlPlaneMachineLabelsForCluster(kcp, cluster),
 					},
 					Spec: clusterv1.MachineSpec{
 						Bootstrap: clusterv1.Bootstrap{
@@ -2080,6 +2081,111 @@ kubernetesVersion: metav1.16.1`,
 	})
 }
 
+func TestKubeadmControlPlaneReconciler_allMachinesUpgraded(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.MachinePool, true)()
+
+	machineWithVersion := func(name string, version *string) *clusterv1.Machine {
+		return &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: metav1.NamespaceDefault,
+			},
+			Spec: clusterv1.MachineSpec{
+				Version: version,
+			},
+		}
+	}
+
+	machinePoolWithVersion := func(name string, version *string) expv1.MachinePool {
+		return expv1.MachinePool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: metav1.NamespaceDefault,
+			},
+			Spec: expv1.MachinePoolSpec{
+				Template: clusterv1.MachineTemplateSpec{
+					Spec: clusterv1.MachineSpec{
+						Version: version,
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		machines     collections.Machines
+		machinePools []expv1.MachinePool
+		want         bool
+	}{
+		{
+			name: "return true if all the Machines are running the Kubernetes version",
+			machines: collections.FromMachines(
+				machineWithVersion("cp", pointer.String("v1.28.0")),
+				machineWithVersion("worker", pointer.String("v1.28.0")),
+			),
+			want: true,
+		},
+		{
+			name: "return true if all the Machines and MachinePools are running the Kubernetes version",
+			machines: collections.FromMachines(
+				machineWithVersion("cp", pointer.String("v1.28.0")),
+			),
+			machinePools: []expv1.MachinePool{
+				machinePoolWithVersion("pool", pointer.String("v1.28.0")),
+				machinePoolWithVersion("pool-without-version", nil),
+			},
+			want: true,
+		},
+		{
+			name: "return false if a MachinePool is running an older Kubernetes version",
+			machines: collections.FromMachines(
+				machineWithVersion("cp", pointer.String("v1.28.0")),
+			),
+			machinePools: []expv1.MachinePool{
+				machinePoolWithVersion("pool", pointer.String("v1.27.3")),
+			},
+			want: false,
+		},
+		{
+			name: "return true if Machines are running a newer Kubernetes version or have no version",
+			machines: collections.FromMachines(
+				machineWithVersion("cp", pointer.String("v1.28.1")),
+				machineWithVersion("worker", nil),
+			),
+			want: true,
+		},
+		{
+			name: "return false if a worker Machine is running an older Kubernetes version",
+			machines: collections.FromMachines(
+				machineWithVersion("cp", pointer.String("v1.28.0")),
+				machineWithVersion("worker", pointer.String("v1.27.3")),
+			),
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			r := &KubeadmControlPlaneReconciler{
+				managementCluster: &fakeManagementCluster{
+					Machines:     tt.machines,
+					MachinePools: &expv1.MachinePoolList{Items: tt.machinePools},
+				},
+			}
+			controlPlane := &internal.ControlPlane{
+				Cluster: &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: metav1.NamespaceDefault}},
+			}
+
+			got, err := r.allMachinesUpgraded(ctx, controlPlane, semver.MustParse("1.28.0"))
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
 func TestKubeadmControlPlaneReconciler_reconcileDelete(t *testing.T) {
 	t.Run("removes all control plane Machines", func(t *testing.T) {
 		g := NewWithT(t)
@@ -2396,7 +2502,7 @@ func createMachineNodePair(name string, cluster *clusterv1.Cluster, kcp *control
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: cluster.Namespace,
 			Name:      name,
-			Labels:    internal.ControlPlaneMachineLabelsForCluster(kcp, cluster.Name),
+			Labels:    internal.ControlPlaneMachineLabelsForCluster(kcp, cluster),
 			OwnerReferences: []metav1.OwnerReference{
 				*metav1.NewControllerRef(kcp, controlplanev1.GroupVersion.WithKind("KubeadmControlPlane")),
 			},
@@ -0,0 +1,342 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/remote"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/collections"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+)
+
+// etcdRestoreRequeueAfter is the interval the restore is checked at while in progress; the kube-apiserver of
+// the workload cluster is expected to be unavailable while the etcd data directory is swapped.
+const etcdRestoreRequeueAfter = 10 * time.Second
+
+// etcdRestoreTimeout is the maximum time a restore can take after it has been started; after this time the
+// restore is marked as failed and the KubeadmControlPlane is unpaused.
+const etcdRestoreTimeout = 15 * time.Minute
+
+// EtcdRestoreReconciler reconciles an EtcdRestore object.
+type EtcdRestoreReconciler struct {
+	Client  client.Client
+	Tracker *remote.ClusterCacheTracker
+
+	EtcdDialTimeout time.Duration
+	EtcdCallTimeout time.Duration
+
+	// WatchFilterValue is the label value used to filter events prior to reconciliation.
+	WatchFilterValue string
+
+	managementCluster internal.ManagementCluster
+}
+
+// SetupWithManager sets up the reconciler with the Manager.
+func (r *EtcdRestoreReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	err := ctrl.NewControllerManagedBy(mgr).
+		For(&controlplanev1.EtcdRestore{}).
+		WithOptions(options).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
+		Complete(r)
+	if err != nil {
+		return errors.Wrap(err, "failed setting up with a controller manager")
+	}
+
+	if r.managementCluster == nil {
+		if r.Tracker == nil {
+			return errors.New("cluster cache tracker is nil, cannot create the internal management cluster resource")
+		}
+		r.managementCluster = &internal.Management{
+			Client:          r.Client,
+			Tracker:         r.Tracker,
+			EtcdDialTimeout: r.EtcdDialTimeout,
+			EtcdCallTimeout: r.EtcdCallTimeout,
+		}
+	}
+	return nil
+}
+
+func (r *EtcdRestoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	restore := &controlplanev1.EtcdRestore{}
+	if err := r.Client.Get(ctx, req.NamespacedName, restore); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Once the restore is completed or failed, the only thing left to do is to unpause the KubeadmControlPlane.
+	if restore.Status.Phase == controlplanev1.EtcdRestorePhaseCompleted || restore.Status.Phase == controlplanev1.EtcdRestorePhaseFailed {
+		return ctrl.Result{}, r.unpauseControlPlane(ctx, restore)
+	}
+
+	patchHelper, err := patch.NewHelper(restore, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	defer func() {
+		if err := patchHelper.Patch(ctx, restore, patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{controlplanev1.EtcdRestoreCompletedCondition}}); err != nil {
+			reterr = kerrors.NewAggregate([]error{reterr, err})
+		}
+	}()
+
+	if restore.Status.Phase == "" {
+		restore.Status.Phase = controlplanev1.EtcdRestorePhasePending
+	}
+
+	kcp := &controlplanev1.KubeadmControlPlane{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: restore.Namespace, Name: restore.Spec.ControlPlaneName}, kcp); err != nil {
+		if apierrors.IsNotFound(err) {
+			markEtcdRestoreFailed(restore, controlplanev1.EtcdRestoreValidationFailedReason, "KubeadmControlPlane %s not found", restore.Spec.ControlPlaneName)
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	cluster, err := util.GetOwnerCluster(ctx, r.Client, kcp.ObjectMeta)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if cluster == nil {
+		log.Info("KubeadmControlPlane is missing owner Cluster")
+		return ctrl.Result{}, nil
+	}
+	if annotations.IsPaused(cluster, restore) {
+		log.Info("Reconciliation is paused for this object")
+		return ctrl.Result{}, nil
+	}
+
+	if restore.Status.Phase == controlplanev1.EtcdRestorePhaseRestoring {
+		return r.reconcileRestoring(ctx, cluster, restore)
+	}
+	return r.reconcileStart(ctx, cluster, kcp, restore)
+}
+
+// reconcileStart validates the restore, pauses the KubeadmControlPlane and starts the restore on the control plane node.
+func (r *EtcdRestoreReconciler) reconcileStart(ctx context.Context, cluster *clusterv1.Cluster, kcp *controlplanev1.KubeadmControlPlane, restore *controlplanev1.EtcdRestore) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	snapshot, nodeName, err := r.validate(ctx, cluster, kcp, restore)
+	if err != nil {
+		markEtcdRestoreFailed(restore, controlplanev1.EtcdRestoreValidationFailedReason, "%v", err)
+		return ctrl.Result{}, nil
+	}
+
+	var authorization string
+	if snapshot.Spec.Uploader.SecretName != "" {
+		uploaderSecret := &corev1.Secret{}
+		if err := r.Client.Get(ctx, client.ObjectKey{Namespace: snapshot.Namespace, Name: snapshot.Spec.Uploader.SecretName}, uploaderSecret); err != nil && !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		authorization = string(uploaderSecret.Data[controlplanev1.EtcdSnapshotUploaderAuthorizationKey])
+	}
+
+	// Pause the KubeadmControlPlane, so it does not act on the control plane while etcd and the kube-apiserver are down.
+	if kcp.Annotations[controlplanev1.EtcdRestoreAnnotation] != restore.Name {
+		kcpPatchHelper, err := patch.NewHelper(kcp, r.Client)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		annotations.AddAnnotations(kcp, map[string]string{
+			clusterv1.PausedAnnotation:           "",
+			controlplanev1.EtcdRestoreAnnotation: restore.Name,
+		})
+		if err := kcpPatchHelper.Patch(ctx, kcp); err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to pause KubeadmControlPlane %s", klog.KObj(kcp))
+		}
+	}
+
+	workloadCluster, err := r.managementCluster.GetWorkloadCluster(ctx, util.ObjectKey(cluster))
+	if err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to create remote cluster client")
+	}
+	helperImage := restore.Spec.HelperImage
+	if helperImage == "" {
+		helperImage = controlplanev1.DefaultEtcdRestoreHelperImage
+	}
+	if err := workloadCluster.StartEtcdRestore(ctx, internal.EtcdRestoreInput{
+		Name:          restore.Name,
+		NodeName:      nodeName,
+		SnapshotURL:   snapshot.Status.Location,
+		Authorization: authorization,
+		HelperImage:   helperImage,
+	}); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to start etcd restore")
+	}
+
+	log.Info("Etcd restore started", "node", nodeName, "snapshot", snapshot.Name)
+	restore.Status.Phase = controlplanev1.EtcdRestorePhaseRestoring
+	restore.Status.NodeName = nodeName
+	restore.Status.StartTime = &metav1.Time{Time: time.Now()}
+	conditions.MarkFalse(restore, controlplanev1.EtcdRestoreCompletedCondition, controlplanev1.EtcdRestoreInProgressReason, clusterv1.ConditionSeverityInfo, "Restoring snapshot %s on node %s", snapshot.Name, nodeName)
+	return ctrl.Result{RequeueAfter: etcdRestoreRequeueAfter}, nil
+}
+
+// validate checks that the snapshot can be restored on the KubeadmControlPlane; it returns the snapshot
+// and the name of the node hosting the etcd member to restore.
+// NOTE: Only control planes with a single replica can be restored; control planes with more replicas should be
+// scaled down to one replica first, and scaled up again once the restore is completed.
+func (r *EtcdRestoreReconciler) validate(ctx context.Context, cluster *clusterv1.Cluster, kcp *controlplanev1.KubeadmControlPlane, restore *controlplanev1.EtcdRestore) (*controlplanev1.EtcdSnapshot, string, error) {
+	snapshot := &controlplanev1.EtcdSnapshot{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: restore.Namespace, Name: restore.Spec.SnapshotName}, snapshot); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, "", errors.Errorf("EtcdSnapshot %s not found", restore.Spec.SnapshotName)
+		}
+		return nil, "", err
+	}
+	if snapshot.Spec.ControlPlaneName != kcp.Name {
+		return nil, "", errors.Errorf("EtcdSnapshot %s has been taken from KubeadmControlPlane %s", snapshot.Name, snapshot.Spec.ControlPlaneName)
+	}
+	if snapshot.Status.Phase != controlplanev1.EtcdSnapshotPhaseCompleted || snapshot.Status.Location == "" {
+		return nil, "", errors.Errorf("EtcdSnapshot %s is not completed", snapshot.Name)
+	}
+
+	if cc := kcp.Spec.KubeadmConfigSpec.ClusterConfiguration; cc != nil && cc.Etcd.External != nil {
+		return nil, "", errors.New("KubeadmControlPlane uses an external etcd")
+	}
+	if kcp.Spec.Replicas == nil || *kcp.Spec.Replicas != 1 {
+		return nil, "", errors.New("KubeadmControlPlane must have exactly one replica")
+	}
+	if annotations.HasPaused(kcp) && kcp.Annotations[controlplanev1.EtcdRestoreAnnotation] != restore.Name {
+		return nil, "", errors.New("KubeadmControlPlane is paused")
+	}
+
+	machines, err := r.managementCluster.GetMachinesForCluster(ctx, cluster, collections.OwnedMachines(kcp))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(machines) != 1 {
+		return nil, "", errors.Errorf("KubeadmControlPlane must have exactly one Machine, found %d", len(machines))
+	}
+	machine := machines.Oldest()
+	if machine.Status.NodeRef == nil {
+		return nil, "", errors.Errorf("Machine %s does not have a Node", machine.Name)
+	}
+	return snapshot, machine.Status.NodeRef.Name, nil
+}
+
+// reconcileRestoring supervises the restore running on the control plane node.
+func (r *EtcdRestoreReconciler) reconcileRestoring(ctx context.Context, cluster *clusterv1.Cluster, restore *controlplanev1.EtcdRestore) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	// Errors connecting to the workload cluster are expected while the etcd data directory is swapped.
+	workloadCluster, err := r.managementCluster.GetWorkloadCluster(ctx, util.ObjectKey(cluster))
+	if err != nil {
+		log.V(4).Info("Waiting for the workload cluster to be reachable", "err", err.Error())
+		return r.requeueOrTimeoutRestore(ctx, restore)
+	}
+	completed, failureMessage, err := workloadCluster.EtcdRestoreStatus(ctx, restore.Name)
+	if err != nil {
+		log.V(4).Info("Waiting for the etcd restore status to be available", "err", err.Error())
+		return r.requeueOrTimeoutRestore(ctx, restore)
+	}
+
+	if failureMessage != "" {
+		if err := workloadCluster.CleanupEtcdRestore(ctx, restore.Name); err != nil {
+			return ctrl.Result{}, err
+		}
+		markEtcdRestoreFailed(restore, controlplanev1.EtcdRestoreFailedReason, "%s", failureMessage)
+		return ctrl.Result{}, r.unpauseControlPlane(ctx, restore)
+	}
+	if !completed {
+		if etcdRestoreTimeoutExceeded(restore) {
+			// Best effort cleanup; the workload cluster is reachable, but the restore Pod might be stuck.
+			if err := workloadCluster.CleanupEtcdRestore(ctx, restore.Name); err != nil {
+				log.Error(err, "Failed to cleanup etcd restore after timeout")
+			}
+		}
+		return r.requeueOrTimeoutRestore(ctx, restore)
+	}
+
+	log.Info("Etcd restore completed", "node", restore.Status.NodeName)
+	restore.Status.Phase = controlplanev1.EtcdRestorePhaseCompleted
+	restore.Status.CompletionTime = &metav1.Time{Time: time.Now()}
+	conditions.MarkTrue(restore, controlplanev1.EtcdRestoreCompletedCondition)
+	return ctrl.Result{}, r.unpauseControlPlane(ctx, restore)
+}
+
+// requeueOrTimeoutRestore requeues a restore in progress, or marks it as failed and unpauses the
+// KubeadmControlPlane if the restore did not complete within etcdRestoreTimeout.
+func (r *EtcdRestoreReconciler) requeueOrTimeoutRestore(ctx context.Context, restore *controlplanev1.EtcdRestore) (ctrl.Result, error) {
+	if !etcdRestoreTimeoutExceeded(restore) {
+		return ctrl.Result{RequeueAfter: etcdRestoreRequeueAfter}, nil
+	}
+	markEtcdRestoreFailed(restore, controlplanev1.EtcdRestoreFailedReason, "restore did not complete within %s", etcdRestoreTimeout)
+	return ctrl.Result{}, r.unpauseControlPlane(ctx, restore)
+}
+
+// etcdRestoreTimeoutExceeded returns true if the restore has been started more than etcdRestoreTimeout ago.
+func etcdRestoreTimeoutExceeded(restore *controlplanev1.EtcdRestore) bool {
+	if restore.Status.StartTime == nil {
+		return false
+	}
+	return time.Since(restore.Status.StartTime.Time) > etcdRestoreTimeout
+}
+
+// unpauseControlPlane removes the paused annotation from the KubeadmControlPlane, if it has been added by the restore.
+func (r *EtcdRestoreReconciler) unpauseControlPlane(ctx context.Context, restore *controlplanev1.EtcdRestore) error {
+	kcp := &controlplanev1.KubeadmControlPlane{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: restore.Namespace, Name: restore.Spec.ControlPlaneName}, kcp); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if kcp.Annotations[controlplanev1.EtcdRestoreAnnotation] != restore.Name {
+		return nil
+	}
+
+	patchHelper, err := patch.NewHelper(kcp, r.Client)
+	if err != nil {
+		return err
+	}
+	delete(kcp.Annotations, clusterv1.PausedAnnotation)
+	delete(kcp.Annotations, controlplanev1.EtcdRestoreAnnotation)
+	if err := patchHelper.Patch(ctx, kcp); err != nil {
+		return errors.Wrapf(err, "failed to unpause KubeadmControlPlane %s", klog.KObj(kcp))
+	}
+	return nil
+}
+
+func markEtcdRestoreFailed(restore *controlplanev1.EtcdRestore, reason, messageFormat string, messageArgs ...interface{}) {
+	restore.Status.Phase = controlplanev1.EtcdRestorePhaseFailed
+	restore.Status.FailureMessage = pointer.String(fmt.Sprintf(messageFormat, messageArgs...))
+	conditions.MarkFalse(restore, controlplanev1.EtcdRestoreCompletedCondition, reason, clusterv1.ConditionSeverityError, messageFormat, messageArgs...)
+}
@@ -0,0 +1,284 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/util/collections"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestEtcdRestoreReconciler(t *testing.T) {
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: metav1.NamespaceDefault},
+	}
+	kcp := &controlplanev1.KubeadmControlPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kcp",
+			Namespace: metav1.NamespaceDefault,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: clusterv1.GroupVersion.String(),
+				Kind:       "Cluster",
+				Name:       cluster.Name,
+			}},
+		},
+		Spec: controlplanev1.KubeadmControlPlaneSpec{
+			Replicas: pointer.Int32(1),
+		},
+	}
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{Name: "machine", Namespace: metav1.NamespaceDefault},
+		Status: clusterv1.MachineStatus{
+			NodeRef: &corev1.ObjectReference{Name: "cp1"},
+		},
+	}
+	snapshot := &controlplanev1.EtcdSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Name: "snapshot", Namespace: metav1.NamespaceDefault},
+		Spec: controlplanev1.EtcdSnapshotSpec{
+			ControlPlaneName: kcp.Name,
+			Uploader:         controlplanev1.EtcdSnapshotUploader{SecretName: "uploader"},
+		},
+		Status: controlplanev1.EtcdSnapshotStatus{
+			Phase:    controlplanev1.EtcdSnapshotPhaseCompleted,
+			Location: "https://backend.example.com/default/kcp/snapshot.db",
+		},
+	}
+	restore := &controlplanev1.EtcdRestore{
+		ObjectMeta: metav1.ObjectMeta{Name: "restore", Namespace: metav1.NamespaceDefault},
+		Spec: controlplanev1.EtcdRestoreSpec{
+			ControlPlaneName: kcp.Name,
+			SnapshotName:     snapshot.Name,
+		},
+	}
+	etcdPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "etcd-cp1", Namespace: metav1.NamespaceSystem},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:    "etcd",
+				Image:   "registry.k8s.io/etcd:3.5.9-0",
+				Command: []string{"etcd", "--name=cp1", "--initial-advertise-peer-urls=https://10.0.0.1:2380"},
+			}},
+		},
+	}
+	restorePodKey := client.ObjectKey{Namespace: metav1.NamespaceSystem, Name: "etcd-restore-restore"}
+	pausedByRestore := func(kcp *controlplanev1.KubeadmControlPlane) *controlplanev1.KubeadmControlPlane {
+		kcp = kcp.DeepCopy()
+		kcp.Annotations = map[string]string{
+			clusterv1.PausedAnnotation:           "",
+			controlplanev1.EtcdRestoreAnnotation: restore.Name,
+		}
+		return kcp
+	}
+	restoring := restore.DeepCopy()
+	restoring.Status.Phase = controlplanev1.EtcdRestorePhaseRestoring
+	restoring.Status.NodeName = "cp1"
+
+	type result struct {
+		restore        *controlplanev1.EtcdRestore
+		kcp            *controlplanev1.KubeadmControlPlane
+		workloadClient client.Client
+	}
+	reconcile := func(g *WithT, objs []client.Object, workloadObjs []client.Object) result {
+		fakeClient := fake.NewClientBuilder().WithObjects(objs...).WithStatusSubresource(&controlplanev1.EtcdRestore{}).Build()
+		workloadClient := fake.NewClientBuilder().WithObjects(workloadObjs...).Build()
+		r := &EtcdRestoreReconciler{
+			Client: fakeClient,
+			managementCluster: &fakeManagementCluster{
+				Machines: collections.FromMachines(machine),
+				Workload: fakeWorkloadCluster{Workload: &internal.Workload{Client: workloadClient}},
+			},
+		}
+
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(restore)})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		res := result{
+			restore:        &controlplanev1.EtcdRestore{},
+			kcp:            &controlplanev1.KubeadmControlPlane{},
+			workloadClient: workloadClient,
+		}
+		g.Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(restore), res.restore)).To(Succeed())
+		g.Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(kcp), res.kcp)).To(Succeed())
+		return res
+	}
+
+	t.Run("rejects the restore if the validation fails", func(t *testing.T) {
+		notCompletedSnapshot := snapshot.DeepCopy()
+		notCompletedSnapshot.Status = controlplanev1.EtcdSnapshotStatus{Phase: controlplanev1.EtcdSnapshotPhaseFailed}
+		otherControlPlaneSnapshot := snapshot.DeepCopy()
+		otherControlPlaneSnapshot.Spec.ControlPlaneName = "other-kcp"
+		scaledOutKCP := kcp.DeepCopy()
+		scaledOutKCP.Spec.Replicas = pointer.Int32(3)
+		pausedKCP := kcp.DeepCopy()
+		pausedKCP.Annotations = map[string]string{clusterv1.PausedAnnotation: ""}
+
+		tests := []struct {
+			name           string
+			objs           []client.Object
+			expectedReason string
+		}{
+			{
+				name:           "snapshot not found",
+				objs:           []client.Object{cluster, kcp, restore},
+				expectedReason: "EtcdSnapshot snapshot not found",
+			},
+			{
+				name:           "snapshot not completed",
+				objs:           []client.Object{cluster, kcp, notCompletedSnapshot, restore},
+				expectedReason: "is not completed",
+			},
+			{
+				name:           "snapshot taken from another control plane",
+				objs:           []client.Object{cluster, kcp, otherControlPlaneSnapshot, restore},
+				expectedReason: "has been taken from KubeadmControlPlane other-kcp",
+			},
+			{
+				name:           "control plane with more than one replica",
+				objs:           []client.Object{cluster, scaledOutKCP, snapshot, restore},
+				expectedReason: "must have exactly one replica",
+			},
+			{
+				name:           "control plane already paused",
+				objs:           []client.Object{cluster, pausedKCP, snapshot, restore},
+				expectedReason: "is paused",
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				g := NewWithT(t)
+
+				res := reconcile(g, tt.objs, []client.Object{etcdPod})
+
+				g.Expect(res.restore.Status.Phase).To(Equal(controlplanev1.EtcdRestorePhaseFailed))
+				g.Expect(res.restore.Status.FailureMessage).To(HaveValue(ContainSubstring(tt.expectedReason)))
+				g.Expect(conditions.GetReason(res.restore, controlplanev1.EtcdRestoreCompletedCondition)).To(Equal(controlplanev1.EtcdRestoreValidationFailedReason))
+				g.Expect(res.kcp.Annotations).ToNot(HaveKey(controlplanev1.EtcdRestoreAnnotation))
+				g.Expect(apierrors.IsNotFound(res.workloadClient.Get(ctx, restorePodKey, &corev1.Pod{}))).To(BeTrue())
+			})
+		}
+	})
+
+	t.Run("pauses the control plane and starts the restore", func(t *testing.T) {
+		g := NewWithT(t)
+
+		res := reconcile(g, []client.Object{cluster, kcp, snapshot, restore}, []client.Object{etcdPod})
+
+		g.Expect(res.restore.Status.Phase).To(Equal(controlplanev1.EtcdRestorePhaseRestoring))
+		g.Expect(res.restore.Status.NodeName).To(Equal("cp1"))
+		g.Expect(res.restore.Status.StartTime).ToNot(BeNil())
+		g.Expect(conditions.GetReason(res.restore, controlplanev1.EtcdRestoreCompletedCondition)).To(Equal(controlplanev1.EtcdRestoreInProgressReason))
+		g.Expect(res.kcp.Annotations).To(HaveKey(clusterv1.PausedAnnotation))
+		g.Expect(res.kcp.Annotations).To(HaveKeyWithValue(controlplanev1.EtcdRestoreAnnotation, restore.Name))
+
+		pod := &corev1.Pod{}
+		g.Expect(res.workloadClient.Get(ctx, restorePodKey, pod)).To(Succeed())
+		g.Expect(pod.Spec.NodeName).To(Equal("cp1"))
+		g.Expect(pod.Spec.InitContainers[0].Image).To(Equal(controlplanev1.DefaultEtcdRestoreHelperImage))
+	})
+
+	t.Run("waits for the restore Pod to be gone", func(t *testing.T) {
+		g := NewWithT(t)
+
+		restorePod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: restorePodKey.Name, Namespace: restorePodKey.Namespace},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+		res := reconcile(g, []client.Object{cluster, pausedByRestore(kcp), snapshot, restoring}, []client.Object{etcdPod, restorePod})
+
+		g.Expect(res.restore.Status.Phase).To(Equal(controlplanev1.EtcdRestorePhaseRestoring))
+		g.Expect(res.kcp.Annotations).To(HaveKey(clusterv1.PausedAnnotation))
+	})
+
+	t.Run("fails the restore and unpauses the control plane if the restore does not complete in time", func(t *testing.T) {
+		g := NewWithT(t)
+
+		restorePod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: restorePodKey.Name, Namespace: restorePodKey.Namespace},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+		timedOut := restoring.DeepCopy()
+		timedOut.Status.StartTime = &metav1.Time{Time: time.Now().Add(-etcdRestoreTimeout - time.Minute)}
+		res := reconcile(g, []client.Object{cluster, pausedByRestore(kcp), snapshot, timedOut}, []client.Object{etcdPod, restorePod})
+
+		g.Expect(res.restore.Status.Phase).To(Equal(controlplanev1.EtcdRestorePhaseFailed))
+		g.Expect(res.restore.Status.FailureMessage).To(HaveValue(ContainSubstring("did not complete within")))
+		g.Expect(conditions.GetReason(res.restore, controlplanev1.EtcdRestoreCompletedCondition)).To(Equal(controlplanev1.EtcdRestoreFailedReason))
+		g.Expect(res.kcp.Annotations).ToNot(HaveKey(clusterv1.PausedAnnotation))
+		g.Expect(res.kcp.Annotations).ToNot(HaveKey(controlplanev1.EtcdRestoreAnnotation))
+	})
+
+	t.Run("completes the restore and unpauses the control plane", func(t *testing.T) {
+		g := NewWithT(t)
+
+		res := reconcile(g, []client.Object{cluster, pausedByRestore(kcp), snapshot, restoring}, []client.Object{etcdPod})
+
+		g.Expect(res.restore.Status.Phase).To(Equal(controlplanev1.EtcdRestorePhaseCompleted))
+		g.Expect(res.restore.Status.CompletionTime).ToNot(BeNil())
+		g.Expect(conditions.IsTrue(res.restore, controlplanev1.EtcdRestoreCompletedCondition)).To(BeTrue())
+		g.Expect(res.kcp.Annotations).ToNot(HaveKey(clusterv1.PausedAnnotation))
+		g.Expect(res.kcp.Annotations).ToNot(HaveKey(controlplanev1.EtcdRestoreAnnotation))
+	})
+
+	t.Run("fails the restore and unpauses the control plane if the restore Pod failed", func(t *testing.T) {
+		g := NewWithT(t)
+
+		restorePod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: restorePodKey.Name, Namespace: restorePodKey.Namespace},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodFailed,
+				InitContainerStatuses: []corev1.ContainerStatus{{
+					Name:  "download",
+					State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 22, Message: "404 Not Found"}},
+				}},
+			},
+		}
+		res := reconcile(g, []client.Object{cluster, pausedByRestore(kcp), snapshot, restoring}, []client.Object{etcdPod, restorePod})
+
+		g.Expect(res.restore.Status.Phase).To(Equal(controlplanev1.EtcdRestorePhaseFailed))
+		g.Expect(res.restore.Status.FailureMessage).To(HaveValue(ContainSubstring("404 Not Found")))
+		g.Expect(conditions.GetReason(res.restore, controlplanev1.EtcdRestoreCompletedCondition)).To(Equal(controlplanev1.EtcdRestoreFailedReason))
+		g.Expect(res.kcp.Annotations).ToNot(HaveKey(clusterv1.PausedAnnotation))
+		g.Expect(apierrors.IsNotFound(res.workloadClient.Get(ctx, restorePodKey, &corev1.Pod{}))).To(BeTrue())
+	})
+
+	t.Run("does not unpause a control plane paused by someone else", func(t *testing.T) {
+		g := NewWithT(t)
+
+		pausedKCP := kcp.DeepCopy()
+		pausedKCP.Annotations = map[string]string{clusterv1.PausedAnnotation: ""}
+		failed := restore.DeepCopy()
+		failed.Status.Phase = controlplanev1.EtcdRestorePhaseFailed
+
+		res := reconcile(g, []client.Object{cluster, pausedKCP, snapshot, failed}, nil)
+
+		g.Expect(res.kcp.Annotations).To(HaveKey(clusterv1.PausedAnnotation))
+	})
+}
@@ -0,0 +1,235 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/remote"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+)
+
+// etcdSnapshotTimeout is the maximum time taking and uploading a snapshot can take; it bounds the
+// reconcile, so a slow or unresponsive upload endpoint does not block a worker indefinitely.
+const etcdSnapshotTimeout = 10 * time.Minute
+
+// EtcdSnapshotReconciler reconciles an EtcdSnapshot object.
+type EtcdSnapshotReconciler struct {
+	Client  client.Client
+	Tracker *remote.ClusterCacheTracker
+
+	EtcdDialTimeout time.Duration
+	EtcdCallTimeout time.Duration
+
+	// WatchFilterValue is the label value used to filter events prior to reconciliation.
+	WatchFilterValue string
+
+	managementCluster internal.ManagementCluster
+	httpClient        *http.Client
+}
+
+// SetupWithManager sets up the reconciler with the Manager.
+func (r *EtcdSnapshotReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	err := ctrl.NewControllerManagedBy(mgr).
+		For(&controlplanev1.EtcdSnapshot{}).
+		WithOptions(options).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
+		Complete(r)
+	if err != nil {
+		return errors.Wrap(err, "failed setting up with a controller manager")
+	}
+
+	if r.managementCluster == nil {
+		if r.Tracker == nil {
+			return errors.New("cluster cache tracker is nil, cannot create the internal management cluster resource")
+		}
+		r.managementCluster = &internal.Management{
+			Client:          r.Client,
+			Tracker:         r.Tracker,
+			EtcdDialTimeout: r.EtcdDialTimeout,
+			EtcdCallTimeout: r.EtcdCallTimeout,
+		}
+	}
+	if r.httpClient == nil {
+		r.httpClient = http.DefaultClient
+	}
+	return nil
+}
+
+func (r *EtcdSnapshotReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	snapshot := &controlplanev1.EtcdSnapshot{}
+	if err := r.Client.Get(ctx, req.NamespacedName, snapshot); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Snapshots are taken only once; failed snapshots are not retried.
+	if snapshot.Status.Phase == controlplanev1.EtcdSnapshotPhaseCompleted || snapshot.Status.Phase == controlplanev1.EtcdSnapshotPhaseFailed {
+		return ctrl.Result{}, nil
+	}
+
+	patchHelper, err := patch.NewHelper(snapshot, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	defer func() {
+		if err := patchHelper.Patch(ctx, snapshot, patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{controlplanev1.EtcdSnapshotCompletedCondition}}); err != nil {
+			reterr = kerrors.NewAggregate([]error{reterr, err})
+		}
+	}()
+
+	if snapshot.Status.Phase == "" {
+		snapshot.Status.Phase = controlplanev1.EtcdSnapshotPhasePending
+	}
+
+	kcp := &controlplanev1.KubeadmControlPlane{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: snapshot.Namespace, Name: snapshot.Spec.ControlPlaneName}, kcp); err != nil {
+		if apierrors.IsNotFound(err) {
+			markEtcdSnapshotFailed(snapshot, "KubeadmControlPlane %s not found", snapshot.Spec.ControlPlaneName)
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	cluster, err := util.GetOwnerCluster(ctx, r.Client, kcp.ObjectMeta)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if cluster == nil {
+		log.Info("KubeadmControlPlane is missing owner Cluster")
+		return ctrl.Result{}, nil
+	}
+	if annotations.IsPaused(cluster, snapshot) {
+		log.Info("Reconciliation is paused for this object")
+		return ctrl.Result{}, nil
+	}
+
+	uploaderSecret := &corev1.Secret{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: snapshot.Namespace, Name: snapshot.Spec.Uploader.SecretName}, uploaderSecret); err != nil {
+		if apierrors.IsNotFound(err) {
+			markEtcdSnapshotFailed(snapshot, "uploader Secret %s not found", snapshot.Spec.Uploader.SecretName)
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	baseURL := string(uploaderSecret.Data[controlplanev1.EtcdSnapshotUploaderURLKey])
+	if baseURL == "" {
+		markEtcdSnapshotFailed(snapshot, "uploader Secret %s does not contain the %q key", snapshot.Spec.Uploader.SecretName, controlplanev1.EtcdSnapshotUploaderURLKey)
+		return ctrl.Result{}, nil
+	}
+	location := etcdSnapshotLocation(baseURL, snapshot)
+
+	workloadCluster, err := r.managementCluster.GetWorkloadCluster(ctx, util.ObjectKey(cluster))
+	if err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to create remote cluster client")
+	}
+
+	snapshotCtx, cancel := context.WithTimeout(ctx, etcdSnapshotTimeout)
+	defer cancel()
+
+	var size int64
+	member, err := workloadCluster.SnapshotEtcd(snapshotCtx, func(data io.Reader) error {
+		var uploadErr error
+		size, uploadErr = r.upload(snapshotCtx, location, string(uploaderSecret.Data[controlplanev1.EtcdSnapshotUploaderAuthorizationKey]), data)
+		return uploadErr
+	})
+	if err != nil {
+		markEtcdSnapshotFailed(snapshot, "%v", err)
+		return ctrl.Result{}, nil
+	}
+
+	log.Info("Etcd snapshot completed", "location", location, "member", member)
+	snapshot.Status.Phase = controlplanev1.EtcdSnapshotPhaseCompleted
+	snapshot.Status.Location = location
+	snapshot.Status.Size = pointer.Int64(size)
+	snapshot.Status.Member = member
+	snapshot.Status.CompletionTime = &metav1.Time{Time: time.Now()}
+	conditions.MarkTrue(snapshot, controlplanev1.EtcdSnapshotCompletedCondition)
+	return ctrl.Result{}, nil
+}
+
+// upload uploads the snapshot to the given location with an HTTP PUT request and returns its size.
+func (r *EtcdSnapshotReconciler) upload(ctx context.Context, location, authorization string, snapshot io.Reader) (int64, error) {
+	body := &countingReader{reader: snapshot}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, location, body)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to create upload request")
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to upload etcd snapshot to %s", location)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return 0, errors.Errorf("failed to upload etcd snapshot to %s: unexpected status %s", location, resp.Status)
+	}
+	return body.size, nil
+}
+
+// etcdSnapshotLocation returns the URL an EtcdSnapshot is uploaded to.
+func etcdSnapshotLocation(baseURL string, snapshot *controlplanev1.EtcdSnapshot) string {
+	return fmt.Sprintf("%s/%s/%s/%s.db", strings.TrimSuffix(baseURL, "/"), snapshot.Namespace, snapshot.Spec.ControlPlaneName, snapshot.Name)
+}
+
+func markEtcdSnapshotFailed(snapshot *controlplanev1.EtcdSnapshot, messageFormat string, messageArgs ...interface{}) {
+	snapshot.Status.Phase = controlplanev1.EtcdSnapshotPhaseFailed
+	snapshot.Status.FailureMessage = pointer.String(fmt.Sprintf(messageFormat, messageArgs...))
+	conditions.MarkFalse(snapshot, controlplanev1.EtcdSnapshotCompletedCondition, controlplanev1.EtcdSnapshotFailedReason, clusterv1.ConditionSeverityError, messageFormat, messageArgs...)
+}
+
+// countingReader counts the bytes read from the underlying reader.
+type countingReader struct {
+	reader io.Reader
+	size   int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.reader.Read(p)
+	c.size += int64(n)
+	return n, err
+}
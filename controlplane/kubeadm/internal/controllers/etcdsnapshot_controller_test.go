@@ -0,0 +1,157 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestEtcdSnapshotReconciler(t *testing.T) {
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: metav1.NamespaceDefault},
+	}
+	kcp := &controlplanev1.KubeadmControlPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kcp",
+			Namespace: metav1.NamespaceDefault,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: clusterv1.GroupVersion.String(),
+				Kind:       "Cluster",
+				Name:       cluster.Name,
+			}},
+		},
+	}
+	snapshot := &controlplanev1.EtcdSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Name: "snapshot", Namespace: metav1.NamespaceDefault},
+		Spec: controlplanev1.EtcdSnapshotSpec{
+			ControlPlaneName: kcp.Name,
+			Uploader:         controlplanev1.EtcdSnapshotUploader{SecretName: "uploader"},
+		},
+	}
+	uploaderSecret := func(url string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "uploader", Namespace: metav1.NamespaceDefault},
+			Data: map[string][]byte{
+				controlplanev1.EtcdSnapshotUploaderURLKey:           []byte(url),
+				controlplanev1.EtcdSnapshotUploaderAuthorizationKey: []byte("Bearer token"),
+			},
+		}
+	}
+
+	reconcile := func(g *WithT, server *httptest.Server, objs ...client.Object) *controlplanev1.EtcdSnapshot {
+		fakeClient := fake.NewClientBuilder().WithObjects(objs...).WithStatusSubresource(&controlplanev1.EtcdSnapshot{}).Build()
+		r := &EtcdSnapshotReconciler{
+			Client: fakeClient,
+			managementCluster: &fakeManagementCluster{
+				Workload: fakeWorkloadCluster{EtcdSnapshotResult: []byte("snapshot-data")},
+			},
+			httpClient: server.Client(),
+		}
+
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(snapshot)})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		got := &controlplanev1.EtcdSnapshot{}
+		g.Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(snapshot), got)).To(Succeed())
+		return got
+	}
+
+	t.Run("uploads the snapshot to the backend", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var method, path, authorization, body string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			data, _ := io.ReadAll(r.Body)
+			method, path, authorization, body = r.Method, r.URL.Path, r.Header.Get("Authorization"), string(data)
+			w.WriteHeader(http.StatusCreated)
+		}))
+		defer server.Close()
+
+		got := reconcile(g, server, cluster, kcp, snapshot, uploaderSecret(server.URL+"/snapshots/"))
+
+		g.Expect(method).To(Equal(http.MethodPut))
+		g.Expect(path).To(Equal("/snapshots/default/kcp/snapshot.db"))
+		g.Expect(authorization).To(Equal("Bearer token"))
+		g.Expect(body).To(Equal("snapshot-data"))
+
+		g.Expect(got.Status.Phase).To(Equal(controlplanev1.EtcdSnapshotPhaseCompleted))
+		g.Expect(got.Status.Location).To(Equal(server.URL + "/snapshots/default/kcp/snapshot.db"))
+		g.Expect(got.Status.Size).To(HaveValue(Equal(int64(len("snapshot-data")))))
+		g.Expect(got.Status.Member).To(Equal("etcd-member"))
+		g.Expect(got.Status.CompletionTime).ToNot(BeNil())
+		g.Expect(conditions.IsTrue(got, controlplanev1.EtcdSnapshotCompletedCondition)).To(BeTrue())
+	})
+
+	t.Run("fails if the backend rejects the snapshot", func(t *testing.T) {
+		g := NewWithT(t)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		}))
+		defer server.Close()
+
+		got := reconcile(g, server, cluster, kcp, snapshot, uploaderSecret(server.URL))
+
+		g.Expect(got.Status.Phase).To(Equal(controlplanev1.EtcdSnapshotPhaseFailed))
+		g.Expect(got.Status.FailureMessage).To(HaveValue(ContainSubstring("403 Forbidden")))
+		g.Expect(conditions.GetReason(got, controlplanev1.EtcdSnapshotCompletedCondition)).To(Equal(controlplanev1.EtcdSnapshotFailedReason))
+	})
+
+	t.Run("fails if the uploader Secret does not contain the url", func(t *testing.T) {
+		g := NewWithT(t)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Error("unexpected upload")
+		}))
+		defer server.Close()
+
+		got := reconcile(g, server, cluster, kcp, snapshot, uploaderSecret(""))
+
+		g.Expect(got.Status.Phase).To(Equal(controlplanev1.EtcdSnapshotPhaseFailed))
+		g.Expect(got.Status.FailureMessage).To(HaveValue(ContainSubstring(`"url"`)))
+	})
+
+	t.Run("does not take completed snapshots again", func(t *testing.T) {
+		g := NewWithT(t)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Error("unexpected upload")
+		}))
+		defer server.Close()
+
+		completed := snapshot.DeepCopy()
+		completed.Status.Phase = controlplanev1.EtcdSnapshotPhaseCompleted
+
+		got := reconcile(g, server, cluster, kcp, completed, uploaderSecret(server.URL))
+
+		g.Expect(got.Status.Phase).To(Equal(controlplanev1.EtcdSnapshotPhaseCompleted))
+	})
+}
@@ -17,7 +17,9 @@ limitations under the License.
 package controllers
 
 import (
+	"bytes"
 	"context"
+	"io"
 	"time"
 
 	"github.com/blang/semver"
@@ -71,6 +73,8 @@ type fakeWorkloadCluster struct {
 	Status                     internal.ClusterStatus
 	EtcdMembersResult          []string
 	APIServerCertificateExpiry *time.Time
+	ControlPlaneVersionResult  *semver.Version
+	EtcdSnapshotResult         []byte
 }
 
 func (f fakeWorkloadCluster) ForwardEtcdLeadership(_ context.Context, _ *clusterv1.Machine, leaderCandidate *clusterv1.Machine) error {
@@ -84,10 +88,21 @@ func (f fakeWorkloadCluster) ReconcileEtcdMembers(_ context.Context, _ []string,
 	return nil, nil
 }
 
+func (f fakeWorkloadCluster) SnapshotEtcd(_ context.Context, upload func(snapshot io.Reader) error) (string, error) {
+	if err := upload(bytes.NewReader(f.EtcdSnapshotResult)); err != nil {
+		return "", err
+	}
+	return "etcd-member", nil
+}
+
 func (f fakeWorkloadCluster) ClusterStatus(_ context.Context) (internal.ClusterStatus, error) {
 	return f.Status, nil
 }
 
+func (f fakeWorkloadCluster) ControlPlaneVersion(_ context.Context) (*semver.Version, error) {
+	return f.ControlPlaneVersionResult, nil
+}
+
 func (f fakeWorkloadCluster) GetAPIServerCertificateExpiry(_ context.Context, _ *bootstrapv1.KubeadmConfig, _ string) (*time.Time, error) {
 	return f.APIServerCertificateExpiry, nil
 }
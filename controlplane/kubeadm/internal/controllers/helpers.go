@@ -181,8 +181,8 @@ func (r *KubeadmControlPlaneReconciler) cloneConfigsAndGenerateMachine(ctx conte
 		Namespace:   kcp.Namespace,
 		OwnerRef:    infraCloneOwner,
 		ClusterName: cluster.Name,
-		Labels:      internal.ControlPlaneMachineLabelsForCluster(kcp, cluster.Name),
-		Annotations: kcp.Spec.MachineTemplate.ObjectMeta.Annotations,
+		Labels:      internal.ControlPlaneMachineLabelsForCluster(kcp, cluster),
+		Annotations: internal.ControlPlaneMachineAnnotationsForCluster(kcp, cluster),
 	})
 	if err != nil {
 		// Safe to return early here since no resources have been created yet.
@@ -254,8 +254,8 @@ func (r *KubeadmControlPlaneReconciler) generateKubeadmConfig(ctx context.Contex
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            names.SimpleNameGenerator.GenerateName(kcp.Name + "-"),
 			Namespace:       kcp.Namespace,
-			Labels:          internal.ControlPlaneMachineLabelsForCluster(kcp, cluster.Name),
-			Annotations:     kcp.Spec.MachineTemplate.ObjectMeta.Annotations,
+			Labels:          internal.ControlPlaneMachineLabelsForCluster(kcp, cluster),
+			Annotations:     internal.ControlPlaneMachineAnnotationsForCluster(kcp, cluster),
 			OwnerReferences: []metav1.OwnerReference{owner},
 		},
 		Spec: *spec,
@@ -287,9 +287,9 @@ func (r *KubeadmControlPlaneReconciler) updateExternalObject(ctx context.Context
 	updatedObject.SetUID(obj.GetUID())
 
 	// Update labels
-	updatedObject.SetLabels(internal.ControlPlaneMachineLabelsForCluster(kcp, cluster.Name))
+	updatedObject.SetLabels(internal.ControlPlaneMachineLabelsForCluster(kcp, cluster))
 	// Update annotations
-	updatedObject.SetAnnotations(kcp.Spec.MachineTemplate.ObjectMeta.Annotations)
+	updatedObject.SetAnnotations(internal.ControlPlaneMachineAnnotationsForCluster(kcp, cluster))
 
 	if err := ssa.Patch(ctx, r.Client, kcpManagerName, updatedObject, ssa.WithCachingProxy{Cache: r.ssaCache, Original: obj}); err != nil {
 		return errors.Wrapf(err, "failed to update %s", obj.GetObjectKind().GroupVersionKind().Kind)
@@ -414,12 +414,11 @@ func (r *KubeadmControlPlaneReconciler) computeDesiredMachine(kcp *controlplanev
 	// When we update an existing Machine will we update the fields on the existing Machine (in-place mutate).
 
 	// Set labels
-	desiredMachine.Labels = internal.ControlPlaneMachineLabelsForCluster(kcp, cluster.Name)
+	desiredMachine.Labels = internal.ControlPlaneMachineLabelsForCluster(kcp, cluster)
 
 	// Set annotations
-	// Add the annotations from the MachineTemplate.
-	// Note: we intentionally don't use the map directly to ensure we don't modify the map in KCP.
-	for k, v := range kcp.Spec.MachineTemplate.ObjectMeta.Annotations {
+	// Add the annotations from the Cluster metadata and the MachineTemplate.
+	for k, v := range internal.ControlPlaneMachineAnnotationsForCluster(kcp, cluster) {
 		desiredMachine.Annotations[k] = v
 	}
 	for k, v := range annotations {
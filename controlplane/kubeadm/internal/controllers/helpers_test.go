@@ -573,6 +573,43 @@ func TestKubeadmControlPlaneReconciler_computeDesiredMachine(t *testing.T) {
 		g.Expect(kcp.Spec.MachineTemplate.ObjectMeta.Annotations).To(Equal(kcpMachineTemplateObjectMetaCopy.Annotations))
 	})
 
+	t.Run("should propagate the Cluster metadata to the Machine", func(t *testing.T) {
+		g := NewWithT(t)
+
+		clusterWithMetadata := cluster.DeepCopy()
+		clusterWithMetadata.Spec.Metadata = &clusterv1.ObjectMeta{
+			Labels: map[string]string{
+				"clusterLabel":         "clusterLabelValue",
+				"machineTemplateLabel": "clusterLabelValue",
+			},
+			Annotations: map[string]string{
+				"clusterAnnotation":         "clusterAnnotationValue",
+				"machineTemplateAnnotation": "clusterAnnotationValue",
+			},
+		}
+
+		createdMachine, err := (&KubeadmControlPlaneReconciler{}).computeDesiredMachine(
+			kcp, clusterWithMetadata,
+			infraRef, bootstrapRef,
+			nil, nil,
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		// Labels and annotations from the machineTemplate.ObjectMeta take precedence over the Cluster metadata.
+		g.Expect(createdMachine.Labels).To(Equal(map[string]string{
+			"clusterLabel":                         "clusterLabelValue",
+			"machineTemplateLabel":                 "machineTemplateLabelValue",
+			clusterv1.ClusterNameLabel:             cluster.Name,
+			clusterv1.MachineControlPlaneLabel:     "",
+			clusterv1.MachineControlPlaneNameLabel: kcp.Name,
+		}))
+		g.Expect(createdMachine.Annotations).To(Equal(map[string]string{
+			"clusterAnnotation":                                  "clusterAnnotationValue",
+			"machineTemplateAnnotation":                          "machineTemplateAnnotationValue",
+			controlplanev1.KubeadmClusterConfigurationAnnotation: clusterConfigurationString,
+		}))
+	})
+
 	t.Run("should return the correct Machine object when updating an existing Machine", func(t *testing.T) {
 		g := NewWithT(t)
 
@@ -34,6 +34,7 @@ import (
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
 	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/collections"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
 )
@@ -80,12 +81,8 @@ func (r *KubeadmControlPlaneReconciler) reconcileUnhealthyMachines(ctx context.C
 		return ctrl.Result{}, nil
 	}
 
-	// Select the machine to be remediated, which is the oldest machine marked as unhealthy.
-	//
-	// NOTE: The current solution is considered acceptable for the most frequent use case (only one unhealthy machine),
-	// however, in the future this could potentially be improved for the scenario where more than one unhealthy machine exists
-	// by considering which machine has lower impact on etcd quorum.
-	machineToBeRemediated := unhealthyMachines.Oldest()
+	// Select the machine to be remediated according to the remediation order, by default the oldest machine marked as unhealthy.
+	machineToBeRemediated := selectMachineToBeRemediated(controlPlane, unhealthyMachines)
 
 	// Returns if the machine is in the process of being deleted.
 	if !machineToBeRemediated.ObjectMeta.DeletionTimestamp.IsZero() {
@@ -156,16 +153,31 @@ func (r *KubeadmControlPlaneReconciler) reconcileUnhealthyMachines(ctx context.C
 		// Remediation MUST preserve etcd quorum. This rule ensures that KCP will not remove a member that would result in etcd
 		// losing a majority of members and thus become unable to field new requests.
 		if controlPlane.IsEtcdManaged() {
-			canSafelyRemediate, err := r.canSafelyRemoveEtcdMember(ctx, controlPlane, machineToBeRemediated)
+			canSafelyRemediate, projection, err := r.canSafelyRemoveEtcdMember(ctx, controlPlane, machineToBeRemediated)
 			if err != nil {
 				conditions.MarkFalse(machineToBeRemediated, clusterv1.MachineOwnerRemediatedCondition, clusterv1.RemediationFailedReason, clusterv1.ConditionSeverityError, err.Error())
 				return ctrl.Result{}, err
 			}
 			if !canSafelyRemediate {
 				log.Info("A control plane machine needs remediation, but removing this machine could result in etcd quorum loss. Skipping remediation")
-				conditions.MarkFalse(machineToBeRemediated, clusterv1.MachineOwnerRemediatedCondition, clusterv1.WaitingForRemediationReason, clusterv1.ConditionSeverityWarning, "KCP can't remediate this machine because this could result in etcd loosing quorum")
+				conditions.MarkFalse(machineToBeRemediated, clusterv1.MachineOwnerRemediatedCondition, clusterv1.WaitingForRemediationReason, clusterv1.ConditionSeverityWarning, "KCP can't remediate this machine because this could result in etcd loosing quorum (%d of %d etcd members healthy after remediation, quorum %d)",
+					projection.healthyMembers(), projection.totalMembers, projection.quorum)
 				return ctrl.Result{}, nil
 			}
+
+			// With the PreserveQuorumMargin policy, remediation MUST also leave etcd able to tolerate an additional member failure,
+			// unless remediation of the machine is forced.
+			if controlPlane.KCP.Spec.RemediationStrategy != nil &&
+				controlPlane.KCP.Spec.RemediationStrategy.EtcdQuorumPolicy == controlplanev1.PreserveQuorumMarginEtcdQuorumPolicyType &&
+				projection.margin() == 0 {
+				if _, ok := machineToBeRemediated.Annotations[controlplanev1.RemediationForceAnnotation]; !ok {
+					log.Info("A control plane machine needs remediation, but after removing this machine etcd could not tolerate any additional member failure. Skipping remediation")
+					conditions.MarkFalse(machineToBeRemediated, clusterv1.MachineOwnerRemediatedCondition, clusterv1.WaitingForRemediationReason, clusterv1.ConditionSeverityWarning, "KCP can't remediate this machine because etcd could not tolerate any additional member failure (%d of %d etcd members healthy after remediation, quorum %d) and EtcdQuorumPolicy is %s; set the %s annotation on the machine to force remediation",
+						projection.healthyMembers(), projection.totalMembers, projection.quorum, controlplanev1.PreserveQuorumMarginEtcdQuorumPolicyType, controlplanev1.RemediationForceAnnotation)
+					return ctrl.Result{}, nil
+				}
+				log.Info("A control plane machine needs remediation, and after removing this machine etcd could not tolerate any additional member failure. Forcing remediation", "annotation", controlplanev1.RemediationForceAnnotation)
+			}
 		}
 
 		// Start remediating the unhealthy control plane machine by deleting it.
@@ -238,6 +250,23 @@ func (r *KubeadmControlPlaneReconciler) reconcileUnhealthyMachines(ctx context.C
 	return ctrl.Result{Requeue: true}, nil
 }
 
+// selectMachineToBeRemediated selects the machine to be remediated among the unhealthy machines according to the
+// remediation order: by default the oldest unhealthy machine, or, with the EtcdUnhealthyFirst order, the oldest unhealthy
+// machine whose etcd member is not healthy, if any; remediating such a machine does not reduce the number of healthy etcd members.
+func selectMachineToBeRemediated(controlPlane *internal.ControlPlane, unhealthyMachines collections.Machines) *clusterv1.Machine {
+	if controlPlane.KCP.Spec.RemediationStrategy != nil &&
+		controlPlane.KCP.Spec.RemediationStrategy.Order == controlplanev1.EtcdUnhealthyFirstRemediationOrderType &&
+		controlPlane.IsEtcdManaged() {
+		etcdUnhealthyMachines := unhealthyMachines.Filter(func(machine *clusterv1.Machine) bool {
+			return !conditions.IsTrue(machine, controlplanev1.MachineEtcdMemberHealthyCondition)
+		})
+		if machine := etcdUnhealthyMachines.Oldest(); machine != nil {
+			return machine
+		}
+	}
+	return unhealthyMachines.Oldest()
+}
+
 // checkRetryLimits checks if KCP is allowed to remediate considering retry limits:
 // - Remediation cannot happen because retryPeriod is not yet expired.
 // - KCP already reached the maximum number of retries for a machine.
@@ -348,14 +377,16 @@ func max(x, y time.Duration) time.Duration {
 //     cluster size after deletion is 6, fault tolerance 2)
 //   - etc.
 //
+// The func also returns the etcd cluster projected after remediation, e.g. to surface why remediation is not possible.
+//
 // NOTE: this func assumes the list of members in sync with the list of machines/nodes, it is required to call reconcileEtcdMembers
 // as well as reconcileControlPlaneConditions before this.
-func (r *KubeadmControlPlaneReconciler) canSafelyRemoveEtcdMember(ctx context.Context, controlPlane *internal.ControlPlane, machineToBeRemediated *clusterv1.Machine) (bool, error) {
+func (r *KubeadmControlPlaneReconciler) canSafelyRemoveEtcdMember(ctx context.Context, controlPlane *internal.ControlPlane, machineToBeRemediated *clusterv1.Machine) (bool, *etcdQuorumProjection, error) {
 	log := ctrl.LoggerFrom(ctx)
 
 	workloadCluster, err := controlPlane.GetWorkloadCluster(ctx)
 	if err != nil {
-		return false, errors.Wrapf(err, "failed to get client for workload cluster %s", controlPlane.Cluster.Name)
+		return false, nil, errors.Wrapf(err, "failed to get client for workload cluster %s", controlPlane.Cluster.Name)
 	}
 
 	// Gets the etcd status
@@ -363,7 +394,7 @@ func (r *KubeadmControlPlaneReconciler) canSafelyRemoveEtcdMember(ctx context.Co
 	// This makes it possible to have a set of etcd members status different from the MHC unhealthy/unhealthy conditions.
 	etcdMembers, err := workloadCluster.EtcdMembers(ctx)
 	if err != nil {
-		return false, errors.Wrapf(err, "failed to get etcdStatus for workload cluster %s", controlPlane.Cluster.Name)
+		return false, nil, errors.Wrapf(err, "failed to get etcdStatus for workload cluster %s", controlPlane.Cluster.Name)
 	}
 
 	currentTotalMembers := len(etcdMembers)
@@ -419,7 +450,12 @@ func (r *KubeadmControlPlaneReconciler) canSafelyRemoveEtcdMember(ctx context.Co
 
 	// See https://etcd.io/docs/v3.3/faq/#what-is-failure-tolerance for fault tolerance formula explanation.
 	targetQuorum := (targetTotalMembers / 2.0) + 1
-	canSafelyRemediate := targetTotalMembers-targetUnhealthyMembers >= targetQuorum
+	projection := &etcdQuorumProjection{
+		totalMembers:     targetTotalMembers,
+		unhealthyMembers: targetUnhealthyMembers,
+		quorum:           targetQuorum,
+	}
+	canSafelyRemediate := projection.margin() >= 0
 
 	log.Info(fmt.Sprintf("etcd cluster projected after remediation of %s", machineToBeRemediated.Name),
 		"healthyMembers", healthyMembers,
@@ -429,7 +465,25 @@ func (r *KubeadmControlPlaneReconciler) canSafelyRemoveEtcdMember(ctx context.Co
 		"targetUnhealthyMembers", targetUnhealthyMembers,
 		"canSafelyRemediate", canSafelyRemediate)
 
-	return canSafelyRemediate, nil
+	return canSafelyRemediate, projection, nil
+}
+
+// etcdQuorumProjection describes the etcd cluster projected after the remediation of a machine.
+type etcdQuorumProjection struct {
+	totalMembers     int
+	unhealthyMembers int
+	quorum           int
+}
+
+// healthyMembers returns the number of healthy etcd members after remediation.
+func (p *etcdQuorumProjection) healthyMembers() int {
+	return p.totalMembers - p.unhealthyMembers
+}
+
+// margin returns the number of additional etcd members that could fail after remediation without etcd losing
+// quorum; a negative value means that etcd loses quorum.
+func (p *etcdQuorumProjection) margin() int {
+	return p.healthyMembers() - p.quorum
 }
 
 // RemediationData struct is used to keep track of information stored in the RemediationInProgressAnnotation in KCP
@@ -33,6 +33,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
 	"sigs.k8s.io/cluster-api/util/collections"
@@ -470,7 +471,7 @@ func TestReconcileUnhealthyMachines(t *testing.T) {
 
 		g.Expect(controlPlane.KCP.Annotations).ToNot(HaveKey(controlplanev1.RemediationInProgressAnnotation))
 
-		assertMachineCondition(ctx, g, m1, clusterv1.MachineOwnerRemediatedCondition, corev1.ConditionFalse, clusterv1.WaitingForRemediationReason, clusterv1.ConditionSeverityWarning, "KCP can't remediate this machine because this could result in etcd loosing quorum")
+		assertMachineCondition(ctx, g, m1, clusterv1.MachineOwnerRemediatedCondition, corev1.ConditionFalse, clusterv1.WaitingForRemediationReason, clusterv1.ConditionSeverityWarning, "KCP can't remediate this machine because this could result in etcd loosing quorum (1 of 2 etcd members healthy after remediation, quorum 2)")
 
 		g.Expect(env.Cleanup(ctx, m1, m2, m3)).To(Succeed())
 	})
@@ -514,7 +515,7 @@ func TestReconcileUnhealthyMachines(t *testing.T) {
 
 		g.Expect(controlPlane.KCP.Annotations).ToNot(HaveKey(controlplanev1.RemediationInProgressAnnotation))
 
-		assertMachineCondition(ctx, g, m1, clusterv1.MachineOwnerRemediatedCondition, corev1.ConditionFalse, clusterv1.WaitingForRemediationReason, clusterv1.ConditionSeverityWarning, "KCP can't remediate this machine because this could result in etcd loosing quorum")
+		assertMachineCondition(ctx, g, m1, clusterv1.MachineOwnerRemediatedCondition, corev1.ConditionFalse, clusterv1.WaitingForRemediationReason, clusterv1.ConditionSeverityWarning, "KCP can't remediate this machine because this could result in etcd loosing quorum (2 of 4 etcd members healthy after remediation, quorum 3)")
 
 		g.Expect(env.Cleanup(ctx, m1, m2, m3, m4, m5)).To(Succeed())
 	})
@@ -762,6 +763,103 @@ func TestReconcileUnhealthyMachines(t *testing.T) {
 		removeFinalizer(g, m1)
 		g.Expect(env.Cleanup(ctx, m1, m2, m3)).To(Succeed())
 	})
+	t.Run("Remediation does not happen if etcd can't tolerate additional failures after remediation with the PreserveQuorumMargin policy", func(t *testing.T) {
+		g := NewWithT(t)
+
+		m1 := createMachine(ctx, g, ns.Name, "m1-mhc-unhealthy-", withMachineHealthCheckFailed())
+		m2 := createMachine(ctx, g, ns.Name, "m2-etcd-healthy-", withHealthyEtcdMember())
+		m3 := createMachine(ctx, g, ns.Name, "m3-etcd-healthy-", withHealthyEtcdMember())
+
+		controlPlane := &internal.ControlPlane{
+			KCP: &controlplanev1.KubeadmControlPlane{
+				Spec: controlplanev1.KubeadmControlPlaneSpec{
+					Replicas: utilpointer.Int32(3),
+					RemediationStrategy: &controlplanev1.RemediationStrategy{
+						EtcdQuorumPolicy: controlplanev1.PreserveQuorumMarginEtcdQuorumPolicyType,
+					},
+				},
+				Status: controlplanev1.KubeadmControlPlaneStatus{
+					Initialized: true,
+				},
+			},
+			Cluster:  &clusterv1.Cluster{},
+			Machines: collections.FromMachines(m1, m2, m3),
+		}
+
+		r := &KubeadmControlPlaneReconciler{
+			Client:   env.GetClient(),
+			recorder: record.NewFakeRecorder(32),
+			managementCluster: &fakeManagementCluster{
+				Workload: fakeWorkloadCluster{
+					EtcdMembersResult: nodes(controlPlane.Machines),
+				},
+			},
+		}
+		controlPlane.InjectTestManagementCluster(r.managementCluster)
+
+		ret, err := r.reconcileUnhealthyMachines(ctx, controlPlane)
+
+		g.Expect(ret.IsZero()).To(BeTrue()) // Remediation skipped
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(controlPlane.KCP.Annotations).ToNot(HaveKey(controlplanev1.RemediationInProgressAnnotation))
+
+		assertMachineCondition(ctx, g, m1, clusterv1.MachineOwnerRemediatedCondition, corev1.ConditionFalse, clusterv1.WaitingForRemediationReason, clusterv1.ConditionSeverityWarning,
+			"KCP can't remediate this machine because etcd could not tolerate any additional member failure (2 of 2 etcd members healthy after remediation, quorum 2) and EtcdQuorumPolicy is PreserveQuorumMargin; set the controlplane.cluster.x-k8s.io/remediation-force annotation on the machine to force remediation")
+
+		g.Expect(env.Cleanup(ctx, m1, m2, m3)).To(Succeed())
+	})
+	t.Run("Remediation deletes unhealthy machine with the PreserveQuorumMargin policy if remediation is forced - 3 CP", func(t *testing.T) {
+		g := NewWithT(t)
+
+		m1 := createMachine(ctx, g, ns.Name, "m1-unhealthy-", withMachineHealthCheckFailed(), withWaitBeforeDeleteFinalizer(), withRemediationForceAnnotation())
+		m2 := createMachine(ctx, g, ns.Name, "m2-healthy-", withHealthyEtcdMember())
+		m3 := createMachine(ctx, g, ns.Name, "m3-healthy-", withHealthyEtcdMember())
+
+		controlPlane := &internal.ControlPlane{
+			KCP: &controlplanev1.KubeadmControlPlane{
+				Spec: controlplanev1.KubeadmControlPlaneSpec{
+					Replicas: utilpointer.Int32(3),
+					Version:  "v1.19.1",
+					RemediationStrategy: &controlplanev1.RemediationStrategy{
+						EtcdQuorumPolicy: controlplanev1.PreserveQuorumMarginEtcdQuorumPolicyType,
+					},
+				},
+				Status: controlplanev1.KubeadmControlPlaneStatus{
+					Initialized: true,
+				},
+			},
+			Cluster:  &clusterv1.Cluster{},
+			Machines: collections.FromMachines(m1, m2, m3),
+		}
+
+		r := &KubeadmControlPlaneReconciler{
+			Client:   env.GetClient(),
+			recorder: record.NewFakeRecorder(32),
+			managementCluster: &fakeManagementCluster{
+				Workload: fakeWorkloadCluster{
+					EtcdMembersResult: nodes(controlPlane.Machines),
+				},
+			},
+		}
+		controlPlane.InjectTestManagementCluster(r.managementCluster)
+
+		ret, err := r.reconcileUnhealthyMachines(ctx, controlPlane)
+
+		g.Expect(ret.IsZero()).To(BeFalse()) // Remediation completed, requeue
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(controlPlane.KCP.Annotations).To(HaveKey(controlplanev1.RemediationInProgressAnnotation))
+
+		assertMachineCondition(ctx, g, m1, clusterv1.MachineOwnerRemediatedCondition, corev1.ConditionFalse, clusterv1.RemediationInProgressReason, clusterv1.ConditionSeverityWarning, "")
+
+		err = env.Get(ctx, client.ObjectKey{Namespace: m1.Namespace, Name: m1.Name}, m1)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(m1.ObjectMeta.DeletionTimestamp.IsZero()).To(BeFalse())
+
+		removeFinalizer(g, m1)
+		g.Expect(env.Cleanup(ctx, m1, m2, m3)).To(Succeed())
+	})
 	t.Run("Remediation deletes unhealthy machine - 4 CP (during 3 CP rolling upgrade)", func(t *testing.T) {
 		g := NewWithT(t)
 
@@ -1257,6 +1355,79 @@ func TestReconcileUnhealthyMachinesSequences(t *testing.T) {
 	})
 }
 
+func TestSelectMachineToBeRemediated(t *testing.T) {
+	now := time.Now()
+	unhealthyMachine := func(name string, creationTimestamp time.Time, etcdMemberOption machineOption) *clusterv1.Machine {
+		m := machine(name)
+		m.CreationTimestamp = metav1.NewTime(creationTimestamp)
+		withMachineHealthCheckFailed()(m)
+		etcdMemberOption(m)
+		return m
+	}
+	oldestEtcdHealthy := unhealthyMachine("oldest-etcd-healthy", now.Add(-2*time.Minute), withHealthyEtcdMember())
+	newestEtcdUnhealthy := unhealthyMachine("newest-etcd-unhealthy", now.Add(-time.Minute), withUnhealthyEtcdMember())
+	newestEtcdHealthy := unhealthyMachine("newest-etcd-healthy", now.Add(-time.Minute), withHealthyEtcdMember())
+
+	tests := []struct {
+		name                 string
+		remediationStrategy  *controlplanev1.RemediationStrategy
+		clusterConfiguration *bootstrapv1.ClusterConfiguration
+		unhealthyMachines    collections.Machines
+		expected             *clusterv1.Machine
+	}{
+		{
+			name:              "without remediation strategy, should select the oldest machine",
+			unhealthyMachines: collections.FromMachines(oldestEtcdHealthy, newestEtcdUnhealthy),
+			expected:          oldestEtcdHealthy,
+		},
+		{
+			name:                "with the Oldest order, should select the oldest machine",
+			remediationStrategy: &controlplanev1.RemediationStrategy{Order: controlplanev1.OldestRemediationOrderType},
+			unhealthyMachines:   collections.FromMachines(oldestEtcdHealthy, newestEtcdUnhealthy),
+			expected:            oldestEtcdHealthy,
+		},
+		{
+			name:                "with the EtcdUnhealthyFirst order, should select the machine with an unhealthy etcd member",
+			remediationStrategy: &controlplanev1.RemediationStrategy{Order: controlplanev1.EtcdUnhealthyFirstRemediationOrderType},
+			unhealthyMachines:   collections.FromMachines(oldestEtcdHealthy, newestEtcdUnhealthy),
+			expected:            newestEtcdUnhealthy,
+		},
+		{
+			name:                "with the EtcdUnhealthyFirst order, should select the oldest machine if all etcd members are healthy",
+			remediationStrategy: &controlplanev1.RemediationStrategy{Order: controlplanev1.EtcdUnhealthyFirstRemediationOrderType},
+			unhealthyMachines:   collections.FromMachines(oldestEtcdHealthy, newestEtcdHealthy),
+			expected:            oldestEtcdHealthy,
+		},
+		{
+			name:                "with the EtcdUnhealthyFirst order, should select the oldest machine if etcd is external",
+			remediationStrategy: &controlplanev1.RemediationStrategy{Order: controlplanev1.EtcdUnhealthyFirstRemediationOrderType},
+			clusterConfiguration: &bootstrapv1.ClusterConfiguration{
+				Etcd: bootstrapv1.Etcd{External: &bootstrapv1.ExternalEtcd{}},
+			},
+			unhealthyMachines: collections.FromMachines(oldestEtcdHealthy, newestEtcdUnhealthy),
+			expected:          oldestEtcdHealthy,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			controlPlane := &internal.ControlPlane{
+				KCP: &controlplanev1.KubeadmControlPlane{
+					Spec: controlplanev1.KubeadmControlPlaneSpec{
+						RemediationStrategy: tt.remediationStrategy,
+						KubeadmConfigSpec: bootstrapv1.KubeadmConfigSpec{
+							ClusterConfiguration: tt.clusterConfiguration,
+						},
+					},
+				},
+				Machines: tt.unhealthyMachines,
+			}
+			g.Expect(selectMachineToBeRemediated(controlPlane, tt.unhealthyMachines).Name).To(Equal(tt.expected.Name))
+		})
+	}
+}
+
 func TestCanSafelyRemoveEtcdMember(t *testing.T) {
 	g := NewWithT(t)
 
@@ -1290,7 +1461,7 @@ func TestCanSafelyRemoveEtcdMember(t *testing.T) {
 		}
 		controlPlane.InjectTestManagementCluster(r.managementCluster)
 
-		ret, err := r.canSafelyRemoveEtcdMember(ctx, controlPlane, m1)
+		ret, _, err := r.canSafelyRemoveEtcdMember(ctx, controlPlane, m1)
 		g.Expect(ret).To(BeFalse())
 		g.Expect(err).ToNot(HaveOccurred())
 
@@ -1322,7 +1493,7 @@ func TestCanSafelyRemoveEtcdMember(t *testing.T) {
 		}
 		controlPlane.InjectTestManagementCluster(r.managementCluster)
 
-		ret, err := r.canSafelyRemoveEtcdMember(ctx, controlPlane, m1)
+		ret, _, err := r.canSafelyRemoveEtcdMember(ctx, controlPlane, m1)
 		g.Expect(ret).To(BeTrue())
 		g.Expect(err).ToNot(HaveOccurred())
 
@@ -1360,7 +1531,7 @@ func TestCanSafelyRemoveEtcdMember(t *testing.T) {
 		}
 		controlPlane.InjectTestManagementCluster(r.managementCluster)
 
-		ret, err := r.canSafelyRemoveEtcdMember(ctx, controlPlane, m1)
+		ret, _, err := r.canSafelyRemoveEtcdMember(ctx, controlPlane, m1)
 		g.Expect(ret).To(BeTrue())
 		g.Expect(err).ToNot(HaveOccurred())
 
@@ -1391,7 +1562,7 @@ func TestCanSafelyRemoveEtcdMember(t *testing.T) {
 		}
 		controlPlane.InjectTestManagementCluster(r.managementCluster)
 
-		ret, err := r.canSafelyRemoveEtcdMember(ctx, controlPlane, m1)
+		ret, _, err := r.canSafelyRemoveEtcdMember(ctx, controlPlane, m1)
 		g.Expect(ret).To(BeFalse())
 		g.Expect(err).ToNot(HaveOccurred())
 
@@ -1423,7 +1594,7 @@ func TestCanSafelyRemoveEtcdMember(t *testing.T) {
 		}
 		controlPlane.InjectTestManagementCluster(r.managementCluster)
 
-		ret, err := r.canSafelyRemoveEtcdMember(ctx, controlPlane, m1)
+		ret, _, err := r.canSafelyRemoveEtcdMember(ctx, controlPlane, m1)
 		g.Expect(ret).To(BeTrue())
 		g.Expect(err).ToNot(HaveOccurred())
 
@@ -1462,7 +1633,7 @@ func TestCanSafelyRemoveEtcdMember(t *testing.T) {
 		}
 		controlPlane.InjectTestManagementCluster(r.managementCluster)
 
-		ret, err := r.canSafelyRemoveEtcdMember(ctx, controlPlane, m1)
+		ret, _, err := r.canSafelyRemoveEtcdMember(ctx, controlPlane, m1)
 		g.Expect(ret).To(BeTrue())
 		g.Expect(err).ToNot(HaveOccurred())
 
@@ -1494,7 +1665,7 @@ func TestCanSafelyRemoveEtcdMember(t *testing.T) {
 		}
 		controlPlane.InjectTestManagementCluster(r.managementCluster)
 
-		ret, err := r.canSafelyRemoveEtcdMember(ctx, controlPlane, m1)
+		ret, _, err := r.canSafelyRemoveEtcdMember(ctx, controlPlane, m1)
 		g.Expect(ret).To(BeFalse())
 		g.Expect(err).ToNot(HaveOccurred())
 
@@ -1528,7 +1699,7 @@ func TestCanSafelyRemoveEtcdMember(t *testing.T) {
 		}
 		controlPlane.InjectTestManagementCluster(r.managementCluster)
 
-		ret, err := r.canSafelyRemoveEtcdMember(ctx, controlPlane, m1)
+		ret, _, err := r.canSafelyRemoveEtcdMember(ctx, controlPlane, m1)
 		g.Expect(ret).To(BeTrue())
 		g.Expect(err).ToNot(HaveOccurred())
 
@@ -1562,7 +1733,7 @@ func TestCanSafelyRemoveEtcdMember(t *testing.T) {
 		}
 		controlPlane.InjectTestManagementCluster(r.managementCluster)
 
-		ret, err := r.canSafelyRemoveEtcdMember(ctx, controlPlane, m1)
+		ret, _, err := r.canSafelyRemoveEtcdMember(ctx, controlPlane, m1)
 		g.Expect(ret).To(BeFalse())
 		g.Expect(err).ToNot(HaveOccurred())
 
@@ -1598,7 +1769,7 @@ func TestCanSafelyRemoveEtcdMember(t *testing.T) {
 		}
 		controlPlane.InjectTestManagementCluster(r.managementCluster)
 
-		ret, err := r.canSafelyRemoveEtcdMember(ctx, controlPlane, m1)
+		ret, _, err := r.canSafelyRemoveEtcdMember(ctx, controlPlane, m1)
 		g.Expect(ret).To(BeTrue())
 		g.Expect(err).ToNot(HaveOccurred())
 
@@ -1634,7 +1805,7 @@ func TestCanSafelyRemoveEtcdMember(t *testing.T) {
 		}
 		controlPlane.InjectTestManagementCluster(r.managementCluster)
 
-		ret, err := r.canSafelyRemoveEtcdMember(ctx, controlPlane, m1)
+		ret, _, err := r.canSafelyRemoveEtcdMember(ctx, controlPlane, m1)
 		g.Expect(ret).To(BeFalse())
 		g.Expect(err).ToNot(HaveOccurred())
 
@@ -1680,6 +1851,15 @@ func withUnhealthyEtcdMember() machineOption {
 	}
 }
 
+func withRemediationForceAnnotation() machineOption {
+	return func(machine *clusterv1.Machine) {
+		if machine.Annotations == nil {
+			machine.Annotations = map[string]string{}
+		}
+		machine.Annotations[controlplanev1.RemediationForceAnnotation] = ""
+	}
+}
+
 func withNodeRef(ref string) machineOption {
 	return func(machine *clusterv1.Machine) {
 		machine.Status.NodeRef = &corev1.ObjectReference{
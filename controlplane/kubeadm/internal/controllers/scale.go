@@ -58,19 +58,49 @@ func (r *KubeadmControlPlaneReconciler) scaleUpControlPlane(ctx context.Context,
 		return result, err
 	}
 
-	// Create the bootstrap configuration
-	bootstrapSpec := controlPlane.JoinControlPlaneConfig()
-	fd := controlPlane.NextFailureDomainForScaleUp()
-	if err := r.cloneConfigsAndGenerateMachine(ctx, controlPlane.Cluster, controlPlane.KCP, bootstrapSpec, fd); err != nil {
-		logger.Error(err, "Failed to create additional control plane Machine")
-		r.recorder.Eventf(controlPlane.KCP, corev1.EventTypeWarning, "FailedScaleUp", "Failed to create additional control plane Machine for cluster % control plane: %v", klog.KObj(controlPlane.Cluster), err)
-		return ctrl.Result{}, err
+	// If required, enable or disable etcd learner mode in the kubeadm-config ConfigMap, which is read by kubeadm join.
+	if learnerMode := controlPlane.EtcdLearnerMode(); learnerMode != nil {
+		if err := r.updateEtcdLearnerMode(ctx, controlPlane, *learnerMode); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Create the Machines; when etcd is external, up to spec.scaleUpStrategy.maxConcurrent Machines are created at the
+	// same time, so their infrastructure is provisioned concurrently, otherwise Machines are created one at a time.
+	// NOTE: The next Machines are created only when all the Machines, including their etcd members, pass the
+	// preflight checks above.
+	for _, fd := range controlPlane.NextFailureDomainsForScaleUp(controlPlane.MachinesToCreateForScaleUp()) {
+		// Create the bootstrap configuration
+		bootstrapSpec := controlPlane.JoinControlPlaneConfig()
+		if err := r.cloneConfigsAndGenerateMachine(ctx, controlPlane.Cluster, controlPlane.KCP, bootstrapSpec, fd); err != nil {
+			logger.Error(err, "Failed to create additional control plane Machine")
+			r.recorder.Eventf(controlPlane.KCP, corev1.EventTypeWarning, "FailedScaleUp", "Failed to create additional control plane Machine for cluster % control plane: %v", klog.KObj(controlPlane.Cluster), err)
+			return ctrl.Result{}, err
+		}
 	}
 
 	// Requeue the control plane, in case there are other operations to perform
 	return ctrl.Result{Requeue: true}, nil
 }
 
+// updateEtcdLearnerMode sets the EtcdLearnerMode feature gate in the kubeadm-config ConfigMap of the workload cluster.
+func (r *KubeadmControlPlaneReconciler) updateEtcdLearnerMode(ctx context.Context, controlPlane *internal.ControlPlane, enabled bool) error {
+	workloadCluster, err := controlPlane.GetWorkloadCluster(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to create client to workload cluster")
+	}
+
+	parsedVersion, err := semver.ParseTolerant(controlPlane.KCP.Spec.Version)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse kubernetes version %q", controlPlane.KCP.Spec.Version)
+	}
+
+	if err := workloadCluster.UpdateFeatureGateInKubeadmConfigMap(ctx, internal.EtcdLearnerModeFeatureGate, enabled, parsedVersion); err != nil {
+		return errors.Wrap(err, "failed to update the EtcdLearnerMode feature gate in the kubeadm config map")
+	}
+	return nil
+}
+
 func (r *KubeadmControlPlaneReconciler) scaleDownControlPlane(
 	ctx context.Context,
 	controlPlane *internal.ControlPlane,
@@ -24,6 +24,7 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/internal/util/rollout"
 	"sigs.k8s.io/cluster-api/util/collections"
 	"sigs.k8s.io/cluster-api/util/conditions"
 )
@@ -46,6 +47,12 @@ func (r *KubeadmControlPlaneReconciler) updateStatus(ctx context.Context, contro
 	controlPlane.KCP.Status.ReadyReplicas = 0
 	controlPlane.KCP.Status.UnavailableReplicas = replicas
 
+	rollout.SetCondition(controlPlane.KCP, "KubeadmControlPlane", controlPlane.Cluster.Name, rollout.Progress{
+		Replicas:         replicas,
+		UpToDateReplicas: controlPlane.KCP.Status.UpdatedReplicas,
+		DesiredReplicas:  desiredReplicas,
+	})
+
 	// Return early if the deletion timestamp is set, because we don't want to try to connect to the workload cluster
 	// and we don't want to report resize condition (because it is set to deleting into reconcile delete).
 	if !controlPlane.KCP.DeletionTimestamp.IsZero() {
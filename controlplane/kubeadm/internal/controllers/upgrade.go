@@ -18,11 +18,16 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/blang/semver"
 	"github.com/pkg/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
 	"sigs.k8s.io/cluster-api/util"
@@ -87,13 +92,15 @@ func (r *KubeadmControlPlaneReconciler) upgradeControlPlane(
 		}
 	}
 
-	if controlPlane.KCP.Spec.KubeadmConfigSpec.ClusterConfiguration != nil && controlPlane.KCP.Spec.KubeadmConfigSpec.ClusterConfiguration.Etcd.Local != nil {
-		meta := controlPlane.KCP.Spec.KubeadmConfigSpec.ClusterConfiguration.Etcd.Local.ImageMeta
+	// Use the ClusterConfiguration with the image overrides applied, so the etcd image repository override is honored.
+	clusterConfiguration := controlPlane.KCP.Spec.KubeadmConfigSpec.ClusterConfigurationWithImageOverrides()
+	if clusterConfiguration != nil && clusterConfiguration.Etcd.Local != nil {
+		meta := clusterConfiguration.Etcd.Local.ImageMeta
 		if err := workloadCluster.UpdateEtcdVersionInKubeadmConfigMap(ctx, meta.ImageRepository, meta.ImageTag, parsedVersion); err != nil {
 			return ctrl.Result{}, errors.Wrap(err, "failed to update the etcd version in the kubeadm config map")
 		}
 
-		extraArgs := controlPlane.KCP.Spec.KubeadmConfigSpec.ClusterConfiguration.Etcd.Local.ExtraArgs
+		extraArgs := clusterConfiguration.Etcd.Local.ExtraArgs
 		if err := workloadCluster.UpdateEtcdExtraArgsInKubeadmConfigMap(ctx, extraArgs, parsedVersion); err != nil {
 			return ctrl.Result{}, errors.Wrap(err, "failed to update the etcd extra args in the kubeadm config map")
 		}
@@ -132,3 +139,68 @@ func (r *KubeadmControlPlaneReconciler) upgradeControlPlane(
 		return ctrl.Result{}, nil
 	}
 }
+
+// minKubernetesVersionKubeletSkewThree is the first Kubernetes version allowing kubelets to be
+// up to three minor versions older than kube-apiserver; before, only two minor versions were allowed.
+//
+// See https://kubernetes.io/releases/version-skew-policy/#kubelet
+var minKubernetesVersionKubeletSkewThree = semver.MustParse("1.28.0")
+
+// validateVersionSkew checks if the version of the KubeadmControlPlane can be rolled out given the version of the
+// control plane actually running in the workload cluster, i.e. the upgrade does not skip a minor version, and the
+// kubelets of the MachineDeployments of the Cluster are not older than allowed by the Kubernetes version skew policy.
+// If the version cannot be rolled out, a message describing the problem is returned.
+func (r *KubeadmControlPlaneReconciler) validateVersionSkew(ctx context.Context, controlPlane *internal.ControlPlane) (string, error) {
+	desiredVersion, err := semver.ParseTolerant(controlPlane.KCP.Spec.Version)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse kubernetes version %q", controlPlane.KCP.Spec.Version)
+	}
+
+	workloadCluster, err := controlPlane.GetWorkloadCluster(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get remote client for workload cluster")
+	}
+	runningVersion, err := workloadCluster.ControlPlaneVersion(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get the version of the control plane of the workload cluster")
+	}
+
+	// Nothing to validate if there are no control plane nodes yet, or if the minor version does not change.
+	if runningVersion == nil || (desiredVersion.Major == runningVersion.Major && desiredVersion.Minor <= runningVersion.Minor) {
+		return "", nil
+	}
+
+	if desiredVersion.Major != runningVersion.Major || desiredVersion.Minor > runningVersion.Minor+1 {
+		return fmt.Sprintf("cannot upgrade the control plane from v%s to %s: the control plane in the workload cluster must be upgraded to v%d.%d first",
+			runningVersion, controlPlane.KCP.Spec.Version, runningVersion.Major, runningVersion.Minor+1), nil
+	}
+
+	maxKubeletSkew := uint64(2)
+	if version.Compare(desiredVersion, minKubernetesVersionKubeletSkewThree, version.WithoutPreReleases()) >= 0 {
+		maxKubeletSkew = 3
+	}
+
+	machineDeployments := &clusterv1.MachineDeploymentList{}
+	if err := r.Client.List(ctx, machineDeployments, client.InNamespace(controlPlane.Cluster.Namespace), client.MatchingLabels{clusterv1.ClusterNameLabel: controlPlane.Cluster.Name}); err != nil {
+		return "", errors.Wrap(err, "failed to list MachineDeployments")
+	}
+	var tooOld []string
+	for _, md := range machineDeployments.Items {
+		if md.Spec.Template.Spec.Version == nil {
+			continue
+		}
+		mdVersion, err := semver.ParseTolerant(*md.Spec.Template.Spec.Version)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to parse kubernetes version %q of MachineDeployment %s", *md.Spec.Template.Spec.Version, md.Name)
+		}
+		if mdVersion.Major != desiredVersion.Major || mdVersion.Minor+maxKubeletSkew < desiredVersion.Minor {
+			tooOld = append(tooOld, fmt.Sprintf("%s (%s)", md.Name, *md.Spec.Template.Spec.Version))
+		}
+	}
+	if len(tooOld) > 0 {
+		sort.Strings(tooOld)
+		return fmt.Sprintf("cannot upgrade the control plane to %s: kubelets can be at most %d minor versions older than kube-apiserver, MachineDeployments %s must be upgraded first",
+			controlPlane.KCP.Spec.Version, maxKubeletSkew, strings.Join(tooOld, ", ")), nil
+	}
+	return "", nil
+}
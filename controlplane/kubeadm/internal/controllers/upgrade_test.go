@@ -22,6 +22,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/blang/semver"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -202,7 +203,7 @@ func TestKubeadmControlPlaneReconciler_RolloutStrategy_ScaleDown(t *testing.T) {
 			ObjectMeta: metav1.ObjectMeta{
 				Namespace: cluster.Namespace,
 				Name:      name,
-				Labels:    internal.ControlPlaneMachineLabelsForCluster(kcp, cluster.Name),
+				Labels:    internal.ControlPlaneMachineLabelsForCluster(kcp, cluster),
 			},
 			Spec: clusterv1.MachineSpec{
 				Bootstrap: clusterv1.Bootstrap{
@@ -266,6 +267,106 @@ func TestKubeadmControlPlaneReconciler_RolloutStrategy_ScaleDown(t *testing.T) {
 	g.Expect(remainingMachines.Items).To(HaveLen(2))
 }
 
+func TestKubeadmControlPlaneReconciler_validateVersionSkew(t *testing.T) {
+	machineDeployment := func(clusterName, name, version string) *clusterv1.MachineDeployment {
+		return &clusterv1.MachineDeployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: metav1.NamespaceDefault,
+				Name:      name,
+				Labels:    map[string]string{clusterv1.ClusterNameLabel: clusterName},
+			},
+			Spec: clusterv1.MachineDeploymentSpec{
+				ClusterName: clusterName,
+				Template: clusterv1.MachineTemplateSpec{
+					Spec: clusterv1.MachineSpec{
+						ClusterName: clusterName,
+						Version:     pointer.String(version),
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name            string
+		desiredVersion  string
+		runningVersion  *semver.Version
+		mdVersions      map[string]string
+		expectedMessage string
+	}{
+		{
+			name:           "allow rollouts without control plane nodes",
+			desiredVersion: "v1.28.0",
+			runningVersion: nil,
+		},
+		{
+			name:           "allow rollouts without minor version changes",
+			desiredVersion: "v1.27.5",
+			runningVersion: &semver.Version{Major: 1, Minor: 27, Patch: 3},
+			mdVersions:     map[string]string{"md1": "v1.24.0"},
+		},
+		{
+			name:           "allow upgrades to the next minor version",
+			desiredVersion: "v1.28.0",
+			runningVersion: &semver.Version{Major: 1, Minor: 27, Patch: 3},
+			mdVersions:     map[string]string{"md1": "v1.25.0", "md2": "v1.27.3"},
+		},
+		{
+			name:            "block upgrades skipping a minor version of the running control plane",
+			desiredVersion:  "v1.28.0",
+			runningVersion:  &semver.Version{Major: 1, Minor: 26, Patch: 3},
+			expectedMessage: "cannot upgrade the control plane from v1.26.3 to v1.28.0: the control plane in the workload cluster must be upgraded to v1.27 first",
+		},
+		{
+			name:            "block upgrades violating the kubelet version skew of MachineDeployments",
+			desiredVersion:  "v1.28.0",
+			runningVersion:  &semver.Version{Major: 1, Minor: 27, Patch: 3},
+			mdVersions:      map[string]string{"md2": "v1.24.0", "md1": "v1.27.3", "md3": "v1.24.5"},
+			expectedMessage: "cannot upgrade the control plane to v1.28.0: kubelets can be at most 3 minor versions older than kube-apiserver, MachineDeployments md2 (v1.24.0), md3 (v1.24.5) must be upgraded first",
+		},
+		{
+			name:            "block upgrades violating the kubelet version skew of MachineDeployments before v1.28",
+			desiredVersion:  "v1.27.0",
+			runningVersion:  &semver.Version{Major: 1, Minor: 26, Patch: 3},
+			mdVersions:      map[string]string{"md1": "v1.24.0"},
+			expectedMessage: "cannot upgrade the control plane to v1.27.0: kubelets can be at most 2 minor versions older than kube-apiserver, MachineDeployments md1 (v1.24.0) must be upgraded first",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster, kcp, _ := createClusterWithControlPlane(metav1.NamespaceDefault)
+			kcp.Spec.Version = tt.desiredVersion
+
+			objs := []client.Object{}
+			for name, version := range tt.mdVersions {
+				objs = append(objs, machineDeployment(cluster.Name, name, version))
+			}
+			fakeClient := newFakeClient(objs...)
+			fmc := &fakeManagementCluster{
+				Workload: fakeWorkloadCluster{
+					ControlPlaneVersionResult: tt.runningVersion,
+				},
+				Reader: fakeClient,
+			}
+			r := &KubeadmControlPlaneReconciler{
+				Client:            fakeClient,
+				managementCluster: fmc,
+			}
+			controlPlane := &internal.ControlPlane{
+				KCP:     kcp,
+				Cluster: cluster,
+			}
+			controlPlane.InjectTestManagementCluster(r.managementCluster)
+
+			message, err := r.validateVersionSkew(ctx, controlPlane)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(message).To(Equal(tt.expectedMessage))
+		})
+	}
+}
+
 type machineOpt func(*clusterv1.Machine)
 
 func machine(name string, opts ...machineOpt) *clusterv1.Machine {
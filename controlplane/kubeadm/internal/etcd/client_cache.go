@@ -0,0 +1,227 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ClientCache caches connections with etcd members, so they are reused across reconciles instead of
+// being established every time.
+//
+// Cached connections are checked with a status call before being reused, and connections failing the check
+// are closed and established again; connections not used for longer than the idle timeout are closed.
+type ClientCache struct {
+	idleTimeout time.Duration
+	dial        func(config ClientConfiguration) (etcd, error)
+
+	lock    sync.Mutex
+	entries map[clientCacheKey]*clientCacheEntry
+}
+
+type clientCacheKey struct {
+	cluster  client.ObjectKey
+	endpoint string
+}
+
+// clientCacheEntry is a connection held by the cache.
+type clientCacheEntry struct {
+	key        clientCacheKey
+	etcdClient etcd
+	// refs is the number of clients using the connection; evicted connections are closed once
+	// they are no longer used.
+	refs     int
+	lastUsed time.Time
+	evicted  bool
+}
+
+// NewClientCache returns a cache closing connections not used for longer than the given idle timeout,
+// which must be greater than zero.
+func NewClientCache(idleTimeout time.Duration) *ClientCache {
+	return &ClientCache{
+		idleTimeout: idleTimeout,
+		dial:        dial,
+		entries:     map[clientCacheKey]*clientCacheEntry{},
+	}
+}
+
+// Get returns a client for the etcd member at the endpoint of the given configuration of a cluster, reusing
+// the cached connection if it is healthy.
+// NOTE: Closing the returned client releases the connection to the cache.
+func (c *ClientCache) Get(ctx context.Context, cluster client.ObjectKey, config ClientConfiguration) (*Client, error) {
+	c.closeIdle()
+
+	key := clientCacheKey{cluster: cluster, endpoint: config.Endpoint}
+	callTimeout := callTimeoutOrDefault(config)
+
+	if entry := c.acquire(key); entry != nil {
+		client, err := newEtcdClient(ctx, &cachedEtcd{etcd: entry.etcdClient, cache: c, entry: entry}, callTimeout)
+		if err == nil {
+			clientCacheRequestsTotal.WithLabelValues(cacheHitResult).Inc()
+			return client, nil
+		}
+		// The connection is not healthy anymore, e.g. because the etcd member or the
+		// proxy to it restarted, so it is replaced by a new one.
+		c.release(entry, true)
+	}
+	clientCacheRequestsTotal.WithLabelValues(cacheMissResult).Inc()
+
+	start := time.Now()
+	etcdClient, err := c.dial(config)
+	if err != nil {
+		dialDuration.WithLabelValues(dialErrorResult).Observe(time.Since(start).Seconds())
+		return nil, err
+	}
+	dialDuration.WithLabelValues(dialSuccessResult).Observe(time.Since(start).Seconds())
+
+	entry := c.store(key, etcdClient)
+	client, err := newEtcdClient(ctx, &cachedEtcd{etcd: etcdClient, cache: c, entry: entry}, callTimeout)
+	if err != nil {
+		closeErr := c.release(entry, true)
+		return nil, errors.Wrap(kerrors.NewAggregate([]error{err, closeErr}), "unable to create etcd client")
+	}
+	return client, nil
+}
+
+// Start closes idle connections every idle timeout until the context is done, then closes all the
+// connections not in use.
+func (c *ClientCache) Start(ctx context.Context) error {
+	ticker := time.NewTicker(c.idleTimeout)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			c.closeAll()
+			return nil
+		case <-ticker.C:
+			c.closeIdle()
+		}
+	}
+}
+
+// acquire returns the cached connection for the key, if any, and marks it as used.
+func (c *ClientCache) acquire(key clientCacheKey) *clientCacheEntry {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	entry.refs++
+	entry.lastUsed = time.Now()
+	return entry
+}
+
+// store caches a new connection for the key and marks it as used; a connection previously cached for the
+// same key, e.g. established concurrently, is evicted.
+func (c *ClientCache) store(key clientCacheKey, etcdClient etcd) *clientCacheEntry {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if previous, ok := c.entries[key]; ok {
+		previous.evicted = true
+		_ = c.closeIfUnused(previous)
+	}
+
+	entry := &clientCacheEntry{key: key, etcdClient: etcdClient, refs: 1, lastUsed: time.Now()}
+	c.entries[key] = entry
+	clientCacheConnections.Inc()
+	return entry
+}
+
+// release marks the connection as no longer used by a client; if evict is true, the connection is removed
+// from the cache and closed once no longer used.
+func (c *ClientCache) release(entry *clientCacheEntry, evict bool) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	entry.refs--
+	entry.lastUsed = time.Now()
+	if evict && !entry.evicted {
+		c.evict(entry, unhealthyEvictionReason)
+	}
+	return c.closeIfUnused(entry)
+}
+
+// closeIdle closes the connections not used for longer than the idle timeout.
+func (c *ClientCache) closeIdle() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, entry := range c.entries {
+		if entry.refs == 0 && time.Since(entry.lastUsed) > c.idleTimeout {
+			c.evict(entry, idleEvictionReason)
+			_ = c.closeIfUnused(entry)
+		}
+	}
+}
+
+// closeAll closes all the connections not in use; connections in use are closed once released.
+func (c *ClientCache) closeAll() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, entry := range c.entries {
+		c.evict(entry, idleEvictionReason)
+		_ = c.closeIfUnused(entry)
+	}
+}
+
+// evict removes the connection from the cache; it must be called with the lock held.
+func (c *ClientCache) evict(entry *clientCacheEntry, reason string) {
+	entry.evicted = true
+	if c.entries[entry.key] == entry {
+		delete(c.entries, entry.key)
+	}
+	clientCacheEvictionsTotal.WithLabelValues(reason).Inc()
+}
+
+// closeIfUnused closes an evicted connection if it is no longer used; it must be called with the lock held.
+func (c *ClientCache) closeIfUnused(entry *clientCacheEntry) error {
+	if !entry.evicted || entry.refs > 0 || entry.etcdClient == nil {
+		return nil
+	}
+	err := entry.etcdClient.Close()
+	entry.etcdClient = nil
+	clientCacheConnections.Dec()
+	return err
+}
+
+// cachedEtcd is a connection held by the cache, which is released to the cache instead of being closed.
+type cachedEtcd struct {
+	etcd
+	cache *ClientCache
+	entry *clientCacheEntry
+	once  sync.Once
+}
+
+// Close releases the connection to the cache.
+func (e *cachedEtcd) Close() error {
+	var err error
+	e.once.Do(func() {
+		err = e.cache.release(e.entry, false)
+	})
+	return err
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	etcdfake "sigs.k8s.io/cluster-api/controlplane/kubeadm/internal/etcd/fake"
+)
+
+func TestClientCache(t *testing.T) {
+	cluster := client.ObjectKey{Namespace: "default", Name: "cluster"}
+	config := ClientConfiguration{Endpoint: "etcd-machine-1"}
+
+	t.Run("reuses connections across clients", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cache, dialed := newTestClientCache()
+
+		first, err := cache.Get(ctx, cluster, config)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(first.LeaderID).To(Equal(uint64(1)))
+		g.Expect(first.Close()).To(Succeed())
+
+		second, err := cache.Get(ctx, cluster, config)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(second.Close()).To(Succeed())
+
+		g.Expect(*dialed).To(HaveLen(1))
+		g.Expect((*dialed)[0].closed).To(BeFalse())
+	})
+
+	t.Run("uses different connections for different clusters and endpoints", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cache, dialed := newTestClientCache()
+
+		for _, key := range []struct {
+			cluster  client.ObjectKey
+			endpoint string
+		}{
+			{cluster: cluster, endpoint: "etcd-machine-1"},
+			{cluster: cluster, endpoint: "etcd-machine-2"},
+			{cluster: client.ObjectKey{Namespace: "default", Name: "other-cluster"}, endpoint: "etcd-machine-1"},
+		} {
+			c, err := cache.Get(ctx, key.cluster, ClientConfiguration{Endpoint: key.endpoint})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(c.Close()).To(Succeed())
+		}
+
+		g.Expect(*dialed).To(HaveLen(3))
+	})
+
+	t.Run("replaces unhealthy connections", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cache, dialed := newTestClientCache()
+
+		first, err := cache.Get(ctx, cluster, config)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(first.Close()).To(Succeed())
+
+		(*dialed)[0].statusErr = errors.New("connection lost")
+
+		second, err := cache.Get(ctx, cluster, config)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(second.Close()).To(Succeed())
+
+		g.Expect(*dialed).To(HaveLen(2))
+		g.Expect((*dialed)[0].closed).To(BeTrue())
+		g.Expect((*dialed)[1].closed).To(BeFalse())
+	})
+
+	t.Run("closes idle connections", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cache, dialed := newTestClientCache()
+
+		c, err := cache.Get(ctx, cluster, config)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(c.Close()).To(Succeed())
+
+		cache.closeIdle()
+		g.Expect((*dialed)[0].closed).To(BeFalse())
+
+		cache.entries[clientCacheKey{cluster: cluster, endpoint: config.Endpoint}].lastUsed = time.Now().Add(-2 * time.Minute)
+		cache.closeIdle()
+		g.Expect((*dialed)[0].closed).To(BeTrue())
+		g.Expect(cache.entries).To(BeEmpty())
+	})
+
+	t.Run("closes evicted connections once no longer used", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cache, dialed := newTestClientCache()
+
+		first, err := cache.Get(ctx, cluster, config)
+		g.Expect(err).ToNot(HaveOccurred())
+		second, err := cache.Get(ctx, cluster, config)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		cache.closeAll()
+		g.Expect(cache.entries).To(BeEmpty())
+
+		g.Expect(first.Close()).To(Succeed())
+		// Closing a client twice must not release the connection twice.
+		g.Expect(first.Close()).To(Succeed())
+		g.Expect((*dialed)[0].closed).To(BeFalse())
+
+		g.Expect(second.Close()).To(Succeed())
+		g.Expect((*dialed)[0].closed).To(BeTrue())
+	})
+
+	t.Run("returns dial errors", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cache := NewClientCache(time.Minute)
+		cache.dial = func(ClientConfiguration) (etcd, error) {
+			return nil, errors.New("dial failed")
+		}
+
+		_, err := cache.Get(ctx, cluster, config)
+		g.Expect(err).To(MatchError("dial failed"))
+		g.Expect(cache.entries).To(BeEmpty())
+	})
+}
+
+// newTestClientCache returns a cache with an idle timeout of one minute, which records the connections it dials.
+func newTestClientCache() (*ClientCache, *[]*fakeCachedEtcdClient) {
+	dialed := &[]*fakeCachedEtcdClient{}
+	cache := NewClientCache(time.Minute)
+	cache.dial = func(config ClientConfiguration) (etcd, error) {
+		c := &fakeCachedEtcdClient{
+			FakeEtcdClient: &etcdfake.FakeEtcdClient{
+				EtcdEndpoints:  []string{config.Endpoint},
+				StatusResponse: &clientv3.StatusResponse{Leader: 1},
+			},
+		}
+		*dialed = append(*dialed, c)
+		return c, nil
+	}
+	return cache, dialed
+}
+
+type fakeCachedEtcdClient struct {
+	*etcdfake.FakeEtcdClient
+	statusErr error
+	closed    bool
+}
+
+func (c *fakeCachedEtcdClient) Status(ctx context.Context, endpoint string) (*clientv3.StatusResponse, error) {
+	if c.statusErr != nil {
+		return nil, c.statusErr
+	}
+	return c.FakeEtcdClient.Status(ctx, endpoint)
+}
+
+func (c *fakeCachedEtcdClient) Close() error {
+	c.closed = true
+	return nil
+}
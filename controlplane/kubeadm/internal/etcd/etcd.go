@@ -19,6 +19,7 @@ package etcd
 import (
 	"context"
 	"crypto/tls"
+	"io"
 	"net"
 	"time"
 
@@ -44,6 +45,7 @@ type etcd interface {
 	MemberRemove(ctx context.Context, id uint64) (*clientv3.MemberRemoveResponse, error)
 	MemberUpdate(ctx context.Context, id uint64, peerURLs []string) (*clientv3.MemberUpdateResponse, error)
 	MoveLeader(ctx context.Context, id uint64) (*clientv3.MoveLeaderResponse, error)
+	Snapshot(ctx context.Context) (io.ReadCloser, error)
 	Status(ctx context.Context, endpoint string) (*clientv3.StatusResponse, error)
 }
 
@@ -53,6 +55,7 @@ type Client struct {
 	Endpoint    string
 	LeaderID    uint64
 	Errors      []string
+	DBSize      int64
 	CallTimeout time.Duration
 }
 
@@ -140,6 +143,21 @@ type ClientConfiguration struct {
 
 // NewClient creates a new etcd client with the given configuration.
 func NewClient(ctx context.Context, config ClientConfiguration) (*Client, error) {
+	etcdClient, err := dial(config)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := newEtcdClient(ctx, etcdClient, callTimeoutOrDefault(config))
+	if err != nil {
+		closeErr := etcdClient.Close()
+		return nil, errors.Wrap(kerrors.NewAggregate([]error{err, closeErr}), "unable to create etcd client")
+	}
+	return client, nil
+}
+
+// dial establishes a connection with the etcd member at the endpoint of the given configuration.
+func dial(config ClientConfiguration) (etcd, error) {
 	dialer, err := proxy.NewDialer(config.Proxy)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to create a dialer for etcd client")
@@ -157,18 +175,14 @@ func NewClient(ctx context.Context, config ClientConfiguration) (*Client, error)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to create etcd client")
 	}
+	return etcdClient, nil
+}
 
-	callTimeout := config.CallTimeout
-	if callTimeout == 0 {
-		callTimeout = DefaultCallTimeout
-	}
-
-	client, err := newEtcdClient(ctx, etcdClient, callTimeout)
-	if err != nil {
-		closeErr := etcdClient.Close()
-		return nil, errors.Wrap(kerrors.NewAggregate([]error{err, closeErr}), "unable to create etcd client")
+func callTimeoutOrDefault(config ClientConfiguration) time.Duration {
+	if config.CallTimeout == 0 {
+		return DefaultCallTimeout
 	}
-	return client, nil
+	return config.CallTimeout
 }
 
 func newEtcdClient(ctx context.Context, etcdClient etcd, callTimeout time.Duration) (*Client, error) {
@@ -190,6 +204,7 @@ func newEtcdClient(ctx context.Context, etcdClient etcd, callTimeout time.Durati
 		EtcdClient:  etcdClient,
 		LeaderID:    status.Leader,
 		Errors:      status.Errors,
+		DBSize:      status.DbSize,
 		CallTimeout: callTimeout,
 	}, nil
 }
@@ -239,6 +254,17 @@ func (c *Client) MoveLeader(ctx context.Context, newLeaderID uint64) error {
 	return errors.Wrapf(err, "failed to move etcd leader: %v", newLeaderID)
 }
 
+// Snapshot streams a snapshot of the etcd database from the member the client is connected to.
+// NOTE: The call timeout is not applied, because streaming a snapshot can take longer depending on
+// the size of the database; the caller is responsible for closing the returned reader.
+func (c *Client) Snapshot(ctx context.Context) (io.ReadCloser, error) {
+	snapshot, err := c.EtcdClient.Snapshot(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to take etcd snapshot")
+	}
+	return snapshot, nil
+}
+
 // RemoveMember removes a given member.
 func (c *Client) RemoveMember(ctx context.Context, id uint64) error {
 	ctx, cancel := context.WithTimeout(ctx, c.CallTimeout)
@@ -18,7 +18,9 @@ limitations under the License.
 package fake
 
 import (
+	"bytes"
 	"context"
+	"io"
 
 	clientv3 "go.etcd.io/etcd/client/v3"
 )
@@ -31,6 +33,7 @@ type FakeEtcdClient struct { //nolint:revive
 	MemberUpdateResponse *clientv3.MemberUpdateResponse
 	MoveLeaderResponse   *clientv3.MoveLeaderResponse
 	StatusResponse       *clientv3.StatusResponse
+	SnapshotResponse     []byte
 	ErrorResponse        error
 	MovedLeader          uint64
 	RemovedMember        uint64
@@ -66,3 +69,9 @@ func (c *FakeEtcdClient) MemberUpdate(_ context.Context, _ uint64, _ []string) (
 func (c *FakeEtcdClient) Status(_ context.Context, _ string) (*clientv3.StatusResponse, error) {
 	return c.StatusResponse, nil
 }
+func (c *FakeEtcdClient) Snapshot(_ context.Context) (io.ReadCloser, error) {
+	if c.ErrorResponse != nil {
+		return nil, c.ErrorResponse
+	}
+	return io.NopCloser(bytes.NewReader(c.SnapshotResponse)), nil
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+func init() {
+	// Register the metrics at the controller-runtime metrics registry.
+	ctrlmetrics.Registry.MustRegister(clientCacheRequestsTotal)
+	ctrlmetrics.Registry.MustRegister(clientCacheEvictionsTotal)
+	ctrlmetrics.Registry.MustRegister(clientCacheConnections)
+	ctrlmetrics.Registry.MustRegister(dialDuration)
+}
+
+// Metrics subsystem and all of the label values used by the etcd client cache.
+const (
+	etcdClientSubsystem = "capi_kcp_etcd_client"
+
+	cacheHitResult  = "hit"
+	cacheMissResult = "miss"
+
+	unhealthyEvictionReason = "unhealthy"
+	idleEvictionReason      = "idle"
+
+	dialSuccessResult = "success"
+	dialErrorResult   = "error"
+)
+
+var (
+	// clientCacheRequestsTotal reports the requests of etcd clients to the cache, partitioned by result.
+	clientCacheRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: etcdClientSubsystem,
+		Name:      "cache_requests_total",
+		Help:      "Number of requests of etcd clients to the cache, partitioned by result (hit or miss).",
+	}, []string{"result"})
+
+	// clientCacheEvictionsTotal reports the connections evicted from the cache, partitioned by reason.
+	clientCacheEvictionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: etcdClientSubsystem,
+		Name:      "cache_evictions_total",
+		Help:      "Number of etcd connections evicted from the cache, partitioned by reason (unhealthy or idle).",
+	}, []string{"reason"})
+
+	// clientCacheConnections reports the connections currently held by the cache.
+	clientCacheConnections = prometheus.NewGauge(prometheus.GaugeOpts{
+		Subsystem: etcdClientSubsystem,
+		Name:      "cache_connections",
+		Help:      "Number of etcd connections currently held by the cache.",
+	})
+
+	// dialDuration reports the time spent establishing connections with etcd members.
+	dialDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Subsystem: etcdClientSubsystem,
+		Name:      "dial_duration_seconds",
+		Help:      "Time spent establishing connections with etcd members in seconds, broken down by result.",
+		Buckets:   []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2, 5, 10, 15, 30},
+	}, []string{"result"})
+)
@@ -26,6 +26,7 @@ import (
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal/etcd"
 	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal/proxy"
@@ -43,7 +44,9 @@ type clientCreator func(ctx context.Context, endpoint string) (*etcd.Client, err
 var errEtcdNodeConnection = errors.New("failed to connect to etcd node")
 
 // NewEtcdClientGenerator returns a new etcdClientGenerator instance.
-func NewEtcdClientGenerator(restConfig *rest.Config, tlsConfig *tls.Config, etcdDialTimeout, etcdCallTimeout time.Duration) *EtcdClientGenerator {
+// If clientCache is not nil, connections with etcd members of the cluster are taken from the cache,
+// so they are reused across reconciles.
+func NewEtcdClientGenerator(restConfig *rest.Config, tlsConfig *tls.Config, etcdDialTimeout, etcdCallTimeout time.Duration, clientCache *etcd.ClientCache, cluster client.ObjectKey) *EtcdClientGenerator {
 	ecg := &EtcdClientGenerator{restConfig: restConfig, tlsConfig: tlsConfig}
 
 	ecg.createClient = func(ctx context.Context, endpoint string) (*etcd.Client, error) {
@@ -53,13 +56,17 @@ func NewEtcdClientGenerator(restConfig *rest.Config, tlsConfig *tls.Config, etcd
 			KubeConfig: ecg.restConfig,
 			Port:       2379,
 		}
-		return etcd.NewClient(ctx, etcd.ClientConfiguration{
+		config := etcd.ClientConfiguration{
 			Endpoint:    endpoint,
 			Proxy:       p,
 			TLSConfig:   tlsConfig,
 			DialTimeout: etcdDialTimeout,
 			CallTimeout: etcdCallTimeout,
-		})
+		}
+		if clientCache != nil {
+			return clientCache.Get(ctx, cluster, config)
+		}
+		return etcd.NewClient(ctx, config)
 	}
 
 	return ecg
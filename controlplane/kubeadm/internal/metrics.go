@@ -0,0 +1,143 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal/etcd"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func init() {
+	// Register the metrics at the controller-runtime metrics registry.
+	ctrlmetrics.Registry.MustRegister(etcdMemberHealthy)
+	ctrlmetrics.Registry.MustRegister(etcdMemberAlarm)
+	ctrlmetrics.Registry.MustRegister(etcdMemberDBSize)
+	ctrlmetrics.Registry.MustRegister(controlPlaneComponentHealthy)
+}
+
+// Metrics subsystem of the health of the workload cluster control plane.
+const kcpSubsystem = "capi_kcp"
+
+var (
+	// etcdMemberHealthy reports if etcd members are healthy according to the MachineEtcdMemberHealthy condition.
+	etcdMemberHealthy = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: kcpSubsystem,
+		Name:      "etcd_member_healthy",
+		Help:      "Whether the etcd member is healthy (1) or not (0), broken down by cluster and member.",
+	}, []string{"namespace", "cluster", "member"})
+
+	// etcdMemberAlarm reports the alarms raised by etcd members.
+	etcdMemberAlarm = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: kcpSubsystem,
+		Name:      "etcd_member_alarm",
+		Help:      "Whether the alarm is raised (1) or not (0) by the etcd member, broken down by cluster, member and alarm type.",
+	}, []string{"namespace", "cluster", "member", "alarm"})
+
+	// etcdMemberDBSize reports the size of the database of etcd members.
+	etcdMemberDBSize = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: kcpSubsystem,
+		Name:      "etcd_member_db_size_bytes",
+		Help:      "Size of the database of the etcd member in bytes, broken down by cluster and member.",
+	}, []string{"namespace", "cluster", "member"})
+
+	// controlPlaneComponentHealthy reports if the control plane components, e.g. the kube-apiserver, are healthy
+	// according to the conditions of the control plane Machines hosting them.
+	controlPlaneComponentHealthy = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: kcpSubsystem,
+		Name:      "control_plane_component_healthy",
+		Help:      "Whether the control plane component Pod is healthy (1) or not (0), broken down by cluster, machine and component.",
+	}, []string{"namespace", "cluster", "machine", "component"})
+)
+
+// staticPodComponents maps the conditions of control plane Machines to the components hosted as static Pods.
+var staticPodComponents = map[clusterv1.ConditionType]string{
+	controlplanev1.MachineAPIServerPodHealthyCondition:         "kube-apiserver",
+	controlplanev1.MachineControllerManagerPodHealthyCondition: "kube-controller-manager",
+	controlplanev1.MachineSchedulerPodHealthyCondition:         "kube-scheduler",
+	controlplanev1.MachineEtcdPodHealthyCondition:              "etcd",
+}
+
+// DeleteHealthMetrics deletes the metrics reporting the health of the control plane of the Cluster,
+// e.g. once the control plane has been deleted.
+func DeleteHealthMetrics(cluster *clusterv1.Cluster) {
+	deleteEtcdMetrics(cluster)
+	controlPlaneComponentHealthy.DeletePartialMatch(clusterLabels(cluster))
+}
+
+func deleteEtcdMetrics(cluster *clusterv1.Cluster) {
+	etcdMemberHealthy.DeletePartialMatch(clusterLabels(cluster))
+	etcdMemberAlarm.DeletePartialMatch(clusterLabels(cluster))
+	etcdMemberDBSize.DeletePartialMatch(clusterLabels(cluster))
+}
+
+func clusterLabels(cluster *clusterv1.Cluster) prometheus.Labels {
+	return prometheus.Labels{"namespace": cluster.Namespace, "cluster": cluster.Name}
+}
+
+// setEtcdMemberStatusMetrics sets the alarm and the database size metrics of an etcd member.
+func setEtcdMemberStatusMetrics(cluster *clusterv1.Cluster, member *etcd.Member, dbSize int64) {
+	raised := map[etcd.AlarmType]bool{}
+	for _, alarm := range member.Alarms {
+		raised[alarm] = true
+	}
+	for alarm, name := range etcd.AlarmTypeName {
+		if alarm == etcd.AlarmOK {
+			continue
+		}
+		etcdMemberAlarm.WithLabelValues(cluster.Namespace, cluster.Name, member.Name, name).Set(boolToFloat(raised[alarm]))
+	}
+	etcdMemberDBSize.WithLabelValues(cluster.Namespace, cluster.Name, member.Name).Set(float64(dbSize))
+}
+
+// setEtcdMemberHealthyMetrics sets the health metric of the etcd members from the MachineEtcdMemberHealthy condition
+// of the control plane Machines; the etcd members are named after the Nodes hosting them.
+func setEtcdMemberHealthyMetrics(controlPlane *ControlPlane) {
+	for _, machine := range controlPlane.Machines {
+		if machine.Status.NodeRef == nil {
+			continue
+		}
+		if c := conditions.Get(machine, controlplanev1.MachineEtcdMemberHealthyCondition); c != nil {
+			etcdMemberHealthy.WithLabelValues(controlPlane.Cluster.Namespace, controlPlane.Cluster.Name, machine.Status.NodeRef.Name).Set(boolToFloat(c.Status == corev1.ConditionTrue))
+		}
+	}
+}
+
+// setControlPlaneComponentHealthyMetrics sets the health metric of the control plane components from the conditions
+// of the control plane Machines hosting them.
+func setControlPlaneComponentHealthyMetrics(controlPlane *ControlPlane, machineConditions []clusterv1.ConditionType) {
+	controlPlaneComponentHealthy.DeletePartialMatch(clusterLabels(controlPlane.Cluster))
+	for _, machine := range controlPlane.Machines {
+		for _, condition := range machineConditions {
+			if c := conditions.Get(machine, condition); c != nil {
+				controlPlaneComponentHealthy.WithLabelValues(controlPlane.Cluster.Namespace, controlPlane.Cluster.Name, machine.Name, staticPodComponents[condition]).Set(boolToFloat(c.Status == corev1.ConditionTrue))
+			}
+		}
+	}
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
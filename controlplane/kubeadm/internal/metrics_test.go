@@ -0,0 +1,81 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal/etcd"
+	"sigs.k8s.io/cluster-api/util/collections"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestHealthMetrics(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: "health-metrics-test", Name: "cluster"}}
+
+	healthyMachine := fakeMachine("m1", withNodeRef("n1"))
+	conditions.MarkTrue(healthyMachine, controlplanev1.MachineEtcdMemberHealthyCondition)
+	conditions.MarkTrue(healthyMachine, controlplanev1.MachineAPIServerPodHealthyCondition)
+	unhealthyMachine := fakeMachine("m2", withNodeRef("n2"))
+	conditions.MarkFalse(unhealthyMachine, controlplanev1.MachineEtcdMemberHealthyCondition, controlplanev1.EtcdMemberUnhealthyReason, clusterv1.ConditionSeverityError, "")
+	conditions.MarkUnknown(unhealthyMachine, controlplanev1.MachineAPIServerPodHealthyCondition, controlplanev1.PodInspectionFailedReason, "")
+	controlPlane := &ControlPlane{
+		Cluster:  cluster,
+		Machines: collections.FromMachines(healthyMachine, unhealthyMachine),
+	}
+
+	setEtcdMemberStatusMetrics(cluster, &etcd.Member{Name: "n1"}, 1024)
+	setEtcdMemberStatusMetrics(cluster, &etcd.Member{Name: "n2", Alarms: []etcd.AlarmType{etcd.AlarmNoSpace}}, 2048)
+	setEtcdMemberHealthyMetrics(controlPlane)
+	setControlPlaneComponentHealthyMetrics(controlPlane, []clusterv1.ConditionType{controlplanev1.MachineAPIServerPodHealthyCondition})
+
+	g.Expect(testutil.ToFloat64(etcdMemberHealthy.WithLabelValues(cluster.Namespace, cluster.Name, "n1"))).To(Equal(float64(1)))
+	g.Expect(testutil.ToFloat64(etcdMemberHealthy.WithLabelValues(cluster.Namespace, cluster.Name, "n2"))).To(Equal(float64(0)))
+	g.Expect(testutil.ToFloat64(etcdMemberAlarm.WithLabelValues(cluster.Namespace, cluster.Name, "n1", "NOSPACE"))).To(Equal(float64(0)))
+	g.Expect(testutil.ToFloat64(etcdMemberAlarm.WithLabelValues(cluster.Namespace, cluster.Name, "n2", "NOSPACE"))).To(Equal(float64(1)))
+	g.Expect(testutil.ToFloat64(etcdMemberAlarm.WithLabelValues(cluster.Namespace, cluster.Name, "n2", "CORRUPT"))).To(Equal(float64(0)))
+	g.Expect(testutil.ToFloat64(etcdMemberDBSize.WithLabelValues(cluster.Namespace, cluster.Name, "n2"))).To(Equal(float64(2048)))
+	g.Expect(testutil.ToFloat64(controlPlaneComponentHealthy.WithLabelValues(cluster.Namespace, cluster.Name, "m1", "kube-apiserver"))).To(Equal(float64(1)))
+	g.Expect(testutil.ToFloat64(controlPlaneComponentHealthy.WithLabelValues(cluster.Namespace, cluster.Name, "m2", "kube-apiserver"))).To(Equal(float64(0)))
+
+	// Metrics of the Cluster are dropped once the control plane is deleted.
+	DeleteHealthMetrics(cluster)
+	g.Expect(etcdMemberHealthy.DeletePartialMatch(clusterLabels(cluster))).To(Equal(0))
+	g.Expect(etcdMemberAlarm.DeletePartialMatch(clusterLabels(cluster))).To(Equal(0))
+	g.Expect(etcdMemberDBSize.DeletePartialMatch(clusterLabels(cluster))).To(Equal(0))
+	g.Expect(controlPlaneComponentHealthy.DeletePartialMatch(clusterLabels(cluster))).To(Equal(0))
+}
+
+func TestSetEtcdMemberHealthyMetricsSkipsMachinesWithoutNode(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: "health-metrics-test-no-node", Name: "cluster"}}
+	machine := fakeMachine("m1")
+	machine.Status.Conditions = clusterv1.Conditions{{Type: controlplanev1.MachineEtcdMemberHealthyCondition, Status: corev1.ConditionTrue}}
+
+	setEtcdMemberHealthyMetrics(&ControlPlane{Cluster: cluster, Machines: collections.FromMachines(machine)})
+	g.Expect(etcdMemberHealthy.DeletePartialMatch(clusterLabels(cluster))).To(Equal(0))
+}
@@ -0,0 +1,39 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	corewebhooks "sigs.k8s.io/cluster-api/internal/webhooks"
+)
+
+// +kubebuilder:webhook:verbs=update,path=/validate-topology-managed-fields,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=controlplane.cluster.x-k8s.io,resources=kubeadmcontrolplanes,versions=v1beta1,name=validation-topology-managed-fields.kubeadmcontrolplane.controlplane.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
+
+// TopologyManagedFieldsValidator rejects changes to the fields of KCP managed by the Cluster topology.
+type TopologyManagedFieldsValidator struct {
+	// TopologyControllerUsername is the username the topology controller of the core Cluster API controller
+	// manager authenticates with.
+	TopologyControllerUsername string
+}
+
+// SetupWebhookWithManager sets up the TopologyManagedFieldsValidator webhook.
+func (v *TopologyManagedFieldsValidator) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return (&corewebhooks.TopologyManagedFields{
+		TopologyControllerUsername: v.TopologyControllerUsername,
+	}).SetupWebhookWithManager(mgr)
+}
@@ -25,6 +25,7 @@ import (
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"fmt"
+	"io"
 	"math/big"
 	"reflect"
 	"time"
@@ -50,6 +51,7 @@ import (
 	"sigs.k8s.io/cluster-api/internal/util/kubeadm"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/certs"
+	"sigs.k8s.io/cluster-api/util/collections"
 	containerutil "sigs.k8s.io/cluster-api/util/container"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/version"
@@ -67,6 +69,9 @@ const (
 	clusterConfigurationKey        = "ClusterConfiguration"
 )
 
+// EtcdLearnerModeFeatureGate is the kubeadm feature gate for joining control plane nodes as etcd learners.
+const EtcdLearnerModeFeatureGate = "EtcdLearnerMode"
+
 var (
 	// Starting from v1.22.0 kubeadm dropped the usage of the ClusterStatus entry from the kubeadm-config ConfigMap
 	// so we're not anymore required to remove API endpoints for control plane nodes after deletion.
@@ -86,6 +91,11 @@ var (
 	// NOTE: The following assumes that kubeadm version equals to Kubernetes version.
 	minVerUnversionedKubeletConfig = semver.MustParse("1.24.0")
 
+	// Starting from v1.27.0 kubeadm supports joining control plane nodes as etcd learners, via the EtcdLearnerMode feature gate.
+	//
+	// NOTE: The following assumes that kubeadm version equals to Kubernetes version.
+	minVerEtcdLearnerMode = semver.MustParse("1.27.0")
+
 	// ErrControlPlaneMinNodes signals that a cluster doesn't meet the minimum required nodes
 	// to remove an etcd member.
 	ErrControlPlaneMinNodes = errors.New("cluster has fewer than 2 control plane nodes; removing an etcd member is not supported")
@@ -100,6 +110,7 @@ type WorkloadCluster interface {
 	UpdateStaticPodConditions(ctx context.Context, controlPlane *ControlPlane)
 	UpdateEtcdConditions(ctx context.Context, controlPlane *ControlPlane)
 	EtcdMembers(ctx context.Context) ([]string, error)
+	ControlPlaneVersion(ctx context.Context) (*semver.Version, error)
 	GetAPIServerCertificateExpiry(ctx context.Context, kubeadmConfig *bootstrapv1.KubeadmConfig, nodeName string) (*time.Time, error)
 
 	// Upgrade related tasks.
@@ -112,6 +123,7 @@ type WorkloadCluster interface {
 	UpdateAPIServerInKubeadmConfigMap(ctx context.Context, apiServer bootstrapv1.APIServer, version semver.Version) error
 	UpdateControllerManagerInKubeadmConfigMap(ctx context.Context, controllerManager bootstrapv1.ControlPlaneComponent, version semver.Version) error
 	UpdateSchedulerInKubeadmConfigMap(ctx context.Context, scheduler bootstrapv1.ControlPlaneComponent, version semver.Version) error
+	UpdateFeatureGateInKubeadmConfigMap(ctx context.Context, featureGate string, enabled bool, version semver.Version) error
 	UpdateKubeletConfigMap(ctx context.Context, version semver.Version) error
 	UpdateKubeProxyImageInfo(ctx context.Context, kcp *controlplanev1.KubeadmControlPlane, version semver.Version) error
 	UpdateCoreDNS(ctx context.Context, kcp *controlplanev1.KubeadmControlPlane, version semver.Version) error
@@ -120,9 +132,16 @@ type WorkloadCluster interface {
 	RemoveNodeFromKubeadmConfigMap(ctx context.Context, nodeName string, version semver.Version) error
 	ForwardEtcdLeadership(ctx context.Context, machine *clusterv1.Machine, leaderCandidate *clusterv1.Machine) error
 	AllowBootstrapTokensToGetNodes(ctx context.Context) error
+	ApproveKubeletServingCertificateSigningRequests(ctx context.Context, machines collections.Machines) ([]string, error)
 
 	// State recovery tasks.
 	ReconcileEtcdMembers(ctx context.Context, nodeNames []string, version semver.Version) ([]string, error)
+
+	// Disaster recovery tasks.
+	SnapshotEtcd(ctx context.Context, upload func(snapshot io.Reader) error) (string, error)
+	StartEtcdRestore(ctx context.Context, input EtcdRestoreInput) error
+	EtcdRestoreStatus(ctx context.Context, name string) (bool, string, error)
+	CleanupEtcdRestore(ctx context.Context, name string) error
 }
 
 // Workload defines operations on workload clusters.
@@ -163,6 +182,27 @@ func (w *Workload) getControlPlaneNodes(ctx context.Context) (*corev1.NodeList,
 	return controlPlaneNodes, nil
 }
 
+// ControlPlaneVersion returns the Kubernetes version actually running in the control plane of the workload cluster,
+// i.e. the oldest kubelet version of the control plane nodes. It returns nil if there are no control plane nodes yet.
+func (w *Workload) ControlPlaneVersion(ctx context.Context) (*semver.Version, error) {
+	nodes, err := w.getControlPlaneNodes(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list control plane nodes")
+	}
+
+	var controlPlaneVersion *semver.Version
+	for _, node := range nodes.Items {
+		kubeletVersion, err := semver.ParseTolerant(node.Status.NodeInfo.KubeletVersion)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse kubelet version %q of Node %s", node.Status.NodeInfo.KubeletVersion, node.Name)
+		}
+		if controlPlaneVersion == nil || kubeletVersion.LT(*controlPlaneVersion) {
+			controlPlaneVersion = &kubeletVersion
+		}
+	}
+	return controlPlaneVersion, nil
+}
+
 func (w *Workload) getConfigMap(ctx context.Context, configMap ctrlclient.ObjectKey) (*corev1.ConfigMap, error) {
 	original := &corev1.ConfigMap{}
 	if err := w.Client.Get(ctx, configMap, original); err != nil {
@@ -289,6 +329,16 @@ func (w *Workload) UpdateSchedulerInKubeadmConfigMap(ctx context.Context, schedu
 	}, version)
 }
 
+// UpdateFeatureGateInKubeadmConfigMap enables or disables a kubeadm feature gate in kubeadm config map.
+func (w *Workload) UpdateFeatureGateInKubeadmConfigMap(ctx context.Context, featureGate string, enabled bool, version semver.Version) error {
+	return w.updateClusterConfiguration(ctx, func(c *bootstrapv1.ClusterConfiguration) {
+		if c.FeatureGates == nil {
+			c.FeatureGates = map[string]bool{}
+		}
+		c.FeatureGates[featureGate] = enabled
+	}, version)
+}
+
 // RemoveMachineFromKubeadmConfigMap removes the entry for the machine from the kubeadm configmap.
 func (w *Workload) RemoveMachineFromKubeadmConfigMap(ctx context.Context, machine *clusterv1.Machine, version semver.Version) error {
 	if machine == nil || machine.Status.NodeRef == nil {
@@ -540,7 +590,8 @@ func staticPodName(component, nodeName string) string {
 // UpdateKubeProxyImageInfo updates kube-proxy image in the kube-proxy DaemonSet.
 func (w *Workload) UpdateKubeProxyImageInfo(ctx context.Context, kcp *controlplanev1.KubeadmControlPlane, version semver.Version) error {
 	// Return early if we've been asked to skip kube-proxy upgrades entirely.
-	if _, ok := kcp.Annotations[controlplanev1.SkipKubeProxyAnnotation]; ok {
+	upgradePolicy := KubeProxyUpgradePolicy(kcp)
+	if _, ok := kcp.Annotations[controlplanev1.SkipKubeProxyAnnotation]; ok || upgradePolicy.Type == controlplanev1.SkipAddonUpgradePolicyType {
 		return nil
 	}
 
@@ -559,7 +610,12 @@ func (w *Workload) UpdateKubeProxyImageInfo(ctx context.Context, kcp *controlpla
 		return nil
 	}
 
-	newImageName, err := containerutil.ModifyImageTag(container.Image, kcp.Spec.Version)
+	// Use the pinned version of kube-proxy if set, the Kubernetes version of the control plane otherwise.
+	imageTag := kcp.Spec.Version
+	if upgradePolicy.Version != "" {
+		imageTag = upgradePolicy.Version
+	}
+	newImageName, err := containerutil.ModifyImageTag(container.Image, imageTag)
 	if err != nil {
 		return err
 	}
@@ -584,6 +640,14 @@ func (w *Workload) UpdateKubeProxyImageInfo(ctx context.Context, kcp *controlpla
 	return nil
 }
 
+// KubeProxyUpgradePolicy returns the upgrade policy for kube-proxy defined in the KubeadmControlPlane.
+func KubeProxyUpgradePolicy(kcp *controlplanev1.KubeadmControlPlane) controlplanev1.AddonUpgradePolicy {
+	if kcp.Spec.UpgradePolicy == nil || kcp.Spec.UpgradePolicy.KubeProxy == nil {
+		return controlplanev1.AddonUpgradePolicy{}
+	}
+	return *kcp.Spec.UpgradePolicy.KubeProxy
+}
+
 func findKubeProxyContainer(ds *appsv1.DaemonSet) *corev1.Container {
 	containers := ds.Spec.Template.Spec.Containers
 	for idx := range containers {
@@ -39,7 +39,11 @@ import (
 // UpdateEtcdConditions is responsible for updating machine conditions reflecting the status of all the etcd members.
 // This operation is best effort, in the sense that in case of problems in retrieving member status, it sets
 // the condition to Unknown state without returning any error.
+// Metrics reporting the health of the etcd members are updated accordingly.
 func (w *Workload) UpdateEtcdConditions(ctx context.Context, controlPlane *ControlPlane) {
+	// Drop the etcd metrics of the Cluster, so metrics of members which are gone are not reported anymore.
+	deleteEtcdMetrics(controlPlane.Cluster)
+
 	if controlPlane.IsEtcdManaged() {
 		w.updateManagedEtcdConditions(ctx, controlPlane)
 		return
@@ -103,7 +107,7 @@ func (w *Workload) updateManagedEtcdConditions(ctx context.Context, controlPlane
 			continue
 		}
 
-		currentMembers, err := w.getCurrentEtcdMembers(ctx, machine, node.Name)
+		currentMembers, dbSize, err := w.getCurrentEtcdMembers(ctx, machine, node.Name)
 		if err != nil {
 			continue
 		}
@@ -125,6 +129,7 @@ func (w *Workload) updateManagedEtcdConditions(ctx context.Context, controlPlane
 			conditions.MarkFalse(machine, controlplanev1.MachineEtcdMemberHealthyCondition, controlplanev1.EtcdMemberUnhealthyReason, clusterv1.ConditionSeverityError, "etcd member reports the cluster is composed by members %s, but the member itself (%s) is not included", etcdutil.MemberNames(currentMembers), node.Name)
 			continue
 		}
+		setEtcdMemberStatusMetrics(controlPlane.Cluster, member, dbSize)
 		if len(member.Alarms) > 0 {
 			alarmList := []string{}
 			for _, alarm := range member.Alarms {
@@ -151,12 +156,21 @@ func (w *Workload) updateManagedEtcdConditions(ctx context.Context, controlPlane
 			continue
 		}
 
+		// Check if the member is a learner still waiting to be promoted; this happens when control plane machines join
+		// etcd using the kubeadm EtcdLearnerMode feature gate.
+		if member.IsLearner {
+			conditions.MarkFalse(machine, controlplanev1.MachineEtcdMemberHealthyCondition, controlplanev1.EtcdMemberLearnerReason, clusterv1.ConditionSeverityInfo, "Etcd member is a learner, waiting to be promoted")
+			continue
+		}
+
 		conditions.MarkTrue(machine, controlplanev1.MachineEtcdMemberHealthyCondition)
 	}
 
 	// Make sure that the list of etcd members and machines is consistent.
 	kcpErrors = compareMachinesAndMembers(controlPlane, members, kcpErrors)
 
+	setEtcdMemberHealthyMetrics(controlPlane)
+
 	// Aggregate components error from machines at KCP level
 	aggregateFromMachinesToKCP(aggregateFromMachinesToKCPInput{
 		controlPlane:      controlPlane,
@@ -169,19 +183,20 @@ func (w *Workload) updateManagedEtcdConditions(ctx context.Context, controlPlane
 	})
 }
 
-func (w *Workload) getCurrentEtcdMembers(ctx context.Context, machine *clusterv1.Machine, nodeName string) ([]*etcd.Member, error) {
+// getCurrentEtcdMembers returns the list of etcd members known by the etcd member on the node, and the size of its database.
+func (w *Workload) getCurrentEtcdMembers(ctx context.Context, machine *clusterv1.Machine, nodeName string) ([]*etcd.Member, int64, error) {
 	// Create the etcd Client for the etcd Pod scheduled on the Node
 	etcdClient, err := w.etcdClientGenerator.forFirstAvailableNode(ctx, []string{nodeName})
 	if err != nil {
 		conditions.MarkUnknown(machine, controlplanev1.MachineEtcdMemberHealthyCondition, controlplanev1.EtcdMemberInspectionFailedReason, "Failed to connect to the etcd pod on the %s node: %s", nodeName, err)
-		return nil, errors.Wrapf(err, "failed to get current etcd members: failed to connect to the etcd pod on the %s node", nodeName)
+		return nil, 0, errors.Wrapf(err, "failed to get current etcd members: failed to connect to the etcd pod on the %s node", nodeName)
 	}
 	defer etcdClient.Close()
 
 	// While creating a new client, forFirstAvailableNode retrieves the status for the endpoint; check if the endpoint has errors.
 	if len(etcdClient.Errors) > 0 {
 		conditions.MarkFalse(machine, controlplanev1.MachineEtcdMemberHealthyCondition, controlplanev1.EtcdMemberUnhealthyReason, clusterv1.ConditionSeverityError, "Etcd member status reports errors: %s", strings.Join(etcdClient.Errors, ", "))
-		return nil, errors.Errorf("failed to get current etcd members: etcd member status reports errors: %s", strings.Join(etcdClient.Errors, ", "))
+		return nil, 0, errors.Errorf("failed to get current etcd members: etcd member status reports errors: %s", strings.Join(etcdClient.Errors, ", "))
 	}
 
 	// Gets the list etcd members known by this member.
@@ -190,10 +205,10 @@ func (w *Workload) getCurrentEtcdMembers(ctx context.Context, machine *clusterv1
 		// NB. We should never be in here, given that we just received answer to the etcd calls included in forFirstAvailableNode;
 		// however, we are considering the calls to Members a signal of etcd not being stable.
 		conditions.MarkFalse(machine, controlplanev1.MachineEtcdMemberHealthyCondition, controlplanev1.EtcdMemberUnhealthyReason, clusterv1.ConditionSeverityError, "Failed get answer from the etcd member on the %s node", nodeName)
-		return nil, errors.Errorf("failed to get current etcd members: failed get answer from the etcd member on the %s node", nodeName)
+		return nil, 0, errors.Errorf("failed to get current etcd members: failed get answer from the etcd member on the %s node", nodeName)
 	}
 
-	return currentMembers, nil
+	return currentMembers, etcdClient.DBSize, nil
 }
 
 func compareMachinesAndMembers(controlPlane *ControlPlane, members []*etcd.Member, kcpErrors []string) []string {
@@ -243,6 +258,7 @@ func compareMachinesAndMembers(controlPlane *ControlPlane, members []*etcd.Membe
 // UpdateStaticPodConditions is responsible for updating machine conditions reflecting the status of all the control plane
 // components running in a static pod generated by kubeadm. This operation is best effort, in the sense that in case
 // of problems in retrieving the pod status, it sets the condition to Unknown state without returning any error.
+// Metrics reporting the health of the control plane components are updated accordingly.
 func (w *Workload) UpdateStaticPodConditions(ctx context.Context, controlPlane *ControlPlane) {
 	allMachinePodConditions := []clusterv1.ConditionType{
 		controlplanev1.MachineAPIServerPodHealthyCondition,
@@ -263,6 +279,7 @@ func (w *Workload) UpdateStaticPodConditions(ctx context.Context, controlPlane *
 			}
 		}
 		conditions.MarkUnknown(controlPlane.KCP, controlplanev1.ControlPlaneComponentsHealthyCondition, controlplanev1.ControlPlaneComponentsInspectionFailedReason, "Failed to list nodes which are hosting control plane components: %v", err)
+		setControlPlaneComponentHealthyMetrics(controlPlane, allMachinePodConditions)
 		return
 	}
 
@@ -337,6 +354,8 @@ func (w *Workload) UpdateStaticPodConditions(ctx context.Context, controlPlane *
 		}
 	}
 
+	setControlPlaneComponentHealthyMetrics(controlPlane, allMachinePodConditions)
+
 	// Aggregate components error from machines at KCP level.
 	aggregateFromMachinesToKCP(aggregateFromMachinesToKCPInput{
 		controlPlane:      controlPlane,
@@ -106,17 +106,27 @@ type coreDNSInfo struct {
 // deployment.
 func (w *Workload) UpdateCoreDNS(ctx context.Context, kcp *controlplanev1.KubeadmControlPlane, version semver.Version) error {
 	// Return early if we've been asked to skip CoreDNS upgrades entirely.
-	if _, ok := kcp.Annotations[controlplanev1.SkipCoreDNSAnnotation]; ok {
+	upgradePolicy := CoreDNSUpgradePolicy(kcp)
+	if _, ok := kcp.Annotations[controlplanev1.SkipCoreDNSAnnotation]; ok || upgradePolicy.Type == controlplanev1.SkipAddonUpgradePolicyType {
 		return nil
 	}
 
+	// Use the ClusterConfiguration with the image overrides applied, so the CoreDNS image repository override is honored.
+	clusterConfig := kcp.Spec.KubeadmConfigSpec.ClusterConfigurationWithImageOverrides()
+
+	// The pinned version of CoreDNS takes precedence over the image tag of the ClusterConfiguration.
+	if upgradePolicy.Version != "" {
+		if clusterConfig == nil {
+			clusterConfig = &bootstrapv1.ClusterConfiguration{}
+		}
+		clusterConfig.DNS.ImageTag = upgradePolicy.Version
+	}
+
 	// Return early if the configuration is nil.
-	if kcp.Spec.KubeadmConfigSpec.ClusterConfiguration == nil {
+	if clusterConfig == nil {
 		return nil
 	}
 
-	clusterConfig := kcp.Spec.KubeadmConfigSpec.ClusterConfiguration
-
 	// Get the CoreDNS info needed for the upgrade.
 	info, err := w.getCoreDNSInfo(ctx, clusterConfig, version)
 	if err != nil {
@@ -158,6 +168,14 @@ func (w *Workload) UpdateCoreDNS(ctx context.Context, kcp *controlplanev1.Kubead
 	return nil
 }
 
+// CoreDNSUpgradePolicy returns the upgrade policy for CoreDNS defined in the KubeadmControlPlane.
+func CoreDNSUpgradePolicy(kcp *controlplanev1.KubeadmControlPlane) controlplanev1.AddonUpgradePolicy {
+	if kcp.Spec.UpgradePolicy == nil || kcp.Spec.UpgradePolicy.CoreDNS == nil {
+		return controlplanev1.AddonUpgradePolicy{}
+	}
+	return *kcp.Spec.UpgradePolicy.CoreDNS
+}
+
 // getCoreDNSInfo returns all necessary coredns based information.
 func (w *Workload) getCoreDNSInfo(ctx context.Context, clusterConfig *bootstrapv1.ClusterConfiguration, version semver.Version) (*coreDNSInfo, error) {
 	// Get the coredns configmap and corefile.
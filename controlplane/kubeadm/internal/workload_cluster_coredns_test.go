@@ -197,6 +197,24 @@ func TestUpdateCoreDNS(t *testing.T) {
 			objs:      []client.Object{badCM},
 			expectErr: false,
 		},
+		{
+			name: "returns early without error if the CoreDNS upgrade policy is Skip",
+			kcp: &controlplanev1.KubeadmControlPlane{
+				Spec: controlplanev1.KubeadmControlPlaneSpec{
+					KubeadmConfigSpec: bootstrapv1.KubeadmConfigSpec{
+						ClusterConfiguration: &bootstrapv1.ClusterConfiguration{
+							DNS: bootstrapv1.DNS{},
+						},
+					},
+					UpgradePolicy: &controlplanev1.UpgradePolicy{
+						CoreDNS: &controlplanev1.AddonUpgradePolicy{Type: controlplanev1.SkipAddonUpgradePolicyType},
+					},
+				},
+			},
+			semver:    semver1191,
+			objs:      []client.Object{badCM},
+			expectErr: false,
+		},
 		{
 			name: "returns early without error if KCP ClusterConfiguration is nil",
 			kcp: &controlplanev1.KubeadmControlPlane{
@@ -322,6 +340,61 @@ func TestUpdateCoreDNS(t *testing.T) {
 			expectUpdates: true,
 			expectImage:   "k8s.gcr.io/some-repo/coredns:1.7.2",
 		},
+		{
+			name: "updates everything successfully using the version pinned in the CoreDNS upgrade policy",
+			kcp: &controlplanev1.KubeadmControlPlane{
+				Spec: controlplanev1.KubeadmControlPlaneSpec{
+					KubeadmConfigSpec: bootstrapv1.KubeadmConfigSpec{
+						ClusterConfiguration: &bootstrapv1.ClusterConfiguration{
+							DNS: bootstrapv1.DNS{
+								ImageMeta: bootstrapv1.ImageMeta{
+									ImageRepository: "k8s.gcr.io/some-repo",
+								},
+							},
+						},
+					},
+					UpgradePolicy: &controlplanev1.UpgradePolicy{
+						CoreDNS: &controlplanev1.AddonUpgradePolicy{Version: "1.7.2"},
+					},
+				},
+			},
+			migrator: &fakeMigrator{
+				migratedCorefile: "updated-core-file",
+			},
+			semver:        semver1191,
+			objs:          []client.Object{depl, cm, kubeadmCM},
+			expectErr:     false,
+			expectUpdates: true,
+			expectImage:   "k8s.gcr.io/some-repo/coredns:1.7.2",
+		},
+		{
+			name: "updates everything successfully using the CoreDNS image repository override",
+			kcp: &controlplanev1.KubeadmControlPlane{
+				Spec: controlplanev1.KubeadmControlPlaneSpec{
+					KubeadmConfigSpec: bootstrapv1.KubeadmConfigSpec{
+						ClusterConfiguration: &bootstrapv1.ClusterConfiguration{
+							DNS: bootstrapv1.DNS{
+								ImageMeta: bootstrapv1.ImageMeta{
+									// provide an newer image to update to
+									ImageTag: "1.7.2",
+								},
+							},
+						},
+						ImageOverrides: &bootstrapv1.ImageOverrides{
+							CoreDNSImageRepository: "k8s.gcr.io/some-mirror",
+						},
+					},
+				},
+			},
+			migrator: &fakeMigrator{
+				migratedCorefile: "updated-core-file",
+			},
+			semver:        semver1191,
+			objs:          []client.Object{depl, cm, kubeadmCM},
+			expectErr:     false,
+			expectUpdates: true,
+			expectImage:   "k8s.gcr.io/some-mirror/coredns:1.7.2",
+		},
 		{
 			name: "updates everything successfully to v1.8.0 with a custom repo should not change the image name",
 			kcp: &controlplanev1.KubeadmControlPlane{
@@ -531,8 +604,9 @@ func TestUpdateCoreDNS(t *testing.T) {
 				g.Eventually(func(g Gomega) error {
 					var expectedKubeadmConfigMap corev1.ConfigMap
 					g.Expect(env.Get(ctx, client.ObjectKey{Name: kubeadmConfigKey, Namespace: metav1.NamespaceSystem}, &expectedKubeadmConfigMap)).To(Succeed())
-					g.Expect(expectedKubeadmConfigMap.Data).To(HaveKeyWithValue("ClusterConfiguration", ContainSubstring(tt.kcp.Spec.KubeadmConfigSpec.ClusterConfiguration.DNS.ImageTag)))
-					g.Expect(expectedKubeadmConfigMap.Data).To(HaveKeyWithValue("ClusterConfiguration", ContainSubstring(tt.kcp.Spec.KubeadmConfigSpec.ClusterConfiguration.DNS.ImageRepository)))
+					clusterConfiguration := tt.kcp.Spec.KubeadmConfigSpec.ClusterConfigurationWithImageOverrides()
+					g.Expect(expectedKubeadmConfigMap.Data).To(HaveKeyWithValue("ClusterConfiguration", ContainSubstring(clusterConfiguration.DNS.ImageTag)))
+					g.Expect(expectedKubeadmConfigMap.Data).To(HaveKeyWithValue("ClusterConfiguration", ContainSubstring(clusterConfiguration.DNS.ImageRepository)))
 					return nil
 				}, "5s").Should(Succeed())
 
@@ -0,0 +1,165 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/collections"
+)
+
+const (
+	// nodeUserPrefix is the prefix of the user names of the nodes.
+	nodeUserPrefix = "system:node:"
+
+	// kubeletServingCertificateApprovedReason is the reason of the Approved condition set on the kubelet serving
+	// certificate signing requests approved by KCP.
+	kubeletServingCertificateApprovedReason = "KubeadmControlPlaneApproved"
+)
+
+// ApproveKubeletServingCertificateSigningRequests approves the pending kubelet serving certificate signing requests
+// of the nodes of the given machines, and returns the names of the approved requests.
+// A request is approved only if it is requested by the node of a machine for that node, and all the DNS names and
+// IP addresses in the request are addresses of the machine; other requests are left pending.
+func (w *Workload) ApproveKubeletServingCertificateSigningRequests(ctx context.Context, machines collections.Machines) ([]string, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	csrList := &certificatesv1.CertificateSigningRequestList{}
+	if err := w.Client.List(ctx, csrList); err != nil {
+		return nil, errors.Wrap(err, "failed to list certificate signing requests")
+	}
+
+	var approved []string
+	var errs []error
+	for i := range csrList.Items {
+		csr := &csrList.Items[i]
+		if csr.Spec.SignerName != certificatesv1.KubeletServingSignerName || isCertificateSigningRequestCompleted(csr) {
+			continue
+		}
+
+		machine, err := validateKubeletServingCertificateSigningRequest(csr, machines)
+		if err != nil {
+			log.V(4).Info(fmt.Sprintf("Not approving kubelet serving certificate signing request %s: %v", csr.Name, err))
+			continue
+		}
+
+		csr.Status.Conditions = append(csr.Status.Conditions, certificatesv1.CertificateSigningRequestCondition{
+			Type:           certificatesv1.CertificateApproved,
+			Status:         corev1.ConditionTrue,
+			Reason:         kubeletServingCertificateApprovedReason,
+			Message:        fmt.Sprintf("Approved by the KubeadmControlPlane controller, the request matches the addresses of Machine %s", machine.Name),
+			LastUpdateTime: metav1.Now(),
+		})
+		if err := w.Client.SubResource("approval").Update(ctx, csr); err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to approve kubelet serving certificate signing request %s", csr.Name))
+			continue
+		}
+		approved = append(approved, csr.Name)
+	}
+	return approved, kerrors.NewAggregate(errs)
+}
+
+// isCertificateSigningRequestCompleted returns true if the certificate signing request is already approved or denied.
+func isCertificateSigningRequestCompleted(csr *certificatesv1.CertificateSigningRequest) bool {
+	for _, c := range csr.Status.Conditions {
+		if c.Type == certificatesv1.CertificateApproved || c.Type == certificatesv1.CertificateDenied {
+			return true
+		}
+	}
+	return false
+}
+
+// validateKubeletServingCertificateSigningRequest validates a kubelet serving certificate signing request against the
+// given machines, and returns the machine of the node that requested it.
+func validateKubeletServingCertificateSigningRequest(csr *certificatesv1.CertificateSigningRequest, machines collections.Machines) (*clusterv1.Machine, error) {
+	if !strings.HasPrefix(csr.Spec.Username, nodeUserPrefix) {
+		return nil, errors.Errorf("requested by %q, which is not a node", csr.Spec.Username)
+	}
+	nodeName := strings.TrimPrefix(csr.Spec.Username, nodeUserPrefix)
+
+	machine := machines.Filter(func(m *clusterv1.Machine) bool {
+		return m.Status.NodeRef != nil && m.Status.NodeRef.Name == nodeName
+	}).Oldest()
+	if machine == nil {
+		return nil, errors.Errorf("no Machine found for node %s", nodeName)
+	}
+
+	for _, usage := range csr.Spec.Usages {
+		switch usage {
+		case certificatesv1.UsageDigitalSignature, certificatesv1.UsageKeyEncipherment, certificatesv1.UsageServerAuth:
+		default:
+			return nil, errors.Errorf("usage %q is not allowed", usage)
+		}
+	}
+
+	block, _ := pem.Decode(csr.Spec.Request)
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return nil, errors.New("request is not a PEM encoded certificate request")
+	}
+	request, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse certificate request")
+	}
+
+	if request.Subject.CommonName != csr.Spec.Username {
+		return nil, errors.Errorf("common name %q doesn't match the requesting node", request.Subject.CommonName)
+	}
+	if len(request.Subject.Organization) != 1 || request.Subject.Organization[0] != NodesGroup {
+		return nil, errors.Errorf("organization %v is not %q", request.Subject.Organization, NodesGroup)
+	}
+	if len(request.EmailAddresses) > 0 || len(request.URIs) > 0 {
+		return nil, errors.New("email addresses and URIs are not allowed")
+	}
+	if len(request.DNSNames) == 0 && len(request.IPAddresses) == 0 {
+		return nil, errors.New("no DNS names or IP addresses requested")
+	}
+
+	dnsNames := sets.Set[string]{}
+	ipAddresses := sets.Set[string]{}
+	for _, address := range machine.Status.Addresses {
+		switch address.Type {
+		case clusterv1.MachineHostName, clusterv1.MachineInternalDNS, clusterv1.MachineExternalDNS:
+			dnsNames.Insert(address.Address)
+		case clusterv1.MachineInternalIP, clusterv1.MachineExternalIP:
+			ipAddresses.Insert(address.Address)
+		}
+	}
+	for _, dnsName := range request.DNSNames {
+		if !dnsNames.Has(dnsName) {
+			return nil, errors.Errorf("DNS name %q is not an address of Machine %s", dnsName, machine.Name)
+		}
+	}
+	for _, ip := range request.IPAddresses {
+		if !ipAddresses.Has(ip.String()) {
+			return nil, errors.Errorf("IP address %q is not an address of Machine %s", ip, machine.Name)
+		}
+	}
+	return machine, nil
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"net"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/collections"
+)
+
+func TestApproveKubeletServingCertificateSigningRequests(t *testing.T) {
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{Name: "machine"},
+		Status: clusterv1.MachineStatus{
+			NodeRef: &corev1.ObjectReference{Name: "node"},
+			Addresses: clusterv1.MachineAddresses{
+				{Type: clusterv1.MachineHostName, Address: "node"},
+				{Type: clusterv1.MachineInternalIP, Address: "10.0.0.1"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		csr          *certificatesv1.CertificateSigningRequest
+		wantApproved bool
+	}{
+		{
+			name:         "approves a request matching the Machine addresses",
+			csr:          kubeletServingCSR(t, "node", []string{"node"}, []string{"10.0.0.1"}),
+			wantApproved: true,
+		},
+		{
+			name: "ignores requests for other signers",
+			csr: func() *certificatesv1.CertificateSigningRequest {
+				csr := kubeletServingCSR(t, "node", []string{"node"}, []string{"10.0.0.1"})
+				csr.Spec.SignerName = certificatesv1.KubeAPIServerClientKubeletSignerName
+				return csr
+			}(),
+		},
+		{
+			name: "ignores denied requests",
+			csr: func() *certificatesv1.CertificateSigningRequest {
+				csr := kubeletServingCSR(t, "node", []string{"node"}, []string{"10.0.0.1"})
+				csr.Status.Conditions = []certificatesv1.CertificateSigningRequestCondition{{Type: certificatesv1.CertificateDenied, Status: corev1.ConditionTrue}}
+				return csr
+			}(),
+		},
+		{
+			name: "ignores requests not requested by a node",
+			csr: func() *certificatesv1.CertificateSigningRequest {
+				csr := kubeletServingCSR(t, "node", []string{"node"}, []string{"10.0.0.1"})
+				csr.Spec.Username = "admin"
+				return csr
+			}(),
+		},
+		{
+			name: "ignores requests of nodes without a Machine",
+			csr:  kubeletServingCSR(t, "other-node", []string{"other-node"}, nil),
+		},
+		{
+			name: "ignores requests with a DNS name which is not a Machine address",
+			csr:  kubeletServingCSR(t, "node", []string{"node", "example.com"}, []string{"10.0.0.1"}),
+		},
+		{
+			name: "ignores requests with an IP address which is not a Machine address",
+			csr:  kubeletServingCSR(t, "node", []string{"node"}, []string{"10.0.0.2"}),
+		},
+		{
+			name: "ignores requests with client auth usage",
+			csr: func() *certificatesv1.CertificateSigningRequest {
+				csr := kubeletServingCSR(t, "node", []string{"node"}, []string{"10.0.0.1"})
+				csr.Spec.Usages = append(csr.Spec.Usages, certificatesv1.UsageClientAuth)
+				return csr
+			}(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			scheme := runtime.NewScheme()
+			g.Expect(certificatesv1.AddToScheme(scheme)).To(Succeed())
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(tt.csr).
+				WithStatusSubresource(&certificatesv1.CertificateSigningRequest{}).
+				Build()
+			w := &Workload{Client: fakeClient}
+
+			approved, err := w.ApproveKubeletServingCertificateSigningRequests(context.Background(), collections.FromMachines(machine))
+			g.Expect(err).ToNot(HaveOccurred())
+
+			csr := &certificatesv1.CertificateSigningRequest{}
+			g.Expect(fakeClient.Get(context.Background(), ctrlclient.ObjectKeyFromObject(tt.csr), csr)).To(Succeed())
+			if tt.wantApproved {
+				g.Expect(approved).To(ConsistOf(tt.csr.Name))
+				g.Expect(csr.Status.Conditions).To(HaveLen(1))
+				g.Expect(csr.Status.Conditions[0].Type).To(Equal(certificatesv1.CertificateApproved))
+				g.Expect(csr.Status.Conditions[0].Status).To(Equal(corev1.ConditionTrue))
+				return
+			}
+			g.Expect(approved).To(BeEmpty())
+			g.Expect(csr.Status.Conditions).To(Equal(tt.csr.Status.Conditions))
+		})
+	}
+}
+
+func kubeletServingCSR(t *testing.T, nodeName string, dnsNames, ipAddresses []string) *certificatesv1.CertificateSigningRequest {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.CertificateRequest{
+		Subject: pkix.Name{
+			CommonName:   nodeUserPrefix + nodeName,
+			Organization: []string{NodesGroup},
+		},
+		DNSNames: dnsNames,
+	}
+	for _, ip := range ipAddresses {
+		template.IPAddresses = append(template.IPAddresses, net.ParseIP(ip))
+	}
+	request, err := x509.CreateCertificateRequest(rand.Reader, template, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &certificatesv1.CertificateSigningRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "csr-" + nodeName},
+		Spec: certificatesv1.CertificateSigningRequestSpec{
+			Request:    pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: request}),
+			SignerName: certificatesv1.KubeletServingSignerName,
+			Usages:     []certificatesv1.KeyUsage{certificatesv1.UsageDigitalSignature, certificatesv1.UsageServerAuth},
+			Username:   nodeUserPrefix + nodeName,
+		},
+	}
+}
@@ -0,0 +1,324 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/utils/pointer"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal/etcd"
+)
+
+const (
+	// defaultEtcdDataDir is the etcd data directory used by kubeadm when the etcd static pod does not define one.
+	defaultEtcdDataDir = "/var/lib/etcd"
+
+	// staticPodManifestsDir is the directory of the static pod manifests on kubeadm control plane nodes.
+	staticPodManifestsDir = "/etc/kubernetes/manifests"
+
+	// etcdRestoreStagingDir is the directory the etcd and kube-apiserver static pod manifests are moved to while
+	// the etcd data directory is swapped; if a restore is interrupted the manifests can be recovered from there.
+	etcdRestoreStagingDir = "/etc/kubernetes/etcd-restore"
+
+	etcdRestoreSnapshotPath = "/snapshot/snapshot.db"
+
+	// etcdRestoreBumpRevision is the number the etcd revision is increased by when restoring a snapshot; it must
+	// be greater than the number of revisions written since the snapshot was taken.
+	etcdRestoreBumpRevision = "1000000000"
+)
+
+// EtcdRestoreInput defines the input for restoring etcd from a snapshot on a control plane node.
+type EtcdRestoreInput struct {
+	// Name is used to name the objects created in the workload cluster to restore etcd.
+	Name string
+
+	// NodeName is the name of the control plane node hosting the etcd member to restore.
+	NodeName string
+
+	// SnapshotURL is the URL the snapshot is downloaded from.
+	SnapshotURL string
+
+	// Authorization is the optional value of the Authorization header used to download the snapshot.
+	Authorization string
+
+	// HelperImage is the image used to download the snapshot and to swap the etcd data directory.
+	HelperImage string
+}
+
+// SnapshotEtcd takes a snapshot of the etcd database from the first available etcd member and passes it
+// to the upload func. It returns the name of the etcd member the snapshot has been taken from.
+func (w *Workload) SnapshotEtcd(ctx context.Context, upload func(snapshot io.Reader) error) (string, error) {
+	nodes, err := w.getControlPlaneNodes(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to list control plane nodes")
+	}
+
+	var errs []error
+	for _, node := range nodes.Items {
+		etcdClient, err := w.etcdClientGenerator.forFirstAvailableNode(ctx, []string{node.Name})
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := snapshotEtcdMember(ctx, etcdClient, upload); err != nil {
+			return "", errors.Wrapf(err, "failed to snapshot etcd member %q", node.Name)
+		}
+		return node.Name, nil
+	}
+	return "", errors.Wrap(kerrors.NewAggregate(errs), "could not establish a connection to any etcd member")
+}
+
+func snapshotEtcdMember(ctx context.Context, etcdClient *etcd.Client, upload func(snapshot io.Reader) error) error {
+	defer etcdClient.Close()
+
+	snapshot, err := etcdClient.Snapshot(ctx)
+	if err != nil {
+		return err
+	}
+	defer snapshot.Close()
+
+	return upload(snapshot)
+}
+
+// StartEtcdRestore creates a Pod restoring etcd from a snapshot on a control plane node.
+// The Pod downloads the snapshot, restores it into a new data directory and then swaps the etcd data directory
+// while the etcd and kube-apiserver static pods are stopped.
+// NOTE: The restored etcd does not contain the objects created by this func, so the restore is completed
+// as soon as the Pod cannot be found anymore; see EtcdRestoreStatus.
+func (w *Workload) StartEtcdRestore(ctx context.Context, input EtcdRestoreInput) error {
+	etcdPod := &corev1.Pod{}
+	etcdPodKey := ctrlclient.ObjectKey{Namespace: metav1.NamespaceSystem, Name: staticPodName("etcd", input.NodeName)}
+	if err := w.Client.Get(ctx, etcdPodKey, etcdPod); err != nil {
+		return errors.Wrapf(err, "failed to get etcd pod %s", etcdPodKey)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      etcdRestoreObjectName(input.Name),
+			Namespace: metav1.NamespaceSystem,
+		},
+		StringData: map[string]string{
+			"url":           input.SnapshotURL,
+			"authorization": input.Authorization,
+		},
+	}
+	if err := w.Client.Create(ctx, secret); err != nil && !apierrors.IsAlreadyExists(err) {
+		return errors.Wrapf(err, "failed to create Secret %s", ctrlclient.ObjectKeyFromObject(secret))
+	}
+
+	pod, err := etcdRestorePod(input, etcdPod)
+	if err != nil {
+		return err
+	}
+	if err := w.Client.Create(ctx, pod); err != nil && !apierrors.IsAlreadyExists(err) {
+		return errors.Wrapf(err, "failed to create Pod %s", ctrlclient.ObjectKeyFromObject(pod))
+	}
+	return nil
+}
+
+// EtcdRestoreStatus returns true if the etcd restore with the given name is completed, or an error message
+// if the restore Pod failed.
+func (w *Workload) EtcdRestoreStatus(ctx context.Context, name string) (bool, string, error) {
+	pod := &corev1.Pod{}
+	podKey := ctrlclient.ObjectKey{Namespace: metav1.NamespaceSystem, Name: etcdRestoreObjectName(name)}
+	if err := w.Client.Get(ctx, podKey, pod); err != nil {
+		if apierrors.IsNotFound(err) {
+			// The restore Pod is not part of the restored etcd, so it is gone once the kube-apiserver is back.
+			return true, "", nil
+		}
+		return false, "", errors.Wrapf(err, "failed to get Pod %s", podKey)
+	}
+
+	if pod.Status.Phase != corev1.PodFailed {
+		return false, "", nil
+	}
+	failures := []string{}
+	for _, status := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+		if status.State.Terminated != nil && status.State.Terminated.ExitCode != 0 {
+			failures = append(failures, fmt.Sprintf("container %s failed with exit code %d: %s", status.Name, status.State.Terminated.ExitCode, strings.TrimSpace(status.State.Terminated.Message)))
+		}
+	}
+	if len(failures) == 0 {
+		failures = append(failures, fmt.Sprintf("Pod %s failed: %s", podKey, pod.Status.Message))
+	}
+	return false, strings.Join(failures, "; "), nil
+}
+
+// CleanupEtcdRestore deletes the objects created in the workload cluster to restore etcd.
+func (w *Workload) CleanupEtcdRestore(ctx context.Context, name string) error {
+	objs := []ctrlclient.Object{
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceSystem, Name: etcdRestoreObjectName(name)}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceSystem, Name: etcdRestoreObjectName(name)}},
+	}
+	var errs []error
+	for _, obj := range objs {
+		if err := w.Client.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, errors.Wrapf(err, "failed to delete %T %s", obj, ctrlclient.ObjectKeyFromObject(obj)))
+		}
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+func etcdRestoreObjectName(name string) string {
+	return fmt.Sprintf("etcd-restore-%s", name)
+}
+
+// etcdRestorePod returns the Pod restoring etcd on the node of the given etcd static pod.
+// The restored member keeps the name and the peer URL of the existing member.
+func etcdRestorePod(input EtcdRestoreInput, etcdPod *corev1.Pod) (*corev1.Pod, error) {
+	var etcdContainer *corev1.Container
+	for i := range etcdPod.Spec.Containers {
+		if etcdPod.Spec.Containers[i].Name == "etcd" {
+			etcdContainer = &etcdPod.Spec.Containers[i]
+		}
+	}
+	if etcdContainer == nil {
+		return nil, errors.Errorf("failed to find etcd container in Pod %s", ctrlclient.ObjectKeyFromObject(etcdPod))
+	}
+
+	args := map[string]string{}
+	for _, arg := range append(etcdContainer.Command, etcdContainer.Args...) {
+		if key, value, ok := strings.Cut(strings.TrimPrefix(arg, "--"), "="); ok {
+			args[key] = value
+		}
+	}
+	memberName, peerURL := args["name"], args["initial-advertise-peer-urls"]
+	if memberName == "" || peerURL == "" {
+		return nil, errors.Errorf("failed to get etcd member name and peer URL from Pod %s", ctrlclient.ObjectKeyFromObject(etcdPod))
+	}
+	dataDir := args["data-dir"]
+	if dataDir == "" {
+		dataDir = defaultEtcdDataDir
+	}
+	restoreDir := fmt.Sprintf("%s.restore-%s", dataDir, input.Name)
+	backupDir := fmt.Sprintf("%s.before-restore-%s", dataDir, input.Name)
+
+	downloadScript := fmt.Sprintf(`set -e
+if [ -n "$AUTHORIZATION" ]; then
+  curl -fsSL -H "Authorization: $AUTHORIZATION" -o %[1]s "$URL"
+else
+  curl -fsSL -o %[1]s "$URL"
+fi`, etcdRestoreSnapshotPath)
+
+	// Stopping the etcd and kube-apiserver static pods before swapping the data directory; the static pods
+	// are started again by the kubelet as soon as the manifests are moved back.
+	// NOTE: The manifests are moved back by an exit trap, so the control plane is not left stopped if the
+	// swap fails or the container is terminated; if the data directory has already been moved away, the
+	// previous data directory is put back as well.
+	swapScript := fmt.Sprintf(`set -e
+mkdir -p %[1]s
+cleanup() {
+  if [ ! -d %[3]s ] && [ -d %[4]s ]; then mv %[4]s %[3]s; fi
+  for manifest in etcd.yaml kube-apiserver.yaml; do
+    if [ -f %[1]s/$manifest ]; then mv %[1]s/$manifest %[2]s/; fi
+  done
+}
+trap cleanup EXIT
+trap 'exit 1' INT TERM
+mv %[2]s/etcd.yaml %[2]s/kube-apiserver.yaml %[1]s/
+sleep 30
+mv %[3]s %[4]s
+mv %[5]s %[3]s`, etcdRestoreStagingDir, staticPodManifestsDir, dataDir, backupDir, restoreDir)
+
+	volumeMounts := []corev1.VolumeMount{
+		{Name: "snapshot", MountPath: filepath.Dir(etcdRestoreSnapshotPath)},
+		{Name: "etcd-data", MountPath: filepath.Dir(dataDir)},
+		{Name: "kubernetes", MountPath: filepath.Dir(staticPodManifestsDir)},
+	}
+	securityContext := &corev1.SecurityContext{RunAsUser: pointer.Int64(0)}
+
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      etcdRestoreObjectName(input.Name),
+			Namespace: metav1.NamespaceSystem,
+		},
+		Spec: corev1.PodSpec{
+			NodeName:          input.NodeName,
+			HostNetwork:       true,
+			RestartPolicy:     corev1.RestartPolicyNever,
+			PriorityClassName: "system-node-critical",
+			Tolerations:       []corev1.Toleration{{Operator: corev1.TolerationOpExists}},
+			InitContainers: []corev1.Container{
+				{
+					Name:    "download",
+					Image:   input.HelperImage,
+					Command: []string{"sh", "-c", downloadScript},
+					Env: []corev1.EnvVar{
+						{Name: "URL", ValueFrom: etcdRestoreSecretKeyRef(input.Name, "url")},
+						{Name: "AUTHORIZATION", ValueFrom: etcdRestoreSecretKeyRef(input.Name, "authorization")},
+					},
+					VolumeMounts:             volumeMounts,
+					SecurityContext:          securityContext,
+					TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+				},
+				{
+					Name:  "restore",
+					Image: etcdContainer.Image,
+					Command: []string{
+						"etcdutl", "snapshot", "restore", etcdRestoreSnapshotPath,
+						"--data-dir", restoreDir,
+						"--name", memberName,
+						"--initial-cluster", fmt.Sprintf("%s=%s", memberName, peerURL),
+						"--initial-advertise-peer-urls", peerURL,
+						// Bumping the revision and marking it as compacted, so watchers of the kube-apiserver
+						// do not miss events or get stale data because the revision moved backwards.
+						"--bump-revision", etcdRestoreBumpRevision,
+						"--mark-compacted",
+					},
+					VolumeMounts:             volumeMounts,
+					SecurityContext:          securityContext,
+					TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+				},
+			},
+			Containers: []corev1.Container{
+				{
+					Name:                     "swap",
+					Image:                    input.HelperImage,
+					Command:                  []string{"sh", "-c", swapScript},
+					VolumeMounts:             volumeMounts,
+					SecurityContext:          securityContext,
+					TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+				},
+			},
+			Volumes: []corev1.Volume{
+				{Name: "snapshot", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+				{Name: "etcd-data", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: filepath.Dir(dataDir)}}},
+				{Name: "kubernetes", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: filepath.Dir(staticPodManifestsDir)}}},
+			},
+		},
+	}, nil
+}
+
+func etcdRestoreSecretKeyRef(name, key string) *corev1.EnvVarSource {
+	return &corev1.EnvVarSource{
+		SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: etcdRestoreObjectName(name)},
+			Key:                  key,
+		},
+	}
+}
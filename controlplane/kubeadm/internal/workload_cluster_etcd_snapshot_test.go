@@ -0,0 +1,242 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal/etcd"
+	fake2 "sigs.k8s.io/cluster-api/controlplane/kubeadm/internal/etcd/fake"
+)
+
+func TestSnapshotEtcd(t *testing.T) {
+	controlPlaneNode := func(name string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{labelNodeRoleControlPlane: ""},
+			},
+		}
+	}
+	cp1 := controlPlaneNode("cp1")
+	cp2 := controlPlaneNode("cp2")
+
+	t.Run("uploads the snapshot taken from the first available member", func(t *testing.T) {
+		g := NewWithT(t)
+
+		w := &Workload{
+			Client: fake.NewClientBuilder().WithObjects(cp1, cp2).Build(),
+			etcdClientGenerator: &fakeEtcdClientGenerator{
+				forNodesClientFunc: func(nodeNames []string) (*etcd.Client, error) {
+					if nodeNames[0] == "cp1" {
+						return nil, errors.New("no client")
+					}
+					return &etcd.Client{EtcdClient: &fake2.FakeEtcdClient{SnapshotResponse: []byte("snapshot")}}, nil
+				},
+			},
+		}
+
+		uploaded := &bytes.Buffer{}
+		member, err := w.SnapshotEtcd(ctx, func(snapshot io.Reader) error {
+			_, err := io.Copy(uploaded, snapshot)
+			return err
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(member).To(Equal("cp2"))
+		g.Expect(uploaded.String()).To(Equal("snapshot"))
+	})
+
+	t.Run("returns an error if the upload fails", func(t *testing.T) {
+		g := NewWithT(t)
+
+		w := &Workload{
+			Client: fake.NewClientBuilder().WithObjects(cp1).Build(),
+			etcdClientGenerator: &fakeEtcdClientGenerator{
+				forNodesClient: &etcd.Client{EtcdClient: &fake2.FakeEtcdClient{SnapshotResponse: []byte("snapshot")}},
+			},
+		}
+
+		_, err := w.SnapshotEtcd(ctx, func(io.Reader) error {
+			return errors.New("upload failed")
+		})
+		g.Expect(err).To(MatchError(ContainSubstring("upload failed")))
+	})
+
+	t.Run("returns an error if no etcd member is available", func(t *testing.T) {
+		g := NewWithT(t)
+
+		w := &Workload{
+			Client:              fake.NewClientBuilder().WithObjects(cp1, cp2).Build(),
+			etcdClientGenerator: &fakeEtcdClientGenerator{forNodesErr: errors.New("no client")},
+		}
+
+		_, err := w.SnapshotEtcd(ctx, func(io.Reader) error { return nil })
+		g.Expect(err).To(HaveOccurred())
+	})
+}
+
+func TestStartEtcdRestore(t *testing.T) {
+	g := NewWithT(t)
+
+	etcdPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "etcd-cp1", Namespace: metav1.NamespaceSystem},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:  "etcd",
+				Image: "registry.k8s.io/etcd:3.5.9-0",
+				Command: []string{
+					"etcd",
+					"--data-dir=/var/lib/etcd",
+					"--initial-advertise-peer-urls=https://10.0.0.1:2380",
+					"--name=cp1",
+				},
+			}},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithObjects(etcdPod).Build()
+	w := &Workload{Client: fakeClient}
+
+	input := EtcdRestoreInput{
+		Name:          "restore",
+		NodeName:      "cp1",
+		SnapshotURL:   "https://backend.example.com/default/kcp/snapshot.db",
+		Authorization: "Bearer token",
+		HelperImage:   "curlimages/curl:8.4.0",
+	}
+	g.Expect(w.StartEtcdRestore(ctx, input)).To(Succeed())
+	// Starting the restore again is a no-op.
+	g.Expect(w.StartEtcdRestore(ctx, input)).To(Succeed())
+
+	secret := &corev1.Secret{}
+	g.Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceSystem, Name: "etcd-restore-restore"}, secret)).To(Succeed())
+	g.Expect(secret.StringData).To(HaveKeyWithValue("url", input.SnapshotURL))
+	g.Expect(secret.StringData).To(HaveKeyWithValue("authorization", input.Authorization))
+
+	pod := &corev1.Pod{}
+	g.Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceSystem, Name: "etcd-restore-restore"}, pod)).To(Succeed())
+	g.Expect(pod.Spec.NodeName).To(Equal("cp1"))
+	g.Expect(pod.Spec.InitContainers).To(HaveLen(2))
+	g.Expect(pod.Spec.InitContainers[0].Image).To(Equal(input.HelperImage))
+	g.Expect(pod.Spec.InitContainers[1].Image).To(Equal("registry.k8s.io/etcd:3.5.9-0"))
+	g.Expect(pod.Spec.InitContainers[1].Command).To(ContainElements(
+		"/var/lib/etcd.restore-restore",
+		"cp1",
+		"cp1=https://10.0.0.1:2380",
+		"--bump-revision",
+		"--mark-compacted",
+	))
+	g.Expect(pod.Spec.Containers).To(HaveLen(1))
+	g.Expect(pod.Spec.Containers[0].Command[2]).To(ContainSubstring("mv /var/lib/etcd.restore-restore /var/lib/etcd"))
+	g.Expect(pod.Spec.Containers[0].Command[2]).To(ContainSubstring("trap cleanup EXIT"))
+}
+
+func TestStartEtcdRestoreFailsWithoutMemberName(t *testing.T) {
+	g := NewWithT(t)
+
+	etcdPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "etcd-cp1", Namespace: metav1.NamespaceSystem},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "etcd", Command: []string{"etcd"}}},
+		},
+	}
+	w := &Workload{Client: fake.NewClientBuilder().WithObjects(etcdPod).Build()}
+
+	g.Expect(w.StartEtcdRestore(ctx, EtcdRestoreInput{Name: "restore", NodeName: "cp1"})).ToNot(Succeed())
+}
+
+func TestEtcdRestoreStatus(t *testing.T) {
+	restorePod := func(phase corev1.PodPhase, statuses ...corev1.ContainerStatus) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "etcd-restore-restore", Namespace: metav1.NamespaceSystem},
+			Status: corev1.PodStatus{
+				Phase:                 phase,
+				InitContainerStatuses: statuses,
+			},
+		}
+	}
+
+	tests := []struct {
+		name                 string
+		objs                 []client.Object
+		expectCompleted      bool
+		expectFailureMessage string
+	}{
+		{
+			name:            "completed if the restore Pod does not exist",
+			expectCompleted: true,
+		},
+		{
+			name:            "in progress if the restore Pod is running",
+			objs:            []client.Object{restorePod(corev1.PodRunning)},
+			expectCompleted: false,
+		},
+		{
+			name:            "in progress if the restore Pod succeeded but the kube-apiserver is still using the old etcd data",
+			objs:            []client.Object{restorePod(corev1.PodSucceeded)},
+			expectCompleted: false,
+		},
+		{
+			name: "failed if a container of the restore Pod failed",
+			objs: []client.Object{restorePod(corev1.PodFailed, corev1.ContainerStatus{
+				Name:  "download",
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 22, Message: "404 Not Found\n"}},
+			})},
+			expectFailureMessage: "container download failed with exit code 22: 404 Not Found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			w := &Workload{Client: fake.NewClientBuilder().WithObjects(tt.objs...).Build()}
+			completed, failureMessage, err := w.EtcdRestoreStatus(ctx, "restore")
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(completed).To(Equal(tt.expectCompleted))
+			g.Expect(failureMessage).To(Equal(tt.expectFailureMessage))
+		})
+	}
+}
+
+func TestCleanupEtcdRestore(t *testing.T) {
+	g := NewWithT(t)
+
+	objs := []client.Object{
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "etcd-restore-restore", Namespace: metav1.NamespaceSystem}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "etcd-restore-restore", Namespace: metav1.NamespaceSystem}},
+	}
+	fakeClient := fake.NewClientBuilder().WithObjects(objs...).Build()
+	w := &Workload{Client: fakeClient}
+
+	g.Expect(w.CleanupEtcdRestore(ctx, "restore")).To(Succeed())
+	// Cleaning up again is a no-op.
+	g.Expect(w.CleanupEtcdRestore(ctx, "restore")).To(Succeed())
+
+	for _, obj := range objs {
+		err := fakeClient.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+		g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	}
+}
@@ -110,6 +110,67 @@ func TestGetControlPlaneNodes(t *testing.T) {
 	}
 }
 
+func TestControlPlaneVersion(t *testing.T) {
+	node := func(name, kubeletVersion string, labels map[string]string) client.Object {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: labels,
+			},
+			Status: corev1.NodeStatus{
+				NodeInfo: corev1.NodeSystemInfo{KubeletVersion: kubeletVersion},
+			},
+		}
+	}
+	controlPlaneLabels := map[string]string{labelNodeRoleControlPlane: ""}
+
+	tests := []struct {
+		name            string
+		nodes           []client.Object
+		expectedVersion *semver.Version
+		expectErr       bool
+	}{
+		{
+			name:            "Return nil without control plane nodes",
+			nodes:           []client.Object{node("worker-node", "v1.27.3", nil)},
+			expectedVersion: nil,
+		},
+		{
+			name: "Return the oldest kubelet version of the control plane nodes",
+			nodes: []client.Object{
+				node("control-plane-node-1", "v1.28.1", controlPlaneLabels),
+				node("control-plane-node-2", "v1.27.3", controlPlaneLabels),
+				node("control-plane-node-3", "v1.28.1", controlPlaneLabels),
+				node("worker-node", "v1.26.0", nil),
+			},
+			expectedVersion: &semver.Version{Major: 1, Minor: 27, Patch: 3},
+		},
+		{
+			name:      "Fail with an invalid kubelet version",
+			nodes:     []client.Object{node("control-plane-node-1", "invalid", controlPlaneLabels)},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			fakeClient := fake.NewClientBuilder().WithObjects(tt.nodes...).Build()
+
+			w := &Workload{
+				Client: fakeClient,
+			}
+			controlPlaneVersion, err := w.ControlPlaneVersion(ctx)
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(controlPlaneVersion).To(Equal(tt.expectedVersion))
+		})
+	}
+}
+
 func TestUpdateKubeProxyImageInfo(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -216,6 +277,32 @@ func TestUpdateKubeProxyImageInfo(t *testing.T) {
 					Version: "v1.16.3",
 				}},
 		},
+		{
+			name:        "does not update image when the kube-proxy upgrade policy is Skip",
+			ds:          newKubeProxyDSWithImage(""), // Using the same image name that would otherwise lead to an error
+			expectErr:   false,
+			expectImage: "",
+			KCP: &controlplanev1.KubeadmControlPlane{
+				Spec: controlplanev1.KubeadmControlPlaneSpec{
+					Version: "v1.16.3",
+					UpgradePolicy: &controlplanev1.UpgradePolicy{
+						KubeProxy: &controlplanev1.AddonUpgradePolicy{Type: controlplanev1.SkipAddonUpgradePolicyType},
+					},
+				}},
+		},
+		{
+			name:        "updates image to the version pinned in the kube-proxy upgrade policy",
+			ds:          newKubeProxyDS(),
+			expectErr:   false,
+			expectImage: "k8s.gcr.io/kube-proxy:v1.16.3",
+			KCP: &controlplanev1.KubeadmControlPlane{
+				Spec: controlplanev1.KubeadmControlPlaneSpec{
+					Version: "v1.16.4",
+					UpgradePolicy: &controlplanev1.UpgradePolicy{
+						KubeProxy: &controlplanev1.AddonUpgradePolicy{Version: "v1.16.3"},
+					},
+				}},
+		},
 	}
 
 	for _, tt := range tests {
@@ -1220,6 +1307,89 @@ func TestUpdateSchedulerInKubeadmConfigMap(t *testing.T) {
 	}
 }
 
+func TestUpdateFeatureGateInKubeadmConfigMap(t *testing.T) {
+	tests := []struct {
+		name                     string
+		clusterConfigurationData string
+		enabled                  bool
+		wantClusterConfiguration string
+	}{
+		{
+			name: "it should enable the feature gate",
+			clusterConfigurationData: yaml.Raw(`
+				apiVersion: kubeadm.k8s.io/v1beta3
+				kind: ClusterConfiguration
+				featureGates:
+				  PublicKeysECDSA: true
+				`),
+			enabled: true,
+			wantClusterConfiguration: yaml.Raw(`
+				apiServer: {}
+				apiVersion: kubeadm.k8s.io/v1beta3
+				controllerManager: {}
+				dns: {}
+				etcd: {}
+				featureGates:
+				  EtcdLearnerMode: true
+				  PublicKeysECDSA: true
+				kind: ClusterConfiguration
+				networking: {}
+				scheduler: {}
+				`),
+		},
+		{
+			name: "it should disable the feature gate",
+			clusterConfigurationData: yaml.Raw(`
+				apiVersion: kubeadm.k8s.io/v1beta3
+				kind: ClusterConfiguration
+				featureGates:
+				  EtcdLearnerMode: true
+				`),
+			enabled: false,
+			wantClusterConfiguration: yaml.Raw(`
+				apiServer: {}
+				apiVersion: kubeadm.k8s.io/v1beta3
+				controllerManager: {}
+				dns: {}
+				etcd: {}
+				featureGates:
+				  EtcdLearnerMode: false
+				kind: ClusterConfiguration
+				networking: {}
+				scheduler: {}
+				`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			fakeClient := fake.NewClientBuilder().WithObjects(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      kubeadmConfigKey,
+					Namespace: metav1.NamespaceSystem,
+				},
+				Data: map[string]string{
+					clusterConfigurationKey: tt.clusterConfigurationData,
+				},
+			}).Build()
+
+			w := &Workload{
+				Client: fakeClient,
+			}
+			err := w.UpdateFeatureGateInKubeadmConfigMap(ctx, "EtcdLearnerMode", tt.enabled, semver.MustParse("1.27.1"))
+			g.Expect(err).ToNot(HaveOccurred())
+
+			var actualConfig corev1.ConfigMap
+			g.Expect(w.Client.Get(
+				ctx,
+				client.ObjectKey{Name: kubeadmConfigKey, Namespace: metav1.NamespaceSystem},
+				&actualConfig,
+			)).To(Succeed())
+			g.Expect(actualConfig.Data[clusterConfigurationKey]).Should(Equal(tt.wantClusterConfiguration), cmp.Diff(tt.wantClusterConfiguration, actualConfig.Data[clusterConfigurationKey]))
+		})
+	}
+}
+
 func TestClusterStatus(t *testing.T) {
 	node1 := &corev1.Node{
 		ObjectMeta: metav1.ObjectMeta{
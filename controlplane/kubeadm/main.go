@@ -99,6 +99,9 @@ var (
 	healthAddr                     string
 	etcdDialTimeout                time.Duration
 	etcdCallTimeout                time.Duration
+	etcdClientIdleTimeout          time.Duration
+	approveKubeletServingCerts     bool
+	topologyControllerUsername     string
 	tlsOptions                     = flags.TLSOptions{}
 	logOptions                     = logs.NewOptions()
 )
@@ -161,6 +164,15 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.DurationVar(&etcdCallTimeout, "etcd-call-timeout-duration", etcd.DefaultCallTimeout,
 		"Duration that the etcd client waits at most for read and write operations to etcd.")
 
+	fs.DurationVar(&etcdClientIdleTimeout, "etcd-client-idle-timeout-duration", 5*time.Minute,
+		"Duration after which unused connections with etcd, which are otherwise reused across reconciles, are closed. If set to 0, connections with etcd are established at every reconcile.")
+
+	fs.BoolVar(&approveKubeletServingCerts, "approve-kubelet-serving-certificates", false,
+		"Approve the kubelet serving certificate signing requests of the nodes of the Machines of the workload clusters, if the requested addresses match the Machine addresses. Useful when kubelets are configured with rotate-server-certificates and no other approver is deployed.")
+
+	fs.StringVar(&topologyControllerUsername, "topology-controller-username", kcpwebhooks.DefaultTopologyControllerUsername,
+		"Username of the core Cluster API controller manager, which runs the topology controller; only this user can change the fields of KubeadmControlPlanes managed by the Cluster topology. Must be set when the core Cluster API controller manager runs with a service account other than the default one.")
+
 	flags.AddTLSOptions(fs, &tlsOptions)
 
 	feature.MutableGates.AddFlag(fs)
@@ -327,10 +339,37 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 		WatchFilterValue:    watchFilterValue,
 		EtcdDialTimeout:     etcdDialTimeout,
 		EtcdCallTimeout:     etcdCallTimeout,
+
+		EtcdClientIdleTimeout:             etcdClientIdleTimeout,
+		ApproveKubeletServingCertificates: approveKubeletServingCerts,
 	}).SetupWithManager(ctx, mgr, concurrency(kubeadmControlPlaneConcurrency)); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "KubeadmControlPlane")
 		os.Exit(1)
 	}
+
+	if feature.Gates.Enabled(feature.EtcdSnapshotRestore) {
+		if err := (&kubeadmcontrolplanecontrollers.EtcdSnapshotReconciler{
+			Client:           mgr.GetClient(),
+			Tracker:          tracker,
+			WatchFilterValue: watchFilterValue,
+			EtcdDialTimeout:  etcdDialTimeout,
+			EtcdCallTimeout:  etcdCallTimeout,
+		}).SetupWithManager(ctx, mgr, concurrency(kubeadmControlPlaneConcurrency)); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "EtcdSnapshot")
+			os.Exit(1)
+		}
+
+		if err := (&kubeadmcontrolplanecontrollers.EtcdRestoreReconciler{
+			Client:           mgr.GetClient(),
+			Tracker:          tracker,
+			WatchFilterValue: watchFilterValue,
+			EtcdDialTimeout:  etcdDialTimeout,
+			EtcdCallTimeout:  etcdCallTimeout,
+		}).SetupWithManager(ctx, mgr, concurrency(kubeadmControlPlaneConcurrency)); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "EtcdRestore")
+			os.Exit(1)
+		}
+	}
 }
 
 func setupWebhooks(mgr ctrl.Manager) {
@@ -346,10 +385,26 @@ func setupWebhooks(mgr ctrl.Manager) {
 		os.Exit(1)
 	}
 
+	setupLog.Info("Using topology controller username", "username", topologyControllerUsername)
+	if err := (&kcpwebhooks.TopologyManagedFieldsValidator{TopologyControllerUsername: topologyControllerUsername}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "KubeadmControlPlane topology managed fields")
+		os.Exit(1)
+	}
+
 	if err := (&controlplanev1.KubeadmControlPlaneTemplate{}).SetupWebhookWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "KubeadmControlPlaneTemplate")
 		os.Exit(1)
 	}
+
+	if err := (&controlplanev1.EtcdSnapshot{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "EtcdSnapshot")
+		os.Exit(1)
+	}
+
+	if err := (&controlplanev1.EtcdRestore{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "EtcdRestore")
+		os.Exit(1)
+	}
 }
 
 func concurrency(c int) controller.Options {
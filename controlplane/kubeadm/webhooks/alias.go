@@ -21,8 +21,27 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal/webhooks"
+	corewebhooks "sigs.k8s.io/cluster-api/internal/webhooks"
 )
 
+// DefaultTopologyControllerUsername is the username of the service account of the core Cluster API controller
+// manager, which runs the topology controller, in the default installation.
+const DefaultTopologyControllerUsername = corewebhooks.DefaultTopologyControllerUsername
+
+// TopologyManagedFieldsValidator rejects changes to the fields of KCP managed by the Cluster topology.
+type TopologyManagedFieldsValidator struct {
+	// TopologyControllerUsername is the username the topology controller of the core Cluster API controller
+	// manager authenticates with.
+	TopologyControllerUsername string
+}
+
+// SetupWebhookWithManager sets up the TopologyManagedFieldsValidator webhook.
+func (v *TopologyManagedFieldsValidator) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return (&webhooks.TopologyManagedFieldsValidator{
+		TopologyControllerUsername: v.TopologyControllerUsername,
+	}).SetupWebhookWithManager(mgr)
+}
+
 // ScaleValidator validates KCP for replicas.
 type ScaleValidator struct {
 	Client client.Reader
@@ -49,6 +49,21 @@ type ClusterResourceSetSpec struct {
 	// +kubebuilder:validation:Enum=ApplyOnce;Reconcile
 	// +optional
 	Strategy string `json:"strategy,omitempty"`
+
+	// TemplateEngine is the engine used to render the data of the referenced resources for each
+	// matching Cluster before applying it. When not set, the data is applied as is.
+	// With GoTemplate, values can reference the Cluster name, namespace, network and topology
+	// variables, e.g. `{{ .cluster.name }}`, `{{ index .cluster.network.pods 0 }}` or `{{ .variables.myVar }}`.
+	// +kubebuilder:validation:Enum=GoTemplate
+	// +optional
+	TemplateEngine string `json:"templateEngine,omitempty"`
+
+	// DeletionPolicy defines what happens to the resources applied to a Cluster when the ClusterResourceSet
+	// is deleted or the Cluster stops matching the ClusterSelector. Defaults to Orphan, which leaves the
+	// resources in the Cluster; Delete removes them from the Cluster.
+	// +kubebuilder:validation:Enum=Orphan;Delete
+	// +optional
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
 }
 
 // ANCHOR_END: ClusterResourceSetSpec
@@ -85,6 +100,27 @@ const (
 	ClusterResourceSetStrategyReconcile ClusterResourceSetStrategy = "Reconcile"
 )
 
+// ClusterResourceSetTemplateEngine is a string representation of a ClusterResourceSet TemplateEngine.
+type ClusterResourceSetTemplateEngine string
+
+const (
+	// ClusterResourceSetTemplateEngineGoTemplate renders the data of the referenced resources as Go templates
+	// before applying them to a Cluster.
+	ClusterResourceSetTemplateEngineGoTemplate ClusterResourceSetTemplateEngine = "GoTemplate"
+)
+
+// ClusterResourceSetDeletionPolicy is a string representation of a ClusterResourceSet DeletionPolicy.
+type ClusterResourceSetDeletionPolicy string
+
+const (
+	// ClusterResourceSetDeletionPolicyOrphan is the default deletion policy; resources applied to a Cluster
+	// are left untouched when they are no longer managed by the ClusterResourceSet.
+	ClusterResourceSetDeletionPolicyOrphan ClusterResourceSetDeletionPolicy = "Orphan"
+	// ClusterResourceSetDeletionPolicyDelete removes the resources applied to a Cluster when they are no longer
+	// managed by the ClusterResourceSet.
+	ClusterResourceSetDeletionPolicyDelete ClusterResourceSetDeletionPolicy = "Delete"
+)
+
 // SetTypedStrategy sets the Strategy field to the string representation of ClusterResourceSetStrategy.
 func (c *ClusterResourceSetSpec) SetTypedStrategy(p ClusterResourceSetStrategy) {
 	c.Strategy = string(p)
@@ -49,6 +49,10 @@ func (m *ClusterResourceSet) Default() {
 	if m.Spec.Strategy == "" {
 		m.Spec.Strategy = string(ClusterResourceSetStrategyApplyOnce)
 	}
+	// ClusterResourceSet DeletionPolicy defaults to Orphan.
+	if m.Spec.DeletionPolicy == "" {
+		m.Spec.DeletionPolicy = string(ClusterResourceSetDeletionPolicyOrphan)
+	}
 }
 
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
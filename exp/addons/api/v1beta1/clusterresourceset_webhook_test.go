@@ -36,6 +36,7 @@ func TestClusterResourcesetDefault(t *testing.T) {
 	clusterResourceSet.Default()
 
 	g.Expect(clusterResourceSet.Spec.Strategy).To(Equal(string(ClusterResourceSetStrategyApplyOnce)))
+	g.Expect(clusterResourceSet.Spec.DeletionPolicy).To(Equal(string(ClusterResourceSetDeletionPolicyOrphan)))
 }
 
 func TestClusterResourceSetLabelSelectorAsSelectorValidation(t *testing.T) {
@@ -40,3 +40,16 @@ const (
 	// WrongSecretTypeReason (Severity=Warning) documents at least one of the Secret's type in the resource list is not supported.
 	WrongSecretTypeReason = "WrongSecretType"
 )
+
+const (
+	// ResourcesDeletedCondition documents the deletion of the resources applied to the clusters which are no longer
+	// managed by the ClusterResourceSet, when the deletion policy is Delete.
+	ResourcesDeletedCondition clusterv1.ConditionType = "ResourcesDeleted"
+
+	// DeletingResourcesFailedReason (Severity=Warning) documents a failure deleting the resources applied to a cluster.
+	DeletingResourcesFailedReason = "DeletingResourcesFailed"
+
+	// DeletingResourcesTimedOutReason (Severity=Warning) documents that the controller gave up deleting the resources applied
+	// to a cluster while the ClusterResourceSet is deleted, e.g. because the cluster is unreachable; the resources are orphaned.
+	DeletingResourcesTimedOutReason = "DeletingResourcesTimedOut"
+)
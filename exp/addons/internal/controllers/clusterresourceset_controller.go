@@ -29,6 +29,7 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
@@ -51,6 +52,11 @@ import (
 // ErrSecretTypeNotSupported signals that a Secret is not supported.
 var ErrSecretTypeNotSupported = errors.New("unsupported secret type")
 
+// resourcesDeletionTimeout is how long the controller tries to delete the resources applied to a Cluster when a
+// ClusterResourceSet with the Delete deletion policy is deleted; afterwards the resources are orphaned, so an
+// unreachable Cluster does not block the deletion of the ClusterResourceSet forever.
+const resourcesDeletionTimeout = 10 * time.Minute
+
 // +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;patch
 // +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;patch
 // +kubebuilder:rbac:groups=addons.cluster.x-k8s.io,resources=*,verbs=get;list;watch;create;update;patch;delete
@@ -159,6 +165,15 @@ func (r *ClusterResourceSetReconciler) Reconcile(ctx context.Context, req ctrl.R
 		}
 	}
 
+	if err := r.reconcileUnmatchedClusters(ctx, clusters, clusterResourceSet); err != nil {
+		if errors.Is(err, remote.ErrClusterLocked) {
+			log.V(5).Info("Requeuing because another worker has the lock on the ClusterCacheTracker")
+			errClusterLockedOccurred = true
+		} else {
+			errs = append(errs, err)
+		}
+	}
+
 	// Return an aggregated error if errors occurred.
 	if len(errs) > 0 {
 		return ctrl.Result{}, kerrors.NewAggregate(errs)
@@ -190,30 +205,166 @@ func (r *ClusterResourceSetReconciler) reconcileDelete(ctx context.Context, clus
 			return nil
 		}
 
-		// Initialize the patch helper.
-		patchHelper, err := patch.NewHelper(clusterResourceSetBinding, r.Client)
-		if err != nil {
+		if err := r.unbindCluster(ctrl.LoggerInto(ctx, log), cluster, crs, clusterResourceSetBinding); err != nil {
 			return err
 		}
+	}
+
+	controllerutil.RemoveFinalizer(crs, addonsv1.ClusterResourceSetFinalizer)
+	return nil
+}
+
+// reconcileUnmatchedClusters unbinds the ClusterResourceSet from the Clusters which are no longer matched by its selector.
+// This is only required with the Delete deletion policy, so the resources applied to those Clusters are removed.
+func (r *ClusterResourceSetReconciler) reconcileUnmatchedClusters(ctx context.Context, clusters []*clusterv1.Cluster, crs *addonsv1.ClusterResourceSet) error {
+	if addonsv1.ClusterResourceSetDeletionPolicy(crs.Spec.DeletionPolicy) != addonsv1.ClusterResourceSetDeletionPolicyDelete {
+		return nil
+	}
+
+	matched := sets.Set[string]{}
+	for _, cluster := range clusters {
+		matched.Insert(cluster.Name)
+	}
+
+	bindingList := &addonsv1.ClusterResourceSetBindingList{}
+	if err := r.Client.List(ctx, bindingList, client.InNamespace(crs.Namespace)); err != nil {
+		return errors.Wrap(err, "failed to list ClusterResourceSetBindings")
+	}
 
-		clusterResourceSetBinding.DeleteBinding(crs)
+	errList := []error{}
+	for i := range bindingList.Items {
+		clusterResourceSetBinding := &bindingList.Items[i]
+		if getResourceSetBinding(clusterResourceSetBinding, crs) == nil {
+			continue
+		}
 
-		// If CRS list is empty in the binding, delete the binding else
-		// attempt to Patch the ClusterResourceSetBinding object after delete reconciliation if there is at least 1 binding left.
-		if len(clusterResourceSetBinding.Spec.Bindings) == 0 {
-			if r.Client.Delete(ctx, clusterResourceSetBinding) != nil {
-				log.Error(err, "failed to delete empty ClusterResourceSetBinding")
+		// Bindings created before the ClusterName field was introduced are named after their Cluster.
+		clusterName := clusterResourceSetBinding.Spec.ClusterName
+		if clusterName == "" {
+			clusterName = clusterResourceSetBinding.Name
+		}
+		if matched.Has(clusterName) {
+			continue
+		}
+
+		// Nothing to do for Clusters which are gone or going away; their ClusterResourceSetBinding is
+		// garbage collected together with the Cluster.
+		cluster := &clusterv1.Cluster{}
+		if err := r.Client.Get(ctx, client.ObjectKey{Namespace: crs.Namespace, Name: clusterName}, cluster); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
 			}
-		} else if err := patchHelper.Patch(ctx, clusterResourceSetBinding); err != nil {
-			log.Error(err, "failed to patch ClusterResourceSetBinding")
-			return err
+			errList = append(errList, errors.Wrapf(err, "failed to get Cluster %s", klog.KRef(crs.Namespace, clusterName)))
+			continue
+		}
+		if !cluster.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		log := ctrl.LoggerFrom(ctx, "Cluster", klog.KObj(cluster))
+		log.Info("Cluster no longer matches the ClusterResourceSet selector, removing applied resources")
+		if err := r.unbindCluster(ctrl.LoggerInto(ctx, log), cluster, crs, clusterResourceSetBinding); err != nil {
+			errList = append(errList, err)
 		}
 	}
 
-	controllerutil.RemoveFinalizer(crs, addonsv1.ClusterResourceSetFinalizer)
+	if len(errList) == 0 {
+		conditions.Delete(crs, addonsv1.ResourcesDeletedCondition)
+	}
+	return kerrors.NewAggregate(errList)
+}
+
+// unbindCluster removes the ClusterResourceSet from the ClusterResourceSetBinding of a Cluster, after removing
+// the resources applied to the Cluster if the deletion policy is Delete.
+func (r *ClusterResourceSetReconciler) unbindCluster(ctx context.Context, cluster *clusterv1.Cluster, crs *addonsv1.ClusterResourceSet, clusterResourceSetBinding *addonsv1.ClusterResourceSetBinding) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	if addonsv1.ClusterResourceSetDeletionPolicy(crs.Spec.DeletionPolicy) == addonsv1.ClusterResourceSetDeletionPolicyDelete {
+		if resourceSetBinding := getResourceSetBinding(clusterResourceSetBinding, crs); resourceSetBinding != nil {
+			if err := r.deleteAppliedResources(ctx, cluster, crs, resourceSetBinding); err != nil {
+				if !resourcesDeletionTimedOut(crs, time.Now()) {
+					conditions.MarkFalse(crs, addonsv1.ResourcesDeletedCondition, addonsv1.DeletingResourcesFailedReason, clusterv1.ConditionSeverityWarning,
+						"Failed to delete resources applied to Cluster %s: %v", klog.KObj(cluster), err)
+					return err
+				}
+				log.Error(err, "Timed out deleting resources applied to Cluster, orphaning them", "timeout", resourcesDeletionTimeout)
+				conditions.MarkFalse(crs, addonsv1.ResourcesDeletedCondition, addonsv1.DeletingResourcesTimedOutReason, clusterv1.ConditionSeverityWarning,
+					"Timed out deleting resources applied to Cluster %s after %s, the resources are orphaned", klog.KObj(cluster), resourcesDeletionTimeout)
+			}
+		}
+	}
+
+	// Initialize the patch helper.
+	patchHelper, err := patch.NewHelper(clusterResourceSetBinding, r.Client)
+	if err != nil {
+		return err
+	}
+
+	clusterResourceSetBinding.DeleteBinding(crs)
+
+	// If CRS list is empty in the binding, delete the binding else
+	// attempt to Patch the ClusterResourceSetBinding object after delete reconciliation if there is at least 1 binding left.
+	if len(clusterResourceSetBinding.Spec.Bindings) == 0 {
+		if err := r.Client.Delete(ctx, clusterResourceSetBinding); err != nil && !apierrors.IsNotFound(err) {
+			log.Error(err, "failed to delete empty ClusterResourceSetBinding")
+		}
+	} else if err := patchHelper.Patch(ctx, clusterResourceSetBinding); err != nil {
+		log.Error(err, "failed to patch ClusterResourceSetBinding")
+		return err
+	}
 	return nil
 }
 
+// resourcesDeletionTimedOut returns true if the ClusterResourceSet is being deleted since more than resourcesDeletionTimeout.
+// Deletions triggered by Clusters no longer matching the ClusterResourceSet never time out, because they do not block anything.
+func resourcesDeletionTimedOut(crs *addonsv1.ClusterResourceSet, now time.Time) bool {
+	if crs.DeletionTimestamp.IsZero() {
+		return false
+	}
+	return now.Sub(crs.DeletionTimestamp.Time) > resourcesDeletionTimeout
+}
+
+// deleteAppliedResources deletes the objects of the resources which have been applied to a Cluster by a ClusterResourceSet.
+// Resources which no longer exist in the management cluster are skipped, as the objects they defined are unknown.
+func (r *ClusterResourceSetReconciler) deleteAppliedResources(ctx context.Context, cluster *clusterv1.Cluster, crs *addonsv1.ClusterResourceSet, resourceSetBinding *addonsv1.ResourceSetBinding) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	remoteClient, err := r.Tracker.GetClient(ctx, util.ObjectKey(cluster))
+	if err != nil {
+		return err
+	}
+
+	errList := []error{}
+	for _, resourceBinding := range resourceSetBinding.Resources {
+		if !resourceBinding.Applied {
+			continue
+		}
+
+		unstructuredObj, err := r.getResource(ctx, resourceBinding.ResourceRef, cluster.GetNamespace())
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				log.Info("Resource not found, skipping deletion of its objects", "Resource kind", resourceBinding.Kind, "Resource name", resourceBinding.Name)
+				continue
+			}
+			errList = append(errList, err)
+			continue
+		}
+
+		resourceScope, err := reconcileScopeForResource(crs, cluster, resourceBinding.ResourceRef, resourceSetBinding, unstructuredObj)
+		if err != nil {
+			errList = append(errList, err)
+			continue
+		}
+
+		log.Info("Deleting objects applied by ClusterResourceSet", "Resource kind", resourceBinding.Kind, "Resource name", resourceBinding.Name)
+		if err := resourceScope.delete(ctx, remoteClient); err != nil {
+			errList = append(errList, err)
+		}
+	}
+
+	return kerrors.NewAggregate(errList)
+}
+
 // getClustersByClusterResourceSetSelector fetches Clusters matched by the ClusterResourceSet's label selector that are in the same namespace as the ClusterResourceSet object.
 func (r *ClusterResourceSetReconciler) getClustersByClusterResourceSetSelector(ctx context.Context, clusterResourceSet *addonsv1.ClusterResourceSet) ([]*clusterv1.Cluster, error) {
 	log := ctrl.LoggerFrom(ctx)
@@ -322,7 +473,7 @@ func (r *ClusterResourceSetReconciler) ApplyClusterResourceSet(ctx context.Conte
 			errList = append(errList, err)
 		}
 
-		resourceScope, err := reconcileScopeForResource(clusterResourceSet, resource, resourceSetBinding, unstructuredObj)
+		resourceScope, err := reconcileScopeForResource(clusterResourceSet, cluster, resource, resourceSetBinding, unstructuredObj)
 		if err != nil {
 			resourceSetBinding.SetBinding(addonsv1.ResourceBinding{
 				ResourceRef:     resource,
@@ -427,6 +578,16 @@ func (r *ClusterResourceSetReconciler) ensureResourceOwnerRef(ctx context.Contex
 	return patchHelper.Patch(ctx, obj)
 }
 
+// getResourceSetBinding returns the ResourceSetBinding for a given ClusterResourceSet if it exists.
+func getResourceSetBinding(clusterResourceSetBinding *addonsv1.ClusterResourceSetBinding, crs *addonsv1.ClusterResourceSet) *addonsv1.ResourceSetBinding {
+	for _, binding := range clusterResourceSetBinding.Spec.Bindings {
+		if binding.ClusterResourceSetName == crs.Name {
+			return binding
+		}
+	}
+	return nil
+}
+
 // clusterToClusterResourceSet is mapper function that maps clusters to ClusterResourceSet.
 func (r *ClusterResourceSetReconciler) clusterToClusterResourceSet(ctx context.Context, o client.Object) []ctrl.Request {
 	result := []ctrl.Request{}
@@ -436,6 +597,15 @@ func (r *ClusterResourceSetReconciler) clusterToClusterResourceSet(ctx context.C
 		panic(fmt.Sprintf("Expected a Cluster but got a %T", o))
 	}
 
+	// Add all ClusterResourceSets bound to the Cluster, so they can be unbound if the Cluster no longer matches their selector.
+	clusterResourceSetBinding := &addonsv1.ClusterResourceSetBinding{}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(cluster), clusterResourceSetBinding); err == nil {
+		for _, binding := range clusterResourceSetBinding.Spec.Bindings {
+			name := client.ObjectKey{Namespace: cluster.Namespace, Name: binding.ClusterResourceSetName}
+			result = append(result, ctrl.Request{NamespacedName: name})
+		}
+	}
+
 	resourceList := &addonsv1.ClusterResourceSetList{}
 	if err := r.Client.List(ctx, resourceList, client.InNamespace(cluster.Namespace)); err != nil {
 		return nil
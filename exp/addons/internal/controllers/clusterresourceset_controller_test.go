@@ -558,6 +558,92 @@ metadata:
 		g.Expect(env.Delete(ctx, testCluster)).To(Succeed())
 	})
 
+	t.Run("Should delete applied resources when a ClusterResourceSet with Delete policy is deleted", func(t *testing.T) {
+		g := NewWithT(t)
+		ns := setup(t, g)
+		defer teardown(t, g, ns)
+
+		t.Log("Updating the cluster with labels")
+		testCluster.SetLabels(labels)
+		g.Expect(env.Update(ctx, testCluster)).To(Succeed())
+
+		t.Log("Creating a ClusterResourceSet instance with Delete policy that has same labels as selector")
+		clusterResourceSetInstance := &addonsv1.ClusterResourceSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      clusterResourceSetName,
+				Namespace: ns.Name,
+			},
+			Spec: addonsv1.ClusterResourceSetSpec{
+				ClusterSelector: metav1.LabelSelector{
+					MatchLabels: labels,
+				},
+				Resources:      []addonsv1.ResourceRef{{Name: configmapName, Kind: "ConfigMap"}},
+				DeletionPolicy: string(addonsv1.ClusterResourceSetDeletionPolicyDelete),
+			},
+		}
+		g.Expect(env.Create(ctx, clusterResourceSetInstance)).To(Succeed())
+
+		t.Log("Verifying the resource has been applied to the cluster")
+		cm1 := &corev1.ConfigMap{}
+		cm1Key := client.ObjectKey{Namespace: resourceConfigMapsNamespace, Name: resourceConfigMap1Name}
+		g.Eventually(func() error {
+			return env.Get(ctx, cm1Key, cm1)
+		}, timeout).Should(Succeed())
+
+		t.Log("Deleting the ClusterResourceSet and verifying the applied resource is deleted")
+		g.Expect(env.Delete(ctx, clusterResourceSetInstance)).To(Succeed())
+		g.Eventually(func() bool {
+			return apierrors.IsNotFound(env.Get(ctx, cm1Key, cm1))
+		}, timeout).Should(BeTrue())
+	})
+
+	t.Run("Should delete applied resources when a cluster no longer matches a ClusterResourceSet with Delete policy", func(t *testing.T) {
+		g := NewWithT(t)
+		ns := setup(t, g)
+		defer teardown(t, g, ns)
+
+		t.Log("Updating the cluster with labels")
+		testCluster.SetLabels(labels)
+		g.Expect(env.Update(ctx, testCluster)).To(Succeed())
+
+		t.Log("Creating a ClusterResourceSet instance with Delete policy that has same labels as selector")
+		clusterResourceSetInstance := &addonsv1.ClusterResourceSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      clusterResourceSetName,
+				Namespace: ns.Name,
+			},
+			Spec: addonsv1.ClusterResourceSetSpec{
+				ClusterSelector: metav1.LabelSelector{
+					MatchLabels: labels,
+				},
+				Resources:      []addonsv1.ResourceRef{{Name: configmapName, Kind: "ConfigMap"}},
+				DeletionPolicy: string(addonsv1.ClusterResourceSetDeletionPolicyDelete),
+			},
+		}
+		g.Expect(env.Create(ctx, clusterResourceSetInstance)).To(Succeed())
+
+		t.Log("Verifying the resource has been applied to the cluster")
+		cm1 := &corev1.ConfigMap{}
+		cm1Key := client.ObjectKey{Namespace: resourceConfigMapsNamespace, Name: resourceConfigMap1Name}
+		g.Eventually(func() error {
+			return env.Get(ctx, cm1Key, cm1)
+		}, timeout).Should(Succeed())
+
+		t.Log("Removing the labels from the cluster and verifying the applied resource is deleted")
+		g.Expect(env.Get(ctx, client.ObjectKeyFromObject(testCluster), testCluster)).To(Succeed())
+		testCluster.SetLabels(nil)
+		g.Expect(env.Update(ctx, testCluster)).To(Succeed())
+		g.Eventually(func() bool {
+			return apierrors.IsNotFound(env.Get(ctx, cm1Key, cm1))
+		}, timeout).Should(BeTrue())
+
+		t.Log("Verifying the ClusterResourceSetBinding is deleted")
+		g.Eventually(func() bool {
+			binding := &addonsv1.ClusterResourceSetBinding{}
+			return apierrors.IsNotFound(env.Get(ctx, client.ObjectKeyFromObject(testCluster), binding))
+		}, timeout).Should(BeTrue())
+	})
+
 	t.Run("Should add finalizer after reconcile", func(t *testing.T) {
 		g := NewWithT(t)
 		ns := setup(t, g)
@@ -1063,3 +1149,42 @@ func randomNamespaceForTest(tb testing.TB) string {
 	testNameHash := fmt.Sprintf("%x", h.Sum(nil))
 	return "ns-" + testNameHash[:7] + "-" + util.RandomString(6)
 }
+
+func TestResourcesDeletionTimedOut(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name              string
+		deletionTimestamp *metav1.Time
+		want              bool
+	}{
+		{
+			name:              "not deleted",
+			deletionTimestamp: nil,
+			want:              false,
+		},
+		{
+			name:              "deleted within the timeout",
+			deletionTimestamp: &metav1.Time{Time: now.Add(-resourcesDeletionTimeout / 2)},
+			want:              false,
+		},
+		{
+			name:              "deleted since more than the timeout",
+			deletionTimestamp: &metav1.Time{Time: now.Add(-2 * resourcesDeletionTimeout)},
+			want:              true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			crs := &addonsv1.ClusterResourceSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "crs",
+					DeletionTimestamp: tt.deletionTimestamp,
+				},
+			}
+			g.Expect(resourcesDeletionTimedOut(crs, now)).To(Equal(tt.want))
+		})
+	}
+}
@@ -25,8 +25,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"sort"
+	"text/template"
 	"unicode"
 
+	"github.com/Masterminds/sprig/v3"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -222,6 +224,74 @@ func normalizeData(resource *unstructured.Unstructured) ([][]byte, error) {
 	return dataList, nil
 }
 
+// renderData renders each entry of the normalized data of a resource as a Go template, using
+// data computed from the given Cluster.
+func renderData(dataList [][]byte, cluster *clusterv1.Cluster) ([][]byte, error) {
+	templateData, err := calculateTemplateData(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	renderedList := make([][]byte, 0, len(dataList))
+	for i := range dataList {
+		tpl, err := template.New("resource").Funcs(sprig.HermeticTxtFuncMap()).Option("missingkey=error").Parse(string(dataList[i]))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse template for Cluster %s", klog.KObj(cluster))
+		}
+
+		var buf bytes.Buffer
+		if err := tpl.Execute(&buf, templateData); err != nil {
+			return nil, errors.Wrapf(err, "failed to render template for Cluster %s", klog.KObj(cluster))
+		}
+		renderedList = append(renderedList, buf.Bytes())
+	}
+
+	return renderedList, nil
+}
+
+// calculateTemplateData calculates the data available to resource templates.
+// Topology variables are converted to their Go types, so they can be consumed like
+// `{{ .variables.myVar }}`; variables scoped to a definition are ignored.
+func calculateTemplateData(cluster *clusterv1.Cluster) (map[string]interface{}, error) {
+	network := map[string]interface{}{
+		"pods":          []string{},
+		"services":      []string{},
+		"serviceDomain": "",
+	}
+	if cluster.Spec.ClusterNetwork != nil {
+		if cluster.Spec.ClusterNetwork.Pods != nil {
+			network["pods"] = cluster.Spec.ClusterNetwork.Pods.CIDRBlocks
+		}
+		if cluster.Spec.ClusterNetwork.Services != nil {
+			network["services"] = cluster.Spec.ClusterNetwork.Services.CIDRBlocks
+		}
+		network["serviceDomain"] = cluster.Spec.ClusterNetwork.ServiceDomain
+	}
+
+	variables := map[string]interface{}{}
+	if cluster.Spec.Topology != nil {
+		for _, variable := range cluster.Spec.Topology.Variables {
+			if variable.DefinitionFrom != "" {
+				continue
+			}
+			var value interface{}
+			if err := json.Unmarshal(variable.Value.Raw, &value); err != nil {
+				return nil, errors.Wrapf(err, "failed to unmarshal value of variable %q of Cluster %s", variable.Name, klog.KObj(cluster))
+			}
+			variables[variable.Name] = value
+		}
+	}
+
+	return map[string]interface{}{
+		"cluster": map[string]interface{}{
+			"name":      cluster.Name,
+			"namespace": cluster.Namespace,
+			"network":   network,
+		},
+		"variables": variables,
+	}, nil
+}
+
 func getClusterNameFromOwnerRef(obj metav1.ObjectMeta) (string, error) {
 	for _, ref := range obj.GetOwnerReferences() {
 		if ref.Kind != "Cluster" {
@@ -23,6 +23,7 @@ import (
 
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -271,3 +272,83 @@ func TestEnsureKubernetesServiceCreated(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderData(t *testing.T) {
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: clusterv1.ClusterSpec{
+			ClusterNetwork: &clusterv1.ClusterNetwork{
+				Pods: &clusterv1.NetworkRanges{
+					CIDRBlocks: []string{"192.168.0.0/16"},
+				},
+				Services: &clusterv1.NetworkRanges{
+					CIDRBlocks: []string{"10.128.0.0/12"},
+				},
+			},
+			Topology: &clusterv1.Topology{
+				Variables: []clusterv1.ClusterVariable{
+					{
+						Name:  "mtu",
+						Value: apiextensionsv1.JSON{Raw: []byte(`1440`)},
+					},
+					{
+						Name:           "mtu",
+						DefinitionFrom: "other-patch",
+						Value:          apiextensionsv1.JSON{Raw: []byte(`9000`)},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		data    [][]byte
+		want    [][]byte
+		wantErr bool
+	}{
+		{
+			name: "should render cluster fields and topology variables",
+			data: [][]byte{
+				[]byte(`name: {{ .cluster.name }}-{{ .cluster.namespace }}`),
+				[]byte(`cidr: {{ index .cluster.network.pods 0 }},{{ index .cluster.network.services 0 }} mtu: {{ .variables.mtu }}`),
+			},
+			want: [][]byte{
+				[]byte(`name: test-cluster-default`),
+				[]byte(`cidr: 192.168.0.0/16,10.128.0.0/12 mtu: 1440`),
+			},
+		},
+		{
+			name: "should leave data without template actions unchanged",
+			data: [][]byte{[]byte(`kind: ConfigMap`)},
+			want: [][]byte{[]byte(`kind: ConfigMap`)},
+		},
+		{
+			name:    "should return error when a variable does not exist",
+			data:    [][]byte{[]byte(`{{ .variables.notDefined }}`)},
+			wantErr: true,
+		},
+		{
+			name:    "should return error when the template is invalid",
+			data:    [][]byte{[]byte(`{{ .cluster.name`)},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := renderData(tt.data, cluster)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
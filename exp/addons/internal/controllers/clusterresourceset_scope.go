@@ -26,6 +26,7 @@ import (
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
 )
 
@@ -40,10 +41,13 @@ type resourceReconcileScope interface {
 	// hash returns a computed hash of the defined objects in the resource. It is consistent
 	// between runs.
 	hash() string
+	// delete removes all objects defined by the resource from the target cluster.
+	delete(ctx context.Context, c client.Client) error
 }
 
 func reconcileScopeForResource(
 	crs *addonsv1.ClusterResourceSet,
+	cluster *clusterv1.Cluster,
 	resourceRef addonsv1.ResourceRef,
 	resourceSetBinding *addonsv1.ResourceSetBinding,
 	resource *unstructured.Unstructured,
@@ -53,6 +57,15 @@ func reconcileScopeForResource(
 		return nil, err
 	}
 
+	// Render the data for the Cluster before parsing the objects, so that the computed hash
+	// changes whenever the rendered result changes.
+	if addonsv1.ClusterResourceSetTemplateEngine(crs.Spec.TemplateEngine) == addonsv1.ClusterResourceSetTemplateEngineGoTemplate {
+		normalizedData, err = renderData(normalizedData, cluster)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	objs, err := objsFromYamlData(normalizedData)
 	if err != nil {
 		return nil, err
@@ -104,6 +117,25 @@ func (b baseResourceReconcileScope) hash() string {
 	return b.computedHash
 }
 
+func (b baseResourceReconcileScope) delete(ctx context.Context, c client.Client) error {
+	errList := []error{}
+	objs := b.objs()
+	// Delete objects in reverse creation order, e.g. namespaced objects before their Namespace.
+	for i := len(objs) - 1; i >= 0; i-- {
+		obj := objs[i].DeepCopy()
+		if err := c.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+			errList = append(errList, errors.Wrapf(
+				err,
+				"deleting object %s %s",
+				obj.GroupVersionKind(),
+				klog.KObj(obj),
+			))
+		}
+	}
+
+	return kerrors.NewAggregate(errList)
+}
+
 type reconcileStrategyScope struct {
 	baseResourceReconcileScope
 }
@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha4
 
 import (
+	apimachineryconversion "k8s.io/apimachinery/pkg/conversion"
 	"sigs.k8s.io/controller-runtime/pkg/conversion"
 
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
@@ -37,6 +38,10 @@ func (src *MachinePool) ConvertTo(dstRaw conversion.Hub) error {
 	}
 	dst.Spec.Template.Spec.NodeDeletionTimeout = restored.Spec.Template.Spec.NodeDeletionTimeout
 	dst.Spec.Template.Spec.NodeVolumeDetachTimeout = restored.Spec.Template.Spec.NodeVolumeDetachTimeout
+	dst.Spec.Template.Spec.NodeStartupTimeout = restored.Spec.Template.Spec.NodeStartupTimeout
+	dst.Spec.Template.Spec.NodeReRegistrationPolicy = restored.Spec.Template.Spec.NodeReRegistrationPolicy
+	dst.Spec.Template.Spec.NodeDrainEvictionTimeout = restored.Spec.Template.Spec.NodeDrainEvictionTimeout
+	dst.Status.NodeStatuses = restored.Status.NodeStatuses
 	return nil
 }
 
@@ -60,3 +65,8 @@ func (dst *MachinePoolList) ConvertFrom(srcRaw conversion.Hub) error {
 
 	return Convert_v1beta1_MachinePoolList_To_v1alpha4_MachinePoolList(src, dst, nil)
 }
+
+func Convert_v1beta1_MachinePoolStatus_To_v1alpha4_MachinePoolStatus(in *expv1.MachinePoolStatus, out *MachinePoolStatus, s apimachineryconversion.Scope) error {
+	// NodeStatuses does not exist in v1alpha4.
+	return autoConvert_v1beta1_MachinePoolStatus_To_v1alpha4_MachinePoolStatus(in, out, s)
+}
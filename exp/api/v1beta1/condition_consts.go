@@ -28,3 +28,22 @@ const (
 	// to be ready.
 	WaitingForReplicasReadyReason = "WaitingForReplicasReady"
 )
+
+// Conditions and condition Reasons for the MachineWarmPool object.
+
+const (
+	// PreProvisionedCondition is set by infrastructure providers on the infrastructure machines in a MachineWarmPool,
+	// reporting the infrastructure machine is pre-provisioned and can be claimed by a MachineSet.
+	PreProvisionedCondition clusterv1.ConditionType = "PreProvisioned"
+
+	// MachinesPreProvisionedCondition reports the MachineWarmPool has the desired number of pre-provisioned machines.
+	MachinesPreProvisionedCondition clusterv1.ConditionType = "MachinesPreProvisioned"
+
+	// WaitingForMachinesPreProvisionedReason (Severity=Info) documents a MachineWarmPool waiting for the
+	// infrastructure machines in the pool to be pre-provisioned.
+	WaitingForMachinesPreProvisionedReason = "WaitingForMachinesPreProvisioned"
+
+	// MachinesCreationFailedReason (Severity=Error) documents a MachineWarmPool failing to create
+	// infrastructure machines from the infrastructure machine template.
+	MachinesCreationFailedReason = "MachinesCreationFailed"
+)
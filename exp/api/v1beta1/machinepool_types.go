@@ -73,6 +73,10 @@ type MachinePoolStatus struct {
 	// +optional
 	NodeRefs []corev1.ObjectReference `json:"nodeRefs,omitempty"`
 
+	// NodeStatuses reports the status of the Nodes matching the ProviderIDList, sorted by provider ID.
+	// +optional
+	NodeStatuses []MachinePoolNodeStatus `json:"nodeStatuses,omitempty"`
+
 	// Replicas is the most recently observed number of replicas.
 	// +optional
 	Replicas int32 `json:"replicas"`
@@ -127,6 +131,18 @@ type MachinePoolStatus struct {
 
 // ANCHOR_END: MachinePoolStatus
 
+// MachinePoolNodeStatus defines the observed state of a Node of a MachinePool.
+type MachinePoolNodeStatus struct {
+	// Name is the name of the Node.
+	Name string `json:"name"`
+
+	// ProviderID is the provider ID of the Node.
+	ProviderID string `json:"providerID"`
+
+	// Ready is true if the Node has the Ready condition set to true.
+	Ready bool `json:"ready"`
+}
+
 // MachinePoolPhase is a string representation of a MachinePool Phase.
 //
 // This type is a high-level indicator of the status of the MachinePool as it is provisioned,
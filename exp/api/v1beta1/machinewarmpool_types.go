@@ -0,0 +1,126 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const (
+	// MachineWarmPoolNameLabel is the label set on the infrastructure machines in a MachineWarmPool, with
+	// the name of the MachineWarmPool as value. The label is removed when an infrastructure machine is
+	// claimed by a MachineSet.
+	MachineWarmPoolNameLabel = "cluster.x-k8s.io/warm-pool-name"
+)
+
+// MachineWarmPoolSpec defines the desired state of MachineWarmPool.
+type MachineWarmPoolSpec struct {
+	// ClusterName is the name of the Cluster this object belongs to.
+	// +kubebuilder:validation:MinLength=1
+	ClusterName string `json:"clusterName"`
+
+	// Replicas is the number of pre-provisioned infrastructure machines to keep in the pool.
+	// Defaults to 1.
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Template describes the machines in the pool; MachineSets with the same Kubernetes version and
+	// infrastructure template claim the machines in the pool when scaling up.
+	Template MachineWarmPoolTemplate `json:"template"`
+}
+
+// MachineWarmPoolTemplate describes the machines in a MachineWarmPool.
+type MachineWarmPoolTemplate struct {
+	// Version defines the desired Kubernetes version of the machines in the pool.
+	// Infrastructure providers can use it to pre-provision the machines, e.g. to select the machine image.
+	// +optional
+	Version *string `json:"version,omitempty"`
+
+	// InfrastructureRef is a reference to the infrastructure machine template used to create
+	// the infrastructure machines in the pool.
+	InfrastructureRef corev1.ObjectReference `json:"infrastructureRef"`
+}
+
+// MachineWarmPoolStatus defines the observed state of MachineWarmPool.
+type MachineWarmPoolStatus struct {
+	// Replicas is the number of infrastructure machines in the pool.
+	// +optional
+	Replicas int32 `json:"replicas"`
+
+	// ReadyReplicas is the number of infrastructure machines in the pool which are pre-provisioned
+	// and can be claimed by MachineSets.
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas"`
+
+	// ObservedGeneration is the latest generation observed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions define the current service state of the MachineWarmPool.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=machinewarmpools,shortName=mwp,scope=Namespaced,categories=cluster-api
+// +kubebuilder:subresource:status
+// +kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.replicas
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.clusterName",description="Cluster"
+// +kubebuilder:printcolumn:name="Desired",type=integer,JSONPath=".spec.replicas",description="Total number of pre-provisioned machines desired by this MachineWarmPool"
+// +kubebuilder:printcolumn:name="Replicas",type=integer,JSONPath=".status.replicas",description="Total number of machines in this MachineWarmPool"
+// +kubebuilder:printcolumn:name="Ready",type=integer,JSONPath=".status.readyReplicas",description="Total number of pre-provisioned machines in this MachineWarmPool"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of MachineWarmPool"
+// +kubebuilder:printcolumn:name="Version",type="string",JSONPath=".spec.template.version",description="Kubernetes version associated with this MachineWarmPool"
+// +k8s:conversion-gen=false
+
+// MachineWarmPool is the Schema for the machinewarmpools API.
+// A MachineWarmPool keeps a number of pre-provisioned infrastructure machines, which are claimed by
+// MachineSets when scaling up, reducing the time required to create Machines with slow providers.
+type MachineWarmPool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MachineWarmPoolSpec   `json:"spec,omitempty"`
+	Status MachineWarmPoolStatus `json:"status,omitempty"`
+}
+
+// GetConditions returns the set of conditions for this object.
+func (m *MachineWarmPool) GetConditions() clusterv1.Conditions {
+	return m.Status.Conditions
+}
+
+// SetConditions sets the conditions on this object.
+func (m *MachineWarmPool) SetConditions(conditions clusterv1.Conditions) {
+	m.Status.Conditions = conditions
+}
+
+// +kubebuilder:object:root=true
+
+// MachineWarmPoolList contains a list of MachineWarmPool.
+type MachineWarmPoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MachineWarmPool `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MachineWarmPool{}, &MachineWarmPoolList{})
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/util/version"
+)
+
+func (m *MachineWarmPool) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(m).
+		Complete()
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-cluster-x-k8s-io-v1beta1-machinewarmpool,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=cluster.x-k8s.io,resources=machinewarmpools,versions=v1beta1,name=validation.machinewarmpool.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
+// +kubebuilder:webhook:verbs=create;update,path=/mutate-cluster-x-k8s-io-v1beta1-machinewarmpool,mutating=true,failurePolicy=fail,matchPolicy=Equivalent,groups=cluster.x-k8s.io,resources=machinewarmpools,versions=v1beta1,name=default.machinewarmpool.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
+
+var _ webhook.Defaulter = &MachineWarmPool{}
+var _ webhook.Validator = &MachineWarmPool{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type.
+func (m *MachineWarmPool) Default() {
+	if m.Labels == nil {
+		m.Labels = make(map[string]string)
+	}
+	m.Labels[clusterv1.ClusterNameLabel] = m.Spec.ClusterName
+
+	if m.Spec.Replicas == nil {
+		m.Spec.Replicas = pointer.Int32(1)
+	}
+
+	if m.Spec.Template.InfrastructureRef.Namespace == "" {
+		m.Spec.Template.InfrastructureRef.Namespace = m.Namespace
+	}
+
+	// tolerate version strings without a "v" prefix: prepend it if it's not there.
+	if m.Spec.Template.Version != nil && !strings.HasPrefix(*m.Spec.Template.Version, "v") {
+		normalizedVersion := "v" + *m.Spec.Template.Version
+		m.Spec.Template.Version = &normalizedVersion
+	}
+}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
+func (m *MachineWarmPool) ValidateCreate() (admission.Warnings, error) {
+	return nil, m.validate(nil)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
+func (m *MachineWarmPool) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	oldMWP, ok := old.(*MachineWarmPool)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a MachineWarmPool but got a %T", old))
+	}
+	return nil, m.validate(oldMWP)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
+func (m *MachineWarmPool) ValidateDelete() (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (m *MachineWarmPool) validate(old *MachineWarmPool) error {
+	// NOTE: MachineWarmPool is behind MachineWarmPool feature gate flag; the web hook
+	// must prevent creating new objects when the feature flag is disabled.
+	specPath := field.NewPath("spec")
+	if !feature.Gates.Enabled(feature.MachineWarmPool) {
+		return field.Forbidden(
+			specPath,
+			"can be set only if the MachineWarmPool feature flag is enabled",
+		)
+	}
+	var allErrs field.ErrorList
+
+	if m.Spec.Replicas != nil && *m.Spec.Replicas < 0 {
+		allErrs = append(
+			allErrs,
+			field.Invalid(
+				specPath.Child("replicas"),
+				*m.Spec.Replicas,
+				"must be greater than or equal to 0",
+			),
+		)
+	}
+
+	if m.Spec.Template.InfrastructureRef.Namespace != m.Namespace {
+		allErrs = append(
+			allErrs,
+			field.Invalid(
+				specPath.Child("template", "infrastructureRef", "namespace"),
+				m.Spec.Template.InfrastructureRef.Namespace,
+				"must match metadata.namespace",
+			),
+		)
+	}
+
+	if old != nil {
+		if old.Spec.ClusterName != m.Spec.ClusterName {
+			allErrs = append(
+				allErrs,
+				field.Forbidden(
+					specPath.Child("clusterName"),
+					"field is immutable"),
+			)
+		}
+		// Changing the template would leave machines created from the previous template in the pool.
+		if !templateEqual(old.Spec.Template, m.Spec.Template) {
+			allErrs = append(
+				allErrs,
+				field.Forbidden(
+					specPath.Child("template"),
+					"field is immutable"),
+			)
+		}
+	}
+
+	if m.Spec.Template.Version != nil {
+		if !version.KubeSemver.MatchString(*m.Spec.Template.Version) {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("template", "version"), *m.Spec.Template.Version, "must be a valid semantic version"))
+		}
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind("MachineWarmPool").GroupKind(), m.Name, allErrs)
+}
+
+func templateEqual(a, b MachineWarmPoolTemplate) bool {
+	return pointer.StringDeref(a.Version, "") == pointer.StringDeref(b.Version, "") &&
+		a.InfrastructureRef.APIVersion == b.InfrastructureRef.APIVersion &&
+		a.InfrastructureRef.Kind == b.InfrastructureRef.Kind &&
+		a.InfrastructureRef.Name == b.InfrastructureRef.Name &&
+		a.InfrastructureRef.Namespace == b.InfrastructureRef.Namespace
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilfeature "k8s.io/component-base/featuregate/testing"
+	"k8s.io/utils/pointer"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/feature"
+	utildefaulting "sigs.k8s.io/cluster-api/util/defaulting"
+)
+
+func TestMachineWarmPoolDefault(t *testing.T) {
+	// NOTE: MachineWarmPool feature flag is disabled by default, thus preventing to create or update MachineWarmPool.
+	// Enabling the feature flag temporarily for this test.
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.MachineWarmPool, true)()
+
+	g := NewWithT(t)
+
+	m := &MachineWarmPool{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "foobar",
+		},
+		Spec: MachineWarmPoolSpec{
+			ClusterName: "test-cluster",
+			Template: MachineWarmPoolTemplate{
+				Version: pointer.String("1.28.0"),
+			},
+		},
+	}
+	t.Run("for MachineWarmPool", utildefaulting.DefaultValidateTest(m))
+	m.Default()
+
+	g.Expect(m.Labels[clusterv1.ClusterNameLabel]).To(Equal(m.Spec.ClusterName))
+	g.Expect(m.Spec.Replicas).To(Equal(pointer.Int32(1)))
+	g.Expect(m.Spec.Template.InfrastructureRef.Namespace).To(Equal(m.Namespace))
+	g.Expect(m.Spec.Template.Version).To(Equal(pointer.String("v1.28.0")))
+}
+
+func TestMachineWarmPoolValidation(t *testing.T) {
+	infraRef := corev1.ObjectReference{
+		APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+		Kind:       "DockerMachineTemplate",
+		Name:       "md-template",
+		Namespace:  "foobar",
+	}
+	valid := &MachineWarmPool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "foobar",
+		},
+		Spec: MachineWarmPoolSpec{
+			ClusterName: "test-cluster",
+			Replicas:    pointer.Int32(2),
+			Template: MachineWarmPoolTemplate{
+				Version:           pointer.String("v1.28.0"),
+				InfrastructureRef: infraRef,
+			},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		gate      bool
+		old       *MachineWarmPool
+		mutate    func(m *MachineWarmPool)
+		expectErr bool
+	}{
+		{
+			name:      "should return error if the feature gate is disabled",
+			gate:      false,
+			expectErr: true,
+		},
+		{
+			name:      "should not return error for a valid MachineWarmPool",
+			gate:      true,
+			expectErr: false,
+		},
+		{
+			name: "should return error for negative replicas",
+			gate: true,
+			mutate: func(m *MachineWarmPool) {
+				m.Spec.Replicas = pointer.Int32(-1)
+			},
+			expectErr: true,
+		},
+		{
+			name: "should return error if the infrastructureRef is in another namespace",
+			gate: true,
+			mutate: func(m *MachineWarmPool) {
+				m.Spec.Template.InfrastructureRef.Namespace = "other"
+			},
+			expectErr: true,
+		},
+		{
+			name: "should return error for an invalid version",
+			gate: true,
+			mutate: func(m *MachineWarmPool) {
+				m.Spec.Template.Version = pointer.String("vx.y.z")
+			},
+			expectErr: true,
+		},
+		{
+			name: "should not return error when scaling",
+			gate: true,
+			old:  valid,
+			mutate: func(m *MachineWarmPool) {
+				m.Spec.Replicas = pointer.Int32(5)
+			},
+			expectErr: false,
+		},
+		{
+			name: "should return error when changing the clusterName",
+			gate: true,
+			old:  valid,
+			mutate: func(m *MachineWarmPool) {
+				m.Spec.ClusterName = "other-cluster"
+			},
+			expectErr: true,
+		},
+		{
+			name: "should return error when changing the template",
+			gate: true,
+			old:  valid,
+			mutate: func(m *MachineWarmPool) {
+				m.Spec.Template.Version = pointer.String("v1.28.1")
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.MachineWarmPool, tt.gate)()
+			g := NewWithT(t)
+
+			m := valid.DeepCopy()
+			if tt.mutate != nil {
+				tt.mutate(m)
+			}
+
+			var err error
+			if tt.old != nil {
+				_, err = m.ValidateUpdate(tt.old)
+			} else {
+				_, err = m.ValidateCreate()
+			}
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+		})
+	}
+}
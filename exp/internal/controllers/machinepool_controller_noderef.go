@@ -19,14 +19,17 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"time"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/api/v1beta1/index"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api/internal/util/taints"
 	"sigs.k8s.io/cluster-api/util"
@@ -41,6 +44,7 @@ var (
 
 type getNodeReferencesResult struct {
 	references []corev1.ObjectReference
+	statuses   []expv1.MachinePoolNodeStatus
 	available  int
 	ready      int
 }
@@ -58,13 +62,6 @@ func (r *MachinePoolReconciler) reconcileNodeRefs(ctx context.Context, cluster *
 		return ctrl.Result{}, nil
 	}
 
-	// Check that the Machine doesn't already have a NodeRefs.
-	// Return early if there is no work to do.
-	if mp.Status.Replicas == mp.Status.ReadyReplicas && len(mp.Status.NodeRefs) == int(mp.Status.ReadyReplicas) {
-		conditions.MarkTrue(mp, expv1.ReplicasReadyCondition)
-		return ctrl.Result{}, nil
-	}
-
 	// Check that the MachinePool has valid ProviderIDList.
 	if len(mp.Spec.ProviderIDList) == 0 && (mp.Spec.Replicas == nil || *mp.Spec.Replicas != 0) {
 		log.V(2).Info("MachinePool doesn't have any ProviderIDs yet")
@@ -95,10 +92,14 @@ func (r *MachinePoolReconciler) reconcileNodeRefs(ctx context.Context, cluster *
 	mp.Status.ReadyReplicas = int32(nodeRefsResult.ready)
 	mp.Status.AvailableReplicas = int32(nodeRefsResult.available)
 	mp.Status.UnavailableReplicas = mp.Status.Replicas - mp.Status.AvailableReplicas
-	mp.Status.NodeRefs = nodeRefsResult.references
-
-	log.Info("Set MachinePools's NodeRefs", "noderefs", mp.Status.NodeRefs)
-	r.recorder.Event(mp, corev1.EventTypeNormal, "SuccessfulSetNodeRefs", fmt.Sprintf("%+v", mp.Status.NodeRefs))
+	mp.Status.NodeStatuses = nodeRefsResult.statuses
+	// Note: NodeRefs are computed at every reconcile to keep node statuses up to date, so they are only
+	// logged and reported when they change.
+	if !reflect.DeepEqual(mp.Status.NodeRefs, nodeRefsResult.references) {
+		mp.Status.NodeRefs = nodeRefsResult.references
+		log.Info("Set MachinePools's NodeRefs", "noderefs", mp.Status.NodeRefs)
+		r.recorder.Event(mp, corev1.EventTypeNormal, "SuccessfulSetNodeRefs", fmt.Sprintf("%+v", mp.Status.NodeRefs))
+	}
 
 	// Reconcile node annotations and taints.
 	err = r.patchNodes(ctx, clusterClient, nodeRefsResult.references, mp)
@@ -152,55 +153,96 @@ func (r *MachinePoolReconciler) deleteRetiredNodes(ctx context.Context, c client
 	return nil
 }
 
-func (r *MachinePoolReconciler) getNodeReferences(ctx context.Context, c client.Client, providerIDList []string) (getNodeReferencesResult, error) {
+// getNodeReferences returns the references to the Nodes matching the providerIDList, sorted by provider ID so
+// they do not depend on the order of the providerIDList, along with their status.
+func (r *MachinePoolReconciler) getNodeReferences(ctx context.Context, c client.Reader, providerIDList []string) (getNodeReferencesResult, error) {
 	log := ctrl.LoggerFrom(ctx, "providerIDList", len(providerIDList))
 
-	var ready, available int
-	nodeRefsMap := make(map[string]corev1.Node)
-	nodeList := corev1.NodeList{}
-	for {
-		if err := c.List(ctx, &nodeList, client.Continue(nodeList.Continue)); err != nil {
-			return getNodeReferencesResult{}, errors.Wrapf(err, "failed to List nodes")
+	providerIDs := sets.Set[string]{}
+	for _, providerID := range providerIDList {
+		if providerID == "" {
+			log.V(2).Info("No ProviderID detected, skipping", "providerID", providerID)
+			continue
 		}
+		providerIDs.Insert(providerID)
+	}
 
-		for _, node := range nodeList.Items {
-			if node.Spec.ProviderID == "" {
-				log.V(2).Info("No ProviderID detected, skipping", "providerID", node.Spec.ProviderID)
-				continue
-			}
+	nodes, err := getNodesByProviderID(ctx, c, providerIDs)
+	if err != nil {
+		return getNodeReferencesResult{}, err
+	}
 
-			nodeRefsMap[node.Spec.ProviderID] = node
+	var ready, available int
+	var nodeRefs []corev1.ObjectReference
+	var nodeStatuses []expv1.MachinePoolNodeStatus
+	for _, providerID := range sets.List(providerIDs) {
+		node, ok := nodes[providerID]
+		if !ok {
+			continue
+		}
+		available++
+		isReady := nodeIsReady(node)
+		if isReady {
+			ready++
 		}
+		nodeRefs = append(nodeRefs, corev1.ObjectReference{
+			Kind:       node.Kind,
+			APIVersion: node.APIVersion,
+			Name:       node.Name,
+			UID:        node.UID,
+		})
+		nodeStatuses = append(nodeStatuses, expv1.MachinePoolNodeStatus{
+			Name:       node.Name,
+			ProviderID: providerID,
+			Ready:      isReady,
+		})
+	}
 
-		if nodeList.Continue == "" {
+	if len(nodeRefs) == 0 && len(providerIDList) != 0 {
+		return getNodeReferencesResult{}, errNoAvailableNodes
+	}
+	return getNodeReferencesResult{nodeRefs, nodeStatuses, available, ready}, nil
+}
+
+// getNodesByProviderID returns the Nodes with the given provider IDs, indexed by provider ID.
+// Nodes are looked up using the provider ID index of the workload cluster cache; if for whatever reason the
+// index isn't registered or available, we fall back to loop over the whole list of Nodes.
+func getNodesByProviderID(ctx context.Context, c client.Reader, providerIDs sets.Set[string]) (map[string]*corev1.Node, error) {
+	nodes := make(map[string]*corev1.Node, providerIDs.Len())
+
+	indexAvailable := true
+	for providerID := range providerIDs {
+		nodeList := &corev1.NodeList{}
+		if err := c.List(ctx, nodeList, client.MatchingFields{index.NodeProviderIDField: providerID}); err != nil {
+			indexAvailable = false
 			break
 		}
+		for i := range nodeList.Items {
+			nodes[providerID] = &nodeList.Items[i]
+		}
+	}
+	if indexAvailable {
+		return nodes, nil
 	}
 
-	var nodeRefs []corev1.ObjectReference
-	for _, providerID := range providerIDList {
-		if providerID == "" {
-			log.V(2).Info("No ProviderID detected, skipping", "providerID", providerID)
-			continue
+	nodeList := &corev1.NodeList{}
+	for {
+		if err := c.List(ctx, nodeList, client.Continue(nodeList.Continue)); err != nil {
+			return nil, errors.Wrapf(err, "failed to List nodes")
 		}
-		if node, ok := nodeRefsMap[providerID]; ok {
-			available++
-			if nodeIsReady(&node) {
-				ready++
+
+		for i := range nodeList.Items {
+			node := &nodeList.Items[i]
+			if providerIDs.Has(node.Spec.ProviderID) {
+				nodes[node.Spec.ProviderID] = node
 			}
-			nodeRefs = append(nodeRefs, corev1.ObjectReference{
-				Kind:       node.Kind,
-				APIVersion: node.APIVersion,
-				Name:       node.Name,
-				UID:        node.UID,
-			})
 		}
-	}
 
-	if len(nodeRefs) == 0 && len(providerIDList) != 0 {
-		return getNodeReferencesResult{}, errNoAvailableNodes
+		if nodeList.Continue == "" {
+			break
+		}
 	}
-	return getNodeReferencesResult{nodeRefs, available, ready}, nil
+	return nodes, nil
 }
 
 // patchNodes patches the nodes with the cluster name and cluster namespace annotations.
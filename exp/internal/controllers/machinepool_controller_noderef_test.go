@@ -27,6 +27,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/api/v1beta1/index"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 )
 
@@ -68,6 +69,11 @@ func TestMachinePoolGetNodeReference(t *testing.T) {
 			Spec: corev1.NodeSpec{
 				ProviderID: "azure://westus2/id-node-4",
 			},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{
+					{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+				},
+			},
 		},
 		&corev1.Node{
 			ObjectMeta: metav1.ObjectMeta{
@@ -87,7 +93,10 @@ func TestMachinePoolGetNodeReference(t *testing.T) {
 		},
 	}
 
-	client := fake.NewClientBuilder().WithObjects(nodeList...).Build()
+	clients := map[string]client.Client{
+		"with provider ID index":    fake.NewClientBuilder().WithObjects(nodeList...).WithIndex(&corev1.Node{}, index.NodeProviderIDField, index.NodeByProviderID).Build(),
+		"without provider ID index": fake.NewClientBuilder().WithObjects(nodeList...).Build(),
+	}
 
 	testCases := []struct {
 		name           string
@@ -129,6 +138,11 @@ func TestMachinePoolGetNodeReference(t *testing.T) {
 				references: []corev1.ObjectReference{
 					{Name: "azure-node-4"},
 				},
+				statuses: []expv1.MachinePoolNodeStatus{
+					{Name: "azure-node-4", ProviderID: "azure://westus2/id-node-4", Ready: true},
+				},
+				available: 1,
+				ready:     1,
 			},
 		},
 		{
@@ -139,6 +153,42 @@ func TestMachinePoolGetNodeReference(t *testing.T) {
 					{Name: "node-1"},
 					{Name: "azure-node-4"},
 				},
+				statuses: []expv1.MachinePoolNodeStatus{
+					{Name: "node-1", ProviderID: "aws://us-east-1/id-node-1", Ready: false},
+					{Name: "azure-node-4", ProviderID: "azure://westus2/id-node-4", Ready: true},
+				},
+				available: 2,
+				ready:     1,
+			},
+		},
+		{
+			name:           "valid provider ids in a different order, references sorted by provider id",
+			providerIDList: []string{"azure://westus2/id-node-4", "aws://us-east-1/id-node-1"},
+			expected: &getNodeReferencesResult{
+				references: []corev1.ObjectReference{
+					{Name: "node-1"},
+					{Name: "azure-node-4"},
+				},
+				statuses: []expv1.MachinePoolNodeStatus{
+					{Name: "node-1", ProviderID: "aws://us-east-1/id-node-1", Ready: false},
+					{Name: "azure-node-4", ProviderID: "azure://westus2/id-node-4", Ready: true},
+				},
+				available: 2,
+				ready:     1,
+			},
+		},
+		{
+			name:           "valid provider ids, some nodes not found",
+			providerIDList: []string{"aws://us-east-1/id-node-1", "aws:///id-node-100"},
+			expected: &getNodeReferencesResult{
+				references: []corev1.ObjectReference{
+					{Name: "node-1"},
+				},
+				statuses: []expv1.MachinePoolNodeStatus{
+					{Name: "node-1", ProviderID: "aws://us-east-1/id-node-1", Ready: false},
+				},
+				available: 1,
+				ready:     0,
 			},
 		},
 		{
@@ -177,29 +227,37 @@ func TestMachinePoolGetNodeReference(t *testing.T) {
 		},
 	}
 
-	for _, test := range testCases {
-		t.Run(test.name, func(t *testing.T) {
-			g := NewWithT(t)
+	for clientName, c := range clients {
+		for _, test := range testCases {
+			t.Run(clientName+": "+test.name, func(t *testing.T) {
+				g := NewWithT(t)
 
-			result, err := r.getNodeReferences(ctx, client, test.providerIDList)
-			if test.err == nil {
-				g.Expect(err).ToNot(HaveOccurred())
-			} else {
-				g.Expect(err).To(HaveOccurred())
-				g.Expect(err).To(Equal(test.err), "Expected error %v, got %v", test.err, err)
-			}
+				result, err := r.getNodeReferences(ctx, c, test.providerIDList)
+				if test.err == nil {
+					g.Expect(err).ToNot(HaveOccurred())
+				} else {
+					g.Expect(err).To(HaveOccurred())
+					g.Expect(err).To(Equal(test.err), "Expected error %v, got %v", test.err, err)
+				}
 
-			if test.expected == nil && len(result.references) == 0 {
-				return
-			}
+				if test.expected == nil && len(result.references) == 0 {
+					return
+				}
 
-			g.Expect(result.references).To(HaveLen(len(test.expected.references)), "Expected NodeRef count to be %v, got %v", len(result.references), len(test.expected.references))
+				g.Expect(result.references).To(HaveLen(len(test.expected.references)), "Expected NodeRef count to be %v, got %v", len(result.references), len(test.expected.references))
 
-			for n := range test.expected.references {
-				g.Expect(result.references[n].Name).To(Equal(test.expected.references[n].Name), "Expected NodeRef's name to be %v, got %v", result.references[n].Name, test.expected.references[n].Name)
-				g.Expect(result.references[n].Namespace).To(Equal(test.expected.references[n].Namespace), "Expected NodeRef's namespace to be %v, got %v", result.references[n].Namespace, test.expected.references[n].Namespace)
-			}
-		})
+				for n := range test.expected.references {
+					g.Expect(result.references[n].Name).To(Equal(test.expected.references[n].Name), "Expected NodeRef's name to be %v, got %v", result.references[n].Name, test.expected.references[n].Name)
+					g.Expect(result.references[n].Namespace).To(Equal(test.expected.references[n].Namespace), "Expected NodeRef's namespace to be %v, got %v", result.references[n].Namespace, test.expected.references[n].Namespace)
+				}
+
+				if test.expected.statuses != nil {
+					g.Expect(result.statuses).To(Equal(test.expected.statuses))
+					g.Expect(result.available).To(Equal(test.expected.available))
+					g.Expect(result.ready).To(Equal(test.expected.ready))
+				}
+			})
+		}
 	}
 }
 
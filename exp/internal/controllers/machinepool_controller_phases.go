@@ -143,13 +143,28 @@ func (r *MachinePoolReconciler) reconcileExternal(ctx context.Context, cluster *
 		return external.ReconcileOutput{}, err
 	}
 
-	// Set the Cluster label.
+	// Propagate the Cluster metadata and set the Cluster label.
 	labels := obj.GetLabels()
 	if labels == nil {
 		labels = make(map[string]string)
 	}
+	if cluster.Spec.Metadata != nil {
+		for k, v := range cluster.Spec.Metadata.Labels {
+			labels[k] = v
+		}
+	}
 	labels[clusterv1.ClusterNameLabel] = m.Spec.ClusterName
 	obj.SetLabels(labels)
+	if cluster.Spec.Metadata != nil && len(cluster.Spec.Metadata.Annotations) > 0 {
+		annotations := obj.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		for k, v := range cluster.Spec.Metadata.Annotations {
+			annotations[k] = v
+		}
+		obj.SetAnnotations(annotations)
+	}
 
 	// Always attempt to Patch the external object.
 	if err := patchHelper.Patch(ctx, obj); err != nil {
@@ -282,7 +297,7 @@ func (r *MachinePoolReconciler) reconcileInfrastructure(ctx context.Context, clu
 		conditions.WithFallbackValue(ready, clusterv1.WaitingForInfrastructureFallbackReason, clusterv1.ConditionSeverityInfo, ""),
 	)
 
-	if err := r.reconcileMachines(ctx, mp, infraConfig); err != nil {
+	if err := r.reconcileMachines(ctx, cluster, mp, infraConfig); err != nil {
 		return ctrl.Result{}, errors.Wrapf(err, "failed to reconcile Machines for MachinePool %s", klog.KObj(mp))
 	}
 
@@ -310,11 +325,15 @@ func (r *MachinePoolReconciler) reconcileInfrastructure(ctx context.Context, clu
 		return ctrl.Result{}, nil
 	}
 
-	if !reflect.DeepEqual(mp.Spec.ProviderIDList, providerIDList) {
+	// Note: Readiness is only reset when the set of provider IDs changes, so providers reordering the
+	// providerIDList do not make all the replicas flap to not ready.
+	if !sets.New[string](mp.Spec.ProviderIDList...).Equal(sets.New[string](providerIDList...)) {
 		mp.Spec.ProviderIDList = providerIDList
 		mp.Status.ReadyReplicas = 0
 		mp.Status.AvailableReplicas = 0
 		mp.Status.UnavailableReplicas = mp.Status.Replicas
+	} else if !reflect.DeepEqual(mp.Spec.ProviderIDList, providerIDList) {
+		mp.Spec.ProviderIDList = providerIDList
 	}
 
 	return ctrl.Result{}, nil
@@ -327,7 +346,7 @@ func (r *MachinePoolReconciler) reconcileInfrastructure(ctx context.Context, clu
 // infrastructure is created accordingly.
 // Note: When supported by the cloud provider implementation of the MachinePool, machines will provide a means to interact
 // with the corresponding infrastructure (e.g. delete a specific machine in case MachineHealthCheck detects it is unhealthy).
-func (r *MachinePoolReconciler) reconcileMachines(ctx context.Context, mp *expv1.MachinePool, infraMachinePool *unstructured.Unstructured) error {
+func (r *MachinePoolReconciler) reconcileMachines(ctx context.Context, cluster *clusterv1.Cluster, mp *expv1.MachinePool, infraMachinePool *unstructured.Unstructured) error {
 	log := ctrl.LoggerFrom(ctx)
 
 	var infraMachineKind string
@@ -347,6 +366,14 @@ func (r *MachinePoolReconciler) reconcileMachines(ctx context.Context, mp *expv1
 		},
 	}
 
+	// If requested, adopt the pre-existing provider instances reported by the InfraMachinePool, so they are
+	// picked up by the following steps.
+	if _, ok := mp.Annotations[clusterv1.MachinePoolAdoptExistingMachinesAnnotation]; ok {
+		if err := r.adoptExistingMachines(ctx, mp, infraMachinePool, infraMachineKind); err != nil {
+			return errors.Wrapf(err, "failed to adopt existing machines for MachinePool %s", klog.KObj(mp))
+		}
+	}
+
 	log.V(4).Info("Reconciling MachinePool Machines", "infrastructureMachineKind", infraMachineKind, "infrastructureMachineSelector", infraMachineSelector)
 	var infraMachineList unstructured.UnstructuredList
 
@@ -375,7 +402,11 @@ func (r *MachinePoolReconciler) reconcileMachines(ctx context.Context, mp *expv1
 		return err
 	}
 
-	updatedMachines, err := r.createMachinesIfNotExists(ctx, mp, machineList.Items, infraMachineList.Items)
+	if err := r.syncMachinesMetadata(ctx, cluster, mp, machineList.Items); err != nil {
+		return errors.Wrapf(err, "failed to update machines for MachinePool %q in namespace %q", mp.Name, mp.Namespace)
+	}
+
+	updatedMachines, err := r.createMachinesIfNotExists(ctx, cluster, mp, machineList.Items, infraMachineList.Items)
 	if err != nil {
 		return errors.Wrapf(err, "failed to create machines for MachinePool %q in namespace %q", mp.Name, mp.Namespace)
 	}
@@ -387,8 +418,128 @@ func (r *MachinePoolReconciler) reconcileMachines(ctx context.Context, mp *expv1
 	return nil
 }
 
+// adoptExistingMachines adopts the pre-existing provider instances reported by the InfraMachinePool, i.e. the
+// stand-alone Machines and the InfraMachines of the Cluster with a provider ID in the InfraMachinePool's spec.providerIDList.
+// Adopted Machines get the MachinePool as controller and the MachinePool name label, while adopted InfraMachines only
+// get the MachinePool name label; Machines are then created for the adopted InfraMachines without a Machine like for
+// any other InfraMachine of the MachinePool.
+func (r *MachinePoolReconciler) adoptExistingMachines(ctx context.Context, mp *expv1.MachinePool, infraMachinePool *unstructured.Unstructured, infraMachineKind string) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	var providerIDList []string
+	if err := util.UnstructuredUnmarshalField(infraMachinePool, &providerIDList, "spec", "providerIDList"); err != nil {
+		if errors.Is(err, util.ErrUnstructuredFieldNotFound) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to retrieve providerIDList from infrastructure provider for MachinePool %s", klog.KObj(mp))
+	}
+	providerIDs := sets.New[string](providerIDList...)
+	if providerIDs.Len() == 0 {
+		return nil
+	}
+
+	// Adopt the stand-alone Machines of the Cluster.
+	machineList := &clusterv1.MachineList{}
+	if err := r.Client.List(ctx, machineList, client.InNamespace(mp.Namespace), client.MatchingLabels{clusterv1.ClusterNameLabel: mp.Spec.ClusterName}); err != nil {
+		return errors.Wrapf(err, "failed to list Machines for MachinePool %s", klog.KObj(mp))
+	}
+	for i := range machineList.Items {
+		machine := &machineList.Items[i]
+		if machine.Spec.ProviderID == nil || !providerIDs.Has(*machine.Spec.ProviderID) {
+			continue
+		}
+		if metav1.GetControllerOf(machine) != nil || !machine.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		log.Info("Adopting existing Machine", "Machine", klog.KObj(machine), "providerID", *machine.Spec.ProviderID)
+		patchHelper, err := patch.NewHelper(machine, r.Client)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create patch helper for Machine %s", klog.KObj(machine))
+		}
+		machine.SetOwnerReferences(util.EnsureOwnerRef(machine.GetOwnerReferences(), *metav1.NewControllerRef(mp, expv1.GroupVersion.WithKind("MachinePool"))))
+		if machine.Labels == nil {
+			machine.Labels = map[string]string{}
+		}
+		machine.Labels[clusterv1.MachinePoolNameLabel] = format.MustFormatValue(mp.Name)
+		if err := patchHelper.Patch(ctx, machine); err != nil {
+			return errors.Wrapf(err, "failed to patch Machine %s", klog.KObj(machine))
+		}
+	}
+
+	// Adopt the InfraMachines of the Cluster.
+	var infraMachineList unstructured.UnstructuredList
+	infraMachineList.SetAPIVersion(infraMachinePool.GetAPIVersion())
+	infraMachineList.SetKind(infraMachineKind + "List")
+	if err := r.Client.List(ctx, &infraMachineList, client.InNamespace(mp.Namespace), client.MatchingLabels{clusterv1.ClusterNameLabel: mp.Spec.ClusterName}); err != nil {
+		return errors.Wrapf(err, "failed to list infra machines for MachinePool %s", klog.KObj(mp))
+	}
+	for i := range infraMachineList.Items {
+		infraMachine := &infraMachineList.Items[i]
+		if _, ok := infraMachine.GetLabels()[clusterv1.MachinePoolNameLabel]; ok {
+			continue
+		}
+		// InfraMachines controlled by another object, e.g. a Machine of a MachineSet, are never adopted.
+		if metav1.GetControllerOf(infraMachine) != nil || !infraMachine.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+		var providerID string
+		if err := util.UnstructuredUnmarshalField(infraMachine, &providerID, "spec", "providerID"); err != nil {
+			if errors.Is(err, util.ErrUnstructuredFieldNotFound) {
+				continue
+			}
+			return errors.Wrapf(err, "failed to retrieve providerID from %s", klog.KObj(infraMachine))
+		}
+		if !providerIDs.Has(providerID) {
+			continue
+		}
+
+		log.Info("Adopting existing infraMachine", infraMachine.GetKind(), klog.KObj(infraMachine), "providerID", providerID)
+		patchHelper, err := patch.NewHelper(infraMachine, r.Client)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create patch helper for %s", klog.KObj(infraMachine))
+		}
+		labels := infraMachine.GetLabels()
+		labels[clusterv1.MachinePoolNameLabel] = format.MustFormatValue(mp.Name)
+		infraMachine.SetLabels(labels)
+		if err := patchHelper.Patch(ctx, infraMachine); err != nil {
+			return errors.Wrapf(err, "failed to patch %s", klog.KObj(infraMachine))
+		}
+	}
+
+	return nil
+}
+
+// syncMachinesMetadata updates the labels and annotations of the existing MachinePool Machines, so changes to the
+// MachinePool template and to the Cluster metadata are propagated to Machines created before the change.
+// NOTE: Labels and annotations are only added or updated; this is consistent with the propagation to other objects
+// not managed with server-side apply, e.g. the Nodes.
+func (r *MachinePoolReconciler) syncMachinesMetadata(ctx context.Context, cluster *clusterv1.Cluster, mp *expv1.MachinePool, machines []clusterv1.Machine) error {
+	desiredLabels := machinePoolMachineLabels(cluster, mp)
+	desiredAnnotations := machinePoolMachineAnnotations(cluster, mp)
+
+	var errs []error
+	for i := range machines {
+		machine := &machines[i]
+		if !machine.DeletionTimestamp.IsZero() {
+			continue
+		}
+		patchHelper, err := patch.NewHelper(machine, r.Client)
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to create patch helper for Machine %s", klog.KObj(machine)))
+			continue
+		}
+		machine.Labels = util.MergeMap(desiredLabels, machine.Labels)
+		machine.Annotations = util.MergeMap(desiredAnnotations, machine.Annotations)
+		if err := patchHelper.Patch(ctx, machine); err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to patch Machine %s", klog.KObj(machine)))
+		}
+	}
+	return kerrors.NewAggregate(errs)
+}
+
 // createMachinesIfNotExists creates a MachinePool Machine for each infraMachine if it doesn't already exist and sets the owner reference and infraRef.
-func (r *MachinePoolReconciler) createMachinesIfNotExists(ctx context.Context, mp *expv1.MachinePool, machines []clusterv1.Machine, infraMachines []unstructured.Unstructured) ([]clusterv1.Machine, error) {
+func (r *MachinePoolReconciler) createMachinesIfNotExists(ctx context.Context, cluster *clusterv1.Cluster, mp *expv1.MachinePool, machines []clusterv1.Machine, infraMachines []unstructured.Unstructured) ([]clusterv1.Machine, error) {
 	log := ctrl.LoggerFrom(ctx)
 
 	// Construct a set of names of infraMachines that already have a Machine.
@@ -409,7 +560,7 @@ func (r *MachinePoolReconciler) createMachinesIfNotExists(ctx context.Context, m
 		}
 		// Otherwise create a new Machine for the infraMachine.
 		log.Info("Creating new Machine for infraMachine", infraMachine.GroupVersionKind().Kind, klog.KObj(infraMachine))
-		machine := getNewMachine(mp, infraMachine)
+		machine := getNewMachine(cluster, mp, infraMachine)
 		if err := r.Client.Create(ctx, machine); err != nil {
 			errs = append(errs, errors.Wrapf(err, "failed to create new Machine for infraMachine %q in namespace %q", infraMachine.GetName(), infraMachine.GetNamespace()))
 			continue
@@ -466,7 +617,7 @@ func (r *MachinePoolReconciler) ensureInfraMachineOnwerRefs(ctx context.Context,
 }
 
 // getNewMachine creates a new Machine object.
-func getNewMachine(mp *expv1.MachinePool, infraMachine *unstructured.Unstructured) *clusterv1.Machine {
+func getNewMachine(cluster *clusterv1.Cluster, mp *expv1.MachinePool, infraMachine *unstructured.Unstructured) *clusterv1.Machine {
 	infraRef := corev1.ObjectReference{
 		APIVersion: infraMachine.GetAPIVersion(),
 		Kind:       infraMachine.GetKind(),
@@ -480,8 +631,6 @@ func getNewMachine(mp *expv1.MachinePool, infraMachine *unstructured.Unstructure
 			// Note: by setting the ownerRef on creation we signal to the Machine controller that this is not a stand-alone Machine.
 			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(mp, mp.GroupVersionKind())},
 			Namespace:       mp.Namespace,
-			Labels:          make(map[string]string),
-			Annotations:     make(map[string]string),
 		},
 		Spec: clusterv1.MachineSpec{
 			ClusterName:       mp.Spec.ClusterName,
@@ -489,24 +638,47 @@ func getNewMachine(mp *expv1.MachinePool, infraMachine *unstructured.Unstructure
 		},
 	}
 
-	for k, v := range mp.Spec.Template.Annotations {
-		machine.Annotations[k] = v
-	}
+	machine.Labels = machinePoolMachineLabels(cluster, mp)
+	machine.Annotations = machinePoolMachineAnnotations(cluster, mp)
+
+	return machine
+}
 
-	// Set the labels from machinePool.Spec.Template.Labels as labels for the new Machine.
+// machinePoolMachineLabels computes the labels the Machines of this MachinePool should have.
+// Labels from the MachinePool template take precedence over the ones from the Cluster metadata.
+func machinePoolMachineLabels(cluster *clusterv1.Cluster, mp *expv1.MachinePool) map[string]string {
+	labels := map[string]string{}
+	if cluster.Spec.Metadata != nil {
+		for k, v := range cluster.Spec.Metadata.Labels {
+			labels[k] = v
+		}
+	}
 	// Note: We can't just set `machinePool.Spec.Template.Labels` directly and thus "share" the labels
 	// map between Machine and machinePool.Spec.Template.Labels. This would mean that adding the
 	// MachinePoolNameLabel later on the Machine would also add the labels to machinePool.Spec.Template.Labels
 	// and thus modify the labels of the MachinePool.
 	for k, v := range mp.Spec.Template.Labels {
-		machine.Labels[k] = v
+		labels[k] = v
 	}
-
 	// Enforce that the MachinePoolNameLabel and ClusterNameLabel are present on the Machine.
-	machine.Labels[clusterv1.MachinePoolNameLabel] = format.MustFormatValue(mp.Name)
-	machine.Labels[clusterv1.ClusterNameLabel] = mp.Spec.ClusterName
+	labels[clusterv1.MachinePoolNameLabel] = format.MustFormatValue(mp.Name)
+	labels[clusterv1.ClusterNameLabel] = mp.Spec.ClusterName
+	return labels
+}
 
-	return machine
+// machinePoolMachineAnnotations computes the annotations the Machines of this MachinePool should have.
+// Annotations from the MachinePool template take precedence over the ones from the Cluster metadata.
+func machinePoolMachineAnnotations(cluster *clusterv1.Cluster, mp *expv1.MachinePool) map[string]string {
+	annotations := map[string]string{}
+	if cluster.Spec.Metadata != nil {
+		for k, v := range cluster.Spec.Metadata.Annotations {
+			annotations[k] = v
+		}
+	}
+	for k, v := range mp.Spec.Template.Annotations {
+		annotations[k] = v
+	}
+	return annotations
 }
 
 // infraMachineToMachinePoolMapper is a mapper function that maps an InfraMachine to the MachinePool that owns it.
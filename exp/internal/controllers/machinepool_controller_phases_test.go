@@ -1029,6 +1029,58 @@ func TestReconcileMachinePoolInfrastructure(t *testing.T) {
 				g.Expect(m.Status.GetTypedPhase()).To(Equal(expv1.MachinePoolPhaseRunning))
 			},
 		},
+		{
+			name: "ready bootstrap, infra, and nodeRef, machinepool is running, providerIDList reordered, replicas stay ready",
+			machinepool: &expv1.MachinePool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "machinepool-test",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: expv1.MachinePoolSpec{
+					Replicas: pointer.Int32(2),
+					Template: clusterv1.MachineTemplateSpec{
+						Spec: clusterv1.MachineSpec{
+							InfrastructureRef: corev1.ObjectReference{
+								APIVersion: builder.InfrastructureGroupVersion.String(),
+								Kind:       builder.TestInfrastructureMachineTemplateKind,
+								Name:       "infra-config1",
+							},
+						},
+					},
+					ProviderIDList: []string{"test://id-1", "test://id-2"},
+				},
+				Status: expv1.MachinePoolStatus{
+					InfrastructureReady: true,
+					Replicas:            2,
+					ReadyReplicas:       2,
+					AvailableReplicas:   2,
+				},
+			},
+			infraConfig: map[string]interface{}{
+				"kind":       builder.TestInfrastructureMachineTemplateKind,
+				"apiVersion": builder.InfrastructureGroupVersion.String(),
+				"metadata": map[string]interface{}{
+					"name":      "infra-config1",
+					"namespace": metav1.NamespaceDefault,
+				},
+				"spec": map[string]interface{}{
+					"providerIDList": []interface{}{
+						"test://id-2",
+						"test://id-1",
+					},
+				},
+				"status": map[string]interface{}{
+					"ready": true,
+				},
+			},
+			expectError: false,
+			expected: func(g *WithT, m *expv1.MachinePool) {
+				g.Expect(m.Spec.ProviderIDList).To(Equal([]string{"test://id-2", "test://id-1"}))
+				g.Expect(m.Status.ReadyReplicas).To(Equal(int32(2)))
+				g.Expect(m.Status.AvailableReplicas).To(Equal(int32(2)))
+				g.Expect(m.Status.UnavailableReplicas).To(Equal(int32(0)))
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -1132,6 +1184,89 @@ func TestReconcileMachinePoolMachines(t *testing.T) {
 		},
 	}
 
+	// infraMachine3 and infraMachine4 are pre-existing infraMachines which are not part of the MachinePool yet.
+	infraMachine3 := unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind":       "InfrastructureMachine",
+			"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+			"metadata": map[string]interface{}{
+				"name":      "infra-machine3",
+				"namespace": metav1.NamespaceDefault,
+				"labels": map[string]interface{}{
+					clusterv1.ClusterNameLabel: defaultCluster.Name,
+				},
+			},
+			"spec": map[string]interface{}{
+				"providerID": "test://id-3",
+			},
+		},
+	}
+
+	infraMachine4 := unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind":       "InfrastructureMachine",
+			"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+			"metadata": map[string]interface{}{
+				"name":      "infra-machine4",
+				"namespace": metav1.NamespaceDefault,
+				"labels": map[string]interface{}{
+					clusterv1.ClusterNameLabel: defaultCluster.Name,
+				},
+			},
+			"spec": map[string]interface{}{
+				"providerID": "test://id-4",
+			},
+		},
+	}
+
+	// infraMachine5 is a pre-existing infraMachine controlled by a Machine of another owner.
+	infraMachine5 := unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind":       "InfrastructureMachine",
+			"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+			"metadata": map[string]interface{}{
+				"name":      "infra-machine5",
+				"namespace": metav1.NamespaceDefault,
+				"labels": map[string]interface{}{
+					clusterv1.ClusterNameLabel: defaultCluster.Name,
+				},
+				"ownerReferences": []interface{}{
+					map[string]interface{}{
+						"apiVersion": clusterv1.GroupVersion.String(),
+						"kind":       "Machine",
+						"name":       "machine5",
+						"uid":        "machine5-uid",
+						"controller": true,
+					},
+				},
+			},
+			"spec": map[string]interface{}{
+				"providerID": "test://id-5",
+			},
+		},
+	}
+
+	// standaloneMachine3 is a pre-existing stand-alone Machine for infraMachine3.
+	standaloneMachine3 := clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "machine3",
+			Namespace: metav1.NamespaceDefault,
+			Labels: map[string]string{
+				clusterv1.ClusterNameLabel: defaultCluster.Name,
+			},
+		},
+		Spec: clusterv1.MachineSpec{
+			ClusterName: clusterName,
+			ProviderID:  pointer.String("test://id-3"),
+			InfrastructureRef: corev1.ObjectReference{
+				APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+				Kind:       "InfrastructureMachine",
+				Name:       "infra-machine3",
+				Namespace:  metav1.NamespaceDefault,
+			},
+		},
+	}
+
 	machine1 := clusterv1.Machine{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "machine1",
@@ -1178,6 +1313,7 @@ func TestReconcileMachinePoolMachines(t *testing.T) {
 		infraConfig                 map[string]interface{}
 		machines                    []clusterv1.Machine
 		infraMachines               []unstructured.Unstructured
+		otherInfraMachines          []unstructured.Unstructured
 		machinepool                 *expv1.MachinePool
 		expectError                 bool
 		supportsMachinePoolMachines bool
@@ -1258,6 +1394,129 @@ func TestReconcileMachinePoolMachines(t *testing.T) {
 			expectError:                 false,
 			supportsMachinePoolMachines: true,
 		},
+		{
+			name: "existing machines and infra machines with a provider ID in the providerIDList, should adopt them",
+			infraConfig: map[string]interface{}{
+				"kind":       "InfrastructureConfig",
+				"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+				"metadata": map[string]interface{}{
+					"name":      "infra-config1",
+					"namespace": metav1.NamespaceDefault,
+				},
+				"spec": map[string]interface{}{
+					"providerIDList": []interface{}{
+						"test://id-3",
+					},
+				},
+				"status": map[string]interface{}{
+					"ready":                     true,
+					"infrastructureMachineKind": "InfrastructureMachine",
+				},
+			},
+			machinepool: func() *expv1.MachinePool {
+				mp := defaultMachinePool.DeepCopy()
+				mp.Annotations = map[string]string{clusterv1.MachinePoolAdoptExistingMachinesAnnotation: ""}
+				return mp
+			}(),
+			machines: []clusterv1.Machine{
+				standaloneMachine3,
+			},
+			infraMachines: []unstructured.Unstructured{
+				infraMachine3,
+			},
+			// infraMachine4 is not adopted because its provider ID is not in the providerIDList.
+			otherInfraMachines: []unstructured.Unstructured{
+				infraMachine4,
+			},
+			expectError:                 false,
+			supportsMachinePoolMachines: true,
+		},
+		{
+			name: "infra machines with a provider ID in the providerIDList, should adopt them and create machinepool machines",
+			infraConfig: map[string]interface{}{
+				"kind":       "InfrastructureConfig",
+				"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+				"metadata": map[string]interface{}{
+					"name":      "infra-config1",
+					"namespace": metav1.NamespaceDefault,
+				},
+				"spec": map[string]interface{}{
+					"providerIDList": []interface{}{
+						"test://id-4",
+					},
+				},
+				"status": map[string]interface{}{
+					"ready":                     true,
+					"infrastructureMachineKind": "InfrastructureMachine",
+				},
+			},
+			machinepool: func() *expv1.MachinePool {
+				mp := defaultMachinePool.DeepCopy()
+				mp.Annotations = map[string]string{clusterv1.MachinePoolAdoptExistingMachinesAnnotation: ""}
+				return mp
+			}(),
+			infraMachines: []unstructured.Unstructured{
+				infraMachine1,
+				infraMachine4,
+			},
+			expectError:                 false,
+			supportsMachinePoolMachines: true,
+		},
+		{
+			name: "infra machines with a controller owner, should not adopt them",
+			infraConfig: map[string]interface{}{
+				"kind":       "InfrastructureConfig",
+				"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+				"metadata": map[string]interface{}{
+					"name":      "infra-config1",
+					"namespace": metav1.NamespaceDefault,
+				},
+				"spec": map[string]interface{}{
+					"providerIDList": []interface{}{
+						"test://id-5",
+					},
+				},
+				"status": map[string]interface{}{
+					"ready":                     true,
+					"infrastructureMachineKind": "InfrastructureMachine",
+				},
+			},
+			machinepool: func() *expv1.MachinePool {
+				mp := defaultMachinePool.DeepCopy()
+				mp.Annotations = map[string]string{clusterv1.MachinePoolAdoptExistingMachinesAnnotation: ""}
+				return mp
+			}(),
+			otherInfraMachines: []unstructured.Unstructured{
+				infraMachine5,
+			},
+			expectError:                 false,
+			supportsMachinePoolMachines: true,
+		},
+		{
+			name: "existing infra machines without the adopt annotation, nothing to adopt",
+			infraConfig: map[string]interface{}{
+				"kind":       "InfrastructureConfig",
+				"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+				"metadata": map[string]interface{}{
+					"name":      "infra-config1",
+					"namespace": metav1.NamespaceDefault,
+				},
+				"spec": map[string]interface{}{
+					"providerIDList": []interface{}{
+						"test://id-4",
+					},
+				},
+				"status": map[string]interface{}{
+					"ready":                     true,
+					"infrastructureMachineKind": "InfrastructureMachine",
+				},
+			},
+			otherInfraMachines: []unstructured.Unstructured{
+				infraMachine4,
+			},
+			expectError:                 false,
+			supportsMachinePoolMachines: true,
+		},
 		{
 			name: "machinepool does not support machinepool machines, nothing to do",
 			infraConfig: map[string]interface{}{
@@ -1307,6 +1566,10 @@ func TestReconcileMachinePoolMachines(t *testing.T) {
 				objs = append(objs, infraMachine.DeepCopy())
 			}
 
+			for _, infraMachine := range tc.otherInfraMachines {
+				objs = append(objs, infraMachine.DeepCopy())
+			}
+
 			for _, machine := range tc.machines {
 				objs = append(objs, machine.DeepCopy())
 			}
@@ -1315,7 +1578,7 @@ func TestReconcileMachinePoolMachines(t *testing.T) {
 				Client: fake.NewClientBuilder().WithObjects(objs...).Build(),
 			}
 
-			err := r.reconcileMachines(ctx, tc.machinepool, infraConfig)
+			err := r.reconcileMachines(ctx, &defaultCluster, tc.machinepool, infraConfig)
 
 			r.reconcilePhase(tc.machinepool)
 			if tc.expectError {
@@ -1826,3 +2089,78 @@ func TestReconcileMachinePoolScaleToFromZero(t *testing.T) {
 		g.Expect(env.Get(ctx, client.ObjectKeyFromObject(node), delNode)).To(Succeed())
 	})
 }
+
+func TestSyncMachinesMetadata(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      clusterName,
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: clusterv1.ClusterSpec{
+			Metadata: &clusterv1.ObjectMeta{
+				Labels: map[string]string{
+					"cluster-label":  "cluster-value",
+					"template-label": "cluster-value",
+				},
+				Annotations: map[string]string{
+					"cluster-annotation":  "cluster-value",
+					"template-annotation": "cluster-value",
+				},
+			},
+		},
+	}
+	mp := &expv1.MachinePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "machinepool-test",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: expv1.MachinePoolSpec{
+			ClusterName: clusterName,
+			Template: clusterv1.MachineTemplateSpec{
+				ObjectMeta: clusterv1.ObjectMeta{
+					Labels:      map[string]string{"template-label": "template-value"},
+					Annotations: map[string]string{"template-annotation": "template-value"},
+				},
+			},
+		},
+	}
+	// machine has been created before the Cluster metadata and the MachinePool template metadata were set.
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "machine1",
+			Namespace: metav1.NamespaceDefault,
+			Labels: map[string]string{
+				clusterv1.ClusterNameLabel:     clusterName,
+				clusterv1.MachinePoolNameLabel: mp.Name,
+				"other-label":                  "other-value",
+			},
+		},
+		Spec: clusterv1.MachineSpec{
+			ClusterName: clusterName,
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().WithObjects(machine).Build()
+	r := &MachinePoolReconciler{
+		Client: fakeClient,
+	}
+
+	g.Expect(r.syncMachinesMetadata(ctx, cluster, mp, []clusterv1.Machine{*machine})).To(Succeed())
+
+	// Labels and annotations from the MachinePool template take precedence over the Cluster metadata.
+	got := &clusterv1.Machine{}
+	g.Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(machine), got)).To(Succeed())
+	g.Expect(got.Labels).To(Equal(map[string]string{
+		clusterv1.ClusterNameLabel:     clusterName,
+		clusterv1.MachinePoolNameLabel: mp.Name,
+		"other-label":                  "other-value",
+		"cluster-label":                "cluster-value",
+		"template-label":               "template-value",
+	}))
+	g.Expect(got.Annotations).To(Equal(map[string]string{
+		"cluster-annotation":  "cluster-value",
+		"template-annotation": "template-value",
+	}))
+}
@@ -0,0 +1,305 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apiserver/pkg/storage/names"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/external"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+)
+
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinewarmpools;machinewarmpools/status,verbs=get;list;watch;update;patch
+
+// MachineWarmPoolReconciler reconciles a MachineWarmPool object.
+type MachineWarmPoolReconciler struct {
+	Client client.Client
+
+	// WatchFilterValue is the label value used to filter events prior to reconciliation.
+	WatchFilterValue string
+
+	externalTracker external.ObjectTracker
+}
+
+func (r *MachineWarmPoolReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	clusterToMachineWarmPools, err := util.ClusterToTypedObjectsMapper(mgr.GetClient(), &expv1.MachineWarmPoolList{}, mgr.GetScheme())
+	if err != nil {
+		return err
+	}
+
+	c, err := ctrl.NewControllerManagedBy(mgr).
+		For(&expv1.MachineWarmPool{}).
+		WithOptions(options).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
+		Watches(
+			&clusterv1.Cluster{},
+			handler.EnqueueRequestsFromMapFunc(clusterToMachineWarmPools),
+			builder.WithPredicates(
+				predicates.All(ctrl.LoggerFrom(ctx),
+					predicates.ClusterUnpaused(ctrl.LoggerFrom(ctx)),
+					predicates.ResourceHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue),
+				),
+			),
+		).
+		Build(r)
+	if err != nil {
+		return errors.Wrap(err, "failed setting up with a controller manager")
+	}
+
+	r.externalTracker = external.ObjectTracker{
+		Controller: c,
+		Cache:      mgr.GetCache(),
+	}
+	return nil
+}
+
+func (r *MachineWarmPoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	mwp := &expv1.MachineWarmPool{}
+	if err := r.Client.Get(ctx, req.NamespacedName, mwp); err != nil {
+		if apierrors.IsNotFound(err) {
+			// Object not found, return. The infrastructure machines in the pool are owned by the MachineWarmPool,
+			// so they are garbage collected.
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	log = log.WithValues("Cluster", klog.KRef(mwp.Namespace, mwp.Spec.ClusterName))
+	ctx = ctrl.LoggerInto(ctx, log)
+
+	// Return early if the object is being deleted; the infrastructure machines in the pool are garbage collected.
+	if !mwp.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	cluster, err := util.GetClusterByName(ctx, r.Client, mwp.Namespace, mwp.Spec.ClusterName)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to get cluster %q for MachineWarmPool %q in namespace %q",
+			mwp.Spec.ClusterName, mwp.Name, mwp.Namespace)
+	}
+
+	// Return early if the object or Cluster is paused.
+	if annotations.IsPaused(cluster, mwp) {
+		log.Info("Reconciliation is paused for this object")
+		return ctrl.Result{}, nil
+	}
+
+	// Initialize the patch helper.
+	patchHelper, err := patch.NewHelper(mwp, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	defer func() {
+		// Always update the readyCondition with the summary of the MachineWarmPool conditions.
+		conditions.SetSummary(mwp,
+			conditions.WithConditions(
+				expv1.MachinesPreProvisionedCondition,
+			),
+		)
+
+		// Always attempt to patch the object and status after each reconciliation.
+		// Patch ObservedGeneration only if the reconciliation completed successfully.
+		patchOpts := []patch.Option{
+			patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
+				clusterv1.ReadyCondition,
+				expv1.MachinesPreProvisionedCondition,
+			}},
+		}
+		if reterr == nil {
+			patchOpts = append(patchOpts, patch.WithStatusObservedGeneration{})
+		}
+		if err := patchHelper.Patch(ctx, mwp, patchOpts...); err != nil {
+			reterr = kerrors.NewAggregate([]error{reterr, err})
+		}
+	}()
+
+	// Reconcile labels.
+	if mwp.Labels == nil {
+		mwp.Labels = make(map[string]string)
+	}
+	mwp.Labels[clusterv1.ClusterNameLabel] = mwp.Spec.ClusterName
+
+	// Ensure the MachineWarmPool is owned by the Cluster it belongs to.
+	mwp.SetOwnerReferences(util.EnsureOwnerRef(mwp.GetOwnerReferences(), metav1.OwnerReference{
+		APIVersion: clusterv1.GroupVersion.String(),
+		Kind:       "Cluster",
+		Name:       cluster.Name,
+		UID:        cluster.UID,
+	}))
+
+	return ctrl.Result{}, r.reconcileMachines(ctx, cluster, mwp)
+}
+
+// reconcileMachines creates or deletes infrastructure machines so the pool has the desired number of replicas.
+func (r *MachineWarmPoolReconciler) reconcileMachines(ctx context.Context, cluster *clusterv1.Cluster, mwp *expv1.MachineWarmPool) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	// Do not create new infrastructure machines while the Cluster is being deleted.
+	if !cluster.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	infraMachines, err := r.getInfraMachines(ctx, mwp)
+	if err != nil {
+		return err
+	}
+
+	desiredReplicas := int(pointer.Int32Deref(mwp.Spec.Replicas, 1))
+	var errs []error
+	switch diff := desiredReplicas - len(infraMachines); {
+	case diff > 0:
+		log.Info(fmt.Sprintf("MachineWarmPool is scaling up to %d replicas by creating %d infrastructure machines", desiredReplicas, diff))
+		for i := 0; i < diff; i++ {
+			ref, err := external.CreateFromTemplate(ctx, &external.CreateFromTemplateInput{
+				Client:      r.Client,
+				TemplateRef: &mwp.Spec.Template.InfrastructureRef,
+				Namespace:   mwp.Namespace,
+				ClusterName: mwp.Spec.ClusterName,
+				Name:        names.SimpleNameGenerator.GenerateName(mwp.Name + "-"),
+				Labels: map[string]string{
+					expv1.MachineWarmPoolNameLabel: mwp.Name,
+				},
+				OwnerRef: &metav1.OwnerReference{
+					APIVersion: expv1.GroupVersion.String(),
+					Kind:       "MachineWarmPool",
+					Name:       mwp.Name,
+					UID:        mwp.UID,
+				},
+			})
+			if err != nil {
+				conditions.MarkFalse(mwp, expv1.MachinesPreProvisionedCondition, expv1.MachinesCreationFailedReason, clusterv1.ConditionSeverityError, err.Error())
+				errs = append(errs, errors.Wrapf(err, "failed to clone infrastructure machine from %s %s",
+					mwp.Spec.Template.InfrastructureRef.Kind,
+					klog.KRef(mwp.Spec.Template.InfrastructureRef.Namespace, mwp.Spec.Template.InfrastructureRef.Name)))
+				break
+			}
+			log.Info("Created infrastructure machine", ref.Kind, klog.KRef(ref.Namespace, ref.Name))
+		}
+	case diff < 0:
+		log.Info(fmt.Sprintf("MachineWarmPool is scaling down to %d replicas by deleting %d infrastructure machines", desiredReplicas, -diff))
+		for _, infraMachine := range infraMachinesToDelete(infraMachines, -diff) {
+			if err := r.Client.Delete(ctx, infraMachine); err != nil && !apierrors.IsNotFound(err) {
+				errs = append(errs, errors.Wrapf(err, "failed to delete %s %s", infraMachine.GetKind(), klog.KObj(infraMachine)))
+				continue
+			}
+			log.Info("Deleted infrastructure machine", infraMachine.GetKind(), klog.KObj(infraMachine))
+		}
+	}
+	if len(errs) > 0 {
+		return kerrors.NewAggregate(errs)
+	}
+
+	// Compute the status with the infrastructure machines currently in the pool.
+	infraMachines, err = r.getInfraMachines(ctx, mwp)
+	if err != nil {
+		return err
+	}
+	readyReplicas := 0
+	for _, infraMachine := range infraMachines {
+		if conditions.IsTrue(conditions.UnstructuredGetter(infraMachine), expv1.PreProvisionedCondition) {
+			readyReplicas++
+		}
+	}
+	mwp.Status.Replicas = int32(len(infraMachines))
+	mwp.Status.ReadyReplicas = int32(readyReplicas)
+
+	if readyReplicas < desiredReplicas {
+		conditions.MarkFalse(mwp, expv1.MachinesPreProvisionedCondition, expv1.WaitingForMachinesPreProvisionedReason, clusterv1.ConditionSeverityInfo,
+			"%d of %d machines pre-provisioned", readyReplicas, desiredReplicas)
+		return nil
+	}
+	conditions.MarkTrue(mwp, expv1.MachinesPreProvisionedCondition)
+	return nil
+}
+
+// getInfraMachines returns the infrastructure machines in the pool which are not being deleted.
+func (r *MachineWarmPoolReconciler) getInfraMachines(ctx context.Context, mwp *expv1.MachineWarmPool) ([]*unstructured.Unstructured, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	// The infrastructure machines have the kind of the infrastructure machine template without the Template suffix.
+	gvk := mwp.Spec.Template.InfrastructureRef.GroupVersionKind()
+	gvk.Kind = strings.TrimSuffix(gvk.Kind, clusterv1.TemplateSuffix)
+
+	// Ensure we add a watch to the infrastructure machines, if there isn't one already.
+	sampleInfraMachine := &unstructured.Unstructured{}
+	sampleInfraMachine.SetGroupVersionKind(gvk)
+	if err := r.externalTracker.Watch(log, sampleInfraMachine, handler.EnqueueRequestForOwner(r.Client.Scheme(), r.Client.RESTMapper(), &expv1.MachineWarmPool{})); err != nil {
+		return nil, err
+	}
+
+	infraMachineList := &unstructured.UnstructuredList{}
+	infraMachineList.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err := r.Client.List(ctx, infraMachineList, client.InNamespace(mwp.Namespace), client.MatchingLabels{expv1.MachineWarmPoolNameLabel: mwp.Name}); err != nil {
+		return nil, errors.Wrapf(err, "failed to list infrastructure machines for MachineWarmPool %s", klog.KObj(mwp))
+	}
+
+	infraMachines := make([]*unstructured.Unstructured, 0, len(infraMachineList.Items))
+	for i := range infraMachineList.Items {
+		infraMachine := &infraMachineList.Items[i]
+		if !infraMachine.GetDeletionTimestamp().IsZero() || !util.IsOwnedByObject(infraMachine, mwp) {
+			continue
+		}
+		infraMachines = append(infraMachines, infraMachine)
+	}
+	return infraMachines, nil
+}
+
+// infraMachinesToDelete returns the infrastructure machines to delete when scaling down the pool,
+// giving priority to the machines which are not yet pre-provisioned, and then to the newest ones.
+func infraMachinesToDelete(infraMachines []*unstructured.Unstructured, diff int) []*unstructured.Unstructured {
+	if diff >= len(infraMachines) {
+		return infraMachines
+	}
+	sorted := make([]*unstructured.Unstructured, len(infraMachines))
+	copy(sorted, infraMachines)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		iReady := conditions.IsTrue(conditions.UnstructuredGetter(sorted[i]), expv1.PreProvisionedCondition)
+		jReady := conditions.IsTrue(conditions.UnstructuredGetter(sorted[j]), expv1.PreProvisionedCondition)
+		if iReady != jReady {
+			return !iReady
+		}
+		iCreationTimestamp, jCreationTimestamp := sorted[i].GetCreationTimestamp(), sorted[j].GetCreationTimestamp()
+		return jCreationTimestamp.Before(&iCreationTimestamp)
+	})
+	return sorted[:diff]
+}
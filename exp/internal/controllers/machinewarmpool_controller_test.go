@@ -0,0 +1,151 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/test/builder"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestReconcileMachineWarmPool(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	g.Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(expv1.AddToScheme(scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: metav1.NamespaceDefault,
+		},
+	}
+	infraMachineTemplate := builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "infra-template").Build()
+	mwp := &expv1.MachineWarmPool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-pool",
+			Namespace: metav1.NamespaceDefault,
+			UID:       "test-pool-uid",
+		},
+		Spec: expv1.MachineWarmPoolSpec{
+			ClusterName: cluster.Name,
+			Replicas:    pointer.Int32(3),
+			Template: expv1.MachineWarmPoolTemplate{
+				Version: pointer.String("v1.28.0"),
+				InfrastructureRef: corev1.ObjectReference{
+					APIVersion: infraMachineTemplate.GetAPIVersion(),
+					Kind:       infraMachineTemplate.GetKind(),
+					Name:       infraMachineTemplate.GetName(),
+					Namespace:  infraMachineTemplate.GetNamespace(),
+				},
+			},
+		},
+	}
+
+	// An infrastructure machine already in the pool, pre-provisioned.
+	preProvisioned := &unstructured.Unstructured{}
+	preProvisioned.SetAPIVersion(builder.InfrastructureGroupVersion.String())
+	preProvisioned.SetKind(builder.GenericInfrastructureMachineKind)
+	preProvisioned.SetName("test-pool-pre-provisioned")
+	preProvisioned.SetNamespace(metav1.NamespaceDefault)
+	preProvisioned.SetLabels(map[string]string{
+		clusterv1.ClusterNameLabel:     cluster.Name,
+		expv1.MachineWarmPoolNameLabel: mwp.Name,
+	})
+	preProvisioned.SetOwnerReferences([]metav1.OwnerReference{{
+		APIVersion: expv1.GroupVersion.String(),
+		Kind:       "MachineWarmPool",
+		Name:       mwp.Name,
+		UID:        mwp.UID,
+	}})
+	conditions.MarkTrue(conditions.UnstructuredSetter(preProvisioned), expv1.PreProvisionedCondition)
+
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(cluster, infraMachineTemplate, mwp, preProvisioned).
+		WithStatusSubresource(&expv1.MachineWarmPool{}).
+		Build()
+	r := &MachineWarmPoolReconciler{Client: c}
+
+	listInfraMachines := func(g *WithT) []unstructured.Unstructured {
+		infraMachineList := &unstructured.UnstructuredList{}
+		infraMachineList.SetAPIVersion(builder.InfrastructureGroupVersion.String())
+		infraMachineList.SetKind(builder.GenericInfrastructureMachineKind + "List")
+		g.Expect(c.List(ctx, infraMachineList, client.MatchingLabels{expv1.MachineWarmPoolNameLabel: mwp.Name})).To(Succeed())
+		return infraMachineList.Items
+	}
+
+	t.Run("creates infrastructure machines to reach the desired replicas", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mwp)})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		infraMachines := listInfraMachines(g)
+		g.Expect(infraMachines).To(HaveLen(3))
+		for _, infraMachine := range infraMachines {
+			g.Expect(infraMachine.GetLabels()).To(HaveKeyWithValue(clusterv1.ClusterNameLabel, cluster.Name))
+			g.Expect(infraMachine.GetOwnerReferences()).To(HaveLen(1))
+			g.Expect(infraMachine.GetOwnerReferences()[0].Name).To(Equal(mwp.Name))
+		}
+
+		got := &expv1.MachineWarmPool{}
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(mwp), got)).To(Succeed())
+		g.Expect(got.Labels).To(HaveKeyWithValue(clusterv1.ClusterNameLabel, cluster.Name))
+		g.Expect(got.Status.Replicas).To(Equal(int32(3)))
+		g.Expect(got.Status.ReadyReplicas).To(Equal(int32(1)))
+		g.Expect(conditions.IsFalse(got, expv1.MachinesPreProvisionedCondition)).To(BeTrue())
+		g.Expect(conditions.GetReason(got, expv1.MachinesPreProvisionedCondition)).To(Equal(expv1.WaitingForMachinesPreProvisionedReason))
+	})
+
+	t.Run("deletes the infrastructure machines not yet pre-provisioned first when scaling down", func(t *testing.T) {
+		g := NewWithT(t)
+
+		got := &expv1.MachineWarmPool{}
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(mwp), got)).To(Succeed())
+		got.Spec.Replicas = pointer.Int32(1)
+		g.Expect(c.Update(ctx, got)).To(Succeed())
+
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mwp)})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		infraMachines := listInfraMachines(g)
+		g.Expect(infraMachines).To(HaveLen(1))
+		g.Expect(infraMachines[0].GetName()).To(Equal(preProvisioned.GetName()))
+
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(mwp), got)).To(Succeed())
+		g.Expect(got.Status.Replicas).To(Equal(int32(1)))
+		g.Expect(got.Status.ReadyReplicas).To(Equal(int32(1)))
+		g.Expect(conditions.IsTrue(got, expv1.MachinesPreProvisionedCondition)).To(BeTrue())
+	})
+}
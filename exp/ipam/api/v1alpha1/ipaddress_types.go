@@ -21,6 +21,12 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+const (
+	// RetainedForMachineLabel is set on IPAddresses that were retained after their IPAddressClaim was deleted.
+	// Its value is the name of the Machine the address is reserved for.
+	RetainedForMachineLabel = "ipam.cluster.x-k8s.io/retained-for-machine"
+)
+
 // IPAddressSpec is the desired state of an IPAddress.
 type IPAddressSpec struct {
 	// ClaimRef is a reference to the claim this IPAddress was created for.
@@ -23,10 +23,41 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
+const (
+	// ReleaseAddressFinalizer is added to an IPAddressClaim by the IPAM provider serving its pool, to release or
+	// retain the allocated IPAddress according to the claim's ReleasePolicy before the claim is removed.
+	ReleaseAddressFinalizer = "ipam.cluster.x-k8s.io/ReleaseAddress"
+
+	// ProtectAddressFinalizer is added to an IPAddress by the IPAM provider serving its pool, to prevent it from
+	// being deleted while it is still allocated.
+	ProtectAddressFinalizer = "ipam.cluster.x-k8s.io/ProtectAddress"
+)
+
+// IPAddressClaimReleasePolicy defines what happens to the IPAddress allocated for an IPAddressClaim
+// when the claim is deleted.
+type IPAddressClaimReleasePolicy string
+
+const (
+	// ReleaseOnDeleteReleasePolicy releases the IPAddress back to the pool when the IPAddressClaim is deleted.
+	ReleaseOnDeleteReleasePolicy IPAddressClaimReleasePolicy = "ReleaseOnDelete"
+
+	// RetainReleasePolicy keeps the IPAddress when the IPAddressClaim is deleted, and assigns it again to
+	// the next IPAddressClaim for the same Machine and pool.
+	RetainReleasePolicy IPAddressClaimReleasePolicy = "Retain"
+)
+
 // IPAddressClaimSpec is the desired state of an IPAddressClaim.
 type IPAddressClaimSpec struct {
 	// PoolRef is a reference to the pool from which an IP address should be created.
 	PoolRef corev1.TypedLocalObjectReference `json:"poolRef"`
+
+	// ReleasePolicy defines what happens to the allocated IPAddress when this claim is deleted.
+	// ReleaseOnDelete returns the address to the pool, Retain keeps it reserved for the Machine
+	// owning this claim, so it is assigned again to a claim for a Machine with the same name.
+	// Defaults to ReleaseOnDelete.
+	// +kubebuilder:validation:Enum=ReleaseOnDelete;Retain
+	// +optional
+	ReleasePolicy IPAddressClaimReleasePolicy `json:"releasePolicy,omitempty"`
 }
 
 // IPAddressClaimStatus is the observed status of a IPAddressClaim.
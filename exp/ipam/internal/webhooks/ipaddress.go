@@ -24,6 +24,7 @@ import (
 
 	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -32,7 +33,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1alpha1"
+	"sigs.k8s.io/cluster-api/util"
 )
 
 // SetupWebhookWithManager sets up IPAddress webhooks.
@@ -63,7 +66,7 @@ func (webhook *IPAddress) ValidateCreate(ctx context.Context, obj runtime.Object
 }
 
 // ValidateUpdate implements webhook.CustomValidator.
-func (webhook *IPAddress) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+func (webhook *IPAddress) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
 	oldIP, ok := oldObj.(*ipamv1.IPAddress)
 	if !ok {
 		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected an IPAddress but got a %T", oldObj))
@@ -73,12 +76,60 @@ func (webhook *IPAddress) ValidateUpdate(_ context.Context, oldObj, newObj runti
 		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected an IPAddress but got a %T", newObj))
 	}
 
-	if !reflect.DeepEqual(oldIP.Spec, newIP.Spec) {
+	oldSpec := oldIP.Spec.DeepCopy()
+	if oldSpec.ClaimRef != newIP.Spec.ClaimRef {
+		if err := webhook.validateReassignment(ctx, oldIP, newIP); err != nil {
+			return nil, err
+		}
+		oldSpec.ClaimRef = newIP.Spec.ClaimRef
+	}
+
+	if !reflect.DeepEqual(*oldSpec, newIP.Spec) {
 		return nil, field.Forbidden(field.NewPath("spec"), "the spec of IPAddress is immutable")
 	}
 	return nil, nil
 }
 
+// validateReassignment checks that an IPAddress is only moved to a new claim when it has been retained for a Machine.
+// The retained label alone can be set by anyone, so the reassignment is only allowed if the claim the address was
+// bound to is gone or being deleted, the label is removed in the same update, and the new claim is owned by the Machine
+// the address has been retained for.
+func (webhook *IPAddress) validateReassignment(ctx context.Context, oldIP, newIP *ipamv1.IPAddress) error {
+	claimRefPath := field.NewPath("spec", "claimRef")
+	forbidden := field.Forbidden(claimRefPath, "the claim reference of IPAddress is immutable unless the address has been retained for a Machine")
+
+	machineName, retained := oldIP.Labels[ipamv1.RetainedForMachineLabel]
+	if !retained || machineName == "" {
+		return forbidden
+	}
+	if _, ok := newIP.Labels[ipamv1.RetainedForMachineLabel]; ok {
+		return field.Forbidden(claimRefPath, fmt.Sprintf("the %s label has to be removed when assigning a retained IPAddress to a new claim", ipamv1.RetainedForMachineLabel))
+	}
+
+	oldClaim := &ipamv1.IPAddressClaim{}
+	err := webhook.Client.Get(ctx, types.NamespacedName{Name: oldIP.Spec.ClaimRef.Name, Namespace: oldIP.Namespace}, oldClaim)
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return field.InternalError(claimRefPath, errors.Wrap(err, "failed to fetch the claim the IPAddress was bound to"))
+	case oldClaim.DeletionTimestamp.IsZero():
+		return field.Forbidden(claimRefPath, "the claim the IPAddress is bound to still exists")
+	}
+
+	newClaim := &ipamv1.IPAddressClaim{}
+	if err := webhook.Client.Get(ctx, types.NamespacedName{Name: newIP.Spec.ClaimRef.Name, Namespace: newIP.Namespace}, newClaim); err != nil {
+		if apierrors.IsNotFound(err) {
+			return field.Invalid(claimRefPath, newIP.Spec.ClaimRef.Name, "the new claim does not exist")
+		}
+		return field.InternalError(claimRefPath, errors.Wrap(err, "failed to fetch the new claim"))
+	}
+	machineRef := metav1.OwnerReference{APIVersion: clusterv1.GroupVersion.String(), Kind: "Machine", Name: machineName}
+	if !util.HasOwnerRef(newClaim.GetOwnerReferences(), machineRef) {
+		return field.Invalid(claimRefPath, newIP.Spec.ClaimRef.Name, fmt.Sprintf("the new claim is not owned by Machine %s the IPAddress has been retained for", machineName))
+	}
+	return nil
+}
+
 // ValidateDelete implements webhook.CustomValidator.
 func (webhook *IPAddress) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
 	return nil, nil
@@ -19,6 +19,7 @@ package webhooks
 import (
 	"context"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
@@ -28,6 +29,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1alpha1"
 )
 
@@ -190,6 +192,24 @@ func TestIPAddressValidateUpdate(t *testing.T) {
 		fn(&addr)
 		return addr
 	}
+	getClaim := func(name, machineName string, deleting bool) *ipamv1.IPAddressClaim {
+		claim := &ipamv1.IPAddressClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: clusterv1.GroupVersion.String(),
+					Kind:       "Machine",
+					Name:       machineName,
+				}},
+			},
+		}
+		if deleting {
+			claim.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+			claim.Finalizers = []string{ipamv1.ReleaseAddressFinalizer}
+		}
+		return claim
+	}
 
 	tests := []struct {
 		name      string
@@ -212,6 +232,99 @@ func TestIPAddressValidateUpdate(t *testing.T) {
 			}),
 			expectErr: true,
 		},
+		{
+			name:  "should reject objects with different claim reference",
+			oldIP: getAddress(func(addr *ipamv1.IPAddress) {}),
+			newIP: getAddress(func(addr *ipamv1.IPAddress) {
+				addr.Spec.ClaimRef.Name = "new-claim"
+			}),
+			expectErr: true,
+		},
+		{
+			name: "should accept retained objects with different claim reference",
+			oldIP: getAddress(func(addr *ipamv1.IPAddress) {
+				addr.Labels = map[string]string{ipamv1.RetainedForMachineLabel: "machine"}
+				addr.Spec.ClaimRef.Name = "old-claim"
+			}),
+			newIP: getAddress(func(addr *ipamv1.IPAddress) {
+				addr.Spec.ClaimRef.Name = "new-claim"
+			}),
+			extraObjs: []client.Object{getClaim("new-claim", "machine", false)},
+			expectErr: false,
+		},
+		{
+			name: "should accept retained objects with different claim reference if the old claim is being deleted",
+			oldIP: getAddress(func(addr *ipamv1.IPAddress) {
+				addr.Labels = map[string]string{ipamv1.RetainedForMachineLabel: "machine"}
+				addr.Spec.ClaimRef.Name = "old-claim"
+			}),
+			newIP: getAddress(func(addr *ipamv1.IPAddress) {
+				addr.Spec.ClaimRef.Name = "new-claim"
+			}),
+			extraObjs: []client.Object{getClaim("old-claim", "machine", true), getClaim("new-claim", "machine", false)},
+			expectErr: false,
+		},
+		{
+			name: "should reject retained objects if the old claim still exists",
+			oldIP: getAddress(func(addr *ipamv1.IPAddress) {
+				addr.Labels = map[string]string{ipamv1.RetainedForMachineLabel: "machine"}
+				addr.Spec.ClaimRef.Name = "old-claim"
+			}),
+			newIP: getAddress(func(addr *ipamv1.IPAddress) {
+				addr.Spec.ClaimRef.Name = "new-claim"
+			}),
+			extraObjs: []client.Object{getClaim("old-claim", "machine", false), getClaim("new-claim", "machine", false)},
+			expectErr: true,
+		},
+		{
+			name: "should reject retained objects if the retained label is not removed",
+			oldIP: getAddress(func(addr *ipamv1.IPAddress) {
+				addr.Labels = map[string]string{ipamv1.RetainedForMachineLabel: "machine"}
+				addr.Spec.ClaimRef.Name = "old-claim"
+			}),
+			newIP: getAddress(func(addr *ipamv1.IPAddress) {
+				addr.Labels = map[string]string{ipamv1.RetainedForMachineLabel: "machine"}
+				addr.Spec.ClaimRef.Name = "new-claim"
+			}),
+			extraObjs: []client.Object{getClaim("new-claim", "machine", false)},
+			expectErr: true,
+		},
+		{
+			name: "should reject retained objects if the new claim does not exist",
+			oldIP: getAddress(func(addr *ipamv1.IPAddress) {
+				addr.Labels = map[string]string{ipamv1.RetainedForMachineLabel: "machine"}
+				addr.Spec.ClaimRef.Name = "old-claim"
+			}),
+			newIP: getAddress(func(addr *ipamv1.IPAddress) {
+				addr.Spec.ClaimRef.Name = "new-claim"
+			}),
+			expectErr: true,
+		},
+		{
+			name: "should reject retained objects if the new claim is owned by a different Machine",
+			oldIP: getAddress(func(addr *ipamv1.IPAddress) {
+				addr.Labels = map[string]string{ipamv1.RetainedForMachineLabel: "machine"}
+				addr.Spec.ClaimRef.Name = "old-claim"
+			}),
+			newIP: getAddress(func(addr *ipamv1.IPAddress) {
+				addr.Spec.ClaimRef.Name = "new-claim"
+			}),
+			extraObjs: []client.Object{getClaim("new-claim", "other-machine", false)},
+			expectErr: true,
+		},
+		{
+			name: "should reject retained objects with different address",
+			oldIP: getAddress(func(addr *ipamv1.IPAddress) {
+				addr.Labels = map[string]string{ipamv1.RetainedForMachineLabel: "machine"}
+				addr.Spec.ClaimRef.Name = "old-claim"
+			}),
+			newIP: getAddress(func(addr *ipamv1.IPAddress) {
+				addr.Spec.ClaimRef.Name = "new-claim"
+				addr.Spec.Address = "10.0.0.2"
+			}),
+			extraObjs: []client.Object{getClaim("new-claim", "machine", false)},
+			expectErr: true,
+		},
 	}
 
 	for _, tt := range tests {
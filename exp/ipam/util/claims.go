@@ -0,0 +1,217 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package util implements helpers for IPAM providers to handle the lifecycle of IPAddressClaims and IPAddresses.
+package util
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1alpha1"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/patch"
+)
+
+// ReleasePolicy returns the ReleasePolicy of an IPAddressClaim, defaulting to ReleaseOnDelete.
+func ReleasePolicy(claim *ipamv1.IPAddressClaim) ipamv1.IPAddressClaimReleasePolicy {
+	if claim.Spec.ReleasePolicy == "" {
+		return ipamv1.ReleaseOnDeleteReleasePolicy
+	}
+	return claim.Spec.ReleasePolicy
+}
+
+// OwnerMachineName returns the name of the Machine owning an IPAddressClaim.
+// It returns false if the claim is not owned by a Machine.
+func OwnerMachineName(claim *ipamv1.IPAddressClaim) (string, bool) {
+	for _, ref := range claim.GetOwnerReferences() {
+		if ref.Kind != "Machine" {
+			continue
+		}
+		gv, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err != nil {
+			continue
+		}
+		if gv.Group == clusterv1.GroupVersion.Group {
+			return ref.Name, true
+		}
+	}
+	return "", false
+}
+
+// EnsureFinalizers adds the ReleaseAddressFinalizer to the claim and the ProtectAddressFinalizer to the address
+// allocated for it. It returns true if any of the objects has been changed and has to be patched.
+func EnsureFinalizers(claim *ipamv1.IPAddressClaim, address *ipamv1.IPAddress) bool {
+	changed := controllerutil.AddFinalizer(claim, ipamv1.ReleaseAddressFinalizer)
+	if address != nil && controllerutil.AddFinalizer(address, ipamv1.ProtectAddressFinalizer) {
+		changed = true
+	}
+	return changed
+}
+
+// FindRetainedAddress returns an IPAddress from the pool referenced by the claim that has been retained for
+// the Machine owning the claim, or nil if there is none.
+// Addresses previously allocated for a claim with the same name are preferred.
+func FindRetainedAddress(ctx context.Context, c client.Reader, claim *ipamv1.IPAddressClaim) (*ipamv1.IPAddress, error) {
+	machineName, ok := OwnerMachineName(claim)
+	if !ok {
+		return nil, nil
+	}
+
+	addresses := &ipamv1.IPAddressList{}
+	if err := c.List(ctx, addresses,
+		client.InNamespace(claim.Namespace),
+		client.MatchingLabels{ipamv1.RetainedForMachineLabel: machineName},
+	); err != nil {
+		return nil, errors.Wrapf(err, "failed to list IPAddresses retained for Machine %s", klog.KRef(claim.Namespace, machineName))
+	}
+
+	var retained *ipamv1.IPAddress
+	for i := range addresses.Items {
+		address := &addresses.Items[i]
+		if !address.DeletionTimestamp.IsZero() || !samePool(address.Spec.PoolRef, claim.Spec.PoolRef) {
+			continue
+		}
+		if address.Spec.ClaimRef.Name == claim.Name {
+			return address, nil
+		}
+		if retained == nil || address.Name < retained.Name {
+			retained = address
+		}
+	}
+	return retained, nil
+}
+
+// AssignRetainedAddress assigns an IPAddress returned by FindRetainedAddress to the claim, and sets the
+// AddressRef of the claim accordingly. The claim becomes the controller of the address; the caller is
+// responsible for patching the claim.
+func AssignRetainedAddress(ctx context.Context, c client.Client, claim *ipamv1.IPAddressClaim, address *ipamv1.IPAddress) error {
+	patchHelper, err := patch.NewHelper(address, c)
+	if err != nil {
+		return err
+	}
+
+	delete(address.Labels, ipamv1.RetainedForMachineLabel)
+	address.Spec.ClaimRef = corev1.LocalObjectReference{Name: claim.Name}
+	if err := controllerutil.SetControllerReference(claim, address, c.Scheme()); err != nil {
+		return errors.Wrapf(err, "failed to set controller reference on IPAddress %s", klog.KObj(address))
+	}
+	controllerutil.AddFinalizer(address, ipamv1.ProtectAddressFinalizer)
+
+	if err := patchHelper.Patch(ctx, address); err != nil {
+		return errors.Wrapf(err, "failed to assign IPAddress %s to IPAddressClaim %s", klog.KObj(address), klog.KObj(claim))
+	}
+
+	claim.Status.AddressRef = corev1.LocalObjectReference{Name: address.Name}
+	return nil
+}
+
+// ReleaseClaim handles the deletion of an IPAddressClaim according to its ReleasePolicy.
+// With ReleaseOnDelete, or when the claim is not owned by a Machine, the allocated IPAddress is deleted.
+// With Retain, the IPAddress is detached from the claim and reserved for the Machine owning it.
+// Afterwards the ReleaseAddressFinalizer is removed from the claim; the caller is responsible for patching the claim.
+func ReleaseClaim(ctx context.Context, c client.Client, claim *ipamv1.IPAddressClaim) error {
+	if claim.Status.AddressRef.Name == "" {
+		controllerutil.RemoveFinalizer(claim, ipamv1.ReleaseAddressFinalizer)
+		return nil
+	}
+
+	address := &ipamv1.IPAddress{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: claim.Namespace, Name: claim.Status.AddressRef.Name}, address); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to get IPAddress for IPAddressClaim %s", klog.KObj(claim))
+		}
+		controllerutil.RemoveFinalizer(claim, ipamv1.ReleaseAddressFinalizer)
+		return nil
+	}
+
+	// Only touch the address if it still belongs to this claim, it could have been reassigned already.
+	if address.Spec.ClaimRef.Name == claim.Name && !isRetained(address) {
+		machineName, ok := OwnerMachineName(claim)
+		if ReleasePolicy(claim) == ipamv1.RetainReleasePolicy && ok {
+			if err := retainAddress(ctx, c, claim, address, machineName); err != nil {
+				return err
+			}
+		} else if err := ReleaseAddress(ctx, c, address); err != nil {
+			return err
+		}
+	}
+
+	controllerutil.RemoveFinalizer(claim, ipamv1.ReleaseAddressFinalizer)
+	return nil
+}
+
+// ReleaseAddress removes the ProtectAddressFinalizer from an IPAddress and deletes it, returning the address to its pool.
+// It can also be used to release addresses which have been retained but are not needed anymore.
+func ReleaseAddress(ctx context.Context, c client.Client, address *ipamv1.IPAddress) error {
+	patchHelper, err := patch.NewHelper(address, c)
+	if err != nil {
+		return err
+	}
+	controllerutil.RemoveFinalizer(address, ipamv1.ProtectAddressFinalizer)
+	if err := patchHelper.Patch(ctx, address); err != nil {
+		return errors.Wrapf(err, "failed to remove finalizer from IPAddress %s", klog.KObj(address))
+	}
+
+	if err := c.Delete(ctx, address); err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete IPAddress %s", klog.KObj(address))
+	}
+	return nil
+}
+
+// retainAddress detaches the IPAddress from the claim, so it is not garbage collected together with the claim,
+// and reserves it for the Machine with the given name.
+func retainAddress(ctx context.Context, c client.Client, claim *ipamv1.IPAddressClaim, address *ipamv1.IPAddress, machineName string) error {
+	patchHelper, err := patch.NewHelper(address, c)
+	if err != nil {
+		return err
+	}
+
+	for _, ref := range address.GetOwnerReferences() {
+		if ref.UID == claim.UID {
+			address.OwnerReferences = util.RemoveOwnerRef(address.OwnerReferences, ref)
+		}
+	}
+	if address.Labels == nil {
+		address.Labels = map[string]string{}
+	}
+	address.Labels[ipamv1.RetainedForMachineLabel] = machineName
+
+	if err := patchHelper.Patch(ctx, address); err != nil {
+		return errors.Wrapf(err, "failed to retain IPAddress %s for Machine %s", klog.KObj(address), klog.KRef(claim.Namespace, machineName))
+	}
+	return nil
+}
+
+func isRetained(address *ipamv1.IPAddress) bool {
+	_, ok := address.Labels[ipamv1.RetainedForMachineLabel]
+	return ok
+}
+
+func samePool(a, b corev1.TypedLocalObjectReference) bool {
+	return pointer.StringDeref(a.APIGroup, "") == pointer.StringDeref(b.APIGroup, "") &&
+		a.Kind == b.Kind &&
+		a.Name == b.Name
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1alpha1"
+)
+
+var poolRef = corev1.TypedLocalObjectReference{
+	APIGroup: pointer.String("ipam.cluster.x-k8s.io"),
+	Kind:     "InClusterIPPool",
+	Name:     "pool",
+}
+
+func newClaim(name, machineName string, policy ipamv1.IPAddressClaimReleasePolicy) *ipamv1.IPAddressClaim {
+	claim := &ipamv1.IPAddressClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  metav1.NamespaceDefault,
+			Name:       name,
+			UID:        "claim-uid",
+			Finalizers: []string{ipamv1.ReleaseAddressFinalizer},
+		},
+		Spec: ipamv1.IPAddressClaimSpec{
+			PoolRef:       poolRef,
+			ReleasePolicy: policy,
+		},
+	}
+	if machineName != "" {
+		claim.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "Machine",
+			Name:       machineName,
+			UID:        "machine-uid",
+		}}
+	}
+	return claim
+}
+
+func newAddress(name, claimName string, labels map[string]string) *ipamv1.IPAddress {
+	return &ipamv1.IPAddress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  metav1.NamespaceDefault,
+			Name:       name,
+			Labels:     labels,
+			Finalizers: []string{ipamv1.ProtectAddressFinalizer},
+		},
+		Spec: ipamv1.IPAddressSpec{
+			ClaimRef: corev1.LocalObjectReference{Name: claimName},
+			PoolRef:  poolRef,
+			Address:  "10.0.0.1",
+			Prefix:   24,
+		},
+	}
+}
+
+func fakeClient(g *WithT, objs ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	g.Expect(ipamv1.AddToScheme(scheme)).To(Succeed())
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+func TestFindRetainedAddress(t *testing.T) {
+	retainedForMachine := map[string]string{ipamv1.RetainedForMachineLabel: "machine"}
+	otherPool := newAddress("other-pool", "claim", retainedForMachine)
+	otherPool.Spec.PoolRef.Name = "other-pool"
+
+	tests := []struct {
+		name    string
+		claim   *ipamv1.IPAddressClaim
+		objs    []client.Object
+		wantErr bool
+		want    string
+	}{
+		{
+			name:  "no address for claims which are not owned by a Machine",
+			claim: newClaim("claim", "", ipamv1.RetainReleasePolicy),
+			objs:  []client.Object{newAddress("address", "claim", retainedForMachine)},
+			want:  "",
+		},
+		{
+			name:  "no address if none has been retained for the Machine",
+			claim: newClaim("claim", "machine", ipamv1.RetainReleasePolicy),
+			objs: []client.Object{
+				newAddress("address", "claim", nil),
+				newAddress("other-machine", "claim", map[string]string{ipamv1.RetainedForMachineLabel: "other-machine"}),
+				otherPool,
+			},
+			want: "",
+		},
+		{
+			name:  "address retained for a claim with the same name is preferred",
+			claim: newClaim("claim", "machine", ipamv1.RetainReleasePolicy),
+			objs: []client.Object{
+				newAddress("address-a", "other-claim", retainedForMachine),
+				newAddress("address-b", "claim", retainedForMachine),
+			},
+			want: "address-b",
+		},
+		{
+			name:  "any address retained for the Machine from the same pool",
+			claim: newClaim("claim", "machine", ipamv1.RetainReleasePolicy),
+			objs: []client.Object{
+				newAddress("address-b", "other-claim", retainedForMachine),
+				newAddress("address-a", "other-claim", retainedForMachine),
+			},
+			want: "address-a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			address, err := FindRetainedAddress(context.Background(), fakeClient(g, tt.objs...), tt.claim)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			if tt.want == "" {
+				g.Expect(address).To(BeNil())
+				return
+			}
+			g.Expect(address).ToNot(BeNil())
+			g.Expect(address.Name).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestAssignRetainedAddress(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	claim := newClaim("new-claim", "machine", ipamv1.RetainReleasePolicy)
+	address := newAddress("address", "claim", map[string]string{ipamv1.RetainedForMachineLabel: "machine"})
+	c := fakeClient(g, claim, address)
+
+	g.Expect(AssignRetainedAddress(ctx, c, claim, address)).To(Succeed())
+	g.Expect(claim.Status.AddressRef.Name).To(Equal("address"))
+
+	got := &ipamv1.IPAddress{}
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(address), got)).To(Succeed())
+	g.Expect(got.Labels).ToNot(HaveKey(ipamv1.RetainedForMachineLabel))
+	g.Expect(got.Spec.ClaimRef.Name).To(Equal("new-claim"))
+	g.Expect(got.OwnerReferences).To(HaveLen(1))
+	g.Expect(got.OwnerReferences[0].Name).To(Equal("new-claim"))
+	g.Expect(*got.OwnerReferences[0].Controller).To(BeTrue())
+}
+
+func TestReleaseClaim(t *testing.T) {
+	tests := []struct {
+		name         string
+		claim        *ipamv1.IPAddressClaim
+		wantDeleted  bool
+		wantRetained bool
+	}{
+		{
+			name:        "ReleaseOnDelete deletes the address",
+			claim:       newClaim("claim", "machine", ipamv1.ReleaseOnDeleteReleasePolicy),
+			wantDeleted: true,
+		},
+		{
+			name:        "no policy deletes the address",
+			claim:       newClaim("claim", "machine", ""),
+			wantDeleted: true,
+		},
+		{
+			name:         "Retain keeps the address for the Machine",
+			claim:        newClaim("claim", "machine", ipamv1.RetainReleasePolicy),
+			wantRetained: true,
+		},
+		{
+			name:        "Retain deletes the address if the claim is not owned by a Machine",
+			claim:       newClaim("claim", "", ipamv1.RetainReleasePolicy),
+			wantDeleted: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+
+			tt.claim.Status.AddressRef.Name = "address"
+			address := newAddress("address", tt.claim.Name, nil)
+			address.OwnerReferences = []metav1.OwnerReference{{
+				APIVersion: ipamv1.GroupVersion.String(),
+				Kind:       "IPAddressClaim",
+				Name:       tt.claim.Name,
+				UID:        tt.claim.UID,
+				Controller: pointer.Bool(true),
+			}}
+			c := fakeClient(g, tt.claim, address)
+
+			g.Expect(ReleaseClaim(ctx, c, tt.claim)).To(Succeed())
+			g.Expect(tt.claim.Finalizers).ToNot(ContainElement(ipamv1.ReleaseAddressFinalizer))
+
+			got := &ipamv1.IPAddress{}
+			err := c.Get(ctx, client.ObjectKeyFromObject(address), got)
+			if tt.wantDeleted {
+				g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			if tt.wantRetained {
+				g.Expect(got.Labels).To(HaveKeyWithValue(ipamv1.RetainedForMachineLabel, "machine"))
+				g.Expect(got.OwnerReferences).To(BeEmpty())
+				g.Expect(got.Finalizers).To(ContainElement(ipamv1.ProtectAddressFinalizer))
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	runtimecatalog "sigs.k8s.io/cluster-api/exp/runtime/catalog"
+)
+
+// AfterCanaryReadyRequest is the request of the AfterCanaryReady hook.
+// +kubebuilder:object:root=true
+type AfterCanaryReadyRequest struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// CommonRequest contains fields common to all request types.
+	CommonRequest `json:",inline"`
+
+	// Cluster is the cluster object the MachineDeployment belongs to.
+	Cluster clusterv1.Cluster `json:"cluster"`
+
+	// MachineDeployment is the MachineDeployment object which is being rolled out.
+	MachineDeployment clusterv1.MachineDeployment `json:"machineDeployment"`
+
+	// MachineSet is the new MachineSet object owning the canary Machines.
+	MachineSet clusterv1.MachineSet `json:"machineSet"`
+}
+
+var _ RetryResponseObject = &AfterCanaryReadyResponse{}
+
+// AfterCanaryReadyResponse is the response of the AfterCanaryReady hook.
+// A non-zero RetryAfterSeconds signals that the analysis of the canary Machines is still in progress.
+// +kubebuilder:object:root=true
+type AfterCanaryReadyResponse struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// CommonRetryResponse contains Status, Message and RetryAfterSeconds fields.
+	CommonRetryResponse `json:",inline"`
+
+	// Rollback signals that the canary Machines have been rejected; in this case the new MachineSet
+	// is scaled down to zero, the old MachineSets are kept and the Message should describe the reason.
+	// +optional
+	Rollback bool `json:"rollback,omitempty"`
+}
+
+// AfterCanaryReady is the hook that will be called after the canary Machines of a MachineDeployment
+// using the Canary strategy are available.
+func AfterCanaryReady(*AfterCanaryReadyRequest, *AfterCanaryReadyResponse) {}
+
+func init() {
+	catalogBuilder.RegisterHook(AfterCanaryReady, &runtimecatalog.HookMeta{
+		Tags:    []string{"Rollout Hooks"},
+		Summary: "Cluster API Runtime will call this hook after the canary Machines of a MachineDeployment are available",
+		Description: "Cluster API Runtime will call this hook when a MachineDeployment using the Canary strategy is rolling out " +
+			"a new MachineSet, after the canary Machines created from the new machine template are available.\n" +
+			"\n" +
+			"Notes:\n" +
+			"- This hook will be called only if the RuntimeSDK feature flag is enabled\n" +
+			"- The call's request contains the Cluster object, the MachineDeployment object and the new MachineSet object\n" +
+			"- This is a blocking hook; Runtime Extension implementers can use this hook to analyse the canary Machines, " +
+			"e.g. by running conformance or smoke tests, and then either let the rollout proceed or roll it back",
+	})
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	runtimecatalog "sigs.k8s.io/cluster-api/exp/runtime/catalog"
+)
+
+// MachineSetPreflightCheckRequest is the request of the MachineSetPreflightCheck hook.
+// +kubebuilder:object:root=true
+type MachineSetPreflightCheckRequest struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// CommonRequest contains fields common to all request types.
+	CommonRequest `json:",inline"`
+
+	// Cluster is the cluster object the MachineSet belongs to.
+	Cluster clusterv1.Cluster `json:"cluster"`
+
+	// MachineSet is the MachineSet object the preflight checks are performed for.
+	MachineSet clusterv1.MachineSet `json:"machineSet"`
+
+	// Action is the operation which is on hold until the preflight checks pass, e.g. "Scale up".
+	Action string `json:"action"`
+
+	// SkippedPreflightChecks is the list of provider-defined preflight checks which have been skipped
+	// using the machineset.cluster.x-k8s.io/skip-preflight-checks annotation.
+	// Runtime Extension implementers must not fail the preflight checks listed here.
+	// +optional
+	SkippedPreflightChecks []string `json:"skippedPreflightChecks,omitempty"`
+}
+
+var _ RetryResponseObject = &MachineSetPreflightCheckResponse{}
+
+// MachineSetPreflightCheckResponse is the response of the MachineSetPreflightCheck hook.
+// A non-zero RetryAfterSeconds signals that the preflight checks did not pass; in this case
+// the Message should describe the failing checks.
+// +kubebuilder:object:root=true
+type MachineSetPreflightCheckResponse struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// CommonRetryResponse contains Status, Message and RetryAfterSeconds fields.
+	CommonRetryResponse `json:",inline"`
+}
+
+// MachineSetPreflightCheck is the hook that will be called before a MachineSet creates or remediates Machines.
+func MachineSetPreflightCheck(*MachineSetPreflightCheckRequest, *MachineSetPreflightCheckResponse) {}
+
+func init() {
+	catalogBuilder.RegisterHook(MachineSetPreflightCheck, &runtimecatalog.HookMeta{
+		Tags:    []string{"Preflight Checks"},
+		Summary: "Cluster API Runtime will call this hook before a MachineSet creates or remediates Machines",
+		Description: "Cluster API Runtime will call this hook when a MachineSet is going to create new Machines, either when scaling up " +
+			"or when remediating unhealthy Machines, after the built-in MachineSet preflight checks have been performed.\n" +
+			"\n" +
+			"Notes:\n" +
+			"- This hook will be called only if the MachineSetPreflightChecks feature flag is enabled\n" +
+			"- The call's request contains the Cluster object, the MachineSet object, the action on hold and " +
+			"the provider-defined preflight checks skipped by the user\n" +
+			"- This is a blocking hook; Runtime Extension implementers can use this hook to perform additional checks, " +
+			"e.g. capacity available or image exists, before Machines are created",
+	})
+}
@@ -26,6 +26,59 @@ import (
 	"sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AfterCanaryReadyRequest) DeepCopyInto(out *AfterCanaryReadyRequest) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.CommonRequest.DeepCopyInto(&out.CommonRequest)
+	in.Cluster.DeepCopyInto(&out.Cluster)
+	in.MachineDeployment.DeepCopyInto(&out.MachineDeployment)
+	in.MachineSet.DeepCopyInto(&out.MachineSet)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AfterCanaryReadyRequest.
+func (in *AfterCanaryReadyRequest) DeepCopy() *AfterCanaryReadyRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(AfterCanaryReadyRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AfterCanaryReadyRequest) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AfterCanaryReadyResponse) DeepCopyInto(out *AfterCanaryReadyResponse) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.CommonRetryResponse = in.CommonRetryResponse
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AfterCanaryReadyResponse.
+func (in *AfterCanaryReadyResponse) DeepCopy() *AfterCanaryReadyResponse {
+	if in == nil {
+		return nil
+	}
+	out := new(AfterCanaryReadyResponse)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AfterCanaryReadyResponse) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AfterClusterUpgradeRequest) DeepCopyInto(out *AfterClusterUpgradeRequest) {
 	*out = *in
@@ -669,6 +722,63 @@ func (in *HolderReference) DeepCopy() *HolderReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineSetPreflightCheckRequest) DeepCopyInto(out *MachineSetPreflightCheckRequest) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.CommonRequest.DeepCopyInto(&out.CommonRequest)
+	in.Cluster.DeepCopyInto(&out.Cluster)
+	in.MachineSet.DeepCopyInto(&out.MachineSet)
+	if in.SkippedPreflightChecks != nil {
+		in, out := &in.SkippedPreflightChecks, &out.SkippedPreflightChecks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineSetPreflightCheckRequest.
+func (in *MachineSetPreflightCheckRequest) DeepCopy() *MachineSetPreflightCheckRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineSetPreflightCheckRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MachineSetPreflightCheckRequest) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineSetPreflightCheckResponse) DeepCopyInto(out *MachineSetPreflightCheckResponse) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.CommonRetryResponse = in.CommonRetryResponse
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineSetPreflightCheckResponse.
+func (in *MachineSetPreflightCheckResponse) DeepCopy() *MachineSetPreflightCheckResponse {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineSetPreflightCheckResponse)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MachineSetPreflightCheckResponse) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ValidateTopologyRequest) DeepCopyInto(out *ValidateTopologyRequest) {
 	*out = *in
@@ -60,6 +60,31 @@ const (
 	//
 	// alpha: v1.5
 	MachineSetPreflightChecks featuregate.Feature = "MachineSetPreflightChecks"
+
+	// ClusterClassReplication is a feature gate for replicating ClusterClasses and templates from a source
+	// namespace to all the namespaces opting-in.
+	// NOTE: It requires the ClusterTopology feature gate to be enabled.
+	//
+	// alpha: v1.6
+	ClusterClassReplication featuregate.Feature = "ClusterClassReplication"
+
+	// MachineWarmPool is a feature gate for the MachineWarmPool functionality, keeping pre-provisioned
+	// infrastructure machines claimed by MachineSets when scaling up.
+	//
+	// alpha: v1.6
+	MachineWarmPool featuregate.Feature = "MachineWarmPool"
+
+	// EtcdSnapshotRestore is a feature gate for the EtcdSnapshot and EtcdRestore functionality, taking snapshots
+	// of the etcd cluster of a KubeadmControlPlane and restoring them in a disaster recovery scenario.
+	//
+	// alpha: v1.6
+	EtcdSnapshotRestore featuregate.Feature = "EtcdSnapshotRestore"
+
+	// MachineDrainRule is a feature gate for the MachineDrainRule functionality, customizing the order
+	// and the behavior used when draining Pods from the Node of a Machine.
+	//
+	// alpha: v1.6
+	MachineDrainRule featuregate.Feature = "MachineDrainRule"
 )
 
 func init() {
@@ -76,4 +101,8 @@ var defaultClusterAPIFeatureGates = map[featuregate.Feature]featuregate.FeatureS
 	KubeadmBootstrapFormatIgnition: {Default: false, PreRelease: featuregate.Alpha},
 	RuntimeSDK:                     {Default: false, PreRelease: featuregate.Alpha},
 	MachineSetPreflightChecks:      {Default: false, PreRelease: featuregate.Alpha},
+	ClusterClassReplication:        {Default: false, PreRelease: featuregate.Alpha},
+	MachineWarmPool:                {Default: false, PreRelease: featuregate.Alpha},
+	EtcdSnapshotRestore:            {Default: false, PreRelease: featuregate.Alpha},
+	MachineDrainRule:               {Default: false, PreRelease: featuregate.Alpha},
 }
@@ -510,9 +510,9 @@ func makeTask(name string) taskFunction {
 // preLoadImageTask generates a task for pre-loading an image into kind.
 func preLoadImageTask(image string) taskFunction {
 	return func(ctx context.Context, prefix string, errCh chan error) {
-		docker, err := container.NewDockerClient()
+		docker, err := container.NewRuntimeClient()
 		if err != nil {
-			errCh <- errors.Wrapf(err, "[%s] failed to create docker client", prefix)
+			errCh <- errors.Wrapf(err, "[%s] failed to create container runtime client", prefix)
 			return
 		}
 
@@ -93,6 +93,13 @@ func (c *InfrastructureClusterContract) FailureDomains() *FailureDomains {
 	}
 }
 
+// Summary provides access to the status.summary field in an InfrastructureCluster object. Note that this field is optional.
+func (c *InfrastructureClusterContract) Summary() *SummaryRows {
+	return &SummaryRows{
+		path: []string{"status", "summary"},
+	}
+}
+
 // IgnorePaths returns a list of paths to be ignored when reconciling an InfrastructureCluster.
 // NOTE: The controlPlaneEndpoint struct currently contains two mandatory fields (host and port).
 // As the host and port fields are not using omitempty, they are automatically set to their zero values
@@ -70,6 +70,13 @@ func (m *InfrastructureMachineContract) Addresses() *MachineAddresses {
 	}
 }
 
+// Summary provides access to the status.summary field in an InfrastructureMachine object. Note that this field is optional.
+func (m *InfrastructureMachineContract) Summary() *SummaryRows {
+	return &SummaryRows{
+		path: []string{"status", "summary"},
+	}
+}
+
 // ProviderID provides access to the spec.providerID field in an InfrastructureMachine object.
 func (m *InfrastructureMachineContract) ProviderID() *String {
 	return &String{
@@ -116,4 +116,20 @@ func TestInfrastructureMachine(t *testing.T) {
 		g.Expect(got).ToNot(BeNil())
 		g.Expect(*got).To(Equal("fake-failure-domain"))
 	})
+	t.Run("Manages optional status.summary", func(t *testing.T) {
+		g := NewWithT(t)
+
+		summary := []clusterv1.SummaryRow{
+			{Key: "Instance Type", Value: "m5.large"},
+			{Key: "Zone", Value: "us-east-1a"},
+		}
+		g.Expect(InfrastructureMachine().Summary().Path()).To(Equal(Path{"status", "summary"}))
+
+		err := InfrastructureMachine().Summary().Set(obj, summary)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		got, err := InfrastructureMachine().Summary().Get(obj)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(got).To(Equal(summary))
+	})
 }
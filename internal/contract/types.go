@@ -17,12 +17,15 @@ limitations under the License.
 package contract
 
 import (
+	"encoding/json"
 	"strconv"
 	"strings"
 
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
 // ErrFieldNotFound is returned when a field is not found in the object.
@@ -197,3 +200,52 @@ func (i *Duration) Set(obj *unstructured.Unstructured, value metav1.Duration) er
 	}
 	return nil
 }
+
+// SummaryRows represents an accessor to a []clusterv1.SummaryRow path value.
+type SummaryRows struct {
+	path Path
+}
+
+// Path returns the path to the []clusterv1.SummaryRow value.
+func (r *SummaryRows) Path() Path {
+	return r.path
+}
+
+// Get gets the []clusterv1.SummaryRow value.
+func (r *SummaryRows) Get(obj *unstructured.Unstructured) ([]clusterv1.SummaryRow, error) {
+	slice, ok, err := unstructured.NestedSlice(obj.UnstructuredContent(), r.path...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get %s from object", "."+strings.Join(r.path, "."))
+	}
+	if !ok {
+		return nil, errors.Wrapf(ErrFieldNotFound, "path %s", "."+strings.Join(r.path, "."))
+	}
+
+	rows := make([]clusterv1.SummaryRow, len(slice))
+	s, err := json.Marshal(slice)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshall field at %s to json", "."+strings.Join(r.path, "."))
+	}
+	if err := json.Unmarshal(s, &rows); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshall field at %s to json", "."+strings.Join(r.path, "."))
+	}
+
+	return rows, nil
+}
+
+// Set sets the []clusterv1.SummaryRow value in the path.
+func (r *SummaryRows) Set(obj *unstructured.Unstructured, values []clusterv1.SummaryRow) error {
+	slice := make([]interface{}, len(values))
+	s, err := json.Marshal(values)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshall supplied values to json for path %s", "."+strings.Join(r.path, "."))
+	}
+	if err := json.Unmarshal(s, &slice); err != nil {
+		return errors.Wrapf(err, "failed to unmarshall supplied values to json for path %s", "."+strings.Join(r.path, "."))
+	}
+
+	if err := unstructured.SetNestedField(obj.UnstructuredContent(), slice, r.path...); err != nil {
+		return errors.Wrapf(err, "failed to set path %s of object %v", "."+strings.Join(r.path, "."), obj.GroupVersionKind())
+	}
+	return nil
+}
@@ -20,34 +20,46 @@ import (
 	"context"
 	"fmt"
 	"path"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
-	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controllers/external"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/internal/controllers/machinedeployment/mdutil"
 	"sigs.k8s.io/cluster-api/internal/hooks"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/collections"
+	"sigs.k8s.io/cluster-api/util/concurrency"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/events"
+	clog "sigs.k8s.io/cluster-api/util/log"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
+	"sigs.k8s.io/cluster-api/util/priority"
+	"sigs.k8s.io/cluster-api/util/tracing"
 )
 
 const (
@@ -72,20 +84,43 @@ type Reconciler struct {
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
 
+	// WorkerMachineDeletionBatchSize is the maximum number of worker Machines being deleted at the same time
+	// when a Cluster is deleted. If zero, worker Machines are deleted together with their owners.
+	WorkerMachineDeletionBatchSize int
+
+	// ReconcilePriorityWeights are the weights of the priority queue serving the requests for the objects of
+	// Clusters by their reconcile priority. If not set, requests are served in the order they are received.
+	ReconcilePriorityWeights priority.Weights
+
+	// NamespaceConcurrency is the maximum number of objects of the same namespace reconciled at the same time.
+	// If zero, it is not limited.
+	NamespaceConcurrency int
+
 	recorder        record.EventRecorder
 	externalTracker external.ObjectTracker
 }
 
 func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	reconciler, err := priority.NewReconciler(mgr, concurrency.NewNamespaceLimitedReconciler(tracing.NewReconciler(r, "Cluster"), r.NamespaceConcurrency), &clusterv1.Cluster{}, r.ReconcilePriorityWeights, options.MaxConcurrentReconciles)
+	if err != nil {
+		return errors.Wrap(err, "failed setting up with a controller manager")
+	}
+
 	c, err := ctrl.NewControllerManagedBy(mgr).
 		For(&clusterv1.Cluster{}).
 		Watches(
 			&clusterv1.Machine{},
-			handler.EnqueueRequestsFromMapFunc(r.controlPlaneMachineToCluster),
+			handler.EnqueueRequestsFromMapFunc(r.machineToCluster),
+			builder.WithPredicates(machineStatusChanged(ctrl.LoggerFrom(ctx))),
+		).
+		Watches(
+			&clusterv1.MachineDeployment{},
+			handler.EnqueueRequestsFromMapFunc(r.machineDeploymentToCluster),
+			builder.WithPredicates(machineDeploymentTemplateChanged(ctrl.LoggerFrom(ctx))),
 		).
 		WithOptions(options).
 		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
-		Build(r)
+		Build(reconciler)
 
 	if err != nil {
 		return errors.Wrap(err, "failed setting up with a controller manager")
@@ -115,6 +150,14 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 		return ctrl.Result{}, err
 	}
 
+	ctx, log = clog.AddOperationID(ctx, cluster)
+
+	// Propagate the pause state of the Cluster to the objects of the Cluster, so provider controllers stop
+	// reconciling them while the Cluster is paused.
+	if err := r.reconcilePaused(ctx, cluster); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Return early if the object or Cluster is paused.
 	if annotations.IsPaused(cluster, cluster) {
 		log.Info("Reconciliation is paused for this object")
@@ -197,6 +240,7 @@ func (r *Reconciler) reconcile(ctx context.Context, cluster *clusterv1.Cluster)
 		r.reconcileControlPlane,
 		r.reconcileKubeconfig,
 		r.reconcileControlPlaneInitialized,
+		r.reconcileMachinesStatus,
 	}
 
 	res := ctrl.Result{}
@@ -233,6 +277,13 @@ func (r *Reconciler) reconcileDelete(ctx context.Context, cluster *clusterv1.Clu
 		return reconcile.Result{}, err
 	}
 
+	// If a deletion budget is configured, delete worker Machines in batches before deleting their owners;
+	// otherwise the garbage collector deletes all of them at once.
+	// NOTE: Nodes are not drained when the Cluster is being deleted, see isDeleteNodeAllowed in the Machine controller.
+	if r.WorkerMachineDeletionBatchSize > 0 && len(descendants.workerMachines.Items) > 0 {
+		return r.reconcileDeleteWorkerMachines(ctx, descendants.workerMachines)
+	}
+
 	children, err := descendants.filterOwnedDescendants(cluster)
 	if err != nil {
 		log.Error(err, "Failed to extract direct descendants")
@@ -334,10 +385,49 @@ func (r *Reconciler) reconcileDelete(ctx context.Context, cluster *clusterv1.Clu
 	}
 
 	controllerutil.RemoveFinalizer(cluster, clusterv1.ClusterFinalizer)
-	r.recorder.Eventf(cluster, corev1.EventTypeNormal, "Deleted", "Cluster %s has been deleted", cluster.Name)
+	events.Normalf(r.recorder, cluster, events.DeletedReason, "Cluster %s has been deleted", cluster.Name)
 	return ctrl.Result{}, nil
 }
 
+// reconcileDeleteWorkerMachines deletes worker Machines so that at most WorkerMachineDeletionBatchSize
+// of them are being deleted at the same time.
+func (r *Reconciler) reconcileDeleteWorkerMachines(ctx context.Context, workerMachines clusterv1.MachineList) (reconcile.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	inFlight := 0
+	pending := []*clusterv1.Machine{}
+	for i := range workerMachines.Items {
+		m := &workerMachines.Items[i]
+		if !m.DeletionTimestamp.IsZero() {
+			inFlight++
+			continue
+		}
+		pending = append(pending, m)
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].Name < pending[j].Name
+	})
+
+	var errs []error
+	for i := 0; i < len(pending) && inFlight < r.WorkerMachineDeletionBatchSize; i++ {
+		m := pending[i]
+		log.Info("Deleting worker Machine", "Machine", klog.KObj(m))
+		if err := r.Client.Delete(ctx, m); err != nil && !apierrors.IsNotFound(err) {
+			err = errors.Wrapf(err, "failed to delete Machine %s", klog.KObj(m))
+			log.Error(err, "Error deleting worker Machine", "Machine", klog.KObj(m))
+			errs = append(errs, err)
+			continue
+		}
+		inFlight++
+	}
+	if len(errs) > 0 {
+		return ctrl.Result{}, kerrors.NewAggregate(errs)
+	}
+
+	log.Info("Cluster still has worker machines - need to requeue", "deleting", inFlight, "pending", len(workerMachines.Items)-inFlight)
+	return ctrl.Result{RequeueAfter: deleteRequeueAfter}, nil
+}
+
 type clusterDescendants struct {
 	machineDeployments   clusterv1.MachineDeploymentList
 	machineSets          clusterv1.MachineSetList
@@ -509,30 +599,112 @@ func (r *Reconciler) reconcileControlPlaneInitialized(ctx context.Context, clust
 	return ctrl.Result{}, nil
 }
 
-// controlPlaneMachineToCluster is a handler.ToRequestsFunc to be used to enqueue requests for reconciliation
-// for Cluster to update its status.controlPlaneInitialized field.
-func (r *Reconciler) controlPlaneMachineToCluster(ctx context.Context, o client.Object) []ctrl.Request {
+// machineToCluster is a handler.ToRequestsFunc to be used to enqueue requests for reconciliation
+// for Cluster to update its status.controlPlaneInitialized field and the Machine counters in its status.
+func (r *Reconciler) machineToCluster(_ context.Context, o client.Object) []ctrl.Request {
 	m, ok := o.(*clusterv1.Machine)
 	if !ok {
 		panic(fmt.Sprintf("Expected a Machine but got a %T", o))
 	}
-	if !util.IsControlPlaneMachine(m) {
+	if m.Spec.ClusterName == "" {
 		return nil
 	}
-	if m.Status.NodeRef == nil {
-		return nil
+
+	return []ctrl.Request{{
+		NamespacedName: client.ObjectKey{Namespace: m.Namespace, Name: m.Spec.ClusterName},
+	}}
+}
+
+// machineStatusChanged returns a predicate that returns true for Machine update events only if the change
+// affects the Machine counters in the Cluster status or the Cluster's ControlPlaneInitialized condition, i.e.
+// if the Machine is being deleted, or if its control plane label, its owner, its Node or its readiness or
+// remediation conditions changed. Create, delete and generic events are always processed.
+func machineStatusChanged(logger logr.Logger) predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldMachine, ok := e.ObjectOld.(*clusterv1.Machine)
+			if !ok {
+				return false
+			}
+			newMachine, ok := e.ObjectNew.(*clusterv1.Machine)
+			if !ok {
+				return false
+			}
+			if machineStatusSummaryFor(oldMachine) == machineStatusSummaryFor(newMachine) {
+				logger.V(6).Info("Machine change does not affect the Cluster status, blocking", "Machine", klog.KObj(newMachine))
+				return false
+			}
+			return true
+		},
+		CreateFunc:  func(e event.CreateEvent) bool { return true },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return true },
+		GenericFunc: func(e event.GenericEvent) bool { return true },
 	}
+}
 
-	cluster, err := util.GetClusterByName(ctx, r.Client, m.Namespace, m.Spec.ClusterName)
-	if err != nil {
-		return nil
+// machineStatusSummary holds the info of a Machine the Cluster controller computes the Cluster status from.
+type machineStatusSummary struct {
+	deleting     bool
+	controlPlane bool
+	owner        string
+	hasNodeRef   bool
+	ready        bool
+	remediating  bool
+}
+
+func machineStatusSummaryFor(m *clusterv1.Machine) machineStatusSummary {
+	summary := machineStatusSummary{
+		deleting:     !m.DeletionTimestamp.IsZero(),
+		controlPlane: util.IsControlPlaneMachine(m),
+		hasNodeRef:   m.Status.NodeRef != nil,
+		ready:        isMachineReady(m),
+		remediating:  conditions.IsFalse(m, clusterv1.MachineOwnerRemediatedCondition),
 	}
+	if ref := metav1.GetControllerOf(m); ref != nil {
+		summary.owner = ref.Kind + "/" + ref.Name
+	}
+	return summary
+}
 
-	if conditions.IsTrue(cluster, clusterv1.ControlPlaneInitializedCondition) {
+// machineDeploymentToCluster is a handler.ToRequestsFunc to be used to enqueue requests for reconciliation
+// for Cluster to update the Machine counters in its status when the template of a MachineDeployment changes.
+func (r *Reconciler) machineDeploymentToCluster(_ context.Context, o client.Object) []ctrl.Request {
+	md, ok := o.(*clusterv1.MachineDeployment)
+	if !ok {
+		panic(fmt.Sprintf("Expected a MachineDeployment but got a %T", o))
+	}
+	if md.Spec.ClusterName == "" {
 		return nil
 	}
 
 	return []ctrl.Request{{
-		NamespacedName: util.ObjectKey(cluster),
+		NamespacedName: client.ObjectKey{Namespace: md.Namespace, Name: md.Spec.ClusterName},
 	}}
 }
+
+// machineDeploymentTemplateChanged returns a predicate that returns true for MachineDeployment update events only
+// if the template of the MachineDeployment changed, given that the template is the only info of a MachineDeployment
+// the Cluster controller computes the Machine counters in the Cluster status from. Create, delete and generic events
+// are always processed.
+func machineDeploymentTemplateChanged(logger logr.Logger) predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldMD, ok := e.ObjectOld.(*clusterv1.MachineDeployment)
+			if !ok {
+				return false
+			}
+			newMD, ok := e.ObjectNew.(*clusterv1.MachineDeployment)
+			if !ok {
+				return false
+			}
+			if mdutil.EqualMachineTemplate(&oldMD.Spec.Template, &newMD.Spec.Template) {
+				logger.V(6).Info("MachineDeployment change does not affect the Cluster status, blocking", "MachineDeployment", klog.KObj(newMD))
+				return false
+			}
+			return true
+		},
+		CreateFunc:  func(e event.CreateEvent) bool { return true },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return true },
+		GenericFunc: func(e event.GenericEvent) bool { return true },
+	}
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/external"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/patch"
+)
+
+// reconcilePaused propagates the pause state of the Cluster to the infrastructure, control plane and bootstrap
+// objects of the Cluster, of its Machines and of its MachinePools, by adding or removing the paused annotation.
+// This ensures provider controllers stop reconciling these objects while the Cluster is paused, even if they
+// only check the paused annotation of the reconciled object.
+// Once propagated, the pause state is recorded on the Cluster with the paused-by-cluster annotation, so the objects
+// are only listed and patched again when the Cluster is paused or unpaused.
+// NOTE: this happens before checking if the Cluster is paused, so the annotations are added as soon as the Cluster
+// is paused and removed as soon as it is unpaused.
+func (r *Reconciler) reconcilePaused(ctx context.Context, cluster *clusterv1.Cluster) error {
+	if cluster.Spec.Paused == annotations.HasPausedByCluster(cluster) {
+		return nil
+	}
+
+	refs, err := r.pausableRefs(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	errs := []error{}
+	for _, ref := range refs {
+		if err := r.syncPaused(ctx, cluster, ref); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return kerrors.NewAggregate(errs)
+	}
+
+	patchHelper, err := patch.NewHelper(cluster, r.Client)
+	if err != nil {
+		return err
+	}
+	if cluster.Spec.Paused {
+		annotations.AddAnnotations(cluster, map[string]string{clusterv1.PausedByClusterAnnotation: ""})
+	} else {
+		delete(cluster.Annotations, clusterv1.PausedByClusterAnnotation)
+	}
+	if err := patchHelper.Patch(ctx, cluster); err != nil {
+		return errors.Wrapf(err, "failed to record the pause state on Cluster %s", klog.KObj(cluster))
+	}
+	return nil
+}
+
+// pausableRefs returns the references to the infrastructure, control plane and bootstrap objects of the Cluster,
+// of its Machines and of its MachinePools.
+func (r *Reconciler) pausableRefs(ctx context.Context, cluster *clusterv1.Cluster) ([]*corev1.ObjectReference, error) {
+	refs := []*corev1.ObjectReference{cluster.Spec.InfrastructureRef, cluster.Spec.ControlPlaneRef}
+
+	listOptions := []client.ListOption{
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{clusterv1.ClusterNameLabel: cluster.Name},
+	}
+
+	machines := &clusterv1.MachineList{}
+	if err := r.Client.List(ctx, machines, listOptions...); err != nil {
+		return nil, errors.Wrapf(err, "failed to list Machines for cluster %s", klog.KObj(cluster))
+	}
+	for i := range machines.Items {
+		m := &machines.Items[i]
+		refs = append(refs, &m.Spec.InfrastructureRef, m.Spec.Bootstrap.ConfigRef)
+	}
+
+	if feature.Gates.Enabled(feature.MachinePool) {
+		machinePools := &expv1.MachinePoolList{}
+		if err := r.Client.List(ctx, machinePools, listOptions...); err != nil {
+			return nil, errors.Wrapf(err, "failed to list MachinePools for cluster %s", klog.KObj(cluster))
+		}
+		for i := range machinePools.Items {
+			mp := &machinePools.Items[i]
+			refs = append(refs, &mp.Spec.Template.Spec.InfrastructureRef, mp.Spec.Template.Spec.Bootstrap.ConfigRef)
+		}
+	}
+
+	ret := make([]*corev1.ObjectReference, 0, len(refs))
+	for _, ref := range refs {
+		if ref != nil && ref.Name != "" {
+			ret = append(ret, ref)
+		}
+	}
+	return ret, nil
+}
+
+// syncPaused adds or removes the paused annotation on the referenced object, depending on the Cluster being paused.
+func (r *Reconciler) syncPaused(ctx context.Context, cluster *clusterv1.Cluster, ref *corev1.ObjectReference) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	obj, err := external.Get(ctx, r.UnstructuredCachingClient, ref, cluster.Namespace)
+	if err != nil {
+		if apierrors.IsNotFound(errors.Cause(err)) {
+			return nil
+		}
+		return err
+	}
+
+	patchHelper, err := patch.NewHelper(obj, r.Client)
+	if err != nil {
+		return err
+	}
+	if !annotations.SyncPausedFromCluster(cluster, obj) {
+		return nil
+	}
+
+	log.V(4).Info("Syncing paused annotation", ref.Kind, klog.KObj(obj), "paused", cluster.Spec.Paused)
+	if err := patchHelper.Patch(ctx, obj); err != nil {
+		return errors.Wrapf(err, "failed to sync paused annotation on %s %s", ref.Kind, klog.KObj(obj))
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/test/builder"
+)
+
+func TestClusterReconcilePaused(t *testing.T) {
+	g := NewWithT(t)
+
+	infraCluster := builder.InfrastructureCluster(metav1.NamespaceDefault, "infra-cluster").Build()
+	controlPlane := builder.ControlPlane(metav1.NamespaceDefault, "control-plane").Build()
+
+	infraMachine := &unstructured.Unstructured{}
+	infraMachine.SetAPIVersion(builder.InfrastructureGroupVersion.String())
+	infraMachine.SetKind(builder.GenericInfrastructureMachineKind)
+	infraMachine.SetNamespace(metav1.NamespaceDefault)
+	infraMachine.SetName("infra-machine")
+
+	// The bootstrap config has been paused by a user before the Cluster was paused.
+	bootstrapConfig := &unstructured.Unstructured{}
+	bootstrapConfig.SetAPIVersion(builder.BootstrapGroupVersion.String())
+	bootstrapConfig.SetKind(builder.GenericBootstrapConfigKind)
+	bootstrapConfig.SetNamespace(metav1.NamespaceDefault)
+	bootstrapConfig.SetName("bootstrap-config")
+	bootstrapConfig.SetAnnotations(map[string]string{clusterv1.PausedAnnotation: "true"})
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: metav1.NamespaceDefault},
+		Spec: clusterv1.ClusterSpec{
+			Paused:            true,
+			InfrastructureRef: objToRef(infraCluster),
+			ControlPlaneRef:   objToRef(controlPlane),
+		},
+	}
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-machine",
+			Namespace: metav1.NamespaceDefault,
+			Labels:    map[string]string{clusterv1.ClusterNameLabel: cluster.Name},
+		},
+		Spec: clusterv1.MachineSpec{
+			ClusterName:       cluster.Name,
+			InfrastructureRef: *objToRef(infraMachine),
+			Bootstrap:         clusterv1.Bootstrap{ConfigRef: objToRef(bootstrapConfig)},
+		},
+	}
+	// Objects that don't exist are ignored.
+	missingMachine := machine.DeepCopy()
+	missingMachine.Name = "missing-machine"
+	missingMachine.Spec.InfrastructureRef.Name = "missing-infra-machine"
+	missingMachine.Spec.Bootstrap.ConfigRef = nil
+
+	c := fake.NewClientBuilder().WithObjects(cluster, machine, missingMachine, infraCluster, controlPlane, infraMachine, bootstrapConfig).Build()
+	r := &Reconciler{
+		Client:                    c,
+		UnstructuredCachingClient: c,
+	}
+
+	getAnnotations := func(obj *unstructured.Unstructured) map[string]string {
+		got := &unstructured.Unstructured{}
+		got.SetGroupVersionKind(obj.GroupVersionKind())
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), got)).To(Succeed())
+		return got.GetAnnotations()
+	}
+	pausedByCluster := map[string]string{
+		clusterv1.PausedAnnotation:          "",
+		clusterv1.PausedByClusterAnnotation: "",
+	}
+	pausedByUser := map[string]string{clusterv1.PausedAnnotation: "true"}
+
+	// Pausing the Cluster pauses all its objects, preserving the annotations set by users.
+	g.Expect(r.reconcilePaused(ctx, cluster)).To(Succeed())
+	g.Expect(getAnnotations(infraCluster)).To(Equal(pausedByCluster))
+	g.Expect(getAnnotations(controlPlane)).To(Equal(pausedByCluster))
+	g.Expect(getAnnotations(infraMachine)).To(Equal(pausedByCluster))
+	g.Expect(getAnnotations(bootstrapConfig)).To(Equal(pausedByUser))
+	g.Expect(cluster.Annotations).To(HaveKey(clusterv1.PausedByClusterAnnotation))
+
+	// The objects are not patched again while the pause state of the Cluster is unchanged.
+	unpausedInfraCluster := infraCluster.DeepCopy()
+	unpausedInfraCluster.SetResourceVersion("")
+	g.Expect(c.Delete(ctx, infraCluster)).To(Succeed())
+	g.Expect(c.Create(ctx, unpausedInfraCluster)).To(Succeed())
+	g.Expect(r.reconcilePaused(ctx, cluster)).To(Succeed())
+	g.Expect(getAnnotations(infraCluster)).To(BeEmpty())
+
+	// Unpausing the Cluster unpauses the objects paused by the Cluster only.
+	cluster.Spec.Paused = false
+	g.Expect(r.reconcilePaused(ctx, cluster)).To(Succeed())
+	g.Expect(cluster.Annotations).ToNot(HaveKey(clusterv1.PausedByClusterAnnotation))
+	g.Expect(getAnnotations(infraCluster)).To(BeEmpty())
+	g.Expect(getAnnotations(controlPlane)).To(BeEmpty())
+	g.Expect(getAnnotations(infraMachine)).To(BeEmpty())
+	g.Expect(getAnnotations(bootstrapConfig)).To(Equal(pausedByUser))
+}
+
+func objToRef(obj client.Object) *corev1.ObjectReference {
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	return &corev1.ObjectReference{
+		APIVersion: gvk.GroupVersion().String(),
+		Kind:       gvk.Kind,
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+	}
+}
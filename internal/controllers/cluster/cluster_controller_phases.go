@@ -24,18 +24,26 @@ import (
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
 	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controllers/external"
 	capierrors "sigs.k8s.io/cluster-api/errors"
+	"sigs.k8s.io/cluster-api/internal/contract"
+	"sigs.k8s.io/cluster-api/internal/controllers/machinedeployment/mdutil"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/collections"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
+	"sigs.k8s.io/cluster-api/util/events"
 	"sigs.k8s.io/cluster-api/util/kubeconfig"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/secret"
@@ -114,13 +122,31 @@ func (r *Reconciler) reconcileExternal(ctx context.Context, cluster *clusterv1.C
 		return external.ReconcileOutput{}, err
 	}
 
-	// Set the Cluster label.
+	// Propagate the Cluster metadata and set the Cluster label.
+	// NOTE: The Cluster metadata is propagated to objects managed by the topology controller by the topology
+	// controller itself, so the labels and annotations from the topology and the ClusterClass can take precedence.
+	_, topologyOwned := obj.GetLabels()[clusterv1.ClusterTopologyOwnedLabel]
 	labels := obj.GetLabels()
 	if labels == nil {
 		labels = make(map[string]string)
 	}
+	if cluster.Spec.Metadata != nil && !topologyOwned {
+		for k, v := range cluster.Spec.Metadata.Labels {
+			labels[k] = v
+		}
+	}
 	labels[clusterv1.ClusterNameLabel] = cluster.Name
 	obj.SetLabels(labels)
+	if cluster.Spec.Metadata != nil && len(cluster.Spec.Metadata.Annotations) > 0 && !topologyOwned {
+		annotations := obj.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		for k, v := range cluster.Spec.Metadata.Annotations {
+			annotations[k] = v
+		}
+		obj.SetAnnotations(annotations)
+	}
 
 	// Always attempt to Patch the external object.
 	if err := patchHelper.Patch(ctx, obj); err != nil {
@@ -150,6 +176,17 @@ func (r *Reconciler) reconcileExternal(ctx context.Context, cluster *clusterv1.C
 func (r *Reconciler) reconcileInfrastructure(ctx context.Context, cluster *clusterv1.Cluster) (ctrl.Result, error) {
 	log := ctrl.LoggerFrom(ctx)
 
+	// If the infrastructure is provided by the control plane, there is no infrastructure cluster to wait for;
+	// the control plane provider is responsible for provisioning the infrastructure.
+	if cluster.Spec.ControlPlaneProvidesInfrastructure {
+		if !cluster.Status.InfrastructureReady {
+			events.Normalf(r.recorder, cluster, events.InfrastructureReadyReason, "Cluster %s InfrastructureReady is now true", cluster.Name)
+		}
+		cluster.Status.InfrastructureReady = true
+		conditions.MarkTrue(cluster, clusterv1.InfrastructureReadyCondition)
+		return ctrl.Result{}, nil
+	}
+
 	if cluster.Spec.InfrastructureRef == nil {
 		return ctrl.Result{}, nil
 	}
@@ -183,13 +220,22 @@ func (r *Reconciler) reconcileInfrastructure(ctx context.Context, cluster *clust
 	cluster.Status.InfrastructureReady = ready
 	// Only record the event if the status has changed
 	if preReconcileInfrastructureReady != cluster.Status.InfrastructureReady {
-		r.recorder.Eventf(cluster, corev1.EventTypeNormal, "InfrastructureReady", "Cluster %s InfrastructureReady is now %t", cluster.Name, cluster.Status.InfrastructureReady)
+		events.Normalf(r.recorder, cluster, events.InfrastructureReadyReason, "Cluster %s InfrastructureReady is now %t", cluster.Name, cluster.Status.InfrastructureReady)
 	}
 
-	// Report a summary of current status of the infrastructure object defined for this cluster.
+	// Report a summary of current status of the infrastructure object defined for this cluster, including the
+	// provider-specific summary rows surfaced in status.summary, if any.
+	summaryRows, err := contract.InfrastructureCluster().Summary().Get(infraConfig)
+	// NOTE: The summary rows are only displayed, so a malformed status.summary must not block the reconcile;
+	// in this case the condition is mirrored without summary rows.
+	if err != nil && !errors.Is(err, contract.ErrFieldNotFound) {
+		log.Error(err, "Failed to retrieve summary from infrastructure provider, ignoring it", infraConfig.GetKind(), klog.KObj(infraConfig))
+		summaryRows = nil
+	}
 	conditions.SetMirror(cluster, clusterv1.InfrastructureReadyCondition,
 		conditions.UnstructuredGetter(infraConfig),
 		conditions.WithFallbackValue(ready, clusterv1.WaitingForInfrastructureFallbackReason, clusterv1.ConditionSeverityInfo, ""),
+		conditions.WithSummaryRows(summaryRows),
 	)
 
 	if !ready {
@@ -251,7 +297,7 @@ func (r *Reconciler) reconcileControlPlane(ctx context.Context, cluster *cluster
 	cluster.Status.ControlPlaneReady = ready
 	// Only record the event if the status has changed
 	if preReconcileControlPlaneReady != cluster.Status.ControlPlaneReady {
-		r.recorder.Eventf(cluster, corev1.EventTypeNormal, "ControlPlaneReady", "Cluster %s ControlPlaneReady is now %t", cluster.Name, cluster.Status.ControlPlaneReady)
+		events.Normalf(r.recorder, cluster, events.ControlPlaneReadyReason, "Cluster %s ControlPlaneReady is now %t", cluster.Name, cluster.Status.ControlPlaneReady)
 	}
 
 	// Report a summary of current status of the control plane object defined for this cluster.
@@ -260,6 +306,14 @@ func (r *Reconciler) reconcileControlPlane(ctx context.Context, cluster *cluster
 		conditions.WithFallbackValue(ready, clusterv1.WaitingForControlPlaneFallbackReason, clusterv1.ConditionSeverityInfo, ""),
 	)
 
+	// If the infrastructure is provided by the control plane, get the control plane endpoint and the failure domains
+	// from the control plane object.
+	if cluster.Spec.ControlPlaneProvidesInfrastructure {
+		if err := reconcileInfrastructureFromControlPlane(cluster, controlPlaneConfig); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
 	// Update cluster.Status.ControlPlaneInitialized if it hasn't already been set
 	// Determine if the control plane provider is initialized.
 	if !conditions.IsTrue(cluster, clusterv1.ControlPlaneInitializedCondition) {
@@ -277,6 +331,27 @@ func (r *Reconciler) reconcileControlPlane(ctx context.Context, cluster *cluster
 	return ctrl.Result{}, nil
 }
 
+// reconcileInfrastructureFromControlPlane gets Spec.ControlPlaneEndpoint and Status.FailureDomains from the control plane object,
+// for Clusters whose infrastructure is provided by the control plane.
+func reconcileInfrastructureFromControlPlane(cluster *clusterv1.Cluster, controlPlaneConfig *unstructured.Unstructured) error {
+	// Get and parse Spec.ControlPlaneEndpoint field from the control plane provider.
+	if !cluster.Spec.ControlPlaneEndpoint.IsValid() {
+		if err := util.UnstructuredUnmarshalField(controlPlaneConfig, &cluster.Spec.ControlPlaneEndpoint, "spec", "controlPlaneEndpoint"); err != nil && err != util.ErrUnstructuredFieldNotFound {
+			return errors.Wrapf(err, "failed to retrieve Spec.ControlPlaneEndpoint from control plane provider for Cluster %q in namespace %q",
+				cluster.Name, cluster.Namespace)
+		}
+	}
+
+	// Get and parse Status.FailureDomains from the control plane provider.
+	failureDomains := clusterv1.FailureDomains{}
+	if err := util.UnstructuredUnmarshalField(controlPlaneConfig, &failureDomains, "status", "failureDomains"); err != nil && err != util.ErrUnstructuredFieldNotFound {
+		return errors.Wrapf(err, "failed to retrieve Status.FailureDomains from control plane provider for Cluster %q in namespace %q",
+			cluster.Name, cluster.Namespace)
+	}
+	cluster.Status.FailureDomains = failureDomains
+	return nil
+}
+
 func (r *Reconciler) reconcileKubeconfig(ctx context.Context, cluster *clusterv1.Cluster) (ctrl.Result, error) {
 	log := ctrl.LoggerFrom(ctx)
 
@@ -307,3 +382,87 @@ func (r *Reconciler) reconcileKubeconfig(ctx context.Context, cluster *clusterv1
 
 	return ctrl.Result{}, nil
 }
+
+// reconcileMachinesStatus computes the aggregated counters of the Machines belonging to the Cluster
+// and surfaces them in the Cluster status.
+func (r *Reconciler) reconcileMachinesStatus(ctx context.Context, cluster *clusterv1.Cluster) (ctrl.Result, error) {
+	machines, err := collections.GetFilteredMachinesForCluster(ctx, r.Client, cluster)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to list Machines for Cluster %s", klog.KObj(cluster))
+	}
+
+	machineSets := &clusterv1.MachineSetList{}
+	if err := r.Client.List(ctx, machineSets, client.InNamespace(cluster.Namespace), client.MatchingLabels{clusterv1.ClusterNameLabel: cluster.Name}); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to list MachineSets for Cluster %s", klog.KObj(cluster))
+	}
+
+	machineDeployments := &clusterv1.MachineDeploymentList{}
+	if err := r.Client.List(ctx, machineDeployments, client.InNamespace(cluster.Namespace), client.MatchingLabels{clusterv1.ClusterNameLabel: cluster.Name}); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to list MachineDeployments for Cluster %s", klog.KObj(cluster))
+	}
+
+	setMachinesStatus(cluster, machines, machineSets.Items, machineDeployments.Items)
+	return ctrl.Result{}, nil
+}
+
+// setMachinesStatus sets the readyWorkers, upToDateWorkers, unavailableControlPlane and remediatingMachines
+// counters of the Cluster status.
+func setMachinesStatus(cluster *clusterv1.Cluster, machines collections.Machines, machineSets []clusterv1.MachineSet, machineDeployments []clusterv1.MachineDeployment) {
+	machineDeploymentsByName := map[string]*clusterv1.MachineDeployment{}
+	for i := range machineDeployments {
+		machineDeploymentsByName[machineDeployments[i].Name] = &machineDeployments[i]
+	}
+
+	// Compute which MachineSets have the same template of the MachineDeployment owning them; MachineSets
+	// not owned by a MachineDeployment are always considered up-to-date.
+	upToDateMachineSets := map[string]bool{}
+	for i := range machineSets {
+		ms := &machineSets[i]
+		upToDate := true
+		if ref := metav1.GetControllerOf(ms); ref != nil && ref.Kind == "MachineDeployment" {
+			if md, ok := machineDeploymentsByName[ref.Name]; ok {
+				upToDate = mdutil.EqualMachineTemplate(&ms.Spec.Template, &md.Spec.Template)
+			}
+		}
+		upToDateMachineSets[ms.Name] = upToDate
+	}
+
+	var readyWorkers, upToDateWorkers, unavailableControlPlane, remediatingMachines int32
+	for _, m := range machines {
+		if conditions.IsFalse(m, clusterv1.MachineOwnerRemediatedCondition) {
+			remediatingMachines++
+		}
+
+		if util.IsControlPlaneMachine(m) {
+			if !isMachineReady(m) {
+				unavailableControlPlane++
+			}
+			continue
+		}
+
+		if isMachineReady(m) {
+			readyWorkers++
+		}
+		// Only Machines belonging to a MachineSet are considered for the up-to-date counter; MachinePool
+		// Machines and orphan Machines have no template they could be up-to-date with.
+		if ref := metav1.GetControllerOf(m); ref != nil && ref.Kind == "MachineSet" {
+			if upToDateMachineSets[ref.Name] {
+				upToDateWorkers++
+			}
+		}
+	}
+
+	cluster.Status.ReadyWorkers = readyWorkers
+	cluster.Status.UpToDateWorkers = upToDateWorkers
+	cluster.Status.UnavailableControlPlane = unavailableControlPlane
+	cluster.Status.RemediatingMachines = remediatingMachines
+}
+
+// isMachineReady returns true if the Machine is not being deleted, it has a Node and both the Ready
+// and the NodeHealthy conditions are true.
+func isMachineReady(m *clusterv1.Machine) bool {
+	if !m.DeletionTimestamp.IsZero() || m.Status.NodeRef == nil {
+		return false
+	}
+	return conditions.IsTrue(m, clusterv1.ReadyCondition) && conditions.IsTrue(m, clusterv1.MachineNodeHealthyCondition)
+}
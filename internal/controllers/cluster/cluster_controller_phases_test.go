@@ -24,7 +24,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -32,6 +34,7 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	capierrors "sigs.k8s.io/cluster-api/errors"
 	"sigs.k8s.io/cluster-api/internal/test/builder"
+	"sigs.k8s.io/cluster-api/util/conditions"
 )
 
 func TestClusterReconcilePhases(t *testing.T) {
@@ -119,6 +122,23 @@ func TestClusterReconcilePhases(t *testing.T) {
 				},
 				expectErr: false,
 			},
+			{
+				name:    "returns no error if infrastructure has a malformed status.summary",
+				cluster: cluster,
+				infraRef: map[string]interface{}{
+					"kind":       "GenericInfrastructureMachine",
+					"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+					"metadata": map[string]interface{}{
+						"name":      "test",
+						"namespace": "test-namespace",
+					},
+					"status": map[string]interface{}{
+						"ready":   false,
+						"summary": "malformed",
+					},
+				},
+				expectErr: false,
+			},
 		}
 
 		for _, tt := range tests {
@@ -530,3 +550,302 @@ func generateInfraRef(withFailureDomain bool) map[string]interface{} {
 
 	return infraRef
 }
+
+func TestClusterReconcilePhases_reconcileControlPlaneProvidesInfrastructure(t *testing.T) {
+	t.Run("infrastructure is ready", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cluster := &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "test-namespace"},
+			Spec: clusterv1.ClusterSpec{
+				ControlPlaneProvidesInfrastructure: true,
+			},
+		}
+		r := &Reconciler{
+			recorder: record.NewFakeRecorder(32),
+		}
+
+		res, err := r.reconcileInfrastructure(ctx, cluster)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(res).To(Equal(ctrl.Result{}))
+		g.Expect(cluster.Status.InfrastructureReady).To(BeTrue())
+		g.Expect(conditions.IsTrue(cluster, clusterv1.InfrastructureReadyCondition)).To(BeTrue())
+	})
+
+	t.Run("control plane endpoint and failure domains are read from the control plane", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cluster := &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "test-namespace"},
+			Spec: clusterv1.ClusterSpec{
+				ControlPlaneProvidesInfrastructure: true,
+			},
+		}
+		controlPlane := &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"controlPlaneEndpoint": map[string]interface{}{
+					"host": "1.2.3.4",
+					"port": int64(443),
+				},
+			},
+			"status": map[string]interface{}{
+				"failureDomains": map[string]interface{}{
+					"domain1": map[string]interface{}{
+						"controlPlane": false,
+					},
+				},
+			},
+		}}
+
+		g.Expect(reconcileInfrastructureFromControlPlane(cluster, controlPlane)).To(Succeed())
+		g.Expect(cluster.Spec.ControlPlaneEndpoint).To(Equal(clusterv1.APIEndpoint{Host: "1.2.3.4", Port: 443}))
+		g.Expect(cluster.Status.FailureDomains).To(Equal(clusterv1.FailureDomains{"domain1": clusterv1.FailureDomainSpec{}}))
+	})
+
+	t.Run("control plane endpoint not yet set on the control plane", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cluster := &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "test-namespace"},
+			Spec: clusterv1.ClusterSpec{
+				ControlPlaneProvidesInfrastructure: true,
+			},
+		}
+
+		g.Expect(reconcileInfrastructureFromControlPlane(cluster, &unstructured.Unstructured{Object: map[string]interface{}{}})).To(Succeed())
+		g.Expect(cluster.Spec.ControlPlaneEndpoint.IsValid()).To(BeFalse())
+		g.Expect(cluster.Status.FailureDomains).To(BeEmpty())
+	})
+}
+
+func TestClusterReconcilePhases_reconcileExternalPropagatesMetadata(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "test-namespace"},
+		Spec: clusterv1.ClusterSpec{
+			Metadata: &clusterv1.ObjectMeta{
+				Labels: map[string]string{
+					"cost-center":              "platform",
+					clusterv1.ClusterNameLabel: "other-cluster",
+				},
+				Annotations: map[string]string{"owner": "team-a"},
+			},
+		},
+	}
+	infraCluster := &unstructured.Unstructured{Object: map[string]interface{}{
+		"kind":       "GenericInfrastructureMachine",
+		"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+		"metadata": map[string]interface{}{
+			"name":      "test",
+			"namespace": "test-namespace",
+			"labels": map[string]interface{}{
+				"cost-center": "other",
+				"foo":         "bar",
+			},
+		},
+	}}
+	ref := &corev1.ObjectReference{
+		APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+		Kind:       "GenericInfrastructureMachine",
+		Name:       "test",
+	}
+
+	c := fake.NewClientBuilder().
+		WithObjects(builder.GenericInfrastructureMachineCRD.DeepCopy(), cluster, infraCluster).
+		Build()
+	r := &Reconciler{
+		Client:                    c,
+		UnstructuredCachingClient: c,
+		recorder:                  record.NewFakeRecorder(32),
+	}
+
+	_, err := r.reconcileExternal(ctx, cluster, ref)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	got := &unstructured.Unstructured{}
+	got.SetGroupVersionKind(infraCluster.GroupVersionKind())
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(infraCluster), got)).To(Succeed())
+	g.Expect(got.GetLabels()).To(Equal(map[string]string{
+		"cost-center":              "platform",
+		"foo":                      "bar",
+		clusterv1.ClusterNameLabel: "test-cluster",
+	}))
+	g.Expect(got.GetAnnotations()).To(Equal(map[string]string{"owner": "team-a"}))
+}
+
+func TestClusterReconcilePhases_reconcileExternalSkipsMetadataOfTopologyOwnedObjects(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "test-namespace"},
+		Spec: clusterv1.ClusterSpec{
+			Metadata: &clusterv1.ObjectMeta{
+				Labels:      map[string]string{"cost-center": "platform"},
+				Annotations: map[string]string{"owner": "team-a"},
+			},
+		},
+	}
+	// The Cluster metadata of objects owned by the topology controller is propagated by the topology controller,
+	// where the labels from the topology and the ClusterClass take precedence.
+	infraCluster := &unstructured.Unstructured{Object: map[string]interface{}{
+		"kind":       "GenericInfrastructureMachine",
+		"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+		"metadata": map[string]interface{}{
+			"name":      "test",
+			"namespace": "test-namespace",
+			"labels": map[string]interface{}{
+				"cost-center":                       "other",
+				clusterv1.ClusterTopologyOwnedLabel: "",
+			},
+		},
+	}}
+	ref := &corev1.ObjectReference{
+		APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+		Kind:       "GenericInfrastructureMachine",
+		Name:       "test",
+	}
+
+	c := fake.NewClientBuilder().
+		WithObjects(builder.GenericInfrastructureMachineCRD.DeepCopy(), cluster, infraCluster).
+		Build()
+	r := &Reconciler{
+		Client:                    c,
+		UnstructuredCachingClient: c,
+		recorder:                  record.NewFakeRecorder(32),
+	}
+
+	_, err := r.reconcileExternal(ctx, cluster, ref)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	got := &unstructured.Unstructured{}
+	got.SetGroupVersionKind(infraCluster.GroupVersionKind())
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(infraCluster), got)).To(Succeed())
+	g.Expect(got.GetLabels()).To(Equal(map[string]string{
+		"cost-center":                       "other",
+		clusterv1.ClusterTopologyOwnedLabel: "",
+		clusterv1.ClusterNameLabel:          "test-cluster",
+	}))
+	g.Expect(got.GetAnnotations()).To(BeEmpty())
+}
+
+func TestClusterReconcilePhases_reconcileMachinesStatus(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test-namespace",
+		},
+	}
+
+	md := &clusterv1.MachineDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "md",
+			Namespace: cluster.Namespace,
+			Labels:    map[string]string{clusterv1.ClusterNameLabel: cluster.Name},
+			UID:       "md-uid",
+		},
+		Spec: clusterv1.MachineDeploymentSpec{
+			ClusterName: cluster.Name,
+			Template: clusterv1.MachineTemplateSpec{
+				Spec: clusterv1.MachineSpec{
+					ClusterName: cluster.Name,
+					Version:     pointer.String("v1.28.0"),
+				},
+			},
+		},
+	}
+	newMachineSet := func(name, version string) *clusterv1.MachineSet {
+		return &clusterv1.MachineSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: cluster.Namespace,
+				Labels:    map[string]string{clusterv1.ClusterNameLabel: cluster.Name},
+				UID:       types.UID(name + "-uid"),
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: clusterv1.GroupVersion.String(),
+					Kind:       "MachineDeployment",
+					Name:       md.Name,
+					UID:        md.UID,
+					Controller: pointer.Bool(true),
+				}},
+			},
+			Spec: clusterv1.MachineSetSpec{
+				ClusterName: cluster.Name,
+				Template: clusterv1.MachineTemplateSpec{
+					Spec: clusterv1.MachineSpec{
+						ClusterName: cluster.Name,
+						Version:     pointer.String(version),
+					},
+				},
+			},
+		}
+	}
+	oldMS := newMachineSet("ms-old", "v1.27.0")
+	newMS := newMachineSet("ms-new", "v1.28.0")
+
+	newMachine := func(name string, controlPlane, ready, remediating bool, ms *clusterv1.MachineSet) *clusterv1.Machine {
+		m := &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: cluster.Namespace,
+				Labels:    map[string]string{clusterv1.ClusterNameLabel: cluster.Name},
+			},
+			Spec: clusterv1.MachineSpec{
+				ClusterName: cluster.Name,
+			},
+		}
+		if controlPlane {
+			m.Labels[clusterv1.MachineControlPlaneLabel] = ""
+		}
+		if ms != nil {
+			m.OwnerReferences = []metav1.OwnerReference{{
+				APIVersion: clusterv1.GroupVersion.String(),
+				Kind:       "MachineSet",
+				Name:       ms.Name,
+				UID:        ms.UID,
+				Controller: pointer.Bool(true),
+			}}
+		}
+		if ready {
+			m.Status.NodeRef = &corev1.ObjectReference{Kind: "Node", Name: name}
+			conditions.MarkTrue(m, clusterv1.ReadyCondition)
+			conditions.MarkTrue(m, clusterv1.MachineNodeHealthyCondition)
+		}
+		if remediating {
+			conditions.MarkFalse(m, clusterv1.MachineOwnerRemediatedCondition, clusterv1.WaitingForRemediationReason, clusterv1.ConditionSeverityWarning, "")
+		}
+		return m
+	}
+
+	// Machines of other Clusters are ignored.
+	otherClusterMachine := newMachine("other-cluster-worker", false, true, true, nil)
+	otherClusterMachine.Labels[clusterv1.ClusterNameLabel] = "other-cluster"
+	otherClusterMachine.Spec.ClusterName = "other-cluster"
+
+	c := fake.NewClientBuilder().WithObjects(
+		cluster, md, oldMS, newMS,
+		newMachine("cp-ready", true, true, false, nil),
+		newMachine("cp-not-ready", true, false, false, nil),
+		newMachine("cp-remediating", true, true, true, nil),
+		newMachine("worker-old-ready", false, true, false, oldMS),
+		newMachine("worker-new-ready", false, true, false, newMS),
+		newMachine("worker-new-not-ready", false, false, true, newMS),
+		newMachine("worker-standalone-ready", false, true, false, nil),
+		otherClusterMachine,
+	).Build()
+	r := &Reconciler{
+		Client: c,
+	}
+
+	res, err := r.reconcileMachinesStatus(ctx, cluster)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(res).To(Equal(ctrl.Result{}))
+
+	g.Expect(cluster.Status.ReadyWorkers).To(Equal(int32(3)))
+	// The stand-alone worker Machine is not considered for the up-to-date counter.
+	g.Expect(cluster.Status.UpToDateWorkers).To(Equal(int32(2)))
+	g.Expect(cluster.Status.UnavailableControlPlane).To(Equal(int32(1)))
+	g.Expect(cluster.Status.RemediatingMachines).To(Equal(int32(2)))
+}
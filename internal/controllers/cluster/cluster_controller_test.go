@@ -17,7 +17,9 @@ limitations under the License.
 package cluster
 
 import (
+	"fmt"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
@@ -28,6 +30,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
@@ -402,6 +405,50 @@ func TestClusterReconciler_reconcileDelete(t *testing.T) {
 	}
 }
 
+func TestClusterReconciler_reconcileDeleteWorkerMachines(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := builder.Cluster("test-ns", "test-cluster").Build()
+
+	objs := []client.Object{}
+	for i := 0; i < 5; i++ {
+		m := &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       fmt.Sprintf("worker-%d", i),
+				Namespace:  cluster.Namespace,
+				Finalizers: []string{clusterv1.MachineFinalizer},
+			},
+		}
+		if i == 4 {
+			m.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		}
+		objs = append(objs, m)
+	}
+	fakeClient := fake.NewClientBuilder().WithObjects(objs...).Build()
+
+	r := &Reconciler{
+		Client:                         fakeClient,
+		WorkerMachineDeletionBatchSize: 3,
+	}
+
+	machines := &clusterv1.MachineList{}
+	g.Expect(fakeClient.List(ctx, machines)).To(Succeed())
+
+	res, err := r.reconcileDeleteWorkerMachines(ctx, *machines)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(res.RequeueAfter).To(Equal(deleteRequeueAfter))
+
+	// The Machine already being deleted counts against the budget, so only 2 more Machines are deleted.
+	g.Expect(fakeClient.List(ctx, machines)).To(Succeed())
+	deleting := []string{}
+	for _, m := range machines.Items {
+		if !m.DeletionTimestamp.IsZero() {
+			deleting = append(deleting, m.Name)
+		}
+	}
+	g.Expect(deleting).To(ConsistOf("worker-0", "worker-1", "worker-4"))
+}
+
 func TestClusterReconcilerNodeRef(t *testing.T) {
 	t.Run("machine to cluster", func(t *testing.T) {
 		cluster := &clusterv1.Cluster{
@@ -506,19 +553,31 @@ func TestClusterReconcilerNodeRef(t *testing.T) {
 				},
 			},
 			{
-				name: "controlplane machine, noderef is not set",
+				name: "controlplane machine, noderef is not set, should return cluster",
 				o:    controlPlaneWithoutNoderef,
-				want: nil,
+				want: []ctrl.Request{
+					{
+						NamespacedName: util.ObjectKey(cluster),
+					},
+				},
 			},
 			{
-				name: "not controlplane machine, noderef is set",
+				name: "not controlplane machine, noderef is set, should return cluster",
 				o:    nonControlPlaneWithNoderef,
-				want: nil,
+				want: []ctrl.Request{
+					{
+						NamespacedName: util.ObjectKey(cluster),
+					},
+				},
 			},
 			{
-				name: "not controlplane machine, noderef is not set",
+				name: "not controlplane machine, noderef is not set, should return cluster",
 				o:    nonControlPlaneWithoutNoderef,
-				want: nil,
+				want: []ctrl.Request{
+					{
+						NamespacedName: util.ObjectKey(cluster),
+					},
+				},
 			},
 		}
 		for _, tt := range tests {
@@ -530,13 +589,141 @@ func TestClusterReconcilerNodeRef(t *testing.T) {
 					Client:                    c,
 					UnstructuredCachingClient: c,
 				}
-				requests := r.controlPlaneMachineToCluster(ctx, tt.o)
+				requests := r.machineToCluster(ctx, tt.o)
 				g.Expect(requests).To(Equal(tt.want))
 			})
 		}
 	})
 }
 
+func TestMachineStatusChanged(t *testing.T) {
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "machine",
+			Namespace: "test",
+			Labels:    map[string]string{clusterv1.ClusterNameLabel: "test-cluster"},
+		},
+		Spec: clusterv1.MachineSpec{
+			ClusterName: "test-cluster",
+		},
+	}
+
+	tests := []struct {
+		name   string
+		modify func(m *clusterv1.Machine)
+		want   bool
+	}{
+		{
+			name:   "unrelated change",
+			modify: func(m *clusterv1.Machine) { m.Annotations = map[string]string{"foo": "bar"} },
+			want:   false,
+		},
+		{
+			name: "unrelated condition change",
+			modify: func(m *clusterv1.Machine) {
+				conditions.MarkFalse(m, clusterv1.DrainingSucceededCondition, clusterv1.DrainingReason, clusterv1.ConditionSeverityInfo, "")
+			},
+			want: false,
+		},
+		{
+			name:   "nodeRef set",
+			modify: func(m *clusterv1.Machine) { m.Status.NodeRef = &corev1.ObjectReference{Kind: "Node", Name: "node"} },
+			want:   true,
+		},
+		{
+			name: "deletionTimestamp set",
+			modify: func(m *clusterv1.Machine) {
+				m.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+			},
+			want: true,
+		},
+		{
+			name:   "control plane label added",
+			modify: func(m *clusterv1.Machine) { m.Labels[clusterv1.MachineControlPlaneLabel] = "" },
+			want:   true,
+		},
+		{
+			name: "remediation requested",
+			modify: func(m *clusterv1.Machine) {
+				conditions.MarkFalse(m, clusterv1.MachineOwnerRemediatedCondition, clusterv1.WaitingForRemediationReason, clusterv1.ConditionSeverityWarning, "")
+			},
+			want: true,
+		},
+		{
+			name: "machine becomes ready",
+			modify: func(m *clusterv1.Machine) {
+				m.Status.NodeRef = &corev1.ObjectReference{Kind: "Node", Name: "node"}
+				conditions.MarkTrue(m, clusterv1.ReadyCondition)
+				conditions.MarkTrue(m, clusterv1.MachineNodeHealthyCondition)
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			newMachine := machine.DeepCopy()
+			tt.modify(newMachine)
+
+			got := machineStatusChanged(ctrl.LoggerFrom(ctx)).Update(event.UpdateEvent{ObjectOld: machine, ObjectNew: newMachine})
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestMachineDeploymentTemplateChanged(t *testing.T) {
+	md := &clusterv1.MachineDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "md",
+			Namespace: "test",
+		},
+		Spec: clusterv1.MachineDeploymentSpec{
+			ClusterName: "test-cluster",
+			Replicas:    pointer.Int32(1),
+			Template: clusterv1.MachineTemplateSpec{
+				Spec: clusterv1.MachineSpec{
+					ClusterName: "test-cluster",
+					Version:     pointer.String("v1.27.0"),
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		modify func(md *clusterv1.MachineDeployment)
+		want   bool
+	}{
+		{
+			name:   "replicas change",
+			modify: func(md *clusterv1.MachineDeployment) { md.Spec.Replicas = pointer.Int32(3) },
+			want:   false,
+		},
+		{
+			name:   "status change",
+			modify: func(md *clusterv1.MachineDeployment) { md.Status.ReadyReplicas = 1 },
+			want:   false,
+		},
+		{
+			name:   "template change",
+			modify: func(md *clusterv1.MachineDeployment) { md.Spec.Template.Spec.Version = pointer.String("v1.28.0") },
+			want:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			newMD := md.DeepCopy()
+			tt.modify(newMD)
+
+			got := machineDeploymentTemplateChanged(ctrl.LoggerFrom(ctx)).Update(event.UpdateEvent{ObjectOld: md, ObjectNew: newMD})
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
 type machineDeploymentBuilder struct {
 	md clusterv1.MachineDeployment
 }
@@ -0,0 +1,346 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterclassreplication
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/external"
+	tlog "sigs.k8s.io/cluster-api/internal/log"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+)
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io;bootstrap.cluster.x-k8s.io;controlplane.cluster.x-k8s.io,resources=*,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusterclasses,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+
+// Reconciler replicates the ClusterClasses and the referenced templates from the source namespace
+// to all the namespaces with the ClusterClassReplicationLabel set to "true".
+// Requests are keyed by the name of the target Namespace.
+type Reconciler struct {
+	Client client.Client
+
+	// UnstructuredCachingClient provides a client that forces caching of unstructured objects,
+	// thus allowing to optimize reads for templates or provider specific objects.
+	UnstructuredCachingClient client.Client
+
+	// SourceNamespace is the namespace of the ClusterClasses and templates to be replicated.
+	SourceNamespace string
+
+	// WatchFilterValue is the label value used to filter events prior to reconciliation.
+	WatchFilterValue string
+}
+
+func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	if r.SourceNamespace == "" {
+		return errors.New("the ClusterClass replication source namespace must be set")
+	}
+
+	err := ctrl.NewControllerManagedBy(mgr).
+		For(&corev1.Namespace{}).
+		Named("clusterclassreplication").
+		WithOptions(options).
+		Watches(
+			&clusterv1.ClusterClass{},
+			handler.EnqueueRequestsFromMapFunc(r.clusterClassToNamespaces),
+		).
+		WithEventFilter(predicates.ResourceHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
+		Complete(r)
+
+	if err != nil {
+		return errors.Wrap(err, "failed setting up with a controller manager")
+	}
+	return nil
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	namespace := &corev1.Namespace{}
+	if err := r.Client.Get(ctx, req.NamespacedName, namespace); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return ctrl.Result{}, err
+	}
+
+	// The source namespace is never a replication target.
+	if namespace.Name == r.SourceNamespace {
+		return ctrl.Result{}, nil
+	}
+
+	// Collect the ClusterClasses to be replicated; if the Namespace did not opt-in (anymore)
+	// or it is being deleted, there are none and all the existing replicas are deleted.
+	desired := []clusterv1.ClusterClass{}
+	if namespace.DeletionTimestamp.IsZero() && namespace.Labels[clusterv1.ClusterClassReplicationLabel] == "true" {
+		sourceList := &clusterv1.ClusterClassList{}
+		if err := r.Client.List(ctx, sourceList, client.InNamespace(r.SourceNamespace)); err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to list ClusterClasses in namespace %s", r.SourceNamespace)
+		}
+		for _, clusterClass := range sourceList.Items {
+			if clusterClass.DeletionTimestamp.IsZero() {
+				desired = append(desired, clusterClass)
+			}
+		}
+	}
+
+	replicaList := &clusterv1.ClusterClassList{}
+	if err := r.Client.List(ctx, replicaList, client.InNamespace(namespace.Name), client.MatchingLabels{clusterv1.ClusterClassReplicatedFromLabel: r.SourceNamespace}); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to list replicated ClusterClasses in namespace %s", namespace.Name)
+	}
+
+	errs := []error{}
+	desiredNames := sets.Set[string]{}
+	for i := range desired {
+		desiredNames.Insert(desired[i].Name)
+		if err := r.reconcileClusterClass(ctx, &desired[i], namespace.Name); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// Delete the replicas which are not desired anymore; the ClusterClass webhook prevents the deletion
+	// of ClusterClasses still in use by Clusters, thus those replicas are retained until the Clusters are gone.
+	// NOTE: the replicated templates are garbage collected by Kubernetes, because the ClusterClass controller
+	// sets the ClusterClass as owner of the templates.
+	for i := range replicaList.Items {
+		replica := &replicaList.Items[i]
+		if desiredNames.Has(replica.Name) {
+			continue
+		}
+		log.Info("Deleting replicated ClusterClass", "ClusterClass", klog.KObj(replica))
+		if err := r.Client.Delete(ctx, replica); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, errors.Wrapf(err, "failed to delete replicated %s", tlog.KObj{Obj: replica}))
+		}
+	}
+
+	return ctrl.Result{}, kerrors.NewAggregate(errs)
+}
+
+// reconcileClusterClass replicates a ClusterClass and the referenced templates to the target namespace.
+func (r *Reconciler) reconcileClusterClass(ctx context.Context, source *clusterv1.ClusterClass, namespace string) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	// Replicate templates first, so they already exist when the ClusterClass is created.
+	for _, ref := range templateRefs(source) {
+		if err := r.reconcileTemplate(ctx, ref, namespace); err != nil {
+			return errors.Wrapf(err, "failed to replicate templates of ClusterClass %s", klog.KObj(source))
+		}
+	}
+
+	desired := &clusterv1.ClusterClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        source.Name,
+			Namespace:   namespace,
+			Labels:      replicaLabels(source.Labels, r.SourceNamespace),
+			Annotations: replicaAnnotations(source.Annotations),
+		},
+		Spec: *source.Spec.DeepCopy(),
+	}
+	for _, ref := range templateRefs(desired) {
+		ref.Namespace = namespace
+	}
+
+	current := &clusterv1.ClusterClass{}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(desired), current); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to get %s", tlog.KObj{Obj: desired})
+		}
+		log.Info("Creating replicated ClusterClass", "ClusterClass", klog.KObj(desired))
+		if err := r.Client.Create(ctx, desired); err != nil {
+			return errors.Wrapf(err, "failed to create %s", tlog.KObj{Obj: desired})
+		}
+		return nil
+	}
+
+	if current.Labels[clusterv1.ClusterClassReplicatedFromLabel] != r.SourceNamespace {
+		return errors.Errorf("failed to replicate ClusterClass %s: a ClusterClass with the same name not managed by replication already exists", klog.KObj(desired))
+	}
+
+	patchHelper, err := patch.NewHelper(current, r.Client)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create patch helper for %s", tlog.KObj{Obj: current})
+	}
+	current.Labels = desired.Labels
+	current.Annotations = desired.Annotations
+	current.Spec = desired.Spec
+	if err := patchHelper.Patch(ctx, current); err != nil {
+		return errors.Wrapf(err, "failed to patch %s", tlog.KObj{Obj: current})
+	}
+	return nil
+}
+
+// reconcileTemplate replicates a template to the target namespace.
+// Labels and annotations of existing replicas are kept in sync with the source template. Templates are usually
+// immutable, so when the spec of the source template changes, as tracked by its generation, the replica is
+// deleted and created again.
+func (r *Reconciler) reconcileTemplate(ctx context.Context, ref *corev1.ObjectReference, namespace string) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	source, err := external.Get(ctx, r.UnstructuredCachingClient, ref, r.SourceNamespace)
+	if err != nil {
+		return err
+	}
+
+	desired := &unstructured.Unstructured{}
+	desired.SetGroupVersionKind(source.GroupVersionKind())
+	desired.SetName(source.GetName())
+	desired.SetNamespace(namespace)
+	desired.SetLabels(replicaLabels(source.GetLabels(), r.SourceNamespace))
+	annotations := replicaAnnotations(source.GetAnnotations())
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[clusterv1.ClusterClassReplicatedGenerationAnnotation] = strconv.FormatInt(source.GetGeneration(), 10)
+	desired.SetAnnotations(annotations)
+	if spec, ok := source.Object["spec"]; ok {
+		desired.Object["spec"] = spec
+	}
+
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(source.GroupVersionKind())
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(desired), current); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to get %s %s", source.GetKind(), klog.KObj(desired))
+		}
+		log.Info("Creating replicated template", source.GetKind(), klog.KObj(desired))
+		if err := r.Client.Create(ctx, desired); err != nil {
+			return errors.Wrapf(err, "failed to create %s %s", source.GetKind(), klog.KObj(desired))
+		}
+		return nil
+	}
+
+	if current.GetLabels()[clusterv1.ClusterClassReplicatedFromLabel] != r.SourceNamespace {
+		return errors.Errorf("a %s with name %s not managed by replication already exists in namespace %s", source.GetKind(), source.GetName(), namespace)
+	}
+
+	// The spec of the source template changed; replace the replica, because templates are usually immutable.
+	if current.GetAnnotations()[clusterv1.ClusterClassReplicatedGenerationAnnotation] != desired.GetAnnotations()[clusterv1.ClusterClassReplicatedGenerationAnnotation] {
+		if !current.GetDeletionTimestamp().IsZero() {
+			return errors.Errorf("waiting for %s %s to be deleted before replicating it again", source.GetKind(), klog.KObj(current))
+		}
+		log.Info("Replacing replicated template", source.GetKind(), klog.KObj(current))
+		uid := current.GetUID()
+		if err := r.Client.Delete(ctx, current, client.Preconditions{UID: &uid}); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete %s %s", source.GetKind(), klog.KObj(current))
+		}
+		if err := r.Client.Create(ctx, desired); err != nil {
+			return errors.Wrapf(err, "failed to create %s %s", source.GetKind(), klog.KObj(desired))
+		}
+		return nil
+	}
+
+	patchHelper, err := patch.NewHelper(current, r.Client)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create patch helper for %s %s", source.GetKind(), klog.KObj(current))
+	}
+	current.SetLabels(desired.GetLabels())
+	current.SetAnnotations(desired.GetAnnotations())
+	if err := patchHelper.Patch(ctx, current); err != nil {
+		return errors.Wrapf(err, "failed to patch %s %s", source.GetKind(), klog.KObj(current))
+	}
+	return nil
+}
+
+// clusterClassToNamespaces maps ClusterClasses in the source namespace to all the namespaces opting-in to replication,
+// and replicated ClusterClasses to their namespace.
+func (r *Reconciler) clusterClassToNamespaces(ctx context.Context, o client.Object) []reconcile.Request {
+	if o.GetNamespace() != r.SourceNamespace {
+		if o.GetLabels()[clusterv1.ClusterClassReplicatedFromLabel] == r.SourceNamespace {
+			return []reconcile.Request{{NamespacedName: client.ObjectKey{Name: o.GetNamespace()}}}
+		}
+		return nil
+	}
+
+	namespaceList := &corev1.NamespaceList{}
+	if err := r.Client.List(ctx, namespaceList, client.MatchingLabels{clusterv1.ClusterClassReplicationLabel: "true"}); err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "failed to list Namespaces opting-in to ClusterClass replication")
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(namespaceList.Items))
+	for _, namespace := range namespaceList.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKey{Name: namespace.Name}})
+	}
+	return requests
+}
+
+// templateRefs returns the unique references to templates of a ClusterClass.
+func templateRefs(clusterClass *clusterv1.ClusterClass) []*corev1.ObjectReference {
+	refs := []*corev1.ObjectReference{}
+	appendRef := func(ref *corev1.ObjectReference) {
+		if ref != nil {
+			refs = append(refs, ref)
+		}
+	}
+
+	appendRef(clusterClass.Spec.Infrastructure.Ref)
+	appendRef(clusterClass.Spec.ControlPlane.Ref)
+	if clusterClass.Spec.ControlPlane.MachineInfrastructure != nil {
+		appendRef(clusterClass.Spec.ControlPlane.MachineInfrastructure.Ref)
+	}
+	for i := range clusterClass.Spec.Workers.MachineDeployments {
+		appendRef(clusterClass.Spec.Workers.MachineDeployments[i].Template.Bootstrap.Ref)
+		appendRef(clusterClass.Spec.Workers.MachineDeployments[i].Template.Infrastructure.Ref)
+	}
+	for i := range clusterClass.Spec.Workers.MachinePools {
+		appendRef(clusterClass.Spec.Workers.MachinePools[i].Template.Bootstrap.Ref)
+		appendRef(clusterClass.Spec.Workers.MachinePools[i].Template.Infrastructure.Ref)
+	}
+	return refs
+}
+
+// replicaLabels returns the labels of a replica, marking it as managed by replication.
+func replicaLabels(labels map[string]string, sourceNamespace string) map[string]string {
+	ret := map[string]string{}
+	for k, v := range labels {
+		ret[k] = v
+	}
+	ret[clusterv1.ClusterClassReplicatedFromLabel] = sourceNamespace
+	return ret
+}
+
+// replicaAnnotations returns the annotations of a replica, dropping the kubectl last-applied-configuration annotation.
+func replicaAnnotations(annotations map[string]string) map[string]string {
+	if len(annotations) == 0 {
+		return nil
+	}
+	ret := map[string]string{}
+	for k, v := range annotations {
+		if k == corev1.LastAppliedConfigAnnotation {
+			continue
+		}
+		ret[k] = v
+	}
+	return ret
+}
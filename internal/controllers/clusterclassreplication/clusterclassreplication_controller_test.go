@@ -0,0 +1,236 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterclassreplication
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/test/builder"
+)
+
+var (
+	ctx        = ctrl.SetupSignalHandler()
+	fakeScheme = runtime.NewScheme()
+)
+
+func init() {
+	_ = clientgoscheme.AddToScheme(fakeScheme)
+	_ = clusterv1.AddToScheme(fakeScheme)
+}
+
+const sourceNamespace = "platform"
+
+func TestReconcile(t *testing.T) {
+	infraClusterTemplate := builder.InfrastructureClusterTemplate(sourceNamespace, "infra-cluster-template").Build()
+	controlPlaneTemplate := builder.ControlPlaneTemplate(sourceNamespace, "control-plane-template").Build()
+	infraMachineTemplate := builder.InfrastructureMachineTemplate(sourceNamespace, "infra-machine-template").Build()
+	bootstrapTemplate := builder.BootstrapTemplate(sourceNamespace, "bootstrap-template").Build()
+	sourceClusterClass := builder.ClusterClass(sourceNamespace, "class").
+		WithInfrastructureClusterTemplate(infraClusterTemplate).
+		WithControlPlaneTemplate(controlPlaneTemplate).
+		WithControlPlaneInfrastructureMachineTemplate(infraMachineTemplate).
+		WithWorkerMachineDeploymentClasses(
+			*builder.MachineDeploymentClass("md-class").
+				WithInfrastructureTemplate(infraMachineTemplate).
+				WithBootstrapTemplate(bootstrapTemplate).
+				Build(),
+		).
+		Build()
+	sourceObjs := []client.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: sourceNamespace}},
+		sourceClusterClass, infraClusterTemplate, controlPlaneTemplate, infraMachineTemplate, bootstrapTemplate,
+	}
+
+	namespace := func(name string, optIn bool) *corev1.Namespace {
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if optIn {
+			ns.Labels = map[string]string{clusterv1.ClusterClassReplicationLabel: "true"}
+		}
+		return ns
+	}
+	replica := func(namespace string) *clusterv1.ClusterClass {
+		cc := builder.ClusterClass(namespace, "class").Build()
+		cc.Labels = map[string]string{clusterv1.ClusterClassReplicatedFromLabel: sourceNamespace}
+		return cc
+	}
+
+	t.Run("replicates ClusterClasses and templates in namespaces opting-in", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(sourceObjs...).WithObjects(namespace("tenant", true)).Build()
+		r := &Reconciler{Client: c, UnstructuredCachingClient: c, SourceNamespace: sourceNamespace}
+
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKey{Name: "tenant"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		got := &clusterv1.ClusterClass{}
+		g.Expect(c.Get(ctx, client.ObjectKey{Namespace: "tenant", Name: "class"}, got)).To(Succeed())
+		g.Expect(got.Labels).To(HaveKeyWithValue(clusterv1.ClusterClassReplicatedFromLabel, sourceNamespace))
+		for _, ref := range templateRefs(got) {
+			g.Expect(ref.Namespace).To(Equal("tenant"))
+		}
+
+		for _, template := range []*unstructured.Unstructured{infraClusterTemplate, controlPlaneTemplate, infraMachineTemplate, bootstrapTemplate} {
+			gotTemplate := &unstructured.Unstructured{}
+			gotTemplate.SetGroupVersionKind(template.GroupVersionKind())
+			g.Expect(c.Get(ctx, client.ObjectKey{Namespace: "tenant", Name: template.GetName()}, gotTemplate)).To(Succeed())
+			g.Expect(gotTemplate.GetLabels()).To(HaveKeyWithValue(clusterv1.ClusterClassReplicatedFromLabel, sourceNamespace))
+			g.Expect(gotTemplate.Object["spec"]).To(Equal(template.Object["spec"]))
+		}
+	})
+
+	t.Run("updates existing replicas", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(sourceObjs...).WithObjects(namespace("tenant", true), replica("tenant")).Build()
+		r := &Reconciler{Client: c, UnstructuredCachingClient: c, SourceNamespace: sourceNamespace}
+
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKey{Name: "tenant"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		got := &clusterv1.ClusterClass{}
+		g.Expect(c.Get(ctx, client.ObjectKey{Namespace: "tenant", Name: "class"}, got)).To(Succeed())
+		g.Expect(got.Spec.Infrastructure.Ref).ToNot(BeNil())
+		g.Expect(got.Spec.Infrastructure.Ref.Name).To(Equal(infraClusterTemplate.GetName()))
+		g.Expect(got.Spec.Workers.MachineDeployments).To(HaveLen(1))
+	})
+
+	t.Run("updates existing template replicas", func(t *testing.T) {
+		g := NewWithT(t)
+
+		// The spec of the source InfrastructureClusterTemplate changed, the replica is replaced.
+		changedInfraClusterTemplate := infraClusterTemplate.DeepCopy()
+		changedInfraClusterTemplate.SetGeneration(2)
+		outdatedInfraClusterTemplate := infraClusterTemplate.DeepCopy()
+		outdatedInfraClusterTemplate.SetNamespace("tenant")
+		outdatedInfraClusterTemplate.SetLabels(map[string]string{clusterv1.ClusterClassReplicatedFromLabel: sourceNamespace})
+		outdatedInfraClusterTemplate.SetAnnotations(map[string]string{clusterv1.ClusterClassReplicatedGenerationAnnotation: "1"})
+		g.Expect(unstructured.SetNestedField(outdatedInfraClusterTemplate.Object, "outdated", "spec", "template", "spec", "foo")).To(Succeed())
+
+		// Only the labels of the source ControlPlaneTemplate changed, the replica is patched.
+		labeledControlPlaneTemplate := controlPlaneTemplate.DeepCopy()
+		labeledControlPlaneTemplate.SetLabels(map[string]string{"foo": "bar"})
+		controlPlaneTemplateReplica := controlPlaneTemplate.DeepCopy()
+		controlPlaneTemplateReplica.SetNamespace("tenant")
+		controlPlaneTemplateReplica.SetLabels(map[string]string{clusterv1.ClusterClassReplicatedFromLabel: sourceNamespace})
+		controlPlaneTemplateReplica.SetAnnotations(map[string]string{clusterv1.ClusterClassReplicatedGenerationAnnotation: "0"})
+
+		c := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: sourceNamespace}}, namespace("tenant", true),
+			sourceClusterClass, changedInfraClusterTemplate, labeledControlPlaneTemplate, infraMachineTemplate, bootstrapTemplate,
+			outdatedInfraClusterTemplate, controlPlaneTemplateReplica,
+		).Build()
+		r := &Reconciler{Client: c, UnstructuredCachingClient: c, SourceNamespace: sourceNamespace}
+
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKey{Name: "tenant"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		gotInfraClusterTemplate := &unstructured.Unstructured{}
+		gotInfraClusterTemplate.SetGroupVersionKind(infraClusterTemplate.GroupVersionKind())
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(outdatedInfraClusterTemplate), gotInfraClusterTemplate)).To(Succeed())
+		g.Expect(gotInfraClusterTemplate.Object["spec"]).To(Equal(changedInfraClusterTemplate.Object["spec"]))
+		g.Expect(gotInfraClusterTemplate.GetAnnotations()).To(HaveKeyWithValue(clusterv1.ClusterClassReplicatedGenerationAnnotation, "2"))
+
+		gotControlPlaneTemplate := &unstructured.Unstructured{}
+		gotControlPlaneTemplate.SetGroupVersionKind(controlPlaneTemplate.GroupVersionKind())
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(controlPlaneTemplateReplica), gotControlPlaneTemplate)).To(Succeed())
+		g.Expect(gotControlPlaneTemplate.GetAnnotations()).To(HaveKeyWithValue(clusterv1.ClusterClassReplicatedGenerationAnnotation, "0"))
+		g.Expect(gotControlPlaneTemplate.GetLabels()).To(HaveKeyWithValue("foo", "bar"))
+	})
+
+	t.Run("deletes replicas in namespaces not opting-in", func(t *testing.T) {
+		g := NewWithT(t)
+
+		userClusterClass := builder.ClusterClass("tenant", "user-class").Build()
+		c := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(sourceObjs...).WithObjects(namespace("tenant", false), replica("tenant"), userClusterClass).Build()
+		r := &Reconciler{Client: c, UnstructuredCachingClient: c, SourceNamespace: sourceNamespace}
+
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKey{Name: "tenant"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		err = c.Get(ctx, client.ObjectKey{Namespace: "tenant", Name: "class"}, &clusterv1.ClusterClass{})
+		g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(userClusterClass), &clusterv1.ClusterClass{})).To(Succeed())
+	})
+
+	t.Run("does not overwrite ClusterClasses not managed by replication", func(t *testing.T) {
+		g := NewWithT(t)
+
+		userClusterClass := builder.ClusterClass("tenant", "class").Build()
+		c := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(sourceObjs...).WithObjects(namespace("tenant", true), userClusterClass).Build()
+		r := &Reconciler{Client: c, UnstructuredCachingClient: c, SourceNamespace: sourceNamespace}
+
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKey{Name: "tenant"}})
+		g.Expect(err).To(HaveOccurred())
+
+		got := &clusterv1.ClusterClass{}
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(userClusterClass), got)).To(Succeed())
+		g.Expect(got.Labels).ToNot(HaveKey(clusterv1.ClusterClassReplicatedFromLabel))
+	})
+
+	t.Run("ignores the source namespace", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(sourceObjs...).Build()
+		r := &Reconciler{Client: c, UnstructuredCachingClient: c, SourceNamespace: sourceNamespace}
+
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKey{Name: sourceNamespace}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		got := &clusterv1.ClusterClass{}
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(sourceClusterClass), got)).To(Succeed())
+		g.Expect(got.Labels).ToNot(HaveKey(clusterv1.ClusterClassReplicatedFromLabel))
+	})
+}
+
+func TestClusterClassToNamespaces(t *testing.T) {
+	g := NewWithT(t)
+
+	c := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-a", Labels: map[string]string{clusterv1.ClusterClassReplicationLabel: "true"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-b", Labels: map[string]string{clusterv1.ClusterClassReplicationLabel: "true"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "other"}},
+	).Build()
+	r := &Reconciler{Client: c, SourceNamespace: sourceNamespace}
+
+	source := builder.ClusterClass(sourceNamespace, "class").Build()
+	g.Expect(r.clusterClassToNamespaces(ctx, source)).To(ConsistOf(
+		reconcile.Request{NamespacedName: client.ObjectKey{Name: "tenant-a"}},
+		reconcile.Request{NamespacedName: client.ObjectKey{Name: "tenant-b"}},
+	))
+
+	replica := builder.ClusterClass("tenant-a", "class").Build()
+	replica.Labels = map[string]string{clusterv1.ClusterClassReplicatedFromLabel: sourceNamespace}
+	g.Expect(r.clusterClassToNamespaces(ctx, replica)).To(ConsistOf(
+		reconcile.Request{NamespacedName: client.ObjectKey{Name: "tenant-a"}},
+	))
+
+	g.Expect(r.clusterClassToNamespaces(ctx, builder.ClusterClass("other", "class").Build())).To(BeEmpty())
+}
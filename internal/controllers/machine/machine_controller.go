@@ -19,13 +19,17 @@ package machine
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -51,10 +55,14 @@ import (
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/collections"
+	"sigs.k8s.io/cluster-api/util/concurrency"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/events"
 	clog "sigs.k8s.io/cluster-api/util/log"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
+	"sigs.k8s.io/cluster-api/util/priority"
+	"sigs.k8s.io/cluster-api/util/tracing"
 )
 
 var (
@@ -70,6 +78,7 @@ var (
 // +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io;bootstrap.cluster.x-k8s.io,resources=*,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines;machines/status;machines/finalizers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinedrainrules,verbs=get;list;watch
 // +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch
 
 // Reconciler reconciles a Machine object.
@@ -85,9 +94,37 @@ type Reconciler struct {
 	// NodeDrainClientTimeout timeout of the client used for draining nodes.
 	NodeDrainClientTimeout time.Duration
 
+	// UnreachableClusterTimeout is the duration after which node drain and wait for volume detach are skipped
+	// when deleting a Machine whose workload cluster control plane cannot be reached. If zero, they are never skipped.
+	UnreachableClusterTimeout time.Duration
+
+	// PreDrainTaint is the taint added to the node of a Machine being deleted before evicting its Pods, e.g.
+	// ToBeDeletedByClusterAutoscaler:NoSchedule. If nil, the node is not tainted.
+	PreDrainTaint *corev1.Taint
+
+	// PreDrainTaintObservationPeriod is how long to wait after adding the PreDrainTaint to the node
+	// before evicting its Pods. It is ignored if PreDrainTaint is nil.
+	PreDrainTaintObservationPeriod time.Duration
+
+	// ReconcilePriorityWeights are the weights of the priority queue serving the requests for the objects of
+	// Clusters by their reconcile priority. If not set, requests are served in the order they are received.
+	ReconcilePriorityWeights priority.Weights
+
+	// NamespaceConcurrency is the maximum number of objects of the same namespace reconciled at the same time.
+	// If zero, it is not limited.
+	NamespaceConcurrency int
+
+	// MachineToNodeLabelDomains are the label domains of the Machine labels propagated to the Node; a domain with
+	// the "*." prefix matches all the subdomains of the domain. If empty, labels in the node-role.kubernetes.io,
+	// node-restriction.kubernetes.io and node.cluster.x-k8s.io domains are propagated.
+	MachineToNodeLabelDomains []string
+
+	// NodeToMachineLabels are the keys of the Node labels reflected on the Machine, e.g. topology.kubernetes.io/zone.
+	NodeToMachineLabels []string
+
 	controller      controller.Controller
 	recorder        record.EventRecorder
-	externalTracker external.ObjectTracker
+	externalTracker *external.ReferenceTracker
 
 	// nodeDeletionRetryTimeout determines how long the controller will retry deleting a node
 	// during a single reconciliation.
@@ -105,6 +142,11 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opt
 		r.nodeDeletionRetryTimeout = 10 * time.Second
 	}
 
+	reconciler, err := priority.NewReconciler(mgr, concurrency.NewNamespaceLimitedReconciler(tracing.NewReconciler(r, "Machine"), r.NamespaceConcurrency), &clusterv1.Machine{}, r.ReconcilePriorityWeights, options.MaxConcurrentReconciles)
+	if err != nil {
+		return errors.Wrap(err, "failed setting up with a controller manager")
+	}
+
 	c, err := ctrl.NewControllerManagedBy(mgr).
 		For(&clusterv1.Machine{}).
 		WithOptions(options).
@@ -122,14 +164,14 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opt
 					predicates.ResourceHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue),
 				),
 			)).
-		Build(r)
+		Build(reconciler)
 	if err != nil {
 		return errors.Wrap(err, "failed setting up with a controller manager")
 	}
 
 	r.controller = c
 	r.recorder = mgr.GetEventRecorderFor("machine-controller")
-	r.externalTracker = external.ObjectTracker{
+	r.externalTracker = &external.ReferenceTracker{
 		Controller: c,
 		Cache:      mgr.GetCache(),
 	}
@@ -142,8 +184,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 	m := &clusterv1.Machine{}
 	if err := r.Client.Get(ctx, req.NamespacedName, m); err != nil {
 		if apierrors.IsNotFound(err) {
-			// Object not found, return.  Created objects are automatically garbage collected.
+			// Object not found, stop tracking the objects it references and return.
+			// Created objects are automatically garbage collected.
 			// For additional cleanup logic use finalizers.
+			if r.externalTracker != nil {
+				r.externalTracker.Untrack(req.NamespacedName)
+			}
 			return ctrl.Result{}, nil
 		}
 
@@ -160,6 +206,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 
 	log = log.WithValues("Cluster", klog.KRef(m.ObjectMeta.Namespace, m.Spec.ClusterName))
 	ctx = ctrl.LoggerInto(ctx, log)
+	ctx, log = clog.AddOperationID(ctx, m)
 
 	cluster, err := util.GetClusterByName(ctx, r.Client, m.ObjectMeta.Namespace, m.Spec.ClusterName)
 	if err != nil {
@@ -199,6 +246,16 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 	}
 	m.Labels[clusterv1.ClusterNameLabel] = m.Spec.ClusterName
 
+	// Watch the bootstrap and infrastructure objects referenced by the Machine, so the Machine is reconciled
+	// as soon as they are created, change or are deleted; this applies to deleting Machines too, which are
+	// waiting for the referenced objects to go away.
+	if r.externalTracker != nil {
+		refs := []*corev1.ObjectReference{m.Spec.Bootstrap.ConfigRef, &m.Spec.InfrastructureRef}
+		if err := r.externalTracker.Track(log, client.ObjectKeyFromObject(m), refs); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
 	// Handle deletion reconciliation loop.
 	if !m.ObjectMeta.DeletionTimestamp.IsZero() {
 		res, err := r.reconcileDelete(ctx, cluster, m)
@@ -258,9 +315,11 @@ func patchMachine(ctx context.Context, patchHelper *patch.Helper, machine *clust
 			clusterv1.ReadyCondition,
 			clusterv1.BootstrapReadyCondition,
 			clusterv1.InfrastructureReadyCondition,
+			clusterv1.BootstrapExecSucceededCondition,
 			clusterv1.DrainingSucceededCondition,
 			clusterv1.MachineHealthCheckSucceededCondition,
 			clusterv1.MachineOwnerRemediatedCondition,
+			clusterv1.WorkloadClusterReachableCondition,
 		}},
 	)
 
@@ -352,40 +411,95 @@ func (r *Reconciler) reconcileDelete(ctx context.Context, cluster *clusterv1.Clu
 		}
 		conditions.MarkTrue(m, clusterv1.PreDrainDeleteHookSucceededCondition)
 
+		// Skip drain and wait for volume detach if the control plane has been unreachable for too long, as
+		// they cannot complete anyway.
+		// The skip is recorded in the DrainingSucceededCondition, so the event is only emitted when the Machine starts skipping.
+		skipNodeTeardown := r.unreachableClusterTimeoutExceeded(ctx, cluster, m)
+		if skipNodeTeardown && conditions.GetReason(m, clusterv1.DrainingSucceededCondition) != clusterv1.DrainingSkippedReason {
+			log.Info("Skipping node drain and wait for volume detach because the workload cluster is unreachable", "Node", klog.KRef("", m.Status.NodeRef.Name))
+			events.Warningf(r.recorder, m, events.SkippedNodeTeardownReason, "skipping drain and wait for volume detach of Machine's node %q: the workload cluster has been unreachable for more than %s", m.Status.NodeRef.Name, r.UnreachableClusterTimeout)
+			conditions.MarkFalse(m, clusterv1.DrainingSucceededCondition, clusterv1.DrainingSkippedReason, clusterv1.ConditionSeverityWarning,
+				"Drain and wait for volume detach skipped: the workload cluster has been unreachable for more than %s", r.UnreachableClusterTimeout)
+		}
+
 		// Drain node before deletion and issue a patch in order to make this operation visible to the users.
-		if r.isNodeDrainAllowed(m) {
+		if !skipNodeTeardown && r.isNodeDrainAllowed(m) {
 			patchHelper, err := patch.NewHelper(m, r.Client)
 			if err != nil {
 				return ctrl.Result{}, err
 			}
 
 			log.Info("Draining node", "Node", klog.KRef("", m.Status.NodeRef.Name))
-			// The DrainingSucceededCondition never exists before the node is drained for the first time,
-			// so its transition time can be used to record the first time draining.
-			// This `if` condition prevents the transition time to be changed more than once.
+			// The DrainingSucceededCondition never exists before the node is drained for the first time.
+			// NOTE: The time the drain started is recorded in status.nodeDrainStartTime, because the transition
+			// time of the condition changes whenever the drain fails with a different error.
 			if conditions.Get(m, clusterv1.DrainingSucceededCondition) == nil {
 				conditions.MarkFalse(m, clusterv1.DrainingSucceededCondition, clusterv1.DrainingReason, clusterv1.ConditionSeverityInfo, "Draining the node before deletion")
 			}
+			if m.Status.NodeDrainStartTime == nil {
+				m.Status.NodeDrainStartTime = &metav1.Time{Time: time.Now()}
+			}
 
 			if err := patchMachine(ctx, patchHelper, m); err != nil {
 				return ctrl.Result{}, errors.Wrap(err, "failed to patch Machine")
 			}
 
-			if result, err := r.drainNode(ctx, cluster, m.Status.NodeRef.Name); !result.IsZero() || err != nil {
-				if err != nil {
+			// Taint the node and give controllers watching the taint, e.g. external load balancer controllers,
+			// time to deregister the node before evicting Pods.
+			if r.PreDrainTaint != nil {
+				if err := r.taintNodeBeforeDrain(ctx, cluster, m.Status.NodeRef.Name); err != nil {
 					conditions.MarkFalse(m, clusterv1.DrainingSucceededCondition, clusterv1.DrainingFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
-					r.recorder.Eventf(m, corev1.EventTypeWarning, "FailedDrainNode", "error draining Machine's node %q: %v", m.Status.NodeRef.Name, err)
+					events.Warningf(r.recorder, m, events.FailedDrainNodeReason, "error tainting Machine's node %q: %v", m.Status.NodeRef.Name, err)
+					return ctrl.Result{}, err
 				}
+				if observationDeadline, ok := r.preDrainTaintObservationDeadline(m); ok {
+					if remaining := time.Until(observationDeadline); remaining > 0 {
+						log.Info("Waiting for the pre-drain taint observation period before evicting Pods", "Node", klog.KRef("", m.Status.NodeRef.Name), "taint", r.PreDrainTaint.ToString(), "remaining", remaining.Round(time.Second))
+						conditions.MarkFalse(m, clusterv1.DrainingSucceededCondition, clusterv1.DrainingReason, clusterv1.ConditionSeverityInfo,
+							"Waiting until %s after tainting the node with %s before evicting Pods", observationDeadline.UTC().Format(time.RFC3339), r.PreDrainTaint.ToString())
+						return ctrl.Result{RequeueAfter: remaining}, nil
+					}
+				}
+			}
+
+			// Once the eviction timeout is exceeded, Pods which could not be evicted, e.g. because of
+			// PodDisruptionBudgets, are deleted instead.
+			evictionDeadline, hasEvictionDeadline := nodeDrainEvictionDeadline(m)
+			fallbackToDelete := hasEvictionDeadline && !time.Now().Before(evictionDeadline)
+
+			result, blockingPods, err := r.drainNode(ctx, cluster, m.Status.NodeRef.Name, fallbackToDelete)
+			if err != nil {
+				conditions.MarkFalse(m, clusterv1.DrainingSucceededCondition, clusterv1.DrainingFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+				events.Warningf(r.recorder, m, events.FailedDrainNodeReason, "error draining Machine's node %q: %v", m.Status.NodeRef.Name, err)
 				return result, err
 			}
+			if len(blockingPods) > 0 {
+				// Surface the Pods blocking the drain, so it is possible to figure out what to do without
+				// accessing the workload cluster.
+				// NOTE: The details change every time the drain is retried, so they are reported in events and
+				// logs only, while the condition message is kept stable.
+				msg := drainBlockingPodsMessage(blockingPods)
+				if hasEvictionDeadline && !fallbackToDelete {
+					msg += fmt.Sprintf("; Pods not evicted by %s will be deleted without honoring PodDisruptionBudgets", evictionDeadline.UTC().Format(time.RFC3339))
+				}
+				log.Info("Draining node is blocked", "Node", klog.KRef("", m.Status.NodeRef.Name), "details", msg)
+				conditions.MarkFalse(m, clusterv1.DrainingSucceededCondition, clusterv1.DrainingReason, clusterv1.ConditionSeverityInfo, "Draining the node before deletion")
+				events.Warningf(r.recorder, m, events.DrainBlockedReason, "draining Machine's node %q is blocked: %s", m.Status.NodeRef.Name, msg)
+			}
+			if !result.IsZero() {
+				return result, nil
+			}
 
+			if conditions.IsFalse(m, clusterv1.DrainingSucceededCondition) {
+				observeDeletionStepDuration(m, drainDeletionStep, m.Status.NodeDrainStartTime.Time)
+			}
 			conditions.MarkTrue(m, clusterv1.DrainingSucceededCondition)
-			r.recorder.Eventf(m, corev1.EventTypeNormal, "SuccessfulDrainNode", "success draining Machine's node %q", m.Status.NodeRef.Name)
+			events.Normalf(r.recorder, m, events.SuccessfulDrainNodeReason, "success draining Machine's node %q", m.Status.NodeRef.Name)
 		}
 
 		// After node draining is completed, and if isNodeVolumeDetachingAllowed returns True, make sure all
 		// volumes are detached before proceeding to delete the Node.
-		if r.isNodeVolumeDetachingAllowed(m) {
+		if !skipNodeTeardown && r.isNodeVolumeDetachingAllowed(m) {
 			// The VolumeDetachSucceededCondition never exists before we wait for volume detachment for the first time,
 			// so its transition time can be used to record the first time we wait for volume detachment.
 			// This `if` condition prevents the transition time to be changed more than once.
@@ -395,14 +509,17 @@ func (r *Reconciler) reconcileDelete(ctx context.Context, cluster *clusterv1.Clu
 
 			if ok, err := r.shouldWaitForNodeVolumes(ctx, cluster, m.Status.NodeRef.Name); ok || err != nil {
 				if err != nil {
-					r.recorder.Eventf(m, corev1.EventTypeWarning, "FailedWaitForVolumeDetach", "error waiting for node volumes detaching, Machine's node %q: %v", m.Status.NodeRef.Name, err)
+					events.Warningf(r.recorder, m, events.FailedWaitForVolumeDetachReason, "error waiting for node volumes detaching, Machine's node %q: %v", m.Status.NodeRef.Name, err)
 					return ctrl.Result{}, err
 				}
 				log.Info("Waiting for node volumes to be detached", "Node", klog.KRef("", m.Status.NodeRef.Name))
 				return ctrl.Result{}, nil
 			}
+			if conditions.IsFalse(m, clusterv1.VolumeDetachSucceededCondition) {
+				observeDeletionStepDuration(m, volumeDetachDeletionStep, conditions.GetLastTransitionTime(m, clusterv1.VolumeDetachSucceededCondition).Time)
+			}
 			conditions.MarkTrue(m, clusterv1.VolumeDetachSucceededCondition)
-			r.recorder.Eventf(m, corev1.EventTypeNormal, "NodeVolumesDetached", "success waiting for node volumes detaching Machine's node %q", m.Status.NodeRef.Name)
+			events.Normalf(r.recorder, m, events.NodeVolumesDetachedReason, "success waiting for node volumes detaching Machine's node %q", m.Status.NodeRef.Name)
 		}
 	}
 
@@ -460,7 +577,7 @@ func (r *Reconciler) reconcileDelete(ctx context.Context, cluster *clusterv1.Clu
 		if waitErr != nil {
 			log.Error(deleteNodeErr, "Timed out deleting node", "Node", klog.KRef("", m.Status.NodeRef.Name))
 			conditions.MarkFalse(m, clusterv1.MachineNodeHealthyCondition, clusterv1.DeletionFailedReason, clusterv1.ConditionSeverityWarning, "")
-			r.recorder.Eventf(m, corev1.EventTypeWarning, "FailedDeleteNode", "error deleting Machine's node: %v", deleteNodeErr)
+			events.Warningf(r.recorder, m, events.FailedDeleteNodeReason, "error deleting Machine's node: %v", deleteNodeErr)
 
 			// If the node deletion timeout is not expired yet, requeue the Machine for reconciliation.
 			if m.Spec.NodeDeletionTimeout == nil || m.Spec.NodeDeletionTimeout.Nanoseconds() == 0 || m.DeletionTimestamp.Add(m.Spec.NodeDeletionTimeout.Duration).After(time.Now()) {
@@ -500,23 +617,80 @@ func (r *Reconciler) isNodeVolumeDetachingAllowed(m *clusterv1.Machine) bool {
 	return true
 }
 
+// unreachableClusterTimeoutExceeded returns true if the control plane of the workload cluster has been unreachable
+// for longer than UnreachableClusterTimeout. The WorkloadClusterReachableCondition is used to record when the control
+// plane was first found unreachable.
+func (r *Reconciler) unreachableClusterTimeoutExceeded(ctx context.Context, cluster *clusterv1.Cluster, machine *clusterv1.Machine) bool {
+	if r.UnreachableClusterTimeout <= 0 {
+		return false
+	}
+
+	probeErr := r.probeWorkloadCluster(ctx, cluster)
+	if errors.Is(probeErr, remote.ErrClusterLocked) {
+		// The reachability of the workload cluster is unknown, keep the previous decision.
+		return conditions.GetReason(machine, clusterv1.DrainingSucceededCondition) == clusterv1.DrainingSkippedReason
+	}
+	if probeErr != nil {
+		ctrl.LoggerFrom(ctx).V(4).Info("Workload cluster is unreachable", "err", probeErr.Error())
+	}
+	return workloadClusterUnreachableFor(machine, probeErr, r.UnreachableClusterTimeout)
+}
+
+// workloadClusterUnreachableFor updates the WorkloadClusterReachableCondition with the result of the last probe
+// and returns true if the workload cluster has been unreachable for at least the given timeout.
+func workloadClusterUnreachableFor(machine *clusterv1.Machine, probeErr error, timeout time.Duration) bool {
+	if probeErr == nil {
+		conditions.MarkTrue(machine, clusterv1.WorkloadClusterReachableCondition)
+		return false
+	}
+
+	// NOTE: The last transition time is preserved as long as the condition does not change, so it records
+	// when the workload cluster was first found unreachable; the message must not include the probe error
+	// because it might change across probes.
+	conditions.MarkFalse(machine, clusterv1.WorkloadClusterReachableCondition, clusterv1.WorkloadClusterUnreachableReason, clusterv1.ConditionSeverityWarning, "Control plane of the workload cluster is unreachable")
+	firstTimeUnreachable := conditions.GetLastTransitionTime(machine, clusterv1.WorkloadClusterReachableCondition)
+	return firstTimeUnreachable != nil && time.Since(firstTimeUnreachable.Time) >= timeout
+}
+
+// probeWorkloadCluster returns an error if the API server of the workload cluster cannot be reached.
+// NOTE: The ClusterCacheTracker health checks the workload cluster and drops its client as soon as the cluster
+// is unreachable, and creating a new client fails until the cluster is reachable again.
+func (r *Reconciler) probeWorkloadCluster(ctx context.Context, cluster *clusterv1.Cluster) error {
+	_, err := r.Tracker.GetClient(ctx, util.ObjectKey(cluster))
+	return err
+}
+
 func (r *Reconciler) nodeDrainTimeoutExceeded(machine *clusterv1.Machine) bool {
 	// if the NodeDrainTimeout type is not set by user
 	if machine.Spec.NodeDrainTimeout == nil || machine.Spec.NodeDrainTimeout.Seconds() <= 0 {
 		return false
 	}
 
-	// if the draining succeeded condition does not exist
-	if conditions.Get(machine, clusterv1.DrainingSucceededCondition) == nil {
+	// if the drain has not been started yet
+	if machine.Status.NodeDrainStartTime == nil {
 		return false
 	}
 
 	now := time.Now()
-	firstTimeDrain := conditions.GetLastTransitionTime(machine, clusterv1.DrainingSucceededCondition)
-	diff := now.Sub(firstTimeDrain.Time)
+	diff := now.Sub(machine.Status.NodeDrainStartTime.Time)
 	return diff.Seconds() >= machine.Spec.NodeDrainTimeout.Seconds()
 }
 
+// nodeDrainEvictionDeadline returns the time after which Pods which could not be evicted from the node of
+// the Machine are deleted instead; it returns false if NodeDrainEvictionTimeout is not set or the node
+// has not been drained yet.
+func nodeDrainEvictionDeadline(machine *clusterv1.Machine) (time.Time, bool) {
+	if machine.Spec.NodeDrainEvictionTimeout == nil || machine.Spec.NodeDrainEvictionTimeout.Seconds() <= 0 {
+		return time.Time{}, false
+	}
+
+	if machine.Status.NodeDrainStartTime == nil {
+		return time.Time{}, false
+	}
+
+	return machine.Status.NodeDrainStartTime.Add(machine.Spec.NodeDrainEvictionTimeout.Duration), true
+}
+
 // nodeVolumeDetachTimeoutExceeded returns False if either NodeVolumeDetachTimeout is set to nil or <=0 OR
 // VolumeDetachSucceededCondition is not set on the Machine. Otherwise returns true if the timeout is expired
 // since the last transition time of VolumeDetachSucceededCondition.
@@ -599,20 +773,23 @@ func (r *Reconciler) isDeleteNodeAllowed(ctx context.Context, cluster *clusterv1
 	return nil
 }
 
-func (r *Reconciler) drainNode(ctx context.Context, cluster *clusterv1.Cluster, nodeName string) (ctrl.Result, error) {
+// drainNode drains the given node. If the drain has to be retried, it returns the Pods which are
+// still blocking the drain. Pods are evicted using the Eviction API, unless fallbackToDelete is true,
+// in which case they are deleted without honoring PodDisruptionBudgets.
+func (r *Reconciler) drainNode(ctx context.Context, cluster *clusterv1.Cluster, nodeName string, fallbackToDelete bool) (ctrl.Result, []drainBlockingPod, error) {
 	log := ctrl.LoggerFrom(ctx, "Node", klog.KRef("", nodeName))
 
 	restConfig, err := r.Tracker.GetRESTConfig(ctx, util.ObjectKey(cluster))
 	if err != nil {
 		log.Error(err, "Error creating a remote client while deleting Machine, won't retry")
-		return ctrl.Result{}, nil
+		return ctrl.Result{}, nil, nil
 	}
 	restConfig = rest.CopyConfig(restConfig)
 	restConfig.Timeout = r.NodeDrainClientTimeout
 	kubeClient, err := kubernetes.NewForConfig(restConfig)
 	if err != nil {
 		log.Error(err, "Error creating a remote client while deleting Machine, won't retry")
-		return ctrl.Result{}, nil
+		return ctrl.Result{}, nil, nil
 	}
 
 	node, err := kubeClient.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
@@ -620,9 +797,9 @@ func (r *Reconciler) drainNode(ctx context.Context, cluster *clusterv1.Cluster,
 		if apierrors.IsNotFound(err) {
 			// If an admin deletes the node directly, we'll end up here.
 			log.Error(err, "Could not find node from noderef, it may have already been deleted")
-			return ctrl.Result{}, nil
+			return ctrl.Result{}, nil, nil
 		}
-		return ctrl.Result{}, errors.Wrapf(err, "unable to get node %v", nodeName)
+		return ctrl.Result{}, nil, errors.Wrapf(err, "unable to get node %v", nodeName)
 	}
 
 	drainer := &kubedrain.Helper{
@@ -649,6 +826,11 @@ func (r *Reconciler) drainNode(ctx context.Context, cluster *clusterv1.Cluster,
 		}},
 	}
 
+	if fallbackToDelete {
+		log.Info("Node drain eviction timeout exceeded, deleting Pods instead of evicting them")
+		drainer.DisableEviction = true
+	}
+
 	if noderefutil.IsNodeUnreachable(node) {
 		// When the node is unreachable and some pods are not evicted for as long as this timeout, we ignore them.
 		drainer.SkipWaitForDeleteTimeoutSeconds = 60 * 5 // 5 minutes
@@ -657,17 +839,175 @@ func (r *Reconciler) drainNode(ctx context.Context, cluster *clusterv1.Cluster,
 	if err := kubedrain.RunCordonOrUncordon(drainer, node, true); err != nil {
 		// Machine will be re-reconciled after a cordon failure.
 		log.Error(err, "Cordon failed")
-		return ctrl.Result{}, errors.Wrapf(err, "unable to cordon node %v", node.Name)
+		return ctrl.Result{}, nil, errors.Wrapf(err, "unable to cordon node %v", node.Name)
+	}
+
+	rules, err := r.getMachineDrainRules(ctx, cluster)
+	if err != nil {
+		return ctrl.Result{}, nil, err
+	}
+	behaviors, pods, err := getPodDrainBehaviors(ctx, kubeClient, node.Name, rules)
+	if err != nil {
+		return ctrl.Result{}, nil, err
 	}
 
-	if err := kubedrain.RunNodeDrain(drainer, node.Name); err != nil {
+	// First evict the Pods not selected by a MachineDrainRule and the Pods with the Drain behavior,
+	// in the order defined by MachineDrainRules.
+	for _, order := range behaviors.drainOrders() {
+		drainer.AdditionalFilters = []kubedrain.PodFilter{behaviors.filterUpToOrder(order)}
+		if result, blockingPods, done := runNodeDrain(ctx, drainer, node.Name); !done {
+			return result, blockingPods, nil
+		}
+	}
+
+	// Then wait for the Pods with the WaitCompleted behavior to complete.
+	if waitCompletedPods := getWaitCompletedPods(pods, behaviors); len(waitCompletedPods) > 0 {
+		log.Info("Waiting for Pods to complete, retry in 20s", "Pods", len(waitCompletedPods))
+		retryAfter := 20 * time.Second
+		blockingPods := []drainBlockingPod{}
+		for _, pod := range waitCompletedPods {
+			blockingPods = append(blockingPods, drainBlockingPod{
+				Namespace:            pod.Namespace,
+				Name:                 pod.Name,
+				WaitingForCompletion: true,
+			})
+		}
+		return ctrl.Result{RequeueAfter: retryAfter}, blockingPods, nil
+	}
+
+	// Finally evict the Pods with the DrainLast behavior.
+	drainer.AdditionalFilters = []kubedrain.PodFilter{behaviors.filter(
+		clusterv1.MachineDrainRuleDrainBehaviorSkip,
+		clusterv1.MachineDrainRuleDrainBehaviorWaitCompleted,
+	)}
+	if result, blockingPods, done := runNodeDrain(ctx, drainer, node.Name); !done {
+		return result, blockingPods, nil
+	}
+
+	log.Info("Drain successful")
+	return ctrl.Result{}, nil, nil
+}
+
+// runNodeDrain evicts the Pods selected by the drainer from the given Node. If the drain has to be retried,
+// it returns false together with the Pods which are still blocking the drain.
+func runNodeDrain(ctx context.Context, drainer *kubedrain.Helper, nodeName string) (ctrl.Result, []drainBlockingPod, bool) {
+	log := ctrl.LoggerFrom(ctx, "Node", klog.KRef("", nodeName))
+
+	if err := kubedrain.RunNodeDrain(drainer, nodeName); err != nil {
 		// Machine will be re-reconciled after a drain failure.
 		log.Error(err, "Drain failed, retry in 20s")
-		return ctrl.Result{RequeueAfter: 20 * time.Second}, nil
+		retryAfter := 20 * time.Second
+		blockingPods, err := getDrainBlockingPods(ctx, drainer, nodeName, time.Now().Add(retryAfter))
+		if err != nil {
+			log.Error(err, "Failed to determine the Pods blocking the drain")
+		}
+		return ctrl.Result{RequeueAfter: retryAfter}, blockingPods, false
 	}
+	return ctrl.Result{}, nil, true
+}
 
-	log.Info("Drain successful")
-	return ctrl.Result{}, nil
+// maxDrainBlockingPodsInMessage is the maximum number of Pods listed in the message
+// reporting the Pods blocking a drain.
+const maxDrainBlockingPodsInMessage = 5
+
+// drainBlockingPod is a Pod which is blocking the drain of a Node.
+type drainBlockingPod struct {
+	Namespace string
+	Name      string
+
+	// PodDisruptionBudgets are the names of the PodDisruptionBudgets matching the Pod
+	// which currently do not allow any disruption.
+	PodDisruptionBudgets []string
+
+	// Terminating is true if the Pod has already been evicted and is terminating.
+	Terminating bool
+
+	// WaitingForCompletion is true if the Pod is selected by a MachineDrainRule with the WaitCompleted
+	// behavior and it has not completed yet.
+	WaitingForCompletion bool
+
+	// EarliestEvictionTime is the earliest time the Pod can be gone from the Node; this is the
+	// deletion time for terminating Pods and the time of the next eviction attempt otherwise.
+	EarliestEvictionTime time.Time
+}
+
+// getDrainBlockingPods returns the Pods which are still to be evicted from the given Node, together with
+// the PodDisruptionBudgets preventing their eviction.
+func getDrainBlockingPods(ctx context.Context, drainer *kubedrain.Helper, nodeName string, nextEvictionTime time.Time) ([]drainBlockingPod, error) {
+	podDeleteList, errs := drainer.GetPodsForDeletion(nodeName)
+	if len(errs) > 0 {
+		return nil, kerrors.NewAggregate(errs)
+	}
+
+	pdbsByNamespace := map[string][]policyv1.PodDisruptionBudget{}
+	blockingPods := []drainBlockingPod{}
+	for _, pod := range podDeleteList.Pods() {
+		blockingPod := drainBlockingPod{
+			Namespace:            pod.Namespace,
+			Name:                 pod.Name,
+			EarliestEvictionTime: nextEvictionTime,
+		}
+
+		if pod.DeletionTimestamp != nil {
+			blockingPod.Terminating = true
+			blockingPod.EarliestEvictionTime = pod.DeletionTimestamp.Time
+			blockingPods = append(blockingPods, blockingPod)
+			continue
+		}
+
+		pdbs, ok := pdbsByNamespace[pod.Namespace]
+		if !ok {
+			pdbList, err := drainer.Client.PolicyV1().PodDisruptionBudgets(pod.Namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to list PodDisruptionBudgets in namespace %q", pod.Namespace)
+			}
+			pdbs = pdbList.Items
+			pdbsByNamespace[pod.Namespace] = pdbs
+		}
+		for _, pdb := range pdbs {
+			if pdb.Status.DisruptionsAllowed > 0 {
+				continue
+			}
+			selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+			if err != nil || selector.Empty() || !selector.Matches(labels.Set(pod.Labels)) {
+				continue
+			}
+			blockingPod.PodDisruptionBudgets = append(blockingPod.PodDisruptionBudgets, pdb.Name)
+		}
+		blockingPods = append(blockingPods, blockingPod)
+	}
+
+	sort.Slice(blockingPods, func(i, j int) bool {
+		if blockingPods[i].Namespace != blockingPods[j].Namespace {
+			return blockingPods[i].Namespace < blockingPods[j].Namespace
+		}
+		return blockingPods[i].Name < blockingPods[j].Name
+	})
+	return blockingPods, nil
+}
+
+// drainBlockingPodsMessage returns a human readable message listing the Pods blocking a drain.
+func drainBlockingPodsMessage(blockingPods []drainBlockingPod) string {
+	msgs := []string{}
+	for i, pod := range blockingPods {
+		if i == maxDrainBlockingPodsInMessage {
+			msgs = append(msgs, fmt.Sprintf("and %d more Pods", len(blockingPods)-maxDrainBlockingPodsInMessage))
+			break
+		}
+
+		earliest := pod.EarliestEvictionTime.UTC().Format(time.RFC3339)
+		switch {
+		case pod.Terminating:
+			msgs = append(msgs, fmt.Sprintf("Pod %s/%s is terminating, expected to be deleted at %s", pod.Namespace, pod.Name, earliest))
+		case pod.WaitingForCompletion:
+			msgs = append(msgs, fmt.Sprintf("Pod %s/%s is waiting for completion as defined by a MachineDrainRule", pod.Namespace, pod.Name))
+		case len(pod.PodDisruptionBudgets) > 0:
+			msgs = append(msgs, fmt.Sprintf("Pod %s/%s cannot be evicted because of PodDisruptionBudgets %s, next eviction attempt at %s", pod.Namespace, pod.Name, strings.Join(pod.PodDisruptionBudgets, ", "), earliest))
+		default:
+			msgs = append(msgs, fmt.Sprintf("Pod %s/%s has not been evicted yet, next eviction attempt at %s", pod.Namespace, pod.Name, earliest))
+		}
+	}
+	return strings.Join(msgs, "; ")
 }
 
 // shouldWaitForNodeVolumes returns true if node status still have volumes attached
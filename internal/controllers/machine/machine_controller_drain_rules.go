@@ -0,0 +1,227 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes"
+	kubedrain "k8s.io/kubectl/pkg/drain"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/feature"
+)
+
+// drainBehaviorPriority defines which behavior wins when a Pod is selected by more than one MachineDrainRule.
+var drainBehaviorPriority = map[clusterv1.MachineDrainRuleDrainBehavior]int{
+	clusterv1.MachineDrainRuleDrainBehaviorDrain:         1,
+	clusterv1.MachineDrainRuleDrainBehaviorDrainLast:     2,
+	clusterv1.MachineDrainRuleDrainBehaviorWaitCompleted: 3,
+	clusterv1.MachineDrainRuleDrainBehaviorSkip:          4,
+}
+
+// podDrainBehavior is the drain behavior of a Pod defined by MachineDrainRules.
+type podDrainBehavior struct {
+	Behavior clusterv1.MachineDrainRuleDrainBehavior
+
+	// Order is the order in which the Pod is evicted, if the behavior is Drain.
+	Order int32
+}
+
+// podDrainBehaviors maps the Pods on a Node to the drain behavior defined by MachineDrainRules.
+// Pods not selected by any MachineDrainRule are not included.
+type podDrainBehaviors map[types.NamespacedName]podDrainBehavior
+
+// filter returns a PodFilter skipping the Pods with one of the given behaviors.
+func (b podDrainBehaviors) filter(skip ...clusterv1.MachineDrainRuleDrainBehavior) kubedrain.PodFilter {
+	return func(pod corev1.Pod) kubedrain.PodDeleteStatus {
+		behavior, ok := b[types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}]
+		if !ok {
+			return kubedrain.MakePodDeleteStatusOkay()
+		}
+		for _, s := range skip {
+			if behavior.Behavior == s {
+				return kubedrain.MakePodDeleteStatusSkip()
+			}
+		}
+		return kubedrain.MakePodDeleteStatusOkay()
+	}
+}
+
+// filterUpToOrder returns a PodFilter selecting the Pods to evict before waiting for the Pods with the WaitCompleted
+// behavior, i.e. the Pods with the Drain behavior and the Pods not selected by any MachineDrainRule, up to the given order.
+func (b podDrainBehaviors) filterUpToOrder(order int32) kubedrain.PodFilter {
+	return func(pod corev1.Pod) kubedrain.PodDeleteStatus {
+		behavior, ok := b[types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}]
+		if !ok {
+			behavior = podDrainBehavior{Behavior: clusterv1.MachineDrainRuleDrainBehaviorDrain}
+		}
+		if behavior.Behavior != clusterv1.MachineDrainRuleDrainBehaviorDrain || behavior.Order > order {
+			return kubedrain.MakePodDeleteStatusSkip()
+		}
+		return kubedrain.MakePodDeleteStatusOkay()
+	}
+}
+
+// drainOrders returns the orders in which the Pods with the Drain behavior are evicted, sorted in ascending order;
+// the order of the Pods not selected by any MachineDrainRule, 0, is always included.
+func (b podDrainBehaviors) drainOrders() []int32 {
+	orders := sets.New[int32](0)
+	for _, behavior := range b {
+		if behavior.Behavior == clusterv1.MachineDrainRuleDrainBehaviorDrain {
+			orders.Insert(behavior.Order)
+		}
+	}
+	return sets.List(orders)
+}
+
+// getMachineDrainRules returns the MachineDrainRules in the namespace of the Cluster which apply to the Cluster.
+func (r *Reconciler) getMachineDrainRules(ctx context.Context, cluster *clusterv1.Cluster) ([]clusterv1.MachineDrainRule, error) {
+	ruleList := &clusterv1.MachineDrainRuleList{}
+	if err := r.Client.List(ctx, ruleList, client.InNamespace(cluster.Namespace)); err != nil {
+		return nil, errors.Wrapf(err, "failed to list MachineDrainRules in namespace %q", cluster.Namespace)
+	}
+
+	rules := []clusterv1.MachineDrainRule{}
+	for _, rule := range ruleList.Items {
+		// NOTE: The Drain behavior is behind the MachineDrainRule feature gate; if the feature gate is disabled,
+		// rules with the Drain behavior are ignored, so the selected Pods are drained with the default order.
+		if rule.Spec.Behavior == clusterv1.MachineDrainRuleDrainBehaviorDrain && !feature.Gates.Enabled(feature.MachineDrainRule) {
+			continue
+		}
+		if rule.Spec.ClusterSelector != nil {
+			selector, err := metav1.LabelSelectorAsSelector(rule.Spec.ClusterSelector)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid clusterSelector in MachineDrainRule %s", rule.Name)
+			}
+			if !selector.Matches(labels.Set(cluster.Labels)) {
+				continue
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// getPodDrainBehaviors returns the drain behavior of the Pods on the given Node selected by the MachineDrainRules,
+// together with the Pods on the Node.
+func getPodDrainBehaviors(ctx context.Context, kubeClient kubernetes.Interface, nodeName string, rules []clusterv1.MachineDrainRule) (podDrainBehaviors, []corev1.Pod, error) {
+	behaviors := podDrainBehaviors{}
+	if len(rules) == 0 {
+		return behaviors, nil, nil
+	}
+
+	podList, err := kubeClient.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.SelectorFromSet(fields.Set{"spec.nodeName": nodeName}).String(),
+	})
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to list Pods on Node %s", nodeName)
+	}
+
+	// Namespaces are read only if required by at least one rule.
+	var namespaceLabels map[string]labels.Set
+	for _, rule := range rules {
+		for _, podSelector := range rule.Spec.Pods {
+			if podSelector.NamespaceSelector == nil || namespaceLabels != nil {
+				continue
+			}
+			namespaceList, err := kubeClient.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, nil, errors.Wrap(err, "failed to list Namespaces")
+			}
+			namespaceLabels = map[string]labels.Set{}
+			for _, ns := range namespaceList.Items {
+				namespaceLabels[ns.Name] = labels.Set(ns.Labels)
+			}
+		}
+	}
+
+	for _, pod := range podList.Items {
+		for _, rule := range rules {
+			matches, err := machineDrainRuleMatchesPod(rule, pod, namespaceLabels[pod.Namespace])
+			if err != nil {
+				return nil, nil, err
+			}
+			if !matches {
+				continue
+			}
+			key := types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}
+			behavior := podDrainBehavior{Behavior: rule.Spec.Behavior}
+			if rule.Spec.Behavior == clusterv1.MachineDrainRuleDrainBehaviorDrain {
+				behavior.Order = pointer.Int32Deref(rule.Spec.Order, 0)
+			}
+			current, ok := behaviors[key]
+			switch {
+			case !ok, drainBehaviorPriority[behavior.Behavior] > drainBehaviorPriority[current.Behavior]:
+				behaviors[key] = behavior
+			case behavior.Behavior == current.Behavior && behavior.Order > current.Order:
+				behaviors[key] = behavior
+			}
+		}
+	}
+	return behaviors, podList.Items, nil
+}
+
+// machineDrainRuleMatchesPod returns true if any of the Pod selectors of the MachineDrainRule matches the Pod.
+func machineDrainRuleMatchesPod(rule clusterv1.MachineDrainRule, pod corev1.Pod, namespaceLabels labels.Set) (bool, error) {
+	for _, podSelector := range rule.Spec.Pods {
+		if podSelector.Selector != nil {
+			selector, err := metav1.LabelSelectorAsSelector(podSelector.Selector)
+			if err != nil {
+				return false, errors.Wrapf(err, "invalid Pod selector in MachineDrainRule %s", rule.Name)
+			}
+			if !selector.Matches(labels.Set(pod.Labels)) {
+				continue
+			}
+		}
+		if podSelector.NamespaceSelector != nil {
+			selector, err := metav1.LabelSelectorAsSelector(podSelector.NamespaceSelector)
+			if err != nil {
+				return false, errors.Wrapf(err, "invalid namespace selector in MachineDrainRule %s", rule.Name)
+			}
+			if !selector.Matches(namespaceLabels) {
+				continue
+			}
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// getWaitCompletedPods returns the Pods with the WaitCompleted behavior which are not completed yet.
+func getWaitCompletedPods(pods []corev1.Pod, behaviors podDrainBehaviors) []corev1.Pod {
+	waiting := []corev1.Pod{}
+	for _, pod := range pods {
+		if behaviors[types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}].Behavior != clusterv1.MachineDrainRuleDrainBehaviorWaitCompleted {
+			continue
+		}
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		waiting = append(waiting, pod)
+	}
+	return waiting
+}
@@ -0,0 +1,290 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakeclientset "k8s.io/client-go/kubernetes/fake"
+	utilfeature "k8s.io/component-base/featuregate/testing"
+	kubedrain "k8s.io/kubectl/pkg/drain"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/feature"
+)
+
+func TestGetMachineDrainRules(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "cluster", Labels: map[string]string{"env": "prod"}},
+	}
+	rule := func(namespace, name string, clusterSelector *metav1.LabelSelector) *clusterv1.MachineDrainRule {
+		return &clusterv1.MachineDrainRule{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec: clusterv1.MachineDrainRuleSpec{
+				Behavior:        clusterv1.MachineDrainRuleDrainBehaviorSkip,
+				ClusterSelector: clusterSelector,
+				Pods:            []clusterv1.MachineDrainRulePodSelector{{}},
+			},
+		}
+	}
+
+	c := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(
+		rule("default", "all-clusters", nil),
+		rule("default", "matching-cluster", &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}}),
+		rule("default", "not-matching-cluster", &metav1.LabelSelector{MatchLabels: map[string]string{"env": "dev"}}),
+		rule("other", "other-namespace", nil),
+	).Build()
+	r := &Reconciler{Client: c}
+
+	rules, err := r.getMachineDrainRules(ctx, cluster)
+	g.Expect(err).ToNot(HaveOccurred())
+	names := []string{}
+	for _, rule := range rules {
+		names = append(names, rule.Name)
+	}
+	g.Expect(names).To(ConsistOf("all-clusters", "matching-cluster"))
+}
+
+func TestGetMachineDrainRulesIgnoresDrainBehaviorWhenFeatureGateDisabled(t *testing.T) {
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "cluster"},
+	}
+	rule := func(name string, behavior clusterv1.MachineDrainRuleDrainBehavior) *clusterv1.MachineDrainRule {
+		return &clusterv1.MachineDrainRule{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Spec: clusterv1.MachineDrainRuleSpec{
+				Behavior: behavior,
+				Pods:     []clusterv1.MachineDrainRulePodSelector{{}},
+			},
+		}
+	}
+	c := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(
+		rule("drain", clusterv1.MachineDrainRuleDrainBehaviorDrain),
+		rule("skip", clusterv1.MachineDrainRuleDrainBehaviorSkip),
+	).Build()
+	r := &Reconciler{Client: c}
+
+	for _, tt := range []struct {
+		enabled bool
+		want    []string
+	}{
+		{enabled: true, want: []string{"drain", "skip"}},
+		{enabled: false, want: []string{"skip"}},
+	} {
+		t.Run(fmt.Sprintf("feature gate enabled: %t", tt.enabled), func(t *testing.T) {
+			defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.MachineDrainRule, tt.enabled)()
+			g := NewWithT(t)
+
+			rules, err := r.getMachineDrainRules(ctx, cluster)
+			g.Expect(err).ToNot(HaveOccurred())
+			names := []string{}
+			for _, rule := range rules {
+				names = append(names, rule.Name)
+			}
+			g.Expect(names).To(ConsistOf(tt.want))
+		})
+	}
+}
+
+func TestGetPodDrainBehaviors(t *testing.T) {
+	pod := func(namespace, name string, labels map[string]string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels},
+			Spec:       corev1.PodSpec{NodeName: "node-1"},
+		}
+	}
+	rule := func(behavior clusterv1.MachineDrainRuleDrainBehavior, pods ...clusterv1.MachineDrainRulePodSelector) clusterv1.MachineDrainRule {
+		return clusterv1.MachineDrainRule{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: string(behavior)},
+			Spec:       clusterv1.MachineDrainRuleSpec{Behavior: behavior, Pods: pods},
+		}
+	}
+	orderedRule := func(name string, order int32, pods ...clusterv1.MachineDrainRulePodSelector) clusterv1.MachineDrainRule {
+		return clusterv1.MachineDrainRule{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Spec:       clusterv1.MachineDrainRuleSpec{Behavior: clusterv1.MachineDrainRuleDrainBehaviorDrain, Order: pointer.Int32(order), Pods: pods},
+		}
+	}
+
+	kubeClient := fakeclientset.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "backup", Labels: map[string]string{"team": "backup"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "apps"}},
+		pod("backup", "agent", map[string]string{"app": "agent"}),
+		pod("apps", "agent", map[string]string{"app": "agent"}),
+		pod("apps", "job", map[string]string{"app": "job"}),
+		pod("apps", "web", map[string]string{"app": "web"}),
+	)
+
+	tests := []struct {
+		name  string
+		rules []clusterv1.MachineDrainRule
+		want  podDrainBehaviors
+	}{
+		{
+			name: "No rules",
+			want: podDrainBehaviors{},
+		},
+		{
+			name: "Pods selected by Pod and namespace selectors",
+			rules: []clusterv1.MachineDrainRule{
+				rule(clusterv1.MachineDrainRuleDrainBehaviorDrainLast, clusterv1.MachineDrainRulePodSelector{
+					Selector:          &metav1.LabelSelector{MatchLabels: map[string]string{"app": "agent"}},
+					NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "backup"}},
+				}),
+				rule(clusterv1.MachineDrainRuleDrainBehaviorWaitCompleted, clusterv1.MachineDrainRulePodSelector{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "job"}},
+				}),
+			},
+			want: podDrainBehaviors{
+				{Namespace: "backup", Name: "agent"}: {Behavior: clusterv1.MachineDrainRuleDrainBehaviorDrainLast},
+				{Namespace: "apps", Name: "job"}:     {Behavior: clusterv1.MachineDrainRuleDrainBehaviorWaitCompleted},
+			},
+		},
+		{
+			name: "Skip takes precedence over WaitCompleted and DrainLast",
+			rules: []clusterv1.MachineDrainRule{
+				rule(clusterv1.MachineDrainRuleDrainBehaviorDrainLast, clusterv1.MachineDrainRulePodSelector{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "agent"}},
+				}),
+				rule(clusterv1.MachineDrainRuleDrainBehaviorSkip, clusterv1.MachineDrainRulePodSelector{
+					NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "backup"}},
+				}),
+				rule(clusterv1.MachineDrainRuleDrainBehaviorWaitCompleted, clusterv1.MachineDrainRulePodSelector{
+					NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "backup"}},
+				}),
+			},
+			want: podDrainBehaviors{
+				{Namespace: "backup", Name: "agent"}: {Behavior: clusterv1.MachineDrainRuleDrainBehaviorSkip},
+				{Namespace: "apps", Name: "agent"}:   {Behavior: clusterv1.MachineDrainRuleDrainBehaviorDrainLast},
+			},
+		},
+		{
+			name: "Highest order is used for Pods selected by more than one rule with the Drain behavior",
+			rules: []clusterv1.MachineDrainRule{
+				orderedRule("first", -1, clusterv1.MachineDrainRulePodSelector{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "agent"}},
+				}),
+				orderedRule("last", 2, clusterv1.MachineDrainRulePodSelector{
+					NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "backup"}},
+				}),
+				orderedRule("web", 1, clusterv1.MachineDrainRulePodSelector{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+				}),
+				rule(clusterv1.MachineDrainRuleDrainBehaviorDrainLast, clusterv1.MachineDrainRulePodSelector{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+				}),
+			},
+			want: podDrainBehaviors{
+				{Namespace: "backup", Name: "agent"}: {Behavior: clusterv1.MachineDrainRuleDrainBehaviorDrain, Order: 2},
+				{Namespace: "apps", Name: "agent"}:   {Behavior: clusterv1.MachineDrainRuleDrainBehaviorDrain, Order: -1},
+				{Namespace: "apps", Name: "web"}:     {Behavior: clusterv1.MachineDrainRuleDrainBehaviorDrainLast},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, _, err := getPodDrainBehaviors(ctx, kubeClient, "node-1", tt.rules)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestGetWaitCompletedPods(t *testing.T) {
+	g := NewWithT(t)
+
+	pod := func(name string, phase corev1.PodPhase) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Status:     corev1.PodStatus{Phase: phase},
+		}
+	}
+	pods := []corev1.Pod{
+		pod("running", corev1.PodRunning),
+		pod("succeeded", corev1.PodSucceeded),
+		pod("failed", corev1.PodFailed),
+		pod("not-selected", corev1.PodRunning),
+	}
+	behaviors := podDrainBehaviors{
+		{Namespace: "default", Name: "running"}:   {Behavior: clusterv1.MachineDrainRuleDrainBehaviorWaitCompleted},
+		{Namespace: "default", Name: "succeeded"}: {Behavior: clusterv1.MachineDrainRuleDrainBehaviorWaitCompleted},
+		{Namespace: "default", Name: "failed"}:    {Behavior: clusterv1.MachineDrainRuleDrainBehaviorWaitCompleted},
+	}
+
+	got := getWaitCompletedPods(pods, behaviors)
+	g.Expect(got).To(HaveLen(1))
+	g.Expect(got[0].Name).To(Equal("running"))
+}
+
+func TestPodDrainBehaviorsDrainOrders(t *testing.T) {
+	g := NewWithT(t)
+
+	behaviors := podDrainBehaviors{
+		{Namespace: "default", Name: "first"}:     {Behavior: clusterv1.MachineDrainRuleDrainBehaviorDrain, Order: -5},
+		{Namespace: "default", Name: "last"}:      {Behavior: clusterv1.MachineDrainRuleDrainBehaviorDrain, Order: 10},
+		{Namespace: "default", Name: "also-last"}: {Behavior: clusterv1.MachineDrainRuleDrainBehaviorDrain, Order: 10},
+		{Namespace: "default", Name: "skip"}:      {Behavior: clusterv1.MachineDrainRuleDrainBehaviorSkip},
+	}
+	g.Expect(behaviors.drainOrders()).To(Equal([]int32{-5, 0, 10}))
+	g.Expect(podDrainBehaviors{}.drainOrders()).To(Equal([]int32{0}))
+}
+
+func TestPodDrainBehaviorsFilterUpToOrder(t *testing.T) {
+	pod := func(name string) corev1.Pod {
+		return corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name}}
+	}
+	behaviors := podDrainBehaviors{
+		{Namespace: "default", Name: "first"}:          {Behavior: clusterv1.MachineDrainRuleDrainBehaviorDrain, Order: -5},
+		{Namespace: "default", Name: "last"}:           {Behavior: clusterv1.MachineDrainRuleDrainBehaviorDrain, Order: 10},
+		{Namespace: "default", Name: "skip"}:           {Behavior: clusterv1.MachineDrainRuleDrainBehaviorSkip},
+		{Namespace: "default", Name: "wait-completed"}: {Behavior: clusterv1.MachineDrainRuleDrainBehaviorWaitCompleted},
+		{Namespace: "default", Name: "drain-last"}:     {Behavior: clusterv1.MachineDrainRuleDrainBehaviorDrainLast},
+	}
+
+	tests := []struct {
+		order   int32
+		evicted []string
+	}{
+		{order: -5, evicted: []string{"first"}},
+		{order: 0, evicted: []string{"first", "not-selected"}},
+		{order: 10, evicted: []string{"first", "not-selected", "last"}},
+	}
+	for _, tt := range tests {
+		g := NewWithT(t)
+
+		filter := behaviors.filterUpToOrder(tt.order)
+		evicted := []string{}
+		for _, name := range []string{"first", "not-selected", "last", "skip", "wait-completed", "drain-last"} {
+			if filter(pod(name)).Delete {
+				evicted = append(evicted, name)
+			}
+		}
+		g.Expect(evicted).To(Equal(tt.evicted), "order %d", tt.order)
+		g.Expect(filter(pod("skip")).Reason).To(Equal(kubedrain.PodDeleteStatusTypeSkip))
+	}
+}
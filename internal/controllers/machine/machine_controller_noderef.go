@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -35,11 +36,22 @@ import (
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/events"
 )
 
 var (
 	// ErrNodeNotFound signals that a corev1.Node could not be found for the given provider id.
 	ErrNodeNotFound = errors.New("cannot find node with matching ProviderID")
+
+	// defaultMachineToNodeLabelDomains are the label domains of the Machine labels propagated to the Node
+	// when no label domains are configured.
+	defaultMachineToNodeLabelDomains = []string{
+		clusterv1.NodeRoleLabelPrefix,
+		clusterv1.NodeRestrictionLabelDomain,
+		"*." + clusterv1.NodeRestrictionLabelDomain,
+		clusterv1.ManagedNodeLabelDomain,
+		"*." + clusterv1.ManagedNodeLabelDomain,
+	}
 )
 
 func (r *Reconciler) reconcileNode(ctx context.Context, s *scope) (ctrl.Result, error) {
@@ -57,7 +69,7 @@ func (r *Reconciler) reconcileNode(ctx context.Context, s *scope) (ctrl.Result,
 	if machine.Spec.ProviderID == nil || *machine.Spec.ProviderID == "" {
 		log.Info("Waiting for infrastructure provider to report spec.providerID", machine.Spec.InfrastructureRef.Kind, klog.KRef(machine.Spec.InfrastructureRef.Namespace, machine.Spec.InfrastructureRef.Name))
 		conditions.MarkFalse(machine, clusterv1.MachineNodeHealthyCondition, clusterv1.WaitingForNodeRefReason, clusterv1.ConditionSeverityInfo, "")
-		return ctrl.Result{}, nil
+		return r.reconcileNodeStartupTimeout(ctx, machine), nil
 	}
 
 	remoteClient, err := r.Tracker.GetClient(ctx, util.ObjectKey(cluster))
@@ -76,14 +88,32 @@ func (r *Reconciler) reconcileNode(ctx context.Context, s *scope) (ctrl.Result,
 				return ctrl.Result{}, errors.Wrapf(err, "no matching Node for Machine %q in namespace %q", machine.Name, machine.Namespace)
 			}
 			conditions.MarkFalse(machine, clusterv1.MachineNodeHealthyCondition, clusterv1.NodeProvisioningReason, clusterv1.ConditionSeverityWarning, "")
-			// No need to requeue here. Nodes emit an event that triggers reconciliation.
-			return ctrl.Result{}, nil
+			// No need to requeue here unless NodeStartupTimeout is set. Nodes emit an event that triggers reconciliation.
+			return r.reconcileNodeStartupTimeout(ctx, machine), nil
 		}
 		log.Error(err, "Failed to retrieve Node by ProviderID")
 		r.recorder.Event(machine, corev1.EventTypeWarning, "Failed to retrieve Node by ProviderID", err.Error())
 		return ctrl.Result{}, err
 	}
 
+	// Check if the Node re-registered with the same ProviderID, e.g. after the host has been reprovisioned at the OS level.
+	// By default the Node is accepted and the UID in the NodeRef is updated; Machines can opt in to report the Node
+	// the Machine is linked to as not found, or to be linked to the new Node.
+	// NOTE: NodeRefs without UID, if any, are considered as matching the Node.
+	if machine.Status.NodeRef != nil && machine.Status.NodeRef.UID != "" && machine.Status.NodeRef.UID != node.UID {
+		switch machine.Spec.NodeReRegistrationPolicy {
+		case clusterv1.NodeNotFoundNodeReRegistrationPolicy:
+			conditions.MarkFalse(machine, clusterv1.MachineNodeHealthyCondition, clusterv1.NodeNotFoundReason, clusterv1.ConditionSeverityError, "Node %s re-registered with a different UID", node.Name)
+			return ctrl.Result{}, errors.Errorf("Node %s linked to Machine %q in namespace %q re-registered with UID %s, expected UID %s", node.Name, machine.Name, machine.Namespace, node.UID, machine.Status.NodeRef.UID)
+		case clusterv1.RelinkNodeReRegistrationPolicy:
+			log.Info("Node re-registered with the same ProviderID, linking the Machine to the new Node", "providerID", *machine.Spec.ProviderID, "previousNode", klog.KRef("", machine.Status.NodeRef.Name), "node", klog.KObj(node))
+			events.Normalf(r.recorder, machine, events.NodeReRegisteredReason, "Node %s re-registered with UID %s, previous Node %s had UID %s", node.Name, node.UID, machine.Status.NodeRef.Name, machine.Status.NodeRef.UID)
+			machine.Status.NodeRef = nil
+		default:
+			machine.Status.NodeRef.UID = node.UID
+		}
+	}
+
 	// Set the Machine NodeRef.
 	if machine.Status.NodeRef == nil {
 		machine.Status.NodeRef = &corev1.ObjectReference{
@@ -93,19 +123,30 @@ func (r *Reconciler) reconcileNode(ctx context.Context, s *scope) (ctrl.Result,
 			UID:        node.UID,
 		}
 		log.Info("Infrastructure provider reporting spec.providerID, Kubernetes node is now available", machine.Spec.InfrastructureRef.Kind, klog.KRef(machine.Spec.InfrastructureRef.Namespace, machine.Spec.InfrastructureRef.Name), "providerID", *machine.Spec.ProviderID, "node", klog.KRef("", machine.Status.NodeRef.Name))
-		r.recorder.Event(machine, corev1.EventTypeNormal, "SuccessfulSetNodeRef", machine.Status.NodeRef.Name)
+		events.Normal(r.recorder, machine, events.SuccessfulSetNodeRefReason, machine.Status.NodeRef.Name)
 	}
 
 	// Set the NodeSystemInfo.
 	machine.Status.NodeInfo = &node.Status.NodeInfo
 
-	// Compute all the annotations that CAPI is setting on nodes;
+	// Mirror the resources of the Node, e.g. to surface GPUs or other devices.
+	machine.Status.Capacity = node.Status.Capacity
+	machine.Status.Allocatable = node.Status.Allocatable
+
+	// Reflect labels from the Node to the Machine, e.g. to surface allocation info like region and zone.
+	syncNodeToMachineLabels(machine, node, r.NodeToMachineLabels)
+
+	// Compute all the annotations that CAPI is setting on nodes, including the ones from the Cluster metadata;
 	// CAPI only enforces some annotations and never changes or removes them.
-	nodeAnnotations := map[string]string{
-		clusterv1.ClusterNameAnnotation:      machine.Spec.ClusterName,
-		clusterv1.ClusterNamespaceAnnotation: machine.GetNamespace(),
-		clusterv1.MachineAnnotation:          machine.Name,
+	nodeAnnotations := map[string]string{}
+	if cluster.Spec.Metadata != nil {
+		for k, v := range cluster.Spec.Metadata.Annotations {
+			nodeAnnotations[k] = v
+		}
 	}
+	nodeAnnotations[clusterv1.ClusterNameAnnotation] = machine.Spec.ClusterName
+	nodeAnnotations[clusterv1.ClusterNamespaceAnnotation] = machine.GetNamespace()
+	nodeAnnotations[clusterv1.MachineAnnotation] = machine.Name
 	if owner := metav1.GetControllerOfNoCopy(machine); owner != nil {
 		nodeAnnotations[clusterv1.OwnerKindAnnotation] = owner.Kind
 		nodeAnnotations[clusterv1.OwnerNameAnnotation] = owner.Name
@@ -114,7 +155,17 @@ func (r *Reconciler) reconcileNode(ctx context.Context, s *scope) (ctrl.Result,
 	// Compute labels to be propagated from Machines to nodes.
 	// NOTE: CAPI should manage only a subset of node labels, everything else should be preserved.
 	// NOTE: Once we reconcile node labels for the first time, the NodeUninitializedTaint is removed from the node.
-	nodeLabels := getManagedLabels(machine.Labels)
+	// NOTE: All the labels from the Cluster metadata are propagated, no matter of their domain; the managed labels
+	// of the Machine take precedence over them.
+	nodeLabels := map[string]string{}
+	if cluster.Spec.Metadata != nil {
+		for k, v := range cluster.Spec.Metadata.Labels {
+			nodeLabels[k] = v
+		}
+	}
+	for k, v := range getManagedLabels(machine.Labels, r.machineToNodeLabelDomains()) {
+		nodeLabels[k] = v
+	}
 
 	// Get interruptible instance status from the infrastructure provider and set the interruptible label on the node.
 	interruptible := false
@@ -130,17 +181,31 @@ func (r *Reconciler) reconcileNode(ctx context.Context, s *scope) (ctrl.Result,
 		}
 	}
 
+	// Add the labels and the default taints of the failure domain the Machine is placed in, if any.
+	var nodeTaints []corev1.Taint
+	if machine.Spec.FailureDomain != nil {
+		if failureDomain, ok := cluster.Status.FailureDomains[*machine.Spec.FailureDomain]; ok {
+			for k, v := range failureDomain.Labels {
+				nodeLabels[k] = v
+			}
+			nodeTaints = failureDomain.Taints
+		}
+	}
+
 	_, nodeHadInterruptibleLabel := node.Labels[clusterv1.InterruptibleLabel]
 
+	// Cordon the node if requested with the cordon-node annotation on the Machine.
+	_, cordon := machine.Annotations[clusterv1.CordonNodeAnnotation]
+
 	// Reconcile node taints
-	if err := r.patchNode(ctx, remoteClient, node, nodeLabels, nodeAnnotations); err != nil {
+	if err := r.patchNode(ctx, remoteClient, node, nodeLabels, nodeAnnotations, nodeTaints, cordon); err != nil {
 		return ctrl.Result{}, errors.Wrapf(err, "failed to reconcile Node %s", klog.KObj(node))
 	}
 	if !nodeHadInterruptibleLabel && interruptible {
 		// If the interruptible label is added to the node then record the event.
 		// Nb. Only record the event if the node previously did not have the label to avoid recording
 		// the event during every reconcile.
-		r.recorder.Event(machine, corev1.EventTypeNormal, "SuccessfulSetInterruptibleNodeLabel", node.Name)
+		events.Normal(r.recorder, machine, events.SuccessfulSetInterruptibleNodeLabelReason, node.Name)
 	}
 
 	// Do the remaining node health checks, then set the node health to true if all checks pass.
@@ -158,20 +223,70 @@ func (r *Reconciler) reconcileNode(ctx context.Context, s *scope) (ctrl.Result,
 	return ctrl.Result{}, nil
 }
 
+// reconcileNodeStartupTimeout checks if the Machine has been waiting for a Node longer than NodeStartupTimeout.
+// If the timeout is exceeded, the NodeHealthy condition is set to false and, when the Machine is owned by
+// a MachineSet, the Machine is marked for remediation, so the MachineSet replaces it; otherwise a requeue
+// is scheduled for when the timeout expires.
+func (r *Reconciler) reconcileNodeStartupTimeout(ctx context.Context, machine *clusterv1.Machine) ctrl.Result {
+	log := ctrl.LoggerFrom(ctx)
+
+	// If the NodeStartupTimeout is not set by the user, wait for the Node without any time limitations.
+	if machine.Spec.NodeStartupTimeout == nil || machine.Spec.NodeStartupTimeout.Seconds() <= 0 {
+		return ctrl.Result{}
+	}
+
+	timeout := machine.Spec.NodeStartupTimeout.Duration
+	if elapsed := time.Since(machine.CreationTimestamp.Time); elapsed < timeout {
+		return ctrl.Result{RequeueAfter: timeout - elapsed}
+	}
+
+	log.Info("Node failed to start up within NodeStartupTimeout", "timeout", timeout.String())
+	conditions.MarkFalse(machine, clusterv1.MachineNodeHealthyCondition, clusterv1.NodeStartupTimeoutReason, clusterv1.ConditionSeverityError, "Node failed to start up within %s", timeout)
+
+	// If the Machine is owned by a MachineSet, mark the Machine for remediation, so the MachineSet replaces it.
+	// NOTE: If remediation is already in progress, e.g. because a MachineHealthCheck marked the Machine as unhealthy, it is preserved.
+	if owner := metav1.GetControllerOfNoCopy(machine); owner != nil && owner.Kind == "MachineSet" && conditions.Get(machine, clusterv1.MachineOwnerRemediatedCondition) == nil {
+		conditions.MarkFalse(machine, clusterv1.MachineOwnerRemediatedCondition, clusterv1.WaitingForRemediationReason, clusterv1.ConditionSeverityWarning, "")
+		events.Warningf(r.recorder, machine, events.NodeStartupTimeoutReason, "Node failed to start up within %s, marking Machine for remediation", timeout)
+	}
+	return ctrl.Result{}
+}
+
+// machineToNodeLabelDomains returns the label domains of the Machine labels propagated to the Node.
+func (r *Reconciler) machineToNodeLabelDomains() []string {
+	if len(r.MachineToNodeLabelDomains) > 0 {
+		return r.MachineToNodeLabelDomains
+	}
+	return defaultMachineToNodeLabelDomains
+}
+
+// syncNodeToMachineLabels sets the Node labels with the given keys on the Machine.
+// NOTE: Reflected labels are added or updated, but never removed from the Machine.
+func syncNodeToMachineLabels(machine *clusterv1.Machine, node *corev1.Node, keys []string) {
+	for _, key := range keys {
+		value, ok := node.Labels[key]
+		if !ok {
+			continue
+		}
+		if machine.Labels == nil {
+			machine.Labels = map[string]string{}
+		}
+		machine.Labels[key] = value
+	}
+}
+
 // getManagedLabels gets a map[string]string and returns another map[string]string
-// filtering out labels not managed by CAPI.
-func getManagedLabels(labels map[string]string) map[string]string {
+// filtering out labels not in the given label domains; a domain with the "*." prefix
+// matches all the subdomains of the domain.
+func getManagedLabels(labels map[string]string, domains []string) map[string]string {
 	managedLabels := make(map[string]string)
 	for key, value := range labels {
 		dnsSubdomainOrName := strings.Split(key, "/")[0]
-		if dnsSubdomainOrName == clusterv1.NodeRoleLabelPrefix {
-			managedLabels[key] = value
-		}
-		if dnsSubdomainOrName == clusterv1.NodeRestrictionLabelDomain || strings.HasSuffix(dnsSubdomainOrName, "."+clusterv1.NodeRestrictionLabelDomain) {
-			managedLabels[key] = value
-		}
-		if dnsSubdomainOrName == clusterv1.ManagedNodeLabelDomain || strings.HasSuffix(dnsSubdomainOrName, "."+clusterv1.ManagedNodeLabelDomain) {
-			managedLabels[key] = value
+		for _, domain := range domains {
+			if dnsSubdomainOrName == domain || (strings.HasPrefix(domain, "*.") && strings.HasSuffix(dnsSubdomainOrName, domain[1:])) {
+				managedLabels[key] = value
+				break
+			}
 		}
 	}
 
@@ -255,9 +370,23 @@ func (r *Reconciler) getNode(ctx context.Context, c client.Reader, providerID st
 
 // PatchNode is required to workaround an issue on Node.Status.Address which is incorrectly annotated as patchStrategy=merge
 // and this causes SSA patch to fail in case there are two addresses with the same key https://github.com/kubernetes-sigs/cluster-api/issues/8417
-func (r *Reconciler) patchNode(ctx context.Context, remoteClient client.Client, node *corev1.Node, newLabels, newAnnotations map[string]string) error {
+// NOTE: newTaints are only added when the Node is reconciled for the first time, so they can be removed from the Node afterwards.
+// NOTE: if cordon is true the Node is cordoned, otherwise the Node is uncordoned only if it was previously cordoned by this func.
+func (r *Reconciler) patchNode(ctx context.Context, remoteClient client.Client, node *corev1.Node, newLabels, newAnnotations map[string]string, newTaints []corev1.Taint, cordon bool) error {
 	newNode := node.DeepCopy()
 
+	// Add the default taints if this is the first time the Node is reconciled, i.e. the Node has not been
+	// annotated with the labels from the Machine yet.
+	hasTaintChanges := false
+	if _, reconciled := newNode.Annotations[clusterv1.LabelsFromMachineAnnotation]; !reconciled {
+		for _, taint := range newTaints {
+			if !taints.HasTaint(newNode.Spec.Taints, taint) {
+				newNode.Spec.Taints = append(newNode.Spec.Taints, taint)
+				hasTaintChanges = true
+			}
+		}
+	}
+
 	// Adds the annotations CAPI sets on the node.
 	hasAnnotationChanges := annotations.AddAnnotations(newNode, newAnnotations)
 
@@ -290,11 +419,34 @@ func (r *Reconciler) patchNode(ctx context.Context, remoteClient client.Client,
 	annotations.AddAnnotations(newNode, map[string]string{clusterv1.LabelsFromMachineAnnotation: strings.Join(labelsFromCurrentReconcile, ",")})
 
 	// Drop the NodeUninitializedTaint taint on the node given that we are reconciling labels.
-	hasTaintChanges := taints.RemoveNodeTaint(newNode, clusterv1.NodeUninitializedTaint)
+	if taints.RemoveNodeTaint(newNode, clusterv1.NodeUninitializedTaint) {
+		hasTaintChanges = true
+	}
+
+	hasCordonChanges := reconcileNodeCordon(newNode, cordon)
 
-	if !hasAnnotationChanges && !hasLabelChanges && !hasTaintChanges {
+	if !hasAnnotationChanges && !hasLabelChanges && !hasTaintChanges && !hasCordonChanges {
 		return nil
 	}
 
 	return remoteClient.Patch(ctx, newNode, client.StrategicMergeFrom(node))
 }
+
+// reconcileNodeCordon cordons the Node if cordon is true, tracking it with the CordonedByMachineAnnotation; otherwise, it
+// uncordons the Node only if the Node has been cordoned by Cluster API, so Nodes cordoned by other actors are preserved.
+// If the Node is already cordoned by another actor when cordon is true, the Node is not tracked, so it stays cordoned
+// after cordon becomes false. It returns true if the Node has been changed.
+func reconcileNodeCordon(node *corev1.Node, cordon bool) bool {
+	_, cordonedByMachine := node.Annotations[clusterv1.CordonedByMachineAnnotation]
+	switch {
+	case cordon && !node.Spec.Unschedulable:
+		node.Spec.Unschedulable = true
+		annotations.AddAnnotations(node, map[string]string{clusterv1.CordonedByMachineAnnotation: ""})
+		return true
+	case !cordon && cordonedByMachine:
+		node.Spec.Unschedulable = false
+		delete(node.Annotations, clusterv1.CordonedByMachineAnnotation)
+		return true
+	}
+	return false
+}
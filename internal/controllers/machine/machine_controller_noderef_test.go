@@ -26,15 +26,20 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/api/v1beta1/index"
 	"sigs.k8s.io/cluster-api/controllers/remote"
 	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/kubeconfig"
 )
 
@@ -510,8 +515,250 @@ func TestGetManagedLabels(t *testing.T) {
 	}
 
 	g := NewWithT(t)
-	got := getManagedLabels(allLabels)
+	got := getManagedLabels(allLabels, defaultMachineToNodeLabelDomains)
 	g.Expect(got).To(BeEquivalentTo(managedLabels))
+
+	// Only labels in the configured label domains are managed.
+	got = getManagedLabels(allLabels, []string{"company.xyz", "*.node-restriction.kubernetes.io"})
+	g.Expect(got).To(BeEquivalentTo(map[string]string{
+		"company.xyz/node.cluster.x-k8s.io":                                   "not-managed",
+		"company.xyz/node-restriction.kubernetes.io":                          "not-managed",
+		"custom-prefix." + clusterv1.NodeRestrictionLabelDomain:               "",
+		"custom-prefix." + clusterv1.NodeRestrictionLabelDomain + "/anything": "",
+	}))
+}
+
+func TestSyncNodeToMachineLabels(t *testing.T) {
+	g := NewWithT(t)
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				corev1.LabelTopologyRegion: "region-a",
+				corev1.LabelTopologyZone:   "zone-b",
+				"foo":                      "bar",
+			},
+		},
+	}
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				corev1.LabelTopologyZone: "zone-a",
+				"hello":                  "world",
+			},
+		},
+	}
+
+	syncNodeToMachineLabels(machine, node, []string{corev1.LabelTopologyRegion, corev1.LabelTopologyZone, corev1.LabelInstanceTypeStable})
+	g.Expect(machine.Labels).To(Equal(map[string]string{
+		corev1.LabelTopologyRegion: "region-a",
+		corev1.LabelTopologyZone:   "zone-b",
+		"hello":                    "world",
+	}))
+
+	// Machines without labels get the reflected labels.
+	machine = &clusterv1.Machine{}
+	syncNodeToMachineLabels(machine, node, []string{corev1.LabelTopologyZone})
+	g.Expect(machine.Labels).To(Equal(map[string]string{corev1.LabelTopologyZone: "zone-b"}))
+}
+
+func TestReconcileNodeStartupTimeout(t *testing.T) {
+	machineSetOwner := metav1.OwnerReference{
+		APIVersion: clusterv1.GroupVersion.String(),
+		Kind:       "MachineSet",
+		Name:       "ms",
+		Controller: pointer.Bool(true),
+	}
+
+	tests := []struct {
+		name                      string
+		nodeStartupTimeout        *metav1.Duration
+		creationTimestamp         time.Time
+		ownerReferences           []metav1.OwnerReference
+		expectRequeue             bool
+		expectNodeStartupTimedOut bool
+		expectOwnerRemediated     bool
+	}{
+		{
+			name:              "NodeStartupTimeout not set",
+			creationTimestamp: time.Now().Add(-time.Hour),
+			ownerReferences:   []metav1.OwnerReference{machineSetOwner},
+		},
+		{
+			name:               "NodeStartupTimeout set to 0",
+			nodeStartupTimeout: &metav1.Duration{Duration: 0},
+			creationTimestamp:  time.Now().Add(-time.Hour),
+			ownerReferences:    []metav1.OwnerReference{machineSetOwner},
+		},
+		{
+			name:               "NodeStartupTimeout not exceeded",
+			nodeStartupTimeout: &metav1.Duration{Duration: 10 * time.Minute},
+			creationTimestamp:  time.Now().Add(-5 * time.Minute),
+			ownerReferences:    []metav1.OwnerReference{machineSetOwner},
+			expectRequeue:      true,
+		},
+		{
+			name:                      "NodeStartupTimeout exceeded, Machine owned by a MachineSet",
+			nodeStartupTimeout:        &metav1.Duration{Duration: 10 * time.Minute},
+			creationTimestamp:         time.Now().Add(-time.Hour),
+			ownerReferences:           []metav1.OwnerReference{machineSetOwner},
+			expectNodeStartupTimedOut: true,
+			expectOwnerRemediated:     true,
+		},
+		{
+			name:                      "NodeStartupTimeout exceeded, Machine not owned by a MachineSet",
+			nodeStartupTimeout:        &metav1.Duration{Duration: 10 * time.Minute},
+			creationTimestamp:         time.Now().Add(-time.Hour),
+			expectNodeStartupTimedOut: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			machine := &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "machine",
+					Namespace:         metav1.NamespaceDefault,
+					CreationTimestamp: metav1.Time{Time: tt.creationTimestamp},
+					OwnerReferences:   tt.ownerReferences,
+				},
+				Spec: clusterv1.MachineSpec{
+					NodeStartupTimeout: tt.nodeStartupTimeout,
+				},
+			}
+
+			r := &Reconciler{
+				recorder: record.NewFakeRecorder(10),
+			}
+			res := r.reconcileNodeStartupTimeout(ctx, machine)
+			if tt.expectRequeue {
+				g.Expect(res.RequeueAfter).To(BeNumerically(">", 0))
+			} else {
+				g.Expect(res.RequeueAfter).To(BeZero())
+			}
+
+			if tt.expectNodeStartupTimedOut {
+				g.Expect(conditions.IsFalse(machine, clusterv1.MachineNodeHealthyCondition)).To(BeTrue())
+				g.Expect(conditions.GetReason(machine, clusterv1.MachineNodeHealthyCondition)).To(Equal(clusterv1.NodeStartupTimeoutReason))
+			} else {
+				g.Expect(conditions.Has(machine, clusterv1.MachineNodeHealthyCondition)).To(BeFalse())
+			}
+
+			if tt.expectOwnerRemediated {
+				g.Expect(conditions.IsFalse(machine, clusterv1.MachineOwnerRemediatedCondition)).To(BeTrue())
+				g.Expect(conditions.GetReason(machine, clusterv1.MachineOwnerRemediatedCondition)).To(Equal(clusterv1.WaitingForRemediationReason))
+			} else {
+				g.Expect(conditions.Has(machine, clusterv1.MachineOwnerRemediatedCondition)).To(BeFalse())
+			}
+		})
+	}
+}
+
+func TestReconcileNodeReRegistration(t *testing.T) {
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: metav1.NamespaceDefault,
+		},
+	}
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node-2",
+			UID:  "uid-2",
+		},
+		Spec: corev1.NodeSpec{
+			ProviderID: "test://id-1",
+		},
+	}
+
+	tests := []struct {
+		name                     string
+		nodeRef                  *corev1.ObjectReference
+		nodeReRegistrationPolicy clusterv1.NodeReRegistrationPolicy
+		wantErr                  bool
+		wantNodeName             string
+		wantNodeUID              types.UID
+	}{
+		{
+			name:         "NodeRef is set when not set yet",
+			wantNodeName: "node-2",
+			wantNodeUID:  "uid-2",
+		},
+		{
+			name:         "NodeRef is preserved when the Node has the same UID",
+			nodeRef:      &corev1.ObjectReference{Kind: "Node", APIVersion: "v1", Name: "node-2", UID: "uid-2"},
+			wantNodeName: "node-2",
+			wantNodeUID:  "uid-2",
+		},
+		{
+			name:         "NodeRef without UID is preserved",
+			nodeRef:      &corev1.ObjectReference{Kind: "Node", APIVersion: "v1", Name: "node-2"},
+			wantNodeName: "node-2",
+			wantNodeUID:  "",
+		},
+		{
+			name:         "Node re-registered with a different UID is accepted by default",
+			nodeRef:      &corev1.ObjectReference{Kind: "Node", APIVersion: "v1", Name: "node-2", UID: "uid-1"},
+			wantNodeName: "node-2",
+			wantNodeUID:  "uid-2",
+		},
+		{
+			name:                     "Node re-registered with a different UID is reported as not found with the NodeNotFound policy",
+			nodeRef:                  &corev1.ObjectReference{Kind: "Node", APIVersion: "v1", Name: "node-1", UID: "uid-1"},
+			nodeReRegistrationPolicy: clusterv1.NodeNotFoundNodeReRegistrationPolicy,
+			wantErr:                  true,
+			wantNodeName:             "node-1",
+			wantNodeUID:              "uid-1",
+		},
+		{
+			name:                     "Machine is linked to the Node re-registered with a different UID with the Relink policy",
+			nodeRef:                  &corev1.ObjectReference{Kind: "Node", APIVersion: "v1", Name: "node-1", UID: "uid-1"},
+			nodeReRegistrationPolicy: clusterv1.RelinkNodeReRegistrationPolicy,
+			wantNodeName:             "node-2",
+			wantNodeUID:              "uid-2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			machine := &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "machine",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: clusterv1.MachineSpec{
+					ClusterName:              cluster.Name,
+					ProviderID:               pointer.String(node.Spec.ProviderID),
+					NodeReRegistrationPolicy: tt.nodeReRegistrationPolicy,
+				},
+				Status: clusterv1.MachineStatus{
+					NodeRef: tt.nodeRef,
+				},
+			}
+
+			fakeClient := fake.NewClientBuilder().WithObjects(node.DeepCopy()).WithIndex(&corev1.Node{}, index.NodeProviderIDField, index.NodeByProviderID).Build()
+			r := &Reconciler{
+				Client:   fakeClient,
+				Tracker:  remote.NewTestClusterCacheTracker(ctrl.Log, fakeClient, fakeClient.Scheme(), client.ObjectKeyFromObject(cluster)),
+				recorder: record.NewFakeRecorder(10),
+			}
+
+			_, err := r.reconcileNode(ctx, &scope{cluster: cluster, machine: machine})
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(conditions.GetReason(machine, clusterv1.MachineNodeHealthyCondition)).To(Equal(clusterv1.NodeNotFoundReason))
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(conditions.GetReason(machine, clusterv1.MachineNodeHealthyCondition)).ToNot(Equal(clusterv1.NodeNotFoundReason))
+			}
+			g.Expect(machine.Status.NodeRef).ToNot(BeNil())
+			g.Expect(machine.Status.NodeRef.Name).To(Equal(tt.wantNodeName))
+			g.Expect(machine.Status.NodeRef.UID).To(Equal(tt.wantNodeUID))
+		})
+	}
 }
 
 func TestPatchNode(t *testing.T) {
@@ -520,6 +767,7 @@ func TestPatchNode(t *testing.T) {
 		oldNode             *corev1.Node
 		newLabels           map[string]string
 		newAnnotations      map[string]string
+		newTaints           []corev1.Taint
 		expectedLabels      map[string]string
 		expectedAnnotations map[string]string
 		expectedTaints      []corev1.Taint
@@ -739,6 +987,44 @@ func TestPatchNode(t *testing.T) {
 				{Key: "node.kubernetes.io/not-ready", Effect: "NoSchedule"}, // Added by the API server
 			},
 		},
+		{
+			name: "Add default taints when the node is reconciled for the first time",
+			oldNode: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: fmt.Sprintf("node-%s", util.RandomString(6)),
+				},
+			},
+			newTaints: []corev1.Taint{
+				{Key: "example.com/dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule},
+			},
+			expectedAnnotations: map[string]string{
+				clusterv1.LabelsFromMachineAnnotation: "",
+			},
+			expectedTaints: []corev1.Taint{
+				{Key: "node.kubernetes.io/not-ready", Effect: "NoSchedule"}, // Added by the API server
+				{Key: "example.com/dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule},
+			},
+		},
+		{
+			name: "Do not add default taints when the node has already been reconciled",
+			oldNode: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: fmt.Sprintf("node-%s", util.RandomString(6)),
+					Annotations: map[string]string{
+						clusterv1.LabelsFromMachineAnnotation: "",
+					},
+				},
+			},
+			newTaints: []corev1.Taint{
+				{Key: "example.com/dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule},
+			},
+			expectedAnnotations: map[string]string{
+				clusterv1.LabelsFromMachineAnnotation: "",
+			},
+			expectedTaints: []corev1.Taint{
+				{Key: "node.kubernetes.io/not-ready", Effect: "NoSchedule"}, // Added by the API server
+			},
+		},
 	}
 
 	r := Reconciler{
@@ -755,7 +1041,7 @@ func TestPatchNode(t *testing.T) {
 				_ = env.Cleanup(ctx, oldNode)
 			})
 
-			err := r.patchNode(ctx, env, oldNode, tc.newLabels, tc.newAnnotations)
+			err := r.patchNode(ctx, env, oldNode, tc.newLabels, tc.newAnnotations, tc.newTaints, false)
 			g.Expect(err).ToNot(HaveOccurred())
 
 			g.Eventually(func(g Gomega) {
@@ -770,3 +1056,80 @@ func TestPatchNode(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcileNodeCordon(t *testing.T) {
+	testCases := []struct {
+		name                string
+		node                *corev1.Node
+		cordon              bool
+		expectedChanged     bool
+		expectedUnscheduled bool
+		expectedAnnotations map[string]string
+	}{
+		{
+			name:                "Cordon a schedulable node",
+			node:                &corev1.Node{},
+			cordon:              true,
+			expectedChanged:     true,
+			expectedUnscheduled: true,
+			expectedAnnotations: map[string]string{clusterv1.CordonedByMachineAnnotation: ""},
+		},
+		{
+			name: "Cordon again a node uncordoned by another actor",
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{clusterv1.CordonedByMachineAnnotation: ""}},
+			},
+			cordon:              true,
+			expectedChanged:     true,
+			expectedUnscheduled: true,
+			expectedAnnotations: map[string]string{clusterv1.CordonedByMachineAnnotation: ""},
+		},
+		{
+			name: "Do not track a node already cordoned by another actor",
+			node: &corev1.Node{
+				Spec: corev1.NodeSpec{Unschedulable: true},
+			},
+			cordon:              true,
+			expectedChanged:     false,
+			expectedUnscheduled: true,
+		},
+		{
+			name: "Uncordon a node cordoned by the machine",
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{clusterv1.CordonedByMachineAnnotation: ""}},
+				Spec:       corev1.NodeSpec{Unschedulable: true},
+			},
+			cordon:              false,
+			expectedChanged:     true,
+			expectedUnscheduled: false,
+			expectedAnnotations: map[string]string{},
+		},
+		{
+			name: "Do not uncordon a node cordoned by another actor",
+			node: &corev1.Node{
+				Spec: corev1.NodeSpec{Unschedulable: true},
+			},
+			cordon:              false,
+			expectedChanged:     false,
+			expectedUnscheduled: true,
+		},
+		{
+			name:                "Do nothing on a schedulable node",
+			node:                &corev1.Node{},
+			cordon:              false,
+			expectedChanged:     false,
+			expectedUnscheduled: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			node := tc.node.DeepCopy()
+
+			g.Expect(reconcileNodeCordon(node, tc.cordon)).To(Equal(tc.expectedChanged))
+			g.Expect(node.Spec.Unschedulable).To(Equal(tc.expectedUnscheduled))
+			g.Expect(node.Annotations).To(BeComparableTo(tc.expectedAnnotations))
+		})
+	}
+}
@@ -31,11 +31,11 @@ import (
 	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
-	"sigs.k8s.io/controller-runtime/pkg/handler"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controllers/external"
 	capierrors "sigs.k8s.io/cluster-api/errors"
+	"sigs.k8s.io/cluster-api/internal/contract"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
@@ -43,10 +43,6 @@ import (
 	"sigs.k8s.io/cluster-api/util/patch"
 )
 
-var (
-	externalReadyWait = 30 * time.Second
-)
-
 func (r *Reconciler) reconcilePhase(_ context.Context, m *clusterv1.Machine) {
 	originalPhase := m.Status.Phase
 
@@ -82,6 +78,11 @@ func (r *Reconciler) reconcilePhase(_ context.Context, m *clusterv1.Machine) {
 
 	// If the phase has changed, update the LastUpdated timestamp
 	if m.Status.Phase != originalPhase {
+		// Observe the time spent in the previous phase, which started when LastUpdated has been set.
+		if originalPhase != "" && m.Status.LastUpdated != nil {
+			observePhaseDuration(m, clusterv1.MachinePhase(originalPhase), m.Status.LastUpdated.Time)
+		}
+
 		now := metav1.Now()
 		m.Status.LastUpdated = &now
 	}
@@ -98,17 +99,14 @@ func (r *Reconciler) reconcileExternal(ctx context.Context, cluster *clusterv1.C
 	obj, err := external.Get(ctx, r.UnstructuredCachingClient, ref, m.Namespace)
 	if err != nil {
 		if apierrors.IsNotFound(errors.Cause(err)) {
-			log.Info("could not find external ref, requeuing", ref.Kind, klog.KRef(ref.Namespace, ref.Name))
-			return external.ReconcileOutput{RequeueAfter: externalReadyWait}, nil
+			// NOTE: There is no need to requeue, the Machine is reconciled as soon as the external object is created,
+			// because the referenced kind is watched by the externalTracker.
+			log.Info("could not find external ref, waiting for it to be created", ref.Kind, klog.KRef(ref.Namespace, ref.Name))
+			return external.ReconcileOutput{}, nil
 		}
 		return external.ReconcileOutput{}, err
 	}
 
-	// Ensure we add a watch to the external object, if there isn't one already.
-	if err := r.externalTracker.Watch(log, obj, handler.EnqueueRequestForOwner(r.Client.Scheme(), r.Client.RESTMapper(), &clusterv1.Machine{})); err != nil {
-		return external.ReconcileOutput{}, err
-	}
-
 	// if external ref is paused, return error.
 	if annotations.IsPaused(cluster, obj) {
 		log.V(3).Info("External object referenced is paused")
@@ -188,8 +186,9 @@ func (r *Reconciler) reconcileBootstrap(ctx context.Context, s *scope) (ctrl.Res
 		return ctrl.Result{}, nil
 	}
 
-	if externalResult.RequeueAfter > 0 {
-		return ctrl.Result{RequeueAfter: externalResult.RequeueAfter}, nil
+	// If the bootstrap config does not exist yet, return.
+	if externalResult.Result == nil {
+		return ctrl.Result{}, nil
 	}
 
 	// If the bootstrap data is populated, set ready and return.
@@ -250,7 +249,7 @@ func (r *Reconciler) reconcileInfrastructure(ctx context.Context, s *scope) (ctr
 		return ctrl.Result{}, err
 	}
 	s.infraMachine = infraReconcileResult.Result
-	if infraReconcileResult.RequeueAfter > 0 {
+	if infraReconcileResult.Result == nil && !infraReconcileResult.Paused {
 		// Infra object went missing after the machine was up and running
 		if m.Status.InfrastructureReady {
 			log.Error(err, "Machine infrastructure reference has been deleted after being ready, setting failure state")
@@ -259,7 +258,7 @@ func (r *Reconciler) reconcileInfrastructure(ctx context.Context, s *scope) (ctr
 				m.Spec.InfrastructureRef.GroupVersionKind(), m.Spec.InfrastructureRef.Name))
 			return ctrl.Result{}, errors.Errorf("could not find %v %q for Machine %q in namespace %q, requeuing", m.Spec.InfrastructureRef.GroupVersionKind().String(), m.Spec.InfrastructureRef.Name, m.Name, m.Namespace)
 		}
-		return ctrl.Result{RequeueAfter: infraReconcileResult.RequeueAfter}, nil
+		return ctrl.Result{}, nil
 	}
 	// if the external object is paused, return without any further processing
 	if infraReconcileResult.Paused {
@@ -281,12 +280,27 @@ func (r *Reconciler) reconcileInfrastructure(ctx context.Context, s *scope) (ctr
 	}
 	m.Status.InfrastructureReady = ready
 
-	// Report a summary of current status of the infrastructure object defined for this machine.
+	// Report a summary of current status of the infrastructure object defined for this machine, including the
+	// provider-specific summary rows surfaced in status.summary, if any.
+	summaryRows, err := contract.InfrastructureMachine().Summary().Get(infraConfig)
+	// NOTE: The summary rows are only displayed, so a malformed status.summary must not block the reconcile;
+	// in this case the condition is mirrored without summary rows.
+	if err != nil && !errors.Is(err, contract.ErrFieldNotFound) {
+		log.Error(err, "Failed to retrieve summary from infrastructure provider, ignoring it", infraConfig.GetKind(), klog.KObj(infraConfig))
+		summaryRows = nil
+	}
 	conditions.SetMirror(m, clusterv1.InfrastructureReadyCondition,
 		conditions.UnstructuredGetter(infraConfig),
 		conditions.WithFallbackValue(ready, clusterv1.WaitingForInfrastructureFallbackReason, clusterv1.ConditionSeverityInfo, ""),
+		conditions.WithSummaryRows(summaryRows),
 	)
 
+	// Mirror the bootstrap progress reported by the infrastructure provider, if any, so failures on the node
+	// are visible on the Machine before the node join timeout.
+	if c := conditions.Get(conditions.UnstructuredGetter(infraConfig), clusterv1.BootstrapExecSucceededCondition); c != nil {
+		conditions.Set(m, c)
+	}
+
 	// If the infrastructure provider is not ready, return early.
 	if !ready {
 		log.Info("Waiting for infrastructure provider to create machine infrastructure and report status.ready", infraConfig.GetKind(), klog.KObj(infraConfig))
@@ -307,6 +321,20 @@ func (r *Reconciler) reconcileInfrastructure(ctx context.Context, s *scope) (ctr
 		return ctrl.Result{}, errors.Wrapf(err, "failed to retrieve addresses from infrastructure provider for Machine %q in namespace %q", m.Name, m.Namespace)
 	}
 
+	// Get and set Status.Capacity from the infrastructure provider until the Node exists, so the resources
+	// of the Machine are known before the Node joins the cluster; once the Node exists, they are copied from the Node.
+	if m.Status.NodeRef == nil {
+		var capacity corev1.ResourceList
+		err = util.UnstructuredUnmarshalField(infraConfig, &capacity, "status", "capacity")
+		switch {
+		case err == util.ErrUnstructuredFieldNotFound: // no-op
+		case err != nil:
+			return ctrl.Result{}, errors.Wrapf(err, "failed to retrieve capacity from infrastructure provider for Machine %q in namespace %q", m.Name, m.Namespace)
+		default:
+			m.Status.Capacity = capacity
+		}
+	}
+
 	// Get and set the failure domain from the infrastructure provider.
 	var failureDomain string
 	err = util.UnstructuredUnmarshalField(infraConfig, &failureDomain, "spec", "failureDomain")
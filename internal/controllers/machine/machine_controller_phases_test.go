@@ -23,6 +23,7 @@ import (
 
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/utils/pointer"
@@ -37,10 +38,6 @@ import (
 	"sigs.k8s.io/cluster-api/util/kubeconfig"
 )
 
-func init() {
-	externalReadyWait = 1 * time.Second
-}
-
 func TestReconcileMachinePhases(t *testing.T) {
 	var defaultKubeconfigSecret *corev1.Secret
 	defaultCluster := &clusterv1.Cluster{
@@ -733,7 +730,7 @@ func TestReconcileBootstrap(t *testing.T) {
 				"spec":   map[string]interface{}{},
 				"status": map[string]interface{}{},
 			},
-			expectResult: ctrl.Result{RequeueAfter: externalReadyWait},
+			expectResult: ctrl.Result{},
 			expectError:  false,
 			expected: func(g *WithT, m *clusterv1.Machine) {
 				g.Expect(m.Status.BootstrapReady).To(BeFalse())
@@ -751,7 +748,7 @@ func TestReconcileBootstrap(t *testing.T) {
 				"spec":   map[string]interface{}{},
 				"status": map[string]interface{}{},
 			},
-			expectResult: ctrl.Result{RequeueAfter: externalReadyWait},
+			expectResult: ctrl.Result{},
 			expectError:  false,
 		},
 		{
@@ -1066,6 +1063,109 @@ func TestReconcileInfrastructure(t *testing.T) {
 				g.Expect(m.Status.GetTypedPhase()).To(Equal(clusterv1.MachinePhaseFailed))
 			},
 		},
+		{
+			name: "new machine, infrastructure config ready with capacity",
+			infraConfig: map[string]interface{}{
+				"kind":       "GenericInfrastructureMachine",
+				"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+				"metadata": map[string]interface{}{
+					"name":      "infra-config1",
+					"namespace": metav1.NamespaceDefault,
+				},
+				"spec": map[string]interface{}{
+					"providerID": "test://id-1",
+				},
+				"status": map[string]interface{}{
+					"ready": true,
+					"capacity": map[string]interface{}{
+						"cpu":            "4",
+						"memory":         "16Gi",
+						"nvidia.com/gpu": "1",
+					},
+				},
+			},
+			expectResult:  ctrl.Result{},
+			expectError:   false,
+			expectChanged: true,
+			expected: func(g *WithT, m *clusterv1.Machine) {
+				g.Expect(m.Status.Capacity).To(HaveLen(3))
+				g.Expect(m.Status.Capacity.Cpu().String()).To(Equal("4"))
+				g.Expect(m.Status.Capacity.Memory().String()).To(Equal("16Gi"))
+				g.Expect(m.Status.Capacity.Name("nvidia.com/gpu", resource.DecimalSI).String()).To(Equal("1"))
+			},
+		},
+		{
+			name: "machine with a node, infrastructure config ready with capacity, capacity is not copied",
+			machine: &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "machine-test",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: defaultMachine.Spec,
+				Status: clusterv1.MachineStatus{
+					NodeRef:  &corev1.ObjectReference{Kind: "Node", Name: "machine-test-node"},
+					Capacity: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+				},
+			},
+			infraConfig: map[string]interface{}{
+				"kind":       "GenericInfrastructureMachine",
+				"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+				"metadata": map[string]interface{}{
+					"name":      "infra-config1",
+					"namespace": metav1.NamespaceDefault,
+				},
+				"spec": map[string]interface{}{
+					"providerID": "test://id-1",
+				},
+				"status": map[string]interface{}{
+					"ready": true,
+					"capacity": map[string]interface{}{
+						"cpu": "4",
+					},
+				},
+			},
+			expectResult:  ctrl.Result{},
+			expectError:   false,
+			expectChanged: true,
+			expected: func(g *WithT, m *clusterv1.Machine) {
+				g.Expect(m.Status.Capacity).To(HaveLen(1))
+				g.Expect(m.Status.Capacity.Cpu().String()).To(Equal("2"))
+			},
+		},
+		{
+			name: "new machine, infrastructure config not ready, bootstrap failure reported by the infrastructure provider",
+			infraConfig: map[string]interface{}{
+				"kind":       "GenericInfrastructureMachine",
+				"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+				"metadata": map[string]interface{}{
+					"name":      "infra-config1",
+					"namespace": metav1.NamespaceDefault,
+				},
+				"spec": map[string]interface{}{},
+				"status": map[string]interface{}{
+					"ready": false,
+					"conditions": []interface{}{
+						map[string]interface{}{
+							"type":               string(clusterv1.BootstrapExecSucceededCondition),
+							"status":             string(corev1.ConditionFalse),
+							"severity":           string(clusterv1.ConditionSeverityWarning),
+							"reason":             "BootstrapFailed",
+							"message":            "readiness check csi did not succeed within 300s",
+							"lastTransitionTime": "2023-01-01T00:00:00Z",
+						},
+					},
+				},
+			},
+			expectResult:  ctrl.Result{},
+			expectError:   false,
+			expectChanged: true,
+			expected: func(g *WithT, m *clusterv1.Machine) {
+				g.Expect(m.Status.InfrastructureReady).To(BeFalse())
+				g.Expect(conditions.IsFalse(m, clusterv1.BootstrapExecSucceededCondition)).To(BeTrue())
+				g.Expect(conditions.GetReason(m, clusterv1.BootstrapExecSucceededCondition)).To(Equal("BootstrapFailed"))
+				g.Expect(conditions.GetMessage(m, clusterv1.BootstrapExecSucceededCondition)).To(Equal("readiness check csi did not succeed within 300s"))
+			},
+		},
 		{
 			name: "infrastructure ref is paused",
 			infraConfig: map[string]interface{}{
@@ -0,0 +1,85 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util"
+)
+
+// taintNodeBeforeDrain adds the PreDrainTaint to the node, unless the node already has it, so that
+// cluster-autoscaler aware workloads and external load balancer controllers can deregister the node
+// before Pods are evicted.
+func (r *Reconciler) taintNodeBeforeDrain(ctx context.Context, cluster *clusterv1.Cluster, nodeName string) error {
+	remoteClient, err := r.Tracker.GetClient(ctx, util.ObjectKey(cluster))
+	if err != nil {
+		return err
+	}
+
+	node := &corev1.Node{}
+	if err := remoteClient.Get(ctx, client.ObjectKey{Name: nodeName}, node); err != nil {
+		if apierrors.IsNotFound(err) {
+			// If an admin deletes the node directly, there is nothing to taint.
+			return nil
+		}
+		return errors.Wrapf(err, "unable to get node %v", nodeName)
+	}
+
+	for i := range node.Spec.Taints {
+		if node.Spec.Taints[i].MatchTaint(r.PreDrainTaint) {
+			return nil
+		}
+	}
+
+	taint := r.PreDrainTaint.DeepCopy()
+	if taint.Effect == corev1.TaintEffectNoExecute {
+		taint.TimeAdded = &metav1.Time{Time: time.Now()}
+	}
+	newNode := node.DeepCopy()
+	newNode.Spec.Taints = append(newNode.Spec.Taints, *taint)
+	// Taints are not merged by key, so use an optimistic lock to not drop taints added in the meantime.
+	if err := remoteClient.Patch(ctx, newNode, client.MergeFromWithOptions(node, client.MergeFromWithOptimisticLock{})); err != nil {
+		return errors.Wrapf(err, "failed to add taint %s to node %v", r.PreDrainTaint.ToString(), nodeName)
+	}
+	return nil
+}
+
+// preDrainTaintObservationDeadline returns the time after which Pods can be evicted from the node of the
+// Machine, i.e. the end of the observation period after the node has been tainted; it returns false
+// if the PreDrainTaint is not set or the node has not been drained yet.
+func (r *Reconciler) preDrainTaintObservationDeadline(machine *clusterv1.Machine) (time.Time, bool) {
+	if r.PreDrainTaint == nil || r.PreDrainTaintObservationPeriod <= 0 {
+		return time.Time{}, false
+	}
+
+	// The NodeDrainStartTime is set when the node is drained for the first time, i.e. right before
+	// the node is tainted, and it does not change afterwards.
+	if machine.Status.NodeDrainStartTime == nil {
+		return time.Time{}, false
+	}
+
+	return machine.Status.NodeDrainStartTime.Add(r.PreDrainTaintObservationPeriod), true
+}
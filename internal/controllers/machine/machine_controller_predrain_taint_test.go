@@ -0,0 +1,184 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/remote"
+)
+
+func TestTaintNodeBeforeDrain(t *testing.T) {
+	preDrainTaint := &corev1.Taint{Key: "ToBeDeletedByClusterAutoscaler", Effect: corev1.TaintEffectNoSchedule}
+	otherTaint := corev1.Taint{Key: "other", Value: "taint", Effect: corev1.TaintEffectNoExecute}
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: metav1.NamespaceDefault,
+		},
+	}
+
+	tests := []struct {
+		name           string
+		nodeTaints     []corev1.Taint
+		expectedTaints []corev1.Taint
+	}{
+		{
+			name:           "Adds the taint to a node without taints",
+			expectedTaints: []corev1.Taint{*preDrainTaint},
+		},
+		{
+			name:           "Adds the taint preserving the other taints",
+			nodeTaints:     []corev1.Taint{otherTaint},
+			expectedTaints: []corev1.Taint{otherTaint, *preDrainTaint},
+		},
+		{
+			name:           "Does not add the taint twice",
+			nodeTaints:     []corev1.Taint{*preDrainTaint, otherTaint},
+			expectedTaints: []corev1.Taint{*preDrainTaint, otherTaint},
+		},
+		{
+			name:           "Does not add the taint if the node already has it with a different value",
+			nodeTaints:     []corev1.Taint{{Key: preDrainTaint.Key, Value: "1700000000", Effect: preDrainTaint.Effect}},
+			expectedTaints: []corev1.Taint{{Key: preDrainTaint.Key, Value: "1700000000", Effect: preDrainTaint.Effect}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-node"},
+				Spec:       corev1.NodeSpec{Taints: tt.nodeTaints},
+			}
+			fakeClient := fake.NewClientBuilder().WithObjects(node).Build()
+			r := &Reconciler{
+				Client:        fakeClient,
+				Tracker:       remote.NewTestClusterCacheTracker(ctrl.Log, fakeClient, fakeClient.Scheme(), client.ObjectKeyFromObject(cluster)),
+				PreDrainTaint: preDrainTaint,
+			}
+
+			g.Expect(r.taintNodeBeforeDrain(ctx, cluster, node.Name)).To(Succeed())
+
+			gotNode := &corev1.Node{}
+			g.Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(node), gotNode)).To(Succeed())
+			g.Expect(gotNode.Spec.Taints).To(Equal(tt.expectedTaints))
+		})
+	}
+
+	t.Run("Does not fail if the node does not exist", func(t *testing.T) {
+		g := NewWithT(t)
+
+		fakeClient := fake.NewClientBuilder().Build()
+		r := &Reconciler{
+			Client:        fakeClient,
+			Tracker:       remote.NewTestClusterCacheTracker(ctrl.Log, fakeClient, fakeClient.Scheme(), client.ObjectKeyFromObject(cluster)),
+			PreDrainTaint: preDrainTaint,
+		}
+
+		g.Expect(r.taintNodeBeforeDrain(ctx, cluster, "does-not-exist")).To(Succeed())
+	})
+}
+
+func TestPreDrainTaintObservationDeadline(t *testing.T) {
+	firstTimeDrain := time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+	drainStartTime := &metav1.Time{Time: firstTimeDrain}
+	preDrainTaint := &corev1.Taint{Key: "ToBeDeletedByClusterAutoscaler", Effect: corev1.TaintEffectNoSchedule}
+
+	tests := []struct {
+		name             string
+		taint            *corev1.Taint
+		period           time.Duration
+		drainStartTime   *metav1.Time
+		conditions       clusterv1.Conditions
+		expectedDeadline time.Time
+		expectedOK       bool
+	}{
+		{
+			name:           "No deadline if the taint is not set",
+			period:         5 * time.Minute,
+			drainStartTime: drainStartTime,
+			expectedOK:     false,
+		},
+		{
+			name:           "No deadline if the observation period is zero",
+			taint:          preDrainTaint,
+			drainStartTime: drainStartTime,
+			expectedOK:     false,
+		},
+		{
+			name:       "No deadline if the node has not been drained yet",
+			taint:      preDrainTaint,
+			period:     5 * time.Minute,
+			expectedOK: false,
+		},
+		{
+			name:             "Deadline is the observation period after the first drain",
+			taint:            preDrainTaint,
+			period:           5 * time.Minute,
+			drainStartTime:   drainStartTime,
+			expectedDeadline: firstTimeDrain.Add(5 * time.Minute),
+			expectedOK:       true,
+		},
+		{
+			name:           "Deadline is not moved by later changes of the DrainingSucceeded condition",
+			taint:          preDrainTaint,
+			period:         5 * time.Minute,
+			drainStartTime: drainStartTime,
+			conditions: clusterv1.Conditions{
+				{
+					Type:               clusterv1.DrainingSucceededCondition,
+					Status:             corev1.ConditionFalse,
+					LastTransitionTime: metav1.Now(),
+				},
+			},
+			expectedDeadline: firstTimeDrain.Add(5 * time.Minute),
+			expectedOK:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			r := &Reconciler{
+				PreDrainTaint:                  tt.taint,
+				PreDrainTaintObservationPeriod: tt.period,
+			}
+			machine := &clusterv1.Machine{
+				Status: clusterv1.MachineStatus{NodeDrainStartTime: tt.drainStartTime, Conditions: tt.conditions},
+			}
+
+			deadline, ok := r.preDrainTaintObservationDeadline(machine)
+			g.Expect(ok).To(Equal(tt.expectedOK))
+			if tt.expectedOK {
+				g.Expect(deadline).To(BeTemporally("==", tt.expectedDeadline))
+			}
+		})
+	}
+}
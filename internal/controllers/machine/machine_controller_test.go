@@ -25,11 +25,14 @@ import (
 	"github.com/go-logr/logr"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	fakeclientset "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/record"
+	kubedrain "k8s.io/kubectl/pkg/drain"
 	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -1126,6 +1129,90 @@ func TestRemoveMachineFinalizerAfterDeleteReconcile(t *testing.T) {
 	g.Expect(actual.ObjectMeta.Finalizers).To(Equal([]string{"test"}))
 }
 
+func TestWorkloadClusterUnreachableFor(t *testing.T) {
+	timeout := 5 * time.Minute
+
+	tests := []struct {
+		name               string
+		condition          *clusterv1.Condition
+		probeErr           error
+		expected           bool
+		expectedCondStatus corev1.ConditionStatus
+	}{
+		{
+			name:               "reachable cluster",
+			probeErr:           nil,
+			expected:           false,
+			expectedCondStatus: corev1.ConditionTrue,
+		},
+		{
+			name:               "cluster became unreachable",
+			condition:          conditions.TrueCondition(clusterv1.WorkloadClusterReachableCondition),
+			probeErr:           fmt.Errorf("connection refused"),
+			expected:           false,
+			expectedCondStatus: corev1.ConditionFalse,
+		},
+		{
+			name: "cluster unreachable for less than the timeout",
+			condition: &clusterv1.Condition{
+				Type:               clusterv1.WorkloadClusterReachableCondition,
+				Status:             corev1.ConditionFalse,
+				Severity:           clusterv1.ConditionSeverityWarning,
+				Reason:             clusterv1.WorkloadClusterUnreachableReason,
+				Message:            "Control plane of the workload cluster is unreachable",
+				LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Minute)),
+			},
+			probeErr:           fmt.Errorf("connection refused"),
+			expected:           false,
+			expectedCondStatus: corev1.ConditionFalse,
+		},
+		{
+			name: "cluster unreachable for more than the timeout",
+			condition: &clusterv1.Condition{
+				Type:               clusterv1.WorkloadClusterReachableCondition,
+				Status:             corev1.ConditionFalse,
+				Severity:           clusterv1.ConditionSeverityWarning,
+				Reason:             clusterv1.WorkloadClusterUnreachableReason,
+				Message:            "Control plane of the workload cluster is unreachable",
+				LastTransitionTime: metav1.NewTime(time.Now().Add(-10 * time.Minute)),
+			},
+			probeErr:           fmt.Errorf("connection refused"),
+			expected:           true,
+			expectedCondStatus: corev1.ConditionFalse,
+		},
+		{
+			name: "cluster reachable again",
+			condition: &clusterv1.Condition{
+				Type:               clusterv1.WorkloadClusterReachableCondition,
+				Status:             corev1.ConditionFalse,
+				Severity:           clusterv1.ConditionSeverityWarning,
+				Reason:             clusterv1.WorkloadClusterUnreachableReason,
+				Message:            "Control plane of the workload cluster is unreachable",
+				LastTransitionTime: metav1.NewTime(time.Now().Add(-10 * time.Minute)),
+			},
+			probeErr:           nil,
+			expected:           false,
+			expectedCondStatus: corev1.ConditionTrue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			machine := &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-machine", Namespace: metav1.NamespaceDefault},
+			}
+			if tt.condition != nil {
+				conditions.Set(machine, tt.condition)
+			}
+
+			g.Expect(workloadClusterUnreachableFor(machine, tt.probeErr, timeout)).To(Equal(tt.expected))
+			g.Expect(conditions.Get(machine, clusterv1.WorkloadClusterReachableCondition).Status).To(Equal(tt.expectedCondStatus))
+		})
+	}
+}
+
 func TestIsNodeDrainedAllowed(t *testing.T) {
 	testCluster := &clusterv1.Cluster{
 		TypeMeta:   metav1.TypeMeta{Kind: "Cluster", APIVersion: clusterv1.GroupVersion.String()},
@@ -1171,13 +1258,7 @@ func TestIsNodeDrainedAllowed(t *testing.T) {
 				},
 
 				Status: clusterv1.MachineStatus{
-					Conditions: clusterv1.Conditions{
-						{
-							Type:               clusterv1.DrainingSucceededCondition,
-							Status:             corev1.ConditionFalse,
-							LastTransitionTime: metav1.Time{Time: time.Now().Add(-(time.Second * 70)).UTC()},
-						},
-					},
+					NodeDrainStartTime: &metav1.Time{Time: time.Now().Add(-(time.Second * 70)).UTC()},
 				},
 			},
 			expected: false,
@@ -1197,19 +1278,13 @@ func TestIsNodeDrainedAllowed(t *testing.T) {
 					NodeDrainTimeout:  &metav1.Duration{Duration: time.Second * 60},
 				},
 				Status: clusterv1.MachineStatus{
-					Conditions: clusterv1.Conditions{
-						{
-							Type:               clusterv1.DrainingSucceededCondition,
-							Status:             corev1.ConditionFalse,
-							LastTransitionTime: metav1.Time{Time: time.Now().Add(-(time.Second * 30)).UTC()},
-						},
-					},
+					NodeDrainStartTime: &metav1.Time{Time: time.Now().Add(-(time.Second * 30)).UTC()},
 				},
 			},
 			expected: true,
 		},
 		{
-			name: "NodeDrainTimeout option is set to its default value 0",
+			name: "Node draining timeout is over even if the DrainingSucceeded condition changed afterwards",
 			machine: &clusterv1.Machine{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:       "test-machine",
@@ -1220,17 +1295,38 @@ func TestIsNodeDrainedAllowed(t *testing.T) {
 					ClusterName:       "test-cluster",
 					InfrastructureRef: corev1.ObjectReference{},
 					Bootstrap:         clusterv1.Bootstrap{DataSecretName: pointer.String("data")},
+					NodeDrainTimeout:  &metav1.Duration{Duration: time.Second * 60},
 				},
 				Status: clusterv1.MachineStatus{
+					NodeDrainStartTime: &metav1.Time{Time: time.Now().Add(-(time.Second * 70)).UTC()},
 					Conditions: clusterv1.Conditions{
 						{
 							Type:               clusterv1.DrainingSucceededCondition,
 							Status:             corev1.ConditionFalse,
-							LastTransitionTime: metav1.Time{Time: time.Now().Add(-(time.Second * 1000)).UTC()},
+							LastTransitionTime: metav1.Time{Time: time.Now().Add(-(time.Second * 5)).UTC()},
 						},
 					},
 				},
 			},
+			expected: false,
+		},
+		{
+			name: "NodeDrainTimeout option is set to its default value 0",
+			machine: &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test-machine",
+					Namespace:  metav1.NamespaceDefault,
+					Finalizers: []string{clusterv1.MachineFinalizer},
+				},
+				Spec: clusterv1.MachineSpec{
+					ClusterName:       "test-cluster",
+					InfrastructureRef: corev1.ObjectReference{},
+					Bootstrap:         clusterv1.Bootstrap{DataSecretName: pointer.String("data")},
+				},
+				Status: clusterv1.MachineStatus{
+					NodeDrainStartTime: &metav1.Time{Time: time.Now().Add(-(time.Second * 1000)).UTC()},
+				},
+			},
 			expected: true,
 		},
 	}
@@ -1253,6 +1349,72 @@ func TestIsNodeDrainedAllowed(t *testing.T) {
 	}
 }
 
+func TestNodeDrainEvictionDeadline(t *testing.T) {
+	firstTimeDrain := time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+	drainStartTime := &metav1.Time{Time: firstTimeDrain}
+
+	tests := []struct {
+		name             string
+		timeout          *metav1.Duration
+		drainStartTime   *metav1.Time
+		conditions       clusterv1.Conditions
+		expectedDeadline time.Time
+		expectedOK       bool
+	}{
+		{
+			name:           "No deadline if the eviction timeout is not set",
+			drainStartTime: drainStartTime,
+			expectedOK:     false,
+		},
+		{
+			name:           "No deadline if the eviction timeout is zero",
+			timeout:        &metav1.Duration{},
+			drainStartTime: drainStartTime,
+			expectedOK:     false,
+		},
+		{
+			name:       "No deadline if the node has not been drained yet",
+			timeout:    &metav1.Duration{Duration: 5 * time.Minute},
+			expectedOK: false,
+		},
+		{
+			name:             "Deadline is the eviction timeout after the first drain",
+			timeout:          &metav1.Duration{Duration: 5 * time.Minute},
+			drainStartTime:   drainStartTime,
+			expectedDeadline: firstTimeDrain.Add(5 * time.Minute),
+			expectedOK:       true,
+		},
+		{
+			name:           "Deadline is not moved by later changes of the DrainingSucceeded condition",
+			timeout:        &metav1.Duration{Duration: 5 * time.Minute},
+			drainStartTime: drainStartTime,
+			conditions: clusterv1.Conditions{
+				{
+					Type:               clusterv1.DrainingSucceededCondition,
+					Status:             corev1.ConditionFalse,
+					LastTransitionTime: metav1.Now(),
+				},
+			},
+			expectedDeadline: firstTimeDrain.Add(5 * time.Minute),
+			expectedOK:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			machine := &clusterv1.Machine{
+				Spec:   clusterv1.MachineSpec{NodeDrainEvictionTimeout: tt.timeout},
+				Status: clusterv1.MachineStatus{NodeDrainStartTime: tt.drainStartTime, Conditions: tt.conditions},
+			}
+
+			deadline, ok := nodeDrainEvictionDeadline(machine)
+			g.Expect(ok).To(Equal(tt.expectedOK))
+			g.Expect(deadline).To(BeTemporally("==", tt.expectedDeadline))
+		})
+	}
+}
+
 func TestIsNodeVolumeDetachingAllowed(t *testing.T) {
 	testCluster := &clusterv1.Cluster{
 		TypeMeta:   metav1.TypeMeta{Kind: "Cluster", APIVersion: clusterv1.GroupVersion.String()},
@@ -1380,6 +1542,124 @@ func TestIsNodeVolumeDetachingAllowed(t *testing.T) {
 	}
 }
 
+func TestGetDrainBlockingPods(t *testing.T) {
+	g := NewWithT(t)
+
+	nextEvictionTime := time.Now().Add(20 * time.Second)
+	deletionTime := metav1.NewTime(time.Now().Add(30 * time.Second))
+
+	kubeClient := fakeclientset.NewSimpleClientset(
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "pod-with-pdb", Labels: map[string]string{"app": "foo"}},
+			Spec:       corev1.PodSpec{NodeName: "node-1"},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "pod-without-pdb", Labels: map[string]string{"app": "bar"}},
+			Spec:       corev1.PodSpec{NodeName: "node-1"},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns2", Name: "terminating-pod", DeletionTimestamp: &deletionTime, Finalizers: []string{"test"}},
+			Spec:       corev1.PodSpec{NodeName: "node-1"},
+		},
+		&policyv1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "pdb-foo"},
+			Spec: policyv1.PodDisruptionBudgetSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}},
+			},
+			Status: policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: 0},
+		},
+		&policyv1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "pdb-bar"},
+			Spec: policyv1.PodDisruptionBudgetSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "bar"}},
+			},
+			Status: policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: 1},
+		},
+	)
+	drainer := &kubedrain.Helper{
+		Client:              kubeClient,
+		Ctx:                 ctx,
+		Force:               true,
+		IgnoreAllDaemonSets: true,
+		DeleteEmptyDirData:  true,
+	}
+
+	blockingPods, err := getDrainBlockingPods(ctx, drainer, "node-1", nextEvictionTime)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(blockingPods).To(Equal([]drainBlockingPod{
+		{
+			Namespace:            "ns1",
+			Name:                 "pod-with-pdb",
+			PodDisruptionBudgets: []string{"pdb-foo"},
+			EarliestEvictionTime: nextEvictionTime,
+		},
+		{
+			Namespace:            "ns1",
+			Name:                 "pod-without-pdb",
+			EarliestEvictionTime: nextEvictionTime,
+		},
+		{
+			Namespace:            "ns2",
+			Name:                 "terminating-pod",
+			Terminating:          true,
+			EarliestEvictionTime: deletionTime.Time,
+		},
+	}))
+}
+
+func TestDrainBlockingPodsMessage(t *testing.T) {
+	evictionTime := time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		blockingPods []drainBlockingPod
+		want         string
+	}{
+		{
+			name: "Pods blocked by PodDisruptionBudgets, not yet evicted and terminating",
+			blockingPods: []drainBlockingPod{
+				{Namespace: "ns1", Name: "pod1", PodDisruptionBudgets: []string{"pdb1", "pdb2"}, EarliestEvictionTime: evictionTime},
+				{Namespace: "ns1", Name: "pod2", EarliestEvictionTime: evictionTime},
+				{Namespace: "ns2", Name: "pod3", Terminating: true, EarliestEvictionTime: evictionTime},
+			},
+			want: "Pod ns1/pod1 cannot be evicted because of PodDisruptionBudgets pdb1, pdb2, next eviction attempt at 2023-06-01T10:00:00Z; " +
+				"Pod ns1/pod2 has not been evicted yet, next eviction attempt at 2023-06-01T10:00:00Z; " +
+				"Pod ns2/pod3 is terminating, expected to be deleted at 2023-06-01T10:00:00Z",
+		},
+		{
+			name: "Pods waiting for completion",
+			blockingPods: []drainBlockingPod{
+				{Namespace: "ns1", Name: "job", WaitingForCompletion: true},
+			},
+			want: "Pod ns1/job is waiting for completion as defined by a MachineDrainRule",
+		},
+		{
+			name: "Too many Pods",
+			blockingPods: func() []drainBlockingPod {
+				pods := []drainBlockingPod{}
+				for i := 0; i < maxDrainBlockingPodsInMessage+2; i++ {
+					pods = append(pods, drainBlockingPod{Namespace: "ns", Name: fmt.Sprintf("pod%d", i), EarliestEvictionTime: evictionTime})
+				}
+				return pods
+			}(),
+			want: "Pod ns/pod0 has not been evicted yet, next eviction attempt at 2023-06-01T10:00:00Z; " +
+				"Pod ns/pod1 has not been evicted yet, next eviction attempt at 2023-06-01T10:00:00Z; " +
+				"Pod ns/pod2 has not been evicted yet, next eviction attempt at 2023-06-01T10:00:00Z; " +
+				"Pod ns/pod3 has not been evicted yet, next eviction attempt at 2023-06-01T10:00:00Z; " +
+				"Pod ns/pod4 has not been evicted yet, next eviction attempt at 2023-06-01T10:00:00Z; " +
+				"and 2 more Pods",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(drainBlockingPodsMessage(tt.blockingPods)).To(Equal(tt.want))
+		})
+	}
+}
+
 func TestIsDeleteNodeAllowed(t *testing.T) {
 	deletionts := metav1.Now()
 
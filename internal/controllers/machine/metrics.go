@@ -0,0 +1,71 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func init() {
+	// Register the metrics at the controller-runtime metrics registry.
+	ctrlmetrics.Registry.MustRegister(phaseDuration)
+	ctrlmetrics.Registry.MustRegister(deletionStepDuration)
+}
+
+// Metrics subsystem and the deletion steps reported by the Machine controller.
+const (
+	machineSubsystem = "capi_machine"
+
+	drainDeletionStep        = "drain"
+	volumeDetachDeletionStep = "volume_detach"
+)
+
+// durationBuckets are the buckets of the duration metrics, from 1 second up to about 4.5 hours.
+var durationBuckets = prometheus.ExponentialBuckets(1, 2, 15)
+
+var (
+	// phaseDuration reports the time spent by Machines in a phase before moving to the next one.
+	phaseDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Subsystem: machineSubsystem,
+		Name:      "phase_duration_seconds",
+		Help:      "Time spent by Machines in a phase before moving to the next one in seconds, broken down by phase, cluster and infrastructure provider kind.",
+		Buckets:   durationBuckets,
+	}, []string{"phase", "namespace", "cluster", "provider"})
+
+	// deletionStepDuration reports the time spent by Machines in a deletion step, e.g. drain.
+	deletionStepDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Subsystem: machineSubsystem,
+		Name:      "deletion_step_duration_seconds",
+		Help:      "Time spent by Machines in a deletion step in seconds, broken down by step, cluster and infrastructure provider kind.",
+		Buckets:   durationBuckets,
+	}, []string{"step", "namespace", "cluster", "provider"})
+)
+
+// observePhaseDuration observes the time spent by the Machine in a phase started at the given time.
+func observePhaseDuration(m *clusterv1.Machine, phase clusterv1.MachinePhase, start time.Time) {
+	phaseDuration.WithLabelValues(string(phase), m.Namespace, m.Spec.ClusterName, m.Spec.InfrastructureRef.Kind).Observe(time.Since(start).Seconds())
+}
+
+// observeDeletionStepDuration observes the time spent by the Machine in a deletion step started at the given time.
+func observeDeletionStepDuration(m *clusterv1.Machine, step string, start time.Time) {
+	deletionStepDuration.WithLabelValues(step, m.Namespace, m.Spec.ClusterName, m.Spec.InfrastructureRef.Kind).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestReconcilePhaseObservesPhaseDuration(t *testing.T) {
+	g := NewWithT(t)
+
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "phase-duration-test",
+			Name:      "machine",
+		},
+		Spec: clusterv1.MachineSpec{
+			ClusterName:       "cluster",
+			InfrastructureRef: corev1.ObjectReference{Kind: "GenericInfrastructureMachine"},
+		},
+		Status: clusterv1.MachineStatus{
+			Phase:          string(clusterv1.MachinePhasePending),
+			LastUpdated:    &metav1.Time{Time: time.Now().Add(-time.Minute)},
+			BootstrapReady: true,
+		},
+	}
+	histogram := func(phase clusterv1.MachinePhase) *dto.Histogram {
+		metric := &dto.Metric{}
+		g.Expect(phaseDuration.WithLabelValues(string(phase), "phase-duration-test", "cluster", "GenericInfrastructureMachine").(prometheus.Metric).Write(metric)).To(Succeed())
+		return metric.GetHistogram()
+	}
+
+	r := &Reconciler{}
+	r.reconcilePhase(ctx, machine)
+	g.Expect(machine.Status.GetTypedPhase()).To(Equal(clusterv1.MachinePhaseProvisioning))
+	g.Expect(histogram(clusterv1.MachinePhasePending).GetSampleCount()).To(Equal(uint64(1)))
+	g.Expect(histogram(clusterv1.MachinePhasePending).GetSampleSum()).To(BeNumerically(">=", time.Minute.Seconds()))
+
+	// The duration is not observed again if the phase doesn't change.
+	r.reconcilePhase(ctx, machine)
+	g.Expect(histogram(clusterv1.MachinePhasePending).GetSampleCount()).To(Equal(uint64(1)))
+	g.Expect(histogram(clusterv1.MachinePhaseProvisioning).GetSampleCount()).To(Equal(uint64(0)))
+}
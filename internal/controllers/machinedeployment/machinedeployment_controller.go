@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -37,13 +38,22 @@ import (
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controllers/external"
+	"sigs.k8s.io/cluster-api/internal/controllers/machinedeployment/mdutil"
+	runtimeclient "sigs.k8s.io/cluster-api/internal/runtime/client"
+	"sigs.k8s.io/cluster-api/internal/util/maintenance"
+	"sigs.k8s.io/cluster-api/internal/util/rollout"
 	"sigs.k8s.io/cluster-api/internal/util/ssa"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/concurrency"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
+	"sigs.k8s.io/cluster-api/util/events"
+	clog "sigs.k8s.io/cluster-api/util/log"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
+	"sigs.k8s.io/cluster-api/util/priority"
+	"sigs.k8s.io/cluster-api/util/tracing"
 )
 
 var (
@@ -67,9 +77,20 @@ type Reconciler struct {
 	UnstructuredCachingClient client.Client
 	APIReader                 client.Reader
 
+	// RuntimeClient is used to call the AfterCanaryReady hook, if the RuntimeSDK feature flag is enabled.
+	RuntimeClient runtimeclient.Client
+
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
 
+	// ReconcilePriorityWeights are the weights of the priority queue serving the requests for the objects of
+	// Clusters by their reconcile priority. If not set, requests are served in the order they are received.
+	ReconcilePriorityWeights priority.Weights
+
+	// NamespaceConcurrency is the maximum number of objects of the same namespace reconciled at the same time.
+	// If zero, it is not limited.
+	NamespaceConcurrency int
+
 	recorder record.EventRecorder
 	ssaCache ssa.Cache
 }
@@ -80,6 +101,11 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opt
 		return err
 	}
 
+	reconciler, err := priority.NewReconciler(mgr, concurrency.NewNamespaceLimitedReconciler(tracing.NewReconciler(r, "MachineDeployment"), r.NamespaceConcurrency), &clusterv1.MachineDeployment{}, r.ReconcilePriorityWeights, options.MaxConcurrentReconciles)
+	if err != nil {
+		return errors.Wrap(err, "failed setting up with a controller manager")
+	}
+
 	err = ctrl.NewControllerManagedBy(mgr).
 		For(&clusterv1.MachineDeployment{}).
 		Owns(&clusterv1.MachineSet{}).
@@ -99,7 +125,7 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opt
 					predicates.ResourceHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue),
 				),
 			),
-		).Complete(r)
+		).Complete(reconciler)
 	if err != nil {
 		return errors.Wrap(err, "failed setting up with a controller manager")
 	}
@@ -118,6 +144,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 		if apierrors.IsNotFound(err) {
 			// Object not found, return.  Created objects are automatically garbage collected.
 			// For additional cleanup logic use finalizers.
+			rollout.DeleteMetrics("MachineDeployment", req.NamespacedName)
 			return ctrl.Result{}, nil
 		}
 		// Error reading the object - requeue the request.
@@ -126,6 +153,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 
 	log = log.WithValues("Cluster", klog.KRef(deployment.Namespace, deployment.Spec.ClusterName))
 	ctx = ctrl.LoggerInto(ctx, log)
+	ctx, log = clog.AddOperationID(ctx, deployment)
 
 	cluster, err := util.GetClusterByName(ctx, r.Client, deployment.Namespace, deployment.Spec.ClusterName)
 	if err != nil {
@@ -162,12 +190,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 		return ctrl.Result{}, nil
 	}
 
-	err = r.reconcile(ctx, cluster, deployment)
+	result, err := r.reconcile(ctx, cluster, deployment)
 	if err != nil {
 		log.Error(err, "Failed to reconcile MachineDeployment")
-		r.recorder.Eventf(deployment, corev1.EventTypeWarning, "ReconcileError", "%v", err)
+		events.Warningf(r.recorder, deployment, events.ReconcileErrorReason, "%v", err)
 	}
-	return ctrl.Result{}, err
+	return result, err
 }
 
 func patchMachineDeployment(ctx context.Context, patchHelper *patch.Helper, md *clusterv1.MachineDeployment, options ...patch.Option) error {
@@ -179,16 +207,19 @@ func patchMachineDeployment(ctx context.Context, patchHelper *patch.Helper, md *
 	)
 
 	// Patch the object, ignoring conflicts on the conditions owned by this controller.
+	// NOTE: Conditions are applied with server-side apply, so conflicts with other controllers setting conditions
+	// on the MachineDeployment are resolved by the API server without retrying the reconcile.
 	options = append(options,
 		patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
 			clusterv1.ReadyCondition,
 			clusterv1.MachineDeploymentAvailableCondition,
 		}},
+		patch.WithServerSideApply{FieldManager: machineDeploymentManagerName},
 	)
 	return patchHelper.Patch(ctx, md, options...)
 }
 
-func (r *Reconciler) reconcile(ctx context.Context, cluster *clusterv1.Cluster, md *clusterv1.MachineDeployment) error {
+func (r *Reconciler) reconcile(ctx context.Context, cluster *clusterv1.Cluster, md *clusterv1.MachineDeployment) (ctrl.Result, error) {
 	log := ctrl.LoggerFrom(ctx)
 	log.V(4).Info("Reconcile MachineDeployment")
 
@@ -215,18 +246,18 @@ func (r *Reconciler) reconcile(ctx context.Context, cluster *clusterv1.Cluster,
 
 	// Make sure to reconcile the external infrastructure reference.
 	if err := reconcileExternalTemplateReference(ctx, r.UnstructuredCachingClient, cluster, &md.Spec.Template.Spec.InfrastructureRef); err != nil {
-		return err
+		return ctrl.Result{}, err
 	}
 	// Make sure to reconcile the external bootstrap reference, if any.
 	if md.Spec.Template.Spec.Bootstrap.ConfigRef != nil {
 		if err := reconcileExternalTemplateReference(ctx, r.UnstructuredCachingClient, cluster, md.Spec.Template.Spec.Bootstrap.ConfigRef); err != nil {
-			return err
+			return ctrl.Result{}, err
 		}
 	}
 
 	msList, err := r.getMachineSetsForDeployment(ctx, md)
 	if err != nil {
-		return err
+		return ctrl.Result{}, err
 	}
 
 	// If not already present, add a label specifying the MachineDeployment name to MachineSets.
@@ -242,11 +273,11 @@ func (r *Reconciler) reconcile(ctx context.Context, cluster *clusterv1.Cluster,
 
 		helper, err := patch.NewHelper(machineSet, r.Client)
 		if err != nil {
-			return errors.Wrapf(err, "failed to apply %s label to MachineSet %q", clusterv1.MachineDeploymentNameLabel, machineSet.Name)
+			return ctrl.Result{}, errors.Wrapf(err, "failed to apply %s label to MachineSet %q", clusterv1.MachineDeploymentNameLabel, machineSet.Name)
 		}
 		machineSet.Labels[clusterv1.MachineDeploymentNameLabel] = md.Name
 		if err := helper.Patch(ctx, machineSet); err != nil {
-			return errors.Wrapf(err, "failed to apply %s label to MachineSet %q", clusterv1.MachineDeploymentNameLabel, machineSet.Name)
+			return ctrl.Result{}, errors.Wrapf(err, "failed to apply %s label to MachineSet %q", clusterv1.MachineDeploymentNameLabel, machineSet.Name)
 		}
 	}
 
@@ -259,30 +290,56 @@ func (r *Reconciler) reconcile(ctx context.Context, cluster *clusterv1.Cluster,
 	for idx := range msList {
 		machineSet := msList[idx]
 		if err := ssa.CleanUpManagedFieldsForSSAAdoption(ctx, r.Client, machineSet, machineDeploymentManagerName); err != nil {
-			return errors.Wrapf(err, "failed to clean up managedFields of MachineSet %s", klog.KObj(machineSet))
+			return ctrl.Result{}, errors.Wrapf(err, "failed to clean up managedFields of MachineSet %s", klog.KObj(machineSet))
 		}
 	}
 
 	if md.Spec.Paused {
-		return r.sync(ctx, md, msList)
+		events.Normal(r.recorder, md, events.RolloutPausedReason, "Rollout is paused, only scaling the MachineSets")
+		return ctrl.Result{}, r.sync(ctx, md, msList)
+	}
+
+	// Defer rollouts until the next maintenance window of the Cluster opens, if any; in the meantime the MachineSets
+	// are only scaled, like for paused MachineDeployments.
+	// NOTE: Rollouts already in progress, i.e. with a MachineSet matching the MachineDeployment, are completed.
+	if len(msList) > 0 && mdutil.FindNewMachineSet(md, msList, &metav1.Time{Time: time.Now()}) == nil {
+		allowed, requeueAfter, err := maintenance.IsAllowed(cluster, time.Now())
+		if err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "failed to check the maintenance windows of the Cluster")
+		}
+		if !allowed {
+			log.Info("Waiting for the next maintenance window of the Cluster to roll out the MachineDeployment", "requeueAfter", requeueAfter)
+			if err := r.sync(ctx, md, msList); err != nil {
+				return ctrl.Result{}, err
+			}
+			conditions.MarkFalse(md, clusterv1.RolloutInProgressCondition, clusterv1.WaitingForMaintenanceWindowReason, clusterv1.ConditionSeverityInfo, "Waiting for the next maintenance window of the Cluster")
+			return ctrl.Result{RequeueAfter: requeueAfter}, nil
+		}
 	}
 
 	if md.Spec.Strategy == nil {
-		return errors.Errorf("missing MachineDeployment strategy")
+		return ctrl.Result{}, errors.Errorf("missing MachineDeployment strategy")
 	}
 
 	if md.Spec.Strategy.Type == clusterv1.RollingUpdateMachineDeploymentStrategyType {
 		if md.Spec.Strategy.RollingUpdate == nil {
-			return errors.Errorf("missing MachineDeployment settings for strategy type: %s", md.Spec.Strategy.Type)
+			return ctrl.Result{}, errors.Errorf("missing MachineDeployment settings for strategy type: %s", md.Spec.Strategy.Type)
 		}
-		return r.rolloutRolling(ctx, md, msList)
+		return ctrl.Result{}, r.rolloutRolling(ctx, md, msList)
 	}
 
 	if md.Spec.Strategy.Type == clusterv1.OnDeleteMachineDeploymentStrategyType {
-		return r.rolloutOnDelete(ctx, md, msList)
+		return ctrl.Result{}, r.rolloutOnDelete(ctx, md, msList)
+	}
+
+	if md.Spec.Strategy.Type == clusterv1.CanaryMachineDeploymentStrategyType {
+		if md.Spec.Strategy.RollingUpdate == nil || md.Spec.Strategy.Canary == nil {
+			return ctrl.Result{}, errors.Errorf("missing MachineDeployment settings for strategy type: %s", md.Spec.Strategy.Type)
+		}
+		return r.rolloutCanary(ctx, cluster, md, msList)
 	}
 
-	return errors.Errorf("unexpected deployment strategy type: %s", md.Spec.Strategy.Type)
+	return ctrl.Result{}, errors.Errorf("unexpected deployment strategy type: %s", md.Spec.Strategy.Type)
 }
 
 // getMachineSetsForDeployment returns a list of MachineSets associated with a MachineDeployment.
@@ -321,11 +378,11 @@ func (r *Reconciler) getMachineSetsForDeployment(ctx context.Context, md *cluste
 		if metav1.GetControllerOf(ms) == nil {
 			if err := r.adoptOrphan(ctx, md, ms); err != nil {
 				log.Error(err, "Failed to adopt MachineSet into MachineDeployment")
-				r.recorder.Eventf(md, corev1.EventTypeWarning, "FailedAdopt", "Failed to adopt MachineSet %q: %v", ms.Name, err)
+				events.Warningf(r.recorder, md, events.FailedAdoptReason, "Failed to adopt MachineSet %q: %v", ms.Name, err)
 				continue
 			}
 			log.Info("Adopted MachineSet into MachineDeployment")
-			r.recorder.Eventf(md, corev1.EventTypeNormal, "SuccessfulAdopt", "Adopted MachineSet %q", ms.Name)
+			events.Normalf(r.recorder, md, events.SuccessfulAdoptReason, "Adopted MachineSet %q", ms.Name)
 		}
 
 		if !metav1.IsControlledBy(ms, md) {
@@ -0,0 +1,224 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinedeployment
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	runtimehooksv1 "sigs.k8s.io/cluster-api/exp/runtime/hooks/api/v1alpha1"
+	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/internal/controllers/machinedeployment/mdutil"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/events"
+	"sigs.k8s.io/cluster-api/util/patch"
+)
+
+// rolloutCanary implements the logic for the Canary MachineDeploymentStrategyType.
+func (r *Reconciler) rolloutCanary(ctx context.Context, cluster *clusterv1.Cluster, md *clusterv1.MachineDeployment, msList []*clusterv1.MachineSet) (ctrl.Result, error) {
+	// Proceed with a rolling update once the canary Machines have been approved, or if there are no old Machines
+	// to be kept while running the canary phase, e.g. when the MachineDeployment has just been created.
+	reconciliationTime := metav1.Now()
+	currentNewMS := mdutil.FindNewMachineSet(md, msList, &reconciliationTime)
+	currentOldMSs := mdutil.FindOldMachineSets(md, msList, &reconciliationTime)
+	rolledBack := currentNewMS != nil && currentNewMS.Annotations[clusterv1.CanaryAnnotation] == clusterv1.CanaryRolledBack
+	if !rolledBack && (mdutil.GetReplicaCountForMachineSets(currentOldMSs) == 0 ||
+		(currentNewMS != nil && currentNewMS.Annotations[clusterv1.CanaryAnnotation] == clusterv1.CanaryApproved)) {
+		return ctrl.Result{}, r.rolloutRolling(ctx, md, msList)
+	}
+
+	newMS, oldMSs, err := r.getAllMachineSetsAndSyncRevision(ctx, md, msList, true)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// newMS can be nil in case there is already a MachineSet associated with this deployment,
+	// but there are only either changes in annotations or MinReadySeconds. Or in other words,
+	// this can be nil if there are changes, but no replacement of existing machines is needed.
+	if newMS == nil {
+		return ctrl.Result{}, nil
+	}
+
+	allMSs := append(oldMSs, newMS)
+
+	// Scale down the canary Machines and keep the MachineDeployment on the old MachineSets if they have been rolled back.
+	if rolledBack {
+		if err := r.reconcileCanaryRollback(ctx, md, newMS, oldMSs); err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := r.syncDeploymentStatus(allMSs, newMS, md); err != nil {
+			return ctrl.Result{}, err
+		}
+		setCanaryRollbackCondition(md, newMS)
+		return ctrl.Result{}, nil
+	}
+
+	// Bring up the canary Machines, if we can.
+	result, err := r.reconcileCanaryMachineSet(ctx, cluster, newMS, md)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.syncDeploymentStatus(allMSs, newMS, md); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return result, nil
+}
+
+// reconcileCanaryMachineSet scales the new MachineSet to the number of canary Machines while old MachineSets are kept
+// at their current size; once the canary Machines are available it calls the AfterCanaryReady hook and records its
+// outcome on the new MachineSet.
+func (r *Reconciler) reconcileCanaryMachineSet(ctx context.Context, cluster *clusterv1.Cluster, newMS *clusterv1.MachineSet, md *clusterv1.MachineDeployment) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	canaryReplicas := canaryReplicas(md)
+	if err := r.scaleMachineSet(ctx, newMS, canaryReplicas, md); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Wait for the canary Machines to be available; changes to the new MachineSet will trigger a new reconcile.
+	if newMS.Status.AvailableReplicas < canaryReplicas {
+		return ctrl.Result{}, nil
+	}
+
+	hookResponse, err := r.callAfterCanaryReadyHook(ctx, cluster, md, newMS)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if hookResponse.RetryAfterSeconds != 0 {
+		log.Info("Waiting for the analysis of the canary Machines to complete", "MachineSet", klog.KObj(newMS))
+		return ctrl.Result{RequeueAfter: time.Duration(hookResponse.RetryAfterSeconds) * time.Second}, nil
+	}
+
+	// Record the outcome of the canary phase on the new MachineSet; the rollout continues accordingly
+	// in the next reconcile, which is triggered by the change to the new MachineSet.
+	outcome := clusterv1.CanaryApproved
+	if hookResponse.Rollback {
+		outcome = clusterv1.CanaryRolledBack
+	}
+	patchHelper, err := patch.NewHelper(newMS, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	annotations.AddAnnotations(newMS, map[string]string{clusterv1.CanaryAnnotation: outcome})
+	if err := patchHelper.Patch(ctx, newMS); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to set %s annotation on MachineSet %s", clusterv1.CanaryAnnotation, klog.KObj(newMS))
+	}
+
+	if hookResponse.Rollback {
+		log.Info("Rolling back canary Machines", "MachineSet", klog.KObj(newMS), "reason", hookResponse.Message)
+		events.Warningf(r.recorder, md, events.CanaryRolledBackReason, "Rolled back canary Machines of MachineSet %v: %s",
+			client.ObjectKeyFromObject(newMS), hookResponse.Message)
+		return ctrl.Result{}, nil
+	}
+
+	log.Info("Canary Machines approved, proceeding with rolling update", "MachineSet", klog.KObj(newMS))
+	events.Normalf(r.recorder, md, events.CanaryApprovedReason, "Approved canary Machines of MachineSet %v",
+		client.ObjectKeyFromObject(newMS))
+	return ctrl.Result{}, nil
+}
+
+// reconcileCanaryRollback scales the new MachineSet of rolled back canary Machines down to zero, while the old
+// MachineSets are kept at the desired number of replicas of the MachineDeployment, e.g. if the MachineDeployment
+// is scaled after the rollback.
+// NOTE: The MachineDeployment stays on the old MachineSets until the machine template is changed again.
+func (r *Reconciler) reconcileCanaryRollback(ctx context.Context, md *clusterv1.MachineDeployment, newMS *clusterv1.MachineSet, oldMSs []*clusterv1.MachineSet) error {
+	if err := r.scaleMachineSet(ctx, newMS, 0, md); err != nil {
+		return err
+	}
+
+	if len(oldMSs) == 0 {
+		return nil
+	}
+	diff := *md.Spec.Replicas - mdutil.GetReplicaCountForMachineSets(oldMSs)
+	if diff == 0 {
+		return nil
+	}
+
+	// Absorb the difference in the newest active old MachineSet, or in the newest old MachineSet if there are none;
+	// scaling down across multiple active old MachineSets completes over subsequent reconciles.
+	sortedOldMSs := append([]*clusterv1.MachineSet{}, oldMSs...)
+	sort.Sort(sort.Reverse(mdutil.MachineSetsByCreationTimestamp(sortedOldMSs)))
+	oldMS := sortedOldMSs[0]
+	if activeOldMSs := mdutil.FilterActiveMachineSets(sortedOldMSs); len(activeOldMSs) > 0 {
+		oldMS = activeOldMSs[0]
+	}
+	newScale := pointer.Int32Deref(oldMS.Spec.Replicas, 0) + diff
+	if newScale < 0 {
+		newScale = 0
+	}
+	return r.scaleMachineSet(ctx, oldMS, newScale, md)
+}
+
+// setCanaryRollbackCondition reports the rollback of the canary Machines in the RolloutInProgress condition of the
+// MachineDeployment; the rollback is completed once all the canary Machines are gone.
+func setCanaryRollbackCondition(md *clusterv1.MachineDeployment, newMS *clusterv1.MachineSet) {
+	if newMS.Status.Replicas > 0 {
+		conditions.Set(md, &clusterv1.Condition{
+			Type:    clusterv1.RolloutInProgressCondition,
+			Status:  corev1.ConditionTrue,
+			Reason:  clusterv1.CanaryRollingBackReason,
+			Message: fmt.Sprintf("Scaling down %d canary Machines of MachineSet %s", newMS.Status.Replicas, newMS.Name),
+		})
+		return
+	}
+	conditions.MarkFalse(md, clusterv1.RolloutInProgressCondition, clusterv1.CanaryRolledBackReason, clusterv1.ConditionSeverityWarning,
+		"Canary Machines of MachineSet %s have been rolled back; the rollout starts over with the next change to the machine template", newMS.Name)
+}
+
+// callAfterCanaryReadyHook calls the AfterCanaryReady hook, so Runtime Extensions can analyse the canary Machines
+// and decide whether the rollout proceeds or is rolled back.
+// Note: The rollout proceeds without calling the hook if the RuntimeSDK feature flag is disabled.
+func (r *Reconciler) callAfterCanaryReadyHook(ctx context.Context, cluster *clusterv1.Cluster, md *clusterv1.MachineDeployment, ms *clusterv1.MachineSet) (*runtimehooksv1.AfterCanaryReadyResponse, error) {
+	hookResponse := &runtimehooksv1.AfterCanaryReadyResponse{}
+	if !feature.Gates.Enabled(feature.RuntimeSDK) || r.RuntimeClient == nil {
+		return hookResponse, nil
+	}
+
+	hookRequest := &runtimehooksv1.AfterCanaryReadyRequest{
+		Cluster:           *cluster,
+		MachineDeployment: *md,
+		MachineSet:        *ms,
+	}
+	if err := r.RuntimeClient.CallAllExtensions(ctx, runtimehooksv1.AfterCanaryReady, md, hookRequest, hookResponse); err != nil {
+		return nil, err
+	}
+	return hookResponse, nil
+}
+
+// canaryReplicas returns the number of canary Machines of a MachineDeployment using the Canary strategy,
+// capped to the desired number of Machines.
+func canaryReplicas(md *clusterv1.MachineDeployment) int32 {
+	replicas := pointer.Int32Deref(md.Spec.Strategy.Canary.Replicas, 1)
+	if md.Spec.Replicas != nil && *md.Spec.Replicas < replicas {
+		replicas = *md.Spec.Replicas
+	}
+	return replicas
+}
@@ -0,0 +1,288 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinedeployment
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	utilfeature "k8s.io/component-base/featuregate/testing"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	runtimecatalog "sigs.k8s.io/cluster-api/exp/runtime/catalog"
+	runtimehooksv1 "sigs.k8s.io/cluster-api/exp/runtime/hooks/api/v1alpha1"
+	"sigs.k8s.io/cluster-api/feature"
+	fakeruntimeclient "sigs.k8s.io/cluster-api/internal/runtime/client/fake"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestReconcileCanaryMachineSet(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.RuntimeSDK, true)()
+
+	catalog := runtimecatalog.New()
+	_ = runtimehooksv1.AddToCatalog(catalog)
+	afterCanaryReadyGVH, err := catalog.GroupVersionHook(runtimehooksv1.AfterCanaryReady)
+	if err != nil {
+		panic("unable to compute GVH")
+	}
+
+	cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "cluster"}}
+	md := &clusterv1.MachineDeployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "bar"},
+		Spec: clusterv1.MachineDeploymentSpec{
+			Replicas: pointer.Int32(3),
+			Strategy: &clusterv1.MachineDeploymentStrategy{
+				Type: clusterv1.CanaryMachineDeploymentStrategyType,
+				RollingUpdate: &clusterv1.MachineRollingUpdateDeployment{
+					MaxUnavailable: intOrStrPtr(0),
+					MaxSurge:       intOrStrPtr(1),
+				},
+				Canary: &clusterv1.MachineCanaryDeployment{
+					Replicas: pointer.Int32(2),
+				},
+			},
+		},
+	}
+	machineSet := func(replicas, availableReplicas int32, outcome string) *clusterv1.MachineSet {
+		ms := &clusterv1.MachineSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "new-ms"},
+			Spec: clusterv1.MachineSetSpec{
+				Replicas: pointer.Int32(replicas),
+			},
+			Status: clusterv1.MachineSetStatus{
+				AvailableReplicas: availableReplicas,
+			},
+		}
+		if outcome != "" {
+			ms.Annotations = map[string]string{clusterv1.CanaryAnnotation: outcome}
+		}
+		return ms
+	}
+	hookResponse := func(retryAfterSeconds int32, rollback bool) *runtimehooksv1.AfterCanaryReadyResponse {
+		return &runtimehooksv1.AfterCanaryReadyResponse{
+			CommonRetryResponse: runtimehooksv1.CommonRetryResponse{
+				CommonResponse:    runtimehooksv1.CommonResponse{Status: runtimehooksv1.ResponseStatusSuccess},
+				RetryAfterSeconds: retryAfterSeconds,
+			},
+			Rollback: rollback,
+		}
+	}
+
+	tests := []struct {
+		name         string
+		newMS        *clusterv1.MachineSet
+		hookResponse *runtimehooksv1.AfterCanaryReadyResponse
+		wantReplicas int32
+		wantOutcome  string
+		wantResult   ctrl.Result
+		wantCalls    int
+	}{
+		{
+			name:         "should scale up the new MachineSet to the number of canary Machines",
+			newMS:        machineSet(0, 0, ""),
+			hookResponse: hookResponse(0, false),
+			wantReplicas: 2,
+			wantCalls:    0,
+		},
+		{
+			name:         "should wait for the canary Machines to be available",
+			newMS:        machineSet(2, 1, ""),
+			hookResponse: hookResponse(0, false),
+			wantReplicas: 2,
+			wantCalls:    0,
+		},
+		{
+			name:         "should wait for the analysis of the canary Machines to complete",
+			newMS:        machineSet(2, 2, ""),
+			hookResponse: hookResponse(30, false),
+			wantReplicas: 2,
+			wantResult:   ctrl.Result{RequeueAfter: 30 * time.Second},
+			wantCalls:    1,
+		},
+		{
+			name:         "should approve the canary Machines",
+			newMS:        machineSet(2, 2, ""),
+			hookResponse: hookResponse(0, false),
+			wantReplicas: 2,
+			wantOutcome:  clusterv1.CanaryApproved,
+			wantCalls:    1,
+		},
+		{
+			name:         "should roll back the canary Machines",
+			newMS:        machineSet(2, 2, ""),
+			hookResponse: hookResponse(0, true),
+			wantReplicas: 2,
+			wantOutcome:  clusterv1.CanaryRolledBack,
+			wantCalls:    1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			runtimeClient := fakeruntimeclient.NewRuntimeClientBuilder().
+				WithCatalog(catalog).
+				WithCallAllExtensionResponses(map[runtimecatalog.GroupVersionHook]runtimehooksv1.ResponseObject{
+					afterCanaryReadyGVH: tt.hookResponse,
+				}).
+				Build()
+			r := &Reconciler{
+				Client:        fake.NewClientBuilder().WithObjects(md.DeepCopy(), tt.newMS).Build(),
+				RuntimeClient: runtimeClient,
+				recorder:      record.NewFakeRecorder(32),
+			}
+
+			result, err := r.reconcileCanaryMachineSet(ctx, cluster, tt.newMS, md)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(result).To(Equal(tt.wantResult))
+			g.Expect(runtimeClient.CallAllCount(runtimehooksv1.AfterCanaryReady)).To(Equal(tt.wantCalls))
+
+			freshNewMS := &clusterv1.MachineSet{}
+			g.Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(tt.newMS), freshNewMS)).To(Succeed())
+			g.Expect(*freshNewMS.Spec.Replicas).To(Equal(tt.wantReplicas))
+			g.Expect(freshNewMS.Annotations[clusterv1.CanaryAnnotation]).To(Equal(tt.wantOutcome))
+		})
+	}
+}
+
+func TestReconcileCanaryRollback(t *testing.T) {
+	md := &clusterv1.MachineDeployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "bar"},
+		Spec: clusterv1.MachineDeploymentSpec{
+			Replicas: pointer.Int32(3),
+			Strategy: &clusterv1.MachineDeploymentStrategy{
+				Type: clusterv1.CanaryMachineDeploymentStrategyType,
+				RollingUpdate: &clusterv1.MachineRollingUpdateDeployment{
+					MaxUnavailable: intOrStrPtr(0),
+					MaxSurge:       intOrStrPtr(1),
+				},
+				Canary: &clusterv1.MachineCanaryDeployment{
+					Replicas: pointer.Int32(2),
+				},
+			},
+		},
+	}
+	machineSet := func(name string, created time.Time, replicas, actualReplicas int32) *clusterv1.MachineSet {
+		return &clusterv1.MachineSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: name, CreationTimestamp: metav1.NewTime(created)},
+			Spec: clusterv1.MachineSetSpec{
+				Replicas: pointer.Int32(replicas),
+			},
+			Status: clusterv1.MachineSetStatus{
+				Replicas: actualReplicas,
+			},
+		}
+	}
+	now := time.Now()
+
+	tests := []struct {
+		name            string
+		mdReplicas      int32
+		newMS           *clusterv1.MachineSet
+		oldMSs          []*clusterv1.MachineSet
+		wantNewReplicas int32
+		wantOldReplicas []int32
+		wantReason      string
+		wantStatus      corev1.ConditionStatus
+	}{
+		{
+			name:            "should scale down the canary Machines",
+			mdReplicas:      3,
+			newMS:           machineSet("new-ms", now, 2, 2),
+			oldMSs:          []*clusterv1.MachineSet{machineSet("old-ms", now.Add(-time.Hour), 3, 3)},
+			wantNewReplicas: 0,
+			wantOldReplicas: []int32{3},
+			wantReason:      clusterv1.CanaryRollingBackReason,
+			wantStatus:      corev1.ConditionTrue,
+		},
+		{
+			name:            "should complete the rollback once the canary Machines are gone",
+			mdReplicas:      3,
+			newMS:           machineSet("new-ms", now, 0, 0),
+			oldMSs:          []*clusterv1.MachineSet{machineSet("old-ms", now.Add(-time.Hour), 3, 3)},
+			wantNewReplicas: 0,
+			wantOldReplicas: []int32{3},
+			wantReason:      clusterv1.CanaryRolledBackReason,
+			wantStatus:      corev1.ConditionFalse,
+		},
+		{
+			name:            "should scale up the old MachineSet if the MachineDeployment is scaled up after the rollback",
+			mdReplicas:      5,
+			newMS:           machineSet("new-ms", now, 0, 0),
+			oldMSs:          []*clusterv1.MachineSet{machineSet("old-ms", now.Add(-time.Hour), 3, 3)},
+			wantNewReplicas: 0,
+			wantOldReplicas: []int32{5},
+			wantReason:      clusterv1.CanaryRolledBackReason,
+			wantStatus:      corev1.ConditionFalse,
+		},
+		{
+			name:       "should scale down the newest active old MachineSet if the MachineDeployment is scaled down after the rollback",
+			mdReplicas: 2,
+			newMS:      machineSet("new-ms", now, 0, 0),
+			oldMSs: []*clusterv1.MachineSet{
+				machineSet("old-ms-1", now.Add(-2*time.Hour), 1, 1),
+				machineSet("old-ms-2", now.Add(-time.Hour), 2, 2),
+			},
+			wantNewReplicas: 0,
+			wantOldReplicas: []int32{1, 1},
+			wantReason:      clusterv1.CanaryRolledBackReason,
+			wantStatus:      corev1.ConditionFalse,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			md := md.DeepCopy()
+			md.Spec.Replicas = pointer.Int32(tt.mdReplicas)
+			objs := []client.Object{md, tt.newMS}
+			for _, ms := range tt.oldMSs {
+				objs = append(objs, ms)
+			}
+			r := &Reconciler{
+				Client:   fake.NewClientBuilder().WithObjects(objs...).Build(),
+				recorder: record.NewFakeRecorder(32),
+			}
+
+			g.Expect(r.reconcileCanaryRollback(ctx, md, tt.newMS, tt.oldMSs)).To(Succeed())
+
+			freshNewMS := &clusterv1.MachineSet{}
+			g.Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(tt.newMS), freshNewMS)).To(Succeed())
+			g.Expect(*freshNewMS.Spec.Replicas).To(Equal(tt.wantNewReplicas))
+			for i, ms := range tt.oldMSs {
+				freshOldMS := &clusterv1.MachineSet{}
+				g.Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(ms), freshOldMS)).To(Succeed())
+				g.Expect(*freshOldMS.Spec.Replicas).To(Equal(tt.wantOldReplicas[i]))
+			}
+
+			setCanaryRollbackCondition(md, tt.newMS)
+			g.Expect(conditions.Get(md, clusterv1.RolloutInProgressCondition)).ToNot(BeNil())
+			g.Expect(conditions.GetReason(md, clusterv1.RolloutInProgressCondition)).To(Equal(tt.wantReason))
+			g.Expect(conditions.Get(md, clusterv1.RolloutInProgressCondition).Status).To(Equal(tt.wantStatus))
+		})
+	}
+}
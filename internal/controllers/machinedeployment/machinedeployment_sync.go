@@ -24,7 +24,6 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
-	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -40,10 +39,13 @@ import (
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/internal/controllers/machinedeployment/mdutil"
+	"sigs.k8s.io/cluster-api/internal/topology/names"
 	"sigs.k8s.io/cluster-api/internal/util/hash"
+	"sigs.k8s.io/cluster-api/internal/util/rollout"
 	"sigs.k8s.io/cluster-api/internal/util/ssa"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/events"
 	"sigs.k8s.io/cluster-api/util/patch"
 )
 
@@ -158,7 +160,7 @@ func (r *Reconciler) updateMachineSet(ctx context.Context, deployment *clusterv1
 	// Update the MachineSet to propagate in-place mutable fields from the MachineDeployment.
 	err = ssa.Patch(ctx, r.Client, machineDeploymentManagerName, updatedMS, ssa.WithCachingProxy{Cache: r.ssaCache, Original: ms})
 	if err != nil {
-		r.recorder.Eventf(deployment, corev1.EventTypeWarning, "FailedUpdate", "Failed to update MachineSet %s: %v", klog.KObj(updatedMS), err)
+		events.Warningf(r.recorder, deployment, events.FailedUpdateReason, "Failed to update MachineSet %s: %v", klog.KObj(updatedMS), err)
 		return nil, errors.Wrapf(err, "failed to update MachineSet %s", klog.KObj(updatedMS))
 	}
 
@@ -179,11 +181,11 @@ func (r *Reconciler) createMachineSetAndWait(ctx context.Context, deployment *cl
 
 	// Create the MachineSet.
 	if err := ssa.Patch(ctx, r.Client, machineDeploymentManagerName, newMS); err != nil {
-		r.recorder.Eventf(deployment, corev1.EventTypeWarning, "FailedCreate", "Failed to create MachineSet %s: %v", klog.KObj(newMS), err)
+		events.Warningf(r.recorder, deployment, events.FailedCreateReason, "Failed to create MachineSet %s: %v", klog.KObj(newMS), err)
 		return nil, errors.Wrapf(err, "failed to create new MachineSet %s", klog.KObj(newMS))
 	}
 	log.V(4).Info("Created new MachineSet", "MachineSet", klog.KObj(newMS))
-	r.recorder.Eventf(deployment, corev1.EventTypeNormal, "SuccessfulCreate", "Created MachineSet %s", klog.KObj(newMS))
+	events.Normalf(r.recorder, deployment, events.SuccessfulCreateReason, "Created MachineSet %s", klog.KObj(newMS))
 
 	// Keep trying to get the MachineSet. This will force the cache to update and prevent any future reconciliation of
 	// the MachineDeployment to reconcile with an outdated list of MachineSets which could lead to unwanted creation of
@@ -242,7 +244,11 @@ func (r *Reconciler) computeDesiredMachineSet(deployment *clusterv1.MachineDeplo
 		// will end up updating the existing MachineSet instead of creating a new one.
 		uniqueIdentifierLabelValue = fmt.Sprintf("%d-%s", templateHash, apirand.String(5))
 
-		name = computeNewMachineSetName(deployment.Name+"-", apirand.SafeEncodeString(uniqueIdentifierLabelValue))
+		// Note: The unique identifier is used as the random suffix of the MachineSet name.
+		name, err = names.MachineSetNameGenerator(deployment.Spec.MachineSetNamingStrategy, deployment.Spec.ClusterName, deployment.Name, apirand.SafeEncodeString(uniqueIdentifierLabelValue)).GenerateName()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to compute desired MachineSet: failed to generate MachineSet name")
+		}
 
 		// Add foregroundDeletion finalizer to MachineSet if the MachineDeployment has it.
 		if sets.New[string](deployment.Finalizers...).Has(metav1.FinalizerDeleteDependents) {
@@ -343,6 +349,11 @@ func (r *Reconciler) computeDesiredMachineSet(deployment *clusterv1.MachineDeplo
 	desiredMS.Spec.Template.Spec.NodeDrainTimeout = deployment.Spec.Template.Spec.NodeDrainTimeout
 	desiredMS.Spec.Template.Spec.NodeDeletionTimeout = deployment.Spec.Template.Spec.NodeDeletionTimeout
 	desiredMS.Spec.Template.Spec.NodeVolumeDetachTimeout = deployment.Spec.Template.Spec.NodeVolumeDetachTimeout
+	desiredMS.Spec.Template.Spec.NodeStartupTimeout = deployment.Spec.Template.Spec.NodeStartupTimeout
+	desiredMS.Spec.Template.Spec.NodeReRegistrationPolicy = deployment.Spec.Template.Spec.NodeReRegistrationPolicy
+	desiredMS.Spec.Template.Spec.NodeDrainEvictionTimeout = deployment.Spec.Template.Spec.NodeDrainEvictionTimeout
+	desiredMS.Spec.FailureDomainSpreadPolicy = deployment.Spec.FailureDomainSpreadPolicy
+	desiredMS.Spec.MachineNamingStrategy = deployment.Spec.MachineNamingStrategy
 
 	return desiredMS, nil
 }
@@ -356,23 +367,6 @@ func cloneStringMap(in map[string]string) map[string]string {
 	return out
 }
 
-const (
-	maxNameLength          = 63
-	randomLength           = 5
-	maxGeneratedNameLength = maxNameLength - randomLength
-)
-
-// computeNewMachineSetName generates a new name for the MachineSet just like
-// the upstream SimpleNameGenerator.
-// Note: We had to extract the logic as we want to use the MachineSet name suffix as
-// unique identifier for the MachineSet.
-func computeNewMachineSetName(base, suffix string) string {
-	if len(base) > maxGeneratedNameLength {
-		base = base[:maxGeneratedNameLength]
-	}
-	return fmt.Sprintf("%s%s", base, suffix)
-}
-
 // scale scales proportionally in order to mitigate risk. Otherwise, scaling up can increase the size
 // of the new machine set and scaling down can decrease the sizes of the old ones, both of which would
 // have the effect of hastening the rollout progress, which could produce a higher proportion of unavailable
@@ -499,6 +493,12 @@ func (r *Reconciler) syncDeploymentStatus(allMSs []*clusterv1.MachineSet, newMS
 	} else {
 		conditions.MarkFalse(md, clusterv1.MachineDeploymentAvailableCondition, clusterv1.WaitingForAvailableMachinesReason, clusterv1.ConditionSeverityWarning, "Minimum availability requires %d replicas, current %d available", minReplicasNeeded, md.Status.AvailableReplicas)
 	}
+
+	rollout.SetCondition(md, "MachineDeployment", md.Spec.ClusterName, rollout.Progress{
+		Replicas:         md.Status.Replicas,
+		UpToDateReplicas: md.Status.UpdatedReplicas,
+		DesiredReplicas:  *md.Spec.Replicas,
+	})
 	return nil
 }
 
@@ -585,12 +585,12 @@ func (r *Reconciler) scaleMachineSet(ctx context.Context, ms *clusterv1.MachineS
 	mdutil.SetReplicasAnnotations(ms, *(deployment.Spec.Replicas), *(deployment.Spec.Replicas)+mdutil.MaxSurge(*deployment))
 
 	if err := patchHelper.Patch(ctx, ms); err != nil {
-		r.recorder.Eventf(deployment, corev1.EventTypeWarning, "FailedScale", "Failed to scale MachineSet %v: %v",
+		events.Warningf(r.recorder, deployment, events.FailedScaleReason, "Failed to scale MachineSet %v: %v",
 			client.ObjectKeyFromObject(ms), err)
 		return err
 	}
 
-	r.recorder.Eventf(deployment, corev1.EventTypeNormal, "SuccessfulScale", "Scaled MachineSet %v: %d -> %d",
+	events.Normalf(r.recorder, deployment, events.SuccessfulScaleReason, "Scaled MachineSet %v: %d -> %d",
 		client.ObjectKeyFromObject(ms), originalReplicas, *ms.Spec.Replicas)
 
 	return nil
@@ -636,10 +636,10 @@ func (r *Reconciler) cleanupDeployment(ctx context.Context, oldMSs []*clusterv1.
 		if err := r.Client.Delete(ctx, ms); err != nil && !apierrors.IsNotFound(err) {
 			// Return error instead of aggregating and continuing DELETEs on the theory
 			// that we may be overloading the api server.
-			r.recorder.Eventf(deployment, corev1.EventTypeWarning, "FailedDelete", "Failed to delete MachineSet %q: %v", ms.Name, err)
+			events.Warningf(r.recorder, deployment, events.FailedDeleteReason, "Failed to delete MachineSet %q: %v", ms.Name, err)
 			return err
 		}
-		r.recorder.Eventf(deployment, corev1.EventTypeNormal, "SuccessfulDelete", "Deleted MachineSet %q", ms.Name)
+		events.Normalf(r.recorder, deployment, events.SuccessfulDeleteReason, "Deleted MachineSet %q", ms.Name)
 	}
 
 	return nil
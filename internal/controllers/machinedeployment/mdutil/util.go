@@ -147,6 +147,7 @@ var annotationsToSkip = map[string]bool{
 	clusterv1.RevisionHistoryAnnotation: true,
 	clusterv1.DesiredReplicasAnnotation: true,
 	clusterv1.MaxReplicasAnnotation:     true,
+	clusterv1.CanaryAnnotation:          true,
 
 	// Exclude the conversion annotation, to avoid infinite loops between the conversion webhook
 	// and the MachineDeployment controller syncing the annotations between a MachineDeployment
@@ -221,6 +222,12 @@ func ComputeMachineSetAnnotations(log logr.Logger, deployment *clusterv1.Machine
 			annotations[clusterv1.RevisionHistoryAnnotation] = revisionHistory
 		}
 
+		// Ensure we preserve the outcome of the canary phase in any case if it already exists.
+		// Note: With Server-Side-Apply not setting the annotation would drop it.
+		if canary, ok := newMS.Annotations[clusterv1.CanaryAnnotation]; ok {
+			annotations[clusterv1.CanaryAnnotation] = canary
+		}
+
 		// If the revision changes then add the old revision to the revision history annotation
 		if currentRevisionExists && currentRevision != newRevision {
 			oldRevisions := strings.Split(revisionHistory, ",")
@@ -389,6 +396,9 @@ func MachineTemplateDeepCopyRolloutFields(template *clusterv1.MachineTemplateSpe
 	templateCopy.Spec.NodeDrainTimeout = nil
 	templateCopy.Spec.NodeDeletionTimeout = nil
 	templateCopy.Spec.NodeVolumeDetachTimeout = nil
+	templateCopy.Spec.NodeStartupTimeout = nil
+	templateCopy.Spec.NodeReRegistrationPolicy = ""
+	templateCopy.Spec.NodeDrainEvictionTimeout = nil
 
 	// Remove the version part from the references APIVersion field,
 	// for more details see issue #2183 and #2140.
@@ -47,9 +47,11 @@ import (
 	"sigs.k8s.io/cluster-api/controllers/external"
 	"sigs.k8s.io/cluster-api/controllers/remote"
 	"sigs.k8s.io/cluster-api/internal/controllers/machine"
+	"sigs.k8s.io/cluster-api/internal/util/maintenance"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/events"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
 )
@@ -59,7 +61,7 @@ const (
 
 	// EventRemediationRestricted is emitted in case when machine remediation
 	// is restricted by remediation circuit shorting logic.
-	EventRemediationRestricted string = "RemediationRestricted"
+	EventRemediationRestricted = string(events.RemediationRestrictedReason)
 
 	maxUnhealthyKeyLog     = "max unhealthy"
 	unhealthyTargetsKeyLog = "unhealthy targets"
@@ -179,7 +181,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 			return ctrl.Result{Requeue: true}, nil
 		}
 		log.Error(err, "Failed to reconcile MachineHealthCheck")
-		r.recorder.Eventf(m, corev1.EventTypeWarning, "ReconcileError", "%v", err)
+		events.Warningf(r.recorder, m, events.ReconcileErrorReason, "%v", err)
 
 		// Requeue immediately if any errors occurred
 		return ctrl.Result{}, err
@@ -280,10 +282,10 @@ func (r *Reconciler) reconcile(ctx context.Context, logger logr.Logger, cluster
 			Message:  message,
 		})
 
-		r.recorder.Event(
+		events.Warning(
+			r.recorder,
 			m,
-			corev1.EventTypeWarning,
-			EventRemediationRestricted,
+			events.RemediationRestrictedReason,
 			message,
 		)
 		errList := []error{}
@@ -299,6 +301,35 @@ func (r *Reconciler) reconcile(ctx context.Context, logger logr.Logger, cluster
 		return reconcile.Result{Requeue: true}, nil
 	}
 
+	// Defer remediation until the next maintenance window of the Cluster opens, if any.
+	if len(unhealthy) > 0 {
+		allowed, requeueAfter, err := maintenance.IsAllowed(cluster, time.Now())
+		if err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "failed to check the maintenance windows of the Cluster")
+		}
+		if !allowed {
+			logger.V(3).Info(
+				"Deferring remediation until the next maintenance window of the Cluster",
+				totalTargetKeyLog, totalTargets,
+				unhealthyTargetsKeyLog, len(unhealthy),
+				"requeueAfter", requeueAfter,
+			)
+			m.Status.RemediationsAllowed = 0
+			conditions.MarkFalse(m, clusterv1.RemediationAllowedCondition, clusterv1.WaitingForMaintenanceWindowReason, clusterv1.ConditionSeverityInfo, "Remediation is deferred until the next maintenance window of the Cluster")
+
+			errList := r.patchHealthyTargets(ctx, logger, healthy, m)
+			for _, t := range unhealthy {
+				if err := t.patchHelper.Patch(ctx, t.Machine); err != nil {
+					errList = append(errList, errors.Wrapf(err, "failed to patch machine status for machine: %s/%s", t.Machine.Namespace, t.Machine.Name))
+				}
+			}
+			if len(errList) > 0 {
+				return ctrl.Result{}, kerrors.NewAggregate(errList)
+			}
+			return ctrl.Result{RequeueAfter: requeueAfter}, nil
+		}
+	}
+
 	if m.Spec.UnhealthyRange == nil {
 		logger.V(3).Info(
 			"Remediations are allowed",
@@ -443,10 +474,10 @@ func (r *Reconciler) patchUnhealthyTargets(ctx context.Context, logger logr.Logg
 			errList = append(errList, errors.Wrapf(err, "failed to patch unhealthy machine status for machine: %s/%s", t.Machine.Namespace, t.Machine.Name))
 			continue
 		}
-		r.recorder.Eventf(
+		events.Normalf(
+			r.recorder,
 			t.Machine,
-			corev1.EventTypeNormal,
-			EventMachineMarkedUnhealthy,
+			events.MachineMarkedUnhealthyReason,
 			"Machine %v has been marked as unhealthy",
 			t.string(),
 		)
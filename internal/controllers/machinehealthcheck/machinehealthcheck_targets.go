@@ -34,6 +34,7 @@ import (
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/events"
 	"sigs.k8s.io/cluster-api/util/patch"
 )
 
@@ -41,10 +42,10 @@ const (
 	// Event types.
 
 	// EventMachineMarkedUnhealthy is emitted when machine was successfully marked as unhealthy.
-	EventMachineMarkedUnhealthy string = "MachineMarkedUnhealthy"
+	EventMachineMarkedUnhealthy = string(events.MachineMarkedUnhealthyReason)
 	// EventDetectedUnhealthy is emitted in case a node associated with a
 	// machine was detected unhealthy.
-	EventDetectedUnhealthy string = "DetectedUnhealthy"
+	EventDetectedUnhealthy = string(events.DetectedUnhealthyReason)
 )
 
 var (
@@ -297,10 +298,10 @@ func (r *Reconciler) healthCheckTargets(targets []healthCheckTarget, logger logr
 
 		if nextCheck > 0 {
 			logger.V(3).Info("Target is likely to go unhealthy", "timeUntilUnhealthy", nextCheck.Truncate(time.Second).String())
-			r.recorder.Eventf(
+			events.Normalf(
+				r.recorder,
 				t.Machine,
-				corev1.EventTypeNormal,
-				EventDetectedUnhealthy,
+				events.DetectedUnhealthyReason,
 				"Machine %v has unhealthy node %v",
 				t.string(),
 				t.nodeName(),
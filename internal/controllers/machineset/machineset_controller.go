@@ -19,6 +19,7 @@ package machineset
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -29,10 +30,11 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
-	"k8s.io/apiserver/pkg/storage/names"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -45,16 +47,23 @@ import (
 	"sigs.k8s.io/cluster-api/controllers/remote"
 	"sigs.k8s.io/cluster-api/internal/contract"
 	"sigs.k8s.io/cluster-api/internal/controllers/machine"
+	runtimeclient "sigs.k8s.io/cluster-api/internal/runtime/client"
+	"sigs.k8s.io/cluster-api/internal/topology/names"
 	"sigs.k8s.io/cluster-api/internal/util/ssa"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/collections"
+	"sigs.k8s.io/cluster-api/util/concurrency"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
+	"sigs.k8s.io/cluster-api/util/events"
+	"sigs.k8s.io/cluster-api/util/failuredomains"
 	"sigs.k8s.io/cluster-api/util/labels/format"
 	clog "sigs.k8s.io/cluster-api/util/log"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
+	"sigs.k8s.io/cluster-api/util/priority"
+	"sigs.k8s.io/cluster-api/util/tracing"
 )
 
 var (
@@ -67,10 +76,30 @@ var (
 	// stateConfirmationInterval is the amount of time between polling for the desired state.
 	// The polling is against a local memory cache.
 	stateConfirmationInterval = 100 * time.Millisecond
+
+	// ordinalsInUseRequeueAfter is how long to wait before checking again if the Machines holding the
+	// ordinals required to scale up a MachineSet with the Ordinal naming strategy have been deleted.
+	ordinalsInUseRequeueAfter = 10 * time.Second
 )
 
 const machineSetManagerName = "capi-machineset"
 
+// AdoptionPolicy defines how a MachineSet adopts orphaned Machines matching its selector.
+type AdoptionPolicy string
+
+const (
+	// AdoptionPolicyAlways adopts all the orphaned Machines matching the selector of the MachineSet.
+	AdoptionPolicyAlways AdoptionPolicy = "Always"
+
+	// AdoptionPolicyAnnotated adopts only the orphaned Machines matching the selector of the MachineSet
+	// which have the MachineSetAdoptAnnotation set to the name of the MachineSet.
+	AdoptionPolicyAnnotated AdoptionPolicy = "Annotated"
+
+	// AdoptionPolicyDryRun never adopts orphaned Machines; it only records an event for each orphaned
+	// Machine that would be adopted with the Always policy.
+	AdoptionPolicyDryRun AdoptionPolicy = "DryRun"
+)
+
 // +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;patch
 // +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch;create;update;patch;delete
@@ -84,19 +113,45 @@ type Reconciler struct {
 	APIReader                 client.Reader
 	Tracker                   *remote.ClusterCacheTracker
 
+	// RuntimeClient is used to call the MachineSetPreflightCheck hook, if the RuntimeSDK feature flag is enabled.
+	RuntimeClient runtimeclient.Client
+
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
 
+	// ReconcilePriorityWeights are the weights of the priority queue serving the requests for the objects of
+	// Clusters by their reconcile priority. If not set, requests are served in the order they are received.
+	ReconcilePriorityWeights priority.Weights
+
+	// NamespaceConcurrency is the maximum number of objects of the same namespace reconciled at the same time.
+	// If zero, it is not limited.
+	NamespaceConcurrency int
+
+	// AdoptionPolicy defines how MachineSets adopt orphaned Machines matching their selector.
+	// If empty, AdoptionPolicyAlways is used.
+	AdoptionPolicy AdoptionPolicy
+
 	ssaCache ssa.Cache
 	recorder record.EventRecorder
 }
 
 func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	switch r.AdoptionPolicy {
+	case "", AdoptionPolicyAlways, AdoptionPolicyAnnotated, AdoptionPolicyDryRun:
+	default:
+		return errors.Errorf("invalid adoption policy %q: must be one of %s, %s or %s", r.AdoptionPolicy, AdoptionPolicyAlways, AdoptionPolicyAnnotated, AdoptionPolicyDryRun)
+	}
+
 	clusterToMachineSets, err := util.ClusterToTypedObjectsMapper(mgr.GetClient(), &clusterv1.MachineSetList{}, mgr.GetScheme())
 	if err != nil {
 		return err
 	}
 
+	reconciler, err := priority.NewReconciler(mgr, concurrency.NewNamespaceLimitedReconciler(tracing.NewReconciler(r, "MachineSet"), r.NamespaceConcurrency), &clusterv1.MachineSet{}, r.ReconcilePriorityWeights, options.MaxConcurrentReconciles)
+	if err != nil {
+		return errors.Wrap(err, "failed setting up with a controller manager")
+	}
+
 	err = ctrl.NewControllerManagedBy(mgr).
 		For(&clusterv1.MachineSet{}).
 		Owns(&clusterv1.Machine{}).
@@ -116,7 +171,7 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opt
 					predicates.ResourceHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue),
 				),
 			),
-		).Complete(r)
+		).Complete(reconciler)
 	if err != nil {
 		return errors.Wrap(err, "failed setting up with a controller manager")
 	}
@@ -147,6 +202,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 
 	log = log.WithValues("Cluster", klog.KRef(machineSet.ObjectMeta.Namespace, machineSet.Spec.ClusterName))
 	ctx = ctrl.LoggerInto(ctx, log)
+	ctx, log = clog.AddOperationID(ctx, machineSet)
 
 	cluster, err := util.GetClusterByName(ctx, r.Client, machineSet.ObjectMeta.Namespace, machineSet.Spec.ClusterName)
 	if err != nil {
@@ -186,7 +242,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 			log.V(5).Info("Requeuing because another worker has the lock on the ClusterCacheTracker")
 			return ctrl.Result{Requeue: true}, nil
 		}
-		r.recorder.Eventf(machineSet, corev1.EventTypeWarning, "ReconcileError", "%v", err)
+		events.Warningf(r.recorder, machineSet, events.ReconcileErrorReason, "%v", err)
 	}
 	return result, err
 }
@@ -284,13 +340,18 @@ func (r *Reconciler) reconcile(ctx context.Context, cluster *clusterv1.Cluster,
 
 		// Attempt to adopt machine if it meets previous conditions and it has no controller references.
 		if metav1.GetControllerOf(machine) == nil {
+			if adopt, reason := r.shouldAdoptOrphan(machineSet, machine); !adopt {
+				log.V(4).Info("Skipped adoption of orphaned Machine", "reason", reason)
+				events.Normalf(r.recorder, machineSet, events.SkippedAdoptReason, "Skipped adoption of Machine %q: %s", machine.Name, reason)
+				continue
+			}
 			if err := r.adoptOrphan(ctx, machineSet, machine); err != nil {
 				log.Error(err, "Failed to adopt Machine")
-				r.recorder.Eventf(machineSet, corev1.EventTypeWarning, "FailedAdopt", "Failed to adopt Machine %q: %v", machine.Name, err)
+				events.Warningf(r.recorder, machineSet, events.FailedAdoptReason, "Failed to adopt Machine %q: %v", machine.Name, err)
 				continue
 			}
 			log.Info("Adopted Machine")
-			r.recorder.Eventf(machineSet, corev1.EventTypeNormal, "SuccessfulAdopt", "Adopted Machine %q", machine.Name)
+			events.Normalf(r.recorder, machineSet, events.SuccessfulAdoptReason, "Adopted Machine %q", machine.Name)
 		}
 
 		filteredMachines = append(filteredMachines, machine)
@@ -304,12 +365,15 @@ func (r *Reconciler) reconcile(ctx context.Context, cluster *clusterv1.Cluster,
 	}
 	result = util.LowestNonZeroResult(result, reconcileUnhealthyMachinesResult)
 
-	if err := r.syncMachines(ctx, machineSet, filteredMachines); err != nil {
+	if err := r.syncMachines(ctx, cluster, machineSet, filteredMachines); err != nil {
 		return ctrl.Result{}, errors.Wrap(err, "failed to update Machines")
 	}
 
 	syncReplicasResult, syncErr := r.syncReplicas(ctx, cluster, machineSet, filteredMachines)
 	result = util.LowestNonZeroResult(result, syncReplicasResult)
+	if syncErr == nil {
+		syncErr = r.reconcileFailureDomainRebalance(ctx, cluster, machineSet, filteredMachines)
+	}
 
 	// Always updates status as machines come up or die.
 	if err := r.updateStatus(ctx, cluster, machineSet, filteredMachines); err != nil {
@@ -357,7 +421,7 @@ func (r *Reconciler) reconcile(ctx context.Context, cluster *clusterv1.Cluster,
 // "metadata.annotations" from "manager" so that "capi-machineset" can own these fields and can work with SSA.
 // Otherwise fields would be co-owned by our "old" "manager" and "capi-machineset" and then we would not be
 // able to e.g. drop labels and annotations.
-func (r *Reconciler) syncMachines(ctx context.Context, machineSet *clusterv1.MachineSet, machines []*clusterv1.Machine) error {
+func (r *Reconciler) syncMachines(ctx context.Context, cluster *clusterv1.Cluster, machineSet *clusterv1.MachineSet, machines []*clusterv1.Machine) error {
 	log := ctrl.LoggerFrom(ctx)
 	for i := range machines {
 		m := machines[i]
@@ -375,8 +439,11 @@ func (r *Reconciler) syncMachines(ctx context.Context, machineSet *clusterv1.Mac
 		}
 
 		// Update Machine to propagate in-place mutable fields from the MachineSet.
-		updatedMachine := r.computeDesiredMachine(machineSet, m)
-		err := ssa.Patch(ctx, r.Client, machineSetManagerName, updatedMachine, ssa.WithCachingProxy{Cache: r.ssaCache, Original: m})
+		updatedMachine, err := r.computeDesiredMachine(cluster, machineSet, m)
+		if err != nil {
+			return errors.Wrapf(err, "failed to update Machine %q", klog.KObj(m))
+		}
+		err = ssa.Patch(ctx, r.Client, machineSetManagerName, updatedMachine, ssa.WithCachingProxy{Cache: r.ssaCache, Original: m})
 		if err != nil {
 			log.Error(err, "failed to update Machine", "Machine", klog.KObj(updatedMachine))
 			return errors.Wrapf(err, "failed to update Machine %q", klog.KObj(updatedMachine))
@@ -400,7 +467,7 @@ func (r *Reconciler) syncMachines(ctx context.Context, machineSet *clusterv1.Mac
 			return errors.Wrapf(err, "failed to update machine: failed to adjust the managedFields of the InfrastructureMachine %s", klog.KObj(infraMachine))
 		}
 		// Update in-place mutating fields on InfrastructureMachine.
-		if err := r.updateExternalObject(ctx, infraMachine, machineSet); err != nil {
+		if err := r.updateExternalObject(ctx, cluster, infraMachine, machineSet); err != nil {
 			return errors.Wrapf(err, "failed to update InfrastructureMachine %s", klog.KObj(infraMachine))
 		}
 
@@ -418,7 +485,7 @@ func (r *Reconciler) syncMachines(ctx context.Context, machineSet *clusterv1.Mac
 				return errors.Wrapf(err, "failed to update machine: failed to adjust the managedFields of the BootstrapConfig %s", klog.KObj(bootstrapConfig))
 			}
 			// Update in-place mutating fields on BootstrapConfig.
-			if err := r.updateExternalObject(ctx, bootstrapConfig, machineSet); err != nil {
+			if err := r.updateExternalObject(ctx, cluster, bootstrapConfig, machineSet); err != nil {
 				return errors.Wrapf(err, "failed to update BootstrapConfig %s", klog.KObj(bootstrapConfig))
 			}
 		}
@@ -444,6 +511,13 @@ func (r *Reconciler) syncReplicas(ctx context.Context, cluster *clusterv1.Cluste
 			}
 		}
 
+		// Do not replace Machines deleted while the Cluster is being deleted, e.g. when worker Machines
+		// are deleted in batches by the Cluster controller.
+		if !cluster.DeletionTimestamp.IsZero() {
+			log.Info("Automatic creation of new machines disabled because the Cluster is being deleted")
+			return ctrl.Result{}, nil
+		}
+
 		result, preflightCheckErrMessage, err := r.runPreflightChecks(ctx, cluster, ms, "Scale up")
 		if err != nil || !result.IsZero() {
 			if err != nil {
@@ -454,6 +528,27 @@ func (r *Reconciler) syncReplicas(ctx context.Context, cluster *clusterv1.Cluste
 			return result, err
 		}
 
+		// With the Ordinal naming strategy, new Machines get the lowest ordinals not in use; if the ordinals are
+		// still in use by Machines being deleted, e.g. the Machines being replaced, wait for them to be gone.
+		var ordinalNames []string
+		if ms.Spec.MachineNamingStrategy != nil && ms.Spec.MachineNamingStrategy.Type == clusterv1.OrdinalMachineNamingStrategyType {
+			ordinalNames, err = r.getAvailableOrdinalMachineNames(ctx, ms)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if len(ordinalNames) < diff {
+				log.Info(fmt.Sprintf("Waiting for Machines to be deleted to reuse their ordinals, creating %d of %d machines", len(ordinalNames), diff))
+				diff = len(ordinalNames)
+				result = ctrl.Result{RequeueAfter: ordinalsInUseRequeueAfter}
+			}
+		}
+
+		// Get the pre-provisioned InfraMachines which can be claimed from MachineWarmPools, if any.
+		warmPoolInfraMachines, err := r.getWarmPoolInfraMachines(ctx, ms)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
 		var (
 			machineList []*clusterv1.Machine
 			errs        []error
@@ -462,12 +557,32 @@ func (r *Reconciler) syncReplicas(ctx context.Context, cluster *clusterv1.Cluste
 		for i := 0; i < diff; i++ {
 			// Create a new logger so the global logger is not modified.
 			log := log
-			machine := r.computeDesiredMachine(ms, nil)
+			machine, err := r.computeDesiredMachine(cluster, ms, nil)
+			if err != nil {
+				conditions.MarkFalse(ms, clusterv1.MachinesCreatedCondition, clusterv1.MachineCreationFailedReason, clusterv1.ConditionSeverityError, err.Error())
+				return ctrl.Result{}, errors.Wrap(err, "failed to create Machine")
+			}
+			if ordinalNames != nil {
+				machine.SetName(ordinalNames[i])
+			}
+
+			// Spread Machines across failure domains, if required by the MachineSet.
+			// NOTE: Machines created in the previous iterations are taken into account.
+			if ms.Spec.FailureDomainSpreadPolicy != nil && len(cluster.Status.FailureDomains) > 0 {
+				existingMachines := collections.FromMachines(machines...).Filter(collections.Not(collections.HasDeletionTimestamp))
+				existingMachines.Insert(machineList...)
+				machine.Spec.FailureDomain = failuredomains.PickFewest(cluster.Status.FailureDomains, existingMachines)
+				log = log.WithValues("failureDomain", pointer.StringDeref(machine.Spec.FailureDomain, ""))
+			}
 			// Clone and set the infrastructure and bootstrap references.
-			var (
-				infraRef, bootstrapRef *corev1.ObjectReference
-				err                    error
-			)
+			var infraRef, bootstrapRef *corev1.ObjectReference
+
+			// If the MachineSet defines a naming strategy for its Machines, the bootstrap config and
+			// the InfrastructureMachine get the same name as the Machine.
+			var externalObjectName string
+			if ms.Spec.MachineNamingStrategy != nil {
+				externalObjectName = machine.Name
+			}
 
 			// Create the BootstrapConfig if necessary.
 			if ms.Spec.Template.Spec.Bootstrap.ConfigRef != nil {
@@ -476,6 +591,7 @@ func (r *Reconciler) syncReplicas(ctx context.Context, cluster *clusterv1.Cluste
 					TemplateRef: ms.Spec.Template.Spec.Bootstrap.ConfigRef,
 					Namespace:   machine.Namespace,
 					ClusterName: machine.Spec.ClusterName,
+					Name:        externalObjectName,
 					Labels:      machine.Labels,
 					Annotations: machine.Annotations,
 					OwnerRef: &metav1.OwnerReference{
@@ -495,26 +611,40 @@ func (r *Reconciler) syncReplicas(ctx context.Context, cluster *clusterv1.Cluste
 				log = log.WithValues(bootstrapRef.Kind, klog.KRef(bootstrapRef.Namespace, bootstrapRef.Name))
 			}
 
-			// Create the InfraMachine.
-			infraRef, err = external.CreateFromTemplate(ctx, &external.CreateFromTemplateInput{
-				Client:      r.UnstructuredCachingClient,
-				TemplateRef: &ms.Spec.Template.Spec.InfrastructureRef,
-				Namespace:   machine.Namespace,
-				ClusterName: machine.Spec.ClusterName,
-				Labels:      machine.Labels,
-				Annotations: machine.Annotations,
-				OwnerRef: &metav1.OwnerReference{
-					APIVersion: clusterv1.GroupVersion.String(),
-					Kind:       "MachineSet",
-					Name:       ms.Name,
-					UID:        ms.UID,
-				},
-			})
-			if err != nil {
-				conditions.MarkFalse(ms, clusterv1.MachinesCreatedCondition, clusterv1.InfrastructureTemplateCloningFailedReason, clusterv1.ConditionSeverityError, err.Error())
-				return ctrl.Result{}, errors.Wrapf(err, "failed to clone infrastructure machine from %s %s while creating a machine",
-					ms.Spec.Template.Spec.InfrastructureRef.Kind,
-					klog.KRef(ms.Spec.Template.Spec.InfrastructureRef.Namespace, ms.Spec.Template.Spec.InfrastructureRef.Name))
+			// Claim a pre-provisioned InfraMachine from a MachineWarmPool, if any; the Machine gets the same name
+			// of the InfraMachine, because providers could use the Machine name for the pre-provisioned infrastructure.
+			// NOTE: InfraMachines are not claimed if the MachineSet defines a naming strategy for its Machines,
+			// or if the Machine must be placed in a specific failure domain.
+			if ms.Spec.MachineNamingStrategy == nil && machine.Spec.FailureDomain == nil {
+				infraRef = r.claimWarmPoolInfraMachine(ctx, ms, machine, &warmPoolInfraMachines)
+				if infraRef != nil {
+					machine.SetName(infraRef.Name)
+				}
+			}
+
+			// Create the InfraMachine, if not claimed from a MachineWarmPool.
+			if infraRef == nil {
+				infraRef, err = external.CreateFromTemplate(ctx, &external.CreateFromTemplateInput{
+					Client:      r.UnstructuredCachingClient,
+					TemplateRef: &ms.Spec.Template.Spec.InfrastructureRef,
+					Namespace:   machine.Namespace,
+					ClusterName: machine.Spec.ClusterName,
+					Name:        externalObjectName,
+					Labels:      machine.Labels,
+					Annotations: machine.Annotations,
+					OwnerRef: &metav1.OwnerReference{
+						APIVersion: clusterv1.GroupVersion.String(),
+						Kind:       "MachineSet",
+						Name:       ms.Name,
+						UID:        ms.UID,
+					},
+				})
+				if err != nil {
+					conditions.MarkFalse(ms, clusterv1.MachinesCreatedCondition, clusterv1.InfrastructureTemplateCloningFailedReason, clusterv1.ConditionSeverityError, err.Error())
+					return ctrl.Result{}, errors.Wrapf(err, "failed to clone infrastructure machine from %s %s while creating a machine",
+						ms.Spec.Template.Spec.InfrastructureRef.Kind,
+						klog.KRef(ms.Spec.Template.Spec.InfrastructureRef.Namespace, ms.Spec.Template.Spec.InfrastructureRef.Name))
+				}
 			}
 			log = log.WithValues(infraRef.Kind, klog.KRef(infraRef.Namespace, infraRef.Name))
 			machine.Spec.InfrastructureRef = *infraRef
@@ -522,7 +652,7 @@ func (r *Reconciler) syncReplicas(ctx context.Context, cluster *clusterv1.Cluste
 			// Create the Machine.
 			if err := ssa.Patch(ctx, r.Client, machineSetManagerName, machine); err != nil {
 				log.Error(err, "Error while creating a machine")
-				r.recorder.Eventf(ms, corev1.EventTypeWarning, "FailedCreate", "Failed to create machine: %v", err)
+				events.Warningf(r.recorder, ms, events.FailedCreateReason, "Failed to create machine: %v", err)
 				errs = append(errs, err)
 				conditions.MarkFalse(ms, clusterv1.MachinesCreatedCondition, clusterv1.MachineCreationFailedReason,
 					clusterv1.ConditionSeverityError, err.Error())
@@ -540,14 +670,14 @@ func (r *Reconciler) syncReplicas(ctx context.Context, cluster *clusterv1.Cluste
 			}
 
 			log.Info(fmt.Sprintf("Created machine %d of %d", i+1, diff), "Machine", klog.KObj(machine))
-			r.recorder.Eventf(ms, corev1.EventTypeNormal, "SuccessfulCreate", "Created machine %q", machine.Name)
+			events.Normalf(r.recorder, ms, events.SuccessfulCreateReason, "Created machine %q", machine.Name)
 			machineList = append(machineList, machine)
 		}
 
 		if len(errs) > 0 {
 			return ctrl.Result{}, kerrors.NewAggregate(errs)
 		}
-		return ctrl.Result{}, r.waitForMachineCreation(ctx, machineList)
+		return result, r.waitForMachineCreation(ctx, machineList)
 	case diff > 0:
 		log.Info(fmt.Sprintf("MachineSet is scaling down to %d replicas by deleting %d machines", *(ms.Spec.Replicas), diff), "replicas", *(ms.Spec.Replicas), "machineCount", len(machines), "deletePolicy", ms.Spec.DeletePolicy)
 
@@ -564,11 +694,11 @@ func (r *Reconciler) syncReplicas(ctx context.Context, cluster *clusterv1.Cluste
 				log.Info(fmt.Sprintf("Deleting machine %d of %d", i+1, diff))
 				if err := r.Client.Delete(ctx, machine); err != nil {
 					log.Error(err, "Unable to delete Machine")
-					r.recorder.Eventf(ms, corev1.EventTypeWarning, "FailedDelete", "Failed to delete machine %q: %v", machine.Name, err)
+					events.Warningf(r.recorder, ms, events.FailedDeleteReason, "Failed to delete machine %q: %v", machine.Name, err)
 					errs = append(errs, err)
 					continue
 				}
-				r.recorder.Eventf(ms, corev1.EventTypeNormal, "SuccessfulDelete", "Deleted machine %q", machine.Name)
+				events.Normalf(r.recorder, ms, events.SuccessfulDeleteReason, "Deleted machine %q", machine.Name)
 			} else {
 				log.Info(fmt.Sprintf("Waiting for machine %d of %d to be deleted", i+1, diff))
 			}
@@ -583,6 +713,122 @@ func (r *Reconciler) syncReplicas(ctx context.Context, cluster *clusterv1.Cluste
 	return ctrl.Result{}, nil
 }
 
+// reconcileFailureDomainRebalance deletes Machines in the failure domains with the most Machines when Machines
+// are not evenly spread across failure domains and the MachineSet uses the Rebalance FailureDomainSpreadPolicy;
+// deleted Machines are then replaced by syncReplicas in the failure domains with the fewest Machines.
+func (r *Reconciler) reconcileFailureDomainRebalance(ctx context.Context, cluster *clusterv1.Cluster, ms *clusterv1.MachineSet, machines []*clusterv1.Machine) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	policy := ms.Spec.FailureDomainSpreadPolicy
+	if policy == nil || policy.Type != clusterv1.RebalanceFailureDomainSpreadPolicyType || len(cluster.Status.FailureDomains) == 0 {
+		return nil
+	}
+
+	// Rebalance only when the MachineSet is not scaling and no Machine is being deleted.
+	allMachines := collections.FromMachines(machines...)
+	if len(allMachines) != int(*ms.Spec.Replicas) || len(allMachines.Filter(collections.HasDeletionTimestamp)) > 0 {
+		return nil
+	}
+
+	// Do not rebalance while the Cluster is being deleted or if creation of new Machines is disabled,
+	// because deleted Machines would not be replaced.
+	if !cluster.DeletionTimestamp.IsZero() {
+		return nil
+	}
+	if _, ok := ms.Annotations[clusterv1.DisableMachineCreateAnnotation]; ok {
+		return nil
+	}
+
+	// Machines which are already unavailable count against MaxUnavailable.
+	maxUnavailable := int(pointer.Int32Deref(policy.MaxUnavailable, 1))
+	unavailable := int(*ms.Spec.Replicas - ms.Status.AvailableReplicas)
+	if unavailable < 0 {
+		unavailable = 0
+	}
+
+	var machinesToDelete []*clusterv1.Machine
+	remainingMachines := collections.FromMachines(machines...)
+	for i := 0; i < maxUnavailable-unavailable; i++ {
+		if failuredomains.IsBalanced(cluster.Status.FailureDomains, remainingMachines) {
+			break
+		}
+
+		// Pick the oldest Machine in the failure domain with the most Machines.
+		failureDomain := failuredomains.PickMost(cluster.Status.FailureDomains, remainingMachines, remainingMachines)
+		machine := remainingMachines.Filter(collections.InFailureDomains(failureDomain)).Oldest()
+		if machine == nil {
+			break
+		}
+		remainingMachines = remainingMachines.Difference(collections.FromMachines(machine))
+		machinesToDelete = append(machinesToDelete, machine)
+
+		// Account for the replacement Machine, which is going to be created in the failure domain with the fewest Machines.
+		remainingMachines.Insert(&clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%s-replacement", machine.Name)},
+			Spec:       clusterv1.MachineSpec{FailureDomain: failuredomains.PickFewest(cluster.Status.FailureDomains, remainingMachines)},
+		})
+	}
+
+	var errs []error
+	for _, machine := range machinesToDelete {
+		log := log.WithValues("Machine", klog.KObj(machine), "failureDomain", pointer.StringDeref(machine.Spec.FailureDomain, ""))
+		log.Info("Deleting Machine to rebalance Machines across failure domains")
+		if err := r.Client.Delete(ctx, machine); err != nil {
+			log.Error(err, "Unable to delete Machine")
+			events.Warningf(r.recorder, ms, events.FailedDeleteReason, "Failed to delete machine %q: %v", machine.Name, err)
+			errs = append(errs, err)
+			continue
+		}
+		events.Normalf(r.recorder, ms, events.SuccessfulDeleteReason, "Deleted machine %q to rebalance failure domains", machine.Name)
+	}
+	if len(errs) > 0 {
+		return kerrors.NewAggregate(errs)
+	}
+	return r.waitForMachineDeletion(ctx, machinesToDelete)
+}
+
+// getAvailableOrdinalMachineNames returns the names of the Machines which can be created by a MachineSet with the
+// Ordinal naming strategy, in order of ordinal. Machines are named after the MachineDeployment owning the MachineSet,
+// if any, so Machines keep their names across MachineSets; ordinals go from 0 to the maximum number of replicas of
+// the MachineDeployment or of the MachineSet, minus one, and ordinals of existing Machines are not available.
+func (r *Reconciler) getAvailableOrdinalMachineNames(ctx context.Context, ms *clusterv1.MachineSet) ([]string, error) {
+	base := ms.Name
+	if name, ok := ms.Labels[clusterv1.MachineDeploymentNameLabel]; ok {
+		base = name
+	}
+
+	maxReplicas := int(pointer.Int32Deref(ms.Spec.Replicas, 0))
+	if value, ok := ms.Annotations[clusterv1.MaxReplicasAnnotation]; ok {
+		if mdMaxReplicas, err := strconv.Atoi(value); err == nil {
+			maxReplicas = mdMaxReplicas
+		}
+	}
+
+	// NOTE: Listing all the Machines in the namespace, given that names must be unique in a namespace.
+	machines := &clusterv1.MachineList{}
+	if err := r.Client.List(ctx, machines, client.InNamespace(ms.Namespace)); err != nil {
+		return nil, errors.Wrap(err, "failed to list Machines")
+	}
+	existingNames := sets.Set[string]{}
+	for _, m := range machines.Items {
+		existingNames.Insert(m.Name)
+	}
+	return availableOrdinalNames(base, maxReplicas, existingNames), nil
+}
+
+// availableOrdinalNames returns the names `<base>-<ordinal>` for ordinals from 0 to maxReplicas-1 which are not
+// in existingNames, in order of ordinal.
+func availableOrdinalNames(base string, maxReplicas int, existingNames sets.Set[string]) []string {
+	names := []string{}
+	for i := 0; i < maxReplicas; i++ {
+		name := fmt.Sprintf("%s-%d", base, i)
+		if !existingNames.Has(name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
 // computeDesiredMachine computes the desired Machine.
 // This Machine will be used during reconciliation to:
 // * create a Machine
@@ -591,14 +837,13 @@ func (r *Reconciler) syncReplicas(ctx context.Context, cluster *clusterv1.Cluste
 // There are small differences in how we calculate the Machine depending on if it
 // is a create or update. Example: for a new Machine we have to calculate a new name,
 // while for an existing Machine we have to use the name of the existing Machine.
-func (r *Reconciler) computeDesiredMachine(machineSet *clusterv1.MachineSet, existingMachine *clusterv1.Machine) *clusterv1.Machine {
+func (r *Reconciler) computeDesiredMachine(cluster *clusterv1.Cluster, machineSet *clusterv1.MachineSet, existingMachine *clusterv1.Machine) (*clusterv1.Machine, error) {
 	desiredMachine := &clusterv1.Machine{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: clusterv1.GroupVersion.String(),
 			Kind:       "Machine",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      names.SimpleNameGenerator.GenerateName(fmt.Sprintf("%s-", machineSet.Name)),
 			Namespace: machineSet.Namespace,
 			// Note: By setting the ownerRef on creation we signal to the Machine controller that this is not a stand-alone Machine.
 			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(machineSet, machineSetKind)},
@@ -630,6 +875,17 @@ func (r *Reconciler) computeDesiredMachine(machineSet *clusterv1.MachineSet, exi
 		desiredMachine.SetUID(existingMachine.UID)
 		desiredMachine.Spec.Bootstrap.ConfigRef = existingMachine.Spec.Bootstrap.ConfigRef
 		desiredMachine.Spec.InfrastructureRef = existingMachine.Spec.InfrastructureRef
+
+		// If Machines are spread across failure domains, preserve the failure domain picked when creating the Machine.
+		if machineSet.Spec.FailureDomainSpreadPolicy != nil {
+			desiredMachine.Spec.FailureDomain = existingMachine.Spec.FailureDomain
+		}
+	} else {
+		name, err := names.MachineNameGenerator(machineSet.Spec.MachineNamingStrategy, machineSet.Spec.ClusterName, machineSet.Name).GenerateName()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to generate Machine name")
+		}
+		desiredMachine.SetName(name)
 	}
 
 	// Set the in-place mutable fields.
@@ -637,22 +893,25 @@ func (r *Reconciler) computeDesiredMachine(machineSet *clusterv1.MachineSet, exi
 	// When we update an existing Machine will we update the fields on the existing Machine (in-place mutate).
 
 	// Set Labels
-	desiredMachine.Labels = machineLabelsFromMachineSet(machineSet)
+	desiredMachine.Labels = machineLabelsFromMachineSet(cluster, machineSet)
 
 	// Set Annotations
-	desiredMachine.Annotations = machineAnnotationsFromMachineSet(machineSet)
+	desiredMachine.Annotations = machineAnnotationsFromMachineSet(cluster, machineSet)
 
 	// Set all other in-place mutable fields.
 	desiredMachine.Spec.NodeDrainTimeout = machineSet.Spec.Template.Spec.NodeDrainTimeout
 	desiredMachine.Spec.NodeDeletionTimeout = machineSet.Spec.Template.Spec.NodeDeletionTimeout
 	desiredMachine.Spec.NodeVolumeDetachTimeout = machineSet.Spec.Template.Spec.NodeVolumeDetachTimeout
+	desiredMachine.Spec.NodeStartupTimeout = machineSet.Spec.Template.Spec.NodeStartupTimeout
+	desiredMachine.Spec.NodeReRegistrationPolicy = machineSet.Spec.Template.Spec.NodeReRegistrationPolicy
+	desiredMachine.Spec.NodeDrainEvictionTimeout = machineSet.Spec.Template.Spec.NodeDrainEvictionTimeout
 
-	return desiredMachine
+	return desiredMachine, nil
 }
 
 // updateExternalObject updates the external object passed in with the
-// updated labels and annotations from the MachineSet.
-func (r *Reconciler) updateExternalObject(ctx context.Context, obj client.Object, machineSet *clusterv1.MachineSet) error {
+// updated labels and annotations from the Cluster and the MachineSet.
+func (r *Reconciler) updateExternalObject(ctx context.Context, cluster *clusterv1.Cluster, obj client.Object, machineSet *clusterv1.MachineSet) error {
 	updatedObject := &unstructured.Unstructured{}
 	updatedObject.SetGroupVersionKind(obj.GetObjectKind().GroupVersionKind())
 	updatedObject.SetNamespace(obj.GetNamespace())
@@ -661,8 +920,8 @@ func (r *Reconciler) updateExternalObject(ctx context.Context, obj client.Object
 	// and does not perform an accidental create.
 	updatedObject.SetUID(obj.GetUID())
 
-	updatedObject.SetLabels(machineLabelsFromMachineSet(machineSet))
-	updatedObject.SetAnnotations(machineAnnotationsFromMachineSet(machineSet))
+	updatedObject.SetLabels(machineLabelsFromMachineSet(cluster, machineSet))
+	updatedObject.SetAnnotations(machineAnnotationsFromMachineSet(cluster, machineSet))
 
 	if err := ssa.Patch(ctx, r.Client, machineSetManagerName, updatedObject, ssa.WithCachingProxy{Cache: r.ssaCache, Original: obj}); err != nil {
 		return errors.Wrapf(err, "failed to update %s", klog.KObj(obj))
@@ -671,8 +930,15 @@ func (r *Reconciler) updateExternalObject(ctx context.Context, obj client.Object
 }
 
 // machineLabelsFromMachineSet computes the labels the Machine created from this MachineSet should have.
-func machineLabelsFromMachineSet(machineSet *clusterv1.MachineSet) map[string]string {
+// Labels from the MachineSet template take precedence over the ones from the Cluster metadata, so the Machine
+// always matches the selector of the MachineSet.
+func machineLabelsFromMachineSet(cluster *clusterv1.Cluster, machineSet *clusterv1.MachineSet) map[string]string {
 	machineLabels := map[string]string{}
+	if cluster.Spec.Metadata != nil {
+		for k, v := range cluster.Spec.Metadata.Labels {
+			machineLabels[k] = v
+		}
+	}
 	// Note: We can't just set `machineSet.Spec.Template.Labels` directly and thus "share" the labels
 	// map between Machine and machineSet.Spec.Template.Labels. This would mean that adding the
 	// MachineSetNameLabel and MachineDeploymentNameLabel later on the Machine would also add the labels
@@ -693,8 +959,14 @@ func machineLabelsFromMachineSet(machineSet *clusterv1.MachineSet) map[string]st
 }
 
 // machineAnnotationsFromMachineSet computes the annotations the Machine created from this MachineSet should have.
-func machineAnnotationsFromMachineSet(machineSet *clusterv1.MachineSet) map[string]string {
+// Annotations from the MachineSet template take precedence over the ones from the Cluster metadata.
+func machineAnnotationsFromMachineSet(cluster *clusterv1.Cluster, machineSet *clusterv1.MachineSet) map[string]string {
 	annotations := map[string]string{}
+	if cluster.Spec.Metadata != nil {
+		for k, v := range cluster.Spec.Metadata.Annotations {
+			annotations[k] = v
+		}
+	}
 	for k, v := range machineSet.Spec.Template.Annotations {
 		annotations[k] = v
 	}
@@ -710,6 +982,20 @@ func shouldExcludeMachine(machineSet *clusterv1.MachineSet, machine *clusterv1.M
 	return false
 }
 
+// shouldAdoptOrphan returns true if the orphaned Machine should be adopted by the MachineSet according to
+// the adoption policy; otherwise, it returns false together with the reason why the Machine is not adopted.
+func (r *Reconciler) shouldAdoptOrphan(machineSet *clusterv1.MachineSet, machine *clusterv1.Machine) (bool, string) {
+	switch r.AdoptionPolicy {
+	case AdoptionPolicyDryRun:
+		return false, "the Machine would be adopted, but the adoption policy is DryRun"
+	case AdoptionPolicyAnnotated:
+		if machine.Annotations[clusterv1.MachineSetAdoptAnnotation] != machineSet.Name {
+			return false, fmt.Sprintf("the Machine does not have the %s annotation set to the name of the MachineSet", clusterv1.MachineSetAdoptAnnotation)
+		}
+	}
+	return true, ""
+}
+
 // adoptOrphan sets the MachineSet as a controller OwnerReference to the Machine.
 func (r *Reconciler) adoptOrphan(ctx context.Context, machineSet *clusterv1.MachineSet, machine *clusterv1.Machine) error {
 	patch := client.MergeFrom(machine.DeepCopy())
@@ -1012,12 +1298,13 @@ func (r *Reconciler) reconcileUnhealthyMachines(ctx context.Context, cluster *cl
 	// Remediate unhealthy machines by deleting them.
 	var errs []error
 	for _, m := range machinesToRemediate {
-		log.Info(fmt.Sprintf("Deleting Machine %s because it was marked as unhealthy by the MachineHealthCheck controller", klog.KObj(m)))
+		log.Info(fmt.Sprintf("Deleting Machine %s because it was marked as unhealthy", klog.KObj(m)))
 		patch := client.MergeFrom(m.DeepCopy())
 		if err := r.Client.Delete(ctx, m); err != nil {
 			errs = append(errs, errors.Wrapf(err, "failed to delete Machine %s", klog.KObj(m)))
 			continue
 		}
+		events.Normalf(r.recorder, ms, events.RemediationTriggeredReason, "Deleted unhealthy Machine %q", m.Name)
 		conditions.MarkTrue(m, clusterv1.MachineOwnerRemediatedCondition)
 		if err := r.Client.Status().Patch(ctx, m, patch); err != nil && !apierrors.IsNotFound(err) {
 			errs = append(errs, errors.Wrapf(err, "failed to update status of Machine %s", klog.KObj(m)))
@@ -815,6 +815,80 @@ func TestAdoptOrphan(t *testing.T) {
 	}
 }
 
+func TestShouldAdoptOrphan(t *testing.T) {
+	ms := &clusterv1.MachineSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "ms",
+		},
+	}
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "orphanMachine",
+		},
+	}
+	annotatedMachine := machine.DeepCopy()
+	annotatedMachine.Annotations = map[string]string{clusterv1.MachineSetAdoptAnnotation: "ms"}
+	annotatedForOtherMachine := machine.DeepCopy()
+	annotatedForOtherMachine.Annotations = map[string]string{clusterv1.MachineSetAdoptAnnotation: "other-ms"}
+
+	testCases := []struct {
+		name     string
+		policy   AdoptionPolicy
+		machine  *clusterv1.Machine
+		expected bool
+	}{
+		{
+			name:     "default policy adopts the Machine",
+			machine:  machine,
+			expected: true,
+		},
+		{
+			name:     "Always policy adopts the Machine",
+			policy:   AdoptionPolicyAlways,
+			machine:  machine,
+			expected: true,
+		},
+		{
+			name:     "Annotated policy adopts the Machine annotated with the name of the MachineSet",
+			policy:   AdoptionPolicyAnnotated,
+			machine:  annotatedMachine,
+			expected: true,
+		},
+		{
+			name:     "Annotated policy does not adopt the Machine without annotation",
+			policy:   AdoptionPolicyAnnotated,
+			machine:  machine,
+			expected: false,
+		},
+		{
+			name:     "Annotated policy does not adopt the Machine annotated with the name of another MachineSet",
+			policy:   AdoptionPolicyAnnotated,
+			machine:  annotatedForOtherMachine,
+			expected: false,
+		},
+		{
+			name:     "DryRun policy does not adopt the Machine",
+			policy:   AdoptionPolicyDryRun,
+			machine:  annotatedMachine,
+			expected: false,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			r := &Reconciler{AdoptionPolicy: tc.policy}
+			adopt, reason := r.shouldAdoptOrphan(ms, tc.machine)
+			g.Expect(adopt).To(Equal(tc.expected))
+			if tc.expected {
+				g.Expect(reason).To(BeEmpty())
+			} else {
+				g.Expect(reason).ToNot(BeEmpty())
+			}
+		})
+	}
+}
+
 func newMachineSet(name, cluster string, replicas int32) *clusterv1.MachineSet {
 	return &clusterv1.MachineSet{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1198,7 +1272,7 @@ func TestMachineSetReconciler_syncMachines(t *testing.T) {
 		UnstructuredCachingClient: env,
 		ssaCache:                  ssa.NewCache(),
 	}
-	g.Expect(reconciler.syncMachines(ctx, ms, machines)).To(Succeed())
+	g.Expect(reconciler.syncMachines(ctx, testCluster, ms, machines)).To(Succeed())
 
 	// The inPlaceMutatingMachine should have cleaned up managed fields.
 	updatedInPlaceMutatingMachine := inPlaceMutatingMachine.DeepCopy()
@@ -1272,7 +1346,7 @@ func TestMachineSetReconciler_syncMachines(t *testing.T) {
 	ms.Spec.Template.Spec.NodeDrainTimeout = duration10s
 	ms.Spec.Template.Spec.NodeDeletionTimeout = duration10s
 	ms.Spec.Template.Spec.NodeVolumeDetachTimeout = duration10s
-	g.Expect(reconciler.syncMachines(ctx, ms, []*clusterv1.Machine{updatedInPlaceMutatingMachine, deletingMachine})).To(Succeed())
+	g.Expect(reconciler.syncMachines(ctx, testCluster, ms, []*clusterv1.Machine{updatedInPlaceMutatingMachine, deletingMachine})).To(Succeed())
 
 	// Verify in-place mutable fields are updated on the Machine.
 	updatedInPlaceMutatingMachine = inPlaceMutatingMachine.DeepCopy()
@@ -1397,6 +1471,7 @@ func TestMachineSetReconciler_reconcileUnhealthyMachines(t *testing.T) {
 		r := &Reconciler{
 			Client:                    fakeClient,
 			UnstructuredCachingClient: fakeClient,
+			recorder:                  record.NewFakeRecorder(32),
 		}
 		_, err := r.reconcileUnhealthyMachines(ctx, cluster, machineSet, machines)
 		g.Expect(err).ToNot(HaveOccurred())
@@ -1458,6 +1533,7 @@ func TestMachineSetReconciler_reconcileUnhealthyMachines(t *testing.T) {
 		r := &Reconciler{
 			Client:                    fakeClient,
 			UnstructuredCachingClient: fakeClient,
+			recorder:                  record.NewFakeRecorder(32),
 		}
 		_, err := r.reconcileUnhealthyMachines(ctx, cluster, machineSet, machines)
 		g.Expect(err).ToNot(HaveOccurred())
@@ -1538,6 +1614,261 @@ func TestMachineSetReconciler_syncReplicas(t *testing.T) {
 		g.Expect(r.Client.List(ctx, machineList)).To(Succeed())
 		g.Expect(machineList.Items).To(BeEmpty(), "There should not be any machines")
 	})
+
+	t.Run("should hold off on creating new machines when their ordinals are in use", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cluster := &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-cluster",
+				Namespace: "default",
+			},
+		}
+		machineSet := &clusterv1.MachineSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "test-md-abcde",
+				Namespace:   "default",
+				Labels:      map[string]string{clusterv1.MachineDeploymentNameLabel: "test-md"},
+				Annotations: map[string]string{clusterv1.MaxReplicasAnnotation: "1"},
+			},
+			Spec: clusterv1.MachineSetSpec{
+				ClusterName:           "test-cluster",
+				Replicas:              pointer.Int32(1),
+				MachineNamingStrategy: &clusterv1.MachineNamingStrategy{Type: clusterv1.OrdinalMachineNamingStrategyType},
+			},
+		}
+		// The Machine of the old MachineSet, which is being replaced.
+		oldMachine := &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-md-0",
+				Namespace: "default",
+			},
+		}
+
+		fakeClient := fake.NewClientBuilder().WithObjects(machineSet, oldMachine).WithStatusSubresource(&clusterv1.MachineSet{}).Build()
+		r := &Reconciler{
+			Client:                    fakeClient,
+			UnstructuredCachingClient: fakeClient,
+		}
+		result, err := r.syncReplicas(ctx, cluster, machineSet, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.RequeueAfter).To(Equal(ordinalsInUseRequeueAfter))
+
+		// Verify no new Machines are created.
+		machineList := &clusterv1.MachineList{}
+		g.Expect(r.Client.List(ctx, machineList)).To(Succeed())
+		g.Expect(machineList.Items).To(HaveLen(1))
+	})
+}
+
+func TestMachineSetReconciler_getAvailableOrdinalMachineNames(t *testing.T) {
+	machine := func(name string) client.Object {
+		return &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		labels        map[string]string
+		annotations   map[string]string
+		replicas      int32
+		machines      []client.Object
+		expectedNames []string
+	}{
+		{
+			name:          "MachineSet without Machines",
+			replicas:      3,
+			expectedNames: []string{"test-ms-0", "test-ms-1", "test-ms-2"},
+		},
+		{
+			name:          "MachineSet with Machines",
+			replicas:      3,
+			machines:      []client.Object{machine("test-ms-1"), machine("other-ms-0")},
+			expectedNames: []string{"test-ms-0", "test-ms-2"},
+		},
+		{
+			name:          "MachineSet owned by a MachineDeployment",
+			labels:        map[string]string{clusterv1.MachineDeploymentNameLabel: "test-md"},
+			annotations:   map[string]string{clusterv1.MaxReplicasAnnotation: "3"},
+			replicas:      1,
+			machines:      []client.Object{machine("test-md-0"), machine("test-md-2")},
+			expectedNames: []string{"test-md-1"},
+		},
+		{
+			name:          "MachineSet owned by a MachineDeployment without available ordinals",
+			labels:        map[string]string{clusterv1.MachineDeploymentNameLabel: "test-md"},
+			annotations:   map[string]string{clusterv1.MaxReplicasAnnotation: "2"},
+			replicas:      1,
+			machines:      []client.Object{machine("test-md-0"), machine("test-md-1")},
+			expectedNames: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ms := &clusterv1.MachineSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-ms",
+					Namespace:   "default",
+					Labels:      tt.labels,
+					Annotations: tt.annotations,
+				},
+				Spec: clusterv1.MachineSetSpec{
+					Replicas: pointer.Int32(tt.replicas),
+				},
+			}
+			r := &Reconciler{
+				Client: fake.NewClientBuilder().WithObjects(tt.machines...).Build(),
+			}
+
+			names, err := r.getAvailableOrdinalMachineNames(ctx, ms)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(names).To(Equal(tt.expectedNames))
+		})
+	}
+}
+
+func TestMachineSetReconciler_reconcileFailureDomainRebalance(t *testing.T) {
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "default",
+		},
+		Status: clusterv1.ClusterStatus{
+			FailureDomains: clusterv1.FailureDomains{
+				"a": clusterv1.FailureDomainSpec{},
+				"b": clusterv1.FailureDomainSpec{},
+				"c": clusterv1.FailureDomainSpec{},
+			},
+		},
+	}
+	now := time.Now()
+	machineInFailureDomain := func(name, failureDomain string, age time.Duration) *clusterv1.Machine {
+		return &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "default",
+				CreationTimestamp: metav1.NewTime(now.Add(-age)),
+			},
+			Spec: clusterv1.MachineSpec{
+				FailureDomain: pointer.String(failureDomain),
+			},
+		}
+	}
+
+	tests := []struct {
+		name                      string
+		failureDomainSpreadPolicy *clusterv1.FailureDomainSpreadPolicy
+		availableReplicas         int32
+		machines                  []*clusterv1.Machine
+		expectedDeletedMachines   []string
+	}{
+		{
+			name:                      "should not delete machines with the Spread policy",
+			failureDomainSpreadPolicy: &clusterv1.FailureDomainSpreadPolicy{Type: clusterv1.SpreadFailureDomainSpreadPolicyType},
+			availableReplicas:         3,
+			machines: []*clusterv1.Machine{
+				machineInFailureDomain("m1", "a", 3*time.Hour),
+				machineInFailureDomain("m2", "a", 2*time.Hour),
+				machineInFailureDomain("m3", "b", 1*time.Hour),
+			},
+		},
+		{
+			name:                      "should not delete machines if failure domains are balanced",
+			failureDomainSpreadPolicy: &clusterv1.FailureDomainSpreadPolicy{Type: clusterv1.RebalanceFailureDomainSpreadPolicyType},
+			availableReplicas:         3,
+			machines: []*clusterv1.Machine{
+				machineInFailureDomain("m1", "a", 3*time.Hour),
+				machineInFailureDomain("m2", "b", 2*time.Hour),
+				machineInFailureDomain("m3", "c", 1*time.Hour),
+			},
+		},
+		{
+			name:                      "should delete the oldest machine in the failure domain with most machines",
+			failureDomainSpreadPolicy: &clusterv1.FailureDomainSpreadPolicy{Type: clusterv1.RebalanceFailureDomainSpreadPolicyType},
+			availableReplicas:         3,
+			machines: []*clusterv1.Machine{
+				machineInFailureDomain("m1", "a", 2*time.Hour),
+				machineInFailureDomain("m2", "a", 3*time.Hour),
+				machineInFailureDomain("m3", "b", 4*time.Hour),
+			},
+			expectedDeletedMachines: []string{"m2"},
+		},
+		{
+			name:                      "should not delete machines if machines are already unavailable",
+			failureDomainSpreadPolicy: &clusterv1.FailureDomainSpreadPolicy{Type: clusterv1.RebalanceFailureDomainSpreadPolicyType},
+			availableReplicas:         2,
+			machines: []*clusterv1.Machine{
+				machineInFailureDomain("m1", "a", 3*time.Hour),
+				machineInFailureDomain("m2", "a", 2*time.Hour),
+				machineInFailureDomain("m3", "b", 1*time.Hour),
+			},
+		},
+		{
+			name: "should delete up to maxUnavailable machines",
+			failureDomainSpreadPolicy: &clusterv1.FailureDomainSpreadPolicy{
+				Type:           clusterv1.RebalanceFailureDomainSpreadPolicyType,
+				MaxUnavailable: pointer.Int32(3),
+			},
+			availableReplicas: 6,
+			machines: []*clusterv1.Machine{
+				machineInFailureDomain("m1", "a", 6*time.Hour),
+				machineInFailureDomain("m2", "a", 5*time.Hour),
+				machineInFailureDomain("m3", "a", 4*time.Hour),
+				machineInFailureDomain("m4", "a", 3*time.Hour),
+				machineInFailureDomain("m5", "b", 2*time.Hour),
+				machineInFailureDomain("m6", "b", 1*time.Hour),
+			},
+			// Deleting two machines in failure domain a and replacing them in failure domain c restores the balance.
+			expectedDeletedMachines: []string{"m1", "m2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			machineSet := &clusterv1.MachineSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-machineset",
+					Namespace: "default",
+				},
+				Spec: clusterv1.MachineSetSpec{
+					Replicas:                  pointer.Int32(int32(len(tt.machines))),
+					FailureDomainSpreadPolicy: tt.failureDomainSpreadPolicy,
+				},
+				Status: clusterv1.MachineSetStatus{
+					AvailableReplicas: tt.availableReplicas,
+				},
+			}
+
+			objs := []client.Object{}
+			for _, m := range tt.machines {
+				objs = append(objs, m)
+			}
+			fakeClient := fake.NewClientBuilder().WithObjects(objs...).Build()
+			r := &Reconciler{
+				Client:   fakeClient,
+				recorder: record.NewFakeRecorder(32),
+			}
+			g.Expect(r.reconcileFailureDomainRebalance(ctx, cluster, machineSet, tt.machines)).To(Succeed())
+
+			machineList := &clusterv1.MachineList{}
+			g.Expect(r.Client.List(ctx, machineList)).To(Succeed())
+			remainingMachines := []string{}
+			for _, m := range machineList.Items {
+				remainingMachines = append(remainingMachines, m.Name)
+			}
+			g.Expect(remainingMachines).To(HaveLen(len(tt.machines) - len(tt.expectedDeletedMachines)))
+			for _, name := range tt.expectedDeletedMachines {
+				g.Expect(remainingMachines).ToNot(ContainElement(name))
+			}
+		})
+	}
 }
 
 func TestComputeDesiredMachine(t *testing.T) {
@@ -1589,16 +1920,40 @@ func TestComputeDesiredMachine(t *testing.T) {
 		},
 	}
 
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test-cluster",
+		},
+		Spec: clusterv1.ClusterSpec{
+			Metadata: &clusterv1.ObjectMeta{
+				Labels: map[string]string{
+					"cluster-label1":              "cluster-value1",
+					"machine-label1":              "cluster-value1",
+					clusterv1.MachineSetNameLabel: "cluster-value2",
+				},
+				Annotations: map[string]string{
+					"cluster-annotation1": "cluster-value1",
+					"machine-annotation1": "cluster-value1",
+				},
+			},
+		},
+	}
+
 	skeletonMachine := &clusterv1.Machine{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: "default",
 			Labels: map[string]string{
 				"machine-label1":                     "machine-value1",
+				"cluster-label1":                     "cluster-value1",
 				clusterv1.MachineSetNameLabel:        "ms1",
 				clusterv1.MachineDeploymentNameLabel: "md1",
 			},
-			Annotations: map[string]string{"machine-annotation1": "machine-value1"},
-			Finalizers:  []string{clusterv1.MachineFinalizer},
+			Annotations: map[string]string{
+				"machine-annotation1": "machine-value1",
+				"cluster-annotation1": "cluster-value1",
+			},
+			Finalizers: []string{clusterv1.MachineFinalizer},
 		},
 		Spec: clusterv1.MachineSpec{
 			ClusterName:             "test-cluster",
@@ -1658,7 +2013,8 @@ func TestComputeDesiredMachine(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			g := NewWithT(t)
-			got := (&Reconciler{}).computeDesiredMachine(ms, tt.existingMachine)
+			got, err := (&Reconciler{}).computeDesiredMachine(cluster, ms, tt.existingMachine)
+			g.Expect(err).ToNot(HaveOccurred())
 			assertMachine(g, got, tt.want)
 		})
 	}
@@ -35,8 +35,11 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controllers/external"
+	runtimecatalog "sigs.k8s.io/cluster-api/exp/runtime/catalog"
+	runtimehooksv1 "sigs.k8s.io/cluster-api/exp/runtime/hooks/api/v1alpha1"
 	"sigs.k8s.io/cluster-api/feature"
 	"sigs.k8s.io/cluster-api/internal/contract"
+	"sigs.k8s.io/cluster-api/util"
 )
 
 type preflightCheckErrorMessage *string
@@ -58,15 +61,48 @@ func (r *Reconciler) runPreflightChecks(ctx context.Context, cluster *clusterv1.
 		return ctrl.Result{}, "", nil
 	}
 
+	preflightCheckErrs, err := r.controlPlanePreflightChecks(ctx, cluster, ms, skipped)
+	if err != nil {
+		return ctrl.Result{}, "", errors.Wrapf(err, "failed to perform %q: failed to perform preflight checks", action)
+	}
+
+	// Run the preflight checks implemented by providers using Runtime Extensions.
+	result := ctrl.Result{}
+	if len(preflightCheckErrs) > 0 {
+		result.RequeueAfter = preflightFailedRequeueAfter
+	}
+	extensionResult, extensionPreflightCheckErr, err := r.extensionPreflightChecks(ctx, cluster, ms, action, skipped)
+	if err != nil {
+		return ctrl.Result{}, "", errors.Wrapf(err, "failed to perform %q: failed to perform preflight checks", action)
+	}
+	if extensionPreflightCheckErr != nil {
+		preflightCheckErrs = append(preflightCheckErrs, extensionPreflightCheckErr)
+		result = util.LowestNonZeroResult(result, extensionResult)
+	}
+
+	if len(preflightCheckErrs) > 0 {
+		preflightCheckErrStrings := []string{}
+		for _, v := range preflightCheckErrs {
+			preflightCheckErrStrings = append(preflightCheckErrStrings, *v)
+		}
+		msg := fmt.Sprintf("Performing %q on hold because %s. The operation will continue after the preflight check(s) pass", action, strings.Join(preflightCheckErrStrings, "; "))
+		log.Info(msg)
+		return result, msg, nil
+	}
+	return ctrl.Result{}, "", nil
+}
+
+// controlPlanePreflightChecks runs the built-in preflight checks, which verify the MachineSet against the ControlPlane.
+func (r *Reconciler) controlPlanePreflightChecks(ctx context.Context, cluster *clusterv1.Cluster, ms *clusterv1.MachineSet, skipped sets.Set[clusterv1.MachineSetPreflightCheck]) ([]preflightCheckErrorMessage, error) {
 	// If the cluster does not have a control plane reference then there is nothing to do. Return early.
 	if cluster.Spec.ControlPlaneRef == nil {
-		return ctrl.Result{}, "", nil
+		return nil, nil
 	}
 
 	// Get the control plane object.
 	controlPlane, err := external.Get(ctx, r.UnstructuredCachingClient, cluster.Spec.ControlPlaneRef, cluster.Namespace)
 	if err != nil {
-		return ctrl.Result{}, "", errors.Wrapf(err, "failed to perform %q: failed to perform preflight checks: failed to get ControlPlane %s", action, klog.KRef(cluster.Spec.ControlPlaneRef.Namespace, cluster.Spec.ControlPlaneRef.Name))
+		return nil, errors.Wrapf(err, "failed to get ControlPlane %s", klog.KRef(cluster.Spec.ControlPlaneRef.Namespace, cluster.Spec.ControlPlaneRef.Name))
 	}
 	cpKlogRef := klog.KRef(controlPlane.GetNamespace(), controlPlane.GetName())
 
@@ -76,13 +112,13 @@ func (r *Reconciler) runPreflightChecks(ctx context.Context, cluster *clusterv1.
 	cpVersion, err := contract.ControlPlane().Version().Get(controlPlane)
 	if err != nil {
 		if errors.Is(err, contract.ErrFieldNotFound) {
-			return ctrl.Result{}, "", nil
+			return nil, nil
 		}
-		return ctrl.Result{}, "", errors.Wrapf(err, "failed to perform %q: failed to perform preflight checks: failed to get the version of ControlPlane %s", action, cpKlogRef)
+		return nil, errors.Wrapf(err, "failed to get the version of ControlPlane %s", cpKlogRef)
 	}
 	cpSemver, err := semver.ParseTolerant(*cpVersion)
 	if err != nil {
-		return ctrl.Result{}, "", errors.Wrapf(err, "failed to perform %q: failed to perform preflight checks: failed to parse version %q of ControlPlane %s", action, *cpVersion, cpKlogRef)
+		return nil, errors.Wrapf(err, "failed to parse version %q of ControlPlane %s", *cpVersion, cpKlogRef)
 	}
 
 	errList := []error{}
@@ -103,7 +139,7 @@ func (r *Reconciler) runPreflightChecks(ctx context.Context, cluster *clusterv1.
 		msVersion := *ms.Spec.Template.Spec.Version
 		msSemver, err := semver.ParseTolerant(msVersion)
 		if err != nil {
-			return ctrl.Result{}, "", errors.Wrapf(err, "failed to perform %q: failed to perform preflight checks: failed to parse version %q of MachineSet %s", action, msVersion, klog.KObj(ms))
+			return nil, errors.Wrapf(err, "failed to parse version %q of MachineSet %s", msVersion, klog.KObj(ms))
 		}
 
 		// Run the kubernetes-version skew preflight check.
@@ -127,18 +163,39 @@ func (r *Reconciler) runPreflightChecks(ctx context.Context, cluster *clusterv1.
 	}
 
 	if len(errList) > 0 {
-		return ctrl.Result{}, "", errors.Wrapf(kerrors.NewAggregate(errList), "failed to perform %q: failed to perform preflight checks", action)
+		return nil, kerrors.NewAggregate(errList)
 	}
-	if len(preflightCheckErrs) > 0 {
-		preflightCheckErrStrings := []string{}
-		for _, v := range preflightCheckErrs {
-			preflightCheckErrStrings = append(preflightCheckErrStrings, *v)
+	return preflightCheckErrs, nil
+}
+
+// extensionPreflightChecks calls the MachineSetPreflightCheck hook, so providers can implement additional preflight checks.
+func (r *Reconciler) extensionPreflightChecks(ctx context.Context, cluster *clusterv1.Cluster, ms *clusterv1.MachineSet, action string, skipped sets.Set[clusterv1.MachineSetPreflightCheck]) (ctrl.Result, preflightCheckErrorMessage, error) {
+	if !feature.Gates.Enabled(feature.RuntimeSDK) || r.RuntimeClient == nil {
+		return ctrl.Result{}, nil, nil
+	}
+
+	skippedProviderChecks := []string{}
+	for _, check := range sets.List(skipped) {
+		if check.IsProviderDefined() {
+			skippedProviderChecks = append(skippedProviderChecks, string(check))
 		}
-		msg := fmt.Sprintf("Performing %q on hold because %s. The operation will continue after the preflight check(s) pass", action, strings.Join(preflightCheckErrStrings, "; "))
-		log.Info(msg)
-		return ctrl.Result{RequeueAfter: preflightFailedRequeueAfter}, msg, nil
 	}
-	return ctrl.Result{}, "", nil
+
+	hookRequest := &runtimehooksv1.MachineSetPreflightCheckRequest{
+		Cluster:                *cluster,
+		MachineSet:             *ms,
+		Action:                 action,
+		SkippedPreflightChecks: skippedProviderChecks,
+	}
+	hookResponse := &runtimehooksv1.MachineSetPreflightCheckResponse{}
+	if err := r.RuntimeClient.CallAllExtensions(ctx, runtimehooksv1.MachineSetPreflightCheck, ms, hookRequest, hookResponse); err != nil {
+		return ctrl.Result{}, nil, err
+	}
+	if hookResponse.RetryAfterSeconds == 0 {
+		return ctrl.Result{}, nil, nil
+	}
+	return ctrl.Result{RequeueAfter: time.Duration(hookResponse.RetryAfterSeconds) * time.Second},
+		pointer.String(fmt.Sprintf("%s (%q preflight failed)", hookResponse.Message, runtimecatalog.HookName(runtimehooksv1.MachineSetPreflightCheck))), nil
 }
 
 func (r *Reconciler) controlPlaneStablePreflightCheck(controlPlane *unstructured.Unstructured) (preflightCheckErrorMessage, error) {
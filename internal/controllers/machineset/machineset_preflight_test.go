@@ -18,6 +18,7 @@ package machineset
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
@@ -25,13 +26,17 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	utilfeature "k8s.io/component-base/featuregate/testing"
 	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	runtimecatalog "sigs.k8s.io/cluster-api/exp/runtime/catalog"
+	runtimehooksv1 "sigs.k8s.io/cluster-api/exp/runtime/hooks/api/v1alpha1"
 	"sigs.k8s.io/cluster-api/feature"
 	"sigs.k8s.io/cluster-api/internal/contract"
+	fakeruntimeclient "sigs.k8s.io/cluster-api/internal/runtime/client/fake"
 	"sigs.k8s.io/cluster-api/internal/test/builder"
 )
 
@@ -549,3 +554,121 @@ func TestMachineSetReconciler_runPreflightChecks(t *testing.T) {
 		g.Expect(result.IsZero()).To(BeTrue())
 	})
 }
+
+func TestMachineSetReconciler_runExtensionPreflightChecks(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.MachineSetPreflightChecks, true)()
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.RuntimeSDK, true)()
+
+	catalog := runtimecatalog.New()
+	_ = runtimehooksv1.AddToCatalog(catalog)
+	preflightCheckGVH, err := catalog.GroupVersionHook(runtimehooksv1.MachineSetPreflightCheck)
+	if err != nil {
+		panic("unable to compute GVH")
+	}
+
+	cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "cluster"}}
+	machineSet := func(skip string) *clusterv1.MachineSet {
+		ms := &clusterv1.MachineSet{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "ms"}}
+		if skip != "" {
+			ms.Annotations = map[string]string{clusterv1.MachineSetSkipPreflightChecksAnnotation: skip}
+		}
+		return ms
+	}
+
+	tests := []struct {
+		name         string
+		machineSet   *clusterv1.MachineSet
+		hookResponse *runtimehooksv1.MachineSetPreflightCheckResponse
+		wantCalls    int
+		wantResult   ctrl.Result
+		wantMessage  bool
+		wantErr      bool
+	}{
+		{
+			name:       "should pass if the extensions pass",
+			machineSet: machineSet(""),
+			hookResponse: &runtimehooksv1.MachineSetPreflightCheckResponse{
+				CommonRetryResponse: runtimehooksv1.CommonRetryResponse{
+					CommonResponse: runtimehooksv1.CommonResponse{Status: runtimehooksv1.ResponseStatusSuccess},
+				},
+			},
+			wantCalls:  1,
+			wantResult: ctrl.Result{},
+		},
+		{
+			name:       "should fail if an extension asks to retry",
+			machineSet: machineSet(""),
+			hookResponse: &runtimehooksv1.MachineSetPreflightCheckResponse{
+				CommonRetryResponse: runtimehooksv1.CommonRetryResponse{
+					CommonResponse:    runtimehooksv1.CommonResponse{Status: runtimehooksv1.ResponseStatusSuccess, Message: "capacity not available"},
+					RetryAfterSeconds: 30,
+				},
+			},
+			wantCalls:   1,
+			wantResult:  ctrl.Result{RequeueAfter: 30 * time.Second},
+			wantMessage: true,
+		},
+		{
+			name:       "should return an error if an extension fails",
+			machineSet: machineSet(""),
+			hookResponse: &runtimehooksv1.MachineSetPreflightCheckResponse{
+				CommonRetryResponse: runtimehooksv1.CommonRetryResponse{
+					CommonResponse: runtimehooksv1.CommonResponse{Status: runtimehooksv1.ResponseStatusFailure},
+				},
+			},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			name:       "should not call the extensions if all the preflight checks are skipped",
+			machineSet: machineSet(string(clusterv1.MachineSetPreflightCheckAll)),
+			wantCalls:  0,
+			wantResult: ctrl.Result{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			runtimeClient := fakeruntimeclient.NewRuntimeClientBuilder().
+				WithCatalog(catalog).
+				WithCallAllExtensionResponses(map[runtimecatalog.GroupVersionHook]runtimehooksv1.ResponseObject{
+					preflightCheckGVH: tt.hookResponse,
+				}).
+				Build()
+			fakeClient := fake.NewClientBuilder().Build()
+			r := &Reconciler{
+				Client:                    fakeClient,
+				UnstructuredCachingClient: fakeClient,
+				RuntimeClient:             runtimeClient,
+			}
+
+			result, message, err := r.runPreflightChecks(ctx, cluster, tt.machineSet, "Scale up")
+			g.Expect(runtimeClient.CallAllCount(runtimehooksv1.MachineSetPreflightCheck)).To(Equal(tt.wantCalls))
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(result).To(Equal(tt.wantResult))
+			if tt.wantMessage {
+				g.Expect(message).To(ContainSubstring(tt.hookResponse.Message))
+			} else {
+				g.Expect(message).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestMachineSetReconciler_extensionPreflightChecksSkipped(t *testing.T) {
+	g := NewWithT(t)
+
+	skipped := skippedPreflightChecks(&clusterv1.MachineSet{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+		clusterv1.MachineSetSkipPreflightChecksAnnotation: "KubeadmVersionSkew, infrastructure.example.com/CapacityAvailable",
+	}}})
+	g.Expect(skipped.Has(clusterv1.MachineSetPreflightCheckKubeadmVersionSkew)).To(BeTrue())
+	g.Expect(skipped.Has("infrastructure.example.com/CapacityAvailable")).To(BeTrue())
+	g.Expect(clusterv1.MachineSetPreflightCheck("infrastructure.example.com/CapacityAvailable").IsProviderDefined()).To(BeTrue())
+	g.Expect(clusterv1.MachineSetPreflightCheckKubeadmVersionSkew.IsProviderDefined()).To(BeFalse())
+}
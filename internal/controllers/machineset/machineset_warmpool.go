@@ -0,0 +1,156 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machineset
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/external"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinewarmpools,verbs=get;list;watch
+
+// getWarmPoolInfraMachines returns the pre-provisioned InfraMachines which can be claimed by the MachineSet, i.e. the
+// InfraMachines in the MachineWarmPools with the same Cluster, infrastructure template and version of the MachineSet.
+// InfraMachines are sorted by creation timestamp, so the oldest ones are claimed first.
+func (r *Reconciler) getWarmPoolInfraMachines(ctx context.Context, ms *clusterv1.MachineSet) ([]*unstructured.Unstructured, error) {
+	if !feature.Gates.Enabled(feature.MachineWarmPool) {
+		return nil, nil
+	}
+
+	warmPoolList := &expv1.MachineWarmPoolList{}
+	if err := r.Client.List(ctx, warmPoolList, client.InNamespace(ms.Namespace), client.MatchingLabels{clusterv1.ClusterNameLabel: ms.Spec.ClusterName}); err != nil {
+		return nil, errors.Wrap(err, "failed to list MachineWarmPools")
+	}
+	warmPoolNames := sets.Set[string]{}
+	for i := range warmPoolList.Items {
+		warmPool := &warmPoolList.Items[i]
+		if warmPool.Spec.ClusterName != ms.Spec.ClusterName || !warmPool.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if !sameTemplateRef(warmPool.Spec.Template.InfrastructureRef, ms.Spec.Template.Spec.InfrastructureRef) ||
+			pointer.StringDeref(warmPool.Spec.Template.Version, "") != pointer.StringDeref(ms.Spec.Template.Spec.Version, "") {
+			continue
+		}
+		warmPoolNames.Insert(warmPool.Name)
+	}
+	if warmPoolNames.Len() == 0 {
+		return nil, nil
+	}
+
+	// The InfraMachines have the kind of the infrastructure machine template without the Template suffix.
+	gvk := ms.Spec.Template.Spec.InfrastructureRef.GroupVersionKind()
+	gvk.Kind = strings.TrimSuffix(gvk.Kind, clusterv1.TemplateSuffix)
+	infraMachineList := &unstructured.UnstructuredList{}
+	infraMachineList.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err := r.UnstructuredCachingClient.List(ctx, infraMachineList, client.InNamespace(ms.Namespace), client.HasLabels{expv1.MachineWarmPoolNameLabel}); err != nil {
+		return nil, errors.Wrapf(err, "failed to list %s in MachineWarmPools", gvk.Kind)
+	}
+
+	var infraMachines []*unstructured.Unstructured
+	for i := range infraMachineList.Items {
+		infraMachine := &infraMachineList.Items[i]
+		if !warmPoolNames.Has(infraMachine.GetLabels()[expv1.MachineWarmPoolNameLabel]) || !infraMachine.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+		if !conditions.IsTrue(conditions.UnstructuredGetter(infraMachine), expv1.PreProvisionedCondition) {
+			continue
+		}
+		infraMachines = append(infraMachines, infraMachine)
+	}
+	sort.SliceStable(infraMachines, func(i, j int) bool {
+		iCreationTimestamp, jCreationTimestamp := infraMachines[i].GetCreationTimestamp(), infraMachines[j].GetCreationTimestamp()
+		return iCreationTimestamp.Before(&jCreationTimestamp)
+	})
+	return infraMachines, nil
+}
+
+// claimWarmPoolInfraMachine claims the first of the given pre-provisioned InfraMachines which is not claimed concurrently
+// by someone else, moving it from its MachineWarmPool to the MachineSet; claimed InfraMachines and InfraMachines which
+// failed to be claimed are removed from the slice.
+// It returns nil if there are no InfraMachines left to claim.
+func (r *Reconciler) claimWarmPoolInfraMachine(ctx context.Context, ms *clusterv1.MachineSet, machine *clusterv1.Machine, infraMachines *[]*unstructured.Unstructured) *corev1.ObjectReference {
+	log := ctrl.LoggerFrom(ctx)
+
+	for len(*infraMachines) > 0 {
+		infraMachine := (*infraMachines)[0]
+		*infraMachines = (*infraMachines)[1:]
+
+		// NOTE: The optimistic lock ensures the InfraMachine is not claimed concurrently by another MachineSet,
+		// nor deleted by the MachineWarmPool while scaling down.
+		patch := client.MergeFromWithOptions(infraMachine.DeepCopy(), client.MergeFromWithOptimisticLock{})
+
+		warmPoolName := infraMachine.GetLabels()[expv1.MachineWarmPoolNameLabel]
+		labels := infraMachine.GetLabels()
+		delete(labels, expv1.MachineWarmPoolNameLabel)
+		for k, v := range machine.Labels {
+			labels[k] = v
+		}
+		infraMachine.SetLabels(labels)
+
+		annotations := infraMachine.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		for k, v := range machine.Annotations {
+			annotations[k] = v
+		}
+		infraMachine.SetAnnotations(annotations)
+
+		ownerRefs := util.RemoveOwnerRef(infraMachine.GetOwnerReferences(), metav1.OwnerReference{
+			APIVersion: expv1.GroupVersion.String(),
+			Kind:       "MachineWarmPool",
+			Name:       warmPoolName,
+		})
+		infraMachine.SetOwnerReferences(util.EnsureOwnerRef(ownerRefs, metav1.OwnerReference{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "MachineSet",
+			Name:       ms.Name,
+			UID:        ms.UID,
+		}))
+
+		if err := r.UnstructuredCachingClient.Patch(ctx, infraMachine, patch); err != nil {
+			log.V(4).Info("Failed to claim pre-provisioned infrastructure machine", infraMachine.GetKind(), klog.KObj(infraMachine), "err", err.Error())
+			continue
+		}
+		log.Info("Claimed pre-provisioned infrastructure machine", infraMachine.GetKind(), klog.KObj(infraMachine), "MachineWarmPool", klog.KRef(infraMachine.GetNamespace(), warmPoolName))
+		return external.GetObjectReference(infraMachine)
+	}
+	return nil
+}
+
+// sameTemplateRef returns true if the two references point to the same template, ignoring the API version.
+func sameTemplateRef(a, b corev1.ObjectReference) bool {
+	return a.GroupVersionKind().GroupKind() == b.GroupVersionKind().GroupKind() && a.Name == b.Name
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machineset
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	utilfeature "k8s.io/component-base/featuregate/testing"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/internal/test/builder"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestMachineSetReconciler_claimWarmPoolInfraMachine(t *testing.T) {
+	infraRef := corev1.ObjectReference{
+		APIVersion: builder.InfrastructureGroupVersion.String(),
+		Kind:       builder.GenericInfrastructureMachineTemplateKind,
+		Name:       "infra-template",
+		Namespace:  metav1.NamespaceDefault,
+	}
+	machineSet := &clusterv1.MachineSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-machineset",
+			Namespace: metav1.NamespaceDefault,
+			UID:       "test-machineset-uid",
+		},
+		Spec: clusterv1.MachineSetSpec{
+			ClusterName: "test-cluster",
+			Replicas:    pointer.Int32(2),
+			Template: clusterv1.MachineTemplateSpec{
+				Spec: clusterv1.MachineSpec{
+					ClusterName:       "test-cluster",
+					Version:           pointer.String("v1.28.0"),
+					InfrastructureRef: infraRef,
+				},
+			},
+		},
+	}
+	warmPool := func(name, version string) *expv1.MachineWarmPool {
+		return &expv1.MachineWarmPool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: metav1.NamespaceDefault,
+				Labels:    map[string]string{clusterv1.ClusterNameLabel: "test-cluster"},
+				UID:       types.UID(name + "-uid"),
+			},
+			Spec: expv1.MachineWarmPoolSpec{
+				ClusterName: "test-cluster",
+				Template: expv1.MachineWarmPoolTemplate{
+					Version:           pointer.String(version),
+					InfrastructureRef: infraRef,
+				},
+			},
+		}
+	}
+	infraMachine := func(name string, warmPool *expv1.MachineWarmPool, preProvisioned bool) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(builder.InfrastructureGroupVersion.String())
+		obj.SetKind(builder.GenericInfrastructureMachineKind)
+		obj.SetName(name)
+		obj.SetNamespace(metav1.NamespaceDefault)
+		obj.SetLabels(map[string]string{
+			clusterv1.ClusterNameLabel:     "test-cluster",
+			expv1.MachineWarmPoolNameLabel: warmPool.Name,
+		})
+		obj.SetOwnerReferences([]metav1.OwnerReference{{
+			APIVersion: expv1.GroupVersion.String(),
+			Kind:       "MachineWarmPool",
+			Name:       warmPool.Name,
+			UID:        warmPool.UID,
+		}})
+		if preProvisioned {
+			conditions.MarkTrue(conditions.UnstructuredSetter(obj), expv1.PreProvisionedCondition)
+		}
+		return obj
+	}
+
+	matchingPool := warmPool("matching-pool", "v1.28.0")
+	otherVersionPool := warmPool("other-version-pool", "v1.27.0")
+	objs := []client.Object{
+		matchingPool,
+		otherVersionPool,
+		infraMachine("matching-pool-ready", matchingPool, true),
+		infraMachine("matching-pool-not-ready", matchingPool, false),
+		infraMachine("other-version-pool-ready", otherVersionPool, true),
+	}
+
+	t.Run("should not return InfraMachines when the feature gate is disabled", func(t *testing.T) {
+		g := NewWithT(t)
+
+		fakeClient := fake.NewClientBuilder().WithObjects(objs...).Build()
+		r := &Reconciler{
+			Client:                    fakeClient,
+			UnstructuredCachingClient: fakeClient,
+		}
+		infraMachines, err := r.getWarmPoolInfraMachines(ctx, machineSet)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(infraMachines).To(BeEmpty())
+	})
+
+	t.Run("should claim pre-provisioned InfraMachines from MachineWarmPools matching the MachineSet", func(t *testing.T) {
+		defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.MachineWarmPool, true)()
+		g := NewWithT(t)
+
+		fakeClient := fake.NewClientBuilder().WithObjects(objs...).Build()
+		r := &Reconciler{
+			Client:                    fakeClient,
+			UnstructuredCachingClient: fakeClient,
+		}
+		infraMachines, err := r.getWarmPoolInfraMachines(ctx, machineSet)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(infraMachines).To(HaveLen(1))
+		g.Expect(infraMachines[0].GetName()).To(Equal("matching-pool-ready"))
+
+		machine := &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels:      map[string]string{clusterv1.MachineSetNameLabel: machineSet.Name},
+				Annotations: map[string]string{"foo": "bar"},
+			},
+		}
+		infraRef := r.claimWarmPoolInfraMachine(ctx, machineSet, machine, &infraMachines)
+		g.Expect(infraRef).ToNot(BeNil())
+		g.Expect(infraRef.Name).To(Equal("matching-pool-ready"))
+		g.Expect(infraMachines).To(BeEmpty())
+
+		claimed := &unstructured.Unstructured{}
+		claimed.SetAPIVersion(builder.InfrastructureGroupVersion.String())
+		claimed.SetKind(builder.GenericInfrastructureMachineKind)
+		g.Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: infraRef.Name}, claimed)).To(Succeed())
+		g.Expect(claimed.GetLabels()).ToNot(HaveKey(expv1.MachineWarmPoolNameLabel))
+		g.Expect(claimed.GetLabels()).To(HaveKeyWithValue(clusterv1.MachineSetNameLabel, machineSet.Name))
+		g.Expect(claimed.GetAnnotations()).To(HaveKeyWithValue("foo", "bar"))
+		g.Expect(claimed.GetOwnerReferences()).To(HaveLen(1))
+		g.Expect(claimed.GetOwnerReferences()[0].Kind).To(Equal("MachineSet"))
+		g.Expect(claimed.GetOwnerReferences()[0].Name).To(Equal(machineSet.Name))
+
+		// There are no pre-provisioned InfraMachines left.
+		g.Expect(r.claimWarmPoolInfraMachine(ctx, machineSet, machine, &infraMachines)).To(BeNil())
+	})
+
+	t.Run("should skip InfraMachines which are changed concurrently", func(t *testing.T) {
+		defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.MachineWarmPool, true)()
+		g := NewWithT(t)
+
+		fakeClient := fake.NewClientBuilder().WithObjects(objs...).Build()
+		r := &Reconciler{
+			Client:                    fakeClient,
+			UnstructuredCachingClient: fakeClient,
+		}
+		infraMachines, err := r.getWarmPoolInfraMachines(ctx, machineSet)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(infraMachines).To(HaveLen(1))
+
+		// Simulate the InfraMachine being claimed by someone else.
+		stale := infraMachines[0].DeepCopy()
+		g.Expect(fakeClient.Update(ctx, stale)).To(Succeed())
+
+		g.Expect(r.claimWarmPoolInfraMachine(ctx, machineSet, &clusterv1.Machine{}, &infraMachines)).To(BeNil())
+		g.Expect(infraMachines).To(BeEmpty())
+	})
+}
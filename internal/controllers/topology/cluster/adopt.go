@@ -0,0 +1,195 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/contract"
+	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/scope"
+	tlog "sigs.k8s.io/cluster-api/internal/log"
+	"sigs.k8s.io/cluster-api/util/labels"
+	"sigs.k8s.io/cluster-api/util/patch"
+)
+
+const adoptEventReason = "TopologyAdopt"
+
+// reconcileAdoption adopts the existing objects of a Cluster attached to a ClusterClass using the
+// ClusterTopologyAdoptAnnotation, by adding the labels used by the topology controller to identify the objects
+// it manages; this way the current state of the Cluster includes the existing objects instead of requiring
+// new objects to be created.
+// It returns true when all the objects are adopted and the annotation has been removed from the Cluster.
+// NOTE: The annotation is removed only when all the objects read from the cache are already adopted, so it is
+// guaranteed that the following reads of the current state are not missing any of the adopted objects.
+func (r *Reconciler) reconcileAdoption(ctx context.Context, s *scope.Scope) (bool, error) {
+	log := tlog.LoggerFrom(ctx)
+	cluster := s.Current.Cluster
+
+	objs, err := r.getObjectsToAdopt(ctx, s)
+	if err != nil {
+		return false, err
+	}
+
+	adopting := false
+	for _, obj := range objs {
+		if isAdopted(obj.object, obj.mdTopologyName) {
+			continue
+		}
+		adopting = true
+
+		log.Infof("Adopting %s", tlog.KObj{Obj: obj.object})
+		patchHelper, err := patch.NewHelper(obj.object, r.Client)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to create patch helper for %s", tlog.KObj{Obj: obj.object})
+		}
+		objLabels := obj.object.GetLabels()
+		if objLabels == nil {
+			objLabels = map[string]string{}
+		}
+		objLabels[clusterv1.ClusterTopologyOwnedLabel] = ""
+		if obj.mdTopologyName != "" {
+			objLabels[clusterv1.ClusterTopologyMachineDeploymentNameLabel] = obj.mdTopologyName
+		}
+		obj.object.SetLabels(objLabels)
+		if err := patchHelper.Patch(ctx, obj.object); err != nil {
+			return false, errors.Wrapf(err, "failed to adopt %s", tlog.KObj{Obj: obj.object})
+		}
+		r.recorder.Eventf(cluster, corev1.EventTypeNormal, adoptEventReason, "Adopted %q", tlog.KObj{Obj: obj.object})
+	}
+
+	// Wait for the adopted objects to be read from the cache before completing the adoption.
+	if adopting {
+		return false, nil
+	}
+
+	log.Infof("Adoption of the existing objects of the Cluster completed")
+	delete(cluster.Annotations, clusterv1.ClusterTopologyAdoptAnnotation)
+	return true, nil
+}
+
+// objectToAdopt is an existing object of a Cluster which should be adopted by the topology controller.
+type objectToAdopt struct {
+	object client.Object
+
+	// mdTopologyName is the name of the MachineDeployment topology a MachineDeployment is adopted by.
+	mdTopologyName string
+}
+
+// getObjectsToAdopt returns the existing objects of a Cluster which should be adopted by the topology controller:
+// the InfrastructureCluster, the ControlPlane with its InfrastructureMachineTemplate and the MachineDeployments
+// with the same name of a MachineDeployment topology, with their templates.
+// MachineDeployments without a corresponding MachineDeployment topology are left unmanaged.
+func (r *Reconciler) getObjectsToAdopt(ctx context.Context, s *scope.Scope) ([]objectToAdopt, error) {
+	cluster := s.Current.Cluster
+	objs := []objectToAdopt{}
+
+	if cluster.Spec.InfrastructureRef != nil {
+		infra, err := r.getAlignedReference(ctx, s.Blueprint.InfrastructureClusterTemplate, cluster.Spec.InfrastructureRef)
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, objectToAdopt{object: infra})
+	}
+
+	if cluster.Spec.ControlPlaneRef != nil {
+		controlPlane, err := r.getAlignedReference(ctx, s.Blueprint.ControlPlane.Template, cluster.Spec.ControlPlaneRef)
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, objectToAdopt{object: controlPlane})
+
+		if s.Blueprint.HasControlPlaneInfrastructureMachine() {
+			machineInfrastructureRef, err := contract.ControlPlane().MachineTemplate().InfrastructureRef().Get(controlPlane)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to get InfrastructureMachineTemplate reference for %s", tlog.KObj{Obj: controlPlane})
+			}
+			infraMachineTemplate, err := r.getAlignedReference(ctx, s.Blueprint.ControlPlane.InfrastructureMachineTemplate, machineInfrastructureRef)
+			if err != nil {
+				return nil, err
+			}
+			objs = append(objs, objectToAdopt{object: infraMachineTemplate})
+		}
+	}
+
+	mds := &clusterv1.MachineDeploymentList{}
+	if err := r.Client.List(ctx, mds,
+		client.MatchingLabels{clusterv1.ClusterNameLabel: cluster.Name},
+		client.InNamespace(cluster.Namespace),
+	); err != nil {
+		return nil, errors.Wrap(err, "failed to read MachineDeployments to adopt")
+	}
+	for i := range mds.Items {
+		md := &mds.Items[i]
+
+		mdTopologyName := md.Name
+		if name, ok := md.Labels[clusterv1.ClusterTopologyMachineDeploymentNameLabel]; ok && name != "" {
+			mdTopologyName = name
+		}
+		mdTopologyExistsInCluster, mdClassName := getMDClassName(cluster, mdTopologyName)
+		if !mdTopologyExistsInCluster {
+			continue
+		}
+		mdBlueprint, ok := s.Blueprint.MachineDeployments[mdClassName]
+		if !ok {
+			return nil, fmt.Errorf("failed to find MachineDeployment class %s in ClusterClass", mdClassName)
+		}
+		objs = append(objs, objectToAdopt{object: md, mdTopologyName: mdTopologyName})
+
+		if md.Spec.Template.Spec.Bootstrap.ConfigRef == nil {
+			return nil, fmt.Errorf("%s does not have a reference to a Bootstrap Config", tlog.KObj{Obj: md})
+		}
+		bootstrapTemplate, err := r.getAlignedReference(ctx, mdBlueprint.BootstrapTemplate, md.Spec.Template.Spec.Bootstrap.ConfigRef)
+		if err != nil {
+			return nil, err
+		}
+		infraMachineTemplate, err := r.getAlignedReference(ctx, mdBlueprint.InfrastructureMachineTemplate, &md.Spec.Template.Spec.InfrastructureRef)
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, objectToAdopt{object: bootstrapTemplate}, objectToAdopt{object: infraMachineTemplate})
+	}
+
+	return objs, nil
+}
+
+// getAlignedReference gets the object referenced in ref, using the apiVersion of the corresponding template in the ClusterClass.
+func (r *Reconciler) getAlignedReference(ctx context.Context, templateFromClusterClass *unstructured.Unstructured, ref *corev1.ObjectReference) (*unstructured.Unstructured, error) {
+	alignedRef, err := alignRefAPIVersion(templateFromClusterClass, ref)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s", tlog.KRef{Ref: ref})
+	}
+	obj, err := r.getReference(ctx, alignedRef)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s", tlog.KRef{Ref: ref})
+	}
+	return obj, nil
+}
+
+// isAdopted returns true if an object already has the labels set on adopted objects.
+func isAdopted(obj client.Object, mdTopologyName string) bool {
+	if !labels.IsTopologyOwned(obj) {
+		return false
+	}
+	return mdTopologyName == "" || obj.GetLabels()[clusterv1.ClusterTopologyMachineDeploymentNameLabel] == mdTopologyName
+}
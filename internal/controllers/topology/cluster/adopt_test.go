@@ -0,0 +1,174 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/scope"
+	"sigs.k8s.io/cluster-api/internal/test/builder"
+)
+
+func TestReconcileAdoption(t *testing.T) {
+	g := NewWithT(t)
+
+	infraClusterTemplate := builder.InfrastructureClusterTemplate(metav1.NamespaceDefault, "infraTemplateOne").
+		Build()
+	infraCluster := builder.InfrastructureCluster(metav1.NamespaceDefault, "infraOne").
+		Build()
+	controlPlaneInfrastructureMachineTemplate := builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "cpInfraTemplate").
+		Build()
+	controlPlaneTemplate := builder.ControlPlaneTemplate(metav1.NamespaceDefault, "cpTemplate").
+		WithInfrastructureMachineTemplate(controlPlaneInfrastructureMachineTemplate).
+		Build()
+	controlPlane := builder.ControlPlane(metav1.NamespaceDefault, "cp1").
+		WithInfrastructureMachineTemplate(controlPlaneInfrastructureMachineTemplate).
+		Build()
+	clusterClass := builder.ClusterClass(metav1.NamespaceDefault, "class1").
+		WithInfrastructureClusterTemplate(infraClusterTemplate).
+		WithControlPlaneTemplate(controlPlaneTemplate).
+		WithControlPlaneInfrastructureMachineTemplate(controlPlaneInfrastructureMachineTemplate).
+		Build()
+
+	machineDeploymentInfrastructure := builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "infra1").
+		Build()
+	machineDeploymentBootstrap := builder.BootstrapTemplate(metav1.NamespaceDefault, "bootstrap1").
+		Build()
+	machineDeployment := builder.MachineDeployment(metav1.NamespaceDefault, "md1").
+		WithLabels(map[string]string{clusterv1.ClusterNameLabel: "cluster1"}).
+		WithBootstrapTemplate(machineDeploymentBootstrap).
+		WithInfrastructureTemplate(machineDeploymentInfrastructure).
+		Build()
+	unmanagedMachineDeploymentInfrastructure := builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "infra2").
+		Build()
+	unmanagedMachineDeploymentBootstrap := builder.BootstrapTemplate(metav1.NamespaceDefault, "bootstrap2").
+		Build()
+	unmanagedMachineDeployment := builder.MachineDeployment(metav1.NamespaceDefault, "md2").
+		WithLabels(map[string]string{clusterv1.ClusterNameLabel: "cluster1"}).
+		WithBootstrapTemplate(unmanagedMachineDeploymentBootstrap).
+		WithInfrastructureTemplate(unmanagedMachineDeploymentInfrastructure).
+		Build()
+
+	cluster := builder.Cluster(metav1.NamespaceDefault, "cluster1").
+		WithAnnotations(map[string]string{clusterv1.ClusterTopologyAdoptAnnotation: ""}).
+		WithInfrastructureCluster(infraCluster).
+		WithControlPlane(controlPlane).
+		WithTopology(builder.ClusterTopology().
+			WithClass("class1").
+			WithMachineDeployment(clusterv1.MachineDeploymentTopology{
+				Class: "mdClass",
+				Name:  "md1",
+			}).
+			Build()).
+		Build()
+
+	blueprint := &scope.ClusterBlueprint{
+		ClusterClass:                  clusterClass,
+		InfrastructureClusterTemplate: infraClusterTemplate,
+		ControlPlane: &scope.ControlPlaneBlueprint{
+			Template:                      controlPlaneTemplate,
+			InfrastructureMachineTemplate: controlPlaneInfrastructureMachineTemplate,
+		},
+		MachineDeployments: map[string]*scope.MachineDeploymentBlueprint{
+			"mdClass": {
+				BootstrapTemplate:             machineDeploymentBootstrap,
+				InfrastructureMachineTemplate: machineDeploymentInfrastructure,
+			},
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(fakeScheme).
+		WithObjects(
+			builder.GenericControlPlaneCRD,
+			builder.GenericInfrastructureClusterCRD,
+			builder.GenericControlPlaneTemplateCRD,
+			builder.GenericInfrastructureClusterTemplateCRD,
+			builder.GenericBootstrapConfigTemplateCRD,
+			builder.GenericInfrastructureMachineTemplateCRD,
+			cluster,
+			clusterClass,
+			infraCluster,
+			controlPlane,
+			controlPlaneInfrastructureMachineTemplate,
+			machineDeployment,
+			machineDeploymentInfrastructure,
+			machineDeploymentBootstrap,
+			unmanagedMachineDeployment,
+			unmanagedMachineDeploymentInfrastructure,
+			unmanagedMachineDeploymentBootstrap,
+		).
+		Build()
+
+	r := &Reconciler{
+		Client:                    fakeClient,
+		APIReader:                 fakeClient,
+		UnstructuredCachingClient: fakeClient,
+		recorder:                  record.NewFakeRecorder(32),
+	}
+
+	s := scope.New(cluster)
+	s.Blueprint = blueprint
+
+	// The first reconcile adopts the objects, and waits for them to be read as adopted before completing.
+	adopted, err := r.reconcileAdoption(ctx, s)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(adopted).To(BeFalse())
+	g.Expect(s.Current.Cluster.Annotations).To(HaveKey(clusterv1.ClusterTopologyAdoptAnnotation))
+
+	for _, obj := range []client.Object{infraCluster, controlPlane, controlPlaneInfrastructureMachineTemplate, machineDeploymentInfrastructure, machineDeploymentBootstrap} {
+		got := &unstructured.Unstructured{}
+		got.SetGroupVersionKind(obj.GetObjectKind().GroupVersionKind())
+		g.Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(obj), got)).To(Succeed())
+		g.Expect(got.GetLabels()).To(HaveKey(clusterv1.ClusterTopologyOwnedLabel), "%s should be adopted", obj.GetName())
+	}
+	for _, obj := range []client.Object{unmanagedMachineDeploymentInfrastructure, unmanagedMachineDeploymentBootstrap} {
+		got := &unstructured.Unstructured{}
+		got.SetGroupVersionKind(obj.GetObjectKind().GroupVersionKind())
+		g.Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(obj), got)).To(Succeed())
+		g.Expect(got.GetLabels()).ToNot(HaveKey(clusterv1.ClusterTopologyOwnedLabel), "%s should not be adopted", obj.GetName())
+	}
+
+	gotMD := &clusterv1.MachineDeployment{}
+	g.Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(machineDeployment), gotMD)).To(Succeed())
+	g.Expect(gotMD.Labels).To(HaveKeyWithValue(clusterv1.ClusterTopologyOwnedLabel, ""))
+	g.Expect(gotMD.Labels).To(HaveKeyWithValue(clusterv1.ClusterTopologyMachineDeploymentNameLabel, "md1"))
+	gotMD = &clusterv1.MachineDeployment{}
+	g.Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(unmanagedMachineDeployment), gotMD)).To(Succeed())
+	g.Expect(gotMD.Labels).ToNot(HaveKey(clusterv1.ClusterTopologyOwnedLabel))
+
+	// The second reconcile completes the adoption, and the current state includes the adopted objects.
+	adopted, err = r.reconcileAdoption(ctx, s)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(adopted).To(BeTrue())
+	g.Expect(s.Current.Cluster.Annotations).ToNot(HaveKey(clusterv1.ClusterTopologyAdoptAnnotation))
+
+	current, err := r.getCurrentState(ctx, s)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(current.InfrastructureCluster.GetName()).To(Equal(infraCluster.GetName()))
+	g.Expect(current.ControlPlane.Object.GetName()).To(Equal(controlPlane.GetName()))
+	g.Expect(current.MachineDeployments).To(HaveLen(1))
+	g.Expect(current.MachineDeployments).To(HaveKey("md1"))
+}
@@ -37,10 +37,12 @@ func (r *Reconciler) getBlueprint(ctx context.Context, cluster *clusterv1.Cluste
 	}
 
 	var err error
-	// Get ClusterClass.spec.infrastructure.
-	blueprint.InfrastructureClusterTemplate, err = r.getReference(ctx, blueprint.ClusterClass.Spec.Infrastructure.Ref)
-	if err != nil {
-		return nil, errors.Wrapf(err, "failed to get infrastructure cluster template for %s", tlog.KObj{Obj: blueprint.ClusterClass})
+	// Get ClusterClass.spec.infrastructure, if defined.
+	if blueprint.HasInfrastructureCluster() {
+		blueprint.InfrastructureClusterTemplate, err = r.getReference(ctx, blueprint.ClusterClass.Spec.Infrastructure.Ref)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get infrastructure cluster template for %s", tlog.KObj{Obj: blueprint.ClusterClass})
+		}
 	}
 
 	// Get ClusterClass.spec.controlPlane.
@@ -89,6 +91,12 @@ func (r *Reconciler) getBlueprint(ctx context.Context, cluster *clusterv1.Cluste
 		if machineDeploymentClass.MachineHealthCheck != nil {
 			machineDeploymentBlueprint.MachineHealthCheck = machineDeploymentClass.MachineHealthCheck
 		}
+
+		// Add the IPAddressClaim templates defined in the machineDeploymentClass to the blueprint.
+		machineDeploymentBlueprint.IPAddressClaims = machineDeploymentClass.IPAddressClaims
+
+		// Add the naming strategy defined in the machineDeploymentClass to the blueprint.
+		machineDeploymentBlueprint.NamingStrategy = machineDeploymentClass.NamingStrategy
 		blueprint.MachineDeployments[machineDeploymentClass.Class] = machineDeploymentBlueprint
 	}
 
@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
@@ -36,6 +37,7 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/api/v1beta1/index"
 	"sigs.k8s.io/cluster-api/controllers/external"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1alpha1"
 	runtimecatalog "sigs.k8s.io/cluster-api/exp/runtime/catalog"
 	runtimehooksv1 "sigs.k8s.io/cluster-api/exp/runtime/hooks/api/v1alpha1"
 	"sigs.k8s.io/cluster-api/feature"
@@ -58,8 +60,13 @@ import (
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusterclasses,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinedeployments,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinehealthchecks,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines,verbs=get;list;watch
+// +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=ipaddressclaims,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=ipaddresses,verbs=get;list;watch
+// +kubebuilder:rbac:groups=addons.cluster.x-k8s.io,resources=clusterresourcesets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch
-// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;create;delete
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
 
 // Reconciler reconciles a managed topology for a Cluster object.
 type Reconciler struct {
@@ -103,6 +110,29 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opt
 			// Only trigger Cluster reconciliation if the MachineDeployment is topology owned.
 			builder.WithPredicates(predicates.ResourceIsTopologyOwned(ctrl.LoggerFrom(ctx))),
 		).
+		Watches(
+			&clusterv1.Machine{},
+			handler.EnqueueRequestsFromMapFunc(r.machineToCluster),
+			// Only trigger Cluster reconciliation if the Machine is topology owned.
+			builder.WithPredicates(predicates.ResourceIsTopologyOwned(ctrl.LoggerFrom(ctx))),
+		).
+		Watches(
+			&ipamv1.IPAddressClaim{},
+			handler.EnqueueRequestsFromMapFunc(r.ipAddressClaimToCluster),
+			// Only trigger Cluster reconciliation if the IPAddressClaim is topology owned.
+			builder.WithPredicates(predicates.ResourceIsTopologyOwned(ctrl.LoggerFrom(ctx))),
+		).
+		// Watch the ConfigMaps and Secrets variables are set from, so changes to the values are picked up immediately.
+		// NOTE: Only metadata is watched, given that values are read when reconciling the Cluster; also, only Secrets
+		// with the cluster name label are cached by the manager.
+		WatchesMetadata(
+			&corev1.ConfigMap{},
+			handler.EnqueueRequestsFromMapFunc(r.configMapToCluster),
+		).
+		WatchesMetadata(
+			&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.secretToCluster),
+		).
 		WithOptions(options).
 		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
 		Build(r)
@@ -230,6 +260,23 @@ func (r *Reconciler) reconcile(ctx context.Context, s *scope.Scope) (ctrl.Result
 		return ctrl.Result{}, errors.Wrap(err, "error reading the ClusterClass")
 	}
 
+	// Resolves the values of the variables set from ConfigMaps or Secrets and store them in the blueprint.
+	if err := r.resolveVariableValueSources(ctx, s); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "error resolving the values of the Cluster variables")
+	}
+
+	// If the Cluster has been attached to a ClusterClass using the adopt annotation, adopt its existing objects
+	// before reading the current state, and wait until the adoption is completed.
+	if _, ok := s.Current.Cluster.Annotations[clusterv1.ClusterTopologyAdoptAnnotation]; ok {
+		adopted, err := r.reconcileAdoption(ctx, s)
+		if err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "error adopting the existing objects of the Cluster")
+		}
+		if !adopted {
+			return ctrl.Result{Requeue: true}, nil
+		}
+	}
+
 	// Gets the current state of the Cluster and store it in the request scope.
 	s.Current, err = r.getCurrentState(ctx, s)
 	if err != nil {
@@ -264,12 +311,14 @@ func (r *Reconciler) reconcile(ctx context.Context, s *scope.Scope) (ctrl.Result
 	}
 
 	// requeueAfter will not be 0 if any of the runtime hooks returns a blocking response.
-	requeueAfter := s.HookResponseTracker.AggregateRetryAfter()
-	if requeueAfter != 0 {
-		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	result := ctrl.Result{RequeueAfter: s.HookResponseTracker.AggregateRetryAfter()}
+
+	// If the control plane rollout is held by the rollout policy of the ClusterClass, requeue to check it again.
+	if s.UpgradeTracker.ControlPlane.IsPendingRollout {
+		result = util.LowestNonZeroResult(result, ctrl.Result{RequeueAfter: s.UpgradeTracker.ControlPlane.PendingRolloutRetryAfter})
 	}
 
-	return ctrl.Result{}, nil
+	return result, nil
 }
 
 // setupDynamicWatches create watches for InfrastructureCluster and ControlPlane CRs when they exist.
@@ -360,6 +409,45 @@ func (r *Reconciler) machineDeploymentToCluster(_ context.Context, o client.Obje
 	}}
 }
 
+// machineToCluster is a handler.ToRequestsFunc to be used to enqueue requests for reconciliation
+// for Cluster to update when one of its own Machines gets updated.
+func (r *Reconciler) machineToCluster(_ context.Context, o client.Object) []ctrl.Request {
+	m, ok := o.(*clusterv1.Machine)
+	if !ok {
+		panic(fmt.Sprintf("Expected a Machine but got a %T", o))
+	}
+	if m.Spec.ClusterName == "" {
+		return nil
+	}
+
+	return []ctrl.Request{{
+		NamespacedName: types.NamespacedName{
+			Namespace: m.Namespace,
+			Name:      m.Spec.ClusterName,
+		},
+	}}
+}
+
+// ipAddressClaimToCluster is a handler.ToRequestsFunc to be used to enqueue requests for reconciliation
+// for Cluster to update when one of its own IPAddressClaims gets updated.
+func (r *Reconciler) ipAddressClaimToCluster(_ context.Context, o client.Object) []ctrl.Request {
+	claim, ok := o.(*ipamv1.IPAddressClaim)
+	if !ok {
+		panic(fmt.Sprintf("Expected an IPAddressClaim but got a %T", o))
+	}
+	clusterName, ok := claim.Labels[clusterv1.ClusterNameLabel]
+	if !ok || clusterName == "" {
+		return nil
+	}
+
+	return []ctrl.Request{{
+		NamespacedName: types.NamespacedName{
+			Namespace: claim.Namespace,
+			Name:      clusterName,
+		},
+	}}
+}
+
 func (r *Reconciler) reconcileDelete(ctx context.Context, cluster *clusterv1.Cluster) (ctrl.Result, error) {
 	// Call the BeforeClusterDelete hook if the 'ok-to-delete' annotation is not set
 	// and add the annotation to the cluster after receiving a successful non-blocking response.
@@ -89,6 +89,20 @@ func (r *Reconciler) reconcileTopologyReconciledCondition(s *scope.Scope, cluste
 		return nil
 	}
 
+	// If the existing objects of the Cluster are still being adopted, the topology is not yet reconciled.
+	if _, ok := cluster.Annotations[clusterv1.ClusterTopologyAdoptAnnotation]; ok {
+		conditions.Set(
+			cluster,
+			conditions.FalseCondition(
+				clusterv1.TopologyReconciledCondition,
+				clusterv1.TopologyReconciledAdoptionPendingReason,
+				clusterv1.ConditionSeverityInfo,
+				"Adoption of the existing objects of the Cluster in progress",
+			),
+		)
+		return nil
+	}
+
 	// If any of the lifecycle hooks are blocking any part of the reconciliation then topology
 	// is not considered as fully reconciled.
 	if s.HookResponseTracker.AggregateRetryAfter() != 0 {
@@ -105,6 +119,21 @@ func (r *Reconciler) reconcileTopologyReconciledCondition(s *scope.Scope, cluste
 		return nil
 	}
 
+	// The topology is not considered as fully reconciled if the control plane rollout is held
+	// by the rollout policy of the ClusterClass.
+	if s.UpgradeTracker.ControlPlane.IsPendingRollout {
+		conditions.Set(
+			cluster,
+			conditions.FalseCondition(
+				clusterv1.TopologyReconciledCondition,
+				clusterv1.TopologyReconciledControlPlaneRolloutPendingReason,
+				clusterv1.ConditionSeverityInfo,
+				s.UpgradeTracker.ControlPlane.PendingRolloutMessage,
+			),
+		)
+		return nil
+	}
+
 	// The topology is not considered as fully reconciled if one of the following is true:
 	// * either the Control Plane or any of the MachineDeployments are still pending to pick up the new version
 	//  (generally happens when upgrading the cluster)
@@ -103,6 +103,37 @@ func TestReconcileTopologyReconciledCondition(t *testing.T) {
 			wantConditionReason:  clusterv1.TopologyReconciledHookBlockingReason,
 			wantConditionMessage: "hook \"BeforeClusterUpgrade\" is blocking: msg",
 		},
+		{
+			name:         "should set the condition to false if the control plane rollout is held by the rollout policy",
+			reconcileErr: nil,
+			cluster:      &clusterv1.Cluster{},
+			s: &scope.Scope{
+				Blueprint: &scope.ClusterBlueprint{
+					Topology: &clusterv1.Topology{
+						Version: "v1.21.2",
+					},
+				},
+				Current: &scope.ClusterState{
+					Cluster: &clusterv1.Cluster{},
+					ControlPlane: &scope.ControlPlaneState{
+						Object: builder.ControlPlane("ns1", "controlplane1").
+							WithVersion("v1.21.2").
+							WithReplicas(3).
+							Build(),
+					},
+				},
+				UpgradeTracker: func() *scope.UpgradeTracker {
+					ut := scope.NewUpgradeTracker()
+					ut.ControlPlane.IsPendingRollout = true
+					ut.ControlPlane.PendingRolloutMessage = "Control plane rollout on hold, waiting for Cluster(s) cluster1 using ClusterClass class1 to complete the control plane rollout."
+					return ut
+				}(),
+				HookResponseTracker: scope.NewHookResponseTracker(),
+			},
+			wantConditionStatus:  corev1.ConditionFalse,
+			wantConditionReason:  clusterv1.TopologyReconciledControlPlaneRolloutPendingReason,
+			wantConditionMessage: "Control plane rollout on hold, waiting for Cluster(s) cluster1 using ClusterClass class1 to complete the control plane rollout.",
+		},
 		{
 			name:         "should set the condition to false if new version is not picked up because control plane is provisioning",
 			reconcileErr: nil,
@@ -0,0 +1,306 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/contract"
+	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/scope"
+	tlog "sigs.k8s.io/cluster-api/internal/log"
+	"sigs.k8s.io/cluster-api/internal/util/hash"
+	"sigs.k8s.io/cluster-api/util/annotations"
+)
+
+// controlPlaneRolloutRetryAfter is the interval at which the rollout of a control plane held by the rollout policy
+// of the ClusterClass is checked again while waiting for the control planes of other Clusters to complete the rollout.
+const controlPlaneRolloutRetryAfter = 30 * time.Second
+
+// defaultControlPlaneRolloutTimeout is the maximum amount of time a Cluster is expected to take to roll out its
+// control plane, if not defined by the rollout policy of the ClusterClass.
+const defaultControlPlaneRolloutTimeout = time.Hour
+
+// computeControlPlaneRollout tracks the rollouts of the control plane to new control plane templates of the
+// ClusterClass or to a new version, and holds them according to the rollout policy of the ClusterClass, so that the control planes
+// of the Clusters using the ClusterClass are rolled out in stages instead of all at the same time.
+// The progress of the rollout is tracked in the annotations of the desired Cluster, which are written when the
+// desired Cluster is applied in reconcileCluster.
+// NOTE: This func must be called after computing the desired control plane version, because the rollout
+// is started only if the control plane is going to be reconciled in the current reconcile loop.
+func (r *Reconciler) computeControlPlaneRollout(ctx context.Context, s *scope.Scope, desiredCluster *clusterv1.Cluster, desiredControlPlane *unstructured.Unstructured) error {
+	log := tlog.LoggerFrom(ctx)
+	cluster := s.Current.Cluster
+
+	policy := s.Blueprint.ClusterClass.Spec.ControlPlane.RolloutPolicy
+	if policy == nil {
+		return nil
+	}
+
+	version, err := contract.ControlPlane().Version().Get(desiredControlPlane)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the version from the desired control plane")
+	}
+	revision, err := controlPlaneRevision(s.Blueprint, *version)
+	if err != nil {
+		return err
+	}
+	currentRevision, hasRevision := cluster.Annotations[clusterv1.ClusterTopologyControlPlaneRevisionAnnotation]
+	rolloutGeneration, rolloutInProgress := cluster.Annotations[clusterv1.ClusterTopologyControlPlaneRolloutInProgressAnnotation]
+
+	// If the control plane is being created, or its revision is not tracked yet, e.g. because the rollout policy
+	// has just been added to the ClusterClass, the control plane is considered as up-to-date.
+	if s.Current.ControlPlane.Object == nil || !hasRevision {
+		setControlPlaneRolloutAnnotations(desiredCluster, map[string]string{
+			clusterv1.ClusterTopologyControlPlaneRevisionAnnotation: revision,
+		})
+		return nil
+	}
+
+	if rolloutInProgress {
+		// A rollout in progress is never held; if the control plane templates changed in the meantime,
+		// the control plane is rolled out to the latest ones.
+		if currentRevision != revision {
+			s.UpgradeTracker.ControlPlane.IsStartingRollout = true
+			setControlPlaneRolloutAnnotations(desiredCluster, map[string]string{
+				clusterv1.ClusterTopologyControlPlaneRevisionAnnotation:       revision,
+				clusterv1.ClusterTopologyControlPlaneRolloutStartedAnnotation: time.Now().UTC().Format(time.RFC3339),
+			})
+			return nil
+		}
+
+		completed, err := isControlPlaneRolloutCompleted(s, rolloutGeneration)
+		if err != nil || !completed {
+			return err
+		}
+		log.Infof("Control plane rollout to revision %s completed", revision)
+		setControlPlaneRolloutAnnotations(desiredCluster, map[string]string{
+			clusterv1.ClusterTopologyControlPlaneRolloutCompletedAnnotation: time.Now().UTC().Format(time.RFC3339),
+		}, clusterv1.ClusterTopologyControlPlaneRolloutInProgressAnnotation, clusterv1.ClusterTopologyControlPlaneRolloutStartedAnnotation)
+		return nil
+	}
+
+	if currentRevision == revision {
+		return nil
+	}
+
+	// If the control plane is pending an upgrade it is not reconciled in the current reconcile loop,
+	// so the rollout cannot be started yet.
+	if s.UpgradeTracker.ControlPlane.IsPendingUpgrade {
+		return nil
+	}
+
+	held, err := r.holdControlPlaneRollout(ctx, s, policy, revision)
+	if err != nil {
+		return err
+	}
+	if held {
+		// The control plane is not upgraded to the desired version until the rollout is started.
+		s.UpgradeTracker.ControlPlane.IsStartingUpgrade = false
+		return nil
+	}
+
+	log.Infof("Starting control plane rollout to revision %s", revision)
+	s.UpgradeTracker.ControlPlane.IsStartingRollout = true
+	setControlPlaneRolloutAnnotations(desiredCluster, map[string]string{
+		clusterv1.ClusterTopologyControlPlaneRevisionAnnotation:          revision,
+		clusterv1.ClusterTopologyControlPlaneRolloutInProgressAnnotation: strconv.FormatInt(s.Current.ControlPlane.Object.GetGeneration(), 10),
+		clusterv1.ClusterTopologyControlPlaneRolloutStartedAnnotation:    time.Now().UTC().Format(time.RFC3339),
+	}, clusterv1.ClusterTopologyControlPlaneRolloutCompletedAnnotation)
+	return nil
+}
+
+// holdControlPlaneRollout returns true and marks the control plane as pending a rollout if the rollout policy of
+// the ClusterClass doesn't allow to start the rollout of the control plane yet.
+// NOTE: Clusters pending a rollout start the rollout in the order of their names, so concurrent reconciles of
+// Clusters using the same ClusterClass, which could not see the rollouts started by each other yet, never start
+// more rollouts than allowed by the rollout policy.
+// NOTE: Clusters rolling out the control plane for longer than the rollout timeout, e.g. because the rollout is
+// stuck or failed, are ignored, so they don't hold the rollout of the other Clusters forever.
+func (r *Reconciler) holdControlPlaneRollout(ctx context.Context, s *scope.Scope, policy *clusterv1.ControlPlaneRolloutPolicy, revision string) (bool, error) {
+	log := tlog.LoggerFrom(ctx)
+	cluster := s.Current.Cluster
+	className := s.Blueprint.ClusterClass.Name
+
+	clusterList := &clusterv1.ClusterList{}
+	if err := r.Client.List(ctx, clusterList, client.InNamespace(cluster.Namespace)); err != nil {
+		return false, errors.Wrapf(err, "failed to list Clusters using ClusterClass %s", className)
+	}
+
+	timeout := defaultControlPlaneRolloutTimeout
+	if policy.Timeout != nil {
+		timeout = policy.Timeout.Duration
+	}
+
+	rollingOut := []string{}
+	pendingBefore := []string{}
+	var lastCompleted time.Time
+	for i := range clusterList.Items {
+		c := &clusterList.Items[i]
+		if c.Name == cluster.Name || c.Spec.Topology == nil || c.Spec.Topology.Class != className {
+			continue
+		}
+		if _, ok := c.Annotations[clusterv1.ClusterTopologyControlPlaneRolloutInProgressAnnotation]; ok {
+			if started, err := time.Parse(time.RFC3339, c.Annotations[clusterv1.ClusterTopologyControlPlaneRolloutStartedAnnotation]); err == nil && time.Since(started) > timeout {
+				log.Infof("Ignoring Cluster %s for the rollout policy of ClusterClass %s: the control plane rollout started at %s did not complete within %s", c.Name, className, started.Format(time.RFC3339), timeout)
+				continue
+			}
+			rollingOut = append(rollingOut, c.Name)
+			continue
+		}
+		if completed, err := time.Parse(time.RFC3339, c.Annotations[clusterv1.ClusterTopologyControlPlaneRolloutCompletedAnnotation]); err == nil && completed.After(lastCompleted) {
+			lastCompleted = completed
+		}
+		// Paused Clusters are not reconciled, so they must not hold the rollout of other Clusters.
+		if c.Name > cluster.Name || annotations.IsPaused(c, c) {
+			continue
+		}
+		if otherRevision, ok := c.Annotations[clusterv1.ClusterTopologyControlPlaneRevisionAnnotation]; ok && otherRevision != revision {
+			pendingBefore = append(pendingBefore, c.Name)
+		}
+	}
+
+	maxConcurrentClusters := 1
+	if policy.MaxConcurrentClusters != nil {
+		maxConcurrentClusters = int(*policy.MaxConcurrentClusters)
+	}
+	if len(rollingOut) >= maxConcurrentClusters {
+		sort.Strings(rollingOut)
+		s.UpgradeTracker.ControlPlane.IsPendingRollout = true
+		s.UpgradeTracker.ControlPlane.PendingRolloutMessage = fmt.Sprintf("Control plane rollout on hold, waiting for Cluster(s) %s using ClusterClass %s to complete the control plane rollout.",
+			computeNameList(rollingOut), className)
+		s.UpgradeTracker.ControlPlane.PendingRolloutRetryAfter = controlPlaneRolloutRetryAfter
+		log.Infof("Control plane rollout is held by the rollout policy of ClusterClass %s: Cluster(s) %s are rolling out the control plane", className, computeNameList(rollingOut))
+		return true, nil
+	}
+	if len(rollingOut)+len(pendingBefore) >= maxConcurrentClusters {
+		sort.Strings(pendingBefore)
+		s.UpgradeTracker.ControlPlane.IsPendingRollout = true
+		s.UpgradeTracker.ControlPlane.PendingRolloutMessage = fmt.Sprintf("Control plane rollout on hold, waiting for Cluster(s) %s using ClusterClass %s to start the control plane rollout.",
+			computeNameList(pendingBefore), className)
+		s.UpgradeTracker.ControlPlane.PendingRolloutRetryAfter = controlPlaneRolloutRetryAfter
+		log.Infof("Control plane rollout is held by the rollout policy of ClusterClass %s: Cluster(s) %s are going to roll out the control plane first", className, computeNameList(pendingBefore))
+		return true, nil
+	}
+
+	if policy.SoakTime != nil && !lastCompleted.IsZero() {
+		soakEnd := lastCompleted.Add(policy.SoakTime.Duration)
+		if remaining := time.Until(soakEnd); remaining > 0 {
+			s.UpgradeTracker.ControlPlane.IsPendingRollout = true
+			s.UpgradeTracker.ControlPlane.PendingRolloutMessage = fmt.Sprintf("Control plane rollout on hold, waiting for the soak time of ClusterClass %s to elapse at %s.",
+				className, soakEnd.Format(time.RFC3339))
+			s.UpgradeTracker.ControlPlane.PendingRolloutRetryAfter = remaining
+			log.Infof("Control plane rollout is held by the rollout policy of ClusterClass %s: waiting for the soak time to elapse at %s", className, soakEnd.Format(time.RFC3339))
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// reconcileControlPlaneRolloutGeneration tracks the generation of the control plane being rolled out in the
+// annotations of the desired Cluster, after the control plane has been updated to start the rollout, so the
+// rollout is not considered completed until the control plane controller has observed the update.
+// NOTE: This func must be called before reconcileCluster, which writes the annotations.
+func reconcileControlPlaneRolloutGeneration(s *scope.Scope) {
+	if !s.UpgradeTracker.ControlPlane.IsStartingRollout {
+		return
+	}
+
+	// NOTE: The generation of the desired control plane is set only if the control plane has been patched.
+	generation := s.Desired.ControlPlane.Object.GetGeneration()
+	if generation <= s.Current.ControlPlane.Object.GetGeneration() {
+		return
+	}
+	setControlPlaneRolloutAnnotations(s.Desired.Cluster, map[string]string{
+		clusterv1.ClusterTopologyControlPlaneRolloutInProgressAnnotation: strconv.FormatInt(generation, 10),
+	})
+}
+
+// isControlPlaneRolloutCompleted returns true if the control plane observed the generation being rolled out, and it is
+// neither provisioning, upgrading nor scaling.
+func isControlPlaneRolloutCompleted(s *scope.Scope, rolloutGeneration string) (bool, error) {
+	controlPlane := s.Current.ControlPlane.Object
+
+	// An invalid generation is ignored, so the rollout is considered completed as soon as the control plane is stable.
+	if generation, err := strconv.ParseInt(rolloutGeneration, 10, 64); err == nil && controlPlane.GetGeneration() < generation {
+		return false, nil
+	}
+
+	// NOTE: status.observedGeneration is optional in the control plane contract, so it is checked only if it exists.
+	observedGeneration, found, err := unstructured.NestedInt64(controlPlane.Object, "status", "observedGeneration")
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get status.observedGeneration from the control plane")
+	}
+	if found && observedGeneration < controlPlane.GetGeneration() {
+		return false, nil
+	}
+
+	provisioning, err := contract.ControlPlane().IsProvisioning(controlPlane)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to check if the control plane is being provisioned")
+	}
+	upgrading, err := contract.ControlPlane().IsUpgrading(controlPlane)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to check if the control plane is upgrading")
+	}
+	if provisioning || upgrading {
+		return false, nil
+	}
+
+	if s.Blueprint.Topology.ControlPlane.Replicas != nil {
+		scaling, err := contract.ControlPlane().IsScaling(controlPlane)
+		if err != nil {
+			return false, errors.Wrap(err, "failed to check if the control plane is scaling")
+		}
+		if scaling {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// controlPlaneRevision returns the revision of the control plane, computed from the desired version and from the
+// spec of the ControlPlaneTemplate and of the control plane InfrastructureMachineTemplate of the ClusterClass.
+// NOTE: Other changes, e.g. to the number of replicas, are not part of the revision, so they are never held.
+func controlPlaneRevision(blueprint *scope.ClusterBlueprint, version string) (string, error) {
+	templates := []interface{}{version, blueprint.ControlPlane.Template.Object["spec"]}
+	if blueprint.HasControlPlaneInfrastructureMachine() {
+		templates = append(templates, blueprint.ControlPlane.InfrastructureMachineTemplate.Object["spec"])
+	}
+	revision, err := hash.Compute(templates)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to compute the revision of the control plane templates")
+	}
+	return strconv.FormatUint(uint64(revision), 16), nil
+}
+
+// setControlPlaneRolloutAnnotations sets and removes the given annotations on the desired Cluster.
+func setControlPlaneRolloutAnnotations(cluster *clusterv1.Cluster, set map[string]string, remove ...string) {
+	annotations.AddAnnotations(cluster, set)
+	for _, key := range remove {
+		delete(cluster.Annotations, key)
+	}
+}
@@ -0,0 +1,416 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/scope"
+	"sigs.k8s.io/cluster-api/internal/test/builder"
+)
+
+func TestComputeControlPlaneRollout(t *testing.T) {
+	oldTemplate := builder.ControlPlaneTemplate(metav1.NamespaceDefault, "cp-template").
+		WithSpecFields(map[string]interface{}{"spec.template.spec.foo": "old"}).
+		Build()
+	newTemplate := builder.ControlPlaneTemplate(metav1.NamespaceDefault, "cp-template").
+		WithSpecFields(map[string]interface{}{"spec.template.spec.foo": "new"}).
+		Build()
+	oldRevision, err := controlPlaneRevision(&scope.ClusterBlueprint{ControlPlane: &scope.ControlPlaneBlueprint{Template: oldTemplate}, ClusterClass: &clusterv1.ClusterClass{}}, "v1.2.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	newRevision, err := controlPlaneRevision(&scope.ClusterBlueprint{ControlPlane: &scope.ControlPlaneBlueprint{Template: newTemplate}, ClusterClass: &clusterv1.ClusterClass{}}, "v1.2.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	newVersionRevision, err := controlPlaneRevision(&scope.ClusterBlueprint{ControlPlane: &scope.ControlPlaneBlueprint{Template: newTemplate}, ClusterClass: &clusterv1.ClusterClass{}}, "v1.2.4")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stableControlPlane := builder.ControlPlane(metav1.NamespaceDefault, "cp").
+		WithSpecFields(map[string]interface{}{
+			"spec.version":  "v1.2.3",
+			"spec.replicas": int64(3),
+		}).
+		WithStatusFields(map[string]interface{}{
+			"status.version":             "v1.2.3",
+			"status.replicas":            int64(3),
+			"status.updatedReplicas":     int64(3),
+			"status.readyReplicas":       int64(3),
+			"status.unavailableReplicas": int64(0),
+			"status.observedGeneration":  int64(2),
+		}).
+		Build()
+	stableControlPlane.SetGeneration(2)
+	rollingOutControlPlane := stableControlPlane.DeepCopy()
+	g := NewWithT(t)
+	g.Expect(unstructured.SetNestedField(rollingOutControlPlane.Object, int64(2), "status", "updatedReplicas")).To(Succeed())
+
+	cluster := func(name, class string, annotations map[string]string) *clusterv1.Cluster {
+		return &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   metav1.NamespaceDefault,
+				Annotations: annotations,
+			},
+			Spec: clusterv1.ClusterSpec{
+				Topology: &clusterv1.Topology{Class: class},
+			},
+		}
+	}
+	recentlyCompleted := time.Now().Add(-1 * time.Minute).UTC().Format(time.RFC3339)
+	startedLongAgo := time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)
+
+	tests := []struct {
+		name                string
+		policy              *clusterv1.ControlPlaneRolloutPolicy
+		annotations         map[string]string
+		controlPlane        *unstructured.Unstructured
+		desiredVersion      string
+		isPendingUpgrade    bool
+		isStartingUpgrade   bool
+		otherClusters       []*clusterv1.Cluster
+		wantAnnotations     map[string]string
+		wantCompleted       bool
+		wantPendingRollout  bool
+		wantPendingMessage  string
+		wantStartingRollout bool
+	}{
+		{
+			name:            "no rollout policy",
+			annotations:     map[string]string{clusterv1.ClusterTopologyControlPlaneRevisionAnnotation: oldRevision},
+			controlPlane:    stableControlPlane,
+			wantAnnotations: map[string]string{clusterv1.ClusterTopologyControlPlaneRevisionAnnotation: oldRevision},
+		},
+		{
+			name:            "revision not tracked yet",
+			policy:          &clusterv1.ControlPlaneRolloutPolicy{},
+			controlPlane:    stableControlPlane,
+			wantAnnotations: map[string]string{clusterv1.ClusterTopologyControlPlaneRevisionAnnotation: newRevision},
+		},
+		{
+			name:            "control plane being created",
+			policy:          &clusterv1.ControlPlaneRolloutPolicy{},
+			annotations:     map[string]string{clusterv1.ClusterTopologyControlPlaneRevisionAnnotation: oldRevision},
+			wantAnnotations: map[string]string{clusterv1.ClusterTopologyControlPlaneRevisionAnnotation: newRevision},
+		},
+		{
+			name:            "control plane up-to-date",
+			policy:          &clusterv1.ControlPlaneRolloutPolicy{},
+			annotations:     map[string]string{clusterv1.ClusterTopologyControlPlaneRevisionAnnotation: newRevision},
+			controlPlane:    stableControlPlane,
+			wantAnnotations: map[string]string{clusterv1.ClusterTopologyControlPlaneRevisionAnnotation: newRevision},
+		},
+		{
+			name:   "start rollout if no other Cluster is rolling out",
+			policy: &clusterv1.ControlPlaneRolloutPolicy{},
+			annotations: map[string]string{
+				clusterv1.ClusterTopologyControlPlaneRevisionAnnotation:         oldRevision,
+				clusterv1.ClusterTopologyControlPlaneRolloutCompletedAnnotation: recentlyCompleted,
+			},
+			controlPlane: stableControlPlane,
+			otherClusters: []*clusterv1.Cluster{
+				cluster("other-class", "other-class", map[string]string{clusterv1.ClusterTopologyControlPlaneRolloutInProgressAnnotation: "1"}),
+			},
+			wantAnnotations: map[string]string{
+				clusterv1.ClusterTopologyControlPlaneRevisionAnnotation:          newRevision,
+				clusterv1.ClusterTopologyControlPlaneRolloutInProgressAnnotation: "2",
+			},
+			wantStartingRollout: true,
+		},
+		{
+			name:           "start rollout to a new version",
+			policy:         &clusterv1.ControlPlaneRolloutPolicy{},
+			annotations:    map[string]string{clusterv1.ClusterTopologyControlPlaneRevisionAnnotation: newRevision},
+			controlPlane:   stableControlPlane,
+			desiredVersion: "v1.2.4",
+			wantAnnotations: map[string]string{
+				clusterv1.ClusterTopologyControlPlaneRevisionAnnotation:          newVersionRevision,
+				clusterv1.ClusterTopologyControlPlaneRolloutInProgressAnnotation: "2",
+			},
+			wantStartingRollout: true,
+		},
+		{
+			name:             "do not start rollout if the control plane is pending an upgrade",
+			policy:           &clusterv1.ControlPlaneRolloutPolicy{},
+			annotations:      map[string]string{clusterv1.ClusterTopologyControlPlaneRevisionAnnotation: oldRevision},
+			controlPlane:     stableControlPlane,
+			isPendingUpgrade: true,
+			wantAnnotations:  map[string]string{clusterv1.ClusterTopologyControlPlaneRevisionAnnotation: oldRevision},
+		},
+		{
+			name:         "hold rollout if other Clusters are rolling out",
+			policy:       &clusterv1.ControlPlaneRolloutPolicy{},
+			annotations:  map[string]string{clusterv1.ClusterTopologyControlPlaneRevisionAnnotation: oldRevision},
+			controlPlane: stableControlPlane,
+			otherClusters: []*clusterv1.Cluster{
+				cluster("rolling-out", "class", map[string]string{clusterv1.ClusterTopologyControlPlaneRolloutInProgressAnnotation: "1"}),
+			},
+			wantAnnotations:    map[string]string{clusterv1.ClusterTopologyControlPlaneRevisionAnnotation: oldRevision},
+			wantPendingRollout: true,
+			wantPendingMessage: "Control plane rollout on hold, waiting for Cluster(s) rolling-out using ClusterClass class to complete the control plane rollout.",
+		},
+		{
+			name:              "hold rollout to a new version if other Clusters are rolling out",
+			policy:            &clusterv1.ControlPlaneRolloutPolicy{},
+			annotations:       map[string]string{clusterv1.ClusterTopologyControlPlaneRevisionAnnotation: newRevision},
+			controlPlane:      stableControlPlane,
+			desiredVersion:    "v1.2.4",
+			isStartingUpgrade: true,
+			otherClusters: []*clusterv1.Cluster{
+				cluster("rolling-out", "class", map[string]string{clusterv1.ClusterTopologyControlPlaneRolloutInProgressAnnotation: "1"}),
+			},
+			wantAnnotations:    map[string]string{clusterv1.ClusterTopologyControlPlaneRevisionAnnotation: newRevision},
+			wantPendingRollout: true,
+			wantPendingMessage: "Control plane rollout on hold, waiting for Cluster(s) rolling-out using ClusterClass class to complete the control plane rollout.",
+		},
+		{
+			name:         "start rollout if other Clusters are rolling out for longer than the rollout timeout",
+			policy:       &clusterv1.ControlPlaneRolloutPolicy{},
+			annotations:  map[string]string{clusterv1.ClusterTopologyControlPlaneRevisionAnnotation: oldRevision},
+			controlPlane: stableControlPlane,
+			otherClusters: []*clusterv1.Cluster{
+				cluster("stuck", "class", map[string]string{
+					clusterv1.ClusterTopologyControlPlaneRolloutInProgressAnnotation: "1",
+					clusterv1.ClusterTopologyControlPlaneRolloutStartedAnnotation:    startedLongAgo,
+				}),
+			},
+			wantAnnotations: map[string]string{
+				clusterv1.ClusterTopologyControlPlaneRevisionAnnotation:          newRevision,
+				clusterv1.ClusterTopologyControlPlaneRolloutInProgressAnnotation: "2",
+			},
+			wantStartingRollout: true,
+		},
+		{
+			name:         "hold rollout if other Clusters are rolling out within the rollout timeout",
+			policy:       &clusterv1.ControlPlaneRolloutPolicy{Timeout: &metav1.Duration{Duration: 3 * time.Hour}},
+			annotations:  map[string]string{clusterv1.ClusterTopologyControlPlaneRevisionAnnotation: oldRevision},
+			controlPlane: stableControlPlane,
+			otherClusters: []*clusterv1.Cluster{
+				cluster("rolling-out", "class", map[string]string{
+					clusterv1.ClusterTopologyControlPlaneRolloutInProgressAnnotation: "1",
+					clusterv1.ClusterTopologyControlPlaneRolloutStartedAnnotation:    startedLongAgo,
+				}),
+			},
+			wantAnnotations:    map[string]string{clusterv1.ClusterTopologyControlPlaneRevisionAnnotation: oldRevision},
+			wantPendingRollout: true,
+		},
+		{
+			name:         "hold rollout if other Clusters pending a rollout come first",
+			policy:       &clusterv1.ControlPlaneRolloutPolicy{},
+			annotations:  map[string]string{clusterv1.ClusterTopologyControlPlaneRevisionAnnotation: oldRevision},
+			controlPlane: stableControlPlane,
+			otherClusters: []*clusterv1.Cluster{
+				cluster("a-pending", "class", map[string]string{clusterv1.ClusterTopologyControlPlaneRevisionAnnotation: oldRevision}),
+				cluster("z-pending", "class", map[string]string{clusterv1.ClusterTopologyControlPlaneRevisionAnnotation: oldRevision}),
+			},
+			wantAnnotations:    map[string]string{clusterv1.ClusterTopologyControlPlaneRevisionAnnotation: oldRevision},
+			wantPendingRollout: true,
+			wantPendingMessage: "Control plane rollout on hold, waiting for Cluster(s) a-pending using ClusterClass class to start the control plane rollout.",
+		},
+		{
+			name:         "start rollout if other Clusters pending a rollout come later or are paused",
+			policy:       &clusterv1.ControlPlaneRolloutPolicy{},
+			annotations:  map[string]string{clusterv1.ClusterTopologyControlPlaneRevisionAnnotation: oldRevision},
+			controlPlane: stableControlPlane,
+			otherClusters: []*clusterv1.Cluster{
+				cluster("a-paused", "class", map[string]string{
+					clusterv1.ClusterTopologyControlPlaneRevisionAnnotation: oldRevision,
+					clusterv1.PausedAnnotation:                              "",
+				}),
+				cluster("a-up-to-date", "class", map[string]string{clusterv1.ClusterTopologyControlPlaneRevisionAnnotation: newRevision}),
+				cluster("z-pending", "class", map[string]string{clusterv1.ClusterTopologyControlPlaneRevisionAnnotation: oldRevision}),
+			},
+			wantAnnotations: map[string]string{
+				clusterv1.ClusterTopologyControlPlaneRevisionAnnotation:          newRevision,
+				clusterv1.ClusterTopologyControlPlaneRolloutInProgressAnnotation: "2",
+			},
+			wantStartingRollout: true,
+		},
+		{
+			name:         "start rollout if less than maxConcurrentClusters Clusters are rolling out",
+			policy:       &clusterv1.ControlPlaneRolloutPolicy{MaxConcurrentClusters: pointer.Int32(2)},
+			annotations:  map[string]string{clusterv1.ClusterTopologyControlPlaneRevisionAnnotation: oldRevision},
+			controlPlane: stableControlPlane,
+			otherClusters: []*clusterv1.Cluster{
+				cluster("rolling-out", "class", map[string]string{clusterv1.ClusterTopologyControlPlaneRolloutInProgressAnnotation: "1"}),
+			},
+			wantAnnotations: map[string]string{
+				clusterv1.ClusterTopologyControlPlaneRevisionAnnotation:          newRevision,
+				clusterv1.ClusterTopologyControlPlaneRolloutInProgressAnnotation: "2",
+			},
+			wantStartingRollout: true,
+		},
+		{
+			name:         "hold rollout during the soak time",
+			policy:       &clusterv1.ControlPlaneRolloutPolicy{SoakTime: &metav1.Duration{Duration: 10 * time.Minute}},
+			annotations:  map[string]string{clusterv1.ClusterTopologyControlPlaneRevisionAnnotation: oldRevision},
+			controlPlane: stableControlPlane,
+			otherClusters: []*clusterv1.Cluster{
+				cluster("completed", "class", map[string]string{clusterv1.ClusterTopologyControlPlaneRolloutCompletedAnnotation: recentlyCompleted}),
+			},
+			wantAnnotations:    map[string]string{clusterv1.ClusterTopologyControlPlaneRevisionAnnotation: oldRevision},
+			wantPendingRollout: true,
+		},
+		{
+			name:         "start rollout after the soak time",
+			policy:       &clusterv1.ControlPlaneRolloutPolicy{SoakTime: &metav1.Duration{Duration: 30 * time.Second}},
+			annotations:  map[string]string{clusterv1.ClusterTopologyControlPlaneRevisionAnnotation: oldRevision},
+			controlPlane: stableControlPlane,
+			otherClusters: []*clusterv1.Cluster{
+				cluster("completed", "class", map[string]string{clusterv1.ClusterTopologyControlPlaneRolloutCompletedAnnotation: recentlyCompleted}),
+			},
+			wantAnnotations: map[string]string{
+				clusterv1.ClusterTopologyControlPlaneRevisionAnnotation:          newRevision,
+				clusterv1.ClusterTopologyControlPlaneRolloutInProgressAnnotation: "2",
+			},
+			wantStartingRollout: true,
+		},
+		{
+			name:   "complete rollout when the control plane is stable",
+			policy: &clusterv1.ControlPlaneRolloutPolicy{},
+			annotations: map[string]string{
+				clusterv1.ClusterTopologyControlPlaneRevisionAnnotation:          newRevision,
+				clusterv1.ClusterTopologyControlPlaneRolloutInProgressAnnotation: "2",
+				clusterv1.ClusterTopologyControlPlaneRolloutStartedAnnotation:    recentlyCompleted,
+			},
+			controlPlane: stableControlPlane,
+			wantAnnotations: map[string]string{
+				clusterv1.ClusterTopologyControlPlaneRevisionAnnotation: newRevision,
+			},
+			wantCompleted: true,
+		},
+		{
+			name:   "do not complete rollout when the control plane is rolling out",
+			policy: &clusterv1.ControlPlaneRolloutPolicy{},
+			annotations: map[string]string{
+				clusterv1.ClusterTopologyControlPlaneRevisionAnnotation:          newRevision,
+				clusterv1.ClusterTopologyControlPlaneRolloutInProgressAnnotation: "2",
+			},
+			controlPlane: rollingOutControlPlane,
+			wantAnnotations: map[string]string{
+				clusterv1.ClusterTopologyControlPlaneRevisionAnnotation:          newRevision,
+				clusterv1.ClusterTopologyControlPlaneRolloutInProgressAnnotation: "2",
+			},
+		},
+		{
+			name:   "do not complete rollout when the control plane has not been updated yet",
+			policy: &clusterv1.ControlPlaneRolloutPolicy{},
+			annotations: map[string]string{
+				clusterv1.ClusterTopologyControlPlaneRevisionAnnotation:          newRevision,
+				clusterv1.ClusterTopologyControlPlaneRolloutInProgressAnnotation: "3",
+			},
+			controlPlane: stableControlPlane,
+			wantAnnotations: map[string]string{
+				clusterv1.ClusterTopologyControlPlaneRevisionAnnotation:          newRevision,
+				clusterv1.ClusterTopologyControlPlaneRolloutInProgressAnnotation: "3",
+			},
+		},
+		{
+			name:   "never hold a rollout in progress",
+			policy: &clusterv1.ControlPlaneRolloutPolicy{},
+			annotations: map[string]string{
+				clusterv1.ClusterTopologyControlPlaneRevisionAnnotation:          oldRevision,
+				clusterv1.ClusterTopologyControlPlaneRolloutInProgressAnnotation: "2",
+			},
+			controlPlane: rollingOutControlPlane,
+			otherClusters: []*clusterv1.Cluster{
+				cluster("rolling-out", "class", map[string]string{clusterv1.ClusterTopologyControlPlaneRolloutInProgressAnnotation: "1"}),
+			},
+			wantAnnotations: map[string]string{
+				clusterv1.ClusterTopologyControlPlaneRevisionAnnotation:          newRevision,
+				clusterv1.ClusterTopologyControlPlaneRolloutInProgressAnnotation: "2",
+			},
+			wantStartingRollout: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			current := cluster("cluster", "class", tt.annotations)
+			objs := []client.Object{current}
+			for _, c := range tt.otherClusters {
+				objs = append(objs, c)
+			}
+			fakeClient := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(objs...).Build()
+
+			s := scope.New(current)
+			s.Blueprint = &scope.ClusterBlueprint{
+				Topology: &clusterv1.Topology{
+					Class:        "class",
+					ControlPlane: clusterv1.ControlPlaneTopology{Replicas: pointer.Int32(3)},
+				},
+				ClusterClass: &clusterv1.ClusterClass{
+					ObjectMeta: metav1.ObjectMeta{Name: "class", Namespace: metav1.NamespaceDefault},
+					Spec: clusterv1.ClusterClassSpec{
+						ControlPlane: clusterv1.ControlPlaneClass{RolloutPolicy: tt.policy},
+					},
+				},
+				ControlPlane: &scope.ControlPlaneBlueprint{Template: newTemplate},
+			}
+			s.Current.ControlPlane = &scope.ControlPlaneState{Object: tt.controlPlane}
+			s.UpgradeTracker.ControlPlane.IsPendingUpgrade = tt.isPendingUpgrade
+			s.UpgradeTracker.ControlPlane.IsStartingUpgrade = tt.isStartingUpgrade
+
+			desiredVersion := "v1.2.3"
+			if tt.desiredVersion != "" {
+				desiredVersion = tt.desiredVersion
+			}
+			desiredControlPlane := builder.ControlPlane(metav1.NamespaceDefault, "cp").
+				WithSpecFields(map[string]interface{}{"spec.version": desiredVersion}).
+				Build()
+
+			r := &Reconciler{Client: fakeClient}
+			got := current.DeepCopy()
+			g.Expect(r.computeControlPlaneRollout(ctx, s, got, desiredControlPlane)).To(Succeed())
+
+			// The annotations are only computed on the desired Cluster, and written in reconcileCluster.
+			g.Expect(current.Annotations).To(Equal(tt.annotations))
+			if tt.wantCompleted {
+				g.Expect(got.Annotations).To(HaveKey(clusterv1.ClusterTopologyControlPlaneRolloutCompletedAnnotation))
+				delete(got.Annotations, clusterv1.ClusterTopologyControlPlaneRolloutCompletedAnnotation)
+			}
+			if tt.wantStartingRollout {
+				g.Expect(got.Annotations).To(HaveKey(clusterv1.ClusterTopologyControlPlaneRolloutStartedAnnotation))
+				delete(got.Annotations, clusterv1.ClusterTopologyControlPlaneRolloutStartedAnnotation)
+			}
+			g.Expect(got.Annotations).To(Equal(tt.wantAnnotations))
+
+			g.Expect(s.UpgradeTracker.ControlPlane.IsPendingRollout).To(Equal(tt.wantPendingRollout))
+			g.Expect(s.UpgradeTracker.ControlPlane.IsStartingRollout).To(Equal(tt.wantStartingRollout))
+			g.Expect(s.UpgradeTracker.ControlPlane.IsStartingUpgrade).To(Equal(tt.isStartingUpgrade && !tt.wantPendingRollout))
+			if tt.wantPendingRollout {
+				g.Expect(s.UpgradeTracker.ControlPlane.PendingRolloutRetryAfter).To(BeNumerically(">", 0))
+			}
+			if tt.wantPendingMessage != "" {
+				g.Expect(s.UpgradeTracker.ControlPlane.PendingRolloutMessage).To(Equal(tt.wantPendingMessage))
+			}
+		})
+	}
+}
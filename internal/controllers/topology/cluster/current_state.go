@@ -28,6 +28,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
 	"sigs.k8s.io/cluster-api/internal/contract"
 	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/scope"
 	tlog "sigs.k8s.io/cluster-api/internal/log"
@@ -69,6 +70,13 @@ func (r *Reconciler) getCurrentState(ctx context.Context, s *scope.Scope) (*scop
 	}
 	currentState.MachineDeployments = m
 
+	// A Cluster may have zero or more ClusterResourceSets for the add-ons defined in its ClusterClass.
+	crs, err := r.getCurrentClusterResourceSetState(ctx, currentState.Cluster)
+	if err != nil {
+		return nil, err
+	}
+	currentState.ClusterResourceSets = crs
+
 	return currentState, nil
 }
 
@@ -312,3 +320,35 @@ func getMDClassName(cluster *clusterv1.Cluster, mdTopologyName string) (bool, st
 	}
 	return false, ""
 }
+
+// getCurrentClusterResourceSetState queries for all the ClusterResourceSets generated for the add-ons of a Cluster
+// and returns them indexed by add-on name.
+func (r *Reconciler) getCurrentClusterResourceSetState(ctx context.Context, cluster *clusterv1.Cluster) (map[string]*addonsv1.ClusterResourceSet, error) {
+	state := map[string]*addonsv1.ClusterResourceSet{}
+
+	crsList := &addonsv1.ClusterResourceSetList{}
+	err := r.Client.List(ctx, crsList,
+		client.MatchingLabels{
+			clusterv1.ClusterNameLabel:          cluster.Name,
+			clusterv1.ClusterTopologyOwnedLabel: "",
+		},
+		client.InNamespace(cluster.Namespace),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read ClusterResourceSets for managed topology")
+	}
+
+	for i := range crsList.Items {
+		crs := &crsList.Items[i]
+
+		addonName, ok := crs.Labels[clusterv1.ClusterTopologyAddonNameLabel]
+		if !ok || addonName == "" {
+			return nil, fmt.Errorf("failed to find label %s in %s", clusterv1.ClusterTopologyAddonNameLabel, tlog.KObj{Obj: crs})
+		}
+		if _, ok := state[addonName]; ok {
+			return nil, fmt.Errorf("duplicate %s found for label %s: %s", tlog.KObj{Obj: crs}, clusterv1.ClusterTopologyAddonNameLabel, addonName)
+		}
+		state[addonName] = crs
+	}
+	return state, nil
+}
@@ -17,27 +17,34 @@ limitations under the License.
 package cluster
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/apiserver/pkg/storage/names"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controllers/external"
+	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
 	runtimecatalog "sigs.k8s.io/cluster-api/exp/runtime/catalog"
 	runtimehooksv1 "sigs.k8s.io/cluster-api/exp/runtime/hooks/api/v1alpha1"
 	"sigs.k8s.io/cluster-api/feature"
 	"sigs.k8s.io/cluster-api/internal/contract"
+	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/patches/inline"
+	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/patches/variables"
 	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/scope"
 	"sigs.k8s.io/cluster-api/internal/hooks"
 	tlog "sigs.k8s.io/cluster-api/internal/log"
+	"sigs.k8s.io/cluster-api/internal/topology/names"
 	"sigs.k8s.io/cluster-api/util"
 )
 
@@ -51,9 +58,11 @@ func (r *Reconciler) computeDesiredState(ctx context.Context, s *scope.Scope) (*
 		ControlPlane: &scope.ControlPlaneState{},
 	}
 
-	// Compute the desired state of the InfrastructureCluster object.
-	if desiredState.InfrastructureCluster, err = computeInfrastructureCluster(ctx, s); err != nil {
-		return nil, errors.Wrapf(err, "failed to compute InfrastructureCluster")
+	// Compute the desired state of the InfrastructureCluster object, if the clusterClass defines one.
+	if s.Blueprint.HasInfrastructureCluster() {
+		if desiredState.InfrastructureCluster, err = computeInfrastructureCluster(ctx, s); err != nil {
+			return nil, errors.Wrapf(err, "failed to compute InfrastructureCluster")
+		}
 	}
 
 	// If the clusterClass mandates the controlPlane has infrastructureMachines, compute the InfrastructureMachineTemplate for the ControlPlane.
@@ -97,6 +106,12 @@ func (r *Reconciler) computeDesiredState(ctx context.Context, s *scope.Scope) (*
 		return nil, errors.Wrapf(err, "failed to compute Cluster")
 	}
 
+	// Track the rollouts of the ControlPlane to new control plane templates of the ClusterClass or to a new version
+	// in the annotations of the desired Cluster, eventually holding them according to the rollout policy of the ClusterClass.
+	if err := r.computeControlPlaneRollout(ctx, s, desiredState.Cluster, desiredState.ControlPlane.Object); err != nil {
+		return nil, errors.Wrapf(err, "failed to compute ControlPlane rollout")
+	}
+
 	// If required, compute the desired state of the MachineDeployments from the list of MachineDeploymentTopologies
 	// defined in the cluster.
 	if s.Blueprint.HasMachineDeployments() {
@@ -116,6 +131,13 @@ func (r *Reconciler) computeDesiredState(ctx context.Context, s *scope.Scope) (*
 		return nil, errors.Wrap(err, "failed to apply patches")
 	}
 
+	// Compute the desired state of the ClusterResourceSets for the add-ons defined in the ClusterClass
+	// and enabled for the Cluster.
+	desiredState.ClusterResourceSets, err = computeClusterResourceSets(ctx, s)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compute ClusterResourceSets")
+	}
+
 	return desiredState, nil
 }
 
@@ -127,12 +149,20 @@ func computeInfrastructureCluster(_ context.Context, s *scope.Scope) (*unstructu
 	cluster := s.Current.Cluster
 	currentRef := cluster.Spec.InfrastructureRef
 
+	// Propagate the labels and annotations from the Cluster metadata.
+	clusterMetadata := clusterv1.ObjectMeta{}
+	if cluster.Spec.Metadata != nil {
+		clusterMetadata = *cluster.Spec.Metadata
+	}
+
 	infrastructureCluster, err := templateToObject(templateToInput{
 		template:              template,
 		templateClonedFromRef: templateClonedFromRef,
 		cluster:               cluster,
-		namePrefix:            fmt.Sprintf("%s-", cluster.Name),
+		nameGenerator:         names.SimpleNameGenerator(fmt.Sprintf("%s-", cluster.Name)),
 		currentObjectRef:      currentRef,
+		labels:                clusterMetadata.Labels,
+		annotations:           clusterMetadata.Annotations,
 		// Note: It is not possible to add an ownerRef to Cluster at this stage, otherwise the provisioning
 		// of the infrastructure cluster starts no matter of the object being actually referenced by the Cluster itself.
 	})
@@ -169,17 +199,20 @@ func computeControlPlaneInfrastructureMachineTemplate(_ context.Context, s *scop
 		}
 	}
 
-	controlPlaneInfrastructureMachineTemplate := templateToTemplate(templateToInput{
+	controlPlaneInfrastructureMachineTemplate, err := templateToTemplate(templateToInput{
 		template:              template,
 		templateClonedFromRef: templateClonedFromRef,
 		cluster:               cluster,
-		namePrefix:            controlPlaneInfrastructureMachineTemplateNamePrefix(cluster.Name),
+		nameGenerator:         names.ControlPlaneInfrastructureMachineTemplateNameGenerator(s.Blueprint.ClusterClass.Spec.ControlPlane.NamingStrategy, cluster.Name),
 		currentObjectRef:      currentRef,
 		// Note: we are adding an ownerRef to Cluster so the template will be automatically garbage collected
 		// in case of errors in between creating this template and updating the Cluster object
 		// with the reference to the ControlPlane object using this template.
 		ownerRef: ownerReferenceTo(s.Current.Cluster),
 	})
+	if err != nil {
+		return nil, err
+	}
 	return controlPlaneInfrastructureMachineTemplate, nil
 }
 
@@ -192,25 +225,29 @@ func (r *Reconciler) computeControlPlane(ctx context.Context, s *scope.Scope, in
 	currentRef := cluster.Spec.ControlPlaneRef
 
 	// Compute the labels and annotations to be applied to ControlPlane metadata and ControlPlane machines.
-	// We merge the labels and annotations from topology and ClusterClass.
+	// We merge the labels and annotations from the topology, ClusterClass and Cluster metadata, in this order of precedence.
 	// We also add the cluster-name and the topology owned labels, so they are propagated down.
+	clusterMetadata := clusterv1.ObjectMeta{}
+	if cluster.Spec.Metadata != nil {
+		clusterMetadata = *cluster.Spec.Metadata
+	}
 	topologyMetadata := s.Blueprint.Topology.ControlPlane.Metadata
 	clusterClassMetadata := s.Blueprint.ClusterClass.Spec.ControlPlane.Metadata
 
-	controlPlaneLabels := util.MergeMap(topologyMetadata.Labels, clusterClassMetadata.Labels)
+	controlPlaneLabels := util.MergeMap(topologyMetadata.Labels, clusterClassMetadata.Labels, clusterMetadata.Labels)
 	if controlPlaneLabels == nil {
 		controlPlaneLabels = map[string]string{}
 	}
 	controlPlaneLabels[clusterv1.ClusterNameLabel] = cluster.Name
 	controlPlaneLabels[clusterv1.ClusterTopologyOwnedLabel] = ""
 
-	controlPlaneAnnotations := util.MergeMap(topologyMetadata.Annotations, clusterClassMetadata.Annotations)
+	controlPlaneAnnotations := util.MergeMap(topologyMetadata.Annotations, clusterClassMetadata.Annotations, clusterMetadata.Annotations)
 
 	controlPlane, err := templateToObject(templateToInput{
 		template:              template,
 		templateClonedFromRef: templateClonedFromRef,
 		cluster:               cluster,
-		namePrefix:            fmt.Sprintf("%s-", cluster.Name),
+		nameGenerator:         names.ControlPlaneNameGenerator(s.Blueprint.ClusterClass.Spec.ControlPlane.NamingStrategy, cluster.Name),
 		currentObjectRef:      currentRef,
 		labels:                controlPlaneLabels,
 		annotations:           controlPlaneAnnotations,
@@ -491,12 +528,28 @@ func computeCluster(_ context.Context, s *scope.Scope, infrastructureCluster, co
 	cluster.Labels[clusterv1.ClusterNameLabel] = cluster.Name
 	cluster.Labels[clusterv1.ClusterTopologyOwnedLabel] = ""
 
+	// Track the values of the variables set from ConfigMaps or Secrets.
+	if s.Blueprint.VariableValueSourcesHash != "" {
+		if cluster.Annotations == nil {
+			cluster.Annotations = map[string]string{}
+		}
+		cluster.Annotations[clusterv1.ClusterTopologyVariableValueSourcesHashAnnotation] = s.Blueprint.VariableValueSourcesHash
+	} else {
+		delete(cluster.Annotations, clusterv1.ClusterTopologyVariableValueSourcesHashAnnotation)
+	}
+
 	// Set the references to the infrastructureCluster and controlPlane objects.
 	// NOTE: Once set for the first time, the references are not expected to change.
+	// NOTE: If the clusterClass does not define an InfrastructureClusterTemplate, the infrastructure of the Cluster
+	// is provided by the control plane.
 	var err error
-	cluster.Spec.InfrastructureRef, err = calculateRefDesiredAPIVersion(cluster.Spec.InfrastructureRef, infrastructureCluster)
-	if err != nil {
-		return nil, errors.Wrapf(err, "failed to calculate infrastructureRef")
+	if infrastructureCluster != nil {
+		cluster.Spec.InfrastructureRef, err = calculateRefDesiredAPIVersion(cluster.Spec.InfrastructureRef, infrastructureCluster)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to calculate infrastructureRef")
+		}
+	} else {
+		cluster.Spec.ControlPlaneProvidesInfrastructure = true
 	}
 	cluster.Spec.ControlPlaneRef, err = calculateRefDesiredAPIVersion(cluster.Spec.ControlPlaneRef, controlPlane)
 	if err != nil {
@@ -578,17 +631,21 @@ func computeMachineDeployment(_ context.Context, s *scope.Scope, machineDeployme
 	if currentMachineDeployment != nil && currentMachineDeployment.BootstrapTemplate != nil {
 		currentBootstrapTemplateRef = currentMachineDeployment.Object.Spec.Template.Spec.Bootstrap.ConfigRef
 	}
-	desiredMachineDeployment.BootstrapTemplate = templateToTemplate(templateToInput{
+	var err error
+	desiredMachineDeployment.BootstrapTemplate, err = templateToTemplate(templateToInput{
 		template:              machineDeploymentBlueprint.BootstrapTemplate,
 		templateClonedFromRef: contract.ObjToRef(machineDeploymentBlueprint.BootstrapTemplate),
 		cluster:               s.Current.Cluster,
-		namePrefix:            bootstrapTemplateNamePrefix(s.Current.Cluster.Name, machineDeploymentTopology.Name),
+		nameGenerator:         names.BootstrapTemplateNameGenerator(machineDeploymentClass.NamingStrategy, s.Current.Cluster.Name, machineDeploymentTopology.Name),
 		currentObjectRef:      currentBootstrapTemplateRef,
 		// Note: we are adding an ownerRef to Cluster so the template will be automatically garbage collected
 		// in case of errors in between creating this template and creating/updating the MachineDeployment object
 		// with the reference to the ControlPlane object using this template.
 		ownerRef: ownerReferenceTo(s.Current.Cluster),
 	})
+	if err != nil {
+		return nil, err
+	}
 
 	bootstrapTemplateLabels := desiredMachineDeployment.BootstrapTemplate.GetLabels()
 	if bootstrapTemplateLabels == nil {
@@ -603,17 +660,20 @@ func computeMachineDeployment(_ context.Context, s *scope.Scope, machineDeployme
 	if currentMachineDeployment != nil && currentMachineDeployment.InfrastructureMachineTemplate != nil {
 		currentInfraMachineTemplateRef = &currentMachineDeployment.Object.Spec.Template.Spec.InfrastructureRef
 	}
-	desiredMachineDeployment.InfrastructureMachineTemplate = templateToTemplate(templateToInput{
+	desiredMachineDeployment.InfrastructureMachineTemplate, err = templateToTemplate(templateToInput{
 		template:              machineDeploymentBlueprint.InfrastructureMachineTemplate,
 		templateClonedFromRef: contract.ObjToRef(machineDeploymentBlueprint.InfrastructureMachineTemplate),
 		cluster:               s.Current.Cluster,
-		namePrefix:            infrastructureMachineTemplateNamePrefix(s.Current.Cluster.Name, machineDeploymentTopology.Name),
+		nameGenerator:         names.InfrastructureMachineTemplateNameGenerator(machineDeploymentClass.NamingStrategy, s.Current.Cluster.Name, machineDeploymentTopology.Name),
 		currentObjectRef:      currentInfraMachineTemplateRef,
 		// Note: we are adding an ownerRef to Cluster so the template will be automatically garbage collected
 		// in case of errors in between creating this template and creating/updating the MachineDeployment object
 		// with the reference to the ControlPlane object using this template.
 		ownerRef: ownerReferenceTo(s.Current.Cluster),
 	})
+	if err != nil {
+		return nil, err
+	}
 
 	infraMachineTemplateLabels := desiredMachineDeployment.InfrastructureMachineTemplate.GetLabels()
 	if infraMachineTemplateLabels == nil {
@@ -671,13 +731,14 @@ func computeMachineDeployment(_ context.Context, s *scope.Scope, machineDeployme
 			APIVersion: clusterv1.GroupVersion.String(),
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      names.SimpleNameGenerator.GenerateName(fmt.Sprintf("%s-%s-", s.Current.Cluster.Name, machineDeploymentTopology.Name)),
 			Namespace: s.Current.Cluster.Namespace,
 		},
 		Spec: clusterv1.MachineDeploymentSpec{
-			ClusterName:     s.Current.Cluster.Name,
-			MinReadySeconds: minReadySeconds,
-			Strategy:        strategy,
+			ClusterName:              s.Current.Cluster.Name,
+			MinReadySeconds:          minReadySeconds,
+			Strategy:                 strategy,
+			MachineSetNamingStrategy: machineDeploymentClass.MachineSetNamingStrategy,
+			MachineNamingStrategy:    machineDeploymentClass.MachineNamingStrategy,
 			Template: clusterv1.MachineTemplateSpec{
 				Spec: clusterv1.MachineSpec{
 					ClusterName:             s.Current.Cluster.Name,
@@ -693,24 +754,49 @@ func computeMachineDeployment(_ context.Context, s *scope.Scope, machineDeployme
 		},
 	}
 
-	// If an existing MachineDeployment is present, override the MachineDeployment generate name
-	// re-using the existing name (this will help in reconcile).
+	// If an existing MachineDeployment is present, re-use the existing name (this will help in reconcile),
+	// otherwise generate a new one.
 	if currentMachineDeployment != nil && currentMachineDeployment.Object != nil {
 		desiredMachineDeploymentObj.SetName(currentMachineDeployment.Object.Name)
+	} else {
+		name, err := names.MachineDeploymentNameGenerator(machineDeploymentClass.NamingStrategy, s.Current.Cluster.Name, machineDeploymentTopology.Name).GenerateName()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to generate name for MachineDeployment")
+		}
+		desiredMachineDeploymentObj.SetName(name)
+	}
+
+	clusterMetadata := clusterv1.ObjectMeta{}
+	if s.Current.Cluster.Spec.Metadata != nil {
+		clusterMetadata = *s.Current.Cluster.Spec.Metadata
 	}
 
 	// Apply annotations
-	machineDeploymentAnnotations := util.MergeMap(machineDeploymentTopology.Metadata.Annotations, machineDeploymentBlueprint.Metadata.Annotations)
+	// NOTE: Annotations from the topology and the ClusterClass take precedence over the ones from the Cluster metadata.
+	machineDeploymentAnnotations := util.MergeMap(machineDeploymentTopology.Metadata.Annotations, machineDeploymentBlueprint.Metadata.Annotations, clusterMetadata.Annotations)
 	// Ensure the annotations used to control the upgrade sequence are never propagated.
 	delete(machineDeploymentAnnotations, clusterv1.ClusterTopologyHoldUpgradeSequenceAnnotation)
 	delete(machineDeploymentAnnotations, clusterv1.ClusterTopologyDeferUpgradeAnnotation)
-	desiredMachineDeploymentObj.SetAnnotations(machineDeploymentAnnotations)
 	desiredMachineDeploymentObj.Spec.Template.Annotations = machineDeploymentAnnotations
 
+	// Add the capacity annotations used by the autoscaler to scale the MachineDeployment from zero.
+	// NOTE: Capacity annotations from the autoscaling variable take precedence over the ones from the metadata, which
+	// take precedence over the ones from the InfrastructureMachineTemplate. Capacity annotations are only set on the
+	// MachineDeployment, given that the autoscaler reads them from the MachineDeployment and not from the Machines.
+	capacityAnnotations, err := computeMachineDeploymentCapacityAnnotations(s, machineDeploymentTopology, machineDeploymentClass)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compute capacity annotations for MachineDeployment topology %s", machineDeploymentTopology.Name)
+	}
+	desiredMachineDeploymentObj.SetAnnotations(util.MergeMap(
+		capacityAnnotations,
+		machineDeploymentAnnotations,
+		infrastructureMachineTemplateCapacityAnnotations(machineDeploymentBlueprint.InfrastructureMachineTemplate),
+	))
+
 	// Apply Labels
 	// NOTE: On top of all the labels applied to managed objects we are applying the ClusterTopologyMachineDeploymentLabel
 	// keeping track of the MachineDeployment name from the Topology; this will be used to identify the object in next reconcile loops.
-	machineDeploymentLabels := util.MergeMap(machineDeploymentTopology.Metadata.Labels, machineDeploymentBlueprint.Metadata.Labels)
+	machineDeploymentLabels := util.MergeMap(machineDeploymentTopology.Metadata.Labels, machineDeploymentBlueprint.Metadata.Labels, clusterMetadata.Labels)
 	if machineDeploymentLabels == nil {
 		machineDeploymentLabels = map[string]string{}
 	}
@@ -749,6 +835,87 @@ func computeMachineDeployment(_ context.Context, s *scope.Scope, machineDeployme
 	return desiredMachineDeployment, nil
 }
 
+// capacityVariableProperties maps the properties of the autoscaling capacity variable to the corresponding
+// capacity annotations.
+var capacityVariableProperties = map[string]string{
+	"cpu":           clusterv1.AutoscalerCapacityCPUAnnotation,
+	"memory":        clusterv1.AutoscalerCapacityMemoryAnnotation,
+	"ephemeralDisk": clusterv1.AutoscalerCapacityEphemeralDiskAnnotation,
+	"gpuType":       clusterv1.AutoscalerCapacityGPUTypeAnnotation,
+	"gpuCount":      clusterv1.AutoscalerCapacityGPUCountAnnotation,
+	"maxPods":       clusterv1.AutoscalerCapacityMaxPodsAnnotation,
+}
+
+// computeMachineDeploymentCapacityAnnotations computes the capacity annotations for a MachineDeployment from the
+// value of the capacity variable of the MachineDeploymentClass, if any.
+// NOTE: The value of the variable from the MachineDeployment topology overrides take precedence over the value
+// from the Cluster topology.
+func computeMachineDeploymentCapacityAnnotations(s *scope.Scope, machineDeploymentTopology clusterv1.MachineDeploymentTopology, machineDeploymentClass *clusterv1.MachineDeploymentClass) (map[string]string, error) {
+	if machineDeploymentClass.Autoscaling == nil || machineDeploymentClass.Autoscaling.CapacityVariable == nil {
+		return nil, nil
+	}
+	variableName := *machineDeploymentClass.Autoscaling.CapacityVariable
+
+	var value *apiextensionsv1.JSON
+	if s.Blueprint.Topology != nil {
+		for i := range s.Blueprint.Topology.Variables {
+			if s.Blueprint.Topology.Variables[i].Name == variableName {
+				value = &s.Blueprint.Topology.Variables[i].Value
+				break
+			}
+		}
+	}
+	if machineDeploymentTopology.Variables != nil {
+		for i := range machineDeploymentTopology.Variables.Overrides {
+			if machineDeploymentTopology.Variables.Overrides[i].Name == variableName {
+				value = &machineDeploymentTopology.Variables.Overrides[i].Value
+				break
+			}
+		}
+	}
+	if value == nil {
+		return nil, nil
+	}
+
+	properties := map[string]interface{}{}
+	decoder := json.NewDecoder(bytes.NewReader(value.Raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&properties); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the value of variable %q", variableName)
+	}
+
+	annotations := map[string]string{}
+	for property, v := range properties {
+		annotation, ok := capacityVariableProperties[property]
+		if !ok {
+			return nil, errors.Errorf("invalid property %q in the value of variable %q", property, variableName)
+		}
+		switch v := v.(type) {
+		case string:
+			annotations[annotation] = v
+		case json.Number:
+			annotations[annotation] = v.String()
+		default:
+			return nil, errors.Errorf("invalid value for property %q in the value of variable %q: must be a string or a number", property, variableName)
+		}
+	}
+	return annotations, nil
+}
+
+// infrastructureMachineTemplateCapacityAnnotations returns the capacity annotations of an InfrastructureMachineTemplate.
+func infrastructureMachineTemplateCapacityAnnotations(infrastructureMachineTemplate *unstructured.Unstructured) map[string]string {
+	if infrastructureMachineTemplate == nil {
+		return nil
+	}
+	annotations := map[string]string{}
+	for k, v := range infrastructureMachineTemplate.GetAnnotations() {
+		if strings.HasPrefix(k, clusterv1.AutoscalerCapacityAnnotationPrefix) {
+			annotations[k] = v
+		}
+	}
+	return annotations
+}
+
 // computeMachineDeploymentVersion calculates the version of the desired machine deployment.
 // The version is calculated using the state of the current machine deployments,
 // the current control plane and the version defined in the topology.
@@ -832,6 +999,12 @@ func isControlPlaneStable(s *scope.Scope) bool {
 		return false
 	}
 
+	// If the rollout of the ControlPlane is held by the rollout policy of the ClusterClass then it is not yet
+	// at the desired state and cannot be considered stable.
+	if s.UpgradeTracker.ControlPlane.IsPendingRollout {
+		return false
+	}
+
 	return true
 }
 
@@ -874,7 +1047,7 @@ type templateToInput struct {
 	template              *unstructured.Unstructured
 	templateClonedFromRef *corev1.ObjectReference
 	cluster               *clusterv1.Cluster
-	namePrefix            string
+	nameGenerator         names.NameGenerator
 	currentObjectRef      *corev1.ObjectReference
 	labels                map[string]string
 	annotations           map[string]string
@@ -914,9 +1087,14 @@ func templateToObject(in templateToInput) (*unstructured.Unstructured, error) {
 	// Ensure the generated objects have a meaningful name.
 	// NOTE: In case there is already a ref to this object in the Cluster, re-use the same name
 	// in order to simplify compare at later stages of the reconcile process.
-	object.SetName(names.SimpleNameGenerator.GenerateName(in.namePrefix))
 	if in.currentObjectRef != nil && len(in.currentObjectRef.Name) > 0 {
 		object.SetName(in.currentObjectRef.Name)
+	} else {
+		name, err := in.nameGenerator.GenerateName()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to generate name for %s", object.GetKind())
+		}
+		object.SetName(name)
 	}
 
 	return object, nil
@@ -927,7 +1105,7 @@ func templateToObject(in templateToInput) (*unstructured.Unstructured, error) {
 // and assigning a meaningful name (or reusing current reference name).
 // NOTE: We are creating a copy of the ClusterClass template for each cluster so
 // it is possible to add cluster specific information without affecting the original object.
-func templateToTemplate(in templateToInput) *unstructured.Unstructured {
+func templateToTemplate(in templateToInput) (*unstructured.Unstructured, error) {
 	template := &unstructured.Unstructured{}
 	in.template.DeepCopyInto(template)
 
@@ -974,12 +1152,92 @@ func templateToTemplate(in templateToInput) *unstructured.Unstructured {
 	// Ensure the generated template gets a meaningful name.
 	// NOTE: In case there is already an object ref to this template, it is required to re-use the same name
 	// in order to simplify compare at later stages of the reconcile process.
-	template.SetName(names.SimpleNameGenerator.GenerateName(in.namePrefix))
 	if in.currentObjectRef != nil && len(in.currentObjectRef.Name) > 0 {
 		template.SetName(in.currentObjectRef.Name)
+	} else {
+		name, err := in.nameGenerator.GenerateName()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to generate name for %s", template.GetKind())
+		}
+		template.SetName(name)
 	}
 
-	return template
+	return template, nil
+}
+
+// computeClusterResourceSets computes the desired state of the ClusterResourceSets for the add-ons defined in the
+// ClusterClass. A ClusterResourceSet selecting only the current Cluster is computed for each add-on which is enabled
+// according to its enabledIf template and the variables of the Cluster.
+func computeClusterResourceSets(_ context.Context, s *scope.Scope) (map[string]*addonsv1.ClusterResourceSet, error) {
+	addons := s.Blueprint.ClusterClass.Spec.Addons
+	if len(addons) == 0 {
+		return nil, nil
+	}
+
+	// Calculate the variables available to the enabledIf templates; those are the same variables
+	// available to inline patches.
+	variableDefinitions := map[string]bool{}
+	for _, definitionsWithName := range s.Blueprint.ClusterClass.Status.Variables {
+		for _, definition := range definitionsWithName.Definitions {
+			if definition.From == clusterv1.VariableDefinitionFromInline {
+				variableDefinitions[definitionsWithName.Name] = true
+			}
+		}
+	}
+	globalVariables, err := variables.Global(s.Blueprint.Topology, s.Current.Cluster, clusterv1.VariableDefinitionFromInline, variableDefinitions)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to calculate global variables")
+	}
+	variableMap := variables.ToMap(globalVariables)
+
+	cluster := s.Current.Cluster
+	clusterResourceSets := map[string]*addonsv1.ClusterResourceSet{}
+	for _, addon := range addons {
+		enabled, err := inline.IsEnabled(addon.EnabledIf, variableMap)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to calculate if add-on %q is enabled", addon.Name)
+		}
+		if !enabled {
+			continue
+		}
+
+		crs := &addonsv1.ClusterResourceSet{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "ClusterResourceSet",
+				APIVersion: addonsv1.GroupVersion.String(),
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("%s-%s", cluster.Name, addon.Name),
+				Namespace: cluster.Namespace,
+				Labels: map[string]string{
+					clusterv1.ClusterNameLabel:              cluster.Name,
+					clusterv1.ClusterTopologyOwnedLabel:     "",
+					clusterv1.ClusterTopologyAddonNameLabel: addon.Name,
+				},
+				OwnerReferences: []metav1.OwnerReference{*ownerReferenceTo(cluster)},
+			},
+			Spec: addonsv1.ClusterResourceSetSpec{
+				// The selector only matches the current Cluster, using the label set by the topology controller.
+				ClusterSelector: metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						clusterv1.ClusterNameLabel: cluster.Name,
+					},
+				},
+				Strategy: addon.ClusterResourceSet.Strategy,
+			},
+		}
+		if crs.Spec.Strategy == "" {
+			crs.Spec.SetTypedStrategy(addonsv1.ClusterResourceSetStrategyApplyOnce)
+		}
+		for _, resource := range addon.ClusterResourceSet.Resources {
+			crs.Spec.Resources = append(crs.Spec.Resources, addonsv1.ResourceRef{
+				Name: resource.Name,
+				Kind: resource.Kind,
+			})
+		}
+		clusterResourceSets[addon.Name] = crs
+	}
+	return clusterResourceSets, nil
 }
 
 func ownerReferenceTo(obj client.Object) *metav1.OwnerReference {
@@ -24,6 +24,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -32,6 +33,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
 	runtimev1 "sigs.k8s.io/cluster-api/exp/runtime/api/v1alpha1"
 	runtimecatalog "sigs.k8s.io/cluster-api/exp/runtime/catalog"
 	runtimehooksv1 "sigs.k8s.io/cluster-api/exp/runtime/hooks/api/v1alpha1"
@@ -41,6 +43,7 @@ import (
 	"sigs.k8s.io/cluster-api/internal/hooks"
 	fakeruntimeclient "sigs.k8s.io/cluster-api/internal/runtime/client/fake"
 	"sigs.k8s.io/cluster-api/internal/test/builder"
+	"sigs.k8s.io/cluster-api/internal/topology/names"
 	"sigs.k8s.io/cluster-api/util"
 )
 
@@ -106,6 +109,32 @@ func TestComputeInfrastructureCluster(t *testing.T) {
 		// Ensure no ownership is added to generated InfrastructureCluster.
 		g.Expect(obj.GetOwnerReferences()).To(BeEmpty())
 	})
+	t.Run("Generates the infrastructureCluster with the Cluster metadata", func(t *testing.T) {
+		g := NewWithT(t)
+
+		clusterWithMetadata := cluster.DeepCopy()
+		clusterWithMetadata.Spec.Metadata = &clusterv1.ObjectMeta{
+			Labels:      map[string]string{"l1": "cluster"},
+			Annotations: map[string]string{"a1": "cluster"},
+		}
+
+		scope := scope.New(clusterWithMetadata)
+		scope.Blueprint = blueprint
+
+		obj, err := computeInfrastructureCluster(ctx, scope)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(obj).ToNot(BeNil())
+
+		assertTemplateToObject(g, assertTemplateInput{
+			cluster:     scope.Current.Cluster,
+			templateRef: blueprint.ClusterClass.Spec.Infrastructure.Ref,
+			template:    blueprint.InfrastructureClusterTemplate,
+			labels:      map[string]string{"l1": "cluster"},
+			annotations: map[string]string{"a1": "cluster"},
+			currentRef:  nil,
+			obj:         obj,
+		})
+	})
 	t.Run("If there is already a reference to the infrastructureCluster, it preserves the reference name", func(t *testing.T) {
 		g := NewWithT(t)
 
@@ -343,6 +372,43 @@ func TestComputeControlPlane(t *testing.T) {
 		// Ensure no ownership is added to generated ControlPlane.
 		g.Expect(obj.GetOwnerReferences()).To(BeEmpty())
 	})
+	t.Run("Generates the ControlPlane with the Cluster metadata", func(t *testing.T) {
+		g := NewWithT(t)
+
+		clusterWithMetadata := cluster.DeepCopy()
+		clusterWithMetadata.Spec.Metadata = &clusterv1.ObjectMeta{
+			Labels:      map[string]string{"l1": "cluster", "l3": ""},
+			Annotations: map[string]string{"a3": ""},
+		}
+
+		blueprint := &scope.ClusterBlueprint{
+			Topology:     clusterWithMetadata.Spec.Topology,
+			ClusterClass: clusterClass,
+			ControlPlane: &scope.ControlPlaneBlueprint{
+				Template: controlPlaneTemplate,
+			},
+		}
+
+		scope := scope.New(clusterWithMetadata)
+		scope.Blueprint = blueprint
+
+		r := &Reconciler{}
+
+		obj, err := r.computeControlPlane(ctx, scope, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(obj).ToNot(BeNil())
+
+		assertTemplateToObject(g, assertTemplateInput{
+			cluster:     scope.Current.Cluster,
+			templateRef: blueprint.ClusterClass.Spec.ControlPlane.Ref,
+			template:    blueprint.ControlPlane.Template,
+			currentRef:  nil,
+			obj:         obj,
+			// The label from the ClusterClass takes precedence over the one from the Cluster metadata.
+			labels:      map[string]string{"l1": "", "l2": "", "l3": ""},
+			annotations: map[string]string{"a1": "", "a2": "", "a3": ""},
+		})
+	})
 	t.Run("Generates the ControlPlane from the template using ClusterClass defaults", func(t *testing.T) {
 		g := NewWithT(t)
 
@@ -1290,6 +1356,153 @@ func TestComputeCluster(t *testing.T) {
 	// Spec
 	g.Expect(obj.Spec.InfrastructureRef).To(Equal(contract.ObjToRef(infrastructureCluster)))
 	g.Expect(obj.Spec.ControlPlaneRef).To(Equal(contract.ObjToRef(controlPlane)))
+	g.Expect(obj.Spec.ControlPlaneProvidesInfrastructure).To(BeFalse())
+}
+
+func TestComputeClusterResourceSets(t *testing.T) {
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster1",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: clusterv1.ClusterSpec{
+			Topology: &clusterv1.Topology{
+				Class:   "class1",
+				Version: "v1.27.3",
+				Variables: []clusterv1.ClusterVariable{
+					{Name: "installCSI", Value: apiextensionsv1.JSON{Raw: []byte(`false`)}},
+				},
+			},
+		},
+	}
+	clusterClass := &clusterv1.ClusterClass{
+		Spec: clusterv1.ClusterClassSpec{
+			Addons: []clusterv1.ClusterClassAddon{
+				{
+					Name: "cni",
+					ClusterResourceSet: clusterv1.ClusterClassAddonClusterResourceSet{
+						Resources: []clusterv1.ClusterClassAddonResource{{Name: "calico", Kind: "ConfigMap"}},
+						Strategy:  string(addonsv1.ClusterResourceSetStrategyReconcile),
+					},
+				},
+				{
+					Name:      "csi",
+					EnabledIf: pointer.String(`{{ .installCSI }}`),
+					ClusterResourceSet: clusterv1.ClusterClassAddonClusterResourceSet{
+						Resources: []clusterv1.ClusterClassAddonResource{{Name: "csi-driver", Kind: "Secret"}},
+					},
+				},
+				{
+					Name:      "dns",
+					EnabledIf: pointer.String(`{{ eq .builtin.cluster.topology.version "v1.27.3" }}`),
+					ClusterResourceSet: clusterv1.ClusterClassAddonClusterResourceSet{
+						Resources: []clusterv1.ClusterClassAddonResource{{Name: "coredns", Kind: "ConfigMap"}},
+					},
+				},
+			},
+		},
+		Status: clusterv1.ClusterClassStatus{
+			Variables: []clusterv1.ClusterClassStatusVariable{
+				{
+					Name:        "installCSI",
+					Definitions: []clusterv1.ClusterClassStatusVariableDefinition{{From: clusterv1.VariableDefinitionFromInline}},
+				},
+			},
+		},
+	}
+
+	t.Run("Computes the ClusterResourceSets for the enabled add-ons", func(t *testing.T) {
+		g := NewWithT(t)
+
+		s := scope.New(cluster.DeepCopy())
+		s.Blueprint = &scope.ClusterBlueprint{
+			Topology:     cluster.Spec.Topology,
+			ClusterClass: clusterClass,
+		}
+
+		got, err := computeClusterResourceSets(ctx, s)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(got).To(HaveLen(2))
+		g.Expect(got).To(HaveKey("cni"))
+		g.Expect(got).To(HaveKey("dns"))
+
+		crs := got["cni"]
+		g.Expect(crs.Name).To(Equal("cluster1-cni"))
+		g.Expect(crs.Namespace).To(Equal(metav1.NamespaceDefault))
+		g.Expect(crs.Labels).To(Equal(map[string]string{
+			clusterv1.ClusterNameLabel:              "cluster1",
+			clusterv1.ClusterTopologyOwnedLabel:     "",
+			clusterv1.ClusterTopologyAddonNameLabel: "cni",
+		}))
+		g.Expect(crs.OwnerReferences).To(HaveLen(1))
+		g.Expect(crs.OwnerReferences[0].Kind).To(Equal("Cluster"))
+		g.Expect(crs.Spec.ClusterSelector.MatchLabels).To(Equal(map[string]string{clusterv1.ClusterNameLabel: "cluster1"}))
+		g.Expect(crs.Spec.Resources).To(Equal([]addonsv1.ResourceRef{{Name: "calico", Kind: "ConfigMap"}}))
+		g.Expect(crs.Spec.Strategy).To(Equal(string(addonsv1.ClusterResourceSetStrategyReconcile)))
+
+		// The strategy defaults to ApplyOnce.
+		g.Expect(got["dns"].Spec.Strategy).To(Equal(string(addonsv1.ClusterResourceSetStrategyApplyOnce)))
+	})
+
+	t.Run("Computes the ClusterResourceSets for add-ons enabled by variables", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := cluster.DeepCopy()
+		c.Spec.Topology.Variables[0].Value = apiextensionsv1.JSON{Raw: []byte(`true`)}
+		s := scope.New(c)
+		s.Blueprint = &scope.ClusterBlueprint{
+			Topology:     c.Spec.Topology,
+			ClusterClass: clusterClass,
+		}
+
+		got, err := computeClusterResourceSets(ctx, s)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(got).To(HaveLen(3))
+		g.Expect(got).To(HaveKey("csi"))
+		g.Expect(got["csi"].Spec.Resources).To(Equal([]addonsv1.ResourceRef{{Name: "csi-driver", Kind: "Secret"}}))
+	})
+
+	t.Run("Returns nothing if the ClusterClass has no add-ons", func(t *testing.T) {
+		g := NewWithT(t)
+
+		s := scope.New(cluster.DeepCopy())
+		s.Blueprint = &scope.ClusterBlueprint{
+			Topology:     cluster.Spec.Topology,
+			ClusterClass: &clusterv1.ClusterClass{},
+		}
+
+		got, err := computeClusterResourceSets(ctx, s)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(got).To(BeEmpty())
+	})
+}
+
+func TestComputeClusterWithoutInfrastructureCluster(t *testing.T) {
+	g := NewWithT(t)
+
+	// generated objects
+	controlPlane := builder.ControlPlane(metav1.NamespaceDefault, "controlplane1").
+		Build()
+
+	// current cluster objects
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster1",
+			Namespace: metav1.NamespaceDefault,
+		},
+	}
+
+	// aggregating current cluster objects into ClusterState (simulating getCurrentState)
+	scope := scope.New(cluster)
+
+	obj, err := computeCluster(ctx, scope, nil, controlPlane)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(obj).ToNot(BeNil())
+
+	// Spec
+	g.Expect(obj.Spec.InfrastructureRef).To(BeNil())
+	g.Expect(obj.Spec.ControlPlaneRef).To(Equal(contract.ObjToRef(controlPlane)))
+	g.Expect(obj.Spec.ControlPlaneProvidesInfrastructure).To(BeTrue())
 }
 
 func TestComputeMachineDeployment(t *testing.T) {
@@ -1465,6 +1678,41 @@ func TestComputeMachineDeployment(t *testing.T) {
 		g.Expect(actualMd.Spec.Template.Spec.InfrastructureRef.Name).ToNot(Equal("linux-worker-inframachinetemplate"))
 		g.Expect(actualMd.Spec.Template.Spec.Bootstrap.ConfigRef.Name).ToNot(Equal("linux-worker-bootstraptemplate"))
 	})
+	t.Run("Generates the machine deployment with the Cluster metadata", func(t *testing.T) {
+		g := NewWithT(t)
+		clusterWithMetadata := cluster.DeepCopy()
+		clusterWithMetadata.Spec.Metadata = &clusterv1.ObjectMeta{
+			Labels:      map[string]string{"fooLabel": "cluster", "clusterLabel": ""},
+			Annotations: map[string]string{"fooAnnotation": "cluster", "clusterAnnotation": ""},
+		}
+		scope := scope.New(clusterWithMetadata)
+		scope.Blueprint = blueprint
+
+		actual, err := computeMachineDeployment(ctx, scope, mdTopology)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		actualMd := actual.Object
+		expectedAnnotations := map[string]string{
+			"fizzAnnotation": "buzz",
+			// The annotation from the topology takes precedence over the one from the Cluster metadata.
+			"fooAnnotation":     "baz",
+			"clusterAnnotation": "",
+		}
+		g.Expect(actualMd.Annotations).To(Equal(expectedAnnotations))
+		g.Expect(actualMd.Spec.Template.ObjectMeta.Annotations).To(Equal(expectedAnnotations))
+
+		expectedLabels := map[string]string{
+			"fizzLabel": "buzz",
+			// The label from the topology takes precedence over the one from the Cluster metadata.
+			"fooLabel":                          "baz",
+			"clusterLabel":                      "",
+			clusterv1.ClusterNameLabel:          cluster.Name,
+			clusterv1.ClusterTopologyOwnedLabel: "",
+			clusterv1.ClusterTopologyMachineDeploymentNameLabel: "big-pool-of-machines",
+		}
+		g.Expect(actualMd.Labels).To(Equal(expectedLabels))
+		g.Expect(actualMd.Spec.Template.ObjectMeta.Labels).To(Equal(expectedLabels))
+	})
 	t.Run("Generates the machine deployment and the referenced templates using ClusterClass defaults", func(t *testing.T) {
 		g := NewWithT(t)
 		scope := scope.New(cluster)
@@ -1493,6 +1741,97 @@ func TestComputeMachineDeployment(t *testing.T) {
 		g.Expect(*actualMd.Spec.Template.Spec.NodeDeletionTimeout).To(Equal(clusterClassDuration))
 	})
 
+	t.Run("Generates the machine deployment and the referenced templates using the ClusterClass naming strategies", func(t *testing.T) {
+		g := NewWithT(t)
+
+		mdClass := md1.DeepCopy()
+		mdClass.NamingStrategy = &clusterv1.MachineDeploymentClassNamingStrategy{Template: pointer.String("{{ .machineDeployment.topologyName }}-{{ .random }}")}
+		mdClass.MachineSetNamingStrategy = &clusterv1.MachineSetNamingStrategy{Template: pointer.String("{{ .machineDeployment.name }}-ms-{{ .random }}")}
+		mdClass.MachineNamingStrategy = &clusterv1.MachineNamingStrategy{Template: pointer.String("{{ .cluster.name }}-{{ .random }}")}
+		s := scope.New(cluster)
+		s.Blueprint = &scope.ClusterBlueprint{
+			Topology: cluster.Spec.Topology,
+			ClusterClass: builder.ClusterClass(metav1.NamespaceDefault, "class1").
+				WithWorkerMachineDeploymentClasses(*mdClass).
+				Build(),
+			MachineDeployments: blueprint.MachineDeployments,
+		}
+
+		actual, err := computeMachineDeployment(ctx, s, mdTopology)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(actual.Object.Name).To(MatchRegexp("^big-pool-of-machines-[a-z0-9]{5}$"))
+		g.Expect(actual.BootstrapTemplate.GetName()).To(MatchRegexp("^big-pool-of-machines-[a-z0-9]{5}$"))
+		g.Expect(actual.InfrastructureMachineTemplate.GetName()).To(MatchRegexp("^big-pool-of-machines-[a-z0-9]{5}$"))
+		g.Expect(actual.Object.Spec.MachineSetNamingStrategy).To(Equal(mdClass.MachineSetNamingStrategy))
+		g.Expect(actual.Object.Spec.MachineNamingStrategy).To(Equal(mdClass.MachineNamingStrategy))
+	})
+
+	t.Run("Generates the machine deployment with the capacity annotations for the autoscaler", func(t *testing.T) {
+		g := NewWithT(t)
+
+		infrastructureMachineTemplate := workerInfrastructureMachineTemplate.DeepCopy()
+		infrastructureMachineTemplate.SetAnnotations(map[string]string{
+			clusterv1.AutoscalerCapacityCPUAnnotation:     "2",
+			clusterv1.AutoscalerCapacityMemoryAnnotation:  "4G",
+			clusterv1.AutoscalerCapacityGPUTypeAnnotation: "nvidia.com/gpu",
+			"some-other-annotation":                       "",
+		})
+		mdBlueprint := &scope.MachineDeploymentBlueprint{
+			Metadata: clusterv1.ObjectMeta{
+				Annotations: map[string]string{
+					// Should overwrite the annotation from the InfrastructureMachineTemplate.
+					clusterv1.AutoscalerCapacityMemoryAnnotation: "8G",
+				},
+			},
+			BootstrapTemplate:             workerBootstrapTemplate,
+			InfrastructureMachineTemplate: infrastructureMachineTemplate,
+		}
+
+		mdClass := md1.DeepCopy()
+		mdClass.Autoscaling = &clusterv1.MachineDeploymentClassAutoscaling{CapacityVariable: pointer.String("capacity")}
+
+		clusterWithVariables := cluster.DeepCopy()
+		clusterWithVariables.Spec.Topology.Variables = []clusterv1.ClusterVariable{
+			{Name: "capacity", Value: apiextensionsv1.JSON{Raw: []byte(`{"gpuCount": 1, "maxPods": "110"}`)}},
+		}
+		mdTopologyWithOverrides := mdTopology.DeepCopy()
+		mdTopologyWithOverrides.Variables = &clusterv1.MachineDeploymentVariables{
+			Overrides: []clusterv1.ClusterVariable{
+				// Should overwrite the value from the Cluster topology.
+				{Name: "capacity", Value: apiextensionsv1.JSON{Raw: []byte(`{"gpuCount": 2}`)}},
+			},
+		}
+
+		s := scope.New(clusterWithVariables)
+		s.Blueprint = &scope.ClusterBlueprint{
+			Topology: clusterWithVariables.Spec.Topology,
+			ClusterClass: builder.ClusterClass(metav1.NamespaceDefault, "class1").
+				WithWorkerMachineDeploymentClasses(*mdClass).
+				Build(),
+			MachineDeployments: map[string]*scope.MachineDeploymentBlueprint{"linux-worker": mdBlueprint},
+		}
+
+		actual, err := computeMachineDeployment(ctx, s, *mdTopologyWithOverrides)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		annotations := actual.Object.GetAnnotations()
+		g.Expect(annotations).To(HaveKeyWithValue(clusterv1.AutoscalerCapacityCPUAnnotation, "2"))
+		g.Expect(annotations).To(HaveKeyWithValue(clusterv1.AutoscalerCapacityMemoryAnnotation, "8G"))
+		g.Expect(annotations).To(HaveKeyWithValue(clusterv1.AutoscalerCapacityGPUTypeAnnotation, "nvidia.com/gpu"))
+		g.Expect(annotations).To(HaveKeyWithValue(clusterv1.AutoscalerCapacityGPUCountAnnotation, "2"))
+		g.Expect(annotations).ToNot(HaveKey(clusterv1.AutoscalerCapacityMaxPodsAnnotation))
+		g.Expect(annotations).ToNot(HaveKey("some-other-annotation"))
+		// Capacity annotations from the InfrastructureMachineTemplate and the variable are not propagated to the Machines.
+		g.Expect(actual.Object.Spec.Template.Annotations).ToNot(HaveKey(clusterv1.AutoscalerCapacityCPUAnnotation))
+		g.Expect(actual.Object.Spec.Template.Annotations).ToNot(HaveKey(clusterv1.AutoscalerCapacityGPUCountAnnotation))
+
+		// An invalid value of the variable should return an error.
+		mdTopologyWithOverrides.Variables.Overrides[0].Value = apiextensionsv1.JSON{Raw: []byte(`{"gpus": 2}`)}
+		_, err = computeMachineDeployment(ctx, s, *mdTopologyWithOverrides)
+		g.Expect(err).To(HaveOccurred())
+	})
+
 	t.Run("If there is already a machine deployment, it preserves the object name and the reference names", func(t *testing.T) {
 		g := NewWithT(t)
 		s := scope.New(cluster)
@@ -1995,7 +2334,7 @@ func TestTemplateToObject(t *testing.T) {
 			template:              template,
 			templateClonedFromRef: fakeRef1,
 			cluster:               cluster,
-			namePrefix:            cluster.Name,
+			nameGenerator:         names.SimpleNameGenerator(cluster.Name),
 			currentObjectRef:      nil,
 		})
 		g.Expect(err).ToNot(HaveOccurred())
@@ -2015,7 +2354,7 @@ func TestTemplateToObject(t *testing.T) {
 			template:              template,
 			templateClonedFromRef: fakeRef1,
 			cluster:               cluster,
-			namePrefix:            cluster.Name,
+			nameGenerator:         names.SimpleNameGenerator(cluster.Name),
 			currentObjectRef:      fakeRef2,
 		})
 		g.Expect(err).ToNot(HaveOccurred())
@@ -2052,13 +2391,14 @@ func TestTemplateToTemplate(t *testing.T) {
 
 	t.Run("Generates a template from a template", func(t *testing.T) {
 		g := NewWithT(t)
-		obj := templateToTemplate(templateToInput{
+		obj, err := templateToTemplate(templateToInput{
 			template:              template,
 			templateClonedFromRef: fakeRef1,
 			cluster:               cluster,
-			namePrefix:            cluster.Name,
+			nameGenerator:         names.SimpleNameGenerator(cluster.Name),
 			currentObjectRef:      nil,
 		})
+		g.Expect(err).ToNot(HaveOccurred())
 		g.Expect(obj).ToNot(BeNil())
 		assertTemplateToTemplate(g, assertTemplateInput{
 			cluster:     cluster,
@@ -2070,13 +2410,14 @@ func TestTemplateToTemplate(t *testing.T) {
 	})
 	t.Run("Overrides the generated name if there is already a reference", func(t *testing.T) {
 		g := NewWithT(t)
-		obj := templateToTemplate(templateToInput{
+		obj, err := templateToTemplate(templateToInput{
 			template:              template,
 			templateClonedFromRef: fakeRef1,
 			cluster:               cluster,
-			namePrefix:            cluster.Name,
+			nameGenerator:         names.SimpleNameGenerator(cluster.Name),
 			currentObjectRef:      fakeRef2,
 		})
+		g.Expect(err).ToNot(HaveOccurred())
 		g.Expect(obj).ToNot(BeNil())
 		assertTemplateToTemplate(g, assertTemplateInput{
 			cluster:     cluster,
@@ -196,7 +196,8 @@ func addVariablesForPatch(blueprint *scope.ClusterBlueprint, desired *scope.Clus
 			}
 
 			// Calculate MachineDeployment variables.
-			mdVariables, err := variables.MachineDeployment(mdTopology, md.Object, md.BootstrapTemplate, md.InfrastructureMachineTemplate, definitionFrom, patchVariableDefinitions)
+			mdVariables, err := variables.MachineDeployment(mdTopology, md.Object, md.BootstrapTemplate, md.InfrastructureMachineTemplate, definitionFrom,
+				machineDeploymentDefinitionsForPatch(blueprint, mdTopology.Class, definitionFrom, patchVariableDefinitions))
 			if err != nil {
 				return errors.Wrapf(err, "failed to calculate variables for %s", klog.KObj(md.Object))
 			}
@@ -228,18 +229,20 @@ func getMDTopologyFromMD(blueprint *scope.ClusterBlueprint, md *clusterv1.Machin
 func createRequest(blueprint *scope.ClusterBlueprint, desired *scope.ClusterState) (*runtimehooksv1.GeneratePatchesRequest, error) {
 	req := &runtimehooksv1.GeneratePatchesRequest{}
 
-	// Add the InfrastructureClusterTemplate.
-	t, err := newRequestItemBuilder(blueprint.InfrastructureClusterTemplate).
-		WithHolder(desired.Cluster, "spec.infrastructureRef").
-		Build()
-	if err != nil {
-		return nil, errors.Wrapf(err, "failed to prepare InfrastructureCluster template %s for patching",
-			tlog.KObj{Obj: blueprint.InfrastructureClusterTemplate})
+	// Add the InfrastructureClusterTemplate, if any.
+	if blueprint.HasInfrastructureCluster() {
+		t, err := newRequestItemBuilder(blueprint.InfrastructureClusterTemplate).
+			WithHolder(desired.Cluster, "spec.infrastructureRef").
+			Build()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to prepare InfrastructureCluster template %s for patching",
+				tlog.KObj{Obj: blueprint.InfrastructureClusterTemplate})
+		}
+		req.Items = append(req.Items, *t)
 	}
-	req.Items = append(req.Items, *t)
 
 	// Add the ControlPlaneTemplate.
-	t, err = newRequestItemBuilder(blueprint.ControlPlane.Template).
+	t, err := newRequestItemBuilder(blueprint.ControlPlane.Template).
 		WithHolder(desired.Cluster, "spec.controlPlaneRef").
 		Build()
 	if err != nil {
@@ -426,13 +429,15 @@ func convertToValidationRequest(generateRequest *runtimehooksv1.GeneratePatchesR
 func updateDesiredState(ctx context.Context, req *runtimehooksv1.GeneratePatchesRequest, blueprint *scope.ClusterBlueprint, desired *scope.ClusterState) error {
 	var err error
 
-	// Update the InfrastructureCluster.
-	infrastructureClusterTemplate, err := getTemplateAsUnstructured(req, "Cluster", "spec.infrastructureRef", "")
-	if err != nil {
-		return err
-	}
-	if err := patchObject(ctx, desired.InfrastructureCluster, infrastructureClusterTemplate); err != nil {
-		return err
+	// Update the InfrastructureCluster, if any.
+	if blueprint.HasInfrastructureCluster() {
+		infrastructureClusterTemplate, err := getTemplateAsUnstructured(req, "Cluster", "spec.infrastructureRef", "")
+		if err != nil {
+			return err
+		}
+		if err := patchObject(ctx, desired.InfrastructureCluster, infrastructureClusterTemplate); err != nil {
+			return err
+		}
 	}
 
 	// Update the ControlPlane.
@@ -500,3 +505,31 @@ func definitionsForPatch(blueprint *scope.ClusterBlueprint, definitionFrom strin
 	}
 	return variableDefinitionsForPatch
 }
+
+// machineDeploymentDefinitionsForPatch returns the variable definitions for a patch for MachineDeployments of the
+// given MachineDeploymentClass. Variables defined in the MachineDeploymentClass are only available to inline patches.
+func machineDeploymentDefinitionsForPatch(blueprint *scope.ClusterBlueprint, mdClass, definitionFrom string, patchVariableDefinitions map[string]bool) map[string]bool {
+	if definitionFrom != clusterv1.VariableDefinitionFromInline {
+		return patchVariableDefinitions
+	}
+
+	var classVariables []clusterv1.ClusterClassVariable
+	for _, mdc := range blueprint.ClusterClass.Spec.Workers.MachineDeployments {
+		if mdc.Class == mdClass {
+			classVariables = mdc.Variables
+			break
+		}
+	}
+	if len(classVariables) == 0 {
+		return patchVariableDefinitions
+	}
+
+	variableDefinitionsForPatch := make(map[string]bool, len(patchVariableDefinitions)+len(classVariables))
+	for name := range patchVariableDefinitions {
+		variableDefinitionsForPatch[name] = true
+	}
+	for _, variable := range classVariables {
+		variableDefinitionsForPatch[variable.Name] = true
+	}
+	return variableDefinitionsForPatch
+}
@@ -978,6 +978,54 @@ func setupTestObjects() (*scope.ClusterBlueprint, *scope.ClusterState) {
 	return blueprint, desired
 }
 
+func TestMachineDeploymentDefinitionsForPatch(t *testing.T) {
+	blueprint := &scope.ClusterBlueprint{
+		ClusterClass: builder.ClusterClass(metav1.NamespaceDefault, "class1").
+			WithWorkerMachineDeploymentClasses(
+				*builder.MachineDeploymentClass("kubeadm-workers").
+					WithVariables(clusterv1.ClusterClassVariable{Name: "kubeadmSetting"}).
+					Build(),
+				*builder.MachineDeploymentClass("other-workers").Build()).
+			Build(),
+	}
+	patchVariableDefinitions := map[string]bool{"clusterSetting": true}
+
+	tests := []struct {
+		name           string
+		mdClass        string
+		definitionFrom string
+		want           map[string]bool
+	}{
+		{
+			name:           "Add variables of the MachineDeploymentClass for inline patches",
+			mdClass:        "kubeadm-workers",
+			definitionFrom: clusterv1.VariableDefinitionFromInline,
+			want:           map[string]bool{"clusterSetting": true, "kubeadmSetting": true},
+		},
+		{
+			name:           "Don't add variables for a MachineDeploymentClass without variables",
+			mdClass:        "other-workers",
+			definitionFrom: clusterv1.VariableDefinitionFromInline,
+			want:           map[string]bool{"clusterSetting": true},
+		},
+		{
+			name:           "Don't add variables of the MachineDeploymentClass for external patches",
+			mdClass:        "kubeadm-workers",
+			definitionFrom: "external-patch",
+			want:           map[string]bool{"clusterSetting": true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(machineDeploymentDefinitionsForPatch(blueprint, tt.mdClass, tt.definitionFrom, patchVariableDefinitions)).To(Equal(tt.want))
+			// The variable definitions for the patch must not be modified.
+			g.Expect(patchVariableDefinitions).To(Equal(map[string]bool{"clusterSetting": true}))
+		})
+	}
+}
+
 // setSpecFields sets fields on an unstructured object from a map.
 func setSpecFields(obj *unstructured.Unstructured, fields map[string]interface{}) {
 	for k, v := range fields {
@@ -86,7 +86,7 @@ func (j *jsonPatchGenerator) Generate(_ context.Context, _ client.Object, req *r
 			continue
 		}
 
-		enabled, err := patchIsEnabled(j.patch.EnabledIf, variables)
+		enabled, err := IsEnabled(j.patch.EnabledIf, variables)
 		if err != nil {
 			errs = append(errs, errors.Wrapf(err, "failed to calculate if patch is enabled for %q", objectKind))
 			continue
@@ -99,7 +99,7 @@ func (j *jsonPatchGenerator) Generate(_ context.Context, _ client.Object, req *r
 		// Loop over all PatchDefinitions.
 		for _, patch := range matchingPatches {
 			// Generate JSON patches.
-			jsonPatches, err := generateJSONPatches(patch.JSONPatches, variables)
+			jsonPatches, err := GenerateJSONPatches(patch.JSONPatches, variables)
 			if err != nil {
 				errs = append(errs, errors.Wrapf(err, "failed to generate JSON patches for %q", objectKind))
 				continue
@@ -189,8 +189,9 @@ func matchesSelector(req *runtimehooksv1.GeneratePatchesRequestItem, templateVar
 	return false
 }
 
-func patchIsEnabled(enabledIf *string, variables map[string]apiextensionsv1.JSON) (bool, error) {
-	// If enabledIf is not set, patch is enabled.
+// IsEnabled calculates if a patch or an add-on is enabled by rendering the given enabledIf template with the variables.
+func IsEnabled(enabledIf *string, variables map[string]apiextensionsv1.JSON) (bool, error) {
+	// If enabledIf is not set, it is enabled.
 	if enabledIf == nil {
 		return true, nil
 	}
@@ -201,7 +202,7 @@ func patchIsEnabled(enabledIf *string, variables map[string]apiextensionsv1.JSON
 		return false, errors.Wrapf(err, "failed to calculate value for enabledIf")
 	}
 
-	// Patch is enabled if the rendered template value is `true`.
+	// Enabled if the rendered template value is `true`.
 	return bytes.Equal(value.Raw, []byte(`true`)), nil
 }
 
@@ -212,8 +213,8 @@ type jsonPatchRFC6902 struct {
 	Value *apiextensionsv1.JSON `json:"value,omitempty"`
 }
 
-// generateJSONPatches generates JSON patches based on the given JSONPatches and variables.
-func generateJSONPatches(jsonPatches []clusterv1.JSONPatch, variables map[string]apiextensionsv1.JSON) ([]byte, error) {
+// GenerateJSONPatches generates JSON patches based on the given JSONPatches and variables.
+func GenerateJSONPatches(jsonPatches []clusterv1.JSONPatch, variables map[string]apiextensionsv1.JSON) ([]byte, error) {
 	res := []jsonPatchRFC6902{}
 
 	for _, jsonPatch := range jsonPatches {
@@ -914,7 +914,7 @@ func TestMatchesSelector(t *testing.T) {
 	}
 }
 
-func TestPatchIsEnabled(t *testing.T) {
+func TestIsEnabled(t *testing.T) {
 	tests := []struct {
 		name      string
 		enabledIf *string
@@ -1068,7 +1068,7 @@ true
 		t.Run(tt.name, func(t *testing.T) {
 			g := NewWithT(t)
 
-			got, err := patchIsEnabled(tt.enabledIf, tt.variables)
+			got, err := IsEnabled(tt.enabledIf, tt.variables)
 			if tt.wantErr {
 				g.Expect(err).To(HaveOccurred())
 				return
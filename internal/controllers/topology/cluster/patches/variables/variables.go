@@ -26,6 +26,7 @@ import (
 	"k8s.io/utils/pointer"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1alpha1"
 	runtimehooksv1 "sigs.k8s.io/cluster-api/exp/runtime/hooks/api/v1alpha1"
 	"sigs.k8s.io/cluster-api/internal/contract"
 )
@@ -42,6 +43,7 @@ type Builtins struct {
 	Cluster           *ClusterBuiltins           `json:"cluster,omitempty"`
 	ControlPlane      *ControlPlaneBuiltins      `json:"controlPlane,omitempty"`
 	MachineDeployment *MachineDeploymentBuiltins `json:"machineDeployment,omitempty"`
+	Machine           *MachineBuiltins           `json:"machine,omitempty"`
 }
 
 // ClusterBuiltins represents builtin cluster variables.
@@ -171,6 +173,29 @@ type MachineDeploymentInfrastructureRefBuiltins struct {
 	Name string `json:"name,omitempty"`
 }
 
+// MachineBuiltins represents builtin Machine variables.
+// NOTE: These variables are only available when computing the JSON patches of an IPAddressClaimTemplate,
+// which are applied to the InfrastructureMachine of a single Machine.
+type MachineBuiltins struct {
+	// Name is the name of the Machine.
+	Name string `json:"name,omitempty"`
+
+	// IPAddresses are the IP addresses allocated for the Machine, keyed by the name of the IPAddressClaimTemplate.
+	IPAddresses map[string]MachineIPAddressBuiltins `json:"ipAddresses,omitempty"`
+}
+
+// MachineIPAddressBuiltins represents an IP address allocated for a Machine.
+type MachineIPAddressBuiltins struct {
+	// Address is the IP address.
+	Address string `json:"address,omitempty"`
+
+	// Prefix is the prefix of the address.
+	Prefix int `json:"prefix,omitempty"`
+
+	// Gateway is the network gateway of the network the address is from.
+	Gateway string `json:"gateway,omitempty"`
+}
+
 // Global returns variables that apply to all the templates, including user provided variables
 // and builtin variables for the Cluster object.
 func Global(clusterTopology *clusterv1.Topology, cluster *clusterv1.Cluster, definitionFrom string, patchVariableDefinitions map[string]bool) ([]runtimehooksv1.Variable, error) {
@@ -327,6 +352,33 @@ func MachineDeployment(mdTopology *clusterv1.MachineDeploymentTopology, md *clus
 	return variables, nil
 }
 
+// Machine returns variables that apply to the InfrastructureMachine of a Machine.
+func Machine(machine *clusterv1.Machine, ipAddresses map[string]*ipamv1.IPAddress) ([]runtimehooksv1.Variable, error) {
+	builtin := Builtins{
+		Machine: &MachineBuiltins{
+			Name: machine.Name,
+		},
+	}
+
+	if len(ipAddresses) > 0 {
+		builtin.Machine.IPAddresses = map[string]MachineIPAddressBuiltins{}
+		for name, ipAddress := range ipAddresses {
+			builtin.Machine.IPAddresses[name] = MachineIPAddressBuiltins{
+				Address: ipAddress.Spec.Address,
+				Prefix:  ipAddress.Spec.Prefix,
+				Gateway: ipAddress.Spec.Gateway,
+			}
+		}
+	}
+
+	variable, err := toVariable(BuiltinsName, builtin)
+	if err != nil {
+		return nil, err
+	}
+
+	return []runtimehooksv1.Variable{*variable}, nil
+}
+
 // toVariable converts name and value to a variable.
 func toVariable(name string, value interface{}) (*runtimehooksv1.Variable, error) {
 	marshalledValue, err := json.Marshal(value)
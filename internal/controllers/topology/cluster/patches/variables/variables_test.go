@@ -28,6 +28,7 @@ import (
 	"k8s.io/utils/pointer"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1alpha1"
 	runtimehooksv1 "sigs.k8s.io/cluster-api/exp/runtime/hooks/api/v1alpha1"
 	"sigs.k8s.io/cluster-api/internal/test/builder"
 )
@@ -853,6 +854,77 @@ func TestMachineDeployment(t *testing.T) {
 	}
 }
 
+func TestMachine(t *testing.T) {
+	tests := []struct {
+		name        string
+		machine     *clusterv1.Machine
+		ipAddresses map[string]*ipamv1.IPAddress
+		want        []runtimehooksv1.Variable
+	}{
+		{
+			name:    "Should calculate Machine variables without IP addresses",
+			machine: builder.Machine(metav1.NamespaceDefault, "machine1").Build(),
+			want: []runtimehooksv1.Variable{
+				{
+					Name: BuiltinsName,
+					Value: toJSONCompact(`{
+					"machine":{
+						"name": "machine1"
+					}}`),
+				},
+			},
+		},
+		{
+			name:    "Should calculate Machine variables with IP addresses",
+			machine: builder.Machine(metav1.NamespaceDefault, "machine1").Build(),
+			ipAddresses: map[string]*ipamv1.IPAddress{
+				"nic0": {
+					Spec: ipamv1.IPAddressSpec{
+						Address: "10.0.0.10",
+						Prefix:  24,
+						Gateway: "10.0.0.1",
+					},
+				},
+				"nic1": {
+					Spec: ipamv1.IPAddressSpec{
+						Address: "fd00::10",
+						Prefix:  64,
+					},
+				},
+			},
+			want: []runtimehooksv1.Variable{
+				{
+					Name: BuiltinsName,
+					Value: toJSONCompact(`{
+					"machine":{
+						"name": "machine1",
+						"ipAddresses":{
+							"nic0":{
+								"address": "10.0.0.10",
+								"prefix": 24,
+								"gateway": "10.0.0.1"
+							},
+							"nic1":{
+								"address": "fd00::10",
+								"prefix": 64
+							}
+						}
+					}}`),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := Machine(tt.machine, tt.ipAddresses)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
 func toJSON(value string) apiextensionsv1.JSON {
 	return apiextensionsv1.JSON{Raw: []byte(value)}
 }
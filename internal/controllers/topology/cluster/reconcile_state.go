@@ -19,31 +19,40 @@ package cluster
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"strings"
 	"time"
 
+	jsonpatch "github.com/evanphx/json-patch/v5"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/apimachinery/pkg/util/wait"
-	"k8s.io/apiserver/pkg/storage/names"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/external"
+	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1alpha1"
 	runtimehooksv1 "sigs.k8s.io/cluster-api/exp/runtime/hooks/api/v1alpha1"
 	"sigs.k8s.io/cluster-api/feature"
 	"sigs.k8s.io/cluster-api/internal/contract"
+	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/patches/inline"
+	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/patches/variables"
 	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/scope"
 	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/structuredmerge"
 	"sigs.k8s.io/cluster-api/internal/hooks"
 	tlog "sigs.k8s.io/cluster-api/internal/log"
 	"sigs.k8s.io/cluster-api/internal/topology/check"
+	"sigs.k8s.io/cluster-api/internal/topology/names"
+	"sigs.k8s.io/cluster-api/util/patch"
 )
 
 const (
@@ -89,7 +98,17 @@ func (r *Reconciler) reconcileState(ctx context.Context, s *scope.Scope) error {
 	}
 
 	// Reconcile desired state of the MachineDeployment objects.
-	return r.reconcileMachineDeployments(ctx, s)
+	if err := r.reconcileMachineDeployments(ctx, s); err != nil {
+		return err
+	}
+
+	// Reconcile the IPAddressClaims for the Machines of the MachineDeployment objects.
+	if err := r.reconcileMachineDeploymentIPAddressClaims(ctx, s); err != nil {
+		return err
+	}
+
+	// Reconcile desired state of the ClusterResourceSets for the add-ons of the ClusterClass.
+	return r.reconcileClusterResourceSets(ctx, s)
 }
 
 // Reconcile the Cluster shim, a temporary object used a mean to collect objects/templates
@@ -102,7 +121,8 @@ func (r *Reconciler) reconcileClusterShim(ctx context.Context, s *scope.Scope) e
 	// This will ensure the objects will be garbage collected in case of errors in between
 	// creating InfrastructureCluster/ControlPlane objects and updating the Cluster with the
 	// references to above objects.
-	if s.Current.InfrastructureCluster == nil || s.Current.ControlPlane.Object == nil {
+	infrastructureClusterMissing := s.Blueprint.HasInfrastructureCluster() && s.Current.InfrastructureCluster == nil
+	if infrastructureClusterMissing || s.Current.ControlPlane.Object == nil {
 		// Given that the cluster shim is a temporary object which is only modified
 		// by this controller, it is not necessary to use the SSA patch helper.
 		if err := r.Client.Create(ctx, shim); err != nil {
@@ -118,13 +138,15 @@ func (r *Reconciler) reconcileClusterShim(ctx context.Context, s *scope.Scope) e
 		shim.Kind = "Secret"
 		shim.APIVersion = corev1.SchemeGroupVersion.String()
 
-		// Add the shim as a temporary owner for the InfrastructureCluster.
-		ownerRefs := s.Desired.InfrastructureCluster.GetOwnerReferences()
-		ownerRefs = append(ownerRefs, *ownerReferenceTo(shim))
-		s.Desired.InfrastructureCluster.SetOwnerReferences(ownerRefs)
+		// Add the shim as a temporary owner for the InfrastructureCluster, if any.
+		if s.Desired.InfrastructureCluster != nil {
+			ownerRefs := s.Desired.InfrastructureCluster.GetOwnerReferences()
+			ownerRefs = append(ownerRefs, *ownerReferenceTo(shim))
+			s.Desired.InfrastructureCluster.SetOwnerReferences(ownerRefs)
+		}
 
 		// Add the shim as a temporary owner for the ControlPlane.
-		ownerRefs = s.Desired.ControlPlane.Object.GetOwnerReferences()
+		ownerRefs := s.Desired.ControlPlane.Object.GetOwnerReferences()
 		ownerRefs = append(ownerRefs, *ownerReferenceTo(shim))
 		s.Desired.ControlPlane.Object.SetOwnerReferences(ownerRefs)
 	}
@@ -135,11 +157,13 @@ func (r *Reconciler) reconcileClusterShim(ctx context.Context, s *scope.Scope) e
 	//
 	// When the Cluster and the shim object are both owners,
 	// it's safe for us to remove the shim and garbage collect any potential orphaned resource.
-	if s.Current.InfrastructureCluster != nil && s.Current.ControlPlane.Object != nil {
-		clusterOwnsAll := hasOwnerReferenceFrom(s.Current.InfrastructureCluster, s.Current.Cluster) &&
-			hasOwnerReferenceFrom(s.Current.ControlPlane.Object, s.Current.Cluster)
-		shimOwnsAtLeastOne := hasOwnerReferenceFrom(s.Current.InfrastructureCluster, shim) ||
-			hasOwnerReferenceFrom(s.Current.ControlPlane.Object, shim)
+	if !infrastructureClusterMissing && s.Current.ControlPlane.Object != nil {
+		clusterOwnsAll := hasOwnerReferenceFrom(s.Current.ControlPlane.Object, s.Current.Cluster)
+		shimOwnsAtLeastOne := hasOwnerReferenceFrom(s.Current.ControlPlane.Object, shim)
+		if s.Current.InfrastructureCluster != nil {
+			clusterOwnsAll = clusterOwnsAll && hasOwnerReferenceFrom(s.Current.InfrastructureCluster, s.Current.Cluster)
+			shimOwnsAtLeastOne = shimOwnsAtLeastOne || hasOwnerReferenceFrom(s.Current.InfrastructureCluster, shim)
+		}
 
 		if clusterOwnsAll && shimOwnsAtLeastOne {
 			if err := r.Client.Delete(ctx, shim); err != nil {
@@ -274,6 +298,11 @@ func (r *Reconciler) callAfterClusterUpgrade(ctx context.Context, s *scope.Scope
 
 // reconcileInfrastructureCluster reconciles the desired state of the InfrastructureCluster object.
 func (r *Reconciler) reconcileInfrastructureCluster(ctx context.Context, s *scope.Scope) error {
+	// Nothing to do if the infrastructure of the Cluster is provided by the control plane.
+	if s.Desired.InfrastructureCluster == nil {
+		return nil
+	}
+
 	ctx, _ = tlog.LoggerFrom(ctx).WithObject(s.Desired.InfrastructureCluster).Into(ctx)
 
 	ignorePaths, err := contract.InfrastructureCluster().IgnorePaths(s.Desired.InfrastructureCluster)
@@ -308,6 +337,11 @@ func (r *Reconciler) reconcileControlPlane(ctx context.Context, s *scope.Scope)
 	if s.UpgradeTracker.ControlPlane.IsPendingUpgrade {
 		return nil
 	}
+	// If the control plane rollout is held by the rollout policy of the ClusterClass, only reconcile the replicas
+	// of the control plane; all the other changes are rolled out when the rollout is started.
+	if s.UpgradeTracker.ControlPlane.IsPendingRollout {
+		return r.reconcileControlPlaneReplicas(ctx, s)
+	}
 	// If the clusterClass mandates the controlPlane has infrastructureMachines, reconcile it.
 	if s.Blueprint.HasControlPlaneInfrastructureMachine() {
 		ctx, _ := tlog.LoggerFrom(ctx).WithObject(s.Desired.ControlPlane.InfrastructureMachineTemplate).Into(ctx)
@@ -319,12 +353,12 @@ func (r *Reconciler) reconcileControlPlane(ctx context.Context, s *scope.Scope)
 
 		// Create or update the MachineInfrastructureTemplate of the control plane.
 		if err = r.reconcileReferencedTemplate(ctx, reconcileReferencedTemplateInput{
-			cluster:              s.Current.Cluster,
-			ref:                  cpInfraRef,
-			current:              s.Current.ControlPlane.InfrastructureMachineTemplate,
-			desired:              s.Desired.ControlPlane.InfrastructureMachineTemplate,
-			compatibilityChecker: check.ObjectsAreCompatible,
-			templateNamePrefix:   controlPlaneInfrastructureMachineTemplateNamePrefix(s.Current.Cluster.Name),
+			cluster:               s.Current.Cluster,
+			ref:                   cpInfraRef,
+			current:               s.Current.ControlPlane.InfrastructureMachineTemplate,
+			desired:               s.Desired.ControlPlane.InfrastructureMachineTemplate,
+			compatibilityChecker:  check.ObjectsAreCompatible,
+			templateNameGenerator: names.ControlPlaneInfrastructureMachineTemplateNameGenerator(s.Blueprint.ClusterClass.Spec.ControlPlane.NamingStrategy, s.Current.Cluster.Name),
 		},
 		); err != nil {
 			return err
@@ -362,9 +396,57 @@ func (r *Reconciler) reconcileControlPlane(ctx context.Context, s *scope.Scope)
 		}
 	}
 
+	// If the control plane is starting a rollout to new control plane templates of the ClusterClass,
+	// track the generation of the control plane being rolled out.
+	reconcileControlPlaneRolloutGeneration(s)
 	return nil
 }
 
+// reconcileControlPlaneReplicas reconciles the replicas of the control plane, while preserving all the other fields of the
+// spec of the current control plane, so that scaling the control plane doesn't start a rollout held by the rollout
+// policy of the ClusterClass.
+func (r *Reconciler) reconcileControlPlaneReplicas(ctx context.Context, s *scope.Scope) error {
+	desired := s.Desired.ControlPlane.Object.DeepCopy()
+
+	// NOTE: Only the fields of the spec set in the desired control plane are preserved, so the topology controller
+	// doesn't take ownership of fields it doesn't manage.
+	desiredSpec, _, err := unstructured.NestedMap(desired.Object, "spec")
+	if err != nil {
+		return errors.Wrapf(err, "failed to get spec from %s", tlog.KObj{Obj: desired})
+	}
+	for field := range desiredSpec {
+		value, found, err := unstructured.NestedFieldCopy(s.Current.ControlPlane.Object.Object, "spec", field)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get spec.%s from %s", field, tlog.KObj{Obj: s.Current.ControlPlane.Object})
+		}
+		if !found {
+			unstructured.RemoveNestedField(desired.Object, "spec", field)
+			continue
+		}
+		if err := unstructured.SetNestedField(desired.Object, value, "spec", field); err != nil {
+			return errors.Wrapf(err, "failed to set spec.%s in %s", field, tlog.KObj{Obj: desired})
+		}
+	}
+
+	replicas, err := contract.ControlPlane().Replicas().Get(s.Desired.ControlPlane.Object)
+	if err != nil && !errors.Is(err, contract.ErrFieldNotFound) {
+		return errors.Wrapf(err, "failed to get replicas from %s", tlog.KObj{Obj: s.Desired.ControlPlane.Object})
+	}
+	if replicas != nil {
+		if err := contract.ControlPlane().Replicas().Set(desired, *replicas); err != nil {
+			return errors.Wrapf(err, "failed to set replicas in %s", tlog.KObj{Obj: desired})
+		}
+	}
+
+	ctx, _ = tlog.LoggerFrom(ctx).WithObject(desired).Into(ctx)
+	return r.reconcileReferencedObject(ctx, reconcileReferencedObjectInput{
+		cluster:       s.Current.Cluster,
+		current:       s.Current.ControlPlane.Object,
+		desired:       desired,
+		versionGetter: contract.ControlPlane().Version().Get,
+	})
+}
+
 // reconcileMachineHealthCheck creates, updates, deletes or leaves untouched a MachineHealthCheck depending on the difference between the
 // current state and the desired state.
 func (r *Reconciler) reconcileMachineHealthCheck(ctx context.Context, current, desired *clusterv1.MachineHealthCheck) error {
@@ -629,26 +711,35 @@ func (r *Reconciler) updateMachineDeployment(ctx context.Context, s *scope.Scope
 	}
 
 	cluster := s.Current.Cluster
+
+	// Get the naming strategy for the templates from the MachineDeployment class.
+	var mdNamingStrategy *clusterv1.MachineDeploymentClassNamingStrategy
+	if ok, mdClassName := getMDClassName(cluster, mdTopologyName); ok {
+		if mdBlueprint, ok := s.Blueprint.MachineDeployments[mdClassName]; ok {
+			mdNamingStrategy = mdBlueprint.NamingStrategy
+		}
+	}
+
 	infraCtx, _ := log.WithObject(desiredMD.InfrastructureMachineTemplate).Into(ctx)
 	if err := r.reconcileReferencedTemplate(infraCtx, reconcileReferencedTemplateInput{
-		cluster:              cluster,
-		ref:                  &desiredMD.Object.Spec.Template.Spec.InfrastructureRef,
-		current:              currentMD.InfrastructureMachineTemplate,
-		desired:              desiredMD.InfrastructureMachineTemplate,
-		templateNamePrefix:   infrastructureMachineTemplateNamePrefix(cluster.Name, mdTopologyName),
-		compatibilityChecker: check.ObjectsAreCompatible,
+		cluster:               cluster,
+		ref:                   &desiredMD.Object.Spec.Template.Spec.InfrastructureRef,
+		current:               currentMD.InfrastructureMachineTemplate,
+		desired:               desiredMD.InfrastructureMachineTemplate,
+		templateNameGenerator: names.InfrastructureMachineTemplateNameGenerator(mdNamingStrategy, cluster.Name, mdTopologyName),
+		compatibilityChecker:  check.ObjectsAreCompatible,
 	}); err != nil {
 		return errors.Wrapf(err, "failed to reconcile %s", tlog.KObj{Obj: currentMD.Object})
 	}
 
 	bootstrapCtx, _ := log.WithObject(desiredMD.BootstrapTemplate).Into(ctx)
 	if err := r.reconcileReferencedTemplate(bootstrapCtx, reconcileReferencedTemplateInput{
-		cluster:              cluster,
-		ref:                  desiredMD.Object.Spec.Template.Spec.Bootstrap.ConfigRef,
-		current:              currentMD.BootstrapTemplate,
-		desired:              desiredMD.BootstrapTemplate,
-		templateNamePrefix:   bootstrapTemplateNamePrefix(cluster.Name, mdTopologyName),
-		compatibilityChecker: check.ObjectsAreInTheSameNamespace,
+		cluster:               cluster,
+		ref:                   desiredMD.Object.Spec.Template.Spec.Bootstrap.ConfigRef,
+		current:               currentMD.BootstrapTemplate,
+		desired:               desiredMD.BootstrapTemplate,
+		templateNameGenerator: names.BootstrapTemplateNameGenerator(mdNamingStrategy, cluster.Name, mdTopologyName),
+		compatibilityChecker:  check.ObjectsAreInTheSameNamespace,
 	}); err != nil {
 		return errors.Wrapf(err, "failed to reconcile %s", tlog.KObj{Obj: currentMD.Object})
 	}
@@ -721,6 +812,176 @@ func (r *Reconciler) deleteMachineDeployment(ctx context.Context, cluster *clust
 	return nil
 }
 
+// reconcileMachineDeploymentIPAddressClaims ensures an IPAddressClaim exists for every Machine of a MachineDeployment
+// and every IPAddressClaim template defined in the corresponding MachineDeploymentClass. Once an IP address has been
+// allocated for a claim, the JSON patches of the template are applied to the InfrastructureMachine of the Machine.
+// NOTE: Allocated IP addresses are injected into InfrastructureMachines and not into InfrastructureMachineTemplates
+// because templates are shared across all the Machines of a MachineDeployment.
+func (r *Reconciler) reconcileMachineDeploymentIPAddressClaims(ctx context.Context, s *scope.Scope) error {
+	for mdTopologyName, md := range s.Current.MachineDeployments {
+		ok, mdClassName := getMDClassName(s.Current.Cluster, mdTopologyName)
+		if !ok {
+			continue
+		}
+		mdBlueprint, ok := s.Blueprint.MachineDeployments[mdClassName]
+		if !ok || len(mdBlueprint.IPAddressClaims) == 0 {
+			continue
+		}
+
+		machines := &clusterv1.MachineList{}
+		if err := r.Client.List(ctx, machines,
+			client.InNamespace(md.Object.Namespace),
+			client.MatchingLabels{
+				clusterv1.ClusterNameLabel:           s.Current.Cluster.Name,
+				clusterv1.MachineDeploymentNameLabel: md.Object.Name,
+			},
+		); err != nil {
+			return errors.Wrapf(err, "failed to list Machines for %s", tlog.KObj{Obj: md.Object})
+		}
+
+		for i := range machines.Items {
+			machine := &machines.Items[i]
+			if !machine.DeletionTimestamp.IsZero() {
+				continue
+			}
+			if err := r.reconcileMachineIPAddressClaims(ctx, s.Current.Cluster, machine, mdBlueprint.IPAddressClaims); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// reconcileMachineIPAddressClaims creates the IPAddressClaims for a Machine and injects the allocated
+// IP addresses into its InfrastructureMachine.
+func (r *Reconciler) reconcileMachineIPAddressClaims(ctx context.Context, cluster *clusterv1.Cluster, machine *clusterv1.Machine, claimTemplates []clusterv1.IPAddressClaimTemplate) error {
+	log := tlog.LoggerFrom(ctx).WithObject(machine)
+
+	ipAddresses := map[string]*ipamv1.IPAddress{}
+	for _, claimTemplate := range claimTemplates {
+		claim := &ipamv1.IPAddressClaim{}
+		claimKey := client.ObjectKey{Namespace: machine.Namespace, Name: ipAddressClaimName(machine, claimTemplate)}
+		if err := r.Client.Get(ctx, claimKey, claim); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return errors.Wrapf(err, "failed to get IPAddressClaim %s", claimKey)
+			}
+
+			claim = &ipamv1.IPAddressClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      claimKey.Name,
+					Namespace: claimKey.Namespace,
+					Labels: map[string]string{
+						clusterv1.ClusterNameLabel:          cluster.Name,
+						clusterv1.ClusterTopologyOwnedLabel: "",
+					},
+					// Ensure the IPAddressClaim is garbage collected together with the Machine.
+					OwnerReferences: []metav1.OwnerReference{
+						*metav1.NewControllerRef(machine, clusterv1.GroupVersion.WithKind("Machine")),
+					},
+				},
+				Spec: ipamv1.IPAddressClaimSpec{
+					PoolRef: claimTemplate.PoolRef,
+				},
+			}
+			log.Infof("Creating %s", tlog.KObj{Obj: claim})
+			if err := r.Client.Create(ctx, claim); err != nil {
+				return createErrorWithoutObjectName(ctx, err, claim)
+			}
+			r.recorder.Eventf(cluster, corev1.EventTypeNormal, createEventReason, "Created %q", tlog.KObj{Obj: claim})
+			continue
+		}
+
+		// Wait for the IP address to be allocated.
+		if claim.Status.AddressRef.Name == "" {
+			continue
+		}
+		ipAddress := &ipamv1.IPAddress{}
+		if err := r.Client.Get(ctx, client.ObjectKey{Namespace: claim.Namespace, Name: claim.Status.AddressRef.Name}, ipAddress); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return errors.Wrapf(err, "failed to get IPAddress for %s", tlog.KObj{Obj: claim})
+		}
+		ipAddresses[claimTemplate.Name] = ipAddress
+	}
+
+	// Nothing to inject if no IP address has been allocated yet or if the InfrastructureMachine does not exist yet.
+	if len(ipAddresses) == 0 || machine.Spec.InfrastructureRef.Name == "" {
+		return nil
+	}
+
+	infraMachine, err := external.Get(ctx, r.Client, &machine.Spec.InfrastructureRef, machine.Namespace)
+	if err != nil {
+		if apierrors.IsNotFound(errors.Cause(err)) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get InfrastructureMachine for %s", tlog.KObj{Obj: machine})
+	}
+
+	machineVariables, err := variables.Machine(machine, ipAddresses)
+	if err != nil {
+		return errors.Wrapf(err, "failed to calculate variables for %s", tlog.KObj{Obj: machine})
+	}
+
+	patchedInfraMachine, err := patchInfrastructureMachineIPAddresses(infraMachine, claimTemplates, ipAddresses, variables.ToMap(machineVariables))
+	if err != nil {
+		return errors.Wrapf(err, "failed to inject IP addresses into %s", tlog.KObj{Obj: infraMachine})
+	}
+	if reflect.DeepEqual(infraMachine.Object, patchedInfraMachine.Object) {
+		return nil
+	}
+
+	patchHelper, err := patch.NewHelper(infraMachine, r.Client)
+	if err != nil {
+		return err
+	}
+	log.Infof("Injecting IP addresses into %s", tlog.KObj{Obj: infraMachine})
+	if err := patchHelper.Patch(ctx, patchedInfraMachine); err != nil {
+		return errors.Wrapf(err, "failed to patch %s", tlog.KObj{Obj: infraMachine})
+	}
+	r.recorder.Eventf(cluster, corev1.EventTypeNormal, updateEventReason, "Injected IP addresses into %q", tlog.KObj{Obj: infraMachine})
+	return nil
+}
+
+// patchInfrastructureMachineIPAddresses returns a copy of the InfrastructureMachine with the JSON patches of
+// the IPAddressClaim templates applied, skipping templates for which no IP address has been allocated yet.
+func patchInfrastructureMachineIPAddresses(infraMachine *unstructured.Unstructured, claimTemplates []clusterv1.IPAddressClaimTemplate, ipAddresses map[string]*ipamv1.IPAddress, machineVariables map[string]apiextensionsv1.JSON) (*unstructured.Unstructured, error) {
+	infraMachineJSON, err := infraMachine.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, claimTemplate := range claimTemplates {
+		if _, ok := ipAddresses[claimTemplate.Name]; !ok {
+			continue
+		}
+
+		patchJSON, err := inline.GenerateJSONPatches(claimTemplate.JSONPatches, machineVariables)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to generate JSON patches for IPAddressClaim template %q", claimTemplate.Name)
+		}
+		jsonPatch, err := jsonpatch.DecodePatch(patchJSON)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode JSON patches for IPAddressClaim template %q", claimTemplate.Name)
+		}
+		infraMachineJSON, err = jsonPatch.Apply(infraMachineJSON)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to apply JSON patches for IPAddressClaim template %q", claimTemplate.Name)
+		}
+	}
+
+	patchedInfraMachine := &unstructured.Unstructured{}
+	if err := patchedInfraMachine.UnmarshalJSON(infraMachineJSON); err != nil {
+		return nil, err
+	}
+	return patchedInfraMachine, nil
+}
+
+// ipAddressClaimName returns the name of the IPAddressClaim for a Machine and an IPAddressClaim template.
+func ipAddressClaimName(machine *clusterv1.Machine, claimTemplate clusterv1.IPAddressClaimTemplate) string {
+	return fmt.Sprintf("%s-%s", machine.Name, claimTemplate.Name)
+}
+
 type machineDeploymentDiff struct {
 	toCreate, toUpdate, toDelete []string
 }
@@ -821,12 +1082,12 @@ func logUnstructuredVersionChange(current, desired *unstructured.Unstructured, v
 }
 
 type reconcileReferencedTemplateInput struct {
-	cluster              *clusterv1.Cluster
-	ref                  *corev1.ObjectReference
-	current              *unstructured.Unstructured
-	desired              *unstructured.Unstructured
-	templateNamePrefix   string
-	compatibilityChecker func(current, desired client.Object) field.ErrorList
+	cluster               *clusterv1.Cluster
+	ref                   *corev1.ObjectReference
+	current               *unstructured.Unstructured
+	desired               *unstructured.Unstructured
+	templateNameGenerator names.NameGenerator
+	compatibilityChecker  func(current, desired client.Object) field.ErrorList
 }
 
 // reconcileReferencedTemplate reconciles the desired state of a referenced Template.
@@ -890,7 +1151,10 @@ func (r *Reconciler) reconcileReferencedTemplate(ctx context.Context, in reconci
 
 	// NOTE: it is required to assign a new name, because during compute the desired object name is enforced to be equal to the current one.
 	// TODO: find a way to make side effect more explicit
-	newName := names.SimpleNameGenerator.GenerateName(in.templateNamePrefix)
+	newName, err := in.templateNameGenerator.GenerateName()
+	if err != nil {
+		return errors.Wrapf(err, "failed to generate name for %s", tlog.KObj{Obj: in.desired})
+	}
 	in.desired.SetName(newName)
 
 	log.Infof("Rotating %s, new name %s", tlog.KObj{Obj: in.current}, newName)
@@ -955,3 +1219,76 @@ func createErrorWithoutObjectName(ctx context.Context, err error, obj client.Obj
 	}
 	return errors.New("failed to create object")
 }
+
+// reconcileClusterResourceSets reconciles the desired state of the ClusterResourceSets for the add-ons defined in
+// the ClusterClass. ClusterResourceSets are created for the add-ons enabled for the Cluster, patched when the add-on
+// definition changes and deleted when an add-on is removed from the ClusterClass or it is not enabled anymore.
+func (r *Reconciler) reconcileClusterResourceSets(ctx context.Context, s *scope.Scope) error {
+	log := tlog.LoggerFrom(ctx)
+
+	// Delete the ClusterResourceSets which are not desired anymore.
+	for addonName, current := range s.Current.ClusterResourceSets {
+		if _, ok := s.Desired.ClusterResourceSets[addonName]; ok {
+			continue
+		}
+		if err := r.deleteClusterResourceSet(ctx, s.Current.Cluster, current); err != nil {
+			return err
+		}
+	}
+
+	for addonName, desired := range s.Desired.ClusterResourceSets {
+		current, ok := s.Current.ClusterResourceSets[addonName]
+
+		// The strategy of a ClusterResourceSet is immutable, so the ClusterResourceSet is deleted and re-created
+		// if the strategy of the add-on changed.
+		if ok && current.Spec.Strategy != desired.Spec.Strategy {
+			if err := r.deleteClusterResourceSet(ctx, s.Current.Cluster, current); err != nil {
+				return err
+			}
+			ok = false
+		}
+
+		if !ok {
+			log.Infof("Creating %s", tlog.KObj{Obj: desired})
+			helper, err := r.patchHelperFactory(ctx, nil, desired)
+			if err != nil {
+				return errors.Wrapf(err, "failed to create patch helper for %s", tlog.KObj{Obj: desired})
+			}
+			if err := helper.Patch(ctx); err != nil {
+				return errors.Wrapf(err, "failed to create %s", tlog.KObj{Obj: desired})
+			}
+			r.recorder.Eventf(s.Current.Cluster, corev1.EventTypeNormal, createEventReason, "Created %q", tlog.KObj{Obj: desired})
+			continue
+		}
+
+		// Check differences between current and desired ClusterResourceSets, and patch if required.
+		// NOTE: we want to be authoritative on the entire spec because the users are
+		// expected to change add-on fields from the ClusterClass only.
+		patchHelper, err := r.patchHelperFactory(ctx, current, desired)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create patch helper for %s", tlog.KObj{Obj: current})
+		}
+		if !patchHelper.HasChanges() {
+			log.V(3).Infof("No changes for %s", tlog.KObj{Obj: current})
+			continue
+		}
+
+		log.Infof("Patching %s", tlog.KObj{Obj: current})
+		if err := patchHelper.Patch(ctx); err != nil {
+			return errors.Wrapf(err, "failed to patch %s", tlog.KObj{Obj: current})
+		}
+		r.recorder.Eventf(s.Current.Cluster, corev1.EventTypeNormal, updateEventReason, "Updated %q", tlog.KObj{Obj: current})
+	}
+	return nil
+}
+
+func (r *Reconciler) deleteClusterResourceSet(ctx context.Context, cluster *clusterv1.Cluster, crs *addonsv1.ClusterResourceSet) error {
+	log := tlog.LoggerFrom(ctx)
+
+	log.Infof("Deleting %s", tlog.KObj{Obj: crs})
+	if err := r.Client.Delete(ctx, crs); err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete %s", tlog.KObj{Obj: crs})
+	}
+	r.recorder.Eventf(cluster, corev1.EventTypeNormal, deleteEventReason, "Deleted %q", tlog.KObj{Obj: crs})
+	return nil
+}
@@ -32,21 +32,24 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	. "sigs.k8s.io/controller-runtime/pkg/envtest/komega"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1alpha1"
 	runtimev1 "sigs.k8s.io/cluster-api/exp/runtime/api/v1alpha1"
 	runtimecatalog "sigs.k8s.io/cluster-api/exp/runtime/catalog"
 	runtimehooksv1 "sigs.k8s.io/cluster-api/exp/runtime/hooks/api/v1alpha1"
 	"sigs.k8s.io/cluster-api/internal/contract"
 	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/scope"
-	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/structuredmerge"
 	"sigs.k8s.io/cluster-api/internal/hooks"
 	fakeruntimeclient "sigs.k8s.io/cluster-api/internal/runtime/client/fake"
 	"sigs.k8s.io/cluster-api/internal/test/builder"
+	"sigs.k8s.io/cluster-api/internal/topology/fieldowner"
 	"sigs.k8s.io/cluster-api/internal/util/ssa"
 )
 
@@ -1104,7 +1107,7 @@ func TestReconcileInfrastructureCluster(t *testing.T) {
 
 			if tt.original != nil {
 				// NOTE: it is required to use server side apply to creat the object in order to ensure consistency with the topology controller behaviour.
-				g.Expect(env.PatchAndWait(ctx, tt.original.DeepCopy(), client.ForceOwnership, client.FieldOwner(structuredmerge.TopologyManagerName))).To(Succeed())
+				g.Expect(env.PatchAndWait(ctx, tt.original.DeepCopy(), client.ForceOwnership, client.FieldOwner(fieldowner.TopologyManagerName))).To(Succeed())
 				// NOTE: it is required to apply instance specific changes with a "plain" Patch operation to simulate a different manger.
 				if tt.externalChanges != "" {
 					g.Expect(env.Patch(ctx, tt.original.DeepCopy(), client.RawPatch(types.MergePatchType, []byte(tt.externalChanges)))).To(Succeed())
@@ -1365,7 +1368,7 @@ func TestReconcileControlPlane(t *testing.T) {
 			if tt.original != nil {
 				if tt.original.InfrastructureMachineTemplate != nil {
 					// NOTE: it is required to use server side apply to creat the object in order to ensure consistency with the topology controller behaviour.
-					g.Expect(env.PatchAndWait(ctx, tt.original.InfrastructureMachineTemplate.DeepCopy(), client.FieldOwner(structuredmerge.TopologyManagerName))).To(Succeed())
+					g.Expect(env.PatchAndWait(ctx, tt.original.InfrastructureMachineTemplate.DeepCopy(), client.FieldOwner(fieldowner.TopologyManagerName))).To(Succeed())
 					// NOTE: it is required to apply instance specific changes with a "plain" Patch operation to simulate a different manger.
 					if tt.machineInfrastructureExternalChanges != "" {
 						g.Expect(env.Patch(ctx, tt.original.InfrastructureMachineTemplate.DeepCopy(), client.RawPatch(types.MergePatchType, []byte(tt.machineInfrastructureExternalChanges)))).To(Succeed())
@@ -1377,7 +1380,7 @@ func TestReconcileControlPlane(t *testing.T) {
 				}
 				if tt.original.Object != nil {
 					// NOTE: it is required to use server side apply to creat the object in order to ensure consistency with the topology controller behaviour.
-					g.Expect(env.PatchAndWait(ctx, tt.original.Object.DeepCopy(), client.ForceOwnership, client.FieldOwner(structuredmerge.TopologyManagerName))).To(Succeed())
+					g.Expect(env.PatchAndWait(ctx, tt.original.Object.DeepCopy(), client.ForceOwnership, client.FieldOwner(fieldowner.TopologyManagerName))).To(Succeed())
 					// NOTE: it is required to apply instance specific changes with a "plain" Patch operation to simulate a different manger.
 					if tt.controlPlaneExternalChanges != "" {
 						g.Expect(env.Patch(ctx, tt.original.Object.DeepCopy(), client.RawPatch(types.MergePatchType, []byte(tt.controlPlaneExternalChanges)))).To(Succeed())
@@ -1454,7 +1457,7 @@ func TestReconcileControlPlane(t *testing.T) {
 				// This check is just for the naming format uses by generated templates - here it's templateName-*
 				// This check is only performed when we had an initial template that has been changed
 				if gotRotation {
-					pattern := fmt.Sprintf("%s.*", controlPlaneInfrastructureMachineTemplateNamePrefix(s.Current.Cluster.Name))
+					pattern := fmt.Sprintf("%s-control-plane-.*", s.Current.Cluster.Name)
 					ok, err := regexp.Match(pattern, []byte(gotInfrastructureMachineRef.Name))
 					g.Expect(err).ToNot(HaveOccurred())
 					g.Expect(ok).To(BeTrue())
@@ -1634,13 +1637,13 @@ func TestReconcileControlPlaneMachineHealthCheck(t *testing.T) {
 			if tt.current != nil {
 				s.Current.ControlPlane = tt.current
 				if tt.current.Object != nil {
-					g.Expect(env.PatchAndWait(ctx, tt.current.Object, client.ForceOwnership, client.FieldOwner(structuredmerge.TopologyManagerName))).To(Succeed())
+					g.Expect(env.PatchAndWait(ctx, tt.current.Object, client.ForceOwnership, client.FieldOwner(fieldowner.TopologyManagerName))).To(Succeed())
 				}
 				if tt.current.InfrastructureMachineTemplate != nil {
-					g.Expect(env.PatchAndWait(ctx, tt.current.InfrastructureMachineTemplate, client.ForceOwnership, client.FieldOwner(structuredmerge.TopologyManagerName))).To(Succeed())
+					g.Expect(env.PatchAndWait(ctx, tt.current.InfrastructureMachineTemplate, client.ForceOwnership, client.FieldOwner(fieldowner.TopologyManagerName))).To(Succeed())
 				}
 				if tt.current.MachineHealthCheck != nil {
-					g.Expect(env.PatchAndWait(ctx, tt.current.MachineHealthCheck, client.ForceOwnership, client.FieldOwner(structuredmerge.TopologyManagerName))).To(Succeed())
+					g.Expect(env.PatchAndWait(ctx, tt.current.MachineHealthCheck, client.ForceOwnership, client.FieldOwner(fieldowner.TopologyManagerName))).To(Succeed())
 				}
 			}
 
@@ -1919,9 +1922,9 @@ func TestReconcileMachineDeployments(t *testing.T) {
 			}
 
 			for _, s := range tt.current {
-				g.Expect(env.PatchAndWait(ctx, s.InfrastructureMachineTemplate, client.ForceOwnership, client.FieldOwner(structuredmerge.TopologyManagerName))).To(Succeed())
-				g.Expect(env.PatchAndWait(ctx, s.BootstrapTemplate, client.ForceOwnership, client.FieldOwner(structuredmerge.TopologyManagerName))).To(Succeed())
-				g.Expect(env.PatchAndWait(ctx, s.Object, client.ForceOwnership, client.FieldOwner(structuredmerge.TopologyManagerName))).To(Succeed())
+				g.Expect(env.PatchAndWait(ctx, s.InfrastructureMachineTemplate, client.ForceOwnership, client.FieldOwner(fieldowner.TopologyManagerName))).To(Succeed())
+				g.Expect(env.PatchAndWait(ctx, s.BootstrapTemplate, client.ForceOwnership, client.FieldOwner(fieldowner.TopologyManagerName))).To(Succeed())
+				g.Expect(env.PatchAndWait(ctx, s.Object, client.ForceOwnership, client.FieldOwner(fieldowner.TopologyManagerName))).To(Succeed())
 			}
 
 			currentMachineDeploymentStates := toMachineDeploymentTopologyStateMap(tt.current)
@@ -1933,9 +1936,9 @@ func TestReconcileMachineDeployments(t *testing.T) {
 			for _, s := range tt.currentOnlyAPIServer {
 				mdState := prepareMachineDeploymentState(s, namespace.GetName())
 
-				g.Expect(env.PatchAndWait(ctx, mdState.InfrastructureMachineTemplate, client.ForceOwnership, client.FieldOwner(structuredmerge.TopologyManagerName))).To(Succeed())
-				g.Expect(env.PatchAndWait(ctx, mdState.BootstrapTemplate, client.ForceOwnership, client.FieldOwner(structuredmerge.TopologyManagerName))).To(Succeed())
-				g.Expect(env.PatchAndWait(ctx, mdState.Object, client.ForceOwnership, client.FieldOwner(structuredmerge.TopologyManagerName))).To(Succeed())
+				g.Expect(env.PatchAndWait(ctx, mdState.InfrastructureMachineTemplate, client.ForceOwnership, client.FieldOwner(fieldowner.TopologyManagerName))).To(Succeed())
+				g.Expect(env.PatchAndWait(ctx, mdState.BootstrapTemplate, client.ForceOwnership, client.FieldOwner(fieldowner.TopologyManagerName))).To(Succeed())
+				g.Expect(env.PatchAndWait(ctx, mdState.Object, client.ForceOwnership, client.FieldOwner(fieldowner.TopologyManagerName))).To(Succeed())
 			}
 
 			s.Desired = &scope.ClusterState{MachineDeployments: toMachineDeploymentTopologyStateMap(tt.desired)}
@@ -2662,9 +2665,9 @@ func TestReconcileMachineDeploymentMachineHealthCheck(t *testing.T) {
 			uidsByName := map[string]types.UID{}
 
 			for _, mdts := range tt.current {
-				g.Expect(env.PatchAndWait(ctx, mdts.Object, client.ForceOwnership, client.FieldOwner(structuredmerge.TopologyManagerName))).To(Succeed())
-				g.Expect(env.PatchAndWait(ctx, mdts.InfrastructureMachineTemplate, client.ForceOwnership, client.FieldOwner(structuredmerge.TopologyManagerName))).To(Succeed())
-				g.Expect(env.PatchAndWait(ctx, mdts.BootstrapTemplate, client.ForceOwnership, client.FieldOwner(structuredmerge.TopologyManagerName))).To(Succeed())
+				g.Expect(env.PatchAndWait(ctx, mdts.Object, client.ForceOwnership, client.FieldOwner(fieldowner.TopologyManagerName))).To(Succeed())
+				g.Expect(env.PatchAndWait(ctx, mdts.InfrastructureMachineTemplate, client.ForceOwnership, client.FieldOwner(fieldowner.TopologyManagerName))).To(Succeed())
+				g.Expect(env.PatchAndWait(ctx, mdts.BootstrapTemplate, client.ForceOwnership, client.FieldOwner(fieldowner.TopologyManagerName))).To(Succeed())
 
 				uidsByName[mdts.Object.Name] = mdts.Object.GetUID()
 
@@ -2673,7 +2676,7 @@ func TestReconcileMachineDeploymentMachineHealthCheck(t *testing.T) {
 						ref.UID = mdts.Object.GetUID()
 						mdts.MachineHealthCheck.OwnerReferences[i] = ref
 					}
-					g.Expect(env.PatchAndWait(ctx, mdts.MachineHealthCheck, client.ForceOwnership, client.FieldOwner(structuredmerge.TopologyManagerName))).To(Succeed())
+					g.Expect(env.PatchAndWait(ctx, mdts.MachineHealthCheck, client.ForceOwnership, client.FieldOwner(fieldowner.TopologyManagerName))).To(Succeed())
 				}
 			}
 
@@ -3133,3 +3136,211 @@ func Test_createErrorWithoutObjectName(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcileMachineIPAddressClaims(t *testing.T) {
+	cluster := builder.Cluster(metav1.NamespaceDefault, "cluster1").Build()
+
+	infraMachine := &unstructured.Unstructured{}
+	infraMachine.SetAPIVersion(builder.InfrastructureGroupVersion.String())
+	infraMachine.SetKind(builder.GenericInfrastructureMachineKind)
+	infraMachine.SetNamespace(metav1.NamespaceDefault)
+	infraMachine.SetName("infra-machine1")
+	infraMachine.Object["spec"] = map[string]interface{}{}
+
+	machine := builder.Machine(metav1.NamespaceDefault, "machine1").
+		WithClusterName(cluster.Name).
+		Build()
+	machine.UID = "machine1-uid"
+	machine.Spec.InfrastructureRef = corev1.ObjectReference{
+		APIVersion: infraMachine.GetAPIVersion(),
+		Kind:       infraMachine.GetKind(),
+		Name:       infraMachine.GetName(),
+	}
+
+	claimTemplate := clusterv1.IPAddressClaimTemplate{
+		Name: "nic0",
+		PoolRef: corev1.TypedLocalObjectReference{
+			APIGroup: pointer.String("ipam.cluster.x-k8s.io"),
+			Kind:     "InClusterIPPool",
+			Name:     "pool1",
+		},
+		JSONPatches: []clusterv1.JSONPatch{
+			{
+				Op:        "add",
+				Path:      "/spec/ipAddress",
+				ValueFrom: &clusterv1.JSONPatchValue{Variable: pointer.String("builtin.machine.ipAddresses.nic0.address")},
+			},
+		},
+	}
+
+	boundClaim := &ipamv1.IPAddressClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceDefault,
+			Name:      "machine1-nic0",
+		},
+		Spec: ipamv1.IPAddressClaimSpec{
+			PoolRef: claimTemplate.PoolRef,
+		},
+		Status: ipamv1.IPAddressClaimStatus{
+			AddressRef: corev1.LocalObjectReference{Name: "machine1-nic0"},
+		},
+	}
+	unboundClaim := boundClaim.DeepCopy()
+	unboundClaim.Status.AddressRef.Name = ""
+	ipAddress := &ipamv1.IPAddress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceDefault,
+			Name:      "machine1-nic0",
+		},
+		Spec: ipamv1.IPAddressSpec{
+			ClaimRef: corev1.LocalObjectReference{Name: boundClaim.Name},
+			PoolRef:  claimTemplate.PoolRef,
+			Address:  "10.0.0.10",
+			Prefix:   24,
+		},
+	}
+
+	tests := []struct {
+		name              string
+		objs              []client.Object
+		wantIPAddress     string
+		wantIPAddressSet  bool
+		wantClaimOwnerRef bool
+	}{
+		{
+			name:              "Should create the IPAddressClaim",
+			objs:              []client.Object{cluster, machine, infraMachine},
+			wantIPAddressSet:  false,
+			wantClaimOwnerRef: true,
+		},
+		{
+			name:             "Should not patch the InfrastructureMachine if the IPAddressClaim is not bound",
+			objs:             []client.Object{cluster, machine, infraMachine, unboundClaim},
+			wantIPAddressSet: false,
+		},
+		{
+			name:             "Should inject the allocated IP address into the InfrastructureMachine",
+			objs:             []client.Object{cluster, machine, infraMachine, boundClaim, ipAddress},
+			wantIPAddress:    "10.0.0.10",
+			wantIPAddressSet: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			objs := make([]client.Object, 0, len(tt.objs))
+			for _, obj := range tt.objs {
+				objs = append(objs, obj.DeepCopyObject().(client.Object))
+			}
+			fakeClient := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(objs...).Build()
+
+			r := Reconciler{
+				Client:   fakeClient,
+				recorder: record.NewFakeRecorder(32),
+			}
+			g.Expect(r.reconcileMachineIPAddressClaims(ctx, cluster, machine, []clusterv1.IPAddressClaimTemplate{claimTemplate})).To(Succeed())
+
+			claim := &ipamv1.IPAddressClaim{}
+			g.Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "machine1-nic0"}, claim)).To(Succeed())
+			g.Expect(claim.Spec.PoolRef).To(Equal(claimTemplate.PoolRef))
+			if tt.wantClaimOwnerRef {
+				g.Expect(claim.OwnerReferences).To(HaveLen(1))
+				g.Expect(claim.OwnerReferences[0].Name).To(Equal(machine.Name))
+				g.Expect(claim.Labels).To(HaveKeyWithValue(clusterv1.ClusterNameLabel, cluster.Name))
+			}
+
+			gotInfraMachine := &unstructured.Unstructured{}
+			gotInfraMachine.SetGroupVersionKind(infraMachine.GroupVersionKind())
+			g.Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(infraMachine), gotInfraMachine)).To(Succeed())
+			gotIPAddress, ok, err := unstructured.NestedString(gotInfraMachine.Object, "spec", "ipAddress")
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(ok).To(Equal(tt.wantIPAddressSet))
+			g.Expect(gotIPAddress).To(Equal(tt.wantIPAddress))
+		})
+	}
+}
+
+func TestReconcileClusterResourceSets(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := builder.Cluster(metav1.NamespaceDefault, "cluster1").Build()
+
+	newCRS := func(addonName, strategy string, resourceNames ...string) *addonsv1.ClusterResourceSet {
+		crs := &addonsv1.ClusterResourceSet{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "ClusterResourceSet",
+				APIVersion: addonsv1.GroupVersion.String(),
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("%s-%s", cluster.Name, addonName),
+				Namespace: cluster.Namespace,
+				Labels: map[string]string{
+					clusterv1.ClusterNameLabel:              cluster.Name,
+					clusterv1.ClusterTopologyOwnedLabel:     "",
+					clusterv1.ClusterTopologyAddonNameLabel: addonName,
+				},
+			},
+			Spec: addonsv1.ClusterResourceSetSpec{
+				ClusterSelector: metav1.LabelSelector{
+					MatchLabels: map[string]string{clusterv1.ClusterNameLabel: cluster.Name},
+				},
+				Strategy: strategy,
+			},
+		}
+		for _, name := range resourceNames {
+			crs.Spec.Resources = append(crs.Spec.Resources, addonsv1.ResourceRef{Name: name, Kind: "ConfigMap"})
+		}
+		return crs
+	}
+
+	current := map[string]*addonsv1.ClusterResourceSet{
+		// Updated, the resources changed.
+		"cni": newCRS("cni", "ApplyOnce", "calico-v1"),
+		// Deleted, the add-on is not desired anymore.
+		"csi": newCRS("csi", "ApplyOnce", "csi-driver"),
+		// Re-created, the strategy changed.
+		"dns": newCRS("dns", "ApplyOnce", "coredns"),
+	}
+	desired := map[string]*addonsv1.ClusterResourceSet{
+		"cni": newCRS("cni", "ApplyOnce", "calico-v2"),
+		"dns": newCRS("dns", "Reconcile", "coredns"),
+		// Created, the add-on is new.
+		"ingress": newCRS("ingress", "ApplyOnce", "nginx"),
+	}
+
+	objs := []client.Object{}
+	for _, crs := range current {
+		objs = append(objs, crs.DeepCopy())
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(objs...).Build()
+
+	// Read the current objects back from the client, so they have a resourceVersion like in getCurrentState.
+	for name, crs := range current {
+		got := &addonsv1.ClusterResourceSet{}
+		g.Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(crs), got)).To(Succeed())
+		current[name] = got
+	}
+
+	s := scope.New(cluster)
+	s.Current.ClusterResourceSets = current
+	s.Desired = &scope.ClusterState{ClusterResourceSets: desired}
+
+	r := Reconciler{
+		Client:             fakeClient,
+		patchHelperFactory: dryRunPatchHelperFactory(fakeClient),
+		recorder:           record.NewFakeRecorder(32),
+	}
+	g.Expect(r.reconcileClusterResourceSets(ctx, s)).To(Succeed())
+
+	crsList := &addonsv1.ClusterResourceSetList{}
+	g.Expect(fakeClient.List(ctx, crsList, client.InNamespace(cluster.Namespace))).To(Succeed())
+	got := map[string]addonsv1.ClusterResourceSetSpec{}
+	for _, crs := range crsList.Items {
+		got[crs.Labels[clusterv1.ClusterTopologyAddonNameLabel]] = crs.Spec
+	}
+	g.Expect(got).To(HaveLen(3))
+	for name, crs := range desired {
+		g.Expect(got).To(HaveKeyWithValue(name, crs.Spec))
+	}
+}
@@ -26,8 +26,12 @@ import (
 // including the ClusterClass and all the referenced templates.
 type ClusterBlueprint struct {
 	// Topology holds the topology info from Cluster.Spec.
+	// NOTE: If variables are set from ConfigMaps or Secrets, this is a copy of the topology with their values resolved.
 	Topology *clusterv1.Topology
 
+	// VariableValueSourcesHash holds the hash of the variable values read from ConfigMaps or Secrets, if any.
+	VariableValueSourcesHash string
+
 	// ClusterClass holds the ClusterClass object referenced from Cluster.Spec.Topology.
 	ClusterClass *clusterv1.ClusterClass
 
@@ -71,6 +75,20 @@ type MachineDeploymentBlueprint struct {
 	// MachineHealthCheck holds the MachineHealthCheckClass for this MachineDeployment.
 	// +optional
 	MachineHealthCheck *clusterv1.MachineHealthCheckClass
+
+	// IPAddressClaims holds the IPAddressClaim templates for the Machines of this MachineDeployment.
+	// +optional
+	IPAddressClaims []clusterv1.IPAddressClaimTemplate
+
+	// NamingStrategy holds the naming strategy for the MachineDeployment and its templates.
+	// +optional
+	NamingStrategy *clusterv1.MachineDeploymentClassNamingStrategy
+}
+
+// HasInfrastructureCluster checks whether the clusterClass defines an InfrastructureClusterTemplate; if not, the
+// infrastructure of the Cluster is provided by the control plane.
+func (b *ClusterBlueprint) HasInfrastructureCluster() bool {
+	return b.ClusterClass.Spec.Infrastructure.Ref != nil
 }
 
 // HasControlPlaneInfrastructureMachine checks whether the clusterClass mandates the controlPlane has infrastructureMachines.
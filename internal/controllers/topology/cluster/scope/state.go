@@ -26,6 +26,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
 )
 
 // ClusterState holds all the objects representing the state of a managed Cluster topology.
@@ -43,6 +44,10 @@ type ClusterState struct {
 
 	// MachineDeployments holds the machine deployments in the Cluster.
 	MachineDeployments MachineDeploymentsStateMap
+
+	// ClusterResourceSets holds the ClusterResourceSets for the add-ons defined in the ClusterClass,
+	// indexed by add-on name.
+	ClusterResourceSets map[string]*addonsv1.ClusterResourceSet
 }
 
 // ControlPlaneState holds all the objects representing the state of a managed control plane.
@@ -16,7 +16,11 @@ limitations under the License.
 
 package scope
 
-import "k8s.io/apimachinery/pkg/util/sets"
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
 
 // UpgradeTracker is a helper to capture the upgrade status and make upgrade decisions.
 type UpgradeTracker struct {
@@ -35,6 +39,23 @@ type ControlPlaneUpgradeTracker struct {
 	// - Upgrade is blocked because any of the current MachineDeployments are upgrading.
 	IsPendingUpgrade bool
 
+	// IsPendingRollout is true if the Control Plane has to be rolled out to new control plane templates of the
+	// ClusterClass or to a new version, but the rollout is held by the rollout policy of the ClusterClass, e.g. because
+	// the control planes of other Clusters using the ClusterClass are rolling out. False otherwise.
+	// If IsPendingRollout is true only the replicas of the Control Plane are updated in the current reconcile loop.
+	IsPendingRollout bool
+
+	// PendingRolloutMessage describes why the rollout of the Control Plane is held, if IsPendingRollout is true.
+	PendingRolloutMessage string
+
+	// PendingRolloutRetryAfter is the duration after which the rollout of the Control Plane should be checked again,
+	// if IsPendingRollout is true.
+	PendingRolloutRetryAfter time.Duration
+
+	// IsStartingRollout is true if the Control Plane is starting a rollout to new control plane templates of the
+	// ClusterClass or to a new version in the current reconcile loop.
+	IsStartingRollout bool
+
 	// IsProvisioning is true if the current Control Plane is being provisioned for the first time. False otherwise.
 	IsProvisioning bool
 
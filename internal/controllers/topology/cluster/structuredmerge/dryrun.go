@@ -28,6 +28,7 @@ import (
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/internal/contract"
+	"sigs.k8s.io/cluster-api/internal/topology/fieldowner"
 	"sigs.k8s.io/cluster-api/internal/util/ssa"
 	"sigs.k8s.io/cluster-api/util/conversion"
 )
@@ -81,7 +82,7 @@ func dryRunSSAPatch(ctx context.Context, dryRunCtx *dryRunSSAPatchInput) (bool,
 	}
 
 	// Do a server-side apply dry-run with modifiedUnstructured to get the updated object.
-	err = dryRunCtx.client.Patch(ctx, dryRunCtx.modifiedUnstructured, client.Apply, client.DryRunAll, client.FieldOwner(TopologyManagerName), client.ForceOwnership)
+	err = dryRunCtx.client.Patch(ctx, dryRunCtx.modifiedUnstructured, client.Apply, client.DryRunAll, client.FieldOwner(fieldowner.TopologyManagerName), client.ForceOwnership)
 	if err != nil {
 		// This catches errors like metadata.uid changes.
 		return false, false, errors.Wrap(err, "server side apply dry-run failed for modified object")
@@ -107,7 +108,7 @@ func dryRunSSAPatch(ctx context.Context, dryRunCtx *dryRunSSAPatchInput) (bool,
 	// Note: Otherwise we would get the following error:
 	// "failed to request dry-run server side apply: metadata.managedFields must be nil"
 	dryRunCtx.originalUnstructured.SetManagedFields(nil)
-	err = dryRunCtx.client.Patch(ctx, dryRunCtx.originalUnstructured, client.Apply, client.DryRunAll, client.FieldOwner(TopologyManagerName), client.ForceOwnership)
+	err = dryRunCtx.client.Patch(ctx, dryRunCtx.originalUnstructured, client.Apply, client.DryRunAll, client.FieldOwner(fieldowner.TopologyManagerName), client.ForceOwnership)
 	if err != nil {
 		return false, false, errors.Wrap(err, "server side apply dry-run failed for original object")
 	}
@@ -192,12 +193,12 @@ func cleanupManagedFieldsAndAnnotation(obj *unstructured.Unstructured) error {
 		}),
 	})
 
-	// Adjust the managed field for Manager=TopologyManagerName, Subresource="", Operation="Apply" and
+	// Adjust the managed field for Manager=fieldowner.TopologyManagerName, Subresource="", Operation="Apply" and
 	// drop managed fields of other controllers.
 	oldManagedFields := obj.GetManagedFields()
 	newManagedFields := []metav1.ManagedFieldsEntry{}
 	for _, managedField := range oldManagedFields {
-		if managedField.Manager != TopologyManagerName {
+		if managedField.Manager != fieldowner.TopologyManagerName {
 			continue
 		}
 		if managedField.Subresource != "" {
@@ -57,6 +57,11 @@ var (
 		// and ClusterTopologyOwnedLabel as well as infrastructureRef and controlPlaneRef in spec.
 		{"metadata", "labels", clusterv1.ClusterNameLabel},
 		{"metadata", "labels", clusterv1.ClusterTopologyOwnedLabel},
+		// the topology controller tracks the rollouts of the control plane with the following annotations.
+		{"metadata", "annotations", clusterv1.ClusterTopologyControlPlaneRevisionAnnotation},
+		{"metadata", "annotations", clusterv1.ClusterTopologyControlPlaneRolloutInProgressAnnotation},
+		{"metadata", "annotations", clusterv1.ClusterTopologyControlPlaneRolloutCompletedAnnotation},
+		{"metadata", "annotations", clusterv1.ClusterTopologyControlPlaneRolloutStartedAnnotation},
 		{"spec", "infrastructureRef"},
 		{"spec", "controlPlaneRef"},
 	}
@@ -24,13 +24,11 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"sigs.k8s.io/cluster-api/internal/topology/fieldowner"
 	"sigs.k8s.io/cluster-api/internal/util/ssa"
 	"sigs.k8s.io/cluster-api/util"
 )
 
-// TopologyManagerName is the manager name in managed fields for the topology controller.
-const TopologyManagerName = "capi-topology"
-
 type serverSidePatchHelper struct {
 	client         client.Client
 	modified       *unstructured.Unstructured
@@ -137,7 +135,7 @@ func (h *serverSidePatchHelper) Patch(ctx context.Context) error {
 	log.V(5).Info("Patching object", "Intent", h.modified)
 
 	options := []client.PatchOption{
-		client.FieldOwner(TopologyManagerName),
+		client.FieldOwner(fieldowner.TopologyManagerName),
 		// NOTE: we are using force ownership so in case of conflicts the topology controller
 		// overwrite values and become sole manager.
 		client.ForceOwnership,
@@ -42,6 +42,7 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
 	"sigs.k8s.io/cluster-api/internal/test/builder"
+	"sigs.k8s.io/cluster-api/internal/topology/fieldowner"
 	"sigs.k8s.io/cluster-api/internal/util/ssa"
 	"sigs.k8s.io/cluster-api/util/patch"
 )
@@ -454,7 +455,7 @@ func TestServerSideApply(t *testing.T) {
 		modified := obj2.DeepCopy()
 
 		// Create the object using server side apply
-		g.Expect(env.PatchAndWait(ctx, original, client.FieldOwner(TopologyManagerName))).To(Succeed())
+		g.Expect(env.PatchAndWait(ctx, original, client.FieldOwner(fieldowner.TopologyManagerName))).To(Succeed())
 		// Get created object to have managed fields
 		g.Expect(env.GetAPIReader().Get(ctx, client.ObjectKeyFromObject(original), original)).To(Succeed())
 
@@ -510,7 +511,7 @@ func getTopologyManagedFields(original client.Object) map[string]interface{} {
 
 	for _, m := range original.GetManagedFields() {
 		if m.Operation == metav1.ManagedFieldsOperationApply &&
-			m.Manager == TopologyManagerName &&
+			m.Manager == fieldowner.TopologyManagerName &&
 			m.APIVersion == original.GetObjectKind().GroupVersionKind().GroupVersion().String() {
 			// NOTE: API server ensures this is a valid json.
 			err := json.Unmarshal(m.FieldsV1.Raw, &r)
@@ -0,0 +1,295 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/scope"
+	"sigs.k8s.io/cluster-api/internal/util/hash"
+	"sigs.k8s.io/cluster-api/internal/webhooks"
+	"sigs.k8s.io/cluster-api/util"
+)
+
+// resolveVariableValueSources resolves the values of the Cluster variables and of the MachineDeployment variable
+// overrides set from ConfigMaps or Secrets. The resolved values are set in a copy of the topology stored in the
+// blueprint, so they are used to compute the desired state without being persisted in the Cluster.
+// NOTE: The values are decoded according to the type of the schema of the variable, so e.g. "1.28" is a string
+// for variables of type string.
+func (r *Reconciler) resolveVariableValueSources(ctx context.Context, s *scope.Scope) error {
+	if !hasVariableValueSources(s.Current.Cluster.Spec.Topology) {
+		return nil
+	}
+
+	cluster := s.Current.Cluster.DeepCopy()
+	topology := cluster.Spec.Topology
+
+	// Keep track of the resolved values, so it is possible to compute their hash.
+	resolvedValues := []clusterv1.ClusterVariable{}
+
+	var err error
+	topology.Variables, err = r.resolveVariables(ctx, cluster.Namespace, topology.Variables, s.Blueprint.ClusterClass.Status.Variables, &resolvedValues)
+	if err != nil {
+		return err
+	}
+	if topology.Workers != nil {
+		for i := range topology.Workers.MachineDeployments {
+			md := &topology.Workers.MachineDeployments[i]
+			if md.Variables == nil {
+				continue
+			}
+			definitions := append(machineDeploymentClassVariableDefinitions(s.Blueprint.ClusterClass, md.Class), s.Blueprint.ClusterClass.Status.Variables...)
+			md.Variables.Overrides, err = r.resolveVariables(ctx, cluster.Namespace, md.Variables.Overrides, definitions, &resolvedValues)
+			if err != nil {
+				return errors.Wrapf(err, "failed to resolve variable overrides for MachineDeployment topology %q", md.Name)
+			}
+		}
+	}
+
+	// Default and validate the resolved values, which have not been defaulted and validated by the webhook.
+	if errs := webhooks.DefaultAndValidateVariables(cluster, s.Blueprint.ClusterClass); len(errs) > 0 {
+		return apierrors.NewInvalid(clusterv1.GroupVersion.WithKind("Cluster").GroupKind(), cluster.Name, errs)
+	}
+
+	valuesHash, err := hash.Compute(resolvedValues)
+	if err != nil {
+		return errors.Wrap(err, "failed to compute the hash of the variable values from ConfigMaps and Secrets")
+	}
+
+	s.Blueprint.Topology = topology
+	s.Blueprint.VariableValueSourcesHash = strconv.FormatUint(uint64(valuesHash), 16)
+	return nil
+}
+
+// resolveVariables returns a copy of the given variables with the values set from ConfigMaps or Secrets resolved.
+// Variables using an optional source which does not exist are dropped.
+func (r *Reconciler) resolveVariables(ctx context.Context, namespace string, variables []clusterv1.ClusterVariable, definitions []clusterv1.ClusterClassStatusVariable, resolvedValues *[]clusterv1.ClusterVariable) ([]clusterv1.ClusterVariable, error) {
+	res := make([]clusterv1.ClusterVariable, 0, len(variables))
+	for _, variable := range variables {
+		if variable.ValueFrom == nil {
+			res = append(res, variable)
+			continue
+		}
+
+		schemaType := variableSchemaType(definitions, variable.Name, variable.DefinitionFrom)
+		value, found, err := r.getVariableSourceValue(ctx, namespace, variable.ValueFrom, schemaType)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to resolve the value of variable %q", variable.Name)
+		}
+		if !found {
+			continue
+		}
+
+		resolved := clusterv1.ClusterVariable{
+			Name:           variable.Name,
+			DefinitionFrom: variable.DefinitionFrom,
+			Value:          *value,
+		}
+		res = append(res, resolved)
+		*resolvedValues = append(*resolvedValues, resolved)
+	}
+	return res, nil
+}
+
+// getVariableSourceValue reads the value of a variable from a key of a ConfigMap or of a Secret.
+// It returns false if the source is optional and either the object or the key does not exist.
+// The value is always used as a string if the schema of the variable is of type string; otherwise the value is parsed
+// as JSON, and used as a string only if it is not valid JSON.
+func (r *Reconciler) getVariableSourceValue(ctx context.Context, namespace string, source *clusterv1.ClusterVariableValueSource, schemaType string) (*apiextensionsv1.JSON, bool, error) {
+	var (
+		data     []byte
+		found    bool
+		optional bool
+		err      error
+	)
+	switch {
+	case source.ConfigMapKeyRef != nil:
+		optional = pointer.BoolDeref(source.ConfigMapKeyRef.Optional, false)
+		data, found, err = r.getConfigMapKey(ctx, namespace, source.ConfigMapKeyRef)
+	case source.SecretKeyRef != nil:
+		optional = pointer.BoolDeref(source.SecretKeyRef.Optional, false)
+		data, found, err = r.getSecretKey(ctx, namespace, source.SecretKeyRef)
+	default:
+		return nil, false, errors.New("one of configMapKeyRef or secretKeyRef must be set")
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if !found {
+		if optional {
+			return nil, false, nil
+		}
+		if source.ConfigMapKeyRef != nil {
+			return nil, false, errors.Errorf("key %q of ConfigMap %s/%s not found", source.ConfigMapKeyRef.Key, namespace, source.ConfigMapKeyRef.Name)
+		}
+		return nil, false, errors.Errorf("key %q of Secret %s/%s not found", source.SecretKeyRef.Key, namespace, source.SecretKeyRef.Name)
+	}
+
+	// Use the data as is if it is valid JSON and the variable is not a string, otherwise use it as a string.
+	if schemaType == "string" || !json.Valid(data) {
+		if data, err = json.Marshal(string(data)); err != nil {
+			return nil, false, errors.Wrap(err, "failed to marshal the value as a string")
+		}
+	}
+	return &apiextensionsv1.JSON{Raw: data}, true, nil
+}
+
+func (r *Reconciler) getConfigMapKey(ctx context.Context, namespace string, ref *corev1.ConfigMapKeySelector) ([]byte, bool, error) {
+	configMap := &corev1.ConfigMap{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, configMap); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, false, nil
+		}
+		return nil, false, errors.Wrapf(err, "failed to get ConfigMap %s/%s", namespace, ref.Name)
+	}
+	if value, ok := configMap.Data[ref.Key]; ok {
+		return []byte(value), true, nil
+	}
+	value, ok := configMap.BinaryData[ref.Key]
+	return value, ok, nil
+}
+
+func (r *Reconciler) getSecretKey(ctx context.Context, namespace string, ref *corev1.SecretKeySelector) ([]byte, bool, error) {
+	secret := &corev1.Secret{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, false, nil
+		}
+		return nil, false, errors.Wrapf(err, "failed to get Secret %s/%s", namespace, ref.Name)
+	}
+	value, ok := secret.Data[ref.Key]
+	return value, ok, nil
+}
+
+// variableSchemaType returns the type of the schema of a variable, using the first definition matching the name
+// and the definitionFrom of the variable; it returns an empty string if there are no matching definitions.
+func variableSchemaType(definitions []clusterv1.ClusterClassStatusVariable, name, definitionFrom string) string {
+	for _, variable := range definitions {
+		if variable.Name != name {
+			continue
+		}
+		for _, definition := range variable.Definitions {
+			if definitionFrom == "" || definition.From == definitionFrom {
+				return definition.Schema.OpenAPIV3Schema.Type
+			}
+		}
+	}
+	return ""
+}
+
+// machineDeploymentClassVariableDefinitions returns the definitions of the variables of a MachineDeploymentClass.
+func machineDeploymentClassVariableDefinitions(clusterClass *clusterv1.ClusterClass, class string) []clusterv1.ClusterClassStatusVariable {
+	var definitions []clusterv1.ClusterClassStatusVariable
+	for _, mdClass := range clusterClass.Spec.Workers.MachineDeployments {
+		if mdClass.Class != class {
+			continue
+		}
+		for _, variable := range mdClass.Variables {
+			definitions = append(definitions, clusterv1.ClusterClassStatusVariable{
+				Name: variable.Name,
+				Definitions: []clusterv1.ClusterClassStatusVariableDefinition{
+					{
+						From:   clusterv1.VariableDefinitionFromInline,
+						Schema: variable.Schema,
+					},
+				},
+			})
+		}
+	}
+	return definitions
+}
+
+// configMapToCluster is a handler.ToRequestsFunc to be used to enqueue requests for reconciliation
+// for Clusters with variables set from a ConfigMap when the ConfigMap changes.
+func (r *Reconciler) configMapToCluster(ctx context.Context, o client.Object) []ctrl.Request {
+	return r.variableValueSourceToCluster(ctx, o, func(source *clusterv1.ClusterVariableValueSource) bool {
+		return source.ConfigMapKeyRef != nil && source.ConfigMapKeyRef.Name == o.GetName()
+	})
+}
+
+// secretToCluster is a handler.ToRequestsFunc to be used to enqueue requests for reconciliation
+// for Clusters with variables set from a Secret when the Secret changes.
+func (r *Reconciler) secretToCluster(ctx context.Context, o client.Object) []ctrl.Request {
+	return r.variableValueSourceToCluster(ctx, o, func(source *clusterv1.ClusterVariableValueSource) bool {
+		return source.SecretKeyRef != nil && source.SecretKeyRef.Name == o.GetName()
+	})
+}
+
+// variableValueSourceToCluster returns requests for the Clusters in the namespace of the object with a variable
+// set from the object.
+func (r *Reconciler) variableValueSourceToCluster(ctx context.Context, o client.Object, matches func(source *clusterv1.ClusterVariableValueSource) bool) []ctrl.Request {
+	clusterList := &clusterv1.ClusterList{}
+	if err := r.Client.List(ctx, clusterList, client.InNamespace(o.GetNamespace())); err != nil {
+		return nil
+	}
+
+	requests := []ctrl.Request{}
+	for i := range clusterList.Items {
+		cluster := &clusterList.Items[i]
+		if cluster.Spec.Topology == nil {
+			continue
+		}
+		for _, source := range variableValueSources(cluster.Spec.Topology) {
+			if matches(source) {
+				requests = append(requests, ctrl.Request{NamespacedName: util.ObjectKey(cluster)})
+				break
+			}
+		}
+	}
+	return requests
+}
+
+// hasVariableValueSources returns true if any variable or MachineDeployment variable override of the topology
+// is set from a ConfigMap or a Secret.
+func hasVariableValueSources(topology *clusterv1.Topology) bool {
+	return len(variableValueSources(topology)) > 0
+}
+
+// variableValueSources returns the ConfigMaps and Secrets the variables and the MachineDeployment variable
+// overrides of the topology are set from.
+func variableValueSources(topology *clusterv1.Topology) []*clusterv1.ClusterVariableValueSource {
+	sources := []*clusterv1.ClusterVariableValueSource{}
+	for _, variable := range topology.Variables {
+		if variable.ValueFrom != nil {
+			sources = append(sources, variable.ValueFrom)
+		}
+	}
+	if topology.Workers != nil {
+		for _, md := range topology.Workers.MachineDeployments {
+			if md.Variables == nil {
+				continue
+			}
+			for _, variable := range md.Variables.Overrides {
+				if variable.ValueFrom != nil {
+					sources = append(sources, variable.ValueFrom)
+				}
+			}
+		}
+	}
+	return sources
+}
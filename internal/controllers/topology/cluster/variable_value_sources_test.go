@@ -0,0 +1,349 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/scope"
+)
+
+func TestResolveVariableValueSources(t *testing.T) {
+	proxyConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "proxy",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Data: map[string]string{
+			"proxy":    `{"url": "http://proxy:3128"}`,
+			"imageTag": "1.28",
+		},
+	}
+	registrySecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "registry",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Data: map[string][]byte{
+			"password": []byte("s3cr3t"),
+		},
+	}
+
+	clusterClass := &clusterv1.ClusterClass{
+		Status: clusterv1.ClusterClassStatus{
+			Variables: []clusterv1.ClusterClassStatusVariable{
+				statusVariable("proxy", clusterv1.JSONSchemaProps{
+					Type: "object",
+					Properties: map[string]clusterv1.JSONSchemaProps{
+						"url":     {Type: "string"},
+						"noProxy": {Type: "string", Default: &apiextensionsv1.JSON{Raw: []byte(`"localhost"`)}},
+					},
+				}),
+				statusVariable("registryPassword", clusterv1.JSONSchemaProps{Type: "string"}),
+				statusVariable("replicas", clusterv1.JSONSchemaProps{Type: "integer"}),
+				statusVariable("imageTag", clusterv1.JSONSchemaProps{Type: "string"}),
+			},
+		},
+	}
+
+	proxyFromConfigMap := clusterv1.ClusterVariable{
+		Name: "proxy",
+		ValueFrom: &clusterv1.ClusterVariableValueSource{
+			ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "proxy"},
+				Key:                  "proxy",
+			},
+		},
+	}
+	passwordFromSecret := clusterv1.ClusterVariable{
+		Name: "registryPassword",
+		ValueFrom: &clusterv1.ClusterVariableValueSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "registry"},
+				Key:                  "password",
+			},
+		},
+	}
+	inlineReplicas := clusterv1.ClusterVariable{
+		Name:  "replicas",
+		Value: apiextensionsv1.JSON{Raw: []byte(`3`)},
+	}
+
+	newCluster := func(variables []clusterv1.ClusterVariable, overrides []clusterv1.ClusterVariable) *clusterv1.Cluster {
+		cluster := &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster1",
+				Namespace: metav1.NamespaceDefault,
+			},
+			Spec: clusterv1.ClusterSpec{
+				Topology: &clusterv1.Topology{
+					Class:     "class1",
+					Version:   "v1.27.3",
+					Variables: variables,
+				},
+			},
+		}
+		if overrides != nil {
+			cluster.Spec.Topology.Workers = &clusterv1.WorkersTopology{
+				MachineDeployments: []clusterv1.MachineDeploymentTopology{
+					{
+						Name:      "md1",
+						Class:     "default-worker",
+						Variables: &clusterv1.MachineDeploymentVariables{Overrides: overrides},
+					},
+				},
+			}
+		}
+		return cluster
+	}
+
+	tests := []struct {
+		name          string
+		cluster       *clusterv1.Cluster
+		objs          []client.Object
+		wantVariables []clusterv1.ClusterVariable
+		wantOverrides []clusterv1.ClusterVariable
+		wantHash      bool
+		wantErr       bool
+	}{
+		{
+			name:          "No-op if no variable is set from a ConfigMap or a Secret",
+			cluster:       newCluster([]clusterv1.ClusterVariable{inlineReplicas}, nil),
+			wantVariables: []clusterv1.ClusterVariable{inlineReplicas},
+			wantHash:      false,
+		},
+		{
+			name:    "Resolve values from ConfigMaps and Secrets",
+			cluster: newCluster([]clusterv1.ClusterVariable{proxyFromConfigMap, inlineReplicas}, []clusterv1.ClusterVariable{passwordFromSecret}),
+			objs:    []client.Object{proxyConfigMap, registrySecret},
+			wantVariables: []clusterv1.ClusterVariable{
+				// The value from the ConfigMap is defaulted.
+				{Name: "proxy", Value: apiextensionsv1.JSON{Raw: []byte(`{"noProxy":"localhost","url":"http://proxy:3128"}`)}},
+				{Name: "replicas", Value: apiextensionsv1.JSON{Raw: []byte(`3`)}},
+			},
+			wantOverrides: []clusterv1.ClusterVariable{
+				// The value from the Secret is not valid JSON, so it is used as a string.
+				{Name: "registryPassword", Value: apiextensionsv1.JSON{Raw: []byte(`"s3cr3t"`)}},
+			},
+			wantHash: true,
+		},
+		{
+			name: "Resolve values of variables of type string as strings, even if they are valid JSON",
+			cluster: newCluster([]clusterv1.ClusterVariable{
+				{
+					Name: "imageTag",
+					ValueFrom: &clusterv1.ClusterVariableValueSource{
+						ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "proxy"},
+							Key:                  "imageTag",
+						},
+					},
+				},
+			}, nil),
+			objs: []client.Object{proxyConfigMap},
+			wantVariables: []clusterv1.ClusterVariable{
+				{Name: "imageTag", Value: apiextensionsv1.JSON{Raw: []byte(`"1.28"`)}},
+			},
+			wantHash: true,
+		},
+		{
+			name: "Drop variables with an optional source which does not exist",
+			cluster: newCluster([]clusterv1.ClusterVariable{
+				{
+					Name: "registryPassword",
+					ValueFrom: &clusterv1.ClusterVariableValueSource{
+						SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "registry"},
+							Key:                  "password",
+							Optional:             pointer.Bool(true),
+						},
+					},
+				},
+				inlineReplicas,
+			}, nil),
+			wantVariables: []clusterv1.ClusterVariable{inlineReplicas},
+			wantHash:      true,
+		},
+		{
+			name:    "Fail if a source which is not optional does not exist",
+			cluster: newCluster([]clusterv1.ClusterVariable{passwordFromSecret}, nil),
+			wantErr: true,
+		},
+		{
+			name: "Fail if a resolved value is not valid",
+			cluster: newCluster([]clusterv1.ClusterVariable{
+				{
+					Name: "replicas",
+					ValueFrom: &clusterv1.ClusterVariableValueSource{
+						SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "registry"},
+							Key:                  "password",
+						},
+					},
+				},
+			}, nil),
+			objs:    []client.Object{registrySecret},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			fakeClient := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(tt.objs...).Build()
+			r := &Reconciler{
+				Client: fakeClient,
+			}
+
+			s := scope.New(tt.cluster.DeepCopy())
+			s.Blueprint = &scope.ClusterBlueprint{
+				Topology:     s.Current.Cluster.Spec.Topology,
+				ClusterClass: clusterClass,
+			}
+
+			err := r.resolveVariableValueSources(ctx, s)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+
+			// The Cluster is not modified.
+			g.Expect(s.Current.Cluster.Spec.Topology).To(Equal(tt.cluster.Spec.Topology))
+
+			g.Expect(s.Blueprint.Topology.Variables).To(Equal(tt.wantVariables))
+			if tt.wantOverrides != nil {
+				g.Expect(s.Blueprint.Topology.Workers.MachineDeployments[0].Variables.Overrides).To(Equal(tt.wantOverrides))
+			}
+			g.Expect(s.Blueprint.VariableValueSourcesHash != "").To(Equal(tt.wantHash))
+		})
+	}
+
+	t.Run("The hash changes when the values change", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cluster := newCluster([]clusterv1.ClusterVariable{passwordFromSecret}, nil)
+		fakeClient := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(registrySecret.DeepCopy()).Build()
+		r := &Reconciler{
+			Client: fakeClient,
+		}
+
+		resolveHash := func() string {
+			s := scope.New(cluster.DeepCopy())
+			s.Blueprint = &scope.ClusterBlueprint{
+				Topology:     s.Current.Cluster.Spec.Topology,
+				ClusterClass: clusterClass,
+			}
+			g.Expect(r.resolveVariableValueSources(ctx, s)).To(Succeed())
+			return s.Blueprint.VariableValueSourcesHash
+		}
+
+		hash := resolveHash()
+		g.Expect(resolveHash()).To(Equal(hash))
+
+		secret := registrySecret.DeepCopy()
+		secret.Data["password"] = []byte("n3w-s3cr3t")
+		g.Expect(fakeClient.Update(ctx, secret)).To(Succeed())
+		g.Expect(resolveHash()).ToNot(Equal(hash))
+	})
+}
+
+func TestVariableValueSourceToCluster(t *testing.T) {
+	g := NewWithT(t)
+
+	valueFromConfigMap := func(name string) clusterv1.ClusterVariable {
+		return clusterv1.ClusterVariable{
+			Name: "proxy",
+			ValueFrom: &clusterv1.ClusterVariableValueSource{
+				ConfigMapKeyRef: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: name}, Key: "proxy"},
+			},
+		}
+	}
+	valueFromSecret := func(name string) clusterv1.ClusterVariable {
+		return clusterv1.ClusterVariable{
+			Name: "registryPassword",
+			ValueFrom: &clusterv1.ClusterVariableValueSource{
+				SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: name}, Key: "password"},
+			},
+		}
+	}
+	newCluster := func(name string, variables []clusterv1.ClusterVariable, overrides []clusterv1.ClusterVariable) *clusterv1.Cluster {
+		return &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: metav1.NamespaceDefault},
+			Spec: clusterv1.ClusterSpec{
+				Topology: &clusterv1.Topology{
+					Class:     "class1",
+					Version:   "v1.27.3",
+					Variables: variables,
+					Workers: &clusterv1.WorkersTopology{
+						MachineDeployments: []clusterv1.MachineDeploymentTopology{
+							{Name: "md1", Class: "default-worker", Variables: &clusterv1.MachineDeploymentVariables{Overrides: overrides}},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(
+		newCluster("cluster1", []clusterv1.ClusterVariable{valueFromConfigMap("proxy")}, nil),
+		newCluster("cluster2", nil, []clusterv1.ClusterVariable{valueFromConfigMap("proxy"), valueFromSecret("registry")}),
+		newCluster("cluster3", []clusterv1.ClusterVariable{valueFromConfigMap("other")}, nil),
+		&clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster4", Namespace: metav1.NamespaceDefault}},
+	).Build()
+	r := &Reconciler{
+		Client: fakeClient,
+	}
+
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "proxy", Namespace: metav1.NamespaceDefault}}
+	g.Expect(r.configMapToCluster(ctx, configMap)).To(ConsistOf(
+		ctrl.Request{NamespacedName: types.NamespacedName{Namespace: metav1.NamespaceDefault, Name: "cluster1"}},
+		ctrl.Request{NamespacedName: types.NamespacedName{Namespace: metav1.NamespaceDefault, Name: "cluster2"}},
+	))
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "registry", Namespace: metav1.NamespaceDefault}}
+	g.Expect(r.secretToCluster(ctx, secret)).To(ConsistOf(
+		ctrl.Request{NamespacedName: types.NamespacedName{Namespace: metav1.NamespaceDefault, Name: "cluster2"}},
+	))
+
+	// A Secret with the same name of a ConfigMap used by Clusters does not trigger a reconcile.
+	secret = &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "proxy", Namespace: metav1.NamespaceDefault}}
+	g.Expect(r.secretToCluster(ctx, secret)).To(BeEmpty())
+}
+
+func statusVariable(name string, schema clusterv1.JSONSchemaProps) clusterv1.ClusterClassStatusVariable {
+	return clusterv1.ClusterClassStatusVariable{
+		Name: name,
+		Definitions: []clusterv1.ClusterClassStatusVariableDefinition{
+			{
+				From:   clusterv1.VariableDefinitionFromInline,
+				Schema: clusterv1.VariableSchema{OpenAPIV3Schema: schema},
+			},
+		},
+	}
+}
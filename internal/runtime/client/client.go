@@ -32,6 +32,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -50,6 +51,7 @@ import (
 	runtimemetrics "sigs.k8s.io/cluster-api/internal/runtime/metrics"
 	runtimeregistry "sigs.k8s.io/cluster-api/internal/runtime/registry"
 	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/tracing"
 )
 
 type errCallingExtensionHandler error
@@ -190,8 +192,16 @@ func (c *client) Unregister(extensionConfig *runtimev1.ExtensionConfig) error {
 // This ensures we don't end up waiting for timeout from multiple unreachable Extensions.
 // See CallExtension for more details on when an ExtensionHandler returns an error.
 // The aggregated result of the ExtensionHandlers is updated into the response object passed to the function.
-func (c *client) CallAllExtensions(ctx context.Context, hook runtimecatalog.Hook, forObject metav1.Object, request runtimehooksv1.RequestObject, response runtimehooksv1.ResponseObject) error {
+func (c *client) CallAllExtensions(ctx context.Context, hook runtimecatalog.Hook, forObject metav1.Object, request runtimehooksv1.RequestObject, response runtimehooksv1.ResponseObject) (reterr error) {
 	hookName := runtimecatalog.HookName(hook)
+	ctx, span := tracing.StartSpan(ctx, "runtime.Client.CallAllExtensions",
+		attribute.String("hook", hookName),
+		attribute.String("namespace", forObject.GetNamespace()),
+		attribute.String("name", forObject.GetName()),
+	)
+	defer func() {
+		tracing.EndSpan(span, reterr)
+	}()
 	log := ctrl.LoggerFrom(ctx).WithValues("hook", hookName)
 	ctx = ctrl.LoggerInto(ctx, log)
 	gvh, err := c.catalog.GroupVersionHook(hook)
@@ -265,6 +275,10 @@ func aggregateSuccessfulResponses(aggregatedResponse runtimehooksv1.ResponseObje
 				resp.(runtimehooksv1.RetryResponseObject).GetRetryAfterSeconds(),
 			))
 		}
+		// The canary Machines are rolled back if any of the extensions rejected them.
+		if aggregatedCanaryResponse, ok := aggregatedResponse.(*runtimehooksv1.AfterCanaryReadyResponse); ok {
+			aggregatedCanaryResponse.Rollback = aggregatedCanaryResponse.Rollback || resp.(*runtimehooksv1.AfterCanaryReadyResponse).Rollback
+		}
 		if resp.GetMessage() != "" {
 			messages = append(messages, resp.GetMessage())
 		}
@@ -284,7 +298,17 @@ func aggregateSuccessfulResponses(aggregatedResponse runtimehooksv1.ResponseObje
 // Nb. FailurePolicy does not affect the following kinds of errors:
 // - Internal errors. Examples: hooks is incompatible with ExtensionHandler, ExtensionHandler information is missing.
 // - Error when ExtensionHandler returns a response with `Status` set to `Failure`.
-func (c *client) CallExtension(ctx context.Context, hook runtimecatalog.Hook, forObject metav1.Object, name string, request runtimehooksv1.RequestObject, response runtimehooksv1.ResponseObject) error {
+func (c *client) CallExtension(ctx context.Context, hook runtimecatalog.Hook, forObject metav1.Object, name string, request runtimehooksv1.RequestObject, response runtimehooksv1.ResponseObject) (reterr error) {
+	ctx, span := tracing.StartSpan(ctx, "runtime.Client.CallExtension",
+		attribute.String("hook", runtimecatalog.HookName(hook)),
+		attribute.String("extensionHandler", name),
+		attribute.String("namespace", forObject.GetNamespace()),
+		attribute.String("name", forObject.GetName()),
+	)
+	defer func() {
+		tracing.EndSpan(span, reterr)
+	}()
+
 	log := ctrl.LoggerFrom(ctx).WithValues("extensionHandler", name, "hook", runtimecatalog.HookName(hook))
 	ctx = ctrl.LoggerInto(ctx, log)
 	hookGVH, err := c.catalog.GroupVersionHook(hook)
@@ -483,9 +507,9 @@ func httpCall(ctx context.Context, request, response runtime.Object, opts *httpC
 		return errors.Wrap(err, "http call failed: failed to create tls config")
 	}
 	// This also adds http2
-	client.Transport = utilnet.SetTransportDefaults(&http.Transport{
+	client.Transport = tracing.WrapTransport(utilnet.SetTransportDefaults(&http.Transport{
 		TLSClientConfig: tlsConfig,
-	})
+	}))
 
 	resp, err := client.Do(httpRequest)
 
@@ -1222,6 +1222,22 @@ func Test_aggregateResponses(t *testing.T) {
 			},
 			want: fakeRetryableSuccessResponse(1, "test1, test2"),
 		},
+		{
+			name:              "Aggregate AfterCanaryReady responses to rollback if any response requests it",
+			aggregateResponse: &runtimehooksv1.AfterCanaryReadyResponse{},
+			responses: []runtimehooksv1.ResponseObject{
+				&runtimehooksv1.AfterCanaryReadyResponse{},
+				&runtimehooksv1.AfterCanaryReadyResponse{
+					CommonRetryResponse: runtimehooksv1.CommonRetryResponse{CommonResponse: runtimehooksv1.CommonResponse{Message: "test"}},
+					Rollback:            true,
+				},
+				&runtimehooksv1.AfterCanaryReadyResponse{},
+			},
+			want: &runtimehooksv1.AfterCanaryReadyResponse{
+				CommonRetryResponse: runtimehooksv1.CommonRetryResponse{CommonResponse: runtimehooksv1.CommonResponse{Status: runtimehooksv1.ResponseStatusSuccess, Message: "test"}},
+				Rollback:            true,
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
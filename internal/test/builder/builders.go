@@ -43,6 +43,10 @@ type ClusterBuilder struct {
 	infrastructureCluster *unstructured.Unstructured
 	controlPlane          *unstructured.Unstructured
 	network               *clusterv1.ClusterNetwork
+
+	controlPlaneProvidesInfrastructure bool
+	maintenanceWindows                 []clusterv1.MaintenanceWindow
+	metadata                           *clusterv1.ObjectMeta
 }
 
 // Cluster returns a ClusterBuilder with the given name and namespace.
@@ -83,6 +87,24 @@ func (c *ClusterBuilder) WithControlPlane(t *unstructured.Unstructured) *Cluster
 	return c
 }
 
+// WithControlPlaneProvidesInfrastructure sets ControlPlaneProvidesInfrastructure for the ClusterBuilder.
+func (c *ClusterBuilder) WithControlPlaneProvidesInfrastructure() *ClusterBuilder {
+	c.controlPlaneProvidesInfrastructure = true
+	return c
+}
+
+// WithMaintenanceWindows sets the MaintenanceWindows for the ClusterBuilder.
+func (c *ClusterBuilder) WithMaintenanceWindows(windows ...clusterv1.MaintenanceWindow) *ClusterBuilder {
+	c.maintenanceWindows = windows
+	return c
+}
+
+// WithMetadata sets the Metadata propagated to the descendants of the Cluster for the ClusterBuilder.
+func (c *ClusterBuilder) WithMetadata(metadata clusterv1.ObjectMeta) *ClusterBuilder {
+	c.metadata = &metadata
+	return c
+}
+
 // WithTopology adds the passed Topology object to the ClusterBuilder.
 func (c *ClusterBuilder) WithTopology(topology *clusterv1.Topology) *ClusterBuilder {
 	c.topology = topology
@@ -103,8 +125,11 @@ func (c *ClusterBuilder) Build() *clusterv1.Cluster {
 			Annotations: c.annotations,
 		},
 		Spec: clusterv1.ClusterSpec{
-			Topology:       c.topology,
-			ClusterNetwork: c.network,
+			Topology:                           c.topology,
+			ClusterNetwork:                     c.network,
+			ControlPlaneProvidesInfrastructure: c.controlPlaneProvidesInfrastructure,
+			MaintenanceWindows:                 c.maintenanceWindows,
+			Metadata:                           c.metadata,
 		},
 	}
 	if c.infrastructureCluster != nil {
@@ -253,6 +278,7 @@ type ClusterClassBuilder struct {
 	controlPlaneNodeDrainTimeout              *metav1.Duration
 	controlPlaneNodeVolumeDetachTimeout       *metav1.Duration
 	controlPlaneNodeDeletionTimeout           *metav1.Duration
+	controlPlaneNamingStrategy                *clusterv1.ControlPlaneClassNamingStrategy
 	machineDeploymentClasses                  []clusterv1.MachineDeploymentClass
 	variables                                 []clusterv1.ClusterClassVariable
 	statusVariables                           []clusterv1.ClusterClassStatusVariable
@@ -318,6 +344,12 @@ func (c *ClusterClassBuilder) WithControlPlaneNodeDeletionTimeout(t *metav1.Dura
 	return c
 }
 
+// WithControlPlaneNamingStrategy adds a NamingStrategy for the ControlPlane to the ClusterClassBuilder.
+func (c *ClusterClassBuilder) WithControlPlaneNamingStrategy(n *clusterv1.ControlPlaneClassNamingStrategy) *ClusterClassBuilder {
+	c.controlPlaneNamingStrategy = n
+	return c
+}
+
 // WithVariables adds the Variables to the ClusterClassBuilder.
 func (c *ClusterClassBuilder) WithVariables(vars ...clusterv1.ClusterClassVariable) *ClusterClassBuilder {
 	c.variables = vars
@@ -389,6 +421,9 @@ func (c *ClusterClassBuilder) Build() *clusterv1.ClusterClass {
 	if c.controlPlaneNodeDeletionTimeout != nil {
 		obj.Spec.ControlPlane.NodeDeletionTimeout = c.controlPlaneNodeDeletionTimeout
 	}
+	if c.controlPlaneNamingStrategy != nil {
+		obj.Spec.ControlPlane.NamingStrategy = c.controlPlaneNamingStrategy
+	}
 	if c.controlPlaneInfrastructureMachineTemplate != nil {
 		obj.Spec.ControlPlane.MachineInfrastructure = &clusterv1.LocalObjectTemplate{
 			Ref: objToRef(c.controlPlaneInfrastructureMachineTemplate),
@@ -413,6 +448,12 @@ type MachineDeploymentClassBuilder struct {
 	nodeDeletionTimeout           *metav1.Duration
 	minReadySeconds               *int32
 	strategy                      *clusterv1.MachineDeploymentStrategy
+	ipAddressClaims               []clusterv1.IPAddressClaimTemplate
+	namingStrategy                *clusterv1.MachineDeploymentClassNamingStrategy
+	machineSetNamingStrategy      *clusterv1.MachineSetNamingStrategy
+	machineNamingStrategy         *clusterv1.MachineNamingStrategy
+	autoscaling                   *clusterv1.MachineDeploymentClassAutoscaling
+	variables                     []clusterv1.ClusterClassVariable
 }
 
 // MachineDeploymentClass returns a MachineDeploymentClassBuilder with the given name and namespace.
@@ -488,6 +529,42 @@ func (m *MachineDeploymentClassBuilder) WithStrategy(s *clusterv1.MachineDeploym
 	return m
 }
 
+// WithIPAddressClaims sets the IPAddressClaims for the MachineDeploymentClassBuilder.
+func (m *MachineDeploymentClassBuilder) WithIPAddressClaims(claims ...clusterv1.IPAddressClaimTemplate) *MachineDeploymentClassBuilder {
+	m.ipAddressClaims = claims
+	return m
+}
+
+// WithNamingStrategy sets the NamingStrategy for the MachineDeploymentClassBuilder.
+func (m *MachineDeploymentClassBuilder) WithNamingStrategy(n *clusterv1.MachineDeploymentClassNamingStrategy) *MachineDeploymentClassBuilder {
+	m.namingStrategy = n
+	return m
+}
+
+// WithMachineSetNamingStrategy sets the MachineSetNamingStrategy for the MachineDeploymentClassBuilder.
+func (m *MachineDeploymentClassBuilder) WithMachineSetNamingStrategy(n *clusterv1.MachineSetNamingStrategy) *MachineDeploymentClassBuilder {
+	m.machineSetNamingStrategy = n
+	return m
+}
+
+// WithMachineNamingStrategy sets the MachineNamingStrategy for the MachineDeploymentClassBuilder.
+func (m *MachineDeploymentClassBuilder) WithMachineNamingStrategy(n *clusterv1.MachineNamingStrategy) *MachineDeploymentClassBuilder {
+	m.machineNamingStrategy = n
+	return m
+}
+
+// WithAutoscaling sets the Autoscaling for the MachineDeploymentClassBuilder.
+func (m *MachineDeploymentClassBuilder) WithAutoscaling(a *clusterv1.MachineDeploymentClassAutoscaling) *MachineDeploymentClassBuilder {
+	m.autoscaling = a
+	return m
+}
+
+// WithVariables sets the Variables for the MachineDeploymentClassBuilder.
+func (m *MachineDeploymentClassBuilder) WithVariables(variables ...clusterv1.ClusterClassVariable) *MachineDeploymentClassBuilder {
+	m.variables = variables
+	return m
+}
+
 // Build creates a full MachineDeploymentClass object with the variables passed to the MachineDeploymentClassBuilder.
 func (m *MachineDeploymentClassBuilder) Build() *clusterv1.MachineDeploymentClass {
 	obj := &clusterv1.MachineDeploymentClass{
@@ -526,6 +603,24 @@ func (m *MachineDeploymentClassBuilder) Build() *clusterv1.MachineDeploymentClas
 	if m.strategy != nil {
 		obj.Strategy = m.strategy
 	}
+	if m.ipAddressClaims != nil {
+		obj.IPAddressClaims = m.ipAddressClaims
+	}
+	if m.namingStrategy != nil {
+		obj.NamingStrategy = m.namingStrategy
+	}
+	if m.machineSetNamingStrategy != nil {
+		obj.MachineSetNamingStrategy = m.machineSetNamingStrategy
+	}
+	if m.machineNamingStrategy != nil {
+		obj.MachineNamingStrategy = m.machineNamingStrategy
+	}
+	if m.autoscaling != nil {
+		obj.Autoscaling = m.autoscaling
+	}
+	if m.variables != nil {
+		obj.Variables = m.variables
+	}
 	return obj
 }
 
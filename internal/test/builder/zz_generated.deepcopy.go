@@ -82,6 +82,16 @@ func (in *ClusterBuilder) DeepCopyInto(out *ClusterBuilder) {
 		*out = new(v1beta1.ClusterNetwork)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.maintenanceWindows != nil {
+		in, out := &in.maintenanceWindows, &out.maintenanceWindows
+		*out = make([]v1beta1.MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+	if in.metadata != nil {
+		in, out := &in.metadata, &out.metadata
+		*out = new(v1beta1.ObjectMeta)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterBuilder.
@@ -134,6 +144,11 @@ func (in *ClusterClassBuilder) DeepCopyInto(out *ClusterClassBuilder) {
 		*out = new(v1.Duration)
 		**out = **in
 	}
+	if in.controlPlaneNamingStrategy != nil {
+		in, out := &in.controlPlaneNamingStrategy, &out.controlPlaneNamingStrategy
+		*out = new(v1beta1.ControlPlaneClassNamingStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.machineDeploymentClasses != nil {
 		in, out := &in.machineDeploymentClasses, &out.machineDeploymentClasses
 		*out = make([]v1beta1.MachineDeploymentClass, len(*in))
@@ -447,6 +462,40 @@ func (in *MachineDeploymentClassBuilder) DeepCopyInto(out *MachineDeploymentClas
 		*out = new(v1beta1.MachineDeploymentStrategy)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ipAddressClaims != nil {
+		in, out := &in.ipAddressClaims, &out.ipAddressClaims
+		*out = make([]v1beta1.IPAddressClaimTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.namingStrategy != nil {
+		in, out := &in.namingStrategy, &out.namingStrategy
+		*out = new(v1beta1.MachineDeploymentClassNamingStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.machineSetNamingStrategy != nil {
+		in, out := &in.machineSetNamingStrategy, &out.machineSetNamingStrategy
+		*out = new(v1beta1.MachineSetNamingStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.machineNamingStrategy != nil {
+		in, out := &in.machineNamingStrategy, &out.machineNamingStrategy
+		*out = new(v1beta1.MachineNamingStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.autoscaling != nil {
+		in, out := &in.autoscaling, &out.autoscaling
+		*out = new(v1beta1.MachineDeploymentClassAutoscaling)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.variables != nil {
+		in, out := &in.variables, &out.variables
+		*out = make([]v1beta1.ClusterClassVariable, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineDeploymentClassBuilder.
@@ -275,6 +275,10 @@ func newEnvironment(uncachedObjs ...client.Object) *Environment {
 	if err := (&webhooks.ClusterClass{Client: mgr.GetClient()}).SetupWebhookWithManager(mgr); err != nil {
 		klog.Fatalf("unable to create webhook: %+v", err)
 	}
+	// NOTE: All the clients of the test environment, including the topology controller, authenticate as the envtest admin.
+	if err := (&webhooks.TopologyManagedFields{TopologyControllerUsername: "admin"}).SetupWebhookWithManager(mgr); err != nil {
+		klog.Fatalf("unable to create webhook: %+v", err)
+	}
 	if err := (&clusterv1.Machine{}).SetupWebhookWithManager(mgr); err != nil {
 		klog.Fatalf("unable to create webhook: %+v", err)
 	}
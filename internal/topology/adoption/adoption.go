@@ -0,0 +1,231 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package adoption implements checks for the adoption of the existing objects of a Cluster by the topology controller.
+package adoption
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/external"
+	"sigs.k8s.io/cluster-api/internal/contract"
+)
+
+// SharedTemplate is a template which would be adopted by the topology controller, but it is referenced by other objects too.
+type SharedTemplate struct {
+	// Ref is the reference to the template.
+	Ref corev1.ObjectReference
+
+	// AdoptedWith is the object the template would be adopted with, e.g. `MachineDeployment md1`.
+	AdoptedWith string
+
+	// ReferencedBy are the other objects referencing the template, e.g. `ClusterClass class1`.
+	ReferencedBy []string
+}
+
+// String returns a description of the shared template.
+func (t SharedTemplate) String() string {
+	return fmt.Sprintf("%s %s used by %s is referenced by %v", t.Ref.Kind, t.Ref.Name, t.AdoptedWith, t.ReferencedBy)
+}
+
+// templateKey identifies a template in the namespace of a Cluster.
+type templateKey struct {
+	groupKind schema.GroupKind
+	name      string
+}
+
+func keyFor(ref *corev1.ObjectReference) templateKey {
+	return templateKey{
+		groupKind: schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind).GroupKind(),
+		name:      ref.Name,
+	}
+}
+
+// GetSharedTemplates returns the templates of a Cluster attached to a ClusterClass which would be adopted by the
+// topology controller, i.e. the InfrastructureMachineTemplate of the control plane and the bootstrap and
+// infrastructure templates of the MachineDeployments with a corresponding MachineDeployment topology, that are
+// referenced by other objects too.
+// Such templates can't be adopted, because the topology controller deletes the templates it owns when rotating them.
+// NOTE: Other MachineDeployments, MachineSets not owned by a MachineDeployment, control planes of the same kind and
+// ClusterClasses in the namespace of the Cluster are considered as references; templates are always in the same
+// namespace of the objects referencing them.
+func GetSharedTemplates(ctx context.Context, c client.Reader, cluster *clusterv1.Cluster) ([]SharedTemplate, error) {
+	// Collect the templates which would be adopted, with the object they would be adopted with.
+	adoptedWith := map[templateKey]string{}
+	adoptedRefs := map[templateKey]*corev1.ObjectReference{}
+	adopt := func(ref *corev1.ObjectReference, owner string) {
+		if ref == nil || ref.Name == "" {
+			return
+		}
+		adoptedWith[keyFor(ref)] = owner
+		adoptedRefs[keyFor(ref)] = ref
+	}
+
+	var controlPlane *unstructured.Unstructured
+	if cluster.Spec.ControlPlaneRef != nil {
+		obj, err := external.Get(ctx, c, cluster.Spec.ControlPlaneRef, cluster.Namespace)
+		if err != nil && !apierrors.IsNotFound(errors.Cause(err)) {
+			return nil, err
+		}
+		if err == nil {
+			controlPlane = obj
+			if ref, err := contract.ControlPlane().MachineTemplate().InfrastructureRef().Get(controlPlane); err == nil {
+				adopt(ref, fmt.Sprintf("%s %s", controlPlane.GetKind(), controlPlane.GetName()))
+			}
+		}
+	}
+
+	mdTopologyNames := sets.Set[string]{}
+	if cluster.Spec.Topology != nil && cluster.Spec.Topology.Workers != nil {
+		for _, md := range cluster.Spec.Topology.Workers.MachineDeployments {
+			mdTopologyNames.Insert(md.Name)
+		}
+	}
+
+	mds := &clusterv1.MachineDeploymentList{}
+	if err := c.List(ctx, mds, client.InNamespace(cluster.Namespace)); err != nil {
+		return nil, errors.Wrapf(err, "failed to list MachineDeployments in namespace %s", cluster.Namespace)
+	}
+	for i := range mds.Items {
+		md := &mds.Items[i]
+		if md.Spec.ClusterName != cluster.Name {
+			continue
+		}
+		mdTopologyName := md.Name
+		if name, ok := md.Labels[clusterv1.ClusterTopologyMachineDeploymentNameLabel]; ok && name != "" {
+			mdTopologyName = name
+		}
+		if !mdTopologyNames.Has(mdTopologyName) {
+			continue
+		}
+		owner := fmt.Sprintf("MachineDeployment %s", md.Name)
+		adopt(md.Spec.Template.Spec.Bootstrap.ConfigRef, owner)
+		adopt(&md.Spec.Template.Spec.InfrastructureRef, owner)
+	}
+
+	if len(adoptedWith) == 0 {
+		return nil, nil
+	}
+
+	// Collect the other objects referencing the templates.
+	referencedBy := map[templateKey]sets.Set[string]{}
+	reference := func(ref *corev1.ObjectReference, referencer string) {
+		if ref == nil || ref.Name == "" {
+			return
+		}
+		key := keyFor(ref)
+		owner, ok := adoptedWith[key]
+		if !ok || owner == referencer {
+			return
+		}
+		if referencedBy[key] == nil {
+			referencedBy[key] = sets.Set[string]{}
+		}
+		referencedBy[key].Insert(referencer)
+	}
+
+	for i := range mds.Items {
+		md := &mds.Items[i]
+		referencer := fmt.Sprintf("MachineDeployment %s", md.Name)
+		reference(md.Spec.Template.Spec.Bootstrap.ConfigRef, referencer)
+		reference(&md.Spec.Template.Spec.InfrastructureRef, referencer)
+	}
+
+	machineSets := &clusterv1.MachineSetList{}
+	if err := c.List(ctx, machineSets, client.InNamespace(cluster.Namespace)); err != nil {
+		return nil, errors.Wrapf(err, "failed to list MachineSets in namespace %s", cluster.Namespace)
+	}
+	for i := range machineSets.Items {
+		ms := &machineSets.Items[i]
+		// MachineSets owned by a MachineDeployment are created from the templates of the MachineDeployment.
+		if isOwnedByMachineDeployment(ms) {
+			continue
+		}
+		referencer := fmt.Sprintf("MachineSet %s", ms.Name)
+		reference(ms.Spec.Template.Spec.Bootstrap.ConfigRef, referencer)
+		reference(&ms.Spec.Template.Spec.InfrastructureRef, referencer)
+	}
+
+	if controlPlane != nil {
+		controlPlanes := &unstructured.UnstructuredList{}
+		controlPlanes.SetGroupVersionKind(controlPlane.GroupVersionKind().GroupVersion().WithKind(controlPlane.GetKind() + "List"))
+		if err := c.List(ctx, controlPlanes, client.InNamespace(cluster.Namespace)); err != nil {
+			return nil, errors.Wrapf(err, "failed to list %s in namespace %s", controlPlane.GetKind(), cluster.Namespace)
+		}
+		for i := range controlPlanes.Items {
+			cp := &controlPlanes.Items[i]
+			if ref, err := contract.ControlPlane().MachineTemplate().InfrastructureRef().Get(cp); err == nil {
+				reference(ref, fmt.Sprintf("%s %s", cp.GetKind(), cp.GetName()))
+			}
+		}
+	}
+
+	clusterClasses := &clusterv1.ClusterClassList{}
+	if err := c.List(ctx, clusterClasses, client.InNamespace(cluster.Namespace)); err != nil {
+		return nil, errors.Wrapf(err, "failed to list ClusterClasses in namespace %s", cluster.Namespace)
+	}
+	for i := range clusterClasses.Items {
+		clusterClass := &clusterClasses.Items[i]
+		referencer := fmt.Sprintf("ClusterClass %s", clusterClass.Name)
+		if clusterClass.Spec.ControlPlane.MachineInfrastructure != nil {
+			reference(clusterClass.Spec.ControlPlane.MachineInfrastructure.Ref, referencer)
+		}
+		for _, mdClass := range clusterClass.Spec.Workers.MachineDeployments {
+			reference(mdClass.Template.Bootstrap.Ref, referencer)
+			reference(mdClass.Template.Infrastructure.Ref, referencer)
+		}
+		for _, mpClass := range clusterClass.Spec.Workers.MachinePools {
+			reference(mpClass.Template.Bootstrap.Ref, referencer)
+			reference(mpClass.Template.Infrastructure.Ref, referencer)
+		}
+	}
+
+	sharedTemplates := []SharedTemplate{}
+	for key, referencers := range referencedBy {
+		sharedTemplates = append(sharedTemplates, SharedTemplate{
+			Ref:          *adoptedRefs[key],
+			AdoptedWith:  adoptedWith[key],
+			ReferencedBy: sets.List(referencers),
+		})
+	}
+	sort.Slice(sharedTemplates, func(i, j int) bool {
+		if sharedTemplates[i].Ref.Kind != sharedTemplates[j].Ref.Kind {
+			return sharedTemplates[i].Ref.Kind < sharedTemplates[j].Ref.Kind
+		}
+		return sharedTemplates[i].Ref.Name < sharedTemplates[j].Ref.Name
+	})
+	return sharedTemplates, nil
+}
+
+func isOwnedByMachineDeployment(ms *clusterv1.MachineSet) bool {
+	for _, ref := range ms.OwnerReferences {
+		if ref.Kind == "MachineDeployment" && schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind).Group == clusterv1.GroupVersion.Group {
+			return true
+		}
+	}
+	return false
+}
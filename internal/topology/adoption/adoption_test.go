@@ -0,0 +1,155 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adoption
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/test/builder"
+)
+
+var (
+	ctx         = ctrl.SetupSignalHandler()
+	fakeScheme  = runtime.NewScheme()
+	namespace   = metav1.NamespaceDefault
+	clusterName = "cluster1"
+)
+
+func init() {
+	_ = clusterv1.AddToScheme(fakeScheme)
+}
+
+func TestGetSharedTemplates(t *testing.T) {
+	bootstrapTemplate := builder.BootstrapTemplate(namespace, "bootstrap").Build()
+	infraTemplate := builder.InfrastructureMachineTemplate(namespace, "infra").Build()
+	otherBootstrapTemplate := builder.BootstrapTemplate(namespace, "other-bootstrap").Build()
+	otherInfraTemplate := builder.InfrastructureMachineTemplate(namespace, "other-infra").Build()
+
+	cluster := builder.Cluster(namespace, clusterName).
+		WithTopology(builder.ClusterTopology().
+			WithClass("class1").
+			WithMachineDeployment(clusterv1.MachineDeploymentTopology{Name: "md1", Class: "default-worker"}).
+			Build()).
+		Build()
+	md := builder.MachineDeployment(namespace, "md1").
+		WithClusterName(clusterName).
+		WithBootstrapTemplate(bootstrapTemplate).
+		WithInfrastructureTemplate(infraTemplate).
+		Build()
+
+	tests := []struct {
+		name     string
+		objs     []client.Object
+		expected []string
+	}{
+		{
+			name: "Templates used only by the adopted MachineDeployment are not shared",
+			objs: []client.Object{
+				md,
+				builder.MachineSet(namespace, "md1-ms").
+					WithClusterName(clusterName).
+					WithBootstrapTemplate(bootstrapTemplate).
+					WithInfrastructureTemplate(infraTemplate).
+					WithOwnerReferences([]metav1.OwnerReference{{APIVersion: clusterv1.GroupVersion.String(), Kind: "MachineDeployment", Name: md.Name}}).
+					Build(),
+				builder.MachineDeployment(namespace, "md2").
+					WithClusterName("cluster2").
+					WithBootstrapTemplate(otherBootstrapTemplate).
+					WithInfrastructureTemplate(otherInfraTemplate).
+					Build(),
+			},
+			expected: nil,
+		},
+		{
+			name: "Templates used by a MachineDeployment of another Cluster are shared",
+			objs: []client.Object{
+				md,
+				builder.MachineDeployment(namespace, "md2").
+					WithClusterName("cluster2").
+					WithBootstrapTemplate(bootstrapTemplate).
+					WithInfrastructureTemplate(otherInfraTemplate).
+					Build(),
+			},
+			expected: []string{"bootstrap"},
+		},
+		{
+			name: "Templates used by a standalone MachineSet are shared",
+			objs: []client.Object{
+				md,
+				builder.MachineSet(namespace, "ms").
+					WithClusterName("cluster2").
+					WithBootstrapTemplate(otherBootstrapTemplate).
+					WithInfrastructureTemplate(infraTemplate).
+					Build(),
+			},
+			expected: []string{"infra"},
+		},
+		{
+			name: "Templates used by a ClusterClass are shared",
+			objs: []client.Object{
+				md,
+				builder.ClusterClass(namespace, "class1").
+					WithWorkerMachineDeploymentClasses(
+						*builder.MachineDeploymentClass("default-worker").
+							WithBootstrapTemplate(bootstrapTemplate).
+							WithInfrastructureTemplate(infraTemplate).
+							Build()).
+					Build(),
+			},
+			expected: []string{"bootstrap", "infra"},
+		},
+		{
+			name: "Templates of MachineDeployments without a MachineDeployment topology are not adopted",
+			objs: []client.Object{
+				builder.MachineDeployment(namespace, "md3").
+					WithClusterName(clusterName).
+					WithBootstrapTemplate(bootstrapTemplate).
+					WithInfrastructureTemplate(infraTemplate).
+					Build(),
+				builder.MachineDeployment(namespace, "md2").
+					WithClusterName("cluster2").
+					WithBootstrapTemplate(bootstrapTemplate).
+					WithInfrastructureTemplate(infraTemplate).
+					Build(),
+			},
+			expected: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			c := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(tt.objs...).Build()
+
+			sharedTemplates, err := GetSharedTemplates(ctx, c, cluster)
+			g.Expect(err).ToNot(HaveOccurred())
+			names := []string{}
+			for _, sharedTemplate := range sharedTemplates {
+				names = append(names, sharedTemplate.Ref.Name)
+			}
+			g.Expect(names).To(ConsistOf(tt.expected))
+		})
+	}
+}
@@ -207,8 +207,18 @@ func ClusterClassesAreCompatible(current, desired *clusterv1.ClusterClass) field
 	}
 
 	// Validate InfrastructureClusterTemplate changes desired a compatible way.
-	allErrs = append(allErrs, LocalObjectTemplatesAreCompatible(current.Spec.Infrastructure, desired.Spec.Infrastructure,
-		field.NewPath("spec", "infrastructure"))...)
+	// NOTE: The InfrastructureClusterTemplate cannot be added or removed, because this would change who provides
+	// the infrastructure of the Clusters.
+	switch {
+	case current.Spec.Infrastructure.Ref != nil && desired.Spec.Infrastructure.Ref != nil:
+		allErrs = append(allErrs, LocalObjectTemplatesAreCompatible(current.Spec.Infrastructure, desired.Spec.Infrastructure,
+			field.NewPath("spec", "infrastructure"))...)
+	case current.Spec.Infrastructure.Ref != nil || desired.Spec.Infrastructure.Ref != nil:
+		allErrs = append(allErrs, field.Forbidden(
+			field.NewPath("spec", "infrastructure", "ref"),
+			"cannot be added or removed to prevent incompatible changes in the Clusters",
+		))
+	}
 
 	// Validate control plane changes desired a compatible way.
 	allErrs = append(allErrs, LocalObjectTemplatesAreCompatible(current.Spec.ControlPlane.LocalObjectTemplate, desired.Spec.ControlPlane.LocalObjectTemplate,
@@ -331,12 +341,127 @@ func MachineDeploymentTopologiesAreValidAndDefinedInClusterClass(desired *cluste
 	return allErrs
 }
 
+// MachinePoolClassesAreUnique checks that no two MachinePoolClasses in a ClusterClass share a name, and that
+// the failure domains of each MachinePoolClass are valid.
+func MachinePoolClassesAreUnique(clusterClass *clusterv1.ClusterClass) field.ErrorList {
+	var allErrs field.ErrorList
+	classes := sets.Set[string]{}
+	for i, class := range clusterClass.Spec.Workers.MachinePools {
+		if classes.Has(class.Class) {
+			allErrs = append(allErrs,
+				field.Invalid(
+					field.NewPath("spec", "workers", "machinePools").Index(i).Child("class"),
+					class.Class,
+					fmt.Sprintf("MachinePool class must be unique. MachinePool with class %q is defined more than once", class.Class),
+				),
+			)
+		}
+		classes.Insert(class.Class)
+
+		allErrs = append(allErrs, failureDomainsAreValid(class.FailureDomains,
+			field.NewPath("spec", "workers", "machinePools").Index(i).Child("failureDomains"))...)
+	}
+	return allErrs
+}
+
+// MachinePoolTopologiesAreValidAndDefinedInClusterClass checks that each MachinePoolTopology name is not empty
+// and unique, each class in use is defined in ClusterClass.spec.Workers.MachinePools, and the failure domains
+// of each MachinePoolTopology are valid.
+func MachinePoolTopologiesAreValidAndDefinedInClusterClass(desired *clusterv1.Cluster, clusterClass *clusterv1.ClusterClass) field.ErrorList {
+	var allErrs field.ErrorList
+	if desired.Spec.Topology.Workers == nil {
+		return nil
+	}
+	if len(desired.Spec.Topology.Workers.MachinePools) == 0 {
+		return nil
+	}
+	// MachinePool class must be defined in the ClusterClass.
+	machinePoolClasses := sets.Set[string]{}
+	for _, class := range clusterClass.Spec.Workers.MachinePools {
+		machinePoolClasses.Insert(class.Class)
+	}
+	names := sets.Set[string]{}
+	for i, mp := range desired.Spec.Topology.Workers.MachinePools {
+		fldPath := field.NewPath("spec", "topology", "workers", "machinePools").Index(i)
+
+		if errs := validation.IsValidLabelValue(mp.Name); len(errs) != 0 {
+			for _, err := range errs {
+				allErrs = append(
+					allErrs,
+					field.Invalid(
+						fldPath.Child("name"),
+						mp.Name,
+						fmt.Sprintf("must be a valid label value %s", err),
+					),
+				)
+			}
+		}
+
+		if !machinePoolClasses.Has(mp.Class) {
+			allErrs = append(allErrs,
+				field.Invalid(
+					fldPath.Child("class"),
+					mp.Class,
+					fmt.Sprintf("MachinePoolClass with name %q does not exist in ClusterClass %q",
+						mp.Class, clusterClass.Name),
+				),
+			)
+		}
+
+		allErrs = append(allErrs, failureDomainsAreValid(mp.FailureDomains, fldPath.Child("failureDomains"))...)
+
+		// MachinePoolTopology name should not be empty.
+		if mp.Name == "" {
+			allErrs = append(
+				allErrs,
+				field.Required(
+					fldPath.Child("name"),
+					"name must not be empty",
+				),
+			)
+			continue
+		}
+
+		if names.Has(mp.Name) {
+			allErrs = append(allErrs,
+				field.Invalid(
+					fldPath.Child("name"),
+					mp.Name,
+					fmt.Sprintf("name must be unique. MachinePool with name %q is defined more than once", mp.Name),
+				),
+			)
+		}
+		names.Insert(mp.Name)
+	}
+	return allErrs
+}
+
+// failureDomainsAreValid checks that a list of failure domains does not contain empty or duplicated entries.
+func failureDomainsAreValid(failureDomains []string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	seen := sets.Set[string]{}
+	for i, failureDomain := range failureDomains {
+		if failureDomain == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Index(i), "failure domain must not be empty"))
+			continue
+		}
+		if seen.Has(failureDomain) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i), failureDomain))
+		}
+		seen.Insert(failureDomain)
+	}
+	return allErrs
+}
+
 // ClusterClassReferencesAreValid checks that each template reference in the ClusterClass is valid .
 func ClusterClassReferencesAreValid(clusterClass *clusterv1.ClusterClass) field.ErrorList {
 	var allErrs field.ErrorList
 
-	allErrs = append(allErrs, LocalObjectTemplateIsValid(&clusterClass.Spec.Infrastructure, clusterClass.Namespace,
-		field.NewPath("spec", "infrastructure"))...)
+	// NOTE: The InfrastructureClusterTemplate is optional; if not set, the infrastructure of the Clusters is provided by the control plane.
+	if clusterClass.Spec.Infrastructure.Ref != nil {
+		allErrs = append(allErrs, LocalObjectTemplateIsValid(&clusterClass.Spec.Infrastructure, clusterClass.Namespace,
+			field.NewPath("spec", "infrastructure"))...)
+	}
 	allErrs = append(allErrs, LocalObjectTemplateIsValid(&clusterClass.Spec.ControlPlane.LocalObjectTemplate, clusterClass.Namespace,
 		field.NewPath("spec", "controlPlane"))...)
 	if clusterClass.Spec.ControlPlane.MachineInfrastructure != nil {
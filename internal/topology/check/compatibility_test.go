@@ -483,6 +483,46 @@ func TestClusterClassesAreCompatible(t *testing.T) {
 				Build(),
 			wantErr: false,
 		},
+		{
+			name: "pass for compatible clusterClasses without infrastructure cluster template",
+			current: builder.ClusterClass(metav1.NamespaceDefault, "class1").
+				WithControlPlaneTemplate(
+					refToUnstructured(ref)).
+				Build(),
+			desired: builder.ClusterClass(metav1.NamespaceDefault, "class1").
+				WithControlPlaneTemplate(
+					refToUnstructured(compatibleRef)).
+				Build(),
+			wantErr: false,
+		},
+		{
+			name: "error if infrastructure cluster template is added",
+			current: builder.ClusterClass(metav1.NamespaceDefault, "class1").
+				WithControlPlaneTemplate(
+					refToUnstructured(ref)).
+				Build(),
+			desired: builder.ClusterClass(metav1.NamespaceDefault, "class1").
+				WithInfrastructureClusterTemplate(
+					builder.InfrastructureClusterTemplate(metav1.NamespaceDefault, "infra1").Build()).
+				WithControlPlaneTemplate(
+					refToUnstructured(ref)).
+				Build(),
+			wantErr: true,
+		},
+		{
+			name: "error if infrastructure cluster template is removed",
+			current: builder.ClusterClass(metav1.NamespaceDefault, "class1").
+				WithInfrastructureClusterTemplate(
+					builder.InfrastructureClusterTemplate(metav1.NamespaceDefault, "infra1").Build()).
+				WithControlPlaneTemplate(
+					refToUnstructured(ref)).
+				Build(),
+			desired: builder.ClusterClass(metav1.NamespaceDefault, "class1").
+				WithControlPlaneTemplate(
+					refToUnstructured(ref)).
+				Build(),
+			wantErr: true,
+		},
 		{
 			name: "error if clusterClass has incompatible ControlPlane ref",
 			current: builder.ClusterClass(metav1.NamespaceDefault, "class1").
@@ -1063,6 +1103,131 @@ func TestMachineDeploymentTopologiesAreUniqueAndDefinedInClusterClass(t *testing
 	}
 }
 
+func TestMachinePoolClassesAreUnique(t *testing.T) {
+	tests := []struct {
+		name    string
+		classes []clusterv1.MachinePoolClass
+		wantErr bool
+	}{
+		{
+			name: "pass if MachinePoolClasses are unique and have valid failure domains",
+			classes: []clusterv1.MachinePoolClass{
+				{Class: "aa", FailureDomains: []string{"fd1", "fd2"}},
+				{Class: "bb"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "fail if MachinePoolClasses are duplicated",
+			classes: []clusterv1.MachinePoolClass{
+				{Class: "aa"},
+				{Class: "aa"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "fail if a MachinePoolClass has duplicated failure domains",
+			classes: []clusterv1.MachinePoolClass{
+				{Class: "aa", FailureDomains: []string{"fd1", "fd1"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "fail if a MachinePoolClass has an empty failure domain",
+			classes: []clusterv1.MachinePoolClass{
+				{Class: "aa", FailureDomains: []string{""}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			clusterClass := builder.ClusterClass(metav1.NamespaceDefault, "class1").Build()
+			clusterClass.Spec.Workers.MachinePools = tt.classes
+			allErrs := MachinePoolClassesAreUnique(clusterClass)
+			if tt.wantErr {
+				g.Expect(allErrs).ToNot(BeEmpty())
+				return
+			}
+			g.Expect(allErrs).To(BeEmpty())
+		})
+	}
+}
+
+func TestMachinePoolTopologiesAreValidAndDefinedInClusterClass(t *testing.T) {
+	tests := []struct {
+		name     string
+		topology []clusterv1.MachinePoolTopology
+		wantErr  bool
+	}{
+		{
+			name: "pass if MachinePoolTopologies are unique, defined in ClusterClass and have valid failure domains",
+			topology: []clusterv1.MachinePoolTopology{
+				{Name: "mp1", Class: "aa", FailureDomains: []string{"fd1", "fd2"}},
+				{Name: "mp2", Class: "aa"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "fail if MachinePoolTopology name is empty",
+			topology: []clusterv1.MachinePoolTopology{
+				{Name: "", Class: "aa"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "fail if MachinePoolTopologies are duplicated",
+			topology: []clusterv1.MachinePoolTopology{
+				{Name: "mp1", Class: "aa"},
+				{Name: "mp1", Class: "aa"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "fail if MachinePoolClass is not defined in ClusterClass",
+			topology: []clusterv1.MachinePoolTopology{
+				{Name: "mp1", Class: "bb"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "fail if a MachinePoolTopology has duplicated failure domains",
+			topology: []clusterv1.MachinePoolTopology{
+				{Name: "mp1", Class: "aa", FailureDomains: []string{"fd1", "fd2", "fd1"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "fail if a MachinePoolTopology has an empty failure domain",
+			topology: []clusterv1.MachinePoolTopology{
+				{Name: "mp1", Class: "aa", FailureDomains: []string{""}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			clusterClass := builder.ClusterClass(metav1.NamespaceDefault, "class1").Build()
+			clusterClass.Spec.Workers.MachinePools = []clusterv1.MachinePoolClass{{Class: "aa"}}
+			cluster := builder.Cluster(metav1.NamespaceDefault, "cluster1").
+				WithTopology(builder.ClusterTopology().
+					WithClass("class1").
+					WithVersion("v1.22.2").
+					Build()).
+				Build()
+			cluster.Spec.Topology.Workers = &clusterv1.WorkersTopology{MachinePools: tt.topology}
+			allErrs := MachinePoolTopologiesAreValidAndDefinedInClusterClass(cluster, clusterClass)
+			if tt.wantErr {
+				g.Expect(allErrs).ToNot(BeEmpty())
+				return
+			}
+			g.Expect(allErrs).To(BeEmpty())
+		})
+	}
+}
+
 func TestClusterClassReferencesAreValid(t *testing.T) {
 	ref := &corev1.ObjectReference{
 		APIVersion: "group.test.io/foo",
@@ -1101,6 +1266,14 @@ func TestClusterClassReferencesAreValid(t *testing.T) {
 				Build(),
 			wantErr: false,
 		},
+		{
+			name: "pass for clusterClass without infrastructure cluster template",
+			clusterClass: builder.ClusterClass(metav1.NamespaceDefault, "class1").
+				WithControlPlaneTemplate(
+					refToUnstructured(ref)).
+				Build(),
+			wantErr: false,
+		},
 		{
 			name: "error if clusterClass has multiple invalid refs",
 			clusterClass: builder.ClusterClass(metav1.NamespaceDefault, "class1").
@@ -0,0 +1,212 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package names implements name generators for the objects created by the topology and the
+// MachineDeployment and MachineSet controllers, supporting the naming strategies defined in the API.
+package names
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const (
+	maxNameLength          = 63
+	randomLength           = 5
+	maxGeneratedNameLength = maxNameLength - randomLength
+)
+
+// NameGenerator generates names for objects.
+type NameGenerator interface {
+	// GenerateName generates a new name.
+	GenerateName() (string, error)
+}
+
+// SimpleNameGenerator returns a NameGenerator which generates names by appending a random suffix
+// to the given base, like the SimpleNameGenerator in k8s.io/apiserver/pkg/storage/names.
+func SimpleNameGenerator(base string) NameGenerator {
+	return &simpleNameGenerator{base: base}
+}
+
+// ControlPlaneNameGenerator returns a NameGenerator for the ControlPlane object of a Cluster.
+func ControlPlaneNameGenerator(strategy *clusterv1.ControlPlaneClassNamingStrategy, clusterName string) NameGenerator {
+	if strategy == nil || strategy.Template == nil {
+		return SimpleNameGenerator(fmt.Sprintf("%s-", clusterName))
+	}
+	return newTemplateGenerator(*strategy.Template, clusterName, nil)
+}
+
+// ControlPlaneInfrastructureMachineTemplateNameGenerator returns a NameGenerator for the InfrastructureMachineTemplates
+// of the ControlPlane object of a Cluster.
+func ControlPlaneInfrastructureMachineTemplateNameGenerator(strategy *clusterv1.ControlPlaneClassNamingStrategy, clusterName string) NameGenerator {
+	if strategy == nil || strategy.Template == nil {
+		return SimpleNameGenerator(fmt.Sprintf("%s-control-plane-", clusterName))
+	}
+	return newTemplateGenerator(*strategy.Template, clusterName, nil)
+}
+
+// MachineDeploymentNameGenerator returns a NameGenerator for a MachineDeployment of a Cluster.
+func MachineDeploymentNameGenerator(strategy *clusterv1.MachineDeploymentClassNamingStrategy, clusterName, topologyName string) NameGenerator {
+	if strategy == nil || strategy.Template == nil {
+		return SimpleNameGenerator(fmt.Sprintf("%s-%s-", clusterName, topologyName))
+	}
+	return newMachineDeploymentTemplateGenerator(*strategy.Template, clusterName, topologyName)
+}
+
+// BootstrapTemplateNameGenerator returns a NameGenerator for the BootstrapTemplates of a MachineDeployment of a Cluster.
+func BootstrapTemplateNameGenerator(strategy *clusterv1.MachineDeploymentClassNamingStrategy, clusterName, topologyName string) NameGenerator {
+	if strategy == nil || strategy.Template == nil {
+		return SimpleNameGenerator(fmt.Sprintf("%s-%s-bootstrap-", clusterName, topologyName))
+	}
+	return newMachineDeploymentTemplateGenerator(*strategy.Template, clusterName, topologyName)
+}
+
+// InfrastructureMachineTemplateNameGenerator returns a NameGenerator for the InfrastructureMachineTemplates
+// of a MachineDeployment of a Cluster.
+func InfrastructureMachineTemplateNameGenerator(strategy *clusterv1.MachineDeploymentClassNamingStrategy, clusterName, topologyName string) NameGenerator {
+	if strategy == nil || strategy.Template == nil {
+		return SimpleNameGenerator(fmt.Sprintf("%s-%s-infra-", clusterName, topologyName))
+	}
+	return newMachineDeploymentTemplateGenerator(*strategy.Template, clusterName, topologyName)
+}
+
+// MachineSetNameGenerator returns a NameGenerator for a MachineSet of a MachineDeployment; differently from
+// the other generators, the random suffix is provided by the caller, because it is used as a unique identifier
+// of the MachineSet.
+func MachineSetNameGenerator(strategy *clusterv1.MachineSetNamingStrategy, clusterName, machineDeploymentName, random string) NameGenerator {
+	if strategy == nil || strategy.Template == nil {
+		return &simpleNameGenerator{base: fmt.Sprintf("%s-", machineDeploymentName), random: random}
+	}
+	return &templateGenerator{
+		template: *strategy.Template,
+		data: map[string]interface{}{
+			"cluster": map[string]interface{}{
+				"name": clusterName,
+			},
+			"machineDeployment": map[string]interface{}{
+				"name": machineDeploymentName,
+			},
+		},
+		random: random,
+	}
+}
+
+// MachineNameGenerator returns a NameGenerator for a Machine of a MachineSet.
+func MachineNameGenerator(strategy *clusterv1.MachineNamingStrategy, clusterName, machineSetName string) NameGenerator {
+	if strategy == nil || strategy.Template == nil {
+		return SimpleNameGenerator(fmt.Sprintf("%s-", machineSetName))
+	}
+	return &templateGenerator{
+		template: *strategy.Template,
+		data: map[string]interface{}{
+			"cluster": map[string]interface{}{
+				"name": clusterName,
+			},
+			"machineSet": map[string]interface{}{
+				"name": machineSetName,
+			},
+		},
+	}
+}
+
+type simpleNameGenerator struct {
+	base string
+	// random is the suffix appended to base; if empty, a random string is used.
+	random string
+}
+
+func (g *simpleNameGenerator) GenerateName() (string, error) {
+	base := g.base
+	if len(base) > maxGeneratedNameLength {
+		base = base[:maxGeneratedNameLength]
+	}
+	random := g.random
+	if random == "" {
+		random = utilrand.String(randomLength)
+	}
+	return fmt.Sprintf("%s%s", base, random), nil
+}
+
+func newTemplateGenerator(templateString, clusterName string, data map[string]interface{}) NameGenerator {
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	data["cluster"] = map[string]interface{}{
+		"name": clusterName,
+	}
+	return &templateGenerator{
+		template: templateString,
+		data:     data,
+	}
+}
+
+func newMachineDeploymentTemplateGenerator(templateString, clusterName, topologyName string) NameGenerator {
+	return newTemplateGenerator(templateString, clusterName, map[string]interface{}{
+		"machineDeployment": map[string]interface{}{
+			"topologyName": topologyName,
+		},
+	})
+}
+
+// templateGenerator generates names by rendering a Go template.
+type templateGenerator struct {
+	template string
+	data     map[string]interface{}
+	// random is the value of the .random argument; if empty, a random string is used.
+	random string
+}
+
+func (g *templateGenerator) GenerateName() (string, error) {
+	tpl, err := template.New("name generator").Option("missingkey=error").Parse(g.template)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse template %q", g.template)
+	}
+
+	random := g.random
+	if random == "" {
+		random = utilrand.String(randomLength)
+	}
+	data := map[string]interface{}{
+		"random": random,
+	}
+	for k, v := range g.data {
+		data[k] = v
+	}
+
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, data); err != nil {
+		return "", errors.Wrapf(err, "failed to render template %q", g.template)
+	}
+
+	// If the name exceeds the max length, trim it and append the random suffix, so the name is still unique.
+	name := buf.String()
+	if len(name) > maxNameLength {
+		name = name[:maxNameLength-len(random)] + random
+	}
+
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return "", errors.Errorf("failed to generate name from template %q: %q is not a valid name: %s", g.template, name, strings.Join(errs, ", "))
+	}
+	return name, nil
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package names
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/pointer"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestNameGenerators(t *testing.T) {
+	tests := []struct {
+		name      string
+		generator NameGenerator
+		want      string
+		wantErr   bool
+	}{
+		{
+			name:      "ControlPlane without naming strategy",
+			generator: ControlPlaneNameGenerator(nil, "cluster"),
+			want:      "cluster-[a-z0-9]{5}",
+		},
+		{
+			name:      "ControlPlane with naming strategy",
+			generator: ControlPlaneNameGenerator(&clusterv1.ControlPlaneClassNamingStrategy{Template: pointer.String("{{ .cluster.name }}-cp-{{ .random }}")}, "cluster"),
+			want:      "cluster-cp-[a-z0-9]{5}",
+		},
+		{
+			name:      "ControlPlane InfrastructureMachineTemplate without naming strategy",
+			generator: ControlPlaneInfrastructureMachineTemplateNameGenerator(nil, "cluster"),
+			want:      "cluster-control-plane-[a-z0-9]{5}",
+		},
+		{
+			name:      "ControlPlane InfrastructureMachineTemplate with naming strategy",
+			generator: ControlPlaneInfrastructureMachineTemplateNameGenerator(&clusterv1.ControlPlaneClassNamingStrategy{Template: pointer.String("{{ .cluster.name }}-cp-{{ .random }}")}, "cluster"),
+			want:      "cluster-cp-[a-z0-9]{5}",
+		},
+		{
+			name:      "MachineDeployment without naming strategy",
+			generator: MachineDeploymentNameGenerator(nil, "cluster", "md"),
+			want:      "cluster-md-[a-z0-9]{5}",
+		},
+		{
+			name:      "MachineDeployment with naming strategy",
+			generator: MachineDeploymentNameGenerator(&clusterv1.MachineDeploymentClassNamingStrategy{Template: pointer.String("{{ .machineDeployment.topologyName }}-{{ .cluster.name }}-{{ .random }}")}, "cluster", "md"),
+			want:      "md-cluster-[a-z0-9]{5}",
+		},
+		{
+			name:      "BootstrapTemplate without naming strategy",
+			generator: BootstrapTemplateNameGenerator(nil, "cluster", "md"),
+			want:      "cluster-md-bootstrap-[a-z0-9]{5}",
+		},
+		{
+			name:      "InfrastructureMachineTemplate without naming strategy",
+			generator: InfrastructureMachineTemplateNameGenerator(nil, "cluster", "md"),
+			want:      "cluster-md-infra-[a-z0-9]{5}",
+		},
+		{
+			name:      "InfrastructureMachineTemplate with naming strategy",
+			generator: InfrastructureMachineTemplateNameGenerator(&clusterv1.MachineDeploymentClassNamingStrategy{Template: pointer.String("{{ .cluster.name }}-{{ .machineDeployment.topologyName }}-{{ .random }}")}, "cluster", "md"),
+			want:      "cluster-md-[a-z0-9]{5}",
+		},
+		{
+			name:      "MachineSet without naming strategy",
+			generator: MachineSetNameGenerator(nil, "cluster", "md", "abcdefgh"),
+			want:      "md-abcdefgh",
+		},
+		{
+			name:      "MachineSet with naming strategy",
+			generator: MachineSetNameGenerator(&clusterv1.MachineSetNamingStrategy{Template: pointer.String("{{ .cluster.name }}-{{ .machineDeployment.name }}-{{ .random }}")}, "cluster", "md", "abcdefgh"),
+			want:      "cluster-md-abcdefgh",
+		},
+		{
+			name:      "Machine without naming strategy",
+			generator: MachineNameGenerator(nil, "cluster", "ms"),
+			want:      "ms-[a-z0-9]{5}",
+		},
+		{
+			name:      "Machine with naming strategy",
+			generator: MachineNameGenerator(&clusterv1.MachineNamingStrategy{Template: pointer.String("{{ .cluster.name }}-worker-{{ .random }}")}, "cluster", "ms"),
+			want:      "cluster-worker-[a-z0-9]{5}",
+		},
+		{
+			name:      "Trims long names and keeps the random suffix",
+			generator: MachineNameGenerator(&clusterv1.MachineNamingStrategy{Template: pointer.String("{{ .machineSet.name }}-{{ .random }}")}, "cluster", strings.Repeat("a", 70)),
+			want:      fmt.Sprintf("%s[a-z0-9]{5}", strings.Repeat("a", 58)),
+		},
+		{
+			name:      "Fails with an undefined argument",
+			generator: ControlPlaneNameGenerator(&clusterv1.ControlPlaneClassNamingStrategy{Template: pointer.String("{{ .cluster.namespace }}-{{ .random }}")}, "cluster"),
+			wantErr:   true,
+		},
+		{
+			name:      "Fails with an invalid template",
+			generator: ControlPlaneNameGenerator(&clusterv1.ControlPlaneClassNamingStrategy{Template: pointer.String("{{ .cluster.name ")}, "cluster"),
+			wantErr:   true,
+		},
+		{
+			name:      "Fails if the generated name is invalid",
+			generator: ControlPlaneNameGenerator(&clusterv1.ControlPlaneClassNamingStrategy{Template: pointer.String("{{ .cluster.name }}_{{ .random }}")}, "cluster"),
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := tt.generator.GenerateName()
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(MatchRegexp("^" + tt.want + "$"))
+		})
+	}
+}
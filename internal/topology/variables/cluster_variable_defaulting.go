@@ -108,6 +108,13 @@ func getCurrentValue(variable clusterv1.ClusterVariable, valuesMap map[string]ma
 
 // defaultValue defaults a clusterVariable based on the default value in the clusterClassVariable.
 func defaultValue(currentValue *clusterv1.ClusterVariable, definition *statusVariableDefinition, fldPath *field.Path, createVariable bool) (*clusterv1.ClusterVariable, field.ErrorList) {
+	// Values from ConfigMaps or Secrets are not defaulted, because they are resolved by the topology controller.
+	if currentValue != nil && currentValue.ValueFrom != nil {
+		v := currentValue.DeepCopy()
+		v.DefinitionFrom = definition.From
+		return v, nil
+	}
+
 	if currentValue == nil {
 		// Return if the variable does not exist yet and createVariable is false.
 		if !createVariable {
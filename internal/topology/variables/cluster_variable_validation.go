@@ -116,6 +116,12 @@ func validateRequiredVariables(values map[string]map[string]clusterv1.ClusterVar
 
 // ValidateClusterVariable validates a clusterVariable.
 func ValidateClusterVariable(value *clusterv1.ClusterVariable, definition *clusterv1.ClusterClassVariable, fldPath *field.Path) field.ErrorList {
+	// Values from ConfigMaps or Secrets are resolved by the topology controller, which validates
+	// them against the schema; here we only validate the source.
+	if value.ValueFrom != nil {
+		return validateClusterVariableValueSource(value, fldPath)
+	}
+
 	// Parse JSON value.
 	var variableValue interface{}
 	// Only try to unmarshal the clusterVariable if it is not nil, otherwise the variableValue is nil.
@@ -152,6 +158,42 @@ func ValidateClusterVariable(value *clusterv1.ClusterVariable, definition *clust
 	return validateUnknownFields(fldPath, value, variableValue, apiExtensionsSchema)
 }
 
+// validateClusterVariableValueSource validates the valueFrom of a clusterVariable.
+func validateClusterVariableValueSource(value *clusterv1.ClusterVariable, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if len(value.Value.Raw) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("value"),
+			fmt.Sprintf("variable %q cannot set both value and valueFrom", value.Name)))
+	}
+
+	source := value.ValueFrom
+	switch {
+	case source.ConfigMapKeyRef != nil && source.SecretKeyRef != nil:
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("valueFrom"),
+			fmt.Sprintf("variable %q cannot set both configMapKeyRef and secretKeyRef", value.Name)))
+	case source.ConfigMapKeyRef != nil:
+		allErrs = append(allErrs, validateKeyRef(source.ConfigMapKeyRef.Name, source.ConfigMapKeyRef.Key, fldPath.Child("valueFrom", "configMapKeyRef"))...)
+	case source.SecretKeyRef != nil:
+		allErrs = append(allErrs, validateKeyRef(source.SecretKeyRef.Name, source.SecretKeyRef.Key, fldPath.Child("valueFrom", "secretKeyRef"))...)
+	default:
+		allErrs = append(allErrs, field.Required(fldPath.Child("valueFrom"),
+			fmt.Sprintf("variable %q must set one of configMapKeyRef or secretKeyRef", value.Name)))
+	}
+	return allErrs
+}
+
+func validateKeyRef(name, key string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if name == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("name"), "name must be set"))
+	}
+	if key == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("key"), "key must be set"))
+	}
+	return allErrs
+}
+
 // validateUnknownFields validates the given variableValue for unknown fields.
 // This func returns an error if there are variable fields in variableValue that are not defined in
 // variableSchema and if x-kubernetes-preserve-unknown-fields is not set.
@@ -0,0 +1,235 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package maintenance implements helpers to evaluate the maintenance windows of Clusters, which define when
+// disruptive operations like rollouts and remediation are allowed.
+package maintenance
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/annotations"
+)
+
+// searchYears is how far in the future the next opening of a maintenance window is searched.
+const searchYears = 5
+
+// field defines the range of values of a field of a schedule.
+type field struct {
+	name     string
+	min, max int
+}
+
+var (
+	minuteField     = field{name: "minute", min: 0, max: 59}
+	hourField       = field{name: "hour", min: 0, max: 23}
+	dayOfMonthField = field{name: "day of month", min: 1, max: 31}
+	monthField      = field{name: "month", min: 1, max: 12}
+	dayOfWeekField  = field{name: "day of week", min: 0, max: 6}
+)
+
+// schedule is a parsed cron expression; each field is a bitset of the matching values.
+type schedule struct {
+	minutes, hours, daysOfMonth, months, daysOfWeek uint64
+
+	// anyDayOfMonth and anyDayOfWeek are true if the respective field is '*'; they are used to implement the cron
+	// semantic for which a day matches either of the day fields, if both are restricted.
+	anyDayOfMonth, anyDayOfWeek bool
+}
+
+// ValidateSchedule returns an error if the schedule is not a valid cron expression, or if it never matches.
+func ValidateSchedule(expr string) error {
+	s, err := parseSchedule(expr)
+	if err != nil {
+		return err
+	}
+	if _, ok := s.next(time.Now()); !ok {
+		return errors.Errorf("schedule %q never matches", expr)
+	}
+	return nil
+}
+
+// IsAllowed returns true if disruptive operations are allowed for the Cluster at the given time, i.e. if the Cluster
+// has no maintenance windows, if it has the ignore-maintenance-windows annotation, or if one of its maintenance
+// windows is open. Otherwise, it returns the time until the next maintenance window opens.
+func IsAllowed(cluster *clusterv1.Cluster, now time.Time) (bool, time.Duration, error) {
+	if len(cluster.Spec.MaintenanceWindows) == 0 || annotations.HasIgnoreMaintenanceWindows(cluster) {
+		return true, 0, nil
+	}
+
+	now = now.UTC()
+	var nextOpening time.Time
+	for _, window := range cluster.Spec.MaintenanceWindows {
+		s, err := parseSchedule(window.Schedule)
+		if err != nil {
+			return false, 0, err
+		}
+
+		// A window containing now opened in (now - duration, now].
+		start, ok := s.next(now.Add(-window.Duration.Duration))
+		if !ok {
+			continue
+		}
+		if !start.After(now) {
+			return true, 0, nil
+		}
+
+		if nextOpening.IsZero() || start.Before(nextOpening) {
+			nextOpening = start
+		}
+	}
+
+	if nextOpening.IsZero() {
+		return false, 0, errors.New("none of the maintenance windows of the Cluster will open")
+	}
+	return false, nextOpening.Sub(now), nil
+}
+
+// parseSchedule parses a cron expression with five fields: minute, hour, day of month, month and day of week.
+func parseSchedule(expr string) (*schedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, errors.Errorf("invalid schedule %q: expected 5 fields, got %d", expr, len(fields))
+	}
+
+	s := &schedule{
+		anyDayOfMonth: fields[2] == "*",
+		anyDayOfWeek:  fields[4] == "*",
+	}
+	for i, f := range []struct {
+		field field
+		bits  *uint64
+	}{
+		{minuteField, &s.minutes},
+		{hourField, &s.hours},
+		{dayOfMonthField, &s.daysOfMonth},
+		{monthField, &s.months},
+		{dayOfWeekField, &s.daysOfWeek},
+	} {
+		bits, err := parseField(fields[i], f.field)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid schedule %q", expr)
+		}
+		*f.bits = bits
+	}
+	return s, nil
+}
+
+// parseField parses a field of a cron expression, i.e. a comma separated list of '*', values or ranges,
+// each optionally followed by a step, into a bitset of the matching values.
+func parseField(expr string, f field) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(expr, ",") {
+		rangeExpr, stepExpr, hasStep := strings.Cut(item, "/")
+
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepExpr); err != nil || step <= 0 {
+				return 0, errors.Errorf("invalid step %q in %s field", stepExpr, f.name)
+			}
+		}
+
+		var start, end int
+		switch {
+		case rangeExpr == "*":
+			start, end = f.min, f.max
+		case strings.Contains(rangeExpr, "-"):
+			startExpr, endExpr, _ := strings.Cut(rangeExpr, "-")
+			var err error
+			if start, err = parseValue(startExpr, f); err != nil {
+				return 0, err
+			}
+			if end, err = parseValue(endExpr, f); err != nil {
+				return 0, err
+			}
+			if start > end {
+				return 0, errors.Errorf("invalid range %q in %s field", rangeExpr, f.name)
+			}
+		default:
+			var err error
+			if start, err = parseValue(rangeExpr, f); err != nil {
+				return 0, err
+			}
+			end = start
+			if hasStep {
+				end = f.max
+			}
+		}
+
+		for v := start; v <= end; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// parseValue parses a value of a field of a cron expression.
+func parseValue(expr string, f field) (int, error) {
+	v, err := strconv.Atoi(expr)
+	if err != nil || v < f.min || v > f.max {
+		return 0, errors.Errorf("invalid value %q in %s field: must be between %d and %d", expr, f.name, f.min, f.max)
+	}
+	return v, nil
+}
+
+// next returns the first time strictly after t matching the schedule, with a granularity of one minute.
+// It returns false if the schedule doesn't match in the next years.
+func (s *schedule) next(t time.Time) (time.Time, bool) {
+	t = t.UTC().Truncate(time.Minute).Add(time.Minute)
+
+	limit := t.Year() + searchYears
+	for t.Year() <= limit {
+		if !has(s.months, int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !s.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !has(s.hours, t.Hour()) {
+			t = t.Truncate(time.Hour).Add(time.Hour)
+			continue
+		}
+		if !has(s.minutes, t.Minute()) {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// matchesDay returns true if the day of t matches the schedule; if both the day of month and the day of week
+// are restricted, a day matching either of them matches the schedule.
+func (s *schedule) matchesDay(t time.Time) bool {
+	domMatch := has(s.daysOfMonth, t.Day())
+	dowMatch := has(s.daysOfWeek, int(t.Weekday()))
+	if s.anyDayOfMonth || s.anyDayOfWeek {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+func has(bits uint64, v int) bool {
+	return bits&(1<<uint(v)) != 0
+}
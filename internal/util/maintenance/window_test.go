@@ -0,0 +1,238 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenance
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestValidateSchedule(t *testing.T) {
+	tests := []struct {
+		schedule  string
+		expectErr bool
+	}{
+		{schedule: "* * * * *"},
+		{schedule: "0 22 * * 1-5"},
+		{schedule: "*/15 0,12 1-7 */2 0"},
+		{schedule: "30 2 29 2 *"},
+		{schedule: "", expectErr: true},
+		{schedule: "0 22 * *", expectErr: true},
+		{schedule: "60 * * * *", expectErr: true},
+		{schedule: "* 24 * * *", expectErr: true},
+		{schedule: "* * 0 * *", expectErr: true},
+		{schedule: "* * * 13 *", expectErr: true},
+		{schedule: "* * * * 7", expectErr: true},
+		{schedule: "5-1 * * * *", expectErr: true},
+		{schedule: "*/0 * * * *", expectErr: true},
+		{schedule: "a * * * *", expectErr: true},
+		{schedule: "0 0 30 2 *", expectErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.schedule, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := ValidateSchedule(tt.schedule)
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+		})
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	// Monday, 2 October 2023.
+	monday := time.Date(2023, time.October, 2, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		schedule string
+		from     time.Time
+		expected time.Time
+	}{
+		{
+			name:     "every minute",
+			schedule: "* * * * *",
+			from:     monday,
+			expected: monday.Add(time.Minute),
+		},
+		{
+			name:     "seconds are ignored",
+			schedule: "* * * * *",
+			from:     monday.Add(30 * time.Second),
+			expected: monday.Add(time.Minute),
+		},
+		{
+			name:     "later the same day",
+			schedule: "0 22 * * *",
+			from:     monday,
+			expected: time.Date(2023, time.October, 2, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "the next day",
+			schedule: "0 8 * * *",
+			from:     monday,
+			expected: time.Date(2023, time.October, 3, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "on the weekend",
+			schedule: "0 0 * * 0,6",
+			from:     monday,
+			expected: time.Date(2023, time.October, 7, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "with steps",
+			schedule: "*/20 */6 * * *",
+			from:     monday,
+			expected: time.Date(2023, time.October, 2, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "day of month or day of week",
+			schedule: "0 0 15 * 5",
+			from:     monday,
+			expected: time.Date(2023, time.October, 6, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "next year",
+			schedule: "0 0 1 1 *",
+			from:     monday,
+			expected: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "leap day",
+			schedule: "0 0 29 2 *",
+			from:     monday,
+			expected: time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "non UTC times",
+			schedule: "0 22 * * *",
+			from:     monday.In(time.FixedZone("UTC+2", 2*60*60)),
+			expected: time.Date(2023, time.October, 2, 22, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			s, err := parseSchedule(tt.schedule)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			next, ok := s.next(tt.from)
+			g.Expect(ok).To(BeTrue())
+			g.Expect(next).To(Equal(tt.expected))
+		})
+	}
+}
+
+func TestIsAllowed(t *testing.T) {
+	// Monday, 2 October 2023, 10:30 UTC.
+	now := time.Date(2023, time.October, 2, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name                 string
+		windows              []clusterv1.MaintenanceWindow
+		annotations          map[string]string
+		expectAllowed        bool
+		expectedRequeueAfter time.Duration
+		expectErr            bool
+	}{
+		{
+			name:          "no maintenance windows",
+			expectAllowed: true,
+		},
+		{
+			name: "within a maintenance window",
+			windows: []clusterv1.MaintenanceWindow{
+				{Schedule: "0 10 * * *", Duration: metav1.Duration{Duration: time.Hour}},
+			},
+			expectAllowed: true,
+		},
+		{
+			name: "within a maintenance window which opened the day before",
+			windows: []clusterv1.MaintenanceWindow{
+				{Schedule: "0 22 * * *", Duration: metav1.Duration{Duration: 13 * time.Hour}},
+			},
+			expectAllowed: true,
+		},
+		{
+			name: "maintenance window opening now",
+			windows: []clusterv1.MaintenanceWindow{
+				{Schedule: "30 10 * * *", Duration: metav1.Duration{Duration: time.Minute}},
+			},
+			expectAllowed: true,
+		},
+		{
+			name: "maintenance window closing now",
+			windows: []clusterv1.MaintenanceWindow{
+				{Schedule: "0 10 * * *", Duration: metav1.Duration{Duration: 30 * time.Minute}},
+			},
+			expectAllowed:        false,
+			expectedRequeueAfter: 23*time.Hour + 30*time.Minute,
+		},
+		{
+			name: "outside of the maintenance windows",
+			windows: []clusterv1.MaintenanceWindow{
+				{Schedule: "0 22 * * *", Duration: metav1.Duration{Duration: 2 * time.Hour}},
+				{Schedule: "0 12 * * 1", Duration: metav1.Duration{Duration: time.Hour}},
+			},
+			expectAllowed:        false,
+			expectedRequeueAfter: 90 * time.Minute,
+		},
+		{
+			name: "outside of the maintenance windows with the ignore annotation",
+			windows: []clusterv1.MaintenanceWindow{
+				{Schedule: "0 22 * * *", Duration: metav1.Duration{Duration: 2 * time.Hour}},
+			},
+			annotations:   map[string]string{clusterv1.IgnoreMaintenanceWindowsAnnotation: ""},
+			expectAllowed: true,
+		},
+		{
+			name: "invalid schedule",
+			windows: []clusterv1.MaintenanceWindow{
+				{Schedule: "0 22 * *", Duration: metav1.Duration{Duration: 2 * time.Hour}},
+			},
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations},
+				Spec:       clusterv1.ClusterSpec{MaintenanceWindows: tt.windows},
+			}
+
+			allowed, requeueAfter, err := IsAllowed(cluster, now)
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(allowed).To(Equal(tt.expectAllowed))
+			g.Expect(requeueAfter).To(Equal(tt.expectedRequeueAfter))
+		})
+	}
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package rollout implements helpers to report the progress of rollouts of MachineDeployments and control planes.
+package rollout
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func init() {
+	// Register the metrics at the controller-runtime metrics registry.
+	ctrlmetrics.Registry.MustRegister(upToDateReplicas)
+	ctrlmetrics.Registry.MustRegister(desiredReplicas)
+	ctrlmetrics.Registry.MustRegister(startTimestamp)
+	ctrlmetrics.Registry.MustRegister(completionTimestamp)
+}
+
+// Metrics subsystem and labels of the rollout metrics.
+const rolloutSubsystem = "capi_rollout"
+
+var labelNames = []string{"kind", "namespace", "name", "cluster"}
+
+var (
+	// upToDateReplicas reports the number of replicas up-to-date with the desired spec.
+	upToDateReplicas = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: rolloutSubsystem,
+		Name:      "up_to_date_replicas",
+		Help:      "Number of replicas up-to-date with the desired spec, broken down by kind, name and cluster.",
+	}, labelNames)
+
+	// desiredReplicas reports the number of desired replicas.
+	desiredReplicas = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: rolloutSubsystem,
+		Name:      "desired_replicas",
+		Help:      "Number of desired replicas, broken down by kind, name and cluster.",
+	}, labelNames)
+
+	// startTimestamp reports the start time of the last rollout.
+	startTimestamp = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: rolloutSubsystem,
+		Name:      "start_timestamp_seconds",
+		Help:      "Start time of the last rollout in seconds since the Unix epoch, broken down by kind, name and cluster.",
+	}, labelNames)
+
+	// completionTimestamp reports the completion time of the last rollout.
+	completionTimestamp = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: rolloutSubsystem,
+		Name:      "completion_timestamp_seconds",
+		Help:      "Completion time of the last rollout in seconds since the Unix epoch, broken down by kind, name and cluster.",
+	}, labelNames)
+)
+
+// Progress is the progress of a rollout.
+type Progress struct {
+	// Replicas is the number of current replicas, including the replicas that are not up-to-date.
+	Replicas int32
+
+	// UpToDateReplicas is the number of replicas up-to-date with the desired spec.
+	UpToDateReplicas int32
+
+	// DesiredReplicas is the number of desired replicas.
+	DesiredReplicas int32
+}
+
+// InProgress returns true if there are replicas that are not up-to-date with the desired spec.
+func (p Progress) InProgress() bool {
+	return p.Replicas > p.UpToDateReplicas
+}
+
+// Percentage returns the percentage of the desired replicas that are up-to-date with the desired spec.
+func (p Progress) Percentage() int32 {
+	if p.DesiredReplicas <= 0 || p.UpToDateReplicas >= p.DesiredReplicas {
+		return 100
+	}
+	return p.UpToDateReplicas * 100 / p.DesiredReplicas
+}
+
+// SetCondition sets the RolloutInProgress condition on the object according to the progress of the rollout, and
+// reports the progress of the rollout with the rollout metrics.
+//
+// The start and completion time of the last rollout are reported using the last transition time of the condition.
+// NOTE: The message of the condition changes while the rollout progresses, which would update the last transition
+// time; the last transition time is preserved instead, so it is the start time of the rollout.
+func SetCondition(obj conditions.Setter, kind, clusterName string, progress Progress) {
+	if progress.InProgress() {
+		condition := &clusterv1.Condition{
+			Type:    clusterv1.RolloutInProgressCondition,
+			Status:  corev1.ConditionTrue,
+			Reason:  clusterv1.RollingOutReason,
+			Message: fmt.Sprintf("%d of %d replicas are up-to-date (%d%%)", progress.UpToDateReplicas, progress.DesiredReplicas, progress.Percentage()),
+		}
+		if current := conditions.Get(obj, clusterv1.RolloutInProgressCondition); current != nil &&
+			current.Status == corev1.ConditionTrue && current.Reason == clusterv1.RollingOutReason {
+			// conditions.Set keeps the given last transition time only for new conditions, so the condition is deleted first.
+			condition.LastTransitionTime = current.LastTransitionTime
+			conditions.Delete(obj, clusterv1.RolloutInProgressCondition)
+		}
+		conditions.Set(obj, condition)
+	} else {
+		conditions.MarkFalse(obj, clusterv1.RolloutInProgressCondition, clusterv1.RolloutCompletedReason, clusterv1.ConditionSeverityInfo,
+			"All %d replicas are up-to-date", progress.DesiredReplicas)
+	}
+
+	labelValues := []string{kind, obj.GetNamespace(), obj.GetName(), clusterName}
+	upToDateReplicas.WithLabelValues(labelValues...).Set(float64(progress.UpToDateReplicas))
+	desiredReplicas.WithLabelValues(labelValues...).Set(float64(progress.DesiredReplicas))
+	lastTransitionTime := float64(conditions.GetLastTransitionTime(obj, clusterv1.RolloutInProgressCondition).Unix())
+	if progress.InProgress() {
+		startTimestamp.WithLabelValues(labelValues...).Set(lastTransitionTime)
+	} else {
+		completionTimestamp.WithLabelValues(labelValues...).Set(lastTransitionTime)
+	}
+}
+
+// DeleteMetrics deletes the rollout metrics of a deleted object.
+func DeleteMetrics(kind string, key client.ObjectKey) {
+	labels := prometheus.Labels{"kind": kind, "namespace": key.Namespace, "name": key.Name}
+	upToDateReplicas.DeletePartialMatch(labels)
+	desiredReplicas.DeletePartialMatch(labels)
+	startTimestamp.DeletePartialMatch(labels)
+	completionTimestamp.DeletePartialMatch(labels)
+}
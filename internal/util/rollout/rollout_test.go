@@ -0,0 +1,107 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rollout
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestProgress(t *testing.T) {
+	tests := []struct {
+		name               string
+		progress           Progress
+		expectedInProgress bool
+		expectedPercentage int32
+	}{
+		{
+			name:               "scaling up from zero is not a rollout",
+			progress:           Progress{Replicas: 1, UpToDateReplicas: 1, DesiredReplicas: 3},
+			expectedInProgress: false,
+			expectedPercentage: 33,
+		},
+		{
+			name:               "rollout with outdated replicas",
+			progress:           Progress{Replicas: 4, UpToDateReplicas: 1, DesiredReplicas: 3},
+			expectedInProgress: true,
+			expectedPercentage: 33,
+		},
+		{
+			name:               "all replicas up-to-date",
+			progress:           Progress{Replicas: 3, UpToDateReplicas: 3, DesiredReplicas: 3},
+			expectedInProgress: false,
+			expectedPercentage: 100,
+		},
+		{
+			name:               "no desired replicas",
+			progress:           Progress{Replicas: 1, UpToDateReplicas: 0, DesiredReplicas: 0},
+			expectedInProgress: true,
+			expectedPercentage: 100,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(tt.progress.InProgress()).To(Equal(tt.expectedInProgress))
+			g.Expect(tt.progress.Percentage()).To(Equal(tt.expectedPercentage))
+		})
+	}
+}
+
+func TestSetCondition(t *testing.T) {
+	g := NewWithT(t)
+
+	md := &clusterv1.MachineDeployment{ObjectMeta: metav1.ObjectMeta{Namespace: "rollout-test", Name: "md"}}
+	labelValues := []string{"MachineDeployment", "rollout-test", "md", "cluster"}
+
+	SetCondition(md, "MachineDeployment", "cluster", Progress{Replicas: 4, UpToDateReplicas: 1, DesiredReplicas: 3})
+	g.Expect(conditions.IsTrue(md, clusterv1.RolloutInProgressCondition)).To(BeTrue())
+	g.Expect(conditions.GetMessage(md, clusterv1.RolloutInProgressCondition)).To(Equal("1 of 3 replicas are up-to-date (33%)"))
+	g.Expect(testutil.ToFloat64(upToDateReplicas.WithLabelValues(labelValues...))).To(Equal(1.0))
+	g.Expect(testutil.ToFloat64(desiredReplicas.WithLabelValues(labelValues...))).To(Equal(3.0))
+	g.Expect(testutil.ToFloat64(startTimestamp.WithLabelValues(labelValues...))).To(BeNumerically(">", 0))
+
+	// The start time of the rollout is preserved while the rollout progresses.
+	startTime := metav1.NewTime(time.Now().Add(-time.Hour).UTC().Truncate(time.Second))
+	for i := range md.Status.Conditions {
+		md.Status.Conditions[i].LastTransitionTime = startTime
+	}
+	SetCondition(md, "MachineDeployment", "cluster", Progress{Replicas: 4, UpToDateReplicas: 2, DesiredReplicas: 3})
+	g.Expect(conditions.GetMessage(md, clusterv1.RolloutInProgressCondition)).To(Equal("2 of 3 replicas are up-to-date (66%)"))
+	g.Expect(conditions.GetLastTransitionTime(md, clusterv1.RolloutInProgressCondition).Time).To(BeTemporally("==", startTime.Time))
+	g.Expect(testutil.ToFloat64(startTimestamp.WithLabelValues(labelValues...))).To(Equal(float64(startTime.Unix())))
+
+	SetCondition(md, "MachineDeployment", "cluster", Progress{Replicas: 3, UpToDateReplicas: 3, DesiredReplicas: 3})
+	g.Expect(conditions.Get(md, clusterv1.RolloutInProgressCondition)).To(HaveField("Status", corev1.ConditionFalse))
+	g.Expect(conditions.GetReason(md, clusterv1.RolloutInProgressCondition)).To(Equal(clusterv1.RolloutCompletedReason))
+	g.Expect(testutil.ToFloat64(upToDateReplicas.WithLabelValues(labelValues...))).To(Equal(3.0))
+	g.Expect(testutil.ToFloat64(completionTimestamp.WithLabelValues(labelValues...))).To(BeNumerically(">", 0))
+
+	DeleteMetrics("MachineDeployment", client.ObjectKeyFromObject(md))
+	g.Expect(testutil.CollectAndCount(upToDateReplicas)).To(Equal(0))
+	g.Expect(testutil.CollectAndCount(completionTimestamp)).To(Equal(0))
+}
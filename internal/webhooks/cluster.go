@@ -26,8 +26,13 @@ import (
 
 	"github.com/blang/semver"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -38,8 +43,10 @@ import (
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/internal/topology/adoption"
 	"sigs.k8s.io/cluster-api/internal/topology/check"
 	"sigs.k8s.io/cluster-api/internal/topology/variables"
+	"sigs.k8s.io/cluster-api/internal/util/maintenance"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/version"
 )
@@ -175,6 +182,38 @@ func (webhook *Cluster) validate(ctx context.Context, oldCluster, newCluster *cl
 			),
 		)
 	}
+
+	// Ensure that the labels and annotations propagated to the descendants of the Cluster are valid.
+	if newCluster.Spec.Metadata != nil {
+		allErrs = append(allErrs, metav1validation.ValidateLabels(newCluster.Spec.Metadata.Labels, specPath.Child("metadata", "labels"))...)
+		allErrs = append(allErrs, apivalidation.ValidateAnnotations(newCluster.Spec.Metadata.Annotations, specPath.Child("metadata", "annotations"))...)
+		allErrs = append(allErrs, validateClusterMetadataDomains(newCluster.Spec.Metadata.Labels, specPath.Child("metadata", "labels"))...)
+		allErrs = append(allErrs, validateClusterMetadataDomains(newCluster.Spec.Metadata.Annotations, specPath.Child("metadata", "annotations"))...)
+	}
+
+	// Ensure that Clusters whose infrastructure is provided by the control plane do not reference an infrastructure cluster,
+	// and that they reference a control plane; for managed topologies the reference is set by the topology controller.
+	if newCluster.Spec.ControlPlaneProvidesInfrastructure {
+		if newCluster.Spec.InfrastructureRef != nil {
+			allErrs = append(
+				allErrs,
+				field.Forbidden(
+					specPath.Child("infrastructureRef"),
+					"must not be set when spec.controlPlaneProvidesInfrastructure is true",
+				),
+			)
+		}
+		if newCluster.Spec.ControlPlaneRef == nil && newCluster.Spec.Topology == nil {
+			allErrs = append(
+				allErrs,
+				field.Required(
+					specPath.Child("controlPlaneRef"),
+					"must be set when spec.controlPlaneProvidesInfrastructure is true",
+				),
+			)
+		}
+	}
+
 	if newCluster.Spec.ClusterNetwork != nil {
 		// Ensure that the CIDR blocks defined under ClusterNetwork are valid.
 		if newCluster.Spec.ClusterNetwork.Pods != nil {
@@ -188,6 +227,9 @@ func (webhook *Cluster) validate(ctx context.Context, oldCluster, newCluster *cl
 		}
 	}
 
+	// Ensure that the maintenance windows are valid.
+	allErrs = append(allErrs, validateMaintenanceWindows(specPath.Child("maintenanceWindows"), newCluster.Spec.MaintenanceWindows)...)
+
 	topologyPath := specPath.Child("topology")
 
 	// Validate the managed topology, if defined.
@@ -206,6 +248,15 @@ func (webhook *Cluster) validate(ctx context.Context, oldCluster, newCluster *cl
 				"cannot be removed from an existing Cluster",
 			))
 		}
+
+		// Error if the update moves the cluster infrastructure away from the control plane, given that there is no
+		// infrastructure cluster to take over.
+		if oldCluster.Spec.ControlPlaneProvidesInfrastructure && !newCluster.Spec.ControlPlaneProvidesInfrastructure {
+			allErrs = append(allErrs, field.Forbidden(
+				specPath.Child("controlPlaneProvidesInfrastructure"),
+				"cannot be unset on an existing Cluster",
+			))
+		}
 	}
 
 	if len(allErrs) > 0 {
@@ -214,6 +265,19 @@ func (webhook *Cluster) validate(ctx context.Context, oldCluster, newCluster *cl
 	return allWarnings, nil
 }
 
+func validateMaintenanceWindows(fldPath *field.Path, windows []clusterv1.MaintenanceWindow) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, window := range windows {
+		if err := maintenance.ValidateSchedule(window.Schedule); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("schedule"), window.Schedule, err.Error()))
+		}
+		if window.Duration.Duration < time.Minute {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("duration"), window.Duration.String(), "must be at least 1m"))
+		}
+	}
+	return allErrs
+}
+
 func (webhook *Cluster) validateTopology(ctx context.Context, oldCluster, newCluster *clusterv1.Cluster, fldPath *field.Path) (admission.Warnings, field.ErrorList) {
 	var allWarnings admission.Warnings
 
@@ -303,12 +367,23 @@ func (webhook *Cluster) validateTopology(ctx context.Context, oldCluster, newClu
 			return allWarnings, allErrs
 		}
 
-		// Topology or Class can not be added on update unless ClusterTopologyUnsafeUpdateClassNameAnnotation is set.
+		// Topology or Class can not be added on update unless ClusterTopologyUnsafeUpdateClassNameAnnotation
+		// or ClusterTopologyAdoptAnnotation is set.
 		if oldCluster.Spec.Topology == nil || oldCluster.Spec.Topology.Class == "" {
 			if _, ok := newCluster.Annotations[clusterv1.ClusterTopologyUnsafeUpdateClassNameAnnotation]; ok {
 				return allWarnings, allErrs
 			}
 
+			// If the Cluster is adopted into the topology, its existing objects must be compatible with the ClusterClass
+			// and its templates must not be shared with other objects.
+			if _, ok := newCluster.Annotations[clusterv1.ClusterTopologyAdoptAnnotation]; ok {
+				if clusterClass != nil {
+					allErrs = append(allErrs, validateClusterAdoption(newCluster, clusterClass)...)
+				}
+				allErrs = append(allErrs, webhook.validateAdoptedTemplatesAreNotShared(ctx, newCluster)...)
+				return allWarnings, allErrs
+			}
+
 			allErrs = append(
 				allErrs,
 				field.Forbidden(
@@ -392,6 +467,59 @@ func (webhook *Cluster) validateTopology(ctx context.Context, oldCluster, newClu
 	return allWarnings, allErrs
 }
 
+// validateClusterAdoption validates that the existing objects of a Cluster, which is attached to a ClusterClass using
+// the ClusterTopologyAdoptAnnotation, are compatible with the templates in the ClusterClass, so they can be adopted
+// by the topology controller.
+func validateClusterAdoption(cluster *clusterv1.Cluster, clusterClass *clusterv1.ClusterClass) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if cluster.Spec.InfrastructureRef != nil && clusterClass.Spec.Infrastructure.Ref != nil {
+		allErrs = append(allErrs, validateAdoptedRef(field.NewPath("spec", "infrastructureRef"), cluster.Spec.InfrastructureRef, clusterClass.Spec.Infrastructure.Ref)...)
+	}
+	if cluster.Spec.ControlPlaneRef != nil && clusterClass.Spec.ControlPlane.Ref != nil {
+		allErrs = append(allErrs, validateAdoptedRef(field.NewPath("spec", "controlPlaneRef"), cluster.Spec.ControlPlaneRef, clusterClass.Spec.ControlPlane.Ref)...)
+	}
+	return allErrs
+}
+
+// validateAdoptedTemplatesAreNotShared validates that the templates of a Cluster, which would be adopted by the
+// topology controller, are not referenced by other objects; the topology controller deletes the templates it owns
+// when rotating them, so adopting shared templates would break the other objects.
+func (webhook *Cluster) validateAdoptedTemplatesAreNotShared(ctx context.Context, cluster *clusterv1.Cluster) field.ErrorList {
+	sharedTemplates, err := adoption.GetSharedTemplates(ctx, webhook.Client, cluster)
+	if err != nil {
+		return field.ErrorList{field.InternalError(field.NewPath(""), errors.Wrap(err, "failed to check the templates to adopt"))}
+	}
+	var allErrs field.ErrorList
+	for _, sharedTemplate := range sharedTemplates {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("metadata", "annotations", clusterv1.ClusterTopologyAdoptAnnotation),
+			fmt.Sprintf("existing objects cannot be adopted: %s; use a dedicated template instead", sharedTemplate)))
+	}
+	return allErrs
+}
+
+// validateAdoptedRef validates that the object referenced by an existing Cluster has the same group and kind
+// of the objects generated from the template in the ClusterClass.
+func validateAdoptedRef(fldPath *field.Path, ref, templateRef *corev1.ObjectReference) field.ErrorList {
+	refGV, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return field.ErrorList{field.Invalid(fldPath.Child("apiVersion"), ref.APIVersion, "must be a valid apiVersion")}
+	}
+	templateGV, err := schema.ParseGroupVersion(templateRef.APIVersion)
+	if err != nil {
+		// NOTE: this should never happen, the ClusterClass webhook validates the references to templates.
+		return field.ErrorList{field.InternalError(fldPath, errors.Wrapf(err, "failed to parse apiVersion %q of the template in the ClusterClass", templateRef.APIVersion))}
+	}
+
+	templateKind := strings.TrimSuffix(templateRef.Kind, clusterv1.TemplateSuffix)
+	if refGV.Group != templateGV.Group || ref.Kind != templateKind {
+		return field.ErrorList{field.Forbidden(fldPath,
+			fmt.Sprintf("%s %s cannot be adopted: the ClusterClass generates objects of kind %s in group %q",
+				ref.Kind, ref.Name, templateKind, templateGV.Group))}
+	}
+	return nil
+}
+
 func validateMachineHealthChecks(cluster *clusterv1.Cluster, clusterClass *clusterv1.ClusterClass) field.ErrorList {
 	var allErrs field.ErrorList
 
@@ -499,12 +627,30 @@ func DefaultAndValidateVariables(cluster *clusterv1.Cluster, clusterClass *clust
 		field.NewPath("spec", "topology", "variables"))...)
 	if cluster.Spec.Topology.Workers != nil {
 		for i, md := range cluster.Spec.Topology.Workers.MachineDeployments {
+			fldPath := field.NewPath("spec", "topology", "workers", "machineDeployments").Index(i).Child("variables", "overrides")
+			var overrides []clusterv1.ClusterVariable
+			if md.Variables != nil {
+				overrides = md.Variables.Overrides
+			}
+			classDefinitions := machineDeploymentClassVariableDefinitions(clusterClass, md.Class)
+			classOverrides, otherOverrides := splitMachineDeploymentClassVariables(overrides, classDefinitions)
+
+			// Variables of the MachineDeploymentClass and of the ClusterClass are set via the same variable overrides,
+			// so an override must not set a variable defined in both; this also covers variables discovered from
+			// runtime extensions, which are not known when the ClusterClass is validated.
+			allErrs = append(allErrs, validateMachineDeploymentVariableOverrideConflicts(overrides, classDefinitions, clusterClass.Status.Variables, fldPath)...)
+
+			// Variables of the MachineDeploymentClass are validated like Cluster variables, so required
+			// variables must be set for every MachineDeployment using the class.
+			if len(classDefinitions) > 0 {
+				allErrs = append(allErrs, variables.ValidateClusterVariables(classOverrides, classDefinitions, fldPath)...)
+			}
+
 			// Continue if there are no variable overrides.
-			if md.Variables == nil || len(md.Variables.Overrides) == 0 {
+			if len(otherOverrides) == 0 {
 				continue
 			}
-			allErrs = append(allErrs, variables.ValidateMachineDeploymentVariables(md.Variables.Overrides, clusterClass.Status.Variables,
-				field.NewPath("spec", "topology", "workers", "machineDeployments").Index(i).Child("variables", "overrides"))...)
+			allErrs = append(allErrs, variables.ValidateMachineDeploymentVariables(otherOverrides, clusterClass.Status.Variables, fldPath)...)
 		}
 	}
 	return allErrs
@@ -528,23 +674,115 @@ func DefaultVariables(cluster *clusterv1.Cluster, clusterClass *clusterv1.Cluste
 	}
 
 	if cluster.Spec.Topology.Workers != nil {
-		for i, md := range cluster.Spec.Topology.Workers.MachineDeployments {
-			// Continue if there are no variable overrides.
-			if md.Variables == nil || len(md.Variables.Overrides) == 0 {
+		for i := range cluster.Spec.Topology.Workers.MachineDeployments {
+			md := &cluster.Spec.Topology.Workers.MachineDeployments[i]
+			fldPath := field.NewPath("spec", "topology", "workers", "machineDeployments").Index(i).Child("variables", "overrides")
+			classDefinitions := machineDeploymentClassVariableDefinitions(clusterClass, md.Class)
+
+			// Continue if there are no variable overrides and no variables defined in the MachineDeploymentClass.
+			if (md.Variables == nil || len(md.Variables.Overrides) == 0) && len(classDefinitions) == 0 {
 				continue
 			}
-			defaultedVariables, errs := variables.DefaultMachineDeploymentVariables(md.Variables.Overrides, clusterClass.Status.Variables,
-				field.NewPath("spec", "topology", "workers", "machineDeployments").Index(i).Child("variables", "overrides"))
+			var overrides []clusterv1.ClusterVariable
+			if md.Variables != nil {
+				overrides = md.Variables.Overrides
+			}
+			classOverrides, otherOverrides := splitMachineDeploymentClassVariables(overrides, classDefinitions)
+
+			// Variables of the MachineDeploymentClass are defaulted like Cluster variables, so they are
+			// created if they have a top-level default value.
+			defaultedClassVariables, errs := variables.DefaultClusterVariables(classOverrides, classDefinitions, fldPath)
 			if len(errs) > 0 {
 				allErrs = append(allErrs, errs...)
-			} else {
-				md.Variables.Overrides = defaultedVariables
+				continue
 			}
+
+			defaultedVariables, errs := variables.DefaultMachineDeploymentVariables(otherOverrides, clusterClass.Status.Variables, fldPath)
+			if len(errs) > 0 {
+				allErrs = append(allErrs, errs...)
+				continue
+			}
+
+			defaultedVariables = append(defaultedVariables, defaultedClassVariables...)
+			if md.Variables == nil {
+				// Continue if there is nothing to set, so the Cluster is not changed by defaulting.
+				if len(defaultedVariables) == 0 {
+					continue
+				}
+				md.Variables = &clusterv1.MachineDeploymentVariables{}
+			}
+			md.Variables.Overrides = defaultedVariables
+		}
+	}
+	return allErrs
+}
+
+// machineDeploymentClassVariableDefinitions returns the variables defined in the MachineDeploymentClass with the
+// given name in the format used for the variables in the ClusterClass status.
+func machineDeploymentClassVariableDefinitions(clusterClass *clusterv1.ClusterClass, class string) []clusterv1.ClusterClassStatusVariable {
+	var definitions []clusterv1.ClusterClassStatusVariable
+	for _, mdClass := range clusterClass.Spec.Workers.MachineDeployments {
+		if mdClass.Class != class {
+			continue
+		}
+		for _, variable := range mdClass.Variables {
+			definitions = append(definitions, clusterv1.ClusterClassStatusVariable{
+				Name: variable.Name,
+				Definitions: []clusterv1.ClusterClassStatusVariableDefinition{
+					{
+						From:     clusterv1.VariableDefinitionFromInline,
+						Required: variable.Required,
+						Schema:   variable.Schema,
+					},
+				},
+			})
+		}
+	}
+	return definitions
+}
+
+// validateMachineDeploymentVariableOverrideConflicts validates that the variable overrides of a MachineDeployment
+// do not set variables defined both in the MachineDeploymentClass and in the ClusterClass.
+func validateMachineDeploymentVariableOverrideConflicts(overrides []clusterv1.ClusterVariable, classDefinitions, clusterClassDefinitions []clusterv1.ClusterClassStatusVariable, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	classVariableNames := sets.Set[string]{}
+	for _, definition := range classDefinitions {
+		classVariableNames.Insert(definition.Name)
+	}
+	clusterClassVariableNames := sets.Set[string]{}
+	for _, definition := range clusterClassDefinitions {
+		clusterClassVariableNames.Insert(definition.Name)
+	}
+
+	for i, override := range overrides {
+		if classVariableNames.Has(override.Name) && clusterClassVariableNames.Has(override.Name) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("name"), override.Name,
+				"variable is defined both in the MachineDeploymentClass and in the ClusterClass"))
 		}
 	}
 	return allErrs
 }
 
+// splitMachineDeploymentClassVariables splits the variable overrides of a MachineDeployment into the values
+// for variables defined in the MachineDeploymentClass and all other values.
+func splitMachineDeploymentClassVariables(overrides []clusterv1.ClusterVariable, classDefinitions []clusterv1.ClusterClassStatusVariable) ([]clusterv1.ClusterVariable, []clusterv1.ClusterVariable) {
+	classVariableNames := sets.Set[string]{}
+	for _, definition := range classDefinitions {
+		classVariableNames.Insert(definition.Name)
+	}
+
+	var classOverrides, otherOverrides []clusterv1.ClusterVariable
+	for _, override := range overrides {
+		if classVariableNames.Has(override.Name) {
+			classOverrides = append(classOverrides, override)
+			continue
+		}
+		otherOverrides = append(otherOverrides, override)
+	}
+	return classOverrides, otherOverrides
+}
+
 // ValidateClusterForClusterClass uses information in the ClusterClass to validate the Cluster.
 func ValidateClusterForClusterClass(cluster *clusterv1.Cluster, clusterClass *clusterv1.ClusterClass) field.ErrorList {
 	var allErrs field.ErrorList
@@ -555,9 +793,18 @@ func ValidateClusterForClusterClass(cluster *clusterv1.Cluster, clusterClass *cl
 		return field.ErrorList{field.InternalError(field.NewPath(""), errors.New("ClusterClass can not be nil"))}
 	}
 	allErrs = append(allErrs, check.MachineDeploymentTopologiesAreValidAndDefinedInClusterClass(cluster, clusterClass)...)
+	allErrs = append(allErrs, check.MachinePoolTopologiesAreValidAndDefinedInClusterClass(cluster, clusterClass)...)
 
 	// Validate the MachineHealthChecks defined in the cluster topology.
 	allErrs = append(allErrs, validateMachineHealthChecks(cluster, clusterClass)...)
+
+	// The infrastructure can be provided by the control plane only if the ClusterClass does not define an InfrastructureClusterTemplate.
+	if cluster.Spec.ControlPlaneProvidesInfrastructure && clusterClass.Spec.Infrastructure.Ref != nil {
+		allErrs = append(allErrs, field.Forbidden(
+			field.NewPath("spec", "controlPlaneProvidesInfrastructure"),
+			fmt.Sprintf("cannot be set when ClusterClass %s defines spec.infrastructure", clusterClass.Name),
+		))
+	}
 	return allErrs
 }
 
@@ -629,3 +876,23 @@ func clusterClassIsReconciled(clusterClass *clusterv1.ClusterClass) error {
 	}
 	return nil
 }
+
+// reservedClusterMetadataDomain is the domain of the labels and annotations managed by Cluster API, which
+// must not be propagated from the Cluster metadata to all the descendants of the Cluster, e.g. because they are
+// used to select the Machines of a MachineSet. All its subdomains, e.g. topology.cluster.x-k8s.io, are reserved too.
+var reservedClusterMetadataDomain = clusterv1.GroupVersion.Group
+
+// validateClusterMetadataDomains returns errors for the keys of the Cluster metadata in the reserved domains.
+func validateClusterMetadataDomains(metadata map[string]string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for key := range metadata {
+		domain, _, found := strings.Cut(key, "/")
+		if !found {
+			continue
+		}
+		if domain == reservedClusterMetadataDomain || strings.HasSuffix(domain, "."+reservedClusterMetadataDomain) {
+			allErrs = append(allErrs, field.Invalid(fldPath, key, fmt.Sprintf("keys in the %s domain and its subdomains are reserved", reservedClusterMetadataDomain)))
+		}
+	}
+	return allErrs
+}
@@ -19,6 +19,7 @@ package webhooks
 import (
 	"context"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 	"github.com/pkg/errors"
@@ -734,6 +735,219 @@ func TestClusterDefaultAndValidateVariables(t *testing.T) {
 					Build()).
 				Build(),
 		},
+		{
+			name: "should default variables defined in the MachineDeploymentClass",
+			clusterClass: builder.ClusterClass(metav1.NamespaceDefault, "class1").
+				WithWorkerMachineDeploymentClasses(
+					*builder.MachineDeploymentClass("md1").
+						WithVariables(clusterv1.ClusterClassVariable{
+							Name: "bootstrapProviderSetting",
+							Schema: clusterv1.VariableSchema{
+								OpenAPIV3Schema: clusterv1.JSONSchemaProps{
+									Type:    "string",
+									Default: &apiextensionsv1.JSON{Raw: []byte(`"default"`)},
+								},
+							},
+						}).
+						Build(),
+					*builder.MachineDeploymentClass("md2").Build()).
+				Build(),
+			topology: builder.ClusterTopology().
+				WithMachineDeployment(builder.MachineDeploymentTopology("workers1").
+					WithClass("md1").
+					Build()).
+				WithMachineDeployment(builder.MachineDeploymentTopology("workers2").
+					WithClass("md2").
+					Build()).
+				Build(),
+			expect: builder.ClusterTopology().
+				WithVariables([]clusterv1.ClusterVariable{}...).
+				WithMachineDeployment(builder.MachineDeploymentTopology("workers1").
+					WithClass("md1").
+					WithVariables(clusterv1.ClusterVariable{
+						Name:  "bootstrapProviderSetting",
+						Value: apiextensionsv1.JSON{Raw: []byte(`"default"`)},
+					}).
+					Build()).
+				WithMachineDeployment(builder.MachineDeploymentTopology("workers2").
+					WithClass("md2").
+					Build()).
+				Build(),
+		},
+		{
+			name: "should pass when variables defined in the MachineDeploymentClass and in the ClusterClass are set",
+			clusterClass: builder.ClusterClass(metav1.NamespaceDefault, "class1").
+				WithWorkerMachineDeploymentClasses(
+					*builder.MachineDeploymentClass("md1").
+						WithVariables(clusterv1.ClusterClassVariable{
+							Name:     "bootstrapProviderSetting",
+							Required: true,
+							Schema: clusterv1.VariableSchema{
+								OpenAPIV3Schema: clusterv1.JSONSchemaProps{
+									Type: "string",
+								},
+							},
+						}).
+						Build()).
+				WithStatusVariables(clusterv1.ClusterClassStatusVariable{
+					Name: "cpu",
+					Definitions: []clusterv1.ClusterClassStatusVariableDefinition{
+						{
+							From: clusterv1.VariableDefinitionFromInline,
+							Schema: clusterv1.VariableSchema{
+								OpenAPIV3Schema: clusterv1.JSONSchemaProps{
+									Type: "integer",
+								},
+							},
+						},
+					}}).Build(),
+			topology: builder.ClusterTopology().
+				WithMachineDeployment(builder.MachineDeploymentTopology("workers1").
+					WithClass("md1").
+					WithVariables(
+						clusterv1.ClusterVariable{
+							Name:  "bootstrapProviderSetting",
+							Value: apiextensionsv1.JSON{Raw: []byte(`"value"`)},
+						},
+						clusterv1.ClusterVariable{
+							Name:  "cpu",
+							Value: apiextensionsv1.JSON{Raw: []byte(`2`)},
+						}).
+					Build()).
+				Build(),
+			expect: builder.ClusterTopology().
+				WithVariables([]clusterv1.ClusterVariable{}...).
+				WithMachineDeployment(builder.MachineDeploymentTopology("workers1").
+					WithClass("md1").
+					WithVariables(
+						clusterv1.ClusterVariable{
+							Name:  "cpu",
+							Value: apiextensionsv1.JSON{Raw: []byte(`2`)},
+						},
+						clusterv1.ClusterVariable{
+							Name:  "bootstrapProviderSetting",
+							Value: apiextensionsv1.JSON{Raw: []byte(`"value"`)},
+						}).
+					Build()).
+				Build(),
+		},
+		{
+			name: "should fail when a required variable defined in the MachineDeploymentClass is not set",
+			clusterClass: builder.ClusterClass(metav1.NamespaceDefault, "class1").
+				WithWorkerMachineDeploymentClasses(
+					*builder.MachineDeploymentClass("md1").
+						WithVariables(clusterv1.ClusterClassVariable{
+							Name:     "bootstrapProviderSetting",
+							Required: true,
+							Schema: clusterv1.VariableSchema{
+								OpenAPIV3Schema: clusterv1.JSONSchemaProps{
+									Type: "string",
+								},
+							},
+						}).
+						Build()).
+				Build(),
+			topology: builder.ClusterTopology().
+				WithMachineDeployment(builder.MachineDeploymentTopology("workers1").
+					WithClass("md1").
+					Build()).
+				Build(),
+			expect:  builder.ClusterTopology().Build(),
+			wantErr: true,
+		},
+		{
+			name: "should fail when a variable defined in the MachineDeploymentClass is set for a MachineDeployment of another class",
+			clusterClass: builder.ClusterClass(metav1.NamespaceDefault, "class1").
+				WithWorkerMachineDeploymentClasses(
+					*builder.MachineDeploymentClass("md1").
+						WithVariables(clusterv1.ClusterClassVariable{
+							Name: "bootstrapProviderSetting",
+							Schema: clusterv1.VariableSchema{
+								OpenAPIV3Schema: clusterv1.JSONSchemaProps{
+									Type: "string",
+								},
+							},
+						}).
+						Build(),
+					*builder.MachineDeploymentClass("md2").Build()).
+				Build(),
+			topology: builder.ClusterTopology().
+				WithMachineDeployment(builder.MachineDeploymentTopology("workers2").
+					WithClass("md2").
+					WithVariables(clusterv1.ClusterVariable{
+						Name:  "bootstrapProviderSetting",
+						Value: apiextensionsv1.JSON{Raw: []byte(`"value"`)},
+					}).
+					Build()).
+				Build(),
+			expect:  builder.ClusterTopology().Build(),
+			wantErr: true,
+		},
+		{
+			name: "should fail when a variable override is defined both in the MachineDeploymentClass and in the ClusterClass",
+			clusterClass: builder.ClusterClass(metav1.NamespaceDefault, "class1").
+				WithWorkerMachineDeploymentClasses(
+					*builder.MachineDeploymentClass("md1").
+						WithVariables(clusterv1.ClusterClassVariable{
+							Name: "cpu",
+							Schema: clusterv1.VariableSchema{
+								OpenAPIV3Schema: clusterv1.JSONSchemaProps{
+									Type: "integer",
+								},
+							},
+						}).
+						Build()).
+				WithStatusVariables(clusterv1.ClusterClassStatusVariable{
+					Name: "cpu",
+					Definitions: []clusterv1.ClusterClassStatusVariableDefinition{
+						{
+							From: "runtime-extension",
+							Schema: clusterv1.VariableSchema{
+								OpenAPIV3Schema: clusterv1.JSONSchemaProps{
+									Type: "integer",
+								},
+							},
+						},
+					}}).Build(),
+			topology: builder.ClusterTopology().
+				WithMachineDeployment(builder.MachineDeploymentTopology("workers1").
+					WithClass("md1").
+					WithVariables(clusterv1.ClusterVariable{
+						Name:  "cpu",
+						Value: apiextensionsv1.JSON{Raw: []byte(`2`)},
+					}).
+					Build()).
+				Build(),
+			expect:  builder.ClusterTopology().Build(),
+			wantErr: true,
+		},
+		{
+			name: "should fail when a variable defined in the MachineDeploymentClass has an invalid value",
+			clusterClass: builder.ClusterClass(metav1.NamespaceDefault, "class1").
+				WithWorkerMachineDeploymentClasses(
+					*builder.MachineDeploymentClass("md1").
+						WithVariables(clusterv1.ClusterClassVariable{
+							Name: "bootstrapProviderSetting",
+							Schema: clusterv1.VariableSchema{
+								OpenAPIV3Schema: clusterv1.JSONSchemaProps{
+									Type: "string",
+								},
+							},
+						}).
+						Build()).
+				Build(),
+			topology: builder.ClusterTopology().
+				WithMachineDeployment(builder.MachineDeploymentTopology("workers1").
+					WithClass("md1").
+					WithVariables(clusterv1.ClusterVariable{
+						Name:  "bootstrapProviderSetting",
+						Value: apiextensionsv1.JSON{Raw: []byte(`1`)},
+					}).
+					Build()).
+				Build(),
+			expect:  builder.ClusterTopology().Build(),
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -848,6 +1062,138 @@ func TestClusterValidation(t *testing.T) {
 						builder.ControlPlane("fooNamespace", "cp1").Build()).
 					Build(),
 			},
+			{
+				name:      "should succeed when the control plane provides the infrastructure",
+				expectErr: false,
+				in: builder.Cluster("fooNamespace", "cluster1").
+					WithControlPlaneProvidesInfrastructure().
+					WithControlPlane(
+						builder.ControlPlane("fooNamespace", "cp1").Build()).
+					Build(),
+			},
+			{
+				name:      "should succeed with valid metadata",
+				expectErr: false,
+				in: builder.Cluster("fooNamespace", "cluster1").
+					WithMetadata(clusterv1.ObjectMeta{
+						Labels:      map[string]string{"example.com/cost-center": "platform"},
+						Annotations: map[string]string{"example.com/owner": "Team A"},
+					}).
+					Build(),
+			},
+			{
+				name:      "should return error with invalid metadata labels",
+				expectErr: true,
+				in: builder.Cluster("fooNamespace", "cluster1").
+					WithMetadata(clusterv1.ObjectMeta{
+						Labels: map[string]string{"example.com/cost-center": "Team A"},
+					}).
+					Build(),
+			},
+			{
+				name:      "should return error with metadata labels in the Cluster API domains",
+				expectErr: true,
+				in: builder.Cluster("fooNamespace", "cluster1").
+					WithMetadata(clusterv1.ObjectMeta{
+						Labels: map[string]string{clusterv1.ClusterNameLabel: "cluster2"},
+					}).
+					Build(),
+			},
+			{
+				name:      "should return error with metadata annotations in the Cluster API domains",
+				expectErr: true,
+				in: builder.Cluster("fooNamespace", "cluster1").
+					WithMetadata(clusterv1.ObjectMeta{
+						Annotations: map[string]string{clusterv1.ClusterTopologyDeferUpgradeAnnotation: ""},
+					}).
+					Build(),
+			},
+			{
+				name:      "should return error with metadata labels in the Cluster API subdomains",
+				expectErr: true,
+				in: builder.Cluster("fooNamespace", "cluster1").
+					WithMetadata(clusterv1.ObjectMeta{
+						Labels: map[string]string{"foo.cluster.x-k8s.io/cost-center": "platform"},
+					}).
+					Build(),
+			},
+			{
+				name:      "should succeed with metadata labels in domains ending with the Cluster API domain",
+				expectErr: false,
+				in: builder.Cluster("fooNamespace", "cluster1").
+					WithMetadata(clusterv1.ObjectMeta{
+						Labels: map[string]string{"mycluster.x-k8s.io/cost-center": "platform"},
+					}).
+					Build(),
+			},
+			{
+				name:      "should return error with invalid metadata annotations",
+				expectErr: true,
+				in: builder.Cluster("fooNamespace", "cluster1").
+					WithMetadata(clusterv1.ObjectMeta{
+						Annotations: map[string]string{"-owner": "Team A"},
+					}).
+					Build(),
+			},
+			{
+				name:      "should return error when the control plane provides the infrastructure and infrastructure ref is set",
+				expectErr: true,
+				in: builder.Cluster("fooNamespace", "cluster1").
+					WithControlPlaneProvidesInfrastructure().
+					WithInfrastructureCluster(
+						builder.InfrastructureClusterTemplate("fooNamespace", "infra1").Build()).
+					WithControlPlane(
+						builder.ControlPlane("fooNamespace", "cp1").Build()).
+					Build(),
+			},
+			{
+				name:      "should return error when the control plane provides the infrastructure and controlPlane ref is not set",
+				expectErr: true,
+				in: builder.Cluster("fooNamespace", "cluster1").
+					WithControlPlaneProvidesInfrastructure().
+					Build(),
+			},
+			{
+				name:      "should return error when unsetting controlPlaneProvidesInfrastructure",
+				expectErr: true,
+				old: builder.Cluster("fooNamespace", "cluster1").
+					WithControlPlaneProvidesInfrastructure().
+					WithControlPlane(
+						builder.ControlPlane("fooNamespace", "cp1").Build()).
+					Build(),
+				in: builder.Cluster("fooNamespace", "cluster1").
+					WithControlPlane(
+						builder.ControlPlane("fooNamespace", "cp1").Build()).
+					Build(),
+			},
+			{
+				name:      "pass with valid maintenance windows",
+				expectErr: false,
+				in: builder.Cluster("fooNamespace", "cluster1").
+					WithMaintenanceWindows(
+						clusterv1.MaintenanceWindow{Schedule: "0 22 * * 1-5", Duration: metav1.Duration{Duration: 2 * time.Hour}},
+						clusterv1.MaintenanceWindow{Schedule: "*/30 * * * 0,6", Duration: metav1.Duration{Duration: 10 * time.Minute}},
+					).
+					Build(),
+			},
+			{
+				name:      "should return error when a maintenance window has an invalid schedule",
+				expectErr: true,
+				in: builder.Cluster("fooNamespace", "cluster1").
+					WithMaintenanceWindows(
+						clusterv1.MaintenanceWindow{Schedule: "0 25 * * *", Duration: metav1.Duration{Duration: 2 * time.Hour}},
+					).
+					Build(),
+			},
+			{
+				name:      "should return error when a maintenance window is shorter than one minute",
+				expectErr: true,
+				in: builder.Cluster("fooNamespace", "cluster1").
+					WithMaintenanceWindows(
+						clusterv1.MaintenanceWindow{Schedule: "0 22 * * *", Duration: metav1.Duration{Duration: 30 * time.Second}},
+					).
+					Build(),
+			},
 			{
 				name:      "fails if topology is set but feature flag is disabled",
 				expectErr: true,
@@ -2035,6 +2381,18 @@ func TestMovingBetweenManagedAndUnmanaged(t *testing.T) {
 		Name:       "baz",
 		Namespace:  "default",
 	}
+	adoptedRef := &corev1.ObjectReference{
+		APIVersion: "group.test.io/foo",
+		Kind:       "bar",
+		Name:       "baz",
+		Namespace:  "default",
+	}
+	notAdoptableRef := &corev1.ObjectReference{
+		APIVersion: "another-group.test.io/foo",
+		Kind:       "bar",
+		Name:       "baz",
+		Namespace:  "default",
+	}
 
 	g := NewWithT(t)
 
@@ -2079,6 +2437,44 @@ func TestMovingBetweenManagedAndUnmanaged(t *testing.T) {
 				Build(),
 			wantErr: false,
 		},
+		{
+			name: "Allow cluster moving from Unmanaged to Managed if ClusterTopologyAdoptAnnotation is set and the existing objects can be adopted",
+			cluster: builder.Cluster(metav1.NamespaceDefault, "cluster1").
+				WithAnnotations(map[string]string{clusterv1.ClusterTopologyAdoptAnnotation: ""}).
+				WithInfrastructureCluster(refToUnstructured(adoptedRef)).
+				WithControlPlane(refToUnstructured(adoptedRef)).
+				Build(),
+			clusterClass: builder.ClusterClass(metav1.NamespaceDefault, "class1").
+				WithInfrastructureClusterTemplate(refToUnstructured(ref)).
+				WithControlPlaneTemplate(refToUnstructured(ref)).
+				WithControlPlaneInfrastructureMachineTemplate(refToUnstructured(ref)).
+				Build(),
+			updatedTopology: builder.ClusterTopology().
+				WithClass("class1").
+				WithVersion("v1.22.2").
+				WithControlPlaneReplicas(3).
+				Build(),
+			wantErr: false,
+		},
+		{
+			name: "Reject cluster moving from Unmanaged to Managed if ClusterTopologyAdoptAnnotation is set but the existing objects cannot be adopted",
+			cluster: builder.Cluster(metav1.NamespaceDefault, "cluster1").
+				WithAnnotations(map[string]string{clusterv1.ClusterTopologyAdoptAnnotation: ""}).
+				WithInfrastructureCluster(refToUnstructured(notAdoptableRef)).
+				WithControlPlane(refToUnstructured(adoptedRef)).
+				Build(),
+			clusterClass: builder.ClusterClass(metav1.NamespaceDefault, "class1").
+				WithInfrastructureClusterTemplate(refToUnstructured(ref)).
+				WithControlPlaneTemplate(refToUnstructured(ref)).
+				WithControlPlaneInfrastructureMachineTemplate(refToUnstructured(ref)).
+				Build(),
+			updatedTopology: builder.ClusterTopology().
+				WithClass("class1").
+				WithVersion("v1.22.2").
+				WithControlPlaneReplicas(3).
+				Build(),
+			wantErr: true,
+		},
 		{
 			name: "Reject cluster moving from Managed to Unmanaged i.e. removing the spec.topology.class field on update",
 			cluster: builder.Cluster(metav1.NamespaceDefault, "cluster1").
@@ -2150,6 +2546,97 @@ func TestMovingBetweenManagedAndUnmanaged(t *testing.T) {
 	}
 }
 
+// TestClusterTopologyAdoptionSharedTemplates tests that Clusters can't be adopted into a ClusterClass if their templates are shared.
+func TestClusterTopologyAdoptionSharedTemplates(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.ClusterTopology, true)()
+	ref := &corev1.ObjectReference{
+		APIVersion: "group.test.io/foo",
+		Kind:       "barTemplate",
+		Name:       "baz",
+		Namespace:  "default",
+	}
+	adoptedRef := &corev1.ObjectReference{
+		APIVersion: "group.test.io/foo",
+		Kind:       "bar",
+		Name:       "baz",
+		Namespace:  "default",
+	}
+	bootstrapTemplate := builder.BootstrapTemplate(metav1.NamespaceDefault, "bootstrap").Build()
+	infraTemplate := builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "infra").Build()
+
+	cluster := builder.Cluster(metav1.NamespaceDefault, "cluster1").
+		WithInfrastructureCluster(refToUnstructured(adoptedRef)).
+		WithControlPlane(refToUnstructured(adoptedRef)).
+		Build()
+	clusterClass := builder.ClusterClass(metav1.NamespaceDefault, "class1").
+		WithInfrastructureClusterTemplate(refToUnstructured(ref)).
+		WithControlPlaneTemplate(refToUnstructured(ref)).
+		WithControlPlaneInfrastructureMachineTemplate(refToUnstructured(ref)).
+		WithWorkerMachineDeploymentClasses(
+			*builder.MachineDeploymentClass("default-worker").
+				WithBootstrapTemplate(refToUnstructured(ref)).
+				WithInfrastructureTemplate(refToUnstructured(ref)).
+				Build()).
+		Build()
+	conditions.MarkTrue(clusterClass, clusterv1.ClusterClassVariablesReconciledCondition)
+	md := builder.MachineDeployment(metav1.NamespaceDefault, "md1").
+		WithClusterName(cluster.Name).
+		WithBootstrapTemplate(bootstrapTemplate).
+		WithInfrastructureTemplate(infraTemplate).
+		Build()
+
+	tests := []struct {
+		name    string
+		objs    []client.Object
+		wantErr bool
+	}{
+		{
+			name:    "Allow adopting a Cluster with dedicated templates",
+			objs:    []client.Object{md},
+			wantErr: false,
+		},
+		{
+			name: "Reject adopting a Cluster with templates shared with other MachineDeployments",
+			objs: []client.Object{
+				md,
+				builder.MachineDeployment(metav1.NamespaceDefault, "md2").
+					WithClusterName("cluster2").
+					WithBootstrapTemplate(bootstrapTemplate).
+					WithInfrastructureTemplate(infraTemplate).
+					Build(),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			fakeClient := fake.NewClientBuilder().
+				WithObjects(append(tt.objs, clusterClass, cluster)...).
+				WithScheme(fakeScheme).
+				Build()
+			c := &Cluster{Client: fakeClient}
+
+			adoptedCluster := cluster.DeepCopy()
+			adoptedCluster.Annotations = map[string]string{clusterv1.ClusterTopologyAdoptAnnotation: ""}
+			adoptedCluster.Spec.Topology = builder.ClusterTopology().
+				WithClass(clusterClass.Name).
+				WithVersion("v1.22.2").
+				WithControlPlaneReplicas(3).
+				WithMachineDeployment(clusterv1.MachineDeploymentTopology{Name: md.Name, Class: "default-worker"}).
+				Build()
+
+			_, err := c.ValidateUpdate(ctx, cluster, adoptedCluster)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
 // TestClusterClassPollingErrors tests when a Cluster can be reconciled given different reconcile states of the ClusterClass.
 func TestClusterClassPollingErrors(t *testing.T) {
 	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.ClusterTopology, true)()
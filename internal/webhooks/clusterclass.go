@@ -27,6 +27,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -37,6 +38,7 @@ import (
 	"sigs.k8s.io/cluster-api/api/v1beta1/index"
 	"sigs.k8s.io/cluster-api/feature"
 	"sigs.k8s.io/cluster-api/internal/topology/check"
+	topologynames "sigs.k8s.io/cluster-api/internal/topology/names"
 	"sigs.k8s.io/cluster-api/internal/topology/variables"
 )
 
@@ -54,6 +56,10 @@ func (webhook *ClusterClass) SetupWebhookWithManager(mgr ctrl.Manager) error {
 // ClusterClass implements a validation and defaulting webhook for ClusterClass.
 type ClusterClass struct {
 	Client client.Reader
+
+	// ReplicationControllerUsername is the username the ClusterClass replication controller authenticates with;
+	// if set, only this user can create or change ClusterClasses with the ClusterClassReplicatedFromLabel.
+	ReplicationControllerUsername string
 }
 
 var _ webhook.CustomDefaulter = &ClusterClass{}
@@ -92,6 +98,9 @@ func (webhook *ClusterClass) ValidateCreate(ctx context.Context, obj runtime.Obj
 	if !ok {
 		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a ClusterClass but got a %T", obj))
 	}
+	if err := webhook.validateReplica(ctx, nil, in); err != nil {
+		return nil, err
+	}
 	return nil, webhook.validate(ctx, nil, in)
 }
 
@@ -105,9 +114,37 @@ func (webhook *ClusterClass) ValidateUpdate(ctx context.Context, oldObj, newObj
 	if !ok {
 		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a ClusterClass but got a %T", oldObj))
 	}
+	if err := webhook.validateReplica(ctx, oldClusterClass, newClusterClass); err != nil {
+		return nil, err
+	}
 	return nil, webhook.validate(ctx, oldClusterClass, newClusterClass)
 }
 
+// validateReplica rejects the creation of ClusterClasses with the ClusterClassReplicatedFromLabel and changes to
+// ClusterClasses replicated from the replication source namespace, unless made by the replication controller;
+// replicated ClusterClasses can only be changed in the source namespace.
+// NOTE: Replicated ClusterClasses being deleted can be changed, e.g. to remove finalizers.
+func (webhook *ClusterClass) validateReplica(ctx context.Context, oldClusterClass, newClusterClass *clusterv1.ClusterClass) error {
+	if webhook.ReplicationControllerUsername == "" || !newClusterClass.DeletionTimestamp.IsZero() {
+		return nil
+	}
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil || req.UserInfo.Username == webhook.ReplicationControllerUsername {
+		return nil
+	}
+
+	obj := newClusterClass
+	if oldClusterClass != nil {
+		obj = oldClusterClass
+	}
+	sourceNamespace, ok := obj.Labels[clusterv1.ClusterClassReplicatedFromLabel]
+	if !ok {
+		return nil
+	}
+	return apierrors.NewForbidden(clusterv1.GroupVersion.WithResource("ClusterClass").GroupResource(), newClusterClass.Name,
+		fmt.Errorf("ClusterClass is replicated from namespace %s and can only be changed there", sourceNamespace))
+}
+
 // ValidateDelete implements validation for ClusterClass delete.
 func (webhook *ClusterClass) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
 	clusterClass, ok := obj.(*clusterv1.ClusterClass)
@@ -145,13 +182,29 @@ func (webhook *ClusterClass) validate(ctx context.Context, oldClusterClass, newC
 	// Ensure all MachineDeployment classes are unique.
 	allErrs = append(allErrs, check.MachineDeploymentClassesAreUnique(newClusterClass)...)
 
+	// Ensure all MachinePool classes are unique and their failure domains are valid.
+	allErrs = append(allErrs, check.MachinePoolClassesAreUnique(newClusterClass)...)
+
 	// Ensure MachineHealthChecks are valid.
 	allErrs = append(allErrs, validateMachineHealthCheckClasses(newClusterClass)...)
 
+	// Ensure IPAddressClaim templates are valid.
+	allErrs = append(allErrs, validateIPAddressClaimTemplates(newClusterClass)...)
+
+	// Ensure naming strategies are valid.
+	allErrs = append(allErrs, validateNamingStrategies(newClusterClass)...)
+
+	// Ensure autoscaling configurations are valid.
+	allErrs = append(allErrs, validateMachineDeploymentClassAutoscaling(newClusterClass)...)
+
+	// Ensure add-ons are valid.
+	allErrs = append(allErrs, validateAddons(newClusterClass)...)
+
 	// Validate variables.
 	allErrs = append(allErrs,
 		variables.ValidateClusterClassVariables(ctx, newClusterClass.Spec.Variables, field.NewPath("spec", "variables"))...,
 	)
+	allErrs = append(allErrs, validateMachineDeploymentClassVariables(ctx, newClusterClass)...)
 
 	// Validate patches.
 	allErrs = append(allErrs, validatePatches(newClusterClass)...)
@@ -349,6 +402,185 @@ func validateMachineHealthCheckClasses(clusterClass *clusterv1.ClusterClass) fie
 	return allErrs
 }
 
+// validateIPAddressClaimTemplates validates the IPAddressClaim templates defined in the MachineDeploymentClasses.
+func validateIPAddressClaimTemplates(clusterClass *clusterv1.ClusterClass) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for i, md := range clusterClass.Spec.Workers.MachineDeployments {
+		names := sets.Set[string]{}
+		for j, claim := range md.IPAddressClaims {
+			fldPath := field.NewPath("spec", "workers", "machineDeployments").Index(i).Child("ipAddressClaims").Index(j)
+
+			if claim.Name == "" {
+				allErrs = append(allErrs, field.Required(fldPath.Child("name"), "name must be set"))
+			} else if names.Has(claim.Name) {
+				allErrs = append(allErrs, field.Duplicate(fldPath.Child("name"), claim.Name))
+			}
+			names.Insert(claim.Name)
+
+			if claim.PoolRef.Kind == "" {
+				allErrs = append(allErrs, field.Required(fldPath.Child("poolRef", "kind"), "kind must be set"))
+			}
+			if claim.PoolRef.Name == "" {
+				allErrs = append(allErrs, field.Required(fldPath.Child("poolRef", "name"), "name must be set"))
+			}
+
+			allErrs = append(allErrs, validateJSONPatches(claim.JSONPatches, nil, machineBuiltinVariables, fldPath.Child("jsonPatches"))...)
+		}
+	}
+	return allErrs
+}
+
+// validateNamingStrategies validates the naming strategies defined in the ClusterClass, by generating
+// names with sample arguments.
+func validateNamingStrategies(clusterClass *clusterv1.ClusterClass) field.ErrorList {
+	var allErrs field.ErrorList
+
+	// NOTE: The same template is used for the objects and for their templates, and templates are rotated
+	// by creating new objects, so all the naming strategies must generate unique names.
+	if strategy := clusterClass.Spec.ControlPlane.NamingStrategy; strategy != nil && strategy.Template != nil {
+		allErrs = append(allErrs, validateRandomNamingStrategyTemplate(field.NewPath("spec", "controlPlane", "namingStrategy", "template"), *strategy.Template, "ControlPlane",
+			topologynames.ControlPlaneNameGenerator(strategy, "cluster"))...)
+	}
+
+	for i, md := range clusterClass.Spec.Workers.MachineDeployments {
+		fldPath := field.NewPath("spec", "workers", "machineDeployments").Index(i)
+
+		if strategy := md.NamingStrategy; strategy != nil && strategy.Template != nil {
+			allErrs = append(allErrs, validateRandomNamingStrategyTemplate(fldPath.Child("namingStrategy", "template"), *strategy.Template, "MachineDeployment",
+				topologynames.MachineDeploymentNameGenerator(strategy, "cluster", "mdtopology"))...)
+		}
+
+		if strategy := md.MachineSetNamingStrategy; strategy != nil && strategy.Template != nil {
+			allErrs = append(allErrs, validateRandomNamingStrategyTemplate(fldPath.Child("machineSetNamingStrategy", "template"), *strategy.Template, "MachineSet",
+				topologynames.MachineSetNameGenerator(strategy, "cluster", "machinedeployment", "abcde"))...)
+		}
+
+		if strategy := md.MachineNamingStrategy; strategy != nil && strategy.Template != nil {
+			allErrs = append(allErrs, validateRandomNamingStrategyTemplate(fldPath.Child("machineNamingStrategy", "template"), *strategy.Template, "Machine",
+				topologynames.MachineNameGenerator(strategy, "cluster", "machineset"))...)
+		}
+	}
+	return allErrs
+}
+
+// validateMachineDeploymentClassAutoscaling validates that the capacity variables of the MachineDeployment classes
+// are defined in the ClusterClass.
+// NOTE: Variables defined by external patches are only known after the ClusterClass has been reconciled, so the
+// capacity variables are not validated if the ClusterClass has external patches.
+func validateMachineDeploymentClassAutoscaling(clusterClass *clusterv1.ClusterClass) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for _, patch := range clusterClass.Spec.Patches {
+		if patch.External != nil {
+			return nil
+		}
+	}
+
+	definedVariables := sets.Set[string]{}
+	for _, variable := range clusterClass.Spec.Variables {
+		definedVariables.Insert(variable.Name)
+	}
+
+	for i, md := range clusterClass.Spec.Workers.MachineDeployments {
+		if md.Autoscaling == nil || md.Autoscaling.CapacityVariable == nil {
+			continue
+		}
+		if !definedVariables.Has(*md.Autoscaling.CapacityVariable) {
+			allErrs = append(allErrs, field.Invalid(
+				field.NewPath("spec", "workers", "machineDeployments").Index(i).Child("autoscaling", "capacityVariable"),
+				*md.Autoscaling.CapacityVariable,
+				"variable is not defined in the ClusterClass"))
+		}
+	}
+	return allErrs
+}
+
+// validateMachineDeploymentClassVariables validates the variables defined in the MachineDeploymentClasses.
+func validateMachineDeploymentClassVariables(ctx context.Context, clusterClass *clusterv1.ClusterClass) field.ErrorList {
+	var allErrs field.ErrorList
+
+	clusterClassVariableNames := sets.Set[string]{}
+	for _, variable := range clusterClass.Spec.Variables {
+		clusterClassVariableNames.Insert(variable.Name)
+	}
+
+	for i, md := range clusterClass.Spec.Workers.MachineDeployments {
+		if len(md.Variables) == 0 {
+			continue
+		}
+		fldPath := field.NewPath("spec", "workers", "machineDeployments").Index(i).Child("variables")
+		allErrs = append(allErrs, variables.ValidateClusterClassVariables(ctx, md.Variables, fldPath)...)
+
+		// Variables of a MachineDeploymentClass are set via the same variable overrides of a MachineDeployment
+		// topology as the variables of the ClusterClass, so their names must not conflict.
+		for j, variable := range md.Variables {
+			if clusterClassVariableNames.Has(variable.Name) {
+				allErrs = append(allErrs, field.Invalid(fldPath.Index(j).Child("name"), variable.Name,
+					"variable name must not conflict with the name of a variable defined in spec.variables"))
+			}
+		}
+	}
+	return allErrs
+}
+
+// validateAddons validates the add-ons defined in the ClusterClass.
+func validateAddons(clusterClass *clusterv1.ClusterClass) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if len(clusterClass.Spec.Addons) == 0 {
+		return nil
+	}
+
+	// NOTE: Add-ons are implemented using ClusterResourceSets, which are behind the ClusterResourceSet feature gate.
+	if !feature.Gates.Enabled(feature.ClusterResourceSet) {
+		return field.ErrorList{field.Forbidden(
+			field.NewPath("spec", "addons"),
+			"can be set only if the ClusterResourceSet feature flag is enabled",
+		)}
+	}
+
+	names := sets.Set[string]{}
+	for i, addon := range clusterClass.Spec.Addons {
+		fldPath := field.NewPath("spec", "addons").Index(i)
+
+		if addon.Name == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("name"), "name must be set"))
+		} else if names.Has(addon.Name) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("name"), addon.Name))
+		} else {
+			// The add-on name is used in the name and in a label of the generated ClusterResourceSets.
+			for _, msg := range validation.IsDNS1123Label(addon.Name) {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("name"), addon.Name, msg))
+			}
+		}
+		names.Insert(addon.Name)
+
+		allErrs = append(allErrs, validateEnabledIf(addon.EnabledIf, fldPath.Child("enabledIf"))...)
+
+		if len(addon.ClusterResourceSet.Resources) == 0 {
+			allErrs = append(allErrs, field.Required(fldPath.Child("clusterResourceSet", "resources"), "at least one resource must be set"))
+		}
+		for j, resource := range addon.ClusterResourceSet.Resources {
+			if resource.Name == "" {
+				allErrs = append(allErrs, field.Required(fldPath.Child("clusterResourceSet", "resources").Index(j).Child("name"), "name must be set"))
+			}
+		}
+	}
+	return allErrs
+}
+
+// validateRandomNamingStrategyTemplate validates a naming strategy template which must contain {{ .random }}.
+func validateRandomNamingStrategyTemplate(fldPath *field.Path, template, kind string, generator topologynames.NameGenerator) field.ErrorList {
+	if !strings.Contains(template, "{{ .random }}") {
+		return field.ErrorList{field.Invalid(fldPath, template, fmt.Sprintf("invalid %s name template: {{ .random }} is missing", kind))}
+	}
+	if _, err := generator.GenerateName(); err != nil {
+		return field.ErrorList{field.Invalid(fldPath, template, fmt.Sprintf("invalid %s name template: %v", kind, err))}
+	}
+	return nil
+}
+
 // validateMachineHealthCheckClass validates the MachineHealthCheckSpec fields defined in a MachineHealthCheckClass.
 func validateMachineHealthCheckClass(fldPath *field.Path, namepace string, m *clusterv1.MachineHealthCheckClass) field.ErrorList {
 	mhc := clusterv1.MachineHealthCheck{
@@ -21,6 +21,8 @@ import (
 	"time"
 
 	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -29,6 +31,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/api/v1beta1/index"
@@ -177,6 +180,91 @@ func TestClusterClassValidationFeatureGated(t *testing.T) {
 	}
 }
 
+func TestClusterClassValidateReplica(t *testing.T) {
+	replica := builder.ClusterClass(metav1.NamespaceDefault, "class").Build()
+	replica.Labels = map[string]string{clusterv1.ClusterClassReplicatedFromLabel: "platform"}
+	changedReplica := replica.DeepCopy()
+	changedReplica.Spec.Variables = []clusterv1.ClusterClassVariable{{Name: "foo"}}
+	deletingReplica := changedReplica.DeepCopy()
+	deletingReplica.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	notReplica := builder.ClusterClass(metav1.NamespaceDefault, "class").Build()
+	changedNotReplica := notReplica.DeepCopy()
+	changedNotReplica.Spec.Variables = []clusterv1.ClusterClassVariable{{Name: "foo"}}
+
+	tests := []struct {
+		name                          string
+		replicationControllerUsername string
+		username                      string
+		oldClusterClass               *clusterv1.ClusterClass
+		newClusterClass               *clusterv1.ClusterClass
+		expectErr                     bool
+	}{
+		{
+			name:                          "reject creating a replica by users",
+			replicationControllerUsername: "controller",
+			username:                      "user",
+			newClusterClass:               replica,
+			expectErr:                     true,
+		},
+		{
+			name:                          "reject changing a replica by users",
+			replicationControllerUsername: "controller",
+			username:                      "user",
+			oldClusterClass:               replica,
+			newClusterClass:               changedReplica,
+			expectErr:                     true,
+		},
+		{
+			name:                          "allow changing a replica by the replication controller",
+			replicationControllerUsername: "controller",
+			username:                      "controller",
+			oldClusterClass:               replica,
+			newClusterClass:               changedReplica,
+			expectErr:                     false,
+		},
+		{
+			name:                          "allow changing a replica being deleted by users",
+			replicationControllerUsername: "controller",
+			username:                      "user",
+			oldClusterClass:               replica,
+			newClusterClass:               deletingReplica,
+			expectErr:                     false,
+		},
+		{
+			name:                          "allow changing ClusterClasses which are not replicas by users",
+			replicationControllerUsername: "controller",
+			username:                      "user",
+			oldClusterClass:               notReplica,
+			newClusterClass:               changedNotReplica,
+			expectErr:                     false,
+		},
+		{
+			name:            "allow changing a replica by users if replication is not enabled",
+			username:        "user",
+			oldClusterClass: replica,
+			newClusterClass: changedReplica,
+			expectErr:       false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			webhook := &ClusterClass{ReplicationControllerUsername: tt.replicationControllerUsername}
+			ctx := admission.NewContextWithRequest(ctx, admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				UserInfo: authenticationv1.UserInfo{Username: tt.username},
+			}})
+
+			err := webhook.validateReplica(ctx, tt.oldClusterClass, tt.newClusterClass)
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+		})
+	}
+}
+
 func TestClusterClassValidation(t *testing.T) {
 	// NOTE: ClusterTopology feature flag is disabled by default, thus preventing to create or update ClusterClasses.
 	// Enabling the feature flag temporarily for this test.
@@ -597,6 +685,340 @@ func TestClusterClassValidation(t *testing.T) {
 			expectErr: true,
 		},
 
+		// namingStrategy tests
+		{
+			name: "create pass with naming strategies",
+			in: builder.ClusterClass(metav1.NamespaceDefault, "class1").
+				WithInfrastructureClusterTemplate(
+					builder.InfrastructureClusterTemplate(metav1.NamespaceDefault, "infra1").Build()).
+				WithControlPlaneTemplate(
+					builder.ControlPlaneTemplate(metav1.NamespaceDefault, "cp1").
+						Build()).
+				WithControlPlaneInfrastructureMachineTemplate(
+					builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "cpInfra1").
+						Build()).
+				WithControlPlaneNamingStrategy(&clusterv1.ControlPlaneClassNamingStrategy{Template: pointer.String("{{ .cluster.name }}-cp-{{ .random }}")}).
+				WithWorkerMachineDeploymentClasses(
+					*builder.MachineDeploymentClass("aa").
+						WithInfrastructureTemplate(
+							builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "infra1").Build()).
+						WithBootstrapTemplate(
+							builder.BootstrapTemplate(metav1.NamespaceDefault, "bootstrap1").Build()).
+						WithNamingStrategy(&clusterv1.MachineDeploymentClassNamingStrategy{Template: pointer.String("{{ .cluster.name }}-{{ .machineDeployment.topologyName }}-{{ .random }}")}).
+						WithMachineSetNamingStrategy(&clusterv1.MachineSetNamingStrategy{Template: pointer.String("{{ .machineDeployment.name }}-{{ .random }}")}).
+						WithMachineNamingStrategy(&clusterv1.MachineNamingStrategy{Template: pointer.String("{{ .cluster.name }}-{{ .random }}")}).
+						Build()).
+				Build(),
+			old:       nil,
+			expectErr: false,
+		},
+		{
+			name: "create fail if the ControlPlane naming strategy uses an undefined argument",
+			in: builder.ClusterClass(metav1.NamespaceDefault, "class1").
+				WithInfrastructureClusterTemplate(
+					builder.InfrastructureClusterTemplate(metav1.NamespaceDefault, "infra1").Build()).
+				WithControlPlaneTemplate(
+					builder.ControlPlaneTemplate(metav1.NamespaceDefault, "cp1").
+						Build()).
+				WithControlPlaneInfrastructureMachineTemplate(
+					builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "cpInfra1").
+						Build()).
+				WithControlPlaneNamingStrategy(&clusterv1.ControlPlaneClassNamingStrategy{Template: pointer.String("{{ .cluster.undefined }}-{{ .random }}")}).
+				WithWorkerMachineDeploymentClasses(
+					*builder.MachineDeploymentClass("aa").
+						WithInfrastructureTemplate(
+							builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "infra1").Build()).
+						WithBootstrapTemplate(
+							builder.BootstrapTemplate(metav1.NamespaceDefault, "bootstrap1").Build()).
+						Build()).
+				Build(),
+			old:       nil,
+			expectErr: true,
+		},
+		{
+			name: "create fail if the MachineDeployment naming strategy generates invalid names",
+			in: builder.ClusterClass(metav1.NamespaceDefault, "class1").
+				WithInfrastructureClusterTemplate(
+					builder.InfrastructureClusterTemplate(metav1.NamespaceDefault, "infra1").Build()).
+				WithControlPlaneTemplate(
+					builder.ControlPlaneTemplate(metav1.NamespaceDefault, "cp1").
+						Build()).
+				WithControlPlaneInfrastructureMachineTemplate(
+					builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "cpInfra1").
+						Build()).
+				WithWorkerMachineDeploymentClasses(
+					*builder.MachineDeploymentClass("aa").
+						WithInfrastructureTemplate(
+							builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "infra1").Build()).
+						WithBootstrapTemplate(
+							builder.BootstrapTemplate(metav1.NamespaceDefault, "bootstrap1").Build()).
+						WithNamingStrategy(&clusterv1.MachineDeploymentClassNamingStrategy{Template: pointer.String("{{ .cluster.name }}_{{ .random }}")}).
+						Build()).
+				Build(),
+			old:       nil,
+			expectErr: true,
+		},
+		{
+			name: "create fail if the Machine naming strategy does not contain {{ .random }}",
+			in: builder.ClusterClass(metav1.NamespaceDefault, "class1").
+				WithInfrastructureClusterTemplate(
+					builder.InfrastructureClusterTemplate(metav1.NamespaceDefault, "infra1").Build()).
+				WithControlPlaneTemplate(
+					builder.ControlPlaneTemplate(metav1.NamespaceDefault, "cp1").
+						Build()).
+				WithControlPlaneInfrastructureMachineTemplate(
+					builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "cpInfra1").
+						Build()).
+				WithWorkerMachineDeploymentClasses(
+					*builder.MachineDeploymentClass("aa").
+						WithInfrastructureTemplate(
+							builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "infra1").Build()).
+						WithBootstrapTemplate(
+							builder.BootstrapTemplate(metav1.NamespaceDefault, "bootstrap1").Build()).
+						WithMachineNamingStrategy(&clusterv1.MachineNamingStrategy{Template: pointer.String("{{ .machineSet.name }}")}).
+						Build()).
+				Build(),
+			old:       nil,
+			expectErr: true,
+		},
+
+		{
+			name: "create fail if the ControlPlane naming strategy does not contain {{ .random }}",
+			in: builder.ClusterClass(metav1.NamespaceDefault, "class1").
+				WithInfrastructureClusterTemplate(
+					builder.InfrastructureClusterTemplate(metav1.NamespaceDefault, "infra1").Build()).
+				WithControlPlaneTemplate(
+					builder.ControlPlaneTemplate(metav1.NamespaceDefault, "cp1").
+						Build()).
+				WithControlPlaneInfrastructureMachineTemplate(
+					builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "cpInfra1").
+						Build()).
+				WithControlPlaneNamingStrategy(&clusterv1.ControlPlaneClassNamingStrategy{Template: pointer.String("{{ .cluster.name }}-cp")}).
+				Build(),
+			old:       nil,
+			expectErr: true,
+		},
+		{
+			name: "create fail if the MachineDeployment naming strategy does not contain {{ .random }}",
+			in: builder.ClusterClass(metav1.NamespaceDefault, "class1").
+				WithInfrastructureClusterTemplate(
+					builder.InfrastructureClusterTemplate(metav1.NamespaceDefault, "infra1").Build()).
+				WithControlPlaneTemplate(
+					builder.ControlPlaneTemplate(metav1.NamespaceDefault, "cp1").
+						Build()).
+				WithControlPlaneInfrastructureMachineTemplate(
+					builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "cpInfra1").
+						Build()).
+				WithWorkerMachineDeploymentClasses(
+					*builder.MachineDeploymentClass("aa").
+						WithInfrastructureTemplate(
+							builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "infra1").Build()).
+						WithBootstrapTemplate(
+							builder.BootstrapTemplate(metav1.NamespaceDefault, "bootstrap1").Build()).
+						WithNamingStrategy(&clusterv1.MachineDeploymentClassNamingStrategy{Template: pointer.String("{{ .cluster.name }}-{{ .machineDeployment.topologyName }}")}).
+						Build()).
+				Build(),
+			old:       nil,
+			expectErr: true,
+		},
+
+		// autoscaling tests
+		{
+			name: "create pass if the autoscaling capacity variable is defined",
+			in: builder.ClusterClass(metav1.NamespaceDefault, "class1").
+				WithInfrastructureClusterTemplate(
+					builder.InfrastructureClusterTemplate(metav1.NamespaceDefault, "infra1").Build()).
+				WithControlPlaneTemplate(
+					builder.ControlPlaneTemplate(metav1.NamespaceDefault, "cp1").
+						Build()).
+				WithControlPlaneInfrastructureMachineTemplate(
+					builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "cpInfra1").
+						Build()).
+				WithVariables(clusterv1.ClusterClassVariable{
+					Name: "capacity",
+					Schema: clusterv1.VariableSchema{
+						OpenAPIV3Schema: clusterv1.JSONSchemaProps{Type: "object"},
+					},
+				}).
+				WithWorkerMachineDeploymentClasses(
+					*builder.MachineDeploymentClass("aa").
+						WithInfrastructureTemplate(
+							builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "infra1").Build()).
+						WithBootstrapTemplate(
+							builder.BootstrapTemplate(metav1.NamespaceDefault, "bootstrap1").Build()).
+						WithAutoscaling(&clusterv1.MachineDeploymentClassAutoscaling{CapacityVariable: pointer.String("capacity")}).
+						Build()).
+				Build(),
+			old:       nil,
+			expectErr: false,
+		},
+		{
+			name: "create fail if the autoscaling capacity variable is not defined",
+			in: builder.ClusterClass(metav1.NamespaceDefault, "class1").
+				WithInfrastructureClusterTemplate(
+					builder.InfrastructureClusterTemplate(metav1.NamespaceDefault, "infra1").Build()).
+				WithControlPlaneTemplate(
+					builder.ControlPlaneTemplate(metav1.NamespaceDefault, "cp1").
+						Build()).
+				WithControlPlaneInfrastructureMachineTemplate(
+					builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "cpInfra1").
+						Build()).
+				WithWorkerMachineDeploymentClasses(
+					*builder.MachineDeploymentClass("aa").
+						WithInfrastructureTemplate(
+							builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "infra1").Build()).
+						WithBootstrapTemplate(
+							builder.BootstrapTemplate(metav1.NamespaceDefault, "bootstrap1").Build()).
+						WithAutoscaling(&clusterv1.MachineDeploymentClassAutoscaling{CapacityVariable: pointer.String("capacity")}).
+						Build()).
+				Build(),
+			old:       nil,
+			expectErr: true,
+		},
+
+		// ipAddressClaims tests
+		{
+			name: "create pass with ipAddressClaims",
+			in: builder.ClusterClass(metav1.NamespaceDefault, "class1").
+				WithInfrastructureClusterTemplate(
+					builder.InfrastructureClusterTemplate(metav1.NamespaceDefault, "infra1").Build()).
+				WithControlPlaneTemplate(
+					builder.ControlPlaneTemplate(metav1.NamespaceDefault, "cp1").
+						Build()).
+				WithControlPlaneInfrastructureMachineTemplate(
+					builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "cpInfra1").
+						Build()).
+				WithWorkerMachineDeploymentClasses(
+					*builder.MachineDeploymentClass("aa").
+						WithInfrastructureTemplate(
+							builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "infra1").Build()).
+						WithBootstrapTemplate(
+							builder.BootstrapTemplate(metav1.NamespaceDefault, "bootstrap1").Build()).
+						WithIPAddressClaims(
+							clusterv1.IPAddressClaimTemplate{
+								Name:    "nic0",
+								PoolRef: corev1.TypedLocalObjectReference{APIGroup: pointer.String("ipam.cluster.x-k8s.io"), Kind: "InClusterIPPool", Name: "pool"},
+								JSONPatches: []clusterv1.JSONPatch{{
+									Op:        "add",
+									Path:      "/spec/ipAddress",
+									ValueFrom: &clusterv1.JSONPatchValue{Variable: pointer.String("builtin.machine.ipAddresses.nic0.address")},
+								}},
+							},
+						).
+						Build()).
+				Build(),
+			old:       nil,
+			expectErr: false,
+		},
+		{
+			name: "create fail if ipAddressClaims have duplicated names",
+			in: builder.ClusterClass(metav1.NamespaceDefault, "class1").
+				WithInfrastructureClusterTemplate(
+					builder.InfrastructureClusterTemplate(metav1.NamespaceDefault, "infra1").Build()).
+				WithControlPlaneTemplate(
+					builder.ControlPlaneTemplate(metav1.NamespaceDefault, "cp1").
+						Build()).
+				WithControlPlaneInfrastructureMachineTemplate(
+					builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "cpInfra1").
+						Build()).
+				WithWorkerMachineDeploymentClasses(
+					*builder.MachineDeploymentClass("aa").
+						WithInfrastructureTemplate(
+							builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "infra1").Build()).
+						WithBootstrapTemplate(
+							builder.BootstrapTemplate(metav1.NamespaceDefault, "bootstrap1").Build()).
+						WithIPAddressClaims(
+							clusterv1.IPAddressClaimTemplate{
+								Name:    "nic0",
+								PoolRef: corev1.TypedLocalObjectReference{APIGroup: pointer.String("ipam.cluster.x-k8s.io"), Kind: "InClusterIPPool", Name: "pool"},
+								JSONPatches: []clusterv1.JSONPatch{{
+									Op:        "add",
+									Path:      "/spec/ipAddress",
+									ValueFrom: &clusterv1.JSONPatchValue{Variable: pointer.String("builtin.machine.ipAddresses.nic0.address")},
+								}},
+							},
+							clusterv1.IPAddressClaimTemplate{
+								Name:    "nic0",
+								PoolRef: corev1.TypedLocalObjectReference{APIGroup: pointer.String("ipam.cluster.x-k8s.io"), Kind: "InClusterIPPool", Name: "pool"},
+								JSONPatches: []clusterv1.JSONPatch{{
+									Op:        "add",
+									Path:      "/spec/ipAddress",
+									ValueFrom: &clusterv1.JSONPatchValue{Variable: pointer.String("builtin.machine.ipAddresses.nic0.address")},
+								}},
+							},
+						).
+						Build()).
+				Build(),
+			old:       nil,
+			expectErr: true,
+		},
+		{
+			name: "create fail if ipAddressClaims use an undefined builtin variable",
+			in: builder.ClusterClass(metav1.NamespaceDefault, "class1").
+				WithInfrastructureClusterTemplate(
+					builder.InfrastructureClusterTemplate(metav1.NamespaceDefault, "infra1").Build()).
+				WithControlPlaneTemplate(
+					builder.ControlPlaneTemplate(metav1.NamespaceDefault, "cp1").
+						Build()).
+				WithControlPlaneInfrastructureMachineTemplate(
+					builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "cpInfra1").
+						Build()).
+				WithWorkerMachineDeploymentClasses(
+					*builder.MachineDeploymentClass("aa").
+						WithInfrastructureTemplate(
+							builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "infra1").Build()).
+						WithBootstrapTemplate(
+							builder.BootstrapTemplate(metav1.NamespaceDefault, "bootstrap1").Build()).
+						WithIPAddressClaims(
+							clusterv1.IPAddressClaimTemplate{
+								Name:    "nic0",
+								PoolRef: corev1.TypedLocalObjectReference{APIGroup: pointer.String("ipam.cluster.x-k8s.io"), Kind: "InClusterIPPool", Name: "pool"},
+								JSONPatches: []clusterv1.JSONPatch{{
+									Op:        "add",
+									Path:      "/spec/ipAddress",
+									ValueFrom: &clusterv1.JSONPatchValue{Variable: pointer.String("builtin.machine.undefined")},
+								}},
+							},
+						).
+						Build()).
+				Build(),
+			old:       nil,
+			expectErr: true,
+		},
+		{
+			name: "create fail if ipAddressClaims use a builtin variable not available for IPAddressClaim templates",
+			in: builder.ClusterClass(metav1.NamespaceDefault, "class1").
+				WithInfrastructureClusterTemplate(
+					builder.InfrastructureClusterTemplate(metav1.NamespaceDefault, "infra1").Build()).
+				WithControlPlaneTemplate(
+					builder.ControlPlaneTemplate(metav1.NamespaceDefault, "cp1").
+						Build()).
+				WithControlPlaneInfrastructureMachineTemplate(
+					builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "cpInfra1").
+						Build()).
+				WithWorkerMachineDeploymentClasses(
+					*builder.MachineDeploymentClass("aa").
+						WithInfrastructureTemplate(
+							builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "infra1").Build()).
+						WithBootstrapTemplate(
+							builder.BootstrapTemplate(metav1.NamespaceDefault, "bootstrap1").Build()).
+						WithIPAddressClaims(
+							clusterv1.IPAddressClaimTemplate{
+								Name:    "nic0",
+								PoolRef: corev1.TypedLocalObjectReference{APIGroup: pointer.String("ipam.cluster.x-k8s.io"), Kind: "InClusterIPPool", Name: "pool"},
+								JSONPatches: []clusterv1.JSONPatch{{
+									Op:        "add",
+									Path:      "/spec/ipAddress",
+									ValueFrom: &clusterv1.JSONPatchValue{Variable: pointer.String("builtin.cluster.name")},
+								}},
+							},
+						).
+						Build()).
+				Build(),
+			old:       nil,
+			expectErr: true,
+		},
+
 		// create test
 		{
 			name: "create fail if duplicated machineDeploymentClasses",
@@ -1140,6 +1562,145 @@ func TestClusterClassValidation(t *testing.T) {
 				Build(),
 			expectErr: true,
 		},
+		{
+			name: "pass if variables defined in a MachineDeploymentClass are valid",
+			in: builder.ClusterClass(metav1.NamespaceDefault, "class1").
+				WithInfrastructureClusterTemplate(
+					builder.InfrastructureClusterTemplate(metav1.NamespaceDefault, "infra1").Build()).
+				WithControlPlaneTemplate(
+					builder.ControlPlaneTemplate(metav1.NamespaceDefault, "cp1").
+						Build()).
+				WithControlPlaneInfrastructureMachineTemplate(
+					builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "cpInfra1").
+						Build()).
+				WithVariables(clusterv1.ClusterClassVariable{
+					Name: "cpu",
+					Schema: clusterv1.VariableSchema{
+						OpenAPIV3Schema: clusterv1.JSONSchemaProps{
+							Type: "integer",
+						},
+					},
+				}).
+				WithWorkerMachineDeploymentClasses(
+					*builder.MachineDeploymentClass("aa").
+						WithInfrastructureTemplate(
+							builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "infra1").Build()).
+						WithBootstrapTemplate(
+							builder.BootstrapTemplate(metav1.NamespaceDefault, "bootstrap1").Build()).
+						WithVariables(clusterv1.ClusterClassVariable{
+							Name: "bootstrapProviderSetting",
+							Schema: clusterv1.VariableSchema{
+								OpenAPIV3Schema: clusterv1.JSONSchemaProps{
+									Type: "string",
+								},
+							},
+						}).
+						Build()).
+				Build(),
+			expectErr: false,
+		},
+		{
+			name: "fail if variables defined in a MachineDeploymentClass have an invalid schema",
+			in: builder.ClusterClass(metav1.NamespaceDefault, "class1").
+				WithInfrastructureClusterTemplate(
+					builder.InfrastructureClusterTemplate(metav1.NamespaceDefault, "infra1").Build()).
+				WithControlPlaneTemplate(
+					builder.ControlPlaneTemplate(metav1.NamespaceDefault, "cp1").
+						Build()).
+				WithControlPlaneInfrastructureMachineTemplate(
+					builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "cpInfra1").
+						Build()).
+				WithWorkerMachineDeploymentClasses(
+					*builder.MachineDeploymentClass("aa").
+						WithInfrastructureTemplate(
+							builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "infra1").Build()).
+						WithBootstrapTemplate(
+							builder.BootstrapTemplate(metav1.NamespaceDefault, "bootstrap1").Build()).
+						WithVariables(clusterv1.ClusterClassVariable{
+							Name: "bootstrapProviderSetting",
+							Schema: clusterv1.VariableSchema{
+								OpenAPIV3Schema: clusterv1.JSONSchemaProps{
+									Type: "invalidType",
+								},
+							},
+						}).
+						Build()).
+				Build(),
+			expectErr: true,
+		},
+		{
+			name: "fail if variables defined in a MachineDeploymentClass are not unique",
+			in: builder.ClusterClass(metav1.NamespaceDefault, "class1").
+				WithInfrastructureClusterTemplate(
+					builder.InfrastructureClusterTemplate(metav1.NamespaceDefault, "infra1").Build()).
+				WithControlPlaneTemplate(
+					builder.ControlPlaneTemplate(metav1.NamespaceDefault, "cp1").
+						Build()).
+				WithControlPlaneInfrastructureMachineTemplate(
+					builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "cpInfra1").
+						Build()).
+				WithWorkerMachineDeploymentClasses(
+					*builder.MachineDeploymentClass("aa").
+						WithInfrastructureTemplate(
+							builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "infra1").Build()).
+						WithBootstrapTemplate(
+							builder.BootstrapTemplate(metav1.NamespaceDefault, "bootstrap1").Build()).
+						WithVariables(clusterv1.ClusterClassVariable{
+							Name: "bootstrapProviderSetting",
+							Schema: clusterv1.VariableSchema{
+								OpenAPIV3Schema: clusterv1.JSONSchemaProps{
+									Type: "string",
+								},
+							},
+						}, clusterv1.ClusterClassVariable{
+							Name: "bootstrapProviderSetting",
+							Schema: clusterv1.VariableSchema{
+								OpenAPIV3Schema: clusterv1.JSONSchemaProps{
+									Type: "string",
+								},
+							},
+						}).
+						Build()).
+				Build(),
+			expectErr: true,
+		},
+		{
+			name: "fail if a variable defined in a MachineDeploymentClass conflicts with a variable of the ClusterClass",
+			in: builder.ClusterClass(metav1.NamespaceDefault, "class1").
+				WithInfrastructureClusterTemplate(
+					builder.InfrastructureClusterTemplate(metav1.NamespaceDefault, "infra1").Build()).
+				WithControlPlaneTemplate(
+					builder.ControlPlaneTemplate(metav1.NamespaceDefault, "cp1").
+						Build()).
+				WithControlPlaneInfrastructureMachineTemplate(
+					builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "cpInfra1").
+						Build()).
+				WithVariables(clusterv1.ClusterClassVariable{
+					Name: "cpu",
+					Schema: clusterv1.VariableSchema{
+						OpenAPIV3Schema: clusterv1.JSONSchemaProps{
+							Type: "integer",
+						},
+					},
+				}).
+				WithWorkerMachineDeploymentClasses(
+					*builder.MachineDeploymentClass("aa").
+						WithInfrastructureTemplate(
+							builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "infra1").Build()).
+						WithBootstrapTemplate(
+							builder.BootstrapTemplate(metav1.NamespaceDefault, "bootstrap1").Build()).
+						WithVariables(clusterv1.ClusterClassVariable{
+							Name: "cpu",
+							Schema: clusterv1.VariableSchema{
+								OpenAPIV3Schema: clusterv1.JSONSchemaProps{
+									Type: "integer",
+								},
+							},
+						}).
+						Build()).
+				Build(),
+			expectErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -1164,6 +1725,117 @@ func TestClusterClassValidation(t *testing.T) {
 	}
 }
 
+func TestClusterClassValidationAddons(t *testing.T) {
+	validResources := []clusterv1.ClusterClassAddonResource{{Name: "calico", Kind: "ConfigMap"}}
+
+	tests := []struct {
+		name                      string
+		addons                    []clusterv1.ClusterClassAddon
+		clusterResourceSetEnabled bool
+		expectErr                 bool
+	}{
+		{
+			name:                      "pass with no add-ons",
+			clusterResourceSetEnabled: false,
+			expectErr:                 false,
+		},
+		{
+			name: "pass with valid add-ons",
+			addons: []clusterv1.ClusterClassAddon{
+				{
+					Name:               "cni",
+					ClusterResourceSet: clusterv1.ClusterClassAddonClusterResourceSet{Resources: validResources},
+				},
+				{
+					Name:               "csi",
+					EnabledIf:          pointer.String(`{{ .installCSI }}`),
+					ClusterResourceSet: clusterv1.ClusterClassAddonClusterResourceSet{Resources: validResources, Strategy: "Reconcile"},
+				},
+			},
+			clusterResourceSetEnabled: true,
+			expectErr:                 false,
+		},
+		{
+			name: "fail if the ClusterResourceSet feature gate is disabled",
+			addons: []clusterv1.ClusterClassAddon{
+				{
+					Name:               "cni",
+					ClusterResourceSet: clusterv1.ClusterClassAddonClusterResourceSet{Resources: validResources},
+				},
+			},
+			clusterResourceSetEnabled: false,
+			expectErr:                 true,
+		},
+		{
+			name: "fail with duplicated add-on names",
+			addons: []clusterv1.ClusterClassAddon{
+				{
+					Name:               "cni",
+					ClusterResourceSet: clusterv1.ClusterClassAddonClusterResourceSet{Resources: validResources},
+				},
+				{
+					Name:               "cni",
+					ClusterResourceSet: clusterv1.ClusterClassAddonClusterResourceSet{Resources: validResources},
+				},
+			},
+			clusterResourceSetEnabled: true,
+			expectErr:                 true,
+		},
+		{
+			name: "fail with an invalid add-on name",
+			addons: []clusterv1.ClusterClassAddon{
+				{
+					Name:               "CNI_Calico",
+					ClusterResourceSet: clusterv1.ClusterClassAddonClusterResourceSet{Resources: validResources},
+				},
+			},
+			clusterResourceSetEnabled: true,
+			expectErr:                 true,
+		},
+		{
+			name: "fail with an invalid enabledIf template",
+			addons: []clusterv1.ClusterClassAddon{
+				{
+					Name:               "cni",
+					EnabledIf:          pointer.String(`{{ .installCNI }`),
+					ClusterResourceSet: clusterv1.ClusterClassAddonClusterResourceSet{Resources: validResources},
+				},
+			},
+			clusterResourceSetEnabled: true,
+			expectErr:                 true,
+		},
+		{
+			name: "fail without resources",
+			addons: []clusterv1.ClusterClassAddon{
+				{
+					Name: "cni",
+				},
+			},
+			clusterResourceSetEnabled: true,
+			expectErr:                 true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.ClusterResourceSet, tt.clusterResourceSetEnabled)()
+			g := NewWithT(t)
+
+			clusterClass := &clusterv1.ClusterClass{
+				Spec: clusterv1.ClusterClassSpec{
+					Addons: tt.addons,
+				},
+			}
+			errs := validateAddons(clusterClass)
+			if tt.expectErr {
+				g.Expect(errs).ToNot(BeEmpty())
+				return
+			}
+			g.Expect(errs).To(BeEmpty())
+		})
+	}
+}
+
 func TestClusterClassValidationWithClusterAwareChecks(t *testing.T) {
 	// NOTE: ClusterTopology feature flag is disabled by default, thus preventing to create or update ClusterClasses.
 	// Enabling the feature flag temporarily for this test.
@@ -115,7 +115,7 @@ func validatePatchDefinitions(patch clusterv1.ClusterClassPatch, clusterClass *c
 	if patch.Definitions != nil {
 		for i, definition := range patch.Definitions {
 			allErrs = append(allErrs,
-				validateJSONPatches(definition.JSONPatches, clusterClass.Spec.Variables, path.Child("definitions").Index(i).Child("jsonPatches"))...)
+				validateJSONPatches(definition.JSONPatches, patchDefinitionVariables(definition.Selector, clusterClass), builtinVariables, path.Child("definitions").Index(i).Child("jsonPatches"))...)
 			allErrs = append(allErrs,
 				validateSelectors(definition.Selector, clusterClass, path.Child("definitions").Index(i).Child("selector"))...)
 		}
@@ -236,16 +236,7 @@ func validateSelectors(selector clusterv1.PatchSelector, class *clusterv1.Cluste
 				}
 			}
 			for _, md := range class.Spec.Workers.MachineDeployments {
-				var matches bool
-				if md.Class == name || name == "*" {
-					matches = true
-				} else if strings.HasPrefix(name, "*") && strings.HasSuffix(md.Class, strings.TrimPrefix(name, "*")) {
-					matches = true
-				} else if strings.HasSuffix(name, "*") && strings.HasPrefix(md.Class, strings.TrimSuffix(name, "*")) {
-					matches = true
-				}
-
-				if matches {
+				if machineDeploymentClassMatchesName(md.Class, name) {
 					if selectorMatchTemplate(selector, md.Template.Infrastructure.Ref) ||
 						selectorMatchTemplate(selector, md.Template.Bootstrap.Ref) {
 						match = true
@@ -266,6 +257,70 @@ func validateSelectors(selector clusterv1.PatchSelector, class *clusterv1.Cluste
 	return allErrs
 }
 
+// machineDeploymentClassMatchesName returns true if the MachineDeploymentClass matches the given name of a selector.
+// The name can contain a single "*" rune at the beginning or the end.
+func machineDeploymentClassMatchesName(mdClass, name string) bool {
+	switch {
+	case mdClass == name || name == "*":
+		return true
+	case strings.HasPrefix(name, "*") && strings.HasSuffix(mdClass, strings.TrimPrefix(name, "*")):
+		return true
+	case strings.HasSuffix(name, "*") && strings.HasPrefix(mdClass, strings.TrimSuffix(name, "*")):
+		return true
+	}
+	return false
+}
+
+// patchDefinitionVariables returns the variables which can be used in the JSON patches of a patch definition.
+// In addition to the variables defined in the ClusterClass, a patch definition which exclusively selects templates of
+// MachineDeploymentClasses can use the variables defined in all the selected MachineDeploymentClasses.
+func patchDefinitionVariables(selector clusterv1.PatchSelector, class *clusterv1.ClusterClass) []clusterv1.ClusterClassVariable {
+	if selector.MatchResources.InfrastructureCluster || selector.MatchResources.ControlPlane ||
+		selector.MatchResources.MachineDeploymentClass == nil {
+		return class.Spec.Variables
+	}
+
+	// Collect the MachineDeploymentClasses with a template selected by the patch definition.
+	var selectedClasses []clusterv1.MachineDeploymentClass
+	for _, md := range class.Spec.Workers.MachineDeployments {
+		if !selectorMatchTemplate(selector, md.Template.Infrastructure.Ref) && !selectorMatchTemplate(selector, md.Template.Bootstrap.Ref) {
+			continue
+		}
+		for _, name := range selector.MatchResources.MachineDeploymentClass.Names {
+			if machineDeploymentClassMatchesName(md.Class, name) {
+				selectedClasses = append(selectedClasses, md)
+				break
+			}
+		}
+	}
+	if len(selectedClasses) == 0 {
+		return class.Spec.Variables
+	}
+
+	// Add the variables which are defined in all the selected MachineDeploymentClasses.
+	allVariables := append([]clusterv1.ClusterClassVariable{}, class.Spec.Variables...)
+	for _, variable := range selectedClasses[0].Variables {
+		definedInAllClasses := true
+		for _, md := range selectedClasses[1:] {
+			found := false
+			for _, v := range md.Variables {
+				if v.Name == variable.Name {
+					found = true
+					break
+				}
+			}
+			if !found {
+				definedInAllClasses = false
+				break
+			}
+		}
+		if definedInAllClasses {
+			allVariables = append(allVariables, variable)
+		}
+	}
+	return allVariables
+}
+
 // selectorMatchTemplate returns true if APIVersion and Kind for the given selector match the reference.
 func selectorMatchTemplate(selector clusterv1.PatchSelector, reference *corev1.ObjectReference) bool {
 	if reference == nil {
@@ -276,7 +331,9 @@ func selectorMatchTemplate(selector clusterv1.PatchSelector, reference *corev1.O
 
 var validOps = sets.Set[string]{}.Insert("add", "replace", "remove")
 
-func validateJSONPatches(jsonPatches []clusterv1.JSONPatch, variables []clusterv1.ClusterClassVariable, path *field.Path) field.ErrorList {
+// validateJSONPatches validates the given JSON patches. The valueFrom.variable of the patches can reference the
+// given variables and builtin variables.
+func validateJSONPatches(jsonPatches []clusterv1.JSONPatch, variables []clusterv1.ClusterClassVariable, builtins sets.Set[string], path *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
 	variableSet, _ := getClusterClassVariablesMapWithReverseIndex(variables)
 
@@ -306,13 +363,13 @@ func validateJSONPatches(jsonPatches []clusterv1.JSONPatch, variables []clusterv
 
 		// Validate the value and valueFrom fields for the patch.
 		allErrs = append(allErrs,
-			validateJSONPatchValues(jsonPatch, variableSet, path.Index(i))...,
+			validateJSONPatchValues(jsonPatch, variableSet, builtins, path.Index(i))...,
 		)
 	}
 	return allErrs
 }
 
-func validateJSONPatchValues(jsonPatch clusterv1.JSONPatch, variableSet map[string]*clusterv1.ClusterClassVariable, path *field.Path) field.ErrorList {
+func validateJSONPatchValues(jsonPatch clusterv1.JSONPatch, variableSet map[string]*clusterv1.ClusterClassVariable, builtins sets.Set[string], path *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
 
 	// move to the next variable if the jsonPatch does not have "replace" or "add" op. Additional validation is not needed.
@@ -385,7 +442,7 @@ func validateJSONPatchValues(jsonPatch clusterv1.JSONPatch, variableSet map[stri
 	if jsonPatch.ValueFrom != nil && jsonPatch.ValueFrom.Variable != nil {
 		// If the variable is one of the list of builtin variables it's valid.
 		if strings.HasPrefix(*jsonPatch.ValueFrom.Variable, "builtin.") {
-			if _, ok := builtinVariables[*jsonPatch.ValueFrom.Variable]; !ok {
+			if !isBuiltinVariable(*jsonPatch.ValueFrom.Variable, builtins) {
 				allErrs = append(allErrs,
 					field.Invalid(
 						path.Child("valueFrom", "variable"),
@@ -460,6 +517,25 @@ var builtinVariables = sets.Set[string]{}.Insert(
 	"builtin.machineDeployment.infrastructureRef.name",
 )
 
+// This contains a list of all of the valid builtin variables in the JSON patches of IPAddressClaim templates.
+var machineBuiltinVariables = sets.Set[string]{}.Insert(
+	"builtin",
+
+	// Machine builtins.
+	"builtin.machine",
+	"builtin.machine.name",
+	"builtin.machine.ipAddresses",
+)
+
+// isBuiltinVariable returns true if the variable is one of the given builtin variables.
+func isBuiltinVariable(variable string, builtins sets.Set[string]) bool {
+	if builtins.Has(variable) {
+		return true
+	}
+	// NOTE: The keys of builtin.machine.ipAddresses are the names of the IPAddressClaim templates.
+	return builtins.Has("builtin.machine.ipAddresses") && strings.HasPrefix(variable, "builtin.machine.ipAddresses.")
+}
+
 // validateIndexAccess checks to see if the jsonPath is attempting to add an element in the array i.e. access by number
 // If the operation is add an error is thrown if a number greater than 0 is used as an index.
 // If the operation is replace an error is thrown if an index is used.
@@ -1339,6 +1339,47 @@ func TestValidatePatches(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "error if jsonPatch uses a builtin machine variable outside of IPAddressClaim templates",
+			clusterClass: clusterv1.ClusterClass{
+				Spec: clusterv1.ClusterClassSpec{
+					ControlPlane: clusterv1.ControlPlaneClass{
+						LocalObjectTemplate: clusterv1.LocalObjectTemplate{
+							Ref: &corev1.ObjectReference{
+								APIVersion: "controlplane.cluster.x-k8s.io/v1beta1",
+								Kind:       "ControlPlaneTemplate",
+							},
+						},
+					},
+					Patches: []clusterv1.ClusterClassPatch{
+						{
+							Name: "patch1",
+							Definitions: []clusterv1.PatchDefinition{
+								{
+									Selector: clusterv1.PatchSelector{
+										APIVersion: "controlplane.cluster.x-k8s.io/v1beta1",
+										Kind:       "ControlPlaneTemplate",
+										MatchResources: clusterv1.PatchSelectorMatch{
+											ControlPlane: true,
+										},
+									},
+									JSONPatches: []clusterv1.JSONPatch{
+										{
+											Op:   "add",
+											Path: "/spec/template/spec/",
+											ValueFrom: &clusterv1.JSONPatchValue{
+												Variable: pointer.String("builtin.machine.ipAddresses.nic0.address"),
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "pass if jsonPatch uses a builtin variable which is defined",
 			clusterClass: clusterv1.ClusterClass{
@@ -1382,6 +1423,148 @@ func TestValidatePatches(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "pass if jsonPatch uses a variable defined in all the MachineDeploymentClasses selected by the patch",
+			clusterClass: clusterv1.ClusterClass{
+				Spec: clusterv1.ClusterClassSpec{
+					Workers: clusterv1.WorkersClass{
+						MachineDeployments: []clusterv1.MachineDeploymentClass{
+							{
+								Class: "kubeadm-workers",
+								Template: clusterv1.MachineDeploymentClassTemplate{
+									Bootstrap: clusterv1.LocalObjectTemplate{
+										Ref: &corev1.ObjectReference{
+											APIVersion: "bootstrap.cluster.x-k8s.io/v1beta1",
+											Kind:       "KubeadmConfigTemplate",
+										},
+									},
+								},
+								Variables: []clusterv1.ClusterClassVariable{
+									{
+										Name: "kubeadmSetting",
+										Schema: clusterv1.VariableSchema{
+											OpenAPIV3Schema: clusterv1.JSONSchemaProps{
+												Type: "string",
+											},
+										},
+									},
+								},
+							},
+							{
+								Class: "other-workers",
+								Template: clusterv1.MachineDeploymentClassTemplate{
+									Bootstrap: clusterv1.LocalObjectTemplate{
+										Ref: &corev1.ObjectReference{
+											APIVersion: "bootstrap.cluster.x-k8s.io/v1beta1",
+											Kind:       "OtherConfigTemplate",
+										},
+									},
+								},
+							},
+						},
+					},
+					Patches: []clusterv1.ClusterClassPatch{
+						{
+							Name: "patch1",
+							Definitions: []clusterv1.PatchDefinition{
+								{
+									Selector: clusterv1.PatchSelector{
+										APIVersion: "bootstrap.cluster.x-k8s.io/v1beta1",
+										Kind:       "KubeadmConfigTemplate",
+										MatchResources: clusterv1.PatchSelectorMatch{
+											MachineDeploymentClass: &clusterv1.PatchSelectorMatchMachineDeploymentClass{
+												Names: []string{"*-workers"},
+											},
+										},
+									},
+									JSONPatches: []clusterv1.JSONPatch{
+										{
+											Op:   "add",
+											Path: "/spec/template/spec/",
+											ValueFrom: &clusterv1.JSONPatchValue{
+												Variable: pointer.String("kubeadmSetting"),
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error if jsonPatch uses a variable which is not defined in all the MachineDeploymentClasses selected by the patch",
+			clusterClass: clusterv1.ClusterClass{
+				Spec: clusterv1.ClusterClassSpec{
+					Workers: clusterv1.WorkersClass{
+						MachineDeployments: []clusterv1.MachineDeploymentClass{
+							{
+								Class: "kubeadm-workers",
+								Template: clusterv1.MachineDeploymentClassTemplate{
+									Bootstrap: clusterv1.LocalObjectTemplate{
+										Ref: &corev1.ObjectReference{
+											APIVersion: "bootstrap.cluster.x-k8s.io/v1beta1",
+											Kind:       "KubeadmConfigTemplate",
+										},
+									},
+								},
+								Variables: []clusterv1.ClusterClassVariable{
+									{
+										Name: "kubeadmSetting",
+										Schema: clusterv1.VariableSchema{
+											OpenAPIV3Schema: clusterv1.JSONSchemaProps{
+												Type: "string",
+											},
+										},
+									},
+								},
+							},
+							{
+								Class: "other-workers",
+								Template: clusterv1.MachineDeploymentClassTemplate{
+									Bootstrap: clusterv1.LocalObjectTemplate{
+										Ref: &corev1.ObjectReference{
+											APIVersion: "bootstrap.cluster.x-k8s.io/v1beta1",
+											Kind:       "KubeadmConfigTemplate",
+										},
+									},
+								},
+							},
+						},
+					},
+					Patches: []clusterv1.ClusterClassPatch{
+						{
+							Name: "patch1",
+							Definitions: []clusterv1.PatchDefinition{
+								{
+									Selector: clusterv1.PatchSelector{
+										APIVersion: "bootstrap.cluster.x-k8s.io/v1beta1",
+										Kind:       "KubeadmConfigTemplate",
+										MatchResources: clusterv1.PatchSelectorMatch{
+											MachineDeploymentClass: &clusterv1.PatchSelectorMatchMachineDeploymentClass{
+												Names: []string{"*-workers"},
+											},
+										},
+									},
+									JSONPatches: []clusterv1.JSONPatch{
+										{
+											Op:   "add",
+											Path: "/spec/template/spec/",
+											ValueFrom: &clusterv1.JSONPatchValue{
+												Variable: pointer.String("kubeadmSetting"),
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
 
 		// Patch with External
 		{
@@ -0,0 +1,178 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/topology/fieldowner"
+)
+
+// TopologyManagedFieldsPath is the path of the webhook validating changes to the fields managed by the Cluster topology.
+// NOTE: The same path is used by all the providers serving this webhook, e.g. for KubeadmControlPlanes.
+const TopologyManagedFieldsPath = "/validate-topology-managed-fields"
+
+// DefaultTopologyControllerUsername is the username of the service account of the core Cluster API controller
+// manager, which runs the topology controller, in the default installation.
+const DefaultTopologyControllerUsername = "system:serviceaccount:capi-system:capi-manager"
+
+// +kubebuilder:webhook:verbs=update,path=/validate-topology-managed-fields,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=cluster.x-k8s.io,resources=machinedeployments,versions=v1beta1,name=validation-topology-managed-fields.machinedeployment.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
+
+// TopologyManagedFields implements a validating webhook rejecting changes to the spec fields of objects managed
+// by the Cluster topology, e.g. MachineDeployments or control planes, which are owned by the topology controller
+// according to server-side apply field ownership. All the other fields, e.g. the annotations owned by users,
+// can still be changed.
+type TopologyManagedFields struct {
+	// TopologyControllerUsername is the username the topology controller authenticates with, e.g. the
+	// service account of the core Cluster API controller manager; only its changes are allowed.
+	TopologyControllerUsername string
+
+	decoder *admission.Decoder
+}
+
+// SetupWebhookWithManager sets up the TopologyManagedFields webhook.
+func (v *TopologyManagedFields) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	if v.TopologyControllerUsername == "" {
+		return errors.New("the username of the topology controller must be set")
+	}
+	v.decoder = admission.NewDecoder(mgr.GetScheme())
+
+	mgr.GetWebhookServer().Register(TopologyManagedFieldsPath, &webhook.Admission{
+		Handler: v,
+	})
+	return nil
+}
+
+// Handle rejects updates changing spec fields owned by the topology controller, unless they are made by the topology controller.
+func (v *TopologyManagedFields) Handle(_ context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+
+	// Changes made by the topology controller are always allowed.
+	// NOTE: The requesting user is checked instead of the field manager of the request, which can be set by any client.
+	if req.UserInfo.Username == v.TopologyControllerUsername {
+		return admission.Allowed("")
+	}
+
+	obj := &unstructured.Unstructured{}
+	if err := v.decoder.DecodeRaw(req.Object, obj); err != nil {
+		return admission.Errored(http.StatusBadRequest, errors.Wrapf(err, "failed to decode %s", req.Kind.Kind))
+	}
+	oldObj := &unstructured.Unstructured{}
+	if err := v.decoder.DecodeRaw(req.OldObject, oldObj); err != nil {
+		return admission.Errored(http.StatusBadRequest, errors.Wrapf(err, "failed to decode %s", req.Kind.Kind))
+	}
+
+	// Only objects managed by the Cluster topology are validated; objects being deleted are skipped so it is
+	// always possible to remove finalizers.
+	if _, ok := oldObj.GetLabels()[clusterv1.ClusterTopologyOwnedLabel]; !ok || !obj.GetDeletionTimestamp().IsZero() {
+		return admission.Allowed("")
+	}
+
+	changedFields, err := changedTopologyManagedFields(oldObj, obj)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if len(changedFields) > 0 {
+		return admission.Denied(fmt.Sprintf("%s %s is managed by the Cluster topology, the following fields cannot be changed: %s; "+
+			"change the Cluster topology or the ClusterClass instead", req.Kind.Kind, klog.KObj(obj), strings.Join(changedFields, ", ")))
+	}
+	return admission.Allowed("")
+}
+
+// changedTopologyManagedFields returns the paths of the spec fields owned by the topology controller in the
+// old object whose values are different in the new object.
+func changedTopologyManagedFields(oldObj, obj *unstructured.Unstructured) ([]string, error) {
+	changedFields := []string{}
+	for _, managedFields := range oldObj.GetManagedFields() {
+		if managedFields.Manager != fieldowner.TopologyManagerName ||
+			managedFields.Operation != metav1.ManagedFieldsOperationApply ||
+			managedFields.Subresource != "" ||
+			managedFields.FieldsV1 == nil {
+			continue
+		}
+
+		fieldsV1 := map[string]interface{}{}
+		if err := json.Unmarshal(managedFields.FieldsV1.Raw, &fieldsV1); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal managed fields")
+		}
+		specFields, ok := fieldsV1["f:spec"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		for _, path := range managedFieldPaths(specFields, []string{"spec"}) {
+			oldValue, oldFound, err := unstructured.NestedFieldNoCopy(oldObj.Object, path...)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to get %s", strings.Join(path, "."))
+			}
+			value, found, err := unstructured.NestedFieldNoCopy(obj.Object, path...)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to get %s", strings.Join(path, "."))
+			}
+			if oldFound != found || !reflect.DeepEqual(oldValue, value) {
+				changedFields = append(changedFields, strings.Join(path, "."))
+			}
+		}
+	}
+	sort.Strings(changedFields)
+	return changedFields, nil
+}
+
+// managedFieldPaths returns the paths of the fields in the given managed fields (in the FieldsV1 format).
+// NOTE: Paths are computed down to list items or leaf fields; lists are compared as a whole.
+func managedFieldPaths(fields map[string]interface{}, path []string) [][]string {
+	childFields := map[string]map[string]interface{}{}
+	for key, value := range fields {
+		if key == "." {
+			continue
+		}
+		// Keys of list items (k:, v:, i:) are not navigable with field paths, so the whole field is considered.
+		if !strings.HasPrefix(key, "f:") {
+			return [][]string{path}
+		}
+		child, _ := value.(map[string]interface{})
+		childFields[strings.TrimPrefix(key, "f:")] = child
+	}
+	if len(childFields) == 0 {
+		return [][]string{path}
+	}
+
+	paths := [][]string{}
+	for name, child := range childFields {
+		childPath := append(append([]string{}, path...), name)
+		paths = append(paths, managedFieldPaths(child, childPath)...)
+	}
+	return paths
+}
@@ -0,0 +1,238 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/topology/fieldowner"
+)
+
+func TestTopologyManagedFields(t *testing.T) {
+	topologyManagedFields := `{
+		"f:metadata": {"f:labels": {"f:topology.cluster.x-k8s.io/owned": {}}},
+		"f:spec": {
+			"f:clusterName": {},
+			"f:template": {
+				"f:spec": {
+					"f:version": {},
+					"f:infrastructureRef": {"f:name": {}}
+				}
+			},
+			"f:strategy": {
+				"f:rollingUpdate": {
+					"f:deletePolicy": {}
+				}
+			},
+			"f:readinessGates": {
+				".": {},
+				"k:{\"conditionType\":\"Foo\"}": {".": {}, "f:conditionType": {}}
+			}
+		}
+	}`
+
+	md := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": clusterv1.GroupVersion.String(),
+		"kind":       "MachineDeployment",
+		"metadata": map[string]interface{}{
+			"name":      "md",
+			"namespace": metav1.NamespaceDefault,
+			"labels": map[string]interface{}{
+				clusterv1.ClusterTopologyOwnedLabel: "",
+			},
+		},
+		"spec": map[string]interface{}{
+			"clusterName": "cluster",
+			"replicas":    int64(3),
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"version": "v1.28.0",
+					"infrastructureRef": map[string]interface{}{
+						"name": "infra-1",
+					},
+				},
+			},
+			"strategy": map[string]interface{}{
+				"rollingUpdate": map[string]interface{}{
+					"deletePolicy": "Random",
+				},
+			},
+			"readinessGates": []interface{}{
+				map[string]interface{}{"conditionType": "Foo"},
+			},
+		},
+	}}
+	md.SetManagedFields([]metav1.ManagedFieldsEntry{
+		{
+			Manager:    fieldowner.TopologyManagerName,
+			Operation:  metav1.ManagedFieldsOperationApply,
+			APIVersion: clusterv1.GroupVersion.String(),
+			FieldsType: "FieldsV1",
+			FieldsV1:   &metav1.FieldsV1{Raw: []byte(topologyManagedFields)},
+		},
+	})
+
+	versionChanged := md.DeepCopy()
+	g := NewWithT(t)
+	g.Expect(unstructured.SetNestedField(versionChanged.Object, "v1.29.0", "spec", "template", "spec", "version")).To(Succeed())
+
+	replicasChanged := md.DeepCopy()
+	g.Expect(unstructured.SetNestedField(replicasChanged.Object, int64(5), "spec", "replicas")).To(Succeed())
+
+	annotationsChanged := md.DeepCopy()
+	annotationsChanged.SetAnnotations(map[string]string{"example.com/owner": "user"})
+
+	readinessGatesChanged := md.DeepCopy()
+	g.Expect(unstructured.SetNestedSlice(readinessGatesChanged.Object, []interface{}{
+		map[string]interface{}{"conditionType": "Bar"},
+	}, "spec", "readinessGates")).To(Succeed())
+
+	deletePolicyRemoved := md.DeepCopy()
+	unstructured.RemoveNestedField(deletePolicyRemoved.Object, "spec", "strategy", "rollingUpdate", "deletePolicy")
+
+	notTopologyOwned := md.DeepCopy()
+	notTopologyOwned.SetLabels(nil)
+	notTopologyOwnedVersionChanged := versionChanged.DeepCopy()
+	notTopologyOwnedVersionChanged.SetLabels(nil)
+
+	tests := []struct {
+		name          string
+		operation     admissionv1.Operation
+		username      string
+		fieldManager  string
+		oldObj        *unstructured.Unstructured
+		obj           *unstructured.Unstructured
+		expectAllowed bool
+		expectMessage string
+	}{
+		{
+			name:          "allow create",
+			operation:     admissionv1.Create,
+			obj:           versionChanged,
+			expectAllowed: true,
+		},
+		{
+			name:          "reject change to a field owned by the topology controller",
+			operation:     admissionv1.Update,
+			oldObj:        md,
+			obj:           versionChanged,
+			expectAllowed: false,
+			expectMessage: "spec.template.spec.version",
+		},
+		{
+			name:          "reject change to a list owned by the topology controller",
+			operation:     admissionv1.Update,
+			oldObj:        md,
+			obj:           readinessGatesChanged,
+			expectAllowed: false,
+			expectMessage: "spec.readinessGates",
+		},
+		{
+			name:          "reject removal of a field owned by the topology controller",
+			operation:     admissionv1.Update,
+			oldObj:        md,
+			obj:           deletePolicyRemoved,
+			expectAllowed: false,
+			expectMessage: "spec.strategy.rollingUpdate.deletePolicy",
+		},
+		{
+			name:          "allow change to a field owned by the topology controller made by the topology controller",
+			operation:     admissionv1.Update,
+			username:      DefaultTopologyControllerUsername,
+			fieldManager:  fieldowner.TopologyManagerName,
+			oldObj:        md,
+			obj:           versionChanged,
+			expectAllowed: true,
+		},
+		{
+			name:          "reject change to a field owned by the topology controller made by another user using the topology controller field manager",
+			operation:     admissionv1.Update,
+			username:      "user",
+			fieldManager:  fieldowner.TopologyManagerName,
+			oldObj:        md,
+			obj:           versionChanged,
+			expectAllowed: false,
+			expectMessage: "spec.template.spec.version",
+		},
+		{
+			name:          "allow change to a field not owned by the topology controller",
+			operation:     admissionv1.Update,
+			oldObj:        md,
+			obj:           replicasChanged,
+			expectAllowed: true,
+		},
+		{
+			name:          "allow change to annotations",
+			operation:     admissionv1.Update,
+			oldObj:        md,
+			obj:           annotationsChanged,
+			expectAllowed: true,
+		},
+		{
+			name:          "allow change to objects not managed by the Cluster topology",
+			operation:     admissionv1.Update,
+			oldObj:        notTopologyOwned,
+			obj:           notTopologyOwnedVersionChanged,
+			expectAllowed: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			webhook := &TopologyManagedFields{
+				TopologyControllerUsername: DefaultTopologyControllerUsername,
+				decoder:                    admission.NewDecoder(runtime.NewScheme()),
+			}
+
+			req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: tt.operation,
+				Kind:      metav1.GroupVersionKind{Group: clusterv1.GroupVersion.Group, Version: clusterv1.GroupVersion.Version, Kind: "MachineDeployment"},
+				Object:    runtime.RawExtension{Raw: mustMarshal(g, tt.obj)},
+				UserInfo:  authenticationv1.UserInfo{Username: tt.username},
+			}}
+			if tt.oldObj != nil {
+				req.OldObject = runtime.RawExtension{Raw: mustMarshal(g, tt.oldObj)}
+			}
+			if tt.fieldManager != "" {
+				req.Options = runtime.RawExtension{Raw: mustMarshal(g, &metav1.PatchOptions{FieldManager: tt.fieldManager})}
+			}
+
+			resp := webhook.Handle(ctx, req)
+			g.Expect(resp.Allowed).To(Equal(tt.expectAllowed), resp.Result.Message)
+			if tt.expectMessage != "" {
+				g.Expect(resp.Result.Message).To(ContainSubstring(tt.expectMessage))
+			}
+		})
+	}
+}
+
+func mustMarshal(g *WithT, obj interface{}) []byte {
+	raw, err := json.Marshal(obj)
+	g.Expect(err).ToNot(HaveOccurred())
+	return raw
+}
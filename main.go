@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"os"
 	goruntime "runtime"
+	"strings"
 	"time"
 
 	// +kubebuilder:scaffold:imports
@@ -33,6 +34,8 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apiserver/pkg/authentication/serviceaccount"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
 	cliflag "k8s.io/component-base/cli/flag"
@@ -71,6 +74,8 @@ import (
 	runtimeregistry "sigs.k8s.io/cluster-api/internal/runtime/registry"
 	runtimewebhooks "sigs.k8s.io/cluster-api/internal/webhooks/runtime"
 	"sigs.k8s.io/cluster-api/util/flags"
+	"sigs.k8s.io/cluster-api/util/priority"
+	"sigs.k8s.io/cluster-api/util/tracing"
 	"sigs.k8s.io/cluster-api/version"
 	"sigs.k8s.io/cluster-api/webhooks"
 )
@@ -93,22 +98,37 @@ var (
 	enableContentionProfiling     bool
 	clusterTopologyConcurrency    int
 	clusterClassConcurrency       int
+	clusterClassReplicationSource string
+	topologyControllerUsername    string
 	clusterConcurrency            int
 	extensionConfigConcurrency    int
 	machineConcurrency            int
 	machineSetConcurrency         int
 	machineDeploymentConcurrency  int
 	machinePoolConcurrency        int
+	machineWarmPoolConcurrency    int
 	clusterResourceSetConcurrency int
 	machineHealthCheckConcurrency int
 	syncPeriod                    time.Duration
 	restConfigQPS                 float32
 	restConfigBurst               int
 	nodeDrainClientTimeout        time.Duration
+	unreachableClusterTimeout     time.Duration
+	preDrainTaint                 string
+	nodePreDrainTaint             *corev1.Taint
+	preDrainTaintObservation      time.Duration
+	machineToNodeLabelDomains     []string
+	nodeToMachineLabels           []string
+	workerMachineDeletionBatch    int
+	machineSetAdoptionPolicy      string
+	reconcilePriorityWeights      map[string]int
+	priorityWeights               priority.Weights
+	namespaceConcurrency          int
 	webhookPort                   int
 	webhookCertDir                string
 	healthAddr                    string
 	tlsOptions                    = flags.TLSOptions{}
+	tracingOptions                = tracing.Options{}
 	logOptions                    = logs.NewOptions()
 )
 
@@ -174,6 +194,12 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&clusterClassConcurrency, "clusterclass-concurrency", 10,
 		"Number of ClusterClasses to process simultaneously")
 
+	fs.StringVar(&clusterClassReplicationSource, "clusterclass-replication-source-namespace", "",
+		"Namespace of the ClusterClasses and templates to be replicated to all the namespaces opting-in. Requires the ClusterClassReplication feature gate to be enabled.")
+
+	fs.StringVar(&topologyControllerUsername, "topology-controller-username", "",
+		"Username of this controller manager, which runs the topology and the ClusterClass replication controllers; only this user can change the fields of MachineDeployments managed by the Cluster topology and the replicated ClusterClasses. Defaults to the service account of the controller manager, read from the POD_NAMESPACE and POD_SERVICE_ACCOUNT environment variables, or to "+webhooks.DefaultTopologyControllerUsername+" if they are not set.")
+
 	fs.IntVar(&clusterConcurrency, "cluster-concurrency", 10,
 		"Number of clusters to process simultaneously")
 
@@ -192,12 +218,18 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&machinePoolConcurrency, "machinepool-concurrency", 10,
 		"Number of machine pools to process simultaneously")
 
+	fs.IntVar(&machineWarmPoolConcurrency, "machinewarmpool-concurrency", 10,
+		"Number of machine warm pools to process simultaneously")
+
 	fs.IntVar(&clusterResourceSetConcurrency, "clusterresourceset-concurrency", 10,
 		"Number of cluster resource sets to process simultaneously")
 
 	fs.IntVar(&machineHealthCheckConcurrency, "machinehealthcheck-concurrency", 10,
 		"Number of machine health checks to process simultaneously")
 
+	fs.IntVar(&namespaceConcurrency, "namespace-concurrency", 0,
+		"Maximum number of objects of the same namespace processed simultaneously by the Cluster, Machine, MachineSet and MachineDeployment controllers. Defaults to 0, which doesn't limit them")
+
 	fs.DurationVar(&syncPeriod, "sync-period", 10*time.Minute,
 		"The minimum interval at which watched resources are reconciled (e.g. 15m)")
 
@@ -210,6 +242,30 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.DurationVar(&nodeDrainClientTimeout, "node-drain-client-timeout-duration", time.Second*10,
 		"The timeout of the client used for draining nodes. Defaults to 10s")
 
+	fs.DurationVar(&unreachableClusterTimeout, "unreachable-cluster-timeout", 0,
+		"The duration after which node drain and wait for volume detach are skipped when deleting a Machine whose workload cluster control plane is unreachable. Defaults to 0, which never skips them")
+
+	fs.StringVar(&preDrainTaint, "node-pre-drain-taint", "",
+		"The taint added to the node of a Machine being deleted before evicting its Pods, in the key[=value]:effect format, e.g. ToBeDeletedByClusterAutoscaler:NoSchedule. Defaults to empty, which does not taint the node")
+
+	fs.DurationVar(&preDrainTaintObservation, "node-pre-drain-taint-observation-period", 0,
+		"The duration to wait after adding the --node-pre-drain-taint to the node of a Machine being deleted before evicting its Pods. Defaults to 0, which evicts Pods right after tainting the node")
+
+	fs.StringSliceVar(&machineToNodeLabelDomains, "machine-to-node-label-domains", nil,
+		"Comma-separated list of label domains of the Machine labels propagated to the Node; a domain with the \"*.\" prefix matches all its subdomains. Defaults to node-role.kubernetes.io, node-restriction.kubernetes.io, *.node-restriction.kubernetes.io, node.cluster.x-k8s.io and *.node.cluster.x-k8s.io")
+
+	fs.StringSliceVar(&nodeToMachineLabels, "node-to-machine-labels", nil,
+		"Comma-separated list of Node label keys reflected on the Machine, e.g. topology.kubernetes.io/region,topology.kubernetes.io/zone. Keys in the cluster.x-k8s.io domain and its subdomains are reserved and rejected")
+
+	fs.IntVar(&workerMachineDeletionBatch, "cluster-deletion-worker-machine-batch-size", 0,
+		"Maximum number of worker machines deleted in parallel when a cluster is deleted. Defaults to 0, which deletes all worker machines at once")
+
+	fs.StringVar(&machineSetAdoptionPolicy, "machineset-adoption-policy", "Always",
+		fmt.Sprintf("The policy used by MachineSets to adopt orphaned Machines matching their selector: Always adopts all of them; Annotated adopts only the Machines with the %s annotation set to the name of the MachineSet; DryRun never adopts them and only records events. Defaults to Always", clusterv1.MachineSetAdoptAnnotation))
+
+	fs.StringToIntVar(&reconcilePriorityWeights, "reconcile-priority-weights", nil,
+		fmt.Sprintf("The weights of the priority queue serving the requests of the Cluster, Machine, MachineSet and MachineDeployment controllers by the %s annotation of the Cluster of the objects, e.g. high=8,normal=2,low=1; in each round up to the given number of requests is served for each priority, starting from the highest one. Defaults to no weights, which serves requests in the order they are received", clusterv1.ReconcilePriorityAnnotation))
+
 	fs.IntVar(&webhookPort, "webhook-port", 9443,
 		"Webhook Server port")
 
@@ -221,6 +277,8 @@ func InitFlags(fs *pflag.FlagSet) {
 
 	flags.AddTLSOptions(fs, &tlsOptions)
 
+	tracing.AddOptions(fs, &tracingOptions)
+
 	feature.MutableGates.AddFlag(fs)
 }
 
@@ -248,6 +306,54 @@ func main() {
 		os.Exit(1)
 	}
 
+	if preDrainTaint != "" {
+		taint, err := parseTaint(preDrainTaint)
+		if err != nil {
+			setupLog.Error(fmt.Errorf("invalid node pre-drain taint %q: %v", preDrainTaint, err), "unable to start manager")
+			os.Exit(1)
+		}
+		nodePreDrainTaint = taint
+	}
+	if preDrainTaintObservation < 0 {
+		setupLog.Error(errors.New("node pre-drain taint observation period must not be negative"), "unable to start manager")
+		os.Exit(1)
+	}
+
+	weights, err := priority.ParseWeights(reconcilePriorityWeights)
+	if err != nil {
+		setupLog.Error(fmt.Errorf("invalid reconcile priority weights: %v", err), "unable to start manager")
+		os.Exit(1)
+	}
+	priorityWeights = weights
+
+	// Default the username of the topology controller to the service account of this controller manager.
+	if topologyControllerUsername == "" {
+		topologyControllerUsername = webhooks.DefaultTopologyControllerUsername
+		if namespace, name := os.Getenv("POD_NAMESPACE"), os.Getenv("POD_SERVICE_ACCOUNT"); namespace != "" && name != "" {
+			topologyControllerUsername = serviceaccount.MakeUsername(namespace, name)
+		}
+	}
+	setupLog.Info("Using topology controller username", "username", topologyControllerUsername)
+
+	for _, domain := range machineToNodeLabelDomains {
+		if errs := validation.IsDNS1123Subdomain(strings.TrimPrefix(domain, "*.")); len(errs) > 0 {
+			setupLog.Error(fmt.Errorf("invalid machine to node label domain %q: %s", domain, strings.Join(errs, ", ")), "unable to start manager")
+			os.Exit(1)
+		}
+	}
+	for _, key := range nodeToMachineLabels {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			setupLog.Error(fmt.Errorf("invalid node to machine label %q: %s", key, strings.Join(errs, ", ")), "unable to start manager")
+			os.Exit(1)
+		}
+		// Labels in the Cluster API domains are managed by Cluster API itself, e.g. the node.cluster.x-k8s.io labels
+		// propagated from the Machine to the Node, and must not be overwritten with the values of the Node.
+		if prefix, _, found := strings.Cut(key, "/"); found && (prefix == clusterv1.GroupVersion.Group || strings.HasSuffix(prefix, "."+clusterv1.GroupVersion.Group)) {
+			setupLog.Error(fmt.Errorf("invalid node to machine label %q: the %s label domain and its subdomains are reserved", key, clusterv1.GroupVersion.Group), "unable to start manager")
+			os.Exit(1)
+		}
+	}
+
 	minVer := version.MinimumKubernetesVersion
 	if feature.Gates.Enabled(feature.ClusterTopology) {
 		minVer = version.MinimumKubernetesVersionClusterTopology
@@ -325,6 +431,12 @@ func main() {
 	// Setup the context that's going to be used in controllers and for the manager.
 	ctx := ctrl.SetupSignalHandler()
 
+	shutdownTracing, err := tracing.Setup(ctx, tracingOptions, controllerName)
+	if err != nil {
+		setupLog.Error(err, "unable to setup tracing")
+		os.Exit(1)
+	}
+
 	setupChecks(mgr)
 	setupIndexes(ctx, mgr)
 	setupReconcilers(ctx, mgr)
@@ -336,6 +448,11 @@ func main() {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
+
+	// Flush the pending spans; the context of the manager is already cancelled at this point.
+	if err := shutdownTracing(context.Background()); err != nil {
+		setupLog.Error(err, "unable to shutdown tracing")
+	}
 }
 
 func setupChecks(mgr ctrl.Manager) {
@@ -428,6 +545,18 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 			os.Exit(1)
 		}
 
+		if feature.Gates.Enabled(feature.ClusterClassReplication) {
+			if err := (&controllers.ClusterClassReplicationReconciler{
+				Client:                    mgr.GetClient(),
+				UnstructuredCachingClient: unstructuredCachingClient,
+				SourceNamespace:           clusterClassReplicationSource,
+				WatchFilterValue:          watchFilterValue,
+			}).SetupWithManager(ctx, mgr, concurrency(clusterClassConcurrency)); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", "ClusterClassReplication")
+				os.Exit(1)
+			}
+		}
+
 		if err := (&controllers.ClusterTopologyReconciler{
 			Client:                    mgr.GetClient(),
 			APIReader:                 mgr.GetAPIReader(),
@@ -471,21 +600,31 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 	}
 
 	if err := (&controllers.ClusterReconciler{
-		Client:                    mgr.GetClient(),
-		UnstructuredCachingClient: unstructuredCachingClient,
-		APIReader:                 mgr.GetAPIReader(),
-		WatchFilterValue:          watchFilterValue,
+		Client:                         mgr.GetClient(),
+		UnstructuredCachingClient:      unstructuredCachingClient,
+		APIReader:                      mgr.GetAPIReader(),
+		WatchFilterValue:               watchFilterValue,
+		WorkerMachineDeletionBatchSize: workerMachineDeletionBatch,
+		ReconcilePriorityWeights:       priorityWeights,
+		NamespaceConcurrency:           namespaceConcurrency,
 	}).SetupWithManager(ctx, mgr, concurrency(clusterConcurrency)); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Cluster")
 		os.Exit(1)
 	}
 	if err := (&controllers.MachineReconciler{
-		Client:                    mgr.GetClient(),
-		UnstructuredCachingClient: unstructuredCachingClient,
-		APIReader:                 mgr.GetAPIReader(),
-		Tracker:                   tracker,
-		WatchFilterValue:          watchFilterValue,
-		NodeDrainClientTimeout:    nodeDrainClientTimeout,
+		Client:                         mgr.GetClient(),
+		UnstructuredCachingClient:      unstructuredCachingClient,
+		APIReader:                      mgr.GetAPIReader(),
+		Tracker:                        tracker,
+		WatchFilterValue:               watchFilterValue,
+		NodeDrainClientTimeout:         nodeDrainClientTimeout,
+		UnreachableClusterTimeout:      unreachableClusterTimeout,
+		PreDrainTaint:                  nodePreDrainTaint,
+		PreDrainTaintObservationPeriod: preDrainTaintObservation,
+		MachineToNodeLabelDomains:      machineToNodeLabelDomains,
+		NodeToMachineLabels:            nodeToMachineLabels,
+		ReconcilePriorityWeights:       priorityWeights,
+		NamespaceConcurrency:           namespaceConcurrency,
 	}).SetupWithManager(ctx, mgr, concurrency(machineConcurrency)); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Machine")
 		os.Exit(1)
@@ -495,7 +634,11 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 		UnstructuredCachingClient: unstructuredCachingClient,
 		APIReader:                 mgr.GetAPIReader(),
 		Tracker:                   tracker,
+		RuntimeClient:             runtimeClient,
 		WatchFilterValue:          watchFilterValue,
+		ReconcilePriorityWeights:  priorityWeights,
+		NamespaceConcurrency:      namespaceConcurrency,
+		AdoptionPolicy:            machineSetAdoptionPolicy,
 	}).SetupWithManager(ctx, mgr, concurrency(machineSetConcurrency)); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "MachineSet")
 		os.Exit(1)
@@ -504,7 +647,10 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 		Client:                    mgr.GetClient(),
 		UnstructuredCachingClient: unstructuredCachingClient,
 		APIReader:                 mgr.GetAPIReader(),
+		RuntimeClient:             runtimeClient,
 		WatchFilterValue:          watchFilterValue,
+		ReconcilePriorityWeights:  priorityWeights,
+		NamespaceConcurrency:      namespaceConcurrency,
 	}).SetupWithManager(ctx, mgr, concurrency(machineDeploymentConcurrency)); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "MachineDeployment")
 		os.Exit(1)
@@ -522,6 +668,16 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 		}
 	}
 
+	if feature.Gates.Enabled(feature.MachineWarmPool) {
+		if err := (&expcontrollers.MachineWarmPoolReconciler{
+			Client:           mgr.GetClient(),
+			WatchFilterValue: watchFilterValue,
+		}).SetupWithManager(ctx, mgr, concurrency(machineWarmPoolConcurrency)); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "MachineWarmPool")
+			os.Exit(1)
+		}
+	}
+
 	if feature.Gates.Enabled(feature.ClusterResourceSet) {
 		if err := (&addonscontrollers.ClusterResourceSetReconciler{
 			Client:           mgr.GetClient(),
@@ -553,7 +709,12 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 func setupWebhooks(mgr ctrl.Manager) {
 	// NOTE: ClusterClass and managed topologies are behind ClusterTopology feature gate flag; the webhook
 	// is going to prevent creating or updating new objects in case the feature flag is disabled.
-	if err := (&webhooks.ClusterClass{Client: mgr.GetClient()}).SetupWebhookWithManager(mgr); err != nil {
+	// NOTE: Replicated ClusterClasses can only be changed by the replication controller.
+	clusterClassWebhook := &webhooks.ClusterClass{Client: mgr.GetClient()}
+	if feature.Gates.Enabled(feature.ClusterClassReplication) {
+		clusterClassWebhook.ReplicationControllerUsername = topologyControllerUsername
+	}
+	if err := clusterClassWebhook.SetupWebhookWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "ClusterClass")
 		os.Exit(1)
 	}
@@ -565,6 +726,12 @@ func setupWebhooks(mgr ctrl.Manager) {
 		os.Exit(1)
 	}
 
+	// NOTE: The webhook prevents changes to the fields of MachineDeployments managed by the Cluster topology.
+	if err := (&webhooks.TopologyManagedFields{TopologyControllerUsername: topologyControllerUsername}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "TopologyManagedFields")
+		os.Exit(1)
+	}
+
 	if err := (&clusterv1.Machine{}).SetupWebhookWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "Machine")
 		os.Exit(1)
@@ -587,6 +754,13 @@ func setupWebhooks(mgr ctrl.Manager) {
 		os.Exit(1)
 	}
 
+	// NOTE: MachineWarmPool is behind MachineWarmPool feature gate flag; the webhook
+	// is going to prevent creating or updating new objects in case the feature flag is disabled
+	if err := (&expv1.MachineWarmPool{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "MachineWarmPool")
+		os.Exit(1)
+	}
+
 	// NOTE: ClusterResourceSet is behind ClusterResourceSet feature gate flag; the webhook
 	// is going to prevent creating or updating new objects in case the feature flag is disabled
 	if err := (&addonsv1.ClusterResourceSet{}).SetupWebhookWithManager(mgr); err != nil {
@@ -605,6 +779,13 @@ func setupWebhooks(mgr ctrl.Manager) {
 		os.Exit(1)
 	}
 
+	// NOTE: The Drain behavior of MachineDrainRules is behind the MachineDrainRule feature gate flag. The webhook will prevent
+	// using it if the feature flag is disabled.
+	if err := (&clusterv1.MachineDrainRule{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "MachineDrainRule")
+		os.Exit(1)
+	}
+
 	// NOTE: ExtensionConfig is behind the RuntimeSDK feature gate flag. The webhook will prevent creating or updating
 	// new objects if the feature flag is disabled.
 	if err := (&runtimewebhooks.ExtensionConfig{}).SetupWebhookWithManager(mgr); err != nil {
@@ -625,6 +806,30 @@ func setupWebhooks(mgr ctrl.Manager) {
 	}
 }
 
+// parseTaint parses a taint in the key[=value]:effect format, the same used by kubectl taint.
+func parseTaint(s string) (*corev1.Taint, error) {
+	keyValue, effect, ok := strings.Cut(s, ":")
+	if !ok {
+		return nil, errors.New("must be in the key[=value]:effect format")
+	}
+	key, value, _ := strings.Cut(keyValue, "=")
+	if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid key: %s", strings.Join(errs, ", "))
+	}
+	if value != "" {
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid value: %s", strings.Join(errs, ", "))
+		}
+	}
+	switch corev1.TaintEffect(effect) {
+	case corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+	default:
+		return nil, fmt.Errorf("invalid effect %q: must be one of %s, %s or %s", effect,
+			corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute)
+	}
+	return &corev1.Taint{Key: key, Value: value, Effect: corev1.TaintEffect(effect)}, nil
+}
+
 func concurrency(c int) controller.Options {
 	return controller.Options{MaxConcurrentReconciles: c}
 }
@@ -0,0 +1,128 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const (
+	// churnScaleUp adds a worker machine to a cluster.
+	churnScaleUp = "scale-up"
+
+	// churnScaleDown removes a worker machine from a cluster.
+	churnScaleDown = "scale-down"
+
+	// churnUpgrade triggers a rolling upgrade of a cluster to the upgrade Kubernetes version.
+	churnUpgrade = "upgrade"
+)
+
+// churn drives churn operations on random clusters at the rate defined by the flags, and returns the number of
+// operations applied by type.
+func churn(ctx context.Context, c client.Client, clusters []*clusterv1.Cluster) map[string]int {
+	klog.Infof("Driving churn on %d clusters for %s", len(clusters), churnDuration)
+
+	applied := map[string]int{}
+	ticker := time.NewTicker(churnInterval)
+	defer ticker.Stop()
+	deadline := time.After(churnDuration)
+	for {
+		select {
+		case <-ctx.Done():
+			return applied
+		case <-deadline:
+			return applied
+		case <-ticker.C:
+			cluster := clusters[rand.Intn(len(clusters))]          //nolint:gosec // Intentionally using a weak random number generator here.
+			op := churnOperations[rand.Intn(len(churnOperations))] //nolint:gosec // Intentionally using a weak random number generator here.
+
+			ok, err := churnCluster(ctx, c, cluster, op)
+			if err != nil {
+				klog.Errorf("Failed to apply %s to Cluster %s: %v", op, klog.KObj(cluster), err)
+				continue
+			}
+			if ok {
+				klog.Infof("Applied %s to Cluster %s", op, klog.KObj(cluster))
+				applied[op]++
+			}
+		}
+	}
+}
+
+// churnCluster applies a churn operation to a cluster; it returns false if the operation cannot be applied
+// to the cluster, e.g. because the cluster is already upgraded.
+func churnCluster(ctx context.Context, c client.Client, cluster *clusterv1.Cluster, op string) (bool, error) {
+	if err := c.Get(ctx, client.ObjectKeyFromObject(cluster), cluster); err != nil {
+		return false, errors.Wrapf(err, "failed to get Cluster")
+	}
+
+	original := cluster.DeepCopy()
+	if !applyChurnOperation(cluster, op) {
+		return false, nil
+	}
+
+	if err := c.Patch(ctx, cluster, client.MergeFrom(original)); err != nil {
+		return false, errors.Wrapf(err, "failed to patch Cluster")
+	}
+	return true, nil
+}
+
+// applyChurnOperation changes the topology of a cluster according to a churn operation; it returns false
+// if the operation cannot be applied to the cluster.
+func applyChurnOperation(cluster *clusterv1.Cluster, op string) bool {
+	if cluster.Spec.Topology == nil {
+		return false
+	}
+
+	switch op {
+	case churnUpgrade:
+		// NOTE: Downgrades are not supported, so each cluster can be upgraded only once.
+		if cluster.Spec.Topology.Version == upgradeKubernetesVersion {
+			return false
+		}
+		cluster.Spec.Topology.Version = upgradeKubernetesVersion
+		return true
+	case churnScaleUp, churnScaleDown:
+		if cluster.Spec.Topology.Workers == nil || len(cluster.Spec.Topology.Workers.MachineDeployments) == 0 {
+			return false
+		}
+		md := &cluster.Spec.Topology.Workers.MachineDeployments[0]
+		replicas := pointer.Int32Deref(md.Replicas, 1)
+		if op == churnScaleUp {
+			if replicas >= maxWorkerReplicas {
+				return false
+			}
+			md.Replicas = pointer.Int32(replicas + 1)
+			return true
+		}
+		if replicas <= 0 {
+			return false
+		}
+		md.Replicas = pointer.Int32(replicas - 1)
+		return true
+	}
+	return false
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// newCluster returns a Cluster using the ClusterClass defined by the flags.
+func newCluster(index int) *clusterv1.Cluster {
+	return &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      fmt.Sprintf("%s-%d", clusterNamePrefix, index),
+		},
+		Spec: clusterv1.ClusterSpec{
+			Topology: &clusterv1.Topology{
+				Class:   clusterClass,
+				Version: kubernetesVersion,
+				ControlPlane: clusterv1.ControlPlaneTopology{
+					Replicas: pointer.Int32(controlPlaneReplicas),
+				},
+				Workers: &clusterv1.WorkersTopology{
+					MachineDeployments: []clusterv1.MachineDeploymentTopology{
+						{
+							Class:    machineDeploymentClass,
+							Name:     "md-0",
+							Replicas: pointer.Int32(workerReplicas),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// forEachCluster calls f for each cluster, with the concurrency defined by the flags.
+func forEachCluster(clusters []*clusterv1.Cluster, f func(cluster *clusterv1.Cluster) error) error {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+
+	sem := make(chan struct{}, concurrency)
+	for _, cluster := range clusters {
+		cluster := cluster
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if err := f(cluster); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return kerrors.NewAggregate(errs)
+}
+
+// createClusters creates the namespace and the clusters defined by the flags.
+func createClusters(ctx context.Context, c client.Client) ([]*clusterv1.Cluster, error) {
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}
+	if err := c.Create(ctx, ns); err != nil && !apierrors.IsAlreadyExists(err) {
+		return nil, errors.Wrapf(err, "failed to create Namespace %s", namespace)
+	}
+
+	clusters := make([]*clusterv1.Cluster, 0, clusterCount)
+	for i := 0; i < clusterCount; i++ {
+		clusters = append(clusters, newCluster(i))
+	}
+
+	klog.Infof("Creating %d clusters from ClusterClass %s", len(clusters), clusterClass)
+	err := forEachCluster(clusters, func(cluster *clusterv1.Cluster) error {
+		if err := c.Create(ctx, cluster); err != nil {
+			return errors.Wrapf(err, "failed to create Cluster %s", klog.KObj(cluster))
+		}
+		return nil
+	})
+	return clusters, err
+}
+
+// waitForClusters waits for the clusters to be provisioned, and returns the time it took for each cluster.
+func waitForClusters(ctx context.Context, c client.Client, clusters []*clusterv1.Cluster) ([]time.Duration, error) {
+	klog.Infof("Waiting for %d clusters to be provisioned", len(clusters))
+
+	var (
+		mu        sync.Mutex
+		durations []time.Duration
+	)
+	err := forEachCluster(clusters, func(cluster *clusterv1.Cluster) error {
+		err := wait.PollUntilContextTimeout(ctx, 5*time.Second, provisioningTimeout, true, func(ctx context.Context) (bool, error) {
+			if err := c.Get(ctx, client.ObjectKeyFromObject(cluster), cluster); err != nil {
+				return false, nil //nolint:nilerr // Retry on transient errors.
+			}
+			return conditions.IsTrue(cluster, clusterv1.ReadyCondition), nil
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed waiting for Cluster %s to be provisioned", klog.KObj(cluster))
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		durations = append(durations, conditions.GetLastTransitionTime(cluster, clusterv1.ReadyCondition).Sub(cluster.CreationTimestamp.Time))
+		return nil
+	})
+	return durations, err
+}
+
+// deleteClusters deletes the clusters and waits for them to be gone.
+func deleteClusters(ctx context.Context, c client.Client, clusters []*clusterv1.Cluster) error {
+	klog.Infof("Deleting %d clusters", len(clusters))
+
+	return forEachCluster(clusters, func(cluster *clusterv1.Cluster) error {
+		if err := c.Delete(ctx, cluster); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete Cluster %s", klog.KObj(cluster))
+		}
+
+		err := wait.PollUntilContextTimeout(ctx, 5*time.Second, provisioningTimeout, true, func(ctx context.Context) (bool, error) {
+			if err := c.Get(ctx, client.ObjectKeyFromObject(cluster), &clusterv1.Cluster{}); err != nil {
+				return apierrors.IsNotFound(err), nil
+			}
+			return false, nil
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed waiting for Cluster %s to be deleted", klog.KObj(cluster))
+		}
+		return nil
+	})
+}
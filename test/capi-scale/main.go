@@ -0,0 +1,252 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// main is the main package for capi-scale, a load generator for Cluster API scale testing.
+//
+// capi-scale creates clusters from a ClusterClass, e.g. against the in-memory provider, drives churn on them
+// at a configurable rate and exports the reconcile latency and the queue depth of the Cluster API controllers
+// as a JSON report which can be used for regression comparison.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+var (
+	scheme = runtime.NewScheme()
+
+	// flags.
+	kubeconfig               string
+	namespace                string
+	clusterNamePrefix        string
+	clusterCount             int
+	clusterClass             string
+	kubernetesVersion        string
+	upgradeKubernetesVersion string
+	controlPlaneReplicas     int32
+	machineDeploymentClass   string
+	workerReplicas           int32
+	maxWorkerReplicas        int32
+	concurrency              int
+	provisioningTimeout      time.Duration
+	churnOperations          []string
+	churnInterval            time.Duration
+	churnDuration            time.Duration
+	metricsURLs              []string
+	metricsInterval          time.Duration
+	outputFile               string
+	cleanup                  bool
+)
+
+func init() {
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = clusterv1.AddToScheme(scheme)
+}
+
+// InitFlags initializes the flags.
+func InitFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&kubeconfig, "kubeconfig", "",
+		"Path to the kubeconfig file of the management cluster. If unspecified, the default loading rules are used.")
+
+	fs.StringVar(&namespace, "namespace", "capi-scale",
+		"Namespace where to create the clusters.")
+
+	fs.StringVar(&clusterNamePrefix, "cluster-name-prefix", "scale",
+		"Prefix of the names of the clusters; clusters are named <prefix>-<index>.")
+
+	fs.IntVar(&clusterCount, "clusters", 10,
+		"Number of clusters to create.")
+
+	fs.StringVar(&clusterClass, "cluster-class", "",
+		"Name of the ClusterClass to create the clusters from; the ClusterClass must exist in the namespace.")
+
+	fs.StringVar(&kubernetesVersion, "kubernetes-version", "",
+		"Kubernetes version of the clusters.")
+
+	fs.StringVar(&upgradeKubernetesVersion, "upgrade-kubernetes-version", "",
+		"Kubernetes version to upgrade the clusters to, required by the upgrade churn operation.")
+
+	fs.Int32Var(&controlPlaneReplicas, "control-plane-replicas", 1,
+		"Number of control plane machines of each cluster.")
+
+	fs.StringVar(&machineDeploymentClass, "machine-deployment-class", "default-worker",
+		"Name of the MachineDeployment class used for the workers of each cluster.")
+
+	fs.Int32Var(&workerReplicas, "worker-replicas", 1,
+		"Initial number of worker machines of each cluster.")
+
+	fs.Int32Var(&maxWorkerReplicas, "max-worker-replicas", 10,
+		"Maximum number of worker machines of each cluster, used by the scale-up churn operation.")
+
+	fs.IntVar(&concurrency, "concurrency", 5,
+		"Number of clusters to create or delete simultaneously.")
+
+	fs.DurationVar(&provisioningTimeout, "provisioning-timeout", 30*time.Minute,
+		"Time to wait for the clusters to be provisioned.")
+
+	fs.StringSliceVar(&churnOperations, "churn-operations", []string{churnScaleUp, churnScaleDown, churnUpgrade},
+		fmt.Sprintf("Churn operations to drive on the clusters. Possible values are: %s, %s, %s.", churnScaleUp, churnScaleDown, churnUpgrade))
+
+	fs.DurationVar(&churnInterval, "churn-interval", 10*time.Second,
+		"Interval between churn operations.")
+
+	fs.DurationVar(&churnDuration, "churn-duration", 0,
+		"Duration of the churn phase. If unspecified, no churn is driven on the clusters.")
+
+	fs.StringSliceVar(&metricsURLs, "metrics-url", nil,
+		"Metrics endpoints of the controllers to collect reconcile latency and queue depth from (e.g. http://localhost:8080/metrics).")
+
+	fs.DurationVar(&metricsInterval, "metrics-interval", 15*time.Second,
+		"Interval between metrics collections.")
+
+	fs.StringVar(&outputFile, "output", "",
+		"File to write the JSON report to. If unspecified, the report is written to stdout.")
+
+	fs.BoolVar(&cleanup, "cleanup", true,
+		"Delete the clusters at the end of the run.")
+}
+
+func main() {
+	InitFlags(pflag.CommandLine)
+	pflag.Parse()
+
+	if err := validateFlags(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	c, err := newClient()
+	if err != nil {
+		klog.Errorf("Failed to create client: %v", err)
+		os.Exit(1)
+	}
+
+	if err := run(ctx, c); err != nil {
+		klog.Errorf("Failed to run: %v", err)
+		os.Exit(1)
+	}
+}
+
+func validateFlags() error {
+	if clusterClass == "" {
+		return errors.New("--cluster-class must be set")
+	}
+	if kubernetesVersion == "" {
+		return errors.New("--kubernetes-version must be set")
+	}
+	if clusterCount < 1 {
+		return errors.New("--clusters must be greater than zero")
+	}
+	if concurrency < 1 {
+		return errors.New("--concurrency must be greater than zero")
+	}
+	for _, op := range churnOperations {
+		switch op {
+		case churnScaleUp, churnScaleDown:
+		case churnUpgrade:
+			if upgradeKubernetesVersion == "" {
+				return errors.Errorf("--upgrade-kubernetes-version must be set when using the %s churn operation", churnUpgrade)
+			}
+		default:
+			return errors.Errorf("invalid churn operation %q", op)
+		}
+	}
+	return nil
+}
+
+func newClient() (client.Client, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeconfig
+	restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load kubeconfig")
+	}
+	restConfig.QPS = 50
+	restConfig.Burst = 100
+
+	return client.New(restConfig, client.Options{Scheme: scheme})
+}
+
+func run(ctx context.Context, c client.Client) error {
+	collector := newMetricsCollector(metricsURLs)
+	collectorDone := collector.Run(ctx, metricsInterval)
+
+	report := &Report{}
+
+	clusters, err := createClusters(ctx, c)
+	if err != nil {
+		return err
+	}
+	report.Clusters = len(clusters)
+
+	provisioningDurations, err := waitForClusters(ctx, c, clusters)
+	if err != nil {
+		return err
+	}
+	report.Provisioning = newLatencyStats(provisioningDurations)
+
+	if churnDuration > 0 {
+		report.ChurnOperations = churn(ctx, c, clusters)
+	}
+
+	collectorDone()
+	collector.AddToReport(report)
+
+	if err := writeReport(report); err != nil {
+		return err
+	}
+
+	if cleanup {
+		return deleteClusters(ctx, c, clusters)
+	}
+	return nil
+}
+
+func writeReport(report *Report) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal report")
+	}
+
+	if outputFile == "" {
+		fmt.Println(string(data))
+		return nil
+	}
+	if err := os.WriteFile(outputFile, data, 0600); err != nil {
+		return errors.Wrapf(err, "failed to write report to %s", outputFile)
+	}
+	klog.Infof("Report written to %s", outputFile)
+	return nil
+}
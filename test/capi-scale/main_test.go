@@ -0,0 +1,184 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/pointer"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func Test_applyChurnOperation(t *testing.T) {
+	kubernetesVersion = "v1.27.0"
+	upgradeKubernetesVersion = "v1.28.0"
+	maxWorkerReplicas = 2
+
+	tests := []struct {
+		name         string
+		version      string
+		replicas     int32
+		op           string
+		wantApplied  bool
+		wantVersion  string
+		wantReplicas int32
+	}{
+		{
+			name:         "upgrade",
+			version:      "v1.27.0",
+			replicas:     1,
+			op:           churnUpgrade,
+			wantApplied:  true,
+			wantVersion:  "v1.28.0",
+			wantReplicas: 1,
+		},
+		{
+			name:         "upgrade is not applied to already upgraded clusters",
+			version:      "v1.28.0",
+			replicas:     1,
+			op:           churnUpgrade,
+			wantApplied:  false,
+			wantVersion:  "v1.28.0",
+			wantReplicas: 1,
+		},
+		{
+			name:         "scale up",
+			version:      "v1.27.0",
+			replicas:     1,
+			op:           churnScaleUp,
+			wantApplied:  true,
+			wantVersion:  "v1.27.0",
+			wantReplicas: 2,
+		},
+		{
+			name:         "scale up is not applied above max replicas",
+			version:      "v1.27.0",
+			replicas:     2,
+			op:           churnScaleUp,
+			wantApplied:  false,
+			wantVersion:  "v1.27.0",
+			wantReplicas: 2,
+		},
+		{
+			name:         "scale down",
+			version:      "v1.27.0",
+			replicas:     1,
+			op:           churnScaleDown,
+			wantApplied:  true,
+			wantVersion:  "v1.27.0",
+			wantReplicas: 0,
+		},
+		{
+			name:         "scale down is not applied below zero replicas",
+			version:      "v1.27.0",
+			replicas:     0,
+			op:           churnScaleDown,
+			wantApplied:  false,
+			wantVersion:  "v1.27.0",
+			wantReplicas: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := newCluster(0)
+			cluster.Spec.Topology.Version = tt.version
+			cluster.Spec.Topology.Workers.MachineDeployments[0].Replicas = pointer.Int32(tt.replicas)
+
+			g.Expect(applyChurnOperation(cluster, tt.op)).To(Equal(tt.wantApplied))
+			g.Expect(cluster.Spec.Topology.Version).To(Equal(tt.wantVersion))
+			g.Expect(*cluster.Spec.Topology.Workers.MachineDeployments[0].Replicas).To(Equal(tt.wantReplicas))
+		})
+	}
+
+	t.Run("no-op for clusters without topology", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(applyChurnOperation(&clusterv1.Cluster{}, churnUpgrade)).To(BeFalse())
+	})
+}
+
+func Test_newLatencyStats(t *testing.T) {
+	g := NewWithT(t)
+
+	durations := []time.Duration{}
+	for i := 1; i <= 100; i++ {
+		durations = append(durations, time.Duration(i)*time.Second)
+	}
+
+	stats := newLatencyStats(durations)
+	g.Expect(stats.Average).To(Equal(50.5))
+	g.Expect(stats.P50).To(Equal(50.0))
+	g.Expect(stats.P99).To(Equal(99.0))
+	g.Expect(stats.Max).To(Equal(100.0))
+
+	g.Expect(newLatencyStats(nil)).To(Equal(LatencyStats{}))
+}
+
+func Test_metricsCollector(t *testing.T) {
+	g := NewWithT(t)
+
+	first := `# TYPE controller_runtime_reconcile_time_seconds histogram
+controller_runtime_reconcile_time_seconds_bucket{controller="cluster",le="0.1"} 10
+controller_runtime_reconcile_time_seconds_bucket{controller="cluster",le="1"} 10
+controller_runtime_reconcile_time_seconds_bucket{controller="cluster",le="+Inf"} 10
+controller_runtime_reconcile_time_seconds_sum{controller="cluster"} 0.5
+controller_runtime_reconcile_time_seconds_count{controller="cluster"} 10
+# TYPE workqueue_depth gauge
+workqueue_depth{name="cluster"} 2
+`
+	last := `# TYPE controller_runtime_reconcile_time_seconds histogram
+controller_runtime_reconcile_time_seconds_bucket{controller="cluster",le="0.1"} 20
+controller_runtime_reconcile_time_seconds_bucket{controller="cluster",le="1"} 30
+controller_runtime_reconcile_time_seconds_bucket{controller="cluster",le="+Inf"} 30
+controller_runtime_reconcile_time_seconds_sum{controller="cluster"} 10.5
+controller_runtime_reconcile_time_seconds_count{controller="cluster"} 30
+# TYPE workqueue_depth gauge
+workqueue_depth{name="cluster"} 4
+`
+
+	m := newMetricsCollector(nil)
+	for _, text := range []string{first, last} {
+		families, err := parseMetrics(strings.NewReader(text))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		s := &sample{
+			reconcileTime: map[string]histogram{},
+			queueDepth:    map[string]float64{},
+		}
+		addToSample(s, families)
+		m.addSample(s)
+	}
+
+	report := &Report{}
+	m.AddToReport(report)
+
+	// Only the 20 reconciles in between the first and the last sample are taken into account;
+	// 10 of them took less than 0.1s, the other 10 between 0.1s and 1s.
+	g.Expect(report.Reconcile).To(HaveKey("cluster"))
+	g.Expect(report.Reconcile["cluster"].Count).To(Equal(uint64(20)))
+	g.Expect(report.Reconcile["cluster"].Average).To(BeNumerically("~", 0.5, 0.0001))
+	g.Expect(report.Reconcile["cluster"].P50).To(BeNumerically("~", 0.1, 0.0001))
+	g.Expect(report.Reconcile["cluster"].P99).To(BeNumerically("~", 0.982, 0.0001))
+
+	g.Expect(report.QueueDepth).To(HaveKeyWithValue("cluster", QueueDepthStats{Average: 3, Max: 4}))
+}
@@ -0,0 +1,335 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"k8s.io/klog/v2"
+)
+
+const (
+	reconcileTimeMetric = "controller_runtime_reconcile_time_seconds"
+	queueDepthMetric    = "workqueue_depth"
+)
+
+// Report is the result of a capi-scale run.
+type Report struct {
+	// Clusters is the number of clusters created.
+	Clusters int `json:"clusters"`
+
+	// Provisioning is the distribution of the time required to provision the clusters, in seconds.
+	Provisioning LatencyStats `json:"provisioning"`
+
+	// ChurnOperations is the number of churn operations applied, by type.
+	ChurnOperations map[string]int `json:"churnOperations,omitempty"`
+
+	// Reconcile is the reconcile latency observed during the run, by controller.
+	Reconcile map[string]ReconcileStats `json:"reconcile,omitempty"`
+
+	// QueueDepth is the queue depth observed during the run, by controller.
+	QueueDepth map[string]QueueDepthStats `json:"queueDepth,omitempty"`
+}
+
+// LatencyStats describes a distribution of durations, in seconds.
+type LatencyStats struct {
+	Average float64 `json:"average"`
+	P50     float64 `json:"p50"`
+	P99     float64 `json:"p99"`
+	Max     float64 `json:"max"`
+}
+
+// ReconcileStats describes the reconcile latency of a controller, in seconds.
+type ReconcileStats struct {
+	Count   uint64  `json:"count"`
+	Average float64 `json:"average"`
+	P50     float64 `json:"p50"`
+	P99     float64 `json:"p99"`
+}
+
+// QueueDepthStats describes the queue depth of a controller.
+type QueueDepthStats struct {
+	Average float64 `json:"average"`
+	Max     float64 `json:"max"`
+}
+
+// newLatencyStats computes LatencyStats from a list of durations.
+func newLatencyStats(durations []time.Duration) LatencyStats {
+	if len(durations) == 0 {
+		return LatencyStats{}
+	}
+
+	seconds := make([]float64, 0, len(durations))
+	total := 0.0
+	for _, d := range durations {
+		seconds = append(seconds, d.Seconds())
+		total += d.Seconds()
+	}
+	sort.Float64s(seconds)
+
+	percentile := func(p float64) float64 {
+		return seconds[int(math.Ceil(p*float64(len(seconds))))-1]
+	}
+	return LatencyStats{
+		Average: total / float64(len(seconds)),
+		P50:     percentile(0.5),
+		P99:     percentile(0.99),
+		Max:     seconds[len(seconds)-1],
+	}
+}
+
+// histogram is a cumulative histogram, as exposed by Prometheus.
+type histogram struct {
+	count   uint64
+	sum     float64
+	buckets map[float64]uint64
+}
+
+// sub returns the difference between two histograms, i.e. the observations made in between.
+func (h histogram) sub(o histogram) histogram {
+	res := histogram{
+		count:   h.count - o.count,
+		sum:     h.sum - o.sum,
+		buckets: map[float64]uint64{},
+	}
+	for bound, count := range h.buckets {
+		res.buckets[bound] = count - o.buckets[bound]
+	}
+	return res
+}
+
+// quantile estimates a quantile from the histogram buckets, using linear interpolation
+// like the Prometheus histogram_quantile function.
+func (h histogram) quantile(q float64) float64 {
+	if h.count == 0 {
+		return 0
+	}
+
+	bounds := make([]float64, 0, len(h.buckets))
+	for bound := range h.buckets {
+		bounds = append(bounds, bound)
+	}
+	sort.Float64s(bounds)
+
+	rank := q * float64(h.count)
+	lowerBound, lowerCount := 0.0, uint64(0)
+	for _, bound := range bounds {
+		count := h.buckets[bound]
+		if float64(count) >= rank {
+			if math.IsInf(bound, +1) {
+				return lowerBound
+			}
+			if count == lowerCount {
+				return bound
+			}
+			return lowerBound + (bound-lowerBound)*(rank-float64(lowerCount))/float64(count-lowerCount)
+		}
+		lowerBound, lowerCount = bound, count
+	}
+	return lowerBound
+}
+
+// sample is the set of metrics collected at a point in time.
+type sample struct {
+	reconcileTime map[string]histogram
+	queueDepth    map[string]float64
+}
+
+// metricsCollector periodically collects metrics from the metrics endpoints of the controllers.
+type metricsCollector struct {
+	urls   []string
+	client *http.Client
+
+	lock   sync.Mutex
+	first  *sample
+	last   *sample
+	depths map[string][]float64
+}
+
+func newMetricsCollector(urls []string) *metricsCollector {
+	return &metricsCollector{
+		urls:   urls,
+		client: &http.Client{Timeout: 10 * time.Second},
+		depths: map[string][]float64{},
+	}
+}
+
+// Run collects metrics at the given interval until the returned func is called; the returned func
+// collects metrics one last time before returning.
+func (m *metricsCollector) Run(ctx context.Context, interval time.Duration) func() {
+	if len(m.urls) == 0 {
+		return func() {}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		m.collect(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				m.collect(ctx)
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		<-done
+		m.collect(context.Background())
+	}
+}
+
+// collect collects metrics from all the endpoints.
+func (m *metricsCollector) collect(ctx context.Context) {
+	s := &sample{
+		reconcileTime: map[string]histogram{},
+		queueDepth:    map[string]float64{},
+	}
+	for _, url := range m.urls {
+		families, err := m.scrape(ctx, url)
+		if err != nil {
+			klog.Errorf("Failed to collect metrics from %s: %v", url, err)
+			return
+		}
+		addToSample(s, families)
+	}
+	m.addSample(s)
+}
+
+func (m *metricsCollector) scrape(ctx context.Context, url string) (map[string]*dto.MetricFamily, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return parseMetrics(resp.Body)
+}
+
+func parseMetrics(r io.Reader) (map[string]*dto.MetricFamily, error) {
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse metrics")
+	}
+	return families, nil
+}
+
+// addToSample adds the reconcile time and queue depth metrics to a sample; metrics with the same
+// controller name coming from different endpoints are summed up.
+func addToSample(s *sample, families map[string]*dto.MetricFamily) {
+	if family, ok := families[reconcileTimeMetric]; ok {
+		for _, metric := range family.GetMetric() {
+			controller := labelValue(metric, "controller")
+			h, ok := s.reconcileTime[controller]
+			if !ok {
+				h = histogram{buckets: map[float64]uint64{}}
+			}
+			h.count += metric.GetHistogram().GetSampleCount()
+			h.sum += metric.GetHistogram().GetSampleSum()
+			for _, bucket := range metric.GetHistogram().GetBucket() {
+				h.buckets[bucket.GetUpperBound()] += bucket.GetCumulativeCount()
+			}
+			s.reconcileTime[controller] = h
+		}
+	}
+	if family, ok := families[queueDepthMetric]; ok {
+		for _, metric := range family.GetMetric() {
+			s.queueDepth[labelValue(metric, "name")] += metric.GetGauge().GetValue()
+		}
+	}
+}
+
+func labelValue(metric *dto.Metric, name string) string {
+	for _, label := range metric.GetLabel() {
+		if label.GetName() == name {
+			return label.GetValue()
+		}
+	}
+	return ""
+}
+
+func (m *metricsCollector) addSample(s *sample) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.first == nil {
+		m.first = s
+	}
+	m.last = s
+	for name, depth := range s.queueDepth {
+		m.depths[name] = append(m.depths[name], depth)
+	}
+}
+
+// AddToReport adds the reconcile latency and queue depth observed in between the first and the last sample to the report.
+func (m *metricsCollector) AddToReport(report *Report) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.first == nil || m.last == nil {
+		return
+	}
+
+	report.Reconcile = map[string]ReconcileStats{}
+	for controller, last := range m.last.reconcileTime {
+		h := last
+		if first, ok := m.first.reconcileTime[controller]; ok {
+			h = last.sub(first)
+		}
+		stats := ReconcileStats{
+			Count: h.count,
+			P50:   h.quantile(0.5),
+			P99:   h.quantile(0.99),
+		}
+		if h.count > 0 {
+			stats.Average = h.sum / float64(h.count)
+		}
+		report.Reconcile[controller] = stats
+	}
+
+	report.QueueDepth = map[string]QueueDepthStats{}
+	for name, depths := range m.depths {
+		stats := QueueDepthStats{}
+		for _, depth := range depths {
+			stats.Average += depth / float64(len(depths))
+			stats.Max = math.Max(stats.Max, depth)
+		}
+		report.QueueDepth[name] = stats
+	}
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package contract
+
+import (
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// ValidateConditions validates that the conditions of a provider object, if any, satisfy the Cluster API v1beta1
+// contract: each condition type is reported only once, the status is True, False or Unknown, the last transition
+// time is set, and the severity is set only for conditions which are not True.
+func ValidateConditions(obj *unstructured.Unstructured) error {
+	conditions, found, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if err != nil {
+		return errors.Wrap(err, "status.conditions must be a list")
+	}
+	if !found {
+		return nil
+	}
+
+	var allErrs []error
+	types := sets.Set[string]{}
+	for i, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			allErrs = append(allErrs, errors.Errorf("status.conditions[%d] must be an object", i))
+			continue
+		}
+		conditionType, _, _ := unstructured.NestedString(condition, "type")
+		status, _, _ := unstructured.NestedString(condition, "status")
+		severity, _, _ := unstructured.NestedString(condition, "severity")
+		lastTransitionTime, _, _ := unstructured.NestedString(condition, "lastTransitionTime")
+
+		if conditionType == "" {
+			allErrs = append(allErrs, errors.Errorf("status.conditions[%d].type must be set", i))
+		} else if types.Has(conditionType) {
+			allErrs = append(allErrs, errors.Errorf("condition %s must be reported only once", conditionType))
+		}
+		types.Insert(conditionType)
+
+		switch corev1.ConditionStatus(status) {
+		case corev1.ConditionTrue:
+			if severity != string(clusterv1.ConditionSeverityNone) {
+				allErrs = append(allErrs, errors.Errorf("condition %s must not have a severity when True", conditionType))
+			}
+		case corev1.ConditionFalse, corev1.ConditionUnknown:
+			switch clusterv1.ConditionSeverity(severity) {
+			case clusterv1.ConditionSeverityNone, clusterv1.ConditionSeverityError, clusterv1.ConditionSeverityWarning, clusterv1.ConditionSeverityInfo:
+			default:
+				allErrs = append(allErrs, errors.Errorf("condition %s has an invalid severity %q", conditionType, severity))
+			}
+		default:
+			allErrs = append(allErrs, errors.Errorf("condition %s must have status True, False or Unknown, got %q", conditionType, status))
+		}
+
+		if lastTransitionTime == "" {
+			allErrs = append(allErrs, errors.Errorf("condition %s must have lastTransitionTime set", conditionType))
+		}
+	}
+	return kerrors.NewAggregate(allErrs)
+}
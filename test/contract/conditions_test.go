@@ -0,0 +1,109 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package contract
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestValidateConditions(t *testing.T) {
+	condition := func(conditionType, status, severity, lastTransitionTime string) interface{} {
+		c := map[string]interface{}{"type": conditionType, "status": status}
+		if severity != "" {
+			c["severity"] = severity
+		}
+		if lastTransitionTime != "" {
+			c["lastTransitionTime"] = lastTransitionTime
+		}
+		return c
+	}
+	now := "2023-10-16T10:00:00Z"
+
+	tests := []struct {
+		name       string
+		conditions []interface{}
+		wantErrs   []string
+	}{
+		{
+			name:       "no conditions",
+			conditions: nil,
+		},
+		{
+			name: "valid conditions",
+			conditions: []interface{}{
+				condition("Ready", "False", "Warning", now),
+				condition("InfrastructureReady", "True", "", now),
+				condition("Provisioned", "Unknown", "", now),
+			},
+		},
+		{
+			name: "duplicated condition",
+			conditions: []interface{}{
+				condition("Ready", "True", "", now),
+				condition("Ready", "False", "Error", now),
+			},
+			wantErrs: []string{"condition Ready must be reported only once"},
+		},
+		{
+			name: "invalid status and severity",
+			conditions: []interface{}{
+				condition("Ready", "Yes", "", now),
+				condition("Provisioned", "True", "Error", now),
+				condition("Available", "False", "Fatal", now),
+			},
+			wantErrs: []string{
+				`condition Ready must have status True, False or Unknown, got "Yes"`,
+				"condition Provisioned must not have a severity when True",
+				`condition Available has an invalid severity "Fatal"`,
+			},
+		},
+		{
+			name: "missing type and lastTransitionTime",
+			conditions: []interface{}{
+				condition("", "True", "", now),
+				condition("Ready", "True", "", ""),
+			},
+			wantErrs: []string{
+				"status.conditions[0].type must be set",
+				"condition Ready must have lastTransitionTime set",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			if tt.conditions != nil {
+				g.Expect(unstructured.SetNestedSlice(obj.Object, tt.conditions, "status", "conditions")).To(Succeed())
+			}
+
+			err := ValidateConditions(obj)
+			if len(tt.wantErrs) == 0 {
+				g.Expect(err).ToNot(HaveOccurred())
+				return
+			}
+			g.Expect(err).To(HaveOccurred())
+			for _, wantErr := range tt.wantErrs {
+				g.Expect(err.Error()).To(ContainSubstring(wantErr))
+			}
+		})
+	}
+}
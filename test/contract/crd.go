@@ -0,0 +1,280 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package contract
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/utils/pointer"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/contract"
+)
+
+// Kind is the kind of provider object a contract applies to.
+type Kind string
+
+const (
+	// InfrastructureClusterKind is the kind of infrastructure cluster objects, e.g. DockerCluster.
+	InfrastructureClusterKind Kind = "InfrastructureCluster"
+
+	// InfrastructureClusterTemplateKind is the kind of infrastructure cluster template objects, e.g. DockerClusterTemplate.
+	InfrastructureClusterTemplateKind Kind = "InfrastructureClusterTemplate"
+
+	// InfrastructureMachineKind is the kind of infrastructure machine objects, e.g. DockerMachine.
+	InfrastructureMachineKind Kind = "InfrastructureMachine"
+
+	// InfrastructureMachineTemplateKind is the kind of infrastructure machine template objects, e.g. DockerMachineTemplate.
+	InfrastructureMachineTemplateKind Kind = "InfrastructureMachineTemplate"
+
+	// BootstrapConfigKind is the kind of bootstrap config objects, e.g. KubeadmConfig.
+	BootstrapConfigKind Kind = "BootstrapConfig"
+
+	// BootstrapConfigTemplateKind is the kind of bootstrap config template objects, e.g. KubeadmConfigTemplate.
+	BootstrapConfigTemplateKind Kind = "BootstrapConfigTemplate"
+
+	// ControlPlaneKind is the kind of control plane objects, e.g. KubeadmControlPlane.
+	ControlPlaneKind Kind = "ControlPlane"
+
+	// ControlPlaneTemplateKind is the kind of control plane template objects, e.g. KubeadmControlPlaneTemplate.
+	ControlPlaneTemplateKind Kind = "ControlPlaneTemplate"
+)
+
+// IsTemplate returns true if the kind is a template kind.
+func (k Kind) IsTemplate() bool {
+	return strings.HasSuffix(string(k), "Template")
+}
+
+// field is a field defined by the contract.
+type field struct {
+	path []string
+	// typ is the OpenAPI type of the field.
+	typ string
+}
+
+func (f field) String() string {
+	return strings.Join(f.path, ".")
+}
+
+var (
+	failureFields = []field{
+		{path: []string{"status", "failureReason"}, typ: "string"},
+		{path: []string{"status", "failureMessage"}, typ: "string"},
+	}
+
+	controlPlaneReplicasStatusFields = []field{
+		{path: []string{"status", "replicas"}, typ: "integer"},
+		{path: []string{"status", "readyReplicas"}, typ: "integer"},
+		{path: []string{"status", "updatedReplicas"}, typ: "integer"},
+		{path: []string{"status", "unavailableReplicas"}, typ: "integer"},
+		{path: []string{"status", "selector"}, typ: "string"},
+	}
+
+	templateFields = []field{
+		{path: []string{"spec", "template", "spec"}, typ: "object"},
+	}
+
+	// requiredFields are the fields each kind must define.
+	requiredFields = map[Kind][]field{
+		InfrastructureClusterKind: {
+			{path: []string{"spec", "controlPlaneEndpoint", "host"}, typ: "string"},
+			{path: []string{"spec", "controlPlaneEndpoint", "port"}, typ: "integer"},
+			{path: []string{"status", "ready"}, typ: "boolean"},
+		},
+		InfrastructureMachineKind: {
+			{path: []string{"spec", "providerID"}, typ: "string"},
+			{path: []string{"status", "ready"}, typ: "boolean"},
+		},
+		BootstrapConfigKind: {
+			{path: []string{"status", "ready"}, typ: "boolean"},
+			{path: []string{"status", "dataSecretName"}, typ: "string"},
+		},
+		ControlPlaneKind: {
+			{path: []string{"status", "initialized"}, typ: "boolean"},
+			{path: []string{"status", "ready"}, typ: "boolean"},
+		},
+		InfrastructureClusterTemplateKind: templateFields,
+		InfrastructureMachineTemplateKind: templateFields,
+		BootstrapConfigTemplateKind:       templateFields,
+		ControlPlaneTemplateKind:          templateFields,
+	}
+
+	// optionalFields are the fields each kind may define; if defined, they must have the expected type.
+	optionalFields = map[Kind][]field{
+		InfrastructureClusterKind: append([]field{
+			{path: []string{"status", "failureDomains"}, typ: "object"},
+			{path: []string{"status", "conditions"}, typ: "array"},
+		}, failureFields...),
+		InfrastructureMachineKind: append([]field{
+			{path: []string{"spec", "failureDomain"}, typ: "string"},
+			{path: []string{"status", "addresses"}, typ: "array"},
+			{path: []string{"status", "conditions"}, typ: "array"},
+		}, failureFields...),
+		BootstrapConfigKind: append([]field{
+			{path: []string{"status", "conditions"}, typ: "array"},
+		}, failureFields...),
+		ControlPlaneKind: append([]field{
+			{path: []string{"spec", "version"}, typ: "string"},
+			{path: []string{"spec", "controlPlaneEndpoint", "host"}, typ: "string"},
+			{path: []string{"spec", "controlPlaneEndpoint", "port"}, typ: "integer"},
+			{path: []string{"status", "failureDomains"}, typ: "object"},
+			{path: []string{"status", "conditions"}, typ: "array"},
+		}, append(failureFields, controlPlaneReplicasStatusFields...)...),
+	}
+)
+
+// ValidateCRD validates that a CRD satisfies the Cluster API v1beta1 contract for the given kind of provider object.
+// All the API versions of the CRD which are compatible with the v1beta1 contract are validated.
+func ValidateCRD(crd *apiextensionsv1.CustomResourceDefinition, kind Kind) error {
+	required, ok := requiredFields[kind]
+	if !ok {
+		return errors.Errorf("unknown kind %q", kind)
+	}
+
+	var allErrs []error
+	if name := contract.CalculateCRDName(crd.Spec.Group, crd.Spec.Names.Kind); crd.Name != name {
+		allErrs = append(allErrs, errors.Errorf("CRD name must be %q", name))
+	}
+	if crd.Spec.Scope != apiextensionsv1.NamespaceScoped {
+		allErrs = append(allErrs, errors.Errorf("CRD must be namespace-scoped"))
+	}
+
+	contractVersions, ok := crd.Labels[clusterv1.GroupVersion.String()]
+	if !ok || contractVersions == "" {
+		allErrs = append(allErrs, errors.Errorf("CRD must have the %q label listing the API versions compatible with the contract", clusterv1.GroupVersion.String()))
+		return kerrors.NewAggregate(allErrs)
+	}
+
+	for _, version := range strings.Split(contractVersions, "_") {
+		crdVersion := crdVersion(crd, version)
+		if crdVersion == nil {
+			allErrs = append(allErrs, errors.Errorf("API version %q in the %q label is not defined in the CRD", version, clusterv1.GroupVersion.String()))
+			continue
+		}
+		for _, err := range validateCRDVersion(crdVersion, kind, required) {
+			allErrs = append(allErrs, errors.Wrapf(err, "API version %q", version))
+		}
+	}
+	return kerrors.NewAggregate(allErrs)
+}
+
+func crdVersion(crd *apiextensionsv1.CustomResourceDefinition, name string) *apiextensionsv1.CustomResourceDefinitionVersion {
+	for i := range crd.Spec.Versions {
+		if crd.Spec.Versions[i].Name == name {
+			return &crd.Spec.Versions[i]
+		}
+	}
+	return nil
+}
+
+func validateCRDVersion(version *apiextensionsv1.CustomResourceDefinitionVersion, kind Kind, required []field) []error {
+	var allErrs []error
+	if !version.Served {
+		allErrs = append(allErrs, errors.New("must be served"))
+	}
+	if !kind.IsTemplate() && (version.Subresources == nil || version.Subresources.Status == nil) {
+		allErrs = append(allErrs, errors.New("must enable the status subresource"))
+	}
+	if version.Schema == nil || version.Schema.OpenAPIV3Schema == nil {
+		return append(allErrs, errors.New("must define an OpenAPI schema"))
+	}
+	schema := version.Schema.OpenAPIV3Schema
+
+	for _, f := range required {
+		if err := validateField(schema, f, true); err != nil {
+			allErrs = append(allErrs, err)
+		}
+	}
+	for _, f := range optionalFields[kind] {
+		if err := validateField(schema, f, false); err != nil {
+			allErrs = append(allErrs, err)
+		}
+	}
+
+	// Control planes using replicas must define the replicas status fields and the scale subresource.
+	if kind == ControlPlaneKind {
+		if _, found := lookupField(schema, []string{"spec", "replicas"}); found {
+			for _, f := range controlPlaneReplicasStatusFields {
+				if err := validateField(schema, f, true); err != nil {
+					allErrs = append(allErrs, errors.Wrap(err, "required when using spec.replicas"))
+				}
+			}
+			if err := validateScale(version.Subresources); err != nil {
+				allErrs = append(allErrs, err)
+			}
+		}
+	}
+	return allErrs
+}
+
+func validateField(schema *apiextensionsv1.JSONSchemaProps, f field, required bool) error {
+	prop, found := lookupField(schema, f.path)
+	if !found {
+		if required {
+			return errors.Errorf("field %s must be defined", f)
+		}
+		return nil
+	}
+	// The type of fields under x-kubernetes-preserve-unknown-fields can't be validated.
+	if prop == nil {
+		return nil
+	}
+	if prop.Type != f.typ {
+		return errors.Errorf("field %s must be of type %s, got %q", f, f.typ, prop.Type)
+	}
+	return nil
+}
+
+// lookupField returns the schema of the field at the given path, if any; the returned schema is nil if the
+// field is allowed by x-kubernetes-preserve-unknown-fields without being defined.
+func lookupField(schema *apiextensionsv1.JSONSchemaProps, path []string) (*apiextensionsv1.JSONSchemaProps, bool) {
+	current := schema
+	for _, p := range path {
+		prop, ok := current.Properties[p]
+		if !ok {
+			if current.XPreserveUnknownFields != nil && *current.XPreserveUnknownFields {
+				return nil, true
+			}
+			return nil, false
+		}
+		current = &prop
+	}
+	return current, true
+}
+
+func validateScale(subresources *apiextensionsv1.CustomResourceSubresources) error {
+	if subresources == nil || subresources.Scale == nil {
+		return errors.New("must enable the scale subresource when using spec.replicas")
+	}
+	scale := subresources.Scale
+	var allErrs []error
+	for _, p := range []struct {
+		name, got, want string
+	}{
+		{name: "specReplicasPath", got: scale.SpecReplicasPath, want: ".spec.replicas"},
+		{name: "statusReplicasPath", got: scale.StatusReplicasPath, want: ".status.replicas"},
+		{name: "labelSelectorPath", got: pointer.StringDeref(scale.LabelSelectorPath, ""), want: ".status.selector"},
+	} {
+		if p.got != p.want {
+			allErrs = append(allErrs, errors.Errorf("scale subresource %s must be %q, got %q", p.name, p.want, p.got))
+		}
+	}
+	return kerrors.NewAggregate(allErrs)
+}
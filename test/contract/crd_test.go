@@ -0,0 +1,176 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package contract
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/yaml"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestValidateCRD_ReferenceProviders(t *testing.T) {
+	tests := []struct {
+		file string
+		kind Kind
+	}{
+		{file: "infrastructure/docker/config/crd/bases/infrastructure.cluster.x-k8s.io_dockerclusters.yaml", kind: InfrastructureClusterKind},
+		{file: "infrastructure/docker/config/crd/bases/infrastructure.cluster.x-k8s.io_dockerclustertemplates.yaml", kind: InfrastructureClusterTemplateKind},
+		{file: "infrastructure/docker/config/crd/bases/infrastructure.cluster.x-k8s.io_dockermachines.yaml", kind: InfrastructureMachineKind},
+		{file: "infrastructure/docker/config/crd/bases/infrastructure.cluster.x-k8s.io_dockermachinetemplates.yaml", kind: InfrastructureMachineTemplateKind},
+		{file: "../bootstrap/kubeadm/config/crd/bases/bootstrap.cluster.x-k8s.io_kubeadmconfigs.yaml", kind: BootstrapConfigKind},
+		{file: "../bootstrap/kubeadm/config/crd/bases/bootstrap.cluster.x-k8s.io_kubeadmconfigtemplates.yaml", kind: BootstrapConfigTemplateKind},
+		{file: "../controlplane/kubeadm/config/crd/bases/controlplane.cluster.x-k8s.io_kubeadmcontrolplanes.yaml", kind: ControlPlaneKind},
+		{file: "../controlplane/kubeadm/config/crd/bases/controlplane.cluster.x-k8s.io_kubeadmcontrolplanetemplates.yaml", kind: ControlPlaneTemplateKind},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.kind), func(t *testing.T) {
+			g := NewWithT(t)
+
+			crd := loadCRD(g, tt.file)
+			g.Expect(ValidateCRD(crd, tt.kind)).To(Succeed())
+		})
+	}
+}
+
+func TestValidateCRD(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		kind    Kind
+		mutate  func(crd *apiextensionsv1.CustomResourceDefinition)
+		wantErr string
+	}{
+		{
+			name:    "unknown kind",
+			file:    "infrastructure/docker/config/crd/bases/infrastructure.cluster.x-k8s.io_dockerclusters.yaml",
+			kind:    Kind("Foo"),
+			mutate:  func(crd *apiextensionsv1.CustomResourceDefinition) {},
+			wantErr: `unknown kind "Foo"`,
+		},
+		{
+			name: "missing contract label",
+			file: "infrastructure/docker/config/crd/bases/infrastructure.cluster.x-k8s.io_dockerclusters.yaml",
+			kind: InfrastructureClusterKind,
+			mutate: func(crd *apiextensionsv1.CustomResourceDefinition) {
+				crd.Labels = nil
+			},
+			wantErr: `CRD must have the "cluster.x-k8s.io/v1beta1" label`,
+		},
+		{
+			name: "contract label with an undefined version",
+			file: "infrastructure/docker/config/crd/bases/infrastructure.cluster.x-k8s.io_dockerclusters.yaml",
+			kind: InfrastructureClusterKind,
+			mutate: func(crd *apiextensionsv1.CustomResourceDefinition) {
+				crd.Labels[clusterv1.GroupVersion.String()] = "v1beta1_v1beta2"
+			},
+			wantErr: `API version "v1beta2" in the "cluster.x-k8s.io/v1beta1" label is not defined in the CRD`,
+		},
+		{
+			name: "invalid name",
+			file: "infrastructure/docker/config/crd/bases/infrastructure.cluster.x-k8s.io_dockerclusters.yaml",
+			kind: InfrastructureClusterKind,
+			mutate: func(crd *apiextensionsv1.CustomResourceDefinition) {
+				crd.Name = "dockers.infrastructure.cluster.x-k8s.io"
+			},
+			wantErr: `CRD name must be "dockerclusters.infrastructure.cluster.x-k8s.io"`,
+		},
+		{
+			name: "cluster-scoped",
+			file: "infrastructure/docker/config/crd/bases/infrastructure.cluster.x-k8s.io_dockerclusters.yaml",
+			kind: InfrastructureClusterKind,
+			mutate: func(crd *apiextensionsv1.CustomResourceDefinition) {
+				crd.Spec.Scope = apiextensionsv1.ClusterScoped
+			},
+			wantErr: "CRD must be namespace-scoped",
+		},
+		{
+			name: "missing status subresource",
+			file: "infrastructure/docker/config/crd/bases/infrastructure.cluster.x-k8s.io_dockerclusters.yaml",
+			kind: InfrastructureClusterKind,
+			mutate: func(crd *apiextensionsv1.CustomResourceDefinition) {
+				crd.Spec.Versions[len(crd.Spec.Versions)-1].Subresources = nil
+			},
+			wantErr: `API version "v1beta1": must enable the status subresource`,
+		},
+		{
+			name: "missing required field",
+			file: "infrastructure/docker/config/crd/bases/infrastructure.cluster.x-k8s.io_dockerclusters.yaml",
+			kind: InfrastructureClusterKind,
+			mutate: func(crd *apiextensionsv1.CustomResourceDefinition) {
+				delete(crd.Spec.Versions[len(crd.Spec.Versions)-1].Schema.OpenAPIV3Schema.Properties["status"].Properties, "ready")
+			},
+			wantErr: `API version "v1beta1": field status.ready must be defined`,
+		},
+		{
+			name: "optional field with an invalid type",
+			file: "infrastructure/docker/config/crd/bases/infrastructure.cluster.x-k8s.io_dockermachines.yaml",
+			kind: InfrastructureMachineKind,
+			mutate: func(crd *apiextensionsv1.CustomResourceDefinition) {
+				crd.Spec.Versions[len(crd.Spec.Versions)-1].Schema.OpenAPIV3Schema.Properties["status"].Properties["addresses"] = apiextensionsv1.JSONSchemaProps{Type: "string"}
+			},
+			wantErr: `API version "v1beta1": field status.addresses must be of type array, got "string"`,
+		},
+		{
+			name: "control plane using replicas without the scale subresource",
+			file: "../controlplane/kubeadm/config/crd/bases/controlplane.cluster.x-k8s.io_kubeadmcontrolplanes.yaml",
+			kind: ControlPlaneKind,
+			mutate: func(crd *apiextensionsv1.CustomResourceDefinition) {
+				crd.Spec.Versions[len(crd.Spec.Versions)-1].Subresources.Scale = nil
+			},
+			wantErr: `API version "v1beta1": must enable the scale subresource when using spec.replicas`,
+		},
+		{
+			name: "template without spec.template.spec",
+			file: "infrastructure/docker/config/crd/bases/infrastructure.cluster.x-k8s.io_dockermachinetemplates.yaml",
+			kind: InfrastructureMachineTemplateKind,
+			mutate: func(crd *apiextensionsv1.CustomResourceDefinition) {
+				delete(crd.Spec.Versions[len(crd.Spec.Versions)-1].Schema.OpenAPIV3Schema.Properties["spec"].Properties["template"].Properties, "spec")
+			},
+			wantErr: `API version "v1beta1": field spec.template.spec must be defined`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			crd := loadCRD(g, tt.file)
+			tt.mutate(crd)
+
+			err := ValidateCRD(crd, tt.kind)
+			g.Expect(err).To(HaveOccurred())
+			g.Expect(err.Error()).To(ContainSubstring(tt.wantErr))
+		})
+	}
+}
+
+// loadCRD loads a CRD from a file relative to the test module, adding the contract label
+// which is otherwise set by kustomize.
+func loadCRD(g *WithT, file string) *apiextensionsv1.CustomResourceDefinition {
+	data, err := os.ReadFile(filepath.Join("..", file)) //nolint:gosec
+	g.Expect(err).ToNot(HaveOccurred())
+
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	g.Expect(yaml.Unmarshal(data, crd)).To(Succeed())
+	crd.Labels = map[string]string{clusterv1.GroupVersion.String(): "v1beta1"}
+	return crd
+}
@@ -0,0 +1,23 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package contract implements reusable test suites verifying that the objects of infrastructure, bootstrap and
+// control plane providers satisfy the Cluster API v1beta1 contract.
+//
+// The package can be imported by providers in their own test suites, e.g. to gate releases on conformance:
+// ValidateCRD checks the CRDs of the provider, while ProviderContractSpec runs Ginkgo specs verifying the provider
+// behavior on a management cluster, like pause handling, finalizers and conditions.
+package contract
@@ -0,0 +1,238 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package contract
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/test/framework"
+	. "sigs.k8s.io/cluster-api/test/framework/ginkgoextensions"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/contract"
+	"sigs.k8s.io/cluster-api/util/patch"
+)
+
+const defaultPauseObservationPeriod = 30 * time.Second
+
+var defaultIntervals = []interface{}{"5m", "5s"}
+
+// ProviderContractSpecInput is the input for ProviderContractSpec.
+type ProviderContractSpecInput struct {
+	// ClusterProxy is the proxy for the management cluster where the provider under test is installed.
+	ClusterProxy   framework.ClusterProxy
+	ArtifactFolder string
+	SkipCleanup    bool
+
+	// Kind is the kind of the provider object under test.
+	Kind Kind
+
+	// NewObject returns the provider object under test for the given Cluster, e.g. a DockerCluster.
+	// If the object has no owner references, the Cluster is set as its owner.
+	NewObject func(cluster *clusterv1.Cluster) *unstructured.Unstructured
+
+	// NewDependencies optionally returns the objects the provider requires to reconcile the object under test,
+	// e.g. the Machine owning an infrastructure machine. They are created before the object under test.
+	NewDependencies func(cluster *clusterv1.Cluster, obj *unstructured.Unstructured) []client.Object
+
+	// PauseObservationPeriod is the period the object under test must not be reconciled while its Cluster is paused.
+	// If not specified, 30 seconds are used.
+	PauseObservationPeriod time.Duration
+
+	// WaitForReconcileIntervals are the intervals to wait for the object under test to be reconciled.
+	// If not specified, the object is checked every 5 seconds for 5 minutes.
+	WaitForReconcileIntervals []interface{}
+
+	// WaitForDeleteIntervals are the intervals to wait for the object under test to be deleted.
+	// If not specified, the object is checked every 5 seconds for 5 minutes.
+	WaitForDeleteIntervals []interface{}
+}
+
+// ProviderContractSpec implements specs verifying that a provider object satisfies the Cluster API v1beta1 contract:
+//   - the CRD of the object defines the fields required by the contract, with the expected types.
+//   - the object is not reconciled while its Cluster is paused, and it is reconciled once the Cluster is unpaused;
+//     an object is considered reconciled when its finalizers or its status change.
+//   - the conditions of the object comply with the Cluster API conditions.
+//   - the finalizers of the object, if any, are removed on deletion.
+//
+// Behavior specs are skipped for template kinds, which are not reconciled.
+func ProviderContractSpec(ctx context.Context, inputGetter func() ProviderContractSpecInput) {
+	var (
+		specName      = "provider-contract"
+		input         ProviderContractSpecInput
+		namespace     *corev1.Namespace
+		cancelWatches context.CancelFunc
+		cluster       *clusterv1.Cluster
+	)
+
+	BeforeEach(func() {
+		Expect(ctx).NotTo(BeNil(), "ctx is required for %s spec", specName)
+		input = inputGetter()
+		Expect(input.ClusterProxy).ToNot(BeNil(), "Invalid argument. input.ClusterProxy can't be nil when calling %s spec", specName)
+		Expect(input.Kind).To(BeKeyOf(requiredFields), "Invalid argument. input.Kind must be a known kind when calling %s spec", specName)
+		Expect(input.NewObject).ToNot(BeNil(), "Invalid argument. input.NewObject can't be nil when calling %s spec", specName)
+		Expect(os.MkdirAll(input.ArtifactFolder, 0750)).To(Succeed(), "Invalid argument. input.ArtifactFolder can't be created for %s spec", specName)
+
+		if input.PauseObservationPeriod == 0 {
+			input.PauseObservationPeriod = defaultPauseObservationPeriod
+		}
+		if input.WaitForReconcileIntervals == nil {
+			input.WaitForReconcileIntervals = defaultIntervals
+		}
+		if input.WaitForDeleteIntervals == nil {
+			input.WaitForDeleteIntervals = defaultIntervals
+		}
+
+		Byf("Creating a namespace for hosting the %q test spec", specName)
+		namespace, cancelWatches = framework.CreateNamespaceAndWatchEvents(ctx, framework.CreateNamespaceAndWatchEventsInput{
+			Creator:   input.ClusterProxy.GetClient(),
+			ClientSet: input.ClusterProxy.GetClientSet(),
+			Name:      fmt.Sprintf("%s-%s", specName, util.RandomString(6)),
+			LogFolder: filepath.Join(input.ArtifactFolder, "clusters", input.ClusterProxy.GetName()),
+		})
+
+		cluster = &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("%s-%s", specName, util.RandomString(6)),
+				Namespace: namespace.Name,
+			},
+			Spec: clusterv1.ClusterSpec{
+				Paused: true,
+			},
+		}
+	})
+
+	It("Should have a CRD satisfying the contract", func() {
+		obj := input.NewObject(cluster)
+		gvk := obj.GroupVersionKind()
+
+		Byf("Validating the CRD for %s", gvk.Kind)
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		crdKey := client.ObjectKey{Name: contract.CalculateCRDName(gvk.Group, gvk.Kind)}
+		Expect(input.ClusterProxy.GetClient().Get(ctx, crdKey, crd)).To(Succeed(), "Failed to get CRD %s", crdKey.Name)
+		Expect(ValidateCRD(crd, input.Kind)).To(Succeed())
+	})
+
+	It("Should handle pause, conditions and finalizers according to the contract", func() {
+		if input.Kind.IsTemplate() {
+			Skip(fmt.Sprintf("%s objects are not reconciled", input.Kind))
+		}
+		c := input.ClusterProxy.GetClient()
+
+		Byf("Creating the paused Cluster %s", cluster.Name)
+		Expect(c.Create(ctx, cluster)).To(Succeed())
+
+		obj := input.NewObject(cluster)
+		obj.SetNamespace(namespace.Name)
+		labels := obj.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[clusterv1.ClusterNameLabel] = cluster.Name
+		obj.SetLabels(labels)
+		if len(obj.GetOwnerReferences()) == 0 {
+			obj.SetOwnerReferences([]metav1.OwnerReference{*metav1.NewControllerRef(cluster, clusterv1.GroupVersion.WithKind("Cluster"))})
+		}
+
+		if input.NewDependencies != nil {
+			for _, dependency := range input.NewDependencies(cluster, obj) {
+				Byf("Creating %s %s", dependency.GetObjectKind().GroupVersionKind().Kind, dependency.GetName())
+				Expect(c.Create(ctx, dependency)).To(Succeed())
+			}
+		}
+
+		Byf("Creating %s %s", obj.GetKind(), obj.GetName())
+		Expect(c.Create(ctx, obj)).To(Succeed())
+		pausedState := reconciledState(obj)
+
+		Byf("Verifying %s %s is not reconciled while the Cluster is paused", obj.GetKind(), obj.GetName())
+		Consistently(func(g Gomega) {
+			current := newEmptyObject(obj)
+			g.Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), current)).To(Succeed())
+			g.Expect(reconciledState(current)).To(Equal(pausedState), "%s must not be reconciled while the Cluster is paused", obj.GetKind())
+		}, input.PauseObservationPeriod, "5s").Should(Succeed())
+
+		Byf("Unpausing the Cluster %s", cluster.Name)
+		patchHelper, err := patch.NewHelper(cluster, c)
+		Expect(err).ToNot(HaveOccurred())
+		cluster.Spec.Paused = false
+		Expect(patchHelper.Patch(ctx, cluster)).To(Succeed())
+
+		Byf("Verifying %s %s is reconciled and reports valid conditions", obj.GetKind(), obj.GetName())
+		Eventually(func(g Gomega) {
+			current := newEmptyObject(obj)
+			g.Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), current)).To(Succeed())
+			g.Expect(reconciledState(current)).ToNot(Equal(pausedState), "%s must be reconciled once the Cluster is unpaused", obj.GetKind())
+			g.Expect(ValidateConditions(current)).To(Succeed())
+		}, input.WaitForReconcileIntervals...).Should(Succeed())
+
+		Byf("Deleting %s %s and verifying its finalizers are removed", obj.GetKind(), obj.GetName())
+		Expect(c.Delete(ctx, obj)).To(Succeed())
+		Eventually(func() bool {
+			err := c.Get(ctx, client.ObjectKeyFromObject(obj), newEmptyObject(obj))
+			return apierrors.IsNotFound(err)
+		}, input.WaitForDeleteIntervals...).Should(BeTrue(), "%s must be deleted, check the finalizers are removed", obj.GetKind())
+
+		By("PASSED!")
+	})
+
+	AfterEach(func() {
+		if !input.SkipCleanup {
+			Byf("Deleting cluster %s", cluster.Name)
+			framework.DeleteAllClustersAndWait(ctx, framework.DeleteAllClustersAndWaitInput{
+				Client:    input.ClusterProxy.GetClient(),
+				Namespace: namespace.Name,
+			}, input.WaitForDeleteIntervals...)
+
+			Byf("Deleting namespace used for hosting the %q test spec", specName)
+			framework.DeleteNamespace(ctx, framework.DeleteNamespaceInput{
+				Deleter: input.ClusterProxy.GetClient(),
+				Name:    namespace.Name,
+			})
+		}
+		cancelWatches()
+	})
+}
+
+func newEmptyObject(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	empty := &unstructured.Unstructured{}
+	empty.SetGroupVersionKind(obj.GroupVersionKind())
+	return empty
+}
+
+// reconciledState returns the parts of an object changed by the provider when reconciling it; other changes,
+// e.g. the pause annotations set by the Cluster controller, are ignored.
+func reconciledState(obj *unstructured.Unstructured) map[string]interface{} {
+	status, _, _ := unstructured.NestedFieldCopy(obj.Object, "status")
+	return map[string]interface{}{
+		"finalizers": obj.GetFinalizers(),
+		"status":     status,
+	}
+}
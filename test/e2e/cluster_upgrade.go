@@ -21,12 +21,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/utils/pointer"
 
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/test/framework"
 	"sigs.k8s.io/cluster-api/test/framework/clusterctl"
 	"sigs.k8s.io/cluster-api/test/framework/kubetest"
@@ -62,6 +64,10 @@ type ClusterUpgradeConformanceSpecInput struct {
 
 	// Flavor to use when creating the cluster for testing, "upgrades" is used if not specified.
 	Flavor *string
+
+	// ChaosActions returns the chaos actions to be run while the cluster is upgraded, e.g. framework.KillRandomControllerPod.
+	// If set, after the upgrade the spec waits for the cluster and its machines to converge.
+	ChaosActions func(managementClusterProxy framework.ClusterProxy, cluster *clusterv1.Cluster) []framework.ChaosAction
 }
 
 // ClusterUpgradeConformanceSpec implements a spec that upgrades a cluster and runs the Kubernetes conformance suite.
@@ -151,6 +157,17 @@ func ClusterUpgradeConformanceSpec(ctx context.Context, inputGetter func() Clust
 			WaitForMachinePools:          input.E2EConfig.GetIntervals(specName, "wait-machine-pool-nodes"),
 		}, clusterResources)
 
+		stopChaos := func() {}
+		if input.ChaosActions != nil {
+			By("Starting chaos actions")
+			stopChaos = framework.RunChaos(ctx, framework.RunChaosInput{
+				Actions:  input.ChaosActions(input.BootstrapClusterProxy, clusterResources.Cluster),
+				Interval: time.Minute,
+			})
+			// Ensure chaos is stopped even if the upgrade fails.
+			defer stopChaos()
+		}
+
 		if clusterResources.Cluster.Spec.Topology != nil {
 			// Cluster is using ClusterClass, upgrade via topology.
 			By("Upgrading the Cluster topology")
@@ -222,6 +239,16 @@ func ClusterUpgradeConformanceSpec(ctx context.Context, inputGetter func() Clust
 			})
 		}
 
+		if input.ChaosActions != nil {
+			By("Stopping chaos actions")
+			stopChaos()
+
+			framework.WaitForClusterToConverge(ctx, framework.WaitForClusterToConvergeInput{
+				GetLister: input.BootstrapClusterProxy.GetClient(),
+				Cluster:   clusterResources.Cluster,
+			}, input.E2EConfig.GetIntervals(specName, "wait-cluster")...)
+		}
+
 		By("Waiting until nodes are ready")
 		workloadProxy := input.BootstrapClusterProxy.GetWorkloadCluster(ctx, namespace.Name, clusterResources.Cluster.Name)
 		workloadClient := workloadProxy.GetClient()
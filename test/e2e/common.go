@@ -30,6 +30,8 @@ import (
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/test/framework"
+	"sigs.k8s.io/cluster-api/test/framework/bootstrap"
+	"sigs.k8s.io/cluster-api/test/framework/clusterctl"
 	"sigs.k8s.io/cluster-api/util"
 )
 
@@ -108,6 +110,15 @@ func dumpSpecResourcesAndCleanup(ctx context.Context, specName string, clusterPr
 	cancelWatches()
 }
 
+// getClusterInfrastructure returns the provider specific hooks to be used when turning workload clusters of the given
+// cluster into management clusters; if none is defined, the hooks for CAPD are used.
+func getClusterInfrastructure(clusterProxy framework.ClusterProxy, e2eConfig *clusterctl.E2EConfig) framework.ClusterInfrastructure {
+	if clusterInfrastructure := clusterProxy.GetClusterInfrastructure(); clusterInfrastructure != nil {
+		return clusterInfrastructure
+	}
+	return bootstrap.DockerClusterInfrastructure{Images: e2eConfig.Images}
+}
+
 // HaveValidVersion succeeds if version is a valid semver version.
 func HaveValidVersion(version string) types.GomegaMatcher {
 	return &validVersionMatcher{version: version}
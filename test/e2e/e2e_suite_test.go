@@ -30,6 +30,7 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/klog/v2"
@@ -167,7 +168,9 @@ var _ = SynchronizedBeforeSuite(func() []byte {
 	kubeconfigPath := parts[3]
 
 	e2eConfig = loadE2EConfig(configPath)
-	bootstrapClusterProxy = framework.NewClusterProxy("bootstrap", kubeconfigPath, initScheme(), framework.WithMachineLogCollector(framework.DockerLogCollector{}))
+	clusterLogCollector := framework.NewClusterLogCollectorRegistry(nil).
+		Register("docker", framework.DockerLogCollector{})
+	bootstrapClusterProxy = framework.NewClusterProxy("bootstrap", kubeconfigPath, initScheme(), framework.WithMachineLogCollector(clusterLogCollector))
 })
 
 // Using a SynchronizedAfterSuite for controlling how to delete resources shared across ParallelNodes (~ginkgo threads).
@@ -282,8 +285,12 @@ func dumpBootstrapClusterLogs(bootstrapClusterProxy framework.ClusterProxy) {
 			// we create a fake machine that wraps the node.
 			// NOTE: This assumes a naming convention between machines and nodes, which e.g. applies to the bootstrap clusters generated with kind.
 			//       This might not work if you are using an existing bootstrap cluster provided by other means.
+			// NOTE: The infrastructure kind is set so the log collector for docker is used, given that kind nodes are docker containers.
 			&clusterv1.Machine{
-				Spec:       clusterv1.MachineSpec{ClusterName: nodeName},
+				Spec: clusterv1.MachineSpec{
+					ClusterName:       nodeName,
+					InfrastructureRef: corev1.ObjectReference{Kind: "DockerMachine"},
+				},
 				ObjectMeta: metav1.ObjectMeta{Name: nodeName},
 			},
 			filepath.Join(artifactFolder, "clusters", bootstrapClusterProxy.GetName(), "machines", nodeName),
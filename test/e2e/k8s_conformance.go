@@ -25,12 +25,16 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/utils/pointer"
 
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/test/framework"
+	"sigs.k8s.io/cluster-api/test/framework/builder"
 	"sigs.k8s.io/cluster-api/test/framework/clusterctl"
 	"sigs.k8s.io/cluster-api/test/framework/kubetest"
 	"sigs.k8s.io/cluster-api/util"
+	utilyaml "sigs.k8s.io/cluster-api/util/yaml"
 )
 
 // K8SConformanceSpecInput is the input for K8SConformanceSpec.
@@ -50,6 +54,19 @@ type K8SConformanceSpecInput struct {
 
 	Flavor              string
 	ControlPlaneWaiters clusterctl.ControlPlaneWaiters
+
+	// ClusterClass, if specified, is the name of the ClusterClass used to create the workload cluster, e.g. quick-start;
+	// the ClusterClass is read from the clusterclass-<name>.yaml file in the repository of the infrastructure provider,
+	// and the Cluster is generated with a MachineDeployment for the first MachineDeployment class.
+	// When ClusterClass is specified, InfrastructureProvider must be specified and Flavor is ignored; if the e2e config
+	// defines the CNI variable, the CNI manifest is applied to the workload cluster.
+	ClusterClass *string
+
+	// ClusterNetwork is the ClusterNetwork of the Cluster generated when ClusterClass is specified.
+	ClusterNetwork *clusterv1.ClusterNetwork
+
+	// ClusterClassVariables are the values of the variables of the Cluster generated when ClusterClass is specified.
+	ClusterClassVariables map[string]interface{}
 }
 
 // K8SConformanceSpec implements a spec that creates a cluster and runs Kubernetes conformance suite.
@@ -78,6 +95,9 @@ func K8SConformanceSpec(ctx context.Context, inputGetter func() K8SConformanceSp
 		Expect(input.E2EConfig.Variables).To(HaveKey(kubetestConfigurationVariable), "% spec requires a %s variable to be defined in the config file", specName, kubetestConfigurationVariable)
 		kubetestConfigFilePath = input.E2EConfig.GetVariable(kubetestConfigurationVariable)
 		Expect(kubetestConfigFilePath).To(BeAnExistingFile(), "%s should be a valid kubetest config file")
+		if input.ClusterClass != nil {
+			Expect(input.InfrastructureProvider).ToNot(BeNil(), "Invalid argument. input.InfrastructureProvider can't be nil when calling %s spec with a ClusterClass", specName)
+		}
 
 		// Setup a Namespace where to host objects for this spec and create a watcher for the namespace events.
 		namespace, cancelWatches = setupSpecNamespace(ctx, specName, input.BootstrapClusterProxy, input.ArtifactFolder)
@@ -96,25 +116,30 @@ func K8SConformanceSpec(ctx context.Context, inputGetter func() K8SConformanceSp
 		// better parallelism of tests and thus a lower execution time.
 		var workerMachineCount int64 = 5
 
-		clusterctl.ApplyClusterTemplateAndWait(ctx, clusterctl.ApplyClusterTemplateAndWaitInput{
-			ClusterProxy: input.BootstrapClusterProxy,
-			ConfigCluster: clusterctl.ConfigClusterInput{
-				LogFolder:                filepath.Join(input.ArtifactFolder, "clusters", input.BootstrapClusterProxy.GetName()),
-				ClusterctlConfigPath:     input.ClusterctlConfigPath,
-				KubeconfigPath:           input.BootstrapClusterProxy.GetKubeconfigPath(),
-				InfrastructureProvider:   infrastructureProvider,
-				Flavor:                   input.Flavor,
-				Namespace:                namespace.Name,
-				ClusterName:              fmt.Sprintf("%s-%s", specName, util.RandomString(6)),
-				KubernetesVersion:        input.E2EConfig.GetVariable(KubernetesVersion),
-				ControlPlaneMachineCount: pointer.Int64(1),
-				WorkerMachineCount:       pointer.Int64(workerMachineCount),
-			},
-			ControlPlaneWaiters:          input.ControlPlaneWaiters,
-			WaitForClusterIntervals:      input.E2EConfig.GetIntervals(specName, "wait-cluster"),
-			WaitForControlPlaneIntervals: input.E2EConfig.GetIntervals(specName, "wait-control-plane"),
-			WaitForMachineDeployments:    input.E2EConfig.GetIntervals(specName, "wait-worker-nodes"),
-		}, clusterResources)
+		clusterName := fmt.Sprintf("%s-%s", specName, util.RandomString(6))
+		if input.ClusterClass != nil {
+			createClusterFromClusterClass(ctx, input, namespace.Name, clusterName, workerMachineCount, clusterResources)
+		} else {
+			clusterctl.ApplyClusterTemplateAndWait(ctx, clusterctl.ApplyClusterTemplateAndWaitInput{
+				ClusterProxy: input.BootstrapClusterProxy,
+				ConfigCluster: clusterctl.ConfigClusterInput{
+					LogFolder:                filepath.Join(input.ArtifactFolder, "clusters", input.BootstrapClusterProxy.GetName()),
+					ClusterctlConfigPath:     input.ClusterctlConfigPath,
+					KubeconfigPath:           input.BootstrapClusterProxy.GetKubeconfigPath(),
+					InfrastructureProvider:   infrastructureProvider,
+					Flavor:                   input.Flavor,
+					Namespace:                namespace.Name,
+					ClusterName:              clusterName,
+					KubernetesVersion:        input.E2EConfig.GetVariable(KubernetesVersion),
+					ControlPlaneMachineCount: pointer.Int64(1),
+					WorkerMachineCount:       pointer.Int64(workerMachineCount),
+				},
+				ControlPlaneWaiters:          input.ControlPlaneWaiters,
+				WaitForClusterIntervals:      input.E2EConfig.GetIntervals(specName, "wait-cluster"),
+				WaitForControlPlaneIntervals: input.E2EConfig.GetIntervals(specName, "wait-control-plane"),
+				WaitForMachineDeployments:    input.E2EConfig.GetIntervals(specName, "wait-worker-nodes"),
+			}, clusterResources)
+		}
 
 		workloadProxy := input.BootstrapClusterProxy.GetWorkloadCluster(ctx, namespace.Name, clusterResources.Cluster.Name)
 
@@ -139,3 +164,59 @@ func K8SConformanceSpec(ctx context.Context, inputGetter func() K8SConformanceSp
 		dumpSpecResourcesAndCleanup(ctx, specName, input.BootstrapClusterProxy, input.ArtifactFolder, namespace, cancelWatches, clusterResources.Cluster, input.E2EConfig.GetIntervals, input.SkipCleanup)
 	})
 }
+
+// createClusterFromClusterClass creates a workload cluster using the ClusterClass from the spec input, and waits for
+// it to be provisioned.
+func createClusterFromClusterClass(ctx context.Context, input K8SConformanceSpecInput, namespace, clusterName string, workerMachineCount int64, result *clusterctl.ApplyClusterTemplateAndWaitResult) {
+	const specName = "k8s-conformance"
+
+	logFolder := filepath.Join(input.ArtifactFolder, "clusters", input.BootstrapClusterProxy.GetName())
+	clusterClassYAML := clusterctl.GetClusterClass(ctx, clusterctl.GetClusterClassInput{
+		LogFolder:              logFolder,
+		ClusterctlConfigPath:   input.ClusterctlConfigPath,
+		InfrastructureProvider: *input.InfrastructureProvider,
+		ClusterClassName:       *input.ClusterClass,
+		Namespace:              namespace,
+	})
+
+	objs, err := utilyaml.ToUnstructured(clusterClassYAML)
+	Expect(err).ToNot(HaveOccurred(), "Failed to parse ClusterClass %s", *input.ClusterClass)
+	clusterClass := &clusterv1.ClusterClass{}
+	for i := range objs {
+		if objs[i].GroupVersionKind() == clusterv1.GroupVersion.WithKind("ClusterClass") {
+			Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(objs[i].Object, clusterClass)).To(Succeed())
+		}
+	}
+	Expect(clusterClass.Spec.Workers.MachineDeployments).ToNot(BeEmpty(), "ClusterClass %s must define a MachineDeployment class", *input.ClusterClass)
+
+	Byf("Generating Cluster %s for ClusterClass %s", clusterName, clusterClass.Name)
+	clusterBuilder := builder.ClusterFromClass(clusterClass, namespace, clusterName).
+		WithClusterNetwork(input.ClusterNetwork).
+		WithKubernetesVersion(input.E2EConfig.GetVariable(KubernetesVersion)).
+		WithControlPlaneReplicas(1).
+		WithMachineDeployment(clusterClass.Spec.Workers.MachineDeployments[0].Class, "md-0", int32(workerMachineCount))
+	for name, value := range input.ClusterClassVariables {
+		clusterBuilder.WithVariable(name, value)
+	}
+	cluster, err := clusterBuilder.Build()
+	Expect(err).ToNot(HaveOccurred(), "Failed to generate Cluster for ClusterClass %s", *input.ClusterClass)
+	clusterYAML, err := builder.Manifests(input.BootstrapClusterProxy.GetScheme(), cluster)
+	Expect(err).ToNot(HaveOccurred())
+
+	cniManifestPath := ""
+	if input.E2EConfig.HasVariable(CNIPath) {
+		cniManifestPath = input.E2EConfig.GetVariable(CNIPath)
+	}
+
+	clusterctl.ApplyCustomClusterTemplateAndWait(ctx, clusterctl.ApplyCustomClusterTemplateAndWaitInput{
+		ClusterProxy:                 input.BootstrapClusterProxy,
+		CustomTemplateYAML:           utilyaml.JoinYaml(clusterClassYAML, clusterYAML),
+		ClusterName:                  clusterName,
+		Namespace:                    namespace,
+		CNIManifestPath:              cniManifestPath,
+		ControlPlaneWaiters:          input.ControlPlaneWaiters,
+		WaitForClusterIntervals:      input.E2EConfig.GetIntervals(specName, "wait-cluster"),
+		WaitForControlPlaneIntervals: input.E2EConfig.GetIntervals(specName, "wait-control-plane"),
+		WaitForMachineDeployments:    input.E2EConfig.GetIntervals(specName, "wait-worker-nodes"),
+	}, (*clusterctl.ApplyCustomClusterTemplateAndWaitResult)(result))
+}
@@ -22,6 +22,8 @@ package e2e
 import (
 	. "github.com/onsi/ginkgo/v2"
 	"k8s.io/utils/pointer"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
 var _ = Describe("When testing K8S conformance [Conformance]", func() {
@@ -35,3 +37,22 @@ var _ = Describe("When testing K8S conformance [Conformance]", func() {
 			InfrastructureProvider: pointer.String("docker")}
 	})
 })
+
+var _ = Describe("When testing K8S conformance with ClusterClass [Conformance] [ClusterClass]", func() {
+	K8SConformanceSpec(ctx, func() K8SConformanceSpecInput {
+		return K8SConformanceSpecInput{
+			E2EConfig:              e2eConfig,
+			ClusterctlConfigPath:   clusterctlConfigPath,
+			BootstrapClusterProxy:  bootstrapClusterProxy,
+			ArtifactFolder:         artifactFolder,
+			SkipCleanup:            skipCleanup,
+			InfrastructureProvider: pointer.String("docker"),
+			ClusterClass:           pointer.String("quick-start"),
+			ClusterNetwork: &clusterv1.ClusterNetwork{
+				Services:      &clusterv1.NetworkRanges{CIDRBlocks: []string{e2eConfig.GetVariable("DOCKER_SERVICE_CIDRS")}},
+				Pods:          &clusterv1.NetworkRanges{CIDRBlocks: []string{e2eConfig.GetVariable("DOCKER_POD_CIDRS")}},
+				ServiceDomain: e2eConfig.GetVariable("DOCKER_SERVICE_DOMAIN"),
+			},
+		}
+	})
+})
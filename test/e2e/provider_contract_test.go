@@ -0,0 +1,74 @@
+//go:build e2e
+// +build e2e
+
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/test/contract"
+)
+
+var _ = Describe("When testing the provider contract of DockerCluster", func() {
+	contract.ProviderContractSpec(ctx, func() contract.ProviderContractSpecInput {
+		return contract.ProviderContractSpecInput{
+			ClusterProxy:   bootstrapClusterProxy,
+			ArtifactFolder: artifactFolder,
+			SkipCleanup:    skipCleanup,
+			Kind:           contract.InfrastructureClusterKind,
+			NewObject: func(cluster *clusterv1.Cluster) *unstructured.Unstructured {
+				return &unstructured.Unstructured{Object: map[string]interface{}{
+					"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+					"kind":       "DockerCluster",
+					"metadata": map[string]interface{}{
+						"name": cluster.Name,
+					},
+					"spec": map[string]interface{}{},
+				}}
+			},
+		}
+	})
+})
+
+var _ = Describe("When testing the provider contract of DockerMachineTemplate", func() {
+	contract.ProviderContractSpec(ctx, func() contract.ProviderContractSpecInput {
+		return contract.ProviderContractSpecInput{
+			ClusterProxy:   bootstrapClusterProxy,
+			ArtifactFolder: artifactFolder,
+			SkipCleanup:    skipCleanup,
+			Kind:           contract.InfrastructureMachineTemplateKind,
+			NewObject: func(cluster *clusterv1.Cluster) *unstructured.Unstructured {
+				return &unstructured.Unstructured{Object: map[string]interface{}{
+					"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+					"kind":       "DockerMachineTemplate",
+					"metadata": map[string]interface{}{
+						"name": cluster.Name,
+					},
+					"spec": map[string]interface{}{
+						"template": map[string]interface{}{
+							"spec": map[string]interface{}{},
+						},
+					},
+				}}
+			},
+		}
+	})
+})
@@ -21,7 +21,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -31,7 +30,6 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
-	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
 	"sigs.k8s.io/cluster-api/test/e2e/internal/log"
 	"sigs.k8s.io/cluster-api/test/framework"
 	"sigs.k8s.io/cluster-api/test/framework/clusterctl"
@@ -136,22 +134,14 @@ func SelfHostedSpec(ctx context.Context, inputGetter func() SelfHostedSpecInput)
 		By("Creating a workload cluster")
 
 		workloadClusterName := fmt.Sprintf("%s-%s", specName, util.RandomString(6))
-		clusterctlVariables := map[string]string{}
+		clusterInfrastructure := getClusterInfrastructure(input.BootstrapClusterProxy, input.E2EConfig)
 
-		// In case the infrastructure-docker provider is installed, ensure to add the preload images variable to load the
-		// controller images into the nodes.
-		// NOTE: we are checking the bootstrap cluster and assuming the workload cluster will be on the same infrastructure provider.
-		// Also, given that we use it to set a variable, then it is up to cluster templates to use it or not.
-		hasDockerInfrastructureProvider := hasProvider(ctx, input.BootstrapClusterProxy.GetClient(), "infrastructure-docker")
-
-		// In case the infrastructure-docker provider is installed, ensure to add the preload images variable to load the
-		// controller images into the nodes.
-		if hasDockerInfrastructureProvider {
-			images := []string{}
-			for _, image := range input.E2EConfig.Images {
-				images = append(images, fmt.Sprintf("%q", image.Name))
-			}
-			clusterctlVariables["DOCKER_PRELOAD_IMAGES"] = `[` + strings.Join(images, ",") + `]`
+		// Allow the infrastructure provider to add variables to the cluster template, e.g. to load the controller
+		// images into the nodes.
+		// NOTE: it is up to cluster templates to use those variables or not.
+		clusterctlVariables := map[string]string{}
+		for k, v := range clusterInfrastructure.GetClusterTemplateVariables(ctx, input.BootstrapClusterProxy.GetClient()) {
+			clusterctlVariables[k] = v
 		}
 
 		infrastructureProvider := clusterctl.DefaultInfrastructureProvider
@@ -434,17 +424,3 @@ func SelfHostedSpec(ctx context.Context, inputGetter func() SelfHostedSpecInput)
 		dumpSpecResourcesAndCleanup(ctx, specName, input.BootstrapClusterProxy, input.ArtifactFolder, namespace, cancelWatches, clusterResources.Cluster, input.E2EConfig.GetIntervals, input.SkipCleanup)
 	})
 }
-
-func hasProvider(ctx context.Context, c client.Client, providerName string) bool {
-	providerList := clusterctlv1.ProviderList{}
-	Eventually(func() error {
-		return c.List(ctx, &providerList)
-	}, "1m", "5s").Should(Succeed(), "Failed to list the Providers")
-
-	for _, provider := range providerList.Items {
-		if provider.GetName() == providerName {
-			return true
-		}
-	}
-	return false
-}
@@ -0,0 +1,81 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/test/framework"
+	"sigs.k8s.io/cluster-api/test/framework/clusterctl"
+)
+
+// DockerClusterInfrastructure implements framework.ClusterInfrastructure for CAPD.
+type DockerClusterInfrastructure struct {
+	// Images to be loaded into the nodes of workload clusters turned into management clusters.
+	Images []clusterctl.ContainerImage
+}
+
+var _ framework.ClusterInfrastructure = DockerClusterInfrastructure{}
+
+// GetClusterTemplateVariables returns the DOCKER_PRELOAD_IMAGES variable, which can be used by cluster templates
+// to load the controller images into the nodes, in case the infrastructure-docker provider is installed.
+// NOTE: we are checking the management cluster and assuming the workload cluster will be on the same infrastructure provider.
+func (d DockerClusterInfrastructure) GetClusterTemplateVariables(ctx context.Context, managementClusterClient client.Client) map[string]string {
+	providerList := &clusterctlv1.ProviderList{}
+	Eventually(func() error {
+		return managementClusterClient.List(ctx, providerList)
+	}, "1m", "5s").Should(Succeed(), "Failed to list the Providers")
+
+	hasDockerInfrastructureProvider := false
+	for _, provider := range providerList.Items {
+		if provider.GetName() == "infrastructure-docker" {
+			hasDockerInfrastructureProvider = true
+			break
+		}
+	}
+	if !hasDockerInfrastructureProvider {
+		return nil
+	}
+
+	images := []string{}
+	for _, image := range d.Images {
+		images = append(images, fmt.Sprintf("%q", image.Name))
+	}
+	return map[string]string{
+		"DOCKER_PRELOAD_IMAGES": `[` + strings.Join(images, ",") + `]`,
+	}
+}
+
+// PreloadImages loads the images into the nodes of the cluster, if the cluster is a DockerCluster.
+// NOTE: the images for official versions of the providers will be pulled from internet, but the latest images must be
+// built locally and loaded into kind.
+func (d DockerClusterInfrastructure) PreloadImages(ctx context.Context, _ client.Client, c *clusterv1.Cluster) error {
+	if c.Spec.InfrastructureRef == nil || c.Spec.InfrastructureRef.Kind != "DockerCluster" {
+		return nil
+	}
+	return LoadImagesToKindCluster(ctx, LoadImagesToKindClusterInput{
+		Name:   c.Name,
+		Images: d.Images,
+	})
+}
@@ -0,0 +1,258 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builder
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/pointer"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// ClusterBuilder builds a Cluster with a managed topology for a ClusterClass.
+type ClusterBuilder struct {
+	clusterClass       *clusterv1.ClusterClass
+	namespace          string
+	name               string
+	labels             map[string]string
+	annotations        map[string]string
+	network            *clusterv1.ClusterNetwork
+	version            string
+	controlPlane       clusterv1.ControlPlaneTopology
+	machineDeployments []clusterv1.MachineDeploymentTopology
+	machinePools       []clusterv1.MachinePoolTopology
+	variables          map[string]interface{}
+}
+
+// ClusterFromClass returns a ClusterBuilder for a Cluster with the given namespace and name, using the
+// given ClusterClass for its managed topology.
+func ClusterFromClass(clusterClass *clusterv1.ClusterClass, namespace, name string) *ClusterBuilder {
+	return &ClusterBuilder{
+		clusterClass: clusterClass,
+		namespace:    namespace,
+		name:         name,
+		variables:    map[string]interface{}{},
+	}
+}
+
+// WithLabels sets the labels of the Cluster.
+func (c *ClusterBuilder) WithLabels(labels map[string]string) *ClusterBuilder {
+	c.labels = labels
+	return c
+}
+
+// WithAnnotations sets the annotations of the Cluster.
+func (c *ClusterBuilder) WithAnnotations(annotations map[string]string) *ClusterBuilder {
+	c.annotations = annotations
+	return c
+}
+
+// WithClusterNetwork sets the ClusterNetwork of the Cluster.
+func (c *ClusterBuilder) WithClusterNetwork(network *clusterv1.ClusterNetwork) *ClusterBuilder {
+	c.network = network
+	return c
+}
+
+// WithKubernetesVersion sets the Kubernetes version of the Cluster.
+func (c *ClusterBuilder) WithKubernetesVersion(version string) *ClusterBuilder {
+	c.version = version
+	return c
+}
+
+// WithControlPlaneReplicas sets the number of replicas of the control plane.
+func (c *ClusterBuilder) WithControlPlaneReplicas(replicas int32) *ClusterBuilder {
+	c.controlPlane.Replicas = pointer.Int32(replicas)
+	return c
+}
+
+// WithMachineDeployment adds a MachineDeployment with the given class, name and replicas to the Cluster.
+func (c *ClusterBuilder) WithMachineDeployment(class, name string, replicas int32) *ClusterBuilder {
+	c.machineDeployments = append(c.machineDeployments, clusterv1.MachineDeploymentTopology{
+		Class:    class,
+		Name:     name,
+		Replicas: pointer.Int32(replicas),
+	})
+	return c
+}
+
+// WithMachineDeploymentForEachClass adds a MachineDeployment with the given replicas for each MachineDeployment
+// class of the ClusterClass; MachineDeployments are named md-<class>.
+func (c *ClusterBuilder) WithMachineDeploymentForEachClass(replicas int32) *ClusterBuilder {
+	if c.clusterClass == nil {
+		return c
+	}
+	for _, mdClass := range c.clusterClass.Spec.Workers.MachineDeployments {
+		c.WithMachineDeployment(mdClass.Class, fmt.Sprintf("md-%s", mdClass.Class), replicas)
+	}
+	return c
+}
+
+// WithMachinePool adds a MachinePool with the given class, name and replicas to the Cluster.
+func (c *ClusterBuilder) WithMachinePool(class, name string, replicas int32) *ClusterBuilder {
+	c.machinePools = append(c.machinePools, clusterv1.MachinePoolTopology{
+		Class:    class,
+		Name:     name,
+		Replicas: pointer.Int32(replicas),
+	})
+	return c
+}
+
+// WithMachinePoolForEachClass adds a MachinePool with the given replicas for each MachinePool class of the
+// ClusterClass; MachinePools are named mp-<class>.
+func (c *ClusterBuilder) WithMachinePoolForEachClass(replicas int32) *ClusterBuilder {
+	if c.clusterClass == nil {
+		return c
+	}
+	for _, mpClass := range c.clusterClass.Spec.Workers.MachinePools {
+		c.WithMachinePool(mpClass.Class, fmt.Sprintf("mp-%s", mpClass.Class), replicas)
+	}
+	return c
+}
+
+// WithVariable sets the value of a variable of the Cluster; the value is marshalled to JSON.
+func (c *ClusterBuilder) WithVariable(name string, value interface{}) *ClusterBuilder {
+	c.variables[name] = value
+	return c
+}
+
+// Build returns the Cluster.
+// Build fails if the ClusterClass doesn't define the MachineDeployment classes, the MachinePool classes or the
+// variables used by the Cluster, or if the Cluster doesn't set a value for a required variable without a default.
+func (c *ClusterBuilder) Build() (*clusterv1.Cluster, error) {
+	if c.clusterClass == nil {
+		return nil, errors.New("ClusterClass must be set")
+	}
+	if c.version == "" {
+		return nil, errors.New("Kubernetes version must be set")
+	}
+
+	var allErrs []error
+	mdClasses := sets.Set[string]{}
+	for _, mdClass := range c.clusterClass.Spec.Workers.MachineDeployments {
+		mdClasses.Insert(mdClass.Class)
+	}
+	mdNames := sets.Set[string]{}
+	for _, md := range c.machineDeployments {
+		if !mdClasses.Has(md.Class) {
+			allErrs = append(allErrs, errors.Errorf("MachineDeployment class %q is not defined in ClusterClass %s", md.Class, c.clusterClass.Name))
+		}
+		if mdNames.Has(md.Name) {
+			allErrs = append(allErrs, errors.Errorf("MachineDeployment name %q is used more than once", md.Name))
+		}
+		mdNames.Insert(md.Name)
+	}
+	mpClasses := sets.Set[string]{}
+	for _, mpClass := range c.clusterClass.Spec.Workers.MachinePools {
+		mpClasses.Insert(mpClass.Class)
+	}
+	mpNames := sets.Set[string]{}
+	for _, mp := range c.machinePools {
+		if !mpClasses.Has(mp.Class) {
+			allErrs = append(allErrs, errors.Errorf("MachinePool class %q is not defined in ClusterClass %s", mp.Class, c.clusterClass.Name))
+		}
+		if mpNames.Has(mp.Name) {
+			allErrs = append(allErrs, errors.Errorf("MachinePool name %q is used more than once", mp.Name))
+		}
+		mpNames.Insert(mp.Name)
+	}
+
+	variables, errs := c.buildVariables()
+	allErrs = append(allErrs, errs...)
+	if len(allErrs) > 0 {
+		return nil, kerrors.NewAggregate(allErrs)
+	}
+
+	cluster := &clusterv1.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "Cluster",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   c.namespace,
+			Name:        c.name,
+			Labels:      c.labels,
+			Annotations: c.annotations,
+		},
+		Spec: clusterv1.ClusterSpec{
+			ClusterNetwork: c.network,
+			Topology: &clusterv1.Topology{
+				Class:        c.clusterClass.Name,
+				Version:      c.version,
+				ControlPlane: c.controlPlane,
+				Variables:    variables,
+			},
+		},
+	}
+	if len(c.machineDeployments) > 0 || len(c.machinePools) > 0 {
+		cluster.Spec.Topology.Workers = &clusterv1.WorkersTopology{
+			MachineDeployments: c.machineDeployments,
+			MachinePools:       c.machinePools,
+		}
+	}
+	return cluster, nil
+}
+
+func (c *ClusterBuilder) buildVariables() ([]clusterv1.ClusterVariable, []error) {
+	var allErrs []error
+	definitions := map[string]clusterv1.ClusterClassVariable{}
+	for _, variable := range c.clusterClass.Spec.Variables {
+		definitions[variable.Name] = variable
+	}
+
+	names := make([]string, 0, len(c.variables))
+	for name := range c.variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	variables := []clusterv1.ClusterVariable{}
+	for _, name := range names {
+		if _, ok := definitions[name]; !ok {
+			allErrs = append(allErrs, errors.Errorf("variable %q is not defined in ClusterClass %s", name, c.clusterClass.Name))
+			continue
+		}
+		value, err := json.Marshal(c.variables[name])
+		if err != nil {
+			allErrs = append(allErrs, errors.Wrapf(err, "failed to marshal the value of variable %q", name))
+			continue
+		}
+		variables = append(variables, clusterv1.ClusterVariable{
+			Name:  name,
+			Value: apiextensionsv1.JSON{Raw: value},
+		})
+	}
+
+	// Required variables without a default must be set, while the other variables are defaulted by the
+	// Cluster webhook.
+	for _, definition := range c.clusterClass.Spec.Variables {
+		if _, ok := c.variables[definition.Name]; ok {
+			continue
+		}
+		if definition.Required && definition.Schema.OpenAPIV3Schema.Default == nil {
+			allErrs = append(allErrs, errors.Errorf("variable %q is required by ClusterClass %s and has no default", definition.Name, c.clusterClass.Name))
+		}
+	}
+	return variables, allErrs
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builder
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	utilyaml "sigs.k8s.io/cluster-api/util/yaml"
+)
+
+func TestClusterBuilder(t *testing.T) {
+	clusterClass := &clusterv1.ClusterClass{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "quick-start"},
+		Spec: clusterv1.ClusterClassSpec{
+			Workers: clusterv1.WorkersClass{
+				MachineDeployments: []clusterv1.MachineDeploymentClass{
+					{Class: "default-worker"},
+					{Class: "large-worker"},
+				},
+				MachinePools: []clusterv1.MachinePoolClass{
+					{Class: "default-pool"},
+				},
+			},
+			Variables: []clusterv1.ClusterClassVariable{
+				{
+					Name:     "imageRepository",
+					Required: true,
+					Schema: clusterv1.VariableSchema{OpenAPIV3Schema: clusterv1.JSONSchemaProps{
+						Type:    "string",
+						Default: &apiextensionsv1.JSON{Raw: []byte(`"registry.k8s.io"`)},
+					}},
+				},
+				{
+					Name:     "lbImage",
+					Required: true,
+					Schema:   clusterv1.VariableSchema{OpenAPIV3Schema: clusterv1.JSONSchemaProps{Type: "object"}},
+				},
+				{
+					Name:   "podSecurityStandard",
+					Schema: clusterv1.VariableSchema{OpenAPIV3Schema: clusterv1.JSONSchemaProps{Type: "object"}},
+				},
+			},
+		},
+	}
+
+	t.Run("builds a Cluster for the ClusterClass", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cluster, err := ClusterFromClass(clusterClass, "ns1", "cluster1").
+			WithLabels(map[string]string{"cni": "kindnet"}).
+			WithKubernetesVersion("v1.28.0").
+			WithControlPlaneReplicas(3).
+			WithMachineDeploymentForEachClass(1).
+			WithMachinePoolForEachClass(2).
+			WithVariable("lbImage", map[string]string{"repository": "kindest", "tag": "v20230510"}).
+			Build()
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(cluster.Namespace).To(Equal("ns1"))
+		g.Expect(cluster.Name).To(Equal("cluster1"))
+		g.Expect(cluster.Labels).To(Equal(map[string]string{"cni": "kindnet"}))
+		g.Expect(cluster.Spec.Topology).To(Equal(&clusterv1.Topology{
+			Class:   "quick-start",
+			Version: "v1.28.0",
+			ControlPlane: clusterv1.ControlPlaneTopology{
+				Replicas: pointer.Int32(3),
+			},
+			Workers: &clusterv1.WorkersTopology{
+				MachineDeployments: []clusterv1.MachineDeploymentTopology{
+					{Class: "default-worker", Name: "md-default-worker", Replicas: pointer.Int32(1)},
+					{Class: "large-worker", Name: "md-large-worker", Replicas: pointer.Int32(1)},
+				},
+				MachinePools: []clusterv1.MachinePoolTopology{
+					{Class: "default-pool", Name: "mp-default-pool", Replicas: pointer.Int32(2)},
+				},
+			},
+			Variables: []clusterv1.ClusterVariable{
+				{Name: "lbImage", Value: apiextensionsv1.JSON{Raw: []byte(`{"repository":"kindest","tag":"v20230510"}`)}},
+			},
+		}))
+	})
+
+	t.Run("fails for invalid classes and variables", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := ClusterFromClass(clusterClass, "ns1", "cluster1").
+			WithKubernetesVersion("v1.28.0").
+			WithMachineDeployment("default-worker", "md-0", 1).
+			WithMachineDeployment("gpu-worker", "md-0", 1).
+			WithMachinePool("default-pool", "mp-0", 1).
+			WithMachinePool("gpu-pool", "mp-0", 1).
+			WithVariable("foo", "bar").
+			Build()
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring(`MachineDeployment class "gpu-worker" is not defined in ClusterClass quick-start`))
+		g.Expect(err.Error()).To(ContainSubstring(`MachineDeployment name "md-0" is used more than once`))
+		g.Expect(err.Error()).To(ContainSubstring(`MachinePool class "gpu-pool" is not defined in ClusterClass quick-start`))
+		g.Expect(err.Error()).To(ContainSubstring(`MachinePool name "mp-0" is used more than once`))
+		g.Expect(err.Error()).To(ContainSubstring(`variable "foo" is not defined in ClusterClass quick-start`))
+		g.Expect(err.Error()).To(ContainSubstring(`variable "lbImage" is required by ClusterClass quick-start and has no default`))
+		g.Expect(err.Error()).ToNot(ContainSubstring("imageRepository"))
+	})
+
+	t.Run("fails without a Kubernetes version", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := ClusterFromClass(clusterClass, "ns1", "cluster1").Build()
+		g.Expect(err).To(HaveOccurred())
+	})
+}
+
+func TestManifests(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "cluster1", ResourceVersion: "1"},
+		Spec: clusterv1.ClusterSpec{
+			Topology: &clusterv1.Topology{Class: "quick-start", Version: "v1.28.0"},
+		},
+		Status: clusterv1.ClusterStatus{Phase: "Provisioned"},
+	}
+	clusterClass := &clusterv1.ClusterClass{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "quick-start"},
+	}
+
+	got, err := Manifests(scheme, clusterClass, cluster)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	objs, err := utilyaml.ToUnstructured(got)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(objs).To(HaveLen(2))
+	g.Expect(objs[0].GroupVersionKind()).To(Equal(clusterv1.GroupVersion.WithKind("ClusterClass")))
+	g.Expect(objs[1].GroupVersionKind()).To(Equal(clusterv1.GroupVersion.WithKind("Cluster")))
+	g.Expect(objs[1].GetName()).To(Equal("cluster1"))
+	g.Expect(objs[1].GetResourceVersion()).To(BeEmpty())
+	g.Expect(objs[1].Object).ToNot(HaveKey("status"))
+	class, _, err := unstructured.NestedString(objs[1].Object, "spec", "topology", "class")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(class).To(Equal("quick-start"))
+}
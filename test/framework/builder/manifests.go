@@ -0,0 +1,54 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builder
+
+import (
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	utilyaml "sigs.k8s.io/cluster-api/util/yaml"
+)
+
+// Manifests returns the YAML manifests of the given objects, e.g. to be applied with
+// clusterctl.ApplyCustomClusterTemplateAndWait; the scheme is used to set the apiVersion and kind of
+// typed objects.
+func Manifests(scheme *runtime.Scheme, objs ...client.Object) ([]byte, error) {
+	us := make([]unstructured.Unstructured, 0, len(objs))
+	for _, obj := range objs {
+		gvk, err := apiutil.GVKForObject(obj, scheme)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get the GroupVersionKind of %s", obj.GetName())
+		}
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to convert %s %s to unstructured", gvk.Kind, obj.GetName())
+		}
+		u := unstructured.Unstructured{Object: content}
+		u.SetGroupVersionKind(gvk)
+		// Drop fields set by the API server, so the manifests can be applied to any cluster.
+		unstructured.RemoveNestedField(u.Object, "metadata", "creationTimestamp")
+		unstructured.RemoveNestedField(u.Object, "metadata", "resourceVersion")
+		unstructured.RemoveNestedField(u.Object, "metadata", "uid")
+		unstructured.RemoveNestedField(u.Object, "metadata", "managedFields")
+		unstructured.RemoveNestedField(u.Object, "status")
+		us = append(us, u)
+	}
+	return utilyaml.FromUnstructured(us)
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	. "sigs.k8s.io/cluster-api/test/framework/ginkgoextensions"
+	"sigs.k8s.io/cluster-api/test/framework/internal/log"
+	"sigs.k8s.io/cluster-api/test/infrastructure/container"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// ChaosAction is a disruptive action to be run while a spec is running, e.g. killing a controller pod.
+type ChaosAction struct {
+	// Name of the action, used for logging.
+	Name string
+
+	// Run runs the action.
+	Run func(ctx context.Context) error
+}
+
+// KillRandomControllerPod returns a ChaosAction that deletes a random pod of the providers installed in the cluster.
+func KillRandomControllerPod(clusterProxy ClusterProxy) ChaosAction {
+	return ChaosAction{
+		Name: "kill-random-controller-pod",
+		Run: func(ctx context.Context) error {
+			podList := &corev1.PodList{}
+			if err := clusterProxy.GetClient().List(ctx, podList, client.HasLabels{clusterv1.ProviderNameLabel}); err != nil {
+				return errors.Wrap(err, "failed to list controller pods")
+			}
+			if len(podList.Items) == 0 {
+				return errors.New("no controller pods found")
+			}
+
+			pod := &podList.Items[rand.Intn(len(podList.Items))] //nolint:gosec // Intentionally using a weak random number generator here.
+			log.Logf("Chaos: deleting controller pod %s", klog.KObj(pod))
+			return clusterProxy.GetClient().Delete(ctx, pod, client.GracePeriodSeconds(0))
+		},
+	}
+}
+
+// RestartKindAPIServer returns a ChaosAction that restarts the API server of a kind cluster, e.g. the bootstrap
+// cluster; the API server container is stopped and then restarted by the kubelet.
+func RestartKindAPIServer(kindClusterName string) ChaosAction {
+	return ChaosAction{
+		Name: "restart-apiserver",
+		Run: func(ctx context.Context) error {
+			containerRuntime, err := container.NewRuntimeClient()
+			if err != nil {
+				return err
+			}
+
+			controlPlaneContainerName := fmt.Sprintf("%s-control-plane", kindClusterName)
+			log.Logf("Chaos: restarting the API server in %s", controlPlaneContainerName)
+			return containerRuntime.ExecContainer(ctx, controlPlaneContainerName, &container.ExecContainerInput{},
+				"sh", "-c", "crictl ps --name kube-apiserver -q | xargs -r crictl stop")
+		},
+	}
+}
+
+// PauseRandomDockerContainer returns a ChaosAction that pauses a random container of a CAPD cluster for the
+// given duration, and then unpauses it.
+func PauseRandomDockerContainer(cluster *clusterv1.Cluster, duration time.Duration) ChaosAction {
+	return ChaosAction{
+		Name: "pause-docker-container",
+		Run: func(ctx context.Context) error {
+			containerRuntime, err := container.NewRuntimeClient()
+			if err != nil {
+				return err
+			}
+
+			filters := container.FilterBuilder{}
+			filters.AddKeyNameValue("label", "io.x-k8s.kind.cluster", cluster.Name)
+			containers, err := containerRuntime.ListContainers(ctx, filters)
+			if err != nil {
+				return err
+			}
+			if len(containers) == 0 {
+				return errors.Errorf("no containers found for Cluster %s", klog.KObj(cluster))
+			}
+
+			containerName := containers[rand.Intn(len(containers))].Name //nolint:gosec // Intentionally using a weak random number generator here.
+			log.Logf("Chaos: pausing container %s for %s", containerName, duration)
+			if err := containerRuntime.PauseContainer(ctx, containerName); err != nil {
+				return err
+			}
+
+			// NOTE: the container is unpaused even if the context is cancelled in the meantime.
+			select {
+			case <-ctx.Done():
+			case <-time.After(duration):
+			}
+			return containerRuntime.UnpauseContainer(context.Background(), containerName)
+		},
+	}
+}
+
+// RunChaosInput is the input for RunChaos.
+type RunChaosInput struct {
+	// Actions to choose from.
+	Actions []ChaosAction
+
+	// Interval between actions.
+	Interval time.Duration
+}
+
+// RunChaos runs a random chaos action at every interval until the returned function is called; the returned
+// function waits for the running action, if any, to complete.
+// Chaos is best effort, so failures of the actions are logged but do not fail the spec.
+func RunChaos(ctx context.Context, input RunChaosInput) func() {
+	Expect(ctx).NotTo(BeNil(), "ctx is required for RunChaos")
+	Expect(input.Actions).ToNot(BeEmpty(), "Invalid argument. input.Actions can't be empty when calling RunChaos")
+	Expect(input.Interval).To(BeNumerically(">", 0), "Invalid argument. input.Interval must be greater than zero when calling RunChaos")
+
+	ctx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer GinkgoRecover()
+		defer wg.Done()
+
+		ticker := time.NewTicker(input.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				action := input.Actions[rand.Intn(len(input.Actions))] //nolint:gosec // Intentionally using a weak random number generator here.
+				if err := action.Run(ctx); err != nil {
+					log.Logf("Chaos: failed to run %s: %v", action.Name, err)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		wg.Wait()
+	}
+}
+
+// WaitForClusterToConvergeInput is the input for WaitForClusterToConverge.
+type WaitForClusterToConvergeInput struct {
+	GetLister GetLister
+	Cluster   *clusterv1.Cluster
+}
+
+// WaitForClusterToConverge waits for a cluster and all its machines to be ready, e.g. after running chaos actions.
+func WaitForClusterToConverge(ctx context.Context, input WaitForClusterToConvergeInput, intervals ...interface{}) {
+	Expect(ctx).NotTo(BeNil(), "ctx is required for WaitForClusterToConverge")
+	Expect(input.GetLister).ToNot(BeNil(), "Invalid argument. input.GetLister can't be nil when calling WaitForClusterToConverge")
+	Expect(input.Cluster).ToNot(BeNil(), "Invalid argument. input.Cluster can't be nil when calling WaitForClusterToConverge")
+
+	Byf("Waiting for Cluster %s to converge", klog.KObj(input.Cluster))
+	Eventually(func() error {
+		cluster := &clusterv1.Cluster{}
+		if err := input.GetLister.Get(ctx, client.ObjectKeyFromObject(input.Cluster), cluster); err != nil {
+			return err
+		}
+		if !conditions.IsTrue(cluster, clusterv1.ReadyCondition) {
+			return errors.Errorf("Cluster %s is not ready", klog.KObj(cluster))
+		}
+
+		machineList := &clusterv1.MachineList{}
+		if err := input.GetLister.List(ctx, machineList, byClusterOptions(cluster.Name, cluster.Namespace)...); err != nil {
+			return err
+		}
+		for i := range machineList.Items {
+			machine := &machineList.Items[i]
+			if machine.Status.NodeRef == nil || !conditions.IsTrue(machine, clusterv1.ReadyCondition) {
+				return errors.Errorf("Machine %s is not ready", klog.KObj(machine))
+			}
+		}
+		return nil
+	}, intervals...).Should(Succeed(), "Timed out waiting for Cluster %s to converge", klog.KObj(input.Cluster))
+}
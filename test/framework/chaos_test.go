@@ -0,0 +1,61 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+func TestRunChaos(t *testing.T) {
+	RegisterTestingT(t)
+
+	var runs, failures int32
+	stop := RunChaos(context.Background(), RunChaosInput{
+		Actions: []ChaosAction{
+			{
+				Name: "succeeding",
+				Run: func(_ context.Context) error {
+					atomic.AddInt32(&runs, 1)
+					return nil
+				},
+			},
+			{
+				Name: "failing",
+				Run: func(_ context.Context) error {
+					atomic.AddInt32(&failures, 1)
+					return errors.New("failed")
+				},
+			},
+		},
+		Interval: 10 * time.Millisecond,
+	})
+
+	// Failing actions do not stop chaos.
+	Eventually(func() int32 { return atomic.LoadInt32(&runs) }, time.Second).Should(BeNumerically(">=", 3))
+	Eventually(func() int32 { return atomic.LoadInt32(&failures) }, time.Second).Should(BeNumerically(">=", 1))
+
+	// No actions are run once chaos is stopped.
+	stop()
+	stopped := atomic.LoadInt32(&runs) + atomic.LoadInt32(&failures)
+	Consistently(func() int32 { return atomic.LoadInt32(&runs) + atomic.LoadInt32(&failures) }, 100*time.Millisecond).Should(Equal(stopped))
+}
@@ -87,6 +87,10 @@ type ClusterProxy interface {
 	// GetLogCollector returns the machine log collector for the Kubernetes cluster.
 	GetLogCollector() ClusterLogCollector
 
+	// GetClusterInfrastructure returns the provider specific hooks used by specs turning workload clusters
+	// into management clusters, if any.
+	GetClusterInfrastructure() ClusterInfrastructure
+
 	// Apply to apply YAML to the Kubernetes cluster, `kubectl apply`.
 	Apply(ctx context.Context, resources []byte, args ...string) error
 
@@ -111,6 +115,19 @@ type ClusterLogCollector interface {
 	CollectInfrastructureLogs(ctx context.Context, managementClusterClient client.Client, c *clusterv1.Cluster, outputPath string) error
 }
 
+// ClusterInfrastructure defines provider specific hooks used by specs that turn a workload cluster into a management
+// cluster, like the self-hosted and the clusterctl upgrade specs; this allows those specs to be reused with
+// infrastructure providers other than CAPD.
+type ClusterInfrastructure interface {
+	// GetClusterTemplateVariables returns additional variables to be used when generating the cluster template
+	// for a workload cluster that will be turned into a management cluster, e.g. the list of images to preload.
+	// NOTE: it is up to the cluster templates to use those variables or not.
+	GetClusterTemplateVariables(ctx context.Context, managementClusterClient client.Client) map[string]string
+	// PreloadImages ensures the images required to run the providers are available on the nodes of a workload cluster
+	// that will be turned into a management cluster, e.g. by loading images built locally.
+	PreloadImages(ctx context.Context, managementClusterClient client.Client, c *clusterv1.Cluster) error
+}
+
 // Option is a configuration option supplied to NewClusterProxy.
 type Option func(*clusterProxy)
 
@@ -121,6 +138,14 @@ func WithMachineLogCollector(logCollector ClusterLogCollector) Option {
 	}
 }
 
+// WithClusterInfrastructure allows to define the provider specific hooks to be used when turning workload clusters
+// of this Cluster into management clusters.
+func WithClusterInfrastructure(clusterInfrastructure ClusterInfrastructure) Option {
+	return func(c *clusterProxy) {
+		c.clusterInfrastructure = clusterInfrastructure
+	}
+}
+
 // clusterProxy provides a base implementation of the ClusterProxy interface.
 type clusterProxy struct {
 	name                    string
@@ -128,6 +153,7 @@ type clusterProxy struct {
 	scheme                  *runtime.Scheme
 	shouldCleanupKubeconfig bool
 	logCollector            ClusterLogCollector
+	clusterInfrastructure   ClusterInfrastructure
 	cache                   cache.Cache
 	onceCache               sync.Once
 }
@@ -156,7 +182,7 @@ func NewClusterProxy(name string, kubeconfigPath string, scheme *runtime.Scheme,
 }
 
 // newFromAPIConfig returns a clusterProxy given a api.Config and the scheme defining the types hosted in the cluster.
-func newFromAPIConfig(name string, config *api.Config, scheme *runtime.Scheme) ClusterProxy {
+func newFromAPIConfig(name string, config *api.Config, scheme *runtime.Scheme, options ...Option) ClusterProxy {
 	// NB. the ClusterProvider is responsible for the cleanup of this file
 	f, err := os.CreateTemp("", "e2e-kubeconfig")
 	Expect(err).ToNot(HaveOccurred(), "Failed to create kubeconfig file for the kind cluster %q")
@@ -165,12 +191,18 @@ func newFromAPIConfig(name string, config *api.Config, scheme *runtime.Scheme) C
 	err = clientcmd.WriteToFile(*config, kubeconfigPath)
 	Expect(err).ToNot(HaveOccurred(), "Failed to write kubeconfig for the kind cluster to a file %q")
 
-	return &clusterProxy{
+	proxy := &clusterProxy{
 		name:                    name,
 		kubeconfigPath:          kubeconfigPath,
 		scheme:                  scheme,
 		shouldCleanupKubeconfig: true,
 	}
+
+	for _, o := range options {
+		o(proxy)
+	}
+
+	return proxy
 }
 
 // GetName returns the name of the cluster.
@@ -264,6 +296,10 @@ func (p *clusterProxy) GetLogCollector() ClusterLogCollector {
 	return p.logCollector
 }
 
+func (p *clusterProxy) GetClusterInfrastructure() ClusterInfrastructure {
+	return p.clusterInfrastructure
+}
+
 // GetWorkloadCluster returns ClusterProxy for the workload cluster.
 func (p *clusterProxy) GetWorkloadCluster(ctx context.Context, namespace, name string) ClusterProxy {
 	Expect(ctx).NotTo(BeNil(), "ctx is required for GetWorkloadCluster")
@@ -279,7 +315,9 @@ func (p *clusterProxy) GetWorkloadCluster(ctx context.Context, namespace, name s
 		p.fixConfig(ctx, name, config)
 	}
 
-	return newFromAPIConfig(name, config, p.scheme)
+	// NOTE: the workload cluster is assumed to run on the same infrastructure provider of the management cluster,
+	// so the provider specific hooks are inherited by the workload cluster proxy.
+	return newFromAPIConfig(name, config, p.scheme, WithClusterInfrastructure(p.clusterInfrastructure))
 }
 
 // CollectWorkloadClusterLogs collects machines and infrastructure logs and from the workload cluster.
@@ -401,8 +439,8 @@ func (p *clusterProxy) isDockerCluster(ctx context.Context, namespace string, na
 }
 
 func (p *clusterProxy) fixConfig(ctx context.Context, name string, config *api.Config) {
-	containerRuntime, err := container.NewDockerClient()
-	Expect(err).ToNot(HaveOccurred(), "Failed to get Docker runtime client")
+	containerRuntime, err := container.NewRuntimeClient()
+	Expect(err).ToNot(HaveOccurred(), "Failed to get container runtime client")
 	ctx = container.RuntimeInto(ctx, containerRuntime)
 
 	lbContainerName := name + "-lb"
@@ -28,7 +28,10 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
 	clusterctlclient "sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+	clusterctlconfig "sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	clusterctlrepository "sigs.k8s.io/cluster-api/cmd/clusterctl/client/repository"
 	clusterctllog "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
 	"sigs.k8s.io/cluster-api/test/framework/clusterctl/logger"
 	"sigs.k8s.io/cluster-api/test/framework/internal/log"
@@ -307,6 +310,55 @@ func ConfigCluster(ctx context.Context, input ConfigClusterInput) []byte {
 	return yaml
 }
 
+// GetClusterClassInput is the input for GetClusterClass.
+type GetClusterClassInput struct {
+	LogFolder              string
+	ClusterctlConfigPath   string
+	InfrastructureProvider string
+	ClusterClassName       string
+	Namespace              string
+}
+
+// GetClusterClass returns the YAML of a ClusterClass and of its templates, read from the clusterclass-<name>.yaml
+// file in the repository of the infrastructure provider, like clusterctl does when generating a Cluster
+// referencing a ClusterClass. The latest version of the provider is used, unless the version is specified in
+// InfrastructureProvider, e.g. docker:v1.5.0.
+func GetClusterClass(_ context.Context, input GetClusterClassInput) []byte {
+	Expect(input.InfrastructureProvider).ToNot(BeEmpty(), "Invalid argument. input.InfrastructureProvider can't be empty when calling GetClusterClass")
+	Expect(input.ClusterClassName).ToNot(BeEmpty(), "Invalid argument. input.ClusterClassName can't be empty when calling GetClusterClass")
+
+	log.Logf("clusterctl get ClusterClass %s --infrastructure %s", input.ClusterClassName, input.InfrastructureProvider)
+
+	logFile := logger.OpenLogFile(logger.OpenLogFileInput{
+		LogFolder: input.LogFolder,
+		Name:      fmt.Sprintf("clusterclass-%s.yaml", input.ClusterClassName),
+	})
+	defer logFile.Close()
+	clusterctllog.SetLogger(logFile.Logger())
+
+	configClient, err := clusterctlconfig.New(input.ClusterctlConfigPath)
+	Expect(err).ToNot(HaveOccurred(), "Failed to create the clusterctl config client")
+
+	name, version, _ := strings.Cut(input.InfrastructureProvider, ":")
+	provider, err := configClient.Providers().Get(name, clusterctlv1.InfrastructureProviderType)
+	Expect(err).ToNot(HaveOccurred(), "Failed to get the configuration of the %s infrastructure provider", name)
+
+	repo, err := clusterctlrepository.New(provider, configClient)
+	Expect(err).ToNot(HaveOccurred(), "Failed to get the repository of the %s infrastructure provider", name)
+	if version == "" {
+		version = repo.DefaultVersion()
+	}
+
+	template, err := repo.ClusterClasses(version).Get(input.ClusterClassName, input.Namespace, false)
+	Expect(err).ToNot(HaveOccurred(), "Failed to get ClusterClass %s", input.ClusterClassName)
+
+	yaml, err := template.Yaml()
+	Expect(err).ToNot(HaveOccurred(), "Failed to generate yaml for ClusterClass %s", input.ClusterClassName)
+
+	_, _ = logFile.WriteString(string(yaml))
+	return yaml
+}
+
 // ConfigClusterWithBinary uses clusterctl binary to run config cluster or generate cluster.
 // NOTE: This func detects the clusterctl version and uses config cluster or generate cluster
 // accordingly. We can drop the detection when we don't have to support clusterctl v0.3.x anymore.
@@ -49,7 +49,7 @@ func machineContainerName(cluster, machine string) string {
 
 func (k DockerLogCollector) CollectMachineLog(ctx context.Context, _ client.Client, m *clusterv1.Machine, outputPath string) error {
 	containerName := machineContainerName(m.Spec.ClusterName, m.Name)
-	containerRuntime, err := container.NewDockerClient()
+	containerRuntime, err := container.NewRuntimeClient()
 	if err != nil {
 		return err
 	}
@@ -58,7 +58,7 @@ func (k DockerLogCollector) CollectMachineLog(ctx context.Context, _ client.Clie
 }
 
 func (k DockerLogCollector) CollectMachinePoolLog(ctx context.Context, _ client.Client, m *expv1.MachinePool, outputPath string) error {
-	containerRuntime, err := container.NewDockerClient()
+	containerRuntime, err := container.NewRuntimeClient()
 	if err != nil {
 		return err
 	}
@@ -77,7 +77,7 @@ func (k DockerLogCollector) CollectMachinePoolLog(ctx context.Context, _ client.
 }
 
 func (k DockerLogCollector) CollectInfrastructureLogs(ctx context.Context, _ client.Client, c *clusterv1.Cluster, outputPath string) error {
-	containerRuntime, err := container.NewDockerClient()
+	containerRuntime, err := container.NewRuntimeClient()
 	if err != nil {
 		return err
 	}
@@ -149,33 +149,12 @@ func (k DockerLogCollector) collectLogsFromNode(ctx context.Context, outputPath
 			return osExec.Command("tar", "--extract", "--file", tempfileName, "--directory", outputDir).Run() //nolint:gosec // We don't care about command injection here.
 		}
 	}
-	return errors.AggregateConcurrent([]func() error{
-		execToPathFn(
-			"journal.log",
-			"journalctl", "--no-pager", "--output=short-precise",
-		),
-		execToPathFn(
-			"kern.log",
-			"journalctl", "--no-pager", "--output=short-precise", "-k",
-		),
-		execToPathFn(
-			"kubelet-version.txt",
-			"kubelet", "--version",
-		),
-		execToPathFn(
-			"kubelet.log",
-			"journalctl", "--no-pager", "--output=short-precise", "-u", "kubelet.service",
-		),
-		execToPathFn(
-			"containerd-info.txt",
-			"crictl", "info",
-		),
-		execToPathFn(
-			"containerd.log",
-			"journalctl", "--no-pager", "--output=short-precise", "-u", "containerd.service",
-		),
-		copyDirFn("/var/log/pods", "pods"),
-	})
+	funcs := []func() error{}
+	for _, c := range nodeLogCommands {
+		funcs = append(funcs, execToPathFn(c.outputFileName, c.command, c.args...))
+	}
+	funcs = append(funcs, copyDirFn("/var/log/pods", "pods"))
+	return errors.AggregateConcurrent(funcs)
 }
 
 // fileOnHost is a helper to create a file at path
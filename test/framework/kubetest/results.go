@@ -0,0 +1,131 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubetest
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// LogFileName is the name of the file with the output of a conformance run, written in the kubetest folder
+	// of the artifacts directory.
+	LogFileName = "e2e.log"
+
+	// ResultsFileName is the name of the file summarizing the results of a conformance run, written in the
+	// kubetest folder of the artifacts directory.
+	ResultsFileName = "conformance-results.yaml"
+)
+
+// Results summarizes the results of a conformance run.
+type Results struct {
+	// KubernetesVersion is the Kubernetes version of the tested cluster.
+	KubernetesVersion string `json:"kubernetesVersion,omitempty"`
+
+	// ConformanceImage is the image used to run the conformance tests.
+	ConformanceImage string `json:"conformanceImage"`
+
+	// Tests is the number of tests, including skipped tests.
+	Tests int `json:"tests"`
+
+	// Passed is the number of passed tests.
+	Passed int `json:"passed"`
+
+	// Failed is the number of failed tests.
+	Failed int `json:"failed"`
+
+	// Skipped is the number of skipped tests.
+	Skipped int `json:"skipped"`
+
+	// FailedTests are the names of the failed tests.
+	FailedTests []string `json:"failedTests,omitempty"`
+}
+
+type junitTestSuites struct {
+	Suites []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name    string    `xml:"name,attr"`
+	Failure *struct{} `xml:"failure"`
+	Error   *struct{} `xml:"error"`
+	Skipped *struct{} `xml:"skipped"`
+}
+
+// ParseJUnitReports returns the results of the tests in the JUnit reports in a directory, i.e. the XML files
+// with the junit prefix.
+func ParseJUnitReports(dir string) (*Results, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "junit*.xml"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list JUnit reports in %s", dir)
+	}
+	sort.Strings(files)
+
+	results := &Results{}
+	for _, file := range files {
+		suites, err := readJUnitReport(file)
+		if err != nil {
+			return nil, err
+		}
+		for _, suite := range suites {
+			for _, testCase := range suite.TestCases {
+				results.Tests++
+				switch {
+				case testCase.Failure != nil || testCase.Error != nil:
+					results.Failed++
+					results.FailedTests = append(results.FailedTests, testCase.Name)
+				case testCase.Skipped != nil:
+					results.Skipped++
+				default:
+					results.Passed++
+				}
+			}
+		}
+	}
+	return results, nil
+}
+
+// readJUnitReport reads a JUnit report, having either a testsuites or a testsuite root element.
+func readJUnitReport(file string) ([]junitTestSuite, error) {
+	data, err := os.ReadFile(file) //nolint:gosec
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read JUnit report %s", file)
+	}
+
+	if strings.Contains(string(data), "<testsuites") {
+		suites := junitTestSuites{}
+		if err := xml.Unmarshal(data, &suites); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse JUnit report %s", file)
+		}
+		return suites.Suites, nil
+	}
+
+	suite := junitTestSuite{}
+	if err := xml.Unmarshal(data, &suite); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse JUnit report %s", file)
+	}
+	return []junitTestSuite{suite}, nil
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubetest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestParseJUnitReports(t *testing.T) {
+	g := NewWithT(t)
+
+	dir := t.TempDir()
+	g.Expect(os.WriteFile(filepath.Join(dir, "junit_kubetest.01.xml"), []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="4" failures="1">
+  <testsuite name="Kubernetes e2e suite" tests="4">
+    <testcase name="[sig-node] Pods should be submitted and removed [Conformance]" status="passed"></testcase>
+    <testcase name="[sig-network] DNS should provide DNS for services [Conformance]" status="failed">
+      <failure message="timed out">timed out</failure>
+    </testcase>
+    <testcase name="[sig-storage] CSI mock volume" status="skipped">
+      <skipped message="skipped"></skipped>
+    </testcase>
+    <testcase name="[sig-apps] Deployment should run the lifecycle of a Deployment [Conformance]" status="passed"></testcase>
+  </testsuite>
+</testsuites>`), 0o600)).To(Succeed())
+	g.Expect(os.WriteFile(filepath.Join(dir, "junit_kubetest.02.xml"), []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<testsuite name="Kubernetes e2e suite" tests="1">
+  <testcase name="[sig-cli] Kubectl client should check if v1 is in available api versions [Conformance]"></testcase>
+</testsuite>`), 0o600)).To(Succeed())
+	// Files without the junit prefix are ignored.
+	g.Expect(os.WriteFile(filepath.Join(dir, "other.xml"), []byte(`<testsuite><testcase name="foo"></testcase></testsuite>`), 0o600)).To(Succeed())
+
+	results, err := ParseJUnitReports(dir)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(results).To(Equal(&Results{
+		Tests:       5,
+		Passed:      3,
+		Failed:      1,
+		Skipped:     1,
+		FailedTests: []string{"[sig-network] DNS should provide DNS for services [Conformance]"},
+	}))
+}
@@ -20,6 +20,7 @@ package kubetest
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/user"
 	"path"
@@ -36,6 +37,7 @@ import (
 
 	"sigs.k8s.io/cluster-api/test/framework"
 	"sigs.k8s.io/cluster-api/test/framework/ginkgoextensions"
+	"sigs.k8s.io/cluster-api/test/framework/internal/log"
 	"sigs.k8s.io/cluster-api/test/infrastructure/container"
 )
 
@@ -175,13 +177,19 @@ func Run(ctx context.Context, input RunInput) error {
 	cwd, _ := os.Getwd()
 	ginkgoextensions.Byf("Running e2e test: dir=%s, command=%q", cwd, args)
 
-	containerRuntime, err := container.NewDockerClient()
+	containerRuntime, err := container.NewRuntimeClient()
 	if err != nil {
 		return errors.Wrap(err, "Unable to run conformance tests")
 	}
 	ctx = container.RuntimeInto(ctx, containerRuntime)
 
-	err = containerRuntime.RunContainer(ctx, &container.RunContainerInput{
+	logFile, err := os.Create(path.Join(reportDir, LogFileName))
+	if err != nil {
+		return errors.Wrap(err, "Unable to create the conformance log file")
+	}
+	defer logFile.Close()
+
+	runErr := containerRuntime.RunContainer(ctx, &container.RunContainerInput{
 		Image:           input.ConformanceImage,
 		Network:         "kind",
 		User:            user.Uid,
@@ -192,13 +200,36 @@ func Run(ctx context.Context, input RunInput) error {
 		Entrypoint:      []string{"/usr/local/bin/ginkgo"},
 		// We don't want the conformance test container to restart once ginkgo exits.
 		RestartPolicy: "no",
-	}, ginkgo.GinkgoWriter)
-	if err != nil {
-		return errors.Wrap(err, "Unable to run conformance tests")
+	}, io.MultiWriter(ginkgo.GinkgoWriter, logFile))
+
+	// Collect the results before gathering the JUnit reports, also when conformance tests fail; failing to collect
+	// the results must not hide the error of the conformance tests.
+	if err := writeResults(reportDir, input); err != nil {
+		log.Logf("Failed to write the results of the conformance tests: %v", err)
+	}
+	if runErr != nil {
+		return errors.Wrap(runErr, "Unable to run conformance tests")
 	}
 	return framework.GatherJUnitReports(reportDir, input.ArtifactsDirectory)
 }
 
+// writeResults writes the summary of the results of the conformance tests in the report directory.
+func writeResults(reportDir string, input RunInput) error {
+	results, err := ParseJUnitReports(reportDir)
+	if err != nil {
+		return errors.Wrap(err, "Unable to collect the results of conformance tests")
+	}
+	results.KubernetesVersion = input.KubernetesVersion
+	results.ConformanceImage = input.ConformanceImage
+	ginkgoextensions.Byf("Conformance results: %d passed, %d failed, %d skipped", results.Passed, results.Failed, results.Skipped)
+
+	data, err := yaml.Marshal(results)
+	if err != nil {
+		return errors.Wrap(err, "Unable to marshal the results of conformance tests")
+	}
+	return os.WriteFile(path.Join(reportDir, ResultsFileName), data, 0o600)
+}
+
 type kubetestConfig map[string]string
 
 func (c kubetestConfig) toFlags() []string {
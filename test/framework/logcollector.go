@@ -0,0 +1,119 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+)
+
+// nodeLogCommand is a command to be run on a node to collect logs.
+type nodeLogCommand struct {
+	outputFileName string
+	command        string
+	args           []string
+}
+
+// nodeLogCommands are the commands used to collect node-level logs, no matter of how they are run on the node.
+var nodeLogCommands = []nodeLogCommand{
+	{outputFileName: "journal.log", command: "journalctl", args: []string{"--no-pager", "--output=short-precise"}},
+	{outputFileName: "kern.log", command: "journalctl", args: []string{"--no-pager", "--output=short-precise", "-k"}},
+	{outputFileName: "kubelet-version.txt", command: "kubelet", args: []string{"--version"}},
+	{outputFileName: "kubelet.log", command: "journalctl", args: []string{"--no-pager", "--output=short-precise", "-u", "kubelet.service"}},
+	{outputFileName: "containerd-info.txt", command: "crictl", args: []string{"info"}},
+	{outputFileName: "containerd.log", command: "journalctl", args: []string{"--no-pager", "--output=short-precise", "-u", "containerd.service"}},
+}
+
+// ClusterLogCollectorRegistry is a ClusterLogCollector which delegates to the ClusterLogCollector registered for the
+// infrastructure provider of each Cluster, Machine or MachinePool; this allows to collect logs from workload clusters
+// running on different infrastructure providers during the same test run.
+// The infrastructure provider is derived from the kind of the infrastructure object, e.g. docker for DockerMachine.
+type ClusterLogCollectorRegistry struct {
+	collectors       map[string]ClusterLogCollector
+	defaultCollector ClusterLogCollector
+}
+
+var _ ClusterLogCollector = &ClusterLogCollectorRegistry{}
+
+// NewClusterLogCollectorRegistry returns a ClusterLogCollectorRegistry; the default collector, if not nil,
+// is used for infrastructure providers without a registered ClusterLogCollector.
+func NewClusterLogCollectorRegistry(defaultCollector ClusterLogCollector) *ClusterLogCollectorRegistry {
+	return &ClusterLogCollectorRegistry{
+		collectors:       map[string]ClusterLogCollector{},
+		defaultCollector: defaultCollector,
+	}
+}
+
+// Register registers the ClusterLogCollector for an infrastructure provider, e.g. docker.
+func (r *ClusterLogCollectorRegistry) Register(infrastructureProvider string, collector ClusterLogCollector) *ClusterLogCollectorRegistry {
+	r.collectors[strings.ToLower(infrastructureProvider)] = collector
+	return r
+}
+
+// CollectMachineLog collects logs from a machine using the ClusterLogCollector for its infrastructure provider.
+func (r *ClusterLogCollectorRegistry) CollectMachineLog(ctx context.Context, managementClusterClient client.Client, m *clusterv1.Machine, outputPath string) error {
+	collector := r.collectorFor(m.Spec.InfrastructureRef.Kind)
+	if collector == nil {
+		return nil
+	}
+	return collector.CollectMachineLog(ctx, managementClusterClient, m, outputPath)
+}
+
+// CollectMachinePoolLog collects logs from a machine pool using the ClusterLogCollector for its infrastructure provider.
+func (r *ClusterLogCollectorRegistry) CollectMachinePoolLog(ctx context.Context, managementClusterClient client.Client, m *expv1.MachinePool, outputPath string) error {
+	collector := r.collectorFor(m.Spec.Template.Spec.InfrastructureRef.Kind)
+	if collector == nil {
+		return nil
+	}
+	return collector.CollectMachinePoolLog(ctx, managementClusterClient, m, outputPath)
+}
+
+// CollectInfrastructureLogs collects logs from the infrastructure using the ClusterLogCollector for the infrastructure provider of the cluster.
+func (r *ClusterLogCollectorRegistry) CollectInfrastructureLogs(ctx context.Context, managementClusterClient client.Client, c *clusterv1.Cluster, outputPath string) error {
+	if c.Spec.InfrastructureRef == nil {
+		return nil
+	}
+	collector := r.collectorFor(c.Spec.InfrastructureRef.Kind)
+	if collector == nil {
+		return nil
+	}
+	return collector.CollectInfrastructureLogs(ctx, managementClusterClient, c, outputPath)
+}
+
+func (r *ClusterLogCollectorRegistry) collectorFor(infrastructureKind string) ClusterLogCollector {
+	if collector, ok := r.collectors[infrastructureProviderFromKind(infrastructureKind)]; ok {
+		return collector
+	}
+	return r.defaultCollector
+}
+
+// infrastructureProviderFromKind returns the name of the infrastructure provider given the kind of one of its
+// infrastructure objects, e.g. docker for DockerMachine, DockerMachinePool or DockerCluster.
+func infrastructureProviderFromKind(kind string) string {
+	for _, suffix := range []string{"MachinePool", "Machine", "Cluster"} {
+		if strings.HasSuffix(kind, suffix) {
+			kind = strings.TrimSuffix(kind, suffix)
+			break
+		}
+	}
+	return strings.ToLower(kind)
+}
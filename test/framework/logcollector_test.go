@@ -0,0 +1,115 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+)
+
+type fakeLogCollector struct {
+	collected []string
+}
+
+func (f *fakeLogCollector) CollectMachineLog(_ context.Context, _ client.Client, m *clusterv1.Machine, _ string) error {
+	f.collected = append(f.collected, m.Name)
+	return nil
+}
+
+func (f *fakeLogCollector) CollectMachinePoolLog(_ context.Context, _ client.Client, m *expv1.MachinePool, _ string) error {
+	f.collected = append(f.collected, m.Name)
+	return nil
+}
+
+func (f *fakeLogCollector) CollectInfrastructureLogs(_ context.Context, _ client.Client, c *clusterv1.Cluster, _ string) error {
+	f.collected = append(f.collected, c.Name)
+	return nil
+}
+
+func TestClusterLogCollectorRegistry(t *testing.T) {
+	machine := func(name, kind string) *clusterv1.Machine {
+		m := &clusterv1.Machine{Spec: clusterv1.MachineSpec{InfrastructureRef: corev1.ObjectReference{Kind: kind}}}
+		m.Name = name
+		return m
+	}
+
+	t.Run("delegates to the collector registered for the infrastructure provider", func(t *testing.T) {
+		g := NewWithT(t)
+
+		docker := &fakeLogCollector{}
+		other := &fakeLogCollector{}
+		registry := NewClusterLogCollectorRegistry(nil).
+			Register("docker", docker).
+			Register("Other", other)
+
+		g.Expect(registry.CollectMachineLog(context.Background(), nil, machine("m1", "DockerMachine"), "")).To(Succeed())
+		g.Expect(registry.CollectMachineLog(context.Background(), nil, machine("m2", "OtherMachine"), "")).To(Succeed())
+
+		mp := &expv1.MachinePool{}
+		mp.Name = "mp1"
+		mp.Spec.Template.Spec.InfrastructureRef.Kind = "DockerMachinePool"
+		g.Expect(registry.CollectMachinePoolLog(context.Background(), nil, mp, "")).To(Succeed())
+
+		cluster := &clusterv1.Cluster{Spec: clusterv1.ClusterSpec{InfrastructureRef: &corev1.ObjectReference{Kind: "OtherCluster"}}}
+		cluster.Name = "c1"
+		g.Expect(registry.CollectInfrastructureLogs(context.Background(), nil, cluster, "")).To(Succeed())
+
+		g.Expect(docker.collected).To(Equal([]string{"m1", "mp1"}))
+		g.Expect(other.collected).To(Equal([]string{"m2", "c1"}))
+	})
+
+	t.Run("falls back to the default collector", func(t *testing.T) {
+		g := NewWithT(t)
+
+		defaultCollector := &fakeLogCollector{}
+		registry := NewClusterLogCollectorRegistry(defaultCollector).
+			Register("docker", &fakeLogCollector{})
+
+		g.Expect(registry.CollectMachineLog(context.Background(), nil, machine("m1", "InMemoryMachine"), "")).To(Succeed())
+		g.Expect(defaultCollector.collected).To(Equal([]string{"m1"}))
+	})
+
+	t.Run("skips providers without a collector", func(t *testing.T) {
+		g := NewWithT(t)
+
+		registry := NewClusterLogCollectorRegistry(nil)
+
+		g.Expect(registry.CollectMachineLog(context.Background(), nil, machine("m1", "InMemoryMachine"), "")).To(Succeed())
+		g.Expect(registry.CollectInfrastructureLogs(context.Background(), nil, &clusterv1.Cluster{}, "")).To(Succeed())
+	})
+}
+
+func TestMachineSSHAddress(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(machineSSHAddress(nil)).To(BeEmpty())
+	g.Expect(machineSSHAddress(clusterv1.MachineAddresses{
+		{Type: clusterv1.MachineInternalIP, Address: "10.0.0.1"},
+		{Type: clusterv1.MachineInternalDNS, Address: "node.internal"},
+	})).To(Equal("10.0.0.1"))
+	g.Expect(machineSSHAddress(clusterv1.MachineAddresses{
+		{Type: clusterv1.MachineInternalIP, Address: "10.0.0.1"},
+		{Type: clusterv1.MachineExternalIP, Address: "1.2.3.4"},
+	})).To(Equal("1.2.3.4"))
+}
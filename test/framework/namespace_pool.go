@@ -0,0 +1,220 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/test/framework/internal/log"
+)
+
+const (
+	// NamespacePoolLabel is the label set on the namespaces of a pool, with the name of the pool as a value.
+	NamespacePoolLabel = "e2e.cluster.x-k8s.io/namespace-pool"
+
+	// NamespaceLeaseOwnerAnnotation is the annotation set on leased namespaces, with the name of the lease owner as a value.
+	NamespaceLeaseOwnerAnnotation = "e2e.cluster.x-k8s.io/lease-owner"
+)
+
+// A namespace pool is a set of namespaces pre-provisioned once, e.g. in the first function of SynchronizedBeforeSuite,
+// and then leased to specs running on parallel Ginkgo nodes; this allows to provision the resources shared by specs,
+// e.g. ClusterClasses and their templates, only once instead of for every spec.
+// Leases are tracked with an annotation on the namespaces, so the pool is safe to be used concurrently by different
+// processes; optimistic locking ensures a namespace is leased to only one owner at a time.
+
+// CreateNamespacePoolInput is the input type for CreateNamespacePool.
+type CreateNamespacePoolInput struct {
+	ClusterProxy ClusterProxy
+	Name         string
+	Size         int
+
+	// Resources to be applied in each namespace of the pool, if any.
+	Resources []byte
+}
+
+// CreateNamespacePool creates the namespaces of a pool, named <pool name>-<index>, and applies the pool resources to each of them.
+func CreateNamespacePool(ctx context.Context, input CreateNamespacePoolInput, intervals ...interface{}) []*corev1.Namespace {
+	Expect(ctx).NotTo(BeNil(), "ctx is required for CreateNamespacePool")
+	Expect(input.ClusterProxy).ToNot(BeNil(), "Invalid argument. input.ClusterProxy can't be nil when calling CreateNamespacePool")
+	Expect(input.Name).ToNot(BeEmpty(), "Invalid argument. input.Name can't be empty when calling CreateNamespacePool")
+	Expect(input.Size).To(BeNumerically(">", 0), "Invalid argument. input.Size must be greater than zero when calling CreateNamespacePool")
+
+	log.Logf("Creating namespace pool %s with %d namespaces", input.Name, input.Size)
+	namespaces := make([]*corev1.Namespace, 0, input.Size)
+	for i := 0; i < input.Size; i++ {
+		ns := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   fmt.Sprintf("%s-%d", input.Name, i),
+				Labels: map[string]string{NamespacePoolLabel: input.Name},
+			},
+		}
+		Eventually(func() error {
+			if err := input.ClusterProxy.GetClient().Create(ctx, ns); err != nil && !apierrors.IsAlreadyExists(err) {
+				return err
+			}
+			return nil
+		}, intervals...).Should(Succeed(), "Failed to create namespace %s", ns.Name)
+
+		if len(input.Resources) > 0 {
+			Eventually(func() error {
+				return input.ClusterProxy.Apply(ctx, input.Resources, "--namespace", ns.Name)
+			}, intervals...).Should(Succeed(), "Failed to apply the pool resources to namespace %s", ns.Name)
+		}
+		namespaces = append(namespaces, ns)
+	}
+	return namespaces
+}
+
+// AcquireNamespaceInput is the input type for AcquireNamespace.
+type AcquireNamespaceInput struct {
+	Client   client.Client
+	PoolName string
+
+	// Owner of the lease, e.g. the name of the spec and the Ginkgo parallel process.
+	Owner string
+}
+
+// AcquireNamespace leases a free namespace of the pool to the owner, waiting for one to be available.
+func AcquireNamespace(ctx context.Context, input AcquireNamespaceInput, intervals ...interface{}) *corev1.Namespace {
+	Expect(ctx).NotTo(BeNil(), "ctx is required for AcquireNamespace")
+	Expect(input.Client).ToNot(BeNil(), "Invalid argument. input.Client can't be nil when calling AcquireNamespace")
+	Expect(input.PoolName).ToNot(BeEmpty(), "Invalid argument. input.PoolName can't be empty when calling AcquireNamespace")
+	Expect(input.Owner).ToNot(BeEmpty(), "Invalid argument. input.Owner can't be empty when calling AcquireNamespace")
+
+	var leased *corev1.Namespace
+	Eventually(func() error {
+		var err error
+		leased, err = tryAcquireNamespace(ctx, input.Client, input.PoolName, input.Owner)
+		return err
+	}, intervals...).Should(Succeed(), "Failed to acquire a namespace from pool %s", input.PoolName)
+
+	log.Logf("Namespace %s leased to %s", leased.Name, input.Owner)
+	return leased
+}
+
+// tryAcquireNamespace leases the first free namespace of the pool to the owner; it returns an error if there are
+// no free namespaces or if another owner leased the namespace concurrently.
+func tryAcquireNamespace(ctx context.Context, c client.Client, poolName, owner string) (*corev1.Namespace, error) {
+	namespaceList := &corev1.NamespaceList{}
+	if err := c.List(ctx, namespaceList, client.MatchingLabels{NamespacePoolLabel: poolName}); err != nil {
+		return nil, errors.Wrapf(err, "failed to list namespaces of pool %s", poolName)
+	}
+	sort.Slice(namespaceList.Items, func(i, j int) bool {
+		return namespaceList.Items[i].Name < namespaceList.Items[j].Name
+	})
+
+	for i := range namespaceList.Items {
+		ns := &namespaceList.Items[i]
+		if ns.DeletionTimestamp != nil {
+			continue
+		}
+		if _, ok := ns.Annotations[NamespaceLeaseOwnerAnnotation]; ok {
+			continue
+		}
+
+		patch := client.MergeFromWithOptions(ns.DeepCopy(), client.MergeFromWithOptimisticLock{})
+		if ns.Annotations == nil {
+			ns.Annotations = map[string]string{}
+		}
+		ns.Annotations[NamespaceLeaseOwnerAnnotation] = owner
+		if err := c.Patch(ctx, ns, patch); err != nil {
+			return nil, errors.Wrapf(err, "failed to lease namespace %s", ns.Name)
+		}
+		return ns, nil
+	}
+	return nil, errors.Errorf("no free namespaces in pool %s", poolName)
+}
+
+// ReleaseNamespaceInput is the input type for ReleaseNamespace.
+type ReleaseNamespaceInput struct {
+	Client client.Client
+	Name   string
+	Owner  string
+}
+
+// ReleaseNamespace waits for the Clusters in a leased namespace to be deleted, and then makes the namespace available
+// to other owners; resources shared by specs, e.g. ClusterClasses, are preserved.
+func ReleaseNamespace(ctx context.Context, input ReleaseNamespaceInput, intervals ...interface{}) {
+	Expect(ctx).NotTo(BeNil(), "ctx is required for ReleaseNamespace")
+	Expect(input.Client).ToNot(BeNil(), "Invalid argument. input.Client can't be nil when calling ReleaseNamespace")
+	Expect(input.Name).ToNot(BeEmpty(), "Invalid argument. input.Name can't be empty when calling ReleaseNamespace")
+	Expect(input.Owner).ToNot(BeEmpty(), "Invalid argument. input.Owner can't be empty when calling ReleaseNamespace")
+
+	Eventually(func() error {
+		clusterList := &clusterv1.ClusterList{}
+		if err := input.Client.List(ctx, clusterList, client.InNamespace(input.Name)); err != nil {
+			return err
+		}
+		if len(clusterList.Items) > 0 {
+			return errors.Errorf("namespace %s still has %d Clusters", input.Name, len(clusterList.Items))
+		}
+		return nil
+	}, intervals...).Should(Succeed(), "Failed waiting for Clusters in namespace %s to be deleted", input.Name)
+
+	Eventually(func() error {
+		ns := &corev1.Namespace{}
+		if err := input.Client.Get(ctx, client.ObjectKey{Name: input.Name}, ns); err != nil {
+			return err
+		}
+		if owner := ns.Annotations[NamespaceLeaseOwnerAnnotation]; owner != input.Owner {
+			return StopTrying(fmt.Sprintf("namespace %s is leased to %q, not to %q", input.Name, owner, input.Owner))
+		}
+
+		patch := client.MergeFromWithOptions(ns.DeepCopy(), client.MergeFromWithOptimisticLock{})
+		delete(ns.Annotations, NamespaceLeaseOwnerAnnotation)
+		return input.Client.Patch(ctx, ns, patch)
+	}, intervals...).Should(Succeed(), "Failed to release namespace %s", input.Name)
+
+	log.Logf("Namespace %s released by %s", input.Name, input.Owner)
+}
+
+// DeleteNamespacePoolInput is the input type for DeleteNamespacePool.
+type DeleteNamespacePoolInput struct {
+	Client client.Client
+	Name   string
+}
+
+// DeleteNamespacePool deletes all the namespaces of a pool, no matter if they are leased or not.
+func DeleteNamespacePool(ctx context.Context, input DeleteNamespacePoolInput, intervals ...interface{}) {
+	Expect(ctx).NotTo(BeNil(), "ctx is required for DeleteNamespacePool")
+	Expect(input.Client).ToNot(BeNil(), "Invalid argument. input.Client can't be nil when calling DeleteNamespacePool")
+	Expect(input.Name).ToNot(BeEmpty(), "Invalid argument. input.Name can't be empty when calling DeleteNamespacePool")
+
+	log.Logf("Deleting namespace pool %s", input.Name)
+	Eventually(func() error {
+		namespaceList := &corev1.NamespaceList{}
+		if err := input.Client.List(ctx, namespaceList, client.MatchingLabels{NamespacePoolLabel: input.Name}); err != nil {
+			return err
+		}
+		for i := range namespaceList.Items {
+			if err := input.Client.Delete(ctx, &namespaceList.Items[i]); err != nil && !apierrors.IsNotFound(err) {
+				return err
+			}
+		}
+		return nil
+	}, intervals...).Should(Succeed(), "Failed to delete namespace pool %s", input.Name)
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestNamespacePool(t *testing.T) {
+	RegisterTestingT(t)
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	Expect(corev1.AddToScheme(scheme)).To(Succeed())
+	Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+
+	poolNamespace := func(name string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{NamespacePoolLabel: "pool"}}}
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		poolNamespace("pool-0"),
+		poolNamespace("pool-1"),
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "other"}},
+	).Build()
+
+	// Leases are given to different owners until the pool is exhausted.
+	ns0 := AcquireNamespace(ctx, AcquireNamespaceInput{Client: c, PoolName: "pool", Owner: "owner-a"})
+	Expect(ns0.Name).To(Equal("pool-0"))
+	ns1 := AcquireNamespace(ctx, AcquireNamespaceInput{Client: c, PoolName: "pool", Owner: "owner-b"})
+	Expect(ns1.Name).To(Equal("pool-1"))
+
+	_, err := tryAcquireNamespace(ctx, c, "pool", "owner-c")
+	Expect(err).To(HaveOccurred())
+
+	// A released namespace can be leased again.
+	ReleaseNamespace(ctx, ReleaseNamespaceInput{Client: c, Name: ns0.Name, Owner: "owner-a"})
+	ns := &corev1.Namespace{}
+	Expect(c.Get(ctx, client.ObjectKey{Name: ns0.Name}, ns)).To(Succeed())
+	Expect(ns.Annotations).ToNot(HaveKey(NamespaceLeaseOwnerAnnotation))
+
+	ns2 := AcquireNamespace(ctx, AcquireNamespaceInput{Client: c, PoolName: "pool", Owner: "owner-c"})
+	Expect(ns2.Name).To(Equal("pool-0"))
+	Expect(ns2.Annotations).To(HaveKeyWithValue(NamespaceLeaseOwnerAnnotation, "owner-c"))
+
+	// Deleting the pool does not affect other namespaces.
+	DeleteNamespacePool(ctx, DeleteNamespacePoolInput{Client: c, Name: "pool"})
+	namespaceList := &corev1.NamespaceList{}
+	Expect(c.List(ctx, namespaceList)).To(Succeed())
+	Expect(namespaceList.Items).To(HaveLen(1))
+	Expect(namespaceList.Items[0].Name).To(Equal("other"))
+}
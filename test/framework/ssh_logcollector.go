@@ -0,0 +1,198 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"net"
+	"os"
+	osExec "os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/labels/format"
+)
+
+const sshDialTimeout = 30 * time.Second
+
+// SSHLogCollector collects logs from the machines of a workload cluster by running commands over SSH;
+// it can be used with any infrastructure provider where machines are reachable from the test environment.
+type SSHLogCollector struct {
+	// User is the user used to connect to the machines.
+	User string
+
+	// PrivateKey is the PEM encoded private key used to connect to the machines.
+	PrivateKey []byte
+
+	// Port is the SSH port of the machines. Default is 22.
+	Port int
+
+	// Sudo runs the commands on the machines with sudo; this is required when User is not root.
+	Sudo bool
+}
+
+var _ ClusterLogCollector = SSHLogCollector{}
+
+// CollectMachineLog collects the systemd journals and the pod logs from a machine.
+func (s SSHLogCollector) CollectMachineLog(ctx context.Context, _ client.Client, m *clusterv1.Machine, outputPath string) error {
+	address := machineSSHAddress(m.Status.Addresses)
+	if address == "" {
+		return errors.Errorf("failed to collect logs from Machine %s: no address available", m.Name)
+	}
+	return s.collectLogsFromNode(ctx, address, outputPath)
+}
+
+// CollectMachinePoolLog collects the systemd journals and the pod logs from the machines of a machine pool;
+// it requires the infrastructure provider to support MachinePool Machines.
+func (s SSHLogCollector) CollectMachinePoolLog(ctx context.Context, managementClusterClient client.Client, m *expv1.MachinePool, outputPath string) error {
+	machineList := &clusterv1.MachineList{}
+	if err := managementClusterClient.List(ctx, machineList, client.InNamespace(m.Namespace), client.MatchingLabels{
+		clusterv1.ClusterNameLabel:     m.Spec.ClusterName,
+		clusterv1.MachinePoolNameLabel: format.MustFormatValue(m.Name),
+	}); err != nil {
+		return errors.Wrapf(err, "failed to list Machines for MachinePool %s", m.Name)
+	}
+
+	var errs []error
+	for i := range machineList.Items {
+		machine := &machineList.Items[i]
+		if err := s.CollectMachineLog(ctx, managementClusterClient, machine, filepath.Join(outputPath, machine.Name)); err != nil {
+			// collecting logs is best effort so we proceed to the next instance even if we encounter an error.
+			errs = append(errs, err)
+		}
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+// CollectInfrastructureLogs is a no-op, given that there is no provider agnostic way to collect logs from the infrastructure.
+func (s SSHLogCollector) CollectInfrastructureLogs(_ context.Context, _ client.Client, _ *clusterv1.Cluster, _ string) error {
+	return nil
+}
+
+func (s SSHLogCollector) collectLogsFromNode(ctx context.Context, address, outputPath string) error {
+	signer, err := ssh.ParsePrivateKey(s.PrivateKey)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse SSH private key")
+	}
+
+	port := s.Port
+	if port == 0 {
+		port = 22
+	}
+	hostPort := net.JoinHostPort(address, strconv.Itoa(port))
+
+	dialer := &net.Dialer{Timeout: sshDialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", hostPort)
+	if err != nil {
+		return errors.Wrapf(err, "failed to connect to %s", hostPort)
+	}
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, hostPort, &ssh.ClientConfig{
+		User:            s.User,
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(), //nolint:gosec // Machines of test clusters are ephemeral, so there is no known host key to check.
+		Timeout:         sshDialTimeout,
+	})
+	if err != nil {
+		conn.Close()
+		return errors.Wrapf(err, "failed to establish an SSH connection to %s", hostPort)
+	}
+	sshClient := ssh.NewClient(sshConn, chans, reqs)
+	defer sshClient.Close()
+
+	execToPathFn := func(outputFileName, command string, args ...string) error {
+		f, err := fileOnHost(filepath.Join(outputPath, outputFileName))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return s.run(sshClient, f, command, args...)
+	}
+	copyDirFn := func(nodeDir, dirName string) error {
+		f, err := os.CreateTemp("", "ssh-logs")
+		if err != nil {
+			return err
+		}
+		tempfileName := f.Name()
+		defer os.Remove(tempfileName)
+
+		err = s.run(sshClient, f, "tar", "--hard-dereference", "--dereference", "--directory", nodeDir, "--create", "--file", "-", ".")
+		f.Close()
+		if err != nil {
+			return err
+		}
+
+		outputDir := filepath.Join(outputPath, dirName)
+		if err := os.MkdirAll(outputDir, os.ModePerm); err != nil {
+			return err
+		}
+		return osExec.Command("tar", "--extract", "--file", tempfileName, "--directory", outputDir).Run() //nolint:gosec // We don't care about command injection here.
+	}
+
+	var errs []error
+	for _, c := range nodeLogCommands {
+		if err := execToPathFn(c.outputFileName, c.command, c.args...); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := copyDirFn("/var/log/pods", "pods"); err != nil {
+		errs = append(errs, err)
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+// run runs a command on the node over a new SSH session, writing its output to the given file.
+func (s SSHLogCollector) run(sshClient *ssh.Client, f *os.File, command string, args ...string) error {
+	session, err := sshClient.NewSession()
+	if err != nil {
+		return errors.Wrap(err, "failed to create SSH session")
+	}
+	defer session.Close()
+
+	cmd := strings.Join(append([]string{command}, args...), " ")
+	if s.Sudo {
+		cmd = "sudo " + cmd
+	}
+
+	stderr := &strings.Builder{}
+	session.Stdout = f
+	session.Stderr = stderr
+	if err := session.Run(cmd); err != nil {
+		return errors.Wrapf(err, "failed to run %q: %s", cmd, stderr.String())
+	}
+	return nil
+}
+
+// machineSSHAddress returns the address to be used to connect to a machine, preferring external addresses.
+func machineSSHAddress(addresses clusterv1.MachineAddresses) string {
+	for _, addressType := range []clusterv1.MachineAddressType{clusterv1.MachineExternalIP, clusterv1.MachineExternalDNS, clusterv1.MachineInternalIP, clusterv1.MachineInternalDNS} {
+		for _, address := range addresses {
+			if address.Type == addressType && address.Address != "" {
+				return address.Address
+			}
+		}
+	}
+	return ""
+}
@@ -26,6 +26,9 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -51,15 +54,65 @@ const (
 	btrfsStorage = "btrfs"
 	zfsStorage   = "zfs"
 	xfsStorage   = "xfs"
+
+	// networkMTUOption is the option of the bridge driver defining the MTU of a network.
+	networkMTUOption = "com.docker.network.driver.mtu"
 )
 
+const (
+	// tlsCACertFile, tlsCertFile and tlsKeyFile are the names of the files in ClientOptions.TLSCertDir;
+	// they match the keys of a kubernetes.io/tls Secret, so the directory can be a Secret mounted as a volume.
+	tlsCACertFile = "ca.crt"
+	tlsCertFile   = "tls.crt"
+	tlsKeyFile    = "tls.key"
+)
+
+// ClientOptions defines the options used to connect to a container runtime.
+type ClientOptions struct {
+	// Host is the address of the container runtime daemon, e.g. "tcp://docker.example.com:2376".
+	// If empty, the address is read from the environment.
+	Host string
+
+	// TLSCertDir is the directory containing the CA certificate (ca.crt), the client certificate (tls.crt) and
+	// the client key (tls.key) used to connect to the container runtime daemon using TLS.
+	// If empty, TLS is configured from the environment.
+	TLSCertDir string
+}
+
+// clientOpts returns the options for the Docker client corresponding to ClientOptions.
+func (o ClientOptions) clientOpts() ([]client.Opt, error) {
+	opts := []client.Opt{}
+	if o.Host != "" {
+		opts = append(opts, client.WithHost(o.Host))
+	}
+	if o.TLSCertDir != "" {
+		for _, file := range []string{tlsCACertFile, tlsCertFile, tlsKeyFile} {
+			if _, err := os.Stat(filepath.Join(o.TLSCertDir, file)); err != nil {
+				return nil, errors.Wrapf(err, "failed to read TLS certificates from %s", o.TLSCertDir)
+			}
+		}
+		opts = append(opts, client.WithTLSClientConfig(
+			filepath.Join(o.TLSCertDir, tlsCACertFile),
+			filepath.Join(o.TLSCertDir, tlsCertFile),
+			filepath.Join(o.TLSCertDir, tlsKeyFile),
+		))
+	}
+	return opts, nil
+}
+
 type dockerRuntime struct {
 	dockerClient *client.Client
 }
 
 // NewDockerClient gets a client for interacting with a Docker container runtime.
 func NewDockerClient() (Runtime, error) {
-	dockerClient, err := getDockerClient()
+	return NewDockerClientWithOptions(ClientOptions{})
+}
+
+// NewDockerClientWithOptions gets a client for interacting with a Docker container runtime
+// using the given ClientOptions, e.g. for connecting to a remote Docker engine using TLS.
+func NewDockerClientWithOptions(options ClientOptions) (Runtime, error) {
+	dockerClient, err := getDockerClient(options)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to created docker runtime client")
 	}
@@ -69,8 +122,12 @@ func NewDockerClient() (Runtime, error) {
 }
 
 // getDockerClient returns a new client connection for interacting with the Docker engine.
-func getDockerClient() (*client.Client, error) {
-	dockerClient, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+func getDockerClient(options ClientOptions) (*client.Client, error) {
+	opts, err := options.clientOpts()
+	if err != nil {
+		return nil, err
+	}
+	dockerClient, err := client.NewClientWithOpts(append([]client.Opt{client.FromEnv, client.WithAPIVersionNegotiation()}, opts...)...)
 	if err != nil {
 		return nil, fmt.Errorf("create Docker client: %v", err)
 	}
@@ -310,6 +367,116 @@ func (d *dockerRuntime) KillContainer(ctx context.Context, containerName, signal
 	return d.dockerClient.ContainerKill(ctx, containerName, signal)
 }
 
+// PauseContainer suspends all the processes of a running container.
+func (d *dockerRuntime) PauseContainer(ctx context.Context, containerName string) error {
+	return d.dockerClient.ContainerPause(ctx, containerName)
+}
+
+// UnpauseContainer resumes all the processes of a paused container.
+func (d *dockerRuntime) UnpauseContainer(ctx context.Context, containerName string) error {
+	return d.dockerClient.ContainerUnpause(ctx, containerName)
+}
+
+// CommitContainer creates an image from the filesystem of a container.
+// NOTE: the content of the volumes attached to the container is not included in the image.
+func (d *dockerRuntime) CommitContainer(ctx context.Context, containerName string, config *CommitContainerInput) error {
+	containerInfo, err := d.dockerClient.ContainerInspect(ctx, containerName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get container details for %q", containerName)
+	}
+
+	commitConfig := &dockercontainer.Config{}
+	if containerInfo.Config != nil {
+		commitConfig.Cmd = containerInfo.Config.Cmd
+		commitConfig.Entrypoint = containerInfo.Config.Entrypoint
+	}
+	if len(config.EntrypointPrefix) > 0 {
+		commitConfig.Entrypoint = append(append([]string{}, config.EntrypointPrefix...), commitConfig.Entrypoint...)
+	}
+
+	if _, err := d.dockerClient.ContainerCommit(ctx, containerName, types.ContainerCommitOptions{
+		Reference: config.Image,
+		Pause:     true,
+		Config:    commitConfig,
+	}); err != nil {
+		return errors.Wrapf(err, "failed to commit container %q to image %q", containerName, config.Image)
+	}
+	return nil
+}
+
+// GetContainerNetworks returns the names of the networks a container is attached to, sorted alphabetically.
+func (d *dockerRuntime) GetContainerNetworks(ctx context.Context, containerName string) ([]string, error) {
+	containerInfo, err := d.dockerClient.ContainerInspect(ctx, containerName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get container details for %q", containerName)
+	}
+
+	if containerInfo.NetworkSettings == nil {
+		return nil, nil
+	}
+	networkNames := make([]string, 0, len(containerInfo.NetworkSettings.Networks))
+	for name := range containerInfo.NetworkSettings.Networks {
+		networkNames = append(networkNames, name)
+	}
+	sort.Strings(networkNames)
+	return networkNames, nil
+}
+
+// ConnectContainerToNetwork attaches a running container to an existing network.
+func (d *dockerRuntime) ConnectContainerToNetwork(ctx context.Context, containerName, networkName string) error {
+	if err := d.dockerClient.NetworkConnect(ctx, networkName, containerName, nil); err != nil {
+		return errors.Wrapf(err, "failed to connect container %q to network %q", containerName, networkName)
+	}
+	return nil
+}
+
+// GetNetwork returns the details of a network, or nil if the network does not exist.
+func (d *dockerRuntime) GetNetwork(ctx context.Context, name string) (*Network, error) {
+	networkInfo, err := d.dockerClient.NetworkInspect(ctx, name, types.NetworkInspectOptions{})
+	if err != nil {
+		if client.IsErrNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to inspect network %q", name)
+	}
+
+	ret := &Network{
+		Name:   networkInfo.Name,
+		Labels: networkInfo.Labels,
+	}
+	for _, config := range networkInfo.IPAM.Config {
+		ret.Subnets = append(ret.Subnets, config.Subnet)
+	}
+	if mtu, ok := networkInfo.Options[networkMTUOption]; ok {
+		ret.MTU, err = strconv.Atoi(mtu)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse the MTU of network %q", name)
+		}
+	}
+	return ret, nil
+}
+
+// CreateNetwork creates a bridge network.
+func (d *dockerRuntime) CreateNetwork(ctx context.Context, input *CreateNetworkInput) error {
+	if _, err := d.dockerClient.NetworkCreate(ctx, input.Name, types.NetworkCreate{
+		CheckDuplicate: true,
+		Driver:         "bridge",
+		EnableIPv6:     input.IPv6,
+		Labels:         input.Labels,
+	}); err != nil {
+		return errors.Wrapf(err, "failed to create network %q", input.Name)
+	}
+	return nil
+}
+
+// DeleteNetwork deletes a network; it is a no-op if the network does not exist.
+func (d *dockerRuntime) DeleteNetwork(ctx context.Context, name string) error {
+	if err := d.dockerClient.NetworkRemove(ctx, name); err != nil && !client.IsErrNotFound(err) {
+		return errors.Wrapf(err, "failed to delete network %q", name)
+	}
+	return nil
+}
+
 // GetContainerIPs inspects a container to get its IPv4 and IPv6 IP addresses.
 // Will not error if there is no IP address assigned. Calling code will need to
 // determine whether that is an issue or not.
@@ -319,8 +486,26 @@ func (d *dockerRuntime) GetContainerIPs(ctx context.Context, containerName strin
 		return "", "", errors.Wrap(err, "failed to get container details")
 	}
 
-	for _, net := range containerInfo.NetworkSettings.Networks {
-		return net.IPAddress, net.GlobalIPv6Address, nil
+	if containerInfo.NetworkSettings == nil || len(containerInfo.NetworkSettings.Networks) == 0 {
+		return "", "", nil
+	}
+
+	// Prefer the network the container has been created on; fall back to the first network in alphabetical order
+	// so the result is stable when the container is attached to more than one network.
+	if containerInfo.ContainerJSONBase != nil && containerInfo.HostConfig != nil {
+		if net, ok := containerInfo.NetworkSettings.Networks[string(containerInfo.HostConfig.NetworkMode)]; ok && net != nil {
+			return net.IPAddress, net.GlobalIPv6Address, nil
+		}
+	}
+	networkNames := make([]string, 0, len(containerInfo.NetworkSettings.Networks))
+	for name := range containerInfo.NetworkSettings.Networks {
+		networkNames = append(networkNames, name)
+	}
+	sort.Strings(networkNames)
+	for _, name := range networkNames {
+		if net := containerInfo.NetworkSettings.Networks[name]; net != nil {
+			return net.IPAddress, net.GlobalIPv6Address, nil
+		}
 	}
 
 	return "", "", nil
@@ -420,6 +605,16 @@ func (d *dockerRuntime) RunContainer(ctx context.Context, runConfig *RunContaine
 			"net.ipv6.conf.all.forwarding":   "1",
 		}
 	}
+	// Additional sysctls are applied on top of the ones required for the IP family.
+	for name, value := range runConfig.Sysctls {
+		if hostConfig.Sysctls == nil {
+			hostConfig.Sysctls = map[string]string{}
+		}
+		hostConfig.Sysctls[name] = value
+	}
+
+	hostConfig.Resources.NanoCPUs = runConfig.Resources.NanoCPUs
+	hostConfig.Resources.Memory = runConfig.Resources.MemoryBytes
 
 	info, err := d.dockerClient.Info(ctx)
 	if err != nil {
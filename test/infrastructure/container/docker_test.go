@@ -0,0 +1,60 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package container
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestNewDockerClientWithOptions(t *testing.T) {
+	t.Run("uses the host from the options", func(t *testing.T) {
+		g := NewWithT(t)
+
+		rtc, err := NewDockerClientWithOptions(ClientOptions{Host: "tcp://docker.example.com:2376"})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(rtc.(*dockerRuntime).dockerClient.DaemonHost()).To(Equal("tcp://docker.example.com:2376"))
+	})
+	t.Run("fails if the TLS certificates are missing", func(t *testing.T) {
+		g := NewWithT(t)
+
+		certDir := t.TempDir()
+		g.Expect(os.WriteFile(filepath.Join(certDir, tlsCACertFile), nil, 0600)).To(Succeed())
+
+		_, err := NewDockerClientWithOptions(ClientOptions{Host: "tcp://docker.example.com:2376", TLSCertDir: certDir})
+		g.Expect(err).To(HaveOccurred())
+	})
+}
+
+func TestClientOptions(t *testing.T) {
+	g := NewWithT(t)
+
+	opts, err := ClientOptions{}.clientOpts()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(opts).To(BeEmpty())
+
+	certDir := t.TempDir()
+	for _, file := range []string{tlsCACertFile, tlsCertFile, tlsKeyFile} {
+		g.Expect(os.WriteFile(filepath.Join(certDir, file), nil, 0600)).To(Succeed())
+	}
+	opts, err = ClientOptions{Host: "tcp://docker.example.com:2376", TLSCertDir: certDir}.clientOpts()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(opts).To(HaveLen(2))
+}
@@ -25,6 +25,8 @@ var runContainerCallLog []RunContainerArgs
 var deleteContainerCallLog []string
 var killContainerCallLog []KillContainerArgs
 var execContainerCallLog []ExecContainerArgs
+var commitContainerCallLog []CommitContainerArgs
+var connectContainerToNetworkCallLog []ConnectContainerToNetworkArgs
 
 // RunContainerArgs contains the arguments passed to calls to RunContainer.
 type RunContainerArgs struct {
@@ -32,6 +34,18 @@ type RunContainerArgs struct {
 	Output    io.Writer
 }
 
+// CommitContainerArgs contains the arguments passed to calls to CommitContainer.
+type CommitContainerArgs struct {
+	ContainerName string
+	Config        *CommitContainerInput
+}
+
+// ConnectContainerToNetworkArgs contains the arguments passed to calls to ConnectContainerToNetwork.
+type ConnectContainerToNetworkArgs struct {
+	ContainerName string
+	Network       string
+}
+
 // KillContainerArgs contains the arguments passed to calls to Kill.
 type KillContainerArgs struct {
 	Container string
@@ -143,6 +157,80 @@ func (f *FakeRuntime) ResetKillContainerCallLogs() {
 	killContainerCallLog = []KillContainerArgs{}
 }
 
+// PauseContainer suspends all the processes of a running container.
+func (f *FakeRuntime) PauseContainer(_ context.Context, _ string) error {
+	return nil
+}
+
+// UnpauseContainer resumes all the processes of a paused container.
+func (f *FakeRuntime) UnpauseContainer(_ context.Context, _ string) error {
+	return nil
+}
+
+// CommitContainer creates an image from the filesystem of a container.
+func (f *FakeRuntime) CommitContainer(_ context.Context, containerName string, config *CommitContainerInput) error {
+	commitContainerCallLog = append(commitContainerCallLog, CommitContainerArgs{
+		ContainerName: containerName,
+		Config:        config,
+	})
+	return nil
+}
+
+// CommitContainerCalls returns the list of arguments passed to calls to the CommitContainer method.
+func (f *FakeRuntime) CommitContainerCalls() []CommitContainerArgs {
+	return commitContainerCallLog
+}
+
+// ResetCommitContainerCallLogs clears all existing records of any calls to the CommitContainer method.
+func (f *FakeRuntime) ResetCommitContainerCallLogs() {
+	commitContainerCallLog = []CommitContainerArgs{}
+}
+
+// GetContainerNetworks returns the networks the container has been connected to with ConnectContainerToNetwork.
+func (f *FakeRuntime) GetContainerNetworks(_ context.Context, containerName string) ([]string, error) {
+	networks := []string{}
+	for _, call := range connectContainerToNetworkCallLog {
+		if call.ContainerName == containerName {
+			networks = append(networks, call.Network)
+		}
+	}
+	return networks, nil
+}
+
+// ConnectContainerToNetwork attaches a running container to an existing network.
+func (f *FakeRuntime) ConnectContainerToNetwork(_ context.Context, containerName, network string) error {
+	connectContainerToNetworkCallLog = append(connectContainerToNetworkCallLog, ConnectContainerToNetworkArgs{
+		ContainerName: containerName,
+		Network:       network,
+	})
+	return nil
+}
+
+// ConnectContainerToNetworkCalls returns the list of arguments passed to calls to the ConnectContainerToNetwork method.
+func (f *FakeRuntime) ConnectContainerToNetworkCalls() []ConnectContainerToNetworkArgs {
+	return connectContainerToNetworkCallLog
+}
+
+// ResetConnectContainerToNetworkCallLogs clears all existing records of any calls to the ConnectContainerToNetwork method.
+func (f *FakeRuntime) ResetConnectContainerToNetworkCallLogs() {
+	connectContainerToNetworkCallLog = []ConnectContainerToNetworkArgs{}
+}
+
+// GetNetwork returns the details of a network; all the networks exist in the fake runtime.
+func (f *FakeRuntime) GetNetwork(_ context.Context, name string) (*Network, error) {
+	return &Network{Name: name}, nil
+}
+
+// CreateNetwork creates a network.
+func (f *FakeRuntime) CreateNetwork(_ context.Context, _ *CreateNetworkInput) error {
+	return nil
+}
+
+// DeleteNetwork deletes a network.
+func (f *FakeRuntime) DeleteNetwork(_ context.Context, _ string) error {
+	return nil
+}
+
 // GetContainerIPs inspects a container to get its IPv4 and IPv6 IP addresses.
 // Will not error if there is no IP address assigned. Calling code will need to
 // determine whether that is an issue or not.
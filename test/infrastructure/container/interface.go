@@ -42,6 +42,36 @@ type Runtime interface {
 	ContainerDebugInfo(ctx context.Context, containerName string, w io.Writer) error
 	DeleteContainer(ctx context.Context, containerName string) error
 	KillContainer(ctx context.Context, containerName, signal string) error
+	PauseContainer(ctx context.Context, containerName string) error
+	UnpauseContainer(ctx context.Context, containerName string) error
+	CommitContainer(ctx context.Context, containerName string, config *CommitContainerInput) error
+	GetContainerNetworks(ctx context.Context, containerName string) ([]string, error)
+	ConnectContainerToNetwork(ctx context.Context, containerName, network string) error
+	GetNetwork(ctx context.Context, name string) (*Network, error)
+	CreateNetwork(ctx context.Context, input *CreateNetworkInput) error
+	DeleteNetwork(ctx context.Context, name string) error
+}
+
+// Network contains the details of a container network.
+type Network struct {
+	// Name is the name of the network.
+	Name string
+	// Subnets are the subnets of the network, in CIDR notation.
+	Subnets []string
+	// MTU is the MTU of the network; zero if not configured.
+	MTU int
+	// Labels are the labels of the network.
+	Labels map[string]string
+}
+
+// CreateNetworkInput holds the configuration settings for creating a network.
+type CreateNetworkInput struct {
+	// Name is the name of the network.
+	Name string
+	// IPv6 enables IPv6 on the network.
+	IPv6 bool
+	// Labels to apply to the network.
+	Labels map[string]string
 }
 
 // Mount contains mount details.
@@ -101,6 +131,18 @@ type RunContainerInput struct {
 	RestartPolicy string
 	// Defines how the kindest/node image must be started.
 	KindMode kind.Mode
+	// Resources defines the resource limits for the container.
+	Resources Resources
+	// Sysctls are the kernel parameters to set in the container.
+	Sysctls map[string]string
+}
+
+// Resources defines the resource limits for a container; zero values mean no limit.
+type Resources struct {
+	// NanoCPUs is the CPU limit in units of 10^-9 CPUs.
+	NanoCPUs int64
+	// MemoryBytes is the memory limit in bytes.
+	MemoryBytes int64
 }
 
 // ExecContainerInput contains values for running exec on a container.
@@ -115,6 +157,15 @@ type ExecContainerInput struct {
 	EnvironmentVars []string
 }
 
+// CommitContainerInput contains values for committing a container to an image.
+type CommitContainerInput struct {
+	// Image is the name of the image to create.
+	Image string
+	// EntrypointPrefix, if set, is prepended to the entrypoint of the container in the new image, e.g. to
+	// run a script before the original entrypoint when starting containers from the image.
+	EntrypointPrefix []string
+}
+
 // FilterBuilder is a helper for building up filter strings of "key=value" or "key=name=value".
 type FilterBuilder map[string]map[string][]string
 
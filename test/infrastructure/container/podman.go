@@ -0,0 +1,155 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package container
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
+)
+
+const (
+	// RuntimeEnvVar is the environment variable used to select the container runtime.
+	RuntimeEnvVar = "CAPD_CONTAINER_RUNTIME"
+
+	// DockerRuntimeName is the name of the Docker container runtime.
+	DockerRuntimeName = "docker"
+
+	// PodmanRuntimeName is the name of the Podman container runtime.
+	PodmanRuntimeName = "podman"
+
+	// podmanHostEnvVar is the environment variable used by the podman CLI to point to a remote socket.
+	podmanHostEnvVar = "CONTAINER_HOST"
+
+	// podmanRootfulSocket is the socket of the podman system service when running as root.
+	podmanRootfulSocket = "/run/podman/podman.sock"
+
+	// defaultRegistry is the registry short image names are resolved against by Docker.
+	defaultRegistry = "docker.io"
+)
+
+// NewRuntimeClient gets a client for interacting with the container runtime selected
+// by the CAPD_CONTAINER_RUNTIME environment variable; Docker is used if the variable is not set.
+func NewRuntimeClient() (Runtime, error) {
+	return NewRuntimeClientFor(os.Getenv(RuntimeEnvVar), ClientOptions{})
+}
+
+// NewRuntimeClientFor gets a client for interacting with the container runtime with the given name,
+// using the given ClientOptions. An empty name selects Docker.
+func NewRuntimeClientFor(name string, options ClientOptions) (Runtime, error) {
+	switch strings.ToLower(name) {
+	case "", DockerRuntimeName:
+		return NewDockerClientWithOptions(options)
+	case PodmanRuntimeName:
+		return NewPodmanClientWithOptions(options)
+	default:
+		return nil, errors.Errorf("unknown container runtime %q, supported values are %q and %q", name, DockerRuntimeName, PodmanRuntimeName)
+	}
+}
+
+// podmanRuntime implements Runtime using the Docker compatible REST API exposed by the podman system service.
+type podmanRuntime struct {
+	*dockerRuntime
+}
+
+// NewPodmanClient gets a client for interacting with a Podman container runtime.
+// The podman socket is read from the CONTAINER_HOST environment variable if set, otherwise the
+// rootless socket under XDG_RUNTIME_DIR is used if it exists, falling back to the rootful socket.
+func NewPodmanClient() (Runtime, error) {
+	return NewPodmanClientWithOptions(ClientOptions{})
+}
+
+// NewPodmanClientWithOptions gets a client for interacting with a Podman container runtime
+// using the given ClientOptions, e.g. for connecting to a remote podman service using TLS.
+func NewPodmanClientWithOptions(options ClientOptions) (Runtime, error) {
+	if options.Host == "" {
+		options.Host = podmanHost()
+	}
+	opts, err := options.clientOpts()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to created podman runtime client")
+	}
+	podmanClient, err := client.NewClientWithOpts(append([]client.Opt{client.WithAPIVersionNegotiation()}, opts...)...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to created podman runtime client")
+	}
+	return &podmanRuntime{
+		dockerRuntime: &dockerRuntime{
+			dockerClient: podmanClient,
+		},
+	}, nil
+}
+
+// podmanHost returns the address of the podman socket.
+func podmanHost() string {
+	if host := os.Getenv(podmanHostEnvVar); host != "" {
+		return host
+	}
+	if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
+		socket := filepath.Join(runtimeDir, "podman", "podman.sock")
+		if _, err := os.Stat(socket); err == nil {
+			return "unix://" + socket
+		}
+	}
+	return "unix://" + podmanRootfulSocket
+}
+
+// PullContainerImageIfNotExists triggers the podman engine to pull an image, but only if it doesn't
+// already exist. This is important when we're using locally build images in CI which do not exist remotely.
+func (p *podmanRuntime) PullContainerImageIfNotExists(ctx context.Context, image string) error {
+	return p.dockerRuntime.PullContainerImageIfNotExists(ctx, qualifiedImageName(image))
+}
+
+// PullContainerImage triggers the podman engine to pull an image.
+func (p *podmanRuntime) PullContainerImage(ctx context.Context, image string) error {
+	return p.dockerRuntime.PullContainerImage(ctx, qualifiedImageName(image))
+}
+
+// ImageExistsLocally returns if the specified image exists in local container image cache.
+func (p *podmanRuntime) ImageExistsLocally(ctx context.Context, image string) (bool, error) {
+	return p.dockerRuntime.ImageExistsLocally(ctx, qualifiedImageName(image))
+}
+
+// RunContainer runs a container with the provided settings.
+func (p *podmanRuntime) RunContainer(ctx context.Context, runConfig *RunContainerInput, output io.Writer) error {
+	runConfig.Image = qualifiedImageName(runConfig.Image)
+	return p.dockerRuntime.RunContainer(ctx, runConfig, output)
+}
+
+// qualifiedImageName returns the image name qualified with the default registry if the image name
+// does not include a registry, e.g. "kindest/node:v1.28.0" becomes "docker.io/kindest/node:v1.28.0".
+// NOTE: Docker resolves short names against Docker Hub, while podman depends on the unqualified-search-registries
+// configured on the host and fails pulling short names in non-interactive mode when none is configured.
+func qualifiedImageName(image string) string {
+	if image == "" {
+		return image
+	}
+	first, rest, found := strings.Cut(image, "/")
+	if found && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		return image
+	}
+	if !found {
+		return fmt.Sprintf("%s/library/%s", defaultRegistry, image)
+	}
+	return fmt.Sprintf("%s/%s/%s", defaultRegistry, first, rest)
+}
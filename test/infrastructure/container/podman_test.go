@@ -0,0 +1,91 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package container
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestNewRuntimeClientFor(t *testing.T) {
+	g := NewWithT(t)
+
+	rtc, err := NewRuntimeClientFor("podman", ClientOptions{})
+	g.Expect(err).ToNot(HaveOccurred())
+	_, ok := rtc.(*podmanRuntime)
+	g.Expect(ok).To(BeTrue())
+
+	rtc, err = NewRuntimeClientFor("", ClientOptions{})
+	g.Expect(err).ToNot(HaveOccurred())
+	_, ok = rtc.(*dockerRuntime)
+	g.Expect(ok).To(BeTrue())
+
+	_, err = NewRuntimeClientFor("containerd", ClientOptions{})
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestPodmanHost(t *testing.T) {
+	t.Run("CONTAINER_HOST takes precedence", func(t *testing.T) {
+		g := NewWithT(t)
+		t.Setenv(podmanHostEnvVar, "tcp://localhost:8888")
+		t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+		g.Expect(podmanHost()).To(Equal("tcp://localhost:8888"))
+	})
+	t.Run("rootless socket is used if it exists", func(t *testing.T) {
+		g := NewWithT(t)
+		runtimeDir := t.TempDir()
+		g.Expect(os.MkdirAll(filepath.Join(runtimeDir, "podman"), 0750)).To(Succeed())
+		g.Expect(os.WriteFile(filepath.Join(runtimeDir, "podman", "podman.sock"), nil, 0600)).To(Succeed())
+		t.Setenv(podmanHostEnvVar, "")
+		t.Setenv("XDG_RUNTIME_DIR", runtimeDir)
+
+		g.Expect(podmanHost()).To(Equal("unix://" + filepath.Join(runtimeDir, "podman", "podman.sock")))
+	})
+	t.Run("falls back to the rootful socket", func(t *testing.T) {
+		g := NewWithT(t)
+		t.Setenv(podmanHostEnvVar, "")
+		t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+		g.Expect(podmanHost()).To(Equal("unix://" + podmanRootfulSocket))
+	})
+}
+
+func TestQualifiedImageName(t *testing.T) {
+	tests := []struct {
+		image string
+		want  string
+	}{
+		{image: "", want: ""},
+		{image: "haproxy:2.8", want: "docker.io/library/haproxy:2.8"},
+		{image: "kindest/node:v1.28.0", want: "docker.io/kindest/node:v1.28.0"},
+		{image: "docker.io/kindest/node:v1.28.0", want: "docker.io/kindest/node:v1.28.0"},
+		{image: "gcr.io/k8s-staging-cluster-api/capd-manager:dev", want: "gcr.io/k8s-staging-cluster-api/capd-manager:dev"},
+		{image: "localhost:5000/node:dev", want: "localhost:5000/node:dev"},
+		{image: "localhost/node:dev", want: "localhost/node:dev"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(qualifiedImageName(tt.image)).To(Equal(tt.want))
+		})
+	}
+}
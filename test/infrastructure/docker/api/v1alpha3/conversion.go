@@ -20,6 +20,7 @@ import (
 	apiconversion "k8s.io/apimachinery/pkg/conversion"
 	"sigs.k8s.io/controller-runtime/pkg/conversion"
 
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1beta1"
 	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
 )
@@ -45,9 +46,33 @@ func (src *DockerCluster) ConvertTo(dstRaw conversion.Hub) error {
 		dst.Spec.LoadBalancer.ImageTag = restored.Spec.LoadBalancer.ImageTag
 	}
 
+	dst.Spec.LoadBalancer.Type = restored.Spec.LoadBalancer.Type
+	dst.Spec.LoadBalancer.CustomConfigTemplateRef = restored.Spec.LoadBalancer.CustomConfigTemplateRef
+	dst.Spec.LoadBalancer.BackendPort = restored.Spec.LoadBalancer.BackendPort
+	dst.Spec.LoadBalancer.AdditionalFrontends = restored.Spec.LoadBalancer.AdditionalFrontends
+	dst.Spec.LoadBalancer.HealthCheck = restored.Spec.LoadBalancer.HealthCheck
+	dst.Spec.Network = restored.Spec.Network
+	dst.Status.Network = restored.Status.Network
+	restoreFailureDomains(restored.Spec.FailureDomains, dst.Spec.FailureDomains)
+	restoreFailureDomains(restored.Status.FailureDomains, dst.Status.FailureDomains)
+
 	return nil
 }
 
+// restoreFailureDomains restores the failure domain fields added with v1beta1.
+func restoreFailureDomains(restored, dst clusterv1.FailureDomains) {
+	for id, fd := range dst {
+		restoredFD, ok := restored[id]
+		if !ok {
+			continue
+		}
+		fd.Capacity = restoredFD.Capacity
+		fd.Labels = restoredFD.Labels
+		fd.Taints = restoredFD.Taints
+		dst[id] = fd
+	}
+}
+
 func (dst *DockerCluster) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*infrav1.DockerCluster)
 
@@ -78,13 +103,36 @@ func (dst *DockerClusterList) ConvertFrom(srcRaw conversion.Hub) error {
 func (src *DockerMachine) ConvertTo(dstRaw conversion.Hub) error {
 	dst := dstRaw.(*infrav1.DockerMachine)
 
-	return Convert_v1alpha3_DockerMachine_To_v1beta1_DockerMachine(src, dst, nil)
+	if err := Convert_v1alpha3_DockerMachine_To_v1beta1_DockerMachine(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Manually restore data.
+	restored := &infrav1.DockerMachine{}
+	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
+		return err
+	}
+
+	dst.Spec.Resources = restored.Spec.Resources
+	dst.Spec.Sysctls = restored.Spec.Sysctls
+	dst.Spec.Networks = restored.Spec.Networks
+
+	return nil
 }
 
 func (dst *DockerMachine) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*infrav1.DockerMachine)
 
-	return Convert_v1beta1_DockerMachine_To_v1alpha3_DockerMachine(src, dst, nil)
+	if err := Convert_v1beta1_DockerMachine_To_v1alpha3_DockerMachine(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Preserve Hub data on down-conversion except for metadata
+	if err := utilconversion.MarshalData(src, dst); err != nil {
+		return err
+	}
+
+	return nil
 }
 
 func (src *DockerMachineList) ConvertTo(dstRaw conversion.Hub) error {
@@ -113,6 +161,9 @@ func (src *DockerMachineTemplate) ConvertTo(dstRaw conversion.Hub) error {
 	}
 
 	dst.Spec.Template.ObjectMeta = restored.Spec.Template.ObjectMeta
+	dst.Spec.Template.Spec.Resources = restored.Spec.Template.Spec.Resources
+	dst.Spec.Template.Spec.Sysctls = restored.Spec.Template.Spec.Sysctls
+	dst.Spec.Template.Spec.Networks = restored.Spec.Template.Spec.Networks
 
 	return nil
 }
@@ -150,7 +201,17 @@ func Convert_v1beta1_DockerClusterSpec_To_v1alpha3_DockerClusterSpec(in *infrav1
 	return autoConvert_v1beta1_DockerClusterSpec_To_v1alpha3_DockerClusterSpec(in, out, s)
 }
 
+func Convert_v1beta1_DockerClusterStatus_To_v1alpha3_DockerClusterStatus(in *infrav1.DockerClusterStatus, out *DockerClusterStatus, s apiconversion.Scope) error {
+	// NOTE: custom conversion func is required because status.network has been added in v1beta1.
+	return autoConvert_v1beta1_DockerClusterStatus_To_v1alpha3_DockerClusterStatus(in, out, s)
+}
+
 func Convert_v1beta1_DockerMachineTemplateResource_To_v1alpha3_DockerMachineTemplateResource(in *infrav1.DockerMachineTemplateResource, out *DockerMachineTemplateResource, s apiconversion.Scope) error {
 	// NOTE: custom conversion func is required because spec.template.metadata has been added in v1beta1.
 	return autoConvert_v1beta1_DockerMachineTemplateResource_To_v1alpha3_DockerMachineTemplateResource(in, out, s)
 }
+
+func Convert_v1beta1_DockerMachineSpec_To_v1alpha3_DockerMachineSpec(in *infrav1.DockerMachineSpec, out *DockerMachineSpec, s apiconversion.Scope) error {
+	// NOTE: custom conversion func is required because spec.resources, spec.sysctls and spec.networks have been added in v1beta1.
+	return autoConvert_v1beta1_DockerMachineSpec_To_v1alpha3_DockerMachineSpec(in, out, s)
+}
@@ -20,6 +20,7 @@ import (
 	apiconversion "k8s.io/apimachinery/pkg/conversion"
 	"sigs.k8s.io/controller-runtime/pkg/conversion"
 
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1beta1"
 	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
 )
@@ -27,13 +28,56 @@ import (
 func (src *DockerCluster) ConvertTo(dstRaw conversion.Hub) error {
 	dst := dstRaw.(*infrav1.DockerCluster)
 
-	return Convert_v1alpha4_DockerCluster_To_v1beta1_DockerCluster(src, dst, nil)
+	if err := Convert_v1alpha4_DockerCluster_To_v1beta1_DockerCluster(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Manually restore data.
+	restored := &infrav1.DockerCluster{}
+	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
+		return err
+	}
+
+	dst.Spec.LoadBalancer.Type = restored.Spec.LoadBalancer.Type
+	dst.Spec.LoadBalancer.CustomConfigTemplateRef = restored.Spec.LoadBalancer.CustomConfigTemplateRef
+	dst.Spec.LoadBalancer.BackendPort = restored.Spec.LoadBalancer.BackendPort
+	dst.Spec.LoadBalancer.AdditionalFrontends = restored.Spec.LoadBalancer.AdditionalFrontends
+	dst.Spec.LoadBalancer.HealthCheck = restored.Spec.LoadBalancer.HealthCheck
+	dst.Spec.Network = restored.Spec.Network
+	dst.Status.Network = restored.Status.Network
+	restoreFailureDomains(restored.Spec.FailureDomains, dst.Spec.FailureDomains)
+	restoreFailureDomains(restored.Status.FailureDomains, dst.Status.FailureDomains)
+
+	return nil
+}
+
+// restoreFailureDomains restores the failure domain fields added with v1beta1.
+func restoreFailureDomains(restored, dst clusterv1.FailureDomains) {
+	for id, fd := range dst {
+		restoredFD, ok := restored[id]
+		if !ok {
+			continue
+		}
+		fd.Capacity = restoredFD.Capacity
+		fd.Labels = restoredFD.Labels
+		fd.Taints = restoredFD.Taints
+		dst[id] = fd
+	}
 }
 
 func (dst *DockerCluster) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*infrav1.DockerCluster)
 
-	return Convert_v1beta1_DockerCluster_To_v1alpha4_DockerCluster(src, dst, nil)
+	if err := Convert_v1beta1_DockerCluster_To_v1alpha4_DockerCluster(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Preserve Hub data on down-conversion except for metadata
+	if err := utilconversion.MarshalData(src, dst); err != nil {
+		return err
+	}
+
+	return nil
 }
 
 func (src *DockerClusterList) ConvertTo(dstRaw conversion.Hub) error {
@@ -62,6 +106,13 @@ func (src *DockerClusterTemplate) ConvertTo(dstRaw conversion.Hub) error {
 	}
 
 	dst.Spec.Template.ObjectMeta = restored.Spec.Template.ObjectMeta
+	dst.Spec.Template.Spec.LoadBalancer.Type = restored.Spec.Template.Spec.LoadBalancer.Type
+	dst.Spec.Template.Spec.LoadBalancer.CustomConfigTemplateRef = restored.Spec.Template.Spec.LoadBalancer.CustomConfigTemplateRef
+	dst.Spec.Template.Spec.LoadBalancer.BackendPort = restored.Spec.Template.Spec.LoadBalancer.BackendPort
+	dst.Spec.Template.Spec.LoadBalancer.AdditionalFrontends = restored.Spec.Template.Spec.LoadBalancer.AdditionalFrontends
+	dst.Spec.Template.Spec.LoadBalancer.HealthCheck = restored.Spec.Template.Spec.LoadBalancer.HealthCheck
+	dst.Spec.Template.Spec.Network = restored.Spec.Template.Spec.Network
+	restoreFailureDomains(restored.Spec.Template.Spec.FailureDomains, dst.Spec.Template.Spec.FailureDomains)
 
 	return nil
 }
@@ -96,13 +147,36 @@ func (dst *DockerClusterTemplateList) ConvertFrom(srcRaw conversion.Hub) error {
 func (src *DockerMachine) ConvertTo(dstRaw conversion.Hub) error {
 	dst := dstRaw.(*infrav1.DockerMachine)
 
-	return Convert_v1alpha4_DockerMachine_To_v1beta1_DockerMachine(src, dst, nil)
+	if err := Convert_v1alpha4_DockerMachine_To_v1beta1_DockerMachine(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Manually restore data.
+	restored := &infrav1.DockerMachine{}
+	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
+		return err
+	}
+
+	dst.Spec.Resources = restored.Spec.Resources
+	dst.Spec.Sysctls = restored.Spec.Sysctls
+	dst.Spec.Networks = restored.Spec.Networks
+
+	return nil
 }
 
 func (dst *DockerMachine) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*infrav1.DockerMachine)
 
-	return Convert_v1beta1_DockerMachine_To_v1alpha4_DockerMachine(src, dst, nil)
+	if err := Convert_v1beta1_DockerMachine_To_v1alpha4_DockerMachine(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Preserve Hub data on down-conversion except for metadata
+	if err := utilconversion.MarshalData(src, dst); err != nil {
+		return err
+	}
+
+	return nil
 }
 
 func (src *DockerMachineList) ConvertTo(dstRaw conversion.Hub) error {
@@ -131,6 +205,9 @@ func (src *DockerMachineTemplate) ConvertTo(dstRaw conversion.Hub) error {
 	}
 
 	dst.Spec.Template.ObjectMeta = restored.Spec.Template.ObjectMeta
+	dst.Spec.Template.Spec.Resources = restored.Spec.Template.Spec.Resources
+	dst.Spec.Template.Spec.Sysctls = restored.Spec.Template.Spec.Sysctls
+	dst.Spec.Template.Spec.Networks = restored.Spec.Template.Spec.Networks
 
 	return nil
 }
@@ -171,3 +248,23 @@ func Convert_v1beta1_DockerMachineTemplateResource_To_v1alpha4_DockerMachineTemp
 	// NOTE: custom conversion func is required because spec.template.metadata has been added in v1beta1.
 	return autoConvert_v1beta1_DockerMachineTemplateResource_To_v1alpha4_DockerMachineTemplateResource(in, out, s)
 }
+
+func Convert_v1beta1_DockerClusterSpec_To_v1alpha4_DockerClusterSpec(in *infrav1.DockerClusterSpec, out *DockerClusterSpec, s apiconversion.Scope) error {
+	// NOTE: custom conversion func is required because spec.network has been added in v1beta1.
+	return autoConvert_v1beta1_DockerClusterSpec_To_v1alpha4_DockerClusterSpec(in, out, s)
+}
+
+func Convert_v1beta1_DockerClusterStatus_To_v1alpha4_DockerClusterStatus(in *infrav1.DockerClusterStatus, out *DockerClusterStatus, s apiconversion.Scope) error {
+	// NOTE: custom conversion func is required because status.network has been added in v1beta1.
+	return autoConvert_v1beta1_DockerClusterStatus_To_v1alpha4_DockerClusterStatus(in, out, s)
+}
+
+func Convert_v1beta1_DockerLoadBalancer_To_v1alpha4_DockerLoadBalancer(in *infrav1.DockerLoadBalancer, out *DockerLoadBalancer, s apiconversion.Scope) error {
+	// NOTE: custom conversion func is required because spec.loadBalancer.type and spec.loadBalancer.customConfigTemplateRef have been added in v1beta1.
+	return autoConvert_v1beta1_DockerLoadBalancer_To_v1alpha4_DockerLoadBalancer(in, out, s)
+}
+
+func Convert_v1beta1_DockerMachineSpec_To_v1alpha4_DockerMachineSpec(in *infrav1.DockerMachineSpec, out *DockerMachineSpec, s apiconversion.Scope) error {
+	// NOTE: custom conversion func is required because spec.resources, spec.sysctls and spec.networks have been added in v1beta1.
+	return autoConvert_v1beta1_DockerMachineSpec_To_v1alpha4_DockerMachineSpec(in, out, s)
+}
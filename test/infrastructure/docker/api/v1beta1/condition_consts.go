@@ -16,7 +16,10 @@ limitations under the License.
 
 package v1beta1
 
-import clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+import (
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+)
 
 // Conditions and condition Reasons for the DockerMachine object.
 
@@ -53,10 +56,14 @@ const (
 	// 	It is set based on successful execution of bootstrap commands and on the existence of
 	//	the /run/cluster-api/bootstrap-success.complete file.
 	// The condition gets generated after ContainerProvisionedCondition is True.
+	// When bootstrap fails, the message of the condition reports the failed phase, e.g. the file being written
+	// or the command being run. The condition is also set on DockerMachinePools, summarizing their instances.
+	//
+	// The condition is mirrored on the Machine, see clusterv1.BootstrapExecSucceededCondition.
 	//
 	// NOTE as a difference from other providers, container provisioning and bootstrap are directly managed
 	// by the DockerMachine controller (not by cloud-init).
-	BootstrapExecSucceededCondition clusterv1.ConditionType = "BootstrapExecSucceeded"
+	BootstrapExecSucceededCondition = clusterv1.BootstrapExecSucceededCondition
 
 	// BootstrappingReason documents (Severity=Info) a DockerMachine currently executing the bootstrap
 	// script that creates the Kubernetes node on the newly provisioned machine infrastructure.
@@ -68,6 +75,12 @@ const (
 	BootstrapFailedReason = "BootstrapFailed"
 )
 
+const (
+	// PreProvisionedCondition documents a DockerMachine in a MachineWarmPool having the container hosting the machine
+	// created, so it can be claimed by a MachineSet; the machine is bootstrapped after being claimed.
+	PreProvisionedCondition = expv1.PreProvisionedCondition
+)
+
 // Conditions and condition Reasons for the DockerCluster object.
 
 const (
@@ -82,4 +95,17 @@ const (
 	// an error while provisioning the container that provides the cluster load balancer.; those kind of
 	// errors are usually transient and failed provisioning are automatically re-tried by the controller.
 	LoadBalancerProvisioningFailedReason = "LoadBalancerProvisioningFailed"
+
+	// NetworkAvailableCondition documents the availability of the docker network the containers of the cluster
+	// are attached to.
+	NetworkAvailableCondition clusterv1.ConditionType = "NetworkAvailable"
+
+	// NetworkNotFoundReason (Severity=Warning) documents a DockerCluster controller detecting that an externally
+	// managed docker network does not exist; the controller waits for the network to be created.
+	NetworkNotFoundReason = "NetworkNotFound"
+
+	// NetworkProvisioningFailedReason (Severity=Warning) documents a DockerCluster controller detecting
+	// an error while provisioning the docker network; those kind of errors are usually transient and failed
+	// provisioning are automatically re-tried by the controller.
+	NetworkProvisioningFailedReason = "NetworkProvisioningFailed"
 )
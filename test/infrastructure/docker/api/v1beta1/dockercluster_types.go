@@ -17,6 +17,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -26,6 +27,15 @@ const (
 	// ClusterFinalizer allows DockerClusterReconciler to clean up resources associated with DockerCluster before
 	// removing it from the apiserver.
 	ClusterFinalizer = "dockercluster.infrastructure.cluster.x-k8s.io"
+
+	// NetworkExternallyManagedAnnotation can be set on a DockerCluster to use a docker network created outside of
+	// CAPD, e.g. with a custom subnet or MTU; CAPD then only checks that the network defined in spec.network.name
+	// exists, and never creates nor deletes it.
+	// NOTE: The default kind network is always considered externally managed.
+	NetworkExternallyManagedAnnotation = "dockercluster.infrastructure.cluster.x-k8s.io/network-externally-managed"
+
+	// DefaultNetworkName is the name of the docker network used when spec.network.name is not set.
+	DefaultNetworkName = "kind"
 )
 
 // DockerClusterSpec defines the desired state of DockerCluster.
@@ -47,24 +57,158 @@ type DockerClusterSpec struct {
 	// LoadBalancer allows defining configurations for the cluster load balancer.
 	// +optional
 	LoadBalancer DockerLoadBalancer `json:"loadBalancer,omitempty"`
+
+	// Network allows defining the docker network the containers of the cluster are attached to.
+	// +optional
+	Network DockerNetwork `json:"network,omitempty"`
+}
+
+// DockerNetwork defines the docker network the containers of the cluster are attached to.
+type DockerNetwork struct {
+	// Name of the docker network.
+	// If not set, the kind network will be used.
+	// If the DockerCluster has the network-externally-managed annotation, the network must already exist;
+	// otherwise it is created if missing, and deleted with the DockerCluster.
+	// +kubebuilder:validation:MaxLength=128
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`
+	// +optional
+	Name string `json:"name,omitempty"`
 }
 
+// GetName returns the name of the docker network, defaulting to kind.
+func (n *DockerNetwork) GetName() string {
+	if n.Name == "" {
+		return DefaultNetworkName
+	}
+	return n.Name
+}
+
+// DockerLoadBalancerType defines the implementation used for the cluster load balancer.
+type DockerLoadBalancerType string
+
+const (
+	// HAProxyLoadBalancerType uses haproxy for the cluster load balancer.
+	HAProxyLoadBalancerType DockerLoadBalancerType = "haproxy"
+
+	// NginxLoadBalancerType uses nginx for the cluster load balancer.
+	NginxLoadBalancerType DockerLoadBalancerType = "nginx"
+
+	// EnvoyLoadBalancerType uses envoy for the cluster load balancer.
+	EnvoyLoadBalancerType DockerLoadBalancerType = "envoy"
+)
+
 // DockerLoadBalancer allows defining configurations for the cluster load balancer.
 type DockerLoadBalancer struct {
+	// Type is the implementation used for the cluster load balancer, one of haproxy, nginx or envoy.
+	// If not set, haproxy will be used.
+	// +kubebuilder:validation:Enum=haproxy;nginx;envoy
+	// +optional
+	Type DockerLoadBalancerType `json:"type,omitempty"`
+
 	// ImageMeta allows customizing the image used for the cluster load balancer.
+	// If not set, the default image for the load balancer type will be used.
 	ImageMeta `json:",inline"`
+
+	// CustomConfigTemplateRef allows replacing the default config template of the load balancer.
+	// The referenced ConfigMap must be in the same namespace as the DockerCluster and must contain
+	// the template under the "value" key; the template is rendered with the same data as the default one.
+	// NOTE: the template must be valid for the load balancer type in use; this is meant for testing
+	// control plane endpoint behaviors and it is not validated in any way.
+	// +optional
+	CustomConfigTemplateRef *corev1.LocalObjectReference `json:"customConfigTemplateRef,omitempty"`
+
+	// BackendPort is the port the API server listens on in the control plane Machines.
+	// The load balancer listens on the port of the control plane endpoint and forwards the traffic to this port.
+	// If not set, 6443 will be used.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	BackendPort int32 `json:"backendPort,omitempty"`
+
+	// AdditionalFrontends are additional ports the load balancer listens on, forwarding the traffic
+	// to all the Machines of the cluster, e.g. to reach an ingress controller.
+	// NOTE: additional frontends are reachable on the load balancer address in the docker network; they
+	// are not published on the host.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	AdditionalFrontends []DockerLoadBalancerFrontend `json:"additionalFrontends,omitempty"`
+
+	// HealthCheck allows tuning the health checks of the load balancer backends.
+	// +optional
+	HealthCheck *DockerLoadBalancerHealthCheck `json:"healthCheck,omitempty"`
+}
+
+// GetBackendPort returns the port of the API server in the control plane Machines, defaulting to 6443.
+func (l *DockerLoadBalancer) GetBackendPort() int32 {
+	if l.BackendPort == 0 {
+		return 6443
+	}
+	return l.BackendPort
+}
+
+// DockerLoadBalancerFrontend defines an additional port of the cluster load balancer.
+type DockerLoadBalancerFrontend struct {
+	// Name of the frontend; it must be unique within the load balancer.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// Port is the port the load balancer listens on.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+
+	// BackendPort is the port on the Machines the traffic is forwarded to.
+	// If not set, Port will be used.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	BackendPort int32 `json:"backendPort,omitempty"`
+}
+
+// DockerLoadBalancerHealthCheck defines the health checks of the load balancer backends.
+// NOTE: nginx only supports passive health checks, so only UnhealthyThreshold is used with the nginx load balancer.
+type DockerLoadBalancerHealthCheck struct {
+	// IntervalSeconds is the interval between two consecutive health checks of a backend.
+	// If not set, 2 will be used.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	IntervalSeconds int32 `json:"intervalSeconds,omitempty"`
+
+	// HealthyThreshold is the number of consecutive successful health checks required to consider a backend healthy.
+	// If not set, 2 will be used.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	HealthyThreshold int32 `json:"healthyThreshold,omitempty"`
+
+	// UnhealthyThreshold is the number of consecutive failed health checks required to consider a backend unhealthy.
+	// If not set, 3 will be used.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	UnhealthyThreshold int32 `json:"unhealthyThreshold,omitempty"`
+}
+
+// GetType returns the load balancer type, defaulting to haproxy.
+func (l *DockerLoadBalancer) GetType() DockerLoadBalancerType {
+	if l.Type == "" {
+		return HAProxyLoadBalancerType
+	}
+	return l.Type
 }
 
 // ImageMeta allows customizing the image used for components that are not
 // originated from the Kubernetes/Kubernetes release process.
 type ImageMeta struct {
-	// ImageRepository sets the container registry to pull the haproxy image from.
-	// if not set, "kindest" will be used instead.
+	// ImageRepository sets the container registry to pull the load balancer image from.
+	// if not set, the default repository for the load balancer type will be used instead,
+	// e.g. "kindest" for haproxy.
 	// +optional
 	ImageRepository string `json:"imageRepository,omitempty"`
 
-	// ImageTag allows to specify a tag for the haproxy image.
-	// if not set, "v20210715-a6da3463" will be used instead.
+	// ImageTag allows to specify a tag for the load balancer image.
+	// if not set, the default tag for the load balancer type will be used instead.
 	// +optional
 	ImageTag string `json:"imageTag,omitempty"`
 }
@@ -80,11 +224,33 @@ type DockerClusterStatus struct {
 	// +optional
 	FailureDomains clusterv1.FailureDomains `json:"failureDomains,omitempty"`
 
+	// Network reports the docker network the containers of the cluster are attached to.
+	// +optional
+	Network *DockerNetworkStatus `json:"network,omitempty"`
+
 	// Conditions defines current service state of the DockerCluster.
 	// +optional
 	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
 }
 
+// DockerNetworkStatus reports the docker network the containers of the cluster are attached to.
+type DockerNetworkStatus struct {
+	// Name of the docker network.
+	Name string `json:"name"`
+
+	// Subnets of the docker network, in CIDR notation.
+	// +optional
+	Subnets []string `json:"subnets,omitempty"`
+
+	// MTU of the docker network, if configured.
+	// +optional
+	MTU int32 `json:"mtu,omitempty"`
+
+	// ExternallyManaged is true if the docker network is not managed by CAPD.
+	// +optional
+	ExternallyManaged bool `json:"externallyManaged,omitempty"`
+}
+
 // APIEndpoint represents a reachable Kubernetes API endpoint.
 type APIEndpoint struct {
 	// Host is the hostname on which the API server is serving.
@@ -17,6 +17,8 @@ limitations under the License.
 package v1beta1
 
 import (
+	"fmt"
+
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -53,7 +55,24 @@ func (c *DockerCluster) ValidateCreate() (admission.Warnings, error) {
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
-func (c *DockerCluster) ValidateUpdate(_ runtime.Object) (admission.Warnings, error) {
+func (c *DockerCluster) ValidateUpdate(oldRaw runtime.Object) (admission.Warnings, error) {
+	old, ok := oldRaw.(*DockerCluster)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a DockerCluster but got a %T", oldRaw))
+	}
+
+	allErrs := validateDockerClusterSpec(c.Spec)
+	// NOTE: the load balancer container is not re-created when the type changes.
+	if c.Spec.LoadBalancer.GetType() != old.Spec.LoadBalancer.GetType() {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "loadBalancer", "type"), c.Spec.LoadBalancer.Type, "field is immutable"))
+	}
+	// NOTE: the containers of the cluster are not moved to another network when the network changes.
+	if c.Spec.Network.GetName() != old.Spec.Network.GetName() {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "network", "name"), c.Spec.Network.Name, "field is immutable"))
+	}
+	if len(allErrs) > 0 {
+		return nil, apierrors.NewInvalid(GroupVersion.WithKind("DockerCluster").GroupKind(), c.Name, allErrs)
+	}
 	return nil, nil
 }
 
@@ -68,6 +87,25 @@ func defaultDockerClusterSpec(s *DockerClusterSpec) {
 	}
 }
 
-func validateDockerClusterSpec(_ DockerClusterSpec) field.ErrorList {
-	return nil
+func validateDockerClusterSpec(spec DockerClusterSpec) field.ErrorList {
+	var allErrs field.ErrorList
+	if spec.LoadBalancer.CustomConfigTemplateRef != nil && spec.LoadBalancer.CustomConfigTemplateRef.Name == "" {
+		allErrs = append(allErrs, field.Required(field.NewPath("spec", "loadBalancer", "customConfigTemplateRef", "name"), "name is required"))
+	}
+
+	// Additional frontends must not clash with each other or with the control plane endpoint.
+	ports := map[int32]bool{}
+	if spec.ControlPlaneEndpoint.Port != 0 {
+		ports[int32(spec.ControlPlaneEndpoint.Port)] = true
+	}
+	for i, frontend := range spec.LoadBalancer.AdditionalFrontends {
+		if frontend.Name == "control-plane" || frontend.Name == "kube-apiservers" {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "loadBalancer", "additionalFrontends").Index(i).Child("name"), frontend.Name, "name is reserved for the control plane frontend"))
+		}
+		if ports[frontend.Port] {
+			allErrs = append(allErrs, field.Duplicate(field.NewPath("spec", "loadBalancer", "additionalFrontends").Index(i).Child("port"), frontend.Port))
+		}
+		ports[frontend.Port] = true
+	}
+	return allErrs
 }
@@ -0,0 +1,179 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDockerClusterValidateCreate(t *testing.T) {
+	tests := []struct {
+		name             string
+		controlPlanePort int
+		loadBalancer     DockerLoadBalancer
+		expectErr        bool
+	}{
+		{
+			name:         "default load balancer",
+			loadBalancer: DockerLoadBalancer{},
+		},
+		{
+			name: "load balancer with a custom config template",
+			loadBalancer: DockerLoadBalancer{
+				Type:                    NginxLoadBalancerType,
+				CustomConfigTemplateRef: &corev1.LocalObjectReference{Name: "lb-config"},
+			},
+		},
+		{
+			name: "load balancer with a custom config template without name",
+			loadBalancer: DockerLoadBalancer{
+				CustomConfigTemplateRef: &corev1.LocalObjectReference{},
+			},
+			expectErr: true,
+		},
+		{
+			name:             "load balancer with additional frontends",
+			controlPlanePort: 6443,
+			loadBalancer: DockerLoadBalancer{
+				AdditionalFrontends: []DockerLoadBalancerFrontend{
+					{Name: "http", Port: 80, BackendPort: 30080},
+					{Name: "https", Port: 443},
+				},
+			},
+		},
+		{
+			name:             "load balancer with additional frontends using the same port",
+			controlPlanePort: 6443,
+			loadBalancer: DockerLoadBalancer{
+				AdditionalFrontends: []DockerLoadBalancerFrontend{
+					{Name: "http", Port: 80},
+					{Name: "http-alt", Port: 80},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name:             "load balancer with an additional frontend using a reserved name",
+			controlPlanePort: 6443,
+			loadBalancer: DockerLoadBalancer{
+				AdditionalFrontends: []DockerLoadBalancerFrontend{
+					{Name: "control-plane", Port: 80},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name:             "load balancer with an additional frontend using the control plane port",
+			controlPlanePort: 6443,
+			loadBalancer: DockerLoadBalancer{
+				AdditionalFrontends: []DockerLoadBalancerFrontend{
+					{Name: "api", Port: 6443},
+				},
+			},
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			c := &DockerCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "dockercluster-test", Namespace: "test-namespace"},
+				Spec: DockerClusterSpec{
+					ControlPlaneEndpoint: APIEndpoint{Port: tt.controlPlanePort},
+					LoadBalancer:         tt.loadBalancer,
+				},
+			}
+			_, err := c.ValidateCreate()
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+		})
+	}
+}
+
+func TestDockerClusterValidateUpdate(t *testing.T) {
+	tests := []struct {
+		name       string
+		oldType    DockerLoadBalancerType
+		newType    DockerLoadBalancerType
+		oldNetwork string
+		newNetwork string
+		expectErr  bool
+	}{
+		{
+			name:    "unchanged type",
+			oldType: EnvoyLoadBalancerType,
+			newType: EnvoyLoadBalancerType,
+		},
+		{
+			name:    "setting the default type explicitly",
+			oldType: "",
+			newType: HAProxyLoadBalancerType,
+		},
+		{
+			name:      "changing type",
+			oldType:   HAProxyLoadBalancerType,
+			newType:   NginxLoadBalancerType,
+			expectErr: true,
+		},
+		{
+			name:       "setting the default network explicitly",
+			oldNetwork: "",
+			newNetwork: DefaultNetworkName,
+		},
+		{
+			name:       "changing network",
+			oldNetwork: "custom",
+			newNetwork: "other",
+			expectErr:  true,
+		},
+		{
+			name:       "unsetting a custom network",
+			oldNetwork: "custom",
+			newNetwork: "",
+			expectErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			oldCluster := &DockerCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "dockercluster-test", Namespace: "test-namespace"},
+				Spec: DockerClusterSpec{
+					LoadBalancer: DockerLoadBalancer{Type: tt.oldType},
+					Network:      DockerNetwork{Name: tt.oldNetwork},
+				},
+			}
+			newCluster := oldCluster.DeepCopy()
+			newCluster.Spec.LoadBalancer.Type = tt.newType
+			newCluster.Spec.Network.Name = tt.newNetwork
+
+			_, err := newCluster.ValidateUpdate(oldCluster)
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+		})
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -26,6 +27,21 @@ const (
 	// MachineFinalizer allows ReconcileDockerMachine to clean up resources associated with DockerMachine before
 	// removing it from the apiserver.
 	MachineFinalizer = "dockermachine.infrastructure.cluster.x-k8s.io"
+
+	// SnapshotAnnotation can be set on a provisioned DockerMachine to save the container hosting the machine
+	// to the image set as annotation value; the annotation is removed once the snapshot is completed, and
+	// the image is recorded in the SnapshotImageAnnotation.
+	// NOTE: Snapshots are intended to speed up iterative test runs, and they should be taken when the cluster is idle.
+	SnapshotAnnotation = "dockermachine.infrastructure.cluster.x-k8s.io/snapshot"
+
+	// SnapshotImageAnnotation is set on a DockerMachine to the image of the last snapshot of the machine.
+	SnapshotImageAnnotation = "dockermachine.infrastructure.cluster.x-k8s.io/snapshot-image"
+
+	// RestoreFromSnapshotAnnotation can be set on a DockerMachine before it is provisioned to create the container
+	// hosting the machine from a snapshot image instead of running bootstrap.
+	// NOTE: The snapshot must have been taken from a machine with the same name, belonging to a Cluster
+	// with the same name and secrets, e.g. a Cluster restored using clusterctl move.
+	RestoreFromSnapshotAnnotation = "dockermachine.infrastructure.cluster.x-k8s.io/restore-from-snapshot"
 )
 
 // DockerMachineSpec defines the desired state of DockerMachine.
@@ -49,6 +65,20 @@ type DockerMachineSpec struct {
 	// +optional
 	ExtraMounts []Mount `json:"extraMounts,omitempty"`
 
+	// Resources defines the CPU and memory limits for the node container.
+	// +optional
+	Resources *DockerMachineResources `json:"resources,omitempty"`
+
+	// Sysctls defines additional kernel parameters to set in the node container,
+	// e.g. net.ipv4.ip_forward: "1".
+	// +optional
+	Sysctls map[string]string `json:"sysctls,omitempty"`
+
+	// Networks is a list of additional user-defined docker networks the node container is attached to,
+	// in addition to the default kind network. The networks must already exist.
+	// +optional
+	Networks []string `json:"networks,omitempty"`
+
 	// Bootstrapped is true when the kubeadm bootstrapping has been run
 	// against this machine
 	//
@@ -58,6 +88,17 @@ type DockerMachineSpec struct {
 	Bootstrapped bool `json:"bootstrapped,omitempty"`
 }
 
+// DockerMachineResources defines the resource limits for a node container.
+type DockerMachineResources struct {
+	// CPU is the maximum amount of CPU the node container can use, e.g. 2 or 500m.
+	// +optional
+	CPU *resource.Quantity `json:"cpu,omitempty"`
+
+	// Memory is the maximum amount of memory the node container can use, e.g. 4Gi.
+	// +optional
+	Memory *resource.Quantity `json:"memory,omitempty"`
+}
+
 // Mount specifies a host volume to mount into a container.
 // This is a simplified version of kind v1alpha4.Mount types.
 type Mount struct {
@@ -22,6 +22,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	"k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	apiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
@@ -111,7 +112,8 @@ func (in *DockerClusterSpec) DeepCopyInto(out *DockerClusterSpec) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
-	out.LoadBalancer = in.LoadBalancer
+	in.LoadBalancer.DeepCopyInto(&out.LoadBalancer)
+	out.Network = in.Network
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DockerClusterSpec.
@@ -134,6 +136,11 @@ func (in *DockerClusterStatus) DeepCopyInto(out *DockerClusterStatus) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.Network != nil {
+		in, out := &in.Network, &out.Network
+		*out = new(DockerNetworkStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make(apiv1beta1.Conditions, len(*in))
@@ -248,6 +255,21 @@ func (in *DockerClusterTemplateSpec) DeepCopy() *DockerClusterTemplateSpec {
 func (in *DockerLoadBalancer) DeepCopyInto(out *DockerLoadBalancer) {
 	*out = *in
 	out.ImageMeta = in.ImageMeta
+	if in.CustomConfigTemplateRef != nil {
+		in, out := &in.CustomConfigTemplateRef, &out.CustomConfigTemplateRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.AdditionalFrontends != nil {
+		in, out := &in.AdditionalFrontends, &out.AdditionalFrontends
+		*out = make([]DockerLoadBalancerFrontend, len(*in))
+		copy(*out, *in)
+	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(DockerLoadBalancerHealthCheck)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DockerLoadBalancer.
@@ -260,6 +282,36 @@ func (in *DockerLoadBalancer) DeepCopy() *DockerLoadBalancer {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DockerLoadBalancerFrontend) DeepCopyInto(out *DockerLoadBalancerFrontend) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DockerLoadBalancerFrontend.
+func (in *DockerLoadBalancerFrontend) DeepCopy() *DockerLoadBalancerFrontend {
+	if in == nil {
+		return nil
+	}
+	out := new(DockerLoadBalancerFrontend)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DockerLoadBalancerHealthCheck) DeepCopyInto(out *DockerLoadBalancerHealthCheck) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DockerLoadBalancerHealthCheck.
+func (in *DockerLoadBalancerHealthCheck) DeepCopy() *DockerLoadBalancerHealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(DockerLoadBalancerHealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DockerMachine) DeepCopyInto(out *DockerMachine) {
 	*out = *in
@@ -319,6 +371,31 @@ func (in *DockerMachineList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DockerMachineResources) DeepCopyInto(out *DockerMachineResources) {
+	*out = *in
+	if in.CPU != nil {
+		in, out := &in.CPU, &out.CPU
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DockerMachineResources.
+func (in *DockerMachineResources) DeepCopy() *DockerMachineResources {
+	if in == nil {
+		return nil
+	}
+	out := new(DockerMachineResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DockerMachineSpec) DeepCopyInto(out *DockerMachineSpec) {
 	*out = *in
@@ -337,6 +414,23 @@ func (in *DockerMachineSpec) DeepCopyInto(out *DockerMachineSpec) {
 		*out = make([]Mount, len(*in))
 		copy(*out, *in)
 	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(DockerMachineResources)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Sysctls != nil {
+		in, out := &in.Sysctls, &out.Sysctls
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Networks != nil {
+		in, out := &in.Networks, &out.Networks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DockerMachineSpec.
@@ -467,6 +561,41 @@ func (in *DockerMachineTemplateSpec) DeepCopy() *DockerMachineTemplateSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DockerNetwork) DeepCopyInto(out *DockerNetwork) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DockerNetwork.
+func (in *DockerNetwork) DeepCopy() *DockerNetwork {
+	if in == nil {
+		return nil
+	}
+	out := new(DockerNetwork)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DockerNetworkStatus) DeepCopyInto(out *DockerNetworkStatus) {
+	*out = *in
+	if in.Subnets != nil {
+		in, out := &in.Subnets, &out.Subnets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DockerNetworkStatus.
+func (in *DockerNetworkStatus) DeepCopy() *DockerNetworkStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DockerNetworkStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ImageMeta) DeepCopyInto(out *ImageMeta) {
 	*out = *in
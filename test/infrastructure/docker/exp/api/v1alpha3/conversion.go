@@ -17,21 +17,44 @@ limitations under the License.
 package v1alpha3
 
 import (
+	apiconversion "k8s.io/apimachinery/pkg/conversion"
 	"sigs.k8s.io/controller-runtime/pkg/conversion"
 
 	infraexpv1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/exp/api/v1beta1"
+	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
 )
 
 func (src *DockerMachinePool) ConvertTo(dstRaw conversion.Hub) error {
 	dst := dstRaw.(*infraexpv1.DockerMachinePool)
 
-	return Convert_v1alpha3_DockerMachinePool_To_v1beta1_DockerMachinePool(src, dst, nil)
+	if err := Convert_v1alpha3_DockerMachinePool_To_v1beta1_DockerMachinePool(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Manually restore data.
+	restored := &infraexpv1.DockerMachinePool{}
+	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
+		return err
+	}
+
+	dst.Spec.Template.Networks = restored.Spec.Template.Networks
+
+	return nil
 }
 
 func (dst *DockerMachinePool) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*infraexpv1.DockerMachinePool)
 
-	return Convert_v1beta1_DockerMachinePool_To_v1alpha3_DockerMachinePool(src, dst, nil)
+	if err := Convert_v1beta1_DockerMachinePool_To_v1alpha3_DockerMachinePool(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Preserve Hub data on down-conversion except for metadata
+	if err := utilconversion.MarshalData(src, dst); err != nil {
+		return err
+	}
+
+	return nil
 }
 
 func (src *DockerMachinePoolList) ConvertTo(dstRaw conversion.Hub) error {
@@ -45,3 +68,8 @@ func (dst *DockerMachinePoolList) ConvertFrom(srcRaw conversion.Hub) error {
 
 	return Convert_v1beta1_DockerMachinePoolList_To_v1alpha3_DockerMachinePoolList(src, dst, nil)
 }
+
+func Convert_v1beta1_DockerMachinePoolMachineTemplate_To_v1alpha3_DockerMachinePoolMachineTemplate(in *infraexpv1.DockerMachinePoolMachineTemplate, out *DockerMachinePoolMachineTemplate, s apiconversion.Scope) error {
+	// NOTE: custom conversion func is required because spec.template.networks has been added in v1beta1.
+	return autoConvert_v1beta1_DockerMachinePoolMachineTemplate_To_v1alpha3_DockerMachinePoolMachineTemplate(in, out, s)
+}
@@ -31,7 +31,9 @@ const (
 // DockerMachinePoolMachineTemplate defines the desired state of DockerMachine.
 type DockerMachinePoolMachineTemplate struct {
 	// CustomImage allows customizing the container image that is used for
-	// running the machine
+	// running the machine.
+	// Changing the image replaces the existing instances: the new image is pulled first, and the
+	// existing instances are deleted once the instances using the new image are ready.
 	// +optional
 	CustomImage string `json:"customImage,omitempty"`
 
@@ -44,6 +46,11 @@ type DockerMachinePoolMachineTemplate struct {
 	// These may be used to bind a hostPath
 	// +optional
 	ExtraMounts []infrav1.Mount `json:"extraMounts,omitempty"`
+
+	// Networks is a list of additional user-defined docker networks the node containers are attached to,
+	// in addition to the default kind network. The networks must already exist.
+	// +optional
+	Networks []string `json:"networks,omitempty"`
 }
 
 // DockerMachinePoolSpec defines the desired state of DockerMachinePool.
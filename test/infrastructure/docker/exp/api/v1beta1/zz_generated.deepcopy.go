@@ -129,6 +129,11 @@ func (in *DockerMachinePoolMachineTemplate) DeepCopyInto(out *DockerMachinePoolM
 		*out = make([]apiv1beta1.Mount, len(*in))
 		copy(*out, *in)
 	}
+	if in.Networks != nil {
+		in, out := &in.Networks, &out.Networks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DockerMachinePoolMachineTemplate.
@@ -39,11 +39,15 @@ import (
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	utilexp "sigs.k8s.io/cluster-api/exp/util"
 	"sigs.k8s.io/cluster-api/test/infrastructure/container"
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1beta1"
 	infraexpv1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api/test/infrastructure/docker/exp/internal/docker"
 	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/finalizers"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
+	"sigs.k8s.io/cluster-api/util/requeue"
 )
 
 // DockerMachinePoolReconciler reconciles a DockerMachinePool object.
@@ -55,6 +59,9 @@ type DockerMachinePoolReconciler struct {
 
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
+
+	// requeueBackoff is used to requeue DockerMachinePools while machines are still provisioning.
+	requeueBackoff *requeue.Backoff
 }
 
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=dockermachinepools,verbs=get;list;watch;create;update;patch;delete
@@ -70,6 +77,7 @@ func (r *DockerMachinePoolReconciler) Reconcile(ctx context.Context, req ctrl.Re
 	dockerMachinePool := &infraexpv1.DockerMachinePool{}
 	if err := r.Client.Get(ctx, req.NamespacedName, dockerMachinePool); err != nil {
 		if apierrors.IsNotFound(err) {
+			r.requeueBackoff.Reset(req.NamespacedName)
 			return ctrl.Result{}, nil
 		}
 		return ctrl.Result{}, err
@@ -103,6 +111,11 @@ func (r *DockerMachinePoolReconciler) Reconcile(ctx context.Context, req ctrl.Re
 	log = log.WithValues("Cluster", klog.KObj(cluster))
 	ctx = ctrl.LoggerInto(ctx, log)
 
+	// Add finalizer first if not set to avoid the race condition between init and delete.
+	if finalizerAdded, err := finalizers.EnsureFinalizer(ctx, r.Client, dockerMachinePool, infraexpv1.MachinePoolFinalizer); err != nil || finalizerAdded {
+		return ctrl.Result{}, err
+	}
+
 	// Initialize the patch helper
 	patchHelper, err := patch.NewHelper(dockerMachinePool, r.Client)
 	if err != nil {
@@ -121,16 +134,10 @@ func (r *DockerMachinePoolReconciler) Reconcile(ctx context.Context, req ctrl.Re
 
 	// Handle deleted machines
 	if !dockerMachinePool.ObjectMeta.DeletionTimestamp.IsZero() {
+		r.requeueBackoff.Reset(req.NamespacedName)
 		return ctrl.Result{}, r.reconcileDelete(ctx, cluster, machinePool, dockerMachinePool)
 	}
 
-	// Add finalizer first if not set to avoid the race condition between init and delete.
-	// Note: Finalizers in general can only be added when the deletionTimestamp is not set.
-	if !controllerutil.ContainsFinalizer(dockerMachinePool, infraexpv1.MachinePoolFinalizer) {
-		controllerutil.AddFinalizer(dockerMachinePool, infraexpv1.MachinePoolFinalizer)
-		return ctrl.Result{}, nil
-	}
-
 	// Handle non-deleted machines
 	res, err = r.reconcileNormal(ctx, cluster, machinePool, dockerMachinePool)
 	// Requeue if the reconcile failed because the ClusterCacheTracker was locked for
@@ -149,6 +156,12 @@ func (r *DockerMachinePoolReconciler) SetupWithManager(ctx context.Context, mgr
 		return err
 	}
 
+	r.requeueBackoff = &requeue.Backoff{
+		Initial: 5 * time.Second,
+		Max:     time.Minute,
+		Jitter:  0.1,
+	}
+
 	err = ctrl.NewControllerManagedBy(mgr).
 		For(&infraexpv1.DockerMachinePool{}).
 		WithOptions(options).
@@ -232,10 +245,12 @@ func (r *DockerMachinePoolReconciler) reconcileNormal(ctx context.Context, clust
 
 	dockerMachinePool.Status.Ready = len(dockerMachinePool.Spec.ProviderIDList) == int(*machinePool.Spec.Replicas)
 
-	// if some machine is still provisioning, force reconcile in few seconds to check again infrastructure.
+	// if some machine is still provisioning, force reconcile to check again infrastructure; the longer
+	// the machines take to provision, the less often the DockerMachinePool is requeued.
 	if !dockerMachinePool.Status.Ready && res.IsZero() {
-		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+		return r.requeueBackoff.Result(client.ObjectKeyFromObject(dockerMachinePool)), nil
 	}
+	r.requeueBackoff.Reset(client.ObjectKeyFromObject(dockerMachinePool))
 	return res, nil
 }
 
@@ -244,11 +259,20 @@ func getDockerMachinePoolProviderID(clusterName, dockerMachinePoolName string) s
 }
 
 func patchDockerMachinePool(ctx context.Context, patchHelper *patch.Helper, dockerMachinePool *infraexpv1.DockerMachinePool) error {
-	// TODO: add conditions
+	// Always update the readyCondition by summarizing the state of other conditions.
+	conditions.SetSummary(dockerMachinePool,
+		conditions.WithConditions(
+			infrav1.BootstrapExecSucceededCondition,
+		),
+	)
 
 	// Patch the object, ignoring conflicts on the conditions owned by this controller.
 	return patchHelper.Patch(
 		ctx,
 		dockerMachinePool,
+		patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
+			clusterv1.ReadyCondition,
+			infrav1.BootstrapExecSucceededCondition,
+		}},
 	)
 }
@@ -36,10 +36,13 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/test/infrastructure/container"
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1beta1"
 	infraexpv1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api/test/infrastructure/docker/internal/docker"
 	"sigs.k8s.io/cluster-api/test/infrastructure/kind"
 	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
 )
 
 const (
@@ -78,18 +81,35 @@ func NewNodePool(ctx context.Context, c client.Client, cluster *clusterv1.Cluste
 // eventually delete all the machine in excess, and update the status for all the machines.
 //
 // NOTE: The goal for the current implementation is to verify MachinePool construct; accordingly,
-// currently the nodepool supports only a recreate strategy for replacing old nodes with new ones
-// (all existing machines are killed before new ones are created).
-// TODO: consider if to support a Rollout strategy (a more progressive node replacement).
+// currently the nodepool supports only a simple staged replacement of old nodes with new ones: the image for the
+// new machines is pulled first, then machines matching the spec are created, and outdated machines are deleted
+// only once all the new machines are ready, so the machine pool is never left partially scaled while waiting
+// for the image to be pulled.
+// TODO: consider if to support a Rollout strategy (a more progressive node replacement, e.g. with maxSurge).
 func (np *NodePool) ReconcileMachines(ctx context.Context, remoteClient client.Client) (ctrl.Result, error) {
 	desiredReplicas := int(*np.machinePool.Spec.Replicas)
 
-	// Delete all the machines in excess (outdated machines or machines exceeding desired replica count).
+	matchingMachines := np.machinesMatchingInfrastructureSpec()
+	outdatedMachines := len(np.machines) - len(matchingMachines)
+
+	// Pull the image for the new machines before touching the existing ones.
+	if outdatedMachines > 0 || len(matchingMachines) < desiredReplicas {
+		if err := np.pullImage(ctx); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Delete all the machines in excess (outdated machines once all the new machines are ready, or machines
+	// exceeding desired replica count).
 	machineDeleted := false
+	matchingMachinesReady := len(matchingMachines) >= desiredReplicas && np.allMachinesReady(matchingMachines)
 	totalNumberOfMachines := 0
 	for _, machine := range np.machines {
-		totalNumberOfMachines++
-		if totalNumberOfMachines > desiredReplicas || !np.isMachineMatchingInfrastructureSpec(machine) {
+		isMatching := np.isMachineMatchingInfrastructureSpec(machine)
+		if isMatching {
+			totalNumberOfMachines++
+		}
+		if totalNumberOfMachines > desiredReplicas || (!isMatching && matchingMachinesReady) {
 			externalMachine, err := docker.NewMachine(ctx, np.cluster, machine.Name(), np.labelFilters)
 			if err != nil {
 				return ctrl.Result{}, errors.Wrapf(err, "failed to create helper for managing the externalMachine named %s", machine.Name())
@@ -98,7 +118,9 @@ func (np *NodePool) ReconcileMachines(ctx context.Context, remoteClient client.C
 				return ctrl.Result{}, errors.Wrapf(err, "failed to delete machine %s", machine.Name())
 			}
 			machineDeleted = true
-			totalNumberOfMachines-- // remove deleted machine from the count
+			if isMatching {
+				totalNumberOfMachines-- // remove deleted machine from the count
+			}
 		}
 	}
 	if machineDeleted {
@@ -149,6 +171,25 @@ func (np *NodePool) ReconcileMachines(ctx context.Context, remoteClient client.C
 			result = util.LowestNonZeroResult(result, res)
 		}
 	}
+
+	// Requeue until all the outdated machines are replaced.
+	if len(np.machines) > len(np.machinesMatchingInfrastructureSpec()) {
+		result = util.LowestNonZeroResult(result, ctrl.Result{RequeueAfter: 5 * time.Second})
+	}
+
+	// Surface the bootstrap progress of the instances; failures are reported while reconciling each machine.
+	bootstrapped := 0
+	for _, instance := range np.dockerMachinePool.Status.Instances {
+		if instance.Bootstrapped {
+			bootstrapped++
+		}
+	}
+	if bootstrapped == len(np.dockerMachinePool.Status.Instances) {
+		conditions.MarkTrue(np.dockerMachinePool, infrav1.BootstrapExecSucceededCondition)
+	} else {
+		conditions.MarkFalse(np.dockerMachinePool, infrav1.BootstrapExecSucceededCondition, infrav1.BootstrappingReason, clusterv1.ConditionSeverityInfo,
+			"%d of %d instances bootstrapped", bootstrapped, len(np.dockerMachinePool.Status.Instances))
+	}
 	return result, nil
 }
 
@@ -170,17 +211,51 @@ func (np *NodePool) Delete(ctx context.Context) error {
 
 func (np *NodePool) isMachineMatchingInfrastructureSpec(machine *docker.Machine) bool {
 	// NOTE: With the current implementation we are checking if the machine is using a kindest/node image for the expected version,
-	// but not checking if the machine has the expected extra.mounts or pre.loaded images.
+	// or the expected custom image, but not checking if the machine has the expected extra.mounts or pre.loaded images.
+	return machine.ContainerImage() == np.image()
+}
 
+// image returns the image of the machines matching the machine pool / docker machine pool spec.
+func (np *NodePool) image() string {
 	semVer, err := semver.Parse(strings.TrimPrefix(*np.machinePool.Spec.Template.Spec.Version, "v"))
 	if err != nil {
 		// TODO: consider if to return an error
 		panic(errors.Wrap(err, "failed to parse DockerMachine version").Error())
 	}
 
-	kindMapping := kind.GetMapping(semVer, np.dockerMachinePool.Spec.Template.CustomImage)
+	return kind.GetMapping(semVer, np.dockerMachinePool.Spec.Template.CustomImage).Image
+}
+
+// pullImage pulls the image of the machines matching the machine pool / docker machine pool spec, if it does not
+// exist locally yet.
+func (np *NodePool) pullImage(ctx context.Context) error {
+	containerRuntime, err := container.RuntimeFrom(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to container runtime")
+	}
 
-	return machine.ContainerImage() == kindMapping.Image
+	image := np.image()
+	if err := containerRuntime.PullContainerImageIfNotExists(ctx, image); err != nil {
+		return errors.Wrapf(err, "failed to pull image %q", image)
+	}
+	return nil
+}
+
+// allMachinesReady returns true if all the given machines are reported as ready in the docker machine pool status.
+func (np *NodePool) allMachinesReady(machines []*docker.Machine) bool {
+	for _, machine := range machines {
+		ready := false
+		for _, instance := range np.dockerMachinePool.Status.Instances {
+			if instance.InstanceName == machine.Name() {
+				ready = instance.Ready
+				break
+			}
+		}
+		if !ready {
+			return false
+		}
+	}
+	return true
 }
 
 // machinesMatchingInfrastructureSpec returns all of the docker.Machines which match the machine pool / docker machine pool spec.
@@ -219,12 +294,31 @@ func (np *NodePool) addMachine(ctx context.Context) error {
 		}
 	}
 
-	if err := externalMachine.Create(ctx, np.dockerMachinePool.Spec.Template.CustomImage, constants.WorkerNodeRoleValue, np.machinePool.Spec.Template.Spec.Version, labels, np.dockerMachinePool.Spec.Template.ExtraMounts); err != nil {
+	network, err := np.getNetwork(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := externalMachine.Create(ctx, np.dockerMachinePool.Spec.Template.CustomImage, constants.WorkerNodeRoleValue, np.machinePool.Spec.Template.Spec.Version, network, labels, np.dockerMachinePool.Spec.Template.ExtraMounts, nil, nil); err != nil {
 		return errors.Wrapf(err, "failed to create docker machine with instance name %s", instanceName)
 	}
 	return nil
 }
 
+// getNetwork returns the docker network the machines of the node pool are attached to, as defined in the DockerCluster.
+func (np *NodePool) getNetwork(ctx context.Context) (string, error) {
+	ref := np.cluster.Spec.InfrastructureRef
+	if ref == nil || ref.Kind != "DockerCluster" {
+		return docker.DefaultNetwork, nil
+	}
+
+	dockerCluster := &infrav1.DockerCluster{}
+	if err := np.client.Get(ctx, client.ObjectKey{Namespace: np.cluster.Namespace, Name: ref.Name}, dockerCluster); err != nil {
+		return "", errors.Wrapf(err, "failed to get DockerCluster %s", klog.KRef(np.cluster.Namespace, ref.Name))
+	}
+	return dockerCluster.Spec.Network.GetName(), nil
+}
+
 // refresh asks docker to list all the machines matching the node pool label and updates the cached list of node pool
 // machines.
 func (np *NodePool) refresh(ctx context.Context) error {
@@ -280,34 +374,30 @@ func (np *NodePool) reconcileMachine(ctx context.Context, machine *docker.Machin
 		return ctrl.Result{}, errors.Wrapf(err, "failed to create helper for managing the externalMachine named %s", machine.Name())
 	}
 
+	// Attach the instance to the additional networks; this is done for existing instances too, so networks
+	// added to the DockerMachinePool are attached without replacing the instances.
+	if err := externalMachine.ReconcileNetworks(ctx, np.dockerMachinePool.Spec.Template.Networks); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to attach DockerMachinePool instance named %s to additional networks", machine.Name())
+	}
+
 	// if the machine isn't bootstrapped, only then run bootstrap scripts
 	if !machineStatus.Bootstrapped {
 		timeoutCtx, cancel := context.WithTimeout(ctx, 3*time.Minute)
 		defer cancel()
 
-		// Check for bootstrap success
-		// We have to check here to make this reentrant for cases where the bootstrap works
-		// but bootstrapped is never set on the object. We only try to bootstrap if the machine
-		// is not already bootstrapped.
-		if err := externalMachine.CheckForBootstrapSuccess(timeoutCtx, false); err != nil {
-			log.Info("Bootstrapping instance", "instance", machine.Name())
-			if err := externalMachine.PreloadLoadImages(timeoutCtx, np.dockerMachinePool.Spec.Template.PreLoadImages); err != nil {
-				return ctrl.Result{}, errors.Wrapf(err, "failed to pre-load images into the docker machine with instance name %s", machine.Name())
-			}
-
-			bootstrapData, format, err := getBootstrapData(timeoutCtx, np.client, np.machinePool)
-			if err != nil {
-				return ctrl.Result{}, errors.Wrapf(err, "failed to get bootstrap data for instance named %s", machine.Name())
-			}
-
-			// Run the bootstrap script. Simulates cloud-init/Ignition.
-			if err := externalMachine.ExecBootstrap(timeoutCtx, bootstrapData, format, np.machinePool.Spec.Template.Spec.Version, np.dockerMachinePool.Spec.Template.CustomImage); err != nil {
-				return ctrl.Result{}, errors.Wrapf(err, "failed to exec DockerMachinePool instance bootstrap for instance named %s", machine.Name())
-			}
-			// Check for bootstrap success
-			if err := externalMachine.CheckForBootstrapSuccess(timeoutCtx, true); err != nil {
-				return ctrl.Result{}, errors.Wrap(err, "failed to check for existence of bootstrap success file at /run/cluster-api/bootstrap-success.complete")
-			}
+		// Run the bootstrap process; this is a no-op if the machine is already bootstrapped,
+		// which makes this reentrant for cases where the bootstrap works but bootstrapped is never set on the object.
+		if err := externalMachine.Bootstrap(timeoutCtx, docker.BootstrapInput{
+			GetBootstrapData: func(ctx context.Context) (string, bootstrapv1.Format, error) {
+				return getBootstrapData(ctx, np.client, np.machinePool)
+			},
+			Version:       np.machinePool.Spec.Template.Spec.Version,
+			Image:         np.dockerMachinePool.Spec.Template.CustomImage,
+			PreLoadImages: np.dockerMachinePool.Spec.Template.PreLoadImages,
+		}); err != nil {
+			conditions.MarkFalse(np.dockerMachinePool, infrav1.BootstrapExecSucceededCondition, infrav1.BootstrapFailedReason, clusterv1.ConditionSeverityWarning,
+				"Instance %s: %s", machine.Name(), docker.BootstrapFailureMessage(err))
+			return ctrl.Result{}, errors.Wrapf(err, "failed to bootstrap DockerMachinePool instance named %s", machine.Name())
 		}
 		machineStatus.Bootstrapped = true
 
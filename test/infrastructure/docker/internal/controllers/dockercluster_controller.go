@@ -36,6 +36,7 @@ import (
 	"sigs.k8s.io/cluster-api/test/infrastructure/docker/internal/docker"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/finalizers"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
 )
@@ -86,6 +87,17 @@ func (r *DockerClusterReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return ctrl.Result{}, errors.Wrapf(err, "failed to create helper for managing the externalLoadBalancer")
 	}
 
+	// Create a helper for managing the docker network the containers of the cluster are attached to.
+	network, err := docker.NewNetwork(cluster, dockerCluster)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to create helper for managing the network")
+	}
+
+	// Add finalizer first if not set to avoid the race condition between init and delete.
+	if finalizerAdded, err := finalizers.EnsureFinalizer(ctx, r.Client, dockerCluster, infrav1.ClusterFinalizer); err != nil || finalizerAdded {
+		return ctrl.Result{}, err
+	}
+
 	// Initialize the patch helper
 	patchHelper, err := patch.NewHelper(dockerCluster, r.Client)
 	if err != nil {
@@ -108,18 +120,11 @@ func (r *DockerClusterReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 
 	// Handle deleted clusters
 	if !dockerCluster.DeletionTimestamp.IsZero() {
-		return ctrl.Result{}, r.reconcileDelete(ctx, dockerCluster, externalLoadBalancer)
-	}
-
-	// Add finalizer first if not set to avoid the race condition between init and delete.
-	// Note: Finalizers in general can only be added when the deletionTimestamp is not set.
-	if !controllerutil.ContainsFinalizer(dockerCluster, infrav1.ClusterFinalizer) {
-		controllerutil.AddFinalizer(dockerCluster, infrav1.ClusterFinalizer)
-		return ctrl.Result{}, nil
+		return ctrl.Result{}, r.reconcileDelete(ctx, dockerCluster, network, externalLoadBalancer)
 	}
 
 	// Handle non-deleted clusters
-	return ctrl.Result{}, r.reconcileNormal(ctx, dockerCluster, externalLoadBalancer)
+	return ctrl.Result{}, r.reconcileNormal(ctx, cluster, dockerCluster, network, externalLoadBalancer)
 }
 
 func patchDockerCluster(ctx context.Context, patchHelper *patch.Helper, dockerCluster *infrav1.DockerCluster) error {
@@ -127,6 +132,7 @@ func patchDockerCluster(ctx context.Context, patchHelper *patch.Helper, dockerCl
 	// A step counter is added to represent progress during the provisioning process (instead we are hiding it during the deletion process).
 	conditions.SetSummary(dockerCluster,
 		conditions.WithConditions(
+			infrav1.NetworkAvailableCondition,
 			infrav1.LoadBalancerAvailableCondition,
 		),
 		conditions.WithStepCounterIf(dockerCluster.ObjectMeta.DeletionTimestamp.IsZero()),
@@ -138,12 +144,18 @@ func patchDockerCluster(ctx context.Context, patchHelper *patch.Helper, dockerCl
 		dockerCluster,
 		patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
 			clusterv1.ReadyCondition,
+			infrav1.NetworkAvailableCondition,
 			infrav1.LoadBalancerAvailableCondition,
 		}},
 	)
 }
 
-func (r *DockerClusterReconciler) reconcileNormal(ctx context.Context, dockerCluster *infrav1.DockerCluster, externalLoadBalancer *docker.LoadBalancer) error {
+func (r *DockerClusterReconciler) reconcileNormal(ctx context.Context, cluster *clusterv1.Cluster, dockerCluster *infrav1.DockerCluster, network *docker.Network, externalLoadBalancer *docker.LoadBalancer) error {
+	// Ensure the docker network the containers of the cluster are attached to exists.
+	if err := r.reconcileNetwork(ctx, dockerCluster, network); err != nil {
+		return err
+	}
+
 	// Create the docker container hosting the load balancer.
 	if err := externalLoadBalancer.Create(ctx); err != nil {
 		conditions.MarkFalse(dockerCluster, infrav1.LoadBalancerAvailableCondition, infrav1.LoadBalancerProvisioningFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
@@ -163,6 +175,21 @@ func (r *DockerClusterReconciler) reconcileNormal(ctx context.Context, dockerClu
 		dockerCluster.Spec.ControlPlaneEndpoint.Host = lbIP
 	}
 
+	// Once the control plane is initialized the load balancer configuration is managed by the DockerMachine controller;
+	// refresh it here too, so changes to the load balancer spec, e.g. ports, additional frontends or health checks,
+	// are applied without waiting for the next Machine to be created or deleted.
+	// NOTE: the configuration is reloaded only if it changed.
+	if conditions.IsTrue(cluster, clusterv1.ControlPlaneInitializedCondition) {
+		unsafeLoadBalancerConfigTemplate, err := getUnsafeLoadBalancerConfigTemplate(ctx, r.Client, dockerCluster)
+		if err != nil {
+			return errors.Wrap(err, "failed to retrieve DockerCluster.loadbalancer config template")
+		}
+		if err := externalLoadBalancer.UpdateConfiguration(ctx, unsafeLoadBalancerConfigTemplate); err != nil {
+			conditions.MarkFalse(dockerCluster, infrav1.LoadBalancerAvailableCondition, infrav1.LoadBalancerProvisioningFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+			return errors.Wrap(err, "failed to update DockerCluster.loadbalancer configuration")
+		}
+	}
+
 	// Mark the dockerCluster ready
 	dockerCluster.Status.Ready = true
 	conditions.MarkTrue(dockerCluster, infrav1.LoadBalancerAvailableCondition)
@@ -170,7 +197,52 @@ func (r *DockerClusterReconciler) reconcileNormal(ctx context.Context, dockerClu
 	return nil
 }
 
-func (r *DockerClusterReconciler) reconcileDelete(ctx context.Context, dockerCluster *infrav1.DockerCluster, externalLoadBalancer *docker.LoadBalancer) error {
+// reconcileNetwork ensures the docker network of the cluster exists and surfaces its details in the DockerCluster status.
+// Externally managed networks must be created by the user; other networks are created by CAPD if missing.
+func (r *DockerClusterReconciler) reconcileNetwork(ctx context.Context, dockerCluster *infrav1.DockerCluster, network *docker.Network) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	networkInfo, err := network.Get(ctx)
+	if err != nil {
+		conditions.MarkFalse(dockerCluster, infrav1.NetworkAvailableCondition, infrav1.NetworkProvisioningFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return errors.Wrapf(err, "failed to get network %q", network.Name())
+	}
+
+	if networkInfo == nil {
+		if network.IsExternallyManaged() {
+			conditions.MarkFalse(dockerCluster, infrav1.NetworkAvailableCondition, infrav1.NetworkNotFoundReason, clusterv1.ConditionSeverityWarning, "Network %q does not exist", network.Name())
+			return errors.Errorf("externally managed network %q does not exist", network.Name())
+		}
+
+		log.Info("Creating network", "Network", network.Name())
+		if err := network.Create(ctx); err != nil {
+			conditions.MarkFalse(dockerCluster, infrav1.NetworkAvailableCondition, infrav1.NetworkProvisioningFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+			return errors.Wrapf(err, "failed to create network %q", network.Name())
+		}
+
+		networkInfo, err = network.Get(ctx)
+		if err != nil {
+			conditions.MarkFalse(dockerCluster, infrav1.NetworkAvailableCondition, infrav1.NetworkProvisioningFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+			return errors.Wrapf(err, "failed to get network %q", network.Name())
+		}
+		if networkInfo == nil {
+			conditions.MarkFalse(dockerCluster, infrav1.NetworkAvailableCondition, infrav1.NetworkProvisioningFailedReason, clusterv1.ConditionSeverityWarning, "Network %q not found after creation", network.Name())
+			return errors.Errorf("network %q not found after creation", network.Name())
+		}
+	}
+
+	dockerCluster.Status.Network = &infrav1.DockerNetworkStatus{
+		Name:              networkInfo.Name,
+		Subnets:           networkInfo.Subnets,
+		MTU:               int32(networkInfo.MTU),
+		ExternallyManaged: network.IsExternallyManaged(),
+	}
+	conditions.MarkTrue(dockerCluster, infrav1.NetworkAvailableCondition)
+
+	return nil
+}
+
+func (r *DockerClusterReconciler) reconcileDelete(ctx context.Context, dockerCluster *infrav1.DockerCluster, network *docker.Network, externalLoadBalancer *docker.LoadBalancer) error {
 	// Set the LoadBalancerAvailableCondition reporting delete is started, and issue a patch in order to make
 	// this visible to the users.
 	// NB. The operation in docker is fast, so there is the chance the user will not notice the status change;
@@ -189,6 +261,13 @@ func (r *DockerClusterReconciler) reconcileDelete(ctx context.Context, dockerClu
 		return errors.Wrap(err, "failed to delete load balancer")
 	}
 
+	// Delete the docker network, if it is managed by CAPD.
+	// NOTE: the network is deleted only once all the containers of the cluster are gone, which happens before
+	// the DockerCluster is deleted given that Machines are deleted before the infrastructure cluster.
+	if err := network.Delete(ctx); err != nil {
+		return errors.Wrapf(err, "failed to delete network %q", network.Name())
+	}
+
 	// Cluster is deleted so remove the finalizer.
 	controllerutil.RemoveFinalizer(dockerCluster, infrav1.ClusterFinalizer)
 
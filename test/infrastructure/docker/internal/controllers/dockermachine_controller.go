@@ -25,6 +25,7 @@ import (
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
@@ -37,12 +38,16 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controllers/remote"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/feature"
 	"sigs.k8s.io/cluster-api/test/infrastructure/container"
 	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1beta1"
 	"sigs.k8s.io/cluster-api/test/infrastructure/docker/internal/docker"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/events"
+	"sigs.k8s.io/cluster-api/util/finalizers"
 	clog "sigs.k8s.io/cluster-api/util/log"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
@@ -56,12 +61,15 @@ type DockerMachineReconciler struct {
 
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
+
+	recorder record.EventRecorder
 }
 
+// +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;patch
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=dockermachines,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=dockermachines/status;dockermachines/finalizers,verbs=get;update;patch
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;machinesets;machines,verbs=get;list;watch
-// +kubebuilder:rbac:groups="",resources=secrets;,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets;configmaps,verbs=get;list;watch
 
 // Reconcile handles DockerMachine events.
 func (r *DockerMachineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, rerr error) {
@@ -90,6 +98,12 @@ func (r *DockerMachineReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return ctrl.Result{}, err
 	}
 	if machine == nil {
+		// DockerMachines in a MachineWarmPool are pre-provisioned before being claimed by a MachineSet; claimed
+		// DockerMachines which are deleted before getting an owner Machine are handled here too.
+		if feature.Gates.Enabled(feature.MachineWarmPool) &&
+			(dockerMachine.Labels[expv1.MachineWarmPoolNameLabel] != "" || !dockerMachine.DeletionTimestamp.IsZero()) {
+			return r.reconcileWarmPoolMachine(ctx, dockerMachine)
+		}
 		log.Info("Waiting for Machine Controller to set OwnerRef on DockerMachine")
 		return ctrl.Result{}, nil
 	}
@@ -128,6 +142,11 @@ func (r *DockerMachineReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return ctrl.Result{}, nil
 	}
 
+	// Add finalizer first if not set to avoid the race condition between init and delete.
+	if finalizerAdded, err := finalizers.EnsureFinalizer(ctx, r.Client, dockerMachine, infrav1.MachineFinalizer); err != nil || finalizerAdded {
+		return ctrl.Result{}, err
+	}
+
 	// Initialize the patch helper
 	patchHelper, err := patch.NewHelper(dockerMachine, r)
 	if err != nil {
@@ -143,13 +162,6 @@ func (r *DockerMachineReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		}
 	}()
 
-	// Add finalizer first if not set to avoid the race condition between init and delete.
-	// Note: Finalizers in general can only be added when the deletionTimestamp is not set.
-	if dockerMachine.ObjectMeta.DeletionTimestamp.IsZero() && !controllerutil.ContainsFinalizer(dockerMachine, infrav1.MachineFinalizer) {
-		controllerutil.AddFinalizer(dockerMachine, infrav1.MachineFinalizer)
-		return ctrl.Result{}, nil
-	}
-
 	// Create a helper for managing the docker container hosting the machine.
 	externalMachine, err := docker.NewMachine(ctx, cluster, machine.Name, nil)
 	if err != nil {
@@ -167,11 +179,11 @@ func (r *DockerMachineReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 
 	// Handle deleted machines
 	if !dockerMachine.ObjectMeta.DeletionTimestamp.IsZero() {
-		return ctrl.Result{}, r.reconcileDelete(ctx, machine, dockerMachine, externalMachine, externalLoadBalancer)
+		return ctrl.Result{}, r.reconcileDelete(ctx, dockerCluster, machine, dockerMachine, externalMachine, externalLoadBalancer)
 	}
 
 	// Handle non-deleted machines
-	res, err := r.reconcileNormal(ctx, cluster, machine, dockerMachine, externalMachine, externalLoadBalancer)
+	res, err := r.reconcileNormal(ctx, cluster, dockerCluster, machine, dockerMachine, externalMachine, externalLoadBalancer)
 	// Requeue if the reconcile failed because the ClusterCacheTracker was locked for
 	// the current cluster because of concurrent access.
 	if errors.Is(err, remote.ErrClusterLocked) {
@@ -200,11 +212,12 @@ func patchDockerMachine(ctx context.Context, patchHelper *patch.Helper, dockerMa
 			clusterv1.ReadyCondition,
 			infrav1.ContainerProvisionedCondition,
 			infrav1.BootstrapExecSucceededCondition,
+			infrav1.PreProvisionedCondition,
 		}},
 	)
 }
 
-func (r *DockerMachineReconciler) reconcileNormal(ctx context.Context, cluster *clusterv1.Cluster, machine *clusterv1.Machine, dockerMachine *infrav1.DockerMachine, externalMachine *docker.Machine, externalLoadBalancer *docker.LoadBalancer) (res ctrl.Result, retErr error) {
+func (r *DockerMachineReconciler) reconcileNormal(ctx context.Context, cluster *clusterv1.Cluster, dockerCluster *infrav1.DockerCluster, machine *clusterv1.Machine, dockerMachine *infrav1.DockerMachine, externalMachine *docker.Machine, externalLoadBalancer *docker.LoadBalancer) (res ctrl.Result, retErr error) {
 	log := ctrl.LoggerFrom(ctx)
 
 	// Check if the infrastructure is ready, otherwise return and wait for the cluster object to be updated
@@ -226,6 +239,9 @@ func (r *DockerMachineReconciler) reconcileNormal(ctx context.Context, cluster *
 			if err := setMachineAddress(ctx, dockerMachine, externalMachine); err != nil {
 				return ctrl.Result{}, errors.Wrap(err, "failed to set the machine address")
 			}
+			if err := reconcileSnapshot(ctx, dockerMachine, externalMachine); err != nil {
+				return ctrl.Result{}, err
+			}
 		} else {
 			conditions.MarkFalse(dockerMachine, infrav1.ContainerProvisionedCondition, infrav1.ContainerDeletedReason, clusterv1.ConditionSeverityError, fmt.Sprintf("Container %s does not exists anymore", externalMachine.Name()))
 		}
@@ -255,11 +271,25 @@ func (r *DockerMachineReconciler) reconcileNormal(ctx context.Context, cluster *
 	if !externalMachine.Exists() {
 		// NOTE: FailureDomains don't mean much in CAPD since it's all local, but we are setting a label on
 		// each container, so we can check placement.
-		if err := externalMachine.Create(ctx, dockerMachine.Spec.CustomImage, role, machine.Spec.Version, docker.FailureDomainLabel(machine.Spec.FailureDomain), dockerMachine.Spec.ExtraMounts); err != nil {
+		// If the machine should be restored from a snapshot, create the container from the snapshot image.
+		image := dockerMachine.Spec.CustomImage
+		if snapshotImage, ok := dockerMachine.Annotations[infrav1.RestoreFromSnapshotAnnotation]; ok && snapshotImage != "" {
+			log.Info("Restoring machine from snapshot", "image", snapshotImage)
+			image = snapshotImage
+		}
+		events.Normalf(r.recorder, dockerMachine, events.ProvisioningStartedReason, "Creating container %q", externalMachine.ContainerName())
+		if err := externalMachine.Create(ctx, image, role, machine.Spec.Version, dockerCluster.Spec.Network.GetName(), docker.FailureDomainLabel(machine.Spec.FailureDomain), dockerMachine.Spec.ExtraMounts, dockerMachine.Spec.Resources, dockerMachine.Spec.Sysctls); err != nil {
+			events.Warningf(r.recorder, dockerMachine, events.ProvisioningFailedReason, "Failed to create container %q: %v", externalMachine.ContainerName(), err)
 			return ctrl.Result{}, errors.Wrap(err, "failed to create worker DockerMachine")
 		}
 	}
 
+	// Attach the container to the additional networks; this is done also for existing containers, so networks
+	// added to the DockerMachine after the container has been created are attached too.
+	if err := externalMachine.ReconcileNetworks(ctx, dockerMachine.Spec.Networks); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to attach the DockerMachine to additional networks")
+	}
+
 	// Preload images into the container
 	if len(dockerMachine.Spec.PreLoadImages) > 0 {
 		if err := externalMachine.PreloadLoadImages(ctx, dockerMachine.Spec.PreLoadImages); err != nil {
@@ -267,11 +297,16 @@ func (r *DockerMachineReconciler) reconcileNormal(ctx context.Context, cluster *
 		}
 	}
 
-	// if the machine is a control plane update the load balancer configuration
+	// if the machine is a control plane, or if the load balancer has additional frontends forwarding
+	// to all the machines, update the load balancer configuration
 	// we should only do this once, as reconfiguration more or less ensures
 	// node ref setting fails
-	if util.IsControlPlaneMachine(machine) && !dockerMachine.Status.LoadBalancerConfigured {
-		if err := externalLoadBalancer.UpdateConfiguration(ctx); err != nil {
+	if isLoadBalancerBackend(dockerCluster, machine) && !dockerMachine.Status.LoadBalancerConfigured {
+		unsafeLoadBalancerConfigTemplate, err := getUnsafeLoadBalancerConfigTemplate(ctx, r.Client, dockerCluster)
+		if err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "failed to retrieve DockerCluster.loadbalancer config template")
+		}
+		if err := externalLoadBalancer.UpdateConfiguration(ctx, unsafeLoadBalancerConfigTemplate); err != nil {
 			return ctrl.Result{}, errors.Wrap(err, "failed to update DockerCluster.loadbalancer configuration")
 		}
 		dockerMachine.Status.LoadBalancerConfigured = true
@@ -297,53 +332,49 @@ func (r *DockerMachineReconciler) reconcileNormal(ctx context.Context, cluster *
 		}
 	}
 
+	// Machines restored from a snapshot are already bootstrapped.
+	if dockerMachine.Annotations[infrav1.RestoreFromSnapshotAnnotation] != "" {
+		dockerMachine.Spec.Bootstrapped = true
+	}
+
 	// if the machine isn't bootstrapped, only then run bootstrap scripts
 	if !dockerMachine.Spec.Bootstrapped {
 		timeoutCtx, cancel := context.WithTimeout(ctx, 3*time.Minute)
 		defer cancel()
 
-		// Check for bootstrap success
-		// We have to check here to make this reentrant for cases where the bootstrap works
-		// but bootstrapped is never set on the object. We only try to bootstrap if the machine
-		// is not already bootstrapped.
-		if err := externalMachine.CheckForBootstrapSuccess(timeoutCtx, false); err != nil {
-			bootstrapData, format, err := r.getBootstrapData(timeoutCtx, machine)
-			if err != nil {
-				return ctrl.Result{}, err
-			}
-
-			// Setup a go routing to check for the machine being deleted while running bootstrap as a
-			// synchronous process, e.g. due to remediation. The routine stops when timeoutCtx is Done
-			// (either because canceled intentionally due to machine deletion or canceled by the defer cancel()
-			// call when exiting from this func).
-			go func() {
-				for {
-					select {
-					case <-timeoutCtx.Done():
+		// Setup a go routing to check for the machine being deleted while running bootstrap as a
+		// synchronous process, e.g. due to remediation. The routine stops when timeoutCtx is Done
+		// (either because canceled intentionally due to machine deletion or canceled by the defer cancel()
+		// call when exiting from this func).
+		go func() {
+			for {
+				select {
+				case <-timeoutCtx.Done():
+					return
+				default:
+					updatedDockerMachine := &infrav1.DockerMachine{}
+					if err := r.Client.Get(ctx, client.ObjectKeyFromObject(dockerMachine), updatedDockerMachine); err == nil &&
+						!updatedDockerMachine.DeletionTimestamp.IsZero() {
+						log.Info("Cancelling Bootstrap because the underlying machine has been deleted")
+						cancel()
 						return
-					default:
-						updatedDockerMachine := &infrav1.DockerMachine{}
-						if err := r.Client.Get(ctx, client.ObjectKeyFromObject(dockerMachine), updatedDockerMachine); err == nil &&
-							!updatedDockerMachine.DeletionTimestamp.IsZero() {
-							log.Info("Cancelling Bootstrap because the underlying machine has been deleted")
-							cancel()
-							return
-						}
-						time.Sleep(5 * time.Second)
 					}
+					time.Sleep(5 * time.Second)
 				}
-			}()
-
-			// Run the bootstrap script. Simulates cloud-init/Ignition.
-			if err := externalMachine.ExecBootstrap(timeoutCtx, bootstrapData, format, machine.Spec.Version, dockerMachine.Spec.CustomImage); err != nil {
-				conditions.MarkFalse(dockerMachine, infrav1.BootstrapExecSucceededCondition, infrav1.BootstrapFailedReason, clusterv1.ConditionSeverityWarning, "Repeating bootstrap")
-				return ctrl.Result{}, errors.Wrap(err, "failed to exec DockerMachine bootstrap")
-			}
-			// Check for bootstrap success
-			if err := externalMachine.CheckForBootstrapSuccess(timeoutCtx, true); err != nil {
-				conditions.MarkFalse(dockerMachine, infrav1.BootstrapExecSucceededCondition, infrav1.BootstrapFailedReason, clusterv1.ConditionSeverityWarning, "Repeating bootstrap")
-				return ctrl.Result{}, errors.Wrap(err, "failed to check for existence of bootstrap success file at /run/cluster-api/bootstrap-success.complete")
 			}
+		}()
+
+		// Run the bootstrap process; this is a no-op if the machine is already bootstrapped,
+		// which makes this reentrant for cases where the bootstrap works but bootstrapped is never set on the object.
+		if err := externalMachine.Bootstrap(timeoutCtx, docker.BootstrapInput{
+			GetBootstrapData: func(ctx context.Context) (string, bootstrapv1.Format, error) {
+				return r.getBootstrapData(ctx, machine)
+			},
+			Version: machine.Spec.Version,
+			Image:   dockerMachine.Spec.CustomImage,
+		}); err != nil {
+			conditions.MarkFalse(dockerMachine, infrav1.BootstrapExecSucceededCondition, infrav1.BootstrapFailedReason, clusterv1.ConditionSeverityWarning, docker.BootstrapFailureMessage(err))
+			return ctrl.Result{}, errors.Wrap(err, "failed to bootstrap DockerMachine")
 		}
 		dockerMachine.Spec.Bootstrapped = true
 	}
@@ -390,7 +421,25 @@ func (r *DockerMachineReconciler) reconcileNormal(ctx context.Context, cluster *
 	return ctrl.Result{}, nil
 }
 
-func (r *DockerMachineReconciler) reconcileDelete(ctx context.Context, machine *clusterv1.Machine, dockerMachine *infrav1.DockerMachine, externalMachine *docker.Machine, externalLoadBalancer *docker.LoadBalancer) error {
+// reconcileSnapshot takes a snapshot of a provisioned machine if requested using the SnapshotAnnotation.
+func reconcileSnapshot(ctx context.Context, dockerMachine *infrav1.DockerMachine, externalMachine *docker.Machine) error {
+	image, ok := dockerMachine.Annotations[infrav1.SnapshotAnnotation]
+	if !ok {
+		return nil
+	}
+	if image == "" {
+		return errors.Errorf("failed to snapshot DockerMachine: the %s annotation must be set to the image to create", infrav1.SnapshotAnnotation)
+	}
+
+	if err := externalMachine.Snapshot(ctx, image); err != nil {
+		return errors.Wrap(err, "failed to snapshot DockerMachine")
+	}
+	delete(dockerMachine.Annotations, infrav1.SnapshotAnnotation)
+	dockerMachine.Annotations[infrav1.SnapshotImageAnnotation] = image
+	return nil
+}
+
+func (r *DockerMachineReconciler) reconcileDelete(ctx context.Context, dockerCluster *infrav1.DockerCluster, machine *clusterv1.Machine, dockerMachine *infrav1.DockerMachine, externalMachine *docker.Machine, externalLoadBalancer *docker.LoadBalancer) error {
 	// Set the ContainerProvisionedCondition reporting delete is started, and issue a patch in order to make
 	// this visible to the users.
 	// NB. The operation in docker is fast, so there is the chance the user will not notice the status change;
@@ -409,9 +458,13 @@ func (r *DockerMachineReconciler) reconcileDelete(ctx context.Context, machine *
 		return errors.Wrap(err, "failed to delete DockerMachine")
 	}
 
-	// if the deleted machine is a control-plane node, remove it from the load balancer configuration;
-	if util.IsControlPlaneMachine(machine) {
-		if err := externalLoadBalancer.UpdateConfiguration(ctx); err != nil {
+	// if the deleted machine is a load balancer backend, remove it from the load balancer configuration;
+	if isLoadBalancerBackend(dockerCluster, machine) {
+		unsafeLoadBalancerConfigTemplate, err := getUnsafeLoadBalancerConfigTemplate(ctx, r.Client, dockerCluster)
+		if err != nil {
+			return errors.Wrap(err, "failed to retrieve DockerCluster.loadbalancer config template")
+		}
+		if err := externalLoadBalancer.UpdateConfiguration(ctx, unsafeLoadBalancerConfigTemplate); err != nil {
 			return errors.Wrap(err, "failed to update DockerCluster.loadbalancer configuration")
 		}
 	}
@@ -428,6 +481,8 @@ func (r *DockerMachineReconciler) SetupWithManager(ctx context.Context, mgr ctrl
 		return err
 	}
 
+	r.recorder = mgr.GetEventRecorderFor("dockermachine-controller")
+
 	err = ctrl.NewControllerManagedBy(mgr).
 		For(&infrav1.DockerMachine{}).
 		WithOptions(options).
@@ -535,3 +590,31 @@ func setMachineAddress(ctx context.Context, dockerMachine *infrav1.DockerMachine
 
 	return nil
 }
+
+// isLoadBalancerBackend returns true if the load balancer forwards traffic to the Machine, i.e. if the Machine
+// is a control plane Machine or if the load balancer has additional frontends.
+func isLoadBalancerBackend(dockerCluster *infrav1.DockerCluster, machine *clusterv1.Machine) bool {
+	return util.IsControlPlaneMachine(machine) || len(dockerCluster.Spec.LoadBalancer.AdditionalFrontends) > 0
+}
+
+// getUnsafeLoadBalancerConfigTemplate returns the custom load balancer config template referenced by the DockerCluster,
+// if any. The template is not validated in any way, thus it is unsafe.
+func getUnsafeLoadBalancerConfigTemplate(ctx context.Context, c client.Client, dockerCluster *infrav1.DockerCluster) (string, error) {
+	if dockerCluster.Spec.LoadBalancer.CustomConfigTemplateRef == nil {
+		return "", nil
+	}
+
+	configMap := &corev1.ConfigMap{}
+	key := client.ObjectKey{
+		Namespace: dockerCluster.Namespace,
+		Name:      dockerCluster.Spec.LoadBalancer.CustomConfigTemplateRef.Name,
+	}
+	if err := c.Get(ctx, key, configMap); err != nil {
+		return "", errors.Wrapf(err, "failed to retrieve custom load balancer config template ConfigMap %s", klog.KRef(key.Namespace, key.Name))
+	}
+	template, ok := configMap.Data["value"]
+	if !ok {
+		return "", errors.Errorf("expected key \"value\" to exist in ConfigMap %s", klog.KRef(key.Namespace, key.Name))
+	}
+	return template, nil
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/kind/pkg/cluster/constants"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1beta1"
+	"sigs.k8s.io/cluster-api/test/infrastructure/docker/internal/docker"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/events"
+	"sigs.k8s.io/cluster-api/util/finalizers"
+	"sigs.k8s.io/cluster-api/util/patch"
+)
+
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinewarmpools,verbs=get;list;watch
+
+// reconcileWarmPoolMachine reconciles a DockerMachine in a MachineWarmPool, i.e. a DockerMachine not yet owned by a Machine.
+// The container hosting the machine is created, but the machine is not bootstrapped; this happens after the DockerMachine
+// is claimed by a MachineSet and the corresponding Machine has bootstrap data.
+// NOTE: the container gets the name of the DockerMachine; this works because MachineSets create the Machine with the same
+// name of the claimed DockerMachine.
+func (r *DockerMachineReconciler) reconcileWarmPoolMachine(ctx context.Context, dockerMachine *infrav1.DockerMachine) (_ ctrl.Result, rerr error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	// Fetch the Cluster.
+	// NOTE: DockerMachines in a MachineWarmPool are deleted by the garbage collector, possibly after the Cluster is gone.
+	clusterName := dockerMachine.Labels[clusterv1.ClusterNameLabel]
+	if clusterName == "" {
+		log.Info("Waiting for Machine Controller to set OwnerRef on DockerMachine")
+		return ctrl.Result{}, nil
+	}
+	cluster := &clusterv1.Cluster{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: dockerMachine.Namespace, Name: clusterName}, cluster); err != nil {
+		if !apierrors.IsNotFound(err) || dockerMachine.DeletionTimestamp.IsZero() {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to get Cluster %s", klog.KRef(dockerMachine.Namespace, clusterName))
+		}
+		cluster = &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: dockerMachine.Namespace, Name: clusterName}}
+	}
+
+	log = log.WithValues("Cluster", klog.KObj(cluster), "MachineWarmPool", klog.KRef(dockerMachine.Namespace, dockerMachine.Labels[expv1.MachineWarmPoolNameLabel]))
+	ctx = ctrl.LoggerInto(ctx, log)
+
+	// Return early if the object or Cluster is paused.
+	if annotations.IsPaused(cluster, dockerMachine) {
+		log.Info("Reconciliation is paused for this object")
+		return ctrl.Result{}, nil
+	}
+
+	// Create a helper for managing the docker container hosting the machine.
+	externalMachine, err := docker.NewMachine(ctx, cluster, dockerMachine.Name, nil)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to create helper for managing the externalMachine")
+	}
+
+	// Handle deleted machines.
+	if !dockerMachine.DeletionTimestamp.IsZero() {
+		if err := externalMachine.Delete(ctx); err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "failed to delete DockerMachine")
+		}
+		patchHelper, err := patch.NewHelper(dockerMachine, r.Client)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		controllerutil.RemoveFinalizer(dockerMachine, infrav1.MachineFinalizer)
+		return ctrl.Result{}, patchHelper.Patch(ctx, dockerMachine)
+	}
+
+	// Fetch the MachineWarmPool; if it is gone, the DockerMachine is going to be garbage collected.
+	warmPool := &expv1.MachineWarmPool{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: dockerMachine.Namespace, Name: dockerMachine.Labels[expv1.MachineWarmPoolNameLabel]}, warmPool); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Fetch the Docker Cluster.
+	dockerCluster := &infrav1.DockerCluster{}
+	if cluster.Spec.InfrastructureRef == nil {
+		log.Info("DockerCluster is not available yet")
+		return ctrl.Result{}, nil
+	}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: dockerMachine.Namespace, Name: cluster.Spec.InfrastructureRef.Name}, dockerCluster); err != nil {
+		log.Info("DockerCluster is not available yet")
+		return ctrl.Result{}, nil
+	}
+
+	// Add finalizer first if not set to avoid the race condition between init and delete.
+	if finalizerAdded, err := finalizers.EnsureFinalizer(ctx, r.Client, dockerMachine, infrav1.MachineFinalizer); err != nil || finalizerAdded {
+		return ctrl.Result{}, err
+	}
+
+	// Initialize the patch helper.
+	patchHelper, err := patch.NewHelper(dockerMachine, r)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	// Always attempt to Patch the DockerMachine object and status after each reconciliation.
+	defer func() {
+		if err := patchDockerMachine(ctx, patchHelper, dockerMachine); err != nil {
+			log.Error(err, "failed to patch DockerMachine")
+			if rerr == nil {
+				rerr = err
+			}
+		}
+	}()
+
+	// Check if the infrastructure is ready, otherwise return and wait for the cluster object to be updated.
+	if !cluster.Status.InfrastructureReady {
+		log.Info("Waiting for DockerCluster Controller to create cluster infrastructure")
+		conditions.MarkFalse(dockerMachine, infrav1.ContainerProvisionedCondition, infrav1.WaitingForClusterInfrastructureReason, clusterv1.ConditionSeverityInfo, "")
+		conditions.MarkFalse(dockerMachine, infrav1.PreProvisionedCondition, infrav1.WaitingForClusterInfrastructureReason, clusterv1.ConditionSeverityInfo, "")
+		return ctrl.Result{}, nil
+	}
+
+	// Create the container hosting the machine if not existing yet; machines in a MachineWarmPool are always workers.
+	if !externalMachine.Exists() {
+		events.Normalf(r.recorder, dockerMachine, events.ProvisioningStartedReason, "Creating container %q", externalMachine.ContainerName())
+		if err := externalMachine.Create(ctx, dockerMachine.Spec.CustomImage, constants.WorkerNodeRoleValue, warmPool.Spec.Template.Version, dockerCluster.Spec.Network.GetName(), nil, dockerMachine.Spec.ExtraMounts, dockerMachine.Spec.Resources, dockerMachine.Spec.Sysctls); err != nil {
+			events.Warningf(r.recorder, dockerMachine, events.ProvisioningFailedReason, "Failed to create container %q: %v", externalMachine.ContainerName(), err)
+			conditions.MarkFalse(dockerMachine, infrav1.PreProvisionedCondition, infrav1.ContainerProvisioningFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+			return ctrl.Result{}, errors.Wrap(err, "failed to create worker DockerMachine")
+		}
+	}
+
+	// Preload images into the container.
+	if len(dockerMachine.Spec.PreLoadImages) > 0 {
+		if err := externalMachine.PreloadLoadImages(ctx, dockerMachine.Spec.PreLoadImages); err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "failed to pre-load images into the DockerMachine")
+		}
+	}
+
+	conditions.MarkTrue(dockerMachine, infrav1.ContainerProvisionedCondition)
+	conditions.MarkTrue(dockerMachine, infrav1.PreProvisionedCondition)
+	return ctrl.Result{}, nil
+}
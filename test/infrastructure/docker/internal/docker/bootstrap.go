@@ -0,0 +1,244 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/test/infrastructure/docker/internal/provisioning"
+	"sigs.k8s.io/cluster-api/test/infrastructure/docker/internal/provisioning/cloudinit"
+	"sigs.k8s.io/cluster-api/test/infrastructure/docker/internal/provisioning/ignition"
+	"sigs.k8s.io/cluster-api/test/infrastructure/kind"
+)
+
+const (
+	bootstrapSuccessFile = "/run/cluster-api/bootstrap-success.complete"
+	// bootstrapFailureReasonFile is written by the readiness checks of CABPK with the reason of the failure.
+	bootstrapFailureReasonFile = "/run/cluster-api/readiness-checks.failed"
+)
+
+// BootstrapInput defines the input for Machine.Bootstrap.
+type BootstrapInput struct {
+	// GetBootstrapData returns the bootstrap data and its format.
+	// It is called only if the machine is not bootstrapped yet.
+	GetBootstrapData func(ctx context.Context) (string, bootstrapv1.Format, error)
+
+	// Version is the Kubernetes version of the machine.
+	Version *string
+
+	// Image is the custom image of the machine, if any.
+	Image string
+
+	// PreLoadImages are the images to load into the machine before running bootstrap.
+	PreLoadImages []string
+}
+
+// BootstrapPhaseError is returned by Machine.Bootstrap when one of the phases of the bootstrap process fails.
+type BootstrapPhaseError struct {
+	// Phase describes the phase which failed, e.g. "writing file /etc/kubernetes/kubeadm.yaml (command 2 of 10)".
+	Phase string
+
+	// Stdout and Stderr are the output of the failed command, if any.
+	Stdout string
+	Stderr string
+
+	// Reason is the reason of the failure reported by the node, if any, e.g. a readiness check not succeeding.
+	Reason string
+
+	Err error
+}
+
+func (e *BootstrapPhaseError) Error() string {
+	return fmt.Sprintf("failed %s: %v: stdout: %s stderr: %s", e.Phase, e.Err, e.Stdout, e.Stderr)
+}
+
+func (e *BootstrapPhaseError) Unwrap() error {
+	return e.Err
+}
+
+// BootstrapFailureMessage returns a message describing a bootstrap failure, to be used in conditions.
+func BootstrapFailureMessage(err error) string {
+	var phaseErr *BootstrapPhaseError
+	if errors.As(err, &phaseErr) {
+		if phaseErr.Reason != "" {
+			return fmt.Sprintf("Bootstrap failed %s: %s, repeating bootstrap", phaseErr.Phase, phaseErr.Reason)
+		}
+		return fmt.Sprintf("Bootstrap failed %s, repeating bootstrap", phaseErr.Phase)
+	}
+	return "Repeating bootstrap"
+}
+
+// Bootstrap runs the bootstrap process on a machine, simulating cloud-init/Ignition.
+// The process is reentrant: if the machine is already bootstrapped, e.g. because bootstrap completed
+// but the owning object was not updated, nothing is done.
+func (m *Machine) Bootstrap(ctx context.Context, input BootstrapInput) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	if err := m.checkForBootstrapSuccess(ctx, false); err == nil {
+		return nil
+	}
+
+	log.Info("Bootstrapping machine", "instance", m.Name())
+	if len(input.PreLoadImages) > 0 {
+		if err := m.PreloadLoadImages(ctx, input.PreLoadImages); err != nil {
+			return &BootstrapPhaseError{Phase: "pre-loading images", Err: err}
+		}
+	}
+
+	data, format, err := input.GetBootstrapData(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get bootstrap data")
+	}
+
+	if err := m.execBootstrap(ctx, data, format, input.Version, input.Image); err != nil {
+		var phaseErr *BootstrapPhaseError
+		if errors.As(err, &phaseErr) {
+			phaseErr.Reason = m.getBootstrapFailureReason(ctx)
+		}
+		return err
+	}
+
+	if err := m.checkForBootstrapSuccess(ctx, true); err != nil {
+		return &BootstrapPhaseError{Phase: fmt.Sprintf("checking for existence of bootstrap success file at %s", bootstrapSuccessFile), Err: err}
+	}
+	return nil
+}
+
+// execBootstrap runs bootstrap on a node, this is generally `kubeadm <init|join>`.
+func (m *Machine) execBootstrap(ctx context.Context, data string, format bootstrapv1.Format, version *string, image string) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	if m.container == nil {
+		return errors.New("unable to set ExecBootstrap. the container hosting this machine does not exists")
+	}
+
+	// Get the kindMapping for the target K8s version.
+	// NOTE: The kindMapping allows to select the most recent kindest/node image available, if any, as well as
+	// provide info about the mode to be used when starting the kindest/node image itself.
+	if version == nil {
+		return errors.New("cannot create a DockerMachine for a nil version")
+	}
+
+	semVer, err := semver.Parse(strings.TrimPrefix(*version, "v"))
+	if err != nil {
+		return errors.Wrap(err, "failed to parse DockerMachine version")
+	}
+
+	kindMapping := kind.GetMapping(semVer, image)
+
+	// Decode the cloud config
+	cloudConfig, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return &BootstrapPhaseError{Phase: "decoding bootstrap data", Err: err}
+	}
+
+	var commands []provisioning.Cmd
+
+	switch format {
+	case bootstrapv1.CloudConfig:
+		commands, err = cloudinit.RawCloudInitToProvisioningCommands(cloudConfig, kindMapping)
+	case bootstrapv1.Ignition:
+		commands, err = ignition.RawIgnitionToProvisioningCommands(cloudConfig)
+	default:
+		return fmt.Errorf("unknown provisioning format %q", format)
+	}
+
+	if err != nil {
+		log.Info("provisioning code failed to parse", "bootstrap data", data)
+		return &BootstrapPhaseError{Phase: fmt.Sprintf("parsing %s bootstrap data", format), Err: err}
+	}
+
+	for i, command := range commands {
+		var outErr bytes.Buffer
+		var outStd bytes.Buffer
+		cmd := m.container.Commander.Command(command.Cmd, command.Args...)
+		cmd.SetStderr(&outErr)
+		cmd.SetStdout(&outStd)
+		if command.Stdin != "" {
+			cmd.SetStdin(strings.NewReader(command.Stdin))
+		}
+		if err := cmd.Run(ctx); err != nil {
+			log.Info("Failed running command", "instance", m.Name(), "command", command, "stdout", outStd.String(), "stderr", outErr.String(), "bootstrap data", data)
+			logContainerDebugInfo(ctx, log, m.ContainerName())
+			return &BootstrapPhaseError{
+				Phase:  commandPhase(command, i, len(commands)),
+				Stdout: outStd.String(),
+				Stderr: outErr.String(),
+				Err:    err,
+			}
+		}
+	}
+
+	return nil
+}
+
+// commandPhase returns a description of a bootstrap command, e.g. "writing file /etc/kubernetes/kubeadm.yaml (command 2 of 10)".
+func commandPhase(command provisioning.Cmd, i, total int) string {
+	// File writes are generated by the provisioning adapters as `/bin/sh -c "cat > path /dev/stdin"`.
+	if command.Cmd == "/bin/sh" && len(command.Args) == 2 && command.Stdin != "" {
+		if fields := strings.Fields(command.Args[1]); len(fields) == 4 && fields[0] == "cat" && fields[3] == "/dev/stdin" {
+			return fmt.Sprintf("writing file %s (command %d of %d)", fields[2], i+1, total)
+		}
+	}
+	return fmt.Sprintf("running %q (command %d of %d)", strings.Join(append([]string{command.Cmd}, command.Args...), " "), i+1, total)
+}
+
+// checkForBootstrapSuccess checks if bootstrap was successful by checking for existence of the sentinel file.
+func (m *Machine) checkForBootstrapSuccess(ctx context.Context, logResult bool) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	if m.container == nil {
+		return errors.New("unable to set CheckForBootstrapSuccess. the container hosting this machine does not exists")
+	}
+
+	var outErr bytes.Buffer
+	var outStd bytes.Buffer
+	cmd := m.container.Commander.Command("test", "-f", bootstrapSuccessFile)
+	cmd.SetStderr(&outErr)
+	cmd.SetStdout(&outStd)
+	if err := cmd.Run(ctx); err != nil {
+		if logResult {
+			log.Info("Failed running command", "command", "test -f "+bootstrapSuccessFile, "stdout", outStd.String(), "stderr", outErr.String())
+		}
+		return errors.Wrap(err, "failed to run bootstrap check")
+	}
+	return nil
+}
+
+// getBootstrapFailureReason returns the reason of a bootstrap failure reported by the node, if any.
+func (m *Machine) getBootstrapFailureReason(ctx context.Context) string {
+	if m.container == nil {
+		return ""
+	}
+
+	var outStd bytes.Buffer
+	cmd := m.container.Commander.Command("cat", bootstrapFailureReasonFile)
+	cmd.SetStdout(&outStd)
+	if err := cmd.Run(ctx); err != nil {
+		return ""
+	}
+	return strings.TrimSpace(outStd.String())
+}
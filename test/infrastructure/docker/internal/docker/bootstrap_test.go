@@ -0,0 +1,74 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/cluster-api/test/infrastructure/docker/internal/provisioning"
+)
+
+func TestCommandPhase(t *testing.T) {
+	tests := []struct {
+		name    string
+		command provisioning.Cmd
+		want    string
+	}{
+		{
+			name:    "file write",
+			command: provisioning.Cmd{Cmd: "/bin/sh", Args: []string{"-c", "cat > /etc/kubernetes/kubeadm.yaml /dev/stdin"}, Stdin: "content"},
+			want:    "writing file /etc/kubernetes/kubeadm.yaml (command 2 of 5)",
+		},
+		{
+			name:    "file append",
+			command: provisioning.Cmd{Cmd: "/bin/sh", Args: []string{"-c", "cat >> /etc/hosts /dev/stdin"}, Stdin: "content"},
+			want:    "writing file /etc/hosts (command 2 of 5)",
+		},
+		{
+			name:    "shell command",
+			command: provisioning.Cmd{Cmd: "/bin/sh", Args: []string{"-c", "kubeadm init --config /run/kubeadm/kubeadm.yaml"}},
+			want:    `running "/bin/sh -c kubeadm init --config /run/kubeadm/kubeadm.yaml" (command 2 of 5)`,
+		},
+		{
+			name:    "command",
+			command: provisioning.Cmd{Cmd: "mkdir", Args: []string{"-p", "/etc/kubernetes"}},
+			want:    `running "mkdir -p /etc/kubernetes" (command 2 of 5)`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(commandPhase(tt.command, 1, 5)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestBootstrapFailureMessage(t *testing.T) {
+	g := NewWithT(t)
+
+	phaseErr := &BootstrapPhaseError{Phase: "writing file /etc/hosts (command 2 of 5)", Err: errors.New("exit status 1")}
+	g.Expect(BootstrapFailureMessage(errors.Wrap(phaseErr, "failed to bootstrap"))).To(Equal("Bootstrap failed writing file /etc/hosts (command 2 of 5), repeating bootstrap"))
+	g.Expect(errors.Is(phaseErr, phaseErr.Err)).To(BeTrue())
+	g.Expect(BootstrapFailureMessage(errors.New("failed to get bootstrap data"))).To(Equal("Repeating bootstrap"))
+
+	phaseErr.Reason = "readiness check csi did not succeed within 300s"
+	g.Expect(BootstrapFailureMessage(phaseErr)).To(Equal("Bootstrap failed writing file /etc/hosts (command 2 of 5): readiness check csi did not succeed within 300s, repeating bootstrap"))
+}
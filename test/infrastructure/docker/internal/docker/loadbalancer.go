@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"strconv"
 
 	"github.com/pkg/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -33,17 +34,23 @@ import (
 )
 
 type lbCreator interface {
-	CreateExternalLoadBalancerNode(ctx context.Context, name, image, clusterName, listenAddress string, port int32, ipFamily clusterv1.ClusterIPFamily) (*types.Node, error)
+	CreateExternalLoadBalancerNode(ctx context.Context, name, image string, entrypoint []string, clusterName, network, listenAddress string, port int32, ipFamily clusterv1.ClusterIPFamily) (*types.Node, error)
 }
 
 // LoadBalancer manages the load balancer for a specific docker cluster.
 type LoadBalancer struct {
 	name             string
+	network          string
 	image            string
+	implementation   loadbalancer.Implementation
 	container        *types.Node
 	ipFamily         clusterv1.ClusterIPFamily
+	primaryIPFamily  clusterv1.ClusterIPFamily
 	lbCreator        lbCreator
 	controlPlanePort int
+	backendPort      int32
+	frontends        []infrav1.DockerLoadBalancerFrontend
+	healthCheck      *infrav1.DockerLoadBalancerHealthCheck
 }
 
 // NewLoadBalancer returns a new helper for managing a docker loadbalancer with a given name.
@@ -68,26 +75,41 @@ func NewLoadBalancer(ctx context.Context, cluster *clusterv1.Cluster, dockerClus
 	if err != nil {
 		return nil, fmt.Errorf("create load balancer: %s", err)
 	}
+	primaryIPFamily, err := primaryIPFamily(cluster)
+	if err != nil {
+		return nil, fmt.Errorf("create load balancer: %s", err)
+	}
+
+	implementation, err := loadbalancer.ImplementationFor(dockerCluster.Spec.LoadBalancer.Type)
+	if err != nil {
+		return nil, fmt.Errorf("create load balancer: %s", err)
+	}
 
-	image := getLoadBalancerImage(dockerCluster)
+	image := getLoadBalancerImage(dockerCluster, implementation)
 
 	return &LoadBalancer{
 		name:             cluster.Name,
+		network:          dockerCluster.Spec.Network.GetName(),
 		image:            image,
+		implementation:   implementation,
 		container:        container,
 		ipFamily:         ipFamily,
+		primaryIPFamily:  primaryIPFamily,
 		lbCreator:        &Manager{},
 		controlPlanePort: dockerCluster.Spec.ControlPlaneEndpoint.Port,
+		backendPort:      dockerCluster.Spec.LoadBalancer.GetBackendPort(),
+		frontends:        dockerCluster.Spec.LoadBalancer.AdditionalFrontends,
+		healthCheck:      dockerCluster.Spec.LoadBalancer.HealthCheck,
 	}, nil
 }
 
 // getLoadBalancerImage will return the image (e.g. "kindest/haproxy:2.1.1-alpine") to use for
 // the load balancer.
-func getLoadBalancerImage(dockerCluster *infrav1.DockerCluster) string {
+func getLoadBalancerImage(dockerCluster *infrav1.DockerCluster, implementation loadbalancer.Implementation) string {
 	// Check if a non-default image was provided
-	image := loadbalancer.Image
-	imageRepo := loadbalancer.DefaultImageRepository
-	imageTag := loadbalancer.DefaultImageTag
+	image := implementation.Image
+	imageRepo := implementation.DefaultImageRepository
+	imageTag := implementation.DefaultImageTag
 
 	if dockerCluster != nil {
 		if dockerCluster.Spec.LoadBalancer.ImageRepository != "" {
@@ -112,7 +134,7 @@ func (s *LoadBalancer) Create(ctx context.Context) error {
 	log = log.WithValues("ipFamily", s.ipFamily, "loadbalancer", s.name)
 
 	listenAddr := "0.0.0.0"
-	if s.ipFamily == clusterv1.IPv6IPFamily {
+	if s.primaryIPFamily == clusterv1.IPv6IPFamily {
 		listenAddr = "::"
 	}
 	// Create if not exists.
@@ -123,7 +145,9 @@ func (s *LoadBalancer) Create(ctx context.Context) error {
 			ctx,
 			s.containerName(),
 			s.image,
+			s.implementation.Entrypoint,
 			s.name,
+			s.network,
 			listenAddr,
 			0,
 			s.ipFamily,
@@ -137,7 +161,11 @@ func (s *LoadBalancer) Create(ctx context.Context) error {
 }
 
 // UpdateConfiguration updates the external load balancer configuration with new control plane nodes.
-func (s *LoadBalancer) UpdateConfiguration(ctx context.Context) error {
+// If unsafeLoadBalancerConfigTemplate is not empty it is used instead of the default config template
+// of the load balancer implementation.
+// NOTE: the load balancer reloads the configuration without re-creating the container, so changes to
+// ports, additional frontends and health checks are applied to the existing load balancer.
+func (s *LoadBalancer) UpdateConfiguration(ctx context.Context, unsafeLoadBalancerConfigTemplate string) error {
 	log := ctrl.LoggerFrom(ctx)
 
 	if s.container == nil {
@@ -154,48 +182,105 @@ func (s *LoadBalancer) UpdateConfiguration(ctx context.Context) error {
 		return errors.WithStack(err)
 	}
 
-	var backendServers = map[string]string{}
-	for _, n := range controlPlaneNodes {
-		controlPlaneIPv4, controlPlaneIPv6, err := n.IP(ctx)
+	backendServers, err := s.backendServers(ctx, controlPlaneNodes, s.backendPort)
+	if err != nil {
+		return err
+	}
+
+	var additionalFrontends []loadbalancer.FrontendConfigData
+	if len(s.frontends) > 0 {
+		// Additional frontends forward the traffic to all the nodes of the cluster.
+		filters := container.FilterBuilder{}
+		filters.AddKeyNameValue(filterLabel, clusterLabelKey, s.name)
+		filters.AddKeyNameValue(filterLabel, nodeRoleLabelKey, constants.WorkerNodeRoleValue)
+		workerNodes, err := listContainers(ctx, filters)
 		if err != nil {
-			return errors.Wrapf(err, "failed to get IP for container %s", n.String())
+			return errors.WithStack(err)
 		}
-		if s.ipFamily == clusterv1.IPv6IPFamily {
-			backendServers[n.String()] = net.JoinHostPort(controlPlaneIPv6, "6443")
-		} else {
-			backendServers[n.String()] = net.JoinHostPort(controlPlaneIPv4, "6443")
+		nodes := append(append([]*types.Node{}, controlPlaneNodes...), workerNodes...)
+
+		for _, frontend := range s.frontends {
+			backendPort := frontend.BackendPort
+			if backendPort == 0 {
+				backendPort = frontend.Port
+			}
+			frontendBackendServers, err := s.backendServers(ctx, nodes, backendPort)
+			if err != nil {
+				return err
+			}
+			additionalFrontends = append(additionalFrontends, loadbalancer.FrontendConfigData{
+				Name:           frontend.Name,
+				Port:           int(frontend.Port),
+				BackendServers: frontendBackendServers,
+			})
 		}
 	}
 
+	var healthCheck loadbalancer.HealthCheckConfigData
+	if s.healthCheck != nil {
+		healthCheck = loadbalancer.HealthCheckConfigData{
+			IntervalSeconds:    int(s.healthCheck.IntervalSeconds),
+			HealthyThreshold:   int(s.healthCheck.HealthyThreshold),
+			UnhealthyThreshold: int(s.healthCheck.UnhealthyThreshold),
+		}
+	}
+
+	configTemplate := s.implementation.ConfigTemplate
+	if unsafeLoadBalancerConfigTemplate != "" {
+		configTemplate = unsafeLoadBalancerConfigTemplate
+	}
+
 	loadBalancerConfig, err := loadbalancer.Config(&loadbalancer.ConfigData{
-		ControlPlanePort: s.controlPlanePort,
-		BackendServers:   backendServers,
-		IPv6:             s.ipFamily == clusterv1.IPv6IPFamily,
-	})
+		ControlPlanePort:    s.controlPlanePort,
+		BackendServers:      backendServers,
+		IPv6:                s.primaryIPFamily == clusterv1.IPv6IPFamily,
+		DualStack:           s.ipFamily == clusterv1.DualStackIPFamily,
+		AdditionalFrontends: additionalFrontends,
+		HealthCheck:         healthCheck,
+	}, configTemplate)
 	if err != nil {
 		return errors.WithStack(err)
 	}
 
+	// Skip reloading the load balancer if the configuration did not change.
+	if currentConfig, err := s.container.ReadFile(ctx, s.implementation.ConfigPath); err == nil && currentConfig == loadBalancerConfig {
+		log.V(4).Info("Load balancer configuration is up to date")
+		return nil
+	}
+
 	log.Info("Updating load balancer configuration")
-	if err := s.container.WriteFile(ctx, loadbalancer.ConfigPath, loadBalancerConfig); err != nil {
+	if err := s.container.WriteFile(ctx, s.implementation.ConfigPath, loadBalancerConfig); err != nil {
 		return errors.WithStack(err)
 	}
 
 	return errors.WithStack(s.container.Kill(ctx, "SIGHUP"))
 }
 
+// backendServers returns the host:port addresses of the given nodes, using the address of the primary IP family.
+func (s *LoadBalancer) backendServers(ctx context.Context, nodes []*types.Node, port int32) (map[string]string, error) {
+	backendServers := map[string]string{}
+	for _, n := range nodes {
+		ipv4, ipv6, err := n.IP(ctx)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get IP for container %s", n.String())
+		}
+		ip := addressForIPFamily(s.primaryIPFamily, ipv4, ipv6)
+		if ip == "" {
+			return nil, errors.Errorf("failed to get %s IP for container %s", s.primaryIPFamily, n.String())
+		}
+		backendServers[n.String()] = net.JoinHostPort(ip, strconv.Itoa(int(port)))
+	}
+	return backendServers, nil
+}
+
 // IP returns the load balancer IP address.
+// For dual-stack clusters the address of the primary IP family is returned.
 func (s *LoadBalancer) IP(ctx context.Context) (string, error) {
 	lbIPv4, lbIPv6, err := s.container.IP(ctx)
 	if err != nil {
 		return "", errors.WithStack(err)
 	}
-	var lbIP string
-	if s.ipFamily == clusterv1.IPv6IPFamily {
-		lbIP = lbIPv6
-	} else {
-		lbIP = lbIPv4
-	}
+	lbIP := addressForIPFamily(s.primaryIPFamily, lbIPv4, lbIPv6)
 	if lbIP == "" {
 		// if there is a load balancer container with the same name exists but is stopped, it may not have IP address associated with it.
 		return "", errors.Errorf("load balancer IP cannot be empty: container %s does not have an associated IP address", s.containerName())
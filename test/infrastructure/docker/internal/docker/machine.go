@@ -19,7 +19,6 @@ package docker
 import (
 	"bytes"
 	"context"
-	"encoding/base64"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -31,6 +30,7 @@ import (
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	apimachinerytypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -38,14 +38,10 @@ import (
 	"sigs.k8s.io/kind/pkg/cluster/constants"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
-	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
 	"sigs.k8s.io/cluster-api/internal/util/taints"
 	"sigs.k8s.io/cluster-api/test/infrastructure/container"
 	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1beta1"
 	"sigs.k8s.io/cluster-api/test/infrastructure/docker/internal/docker/types"
-	"sigs.k8s.io/cluster-api/test/infrastructure/docker/internal/provisioning"
-	"sigs.k8s.io/cluster-api/test/infrastructure/docker/internal/provisioning/cloudinit"
-	"sigs.k8s.io/cluster-api/test/infrastructure/docker/internal/provisioning/ignition"
 	"sigs.k8s.io/cluster-api/test/infrastructure/kind"
 	"sigs.k8s.io/cluster-api/util/patch"
 )
@@ -55,17 +51,18 @@ var (
 )
 
 type nodeCreator interface {
-	CreateControlPlaneNode(ctx context.Context, name, clusterName, listenAddress string, port int32, mounts []v1alpha4.Mount, portMappings []v1alpha4.PortMapping, labels map[string]string, ipFamily clusterv1.ClusterIPFamily, kindMapping kind.Mapping) (node *types.Node, err error)
-	CreateWorkerNode(ctx context.Context, name, clusterName string, mounts []v1alpha4.Mount, portMappings []v1alpha4.PortMapping, labels map[string]string, ipFamily clusterv1.ClusterIPFamily, kindMapping kind.Mapping) (node *types.Node, err error)
+	CreateControlPlaneNode(ctx context.Context, name, clusterName, network, listenAddress string, port int32, mounts []v1alpha4.Mount, portMappings []v1alpha4.PortMapping, resources container.Resources, sysctls map[string]string, labels map[string]string, ipFamily clusterv1.ClusterIPFamily, kindMapping kind.Mapping) (node *types.Node, err error)
+	CreateWorkerNode(ctx context.Context, name, clusterName, network string, mounts []v1alpha4.Mount, portMappings []v1alpha4.PortMapping, resources container.Resources, sysctls map[string]string, labels map[string]string, ipFamily clusterv1.ClusterIPFamily, kindMapping kind.Mapping) (node *types.Node, err error)
 }
 
 // Machine implement a service for managing the docker containers hosting a kubernetes nodes.
 type Machine struct {
-	cluster     string
-	machine     string
-	ipFamily    clusterv1.ClusterIPFamily
-	container   *types.Node
-	nodeCreator nodeCreator
+	cluster         string
+	machine         string
+	ipFamily        clusterv1.ClusterIPFamily
+	primaryIPFamily clusterv1.ClusterIPFamily
+	container       *types.Node
+	nodeCreator     nodeCreator
 }
 
 // NewMachine returns a new Machine service for the given Cluster/DockerCluster pair.
@@ -96,13 +93,18 @@ func NewMachine(ctx context.Context, cluster *clusterv1.Cluster, machine string,
 	if err != nil {
 		return nil, fmt.Errorf("create docker machine: %s", err)
 	}
+	primaryIPFamily, err := primaryIPFamily(cluster)
+	if err != nil {
+		return nil, fmt.Errorf("create docker machine: %s", err)
+	}
 
 	return &Machine{
-		cluster:     cluster.Name,
-		machine:     machine,
-		ipFamily:    ipFamily,
-		container:   newContainer,
-		nodeCreator: &Manager{},
+		cluster:         cluster.Name,
+		machine:         machine,
+		ipFamily:        ipFamily,
+		primaryIPFamily: primaryIPFamily,
+		container:       newContainer,
+		nodeCreator:     &Manager{},
 	}, nil
 }
 
@@ -130,15 +132,20 @@ func ListMachinesByCluster(ctx context.Context, cluster *clusterv1.Cluster, labe
 	if err != nil {
 		return nil, fmt.Errorf("list docker machines by cluster: %s", err)
 	}
+	primaryIPFamily, err := primaryIPFamily(cluster)
+	if err != nil {
+		return nil, fmt.Errorf("list docker machines by cluster: %s", err)
+	}
 
 	machines := make([]*Machine, len(containers))
 	for i, containerNode := range containers {
 		machines[i] = &Machine{
-			cluster:     cluster.Name,
-			machine:     machineFromContainerName(cluster.Name, containerNode.Name),
-			ipFamily:    ipFamily,
-			container:   containerNode,
-			nodeCreator: &Manager{},
+			cluster:         cluster.Name,
+			machine:         machineFromContainerName(cluster.Name, containerNode.Name),
+			ipFamily:        ipFamily,
+			primaryIPFamily: primaryIPFamily,
+			container:       containerNode,
+			nodeCreator:     &Manager{},
 		}
 	}
 
@@ -175,6 +182,7 @@ func (m *Machine) ProviderID() string {
 
 // Address will get the IP address of the machine. It can return
 // a single IPv4 address, a single IPv6 address or one of each depending on the machine.ipFamily.
+// For dual-stack machines the address of the primary IP family is returned first.
 func (m *Machine) Address(ctx context.Context) ([]string, error) {
 	ipv4, ipv6, err := m.container.IP(ctx)
 	if err != nil {
@@ -182,15 +190,30 @@ func (m *Machine) Address(ctx context.Context) ([]string, error) {
 	}
 	switch m.ipFamily {
 	case clusterv1.IPv6IPFamily:
-		return []string{ipv6}, nil
+		return nonEmptyAddresses(ipv6), nil
 	case clusterv1.IPv4IPFamily:
-		return []string{ipv4}, nil
+		return nonEmptyAddresses(ipv4), nil
 	case clusterv1.DualStackIPFamily:
-		return []string{ipv4, ipv6}, nil
+		if m.primaryIPFamily == clusterv1.IPv6IPFamily {
+			return nonEmptyAddresses(ipv6, ipv4), nil
+		}
+		return nonEmptyAddresses(ipv4, ipv6), nil
 	}
 	return nil, errors.New("unknown ipFamily")
 }
 
+// nonEmptyAddresses drops empty addresses, e.g. the IPv6 address of a container attached to a docker network
+// without IPv6 enabled.
+func nonEmptyAddresses(addresses ...string) []string {
+	res := []string{}
+	for _, address := range addresses {
+		if address != "" {
+			res = append(res, address)
+		}
+	}
+	return res
+}
+
 // ContainerImage return the image of the container for this machine
 // or empty string if the container does not exist yet.
 func (m *Machine) ContainerImage() string {
@@ -200,8 +223,8 @@ func (m *Machine) ContainerImage() string {
 	return m.container.Image
 }
 
-// Create creates a docker container hosting a Kubernetes node.
-func (m *Machine) Create(ctx context.Context, image string, role string, version *string, labels map[string]string, mounts []infrav1.Mount) error {
+// Create creates a docker container hosting a Kubernetes node, attached to the given docker network.
+func (m *Machine) Create(ctx context.Context, image string, role string, version *string, network string, labels map[string]string, mounts []infrav1.Mount, resources *infrav1.DockerMachineResources, sysctls map[string]string) error {
 	log := ctrl.LoggerFrom(ctx)
 
 	// Create if not exists.
@@ -229,10 +252,13 @@ func (m *Machine) Create(ctx context.Context, image string, role string, version
 				ctx,
 				m.ContainerName(),
 				m.cluster,
+				network,
 				"127.0.0.1",
 				0,
 				kindMounts(mounts),
 				nil,
+				containerResources(resources),
+				sysctls,
 				labels,
 				m.ipFamily,
 				kindMapping,
@@ -246,8 +272,11 @@ func (m *Machine) Create(ctx context.Context, image string, role string, version
 				ctx,
 				m.ContainerName(),
 				m.cluster,
+				network,
 				kindMounts(mounts),
 				nil,
+				containerResources(resources),
+				sysctls,
 				labels,
 				m.ipFamily,
 				kindMapping,
@@ -291,6 +320,20 @@ func kindMounts(mounts []infrav1.Mount) []v1alpha4.Mount {
 	return ret
 }
 
+func containerResources(resources *infrav1.DockerMachineResources) container.Resources {
+	ret := container.Resources{}
+	if resources == nil {
+		return ret
+	}
+	if resources.CPU != nil {
+		ret.NanoCPUs = resources.CPU.MilliValue() * 1e6
+	}
+	if resources.Memory != nil {
+		ret.MemoryBytes = resources.Memory.Value()
+	}
+	return ret
+}
+
 // PreloadLoadImages takes a list of container images and imports them into a machine.
 func (m *Machine) PreloadLoadImages(ctx context.Context, images []string) error {
 	// Save the image into a tar
@@ -328,88 +371,34 @@ func (m *Machine) PreloadLoadImages(ctx context.Context, images []string) error
 	return nil
 }
 
-// ExecBootstrap runs bootstrap on a node, this is generally `kubeadm <init|join>`.
-func (m *Machine) ExecBootstrap(ctx context.Context, data string, format bootstrapv1.Format, version *string, image string) error {
-	log := ctrl.LoggerFrom(ctx)
-
-	if m.container == nil {
-		return errors.New("unable to set ExecBootstrap. the container hosting this machine does not exists")
-	}
-
-	// Get the kindMapping for the target K8s version.
-	// NOTE: The kindMapping allows to select the most recent kindest/node image available, if any, as well as
-	// provide info about the mode to be used when starting the kindest/node image itself.
-	if version == nil {
-		return errors.New("cannot create a DockerMachine for a nil version")
+// ReconcileNetworks attaches the container hosting the machine to the given networks, if not already attached.
+// NOTE: Networks are only added; the container is never detached from networks not in the list, e.g. the default kind network.
+func (m *Machine) ReconcileNetworks(ctx context.Context, networks []string) error {
+	if m.container == nil || len(networks) == 0 {
+		return nil
 	}
+	log := ctrl.LoggerFrom(ctx)
 
-	semVer, err := semver.Parse(strings.TrimPrefix(*version, "v"))
+	containerRuntime, err := container.RuntimeFrom(ctx)
 	if err != nil {
-		return errors.Wrap(err, "failed to parse DockerMachine version")
+		return errors.Wrap(err, "failed to connect to container runtime")
 	}
 
-	kindMapping := kind.GetMapping(semVer, image)
-
-	// Decode the cloud config
-	cloudConfig, err := base64.StdEncoding.DecodeString(data)
+	attached, err := containerRuntime.GetContainerNetworks(ctx, m.ContainerName())
 	if err != nil {
-		return errors.Wrap(err, "failed to decode machine's bootstrap data")
+		return errors.Wrapf(err, "failed to get the networks of container %q", m.ContainerName())
 	}
+	attachedNetworks := sets.New[string](attached...)
 
-	var commands []provisioning.Cmd
-
-	switch format {
-	case bootstrapv1.CloudConfig:
-		commands, err = cloudinit.RawCloudInitToProvisioningCommands(cloudConfig, kindMapping)
-	case bootstrapv1.Ignition:
-		commands, err = ignition.RawIgnitionToProvisioningCommands(cloudConfig)
-	default:
-		return fmt.Errorf("unknown provisioning format %q", format)
-	}
-
-	if err != nil {
-		log.Info("provisioning code failed to parse", "bootstrap data", data)
-		return errors.Wrap(err, "failed to join a control plane node with kubeadm")
-	}
-
-	var outErr bytes.Buffer
-	var outStd bytes.Buffer
-	for _, command := range commands {
-		cmd := m.container.Commander.Command(command.Cmd, command.Args...)
-		cmd.SetStderr(&outErr)
-		cmd.SetStdout(&outStd)
-		if command.Stdin != "" {
-			cmd.SetStdin(strings.NewReader(command.Stdin))
-		}
-		err := cmd.Run(ctx)
-		if err != nil {
-			log.Info("Failed running command", "instance", m.Name(), "command", command, "stdout", outStd.String(), "stderr", outErr.String(), "bootstrap data", data)
-			logContainerDebugInfo(ctx, log, m.ContainerName())
-			return errors.Wrapf(err, "failed to run cloud config: stdout: %s stderr: %s", outStd.String(), outErr.String())
+	for _, network := range networks {
+		if attachedNetworks.Has(network) {
+			continue
 		}
-	}
-
-	return nil
-}
-
-// CheckForBootstrapSuccess checks if bootstrap was successful by checking for existence of the sentinel file.
-func (m *Machine) CheckForBootstrapSuccess(ctx context.Context, logResult bool) error {
-	log := ctrl.LoggerFrom(ctx)
-
-	if m.container == nil {
-		return errors.New("unable to set CheckForBootstrapSuccess. the container hosting this machine does not exists")
-	}
-
-	var outErr bytes.Buffer
-	var outStd bytes.Buffer
-	cmd := m.container.Commander.Command("test", "-f", "/run/cluster-api/bootstrap-success.complete")
-	cmd.SetStderr(&outErr)
-	cmd.SetStdout(&outStd)
-	if err := cmd.Run(ctx); err != nil {
-		if logResult {
-			log.Info("Failed running command", "command", "test -f /run/cluster-api/bootstrap-success.complete", "stdout", outStd.String(), "stderr", outErr.String())
+		log.Info("Attaching machine container to network", "network", network)
+		if err := containerRuntime.ConnectContainerToNetwork(ctx, m.ContainerName(), network); err != nil {
+			return errors.Wrapf(err, "failed to attach container %q to network %q", m.ContainerName(), network)
 		}
-		return errors.Wrap(err, "failed to run bootstrap check")
+		attachedNetworks.Insert(network)
 	}
 	return nil
 }
@@ -0,0 +1,101 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"sigs.k8s.io/cluster-api/test/infrastructure/container"
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1beta1"
+	"sigs.k8s.io/cluster-api/test/infrastructure/docker/internal/docker/types"
+)
+
+func TestContainerResources(t *testing.T) {
+	cpu := resource.MustParse("1500m")
+	memory := resource.MustParse("2Gi")
+
+	tests := []struct {
+		name      string
+		resources *infrav1.DockerMachineResources
+		want      container.Resources
+	}{
+		{
+			name:      "no resources",
+			resources: nil,
+			want:      container.Resources{},
+		},
+		{
+			name: "CPU and memory",
+			resources: &infrav1.DockerMachineResources{
+				CPU:    &cpu,
+				Memory: &memory,
+			},
+			want: container.Resources{NanoCPUs: 1500000000, MemoryBytes: 2147483648},
+		},
+		{
+			name: "only memory",
+			resources: &infrav1.DockerMachineResources{
+				Memory: &memory,
+			},
+			want: container.Resources{MemoryBytes: 2147483648},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(containerResources(tt.resources)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestReconcileNetworks(t *testing.T) {
+	t.Run("no-op if the container does not exist", func(t *testing.T) {
+		g := NewWithT(t)
+		containerRuntime := &container.FakeRuntime{}
+		ctx := container.RuntimeInto(context.Background(), containerRuntime)
+		containerRuntime.ResetConnectContainerToNetworkCallLogs()
+
+		m := &Machine{cluster: "TestCluster", machine: "TestMachine"}
+		g.Expect(m.ReconcileNetworks(ctx, []string{"net-a"})).To(Succeed())
+		g.Expect(containerRuntime.ConnectContainerToNetworkCalls()).To(BeEmpty())
+	})
+	t.Run("attaches the container to the missing networks only", func(t *testing.T) {
+		g := NewWithT(t)
+		containerRuntime := &container.FakeRuntime{}
+		ctx := container.RuntimeInto(context.Background(), containerRuntime)
+		containerRuntime.ResetConnectContainerToNetworkCallLogs()
+
+		m := &Machine{
+			cluster:   "TestCluster",
+			machine:   "TestMachine",
+			container: types.NewNode("TestCluster-TestMachine", "TestImage", "worker"),
+		}
+		g.Expect(m.ReconcileNetworks(ctx, []string{"net-a"})).To(Succeed())
+		// Reconciling again with an additional network only attaches the new network.
+		g.Expect(m.ReconcileNetworks(ctx, []string{"net-a", "net-b", "net-b"})).To(Succeed())
+
+		g.Expect(containerRuntime.ConnectContainerToNetworkCalls()).To(Equal([]container.ConnectContainerToNetworkArgs{
+			{ContainerName: "TestCluster-TestMachine", Network: "net-a"},
+			{ContainerName: "TestCluster-TestMachine", Network: "net-b"},
+		}))
+	})
+}
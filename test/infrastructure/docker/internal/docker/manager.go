@@ -26,6 +26,7 @@ import (
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/test/infrastructure/container"
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1beta1"
 	"sigs.k8s.io/cluster-api/test/infrastructure/docker/internal/docker/types"
 	"sigs.k8s.io/cluster-api/test/infrastructure/kind"
 )
@@ -37,10 +38,7 @@ const KubeadmContainerPort = 6443
 const ControlPlanePort = 6443
 
 // DefaultNetwork is the default network name to use in kind.
-const DefaultNetwork = "kind"
-
-// haproxyEntrypoint is the entrypoint used to start the haproxy load balancer container.
-var haproxyEntrypoint = []string{"haproxy", "-W", "-db", "-f", "/usr/local/etc/haproxy/haproxy.cfg"}
+const DefaultNetwork = infrav1.DefaultNetworkName
 
 // Manager is the kind manager type.
 type Manager struct{}
@@ -48,10 +46,13 @@ type Manager struct{}
 type nodeCreateOpts struct {
 	Name         string
 	ClusterName  string
+	Network      string
 	Role         string
 	EntryPoint   []string
 	Mounts       []v1alpha4.Mount
 	PortMappings []v1alpha4.PortMapping
+	Resources    container.Resources
+	Sysctls      map[string]string
 	Labels       map[string]string
 	IPFamily     clusterv1.ClusterIPFamily
 	KindMapping  kind.Mapping
@@ -60,7 +61,7 @@ type nodeCreateOpts struct {
 // CreateControlPlaneNode will create a new control plane container.
 // NOTE: If port is 0 picking a host port for the control plane is delegated to the container runtime and is not stable across container restarts.
 // This means that connection to a control plane node may take some time to recover if the underlying container is restarted.
-func (m *Manager) CreateControlPlaneNode(ctx context.Context, name, clusterName, listenAddress string, port int32, mounts []v1alpha4.Mount, portMappings []v1alpha4.PortMapping, labels map[string]string, ipFamily clusterv1.ClusterIPFamily, kindMapping kind.Mapping) (*types.Node, error) {
+func (m *Manager) CreateControlPlaneNode(ctx context.Context, name, clusterName, network, listenAddress string, port int32, mounts []v1alpha4.Mount, portMappings []v1alpha4.PortMapping, resources container.Resources, sysctls map[string]string, labels map[string]string, ipFamily clusterv1.ClusterIPFamily, kindMapping kind.Mapping) (*types.Node, error) {
 	// add api server port mapping
 	portMappingsWithAPIServer := append(portMappings, v1alpha4.PortMapping{
 		ListenAddress: listenAddress,
@@ -71,9 +72,12 @@ func (m *Manager) CreateControlPlaneNode(ctx context.Context, name, clusterName,
 	createOpts := &nodeCreateOpts{
 		Name:         name,
 		ClusterName:  clusterName,
+		Network:      network,
 		Role:         constants.ControlPlaneNodeRoleValue,
 		PortMappings: portMappingsWithAPIServer,
 		Mounts:       mounts,
+		Resources:    resources,
+		Sysctls:      sysctls,
 		Labels:       labels,
 		IPFamily:     ipFamily,
 		KindMapping:  kindMapping,
@@ -87,13 +91,16 @@ func (m *Manager) CreateControlPlaneNode(ctx context.Context, name, clusterName,
 }
 
 // CreateWorkerNode will create a new worker container.
-func (m *Manager) CreateWorkerNode(ctx context.Context, name, clusterName string, mounts []v1alpha4.Mount, portMappings []v1alpha4.PortMapping, labels map[string]string, ipFamily clusterv1.ClusterIPFamily, kindMapping kind.Mapping) (*types.Node, error) {
+func (m *Manager) CreateWorkerNode(ctx context.Context, name, clusterName, network string, mounts []v1alpha4.Mount, portMappings []v1alpha4.PortMapping, resources container.Resources, sysctls map[string]string, labels map[string]string, ipFamily clusterv1.ClusterIPFamily, kindMapping kind.Mapping) (*types.Node, error) {
 	createOpts := &nodeCreateOpts{
 		Name:         name,
 		ClusterName:  clusterName,
+		Network:      network,
 		Role:         constants.WorkerNodeRoleValue,
 		PortMappings: portMappings,
 		Mounts:       mounts,
+		Resources:    resources,
+		Sysctls:      sysctls,
 		Labels:       labels,
 		IPFamily:     ipFamily,
 		KindMapping:  kindMapping,
@@ -104,7 +111,7 @@ func (m *Manager) CreateWorkerNode(ctx context.Context, name, clusterName string
 // CreateExternalLoadBalancerNode will create a new container to act as the load balancer for external access.
 // NOTE: If port is 0 picking a host port for the load balancer is delegated to the container runtime and is not stable across container restarts.
 // This can break the Kubeconfig in kind, i.e. the file resulting from `kind get kubeconfig -n $CLUSTER_NAME' if the load balancer container is restarted.
-func (m *Manager) CreateExternalLoadBalancerNode(ctx context.Context, name, image, clusterName, listenAddress string, port int32, _ clusterv1.ClusterIPFamily) (*types.Node, error) {
+func (m *Manager) CreateExternalLoadBalancerNode(ctx context.Context, name, image string, entrypoint []string, clusterName, network, listenAddress string, port int32, _ clusterv1.ClusterIPFamily) (*types.Node, error) {
 	// load balancer port mapping
 	portMappings := []v1alpha4.PortMapping{{
 		ListenAddress: listenAddress,
@@ -115,9 +122,10 @@ func (m *Manager) CreateExternalLoadBalancerNode(ctx context.Context, name, imag
 	createOpts := &nodeCreateOpts{
 		Name:         name,
 		ClusterName:  clusterName,
+		Network:      network,
 		Role:         constants.ExternalLoadBalancerNodeRoleValue,
 		PortMappings: portMappings,
-		EntryPoint:   haproxyEntrypoint,
+		EntryPoint:   entrypoint,
 		// Load balancer doesn't have an equivalent in kind, but we use a kind.Mapping to
 		// forward the image name to create node.
 		KindMapping: kind.Mapping{
@@ -145,6 +153,11 @@ func createNode(ctx context.Context, opts *nodeCreateOpts) (*types.Node, error)
 		containerLabels[name] = value
 	}
 
+	network := opts.Network
+	if network == "" {
+		network = DefaultNetwork
+	}
+
 	runOptions := &container.RunContainerInput{
 		Name:   opts.Name, // make hostname match container name
 		Image:  opts.KindMapping.Image,
@@ -158,7 +171,9 @@ func createNode(ctx context.Context, opts *nodeCreateOpts) (*types.Node, error)
 		Volumes:      map[string]string{"/var": ""},
 		Mounts:       generateMountInfo(opts.Mounts),
 		PortMappings: generatePortMappings(opts.PortMappings),
-		Network:      DefaultNetwork,
+		Resources:    opts.Resources,
+		Sysctls:      opts.Sysctls,
+		Network:      network,
 		Tmpfs: map[string]string{
 			"/tmp": "", // various things depend on working /tmp
 			"/run": "", // systemd wants a writable /run
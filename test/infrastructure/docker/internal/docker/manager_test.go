@@ -80,7 +80,7 @@ func TestCreateControlPlaneNode(t *testing.T) {
 
 	containerRuntime.ResetRunContainerCallLogs()
 	m := Manager{}
-	node, err := m.CreateControlPlaneNode(ctx, "TestName", "TestCluster", "100.100.100.100", 80, []v1alpha4.Mount{}, []v1alpha4.PortMapping{}, make(map[string]string), clusterv1.IPv4IPFamily, kind.Mapping{Image: "TestImage"})
+	node, err := m.CreateControlPlaneNode(ctx, "TestName", "TestCluster", "", "100.100.100.100", 80, []v1alpha4.Mount{}, []v1alpha4.PortMapping{}, container.Resources{}, nil, make(map[string]string), clusterv1.IPv4IPFamily, kind.Mapping{Image: "TestImage"})
 
 	g.Expect(err).ShouldNot(HaveOccurred())
 	g.Expect(node.Role()).Should(Equal(constants.ControlPlaneNodeRoleValue))
@@ -93,6 +93,7 @@ func TestCreateControlPlaneNode(t *testing.T) {
 	g.Expect(runConfig).ToNot(BeNil())
 	g.Expect(runConfig.Labels).To(HaveLen(2))
 	g.Expect(runConfig.Labels["io.x-k8s.kind.role"]).To(Equal(constants.ControlPlaneNodeRoleValue))
+	g.Expect(runConfig.Network).To(Equal(DefaultNetwork))
 }
 
 func TestCreateWorkerNode(t *testing.T) {
@@ -103,7 +104,9 @@ func TestCreateWorkerNode(t *testing.T) {
 
 	containerRuntime.ResetRunContainerCallLogs()
 	m := Manager{}
-	node, err := m.CreateWorkerNode(ctx, "TestName", "TestCluster", []v1alpha4.Mount{}, []v1alpha4.PortMapping{}, make(map[string]string), clusterv1.IPv4IPFamily, kind.Mapping{Image: "TestImage"})
+	resources := container.Resources{NanoCPUs: 2e9, MemoryBytes: 4 * 1024 * 1024 * 1024}
+	sysctls := map[string]string{"net.ipv4.ip_forward": "1"}
+	node, err := m.CreateWorkerNode(ctx, "TestName", "TestCluster", "test-network", []v1alpha4.Mount{}, []v1alpha4.PortMapping{}, resources, sysctls, make(map[string]string), clusterv1.IPv4IPFamily, kind.Mapping{Image: "TestImage"})
 
 	g.Expect(err).ShouldNot(HaveOccurred())
 	g.Expect(node.Role()).Should(Equal(constants.WorkerNodeRoleValue))
@@ -116,6 +119,9 @@ func TestCreateWorkerNode(t *testing.T) {
 	g.Expect(runConfig).ToNot(BeNil())
 	g.Expect(runConfig.Labels).To(HaveLen(2))
 	g.Expect(runConfig.Labels["io.x-k8s.kind.role"]).To(Equal(constants.WorkerNodeRoleValue))
+	g.Expect(runConfig.Resources).To(Equal(resources))
+	g.Expect(runConfig.Sysctls).To(Equal(sysctls))
+	g.Expect(runConfig.Network).To(Equal("test-network"))
 }
 
 func TestCreateExternalLoadBalancerNode(t *testing.T) {
@@ -126,7 +132,7 @@ func TestCreateExternalLoadBalancerNode(t *testing.T) {
 
 	containerRuntime.ResetRunContainerCallLogs()
 	m := Manager{}
-	node, err := m.CreateExternalLoadBalancerNode(ctx, "TestName", "TestImage", "TestCluster", "100.100.100.100", 0, clusterv1.IPv4IPFamily)
+	node, err := m.CreateExternalLoadBalancerNode(ctx, "TestName", "TestImage", []string{"haproxy"}, "TestCluster", "", "100.100.100.100", 0, clusterv1.IPv4IPFamily)
 
 	g.Expect(err).ShouldNot(HaveOccurred())
 	g.Expect(node.Role()).Should(Equal(constants.ExternalLoadBalancerNodeRoleValue))
@@ -141,4 +147,5 @@ func TestCreateExternalLoadBalancerNode(t *testing.T) {
 	g.Expect(runConfig.Labels["io.x-k8s.kind.role"]).To(Equal(constants.ExternalLoadBalancerNodeRoleValue))
 	g.Expect(runConfig.PortMappings).To(HaveLen(1))
 	g.Expect(runConfig.PortMappings[0].ContainerPort).To(Equal(int32(ControlPlanePort)))
+	g.Expect(runConfig.Entrypoint).To(Equal([]string{"haproxy"}))
 }
@@ -0,0 +1,115 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/test/infrastructure/container"
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1beta1"
+)
+
+// Network is a helper for managing the docker network the containers of a cluster are attached to.
+type Network struct {
+	name              string
+	clusterName       string
+	ipFamily          clusterv1.ClusterIPFamily
+	externallyManaged bool
+}
+
+// NewNetwork returns a new helper for managing the docker network of a cluster.
+func NewNetwork(cluster *clusterv1.Cluster, dockerCluster *infrav1.DockerCluster) (*Network, error) {
+	if cluster.Name == "" {
+		return nil, errors.New("create network: cluster name is empty")
+	}
+
+	ipFamily, err := cluster.GetIPFamily()
+	if err != nil {
+		return nil, errors.Wrap(err, "create network")
+	}
+
+	name := dockerCluster.Spec.Network.GetName()
+	_, annotated := dockerCluster.Annotations[infrav1.NetworkExternallyManagedAnnotation]
+
+	return &Network{
+		name:              name,
+		clusterName:       cluster.Name,
+		ipFamily:          ipFamily,
+		externallyManaged: annotated || name == infrav1.DefaultNetworkName,
+	}, nil
+}
+
+// Name returns the name of the docker network.
+func (n *Network) Name() string {
+	return n.name
+}
+
+// IsExternallyManaged returns true if the docker network is not managed by CAPD.
+func (n *Network) IsExternallyManaged() bool {
+	return n.externallyManaged
+}
+
+// Get returns the docker network, or nil if it does not exist.
+func (n *Network) Get(ctx context.Context) (*container.Network, error) {
+	containerRuntime, err := container.RuntimeFrom(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect to container runtime")
+	}
+	return containerRuntime.GetNetwork(ctx, n.name)
+}
+
+// Create creates the docker network, labeled with the name of the cluster.
+func (n *Network) Create(ctx context.Context) error {
+	if n.externallyManaged {
+		return errors.Errorf("network %q is externally managed and cannot be created", n.name)
+	}
+
+	containerRuntime, err := container.RuntimeFrom(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to container runtime")
+	}
+	return containerRuntime.CreateNetwork(ctx, &container.CreateNetworkInput{
+		Name:   n.name,
+		IPv6:   sets.New(clusterv1.IPv6IPFamily, clusterv1.DualStackIPFamily).Has(n.ipFamily),
+		Labels: map[string]string{clusterLabelKey: n.clusterName},
+	})
+}
+
+// Delete deletes the docker network if it is managed by CAPD and was created for the cluster.
+func (n *Network) Delete(ctx context.Context) error {
+	if n.externallyManaged {
+		return nil
+	}
+
+	network, err := n.Get(ctx)
+	if err != nil {
+		return err
+	}
+	if network == nil || network.Labels[clusterLabelKey] != n.clusterName {
+		return nil
+	}
+
+	containerRuntime, err := container.RuntimeFrom(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to container runtime")
+	}
+	return containerRuntime.DeleteNetwork(ctx, n.name)
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"sigs.k8s.io/cluster-api/test/infrastructure/container"
+)
+
+// snapshotVarArchive is the archive of /var stored in the node container filesystem when taking a snapshot.
+// NOTE: /var is a volume, and volumes are not included in the image created when committing a container.
+const snapshotVarArchive = "/kind/snapshot-var.tar"
+
+// snapshotRestoreScript restores /var from the archive, if any, and then runs the original entrypoint of the node image,
+// which is passed as arguments.
+var snapshotRestoreScript = fmt.Sprintf(`if [ -f %[1]s ]; then tar -C /var -xpf %[1]s && rm -f %[1]s; fi; exec "$@"`, snapshotVarArchive)
+
+// Snapshot saves the container hosting the machine to an image, including the content of /var.
+// Machines created from the image start with the same state of the machine at the time of the snapshot,
+// so they do not need to be bootstrapped again.
+func (m *Machine) Snapshot(ctx context.Context, image string) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	if m.container == nil {
+		return errors.New("unable to snapshot the machine: the container hosting this machine does not exists")
+	}
+
+	containerRuntime, err := container.RuntimeFrom(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to container runtime")
+	}
+
+	log.Info("Taking a snapshot of the machine", "instance", m.Name(), "image", image)
+	if err := m.runSnapshotCommand(ctx, "tar", "-C", "/var", "--exclude=./log", "-cpf", snapshotVarArchive, "."); err != nil {
+		return errors.Wrap(err, "failed to archive /var")
+	}
+	defer func() {
+		if err := m.runSnapshotCommand(ctx, "rm", "-f", snapshotVarArchive); err != nil {
+			log.Error(err, "Failed to delete the /var archive", "instance", m.Name())
+		}
+	}()
+
+	if err := containerRuntime.CommitContainer(ctx, m.ContainerName(), &container.CommitContainerInput{
+		Image:            image,
+		EntrypointPrefix: []string{"/bin/sh", "-c", snapshotRestoreScript, "sh"},
+	}); err != nil {
+		return errors.Wrapf(err, "failed to snapshot the machine to image %s", image)
+	}
+	return nil
+}
+
+func (m *Machine) runSnapshotCommand(ctx context.Context, command string, args ...string) error {
+	var outErr bytes.Buffer
+	cmd := m.container.Commander.Command(command, args...)
+	cmd.SetStderr(&outErr)
+	if err := cmd.Run(ctx); err != nil {
+		return errors.Wrapf(err, "failed to run %s: %s", command, outErr.String())
+	}
+	return nil
+}
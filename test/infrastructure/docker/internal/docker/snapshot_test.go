@@ -0,0 +1,64 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api/test/infrastructure/container"
+	"sigs.k8s.io/cluster-api/test/infrastructure/docker/internal/docker/types"
+)
+
+func TestSnapshot(t *testing.T) {
+	t.Run("fails if the container does not exist", func(t *testing.T) {
+		g := NewWithT(t)
+		ctx := container.RuntimeInto(context.Background(), &container.FakeRuntime{})
+
+		m := &Machine{cluster: "TestCluster", machine: "TestMachine"}
+		g.Expect(m.Snapshot(ctx, "snapshot:latest")).ToNot(Succeed())
+	})
+	t.Run("archives /var and commits the container", func(t *testing.T) {
+		g := NewWithT(t)
+		containerRuntime := &container.FakeRuntime{}
+		ctx := container.RuntimeInto(context.Background(), containerRuntime)
+		containerRuntime.ResetExecContainerCallLogs()
+		containerRuntime.ResetCommitContainerCallLogs()
+
+		m := &Machine{
+			cluster:   "TestCluster",
+			machine:   "TestMachine",
+			container: types.NewNode("TestCluster-TestMachine", "TestImage", "worker"),
+		}
+		g.Expect(m.Snapshot(ctx, "snapshot:latest")).To(Succeed())
+
+		execCalls := containerRuntime.ExecContainerCalls()
+		g.Expect(execCalls).To(HaveLen(2))
+		g.Expect(execCalls[0].Command).To(Equal("tar"))
+		g.Expect(execCalls[0].Args).To(ContainElement(snapshotVarArchive))
+		g.Expect(execCalls[1].Command).To(Equal("rm"))
+		g.Expect(execCalls[1].Args).To(ContainElement(snapshotVarArchive))
+
+		commitCalls := containerRuntime.CommitContainerCalls()
+		g.Expect(commitCalls).To(HaveLen(1))
+		g.Expect(commitCalls[0].ContainerName).To(Equal("TestCluster-TestMachine"))
+		g.Expect(commitCalls[0].Config.Image).To(Equal("snapshot:latest"))
+		g.Expect(commitCalls[0].Config.EntrypointPrefix).To(Equal([]string{"/bin/sh", "-c", snapshotRestoreScript, "sh"}))
+	})
+}
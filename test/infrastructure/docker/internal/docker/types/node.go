@@ -133,6 +133,17 @@ func (n *Node) WriteFile(ctx context.Context, dest, content string) error {
 	return command.Run(ctx)
 }
 
+// ReadFile returns the content of a file inside a running container.
+func (n *Node) ReadFile(ctx context.Context, path string) (string, error) {
+	var out bytes.Buffer
+	command := n.Commander.Command("cat", path)
+	command.SetStdout(&out)
+	if err := command.Run(ctx); err != nil {
+		return "", errors.Wrapf(err, "failed to read file %s", path)
+	}
+	return out.String(), nil
+}
+
 // Kill sends the named signal to the container.
 func (n *Node) Kill(ctx context.Context, signal string) error {
 	containerRuntime, err := container.RuntimeFrom(ctx)
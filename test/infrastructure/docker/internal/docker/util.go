@@ -19,10 +19,12 @@ package docker
 import (
 	"context"
 	"fmt"
+	"net"
 	"strings"
 
 	"github.com/pkg/errors"
 
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/test/infrastructure/container"
 	"sigs.k8s.io/cluster-api/test/infrastructure/docker/internal/docker/types"
 )
@@ -44,6 +46,44 @@ func FailureDomainLabel(failureDomain *string) map[string]string {
 	return nil
 }
 
+// primaryIPFamily returns the primary IP family of a cluster, either IPv4 or IPv6.
+// For dual-stack clusters this is the IP family of the first service CIDR, or of the first pod CIDR
+// if there are no service CIDRs, which is the family Kubernetes uses for the API server and single-stack services.
+func primaryIPFamily(cluster *clusterv1.Cluster) (clusterv1.ClusterIPFamily, error) {
+	ipFamily, err := cluster.GetIPFamily()
+	if err != nil {
+		return clusterv1.InvalidIPFamily, err
+	}
+	if ipFamily != clusterv1.DualStackIPFamily {
+		return ipFamily, nil
+	}
+
+	// NOTE: ClusterNetwork is always set for dual-stack clusters.
+	var cidrs []string
+	if cluster.Spec.ClusterNetwork.Services != nil {
+		cidrs = cluster.Spec.ClusterNetwork.Services.CIDRBlocks
+	}
+	if len(cidrs) == 0 && cluster.Spec.ClusterNetwork.Pods != nil {
+		cidrs = cluster.Spec.ClusterNetwork.Pods.CIDRBlocks
+	}
+	ip, _, err := net.ParseCIDR(cidrs[0])
+	if err != nil {
+		return clusterv1.InvalidIPFamily, errors.Wrapf(err, "could not parse CIDR %q", cidrs[0])
+	}
+	if ip.To4() != nil {
+		return clusterv1.IPv4IPFamily, nil
+	}
+	return clusterv1.IPv6IPFamily, nil
+}
+
+// addressForIPFamily returns the address of the given IP family, either IPv4 or IPv6.
+func addressForIPFamily(ipFamily clusterv1.ClusterIPFamily, ipv4, ipv6 string) string {
+	if ipFamily == clusterv1.IPv6IPFamily {
+		return ipv6
+	}
+	return ipv4
+}
+
 func machineContainerName(cluster, machine string) string {
 	if strings.HasPrefix(machine, cluster) {
 		return machine
@@ -0,0 +1,146 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/test/infrastructure/container"
+	"sigs.k8s.io/cluster-api/test/infrastructure/docker/internal/docker/types"
+)
+
+func TestPrimaryIPFamily(t *testing.T) {
+	tests := []struct {
+		name     string
+		services []string
+		pods     []string
+		want     clusterv1.ClusterIPFamily
+		wantErr  bool
+	}{
+		{
+			name: "no cluster network defaults to IPv4",
+			want: clusterv1.IPv4IPFamily,
+		},
+		{
+			name:     "IPv6 only",
+			services: []string{"fd00:100:64::/108"},
+			pods:     []string{"fd00:100:96::/48"},
+			want:     clusterv1.IPv6IPFamily,
+		},
+		{
+			name:     "dual-stack with IPv4 primary",
+			services: []string{"10.128.0.0/12", "fd00:100:64::/108"},
+			pods:     []string{"192.168.0.0/16", "fd00:100:96::/48"},
+			want:     clusterv1.IPv4IPFamily,
+		},
+		{
+			name:     "dual-stack with IPv6 primary",
+			services: []string{"fd00:100:64::/108", "10.128.0.0/12"},
+			pods:     []string{"fd00:100:96::/48", "192.168.0.0/16"},
+			want:     clusterv1.IPv6IPFamily,
+		},
+		{
+			name: "dual-stack without services uses the pods CIDRs",
+			pods: []string{"fd00:100:96::/48", "192.168.0.0/16"},
+			want: clusterv1.IPv6IPFamily,
+		},
+		{
+			name:     "mismatching families between pods and services",
+			services: []string{"10.128.0.0/12"},
+			pods:     []string{"fd00:100:96::/48"},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := &clusterv1.Cluster{}
+			if tt.services != nil || tt.pods != nil {
+				cluster.Spec.ClusterNetwork = &clusterv1.ClusterNetwork{}
+				if tt.services != nil {
+					cluster.Spec.ClusterNetwork.Services = &clusterv1.NetworkRanges{CIDRBlocks: tt.services}
+				}
+				if tt.pods != nil {
+					cluster.Spec.ClusterNetwork.Pods = &clusterv1.NetworkRanges{CIDRBlocks: tt.pods}
+				}
+			}
+
+			got, err := primaryIPFamily(cluster)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestMachineAddress(t *testing.T) {
+	tests := []struct {
+		name            string
+		ipFamily        clusterv1.ClusterIPFamily
+		primaryIPFamily clusterv1.ClusterIPFamily
+		want            []string
+	}{
+		{
+			name:            "IPv4",
+			ipFamily:        clusterv1.IPv4IPFamily,
+			primaryIPFamily: clusterv1.IPv4IPFamily,
+			want:            []string{"nodeIPv4"},
+		},
+		{
+			name:            "IPv6",
+			ipFamily:        clusterv1.IPv6IPFamily,
+			primaryIPFamily: clusterv1.IPv6IPFamily,
+			want:            []string{"nodeIPv6"},
+		},
+		{
+			name:            "dual-stack with IPv4 primary",
+			ipFamily:        clusterv1.DualStackIPFamily,
+			primaryIPFamily: clusterv1.IPv4IPFamily,
+			want:            []string{"nodeIPv4", "nodeIPv6"},
+		},
+		{
+			name:            "dual-stack with IPv6 primary",
+			ipFamily:        clusterv1.DualStackIPFamily,
+			primaryIPFamily: clusterv1.IPv6IPFamily,
+			want:            []string{"nodeIPv6", "nodeIPv4"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := container.RuntimeInto(context.Background(), &container.FakeRuntime{})
+
+			m := &Machine{
+				ipFamily:        tt.ipFamily,
+				primaryIPFamily: tt.primaryIPFamily,
+				container:       types.NewNode("node", "image", "control-plane"),
+			}
+
+			got, err := m.Address(ctx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
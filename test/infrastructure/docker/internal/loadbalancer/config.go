@@ -18,6 +18,7 @@ package loadbalancer
 
 import (
 	"bytes"
+	"net"
 	"text/template"
 
 	"sigs.k8s.io/kind/pkg/errors"
@@ -26,11 +27,35 @@ import (
 // ConfigData is supplied to the loadbalancer config template.
 type ConfigData struct {
 	ControlPlanePort int
-	BackendServers   map[string]string
-	IPv6             bool
+	// BackendServers maps the name of each control plane node to its host:port address.
+	BackendServers map[string]string
+	// IPv6 is true if the primary IP family of the cluster is IPv6; backends are resolved preferring IPv6.
+	IPv6 bool
+	// DualStack is true if the cluster is dual-stack; the frontend is bound on both IPv4 and IPv6.
+	DualStack bool
+	// AdditionalFrontends are additional ports the load balancer listens on.
+	AdditionalFrontends []FrontendConfigData
+	// HealthCheck defines the health checks of the backend servers.
+	HealthCheck HealthCheckConfigData
 }
 
-// ConfigTemplate is the loadbalancer config template.
+// FrontendConfigData defines an additional port of the load balancer.
+type FrontendConfigData struct {
+	Name string
+	Port int
+	// BackendServers maps the name of each node the traffic is forwarded to to its host:port address.
+	BackendServers map[string]string
+}
+
+// HealthCheckConfigData defines the health checks of the backend servers.
+// Zero values are replaced by the defaults when generating the config.
+type HealthCheckConfigData struct {
+	IntervalSeconds    int
+	HealthyThreshold   int
+	UnhealthyThreshold int
+}
+
+// ConfigTemplate is the haproxy loadbalancer config template.
 const ConfigTemplate = `# generated by kind
 global
   log /dev/log local0
@@ -56,22 +81,184 @@ defaults
 
 frontend control-plane
   bind *:{{ .ControlPlanePort }}
-  {{ if .IPv6 -}}
+  {{ if or .IPv6 .DualStack -}}
   bind :::{{ .ControlPlanePort }};
   {{- end }}
   default_backend kube-apiservers
 
 backend kube-apiservers
   option httpchk GET /healthz
+  default-server inter {{ .HealthCheck.IntervalSeconds }}s rise {{ .HealthCheck.HealthyThreshold }} fall {{ .HealthCheck.UnhealthyThreshold }}
   # TODO: we should be verifying (!)
   {{range $server, $address := .BackendServers}}
   server {{ $server }} {{ $address }} check check-ssl verify none resolvers docker resolve-prefer {{ if $.IPv6 -}} ipv6 {{- else -}} ipv4 {{- end }}
   {{- end}}
+{{- range .AdditionalFrontends }}
+
+frontend {{ .Name }}
+  bind *:{{ .Port }}
+  {{ if or $.IPv6 $.DualStack -}}
+  bind :::{{ .Port }};
+  {{- end }}
+  default_backend {{ .Name }}
+
+backend {{ .Name }}
+  default-server inter {{ $.HealthCheck.IntervalSeconds }}s rise {{ $.HealthCheck.HealthyThreshold }} fall {{ $.HealthCheck.UnhealthyThreshold }}
+  {{- range $server, $address := .BackendServers }}
+  server {{ $server }} {{ $address }} check resolvers docker resolve-prefer {{ if $.IPv6 -}} ipv6 {{- else -}} ipv4 {{- end }}
+  {{- end }}
+{{- end }}
+`
+
+// NginxConfigTemplate is the nginx loadbalancer config template.
+// nginx rejects upstream blocks without servers, so a placeholder server marked as down is used when there are no backends.
+const NginxConfigTemplate = `# generated by CAPD
+worker_processes auto;
+
+events {
+  worker_connections 1024;
+}
+
+stream {
+  upstream kube-apiservers {
+    {{- range $server, $address := .BackendServers }}
+    # {{ $server }}
+    server {{ $address }} max_fails={{ $.HealthCheck.UnhealthyThreshold }} fail_timeout=10s;
+    {{- else }}
+    server 127.0.0.1:{{ .ControlPlanePort }} down;
+    {{- end }}
+  }
+
+  server {
+    listen {{ .ControlPlanePort }};
+    {{- if or .IPv6 .DualStack }}
+    listen [::]:{{ .ControlPlanePort }};
+    {{- end }}
+    proxy_connect_timeout 5s;
+    proxy_pass kube-apiservers;
+  }
+  {{- range .AdditionalFrontends }}
+
+  upstream {{ .Name }} {
+    {{- range $server, $address := .BackendServers }}
+    # {{ $server }}
+    server {{ $address }} max_fails={{ $.HealthCheck.UnhealthyThreshold }} fail_timeout=10s;
+    {{- else }}
+    server 127.0.0.1:{{ .Port }} down;
+    {{- end }}
+  }
+
+  server {
+    listen {{ .Port }};
+    {{- if or $.IPv6 $.DualStack }}
+    listen [::]:{{ .Port }};
+    {{- end }}
+    proxy_connect_timeout 5s;
+    proxy_pass {{ .Name }};
+  }
+  {{- end }}
+}
+`
+
+// EnvoyConfigTemplate is the envoy loadbalancer config template.
+const EnvoyConfigTemplate = `# generated by CAPD
+static_resources:
+  listeners:
+  - name: control-plane
+    address:
+      socket_address:
+        address: {{ if or .IPv6 .DualStack }}"::"{{ else }}0.0.0.0{{ end }}
+        port_value: {{ .ControlPlanePort }}
+        {{- if .DualStack }}
+        ipv4_compat: true
+        {{- end }}
+    filter_chains:
+    - filters:
+      - name: envoy.filters.network.tcp_proxy
+        typed_config:
+          "@type": type.googleapis.com/envoy.extensions.filters.network.tcp_proxy.v3.TcpProxy
+          stat_prefix: kube-apiservers
+          cluster: kube-apiservers
+  {{- range .AdditionalFrontends }}
+  - name: {{ .Name }}
+    address:
+      socket_address:
+        address: {{ if or $.IPv6 $.DualStack }}"::"{{ else }}0.0.0.0{{ end }}
+        port_value: {{ .Port }}
+        {{- if $.DualStack }}
+        ipv4_compat: true
+        {{- end }}
+    filter_chains:
+    - filters:
+      - name: envoy.filters.network.tcp_proxy
+        typed_config:
+          "@type": type.googleapis.com/envoy.extensions.filters.network.tcp_proxy.v3.TcpProxy
+          stat_prefix: {{ .Name }}
+          cluster: {{ .Name }}
+  {{- end }}
+  clusters:
+  - name: kube-apiservers
+    connect_timeout: 5s
+    type: STATIC
+    health_checks:
+    - timeout: 1s
+      interval: {{ .HealthCheck.IntervalSeconds }}s
+      healthy_threshold: {{ .HealthCheck.HealthyThreshold }}
+      unhealthy_threshold: {{ .HealthCheck.UnhealthyThreshold }}
+      tcp_health_check: {}
+    load_assignment:
+      cluster_name: kube-apiservers
+      endpoints:
+      - lb_endpoints:{{ if not .BackendServers }} []{{ end }}
+        {{- range $server, $address := .BackendServers }}
+        # {{ $server }}
+        - endpoint:
+            address:
+              socket_address:
+                address: {{ host $address | printf "%q" }}
+                port_value: {{ port $address }}
+        {{- end }}
+  {{- range .AdditionalFrontends }}
+  - name: {{ .Name }}
+    connect_timeout: 5s
+    type: STATIC
+    health_checks:
+    - timeout: 1s
+      interval: {{ $.HealthCheck.IntervalSeconds }}s
+      healthy_threshold: {{ $.HealthCheck.HealthyThreshold }}
+      unhealthy_threshold: {{ $.HealthCheck.UnhealthyThreshold }}
+      tcp_health_check: {}
+    load_assignment:
+      cluster_name: {{ .Name }}
+      endpoints:
+      - lb_endpoints:{{ if not .BackendServers }} []{{ end }}
+        {{- range $server, $address := .BackendServers }}
+        # {{ $server }}
+        - endpoint:
+            address:
+              socket_address:
+                address: {{ host $address | printf "%q" }}
+                port_value: {{ port $address }}
+        {{- end }}
+  {{- end }}
 `
 
-// Config generates the loadbalancer config from the ConfigTemplate and ConfigData.
-func Config(data *ConfigData) (config string, err error) {
-	t, err := template.New("loadbalancer-config").Parse(ConfigTemplate)
+// Config generates the loadbalancer config from a config template and ConfigData.
+// Besides the standard text/template functions, templates can use "host" and "port" to split
+// the host:port addresses of the backend servers.
+func Config(data *ConfigData, configTemplate string) (config string, err error) {
+	data = data.withDefaults()
+
+	t, err := template.New("loadbalancer-config").Funcs(template.FuncMap{
+		"host": func(address string) (string, error) {
+			host, _, err := net.SplitHostPort(address)
+			return host, err
+		},
+		"port": func(address string) (string, error) {
+			_, port, err := net.SplitHostPort(address)
+			return port, err
+		},
+	}).Parse(configTemplate)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to parse config template")
 	}
@@ -83,3 +270,18 @@ func Config(data *ConfigData) (config string, err error) {
 	}
 	return buff.String(), nil
 }
+
+// withDefaults returns a copy of the ConfigData with the defaults for the health checks set.
+func (d *ConfigData) withDefaults() *ConfigData {
+	data := *d
+	if data.HealthCheck.IntervalSeconds == 0 {
+		data.HealthCheck.IntervalSeconds = 2
+	}
+	if data.HealthCheck.HealthyThreshold == 0 {
+		data.HealthCheck.HealthyThreshold = 2
+	}
+	if data.HealthCheck.UnhealthyThreshold == 0 {
+		data.HealthCheck.UnhealthyThreshold = 3
+	}
+	return &data
+}
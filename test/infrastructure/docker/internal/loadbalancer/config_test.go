@@ -0,0 +1,208 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestConfig(t *testing.T) {
+	tests := []struct {
+		name           string
+		template       string
+		data           *ConfigData
+		wantContain    []string
+		wantNotContain []string
+	}{
+		{
+			name:     "IPv4",
+			template: ConfigTemplate,
+			data: &ConfigData{
+				ControlPlanePort: 6443,
+				BackendServers:   map[string]string{"cp-0": "172.18.0.2:6443"},
+			},
+			wantContain: []string{
+				"bind *:6443",
+				"server cp-0 172.18.0.2:6443 check check-ssl verify none resolvers docker resolve-prefer ipv4",
+			},
+			wantNotContain: []string{"bind :::6443"},
+		},
+		{
+			name:     "IPv6",
+			template: ConfigTemplate,
+			data: &ConfigData{
+				ControlPlanePort: 6443,
+				BackendServers:   map[string]string{"cp-0": "[fd00::2]:6443"},
+				IPv6:             true,
+			},
+			wantContain: []string{
+				"bind :::6443",
+				"server cp-0 [fd00::2]:6443 check check-ssl verify none resolvers docker resolve-prefer ipv6",
+			},
+		},
+		{
+			name:     "dual-stack with IPv4 primary",
+			template: ConfigTemplate,
+			data: &ConfigData{
+				ControlPlanePort: 6443,
+				BackendServers:   map[string]string{"cp-0": "172.18.0.2:6443"},
+				DualStack:        true,
+			},
+			wantContain: []string{
+				"bind *:6443",
+				"bind :::6443",
+				"resolve-prefer ipv4",
+			},
+		},
+		{
+			name:     "additional frontends and health checks",
+			template: ConfigTemplate,
+			data: &ConfigData{
+				ControlPlanePort: 6443,
+				BackendServers:   map[string]string{"cp-0": "172.18.0.2:6443"},
+				AdditionalFrontends: []FrontendConfigData{
+					{Name: "http", Port: 80, BackendServers: map[string]string{"cp-0": "172.18.0.2:30080", "md-0": "172.18.0.3:30080"}},
+				},
+				HealthCheck: HealthCheckConfigData{IntervalSeconds: 5, UnhealthyThreshold: 2},
+			},
+			wantContain: []string{
+				"default-server inter 5s rise 2 fall 2",
+				"frontend http\n  bind *:80\n",
+				"default_backend http",
+				"server cp-0 172.18.0.2:30080 check resolvers docker resolve-prefer ipv4",
+				"server md-0 172.18.0.3:30080 check resolvers docker resolve-prefer ipv4",
+			},
+		},
+		{
+			name:     "default health checks",
+			template: ConfigTemplate,
+			data: &ConfigData{
+				ControlPlanePort: 6443,
+				BackendServers:   map[string]string{"cp-0": "172.18.0.2:6443"},
+			},
+			wantContain:    []string{"default-server inter 2s rise 2 fall 3"},
+			wantNotContain: []string{"frontend http"},
+		},
+		{
+			name:     "nginx",
+			template: NginxConfigTemplate,
+			data: &ConfigData{
+				ControlPlanePort: 6443,
+				BackendServers:   map[string]string{"cp-0": "172.18.0.2:6443", "cp-1": "172.18.0.3:6443"},
+			},
+			wantContain: []string{
+				"listen 6443;",
+				"server 172.18.0.2:6443 max_fails=3 fail_timeout=10s;",
+				"server 172.18.0.3:6443 max_fails=3 fail_timeout=10s;",
+			},
+			wantNotContain: []string{"listen [::]:6443;"},
+		},
+		{
+			name:     "envoy dual-stack",
+			template: EnvoyConfigTemplate,
+			data: &ConfigData{
+				ControlPlanePort: 6443,
+				BackendServers:   map[string]string{"cp-0": "[fd00::2]:6443"},
+				IPv6:             true,
+				DualStack:        true,
+			},
+			wantContain: []string{
+				`address: "::"`,
+				"ipv4_compat: true",
+				`address: "fd00::2"`,
+				"port_value: 6443",
+			},
+		},
+		{
+			name:     "nginx additional frontends",
+			template: NginxConfigTemplate,
+			data: &ConfigData{
+				ControlPlanePort: 6443,
+				BackendServers:   map[string]string{"cp-0": "172.18.0.2:6443"},
+				AdditionalFrontends: []FrontendConfigData{
+					{Name: "http", Port: 80, BackendServers: map[string]string{"md-0": "172.18.0.3:30080"}},
+				},
+				HealthCheck: HealthCheckConfigData{UnhealthyThreshold: 5},
+			},
+			wantContain: []string{
+				"upstream http {",
+				"server 172.18.0.3:30080 max_fails=5 fail_timeout=10s;",
+				"listen 80;",
+				"proxy_pass http;",
+			},
+		},
+		{
+			name:     "envoy additional frontends",
+			template: EnvoyConfigTemplate,
+			data: &ConfigData{
+				ControlPlanePort: 6443,
+				BackendServers:   map[string]string{"cp-0": "172.18.0.2:6443"},
+				AdditionalFrontends: []FrontendConfigData{
+					{Name: "http", Port: 80, BackendServers: map[string]string{"md-0": "172.18.0.3:30080"}},
+				},
+				HealthCheck: HealthCheckConfigData{IntervalSeconds: 10},
+			},
+			wantContain: []string{
+				"- name: http\n    address:",
+				"port_value: 80",
+				"cluster: http",
+				"cluster_name: http",
+				"port_value: 30080",
+				"interval: 10s",
+			},
+		},
+		{
+			name:     "nginx without backends",
+			template: NginxConfigTemplate,
+			data: &ConfigData{
+				ControlPlanePort: 6443,
+				AdditionalFrontends: []FrontendConfigData{
+					{Name: "http", Port: 80},
+				},
+			},
+			wantContain: []string{
+				"upstream kube-apiservers {\n    server 127.0.0.1:6443 down;\n  }",
+				"upstream http {\n    server 127.0.0.1:80 down;\n  }",
+			},
+			wantNotContain: []string{"max_fails"},
+		},
+		{
+			name:     "envoy without backends",
+			template: EnvoyConfigTemplate,
+			data: &ConfigData{
+				ControlPlanePort: 6443,
+			},
+			wantContain: []string{"lb_endpoints: []"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			config, err := Config(tt.data, tt.template)
+			g.Expect(err).ToNot(HaveOccurred())
+			for _, s := range tt.wantContain {
+				g.Expect(config).To(ContainSubstring(s))
+			}
+			for _, s := range tt.wantNotContain {
+				g.Expect(config).ToNot(ContainSubstring(s))
+			}
+		})
+	}
+}
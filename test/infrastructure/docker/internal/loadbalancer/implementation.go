@@ -0,0 +1,97 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1beta1"
+)
+
+// Implementation describes how to run and configure a load balancer implementation.
+type Implementation struct {
+	// Image is the load balancer image name.
+	Image string
+
+	// DefaultImageRepository is the load balancer image repository used if not overridden.
+	DefaultImageRepository string
+
+	// DefaultImageTag is the load balancer image tag used if not overridden.
+	DefaultImageTag string
+
+	// Entrypoint is the entrypoint used to start the load balancer container.
+	// NOTE: the load balancer must reload its configuration when the container receives a SIGHUP.
+	Entrypoint []string
+
+	// ConfigPath is the path to the config file in the container.
+	ConfigPath string
+
+	// ConfigTemplate is the default config template.
+	ConfigTemplate string
+}
+
+const (
+	nginxConfigPath = "/etc/nginx/nginx.conf"
+	envoyConfigPath = "/etc/envoy/envoy.yaml"
+)
+
+// envoyEntrypoint wraps envoy in a shell restarting it on SIGHUP, because envoy does not support reloading
+// a static configuration; restarts take about a second, which is acceptable for a test load balancer.
+var envoyEntrypoint = []string{
+	"/bin/sh", "-c",
+	"trap 'kill $pid; wait $pid' HUP; trap 'kill $pid; exit 0' TERM; " +
+		"while true; do envoy -c " + envoyConfigPath + " & pid=$!; wait $pid; sleep 1; done",
+}
+
+var implementations = map[infrav1.DockerLoadBalancerType]Implementation{
+	infrav1.HAProxyLoadBalancerType: {
+		Image:                  Image,
+		DefaultImageRepository: DefaultImageRepository,
+		DefaultImageTag:        DefaultImageTag,
+		Entrypoint:             []string{"haproxy", "-W", "-db", "-f", ConfigPath},
+		ConfigPath:             ConfigPath,
+		ConfigTemplate:         ConfigTemplate,
+	},
+	infrav1.NginxLoadBalancerType: {
+		Image:                  "nginx",
+		DefaultImageRepository: "library",
+		DefaultImageTag:        "1.25.3-alpine",
+		Entrypoint:             []string{"nginx", "-g", "daemon off;"},
+		ConfigPath:             nginxConfigPath,
+		ConfigTemplate:         NginxConfigTemplate,
+	},
+	infrav1.EnvoyLoadBalancerType: {
+		Image:                  "envoy",
+		DefaultImageRepository: "envoyproxy",
+		DefaultImageTag:        "v1.28.0",
+		Entrypoint:             envoyEntrypoint,
+		ConfigPath:             envoyConfigPath,
+		ConfigTemplate:         EnvoyConfigTemplate,
+	},
+}
+
+// ImplementationFor returns the Implementation for a load balancer type; an empty type means haproxy.
+func ImplementationFor(lbType infrav1.DockerLoadBalancerType) (Implementation, error) {
+	if lbType == "" {
+		lbType = infrav1.HAProxyLoadBalancerType
+	}
+	implementation, ok := implementations[lbType]
+	if !ok {
+		return Implementation{}, errors.Errorf("unknown load balancer type %q", lbType)
+	}
+	return implementation, nil
+}
@@ -84,6 +84,9 @@ var (
 	webhookPort                 int
 	webhookCertDir              string
 	healthAddr                  string
+	containerRuntime            string
+	containerRuntimeHost        string
+	containerRuntimeTLSCertDir  string
 	tlsOptions                  = flags.TLSOptions{}
 	logOptions                  = logs.NewOptions()
 )
@@ -153,6 +156,16 @@ func initFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&healthAddr, "health-addr", ":9440",
 		"The address the health endpoint binds to.")
 
+	fs.StringVar(&containerRuntime, "container-runtime", os.Getenv(container.RuntimeEnvVar),
+		fmt.Sprintf("The container runtime used to run machines, one of %q or %q. Defaults to the value of the %s environment variable, or %q if not set.",
+			container.DockerRuntimeName, container.PodmanRuntimeName, container.RuntimeEnvVar, container.DockerRuntimeName))
+
+	fs.StringVar(&containerRuntimeHost, "container-runtime-host", "",
+		"The address of the container runtime daemon, e.g. tcp://docker.example.com:2376. If not set, the address is read from the environment (DOCKER_HOST for docker, CONTAINER_HOST for podman).")
+
+	fs.StringVar(&containerRuntimeTLSCertDir, "container-runtime-tls-cert-dir", "",
+		"The directory containing the ca.crt, tls.crt and tls.key files used to connect to the container runtime daemon using TLS, e.g. a kubernetes.io/tls Secret mounted as a volume.")
+
 	flags.AddTLSOptions(fs, &tlsOptions)
 
 	feature.MutableGates.AddFlag(fs)
@@ -286,7 +299,10 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 	}
 
 	// Set our runtime client into the context for later use
-	runtimeClient, err := container.NewDockerClient()
+	runtimeClient, err := container.NewRuntimeClientFor(containerRuntime, container.ClientOptions{
+		Host:       containerRuntimeHost,
+		TLSCertDir: containerRuntimeTLSCertDir,
+	})
 	if err != nil {
 		setupLog.Error(err, "unable to establish container runtime connection", "controller", "reconciler")
 		os.Exit(1)
@@ -28,6 +28,28 @@ const (
 	MachineFinalizer = "inmemorymachine.infrastructure.cluster.x-k8s.io"
 )
 
+// Defines annotations that can be applied to an InMemoryMachine in order to inject faults in the components
+// hosted on it, e.g. for testing KCP remediation or MachineHealthCheck without real infrastructure.
+// The annotation value can be empty, and in this case the fault is injected immediately, or a time in RFC3339 format,
+// and in this case the fault is injected at the given time.
+// Removing the annotation recovers from the fault, if possible.
+const (
+	// NodeNotReadyFaultAnnotation makes the Node hosted on the InMemoryMachine NotReady.
+	NodeNotReadyFaultAnnotation = "fault.inmemorymachine.infrastructure.cluster.x-k8s.io/node-not-ready"
+
+	// EtcdUnavailableFaultAnnotation makes the etcd member hosted on the InMemoryMachine fail all the requests,
+	// like an etcd member which is lagging behind or not responding.
+	EtcdUnavailableFaultAnnotation = "fault.inmemorymachine.infrastructure.cluster.x-k8s.io/etcd-unavailable"
+
+	// EtcdMemberLeftFaultAnnotation makes the etcd member hosted on the InMemoryMachine leave the etcd cluster.
+	// NOTE: It is not possible to recover from this fault.
+	EtcdMemberLeftFaultAnnotation = "fault.inmemorymachine.infrastructure.cluster.x-k8s.io/etcd-member-left"
+
+	// APIServerUnavailableFaultAnnotation makes the API server hosted on the InMemoryMachine respond with
+	// 500 Internal Server Error to the requests it serves.
+	APIServerUnavailableFaultAnnotation = "fault.inmemorymachine.infrastructure.cluster.x-k8s.io/apiserver-unavailable"
+)
+
 const (
 	// VMProvisionedCondition documents the status of the provisioning VM implementing the InMemoryMachine.
 	VMProvisionedCondition clusterv1.ConditionType = "VMProvisioned"
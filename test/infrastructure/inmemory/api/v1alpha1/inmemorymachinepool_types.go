@@ -0,0 +1,122 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const (
+	// MachinePoolFinalizer allows ReconcileInMemoryMachinePool to clean up resources associated with InMemoryMachinePool before
+	// removing it from the API server.
+	MachinePoolFinalizer = "inmemorymachinepool.infrastructure.cluster.x-k8s.io"
+
+	// MachinePoolNameLabel is the label applied to the cloud machines and nodes belonging to an InMemoryMachinePool.
+	MachinePoolNameLabel = "inmemorymachinepool.infrastructure.cluster.x-k8s.io/name"
+)
+
+const (
+	// ReplicasReadyCondition documents the status of the replicas of the InMemoryMachinePool.
+	ReplicasReadyCondition clusterv1.ConditionType = "ReplicasReady"
+
+	// WaitingForReplicasReason (Severity=Info) documents an InMemoryMachinePool waiting for replicas
+	// to be provisioned.
+	WaitingForReplicasReason = "WaitingForReplicas"
+)
+
+// InMemoryMachinePoolSpec defines the desired state of InMemoryMachinePool.
+type InMemoryMachinePoolSpec struct {
+	// ProviderID is the identification ID of the Machine Pool
+	// +optional
+	ProviderID string `json:"providerID,omitempty"`
+
+	// ProviderIDList is the list of identification IDs of machine instances managed by this Machine Pool
+	// +optional
+	ProviderIDList []string `json:"providerIDList,omitempty"`
+
+	// Behaviour of the machines in the InMemoryMachinePool; this will allow to make a simulation more alike to real use cases
+	// e.g. by defining the duration of the provisioning phase mimicking the performances of the target infrastructure.
+	// +optional
+	Behaviour *InMemoryMachinePoolBehaviour `json:"behaviour,omitempty"`
+}
+
+// InMemoryMachinePoolBehaviour defines the behaviour of the machines in the InMemoryMachinePool.
+type InMemoryMachinePoolBehaviour struct {
+	// VM defines the behaviour of the VMs implementing the machines in the InMemoryMachinePool.
+	VM *InMemoryVMBehaviour `json:"vm,omitempty"`
+
+	// Node defines the behaviour of the Nodes (the kubelet) hosted on the machines in the InMemoryMachinePool.
+	Node *InMemoryNodeBehaviour `json:"node,omitempty"`
+}
+
+// InMemoryMachinePoolStatus defines the observed state of InMemoryMachinePool.
+type InMemoryMachinePoolStatus struct {
+	// Ready denotes that the machine pool is ready
+	// +optional
+	Ready bool `json:"ready"`
+
+	// Replicas is the most recently observed number of replicas.
+	// +optional
+	Replicas int32 `json:"replicas"`
+
+	// Conditions defines current service state of the InMemoryMachinePool.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:resource:path=inmemorymachinepools,scope=Namespaced,categories=cluster-api
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".metadata.labels['cluster\\.x-k8s\\.io/cluster-name']",description="Cluster"
+// +kubebuilder:printcolumn:name="Replicas",type="string",JSONPath=".status.replicas",description="Machine pool replicas count"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.ready",description="Machine pool ready status"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of InMemoryMachinePool"
+
+// InMemoryMachinePool is the schema for the in-memory machine pool API.
+type InMemoryMachinePool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   InMemoryMachinePoolSpec   `json:"spec,omitempty"`
+	Status InMemoryMachinePoolStatus `json:"status,omitempty"`
+}
+
+// GetConditions returns the set of conditions for this object.
+func (c *InMemoryMachinePool) GetConditions() clusterv1.Conditions {
+	return c.Status.Conditions
+}
+
+// SetConditions sets the conditions on this object.
+func (c *InMemoryMachinePool) SetConditions(conditions clusterv1.Conditions) {
+	c.Status.Conditions = conditions
+}
+
+// +kubebuilder:object:root=true
+
+// InMemoryMachinePoolList contains a list of InMemoryMachinePool.
+type InMemoryMachinePoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []InMemoryMachinePool `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&InMemoryMachinePool{}, &InMemoryMachinePoolList{})
+}
@@ -371,6 +371,137 @@ func (in *InMemoryMachineList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InMemoryMachinePool) DeepCopyInto(out *InMemoryMachinePool) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InMemoryMachinePool.
+func (in *InMemoryMachinePool) DeepCopy() *InMemoryMachinePool {
+	if in == nil {
+		return nil
+	}
+	out := new(InMemoryMachinePool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *InMemoryMachinePool) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InMemoryMachinePoolBehaviour) DeepCopyInto(out *InMemoryMachinePoolBehaviour) {
+	*out = *in
+	if in.VM != nil {
+		in, out := &in.VM, &out.VM
+		*out = new(InMemoryVMBehaviour)
+		**out = **in
+	}
+	if in.Node != nil {
+		in, out := &in.Node, &out.Node
+		*out = new(InMemoryNodeBehaviour)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InMemoryMachinePoolBehaviour.
+func (in *InMemoryMachinePoolBehaviour) DeepCopy() *InMemoryMachinePoolBehaviour {
+	if in == nil {
+		return nil
+	}
+	out := new(InMemoryMachinePoolBehaviour)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InMemoryMachinePoolList) DeepCopyInto(out *InMemoryMachinePoolList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]InMemoryMachinePool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InMemoryMachinePoolList.
+func (in *InMemoryMachinePoolList) DeepCopy() *InMemoryMachinePoolList {
+	if in == nil {
+		return nil
+	}
+	out := new(InMemoryMachinePoolList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *InMemoryMachinePoolList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InMemoryMachinePoolSpec) DeepCopyInto(out *InMemoryMachinePoolSpec) {
+	*out = *in
+	if in.ProviderIDList != nil {
+		in, out := &in.ProviderIDList, &out.ProviderIDList
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Behaviour != nil {
+		in, out := &in.Behaviour, &out.Behaviour
+		*out = new(InMemoryMachinePoolBehaviour)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InMemoryMachinePoolSpec.
+func (in *InMemoryMachinePoolSpec) DeepCopy() *InMemoryMachinePoolSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InMemoryMachinePoolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InMemoryMachinePoolStatus) DeepCopyInto(out *InMemoryMachinePoolStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(v1beta1.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InMemoryMachinePoolStatus.
+func (in *InMemoryMachinePoolStatus) DeepCopy() *InMemoryMachinePoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(InMemoryMachinePoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InMemoryMachineSpec) DeepCopyInto(out *InMemoryMachineSpec) {
 	*out = *in
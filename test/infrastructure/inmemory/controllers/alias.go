@@ -29,6 +29,12 @@ import (
 	"sigs.k8s.io/cluster-api/test/infrastructure/inmemory/internal/server"
 )
 
+// LatencyProfile defines the latency of the operations simulated by the in-memory provider.
+type LatencyProfile = inmemorycontrollers.LatencyProfile
+
+// Latency defines the distribution of the duration of an operation.
+type Latency = inmemorycontrollers.Latency
+
 // Following types provides access to reconcilers implemented in internal/controllers, thus
 // allowing users to provide a single binary "batteries included" with Cluster API and providers of choice.
 
@@ -58,6 +64,9 @@ type InMemoryMachineReconciler struct {
 	CloudManager cloud.Manager
 	APIServerMux *server.WorkloadClustersMux // TODO: find a way to use an interface here
 
+	// LatencyProfile defines the latency of the simulated operations.
+	LatencyProfile LatencyProfile
+
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
 }
@@ -68,6 +77,29 @@ func (r *InMemoryMachineReconciler) SetupWithManager(ctx context.Context, mgr ct
 		Client:           r.Client,
 		CloudManager:     r.CloudManager,
 		APIServerMux:     r.APIServerMux,
+		LatencyProfile:   r.LatencyProfile,
+		WatchFilterValue: r.WatchFilterValue,
+	}).SetupWithManager(ctx, mgr, options)
+}
+
+// InMemoryMachinePoolReconciler reconciles a InMemoryMachinePool object.
+type InMemoryMachinePoolReconciler struct {
+	Client       client.Client
+	CloudManager cloud.Manager
+
+	// LatencyProfile defines the latency of the simulated operations.
+	LatencyProfile LatencyProfile
+
+	// WatchFilterValue is the label value used to filter events prior to reconciliation.
+	WatchFilterValue string
+}
+
+// SetupWithManager sets up the reconciler with the Manager.
+func (r *InMemoryMachinePoolReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	return (&inmemorycontrollers.InMemoryMachinePoolReconciler{
+		Client:           r.Client,
+		CloudManager:     r.CloudManager,
+		LatencyProfile:   r.LatencyProfile,
 		WatchFilterValue: r.WatchFilterValue,
 	}).SetupWithManager(ctx, mgr, options)
 }
@@ -38,3 +38,13 @@ const (
 	// EtcdMemberRemoved is added to etcd pods which have been removed from the etcd cluster.
 	EtcdMemberRemoved = "etcd.inmemory.infrastructure.cluster.x-k8s.io/member-removed"
 )
+
+// defines annotations to be applied to in memory etcd and API server pods in order to inject faults.
+const (
+	// EtcdUnavailableAnnotationName is added to etcd pods whose etcd member should fail all the requests.
+	EtcdUnavailableAnnotationName = "etcd.inmemory.infrastructure.cluster.x-k8s.io/unavailable"
+
+	// APIServerUnavailableAnnotationName is added to API server pods which should respond with 500 Internal Server Error
+	// to the requests they serve.
+	APIServerUnavailableAnnotationName = "apiserver.inmemory.infrastructure.cluster.x-k8s.io/unavailable"
+)
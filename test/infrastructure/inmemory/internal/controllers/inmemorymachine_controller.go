@@ -22,7 +22,6 @@ import (
 	"crypto/rsa"
 	"fmt"
 	"math/rand"
-	"strconv"
 	"time"
 
 	"github.com/pkg/errors"
@@ -64,6 +63,9 @@ type InMemoryMachineReconciler struct {
 	CloudManager cloud.Manager
 	APIServerMux *server.WorkloadClustersMux
 
+	// LatencyProfile defines the latency of the simulated operations.
+	LatencyProfile LatencyProfile
+
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
 }
@@ -221,6 +223,7 @@ func (r *InMemoryMachineReconciler) reconcileNormal(ctx context.Context, cluster
 		r.reconcileNormalKubeadmObjects,
 		r.reconcileNormalKubeProxy,
 		r.reconcileNormalCoredns,
+		r.reconcileNormalFaults,
 	}
 
 	res := ctrl.Result{}
@@ -265,20 +268,13 @@ func (r *InMemoryMachineReconciler) reconcileNormalCloudMachine(ctx context.Cont
 	}
 
 	// Wait for the VM to be provisioned; provisioned happens a configurable time after the cloud machine creation.
-	provisioningDuration := time.Duration(0)
+	var settings *infrav1.CommonProvisioningSettings
 	if inMemoryMachine.Spec.Behaviour != nil && inMemoryMachine.Spec.Behaviour.VM != nil {
-		x := inMemoryMachine.Spec.Behaviour.VM.Provisioning
-
-		provisioningDuration = x.StartupDuration.Duration
-		if x.StartupJitter != "" {
-			jitter, err := strconv.ParseFloat(x.StartupJitter, 64)
-			if err != nil {
-				return ctrl.Result{}, errors.Wrapf(err, "failed to parse VM's StartupJitter")
-			}
-			if jitter > 0.0 {
-				provisioningDuration += time.Duration(rand.Float64() * jitter * float64(provisioningDuration)) //nolint:gosec // Intentionally using a weak random number generator here.
-			}
-		}
+		settings = &inMemoryMachine.Spec.Behaviour.VM.Provisioning
+	}
+	provisioningDuration, err := calculateProvisioningDuration(settings, r.LatencyProfile.VMProvisioning)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to parse VM's StartupJitter")
 	}
 
 	start := cloudMachine.CreationTimestamp
@@ -303,20 +299,13 @@ func (r *InMemoryMachineReconciler) reconcileNormalNode(ctx context.Context, clu
 	}
 
 	// Wait for the node/kubelet to start up; node/kubelet start happens a configurable time after the VM is provisioned.
-	provisioningDuration := time.Duration(0)
+	var settings *infrav1.CommonProvisioningSettings
 	if inMemoryMachine.Spec.Behaviour != nil && inMemoryMachine.Spec.Behaviour.Node != nil {
-		x := inMemoryMachine.Spec.Behaviour.Node.Provisioning
-
-		provisioningDuration = x.StartupDuration.Duration
-		if x.StartupJitter != "" {
-			jitter, err := strconv.ParseFloat(x.StartupJitter, 64)
-			if err != nil {
-				return ctrl.Result{}, errors.Wrapf(err, "failed to parse node's StartupJitter")
-			}
-			if jitter > 0.0 {
-				provisioningDuration += time.Duration(rand.Float64() * jitter * float64(provisioningDuration)) //nolint:gosec // Intentionally using a weak random number generator here.
-			}
-		}
+		settings = &inMemoryMachine.Spec.Behaviour.Node.Provisioning
+	}
+	provisioningDuration, err := calculateProvisioningDuration(settings, r.LatencyProfile.NodeProvisioning)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to parse node's StartupJitter")
 	}
 
 	start := conditions.Get(inMemoryMachine, infrav1.VMProvisionedCondition).LastTransitionTime
@@ -331,8 +320,12 @@ func (r *InMemoryMachineReconciler) reconcileNormalNode(ctx context.Context, clu
 	resourceGroup := klog.KObj(cluster).String()
 	cloudClient := r.CloudManager.GetResourceGroup(resourceGroup).GetClient()
 
-	// Create Node
-	// TODO: consider if to handle an additional setting adding a delay in between create node and node ready/provider ID being set
+	// Create Node; the Node becomes ready a configurable time after it is created.
+	readyDuration := r.LatencyProfile.NodeReady.Sample()
+	readyStatus := corev1.ConditionTrue
+	if readyDuration > 0 {
+		readyStatus = corev1.ConditionFalse
+	}
 	node := &corev1.Node{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: inMemoryMachine.Name,
@@ -344,7 +337,7 @@ func (r *InMemoryMachineReconciler) reconcileNormalNode(ctx context.Context, clu
 			Conditions: []corev1.NodeCondition{
 				{
 					Type:   corev1.NodeReady,
-					Status: corev1.ConditionTrue,
+					Status: readyStatus,
 				},
 			},
 		},
@@ -368,6 +361,19 @@ func (r *InMemoryMachineReconciler) reconcileNormalNode(ctx context.Context, clu
 		}
 	}
 
+	// Wait for the Node to become ready.
+	// NOTE: Once the Node is provisioned, readiness is managed by fault injection.
+	if !conditions.IsTrue(inMemoryMachine, infrav1.NodeProvisionedCondition) && readyDuration > 0 {
+		start := node.CreationTimestamp
+		if now.Before(start.Add(readyDuration)) {
+			conditions.MarkFalse(inMemoryMachine, infrav1.NodeProvisionedCondition, infrav1.NodeWaitingForStartupTimeoutReason, clusterv1.ConditionSeverityInfo, "")
+			return ctrl.Result{RequeueAfter: start.Add(readyDuration).Sub(now)}, nil
+		}
+		if err := setNodeReady(ctx, cloudClient, node.Name, true); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
 	conditions.MarkTrue(inMemoryMachine, infrav1.NodeProvisionedCondition)
 	return ctrl.Result{}, nil
 }
@@ -388,20 +394,13 @@ func (r *InMemoryMachineReconciler) reconcileNormalETCD(ctx context.Context, clu
 	}
 
 	// Wait for the etcd pod to start up; etcd pod start happens a configurable time after the Node is provisioned.
-	provisioningDuration := time.Duration(0)
+	var settings *infrav1.CommonProvisioningSettings
 	if inMemoryMachine.Spec.Behaviour != nil && inMemoryMachine.Spec.Behaviour.Etcd != nil {
-		x := inMemoryMachine.Spec.Behaviour.Etcd.Provisioning
-
-		provisioningDuration = x.StartupDuration.Duration
-		if x.StartupJitter != "" {
-			jitter, err := strconv.ParseFloat(x.StartupJitter, 64)
-			if err != nil {
-				return ctrl.Result{}, errors.Wrapf(err, "failed to parse etcd's StartupJitter")
-			}
-			if jitter > 0.0 {
-				provisioningDuration += time.Duration(rand.Float64() * jitter * float64(provisioningDuration)) //nolint:gosec // Intentionally using a weak random number generator here.
-			}
-		}
+		settings = &inMemoryMachine.Spec.Behaviour.Etcd.Provisioning
+	}
+	provisioningDuration, err := calculateProvisioningDuration(settings, r.LatencyProfile.EtcdProvisioning)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to parse etcd's StartupJitter")
 	}
 
 	start := conditions.Get(inMemoryMachine, infrav1.NodeProvisionedCondition).LastTransitionTime
@@ -592,20 +591,13 @@ func (r *InMemoryMachineReconciler) reconcileNormalAPIServer(ctx context.Context
 	}
 
 	// Wait for the API server pod to start up; API server pod start happens a configurable time after the Node is provisioned.
-	provisioningDuration := time.Duration(0)
+	var settings *infrav1.CommonProvisioningSettings
 	if inMemoryMachine.Spec.Behaviour != nil && inMemoryMachine.Spec.Behaviour.APIServer != nil {
-		x := inMemoryMachine.Spec.Behaviour.APIServer.Provisioning
-
-		provisioningDuration = x.StartupDuration.Duration
-		if x.StartupJitter != "" {
-			jitter, err := strconv.ParseFloat(x.StartupJitter, 64)
-			if err != nil {
-				return ctrl.Result{}, errors.Wrapf(err, "failed to parse API server's StartupJitter")
-			}
-			if jitter > 0.0 {
-				provisioningDuration += time.Duration(rand.Float64() * jitter * float64(provisioningDuration)) //nolint:gosec // Intentionally using a weak random number generator here.
-			}
-		}
+		settings = &inMemoryMachine.Spec.Behaviour.APIServer.Provisioning
+	}
+	provisioningDuration, err := calculateProvisioningDuration(settings, r.LatencyProfile.APIServerProvisioning)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to parse API server's StartupJitter")
 	}
 
 	start := conditions.Get(inMemoryMachine, infrav1.NodeProvisionedCondition).LastTransitionTime
@@ -1006,6 +998,14 @@ func (r *InMemoryMachineReconciler) reconcileDeleteCloudMachine(ctx context.Cont
 	resourceGroup := klog.KObj(cluster).String()
 	cloudClient := r.CloudManager.GetResourceGroup(resourceGroup).GetClient()
 
+	// Wait for the VM to be deleted; VM deletion happens a configurable time after the machine deletion.
+	deletionDuration := r.LatencyProfile.VMDeletion.Sample()
+	start := inMemoryMachine.DeletionTimestamp
+	now := time.Now()
+	if start != nil && now.Before(start.Add(deletionDuration)) {
+		return ctrl.Result{RequeueAfter: start.Add(deletionDuration).Sub(now)}, nil
+	}
+
 	// Delete VM
 	cloudMachine := &cloudv1.CloudMachine{
 		ObjectMeta: metav1.ObjectMeta{
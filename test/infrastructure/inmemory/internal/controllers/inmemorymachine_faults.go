@@ -0,0 +1,225 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/inmemory/api/v1alpha1"
+	cloudv1 "sigs.k8s.io/cluster-api/test/infrastructure/inmemory/internal/cloud/api/v1alpha1"
+	cclient "sigs.k8s.io/cluster-api/test/infrastructure/inmemory/internal/cloud/runtime/client"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// reconcileNormalFaults injects the faults defined by annotations on the InMemoryMachine into the
+// components hosted on it, or recovers from them when the annotations are removed.
+func (r *InMemoryMachineReconciler) reconcileNormalFaults(ctx context.Context, cluster *clusterv1.Cluster, machine *clusterv1.Machine, inMemoryMachine *infrav1.InMemoryMachine) (ctrl.Result, error) {
+	// Compute the resource group unique name.
+	// NOTE: We are using reconcilerGroup also as a name for the listener for sake of simplicity.
+	resourceGroup := klog.KObj(cluster).String()
+	cloudClient := r.CloudManager.GetResourceGroup(resourceGroup).GetClient()
+
+	now := time.Now()
+	res := ctrl.Result{}
+	errs := []error{}
+
+	// isActive returns true if the fault is active, and keeps track of when faults scheduled in the future will become active.
+	isActive := func(annotation string) bool {
+		active, after, err := faultActive(inMemoryMachine, annotation, now)
+		if err != nil {
+			errs = append(errs, err)
+			return false
+		}
+		res = util.LowestNonZeroResult(res, ctrl.Result{RequeueAfter: after})
+		return active
+	}
+
+	if conditions.IsTrue(inMemoryMachine, infrav1.NodeProvisionedCondition) {
+		active := isActive(infrav1.NodeNotReadyFaultAnnotation)
+		if err := setNodeReady(ctx, cloudClient, inMemoryMachine.Name, !active); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if util.IsControlPlaneMachine(machine) && conditions.IsTrue(inMemoryMachine, infrav1.EtcdProvisionedCondition) {
+		etcdPod := fmt.Sprintf("etcd-%s", inMemoryMachine.Name)
+
+		active := isActive(infrav1.EtcdUnavailableFaultAnnotation)
+		if err := setPodAnnotation(ctx, cloudClient, etcdPod, cloudv1.EtcdUnavailableAnnotationName, active); err != nil {
+			errs = append(errs, err)
+		}
+
+		// NOTE: An etcd member which left the cluster cannot join again, so the fault is never reverted.
+		if isActive(infrav1.EtcdMemberLeftFaultAnnotation) {
+			if err := setPodAnnotation(ctx, cloudClient, etcdPod, cloudv1.EtcdMemberRemoved, true); err != nil {
+				errs = append(errs, err)
+			} else if err := electEtcdLeader(ctx, cloudClient); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	if util.IsControlPlaneMachine(machine) && conditions.IsTrue(inMemoryMachine, infrav1.APIServerProvisionedCondition) {
+		active := isActive(infrav1.APIServerUnavailableFaultAnnotation)
+		if err := setPodAnnotation(ctx, cloudClient, fmt.Sprintf("kube-apiserver-%s", inMemoryMachine.Name), cloudv1.APIServerUnavailableAnnotationName, active); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return res, kerrors.NewAggregate(errs)
+}
+
+// faultActive returns true if the fault defined by the given annotation is active; if the fault is scheduled to be
+// injected in the future, it returns the time to wait before the fault becomes active.
+func faultActive(inMemoryMachine *infrav1.InMemoryMachine, annotation string, now time.Time) (bool, time.Duration, error) {
+	value, ok := inMemoryMachine.Annotations[annotation]
+	if !ok {
+		return false, 0, nil
+	}
+	if value == "" {
+		return true, 0, nil
+	}
+
+	at, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return false, 0, errors.Wrapf(err, "failed to parse %s annotation", annotation)
+	}
+	if now.Before(at) {
+		return false, at.Sub(now), nil
+	}
+	return true, 0, nil
+}
+
+// electEtcdLeader makes one of the remaining etcd members the leader, if the etcd cluster has no leader
+// e.g. because the leader left the etcd cluster.
+func electEtcdLeader(ctx context.Context, cloudClient cclient.Client) error {
+	etcdPods := &corev1.PodList{}
+	if err := cloudClient.List(ctx, etcdPods,
+		client.InNamespace(metav1.NamespaceSystem),
+		client.MatchingLabels{
+			"component": "etcd",
+			"tier":      "control-plane"},
+	); err != nil {
+		return errors.Wrap(err, "failed to list etcd members")
+	}
+
+	var candidate *corev1.Pod
+	for i := range etcdPods.Items {
+		pod := &etcdPods.Items[i]
+		if _, ok := pod.Annotations[cloudv1.EtcdMemberRemoved]; ok {
+			continue
+		}
+		if _, err := time.Parse(time.RFC3339, pod.Annotations[cloudv1.EtcdLeaderFromAnnotationName]); err == nil {
+			return nil
+		}
+		if candidate == nil {
+			candidate = pod
+		}
+	}
+	if candidate == nil {
+		return nil
+	}
+
+	updatedPod := candidate.DeepCopy()
+	if updatedPod.Annotations == nil {
+		updatedPod.Annotations = map[string]string{}
+	}
+	updatedPod.Annotations[cloudv1.EtcdLeaderFromAnnotationName] = time.Now().Format(time.RFC3339)
+	if err := cloudClient.Patch(ctx, updatedPod, client.MergeFrom(candidate)); err != nil {
+		return errors.Wrapf(err, "failed to patch Pod %s", candidate.Name)
+	}
+	return nil
+}
+
+// setNodeReady sets the status of the Ready condition of a Node.
+func setNodeReady(ctx context.Context, cloudClient cclient.Client, name string, ready bool) error {
+	node := &corev1.Node{}
+	if err := cloudClient.Get(ctx, client.ObjectKey{Name: name}, node); err != nil {
+		return errors.Wrapf(err, "failed to get Node")
+	}
+
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+
+	updatedNode := node.DeepCopy()
+	found := false
+	for i := range updatedNode.Status.Conditions {
+		c := &updatedNode.Status.Conditions[i]
+		if c.Type != corev1.NodeReady {
+			continue
+		}
+		if c.Status == status {
+			return nil
+		}
+		c.Status = status
+		c.LastTransitionTime = metav1.Now()
+		found = true
+	}
+	if !found {
+		updatedNode.Status.Conditions = append(updatedNode.Status.Conditions, corev1.NodeCondition{
+			Type:               corev1.NodeReady,
+			Status:             status,
+			LastTransitionTime: metav1.Now(),
+		})
+	}
+
+	if err := cloudClient.Patch(ctx, updatedNode, client.MergeFrom(node)); err != nil {
+		return errors.Wrapf(err, "failed to patch Node")
+	}
+	return nil
+}
+
+// setPodAnnotation adds or removes an annotation from a Pod in the kube-system namespace.
+func setPodAnnotation(ctx context.Context, cloudClient cclient.Client, name, annotation string, set bool) error {
+	pod := &corev1.Pod{}
+	if err := cloudClient.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceSystem, Name: name}, pod); err != nil {
+		return errors.Wrapf(err, "failed to get Pod %s", name)
+	}
+
+	if _, ok := pod.Annotations[annotation]; ok == set {
+		return nil
+	}
+
+	updatedPod := pod.DeepCopy()
+	if set {
+		if updatedPod.Annotations == nil {
+			updatedPod.Annotations = map[string]string{}
+		}
+		updatedPod.Annotations[annotation] = ""
+	} else {
+		delete(updatedPod.Annotations, annotation)
+	}
+
+	if err := cloudClient.Patch(ctx, updatedPod, client.MergeFrom(pod)); err != nil {
+		return errors.Wrapf(err, "failed to patch Pod %s", name)
+	}
+	return nil
+}
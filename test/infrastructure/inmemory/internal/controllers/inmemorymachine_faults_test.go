@@ -0,0 +1,207 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/inmemory/api/v1alpha1"
+	cloudv1 "sigs.k8s.io/cluster-api/test/infrastructure/inmemory/internal/cloud/api/v1alpha1"
+	cmanager "sigs.k8s.io/cluster-api/test/infrastructure/inmemory/internal/cloud/runtime/manager"
+)
+
+func TestReconcileNormalFaults(t *testing.T) {
+	g := NewWithT(t)
+
+	inMemoryMachine := &infrav1.InMemoryMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "bar",
+		},
+		Status: infrav1.InMemoryMachineStatus{
+			Conditions: []clusterv1.Condition{
+				{
+					Type:   infrav1.NodeProvisionedCondition,
+					Status: corev1.ConditionTrue,
+				},
+				{
+					Type:   infrav1.EtcdProvisionedCondition,
+					Status: corev1.ConditionTrue,
+				},
+				{
+					Type:   infrav1.APIServerProvisionedCondition,
+					Status: corev1.ConditionTrue,
+				},
+			},
+		},
+	}
+
+	r := InMemoryMachineReconciler{
+		CloudManager: cmanager.New(scheme),
+	}
+	r.CloudManager.AddResourceGroup(klog.KObj(cluster).String())
+	c := r.CloudManager.GetResourceGroup(klog.KObj(cluster).String()).GetClient()
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: inMemoryMachine.Name,
+		},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{
+				{
+					Type:   corev1.NodeReady,
+					Status: corev1.ConditionTrue,
+				},
+			},
+		},
+	}
+	g.Expect(c.Create(ctx, node)).To(Succeed())
+
+	etcdPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceSystem,
+			Name:      "etcd-bar",
+			Labels: map[string]string{
+				"component": "etcd",
+				"tier":      "control-plane",
+			},
+			Annotations: map[string]string{
+				cloudv1.EtcdClusterIDAnnotationName:  "1",
+				cloudv1.EtcdMemberIDAnnotationName:   "2",
+				cloudv1.EtcdLeaderFromAnnotationName: time.Now().Format(time.RFC3339),
+			},
+		},
+	}
+	g.Expect(c.Create(ctx, etcdPod)).To(Succeed())
+
+	apiServerPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceSystem,
+			Name:      "kube-apiserver-bar",
+			Labels: map[string]string{
+				"component": "kube-apiserver",
+				"tier":      "control-plane",
+			},
+		},
+	}
+	g.Expect(c.Create(ctx, apiServerPod)).To(Succeed())
+
+	nodeReadyStatus := func() corev1.ConditionStatus {
+		got := &corev1.Node{}
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(node), got)).To(Succeed())
+		for _, condition := range got.Status.Conditions {
+			if condition.Type == corev1.NodeReady {
+				return condition.Status
+			}
+		}
+		return corev1.ConditionUnknown
+	}
+	podAnnotations := func(pod *corev1.Pod) map[string]string {
+		got := &corev1.Pod{}
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(pod), got)).To(Succeed())
+		return got.Annotations
+	}
+
+	t.Run("no-op if there are no faults", func(t *testing.T) {
+		g := NewWithT(t)
+
+		res, err := r.reconcileNormalFaults(ctx, cluster, cpMachine, inMemoryMachine)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(res.IsZero()).To(BeTrue())
+
+		g.Expect(nodeReadyStatus()).To(Equal(corev1.ConditionTrue))
+		g.Expect(podAnnotations(etcdPod)).ToNot(HaveKey(cloudv1.EtcdUnavailableAnnotationName))
+		g.Expect(podAnnotations(apiServerPod)).ToNot(HaveKey(cloudv1.APIServerUnavailableAnnotationName))
+	})
+
+	t.Run("requeue for faults scheduled in the future", func(t *testing.T) {
+		g := NewWithT(t)
+
+		inMemoryMachine.Annotations = map[string]string{
+			infrav1.NodeNotReadyFaultAnnotation: time.Now().Add(1 * time.Hour).Format(time.RFC3339),
+		}
+
+		res, err := r.reconcileNormalFaults(ctx, cluster, cpMachine, inMemoryMachine)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(res.RequeueAfter).To(BeNumerically(">", 59*time.Minute))
+
+		g.Expect(nodeReadyStatus()).To(Equal(corev1.ConditionTrue))
+	})
+
+	t.Run("inject faults", func(t *testing.T) {
+		g := NewWithT(t)
+
+		inMemoryMachine.Annotations = map[string]string{
+			infrav1.NodeNotReadyFaultAnnotation:         time.Now().Add(-1 * time.Minute).Format(time.RFC3339),
+			infrav1.EtcdUnavailableFaultAnnotation:      "",
+			infrav1.APIServerUnavailableFaultAnnotation: "",
+		}
+
+		res, err := r.reconcileNormalFaults(ctx, cluster, cpMachine, inMemoryMachine)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(res.IsZero()).To(BeTrue())
+
+		g.Expect(nodeReadyStatus()).To(Equal(corev1.ConditionFalse))
+		g.Expect(podAnnotations(etcdPod)).To(HaveKey(cloudv1.EtcdUnavailableAnnotationName))
+		g.Expect(podAnnotations(apiServerPod)).To(HaveKey(cloudv1.APIServerUnavailableAnnotationName))
+	})
+
+	t.Run("recover from faults", func(t *testing.T) {
+		g := NewWithT(t)
+
+		inMemoryMachine.Annotations = nil
+
+		res, err := r.reconcileNormalFaults(ctx, cluster, cpMachine, inMemoryMachine)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(res.IsZero()).To(BeTrue())
+
+		g.Expect(nodeReadyStatus()).To(Equal(corev1.ConditionTrue))
+		g.Expect(podAnnotations(etcdPod)).ToNot(HaveKey(cloudv1.EtcdUnavailableAnnotationName))
+		g.Expect(podAnnotations(apiServerPod)).ToNot(HaveKey(cloudv1.APIServerUnavailableAnnotationName))
+	})
+
+	t.Run("fail for invalid annotation values", func(t *testing.T) {
+		g := NewWithT(t)
+
+		inMemoryMachine.Annotations = map[string]string{
+			infrav1.NodeNotReadyFaultAnnotation: "tomorrow",
+		}
+
+		_, err := r.reconcileNormalFaults(ctx, cluster, cpMachine, inMemoryMachine)
+		g.Expect(err).To(HaveOccurred())
+	})
+
+	t.Run("etcd member leaves the cluster", func(t *testing.T) {
+		g := NewWithT(t)
+
+		inMemoryMachine.Annotations = map[string]string{
+			infrav1.EtcdMemberLeftFaultAnnotation: "",
+		}
+
+		_, err := r.reconcileNormalFaults(ctx, cluster, cpMachine, inMemoryMachine)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(podAnnotations(etcdPod)).To(HaveKey(cloudv1.EtcdMemberRemoved))
+	})
+}
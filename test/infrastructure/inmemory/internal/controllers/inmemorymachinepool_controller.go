@@ -0,0 +1,401 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	utilexp "sigs.k8s.io/cluster-api/exp/util"
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/inmemory/api/v1alpha1"
+	"sigs.k8s.io/cluster-api/test/infrastructure/inmemory/internal/cloud"
+	cloudv1 "sigs.k8s.io/cluster-api/test/infrastructure/inmemory/internal/cloud/api/v1alpha1"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+)
+
+// InMemoryMachinePoolReconciler reconciles a InMemoryMachinePool object.
+type InMemoryMachinePoolReconciler struct {
+	client.Client
+	CloudManager cloud.Manager
+
+	// LatencyProfile defines the latency of the simulated operations.
+	LatencyProfile LatencyProfile
+
+	// WatchFilterValue is the label value used to filter events prior to reconciliation.
+	WatchFilterValue string
+}
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=inmemorymachinepools,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=inmemorymachinepools/status;inmemorymachinepools/finalizers,verbs=get;update;patch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;machinepools,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+
+// Reconcile handles InMemoryMachinePool events.
+func (r *InMemoryMachinePoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, rerr error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	// Fetch the InMemoryMachinePool instance
+	inMemoryMachinePool := &infrav1.InMemoryMachinePool{}
+	if err := r.Client.Get(ctx, req.NamespacedName, inMemoryMachinePool); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Fetch the MachinePool.
+	machinePool, err := utilexp.GetOwnerMachinePool(ctx, r.Client, inMemoryMachinePool.ObjectMeta)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if machinePool == nil {
+		log.Info("Waiting for MachinePool Controller to set OwnerRef on InMemoryMachinePool")
+		return ctrl.Result{}, nil
+	}
+
+	log = log.WithValues("MachinePool", klog.KObj(machinePool))
+	ctx = ctrl.LoggerInto(ctx, log)
+
+	// Fetch the Cluster.
+	cluster, err := util.GetClusterFromMetadata(ctx, r.Client, machinePool.ObjectMeta)
+	if err != nil {
+		log.Info("InMemoryMachinePool owner MachinePool is missing cluster label or cluster does not exist")
+		return ctrl.Result{}, err
+	}
+	if cluster == nil {
+		log.Info(fmt.Sprintf("Please associate this machine pool with a cluster using the label %s: <name of cluster>", clusterv1.ClusterNameLabel))
+		return ctrl.Result{}, nil
+	}
+
+	log = log.WithValues("Cluster", klog.KObj(cluster))
+	ctx = ctrl.LoggerInto(ctx, log)
+
+	// Return early if the object or Cluster is paused.
+	if annotations.IsPaused(cluster, inMemoryMachinePool) {
+		log.Info("Reconciliation is paused for this object")
+		return ctrl.Result{}, nil
+	}
+
+	// Initialize the patch helper
+	patchHelper, err := patch.NewHelper(inMemoryMachinePool, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Always attempt to Patch the InMemoryMachinePool object and status after each reconciliation.
+	defer func() {
+		// Always update the readyCondition by summarizing the state of other conditions.
+		conditions.SetSummary(inMemoryMachinePool,
+			conditions.WithConditions(infrav1.ReplicasReadyCondition),
+		)
+		if err := patchHelper.Patch(ctx, inMemoryMachinePool, patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
+			clusterv1.ReadyCondition,
+			infrav1.ReplicasReadyCondition,
+		}}); err != nil {
+			log.Error(err, "failed to patch InMemoryMachinePool")
+			if rerr == nil {
+				rerr = err
+			}
+		}
+	}()
+
+	// Handle deleted machine pools
+	if !inMemoryMachinePool.DeletionTimestamp.IsZero() {
+		return r.reconcileDelete(ctx, cluster, inMemoryMachinePool)
+	}
+
+	// Add finalizer first if not set to avoid the race condition between init and delete.
+	// Note: Finalizers in general can only be added when the deletionTimestamp is not set.
+	if !controllerutil.ContainsFinalizer(inMemoryMachinePool, infrav1.MachinePoolFinalizer) {
+		controllerutil.AddFinalizer(inMemoryMachinePool, infrav1.MachinePoolFinalizer)
+		return ctrl.Result{}, nil
+	}
+
+	// Handle non-deleted machine pools
+	return r.reconcileNormal(ctx, cluster, machinePool, inMemoryMachinePool)
+}
+
+func (r *InMemoryMachinePoolReconciler) reconcileNormal(ctx context.Context, cluster *clusterv1.Cluster, machinePool *expv1.MachinePool, inMemoryMachinePool *infrav1.InMemoryMachinePool) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	// Check if the infrastructure is ready, otherwise return and wait for the cluster object to be updated
+	if !cluster.Status.InfrastructureReady {
+		conditions.MarkFalse(inMemoryMachinePool, infrav1.ReplicasReadyCondition, infrav1.WaitingForClusterInfrastructureReason, clusterv1.ConditionSeverityInfo, "")
+		log.Info("Waiting for InMemoryCluster Controller to create cluster infrastructure")
+		return ctrl.Result{}, nil
+	}
+
+	// Make sure bootstrap data is available and populated.
+	// NOTE: we are not using bootstrap data, but we wait for it in order to simulate a real machine
+	// provisioning workflow.
+	if machinePool.Spec.Template.Spec.Bootstrap.DataSecretName == nil {
+		if !conditions.IsTrue(cluster, clusterv1.ControlPlaneInitializedCondition) {
+			conditions.MarkFalse(inMemoryMachinePool, infrav1.ReplicasReadyCondition, infrav1.WaitingControlPlaneInitializedReason, clusterv1.ConditionSeverityInfo, "")
+			log.Info("Waiting for the control plane to be initialized")
+			return ctrl.Result{}, nil
+		}
+
+		conditions.MarkFalse(inMemoryMachinePool, infrav1.ReplicasReadyCondition, infrav1.WaitingForBootstrapDataReason, clusterv1.ConditionSeverityInfo, "")
+		log.Info("Waiting for the Bootstrap provider controller to set bootstrap data")
+		return ctrl.Result{}, nil
+	}
+
+	// Compute the resource group unique name.
+	// NOTE: We are using reconcilerGroup also as a name for the listener for sake of simplicity.
+	resourceGroup := klog.KObj(cluster).String()
+	cloudClient := r.CloudManager.GetResourceGroup(resourceGroup).GetClient()
+
+	// NOTE: for sake of simplicity we keep cloud resources as global resources (namespace empty).
+	cloudMachines := &cloudv1.CloudMachineList{}
+	if err := cloudClient.List(ctx, cloudMachines, client.MatchingLabels{infrav1.MachinePoolNameLabel: inMemoryMachinePool.Name}); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to list CloudMachines")
+	}
+	nodes := &corev1.NodeList{}
+	if err := cloudClient.List(ctx, nodes, client.MatchingLabels{infrav1.MachinePoolNameLabel: inMemoryMachinePool.Name}); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to list Nodes")
+	}
+
+	replicas := int(pointer.Int32Deref(machinePool.Spec.Replicas, 1))
+
+	// Scale down by deleting the machines with the highest index, and keep track of the existing ones.
+	existingCloudMachines := map[string]*cloudv1.CloudMachine{}
+	for i := range cloudMachines.Items {
+		cloudMachine := &cloudMachines.Items[i]
+		if machinePoolMemberIndex(inMemoryMachinePool, cloudMachine.Name) < replicas {
+			existingCloudMachines[cloudMachine.Name] = cloudMachine
+			continue
+		}
+		if err := cloudClient.Delete(ctx, cloudMachine); err != nil && !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to delete CloudMachine %s", cloudMachine.Name)
+		}
+	}
+	existingNodes := map[string]*corev1.Node{}
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if machinePoolMemberIndex(inMemoryMachinePool, node.Name) < replicas {
+			existingNodes[node.Name] = node
+			continue
+		}
+		if err := cloudClient.Delete(ctx, node); err != nil && !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to delete Node %s", node.Name)
+		}
+	}
+
+	var vmSettings, nodeSettings *infrav1.CommonProvisioningSettings
+	if inMemoryMachinePool.Spec.Behaviour != nil {
+		if inMemoryMachinePool.Spec.Behaviour.VM != nil {
+			vmSettings = &inMemoryMachinePool.Spec.Behaviour.VM.Provisioning
+		}
+		if inMemoryMachinePool.Spec.Behaviour.Node != nil {
+			nodeSettings = &inMemoryMachinePool.Spec.Behaviour.Node.Provisioning
+		}
+	}
+
+	// Scale up by creating the missing machines, and wait for all of them to be provisioned.
+	res := ctrl.Result{}
+	errs := []error{}
+	providerIDs := []string{}
+	now := time.Now()
+	for i := 0; i < replicas; i++ {
+		name := fmt.Sprintf("%s-%d", inMemoryMachinePool.Name, i)
+
+		cloudMachine, ok := existingCloudMachines[name]
+		if !ok {
+			cloudMachine = &cloudv1.CloudMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: name,
+					Labels: map[string]string{
+						infrav1.MachinePoolNameLabel: inMemoryMachinePool.Name,
+					},
+				},
+			}
+			if err := cloudClient.Create(ctx, cloudMachine); err != nil && !apierrors.IsAlreadyExists(err) {
+				errs = append(errs, errors.Wrapf(err, "failed to create CloudMachine %s", name))
+				continue
+			}
+		}
+
+		// Wait for the VM to be provisioned and for the node/kubelet to start up; both happen a configurable
+		// time after the cloud machine creation.
+		// NOTE: for sake of simplicity Nodes are created ready, so the time required for the Node to become ready
+		// is added to the Node provisioning time.
+		vmProvisioningDuration, err := calculateProvisioningDuration(vmSettings, r.LatencyProfile.VMProvisioning)
+		if err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to parse VM's StartupJitter")
+		}
+		nodeProvisioningDuration, err := calculateProvisioningDuration(nodeSettings, r.LatencyProfile.NodeProvisioning)
+		if err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to parse node's StartupJitter")
+		}
+
+		ready := cloudMachine.CreationTimestamp.Add(vmProvisioningDuration + nodeProvisioningDuration + r.LatencyProfile.NodeReady.Sample())
+		if now.Before(ready) {
+			res = util.LowestNonZeroResult(res, ctrl.Result{RequeueAfter: ready.Sub(now)})
+			continue
+		}
+
+		providerID := calculateMachinePoolProviderID(name)
+		if _, ok := existingNodes[name]; !ok {
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: name,
+					Labels: map[string]string{
+						infrav1.MachinePoolNameLabel: inMemoryMachinePool.Name,
+					},
+				},
+				Spec: corev1.NodeSpec{
+					ProviderID: providerID,
+				},
+				Status: corev1.NodeStatus{
+					Conditions: []corev1.NodeCondition{
+						{
+							Type:   corev1.NodeReady,
+							Status: corev1.ConditionTrue,
+						},
+					},
+				},
+			}
+			if err := cloudClient.Create(ctx, node); err != nil && !apierrors.IsAlreadyExists(err) {
+				errs = append(errs, errors.Wrapf(err, "failed to create Node %s", name))
+				continue
+			}
+		}
+
+		providerIDs = append(providerIDs, providerID)
+	}
+
+	sort.Strings(providerIDs)
+	inMemoryMachinePool.Spec.ProviderIDList = providerIDs
+	inMemoryMachinePool.Status.Replicas = int32(len(providerIDs))
+
+	if len(providerIDs) < replicas {
+		conditions.MarkFalse(inMemoryMachinePool, infrav1.ReplicasReadyCondition, infrav1.WaitingForReplicasReason, clusterv1.ConditionSeverityInfo, "%d of %d replicas ready", len(providerIDs), replicas)
+	} else {
+		conditions.MarkTrue(inMemoryMachinePool, infrav1.ReplicasReadyCondition)
+	}
+	// NOTE: As for other infrastructure providers, the machine pool stays ready while scaling.
+	inMemoryMachinePool.Status.Ready = inMemoryMachinePool.Status.Ready || len(providerIDs) == replicas
+
+	return res, kerrors.NewAggregate(errs)
+}
+
+func (r *InMemoryMachinePoolReconciler) reconcileDelete(ctx context.Context, cluster *clusterv1.Cluster, inMemoryMachinePool *infrav1.InMemoryMachinePool) (ctrl.Result, error) {
+	// Compute the resource group unique name.
+	// NOTE: We are using reconcilerGroup also as a name for the listener for sake of simplicity.
+	resourceGroup := klog.KObj(cluster).String()
+	cloudClient := r.CloudManager.GetResourceGroup(resourceGroup).GetClient()
+
+	// Wait for the VMs to be deleted; VM deletion happens a configurable time after the machine pool deletion.
+	deletionDuration := r.LatencyProfile.VMDeletion.Sample()
+	start := inMemoryMachinePool.DeletionTimestamp
+	now := time.Now()
+	if start != nil && now.Before(start.Add(deletionDuration)) {
+		return ctrl.Result{RequeueAfter: start.Add(deletionDuration).Sub(now)}, nil
+	}
+
+	// Delete VMs
+	cloudMachines := &cloudv1.CloudMachineList{}
+	if err := cloudClient.List(ctx, cloudMachines, client.MatchingLabels{infrav1.MachinePoolNameLabel: inMemoryMachinePool.Name}); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to list CloudMachines")
+	}
+	for i := range cloudMachines.Items {
+		if err := cloudClient.Delete(ctx, &cloudMachines.Items[i]); err != nil && !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to delete CloudMachine %s", cloudMachines.Items[i].Name)
+		}
+	}
+
+	// Delete Nodes
+	nodes := &corev1.NodeList{}
+	if err := cloudClient.List(ctx, nodes, client.MatchingLabels{infrav1.MachinePoolNameLabel: inMemoryMachinePool.Name}); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to list Nodes")
+	}
+	for i := range nodes.Items {
+		if err := cloudClient.Delete(ctx, &nodes.Items[i]); err != nil && !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to delete Node %s", nodes.Items[i].Name)
+		}
+	}
+
+	controllerutil.RemoveFinalizer(inMemoryMachinePool, infrav1.MachinePoolFinalizer)
+	return ctrl.Result{}, nil
+}
+
+// machinePoolMemberIndex returns the index of a machine belonging to an InMemoryMachinePool, or -1 if
+// the name does not match the expected format.
+func machinePoolMemberIndex(inMemoryMachinePool *infrav1.InMemoryMachinePool, name string) int {
+	i, err := strconv.Atoi(strings.TrimPrefix(name, inMemoryMachinePool.Name+"-"))
+	if err != nil {
+		return -1
+	}
+	return i
+}
+
+func calculateMachinePoolProviderID(name string) string {
+	return fmt.Sprintf("in-memory://%s", name)
+}
+
+// SetupWithManager will add watches for this controller.
+func (r *InMemoryMachinePoolReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	clusterToInMemoryMachinePools, err := util.ClusterToTypedObjectsMapper(mgr.GetClient(), &infrav1.InMemoryMachinePoolList{}, mgr.GetScheme())
+	if err != nil {
+		return err
+	}
+
+	err = ctrl.NewControllerManagedBy(mgr).
+		For(&infrav1.InMemoryMachinePool{}).
+		WithOptions(options).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
+		Watches(
+			&expv1.MachinePool{},
+			handler.EnqueueRequestsFromMapFunc(utilexp.MachinePoolToInfrastructureMapFunc(
+				infrav1.GroupVersion.WithKind("InMemoryMachinePool"), ctrl.LoggerFrom(ctx))),
+		).
+		Watches(
+			&clusterv1.Cluster{},
+			handler.EnqueueRequestsFromMapFunc(clusterToInMemoryMachinePools),
+			builder.WithPredicates(
+				predicates.ClusterUnpausedAndInfrastructureReady(ctrl.LoggerFrom(ctx)),
+			),
+		).Complete(r)
+	if err != nil {
+		return errors.Wrap(err, "failed setting up with a controller manager")
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/inmemory/api/v1alpha1"
+	cloudv1 "sigs.k8s.io/cluster-api/test/infrastructure/inmemory/internal/cloud/api/v1alpha1"
+	cmanager "sigs.k8s.io/cluster-api/test/infrastructure/inmemory/internal/cloud/runtime/manager"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestReconcileNormalInMemoryMachinePool(t *testing.T) {
+	g := NewWithT(t)
+
+	readyCluster := cluster.DeepCopy()
+	readyCluster.Status.InfrastructureReady = true
+
+	machinePool := &expv1.MachinePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "pool",
+		},
+		Spec: expv1.MachinePoolSpec{
+			Replicas: pointer.Int32(3),
+			Template: clusterv1.MachineTemplateSpec{
+				Spec: clusterv1.MachineSpec{
+					Bootstrap: clusterv1.Bootstrap{
+						DataSecretName: pointer.String("bootstrap-data"),
+					},
+				},
+			},
+		},
+	}
+
+	inMemoryMachinePool := &infrav1.InMemoryMachinePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "pool",
+		},
+	}
+
+	r := InMemoryMachinePoolReconciler{
+		CloudManager: cmanager.New(scheme),
+	}
+	r.CloudManager.AddResourceGroup(klog.KObj(readyCluster).String())
+	c := r.CloudManager.GetResourceGroup(klog.KObj(readyCluster).String()).GetClient()
+
+	members := func() ([]string, []string) {
+		cloudMachines := &cloudv1.CloudMachineList{}
+		g.Expect(c.List(ctx, cloudMachines, client.MatchingLabels{infrav1.MachinePoolNameLabel: inMemoryMachinePool.Name})).To(Succeed())
+		nodes := &corev1.NodeList{}
+		g.Expect(c.List(ctx, nodes, client.MatchingLabels{infrav1.MachinePoolNameLabel: inMemoryMachinePool.Name})).To(Succeed())
+
+		cloudMachineNames := []string{}
+		for _, m := range cloudMachines.Items {
+			cloudMachineNames = append(cloudMachineNames, m.Name)
+		}
+		nodeNames := []string{}
+		for _, n := range nodes.Items {
+			nodeNames = append(nodeNames, n.Name)
+		}
+		return cloudMachineNames, nodeNames
+	}
+
+	t.Run("waits for the cluster infrastructure to be ready", func(t *testing.T) {
+		g := NewWithT(t)
+
+		res, err := r.reconcileNormal(ctx, cluster, machinePool, inMemoryMachinePool)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(res.IsZero()).To(BeTrue())
+		g.Expect(conditions.GetReason(inMemoryMachinePool, infrav1.ReplicasReadyCondition)).To(Equal(infrav1.WaitingForClusterInfrastructureReason))
+	})
+
+	t.Run("waits for the machines to be provisioned", func(t *testing.T) {
+		g := NewWithT(t)
+
+		inMemoryMachinePool.Spec.Behaviour = &infrav1.InMemoryMachinePoolBehaviour{
+			VM: &infrav1.InMemoryVMBehaviour{
+				Provisioning: infrav1.CommonProvisioningSettings{
+					StartupDuration: metav1.Duration{Duration: 2 * time.Second},
+				},
+			},
+		}
+
+		res, err := r.reconcileNormal(ctx, readyCluster, machinePool, inMemoryMachinePool)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(res.RequeueAfter).To(BeNumerically(">", 0))
+		g.Expect(inMemoryMachinePool.Spec.ProviderIDList).To(BeEmpty())
+		g.Expect(inMemoryMachinePool.Status.Ready).To(BeFalse())
+		g.Expect(conditions.GetReason(inMemoryMachinePool, infrav1.ReplicasReadyCondition)).To(Equal(infrav1.WaitingForReplicasReason))
+
+		cloudMachines, nodes := members()
+		g.Expect(cloudMachines).To(ConsistOf("pool-0", "pool-1", "pool-2"))
+		g.Expect(nodes).To(BeEmpty())
+	})
+
+	t.Run("creates nodes when machines are provisioned", func(t *testing.T) {
+		g := NewWithT(t)
+
+		inMemoryMachinePool.Spec.Behaviour = nil
+
+		res, err := r.reconcileNormal(ctx, readyCluster, machinePool, inMemoryMachinePool)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(res.IsZero()).To(BeTrue())
+		g.Expect(inMemoryMachinePool.Spec.ProviderIDList).To(Equal([]string{"in-memory://pool-0", "in-memory://pool-1", "in-memory://pool-2"}))
+		g.Expect(inMemoryMachinePool.Status.Replicas).To(Equal(int32(3)))
+		g.Expect(inMemoryMachinePool.Status.Ready).To(BeTrue())
+		g.Expect(conditions.IsTrue(inMemoryMachinePool, infrav1.ReplicasReadyCondition)).To(BeTrue())
+
+		_, nodes := members()
+		g.Expect(nodes).To(ConsistOf("pool-0", "pool-1", "pool-2"))
+	})
+
+	t.Run("scales down", func(t *testing.T) {
+		g := NewWithT(t)
+
+		machinePool.Spec.Replicas = pointer.Int32(1)
+
+		res, err := r.reconcileNormal(ctx, readyCluster, machinePool, inMemoryMachinePool)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(res.IsZero()).To(BeTrue())
+		g.Expect(inMemoryMachinePool.Spec.ProviderIDList).To(Equal([]string{"in-memory://pool-0"}))
+		g.Expect(inMemoryMachinePool.Status.Replicas).To(Equal(int32(1)))
+
+		cloudMachines, nodes := members()
+		g.Expect(cloudMachines).To(ConsistOf("pool-0"))
+		g.Expect(nodes).To(ConsistOf("pool-0"))
+	})
+
+	t.Run("deletes all the machines", func(t *testing.T) {
+		g := NewWithT(t)
+
+		inMemoryMachinePool.Finalizers = []string{infrav1.MachinePoolFinalizer}
+
+		res, err := r.reconcileDelete(ctx, readyCluster, inMemoryMachinePool)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(res.IsZero()).To(BeTrue())
+		g.Expect(inMemoryMachinePool.Finalizers).To(BeEmpty())
+
+		cloudMachines, nodes := members()
+		g.Expect(cloudMachines).To(BeEmpty())
+		g.Expect(nodes).To(BeEmpty())
+	})
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"math/rand"
+	"strconv"
+	"time"
+
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/inmemory/api/v1alpha1"
+)
+
+// LatencyProfile defines the latency of the operations simulated by the in-memory provider, thus allowing to make
+// scalability test results representative of slow clouds.
+// NOTE: The provisioning latencies apply only to the machines not defining the corresponding behaviour.
+type LatencyProfile struct {
+	// VMProvisioning is the time from the creation of a VM to the VM being provisioned.
+	VMProvisioning Latency
+
+	// NodeProvisioning is the time from the VM being provisioned to the Node being created.
+	NodeProvisioning Latency
+
+	// NodeReady is the time from the Node being created to the Node becoming ready.
+	NodeReady Latency
+
+	// EtcdProvisioning is the time from the Node being provisioned to the etcd member being provisioned.
+	EtcdProvisioning Latency
+
+	// APIServerProvisioning is the time from the Node being provisioned to the API server being provisioned.
+	APIServerProvisioning Latency
+
+	// VMDeletion is the time from the deletion of a machine to the VM being deleted.
+	VMDeletion Latency
+}
+
+// Latency defines the distribution of the duration of an operation; the actual duration will be Duration plus an
+// additional amount chosen uniformly at random from the interval between zero and `Jitter*Duration`.
+type Latency struct {
+	Duration time.Duration
+	Jitter   float64
+}
+
+// Sample returns a duration from the distribution defined by Latency.
+func (l Latency) Sample() time.Duration {
+	d := l.Duration
+	if l.Jitter > 0.0 {
+		d += time.Duration(rand.Float64() * l.Jitter * float64(d)) //nolint:gosec // Intentionally using a weak random number generator here.
+	}
+	return d
+}
+
+// calculateProvisioningDuration returns the provisioning duration defined by settings, or by the default latency if settings are not defined.
+func calculateProvisioningDuration(settings *infrav1.CommonProvisioningSettings, defaultLatency Latency) (time.Duration, error) {
+	if settings == nil {
+		return defaultLatency.Sample(), nil
+	}
+
+	latency := Latency{Duration: settings.StartupDuration.Duration}
+	if settings.StartupJitter != "" {
+		jitter, err := strconv.ParseFloat(settings.StartupJitter, 64)
+		if err != nil {
+			return 0, err
+		}
+		latency.Jitter = jitter
+	}
+	return latency.Sample(), nil
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/inmemory/api/v1alpha1"
+)
+
+func TestCalculateProvisioningDuration(t *testing.T) {
+	tests := []struct {
+		name           string
+		settings       *infrav1.CommonProvisioningSettings
+		defaultLatency Latency
+		wantMin        time.Duration
+		wantMax        time.Duration
+		wantErr        bool
+	}{
+		{
+			name:    "no settings and no default latency",
+			wantMin: 0,
+			wantMax: 0,
+		},
+		{
+			name:           "no settings, use default latency",
+			defaultLatency: Latency{Duration: 10 * time.Second, Jitter: 0.5},
+			wantMin:        10 * time.Second,
+			wantMax:        15 * time.Second,
+		},
+		{
+			name: "settings take precedence over default latency",
+			settings: &infrav1.CommonProvisioningSettings{
+				StartupDuration: metav1.Duration{Duration: 2 * time.Second},
+				StartupJitter:   "1",
+			},
+			defaultLatency: Latency{Duration: 10 * time.Second},
+			wantMin:        2 * time.Second,
+			wantMax:        4 * time.Second,
+		},
+		{
+			name: "fails for invalid jitter",
+			settings: &infrav1.CommonProvisioningSettings{
+				StartupDuration: metav1.Duration{Duration: 2 * time.Second},
+				StartupJitter:   "a lot",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := calculateProvisioningDuration(tt.settings, tt.defaultLatency)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(BeNumerically(">=", tt.wantMin))
+			g.Expect(got).To(BeNumerically("<=", tt.wantMax))
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"math/rand"
 	"net"
 	"net/http"
 	"net/url"
@@ -30,6 +31,7 @@ import (
 	"github.com/emicklei/go-restful/v3"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -48,6 +50,7 @@ import (
 	"k8s.io/client-go/tools/portforward"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	cloudv1 "sigs.k8s.io/cluster-api/test/infrastructure/inmemory/internal/cloud/api/v1alpha1"
 	cmanager "sigs.k8s.io/cluster-api/test/infrastructure/inmemory/internal/cloud/runtime/manager"
 	gportforward "sigs.k8s.io/cluster-api/test/infrastructure/inmemory/internal/server/api/portforward"
 )
@@ -69,6 +72,7 @@ func NewAPIServerHandler(manager cmanager.Manager, log logr.Logger, resolver Res
 	}
 
 	apiServer.container.Filter(apiServer.globalLogging)
+	apiServer.container.Filter(apiServer.faultInjection)
 
 	ws := new(restful.WebService)
 	ws.Consumes(runtime.ContentTypeJSON)
@@ -173,6 +177,38 @@ func (h *apiServerHandler) globalLogging(req *restful.Request, resp *restful.Res
 	chain.ProcessFilter(req, resp)
 }
 
+// faultInjection simulates API server instances failing requests. Requests are distributed randomly across the API server
+// instances of a workload cluster, like a load balancer would do, and requests served by an API server instance
+// marked as unavailable fail with 500 Internal Server Error.
+func (h *apiServerHandler) faultInjection(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
+	resourceGroup, err := h.resourceGroupResolver(req.Request.Host)
+	if err != nil {
+		chain.ProcessFilter(req, resp)
+		return
+	}
+
+	cloudClient := h.manager.GetResourceGroup(resourceGroup).GetClient()
+	apiServerPods := &corev1.PodList{}
+	if err := cloudClient.List(req.Request.Context(), apiServerPods,
+		client.InNamespace(metav1.NamespaceSystem),
+		client.MatchingLabels{
+			"component": "kube-apiserver",
+			"tier":      "control-plane"},
+	); err != nil || len(apiServerPods.Items) == 0 {
+		chain.ProcessFilter(req, resp)
+		return
+	}
+
+	apiServerPod := apiServerPods.Items[rand.Intn(len(apiServerPods.Items))] //nolint:gosec // Intentionally using a weak random number generator here.
+	if _, ok := apiServerPod.Annotations[cloudv1.APIServerUnavailableAnnotationName]; ok {
+		h.log.V(4).Info("Failing request served by unavailable API server", "method", req.Request.Method, "url", req.Request.URL, "pod", apiServerPod.Name)
+		_ = resp.WriteErrorString(http.StatusInternalServerError, fmt.Sprintf("API server %s is unavailable", apiServerPod.Name))
+		return
+	}
+
+	chain.ProcessFilter(req, resp)
+}
+
 // cleanDryRun gets dryrun from a URL.
 // Note: This is a copy of k8s.io/apiserver/pkg/endpoints/metrics.cleanDryRun.
 func cleanDryRun(u *url.URL) string {
@@ -312,6 +312,11 @@ func (b *baseServer) inspectEtcd(ctx context.Context, cloudClient cclient.Client
 			}
 			continue
 		}
+		if _, ok := pod.Annotations[cloudv1.EtcdUnavailableAnnotationName]; ok {
+			if pod.Name == fmt.Sprintf("%s%s", "etcd-", etcdMember) {
+				return nil, nil, errors.New("etcdserver: request timed out")
+			}
+		}
 		if clusterID == 0 {
 			var err error
 			clusterID, err = strconv.Atoi(pod.Annotations[cloudv1.EtcdClusterIDAnnotationName])
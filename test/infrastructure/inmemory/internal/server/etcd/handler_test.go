@@ -119,3 +119,51 @@ func Test_etcd_scalingflow(t *testing.T) {
 		g.Expect(members.GetMembers()).NotTo(ContainElement(fmt.Sprintf("etcd-%d", etcdMemberToRemove)))
 	})
 }
+
+func Test_etcd_unavailableMember(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	g := NewWithT(t)
+	ctx := context.Background()
+	manager := manager.New(scheme)
+	b := &baseServer{
+		log:                   log.FromContext(ctx),
+		manager:               manager,
+		resourceGroupResolver: func(host string) (string, error) { return "group1", nil },
+	}
+	b.manager.AddResourceGroup("group1")
+	cloudClient := b.manager.GetResourceGroup("group1").GetClient()
+
+	for i := 1; i <= 2; i++ {
+		etcdPod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: metav1.NamespaceSystem,
+				Name:      fmt.Sprintf("etcd-%d", i),
+				Labels: map[string]string{
+					"component": "etcd",
+					"tier":      "control-plane",
+				},
+				Annotations: map[string]string{
+					cloudv1.EtcdMemberIDAnnotationName:   fmt.Sprintf("%d", i),
+					cloudv1.EtcdClusterIDAnnotationName:  "15",
+					cloudv1.EtcdLeaderFromAnnotationName: time.Date(2020, 07, 03, 14, 25, 58, 651387237, time.UTC).Format(time.RFC3339),
+				},
+			},
+		}
+		// Make `etcd-2` unavailable.
+		if i == 2 {
+			etcdPod.Annotations[cloudv1.EtcdUnavailableAnnotationName] = ""
+		}
+		g.Expect(cloudClient.Create(ctx, etcdPod)).To(Succeed())
+	}
+
+	// Expect the inspect call to fail on the unavailable member.
+	_, _, err := b.inspectEtcd(ctx, cloudClient, "2")
+	g.Expect(err).To(HaveOccurred())
+
+	// Expect the inspect call to succeed on other members, and the unavailable member to be still part of the cluster.
+	members, _, err := b.inspectEtcd(ctx, cloudClient, "1")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(members.GetMembers()).To(HaveLen(2))
+}
@@ -45,6 +45,7 @@ import (
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controllers/remote"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api/feature"
 	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/inmemory/api/v1alpha1"
 	"sigs.k8s.io/cluster-api/test/infrastructure/inmemory/controllers"
@@ -72,6 +73,7 @@ var (
 	enableContentionProfiling   bool
 	clusterConcurrency          int
 	machineConcurrency          int
+	machinePoolConcurrency      int
 	syncPeriod                  time.Duration
 	restConfigQPS               float32
 	restConfigBurst             int
@@ -79,6 +81,7 @@ var (
 	webhookCertDir              string
 	healthAddr                  string
 	tlsOptions                  = flags.TLSOptions{}
+	latencyProfile              = controllers.LatencyProfile{}
 	logOptions                  = logs.NewOptions()
 )
 
@@ -86,6 +89,7 @@ func init() {
 	// scheme used for operating on the management cluster.
 	_ = clientgoscheme.AddToScheme(scheme)
 	_ = clusterv1.AddToScheme(scheme)
+	_ = expv1.AddToScheme(scheme)
 	_ = infrav1.AddToScheme(scheme)
 
 	// scheme used for operating on the cloud resource.
@@ -132,6 +136,9 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&machineConcurrency, "machine-concurrency", 10,
 		"Number of machines to process simultaneously")
 
+	fs.IntVar(&machinePoolConcurrency, "machinepool-concurrency", 10,
+		"Number of machine pools to process simultaneously")
+
 	fs.DurationVar(&syncPeriod, "sync-period", 10*time.Minute,
 		"The minimum interval at which watched resources are reconciled (e.g. 15m)")
 
@@ -152,6 +159,13 @@ func InitFlags(fs *pflag.FlagSet) {
 
 	flags.AddTLSOptions(fs, &tlsOptions)
 
+	addLatencyFlags(fs, "vm-provisioning", "the provisioning of a VM", &latencyProfile.VMProvisioning)
+	addLatencyFlags(fs, "node-provisioning", "the provisioning of a Node, after the VM is provisioned", &latencyProfile.NodeProvisioning)
+	addLatencyFlags(fs, "node-ready", "a Node becoming ready, after the Node is provisioned", &latencyProfile.NodeReady)
+	addLatencyFlags(fs, "etcd-provisioning", "the provisioning of an etcd member, after the Node is provisioned", &latencyProfile.EtcdProvisioning)
+	addLatencyFlags(fs, "apiserver-provisioning", "the provisioning of an API server, after the Node is provisioned", &latencyProfile.APIServerProvisioning)
+	addLatencyFlags(fs, "vm-deletion", "the deletion of a VM", &latencyProfile.VMDeletion)
+
 	feature.MutableGates.AddFlag(fs)
 }
 
@@ -289,11 +303,24 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 		Client:           mgr.GetClient(),
 		CloudManager:     cloudMgr,
 		APIServerMux:     apiServerMux,
+		LatencyProfile:   latencyProfile,
 		WatchFilterValue: watchFilterValue,
 	}).SetupWithManager(ctx, mgr, concurrency(machineConcurrency)); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "InMemoryMachine")
 		os.Exit(1)
 	}
+
+	if feature.Gates.Enabled(feature.MachinePool) {
+		if err := (&controllers.InMemoryMachinePoolReconciler{
+			Client:           mgr.GetClient(),
+			CloudManager:     cloudMgr,
+			LatencyProfile:   latencyProfile,
+			WatchFilterValue: watchFilterValue,
+		}).SetupWithManager(ctx, mgr, concurrency(machinePoolConcurrency)); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "InMemoryMachinePool")
+			os.Exit(1)
+		}
+	}
 }
 
 func setupWebhooks(mgr ctrl.Manager) {
@@ -312,12 +339,27 @@ func setupWebhooks(mgr ctrl.Manager) {
 		os.Exit(1)
 	}
 
+	if err := (&infrav1.InMemoryMachinePool{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "InMemoryMachinePool")
+		os.Exit(1)
+	}
+
 	if err := (&infrav1.InMemoryMachineTemplate{}).SetupWebhookWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "InMemoryMachineTemplate")
 		os.Exit(1)
 	}
 }
 
+// addLatencyFlags adds the flags defining the latency of a simulated operation.
+// NOTE: Provisioning latencies apply only to machines not defining the corresponding behaviour.
+func addLatencyFlags(fs *pflag.FlagSet, name, operation string, latency *controllers.Latency) {
+	fs.DurationVar(&latency.Duration, fmt.Sprintf("%s-latency", name), 0,
+		fmt.Sprintf("The time required for %s (e.g. 30s)", operation))
+
+	fs.Float64Var(&latency.Jitter, fmt.Sprintf("%s-jitter", name), 0,
+		fmt.Sprintf("Adds a random amount between zero and jitter*latency to the time required for %s (e.g. 0.2)", operation))
+}
+
 func concurrency(c int) controller.Options {
 	return controller.Options{MaxConcurrentReconciles: c}
 }
@@ -43,6 +43,46 @@ func HasPaused(o metav1.Object) bool {
 	return hasAnnotation(o, clusterv1.PausedAnnotation)
 }
 
+// HasPausedByCluster returns true if the object has the `paused-by-cluster` annotation.
+func HasPausedByCluster(o metav1.Object) bool {
+	return hasAnnotation(o, clusterv1.PausedByClusterAnnotation)
+}
+
+// SyncPausedFromCluster makes the `paused` annotation of the object reflect whether the Cluster is paused, and
+// returns true if the annotations have changed.
+// When the Cluster is paused, the `paused` annotation is added together with the `paused-by-cluster` annotation;
+// once the Cluster is unpaused both annotations are removed, while a `paused` annotation which was already set
+// when the Cluster was paused, e.g. by a user, is preserved.
+func SyncPausedFromCluster(cluster *clusterv1.Cluster, o metav1.Object) bool {
+	// NOTE: annotations are always set back on the object, because GetAnnotations returns a copy for unstructured objects.
+	annotations := o.GetAnnotations()
+	if cluster.Spec.Paused {
+		if HasPaused(o) {
+			return false
+		}
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[clusterv1.PausedAnnotation] = ""
+		annotations[clusterv1.PausedByClusterAnnotation] = ""
+		o.SetAnnotations(annotations)
+		return true
+	}
+
+	if !HasPausedByCluster(o) {
+		return false
+	}
+	delete(annotations, clusterv1.PausedAnnotation)
+	delete(annotations, clusterv1.PausedByClusterAnnotation)
+	o.SetAnnotations(annotations)
+	return true
+}
+
+// HasIgnoreMaintenanceWindows returns true if the object has the `ignore-maintenance-windows` annotation.
+func HasIgnoreMaintenanceWindows(o metav1.Object) bool {
+	return hasAnnotation(o, clusterv1.IgnoreMaintenanceWindowsAnnotation)
+}
+
 // HasSkipRemediation returns true if the object has the `skip-remediation` annotation.
 func HasSkipRemediation(o metav1.Object) bool {
 	return hasAnnotation(o, clusterv1.MachineSkipRemediationAnnotation)
@@ -22,6 +22,9 @@ import (
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
 func TestAddAnnotations(t *testing.T) {
@@ -243,3 +246,91 @@ func TestHasTruthyAnnotationValue(t *testing.T) {
 		})
 	}
 }
+
+func TestSyncPausedFromCluster(t *testing.T) {
+	tests := []struct {
+		name          string
+		clusterPaused bool
+		annotations   map[string]string
+		expected      map[string]string
+		changed       bool
+	}{
+		{
+			name:          "paused cluster, object without annotations",
+			clusterPaused: true,
+			annotations:   nil,
+			expected: map[string]string{
+				clusterv1.PausedAnnotation:          "",
+				clusterv1.PausedByClusterAnnotation: "",
+			},
+			changed: true,
+		},
+		{
+			name:          "paused cluster, object already paused by the cluster",
+			clusterPaused: true,
+			annotations: map[string]string{
+				clusterv1.PausedAnnotation:          "",
+				clusterv1.PausedByClusterAnnotation: "",
+			},
+			expected: map[string]string{
+				clusterv1.PausedAnnotation:          "",
+				clusterv1.PausedByClusterAnnotation: "",
+			},
+			changed: false,
+		},
+		{
+			name:          "paused cluster, object paused by a user",
+			clusterPaused: true,
+			annotations:   map[string]string{clusterv1.PausedAnnotation: "true"},
+			expected:      map[string]string{clusterv1.PausedAnnotation: "true"},
+			changed:       false,
+		},
+		{
+			name:          "unpaused cluster, object paused by the cluster",
+			clusterPaused: false,
+			annotations: map[string]string{
+				"foo":                               "bar",
+				clusterv1.PausedAnnotation:          "",
+				clusterv1.PausedByClusterAnnotation: "",
+			},
+			expected: map[string]string{"foo": "bar"},
+			changed:  true,
+		},
+		{
+			name:          "unpaused cluster, object paused by a user",
+			clusterPaused: false,
+			annotations:   map[string]string{clusterv1.PausedAnnotation: "true"},
+			expected:      map[string]string{clusterv1.PausedAnnotation: "true"},
+			changed:       false,
+		},
+		{
+			name:          "unpaused cluster, object without annotations",
+			clusterPaused: false,
+			annotations:   nil,
+			expected:      nil,
+			changed:       false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := &clusterv1.Cluster{Spec: clusterv1.ClusterSpec{Paused: tt.clusterPaused}}
+
+			// Typed and unstructured objects must behave the same.
+			unstructuredObj := &unstructured.Unstructured{}
+			unstructuredObj.SetAnnotations(tt.annotations)
+			for _, obj := range []metav1.Object{
+				&corev1.Node{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}},
+				unstructuredObj,
+			} {
+				g.Expect(SyncPausedFromCluster(cluster, obj)).To(Equal(tt.changed))
+				g.Expect(obj.GetAnnotations()).To(Equal(tt.expected))
+				g.Expect(IsPaused(cluster, obj)).To(Equal(tt.clusterPaused || HasPaused(obj)))
+
+				// Syncing again is a no-op.
+				g.Expect(SyncPausedFromCluster(cluster, obj)).To(BeFalse())
+			}
+		})
+	}
+}
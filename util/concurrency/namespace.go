@@ -0,0 +1,91 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package concurrency implements helpers to limit the concurrent reconciles of controllers.
+package concurrency
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// namespaceLimitRequeueAfter is the delay after which requests exceeding the limit of their namespace are requeued.
+const namespaceLimitRequeueAfter = time.Second
+
+// NewNamespaceLimitedReconciler returns a reconciler limiting the concurrent reconciles of objects of the same
+// namespace, so a single namespace, e.g. a tenant with a large churn of Clusters in a shared management cluster,
+// can't use all the workers of the controller.
+//
+// Requests exceeding the limit of their namespace are requeued without being reconciled, so the worker is available
+// for requests of other namespaces.
+// NOTE: If the limit is not greater than zero, the reconciler is returned as is.
+func NewNamespaceLimitedReconciler(r reconcile.Reconciler, limit int) reconcile.Reconciler {
+	if limit <= 0 {
+		return r
+	}
+	return &namespaceLimitedReconciler{
+		Reconciler: r,
+		limit:      limit,
+		inFlight:   map[string]int{},
+	}
+}
+
+type namespaceLimitedReconciler struct {
+	reconcile.Reconciler
+
+	limit int
+
+	lock sync.Mutex
+	// inFlight is the number of reconciles in progress for each namespace.
+	inFlight map[string]int
+}
+
+// Reconcile calls the wrapped reconciler if the limit of the namespace of the request is not reached,
+// otherwise it requeues the request.
+func (r *namespaceLimitedReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	if !r.acquire(req.Namespace) {
+		ctrl.LoggerFrom(ctx).V(4).Info("Requeuing, the maximum number of concurrent reconciles for the namespace has been reached", "limit", r.limit)
+		return ctrl.Result{RequeueAfter: namespaceLimitRequeueAfter}, nil
+	}
+	defer r.release(req.Namespace)
+
+	return r.Reconciler.Reconcile(ctx, req)
+}
+
+func (r *namespaceLimitedReconciler) acquire(namespace string) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.inFlight[namespace] >= r.limit {
+		return false
+	}
+	r.inFlight[namespace]++
+	return true
+}
+
+func (r *namespaceLimitedReconciler) release(namespace string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.inFlight[namespace]--
+	if r.inFlight[namespace] <= 0 {
+		delete(r.inFlight, namespace)
+	}
+}
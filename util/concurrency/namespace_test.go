@@ -0,0 +1,87 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package concurrency
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// blockingReconciler blocks each reconcile until a value is sent on release.
+type blockingReconciler struct {
+	started chan string
+	release chan struct{}
+}
+
+func (r *blockingReconciler) Reconcile(_ context.Context, req ctrl.Request) (ctrl.Result, error) {
+	r.started <- req.Namespace
+	<-r.release
+	return ctrl.Result{}, nil
+}
+
+func TestNewNamespaceLimitedReconciler(t *testing.T) {
+	ctx := context.Background()
+	request := func(namespace, name string) ctrl.Request {
+		return ctrl.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}}
+	}
+
+	t.Run("returns the reconciler as is without limit", func(t *testing.T) {
+		g := NewWithT(t)
+		inner := &blockingReconciler{}
+		g.Expect(NewNamespaceLimitedReconciler(inner, 0)).To(BeIdenticalTo(inner))
+	})
+
+	t.Run("requeues requests exceeding the limit of their namespace", func(t *testing.T) {
+		g := NewWithT(t)
+		inner := &blockingReconciler{started: make(chan string, 2), release: make(chan struct{})}
+		r := NewNamespaceLimitedReconciler(inner, 1)
+
+		done := make(chan struct{}, 2)
+		go func() {
+			_, _ = r.Reconcile(ctx, request("tenant-a", "cluster1"))
+			done <- struct{}{}
+		}()
+		g.Expect(<-inner.started).To(Equal("tenant-a"))
+
+		// A second request for the same namespace is requeued.
+		res, err := r.Reconcile(ctx, request("tenant-a", "cluster2"))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(res.RequeueAfter).To(Equal(namespaceLimitRequeueAfter))
+
+		// Requests for other namespaces are reconciled.
+		go func() {
+			_, _ = r.Reconcile(ctx, request("tenant-b", "cluster1"))
+			done <- struct{}{}
+		}()
+		g.Expect(<-inner.started).To(Equal("tenant-b"))
+
+		inner.release <- struct{}{}
+		inner.release <- struct{}{}
+		<-done
+		<-done
+
+		// Requests for the namespace are reconciled again once the reconciles in progress are completed.
+		go func() { inner.release <- struct{}{} }()
+		g.Expect(r.Reconcile(ctx, request("tenant-a", "cluster2"))).To(Equal(ctrl.Result{}))
+		g.Expect(<-inner.started).To(Equal("tenant-a"))
+		g.Expect(r.(*namespaceLimitedReconciler).inFlight).To(BeEmpty())
+	})
+}
@@ -17,6 +17,9 @@ limitations under the License.
 package conditions
 
 import (
+	"fmt"
+	"strings"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -192,6 +195,7 @@ type mirrorOptions struct {
 	fallbackReason   string
 	fallbackSeverity clusterv1.ConditionSeverity
 	fallbackMessage  string
+	summaryRows      []clusterv1.SummaryRow
 }
 
 // MirrorOptions defines an option for mirroring conditions.
@@ -208,6 +212,15 @@ func WithFallbackValue(fallbackValue bool, reason string, severity clusterv1.Con
 	}
 }
 
+// WithSummaryRows specifies the summary rows surfaced by the source object, e.g. in the status.summary field of
+// infrastructure objects; the rows are appended to the message of the target condition when it is not true, so
+// provider-specific information is visible in the conditions of Cluster API objects.
+func WithSummaryRows(rows []clusterv1.SummaryRow) MirrorOptions {
+	return func(c *mirrorOptions) {
+		c.summaryRows = rows
+	}
+}
+
 // mirror mirrors the Ready condition from a dependent object into the target condition;
 // if the Ready condition does not exists in the source object, no target conditions is generated.
 func mirror(from Getter, targetCondition clusterv1.ConditionType, options ...MirrorOptions) *clusterv1.Condition {
@@ -229,11 +242,27 @@ func mirror(from Getter, targetCondition clusterv1.ConditionType, options ...Mir
 
 	if condition != nil {
 		condition.Type = targetCondition
+		if condition.Status != corev1.ConditionTrue && len(mirrorOpt.summaryRows) > 0 {
+			condition = condition.DeepCopy()
+			condition.Message = appendSummaryRows(condition.Message, mirrorOpt.summaryRows)
+		}
 	}
 
 	return condition
 }
 
+// appendSummaryRows renders summary rows as a list of "key: value" pairs and appends them to the message.
+func appendSummaryRows(message string, rows []clusterv1.SummaryRow) string {
+	pairs := make([]string, 0, len(rows))
+	for _, row := range rows {
+		pairs = append(pairs, fmt.Sprintf("%s: %s", row.Key, row.Value))
+	}
+	if message == "" {
+		return strings.Join(pairs, ", ")
+	}
+	return fmt.Sprintf("%s (%s)", message, strings.Join(pairs, ", "))
+}
+
 // Aggregates all the Ready condition from a list of dependent objects into the target object;
 // if the Ready condition does not exists in one of the source object, the object is excluded from
 // the aggregation; if none of the source object have ready condition, no target conditions is generated.
@@ -95,11 +95,16 @@ func TestMirror(t *testing.T) {
 	readyBar := ready.DeepCopy()
 	readyBar.Type = "bar"
 
+	notReady := FalseCondition(clusterv1.ReadyCondition, "reason not ready", clusterv1.ConditionSeverityInfo, "message not ready")
+	notReadyBarWithSummary := FalseCondition("bar", "reason not ready", clusterv1.ConditionSeverityInfo, "message not ready (VPC ID: vpc-1, Zone: zone-a)")
+	summaryRows := []clusterv1.SummaryRow{{Key: "VPC ID", Value: "vpc-1"}, {Key: "Zone", Value: "zone-a"}}
+
 	tests := []struct {
-		name string
-		from Getter
-		t    clusterv1.ConditionType
-		want *clusterv1.Condition
+		name    string
+		from    Getter
+		t       clusterv1.ConditionType
+		options []MirrorOptions
+		want    *clusterv1.Condition
 	}{
 		{
 			name: "Returns nil when the ready condition does not exists",
@@ -112,13 +117,27 @@ func TestMirror(t *testing.T) {
 			t:    "bar",
 			want: readyBar,
 		},
+		{
+			name:    "Returns ready condition from source without summary rows when true",
+			from:    getterWithConditions(ready, foo),
+			t:       "bar",
+			options: []MirrorOptions{WithSummaryRows(summaryRows)},
+			want:    readyBar,
+		},
+		{
+			name:    "Returns ready condition from source with summary rows appended to the message when false",
+			from:    getterWithConditions(notReady, foo),
+			t:       "bar",
+			options: []MirrorOptions{WithSummaryRows(summaryRows)},
+			want:    notReadyBarWithSummary,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			g := NewWithT(t)
 
-			got := mirror(tt.from, tt.t)
+			got := mirror(tt.from, tt.t, tt.options...)
 			if tt.want == nil {
 				g.Expect(got).To(BeNil())
 				return
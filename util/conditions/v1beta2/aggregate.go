@@ -0,0 +1,117 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NewAggregateCondition aggregates the condition with the given type from the source objects into a new condition;
+// the source objects can implement either Getter or the v1beta1 conditions.Getter.
+//
+// Source conditions are grouped into issues, i.e. False conditions or True conditions with negative polarity,
+// unknown, i.e. Unknown conditions or conditions not yet reported, and info, i.e. all the other conditions.
+// The aggregate condition reports an issue if any source object reports an issue, otherwise it is Unknown if any
+// source object reports an unknown condition, otherwise it doesn't report an issue; the reason is the reason
+// of the source condition if the aggregate condition is derived from a single source object, otherwise it is
+// one of MultipleIssuesReportedReason, MultipleUnknownReportedReason, MultipleInfoReportedReason.
+// NOTE: The observedGeneration of the aggregate condition is set when setting the condition on the target object.
+func NewAggregateCondition(from []client.Object, sourceConditionType string, opts ...AggregateOption) (*metav1.Condition, error) {
+	if len(from) == 0 {
+		return nil, errors.New("at least one source object must be provided to aggregate conditions")
+	}
+
+	aggregateOpt := (&AggregateOptions{targetConditionType: sourceConditionType}).ApplyOptions(opts)
+	negativePolarity := sets.New[string](aggregateOpt.negativePolarityConditionTypes...).Has(sourceConditionType)
+
+	var issues, unknown, info []aggregatedCondition
+	for _, obj := range from {
+		condition := *NewMirrorCondition(obj, sourceConditionType)
+		switch {
+		case condition.Status == metav1.ConditionUnknown:
+			unknown = append(unknown, aggregatedCondition{obj: obj, condition: condition})
+		case (condition.Status == metav1.ConditionFalse) != negativePolarity:
+			issues = append(issues, aggregatedCondition{obj: obj, condition: condition})
+		default:
+			info = append(info, aggregatedCondition{obj: obj, condition: condition})
+		}
+	}
+
+	// Compute the status of the aggregate condition, taking the polarity into account.
+	status, group, multipleReason := metav1.ConditionTrue, info, MultipleInfoReportedReason
+	switch {
+	case len(issues) > 0:
+		status, group, multipleReason = metav1.ConditionFalse, issues, MultipleIssuesReportedReason
+	case len(unknown) > 0:
+		status, group, multipleReason = metav1.ConditionUnknown, unknown, MultipleUnknownReportedReason
+	}
+	if negativePolarity && status != metav1.ConditionUnknown {
+		if status == metav1.ConditionTrue {
+			status = metav1.ConditionFalse
+		} else {
+			status = metav1.ConditionTrue
+		}
+	}
+
+	reason := multipleReason
+	if len(group) == 1 {
+		reason = group[0].condition.Reason
+	}
+	return &metav1.Condition{
+		Type:    aggregateOpt.targetConditionType,
+		Status:  status,
+		Reason:  reason,
+		Message: aggregateMessage(group),
+	}, nil
+}
+
+// aggregatedCondition is a condition from a source object of an aggregate condition.
+type aggregatedCondition struct {
+	obj       client.Object
+	condition metav1.Condition
+}
+
+// aggregateMessage returns a message listing the source objects with the same message, e.g.
+// "* machine-1, machine-2: Machine is not ready".
+func aggregateMessage(group []aggregatedCondition) string {
+	if len(group) == 1 {
+		return group[0].condition.Message
+	}
+
+	objectsByMessage := map[string][]string{}
+	for _, c := range group {
+		if c.condition.Message == "" {
+			continue
+		}
+		objectsByMessage[c.condition.Message] = append(objectsByMessage[c.condition.Message], c.obj.GetName())
+	}
+
+	messages := make([]string, 0, len(objectsByMessage))
+	for message, names := range objectsByMessage {
+		sort.Strings(names)
+		messages = append(messages, fmt.Sprintf("* %s: %s", strings.Join(names, ", "), message))
+	}
+	sort.Strings(messages)
+	return strings.Join(messages, "\n")
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestNewAggregateCondition(t *testing.T) {
+	v1beta1Machine := &clusterv1.Machine{}
+	v1beta1Machine.SetName("machine-3")
+	conditions.MarkFalse(v1beta1Machine, clusterv1.ReadyCondition, "NotReady", clusterv1.ConditionSeverityWarning, "not ready")
+
+	tests := []struct {
+		name          string
+		conditionType string
+		from          []client.Object
+		opts          []AggregateOption
+		want          *metav1.Condition
+		wantErr       bool
+	}{
+		{
+			name:          "error without source objects",
+			conditionType: "Ready",
+			from:          []client.Object{},
+			wantErr:       true,
+		},
+		{
+			name:          "single source object",
+			conditionType: "Ready",
+			from: []client.Object{
+				objectWith("machine-1", metav1.Condition{Type: "Ready", Status: metav1.ConditionTrue, Reason: "Ready", Message: "ready"}),
+			},
+			want: &metav1.Condition{Type: "Ready", Status: metav1.ConditionTrue, Reason: "Ready", Message: "ready"},
+		},
+		{
+			name:          "multiple source objects without issues",
+			conditionType: "Ready",
+			from: []client.Object{
+				objectWith("machine-1", metav1.Condition{Type: "Ready", Status: metav1.ConditionTrue, Reason: "Ready", Message: "ready"}),
+				objectWith("machine-2", metav1.Condition{Type: "Ready", Status: metav1.ConditionTrue, Reason: "Ready", Message: "ready"}),
+			},
+			opts: []AggregateOption{TargetConditionType("MachinesReady")},
+			want: &metav1.Condition{Type: "MachinesReady", Status: metav1.ConditionTrue, Reason: MultipleInfoReportedReason, Message: "* machine-1, machine-2: ready"},
+		},
+		{
+			name:          "issues take precedence over unknown conditions, including v1beta1 conditions",
+			conditionType: "Ready",
+			from: []client.Object{
+				objectWith("machine-1", metav1.Condition{Type: "Ready", Status: metav1.ConditionFalse, Reason: "NotReady", Message: "not ready"}),
+				objectWith("machine-2"),
+				v1beta1Machine,
+			},
+			want: &metav1.Condition{Type: "Ready", Status: metav1.ConditionFalse, Reason: MultipleIssuesReportedReason, Message: "* machine-1, machine-3: not ready"},
+		},
+		{
+			name:          "unknown conditions take precedence over conditions without issues",
+			conditionType: "Ready",
+			from: []client.Object{
+				objectWith("machine-1", metav1.Condition{Type: "Ready", Status: metav1.ConditionTrue, Reason: "Ready"}),
+				objectWith("machine-2"),
+			},
+			want: &metav1.Condition{Type: "Ready", Status: metav1.ConditionUnknown, Reason: NotYetReportedReason, Message: "Condition Ready not yet reported"},
+		},
+		{
+			name:          "negative polarity conditions report issues when True",
+			conditionType: "Deleting",
+			from: []client.Object{
+				objectWith("machine-1", metav1.Condition{Type: "Deleting", Status: metav1.ConditionTrue, Reason: "Deleting", Message: "deleting"}),
+				objectWith("machine-2", metav1.Condition{Type: "Deleting", Status: metav1.ConditionFalse, Reason: "NotDeleting"}),
+			},
+			opts: []AggregateOption{NegativePolarityConditionTypes{"Deleting"}},
+			want: &metav1.Condition{Type: "Deleting", Status: metav1.ConditionTrue, Reason: "Deleting", Message: "deleting"},
+		},
+		{
+			name:          "negative polarity conditions without issues are False",
+			conditionType: "Deleting",
+			from: []client.Object{
+				objectWith("machine-1", metav1.Condition{Type: "Deleting", Status: metav1.ConditionFalse, Reason: "NotDeleting"}),
+				objectWith("machine-2", metav1.Condition{Type: "Deleting", Status: metav1.ConditionFalse, Reason: "NotDeleting"}),
+			},
+			opts: []AggregateOption{NegativePolarityConditionTypes{"Deleting"}},
+			want: &metav1.Condition{Type: "Deleting", Status: metav1.ConditionFalse, Reason: MultipleInfoReportedReason},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := NewAggregateCondition(tt.from, tt.conditionType, tt.opts...)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,22 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package v1beta2 implements utilities for conditions using metav1.Condition, with support for
+// observedGeneration and negative polarity conditions.
+//
+// Conditions can be mirrored or aggregated from objects using either metav1.Condition or the Cluster API
+// v1beta1 conditions, so providers can migrate their types incrementally.
+package v1beta2
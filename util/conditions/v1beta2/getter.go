@@ -0,0 +1,140 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// Getter interface defines methods that an object should implement in order to
+// use the v1beta2 conditions package for getting conditions.
+type Getter interface {
+	client.Object
+
+	// GetV1Beta2Conditions returns the list of conditions for an object.
+	GetV1Beta2Conditions() []metav1.Condition
+}
+
+// Get returns the condition with the given type, if the condition does not exist,
+// it returns nil.
+func Get(from Getter, conditionType string) *metav1.Condition {
+	return meta.FindStatusCondition(from.GetV1Beta2Conditions(), conditionType)
+}
+
+// Has returns true if a condition with the given type exists.
+func Has(from Getter, conditionType string) bool {
+	return Get(from, conditionType) != nil
+}
+
+// IsTrue is true if the condition with the given type is True, otherwise it returns false
+// if the condition is not True or if the condition does not exist.
+func IsTrue(from Getter, conditionType string) bool {
+	if c := Get(from, conditionType); c != nil {
+		return c.Status == metav1.ConditionTrue
+	}
+	return false
+}
+
+// IsFalse is true if the condition with the given type is False, otherwise it returns false
+// if the condition is not False or if the condition does not exist.
+func IsFalse(from Getter, conditionType string) bool {
+	if c := Get(from, conditionType); c != nil {
+		return c.Status == metav1.ConditionFalse
+	}
+	return false
+}
+
+// IsUnknown is true if the condition with the given type is Unknown or if the condition
+// does not exist.
+func IsUnknown(from Getter, conditionType string) bool {
+	if c := Get(from, conditionType); c != nil {
+		return c.Status == metav1.ConditionUnknown
+	}
+	return true
+}
+
+// GetReason returns a nil safe string of Reason for the condition with the given type.
+func GetReason(from Getter, conditionType string) string {
+	if c := Get(from, conditionType); c != nil {
+		return c.Reason
+	}
+	return ""
+}
+
+// GetMessage returns a nil safe string of Message for the condition with the given type.
+func GetMessage(from Getter, conditionType string) string {
+	if c := Get(from, conditionType); c != nil {
+		return c.Message
+	}
+	return ""
+}
+
+// IsUpToDate returns true if the condition with the given type has been observed
+// for the current generation of the object.
+func IsUpToDate(from Getter, conditionType string) bool {
+	if c := Get(from, conditionType); c != nil {
+		return c.ObservedGeneration == from.GetGeneration()
+	}
+	return false
+}
+
+// getCondition returns the condition with the given type from an object implementing either Getter or
+// the v1beta1 conditions.Getter; v1beta1 conditions are converted to metav1.Condition.
+func getCondition(from client.Object, conditionType string) *metav1.Condition {
+	switch obj := from.(type) {
+	case Getter:
+		return Get(obj, conditionType)
+	case conditions.Getter:
+		c := conditions.Get(obj, clusterv1.ConditionType(conditionType))
+		if c == nil {
+			return nil
+		}
+		converted := FromV1Beta1(*c)
+		return &converted
+	default:
+		return nil
+	}
+}
+
+// FromV1Beta1 converts a v1beta1 condition to a metav1.Condition; the severity is dropped and, given that
+// the reason is required for metav1.Condition, conditions without a reason get NoReasonReportedReason.
+func FromV1Beta1(c clusterv1.Condition) metav1.Condition {
+	reason := c.Reason
+	if reason == "" {
+		reason = NoReasonReportedReason
+	}
+	status := metav1.ConditionUnknown
+	switch c.Status {
+	case corev1.ConditionTrue:
+		status = metav1.ConditionTrue
+	case corev1.ConditionFalse:
+		status = metav1.ConditionFalse
+	}
+	return metav1.Condition{
+		Type:               string(c.Type),
+		Status:             status,
+		LastTransitionTime: c.LastTransitionTime,
+		Reason:             reason,
+		Message:            c.Message,
+	}
+}
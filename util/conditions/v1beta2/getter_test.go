@@ -0,0 +1,130 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// testObject is an object using metav1.Condition.
+type testObject struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+	Conditions []metav1.Condition
+}
+
+func (o *testObject) GetV1Beta2Conditions() []metav1.Condition {
+	return o.Conditions
+}
+
+func (o *testObject) SetV1Beta2Conditions(conditions []metav1.Condition) {
+	o.Conditions = conditions
+}
+
+func (o *testObject) DeepCopyObject() runtime.Object {
+	out := &testObject{TypeMeta: o.TypeMeta}
+	o.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	for i := range o.Conditions {
+		out.Conditions = append(out.Conditions, *o.Conditions[i].DeepCopy())
+	}
+	return out
+}
+
+func objectWith(name string, conditions ...metav1.Condition) *testObject {
+	return &testObject{ObjectMeta: metav1.ObjectMeta{Name: name, Generation: 1}, Conditions: conditions}
+}
+
+func TestGetAndHas(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := objectWith("obj", metav1.Condition{Type: "Available", Status: metav1.ConditionTrue, Reason: "Available", Message: "all good", ObservedGeneration: 1})
+
+	g.Expect(Has(obj, "Available")).To(BeTrue())
+	g.Expect(Has(obj, "Deleting")).To(BeFalse())
+	g.Expect(Get(obj, "Available")).To(Equal(&obj.Conditions[0]))
+	g.Expect(Get(obj, "Deleting")).To(BeNil())
+	g.Expect(GetReason(obj, "Available")).To(Equal("Available"))
+	g.Expect(GetReason(obj, "Deleting")).To(BeEmpty())
+	g.Expect(GetMessage(obj, "Available")).To(Equal("all good"))
+	g.Expect(GetMessage(obj, "Deleting")).To(BeEmpty())
+}
+
+func TestIsMethods(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := objectWith("obj",
+		metav1.Condition{Type: "True", Status: metav1.ConditionTrue, Reason: "Reason"},
+		metav1.Condition{Type: "False", Status: metav1.ConditionFalse, Reason: "Reason"},
+		metav1.Condition{Type: "Unknown", Status: metav1.ConditionUnknown, Reason: "Reason"},
+	)
+
+	g.Expect(IsTrue(obj, "True")).To(BeTrue())
+	g.Expect(IsTrue(obj, "False")).To(BeFalse())
+	g.Expect(IsTrue(obj, "Missing")).To(BeFalse())
+
+	g.Expect(IsFalse(obj, "False")).To(BeTrue())
+	g.Expect(IsFalse(obj, "True")).To(BeFalse())
+	g.Expect(IsFalse(obj, "Missing")).To(BeFalse())
+
+	g.Expect(IsUnknown(obj, "Unknown")).To(BeTrue())
+	g.Expect(IsUnknown(obj, "True")).To(BeFalse())
+	g.Expect(IsUnknown(obj, "Missing")).To(BeTrue())
+}
+
+func TestIsUpToDate(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := objectWith("obj",
+		metav1.Condition{Type: "UpToDate", Status: metav1.ConditionTrue, Reason: "Reason", ObservedGeneration: 2},
+		metav1.Condition{Type: "Stale", Status: metav1.ConditionTrue, Reason: "Reason", ObservedGeneration: 1},
+	)
+	obj.Generation = 2
+
+	g.Expect(IsUpToDate(obj, "UpToDate")).To(BeTrue())
+	g.Expect(IsUpToDate(obj, "Stale")).To(BeFalse())
+	g.Expect(IsUpToDate(obj, "Missing")).To(BeFalse())
+}
+
+func TestFromV1Beta1(t *testing.T) {
+	g := NewWithT(t)
+
+	now := metav1.Now()
+	g.Expect(FromV1Beta1(clusterv1.Condition{
+		Type:               clusterv1.ReadyCondition,
+		Status:             corev1.ConditionFalse,
+		Severity:           clusterv1.ConditionSeverityWarning,
+		LastTransitionTime: now,
+		Reason:             "NotReady",
+		Message:            "not ready",
+	})).To(Equal(metav1.Condition{
+		Type:               "Ready",
+		Status:             metav1.ConditionFalse,
+		LastTransitionTime: now,
+		Reason:             "NotReady",
+		Message:            "not ready",
+	}))
+
+	// v1beta1 conditions without a reason get a default reason.
+	g.Expect(FromV1Beta1(clusterv1.Condition{Type: clusterv1.ReadyCondition, Status: corev1.ConditionTrue}).Reason).To(Equal(NoReasonReportedReason))
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NewMirrorCondition creates a mirror of the given condition from the source object; the source object can
+// implement either Getter or the v1beta1 conditions.Getter.
+//
+// The mirror condition has the status, reason and message of the source condition; if the source condition
+// doesn't exist, or the source object doesn't implement any of the getters, the mirror condition is Unknown
+// with NotYetReportedReason.
+// NOTE: The observedGeneration of the mirror condition is set when setting the condition on the target object.
+func NewMirrorCondition(from client.Object, sourceConditionType string, opts ...MirrorOption) *metav1.Condition {
+	mirrorOpt := (&MirrorOptions{targetConditionType: sourceConditionType}).ApplyOptions(opts)
+
+	condition := getCondition(from, sourceConditionType)
+	if condition == nil {
+		return &metav1.Condition{
+			Type:    mirrorOpt.targetConditionType,
+			Status:  metav1.ConditionUnknown,
+			Reason:  NotYetReportedReason,
+			Message: fmt.Sprintf("Condition %s not yet reported", sourceConditionType),
+		}
+	}
+
+	reason := condition.Reason
+	if reason == "" {
+		reason = NoReasonReportedReason
+	}
+	return &metav1.Condition{
+		Type:    mirrorOpt.targetConditionType,
+		Status:  condition.Status,
+		Reason:  reason,
+		Message: condition.Message,
+	}
+}
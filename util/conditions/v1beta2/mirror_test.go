@@ -0,0 +1,70 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestNewMirrorCondition(t *testing.T) {
+	g := NewWithT(t)
+
+	t.Log("Mirroring a condition from an object using metav1.Condition")
+	from := objectWith("machine", metav1.Condition{Type: "Ready", Status: metav1.ConditionFalse, Reason: "NotReady", Message: "not ready", ObservedGeneration: 1})
+	g.Expect(NewMirrorCondition(from, "Ready", TargetConditionType("MachineReady"))).To(Equal(&metav1.Condition{
+		Type:    "MachineReady",
+		Status:  metav1.ConditionFalse,
+		Reason:  "NotReady",
+		Message: "not ready",
+	}))
+
+	t.Log("Mirroring a condition from an object using v1beta1 conditions")
+	v1beta1From := &clusterv1.Machine{}
+	conditions.MarkFalse(v1beta1From, clusterv1.ReadyCondition, "NotReady", clusterv1.ConditionSeverityWarning, "not ready")
+	g.Expect(NewMirrorCondition(v1beta1From, "Ready")).To(Equal(&metav1.Condition{
+		Type:    "Ready",
+		Status:  metav1.ConditionFalse,
+		Reason:  "NotReady",
+		Message: "not ready",
+	}))
+
+	t.Log("Mirroring a condition not yet reported")
+	g.Expect(NewMirrorCondition(objectWith("machine"), "Ready")).To(Equal(&metav1.Condition{
+		Type:    "Ready",
+		Status:  metav1.ConditionUnknown,
+		Reason:  NotYetReportedReason,
+		Message: "Condition Ready not yet reported",
+	}))
+}
+
+func TestSetMirrorCondition(t *testing.T) {
+	g := NewWithT(t)
+
+	from := objectWith("machine", metav1.Condition{Type: "Ready", Status: metav1.ConditionTrue, Reason: "Ready"})
+	to := objectWith("machineset")
+	to.Generation = 2
+
+	SetMirrorCondition(from, to, "Ready", TargetConditionType("MachineReady"))
+	g.Expect(IsTrue(to, "MachineReady")).To(BeTrue())
+	g.Expect(IsUpToDate(to, "MachineReady")).To(BeTrue())
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+const (
+	// NotYetReportedReason is set on a mirror or aggregate condition when the source condition is not yet reported.
+	NotYetReportedReason = "NotYetReported"
+
+	// NoReasonReportedReason is set on conditions converted from v1beta1 conditions without a reason.
+	NoReasonReportedReason = "NoReasonReported"
+
+	// MultipleIssuesReportedReason is set on an aggregate condition when more than one source object reports an issue.
+	MultipleIssuesReportedReason = "MultipleIssuesReported"
+
+	// MultipleUnknownReportedReason is set on an aggregate condition when more than one source object reports
+	// an Unknown condition, and no source object reports an issue.
+	MultipleUnknownReportedReason = "MultipleUnknownReported"
+
+	// MultipleInfoReportedReason is set on an aggregate condition when more than one source object reports
+	// a condition without issues.
+	MultipleInfoReportedReason = "MultipleInfoReported"
+)
+
+// MirrorOption is some configuration that modifies options for a mirror call.
+type MirrorOption interface {
+	// ApplyToMirror applies this configuration to the given mirror options.
+	ApplyToMirror(*MirrorOptions)
+}
+
+// MirrorOptions allows to set options for the mirror operation.
+type MirrorOptions struct {
+	targetConditionType string
+}
+
+// ApplyOptions applies the given list options on these options,
+// and then returns itself (for convenient chaining).
+func (o *MirrorOptions) ApplyOptions(opts []MirrorOption) *MirrorOptions {
+	for _, opt := range opts {
+		opt.ApplyToMirror(o)
+	}
+	return o
+}
+
+// AggregateOption is some configuration that modifies options for an aggregate call.
+type AggregateOption interface {
+	// ApplyToAggregate applies this configuration to the given aggregate options.
+	ApplyToAggregate(*AggregateOptions)
+}
+
+// AggregateOptions allows to set options for the aggregate operation.
+type AggregateOptions struct {
+	targetConditionType            string
+	negativePolarityConditionTypes []string
+}
+
+// ApplyOptions applies the given list options on these options,
+// and then returns itself (for convenient chaining).
+func (o *AggregateOptions) ApplyOptions(opts []AggregateOption) *AggregateOptions {
+	for _, opt := range opts {
+		opt.ApplyToAggregate(o)
+	}
+	return o
+}
+
+// TargetConditionType allows to specify the type of new mirror or aggregate conditions;
+// if not set, the type of the source condition is used.
+type TargetConditionType string
+
+// ApplyToMirror applies this configuration to the given mirror options.
+func (t TargetConditionType) ApplyToMirror(opts *MirrorOptions) {
+	opts.targetConditionType = string(t)
+}
+
+// ApplyToAggregate applies this configuration to the given aggregate options.
+func (t TargetConditionType) ApplyToAggregate(opts *AggregateOptions) {
+	opts.targetConditionType = string(t)
+}
+
+// NegativePolarityConditionTypes allows to specify the types of the conditions with negative polarity,
+// i.e. conditions reporting an issue when True, e.g. a Deleting or a Paused condition.
+// An aggregate condition has negative polarity if the source condition has negative polarity.
+type NegativePolarityConditionTypes []string
+
+// ApplyToAggregate applies this configuration to the given aggregate options.
+func (t NegativePolarityConditionTypes) ApplyToAggregate(opts *AggregateOptions) {
+	opts.negativePolarityConditionTypes = t
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Setter interface defines methods that an object should implement in order to
+// use the v1beta2 conditions package for setting conditions.
+type Setter interface {
+	Getter
+
+	// SetV1Beta2Conditions sets the list of conditions for an object.
+	SetV1Beta2Conditions([]metav1.Condition)
+}
+
+// Set sets the given condition, setting its observedGeneration to the generation of the object.
+//
+// NOTE: If a condition already exists, the LastTransitionTime is updated only if a change is detected
+// in the condition status; if the LastTransitionTime of the given condition is not set, it defaults to now.
+func Set(to Setter, condition metav1.Condition) {
+	condition.ObservedGeneration = to.GetGeneration()
+
+	conditions := to.GetV1Beta2Conditions()
+	meta.SetStatusCondition(&conditions, condition)
+	to.SetV1Beta2Conditions(conditions)
+}
+
+// SetMirrorCondition is a convenience method that calls NewMirrorCondition to create a mirror condition from
+// the source object, and then calls Set to add the new condition to the target object.
+func SetMirrorCondition(from client.Object, to Setter, sourceConditionType string, opts ...MirrorOption) {
+	Set(to, *NewMirrorCondition(from, sourceConditionType, opts...))
+}
+
+// SetAggregateCondition is a convenience method that calls NewAggregateCondition to create an aggregate condition
+// from the source objects, and then calls Set to add the new condition to the target object.
+func SetAggregateCondition(from []client.Object, to Setter, sourceConditionType string, opts ...AggregateOption) error {
+	aggregateCondition, err := NewAggregateCondition(from, sourceConditionType, opts...)
+	if err != nil {
+		return err
+	}
+	Set(to, *aggregateCondition)
+	return nil
+}
+
+// Delete deletes the condition with the given type.
+func Delete(to Setter, conditionType string) {
+	conditions := to.GetV1Beta2Conditions()
+	meta.RemoveStatusCondition(&conditions, conditionType)
+	to.SetV1Beta2Conditions(conditions)
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSet(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := objectWith("obj")
+	obj.Generation = 3
+
+	t.Log("Adding a condition sets the observedGeneration and the lastTransitionTime")
+	Set(obj, metav1.Condition{Type: "Available", Status: metav1.ConditionFalse, Reason: "NotAvailable"})
+	available := Get(obj, "Available")
+	g.Expect(available).ToNot(BeNil())
+	g.Expect(available.ObservedGeneration).To(Equal(int64(3)))
+	g.Expect(available.LastTransitionTime.IsZero()).To(BeFalse())
+
+	t.Log("Updating a condition without status changes preserves the lastTransitionTime")
+	lastTransitionTime := metav1.NewTime(available.LastTransitionTime.Add(-time.Hour))
+	obj.Conditions[0].LastTransitionTime = lastTransitionTime
+	obj.Generation = 4
+	Set(obj, metav1.Condition{Type: "Available", Status: metav1.ConditionFalse, Reason: "StillNotAvailable"})
+	available = Get(obj, "Available")
+	g.Expect(available.Reason).To(Equal("StillNotAvailable"))
+	g.Expect(available.ObservedGeneration).To(Equal(int64(4)))
+	g.Expect(available.LastTransitionTime).To(Equal(lastTransitionTime))
+
+	t.Log("Updating a condition with status changes updates the lastTransitionTime")
+	Set(obj, metav1.Condition{Type: "Available", Status: metav1.ConditionTrue, Reason: "Available"})
+	available = Get(obj, "Available")
+	g.Expect(available.Status).To(Equal(metav1.ConditionTrue))
+	g.Expect(available.LastTransitionTime).ToNot(Equal(lastTransitionTime))
+	g.Expect(obj.Conditions).To(HaveLen(1))
+}
+
+func TestDelete(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := objectWith("obj",
+		metav1.Condition{Type: "Available", Status: metav1.ConditionTrue, Reason: "Available"},
+		metav1.Condition{Type: "Deleting", Status: metav1.ConditionFalse, Reason: "NotDeleting"},
+	)
+
+	Delete(obj, "Deleting")
+	g.Expect(Has(obj, "Deleting")).To(BeFalse())
+	g.Expect(Has(obj, "Available")).To(BeTrue())
+
+	// Deleting a condition which doesn't exist is a no-op.
+	Delete(obj, "Missing")
+	g.Expect(obj.Conditions).To(HaveLen(1))
+}
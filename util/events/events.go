@@ -0,0 +1,173 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package events implements the canonical reasons of the events emitted by Cluster API controllers, and
+// helpers to record events with those reasons.
+//
+// Event reasons are part of the observable behaviour of Cluster API, e.g. operators alert on them;
+// controllers must use the reasons defined in this package instead of free-form strings, and existing
+// reasons must not be changed.
+package events
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+// Reason is the reason of an event emitted by Cluster API controllers.
+type Reason string
+
+// Reasons for events emitted when creating, updating, scaling, adopting and deleting objects.
+const (
+	// SuccessfulCreateReason is used when an object has been created.
+	SuccessfulCreateReason Reason = "SuccessfulCreate"
+
+	// FailedCreateReason is used when creating an object failed.
+	FailedCreateReason Reason = "FailedCreate"
+
+	// FailedUpdateReason is used when updating an object failed.
+	FailedUpdateReason Reason = "FailedUpdate"
+
+	// SuccessfulDeleteReason is used when an object has been deleted.
+	SuccessfulDeleteReason Reason = "SuccessfulDelete"
+
+	// FailedDeleteReason is used when deleting an object failed.
+	FailedDeleteReason Reason = "FailedDelete"
+
+	// SuccessfulScaleReason is used when an object has been scaled.
+	SuccessfulScaleReason Reason = "SuccessfulScale"
+
+	// FailedScaleReason is used when scaling an object failed.
+	FailedScaleReason Reason = "FailedScale"
+
+	// SuccessfulAdoptReason is used when an object has been adopted.
+	SuccessfulAdoptReason Reason = "SuccessfulAdopt"
+
+	// FailedAdoptReason is used when adopting an object failed.
+	FailedAdoptReason Reason = "FailedAdopt"
+
+	// SkippedAdoptReason is used when an object has not been adopted because of the adoption policy.
+	SkippedAdoptReason Reason = "SkippedAdopt"
+
+	// ReconcileErrorReason is used when the reconcile of an object failed.
+	ReconcileErrorReason Reason = "ReconcileError"
+
+	// DeletedReason is used when the deletion of an object has been completed.
+	DeletedReason Reason = "Deleted"
+)
+
+// Reasons for events emitted while provisioning infrastructure.
+const (
+	// ProvisioningStartedReason is used when the provisioning of the infrastructure for an object started.
+	ProvisioningStartedReason Reason = "ProvisioningStarted"
+
+	// ProvisioningFailedReason is used when the provisioning of the infrastructure for an object failed.
+	ProvisioningFailedReason Reason = "ProvisioningFailed"
+
+	// InfrastructureReadyReason is used when the infrastructure of a Cluster changed its readiness.
+	InfrastructureReadyReason Reason = "InfrastructureReady"
+
+	// ControlPlaneReadyReason is used when the control plane of a Cluster changed its readiness.
+	ControlPlaneReadyReason Reason = "ControlPlaneReady"
+)
+
+// Reasons for events emitted while managing the Nodes of Machines.
+const (
+	// SuccessfulSetNodeRefReason is used when the Node of a Machine has been found and set in the Machine status.
+	SuccessfulSetNodeRefReason Reason = "SuccessfulSetNodeRef"
+
+	// NodeReRegisteredReason is used when the Node of a Machine re-registered with the same ProviderID but a
+	// different UID, and the Machine has been linked to the new Node.
+	NodeReRegisteredReason Reason = "NodeReRegistered"
+
+	// SuccessfulSetInterruptibleNodeLabelReason is used when the interruptible label has been set on the Node of a Machine.
+	SuccessfulSetInterruptibleNodeLabelReason Reason = "SuccessfulSetInterruptibleNodeLabel"
+
+	// NodeStartupTimeoutReason is used when the Node of a Machine failed to start up within the node startup timeout.
+	NodeStartupTimeoutReason Reason = "NodeStartupTimeout"
+
+	// SuccessfulDrainNodeReason is used when the Node of a Machine has been drained.
+	SuccessfulDrainNodeReason Reason = "SuccessfulDrainNode"
+
+	// FailedDrainNodeReason is used when draining the Node of a Machine failed.
+	FailedDrainNodeReason Reason = "FailedDrainNode"
+
+	// DrainBlockedReason is used when draining the Node of a Machine is blocked, e.g. by a PodDisruptionBudget.
+	DrainBlockedReason Reason = "DrainBlocked"
+
+	// NodeVolumesDetachedReason is used when all the volumes of the Node of a Machine have been detached.
+	NodeVolumesDetachedReason Reason = "NodeVolumesDetached"
+
+	// FailedWaitForVolumeDetachReason is used when waiting for the volumes of the Node of a Machine to be detached failed.
+	FailedWaitForVolumeDetachReason Reason = "FailedWaitForVolumeDetach"
+
+	// SkippedNodeTeardownReason is used when drain and wait for volume detach have been skipped
+	// because the workload cluster is unreachable.
+	SkippedNodeTeardownReason Reason = "SkippedNodeTeardown"
+
+	// FailedDeleteNodeReason is used when deleting the Node of a Machine failed.
+	FailedDeleteNodeReason Reason = "FailedDeleteNode"
+)
+
+// Reasons for events emitted while health checking and remediating Machines.
+const (
+	// DetectedUnhealthyReason is used when a Machine has been detected as unhealthy, but not yet marked
+	// for remediation, e.g. because the unhealthy condition didn't last long enough.
+	DetectedUnhealthyReason Reason = "DetectedUnhealthy"
+
+	// MachineMarkedUnhealthyReason is used when a Machine has been marked for remediation.
+	MachineMarkedUnhealthyReason Reason = "MachineMarkedUnhealthy"
+
+	// RemediationRestrictedReason is used when remediation is restricted by the remediation circuit
+	// shorting logic, e.g. because of too many unhealthy Machines.
+	RemediationRestrictedReason Reason = "RemediationRestricted"
+
+	// RemediationTriggeredReason is used when the owner of a Machine started the remediation of the Machine.
+	RemediationTriggeredReason Reason = "RemediationTriggered"
+)
+
+// Reasons for events emitted during rollouts.
+const (
+	// RolloutPausedReason is used when the rollout of a MachineDeployment is paused.
+	RolloutPausedReason Reason = "RolloutPaused"
+
+	// CanaryApprovedReason is used when the canary Machines of a MachineDeployment rollout have been approved.
+	CanaryApprovedReason Reason = "CanaryApproved"
+
+	// CanaryRolledBackReason is used when the canary Machines of a MachineDeployment rollout have been rolled back.
+	CanaryRolledBackReason Reason = "CanaryRolledBack"
+)
+
+// Normal records a normal event with the given reason.
+func Normal(recorder record.EventRecorder, object runtime.Object, reason Reason, message string) {
+	recorder.Event(object, corev1.EventTypeNormal, string(reason), message)
+}
+
+// Normalf is just like Normal, but with Sprintf for the message field.
+func Normalf(recorder record.EventRecorder, object runtime.Object, reason Reason, messageFmt string, args ...interface{}) {
+	recorder.Eventf(object, corev1.EventTypeNormal, string(reason), messageFmt, args...)
+}
+
+// Warning records a warning event with the given reason.
+func Warning(recorder record.EventRecorder, object runtime.Object, reason Reason, message string) {
+	recorder.Event(object, corev1.EventTypeWarning, string(reason), message)
+}
+
+// Warningf is just like Warning, but with Sprintf for the message field.
+func Warningf(recorder record.EventRecorder, object runtime.Object, reason Reason, messageFmt string, args ...interface{}) {
+	recorder.Eventf(object, corev1.EventTypeWarning, string(reason), messageFmt, args...)
+}
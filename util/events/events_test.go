@@ -0,0 +1,44 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/tools/record"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestRecord(t *testing.T) {
+	g := NewWithT(t)
+
+	recorder := record.NewFakeRecorder(4)
+	machine := &clusterv1.Machine{}
+
+	Normal(recorder, machine, ProvisioningStartedReason, "Provisioning started")
+	Normalf(recorder, machine, SuccessfulDrainNodeReason, "Drained Node %q", "node-1")
+	Warning(recorder, machine, RemediationTriggeredReason, "Remediation triggered")
+	Warningf(recorder, machine, FailedDeleteReason, "Failed to delete Machine %q: %v", "machine-1", "error")
+
+	g.Expect(recorder.Events).To(HaveLen(4))
+	g.Expect(<-recorder.Events).To(Equal("Normal ProvisioningStarted Provisioning started"))
+	g.Expect(<-recorder.Events).To(Equal("Normal SuccessfulDrainNode Drained Node \"node-1\""))
+	g.Expect(<-recorder.Events).To(Equal("Warning RemediationTriggered Remediation triggered"))
+	g.Expect(<-recorder.Events).To(Equal("Warning FailedDelete Failed to delete Machine \"machine-1\": error"))
+}
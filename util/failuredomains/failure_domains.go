@@ -18,6 +18,7 @@ limitations under the License.
 package failuredomains
 
 import (
+	"math"
 	"sort"
 
 	"k8s.io/klog/v2/klogr"
@@ -28,9 +29,20 @@ import (
 )
 
 type failureDomainAggregation struct {
-	id    string
-	count int
+	id       string
+	count    int
+	capacity int32
 }
+
+// load returns the number of machines in the failure domain relative to its capacity;
+// failure domains without capacity are considered fully loaded.
+func (f failureDomainAggregation) load() float64 {
+	if f.capacity <= 0 {
+		return math.Inf(1)
+	}
+	return float64(f.count) / float64(f.capacity)
+}
+
 type failureDomainAggregations []failureDomainAggregation
 
 // Len is the number of elements in the collection.
@@ -41,7 +53,7 @@ func (f failureDomainAggregations) Len() int {
 // Less reports whether the element with
 // index i should sort before the element with index j.
 func (f failureDomainAggregations) Less(i, j int) bool {
-	return f[i].count < f[j].count
+	return f[i].load() < f[j].load()
 }
 
 // Swap swaps the elements with indexes i and j.
@@ -76,16 +88,46 @@ func orderDescending(failureDomains clusterv1.FailureDomains, machines collectio
 	return aggregations
 }
 
-// PickFewest returns the failure domain with the fewest number of machines.
+// PickFewest returns the failure domain with the fewest number of machines relative to its capacity.
+// NOTE: Failure domains with capacity 0 are never picked.
 func PickFewest(failureDomains clusterv1.FailureDomains, machines collections.Machines) *string {
 	aggregations := pick(failureDomains, machines)
 	if len(aggregations) == 0 {
 		return nil
 	}
 	sort.Sort(aggregations)
+	if aggregations[0].capacity <= 0 {
+		return nil
+	}
 	return pointer.String(aggregations[0].id)
 }
 
+// IsBalanced returns true if machines are spread across failure domains proportionally to their capacity, i.e. if moving
+// a machine from the failure domain with the most machines to the one with the fewest machines, relative to their capacity,
+// does not improve the spread. With the default capacity, this means that the failure domain with the most machines has
+// at most one machine more than the failure domain with the fewest machines.
+// NOTE: Machines not in any of the failure domains are ignored.
+func IsBalanced(failureDomains clusterv1.FailureDomains, machines collections.Machines) bool {
+	aggregations := failureDomainAggregations{}
+	for _, a := range pick(failureDomains, machines) {
+		// Failure domains with capacity 0 are balanced only if they are empty.
+		if a.capacity <= 0 {
+			if a.count > 0 {
+				return false
+			}
+			continue
+		}
+		aggregations = append(aggregations, a)
+	}
+	if len(aggregations) < 2 {
+		return true
+	}
+	sort.Sort(aggregations)
+	fewest, most := aggregations[0], aggregations[len(aggregations)-1]
+	fewest.count++
+	return fewest.load() >= most.load()
+}
+
 func pick(failureDomains clusterv1.FailureDomains, machines collections.Machines) failureDomainAggregations {
 	if len(failureDomains) == 0 {
 		return failureDomainAggregations{}
@@ -113,9 +155,9 @@ func pick(failureDomains clusterv1.FailureDomains, machines collections.Machines
 
 	aggregations := make(failureDomainAggregations, 0)
 
-	// Gather up tuples of failure domains ids and counts
+	// Gather up tuples of failure domains ids, counts and capacities
 	for fd, count := range counters {
-		aggregations = append(aggregations, failureDomainAggregation{id: fd, count: count})
+		aggregations = append(aggregations, failureDomainAggregation{id: fd, count: count, capacity: failureDomains[fd].GetCapacity()})
 	}
 
 	return aggregations
@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -82,6 +83,31 @@ func TestNewFailureDomainPicker(t *testing.T) {
 			fds:      fds,
 			expected: []*string{a, b},
 		},
+		{
+			name: "failure domain with more capacity is picked",
+			fds: clusterv1.FailureDomains{
+				*a: clusterv1.FailureDomainSpec{Capacity: pointer.Int32(2)},
+				*b: clusterv1.FailureDomainSpec{},
+			},
+			machines: collections.FromMachines(machinea.DeepCopy(), machineb.DeepCopy()),
+			expected: []*string{a},
+		},
+		{
+			name: "failure domain without capacity is not picked",
+			fds: clusterv1.FailureDomains{
+				*a: clusterv1.FailureDomainSpec{Capacity: pointer.Int32(0)},
+				*b: clusterv1.FailureDomainSpec{},
+			},
+			machines: collections.FromMachines(machineb.DeepCopy()),
+			expected: []*string{b},
+		},
+		{
+			name: "no failure domain with capacity",
+			fds: clusterv1.FailureDomains{
+				*a: clusterv1.FailureDomainSpec{Capacity: pointer.Int32(0)},
+			},
+			expected: nil,
+		},
 	}
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -176,3 +202,102 @@ func TestNewFailureDomainPickMost(t *testing.T) {
 		})
 	}
 }
+
+func TestIsBalanced(t *testing.T) {
+	a := pointer.String("us-west-1a")
+	b := pointer.String("us-west-1b")
+	c := pointer.String("us-west-1c")
+
+	fds := clusterv1.FailureDomains{
+		*a: clusterv1.FailureDomainSpec{},
+		*b: clusterv1.FailureDomainSpec{},
+		*c: clusterv1.FailureDomainSpec{},
+	}
+	machine := func(name string, fd *string) *clusterv1.Machine {
+		return &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: name}, Spec: clusterv1.MachineSpec{FailureDomain: fd}}
+	}
+
+	testcases := []struct {
+		name     string
+		fds      clusterv1.FailureDomains
+		machines collections.Machines
+		expected bool
+	}{
+		{
+			name:     "no failure domains",
+			machines: collections.FromMachines(machine("m1", a), machine("m2", a)),
+			expected: true,
+		},
+		{
+			name: "single failure domain",
+			fds: clusterv1.FailureDomains{
+				*a: clusterv1.FailureDomainSpec{},
+			},
+			machines: collections.FromMachines(machine("m1", a), machine("m2", a)),
+			expected: true,
+		},
+		{
+			name:     "no machines",
+			fds:      fds,
+			expected: true,
+		},
+		{
+			name:     "machines evenly spread",
+			fds:      fds,
+			machines: collections.FromMachines(machine("m1", a), machine("m2", b), machine("m3", c)),
+			expected: true,
+		},
+		{
+			name:     "failure domains differ by one machine",
+			fds:      fds,
+			machines: collections.FromMachines(machine("m1", a), machine("m2", a), machine("m3", b), machine("m4", c)),
+			expected: true,
+		},
+		{
+			name:     "failure domains differ by more than one machine",
+			fds:      fds,
+			machines: collections.FromMachines(machine("m1", a), machine("m2", a), machine("m3", b)),
+			expected: false,
+		},
+		{
+			name: "machines spread proportionally to capacity",
+			fds: clusterv1.FailureDomains{
+				*a: clusterv1.FailureDomainSpec{Capacity: pointer.Int32(2)},
+				*b: clusterv1.FailureDomainSpec{},
+			},
+			machines: collections.FromMachines(machine("m1", a), machine("m2", a), machine("m3", b)),
+			expected: true,
+		},
+		{
+			name: "machines not spread proportionally to capacity",
+			fds: clusterv1.FailureDomains{
+				*a: clusterv1.FailureDomainSpec{Capacity: pointer.Int32(2)},
+				*b: clusterv1.FailureDomainSpec{},
+			},
+			machines: collections.FromMachines(machine("m1", a), machine("m2", b), machine("m3", b)),
+			expected: false,
+		},
+		{
+			name: "machines in failure domains without capacity",
+			fds: clusterv1.FailureDomains{
+				*a: clusterv1.FailureDomainSpec{Capacity: pointer.Int32(0)},
+				*b: clusterv1.FailureDomainSpec{},
+			},
+			machines: collections.FromMachines(machine("m1", a), machine("m2", b)),
+			expected: false,
+		},
+		{
+			name:     "machines not in any of the failure domains are ignored",
+			fds:      fds,
+			machines: collections.FromMachines(machine("m1", a), machine("m2", b), machine("m3", c), machine("m4", nil), machine("m5", pointer.String("us-west-1d"))),
+			expected: true,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(IsBalanced(tc.fds, tc.machines)).To(Equal(tc.expected))
+		})
+	}
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package finalizers implements finalizer helpers.
+package finalizers
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// EnsureFinalizer adds the finalizers to the object if they are not set, and patches the object immediately.
+// It returns true if any finalizer has been added; in this case the caller is expected to return and to let
+// the update event of the object trigger the next reconcile, so finalizers are always persisted before creating
+// any external resource, avoiding the race condition between init and delete.
+//
+// NOTE: Finalizers in general can only be added when the deletionTimestamp is not set; if the object is being
+// deleted, finalizers are not added and EnsureFinalizer returns false.
+//
+// Example use:
+//
+//	if finalizerAdded, err := finalizers.EnsureFinalizer(ctx, r.Client, machine, clusterv1.MachineFinalizer); err != nil || finalizerAdded {
+//		return ctrl.Result{}, err
+//	}
+func EnsureFinalizer(ctx context.Context, c client.Client, obj client.Object, finalizers ...string) (finalizerAdded bool, err error) {
+	if !obj.GetDeletionTimestamp().IsZero() || containsAll(obj, finalizers) {
+		return false, nil
+	}
+
+	original, ok := obj.DeepCopyObject().(client.Object)
+	if !ok {
+		return false, errors.Errorf("failed to add finalizers to %s: object can't be copied", client.ObjectKeyFromObject(obj))
+	}
+	for _, finalizer := range finalizers {
+		controllerutil.AddFinalizer(obj, finalizer)
+	}
+	if err := c.Patch(ctx, obj, client.MergeFrom(original)); err != nil {
+		return false, errors.Wrapf(err, "failed to add finalizers to %s", client.ObjectKeyFromObject(obj))
+	}
+	return true, nil
+}
+
+// containsAll returns true if the object has all the given finalizers.
+func containsAll(obj client.Object, finalizers []string) bool {
+	for _, finalizer := range finalizers {
+		if !controllerutil.ContainsFinalizer(obj, finalizer) {
+			return false
+		}
+	}
+	return true
+}
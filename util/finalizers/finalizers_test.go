@@ -0,0 +1,86 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package finalizers
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestEnsureFinalizer(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	_ = clusterv1.AddToScheme(scheme)
+
+	tests := []struct {
+		name                   string
+		obj                    *clusterv1.Machine
+		expectedFinalizerAdded bool
+		expectedFinalizers     []string
+	}{
+		{
+			name:                   "adds the finalizer if not set",
+			obj:                    &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{"other"}}},
+			expectedFinalizerAdded: true,
+			expectedFinalizers:     []string{"other", clusterv1.MachineFinalizer},
+		},
+		{
+			name:                   "doesn't add the finalizer if already set",
+			obj:                    &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{clusterv1.MachineFinalizer}}},
+			expectedFinalizerAdded: false,
+			expectedFinalizers:     []string{clusterv1.MachineFinalizer},
+		},
+		{
+			name: "doesn't add the finalizer if the object is being deleted",
+			obj: &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{
+				Finalizers:        []string{"other"},
+				DeletionTimestamp: &metav1.Time{Time: metav1.Now().Time},
+			}},
+			expectedFinalizerAdded: false,
+			expectedFinalizers:     []string{"other"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			tt.obj.Namespace = metav1.NamespaceDefault
+			tt.obj.Name = "machine"
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.obj).Build()
+
+			machine := &clusterv1.Machine{}
+			g.Expect(c.Get(ctx, client.ObjectKeyFromObject(tt.obj), machine)).To(Succeed())
+
+			finalizerAdded, err := EnsureFinalizer(ctx, c, machine, clusterv1.MachineFinalizer)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(finalizerAdded).To(Equal(tt.expectedFinalizerAdded))
+			g.Expect(machine.Finalizers).To(Equal(tt.expectedFinalizers))
+
+			// The finalizers must be persisted.
+			g.Expect(c.Get(ctx, client.ObjectKeyFromObject(tt.obj), machine)).To(Succeed())
+			g.Expect(machine.Finalizers).To(Equal(tt.expectedFinalizers))
+		})
+	}
+}
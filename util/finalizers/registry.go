@@ -0,0 +1,105 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package finalizers
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// Registry tracks the finalizers managed by a controller and the order in which they must be removed, e.g.
+// when a controller must release external resources depending on each other in a given order.
+//
+// Finalizers are registered with the finalizers that must be removed before them; given that those
+// finalizers must be registered first, dependencies can't be cyclic.
+type Registry struct {
+	finalizers []string
+	// removeAfter maps each finalizer to the finalizers that must be removed before it.
+	removeAfter map[string]sets.Set[string]
+}
+
+// Register registers a finalizer, which can only be removed after the given finalizers have been removed.
+func (r *Registry) Register(finalizer string, removeAfter ...string) error {
+	if r.removeAfter == nil {
+		r.removeAfter = map[string]sets.Set[string]{}
+	}
+	if _, ok := r.removeAfter[finalizer]; ok {
+		return errors.Errorf("finalizer %q is already registered", finalizer)
+	}
+	for _, dependency := range removeAfter {
+		if _, ok := r.removeAfter[dependency]; !ok {
+			return errors.Errorf("failed to register finalizer %q: finalizer %q must be registered first", finalizer, dependency)
+		}
+	}
+
+	r.finalizers = append(r.finalizers, finalizer)
+	r.removeAfter[finalizer] = sets.New[string](removeAfter...)
+	return nil
+}
+
+// Finalizers returns the registered finalizers, in registration order.
+func (r *Registry) Finalizers() []string {
+	return append([]string{}, r.finalizers...)
+}
+
+// Add adds the registered finalizers missing on the object, without patching it; it returns true if any finalizer
+// has been added. Similarly to EnsureFinalizer, in this case the caller is expected to return and to let the
+// patch of the object trigger the next reconcile.
+//
+// NOTE: Finalizers in general can only be added when the deletionTimestamp is not set; if the object is being
+// deleted, finalizers are not added and Add returns false.
+func (r *Registry) Add(obj client.Object) bool {
+	if !obj.GetDeletionTimestamp().IsZero() {
+		return false
+	}
+
+	added := false
+	for _, finalizer := range r.finalizers {
+		added = controllerutil.AddFinalizer(obj, finalizer) || added
+	}
+	return added
+}
+
+// Pending returns the finalizers still set on the object that must be removed before the given finalizer,
+// sorted alphabetically.
+func (r *Registry) Pending(obj client.Object, finalizer string) []string {
+	pending := []string{}
+	for _, dependency := range r.removeAfter[finalizer].UnsortedList() {
+		if controllerutil.ContainsFinalizer(obj, dependency) {
+			pending = append(pending, dependency)
+		}
+	}
+	sort.Strings(pending)
+	return pending
+}
+
+// Remove removes the finalizer from the object, without patching it, if all the finalizers that must be
+// removed before it have already been removed; it returns an error listing the pending finalizers otherwise.
+func (r *Registry) Remove(obj client.Object, finalizer string) error {
+	if _, ok := r.removeAfter[finalizer]; !ok {
+		return errors.Errorf("failed to remove finalizer %q: finalizer is not registered", finalizer)
+	}
+	if pending := r.Pending(obj, finalizer); len(pending) > 0 {
+		return errors.Errorf("failed to remove finalizer %q: waiting for finalizers %v to be removed first", finalizer, pending)
+	}
+	controllerutil.RemoveFinalizer(obj, finalizer)
+	return nil
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package finalizers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestRegistry(t *testing.T) {
+	const (
+		network      = "infrastructure.cluster.x-k8s.io/network"
+		loadBalancer = "infrastructure.cluster.x-k8s.io/load-balancer"
+		instances    = "infrastructure.cluster.x-k8s.io/instances"
+	)
+
+	newRegistry := func(g *WithT) *Registry {
+		r := &Registry{}
+		g.Expect(r.Register(instances)).To(Succeed())
+		g.Expect(r.Register(loadBalancer, instances)).To(Succeed())
+		g.Expect(r.Register(network, instances, loadBalancer)).To(Succeed())
+		return r
+	}
+
+	t.Run("fails to register a finalizer twice or with unregistered dependencies", func(t *testing.T) {
+		g := NewWithT(t)
+		r := newRegistry(g)
+
+		g.Expect(r.Register(instances)).ToNot(Succeed())
+		g.Expect(r.Register("other", "unregistered")).ToNot(Succeed())
+		g.Expect(r.Finalizers()).To(Equal([]string{instances, loadBalancer, network}))
+	})
+
+	t.Run("adds missing finalizers", func(t *testing.T) {
+		g := NewWithT(t)
+		r := newRegistry(g)
+
+		obj := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{loadBalancer}}}
+		g.Expect(r.Add(obj)).To(BeTrue())
+		g.Expect(obj.Finalizers).To(Equal([]string{loadBalancer, instances, network}))
+		g.Expect(r.Add(obj)).To(BeFalse())
+	})
+
+	t.Run("doesn't add finalizers if the object is being deleted", func(t *testing.T) {
+		g := NewWithT(t)
+		r := newRegistry(g)
+
+		obj := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &metav1.Time{Time: metav1.Now().Time}}}
+		g.Expect(r.Add(obj)).To(BeFalse())
+		g.Expect(obj.Finalizers).To(BeEmpty())
+	})
+
+	t.Run("removes finalizers in order", func(t *testing.T) {
+		g := NewWithT(t)
+		r := newRegistry(g)
+
+		obj := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{network, loadBalancer, instances}}}
+		g.Expect(r.Pending(obj, network)).To(Equal([]string{instances, loadBalancer}))
+		g.Expect(r.Remove(obj, network)).ToNot(Succeed())
+		g.Expect(r.Remove(obj, loadBalancer)).ToNot(Succeed())
+		g.Expect(r.Remove(obj, "unregistered")).ToNot(Succeed())
+
+		g.Expect(r.Remove(obj, instances)).To(Succeed())
+		g.Expect(r.Pending(obj, network)).To(Equal([]string{loadBalancer}))
+		g.Expect(r.Remove(obj, loadBalancer)).To(Succeed())
+		g.Expect(r.Remove(obj, network)).To(Succeed())
+		g.Expect(obj.Finalizers).To(BeEmpty())
+	})
+}
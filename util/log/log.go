@@ -60,6 +60,21 @@ func AddOwners(ctx context.Context, c client.Client, obj metav1.Object) (context
 	return ctx, log, nil
 }
 
+// AddOperationID adds the clusterctl operation ID of an Object, if any, as k/v pair to the logger in ctx.
+// Note: The operation ID is read from the cluster.x-k8s.io/operation-id annotation.
+func AddOperationID(ctx context.Context, obj metav1.Object) (context.Context, logr.Logger) {
+	log := ctrl.LoggerFrom(ctx)
+
+	operationID, ok := obj.GetAnnotations()[clusterv1.OperationIDAnnotation]
+	if !ok || operationID == "" {
+		return ctx, log
+	}
+
+	log = log.WithValues("operationID", operationID)
+	ctx = ctrl.LoggerInto(ctx, log)
+	return ctx, log
+}
+
 // owner represents an owner of an object.
 type owner struct {
 	Kind      string
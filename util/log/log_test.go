@@ -201,6 +201,52 @@ func Test_AddObjectHierarchy(t *testing.T) {
 	}
 }
 
+func Test_AddOperationID(t *testing.T) {
+	tests := []struct {
+		name                  string
+		obj                   metav1.Object
+		expectedKeysAndValues []interface{}
+	}{
+		{
+			name: "No operation ID annotation",
+			obj: &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: metav1.NamespaceDefault,
+					Name:      "development-3961",
+				},
+			},
+			expectedKeysAndValues: nil,
+		},
+		{
+			name: "Operation ID annotation is added",
+			obj: &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: metav1.NamespaceDefault,
+					Name:      "development-3961",
+					Annotations: map[string]string{
+						clusterv1.OperationIDAnnotation: "9f4c6f0e-5cb5-4b8a-a1e1-c8f2dbd3c2a5",
+					},
+				},
+			},
+			expectedKeysAndValues: []interface{}{
+				"operationID",
+				"9f4c6f0e-5cb5-4b8a-a1e1-c8f2dbd3c2a5",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			// Create fake log sink so we can later verify the added k/v pairs.
+			ctx := ctrl.LoggerInto(context.Background(), logr.New(&fakeLogSink{}))
+
+			_, logger := AddOperationID(ctx, tt.obj)
+			g.Expect(logger.GetSink().(fakeLogSink).keysAndValues).To(Equal(tt.expectedKeysAndValues))
+		})
+	}
+}
+
 type fakeLogSink struct {
 	// Embedding NullLogSink so we don't have to implement all funcs
 	// of the LogSink interface.
@@ -37,6 +37,13 @@ type HelperOptions struct {
 	// OwnedConditions defines condition types owned by the controller.
 	// In case of conflicts for the owned conditions, the patch helper will always use the value provided by the controller.
 	OwnedConditions []clusterv1.ConditionType
+
+	// ServerSideApply patches conditions with server-side apply using FieldManager, instead of
+	// a two-way merge patch with optimistic locking.
+	ServerSideApply bool
+
+	// FieldManager is the field manager used to apply conditions with server-side apply.
+	FieldManager string
 }
 
 // WithForceOverwriteConditions allows the patch helper to overwrite conditions in case of conflicts.
@@ -67,3 +74,18 @@ type WithOwnedConditions struct {
 func (w WithOwnedConditions) ApplyToHelper(in *HelperOptions) {
 	in.OwnedConditions = w.Conditions
 }
+
+// WithServerSideApply patches conditions with server-side apply using the given field manager, instead of
+// a two-way merge patch with optimistic locking; conflicts with other controllers setting conditions on the same
+// object are resolved by the API server, without retries.
+// The field manager must be unique to the controller, because each field manager owns the conditions it applies.
+// NOTE: metadata, spec and the rest of the status are still patched with two-way merge patches.
+type WithServerSideApply struct {
+	FieldManager string
+}
+
+// ApplyToHelper applies this configuration to the given HelperOptions.
+func (w WithServerSideApply) ApplyToHelper(in *HelperOptions) {
+	in.ServerSideApply = true
+	in.FieldManager = w.FieldManager
+}
@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -34,6 +35,7 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/tracing"
 )
 
 // Helper is a utility for ensuring the proper patching of objects.
@@ -82,12 +84,21 @@ func NewHelper(obj client.Object, crClient client.Client) (*Helper, error) {
 }
 
 // Patch will attempt to patch the given object, including its status.
-func (h *Helper) Patch(ctx context.Context, obj client.Object, opts ...Option) error {
+func (h *Helper) Patch(ctx context.Context, obj client.Object, opts ...Option) (reterr error) {
 	// Return early if the object is nil.
 	if util.IsNil(obj) {
 		return errors.New("Patch could not be completed: object is nil")
 	}
 
+	ctx, span := tracing.StartSpan(ctx, "patch.Helper.Patch",
+		attribute.String("kind", h.gvk.Kind),
+		attribute.String("namespace", obj.GetNamespace()),
+		attribute.String("name", obj.GetName()),
+	)
+	defer func() {
+		tracing.EndSpan(span, reterr)
+	}()
+
 	// Get the GroupVersionKind of the object that we want to patch.
 	gvk, err := apiutil.GVKForObject(obj, h.client.Scheme())
 	if err != nil {
@@ -130,14 +141,22 @@ func (h *Helper) Patch(ctx context.Context, obj client.Object, opts ...Option) e
 		return err
 	}
 
+	// Patch the conditions first.
+	//
+	// Given that we pass in metadata.resourceVersion to perform a 3-way-merge conflict resolution,
+	// patching conditions first avoids an extra loop if spec or status patch succeeds first
+	// given that causes the resourceVersion to mutate.
+	// NOTE: Applying conditions with server-side apply doesn't require the resourceVersion.
+	var conditionsErr error
+	if options.ServerSideApply {
+		conditionsErr = h.applyStatusConditions(ctx, obj, options.FieldManager, options.ForceOverwriteConditions, options.OwnedConditions)
+	} else {
+		conditionsErr = h.patchStatusConditions(ctx, obj, options.ForceOverwriteConditions, options.OwnedConditions)
+	}
+
 	// Issue patches and return errors in an aggregate.
 	return kerrors.NewAggregate([]error{
-		// Patch the conditions first.
-		//
-		// Given that we pass in metadata.resourceVersion to perform a 3-way-merge conflict resolution,
-		// patching conditions first avoids an extra loop if spec or status patch succeeds first
-		// given that causes the resourceVersion to mutate.
-		h.patchStatusConditions(ctx, obj, options.ForceOverwriteConditions, options.OwnedConditions),
+		conditionsErr,
 
 		// Then proceed to patch the rest of the object.
 		h.patch(ctx, obj),
@@ -256,6 +275,77 @@ func (h *Helper) patchStatusConditions(ctx context.Context, obj client.Object, f
 	})
 }
 
+// applyStatusConditions issues a server-side apply patch if there are any changes to the conditions slice under
+// the status subresource, using the given field manager.
+//
+// The conditions applied are the ones changed by the controller, the ones previously applied by the field manager,
+// and the owned conditions, or all the conditions if forceOverwrite is set; the ownership of the applied conditions
+// is forced, so there are no conflicts to retry. Conditions removed by the controller are removed from the object
+// only if no other field manager owns them.
+func (h *Helper) applyStatusConditions(ctx context.Context, obj client.Object, fieldManager string, forceOverwrite bool, ownedConditions []clusterv1.ConditionType) error {
+	// Nothing to do if the object isn't a condition patcher.
+	if !h.isConditionsSetter {
+		return nil
+	}
+	if fieldManager == "" {
+		return errors.New("field manager is required to apply conditions with server-side apply")
+	}
+
+	before, ok := h.beforeObject.(conditions.Getter)
+	if !ok {
+		return errors.Errorf("object %s doesn't satisfy conditions.Getter, cannot patch", before.GetObjectKind())
+	}
+	after, ok := obj.(conditions.Getter)
+	if !ok {
+		return errors.Errorf("object %s doesn't satisfy conditions.Getter, cannot patch", after.GetObjectKind())
+	}
+
+	// Store the diff from the before/after object, and return early if there are no changes.
+	diff, err := conditions.NewPatch(
+		before,
+		after,
+	)
+	if err != nil {
+		return errors.Wrapf(err, "object can not be patched")
+	}
+	if diff.IsZero() {
+		return nil
+	}
+
+	// Compute the conditions to apply.
+	conditionTypes := appliedConditionTypes(h.beforeObject, fieldManager)
+	for _, conditionType := range ownedConditions {
+		conditionTypes.Insert(string(conditionType))
+	}
+	for _, op := range diff {
+		if op.After != nil {
+			conditionTypes.Insert(string(op.After.Type))
+		}
+	}
+	appliedConditions := []interface{}{}
+	for i := range after.GetConditions() {
+		condition := after.GetConditions()[i]
+		if !forceOverwrite && !conditionTypes.Has(string(condition.Type)) {
+			continue
+		}
+		u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&condition)
+		if err != nil {
+			return errors.Wrapf(err, "failed to convert condition %s to unstructured", condition.Type)
+		}
+		appliedConditions = append(appliedConditions, u)
+	}
+
+	// Issue the server-side apply patch, only including the identity of the object and the applied conditions.
+	applyObj := &unstructured.Unstructured{}
+	applyObj.SetGroupVersionKind(h.gvk)
+	applyObj.SetNamespace(obj.GetNamespace())
+	applyObj.SetName(obj.GetName())
+	if err := unstructured.SetNestedSlice(applyObj.Object, appliedConditions, "status", "conditions"); err != nil {
+		return err
+	}
+	return h.client.Status().Patch(ctx, applyObj, client.Apply, client.FieldOwner(fieldManager), client.ForceOwnership)
+}
+
 // calculatePatch returns the before/after objects to be given in a controller-runtime patch, scoped down to the absolute necessary.
 func (h *Helper) calculatePatch(afterObj client.Object, focus patchType) (client.Object, client.Object, error) {
 	// Get a shallow unsafe copy of the before/after object in unstructured form.
@@ -27,6 +27,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -497,6 +498,89 @@ func TestPatchHelper(t *testing.T) {
 					return *conditions.Get(objAfter, clusterv1.ReadyCondition)
 				}, timeout).Should(conditions.MatchCondition(*readyBefore))
 			})
+
+			t.Run("should apply conditions with server-side apply without conflicts", func(t *testing.T) {
+				g := NewWithT(t)
+
+				obj := obj.DeepCopy()
+
+				t.Log("Creating the object")
+				g.Expect(env.Create(ctx, obj)).To(Succeed())
+				defer func() {
+					g.Expect(env.Delete(ctx, obj)).To(Succeed())
+				}()
+				key := client.ObjectKey{Name: obj.Name, Namespace: obj.Namespace}
+
+				t.Log("Checking that the object has been created")
+				g.Eventually(func() error {
+					obj := obj.DeepCopy()
+					return env.Get(ctx, key, obj)
+				}).Should(Succeed())
+
+				objCopy := obj.DeepCopy()
+
+				t.Log("Marking Ready=False and a custom condition to be false from another controller")
+				conditions.MarkFalse(objCopy, clusterv1.ReadyCondition, "reason", clusterv1.ConditionSeverityInfo, "message")
+				conditions.MarkFalse(objCopy, clusterv1.ConditionType("TestCondition"), "reason", clusterv1.ConditionSeverityInfo, "message")
+				g.Expect(env.Status().Update(ctx, objCopy)).To(Succeed())
+
+				t.Log("Validating that the local object's resource version is behind")
+				g.Expect(obj.ResourceVersion).NotTo(Equal(objCopy.ResourceVersion))
+
+				t.Log("Creating a new patch helper")
+				patcher, err := NewHelper(obj, env)
+				g.Expect(err).ToNot(HaveOccurred())
+
+				t.Log("Marking Ready=True")
+				conditions.MarkTrue(obj, clusterv1.ReadyCondition)
+
+				t.Log("Patching the object with server-side apply")
+				g.Expect(patcher.Patch(ctx, obj, WithServerSideApply{FieldManager: "test-controller"})).To(Succeed())
+
+				t.Log("Validating the object has been updated, preserving the condition of the other controller")
+				readyBefore := conditions.Get(obj, clusterv1.ReadyCondition)
+				testConditionCopy := conditions.Get(objCopy, "TestCondition")
+				g.Eventually(func() bool {
+					objAfter := obj.DeepCopy()
+					if err := env.Get(ctx, key, objAfter); err != nil {
+						return false
+					}
+					if ok, err := conditions.MatchCondition(*readyBefore).Match(*conditions.Get(objAfter, clusterv1.ReadyCondition)); err != nil || !ok {
+						return false
+					}
+					if ok, err := conditions.MatchCondition(*testConditionCopy).Match(*conditions.Get(objAfter, "TestCondition")); err != nil || !ok {
+						return false
+					}
+					return true
+				}, timeout).Should(BeTrue())
+
+				t.Log("Validating the field manager owns the applied conditions")
+				objAfter := obj.DeepCopy()
+				g.Expect(env.Get(ctx, key, objAfter)).To(Succeed())
+				g.Expect(sets.List(appliedConditionTypes(objAfter, "test-controller"))).To(Equal([]string{string(clusterv1.ReadyCondition)}))
+			})
+
+			t.Run("should return an error when applying conditions with server-side apply without a field manager", func(t *testing.T) {
+				g := NewWithT(t)
+
+				obj := obj.DeepCopy()
+
+				t.Log("Creating the object")
+				g.Expect(env.Create(ctx, obj)).To(Succeed())
+				defer func() {
+					g.Expect(env.Delete(ctx, obj)).To(Succeed())
+				}()
+
+				t.Log("Creating a new patch helper")
+				patcher, err := NewHelper(obj, env)
+				g.Expect(err).ToNot(HaveOccurred())
+
+				t.Log("Marking Ready=True")
+				conditions.MarkTrue(obj, clusterv1.ReadyCondition)
+
+				t.Log("Patching the object with server-side apply")
+				g.Expect(patcher.Patch(ctx, obj, WithServerSideApply{})).NotTo(Succeed())
+			})
 		})
 	})
 
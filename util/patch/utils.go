@@ -17,10 +17,13 @@ limitations under the License.
 package patch
 
 import (
+	"encoding/json"
 	"strings"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 )
 
 type patchType string
@@ -95,3 +98,38 @@ func unsafeUnstructuredCopy(obj *unstructured.Unstructured, focus patchType, isC
 
 	return res
 }
+
+// appliedConditionTypes returns the types of the conditions applied by the field manager to the status subresource
+// of an object with server-side apply, according to the managed fields of the object.
+func appliedConditionTypes(obj metav1.Object, fieldManager string) sets.Set[string] {
+	conditionTypes := sets.Set[string]{}
+	for _, managedField := range obj.GetManagedFields() {
+		if managedField.Manager != fieldManager ||
+			managedField.Operation != metav1.ManagedFieldsOperationApply ||
+			managedField.Subresource != "status" ||
+			managedField.FieldsV1 == nil {
+			continue
+		}
+
+		fields := map[string]interface{}{}
+		if err := json.Unmarshal(managedField.FieldsV1.Raw, &fields); err != nil {
+			continue
+		}
+		status, _ := fields["f:status"].(map[string]interface{})
+		conditions, _ := status["f:conditions"].(map[string]interface{})
+		for key := range conditions {
+			// Conditions are identified by a key like k:{"type":"Ready"}.
+			if !strings.HasPrefix(key, "k:") {
+				continue
+			}
+			conditionKey := map[string]interface{}{}
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(key, "k:")), &conditionKey); err != nil {
+				continue
+			}
+			if conditionType, ok := conditionKey["type"].(string); ok {
+				conditionTypes.Insert(conditionType)
+			}
+		}
+	}
+	return conditionTypes
+}
@@ -22,6 +22,7 @@ import (
 	. "github.com/onsi/gomega"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
@@ -225,3 +226,44 @@ func TestUnsafeFocusedUnstructured(t *testing.T) {
 		g.Expect(obj.Object["status"].(map[string]interface{})["conditions"]).ToNot(BeNil())
 	})
 }
+
+func TestAppliedConditionTypes(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			ManagedFields: []metav1.ManagedFieldsEntry{
+				{
+					// Conditions applied by the field manager.
+					Manager:     "cluster-controller",
+					Operation:   metav1.ManagedFieldsOperationApply,
+					Subresource: "status",
+					FieldsV1: &metav1.FieldsV1{
+						Raw: []byte(`{"f:status":{"f:conditions":{"k:{\"type\":\"Ready\"}":{".":{},"f:status":{},"f:type":{}},"k:{\"type\":\"InfrastructureReady\"}":{".":{},"f:status":{},"f:type":{}}}}}`),
+					},
+				},
+				{
+					// Conditions applied by another field manager.
+					Manager:     "other-controller",
+					Operation:   metav1.ManagedFieldsOperationApply,
+					Subresource: "status",
+					FieldsV1: &metav1.FieldsV1{
+						Raw: []byte(`{"f:status":{"f:conditions":{"k:{\"type\":\"Other\"}":{".":{},"f:status":{},"f:type":{}}}}}`),
+					},
+				},
+				{
+					// Conditions updated by the field manager.
+					Manager:     "cluster-controller",
+					Operation:   metav1.ManagedFieldsOperationUpdate,
+					Subresource: "status",
+					FieldsV1: &metav1.FieldsV1{
+						Raw: []byte(`{"f:status":{"f:conditions":{}}}`),
+					},
+				},
+			},
+		},
+	}
+
+	g.Expect(sets.List(appliedConditionTypes(obj, "cluster-controller"))).To(Equal([]string{"InfrastructureReady", "Ready"}))
+	g.Expect(appliedConditionTypes(obj, "unknown-controller")).To(BeEmpty())
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predicates
+
+import (
+	"strings"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// ResourceGenerationChanged returns a predicate that returns true for update events only if the
+// metadata.generation of the resource changed, i.e. it filters out updates to metadata and status.
+// Create, delete and generic events are always processed.
+func ResourceGenerationChanged(logger logr.Logger) predicate.Funcs {
+	return updatePredicate(logger, "ResourceGenerationChanged", func(oldObj, newObj client.Object) bool {
+		return oldObj.GetGeneration() != newObj.GetGeneration()
+	})
+}
+
+// ResourceAnnotationsChanged returns a predicate that returns true for update events only if any of the
+// annotations with the given keys has been added, removed or changed.
+// Create, delete and generic events are always processed.
+func ResourceAnnotationsChanged(logger logr.Logger, keys ...string) predicate.Funcs {
+	return updatePredicate(logger, "ResourceAnnotationsChanged", func(oldObj, newObj client.Object) bool {
+		return anyKeyChanged(oldObj.GetAnnotations(), newObj.GetAnnotations(), keys)
+	})
+}
+
+// ResourceLabelsChanged returns a predicate that returns true for update events only if any of the
+// labels with the given keys has been added, removed or changed.
+// Create, delete and generic events are always processed.
+func ResourceLabelsChanged(logger logr.Logger, keys ...string) predicate.Funcs {
+	return updatePredicate(logger, "ResourceLabelsChanged", func(oldObj, newObj client.Object) bool {
+		return anyKeyChanged(oldObj.GetLabels(), newObj.GetLabels(), keys)
+	})
+}
+
+// ResourceFieldsChanged returns a predicate that returns true for update events only if any of the
+// fields with the given paths changed; paths are dot separated, e.g. "spec.replicas" or "status.conditions".
+// Create, delete and generic events are always processed.
+//
+// NOTE: Updates are processed if the resources can't be converted to unstructured.
+func ResourceFieldsChanged(logger logr.Logger, paths ...string) predicate.Funcs {
+	return updatePredicate(logger, "ResourceFieldsChanged", func(oldObj, newObj client.Object) bool {
+		oldU, err := runtime.DefaultUnstructuredConverter.ToUnstructured(oldObj)
+		if err != nil {
+			return true
+		}
+		newU, err := runtime.DefaultUnstructuredConverter.ToUnstructured(newObj)
+		if err != nil {
+			return true
+		}
+		for _, path := range paths {
+			fields := strings.Split(path, ".")
+			oldValue, _, _ := unstructured.NestedFieldNoCopy(oldU, fields...)
+			newValue, _, _ := unstructured.NestedFieldNoCopy(newU, fields...)
+			if !equality.Semantic.DeepEqual(oldValue, newValue) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// ResourceGenerationOrAnnotationsChanged returns a predicate that returns true for update events only if the
+// ResourceGenerationChanged or the ResourceAnnotationsChanged predicate for the given keys returns true.
+//
+// Example use:
+//
+//	err := controller.Watch(
+//		source.Kind(cache, &clusterv1.MachineDeployment{}),
+//		handler.EnqueueRequestsFromMapFunc(mapFunc),
+//		predicates.ResourceGenerationOrAnnotationsChanged(r.Log, clusterv1.PausedAnnotation),
+//	)
+func ResourceGenerationOrAnnotationsChanged(logger logr.Logger, keys ...string) predicate.Funcs {
+	return Any(logger, ResourceGenerationChanged(logger), ResourceAnnotationsChanged(logger, keys...))
+}
+
+// updatePredicate returns a predicate that filters update events with the given changed func,
+// while always processing create, delete and generic events.
+func updatePredicate(logger logr.Logger, name string, changed func(oldObj, newObj client.Object) bool) predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if e.ObjectOld == nil || e.ObjectNew == nil {
+				return false
+			}
+
+			kind := strings.ToLower(e.ObjectNew.GetObjectKind().GroupVersionKind().Kind)
+			log := logger.WithValues("predicate", name, "eventType", "update", "namespace", e.ObjectNew.GetNamespace(), kind, e.ObjectNew.GetName())
+			if changed(e.ObjectOld, e.ObjectNew) {
+				log.V(6).Info("Resource changed, will attempt to map resource")
+				return true
+			}
+			log.V(6).Info("Resource did not change, will not attempt to map resource")
+			return false
+		},
+		CreateFunc:  func(event.CreateEvent) bool { return true },
+		DeleteFunc:  func(event.DeleteEvent) bool { return true },
+		GenericFunc: func(event.GenericEvent) bool { return true },
+	}
+}
+
+// anyKeyChanged returns true if the value of any of the keys is different in the given maps,
+// including keys added or removed.
+func anyKeyChanged(oldValues, newValues map[string]string, keys []string) bool {
+	for _, key := range keys {
+		oldValue, oldOk := oldValues[key]
+		newValue, newOk := newValues[key]
+		if oldOk != newOk || oldValue != newValue {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predicates_test
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/predicates"
+)
+
+func TestChangePredicates(t *testing.T) {
+	logger := logr.New(log.NullLogSink{})
+
+	base := &clusterv1.MachineDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "md",
+			Generation:  1,
+			Labels:      map[string]string{"tier": "frontend"},
+			Annotations: map[string]string{"example.com/hold": "true"},
+		},
+		Spec: clusterv1.MachineDeploymentSpec{Replicas: pointer.Int32(1)},
+	}
+	generationChanged := base.DeepCopy()
+	generationChanged.Generation = 2
+	generationChanged.Spec.Replicas = pointer.Int32(3)
+	annotationChanged := base.DeepCopy()
+	annotationChanged.Annotations["example.com/hold"] = "false"
+	annotationRemoved := base.DeepCopy()
+	annotationRemoved.Annotations = nil
+	otherAnnotationAdded := base.DeepCopy()
+	otherAnnotationAdded.Annotations["example.com/other"] = ""
+	labelChanged := base.DeepCopy()
+	labelChanged.Labels["tier"] = "backend"
+	statusChanged := base.DeepCopy()
+	conditions.MarkTrue(statusChanged, clusterv1.ReadyCondition)
+
+	tests := []struct {
+		name      string
+		predicate predicate.Funcs
+		newObj    *clusterv1.MachineDeployment
+		expected  bool
+	}{
+		{
+			name:      "generation changed: changed generation",
+			predicate: predicates.ResourceGenerationChanged(logger),
+			newObj:    generationChanged,
+			expected:  true,
+		},
+		{
+			name:      "generation changed: changed status",
+			predicate: predicates.ResourceGenerationChanged(logger),
+			newObj:    statusChanged,
+			expected:  false,
+		},
+		{
+			name:      "annotations changed: changed annotation",
+			predicate: predicates.ResourceAnnotationsChanged(logger, "example.com/hold"),
+			newObj:    annotationChanged,
+			expected:  true,
+		},
+		{
+			name:      "annotations changed: removed annotation",
+			predicate: predicates.ResourceAnnotationsChanged(logger, "example.com/hold"),
+			newObj:    annotationRemoved,
+			expected:  true,
+		},
+		{
+			name:      "annotations changed: other annotation added",
+			predicate: predicates.ResourceAnnotationsChanged(logger, "example.com/hold"),
+			newObj:    otherAnnotationAdded,
+			expected:  false,
+		},
+		{
+			name:      "labels changed: changed label",
+			predicate: predicates.ResourceLabelsChanged(logger, "tier"),
+			newObj:    labelChanged,
+			expected:  true,
+		},
+		{
+			name:      "labels changed: changed annotation",
+			predicate: predicates.ResourceLabelsChanged(logger, "tier"),
+			newObj:    annotationChanged,
+			expected:  false,
+		},
+		{
+			name:      "fields changed: changed field",
+			predicate: predicates.ResourceFieldsChanged(logger, "spec.replicas"),
+			newObj:    generationChanged,
+			expected:  true,
+		},
+		{
+			name:      "fields changed: changed status conditions",
+			predicate: predicates.ResourceFieldsChanged(logger, "spec.replicas", "status.conditions"),
+			newObj:    statusChanged,
+			expected:  true,
+		},
+		{
+			name:      "fields changed: changed other fields",
+			predicate: predicates.ResourceFieldsChanged(logger, "spec.replicas"),
+			newObj:    statusChanged,
+			expected:  false,
+		},
+		{
+			name:      "generation or annotations changed: changed generation",
+			predicate: predicates.ResourceGenerationOrAnnotationsChanged(logger, "example.com/hold"),
+			newObj:    generationChanged,
+			expected:  true,
+		},
+		{
+			name:      "generation or annotations changed: changed annotation",
+			predicate: predicates.ResourceGenerationOrAnnotationsChanged(logger, "example.com/hold"),
+			newObj:    annotationChanged,
+			expected:  true,
+		},
+		{
+			name:      "generation or annotations changed: changed status",
+			predicate: predicates.ResourceGenerationOrAnnotationsChanged(logger, "example.com/hold"),
+			newObj:    statusChanged,
+			expected:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(tt.predicate.Update(event.UpdateEvent{ObjectOld: base, ObjectNew: tt.newObj})).To(Equal(tt.expected))
+
+			// Other events are always processed.
+			g.Expect(tt.predicate.Create(event.CreateEvent{Object: tt.newObj})).To(BeTrue())
+			g.Expect(tt.predicate.Delete(event.DeleteEvent{Object: tt.newObj})).To(BeTrue())
+			g.Expect(tt.predicate.Generic(event.GenericEvent{Object: tt.newObj})).To(BeTrue())
+		})
+	}
+}
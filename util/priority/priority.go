@@ -0,0 +1,254 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package priority implements helpers to reconcile the objects of high priority Clusters
+// ahead of the objects of lower priority Clusters.
+package priority
+
+import (
+	"context"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// Priority is the reconcile priority of a Cluster.
+type Priority string
+
+const (
+	// High is the priority of Clusters which are reconciled first, e.g. production Clusters.
+	High Priority = "high"
+
+	// Normal is the priority of Clusters without the reconcile priority annotation.
+	Normal Priority = "normal"
+
+	// Low is the priority of Clusters which are reconciled last, e.g. development Clusters.
+	Low Priority = "low"
+)
+
+// Weights are the number of requests served for each priority in each round of the weighted priority queue;
+// requests of higher priorities are always served first within a round.
+type Weights struct {
+	High   int
+	Normal int
+	Low    int
+}
+
+// For returns the weight of a priority; weights lower than 1 are considered as 1, so requests of a priority
+// are never starved.
+func (w Weights) For(p Priority) int {
+	weight := w.Normal
+	switch p {
+	case High:
+		weight = w.High
+	case Low:
+		weight = w.Low
+	}
+	if weight < 1 {
+		return 1
+	}
+	return weight
+}
+
+// IsZero returns true if no weights are set.
+func (w Weights) IsZero() bool {
+	return w.High <= 0 && w.Normal <= 0 && w.Low <= 0
+}
+
+// ParseWeights returns the weights from a map of priorities to weights, e.g. {"high": 8, "normal": 2, "low": 1}.
+func ParseWeights(weights map[string]int) (Weights, error) {
+	w := Weights{}
+	for p, weight := range weights {
+		if weight < 0 {
+			return Weights{}, errors.Errorf("weight of priority %q must not be negative", p)
+		}
+		switch Priority(p) {
+		case High:
+			w.High = weight
+		case Normal:
+			w.Normal = weight
+		case Low:
+			w.Low = weight
+		default:
+			return Weights{}, errors.Errorf("unknown priority %q, must be one of %s, %s or %s", p, High, Normal, Low)
+		}
+	}
+	return w, nil
+}
+
+func (p Priority) higherThan(other Priority) bool {
+	return p.rank() < other.rank()
+}
+
+func (p Priority) rank() int {
+	for i := range priorities {
+		if priorities[i] == p {
+			return i
+		}
+	}
+	return len(priorities)
+}
+
+// Get returns the reconcile priority of a Cluster from the reconcile priority annotation;
+// Clusters without the annotation or with an unknown value have normal priority.
+func Get(cluster *clusterv1.Cluster) Priority {
+	switch p := Priority(cluster.GetAnnotations()[clusterv1.ReconcilePriorityAnnotation]); p {
+	case High, Low:
+		return p
+	default:
+		return Normal
+	}
+}
+
+// NewReconciler returns a reconciler serving the requests for the objects of Clusters from a weighted priority
+// queue, so the objects of high priority Clusters are reconciled ahead of the objects of lower priority Clusters,
+// e.g. when a management cluster with thousands of objects starts.
+// The requests of the controller are added to the priority queue, and served by the given number of workers
+// running the wrapped reconciler; the workers are added to the manager, so they are started and stopped with it.
+//
+// The Cluster of an object is the object itself for Clusters, otherwise the Cluster identified by the
+// cluster name label of the object; requests for objects not found or without a Cluster have normal priority.
+// NOTE: If weights are not set, the reconciler is returned as is.
+// NOTE: Requeues and errors of the wrapped reconciler are handled by the priority queue, so the metrics of
+// the controller only account for adding the requests to the priority queue.
+func NewReconciler(mgr manager.Manager, r reconcile.Reconciler, obj client.Object, weights Weights, workers int) (reconcile.Reconciler, error) {
+	if weights.IsZero() {
+		return r, nil
+	}
+	pr := newReconciler(r, mgr.GetClient(), obj, weights, workers)
+	if err := mgr.Add(pr); err != nil {
+		return nil, errors.Wrap(err, "failed to add the workers of the priority queue to the manager")
+	}
+	return pr, nil
+}
+
+func newReconciler(r reconcile.Reconciler, c client.Reader, obj client.Object, weights Weights, workers int) *reconciler {
+	if workers < 1 {
+		workers = 1
+	}
+	return &reconciler{
+		Reconciler:  r,
+		Client:      c,
+		object:      obj,
+		workers:     workers,
+		queue:       newQueue(weights),
+		rateLimiter: workqueue.DefaultControllerRateLimiter(),
+	}
+}
+
+type reconciler struct {
+	reconcile.Reconciler
+	Client client.Reader
+
+	object  client.Object
+	workers int
+
+	queue       *queue
+	rateLimiter workqueue.RateLimiter
+
+	// loggers are the loggers of the controller for the queued requests.
+	loggers sync.Map
+}
+
+// Reconcile adds the request to the priority queue with the priority of the Cluster of the object.
+func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	r.loggers.Store(req, ctrl.LoggerFrom(ctx))
+	r.queue.Add(req, r.priority(ctx, req))
+	return ctrl.Result{}, nil
+}
+
+// Start runs the workers serving the priority queue until the context is done.
+func (r *reconciler) Start(ctx context.Context) error {
+	wg := sync.WaitGroup{}
+	for i := 0; i < r.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for r.processNextRequest(ctx) {
+			}
+		}()
+	}
+
+	<-ctx.Done()
+	r.queue.ShutDown()
+	wg.Wait()
+	return nil
+}
+
+// processNextRequest calls the wrapped reconciler for the next request of the priority queue, and queues the
+// request again if required; it returns false if the priority queue is shutting down.
+func (r *reconciler) processNextRequest(ctx context.Context) bool {
+	req, ok := r.queue.Get()
+	if !ok {
+		return false
+	}
+	defer r.queue.Done(req)
+
+	log := ctrl.LoggerFrom(ctx)
+	if l, ok := r.loggers.Load(req); ok {
+		log = l.(logr.Logger)
+	}
+	ctx = ctrl.LoggerInto(ctx, log)
+
+	result, err := r.Reconciler.Reconcile(ctx, req)
+	switch {
+	case err != nil:
+		log.Error(err, "Reconciler error")
+		r.queue.AddAfter(req, r.priority(ctx, req), r.rateLimiter.When(req))
+	case result.RequeueAfter > 0:
+		r.rateLimiter.Forget(req)
+		r.queue.AddAfter(req, r.priority(ctx, req), result.RequeueAfter)
+	case result.Requeue:
+		r.queue.AddAfter(req, r.priority(ctx, req), r.rateLimiter.When(req))
+	default:
+		r.rateLimiter.Forget(req)
+		r.loggers.Delete(req)
+	}
+	return true
+}
+
+// priority returns the priority for the request; errors reading the object or the Cluster are left to the
+// wrapped reconciler, so the request gets normal priority.
+func (r *reconciler) priority(ctx context.Context, req ctrl.Request) Priority {
+	obj, ok := r.object.DeepCopyObject().(client.Object)
+	if !ok {
+		return Normal
+	}
+	if err := r.Client.Get(ctx, req.NamespacedName, obj); err != nil {
+		return Normal
+	}
+
+	cluster, ok := obj.(*clusterv1.Cluster)
+	if !ok {
+		clusterName, ok := obj.GetLabels()[clusterv1.ClusterNameLabel]
+		if !ok || clusterName == "" {
+			return Normal
+		}
+		cluster = &clusterv1.Cluster{}
+		if err := r.Client.Get(ctx, client.ObjectKey{Namespace: obj.GetNamespace(), Name: clusterName}, cluster); err != nil {
+			return Normal
+		}
+	}
+	return Get(cluster)
+}
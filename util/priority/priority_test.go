@@ -0,0 +1,256 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package priority
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestGet(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        Priority
+	}{
+		{
+			name: "normal without annotation",
+			want: Normal,
+		},
+		{
+			name:        "high",
+			annotations: map[string]string{clusterv1.ReconcilePriorityAnnotation: "high"},
+			want:        High,
+		},
+		{
+			name:        "low",
+			annotations: map[string]string{clusterv1.ReconcilePriorityAnnotation: "low"},
+			want:        Low,
+		},
+		{
+			name:        "normal with an unknown value",
+			annotations: map[string]string{clusterv1.ReconcilePriorityAnnotation: "urgent"},
+			want:        Normal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}}
+			g.Expect(Get(cluster)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestParseWeights(t *testing.T) {
+	g := NewWithT(t)
+
+	weights, err := ParseWeights(map[string]int{"high": 8, "normal": 2})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(weights).To(Equal(Weights{High: 8, Normal: 2}))
+	g.Expect(weights.For(High)).To(Equal(8))
+	g.Expect(weights.For(Normal)).To(Equal(2))
+	// Weights lower than 1 are considered as 1, so requests are never starved.
+	g.Expect(weights.For(Low)).To(Equal(1))
+
+	weights, err = ParseWeights(nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(weights.IsZero()).To(BeTrue())
+
+	_, err = ParseWeights(map[string]int{"urgent": 1})
+	g.Expect(err).To(HaveOccurred())
+	_, err = ParseWeights(map[string]int{"high": -1})
+	g.Expect(err).To(HaveOccurred())
+}
+
+func request(name string) ctrl.Request {
+	return ctrl.Request{NamespacedName: types.NamespacedName{Namespace: metav1.NamespaceDefault, Name: name}}
+}
+
+func TestQueue(t *testing.T) {
+	t.Run("serves requests by priority and weight", func(t *testing.T) {
+		g := NewWithT(t)
+		q := newQueue(Weights{High: 2, Normal: 1, Low: 1})
+
+		q.Add(request("low-1"), Low)
+		q.Add(request("normal-1"), Normal)
+		q.Add(request("high-1"), High)
+		q.Add(request("high-2"), High)
+		q.Add(request("high-3"), High)
+		q.Add(request("normal-2"), Normal)
+
+		served := []string{}
+		for q.Len() > 0 {
+			req, ok := q.Get()
+			g.Expect(ok).To(BeTrue())
+			served = append(served, req.Name)
+			q.Done(req)
+		}
+		g.Expect(served).To(Equal([]string{"high-1", "high-2", "normal-1", "low-1", "high-3", "normal-2"}))
+	})
+
+	t.Run("moves requests added again with a higher priority", func(t *testing.T) {
+		g := NewWithT(t)
+		q := newQueue(Weights{High: 1})
+
+		q.Add(request("a"), Low)
+		q.Add(request("b"), Normal)
+		q.Add(request("a"), High)
+		q.Add(request("b"), Low)
+		g.Expect(q.Len()).To(Equal(2))
+
+		req, _ := q.Get()
+		g.Expect(req.Name).To(Equal("a"))
+		req, _ = q.Get()
+		g.Expect(req.Name).To(Equal("b"))
+	})
+
+	t.Run("queues requests added while being processed when done", func(t *testing.T) {
+		g := NewWithT(t)
+		q := newQueue(Weights{High: 1})
+
+		q.Add(request("a"), Normal)
+		req, _ := q.Get()
+		q.Add(request("a"), Normal)
+		g.Expect(q.Len()).To(Equal(0))
+
+		q.Done(req)
+		g.Expect(q.Len()).To(Equal(1))
+	})
+
+	t.Run("stops serving requests when shutting down", func(t *testing.T) {
+		g := NewWithT(t)
+		q := newQueue(Weights{High: 1})
+
+		q.ShutDown()
+		_, ok := q.Get()
+		g.Expect(ok).To(BeFalse())
+	})
+}
+
+type fakeReconciler struct {
+	result ctrl.Result
+	err    error
+	served []string
+}
+
+func (r *fakeReconciler) Reconcile(_ context.Context, req ctrl.Request) (ctrl.Result, error) {
+	r.served = append(r.served, req.Name)
+	return r.result, r.err
+}
+
+func TestNewReconciler(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	_ = clusterv1.AddToScheme(scheme)
+
+	cluster := func(name, priority string) *clusterv1.Cluster {
+		c := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: name}}
+		if priority != "" {
+			c.Annotations = map[string]string{clusterv1.ReconcilePriorityAnnotation: priority}
+		}
+		return c
+	}
+	machine := func(name, clusterName string) *clusterv1.Machine {
+		return &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceDefault,
+			Name:      name,
+			Labels:    map[string]string{clusterv1.ClusterNameLabel: clusterName},
+		}}
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		cluster("production", "high"),
+		cluster("staging", ""),
+		cluster("development", "low"),
+		machine("production-machine", "production"),
+		machine("staging-machine", "staging"),
+		machine("development-machine", "development"),
+	).Build()
+
+	t.Run("returns the reconciler as is without weights", func(t *testing.T) {
+		g := NewWithT(t)
+		inner := &fakeReconciler{}
+		g.Expect(NewReconciler(nil, inner, &clusterv1.Cluster{}, Weights{}, 1)).To(BeIdenticalTo(inner))
+	})
+
+	t.Run("serves requests for the objects of higher priority Clusters first", func(t *testing.T) {
+		g := NewWithT(t)
+		inner := &fakeReconciler{}
+		r := newReconciler(inner, c, &clusterv1.Machine{}, Weights{High: 1}, 1)
+
+		for _, name := range []string{"development-machine", "deleted-machine", "staging-machine", "production-machine"} {
+			g.Expect(r.Reconcile(ctx, request(name))).To(Equal(ctrl.Result{}))
+		}
+		g.Expect(inner.served).To(BeEmpty())
+
+		for r.queue.Len() > 0 {
+			g.Expect(r.processNextRequest(ctx)).To(BeTrue())
+		}
+		// Requests for objects not found have normal priority; with weight 1 for each priority, the second
+		// request with normal priority is served in the next round.
+		g.Expect(inner.served).To(Equal([]string{"production-machine", "deleted-machine", "development-machine", "staging-machine"}))
+	})
+
+	t.Run("queues requests again when requeued", func(t *testing.T) {
+		g := NewWithT(t)
+		inner := &fakeReconciler{result: ctrl.Result{Requeue: true}}
+		r := newReconciler(inner, c, &clusterv1.Cluster{}, Weights{High: 1}, 1)
+
+		g.Expect(r.Reconcile(ctx, request("production"))).To(Equal(ctrl.Result{}))
+		g.Expect(r.processNextRequest(ctx)).To(BeTrue())
+		g.Eventually(r.queue.Len).Should(Equal(1))
+	})
+
+	t.Run("queues requests again on errors", func(t *testing.T) {
+		g := NewWithT(t)
+		inner := &fakeReconciler{err: errors.New("failed")}
+		r := newReconciler(inner, c, &clusterv1.Cluster{}, Weights{High: 1}, 1)
+
+		g.Expect(r.Reconcile(ctx, request("production"))).To(Equal(ctrl.Result{}))
+		g.Expect(r.processNextRequest(ctx)).To(BeTrue())
+		g.Eventually(r.queue.Len).Should(Equal(1))
+	})
+
+	t.Run("runs the workers until the context is done", func(t *testing.T) {
+		g := NewWithT(t)
+		inner := &fakeReconciler{}
+		r := newReconciler(inner, c, &clusterv1.Cluster{}, Weights{High: 1}, 2)
+
+		ctx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			g.Expect(r.Start(ctx)).To(Succeed())
+		}()
+
+		g.Expect(r.Reconcile(ctx, request("production"))).To(Equal(ctrl.Result{}))
+		g.Eventually(r.queue.Len).Should(Equal(0))
+
+		cancel()
+		g.Eventually(done).Should(BeClosed())
+	})
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package priority
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// priorities are the priorities in the order they are served.
+var priorities = []Priority{High, Normal, Low}
+
+// queue is a weighted priority queue of requests.
+// Requests are served from the highest priority with pending requests, up to the weight of the priority in
+// each round, so requests of higher priorities are served first, while requests of lower priorities are
+// never starved. Requests of the same priority are served in FIFO order.
+// Like the client-go workqueue, a request is never queued twice and never served to more than one worker
+// at the same time; requests added while being processed are queued again when the processing is done.
+type queue struct {
+	lock sync.Mutex
+	cond *sync.Cond
+
+	weights Weights
+	// credits are the requests each priority can still be served in the current round.
+	credits map[Priority]int
+
+	// pending are the queued requests for each priority, in FIFO order.
+	pending map[Priority][]ctrl.Request
+	// queued are the priorities of the queued requests.
+	queued map[ctrl.Request]Priority
+	// processing are the requests being processed by a worker.
+	processing sets.Set[ctrl.Request]
+	// dirty are the priorities of the requests added while being processed.
+	dirty map[ctrl.Request]Priority
+
+	shuttingDown bool
+}
+
+func newQueue(weights Weights) *queue {
+	q := &queue{
+		weights:    weights,
+		credits:    map[Priority]int{},
+		pending:    map[Priority][]ctrl.Request{},
+		queued:     map[ctrl.Request]Priority{},
+		processing: sets.Set[ctrl.Request]{},
+		dirty:      map[ctrl.Request]Priority{},
+	}
+	q.cond = sync.NewCond(&q.lock)
+	return q
+}
+
+// Add queues a request with the given priority; if the request is already queued with a lower priority,
+// it is moved to the given priority.
+func (q *queue) Add(req ctrl.Request, p Priority) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if q.shuttingDown {
+		return
+	}
+	if q.processing.Has(req) {
+		if current, ok := q.dirty[req]; !ok || p.higherThan(current) {
+			q.dirty[req] = p
+		}
+		return
+	}
+	if current, ok := q.queued[req]; ok {
+		if !p.higherThan(current) {
+			return
+		}
+		q.remove(req, current)
+	}
+	q.push(req, p)
+}
+
+// AddAfter queues a request with the given priority after the given duration.
+func (q *queue) AddAfter(req ctrl.Request, p Priority, duration time.Duration) {
+	if duration <= 0 {
+		q.Add(req, p)
+		return
+	}
+	time.AfterFunc(duration, func() { q.Add(req, p) })
+}
+
+// Get blocks until a request is available, and returns the request to be served next; the worker must
+// call Done when the request is processed. Get returns false if the queue is shutting down.
+func (q *queue) Get() (ctrl.Request, bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	for len(q.queued) == 0 && !q.shuttingDown {
+		q.cond.Wait()
+	}
+	if q.shuttingDown {
+		return ctrl.Request{}, false
+	}
+
+	p := q.next()
+	req := q.pending[p][0]
+	q.pending[p] = q.pending[p][1:]
+	delete(q.queued, req)
+	q.credits[p]--
+	q.processing.Insert(req)
+	return req, true
+}
+
+// Done marks a request as processed, queuing it again if it was added while being processed.
+func (q *queue) Done(req ctrl.Request) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	q.processing.Delete(req)
+	if p, ok := q.dirty[req]; ok {
+		delete(q.dirty, req)
+		if !q.shuttingDown {
+			q.push(req, p)
+		}
+	}
+}
+
+// Len returns the number of queued requests.
+func (q *queue) Len() int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return len(q.queued)
+}
+
+// ShutDown makes Get return false to all the workers.
+func (q *queue) ShutDown() {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	q.shuttingDown = true
+	q.cond.Broadcast()
+}
+
+// next returns the priority to be served next, starting a new round when no priority with pending
+// requests has credits left.
+// NOTE: This func must be called with the lock held and at least one request queued.
+func (q *queue) next() Priority {
+	for {
+		for _, p := range priorities {
+			if len(q.pending[p]) > 0 && q.credits[p] > 0 {
+				return p
+			}
+		}
+		for _, p := range priorities {
+			q.credits[p] = q.weights.For(p)
+		}
+	}
+}
+
+func (q *queue) push(req ctrl.Request, p Priority) {
+	q.pending[p] = append(q.pending[p], req)
+	q.queued[req] = p
+	q.cond.Signal()
+}
+
+func (q *queue) remove(req ctrl.Request, p Priority) {
+	for i := range q.pending[p] {
+		if q.pending[p][i] == req {
+			q.pending[p] = append(q.pending[p][:i], q.pending[p][i+1:]...)
+			break
+		}
+	}
+	delete(q.queued, req)
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package requeue implements helpers to requeue objects with an exponential backoff.
+package requeue
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// Backoff tracks an exponential requeue backoff for each object, so controllers waiting for objects to
+// reach a state, e.g. for infrastructure to be provisioned, requeue them less often the longer they wait,
+// smoothing the load on the API server when many objects are provisioned at the same time.
+//
+// The delay for an object starts from Initial and doubles at each requeue up to Max, until the backoff of
+// the object is reset, e.g. once the object reached the expected state or it is deleted.
+type Backoff struct {
+	// Initial is the delay of the first requeue of an object.
+	Initial time.Duration
+
+	// Max is the maximum delay of the requeues of an object; if not greater than Initial,
+	// objects are always requeued after the Initial delay.
+	Max time.Duration
+
+	// Jitter adds a random amount, up to Jitter times the delay, to each delay, so requeues of
+	// objects started at the same time are spread over time. If zero, no jitter is added.
+	Jitter float64
+
+	lock sync.Mutex
+	// steps is the number of requeues of each object since its backoff was last reset.
+	steps map[types.NamespacedName]int
+}
+
+// Next returns the delay for the next requeue of the object, and increases the delay for the following one.
+func (b *Backoff) Next(key types.NamespacedName) time.Duration {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.steps == nil {
+		b.steps = map[types.NamespacedName]int{}
+	}
+
+	delay := b.Initial
+	for i := 0; i < b.steps[key] && delay < b.Max; i++ {
+		delay *= 2
+	}
+	if delay < b.Max {
+		b.steps[key]++
+	} else if b.Max > b.Initial {
+		delay = b.Max
+	}
+
+	if b.Jitter > 0 {
+		delay = wait.Jitter(delay, b.Jitter)
+	}
+	return delay
+}
+
+// Result returns a result requeueing the object after the delay returned by Next.
+func (b *Backoff) Result(key types.NamespacedName) ctrl.Result {
+	return ctrl.Result{RequeueAfter: b.Next(key)}
+}
+
+// Reset resets the backoff of the object, so the next requeue happens after the Initial delay.
+func (b *Backoff) Reset(key types.NamespacedName) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	delete(b.steps, key)
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package requeue
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestBackoff(t *testing.T) {
+	machinePool1 := types.NamespacedName{Namespace: "default", Name: "machinepool-1"}
+	machinePool2 := types.NamespacedName{Namespace: "default", Name: "machinepool-2"}
+
+	t.Run("doubles the delay for each object up to the max", func(t *testing.T) {
+		g := NewWithT(t)
+		b := &Backoff{Initial: time.Second, Max: 5 * time.Second}
+
+		g.Expect(b.Next(machinePool1)).To(Equal(time.Second))
+		g.Expect(b.Next(machinePool1)).To(Equal(2 * time.Second))
+		g.Expect(b.Next(machinePool1)).To(Equal(4 * time.Second))
+		g.Expect(b.Next(machinePool1)).To(Equal(5 * time.Second))
+		g.Expect(b.Next(machinePool1)).To(Equal(5 * time.Second))
+
+		// The backoff of each object is tracked separately.
+		g.Expect(b.Result(machinePool2)).To(Equal(ctrl.Result{RequeueAfter: time.Second}))
+	})
+
+	t.Run("resets the delay of an object", func(t *testing.T) {
+		g := NewWithT(t)
+		b := &Backoff{Initial: time.Second, Max: 5 * time.Second}
+
+		g.Expect(b.Next(machinePool1)).To(Equal(time.Second))
+		g.Expect(b.Next(machinePool1)).To(Equal(2 * time.Second))
+		g.Expect(b.Next(machinePool2)).To(Equal(time.Second))
+
+		b.Reset(machinePool1)
+		g.Expect(b.Next(machinePool1)).To(Equal(time.Second))
+		g.Expect(b.Next(machinePool2)).To(Equal(2 * time.Second))
+	})
+
+	t.Run("always uses the initial delay if the max is not greater than the initial delay", func(t *testing.T) {
+		g := NewWithT(t)
+		b := &Backoff{Initial: time.Second}
+
+		g.Expect(b.Next(machinePool1)).To(Equal(time.Second))
+		g.Expect(b.Next(machinePool1)).To(Equal(time.Second))
+	})
+
+	t.Run("adds jitter to the delay", func(t *testing.T) {
+		g := NewWithT(t)
+		b := &Backoff{Initial: time.Second, Max: 5 * time.Second, Jitter: 0.5}
+
+		delay := b.Next(machinePool1)
+		g.Expect(delay).To(BeNumerically(">=", time.Second))
+		g.Expect(delay).To(BeNumerically("<=", 1500*time.Millisecond))
+	})
+}
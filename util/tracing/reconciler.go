@@ -0,0 +1,55 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// NewReconciler returns a reconciler creating a span for each reconcile of the given reconciler, named
+// after the kind of the reconciled objects, e.g. "Cluster.Reconcile", with the namespace and name of
+// the request as attributes.
+// The span is stored in the context passed to the reconciler, so spans created while reconciling,
+// e.g. for patches or Runtime Hook calls, are its children.
+func NewReconciler(r reconcile.Reconciler, kind string) reconcile.Reconciler {
+	return &tracingReconciler{
+		Reconciler: r,
+		kind:       kind,
+	}
+}
+
+type tracingReconciler struct {
+	reconcile.Reconciler
+	kind string
+}
+
+// Reconcile calls the wrapped reconciler within a span.
+func (r *tracingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	ctx, span := StartSpan(ctx, r.kind+".Reconcile",
+		attribute.String("namespace", req.Namespace),
+		attribute.String("name", req.Name),
+	)
+	defer func() {
+		EndSpan(span, reterr)
+	}()
+
+	return r.Reconciler.Reconcile(ctx, req)
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestReconciler(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(previous)
+
+	req := ctrl.Request{}
+	req.Namespace = "ns"
+	req.Name = "cluster"
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus codes.Code
+	}{
+		{
+			name:       "successful reconcile",
+			wantStatus: codes.Unset,
+		},
+		{
+			name:       "failed reconcile",
+			err:        errors.New("failed"),
+			wantStatus: codes.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			recorder := tracetest.NewSpanRecorder()
+			otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+			var reconcileSpan trace.SpanContext
+			r := NewReconciler(reconcile.Func(func(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+				reconcileSpan = trace.SpanContextFromContext(ctx)
+
+				// Spans created while reconciling are children of the reconcile span.
+				_, span := StartSpan(ctx, "child")
+				EndSpan(span, nil)
+				return reconcile.Result{}, tt.err
+			}), "Cluster")
+
+			_, err := r.Reconcile(context.Background(), req)
+			if tt.err != nil {
+				g.Expect(err).To(MatchError(tt.err))
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+
+			spans := recorder.Ended()
+			g.Expect(spans).To(HaveLen(2))
+			child, parent := spans[0], spans[1]
+			g.Expect(child.Name()).To(Equal("child"))
+			g.Expect(child.Parent().SpanID()).To(Equal(reconcileSpan.SpanID()))
+			g.Expect(parent.Name()).To(Equal("Cluster.Reconcile"))
+			g.Expect(parent.SpanContext().SpanID()).To(Equal(reconcileSpan.SpanID()))
+			g.Expect(parent.Attributes()).To(ConsistOf(attribute.String("namespace", "ns"), attribute.String("name", "cluster")))
+			g.Expect(parent.Status().Code).To(Equal(tt.wantStatus))
+		})
+	}
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing implements OpenTelemetry tracing utilities.
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"sigs.k8s.io/cluster-api/version"
+)
+
+// instrumentationName is the name of the tracer used to create the Cluster API spans.
+const instrumentationName = "sigs.k8s.io/cluster-api"
+
+// Options has the options to configure the export of traces.
+type Options struct {
+	// Endpoint is the address of the OpenTelemetry collector traces are exported to;
+	// if empty, tracing is disabled.
+	Endpoint string
+
+	// SamplingRatio is the ratio of the traces to sample, between 0 and 1.
+	SamplingRatio float64
+
+	// Insecure disables the transport security of the connection to the collector.
+	Insecure bool
+}
+
+// AddOptions adds the tracing configuration flags to the flag set.
+func AddOptions(fs *pflag.FlagSet, options *Options) {
+	fs.StringVar(&options.Endpoint, "tracing-endpoint", "",
+		"The address of the OpenTelemetry collector traces are exported to using OTLP over gRPC, e.g. localhost:4317. If empty, tracing is disabled.")
+
+	fs.Float64Var(&options.SamplingRatio, "tracing-sampling-ratio", 1,
+		"The ratio of the traces to sample, between 0 and 1. Traces with a sampled parent are always sampled.")
+
+	fs.BoolVar(&options.Insecure, "tracing-insecure", false,
+		"Disable the transport security of the connection to the OpenTelemetry collector.")
+}
+
+// Setup configures the global TracerProvider to export the traces of the service to the collector
+// in the options, and returns a func flushing the pending spans and stopping the export.
+// NOTE: If no endpoint is set, spans are not recorded and the global TracerProvider is left unchanged.
+func Setup(ctx context.Context, options Options, serviceName string) (shutdown func(context.Context) error, err error) {
+	if options.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+	if options.SamplingRatio < 0 || options.SamplingRatio > 1 {
+		return nil, errors.Errorf("invalid tracing sampling ratio %v: must be between 0 and 1", options.SamplingRatio)
+	}
+
+	exporterOptions := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(options.Endpoint)}
+	if options.Insecure {
+		exporterOptions = append(exporterOptions, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(ctx, exporterOptions...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create trace exporter")
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceNameKey.String(serviceName),
+		semconv.ServiceVersionKey.String(version.Get().GitVersion),
+	))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create trace resource")
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(options.SamplingRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return provider.Shutdown, nil
+}
+
+// StartSpan starts a span with the given name and attributes, child of the span in the context if any.
+// Callers must end the returned span, e.g. with EndSpan.
+func StartSpan(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// EndSpan records the error, if any, on the span and ends it.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// WrapTransport returns a RoundTripper creating a span for each request sent with the given RoundTripper,
+// and propagating the trace context to the server in the request headers.
+// It can be used as a rest.Config WrapTransport func.
+func WrapTransport(rt http.RoundTripper) http.RoundTripper {
+	return otelhttp.NewTransport(rt)
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel"
+)
+
+func TestSetup(t *testing.T) {
+	tests := []struct {
+		name    string
+		options Options
+		wantErr bool
+	}{
+		{
+			name:    "tracing disabled without endpoint",
+			options: Options{},
+		},
+		{
+			name:    "sampling ratio lower than 0",
+			options: Options{Endpoint: "localhost:4317", SamplingRatio: -0.1},
+			wantErr: true,
+		},
+		{
+			name:    "sampling ratio greater than 1",
+			options: Options{Endpoint: "localhost:4317", SamplingRatio: 1.5},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			provider := otel.GetTracerProvider()
+			shutdown, err := Setup(context.Background(), tt.options, "test")
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(shutdown(context.Background())).To(Succeed())
+
+			// The global TracerProvider is left unchanged.
+			g.Expect(otel.GetTracerProvider()).To(BeIdenticalTo(provider))
+		})
+	}
+}
@@ -35,14 +35,37 @@ func (webhook *Cluster) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	}).SetupWebhookWithManager(mgr)
 }
 
+// DefaultTopologyControllerUsername is the username of the service account of the core Cluster API controller
+// manager, which runs the topology controller, in the default installation.
+const DefaultTopologyControllerUsername = webhooks.DefaultTopologyControllerUsername
+
+// TopologyManagedFields implements a validating webhook rejecting changes to the fields
+// of objects managed by the Cluster topology.
+type TopologyManagedFields struct {
+	// TopologyControllerUsername is the username the topology controller authenticates with.
+	TopologyControllerUsername string
+}
+
+// SetupWebhookWithManager sets up the TopologyManagedFields webhook.
+func (webhook *TopologyManagedFields) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return (&webhooks.TopologyManagedFields{
+		TopologyControllerUsername: webhook.TopologyControllerUsername,
+	}).SetupWebhookWithManager(mgr)
+}
+
 // ClusterClass implements a validation and defaulting webhook for ClusterClass.
 type ClusterClass struct {
 	Client client.Reader
+
+	// ReplicationControllerUsername is the username the ClusterClass replication controller authenticates with;
+	// if set, only this user can create or change replicated ClusterClasses.
+	ReplicationControllerUsername string
 }
 
 // SetupWebhookWithManager sets up ClusterClass webhooks.
 func (webhook *ClusterClass) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return (&webhooks.ClusterClass{
-		Client: webhook.Client,
+		Client:                        webhook.Client,
+		ReplicationControllerUsername: webhook.ReplicationControllerUsername,
 	}).SetupWebhookWithManager(mgr)
 }